@@ -18,6 +18,7 @@ import (
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
+	restmodel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/thirdparty/docker"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/pail"
@@ -93,11 +94,22 @@ type taskContext struct {
 	logDirectories         map[string]interface{}
 	timeout                timeoutInfo
 	project                *model.Project
+	projectRef             *model.ProjectRef
+	distroView             *apimodels.DistroView
 	taskModel              *task.Task
 	oomTracker             jasper.OOMTracker
+	// commandAbort allows the running task command to be aborted by request
+	// without aborting the rest of the task.
+	commandAbort    *commandAbort
+	abortedCommands []apimodels.AbortedCommand
 	sync.RWMutex
 }
 
+type commandAbort struct {
+	cancel  context.CancelFunc
+	aborted chan struct{}
+}
+
 type timeoutInfo struct {
 	// idleTimeoutDuration maintains the current idle timeout in the task context;
 	// the exec timeout is maintained in the project data structure
@@ -172,6 +184,9 @@ func (a *Agent) Start(ctx context.Context) error {
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	// Resume ending a task before cleaning up, since cleaning up removes the
+	// end task manifest.
+	a.resumeEndTask(ctx)
 	if a.opts.Cleanup {
 		tryCleanupDirectory(a.opts.WorkingDirectory)
 	}
@@ -328,6 +343,10 @@ LOOP:
 			}
 
 			jitteredSleep = utility.JitterInterval(agentSleepInterval)
+			// The app server asks agents to back off when it's shedding load.
+			if backoff := time.Duration(nextTask.BackoffSecs) * time.Second; backoff > jitteredSleep {
+				jitteredSleep = utility.JitterInterval(backoff)
+			}
 			grip.Debugf("Agent sleeping %s", jitteredSleep)
 			timer.Reset(jitteredSleep)
 			agentSleepInterval = agentSleepInterval * 2
@@ -389,6 +408,20 @@ func shouldRunSetupGroup(nextTask *apimodels.NextTaskResponse, tc *taskContext)
 }
 
 func (a *Agent) fetchProjectConfig(ctx context.Context, tc *taskContext) error {
+	bootstrap, err := a.comm.GetTaskBootstrap(ctx, tc.task)
+	if err == nil {
+		err = tc.applyTaskBootstrap(bootstrap)
+	}
+	if err == nil {
+		return nil
+	}
+	// The app server may be older or newer than the agent, so fall back to
+	// fetching the configuration one piece at a time.
+	grip.Warning(message.WrapError(err, message.Fields{
+		"message": "could not bootstrap task, fetching its configuration separately",
+		"task":    tc.task.ID,
+	}))
+
 	project, err := a.comm.GetProject(ctx, tc.task)
 	if err != nil {
 		return errors.Wrap(err, "error getting project")
@@ -414,6 +447,35 @@ func (a *Agent) fetchProjectConfig(ctx context.Context, tc *taskContext) error {
 	return nil
 }
 
+// applyTaskBootstrap sets the task's configuration from the configuration
+// that the app server returned all at once.
+func (tc *taskContext) applyTaskBootstrap(bootstrap *restmodel.APITaskBootstrap) error {
+	if bootstrap.Task == nil {
+		return errors.New("task bootstrap is missing the task")
+	}
+	if bootstrap.ProjectRef == nil {
+		return errors.New("task bootstrap is missing the project ref")
+	}
+	project, err := model.GetProjectFromBSON(bootstrap.ParserProject)
+	if err != nil {
+		return errors.Wrap(err, "getting project from task bootstrap")
+	}
+
+	exp := bootstrap.Expansions
+	if exp == nil {
+		exp = util.Expansions{}
+	}
+	expVars := bootstrap.ExpansionVars
+	exp.Update(expVars.Vars)
+	tc.taskModel = bootstrap.Task
+	tc.project = project
+	tc.projectRef = bootstrap.ProjectRef
+	tc.distroView = &bootstrap.DistroView
+	tc.expansions = exp
+	tc.expVars = &expVars
+	return nil
+}
+
 func (a *Agent) startLogging(ctx context.Context, tc *taskContext) error {
 	var err error
 
@@ -602,6 +664,13 @@ func (a *Agent) finishTask(ctx context.Context, tc *taskContext, status string,
 		tc.logger.Task().Errorf("Programmer error: Invalid task status %s", detail.Status)
 	}
 
+	// Retries of the request send the same key, so the task is only ended
+	// once even if a response is lost or the agent restarts and resumes
+	// ending the task.
+	detail.IdempotencyKey = utility.RandomString()
+	a.startFinalizingTask(ctx, tc, detail)
+	defer a.removeEndTaskManifest()
+
 	tc.Lock()
 	if tc.systemMetricsCollector != nil {
 		err := tc.systemMetricsCollector.Close()
@@ -629,6 +698,30 @@ func (a *Agent) finishTask(ctx context.Context, tc *taskContext, status string,
 	return resp, nil
 }
 
+// startFinalizingTask records how the task is being ended, so that if the agent
+// restarts before it finishes uploading the task's results and ending the
+// task, it can resume ending it.
+func (a *Agent) startFinalizingTask(ctx context.Context, tc *taskContext, detail *apimodels.TaskEndDetail) {
+	m := &endTaskManifest{
+		Task:      tc.task,
+		Detail:    *detail,
+		CreatedAt: time.Now(),
+	}
+	resp, err := a.comm.StartFinalizingTask(ctx, tc.task)
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "could not notify app server that task is being ended",
+			"task_id": tc.task.ID,
+		}))
+	} else {
+		m.ResumeBy = resp.FinalizingUntil
+	}
+	grip.Warning(message.WrapError(a.writeEndTaskManifest(m), message.Fields{
+		"message": "could not write end task manifest",
+		"task_id": tc.task.ID,
+	}))
+}
+
 func (a *Agent) endTaskResponse(tc *taskContext, status string, message string) *apimodels.TaskEndDetail {
 	var description string
 	var failureType string
@@ -672,6 +765,7 @@ func (a *Agent) endTaskResponse(tc *taskContext, status string, message string)
 		Status:          status,
 		Message:         message,
 		Logs:            tc.logs,
+		AbortedCommands: tc.getAbortedCommands(),
 	}
 	if tc.taskConfig != nil {
 		detail.Modules.Prefixes = tc.taskConfig.ModulePaths
@@ -229,6 +229,68 @@ func (s *AgentSuite) TestFinishTaskEndTaskError() {
 	s.Error(err)
 }
 
+func (s *AgentSuite) TestFinishTaskRemovesEndTaskManifest() {
+	s.a.opts.WorkingDirectory = s.tmpDirName
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := s.a.finishTask(ctx, s.tc, evergreen.TaskSucceeded, "")
+	s.NoError(err)
+	s.Require().Len(s.mockCommunicator.FinalizingTasks, 1)
+	s.Equal(s.tc.task, s.mockCommunicator.FinalizingTasks[0])
+	s.NotEmpty(s.mockCommunicator.GetEndTaskDetail().IdempotencyKey)
+	s.False(s.mockCommunicator.GetEndTaskDetail().Resumed)
+	m, err := s.a.readEndTaskManifest()
+	s.NoError(err)
+	s.Nil(m)
+}
+
+func (s *AgentSuite) TestResumeEndTask() {
+	s.a.opts.WorkingDirectory = s.tmpDirName
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Require().NoError(s.a.writeEndTaskManifest(&endTaskManifest{
+		Task: s.tc.task,
+		Detail: apimodels.TaskEndDetail{
+			Status:         evergreen.TaskFailed,
+			IdempotencyKey: "key",
+		},
+		CreatedAt: time.Now(),
+		ResumeBy:  time.Now().Add(time.Minute),
+	}))
+	s.a.resumeEndTask(ctx)
+
+	detail := s.mockCommunicator.GetEndTaskDetail()
+	s.Require().NotNil(detail)
+	s.Equal(evergreen.TaskFailed, detail.Status)
+	s.Equal("key", detail.IdempotencyKey)
+	s.True(detail.Resumed)
+	s.Equal(s.tc.task, s.mockCommunicator.EndTaskResult.TaskData)
+	m, err := s.a.readEndTaskManifest()
+	s.NoError(err)
+	s.Nil(m)
+}
+
+func (s *AgentSuite) TestResumeEndTaskDiscardsExpiredManifest() {
+	s.a.opts.WorkingDirectory = s.tmpDirName
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Require().NoError(s.a.writeEndTaskManifest(&endTaskManifest{
+		Task:      s.tc.task,
+		Detail:    apimodels.TaskEndDetail{Status: evergreen.TaskFailed},
+		CreatedAt: time.Now().Add(-time.Hour),
+		ResumeBy:  time.Now().Add(-time.Minute),
+	}))
+	s.a.resumeEndTask(ctx)
+
+	s.Nil(s.mockCommunicator.GetEndTaskDetail())
+	m, err := s.a.readEndTaskManifest()
+	s.NoError(err)
+	s.Nil(m)
+}
+
 func (s *AgentSuite) TestCancelStartTask() {
 	complete := make(chan string)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -481,6 +543,61 @@ func (s *AgentSuite) TestAbort() {
 	}
 }
 
+func (s *AgentSuite) TestAbortCurrentCommand() {
+	s.False(s.tc.abortCurrentCommand(), "should not abort when no command is running")
+
+	s.tc.taskConfig = &internal.TaskConfig{
+		BuildVariant: &model.BuildVariant{
+			Name: "buildvariant_id",
+		},
+		Task: &task.Task{
+			Id: "task_id",
+		},
+		Project: &model.Project{},
+		Timeout: &internal.Timeout{},
+		WorkDir: s.tc.taskDirectory,
+	}
+	cmds := []model.PluginCommandConf{
+		{
+			Command: "shell.exec",
+			Params: map[string]interface{}{
+				"script": "sleep 60",
+			},
+		},
+		{
+			Command: "shell.exec",
+			Params: map[string]interface{}{
+				"script": "echo hi",
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	go func() {
+		for ctx.Err() == nil && !s.tc.abortCurrentCommand() {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	start := time.Now()
+	s.NoError(s.a.runCommands(ctx, s.tc, cmds, runCommandsOptions{isTaskCommands: true}))
+	s.True(time.Since(start) < 30*time.Second)
+
+	aborted := s.tc.getAbortedCommands()
+	s.Require().Len(aborted, 1)
+	s.Contains(aborted[0].Command, "shell.exec")
+	s.False(s.tc.abortCurrentCommand(), "should not abort after the commands finish")
+
+	s.Require().NoError(s.tc.logger.Close())
+	var ranNextCommand bool
+	for _, m := range s.mockCommunicator.GetMockMessages()["task_id"] {
+		if strings.Contains(m.Message, "step 2 of 2") {
+			ranNextCommand = true
+		}
+	}
+	s.True(ranNextCommand, "should continue running commands after the aborted one")
+}
+
 func (s *AgentSuite) TestOOMTracker() {
 	pids := []int{1, 2, 3}
 	lines := []string{"line 1", "line 2", "line 3"}
@@ -673,6 +790,31 @@ func (s *AgentSuite) TestPrepareNextTask() {
 	s.Empty(tc.taskDirectory)
 }
 
+func (s *AgentSuite) TestFetchProjectConfigUsesTaskBootstrap() {
+	tc := &taskContext{task: s.tc.task}
+	s.NoError(s.a.fetchProjectConfig(context.Background(), tc))
+	s.NotNil(tc.project)
+	s.NotNil(tc.taskModel)
+	s.Require().NotNil(tc.projectRef)
+	s.Equal("mock_owner", tc.projectRef.Owner)
+	s.Require().NotNil(tc.distroView)
+	s.Equal(".", tc.distroView.WorkDir)
+	s.Equal("bar", tc.expansions.Get("foo"))
+	s.Equal("2", tc.expansions.Get("my_new_timeout"))
+}
+
+func (s *AgentSuite) TestFetchProjectConfigFallsBackWithoutTaskBootstrap() {
+	s.mockCommunicator.TaskBootstrapShouldFail = true
+	tc := &taskContext{task: s.tc.task}
+	s.NoError(s.a.fetchProjectConfig(context.Background(), tc))
+	s.NotNil(tc.project)
+	s.NotNil(tc.taskModel)
+	s.Nil(tc.projectRef, "project ref should be fetched when making the task config")
+	s.Nil(tc.distroView, "distro view should be fetched when making the task config")
+	s.Equal("bar", tc.expansions.Get("foo"))
+	s.Equal("2", tc.expansions.Get("my_new_timeout"))
+}
+
 func (s *AgentSuite) TestGroupPreGroupCommands() {
 	s.tc.taskGroup = "task_group_name"
 	s.tc.taskGroup = "task_group_name"
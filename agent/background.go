@@ -18,6 +18,7 @@ func (a *Agent) startHeartbeat(ctx context.Context, cancel context.CancelFunc, t
 
 	var failures int
 	var signalBeat string
+	var backoff time.Duration
 	var err error
 	ticker := time.NewTicker(heartbeatInterval)
 	defer ticker.Stop()
@@ -25,7 +26,7 @@ func (a *Agent) startHeartbeat(ctx context.Context, cancel context.CancelFunc, t
 	for {
 		select {
 		case <-ticker.C:
-			signalBeat, err = a.doHeartbeat(ctx, tc)
+			signalBeat, backoff, err = a.doHeartbeat(ctx, tc)
 			if signalBeat == evergreen.TaskConflict {
 				tc.logger.Task().Error("Encountered task conflict while checking heartbeat, aborting task")
 				if err != nil {
@@ -38,6 +39,9 @@ func (a *Agent) startHeartbeat(ctx context.Context, cancel context.CancelFunc, t
 				heartbeat <- signalBeat
 				return
 			}
+			if signalBeat == evergreen.TaskCommandAbort && !tc.abortCurrentCommand() {
+				tc.logger.Task().Warning("Heartbeat received signal to abort the running command, but no task command is running")
+			}
 			if err != nil {
 				failures++
 			} else {
@@ -49,6 +53,12 @@ func (a *Agent) startHeartbeat(ctx context.Context, cancel context.CancelFunc, t
 				heartbeat <- evergreen.TaskFailed
 				return
 			}
+			// The app server asks agents to heartbeat less often when it's
+			// shedding load.
+			if backoff > maxHeartbeatBackoff {
+				backoff = maxHeartbeatBackoff
+			}
+			ticker.Reset(heartbeatInterval + backoff)
 		case <-ctx.Done():
 			heartbeat <- evergreen.TaskFailed
 			return
@@ -56,12 +66,12 @@ func (a *Agent) startHeartbeat(ctx context.Context, cancel context.CancelFunc, t
 	}
 }
 
-func (a *Agent) doHeartbeat(ctx context.Context, tc *taskContext) (string, error) {
-	resp, err := a.comm.Heartbeat(ctx, tc.task)
-	if resp == evergreen.TaskFailed || resp == evergreen.TaskConflict {
-		return resp, err
+func (a *Agent) doHeartbeat(ctx context.Context, tc *taskContext) (string, time.Duration, error) {
+	resp, backoff, err := a.comm.Heartbeat(ctx, tc.task)
+	if resp == evergreen.TaskFailed || resp == evergreen.TaskConflict || resp == evergreen.TaskCommandAbort {
+		return resp, backoff, err
 	}
-	return "", err
+	return "", backoff, err
 }
 
 func (a *Agent) startIdleTimeoutWatch(ctx context.Context, tc *taskContext, cancel context.CancelFunc) {
@@ -110,6 +110,14 @@ func (a *Agent) runCommandSet(ctx context.Context, tc *taskContext, commandInfo
 			tc.setCurrentIdleTimeout(nil)
 		}
 
+		cmdCtx := ctx
+		var aborted <-chan struct{}
+		if options.isTaskCommands {
+			var cancelCmd context.CancelFunc
+			cmdCtx, cancelCmd = context.WithCancel(ctx)
+			aborted = tc.allowCommandAbort(cancelCmd)
+		}
+
 		start := time.Now()
 		// We have seen cases where calling exec.*Cmd.Wait() waits for too long if
 		// the process has called subprocesses. It will wait until a subprocess
@@ -122,19 +130,13 @@ func (a *Agent) runCommandSet(ctx context.Context, tc *taskContext, commandInfo
 				cmdChan <- recovery.HandlePanicWithError(recover(), nil,
 					fmt.Sprintf("problem running command '%s'", cmd.Name()))
 			}()
-			cmdChan <- cmd.Execute(ctx, a.comm, logger, tc.taskConfig)
+			cmdChan <- cmd.Execute(cmdCtx, a.comm, logger, tc.taskConfig)
 		}()
 		select {
 		case err = <-cmdChan:
-			if err != nil {
-				tc.logger.Task().Errorf("Command failed: %v", err)
-				if options.isTaskCommands || options.failPreAndPost ||
-					(cmd.Name() == "git.get_project" && tc.taskModel.Requester == evergreen.MergeTestRequester) {
-					// any git.get_project in the commit queue should fail
-					return errors.Wrap(err, "command failed")
-				}
-			}
+		case <-aborted:
 		case <-ctx.Done():
+			tc.disallowCommandAbort()
 			if ctx.Err() == context.DeadlineExceeded {
 				tc.logger.Task().Errorf("Command stopped early, idle timeout duration of %d seconds has been reached: %s", int(tc.timeout.idleTimeoutDuration.Seconds()), ctx.Err())
 			} else {
@@ -142,6 +144,21 @@ func (a *Agent) runCommandSet(ctx context.Context, tc *taskContext, commandInfo
 			}
 			return errors.Wrap(ctx.Err(), "Agent stopped early")
 		}
+		tc.disallowCommandAbort()
+		if isCommandAborted(aborted) {
+			// Skip the rest of the block but continue running the task.
+			tc.logger.Task().Warningf("Command %s was aborted by request after %s; continuing with the remaining commands", fullCommandName, time.Since(start).String())
+			tc.addAbortedCommand(fullCommandName)
+			return nil
+		}
+		if err != nil {
+			tc.logger.Task().Errorf("Command failed: %v", err)
+			if options.isTaskCommands || options.failPreAndPost ||
+				(cmd.Name() == "git.get_project" && tc.taskModel.Requester == evergreen.MergeTestRequester) {
+				// any git.get_project in the commit queue should fail
+				return errors.Wrap(err, "command failed")
+			}
+		}
 		tc.logger.Task().Infof("Finished %s in %s", fullCommandName, time.Since(start).String())
 		if (options.isTaskCommands || options.failPreAndPost) && a.endTaskResp != nil && !a.endTaskResp.ShouldContinue {
 			// only error if we're running a command that should fail, and we don't want to continue to run other tasks
@@ -14,7 +14,7 @@ import (
 )
 
 type autoExtract struct {
-	ArchivePath string `mapstructure:"path" plugin:"expand"`
+	ArchivePath string `mapstructure:"path" plugin:"expand,required"`
 
 	TargetDirectory string `mapstructure:"destination" plugin:"expand"`
 
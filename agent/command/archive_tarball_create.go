@@ -20,14 +20,14 @@ import (
 // Plugin command responsible for creating a tgz archive.
 type tarballCreate struct {
 	// the tgz file that will be created
-	Target string `mapstructure:"target" plugin:"expand"`
+	Target string `mapstructure:"target" plugin:"expand,required"`
 
 	// the directory to compress
-	SourceDir string `mapstructure:"source_dir" plugin:"expand"`
+	SourceDir string `mapstructure:"source_dir" plugin:"expand,required"`
 
 	// a list of filename blobs to include,
 	// e.g. "*.tgz", "file.txt", "test_*"
-	Include []string `mapstructure:"include" plugin:"expand"`
+	Include []string `mapstructure:"include" plugin:"expand,required"`
 
 	// a list of filename blobs to exclude,
 	// e.g. "*.zip", "results.out", "ignore/**"
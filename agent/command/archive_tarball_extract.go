@@ -15,9 +15,9 @@ import (
 )
 
 type tarballExtract struct {
-	ArchivePath string `mapstructure:"path" plugin:"expand"`
+	ArchivePath string `mapstructure:"path" plugin:"expand,required"`
 
-	TargetDirectory string `mapstructure:"destination" plugin:"expand"`
+	TargetDirectory string `mapstructure:"destination" plugin:"expand,required"`
 
 	// a list of filename blobs to exclude when extracting
 	ExcludeFiles []string `mapstructure:"exclude_files" plugin:"expand"`
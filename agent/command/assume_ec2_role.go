@@ -27,7 +27,7 @@ const (
 type ec2AssumeRole struct {
 	// The Amazon Resource Name (ARN) of the role to assume.
 	// Required.
-	RoleARN string `mapstructure:"role_arn" plugin:"expand"`
+	RoleARN string `mapstructure:"role_arn" plugin:"expand,required"`
 
 	// A unique identifier that might be required when you assume a role in another account.
 	ExternalId string `mapstructure:"external_id" plugin:"expand"`
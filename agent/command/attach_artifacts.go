@@ -18,7 +18,7 @@ import (
 
 type attachArtifacts struct {
 	// Files is a list of files, using gitignore syntax.
-	Files []string `mapstructure:"files" plugin:"expand"`
+	Files []string `mapstructure:"files" plugin:"expand,required"`
 
 	// Prefix is an optional directory prefix to start file globbing in, relative to Evergreen's working directory.
 	Prefix string `mapstructure:"prefix" plugin:"expand"`
@@ -17,7 +17,7 @@ import (
 // sets the downstream parameters for a patch
 type setDownstream struct {
 	// Filename for a yaml file containing key-value pairs
-	YamlFile          string `mapstructure:"file"`
+	YamlFile          string `mapstructure:"file" plugin:"required"`
 	IgnoreMissingFile bool   `mapstructure:"ignore_missing_file"`
 	base
 
@@ -23,10 +23,10 @@ import (
 )
 
 type subprocessExec struct {
-	Binary  string            `mapstructure:"binary"`
+	Binary  string            `mapstructure:"binary" plugin:"required=command"`
 	Args    []string          `mapstructure:"args"`
 	Env     map[string]string `mapstructure:"env"`
-	Command string            `mapstructure:"command"`
+	Command string            `mapstructure:"command" plugin:"required=command"`
 	Path    []string          `mapstructure:"add_to_path"`
 
 	// Add defined expansions to the environment of the process
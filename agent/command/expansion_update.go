@@ -31,13 +31,13 @@ type update struct {
 // and the value they expand to
 type updateParams struct {
 	// The name of the expansion
-	Key string
+	Key string `mapstructure:"key" plugin:"required"`
 
 	// The expanded value
-	Value string
+	Value string `mapstructure:"value"`
 
 	// Can optionally concat a string to the end of the current value
-	Concat string
+	Concat string `mapstructure:"concat"`
 }
 
 func updateExpansionsFactory() Command { return &update{} }
@@ -23,7 +23,7 @@ var (
 )
 
 type expansionsWriter struct {
-	File     string `mapstructure:"file" plugin:"expand"`
+	File     string `mapstructure:"file" plugin:"expand,required"`
 	Redacted bool   `mapstructure:"redacted"`
 
 	base
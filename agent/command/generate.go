@@ -20,7 +20,7 @@ import (
 
 type generateTask struct {
 	// Files are a list of JSON documents.
-	Files []string `mapstructure:"files" plugin:"expand"`
+	Files []string `mapstructure:"files" plugin:"expand,required"`
 
 	// Optional causes generate.tasks to noop if no files match
 	Optional bool `mapstructure:"optional"`
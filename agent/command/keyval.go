@@ -13,8 +13,8 @@ import (
 )
 
 type keyValInc struct {
-	Key         string `mapstructure:"key"`
-	Destination string `mapstructure:"destination"`
+	Key         string `mapstructure:"key" plugin:"required"`
+	Destination string `mapstructure:"destination" plugin:"required"`
 	base
 }
 
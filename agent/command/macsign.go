@@ -20,11 +20,11 @@ import (
 type macSign struct {
 	// KeyId and Secret are the credentials for
 	// authenticating into the macOS signing and notarization service.
-	KeyId  string `mapstructure:"key_id" plugin:"expand"`
-	Secret string `mapstructure:"secret" plugin:"expand"`
+	KeyId  string `mapstructure:"key_id" plugin:"expand,required"`
+	Secret string `mapstructure:"secret" plugin:"expand,required"`
 
 	// ServiceUrl is the url of the macOS signing and notarization service
-	ServiceUrl string `mapstructure:"service_url" plugin:"expand"`
+	ServiceUrl string `mapstructure:"service_url" plugin:"expand,required"`
 
 	// ClientBinary is the path to the macOS signing and notarization service client.
 	// If empty default location(/usr/local/bin/macnotary) will be used.
@@ -32,11 +32,11 @@ type macSign struct {
 
 	// LocalZipFile is the local filepath to the zip file the user
 	// wishes to sign. It should contains the list of artifacts that need to be signed.
-	LocalZipFile string `mapstructure:"local_zip_file" plugin:"expand"`
+	LocalZipFile string `mapstructure:"local_zip_file" plugin:"expand,required"`
 
 	// OutputZipFile is the local filepath to the zip file the service outputs
 	// It will contain the list of artifacts that are signed by the server.
-	OutputZipFile string `mapstructure:"output_zip_file" plugin:"expand"`
+	OutputZipFile string `mapstructure:"output_zip_file" plugin:"expand,required"`
 
 	// ArtifactType is a type of artifact(s) that need to be signed.
 	// Currently supported list: app, binary.
@@ -33,7 +33,7 @@ type perfSend struct {
 
 	// File is the file containing either the json or yaml representation
 	// of the performance report tests.
-	File string `mapstructure:"file" plugin:"expand"`
+	File string `mapstructure:"file" plugin:"expand,required"`
 
 	base
 }
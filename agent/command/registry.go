@@ -58,6 +58,7 @@ func init() {
 		"subprocess.exec":                       subprocessExecFactory,
 		"subprocess.scripting":                  subprocessScriptingFactory,
 		"setup.initial":                         initialSetupFactory,
+		"task_outputs.set":                      taskOutputsSetFactory,
 		"timeout.update":                        timeoutUpdateFactory,
 	}
 
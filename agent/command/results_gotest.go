@@ -22,7 +22,7 @@ import (
 type goTestResults struct {
 	// a list of filename blobs to include
 	// e.g. "monitor.suite", "output/*"
-	Files []string `mapstructure:"files" plugin:"expand"`
+	Files []string `mapstructure:"files" plugin:"expand,required"`
 
 	// Optional, when set to true, causes this command to be skipped over without an error when
 	// no files are found to be parsed.
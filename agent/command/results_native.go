@@ -20,7 +20,7 @@ import (
 type attachResults struct {
 	// FileLoc describes the relative path of the file to be sent.
 	// Note that this can also be described via expansions.
-	FileLoc string `mapstructure:"file_location" plugin:"expand"`
+	FileLoc string `mapstructure:"file_location" plugin:"expand,required"`
 	base
 }
 
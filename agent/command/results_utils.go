@@ -11,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/timber/buildlogger"
 	"github.com/evergreen-ci/timber/testresults"
 	"github.com/evergreen-ci/utility"
@@ -106,10 +107,17 @@ func sendTestResultsToCedar(ctx context.Context, conf *internal.TaskConfig, td c
 		}
 	}
 
-	cedarResults, failed := makeCedarTestResults(conf.CedarTestResultsID, conf.Task, results)
+	cedarResults, attachments, failed := makeCedarTestResults(conf.CedarTestResultsID, conf.Task, results)
 	if err = client.AddResults(ctx, cedarResults); err != nil {
 		return errors.Wrap(err, "adding test results")
 	}
+	// Cedar cannot store the test results' artifacts and log ranges, so they
+	// are stored in Evergreen and matched to the results by test name.
+	if len(attachments) > 0 {
+		if err = comm.AttachTestResultAttachments(ctx, td, attachments); err != nil {
+			return errors.Wrap(err, "attaching test result attachments")
+		}
+	}
 
 	if err = client.CloseRecord(ctx, conf.CedarTestResultsID); err != nil {
 		return errors.Wrap(err, "closing test results record")
@@ -171,8 +179,9 @@ func makeCedarTestResultsRecord(conf *internal.TaskConfig, displayTaskInfo *apim
 	}
 }
 
-func makeCedarTestResults(id string, t *task.Task, results *task.LocalTestResults) (testresults.Results, bool) {
+func makeCedarTestResults(id string, t *task.Task, results *task.LocalTestResults) (testresults.Results, []testresult.TestAttachments, bool) {
 	rs := testresults.Results{ID: id}
+	var attachments []testresult.TestAttachments
 	failed := false
 	for _, r := range results.Results {
 		if r.DisplayTestName == "" {
@@ -181,8 +190,18 @@ func makeCedarTestResults(id string, t *task.Task, results *task.LocalTestResult
 		if r.LogTestName == "" {
 			r.LogTestName = r.TestFile
 		}
+		testName := utility.RandomString()
+		if len(r.Artifacts) > 0 || r.TaskLogRange != nil {
+			attachments = append(attachments, testresult.TestAttachments{
+				TaskID:       t.Id,
+				Execution:    t.Execution,
+				TestName:     testName,
+				Artifacts:    r.Artifacts,
+				TaskLogRange: r.TaskLogRange,
+			})
+		}
 		rs.Results = append(rs.Results, testresults.Result{
-			TestName:        utility.RandomString(),
+			TestName:        testName,
 			DisplayTestName: r.DisplayTestName,
 			GroupID:         r.GroupID,
 			Status:          r.Status,
@@ -200,5 +219,5 @@ func makeCedarTestResults(id string, t *task.Task, results *task.LocalTestResult
 		}
 	}
 
-	return rs, failed
+	return rs, attachments, failed
 }
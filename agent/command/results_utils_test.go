@@ -11,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen/agent/internal/client"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	serviceutil "github.com/evergreen-ci/evergreen/service/testutil"
 	"github.com/evergreen-ci/timber/buildlogger"
 	timberutil "github.com/evergreen-ci/timber/testutil"
@@ -145,6 +146,26 @@ func TestSendTestResults(t *testing.T) {
 				assert.False(t, comm.CedarResultsFailed)
 				results.Results[0].DisplayTestName = displayTestName
 			},
+			"SucceedsWithAttachments": func(ctx context.Context, t *testing.T, srv *timberutil.MockTestResultsServer, comm *client.Mock) {
+				results.Results[0].Artifacts = []testresult.Artifact{{Name: "core", Link: "https://example.com/core"}}
+				results.Results[0].TaskLogRange = &testresult.LogRange{StartLine: 1, EndLine: 2}
+				defer func() {
+					results.Results[0].Artifacts = nil
+					results.Results[0].TaskLogRange = nil
+				}()
+				require.NoError(t, sendTestResults(ctx, comm, logger, conf, results))
+
+				checkResults(t, srv)
+				require.Len(t, comm.TestResultAttachments, 1)
+				attachments := comm.TestResultAttachments[0]
+				assert.Equal(t, conf.Task.Id, attachments.TaskID)
+				assert.Equal(t, conf.Task.Execution, attachments.Execution)
+				assert.Equal(t, results.Results[0].Artifacts, attachments.Artifacts)
+				assert.Equal(t, results.Results[0].TaskLogRange, attachments.TaskLogRange)
+				for _, res := range srv.Results {
+					assert.Equal(t, res[0].Results[0].TestName, attachments.TestName)
+				}
+			},
 			"SucceedsNoLogTestName": func(ctx context.Context, t *testing.T, srv *timberutil.MockTestResultsServer, comm *client.Mock) {
 				logTestName := results.Results[0].LogTestName
 				results.Results[0].LogTestName = ""
@@ -187,6 +208,7 @@ func TestSendTestResults(t *testing.T) {
 				srv := setupCedarServer(ctx, t, comm)
 				comm.HasCedarResults = false
 				comm.CedarResultsFailed = false
+				comm.TestResultAttachments = nil
 				testCase(ctx, t, srv.TestResults, comm)
 			})
 		}
@@ -21,8 +21,8 @@ import (
 type xunitResults struct {
 	// File describes the relative path of the file to be sent. Supports globbing.
 	// Note that this can also be described via expansions.
-	File  string   `mapstructure:"file" plugin:"expand"`
-	Files []string `mapstructure:"files" plugin:"expand"`
+	File  string   `mapstructure:"file" plugin:"expand,required=file"`
+	Files []string `mapstructure:"files" plugin:"expand,required=file"`
 	base
 }
 
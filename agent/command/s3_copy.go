@@ -31,8 +31,8 @@ const (
 type s3copy struct {
 	// AwsKey and AwsSecret are the user's credentials for
 	// authenticating interactions with s3.
-	AwsKey    string `mapstructure:"aws_key" plugin:"expand"`
-	AwsSecret string `mapstructure:"aws_secret" plugin:"expand"`
+	AwsKey    string `mapstructure:"aws_key" plugin:"expand,required"`
+	AwsSecret string `mapstructure:"aws_secret" plugin:"expand,required"`
 	// An array of file copy configurations
 	S3CopyFiles []*s3CopyFile `mapstructure:"s3_copy_files" plugin:"expand"`
 
@@ -60,8 +60,8 @@ type s3CopyFile struct {
 	// e.g.
 	//  bucket: mciuploads
 	//  path: linux-64/x86_64/artifact.tgz
-	Source      s3Loc `mapstructure:"source" plugin:"expand"`
-	Destination s3Loc `mapstructure:"destination" plugin:"expand"`
+	Source      s3Loc `mapstructure:"source" plugin:"expand,required"`
+	Destination s3Loc `mapstructure:"destination" plugin:"expand,required"`
 
 	// Permissions is the ACL to apply to the copied file. See:
 	//  http://docs.aws.amazon.com/AmazonS3/latest/dev/acl-overview.html#canned-acl
@@ -92,7 +92,7 @@ type s3Loc struct {
 	Bucket string `mapstructure:"bucket" plugin:"expand"`
 
 	// Path is the file path within the bucket.
-	Path string `mapstructure:"path" plugin:"expand"`
+	Path string `mapstructure:"path" plugin:"expand,required"`
 }
 
 func s3CopyFactory() Command   { return &s3copy{} }
@@ -23,18 +23,18 @@ import (
 type s3get struct {
 	// AwsKey and AwsSecret are the user's credentials for
 	// authenticating interactions with s3.
-	AwsKey    string `mapstructure:"aws_key" plugin:"expand"`
-	AwsSecret string `mapstructure:"aws_secret" plugin:"expand"`
+	AwsKey    string `mapstructure:"aws_key" plugin:"expand,required"`
+	AwsSecret string `mapstructure:"aws_secret" plugin:"expand,required"`
 
 	// RemoteFile is the filepath of the file to get, within its bucket
-	RemoteFile string `mapstructure:"remote_file" plugin:"expand"`
+	RemoteFile string `mapstructure:"remote_file" plugin:"expand,required"`
 
 	// Region is the s3 region where the bucket is located. It defaults to
 	// "us-east-1".
 	Region string `mapstructure:"region" plugin:"region"`
 
 	// Bucket is the s3 bucket holding the desired file
-	Bucket string `mapstructure:"bucket" plugin:"expand"`
+	Bucket string `mapstructure:"bucket" plugin:"expand,required"`
 
 	// BuildVariants stores a list of MCI build variants to run the command for.
 	// If the list is empty, it runs for all build variants.
@@ -44,8 +44,8 @@ type s3get struct {
 	// s3 resource should be downloaded as-is to the specified file, and
 	// extract_to indicates that the remote resource is a .tgz file to be
 	// downloaded to the specified directory.
-	LocalFile string `mapstructure:"local_file" plugin:"expand"`
-	ExtractTo string `mapstructure:"extract_to" plugin:"expand"`
+	LocalFile string `mapstructure:"local_file" plugin:"expand,required=destination"`
+	ExtractTo string `mapstructure:"extract_to" plugin:"expand,required=destination"`
 
 	bucket pail.Bucket
 
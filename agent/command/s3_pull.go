@@ -66,7 +66,7 @@ type s3Pull struct {
 	base
 
 	FromBuildVariant string `mapstructure:"from_build_variant"`
-	Task             string `mapstructure:"task"`
+	Task             string `mapstructure:"task" plugin:"required"`
 	WorkingDir       string `mapstructure:"working_directory" plugin:"expand"`
 	DeleteOnSync     bool   `mapstructure:"delete_on_sync"`
 }
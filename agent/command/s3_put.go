@@ -31,30 +31,30 @@ import (
 type s3put struct {
 	// AwsKey and AwsSecret are the user's credentials for
 	// authenticating interactions with s3.
-	AwsKey    string `mapstructure:"aws_key" plugin:"expand"`
-	AwsSecret string `mapstructure:"aws_secret" plugin:"expand"`
+	AwsKey    string `mapstructure:"aws_key" plugin:"expand,required"`
+	AwsSecret string `mapstructure:"aws_secret" plugin:"expand,required"`
 
 	// LocalFile is the local filepath to the file the user
 	// wishes to store in s3
-	LocalFile string `mapstructure:"local_file" plugin:"expand"`
+	LocalFile string `mapstructure:"local_file" plugin:"expand,required=local_file"`
 
 	// LocalFilesIncludeFilter is an array of expressions that specify what files should be
 	// included in this upload.
-	LocalFilesIncludeFilter []string `mapstructure:"local_files_include_filter" plugin:"expand"`
+	LocalFilesIncludeFilter []string `mapstructure:"local_files_include_filter" plugin:"expand,required=local_file"`
 
 	// LocalFilesIncludeFilterPrefix is an optional path to start processing the LocalFilesIncludeFilter, relative to the working directory.
 	LocalFilesIncludeFilterPrefix string `mapstructure:"local_files_include_filter_prefix" plugin:"expand"`
 
 	// RemoteFile is the filepath to store the file to,
 	// within an s3 bucket. Is a prefix when multiple files are uploaded via LocalFilesIncludeFilter.
-	RemoteFile string `mapstructure:"remote_file" plugin:"expand"`
+	RemoteFile string `mapstructure:"remote_file" plugin:"expand,required"`
 
 	// Region is the s3 region where the bucket is located. It defaults to
 	// "us-east-1".
 	Region string `mapstructure:"region" plugin:"region"`
 
 	// Bucket is the s3 bucket to use when storing the desired file
-	Bucket string `mapstructure:"bucket" plugin:"expand"`
+	Bucket string `mapstructure:"bucket" plugin:"expand,required"`
 
 	// Permissions is the ACL to apply to the uploaded file. See:
 	//  http://docs.aws.amazon.com/AmazonS3/latest/dev/acl-overview.html#canned-acl
@@ -63,7 +63,7 @@ type s3put struct {
 
 	// ContentType is the MIME type of the uploaded file.
 	//  E.g. text/html, application/pdf, image/jpeg, ...
-	ContentType string `mapstructure:"content_type" plugin:"expand"`
+	ContentType string `mapstructure:"content_type" plugin:"expand,required"`
 
 	// BuildVariants stores a list of MCI build variants to run the command for.
 	// If the list is empty, it runs for all build variants.
@@ -0,0 +1,158 @@
+package command
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	pluginTag         = "plugin"
+	pluginTagRequired = "required"
+	mapstructureTag   = "mapstructure"
+)
+
+// Parameter types reported in command schemas.
+const (
+	ParamTypeString  = "string"
+	ParamTypeBoolean = "boolean"
+	ParamTypeInteger = "integer"
+	ParamTypeNumber  = "number"
+	ParamTypeArray   = "array"
+	ParamTypeObject  = "object"
+	ParamTypeAny     = "any"
+)
+
+// CommandSchema describes a registered command and the parameters that it
+// accepts.
+type CommandSchema struct {
+	Name   string        `json:"name"`
+	Params []ParamSchema `json:"params"`
+}
+
+// ParamSchema describes a single command parameter. Required parameters are
+// marked with the "required" option in the field's plugin tag. Parameters
+// where any one of several must be set are marked with "required=<group>",
+// with the same group for each alternative.
+type ParamSchema struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	// RequiredGroup is set if at least one of the parameters with the same
+	// group must be set.
+	RequiredGroup string `json:"required_group,omitempty"`
+	// Params describes the fields of object parameters, or of the elements
+	// of array parameters, if they're known.
+	Params []ParamSchema `json:"params,omitempty"`
+}
+
+// RegisteredCommandSchemas returns the schemas for all registered commands,
+// sorted by command name.
+func RegisteredCommandSchemas() []CommandSchema {
+	return evgRegistry.commandSchemas()
+}
+
+func (r *commandRegistry) commandSchemas() []CommandSchema {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]CommandSchema, 0, len(r.cmds))
+	for name, factory := range r.cmds {
+		out = append(out, CommandSchema{
+			Name:   name,
+			Params: paramSchemas(factory()),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+
+	return out
+}
+
+// paramSchemas generates the parameter schemas from the mapstructure tags of
+// the command's fields, which are what its parameters are decoded into.
+func paramSchemas(cmd interface{}) []ParamSchema {
+	return paramSchemasForType(reflect.TypeOf(cmd))
+}
+
+func paramSchemasForType(t reflect.Type) []ParamSchema {
+	out := []ParamSchema{}
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return out
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get(mapstructureTag), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		param := ParamSchema{
+			Name:   name,
+			Type:   paramType(field.Type),
+			Params: nestedParamSchemas(field.Type),
+		}
+		param.Required, param.RequiredGroup = requiredParam(field)
+		out = append(out, param)
+	}
+
+	return out
+}
+
+// requiredParam returns whether the field is required on its own, or else the
+// group of alternatives that it's required as part of.
+func requiredParam(field reflect.StructField) (bool, string) {
+	for _, opt := range strings.Split(field.Tag.Get(pluginTag), ",") {
+		opt = strings.TrimSpace(opt)
+		if opt == pluginTagRequired {
+			return true, ""
+		}
+		if strings.HasPrefix(opt, pluginTagRequired+"=") {
+			return false, strings.TrimPrefix(opt, pluginTagRequired+"=")
+		}
+	}
+	return false, ""
+}
+
+// nestedParamSchemas returns the schemas of the fields of a struct, or of the
+// elements of a slice of structs, and nil for any other type.
+func nestedParamSchemas(t reflect.Type) []ParamSchema {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	params := paramSchemasForType(t)
+	if len(params) == 0 {
+		return nil
+	}
+	return params
+}
+
+func paramType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return ParamTypeString
+	case reflect.Bool:
+		return ParamTypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ParamTypeInteger
+	case reflect.Float32, reflect.Float64:
+		return ParamTypeNumber
+	case reflect.Slice, reflect.Array:
+		return ParamTypeArray
+	case reflect.Map, reflect.Struct:
+		return ParamTypeObject
+	default:
+		return ParamTypeAny
+	}
+}
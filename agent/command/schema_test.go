@@ -0,0 +1,84 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisteredCommandSchemas(t *testing.T) {
+	schemas := RegisteredCommandSchemas()
+	require.Len(t, schemas, len(RegisteredCommandNames()))
+	for i := 1; i < len(schemas); i++ {
+		assert.True(t, schemas[i-1].Name < schemas[i].Name, "schemas should be sorted by name")
+	}
+
+	getParams := func(cmdName string) map[string]ParamSchema {
+		for _, schema := range schemas {
+			if schema.Name != cmdName {
+				continue
+			}
+			params := map[string]ParamSchema{}
+			for _, p := range schema.Params {
+				params[p.Name] = p
+			}
+			return params
+		}
+		require.FailNow(t, "command schema not found", cmdName)
+		return nil
+	}
+
+	params := getParams("s3.get")
+	assert.Equal(t, ParamSchema{Name: "remote_file", Type: ParamTypeString, Required: true}, params["remote_file"])
+	assert.Equal(t, ParamSchema{Name: "local_file", Type: ParamTypeString, RequiredGroup: "destination"}, params["local_file"])
+	assert.Equal(t, ParamSchema{Name: "extract_to", Type: ParamTypeString, RequiredGroup: "destination"}, params["extract_to"])
+	assert.Equal(t, ParamSchema{Name: "build_variants", Type: ParamTypeArray}, params["build_variants"])
+
+	assert.True(t, getParams("shell.exec")["script"].Required)
+	assert.Equal(t, "command", getParams("subprocess.exec")["binary"].RequiredGroup)
+	assert.Equal(t, "command", getParams("subprocess.exec")["command"].RequiredGroup)
+	assert.True(t, getParams("generate.tasks")["files"].Required)
+	assert.True(t, getParams("expansions.write")["file"].Required)
+	assert.True(t, getParams("perf.send")["file"].Required)
+
+	copyFiles := getParams("s3Copy.copy")["s3_copy_files"]
+	assert.Equal(t, ParamTypeArray, copyFiles.Type)
+	copyFileParams := map[string]ParamSchema{}
+	for _, p := range copyFiles.Params {
+		copyFileParams[p.Name] = p
+	}
+	require.Contains(t, copyFileParams, "source")
+	assert.True(t, copyFileParams["source"].Required)
+	assert.Contains(t, copyFileParams["source"].Params, ParamSchema{Name: "path", Type: ParamTypeString, Required: true})
+	assert.Contains(t, copyFileParams["source"].Params, ParamSchema{Name: "bucket", Type: ParamTypeString})
+}
+
+func TestParamSchemas(t *testing.T) {
+	type testParams struct {
+		Name    string            `mapstructure:"name" plugin:"expand,required"`
+		Count   int               `mapstructure:"count"`
+		Ratio   float64           `mapstructure:"ratio"`
+		Enabled *bool             `mapstructure:"enabled"`
+		Env     map[string]string `mapstructure:"env" plugin:"expand"`
+		File    string            `mapstructure:"file" plugin:"expand,required=input"`
+		Files   []struct {
+			Path string `mapstructure:"path" plugin:"required"`
+		} `mapstructure:"files" plugin:"required=input"`
+		Ignored  string `mapstructure:"-"`
+		Untagged string
+	}
+
+	assert.Equal(t, []ParamSchema{
+		{Name: "name", Type: ParamTypeString, Required: true},
+		{Name: "count", Type: ParamTypeInteger},
+		{Name: "ratio", Type: ParamTypeNumber},
+		{Name: "enabled", Type: ParamTypeBoolean},
+		{Name: "env", Type: ParamTypeObject},
+		{Name: "file", Type: ParamTypeString, RequiredGroup: "input"},
+		{Name: "files", Type: ParamTypeArray, RequiredGroup: "input", Params: []ParamSchema{
+			{Name: "path", Type: ParamTypeString, Required: true},
+		}},
+	}, paramSchemas(&testParams{}))
+	assert.Empty(t, paramSchemas("not a struct"))
+}
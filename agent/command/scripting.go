@@ -40,18 +40,18 @@ type scriptingExec struct {
 	// Specify the command to run as a string that Evergreen will
 	// split into an argument array. This, as with subprocess.exec,
 	// is split using shell parsing rules.
-	Command string `mapstructure:"command"`
+	Command string `mapstructure:"command" plugin:"required=run"`
 	// Specify the command to run as a list of arguments.
-	Args []string `mapstructure:"args"`
+	Args []string `mapstructure:"args" plugin:"required=run"`
 
 	// Specify the content of a script to execute in the
 	// environment. This probably only makes sense for roswell,
 	// but is here for completeness, and won't be documented.
-	Script string `mapstructure:"script"`
+	Script string `mapstructure:"script" plugin:"required=run"`
 
 	// TestDir specifies the directory containing the tests that should be run.
 	// This should be a subdirectory of the working directory.
-	TestDir string `mapstructure:"test_dir"`
+	TestDir string `mapstructure:"test_dir" plugin:"required=run"`
 	// TestOptions specifies additional options that determine how tests should
 	// be executed.
 	TestOptions *scriptingTestOptions `mapstructure:"test_options"`
@@ -23,7 +23,7 @@ import (
 // shellExec is responsible for running the shell code.
 type shellExec struct {
 	// Script is the shell code to be run on the agent machine.
-	Script string `mapstructure:"script" plugin:"expand"`
+	Script string `mapstructure:"script" plugin:"expand,required"`
 
 	// Silent, if set to true, prevents shell code/output from being
 	// logged to the agent's task logs. This can be used to avoid
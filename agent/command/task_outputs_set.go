@@ -0,0 +1,90 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// taskOutputsSet publishes structured outputs for the task's dependent tasks.
+// The outputs must match the outputs that the task declares in the project
+// config.
+type taskOutputsSet struct {
+	// File is a YAML file containing the outputs as key-value pairs.
+	File              string `mapstructure:"file" plugin:"required=outputs"`
+	IgnoreMissingFile bool   `mapstructure:"ignore_missing_file"`
+	// Outputs are key-value pairs to publish in addition to the ones in
+	// File. They take precedence over outputs in File with the same name.
+	Outputs map[string]string `mapstructure:"outputs" plugin:"required=outputs"`
+	base
+}
+
+func taskOutputsSetFactory() Command   { return &taskOutputsSet{} }
+func (c *taskOutputsSet) Name() string { return "task_outputs.set" }
+
+// ParseParams validates the input to taskOutputsSet, returning an error if
+// something is incorrect. Fulfills Command interface.
+func (c *taskOutputsSet) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrapf(err, "error parsing '%s' params", c.Name())
+	}
+
+	if c.File == "" && len(c.Outputs) == 0 {
+		return errors.New("must specify either a file or outputs")
+	}
+
+	return nil
+}
+
+// Execute publishes the task's outputs. Fulfills Command interface.
+func (c *taskOutputsSet) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *internal.TaskConfig) error {
+
+	outputs := map[string]string{}
+	if c.File != "" {
+		file, err := conf.Expansions.ExpandString(c.File)
+		if err != nil {
+			return errors.Wrap(err, "expanding file")
+		}
+		filename := getJoinedWithWorkDir(conf, file)
+		if _, err = os.Stat(filename); os.IsNotExist(err) {
+			if !c.IgnoreMissingFile {
+				return errors.Errorf("file '%s' does not exist", filename)
+			}
+		} else {
+			fileData, err := ioutil.ReadFile(filename)
+			if err != nil {
+				return errors.Wrapf(err, "reading file '%s'", filename)
+			}
+			if err = yaml.Unmarshal(fileData, outputs); err != nil {
+				return errors.Wrapf(err, "parsing outputs from file '%s'", filename)
+			}
+		}
+	}
+	for name, value := range c.Outputs {
+		expanded, err := conf.Expansions.ExpandString(value)
+		if err != nil {
+			return errors.Wrapf(err, "expanding output '%s'", name)
+		}
+		outputs[name] = expanded
+	}
+
+	if len(outputs) == 0 {
+		logger.Task().Info("No task outputs to set.")
+		return nil
+	}
+
+	logger.Task().Infof("Setting %d task outputs.", len(outputs))
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	if err := comm.SetTaskOutputs(ctx, td, outputs); err != nil {
+		return errors.Wrapf(err, "setting outputs for task '%s'", conf.Task.Id)
+	}
+
+	return nil
+}
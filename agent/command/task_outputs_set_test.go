@@ -0,0 +1,58 @@
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskOutputsSet(t *testing.T) {
+	for testName, testCase := range map[string]func(t *testing.T, ctx context.Context, comm *client.Mock, conf *internal.TaskConfig, logger client.LoggerProducer, cwd string){
+		"ParseParamsRequiresFileOrOutputs": func(t *testing.T, ctx context.Context, comm *client.Mock, conf *internal.TaskConfig, logger client.LoggerProducer, cwd string) {
+			cmd := taskOutputsSetFactory()
+			assert.Error(t, cmd.ParseParams(map[string]interface{}{}))
+			assert.NoError(t, cmd.ParseParams(map[string]interface{}{"outputs": map[string]string{"key": "value"}}))
+		},
+		"SetsOutputsFromFileAndParams": func(t *testing.T, ctx context.Context, comm *client.Mock, conf *internal.TaskConfig, logger client.LoggerProducer, cwd string) {
+			conf.Expansions = util.NewExpansions(map[string]string{
+				"dir":    filepath.Join(cwd, "testdata", "git"),
+				"binary": "/bin/evergreen",
+			})
+			cmd := &taskOutputsSet{
+				File:    "${dir}/test_expansions.yml",
+				Outputs: map[string]string{"key_1": "override", "binary_path": "${binary}"},
+			}
+			require.NoError(t, cmd.Execute(ctx, comm, logger, conf))
+			assert.Equal(t, "override", comm.TaskOutputs["key_1"])
+			assert.Equal(t, "my_image", comm.TaskOutputs["my_docker_image"])
+			assert.Equal(t, "/bin/evergreen", comm.TaskOutputs["binary_path"])
+		},
+		"MissingFile": func(t *testing.T, ctx context.Context, comm *client.Mock, conf *internal.TaskConfig, logger client.LoggerProducer, cwd string) {
+			cmd := &taskOutputsSet{File: filepath.Join(cwd, "does_not_exist.yml")}
+			assert.Error(t, cmd.Execute(ctx, comm, logger, conf))
+
+			cmd.IgnoreMissingFile = true
+			assert.NoError(t, cmd.Execute(ctx, comm, logger, conf))
+			assert.Nil(t, comm.TaskOutputs)
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			comm := client.NewMock("http://localhost.com")
+			conf := &internal.TaskConfig{Expansions: &util.Expansions{}, Task: &task.Task{}, Project: &model.Project{}}
+			logger, _ := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}, nil)
+			cwd := testutil.GetDirectoryOfFile()
+			testCase(t, ctx, comm, conf, logger, cwd)
+		})
+	}
+}
@@ -13,9 +13,9 @@ import (
 )
 
 type taskDataGet struct {
-	File     string `mapstructure:"file" plugin:"expand"`
-	DataName string `mapstructure:"name" plugin:"expand"`
-	TaskName string `mapstructure:"task" plugin:"expand"`
+	File     string `mapstructure:"file" plugin:"expand,required"`
+	DataName string `mapstructure:"name" plugin:"expand,required"`
+	TaskName string `mapstructure:"task" plugin:"expand,required"`
 	Variant  string `mapstructure:"variant" plugin:"expand"`
 	base
 }
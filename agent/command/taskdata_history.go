@@ -14,9 +14,9 @@ import (
 
 type taskDataHistory struct {
 	Tags     bool   `mapstructure:"tags"`
-	File     string `mapstructure:"file" plugin:"expand"`
-	DataName string `mapstructure:"name" plugin:"expand"`
-	TaskName string `mapstructure:"task" plugin:"expand"`
+	File     string `mapstructure:"file" plugin:"expand,required"`
+	DataName string `mapstructure:"name" plugin:"expand,required"`
+	TaskName string `mapstructure:"task" plugin:"expand,required"`
 	base
 }
 
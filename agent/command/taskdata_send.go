@@ -12,8 +12,8 @@ import (
 )
 
 type taskDataSend struct {
-	File     string `mapstructure:"file" plugin:"expand"`
-	DataName string `mapstructure:"name" plugin:"expand"`
+	File     string `mapstructure:"file" plugin:"expand,required"`
+	DataName string `mapstructure:"name" plugin:"expand,required"`
 	base
 }
 
@@ -0,0 +1,137 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// endTaskManifestFile is the name of the file in the agent's working directory
+// that records how the agent is ending the current task. If the agent restarts
+// before the task is ended, it uses the manifest to finish ending the task.
+const endTaskManifestFile = "end_task_manifest.json"
+
+type endTaskManifest struct {
+	Task   client.TaskData         `json:"task"`
+	Detail apimodels.TaskEndDetail `json:"detail"`
+	// CreatedAt is when the agent started ending the task.
+	CreatedAt time.Time `json:"created_at"`
+	// ResumeBy is when the app server stops waiting for the agent to end the
+	// task.
+	ResumeBy time.Time `json:"resume_by"`
+}
+
+// expired returns whether it's too late to resume ending the task.
+func (m *endTaskManifest) expired(now time.Time) bool {
+	resumeBy := m.ResumeBy
+	if resumeBy.IsZero() {
+		resumeBy = m.CreatedAt.Add(evergreen.EndTaskResumeWindow)
+	}
+	return now.After(resumeBy)
+}
+
+func (a *Agent) endTaskManifestPath() string {
+	return filepath.Join(a.opts.WorkingDirectory, endTaskManifestFile)
+}
+
+// writeEndTaskManifest atomically writes the manifest so that a crash while
+// writing it never leaves a partial manifest behind.
+func (a *Agent) writeEndTaskManifest(m *endTaskManifest) error {
+	if a.opts.WorkingDirectory == "" {
+		return errors.New("agent has no working directory")
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return errors.Wrap(err, "marshalling end task manifest")
+	}
+	path := a.endTaskManifestPath()
+	tmpPath := path + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, b, 0600); err != nil {
+		return errors.Wrap(err, "writing end task manifest")
+	}
+	return errors.Wrap(os.Rename(tmpPath, path), "moving end task manifest into place")
+}
+
+// readEndTaskManifest returns the manifest, or nil if there isn't one.
+func (a *Agent) readEndTaskManifest() (*endTaskManifest, error) {
+	if a.opts.WorkingDirectory == "" {
+		return nil, nil
+	}
+	b, err := ioutil.ReadFile(a.endTaskManifestPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading end task manifest")
+	}
+	m := &endTaskManifest{}
+	if err = json.Unmarshal(b, m); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling end task manifest")
+	}
+	return m, nil
+}
+
+func (a *Agent) removeEndTaskManifest() {
+	if a.opts.WorkingDirectory == "" {
+		return
+	}
+	err := os.Remove(a.endTaskManifestPath())
+	if os.IsNotExist(err) {
+		return
+	}
+	grip.Warning(message.WrapError(err, message.Fields{
+		"message": "could not remove end task manifest",
+		"path":    a.endTaskManifestPath(),
+	}))
+}
+
+// resumeEndTask finishes ending a task that the agent was ending when it last
+// exited, as long as the app server is still waiting for it.
+func (a *Agent) resumeEndTask(ctx context.Context) {
+	m, err := a.readEndTaskManifest()
+	if err != nil {
+		grip.Warning(message.WrapError(err, "could not read end task manifest, discarding it"))
+		a.removeEndTaskManifest()
+		return
+	}
+	if m == nil {
+		return
+	}
+	defer a.removeEndTaskManifest()
+
+	if m.expired(time.Now()) {
+		grip.Notice(message.Fields{
+			"message":   "discarding expired end task manifest",
+			"task_id":   m.Task.ID,
+			"resume_by": m.ResumeBy,
+		})
+		return
+	}
+
+	// The detail has the same idempotency key as the original request, so if
+	// the app server already ended the task, this is a no-op.
+	m.Detail.Resumed = true
+	grip.Info(message.Fields{
+		"message": "resuming ending task",
+		"task_id": m.Task.ID,
+		"status":  m.Detail.Status,
+	})
+	if _, err = a.comm.EndTask(ctx, &m.Detail, m.Task); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "could not resume ending task",
+			"task_id": m.Task.ID,
+		}))
+		return
+	}
+	grip.Infof("Resumed ending task '%s' with status: %s", m.Task.ID, m.Detail.Status)
+}
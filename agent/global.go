@@ -40,6 +40,11 @@ const (
 	// reports an error
 	maxHeartbeats = 10
 
+	// maxHeartbeatBackoff is the longest that the agent delays a heartbeat
+	// when the app server asks it to back off. It keeps the agent's
+	// heartbeats well within the stuck task threshold.
+	maxHeartbeatBackoff = time.Minute
+
 	// dockerTimeout is the duration to timeout the Docker cleanup that happens
 	// after an agent completes a task
 	dockerTimeout = 1 * time.Minute
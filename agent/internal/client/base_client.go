@@ -20,6 +20,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/manifest"
 	patchmodel "github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/juniper/gopb"
@@ -44,7 +45,10 @@ type baseCommunicator struct {
 	httpClient      *http.Client
 	reqHeaders      map[string]string
 	cedarGRPCClient *grpc.ClientConn
-	loggerInfo      LoggerMetadata
+	// cedarConfig is the Cedar config from the last task bootstrap, if any,
+	// so that connecting to Cedar doesn't need another request.
+	cedarConfig *apimodels.CedarConfig
+	loggerInfo  LoggerMetadata
 
 	lastMessageSent time.Time
 	mutex           sync.RWMutex
@@ -116,9 +120,15 @@ func (c *baseCommunicator) resetClient() {
 
 func (c *baseCommunicator) createCedarGRPCConn(ctx context.Context) error {
 	if c.cedarGRPCClient == nil {
-		cc, err := c.GetCedarConfig(ctx)
-		if err != nil {
-			return errors.Wrap(err, "getting cedar config")
+		var err error
+		c.mutex.RLock()
+		cc := c.cedarConfig
+		c.mutex.RUnlock()
+		if cc == nil {
+			cc, err = c.GetCedarConfig(ctx)
+			if err != nil {
+				return errors.Wrap(err, "getting cedar config")
+			}
 		}
 
 		if cc.BaseURL == "" {
@@ -205,6 +215,35 @@ func (c *baseCommunicator) GetTask(ctx context.Context, taskData TaskData) (*tas
 	return task, nil
 }
 
+// GetTaskBootstrap returns all the configuration needed to set up the task.
+func (c *baseCommunicator) GetTaskBootstrap(ctx context.Context, taskData TaskData) (*restmodel.APITaskBootstrap, error) {
+	info := requestInfo{
+		method:   http.MethodGet,
+		path:     fmt.Sprintf("tasks/%s/bootstrap", taskData.ID),
+		taskData: &taskData,
+		version:  apiVersion2,
+	}
+	resp, err := c.retryRequest(ctx, info, nil)
+	if err != nil {
+		return nil, utility.RespErrorf(resp, "getting bootstrap for task '%s': %s", taskData.ID, err.Error())
+	}
+	defer resp.Body.Close()
+
+	bootstrap := &restmodel.APITaskBootstrap{}
+	if err = utility.ReadJSON(resp.Body, bootstrap); err != nil {
+		return nil, errors.Wrapf(err, "reading bootstrap for task '%s'", taskData.ID)
+	}
+	if bootstrap.SchemaVersion != restmodel.TaskBootstrapSchemaVersion {
+		return nil, errors.Errorf("task bootstrap has schema version %d but agent requires version %d", bootstrap.SchemaVersion, restmodel.TaskBootstrapSchemaVersion)
+	}
+
+	c.mutex.Lock()
+	c.cedarConfig = &bootstrap.Cedar
+	c.mutex.Unlock()
+
+	return bootstrap, nil
+}
+
 // GetDisplayTaskInfoFromExecution returns the display task info associated
 // with the execution task.
 func (c *baseCommunicator) GetDisplayTaskInfoFromExecution(ctx context.Context, td TaskData) (*apimodels.DisplayTaskInfo, error) {
@@ -311,7 +350,7 @@ func (c *baseCommunicator) GetExpansions(ctx context.Context, taskData TaskData)
 	return e, nil
 }
 
-func (c *baseCommunicator) Heartbeat(ctx context.Context, taskData TaskData) (string, error) {
+func (c *baseCommunicator) Heartbeat(ctx context.Context, taskData TaskData) (string, time.Duration, error) {
 	data := interface{}("heartbeat")
 	ctx, cancel := context.WithTimeout(ctx, heartbeatTimeout)
 	defer cancel()
@@ -324,26 +363,30 @@ func (c *baseCommunicator) Heartbeat(ctx context.Context, taskData TaskData) (st
 	resp, err := c.request(ctx, info, data)
 	if err != nil {
 		err = errors.Wrapf(err, "error sending heartbeat for task %s", taskData.ID)
-		return "", err
+		return "", 0, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusConflict {
-		return evergreen.TaskConflict, errors.Errorf("Unauthorized - wrong secret")
+		return evergreen.TaskConflict, 0, errors.Errorf("Unauthorized - wrong secret")
 	}
 	if resp.StatusCode != http.StatusOK {
-		return "", errors.Errorf("unexpected status code doing heartbeat: %v",
+		return "", 0, errors.Errorf("unexpected status code doing heartbeat: %v",
 			resp.StatusCode)
 	}
 
 	heartbeatResponse := &apimodels.HeartbeatResponse{}
 	if err = utility.ReadJSON(resp.Body, heartbeatResponse); err != nil {
 		err = errors.Wrapf(err, "Error unmarshaling heartbeat response for task %s", taskData.ID)
-		return "", err
+		return "", 0, err
 	}
+	backoff := time.Duration(heartbeatResponse.BackoffSecs) * time.Second
 	if heartbeatResponse.Abort {
-		return evergreen.TaskFailed, nil
+		return evergreen.TaskFailed, backoff, nil
 	}
-	return "", nil
+	if heartbeatResponse.AbortCommand {
+		return evergreen.TaskCommandAbort, backoff, nil
+	}
+	return "", backoff, nil
 }
 
 // FetchExpansionVars loads expansions for a communicator's task from the API server.
@@ -751,6 +794,77 @@ func (c *baseCommunicator) SetHasCedarResults(ctx context.Context, taskData Task
 	return nil
 }
 
+// AttachTestResultAttachments stores the artifacts and log ranges of the test
+// results that were sent to Cedar.
+func (c *baseCommunicator) AttachTestResultAttachments(ctx context.Context, taskData TaskData, attachments []testresult.TestAttachments) error {
+	info := requestInfo{
+		method:   http.MethodPost,
+		taskData: &taskData,
+		version:  apiVersion2,
+	}
+	info.path = fmt.Sprintf("tasks/%s/test_result_attachments", taskData.ID)
+	resp, err := c.retryRequest(ctx, info, attachments)
+	if err != nil {
+		return utility.RespErrorf(resp, "failed to attach test result attachments for task %s: %s", taskData.ID, err.Error())
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+func (c *baseCommunicator) SetTaskOutputs(ctx context.Context, taskData TaskData, outputs map[string]string) error {
+	info := requestInfo{
+		method:   http.MethodPost,
+		taskData: &taskData,
+		version:  apiVersion2,
+	}
+	info.path = fmt.Sprintf("tasks/%s/outputs", taskData.ID)
+	resp, err := c.retryRequest(ctx, info, outputs)
+	if err != nil {
+		return utility.RespErrorf(resp, "failed to set outputs for task %s: %s", taskData.ID, err.Error())
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// StartFinalizingTask tells the app server that the agent has started ending
+// the task.
+func (c *baseCommunicator) StartFinalizingTask(ctx context.Context, taskData TaskData) (*apimodels.FinalizingTaskResponse, error) {
+	info := requestInfo{
+		method:   http.MethodPost,
+		taskData: &taskData,
+		version:  apiVersion2,
+	}
+	info.path = fmt.Sprintf("tasks/%s/finalizing", taskData.ID)
+	resp, err := c.retryRequest(ctx, info, nil)
+	if err != nil {
+		return nil, utility.RespErrorf(resp, "failed to start finalizing task %s: %s", taskData.ID, err.Error())
+	}
+	defer resp.Body.Close()
+
+	out := &apimodels.FinalizingTaskResponse{}
+	if err = utility.ReadJSON(resp.Body, out); err != nil {
+		return nil, errors.Wrapf(err, "reading finalizing response for task %s", taskData.ID)
+	}
+	return out, nil
+}
+
+// AddAnnotationAttachment attaches a small file to the annotation for the
+// task's current execution.
+func (c *baseCommunicator) AddAnnotationAttachment(ctx context.Context, taskData TaskData, attachment apimodels.AnnotationAttachment) error {
+	info := requestInfo{
+		method:   http.MethodPost,
+		taskData: &taskData,
+		version:  apiVersion2,
+	}
+	info.path = fmt.Sprintf("tasks/%s/annotation_attachments", taskData.ID)
+	resp, err := c.retryRequest(ctx, info, attachment)
+	if err != nil {
+		return utility.RespErrorf(resp, "failed to add annotation attachment '%s' for task %s: %s", attachment.Name, taskData.ID, err.Error())
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
 func (c *baseCommunicator) NewPush(ctx context.Context, taskData TaskData, req *apimodels.S3CopyRequest) (*model.PushLog, error) {
 	newPushLog := model.PushLog{}
 	info := requestInfo{
@@ -816,6 +930,28 @@ func (c *baseCommunicator) AttachFiles(ctx context.Context, taskData TaskData, t
 	return nil
 }
 
+// AttachFilesForTasks attaches files to the task and to other tasks in its
+// display task.
+func (c *baseCommunicator) AttachFilesForTasks(ctx context.Context, taskData TaskData, batch []artifact.TaskFiles) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	info := requestInfo{
+		method:   http.MethodPost,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("files/batch")
+	resp, err := c.retryRequest(ctx, info, batch)
+	if err != nil {
+		return utility.RespErrorf(resp, "failed to post files for tasks from task %s: %s", taskData.ID, err.Error())
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
 func (c *baseCommunicator) SetDownstreamParams(ctx context.Context, downstreamParams []patchmodel.Parameter, taskData TaskData) error {
 	info := requestInfo{
 		method:   http.MethodPost,
@@ -12,6 +12,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/manifest"
 	patchmodel "github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
@@ -46,6 +47,10 @@ type SharedCommunicator interface {
 	StartTask(context.Context, TaskData) error
 	// GetTask returns the active task.
 	GetTask(context.Context, TaskData) (*task.Task, error)
+	// GetTaskBootstrap returns all the configuration needed to set up the
+	// task in a single request. It returns an error if the app server's
+	// response has a schema version that the agent doesn't understand.
+	GetTaskBootstrap(context.Context, TaskData) (*restmodel.APITaskBootstrap, error)
 	// GetDisplayTaskInfoFromExecution returns the display task info of an
 	// execution task, if it exists. It will return an empty struct and no
 	// error if the task is not part of a display task.
@@ -64,7 +69,9 @@ type SharedCommunicator interface {
 	// Returning evergreen.TaskConflict means the agent is no longer authorized to run this task and
 	// should move on to the next available one. Returning evergreen.TaskFailed means that the task
 	// has been aborted. An empty string indicates the heartbeat has succeeded.
-	Heartbeat(context.Context, TaskData) (string, error)
+	// It also returns how much longer than usual the app server asks the agent
+	// to wait before its next heartbeat.
+	Heartbeat(context.Context, TaskData) (string, time.Duration, error)
 	// FetchExpansionVars loads expansions for a communicator's task from the API server.
 	FetchExpansionVars(context.Context, TaskData) (*apimodels.ExpansionVars, error)
 	// GetCedarConfig returns the cedar service information including the
@@ -76,6 +83,19 @@ type SharedCommunicator interface {
 	// SetHasCedarResults sets the HasCedarResults flag to true in the
 	// task and sets CedarResultsFailed if there are failed results.
 	SetHasCedarResults(context.Context, TaskData, bool) error
+	// AttachTestResultAttachments stores the artifacts and log ranges of
+	// test results sent to Cedar, which cannot store them.
+	AttachTestResultAttachments(context.Context, TaskData, []testresult.TestAttachments) error
+	// SetTaskOutputs sets the structured outputs that the task publishes
+	// for its dependent tasks.
+	SetTaskOutputs(context.Context, TaskData, map[string]string) error
+	// StartFinalizingTask tells the app server that the agent has started
+	// ending the task, so that the agent can finish ending it even if it
+	// restarts in the meantime.
+	StartFinalizingTask(context.Context, TaskData) (*apimodels.FinalizingTaskResponse, error)
+	// AddAnnotationAttachment attaches a small file to the annotation for the
+	// task's current execution.
+	AddAnnotationAttachment(context.Context, TaskData, apimodels.AnnotationAttachment) error
 
 	// DisableHost signals to the app server that the host should be disabled.
 	DisableHost(context.Context, string, apimodels.DisableInfo) error
@@ -98,6 +118,9 @@ type SharedCommunicator interface {
 	NewPush(context.Context, TaskData, *apimodels.S3CopyRequest) (*model.PushLog, error)
 	UpdatePushStatus(context.Context, TaskData, *model.PushLog) error
 	AttachFiles(context.Context, TaskData, []*artifact.File) error
+	// AttachFilesForTasks attaches files to the task and to other tasks in
+	// its display task in a single request.
+	AttachFilesForTasks(context.Context, TaskData, []artifact.TaskFiles) error
 	GetManifest(context.Context, TaskData) (*manifest.Manifest, error)
 	KeyValInc(context.Context, TaskData, *model.KeyVal) error
 
@@ -18,11 +18,13 @@ import (
 	"github.com/evergreen-ci/evergreen/model/manifest"
 	patchmodel "github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 	"google.golang.org/grpc"
 )
 
@@ -46,21 +48,25 @@ type Mock struct {
 	ShellExecFilename           string
 	TimeoutFilename             string
 	HeartbeatShouldAbort        bool
+	HeartbeatShouldAbortCommand bool
 	HeartbeatShouldConflict     bool
 	HeartbeatShouldErr          bool
 	HeartbeatShouldSometimesErr bool
+	HeartbeatBackoff            time.Duration
 	TaskExecution               int
+	TaskBootstrapShouldFail     bool
 	CreatedHost                 apimodels.CreateHost
 
 	CedarGRPCConn *grpc.ClientConn
 
-	AttachedFiles      map[string][]*artifact.File
-	LogID              string
-	LocalTestResults   *task.LocalTestResults
-	HasCedarResults    bool
-	CedarResultsFailed bool
-	TestLogs           []*serviceModel.TestLog
-	TestLogCount       int
+	AttachedFiles         map[string][]*artifact.File
+	LogID                 string
+	LocalTestResults      *task.LocalTestResults
+	HasCedarResults       bool
+	CedarResultsFailed    bool
+	TestResultAttachments []testresult.TestAttachments
+	TestLogs              []*serviceModel.TestLog
+	TestLogCount          int
 
 	// data collected by mocked methods
 	logMessages      map[string][]apimodels.LogMessage
@@ -68,6 +74,9 @@ type Mock struct {
 	keyVal           map[string]*serviceModel.KeyVal
 	LastMessageSent  time.Time
 	DownstreamParams []patchmodel.Parameter
+	TaskOutputs      map[string]string
+	FinalizingTasks  []TaskData
+	Attachments      []apimodels.AnnotationAttachment
 
 	mu sync.RWMutex
 }
@@ -149,6 +158,48 @@ func (c *Mock) GetTask(ctx context.Context, td TaskData) (*task.Task, error) {
 	}, nil
 }
 
+// GetTaskBootstrap returns a mock task bootstrap made from the other mock
+// task configuration.
+func (c *Mock) GetTaskBootstrap(ctx context.Context, td TaskData) (*model.APITaskBootstrap, error) {
+	if c.TaskBootstrapShouldFail {
+		return nil, errors.New("task bootstrap should fail")
+	}
+
+	_, file, _, _ := runtime.Caller(0)
+	data, err := ioutil.ReadFile(filepath.Join(filepath.Dir(file), "testdata", fmt.Sprintf("%s.yaml", td.ID)))
+	if err != nil {
+		grip.Error(err)
+	}
+	pp, err := serviceModel.LoadProjectInto(ctx, data, nil, "", &serviceModel.Project{})
+	if err != nil {
+		return nil, err
+	}
+	if pp.Functions == nil {
+		pp.Functions = map[string]*serviceModel.YAMLCommandSet{}
+	}
+	ppBytes, err := bson.Marshal(pp)
+	if err != nil {
+		return nil, err
+	}
+
+	t, _ := c.GetTask(ctx, td)
+	projectRef, _ := c.GetProjectRef(ctx, td)
+	distroView, _ := c.GetDistroView(ctx, td)
+	expansions, _ := c.GetExpansions(ctx, td)
+	expVars, _ := c.FetchExpansionVars(ctx, td)
+	cedar, _ := c.GetCedarConfig(ctx)
+	return &model.APITaskBootstrap{
+		SchemaVersion: model.TaskBootstrapSchemaVersion,
+		Task:          t,
+		ParserProject: ppBytes,
+		ProjectRef:    projectRef,
+		DistroView:    *distroView,
+		Expansions:    expansions,
+		ExpansionVars: *expVars,
+		Cedar:         *cedar,
+	}, nil
+}
+
 func (c *Mock) GetDisplayTaskInfoFromExecution(ctx context.Context, td TaskData) (*apimodels.DisplayTaskInfo, error) {
 	return &apimodels.DisplayTaskInfo{
 		ID:   "mock_display_task_id",
@@ -196,25 +247,28 @@ func (c *Mock) GetExpansions(ctx context.Context, taskData TaskData) (util.Expan
 	return e, nil
 }
 
-func (c *Mock) Heartbeat(ctx context.Context, td TaskData) (string, error) {
+func (c *Mock) Heartbeat(ctx context.Context, td TaskData) (string, time.Duration, error) {
 	if c.HeartbeatShouldAbort {
-		return evergreen.TaskFailed, nil
+		return evergreen.TaskFailed, 0, nil
+	}
+	if c.HeartbeatShouldAbortCommand {
+		return evergreen.TaskCommandAbort, c.HeartbeatBackoff, nil
 	}
 	if c.HeartbeatShouldConflict {
-		return evergreen.TaskConflict, errors.Errorf("Unauthorized - wrong secret")
+		return evergreen.TaskConflict, 0, errors.Errorf("Unauthorized - wrong secret")
 	}
 	if c.HeartbeatShouldSometimesErr {
 		if c.HeartbeatShouldErr {
 			c.HeartbeatShouldErr = false
-			return "", errors.New("mock heartbeat error")
+			return "", 0, errors.New("mock heartbeat error")
 		}
 		c.HeartbeatShouldErr = true
-		return "", nil
+		return "", c.HeartbeatBackoff, nil
 	}
 	if c.HeartbeatShouldErr {
-		return "", errors.New("mock heartbeat error")
+		return "", 0, errors.New("mock heartbeat error")
 	}
-	return "", nil
+	return "", c.HeartbeatBackoff, nil
 }
 
 // FetchExpansionVars returns a mock ExpansionVars.
@@ -389,6 +443,32 @@ func (c *Mock) SetHasCedarResults(ctx context.Context, td TaskData, failed bool)
 	return nil
 }
 
+// AttachTestResultAttachments stores the test result attachments in the mock.
+func (c *Mock) AttachTestResultAttachments(ctx context.Context, td TaskData, attachments []testresult.TestAttachments) error {
+	c.TestResultAttachments = append(c.TestResultAttachments, attachments...)
+	return nil
+}
+
+// SetTaskOutputs sets the task's outputs.
+func (c *Mock) SetTaskOutputs(ctx context.Context, td TaskData, outputs map[string]string) error {
+	c.TaskOutputs = outputs
+	return nil
+}
+
+// StartFinalizingTask records that the task is being ended.
+func (c *Mock) StartFinalizingTask(ctx context.Context, td TaskData) (*apimodels.FinalizingTaskResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.FinalizingTasks = append(c.FinalizingTasks, td)
+	return &apimodels.FinalizingTaskResponse{FinalizingUntil: time.Now().Add(evergreen.EndTaskResumeWindow)}, nil
+}
+
+// AddAnnotationAttachment records the annotation attachment.
+func (c *Mock) AddAnnotationAttachment(ctx context.Context, td TaskData, attachment apimodels.AnnotationAttachment) error {
+	c.Attachments = append(c.Attachments, attachment)
+	return nil
+}
+
 // DisableHost signals to the app server that the host should be disabled.
 func (c *Mock) DisableHost(ctx context.Context, hostID string, info apimodels.DisableInfo) error {
 	return nil
@@ -405,6 +485,20 @@ func (c *Mock) AttachFiles(ctx context.Context, td TaskData, taskFiles []*artifa
 	return nil
 }
 
+// AttachFilesForTasks attaches files to multiple tasks.
+func (c *Mock) AttachFilesForTasks(ctx context.Context, td TaskData, batch []artifact.TaskFiles) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, taskFiles := range batch {
+		for i := range taskFiles.Files {
+			c.AttachedFiles[taskFiles.TaskID] = append(c.AttachedFiles[taskFiles.TaskID], &taskFiles.Files[i])
+		}
+	}
+
+	return nil
+}
+
 func (c *Mock) SetDownstreamParams(ctx context.Context, downstreamParams []patchmodel.Parameter, taskData TaskData) error {
 	c.DownstreamParams = downstreamParams
 	return nil
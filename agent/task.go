@@ -246,6 +246,68 @@ func (tc *taskContext) getCurrentCommand() command.Command {
 	return tc.currentCommand
 }
 
+// allowCommandAbort allows the running task command to be aborted by request
+// until disallowCommandAbort is called. The returned channel is closed if the
+// command is aborted, in which case cancel is also called to stop the command.
+func (tc *taskContext) allowCommandAbort(cancel context.CancelFunc) <-chan struct{} {
+	tc.Lock()
+	defer tc.Unlock()
+	tc.commandAbort = &commandAbort{
+		cancel:  cancel,
+		aborted: make(chan struct{}),
+	}
+	return tc.commandAbort.aborted
+}
+
+func (tc *taskContext) disallowCommandAbort() {
+	tc.Lock()
+	defer tc.Unlock()
+	if tc.commandAbort == nil {
+		return
+	}
+	tc.commandAbort.cancel()
+	tc.commandAbort = nil
+}
+
+// abortCurrentCommand stops the running task command so that the task can
+// continue without it. It returns false if there is no task command running
+// that can be aborted.
+func (tc *taskContext) abortCurrentCommand() bool {
+	tc.Lock()
+	defer tc.Unlock()
+	if tc.commandAbort == nil {
+		return false
+	}
+	close(tc.commandAbort.aborted)
+	tc.commandAbort.cancel()
+	tc.commandAbort = nil
+	return true
+}
+
+func isCommandAborted(aborted <-chan struct{}) bool {
+	select {
+	case <-aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+func (tc *taskContext) addAbortedCommand(name string) {
+	tc.Lock()
+	defer tc.Unlock()
+	tc.abortedCommands = append(tc.abortedCommands, apimodels.AbortedCommand{
+		Command:   name,
+		AbortedAt: time.Now(),
+	})
+}
+
+func (tc *taskContext) getAbortedCommands() []apimodels.AbortedCommand {
+	tc.RLock()
+	defer tc.RUnlock()
+	return append([]apimodels.AbortedCommand{}, tc.abortedCommands...)
+}
+
 func (tc *taskContext) setCurrentIdleTimeout(cmd command.Command) {
 	tc.Lock()
 	defer tc.Unlock()
@@ -350,19 +412,26 @@ func (a *Agent) makeTaskConfig(ctx context.Context, tc *taskContext) (*internal.
 			return nil, err
 		}
 	}
-	grip.Info("Fetching distro configuration.")
-	confDistro, err := a.comm.GetDistroView(ctx, tc.task)
-	if err != nil {
-		return nil, err
+	var err error
+	confDistro := tc.distroView
+	if confDistro == nil {
+		grip.Info("Fetching distro configuration.")
+		confDistro, err = a.comm.GetDistroView(ctx, tc.task)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	grip.Info("Fetching project ref.")
-	confRef, err := a.comm.GetProjectRef(ctx, tc.task)
-	if err != nil {
-		return nil, err
-	}
+	confRef := tc.projectRef
 	if confRef == nil {
-		return nil, errors.New("agent retrieved an empty project ref")
+		grip.Info("Fetching project ref.")
+		confRef, err = a.comm.GetProjectRef(ctx, tc.task)
+		if err != nil {
+			return nil, err
+		}
+		if confRef == nil {
+			return nil, errors.New("agent retrieved an empty project ref")
+		}
 	}
 
 	var confPatch *patch.Patch
@@ -32,6 +32,12 @@ type TaskStartRequest struct {
 // the agent's heartbeat message.
 type HeartbeatResponse struct {
 	Abort bool `json:"abort,omitempty"`
+	// BackoffSecs is how much longer than usual the agent should wait before
+	// its next heartbeat because the app server is shedding load.
+	BackoffSecs int `json:"backoff_secs,omitempty"`
+	// AbortCommand indicates that the agent should abort the task's running
+	// command and continue with the rest of the task.
+	AbortCommand bool `json:"abort_command,omitempty"`
 }
 
 // TaskEndDetail contains data sent from the agent to the API server after each task run.
@@ -47,6 +53,32 @@ type TaskEndDetail struct {
 	OOMTracker      *OOMTrackerInfo `bson:"oom_killer,omitempty" json:"oom_killer,omitempty"`
 	Logs            *TaskLogs       `bson:"-" json:"logs,omitempty"`
 	Modules         ModuleCloneInfo `bson:"modules,omitempty" json:"modules,omitempty"`
+	// AbortedCommands are the task commands that were aborted by request
+	// while the rest of the task continued to run.
+	AbortedCommands []AbortedCommand `bson:"aborted_commands,omitempty" json:"aborted_commands,omitempty"`
+	// Resumed is true if the agent restarted while ending the task and
+	// resumed ending it afterward, in which case some of the task's logs and
+	// results may not have been uploaded.
+	Resumed bool `bson:"resumed,omitempty" json:"resumed,omitempty"`
+
+	// IdempotencyKey identifies the agent's attempt to end the task. The
+	// agent sends the same key when it retries the request, so that the
+	// task is only ended once.
+	IdempotencyKey string `bson:"-" json:"idempotency_key,omitempty"`
+}
+
+// FinalizingTaskResponse is the app server's response to the agent starting
+// to end a task.
+type FinalizingTaskResponse struct {
+	// FinalizingUntil is when the agent's window to finish ending the task
+	// closes.
+	FinalizingUntil time.Time `json:"finalizing_until"`
+}
+
+// AbortedCommand is a task command that was aborted by request.
+type AbortedCommand struct {
+	Command   string    `bson:"command" json:"command"`
+	AbortedAt time.Time `bson:"aborted_at" json:"aborted_at"`
 }
 
 type OOMTrackerInfo struct {
@@ -113,11 +145,14 @@ type NextTaskResponse struct {
 	Build               string `json:"build,omitempty"`
 	ShouldExit          bool   `json:"should_exit,omitempty"`
 	ShouldTeardownGroup bool   `json:"should_teardown_group,omitempty"`
+	// BackoffSecs is the minimum time the agent should wait before asking
+	// for a task again because the app server is shedding load.
+	BackoffSecs int `json:"backoff_secs,omitempty"`
 }
 
 // EndTaskResponse is what is returned when the task ends
 type EndTaskResponse struct {
-	ShouldExit bool `json:"should_exit,omitempty"`
+	ShouldExit bool `bson:"should_exit,omitempty" json:"should_exit,omitempty"`
 }
 
 type CreateHost struct {
@@ -323,3 +358,12 @@ type DistroView struct {
 	DisableShallowClone bool   `json:"disable_shallow_clone"`
 	WorkDir             string `json:"work_dir"`
 }
+
+// AnnotationAttachment is a small file to attach to a task's annotation.
+type AnnotationAttachment struct {
+	Name string `json:"name"`
+	// ContentType is the media type of the file. If it's not set, it's
+	// detected from the file's contents.
+	ContentType string `json:"content_type,omitempty"`
+	Data        []byte `json:"data"`
+}
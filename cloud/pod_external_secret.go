@@ -0,0 +1,98 @@
+package cloud
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/evergreen-ci/cocoa"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// externalSecretCacheTTL is how long a resolved external secret is cached
+// before it is looked up again in Secrets Manager.
+const externalSecretCacheTTL = 5 * time.Minute
+
+// externalSecretCache caches the ARNs of existing secrets that are managed
+// outside of Evergreen, keyed by the ARN or name that references them. Only the
+// ARN is cached and never the secret's value, so the container service always
+// reads the current value of a secret that has been rotated.
+type externalSecretCache struct {
+	secrets map[string]cachedExternalSecret
+	sync.Mutex
+}
+
+type cachedExternalSecret struct {
+	arn        string
+	resolvedAt time.Time
+}
+
+var pkgExternalSecretCache *externalSecretCache
+
+func init() {
+	pkgExternalSecretCache = &externalSecretCache{secrets: map[string]cachedExternalSecret{}}
+}
+
+// ResolveExternalSecret returns the ARN of the existing Secrets Manager secret
+// that the reference names. The reference can be either the secret's ARN or
+// its name. Resolved secrets are cached for a short time, so a secret that was
+// just resolved is not looked up again.
+func ResolveExternalSecret(ctx context.Context, c cocoa.SecretsManagerClient, ref string) (string, error) {
+	return pkgExternalSecretCache.resolve(ctx, c, ref, time.Now())
+}
+
+// InvalidateExternalSecret removes the cached resolution of the external
+// secret reference so that it is looked up again the next time that it's
+// resolved. This should be used when the resolved secret could not be used,
+// since it may have been replaced.
+func InvalidateExternalSecret(ref string) {
+	pkgExternalSecretCache.invalidate(ref)
+}
+
+func (c *externalSecretCache) resolve(ctx context.Context, client cocoa.SecretsManagerClient, ref string, now time.Time) (string, error) {
+	c.Lock()
+	cached, ok := c.secrets[ref]
+	c.Unlock()
+	if ok && now.Sub(cached.resolvedAt) < externalSecretCacheTTL {
+		return cached.arn, nil
+	}
+
+	out, err := client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: utility.ToStringPtr(ref)})
+	if err != nil {
+		c.invalidate(ref)
+		return "", errors.Wrapf(err, "describing external secret '%s'", ref)
+	}
+	if out == nil || utility.FromStringPtr(out.ARN) == "" {
+		c.invalidate(ref)
+		return "", errors.Errorf("external secret '%s' has no ARN", ref)
+	}
+	if !utility.IsZeroTime(utility.FromTimePtr(out.DeletedDate)) {
+		c.invalidate(ref)
+		return "", errors.Errorf("external secret '%s' has been deleted", ref)
+	}
+
+	arn := utility.FromStringPtr(out.ARN)
+	grip.DebugWhen(ok && cached.arn != arn, message.Fields{
+		"message":  "external secret now resolves to a different secret",
+		"ref":      ref,
+		"old_arn":  cached.arn,
+		"new_arn":  arn,
+		"ttl_secs": externalSecretCacheTTL.Seconds(),
+	})
+
+	c.Lock()
+	c.secrets[ref] = cachedExternalSecret{arn: arn, resolvedAt: now}
+	c.Unlock()
+
+	return arn, nil
+}
+
+func (c *externalSecretCache) invalidate(ref string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.secrets, ref)
+}
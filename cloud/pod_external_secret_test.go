@@ -0,0 +1,88 @@
+package cloud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cocoaMock "github.com/evergreen-ci/cocoa/mock"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalSecretCache(t *testing.T) {
+	const arn = "arn:aws:secretsmanager:us-east-1:123456789012:secret:registry-creds-AbCdEf"
+
+	for tName, tCase := range map[string]func(ctx context.Context, t *testing.T, c *externalSecretCache, client *cocoaMock.SecretsManagerClient){
+		"ResolvesExistingSecret": func(ctx context.Context, t *testing.T, c *externalSecretCache, client *cocoaMock.SecretsManagerClient) {
+			resolved, err := c.resolve(ctx, client, arn, time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, arn, resolved)
+			assert.Equal(t, arn, utility.FromStringPtr(client.DescribeSecretInput.SecretId))
+		},
+		"UsesCachedSecretWithinTTL": func(ctx context.Context, t *testing.T, c *externalSecretCache, client *cocoaMock.SecretsManagerClient) {
+			now := time.Now()
+			_, err := c.resolve(ctx, client, arn, now)
+			require.NoError(t, err)
+
+			client.DescribeSecretInput = nil
+			delete(cocoaMock.GlobalSecretCache, arn)
+			resolved, err := c.resolve(ctx, client, arn, now.Add(externalSecretCacheTTL/2))
+			require.NoError(t, err)
+			assert.Equal(t, arn, resolved)
+			assert.Zero(t, client.DescribeSecretInput, "cached secret should not be described again")
+		},
+		"ResolvesAgainAfterTTL": func(ctx context.Context, t *testing.T, c *externalSecretCache, client *cocoaMock.SecretsManagerClient) {
+			now := time.Now()
+			_, err := c.resolve(ctx, client, arn, now)
+			require.NoError(t, err)
+
+			delete(cocoaMock.GlobalSecretCache, arn)
+			_, err = c.resolve(ctx, client, arn, now.Add(2*externalSecretCacheTTL))
+			assert.Error(t, err)
+			assert.NotContains(t, c.secrets, arn)
+		},
+		"ResolvesAgainAfterInvalidation": func(ctx context.Context, t *testing.T, c *externalSecretCache, client *cocoaMock.SecretsManagerClient) {
+			now := time.Now()
+			_, err := c.resolve(ctx, client, arn, now)
+			require.NoError(t, err)
+
+			c.invalidate(arn)
+			client.DescribeSecretInput = nil
+			_, err = c.resolve(ctx, client, arn, now)
+			require.NoError(t, err)
+			assert.NotZero(t, client.DescribeSecretInput)
+		},
+		"FailsWithNonexistentSecret": func(ctx context.Context, t *testing.T, c *externalSecretCache, client *cocoaMock.SecretsManagerClient) {
+			_, err := c.resolve(ctx, client, "nonexistent", time.Now())
+			assert.Error(t, err)
+			assert.Empty(t, c.secrets)
+		},
+		"FailsWithDeletedSecret": func(ctx context.Context, t *testing.T, c *externalSecretCache, client *cocoaMock.SecretsManagerClient) {
+			s := cocoaMock.GlobalSecretCache[arn]
+			s.IsDeleted = true
+			s.Deleted = time.Now()
+			cocoaMock.GlobalSecretCache[arn] = s
+
+			_, err := c.resolve(ctx, client, arn, time.Now())
+			assert.Error(t, err)
+			assert.Empty(t, c.secrets)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cocoaMock.ResetGlobalSecretCache()
+			defer cocoaMock.ResetGlobalSecretCache()
+			cocoaMock.GlobalSecretCache[arn] = cocoaMock.StoredSecret{
+				ARN:     arn,
+				Value:   `{"username":"user","password":"pass"}`,
+				Created: time.Now(),
+			}
+
+			tCase(ctx, t, &externalSecretCache{secrets: map[string]cachedExternalSecret{}}, &cocoaMock.SecretsManagerClient{})
+		})
+	}
+}
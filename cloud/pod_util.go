@@ -233,7 +233,13 @@ func exportECSPodContainerDef(settings *evergreen.Settings, p *pod.Pod) (*cocoa.
 		SetEnvironmentVariables(exportPodEnvVars(settings.Providers.AWS.Pod.SecretsManager, p)).
 		AddPortMappings(*cocoa.NewPortMapping().SetContainerPort(agentPort))
 
-	if p.TaskContainerCreationOpts.RepoUsername != "" && p.TaskContainerCreationOpts.RepoPassword != "" {
+	if secretID := p.TaskContainerCreationOpts.RepoCredsExternalSecret; secretID != "" {
+		// The secret is managed outside of Evergreen, so it must not be
+		// cleaned up along with the pod.
+		def.SetRepositoryCredentials(*cocoa.NewRepositoryCredentials().
+			SetID(secretID).
+			SetOwned(false))
+	} else if p.TaskContainerCreationOpts.RepoUsername != "" && p.TaskContainerCreationOpts.RepoPassword != "" {
 		secretName := makeInternalSecretName(settings.Providers.AWS.Pod.SecretsManager, p, repoCredsSecretName)
 
 		def.SetRepositoryCredentials(*cocoa.NewRepositoryCredentials().
@@ -477,6 +477,21 @@ func TestExportECSPodCreationOptions(t *testing.T) {
 		assert.Equal(t, utility.FromStringPtr(cDef.RepoCreds.NewCreds.Username), p.TaskContainerCreationOpts.RepoUsername)
 		assert.Equal(t, utility.FromStringPtr(cDef.RepoCreds.NewCreds.Password), p.TaskContainerCreationOpts.RepoPassword)
 	})
+	t.Run("SucceedsWithExternalRepositoryCredentials", func(t *testing.T) {
+		settings := validSettings()
+		p := validPod()
+		p.TaskContainerCreationOpts.RepoCredsExternalSecret = "arn:aws:secretsmanager:us-east-1:123456789012:secret:registry-creds-AbCdEf"
+		opts, err := ExportECSPodCreationOptions(settings, p)
+		require.NoError(t, err)
+		require.NotZero(t, opts)
+
+		require.Len(t, opts.ContainerDefinitions, 1)
+		cDef := opts.ContainerDefinitions[0]
+		require.NotZero(t, cDef.RepoCreds)
+		assert.Equal(t, p.TaskContainerCreationOpts.RepoCredsExternalSecret, utility.FromStringPtr(cDef.RepoCreds.ID))
+		assert.False(t, utility.FromBoolPtr(cDef.RepoCreds.Owned))
+		assert.Zero(t, cDef.RepoCreds.NewCreds)
+	})
 	t.Run("OnlyUsesAWSVPCWhenAWSVPCSettingsAreGiven", func(t *testing.T) {
 		settings := validSettings()
 		settings.Providers.AWS.Pod.ECS.AWSVPC = evergreen.AWSVPCConfig{}
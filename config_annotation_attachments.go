@@ -0,0 +1,143 @@
+package evergreen
+
+import (
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// AnnotationAttachmentBackendDB stores annotation attachments in the
+	// database.
+	AnnotationAttachmentBackendDB = "db"
+	// AnnotationAttachmentBackendS3 stores annotation attachments in an S3
+	// bucket.
+	AnnotationAttachmentBackendS3 = "s3"
+
+	// DefaultAnnotationAttachmentMaxSize is the default size limit of a single
+	// annotation attachment.
+	DefaultAnnotationAttachmentMaxSize = 1024 * 1024
+	// MaxAnnotationAttachmentMaxSize is the highest size limit that can be
+	// configured for annotation attachments, which keeps attachments stored in
+	// the database well under the document size limit.
+	MaxAnnotationAttachmentMaxSize = 10 * 1024 * 1024
+)
+
+// ValidAnnotationAttachmentBackends are the backends that can store annotation
+// attachments.
+var ValidAnnotationAttachmentBackends = []string{AnnotationAttachmentBackendDB, AnnotationAttachmentBackendS3}
+
+// DefaultAnnotationAttachmentContentTypes are the content types that can be
+// attached to annotations if none are configured.
+var DefaultAnnotationAttachmentContentTypes = []string{
+	"image/gif",
+	"image/jpeg",
+	"image/png",
+	"text/html",
+	"text/plain",
+	"application/pdf",
+}
+
+// AnnotationAttachmentsConfig configures the small files, such as screenshots
+// and HTML reports, that can be attached to task annotations.
+type AnnotationAttachmentsConfig struct {
+	// Backend is where the contents of attachments are stored. If it's not
+	// set, attachments cannot be added to annotations.
+	Backend string `bson:"backend" json:"backend" yaml:"backend"`
+	// S3 is the bucket that attachments are stored in if the backend is S3.
+	S3 S3Credentials `bson:"s3" json:"s3" yaml:"s3"`
+	// SigningKey is the secret used to sign the URLs that attachments stored
+	// in the database are served from.
+	SigningKey string `bson:"signing_key" json:"signing_key" yaml:"signing_key"`
+	// MaxSizeBytes is the size limit of a single attachment.
+	MaxSizeBytes int `bson:"max_size_bytes" json:"max_size_bytes" yaml:"max_size_bytes"`
+	// AllowedContentTypes are the media types that can be attached.
+	AllowedContentTypes []string `bson:"allowed_content_types" json:"allowed_content_types" yaml:"allowed_content_types"`
+}
+
+func (c *AnnotationAttachmentsConfig) SectionId() string { return "annotation_attachments" }
+
+func (c *AnnotationAttachmentsConfig) Get(env Environment) error {
+	ctx, cancel := env.Context()
+	defer cancel()
+	coll := env.DB().Collection(ConfigCollection)
+
+	res := coll.FindOne(ctx, byId(c.SectionId()))
+	if err := res.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			*c = AnnotationAttachmentsConfig{}
+			return nil
+		}
+		return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+	}
+
+	if err := res.Decode(c); err != nil {
+		return errors.Wrap(err, "problem decoding result")
+	}
+
+	return nil
+}
+
+func (c *AnnotationAttachmentsConfig) Set() error {
+	env := GetEnvironment()
+	ctx, cancel := env.Context()
+	defer cancel()
+	coll := env.DB().Collection(ConfigCollection)
+
+	_, err := coll.UpdateOne(ctx, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"backend":               c.Backend,
+			"s3":                    c.S3,
+			"signing_key":           c.SigningKey,
+			"max_size_bytes":        c.MaxSizeBytes,
+			"allowed_content_types": c.AllowedContentTypes,
+		},
+	}, options.Update().SetUpsert(true))
+
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *AnnotationAttachmentsConfig) ValidateAndDefault() error {
+	if c.Backend == "" {
+		return nil
+	}
+	if c.MaxSizeBytes == 0 {
+		c.MaxSizeBytes = DefaultAnnotationAttachmentMaxSize
+	}
+	if len(c.AllowedContentTypes) == 0 {
+		c.AllowedContentTypes = DefaultAnnotationAttachmentContentTypes
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.ErrorfWhen(!utility.StringSliceContains(ValidAnnotationAttachmentBackends, c.Backend), "invalid annotation attachment backend '%s'", c.Backend)
+	if c.Backend == AnnotationAttachmentBackendS3 {
+		catcher.Wrap(c.S3.Validate(), "invalid S3 credentials for annotation attachments")
+	}
+	catcher.NewWhen(c.Backend == AnnotationAttachmentBackendDB && c.SigningKey == "", "signing key must be set to store annotation attachments in the database")
+	catcher.ErrorfWhen(c.MaxSizeBytes < 0 || c.MaxSizeBytes > MaxAnnotationAttachmentMaxSize, "annotation attachment size limit must be between 0 and %d bytes", MaxAnnotationAttachmentMaxSize)
+	return catcher.Resolve()
+}
+
+// IsEnabled returns whether attachments can be added to annotations.
+func (c *AnnotationAttachmentsConfig) IsEnabled() bool {
+	return c.Backend != ""
+}
+
+// GetMaxSizeBytes returns the size limit of a single attachment.
+func (c *AnnotationAttachmentsConfig) GetMaxSizeBytes() int {
+	if c.MaxSizeBytes <= 0 {
+		return DefaultAnnotationAttachmentMaxSize
+	}
+	return c.MaxSizeBytes
+}
+
+// GetAllowedContentTypes returns the media types that can be attached.
+func (c *AnnotationAttachmentsConfig) GetAllowedContentTypes() []string {
+	if len(c.AllowedContentTypes) == 0 {
+		return DefaultAnnotationAttachmentContentTypes
+	}
+	return c.AllowedContentTypes
+}
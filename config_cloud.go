@@ -148,6 +148,18 @@ type ECSConfig struct {
 	Clusters []ECSClusterConfig `bson:"clusters" json:"clusters" yaml:"clusters"`
 	// CapacityProviders specify the available capacity provider configurations.
 	CapacityProviders []ECSCapacityProvider `bson:"capacity_providers" json:"capacity_providers" yaml:"capacity_providers"`
+	// MaxCPU is the most CPU units that a single pod can use. If zero, there's
+	// no limit.
+	MaxCPU int `bson:"max_cpu" json:"max_cpu" yaml:"max_cpu"`
+	// MaxMemoryMB is the most memory (in MB) that a single pod can use. If
+	// zero, there's no limit.
+	MaxMemoryMB int `bson:"max_memory_mb" json:"max_memory_mb" yaml:"max_memory_mb"`
+	// PoolCPU is the total CPU units that all pods running at once can use. If
+	// zero, there's no limit.
+	PoolCPU int `bson:"pool_cpu" json:"pool_cpu" yaml:"pool_cpu"`
+	// PoolMemoryMB is the total memory (in MB) that all pods running at once
+	// can use. If zero, there's no limit.
+	PoolMemoryMB int `bson:"pool_memory_mb" json:"pool_memory_mb" yaml:"pool_memory_mb"`
 }
 
 // AWSVPCConfig represents configuration when using AWSVPC networking in ECS.
@@ -165,6 +177,12 @@ func (c *ECSConfig) Validate() error {
 	for i, cp := range c.CapacityProviders {
 		catcher.Wrapf(cp.Validate(), "invalid capacity provider at index %d", i)
 	}
+	catcher.NewWhen(c.MaxCPU < 0, "max CPU per pod cannot be negative")
+	catcher.NewWhen(c.MaxMemoryMB < 0, "max memory per pod cannot be negative")
+	catcher.NewWhen(c.PoolCPU < 0, "pool CPU cannot be negative")
+	catcher.NewWhen(c.PoolMemoryMB < 0, "pool memory cannot be negative")
+	catcher.NewWhen(c.MaxCPU > 0 && c.PoolCPU > 0 && c.MaxCPU > c.PoolCPU, "max CPU per pod cannot exceed the pool CPU")
+	catcher.NewWhen(c.MaxMemoryMB > 0 && c.PoolMemoryMB > 0 && c.MaxMemoryMB > c.PoolMemoryMB, "max memory per pod cannot exceed the pool memory")
 	return catcher.Resolve()
 }
 
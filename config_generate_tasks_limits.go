@@ -0,0 +1,117 @@
+package evergreen
+
+import (
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	DefaultMaxGeneratedTasksPerVersion        = 50000
+	DefaultMaxGeneratedVariantsPerVersion     = 500
+	DefaultMaxGeneratedConfigSizeMBPerVersion = 8
+)
+
+// GenerateTasksLimitsConfig limits how much generate.tasks can add to a
+// single version, so that generated configs cannot grow the parser project or
+// the number of tasks to schedule without bound.
+type GenerateTasksLimitsConfig struct {
+	Disabled bool `bson:"disabled" json:"disabled" yaml:"disabled"`
+	// MaxTasksPerVersion is the maximum number of tasks that all generators in
+	// a version can define.
+	MaxTasksPerVersion int `bson:"max_tasks_per_version" json:"max_tasks_per_version" yaml:"max_tasks_per_version"`
+	// MaxVariantsPerVersion is the maximum number of new build variants that
+	// all generators in a version can define.
+	MaxVariantsPerVersion int `bson:"max_variants_per_version" json:"max_variants_per_version" yaml:"max_variants_per_version"`
+	// MaxConfigSizeMBPerVersion is the maximum total size of the JSON that
+	// all generators in a version can output.
+	MaxConfigSizeMBPerVersion int `bson:"max_config_size_mb_per_version" json:"max_config_size_mb_per_version" yaml:"max_config_size_mb_per_version"`
+	// ExemptProjects are the IDs of projects that the limits do not apply to.
+	ExemptProjects []string `bson:"exempt_projects" json:"exempt_projects" yaml:"exempt_projects"`
+}
+
+func (c *GenerateTasksLimitsConfig) SectionId() string { return "generate_tasks_limits" }
+
+func (c *GenerateTasksLimitsConfig) Get(env Environment) error {
+	ctx, cancel := env.Context()
+	defer cancel()
+	coll := env.DB().Collection(ConfigCollection)
+
+	res := coll.FindOne(ctx, byId(c.SectionId()))
+	if err := res.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			*c = GenerateTasksLimitsConfig{}
+			return nil
+		}
+		return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+	}
+
+	if err := res.Decode(c); err != nil {
+		return errors.Wrap(err, "problem decoding result")
+	}
+
+	return nil
+}
+
+func (c *GenerateTasksLimitsConfig) Set() error {
+	env := GetEnvironment()
+	ctx, cancel := env.Context()
+	defer cancel()
+	coll := env.DB().Collection(ConfigCollection)
+
+	_, err := coll.UpdateOne(ctx, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"disabled":                       c.Disabled,
+			"max_tasks_per_version":          c.MaxTasksPerVersion,
+			"max_variants_per_version":       c.MaxVariantsPerVersion,
+			"max_config_size_mb_per_version": c.MaxConfigSizeMBPerVersion,
+			"exempt_projects":                c.ExemptProjects,
+		},
+	}, options.Update().SetUpsert(true))
+
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *GenerateTasksLimitsConfig) ValidateAndDefault() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(c.MaxTasksPerVersion < 0, "maximum generated tasks per version cannot be negative")
+	catcher.NewWhen(c.MaxVariantsPerVersion < 0, "maximum generated variants per version cannot be negative")
+	catcher.NewWhen(c.MaxConfigSizeMBPerVersion < 0, "maximum generated config size per version cannot be negative")
+	return catcher.Resolve()
+}
+
+// AppliesToProject returns whether the limits are enforced for the project.
+func (c *GenerateTasksLimitsConfig) AppliesToProject(projectID string) bool {
+	return !c.Disabled && !utility.StringSliceContains(c.ExemptProjects, projectID)
+}
+
+// GetMaxTasksPerVersion returns the configured maximum number of generated
+// tasks per version, or the default if it has not been set.
+func (c *GenerateTasksLimitsConfig) GetMaxTasksPerVersion() int {
+	if c.MaxTasksPerVersion <= 0 {
+		return DefaultMaxGeneratedTasksPerVersion
+	}
+	return c.MaxTasksPerVersion
+}
+
+// GetMaxVariantsPerVersion returns the configured maximum number of generated
+// variants per version, or the default if it has not been set.
+func (c *GenerateTasksLimitsConfig) GetMaxVariantsPerVersion() int {
+	if c.MaxVariantsPerVersion <= 0 {
+		return DefaultMaxGeneratedVariantsPerVersion
+	}
+	return c.MaxVariantsPerVersion
+}
+
+// GetMaxConfigSizePerVersion returns the configured maximum total size in
+// bytes of generated config per version, or the default if it has not been
+// set.
+func (c *GenerateTasksLimitsConfig) GetMaxConfigSizePerVersion() int {
+	if c.MaxConfigSizeMBPerVersion <= 0 {
+		return DefaultMaxGeneratedConfigSizeMBPerVersion * 1024 * 1024
+	}
+	return c.MaxConfigSizeMBPerVersion * 1024 * 1024
+}
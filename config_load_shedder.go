@@ -0,0 +1,124 @@
+package evergreen
+
+import (
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	DefaultLoadShedderDBLatencyThreshold  = 250 * time.Millisecond
+	DefaultLoadShedderQueueDepthThreshold = 20000
+	DefaultLoadShedderMinBackoff          = 15 * time.Second
+	DefaultLoadShedderMaxBackoff          = 2 * time.Minute
+)
+
+// LoadShedderConfig configures when the app servers shed load. When the
+// database latency or the remote queue depth passes its threshold, agents are
+// asked to back off and non-critical background jobs are deferred.
+type LoadShedderConfig struct {
+	Disabled bool `bson:"disabled" json:"disabled" yaml:"disabled"`
+	// DBLatencyThresholdMS is the database round trip latency above which
+	// the app servers shed load.
+	DBLatencyThresholdMS int `bson:"db_latency_threshold_ms" json:"db_latency_threshold_ms" yaml:"db_latency_threshold_ms"`
+	// QueueDepthThreshold is the number of pending remote queue jobs above
+	// which the app servers shed load.
+	QueueDepthThreshold int `bson:"queue_depth_threshold" json:"queue_depth_threshold" yaml:"queue_depth_threshold"`
+	// MinBackoffSecs is how long agents are asked to back off when the app
+	// servers just start shedding load. The backoff grows with the load up
+	// to MaxBackoffSecs.
+	MinBackoffSecs int `bson:"min_backoff_secs" json:"min_backoff_secs" yaml:"min_backoff_secs"`
+	MaxBackoffSecs int `bson:"max_backoff_secs" json:"max_backoff_secs" yaml:"max_backoff_secs"`
+}
+
+func (c *LoadShedderConfig) SectionId() string { return "load_shedder" }
+
+func (c *LoadShedderConfig) Get(env Environment) error {
+	ctx, cancel := env.Context()
+	defer cancel()
+	coll := env.DB().Collection(ConfigCollection)
+
+	res := coll.FindOne(ctx, byId(c.SectionId()))
+	if err := res.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			*c = LoadShedderConfig{}
+			return nil
+		}
+		return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+	}
+
+	if err := res.Decode(c); err != nil {
+		return errors.Wrap(err, "problem decoding result")
+	}
+
+	return nil
+}
+
+func (c *LoadShedderConfig) Set() error {
+	env := GetEnvironment()
+	ctx, cancel := env.Context()
+	defer cancel()
+	coll := env.DB().Collection(ConfigCollection)
+
+	_, err := coll.UpdateOne(ctx, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"disabled":                c.Disabled,
+			"db_latency_threshold_ms": c.DBLatencyThresholdMS,
+			"queue_depth_threshold":   c.QueueDepthThreshold,
+			"min_backoff_secs":        c.MinBackoffSecs,
+			"max_backoff_secs":        c.MaxBackoffSecs,
+		},
+	}, options.Update().SetUpsert(true))
+
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *LoadShedderConfig) ValidateAndDefault() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(c.DBLatencyThresholdMS < 0, "database latency threshold cannot be negative")
+	catcher.NewWhen(c.QueueDepthThreshold < 0, "queue depth threshold cannot be negative")
+	catcher.NewWhen(c.MinBackoffSecs < 0, "minimum backoff cannot be negative")
+	catcher.NewWhen(c.MaxBackoffSecs < 0, "maximum backoff cannot be negative")
+	catcher.NewWhen(c.MaxBackoffSecs != 0 && c.MaxBackoffSecs < c.MinBackoffSecs, "maximum backoff cannot be less than the minimum backoff")
+	return catcher.Resolve()
+}
+
+// GetDBLatencyThreshold returns the configured database latency threshold, or
+// the default if it has not been set.
+func (c *LoadShedderConfig) GetDBLatencyThreshold() time.Duration {
+	if c.DBLatencyThresholdMS <= 0 {
+		return DefaultLoadShedderDBLatencyThreshold
+	}
+	return time.Duration(c.DBLatencyThresholdMS) * time.Millisecond
+}
+
+// GetQueueDepthThreshold returns the configured queue depth threshold, or the
+// default if it has not been set.
+func (c *LoadShedderConfig) GetQueueDepthThreshold() int {
+	if c.QueueDepthThreshold <= 0 {
+		return DefaultLoadShedderQueueDepthThreshold
+	}
+	return c.QueueDepthThreshold
+}
+
+// GetMinBackoff returns the configured minimum backoff, or the default if it
+// has not been set.
+func (c *LoadShedderConfig) GetMinBackoff() time.Duration {
+	if c.MinBackoffSecs <= 0 {
+		return DefaultLoadShedderMinBackoff
+	}
+	return time.Duration(c.MinBackoffSecs) * time.Second
+}
+
+// GetMaxBackoff returns the configured maximum backoff, or the default if it
+// has not been set.
+func (c *LoadShedderConfig) GetMaxBackoff() time.Duration {
+	if c.MaxBackoffSecs <= 0 {
+		return DefaultLoadShedderMaxBackoff
+	}
+	return time.Duration(c.MaxBackoffSecs) * time.Second
+}
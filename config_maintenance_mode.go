@@ -0,0 +1,80 @@
+package evergreen
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const DefaultMaintenanceModeRetryAfter = time.Minute
+
+// MaintenanceModeConfig configures maintenance mode, in which the app servers
+// stop dispatching new tasks so that running tasks can drain before a deploy.
+// Agents can still heartbeat, upload logs and results, and end their tasks.
+type MaintenanceModeConfig struct {
+	Enabled bool `bson:"enabled" json:"enabled" yaml:"enabled"`
+	// RetryAfterSecs is how long agents are asked to wait before asking for a
+	// task again while in maintenance mode.
+	RetryAfterSecs int `bson:"retry_after_secs" json:"retry_after_secs" yaml:"retry_after_secs"`
+	// StartedAt is when maintenance mode was last enabled.
+	StartedAt time.Time `bson:"started_at" json:"started_at" yaml:"started_at"`
+}
+
+func (c *MaintenanceModeConfig) SectionId() string { return "maintenance_mode" }
+
+func (c *MaintenanceModeConfig) Get(env Environment) error {
+	ctx, cancel := env.Context()
+	defer cancel()
+	coll := env.DB().Collection(ConfigCollection)
+
+	res := coll.FindOne(ctx, byId(c.SectionId()))
+	if err := res.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			*c = MaintenanceModeConfig{}
+			return nil
+		}
+		return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+	}
+
+	if err := res.Decode(c); err != nil {
+		return errors.Wrap(err, "problem decoding result")
+	}
+
+	return nil
+}
+
+func (c *MaintenanceModeConfig) Set() error {
+	env := GetEnvironment()
+	ctx, cancel := env.Context()
+	defer cancel()
+	coll := env.DB().Collection(ConfigCollection)
+
+	_, err := coll.UpdateOne(ctx, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"enabled":          c.Enabled,
+			"retry_after_secs": c.RetryAfterSecs,
+			"started_at":       c.StartedAt,
+		},
+	}, options.Update().SetUpsert(true))
+
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *MaintenanceModeConfig) ValidateAndDefault() error {
+	if c.RetryAfterSecs < 0 {
+		return errors.New("maintenance mode retry interval cannot be negative")
+	}
+	return nil
+}
+
+// GetRetryAfter returns how long agents should wait before asking for a task
+// again, or the default if it has not been set.
+func (c *MaintenanceModeConfig) GetRetryAfter() time.Duration {
+	if c.RetryAfterSecs <= 0 {
+		return DefaultMaintenanceModeRetryAfter
+	}
+	return time.Duration(c.RetryAfterSecs) * time.Second
+}
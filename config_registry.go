@@ -39,10 +39,13 @@ func resetRegistry() error {
 		&CloudProviders{},
 		&CommitQueueConfig{},
 		&ContainerPoolsConfig{},
+		&GenerateTasksLimitsConfig{},
 		&HostInitConfig{},
 		&HostJasperConfig{},
 		&JiraConfig{},
+		&LoadShedderConfig{},
 		&LoggerConfig{},
+		&MaintenanceModeConfig{},
 		&NewRelicConfig{},
 		&NotifyConfig{},
 		&PodInitConfig{},
@@ -54,6 +57,8 @@ func resetRegistry() error {
 		&Settings{},
 		&JIRANotificationsConfig{},
 		&TriggerConfig{},
+		&TracerConfig{},
+		&AnnotationAttachmentsConfig{},
 		&SpawnHostConfig{},
 	}
 
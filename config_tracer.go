@@ -0,0 +1,108 @@
+package evergreen
+
+import (
+	"net/url"
+
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// TracerExporterLog exports finished spans as structured log messages.
+	TracerExporterLog = "log"
+	// TracerExporterOTLP exports finished spans to an OpenTelemetry
+	// collector using OTLP over HTTP.
+	TracerExporterOTLP = "otlp"
+)
+
+// ValidTracerExporters are the exporters that the app servers can send spans
+// to.
+var ValidTracerExporters = []string{TracerExporterLog, TracerExporterOTLP}
+
+// TracerConfig configures the OpenTelemetry tracing of the app servers, which
+// operators can use to profile where time is spent handling requests.
+type TracerConfig struct {
+	Enabled bool `bson:"enabled" json:"enabled" yaml:"enabled"`
+	// Exporter is where finished spans are sent.
+	Exporter string `bson:"exporter" json:"exporter" yaml:"exporter"`
+	// SampleRatio is the fraction of traces that are recorded. If it's not
+	// set, all traces are recorded.
+	SampleRatio float64 `bson:"sample_ratio" json:"sample_ratio" yaml:"sample_ratio"`
+	// OTLPEndpoint is the URL of the collector that the OTLP exporter sends
+	// spans to. If it has no path, spans are sent to the standard
+	// /v1/traces path.
+	OTLPEndpoint string `bson:"otlp_endpoint" json:"otlp_endpoint" yaml:"otlp_endpoint"`
+	// OTLPHeaders are headers sent with every request to the collector, such
+	// as for authentication.
+	OTLPHeaders map[string]string `bson:"otlp_headers" json:"otlp_headers" yaml:"otlp_headers"`
+}
+
+func (c *TracerConfig) SectionId() string { return "tracer" }
+
+func (c *TracerConfig) Get(env Environment) error {
+	ctx, cancel := env.Context()
+	defer cancel()
+	coll := env.DB().Collection(ConfigCollection)
+
+	res := coll.FindOne(ctx, byId(c.SectionId()))
+	if err := res.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			*c = TracerConfig{}
+			return nil
+		}
+		return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+	}
+
+	if err := res.Decode(c); err != nil {
+		return errors.Wrap(err, "problem decoding result")
+	}
+
+	return nil
+}
+
+func (c *TracerConfig) Set() error {
+	env := GetEnvironment()
+	ctx, cancel := env.Context()
+	defer cancel()
+	coll := env.DB().Collection(ConfigCollection)
+
+	_, err := coll.UpdateOne(ctx, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"enabled":       c.Enabled,
+			"exporter":      c.Exporter,
+			"sample_ratio":  c.SampleRatio,
+			"otlp_endpoint": c.OTLPEndpoint,
+			"otlp_headers":  c.OTLPHeaders,
+		},
+	}, options.Update().SetUpsert(true))
+
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *TracerConfig) ValidateAndDefault() error {
+	if c.Enabled && c.Exporter == "" {
+		c.Exporter = TracerExporterLog
+	}
+	catcher := grip.NewBasicCatcher()
+	catcher.ErrorfWhen(c.Exporter != "" && !utility.StringSliceContains(ValidTracerExporters, c.Exporter), "invalid tracer exporter '%s'", c.Exporter)
+	catcher.NewWhen(c.SampleRatio < 0 || c.SampleRatio > 1, "tracer sample ratio must be between 0 and 1")
+	if c.Exporter == TracerExporterOTLP {
+		u, err := url.Parse(c.OTLPEndpoint)
+		catcher.Wrap(err, "invalid OTLP endpoint")
+		catcher.NewWhen(err == nil && (!utility.StringSliceContains([]string{"http", "https"}, u.Scheme) || u.Host == ""), "OTLP endpoint must be an HTTP or HTTPS URL")
+	}
+	return catcher.Resolve()
+}
+
+// GetSampleRatio returns the configured sample ratio, or the default of
+// recording all traces if it has not been set.
+func (c *TracerConfig) GetSampleRatio() float64 {
+	if c.SampleRatio <= 0 {
+		return 1
+	}
+	return c.SampleRatio
+}
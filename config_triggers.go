@@ -1,14 +1,28 @@
 package evergreen
 
 import (
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	// DefaultMaxDownstreamTriggerDepth only allows versions created from
+	// mainline commits to trigger downstream projects.
+	DefaultMaxDownstreamTriggerDepth  = 1
+	DefaultMaxDownstreamTriggerFanOut = 25
+)
+
 type TriggerConfig struct {
 	GenerateTaskDistro string `bson:"generate_distro" json:"generate_distro" yaml:"generate_distro"`
+	// MaxDownstreamDepth is the length of the longest chain of project
+	// triggers that can be started from a single mainline commit.
+	MaxDownstreamDepth int `bson:"max_downstream_depth" json:"max_downstream_depth" yaml:"max_downstream_depth"`
+	// MaxDownstreamFanOut is the maximum number of downstream versions that
+	// a single version can trigger.
+	MaxDownstreamFanOut int `bson:"max_downstream_fan_out" json:"max_downstream_fan_out" yaml:"max_downstream_fan_out"`
 }
 
 func (c *TriggerConfig) SectionId() string { return "triggers" }
@@ -41,12 +55,41 @@ func (c *TriggerConfig) Set() error {
 
 	_, err := coll.UpdateOne(ctx, byId(c.SectionId()), bson.M{
 		"$set": bson.M{
-			"generate_distro": c.GenerateTaskDistro,
+			"generate_distro":        c.GenerateTaskDistro,
+			"max_downstream_depth":   c.MaxDownstreamDepth,
+			"max_downstream_fan_out": c.MaxDownstreamFanOut,
 		},
 	}, options.Update().SetUpsert(true))
 
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
 }
 func (c *TriggerConfig) ValidateAndDefault() error {
-	return nil
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(c.MaxDownstreamDepth < 0, "max downstream trigger depth cannot be negative")
+	catcher.NewWhen(c.MaxDownstreamFanOut < 0, "max downstream trigger fan out cannot be negative")
+	if c.MaxDownstreamDepth == 0 {
+		c.MaxDownstreamDepth = DefaultMaxDownstreamTriggerDepth
+	}
+	if c.MaxDownstreamFanOut == 0 {
+		c.MaxDownstreamFanOut = DefaultMaxDownstreamTriggerFanOut
+	}
+	return catcher.Resolve()
+}
+
+// GetMaxDownstreamDepth returns the configured trigger depth limit, or the
+// default if it has not been set.
+func (c *TriggerConfig) GetMaxDownstreamDepth() int {
+	if c.MaxDownstreamDepth <= 0 {
+		return DefaultMaxDownstreamTriggerDepth
+	}
+	return c.MaxDownstreamDepth
+}
+
+// GetMaxDownstreamFanOut returns the configured trigger fan out limit, or the
+// default if it has not been set.
+func (c *TriggerConfig) GetMaxDownstreamFanOut() int {
+	if c.MaxDownstreamFanOut <= 0 {
+		return DefaultMaxDownstreamTriggerFanOut
+	}
+	return c.MaxDownstreamFanOut
 }
@@ -202,6 +202,7 @@ func NewEnvironment(ctx context.Context, confPath string, db *DBSettings) (Envir
 	catcher.Add(e.initJasper())
 	catcher.Add(e.initDepot(ctx))
 	catcher.Add(e.initSenders(ctx))
+	catcher.Add(e.initTracer())
 	catcher.Add(e.createLocalQueue(ctx))
 	catcher.Add(e.createApplicationQueue(ctx))
 	catcher.Add(e.createNotificationQueue(ctx))
@@ -14,6 +14,18 @@ const (
 	User            = "mci"
 	GithubPatchUser = "github_pull_request"
 	ParentPatchUser = "parent_patch"
+	// StalePatchCleanupUser aborts the tasks of patches that were cleaned up
+	// by their project's stale patch policy.
+	StalePatchCleanupUser = "stale_patch_cleanup"
+	// GithubMergeQueueUser aborts the tasks of merge queue versions whose
+	// merge group was removed from GitHub's merge queue.
+	GithubMergeQueueUser = "github_merge_queue"
+	// TaskQuarantineUser skips the tasks that are quarantined in their
+	// project.
+	TaskQuarantineUser = "task_quarantine"
+	// ExternalGateTimeoutUser deactivates the tasks held by external gates
+	// that timed out without being opened.
+	ExternalGateTimeoutUser = "external_gate_timeout"
 
 	HostRunning       = "running"
 	HostTerminated    = "terminated"
@@ -69,6 +81,12 @@ const (
 	// The task statuses below indicate that a task has finished.
 	TaskSucceeded = "success"
 
+	// TaskSkipped indicates that a task was intentionally not run, for
+	// example because of the requester or the files that changed. Unlike an
+	// unscheduled task, a skipped task is finished and records why it was
+	// skipped.
+	TaskSkipped = "skipped"
+
 	// These statuses indicate the types of failures that are stored in
 	// Task.Status field, build TaskCache and TaskEndDetails.
 	TaskFailed       = "failed"
@@ -98,7 +116,14 @@ const (
 	// Task descriptions
 	TaskDescriptionHeartbeat = "heartbeat"
 	TaskDescriptionStranded  = "stranded"
+	TaskDescriptionMigrated  = "migrated"
 	TaskDescriptionNoResults = "expected test results, but none attached"
+	// TaskDescriptionMissingArtifact is the description of tasks that failed
+	// because they did not attach all of their expected artifacts.
+	TaskDescriptionMissingArtifact = "missing expected artifact"
+	// TaskDescriptionMissingOutput is the description of tasks that failed
+	// because they did not publish all of their required outputs.
+	TaskDescriptionMissingOutput = "missing required output"
 
 	// Task Statuses that are currently used only by the UI, and in tests
 	// (these may be used in old tasks as actual task statuses rather than just
@@ -109,6 +134,9 @@ const (
 
 	// TaskConflict is used only in communication with the Agent
 	TaskConflict = "task-conflict"
+	// TaskCommandAbort is used only in communication with the Agent to abort
+	// the running command without aborting the rest of the task.
+	TaskCommandAbort = "task-command-abort"
 
 	TestFailedStatus         = "fail"
 	TestSilentlyFailedStatus = "silentfail"
@@ -169,6 +197,7 @@ const (
 
 	DefaultTaskActivator   = ""
 	StepbackTaskActivator  = "stepback"
+	BisectTaskActivator    = "bisect"
 	APIServerTaskActivator = "apiserver"
 
 	// StaleContainerTaskMonitor is the special name representing the unit
@@ -273,6 +302,12 @@ const (
 	// end of a patch.
 	DefaultTaskSyncAtEndTimeout = time.Hour
 
+	// EndTaskResumeWindow is how long after an agent starts ending a task
+	// that it can still finish ending it, including after the agent
+	// restarts. The task is not reset for missing heartbeats during the
+	// window.
+	EndTaskResumeWindow = 15 * time.Minute
+
 	DefaultShutdownWaitSeconds = 10
 
 	SaveGenerateTasksError     = "error saving config in `generate.tasks`"
@@ -284,6 +319,7 @@ const (
 var TaskStatuses = []string{
 	TaskStarted,
 	TaskSucceeded,
+	TaskSkipped,
 	TaskFailed,
 	TaskSystemFailed,
 	TaskTestTimedOut,
@@ -336,6 +372,7 @@ func IsUnstartedTaskStatus(status string) bool {
 
 func IsFinishedTaskStatus(status string) bool {
 	if status == TaskSucceeded ||
+		status == TaskSkipped ||
 		IsFailedTaskStatus(status) {
 		return true
 	}
@@ -510,6 +547,7 @@ const (
 	TriggerRequester            = "trigger_request"
 	MergeTestRequester          = "merge_test" // commit queue
 	AdHocRequester              = "ad_hoc"
+	GithubMergeQueueRequester   = "github_merge_queue_request"
 )
 
 var AllRequesterTypes = []string{
@@ -520,6 +558,73 @@ var AllRequesterTypes = []string{
 	TriggerRequester,
 	MergeTestRequester,
 	AdHocRequester,
+	GithubMergeQueueRequester,
+}
+
+// UserRequester is the name of a requester type that is used in project
+// configs. Unlike the internal requester types, these names are stable.
+type UserRequester string
+
+const (
+	PatchVersionUserRequester       UserRequester = "patch"
+	GithubPRUserRequester           UserRequester = "github_pr"
+	GitTagUserRequester             UserRequester = "git_tag"
+	RepotrackerVersionUserRequester UserRequester = "commit"
+	TriggerUserRequester            UserRequester = "trigger"
+	MergeTestUserRequester          UserRequester = "commit_queue"
+	AdHocUserRequester              UserRequester = "ad_hoc"
+	GithubMergeQueueUserRequester   UserRequester = "merge_queue"
+)
+
+// userRequesterTypes maps each user-facing requester type to its internal
+// requester type.
+var userRequesterTypes = map[UserRequester]string{
+	PatchVersionUserRequester:       PatchVersionRequester,
+	GithubPRUserRequester:           GithubPRRequester,
+	GitTagUserRequester:             GitTagRequester,
+	RepotrackerVersionUserRequester: RepotrackerVersionRequester,
+	TriggerUserRequester:            TriggerRequester,
+	MergeTestUserRequester:          MergeTestRequester,
+	AdHocUserRequester:              AdHocRequester,
+	GithubMergeQueueUserRequester:   GithubMergeQueueRequester,
+}
+
+var AllUserRequesterTypes = []UserRequester{
+	PatchVersionUserRequester,
+	GithubPRUserRequester,
+	GitTagUserRequester,
+	RepotrackerVersionUserRequester,
+	TriggerUserRequester,
+	MergeTestUserRequester,
+	AdHocUserRequester,
+	GithubMergeQueueUserRequester,
+}
+
+// Validate checks that the user requester is a known requester type.
+func (r UserRequester) Validate() error {
+	if _, ok := userRequesterTypes[r]; !ok {
+		return errors.Errorf("invalid requester '%s'", r)
+	}
+	return nil
+}
+
+// UserRequesterToInternalRequester returns the internal requester type for
+// the user-facing requester type, or an empty string if it's not a known
+// requester type.
+func UserRequesterToInternalRequester(requester UserRequester) string {
+	return userRequesterTypes[requester]
+}
+
+// InternalRequesterToUserRequester returns the user-facing requester type for
+// the internal requester type, or an empty string if it's not a known
+// requester type.
+func InternalRequesterToUserRequester(requester string) UserRequester {
+	for userRequester, internalRequester := range userRequesterTypes {
+		if internalRequester == requester {
+			return userRequester
+		}
+	}
+	return ""
 }
 
 // Constants related to requester types.
@@ -803,8 +908,13 @@ func IsGitTagRequester(requester string) bool {
 	return requester == GitTagRequester
 }
 
+func IsGithubMergeQueueRequester(requester string) bool {
+	return requester == GithubMergeQueueRequester
+}
+
 func ShouldConsiderBatchtime(requester string) bool {
-	return !IsPatchRequester(requester) && requester != AdHocRequester && requester != GitTagRequester
+	return !IsPatchRequester(requester) && requester != AdHocRequester && requester != GitTagRequester &&
+		requester != GithubMergeQueueRequester
 }
 
 func PermissionsDisabledForTests() bool {
@@ -847,6 +957,7 @@ var (
 	PermissionAnnotations      = "project_task_annotations"
 	PermissionPatches          = "project_patches"
 	PermissionLogs             = "project_logs"
+	PermissionActivationHooks  = "project_activation_hooks"
 	// Distro permissions.
 	PermissionDistroSettings = "distro_settings"
 	PermissionHosts          = "distro_hosts"
@@ -938,6 +1049,14 @@ var (
 		Description: "Not able to view logs",
 		Value:       0,
 	}
+	ActivationHooksBypass = PermissionLevel{
+		Description: "Activate variants without waiting for activation hooks",
+		Value:       10,
+	}
+	ActivationHooksNone = PermissionLevel{
+		Description: "Not able to bypass activation hooks",
+		Value:       0,
+	}
 	DistroSettingsAdmin = PermissionLevel{
 		Description: "Remove distro and edit distro settings",
 		Value:       30,
@@ -983,6 +1102,8 @@ func GetDisplayNameForPermissionKey(permissionKey string) string {
 		return "Git Tag Versions"
 	case PermissionLogs:
 		return "Logs"
+	case PermissionActivationHooks:
+		return "Activation Hooks"
 	case PermissionDistroSettings:
 		return "Distro Settings"
 	case PermissionHosts:
@@ -1029,6 +1150,11 @@ func GetPermissionLevelsForPermissionKey(permissionKey string) []PermissionLevel
 			LogsView,
 			LogsNone,
 		}
+	case PermissionActivationHooks:
+		return []PermissionLevel{
+			ActivationHooksBypass,
+			ActivationHooksNone,
+		}
 	case PermissionDistroSettings:
 		return []PermissionLevel{
 			DistroSettingsEdit,
@@ -1057,6 +1183,7 @@ var ProjectPermissions = []string{
 	PermissionPatches,
 	PermissionGitTagVersions,
 	PermissionLogs,
+	PermissionActivationHooks,
 }
 
 var DistroPermissions = []string{
@@ -21,7 +21,7 @@ func bbGetCreatedTicketsPointers(taskId string) ([]*thirdparty.JiraTicket, error
 		}
 	}
 	settings := evergreen.GetEnvironment().Settings()
-	jiraHandler := thirdparty.NewJiraHandler(*settings.Jira.Export())
+	jiraHandler := thirdparty.NewJiraIntegration(*settings.Jira.Export())
 	for _, ticket := range searchTickets {
 		jiraIssue, err := jiraHandler.GetJIRATicket(ticket)
 		if err != nil {
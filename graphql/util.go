@@ -93,7 +93,18 @@ func setManyTasksScheduled(ctx context.Context, url string, isActive bool, taskI
 			return nil, InputValidationError.Send(ctx, "commit queue tasks cannot be manually scheduled")
 		}
 	}
+	if isActive {
+		if err = model.CheckTaskActivationQuotas(usr.Username(), tasks); err != nil {
+			if model.IsProjectQuotaExceeded(err) {
+				return nil, InputValidationError.Send(ctx, err.Error())
+			}
+			return nil, InternalServerError.Send(ctx, err.Error())
+		}
+	}
 	if err = model.SetActiveState(usr.Username(), isActive, tasks...); err != nil {
+		if model.IsVariantActivationDenied(err) {
+			return nil, Forbidden.Send(ctx, err.Error())
+		}
 		return nil, InternalServerError.Send(ctx, err.Error())
 	}
 
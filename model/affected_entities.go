@@ -0,0 +1,69 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// AffectedEntities are the tasks, builds, and versions that an operation
+// changed. For a dry run, they are the ones that the operation would have
+// changed had it not been a dry run.
+type AffectedEntities struct {
+	TaskIDs    []string `json:"task_ids"`
+	BuildIDs   []string `json:"build_ids"`
+	VersionIDs []string `json:"version_ids"`
+}
+
+// AddTasks adds the tasks along with their builds and versions.
+func (a *AffectedEntities) AddTasks(tasks ...task.Task) {
+	for _, t := range tasks {
+		a.TaskIDs = appendUniqueString(a.TaskIDs, t.Id)
+		a.BuildIDs = appendUniqueString(a.BuildIDs, t.BuildId)
+		a.VersionIDs = appendUniqueString(a.VersionIDs, t.Version)
+	}
+}
+
+// AddTaskIDs adds the tasks with the given IDs along with their builds and
+// versions.
+func (a *AffectedEntities) AddTaskIDs(taskIDs ...string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	tasks, err := task.FindAll(db.Query(task.ByIds(taskIDs)).WithFields(task.IdKey, task.BuildIdKey, task.VersionKey))
+	if err != nil {
+		return errors.Wrap(err, "finding affected tasks")
+	}
+	a.AddTasks(tasks...)
+	return nil
+}
+
+// Merge adds all the entities affected by another operation.
+func (a *AffectedEntities) Merge(other AffectedEntities) {
+	for _, id := range other.TaskIDs {
+		a.TaskIDs = appendUniqueString(a.TaskIDs, id)
+	}
+	for _, id := range other.BuildIDs {
+		a.BuildIDs = appendUniqueString(a.BuildIDs, id)
+	}
+	for _, id := range other.VersionIDs {
+		a.VersionIDs = appendUniqueString(a.VersionIDs, id)
+	}
+}
+
+// sortIDs orders the IDs so that the results are stable.
+func (a *AffectedEntities) sortIDs() {
+	sort.Strings(a.TaskIDs)
+	sort.Strings(a.BuildIDs)
+	sort.Strings(a.VersionIDs)
+}
+
+func appendUniqueString(ids []string, id string) []string {
+	if id == "" || utility.StringSliceContains(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
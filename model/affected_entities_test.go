@@ -0,0 +1,99 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(build.Collection, task.Collection, VersionCollection))
+	}()
+	for tName, tCase := range map[string]func(t *testing.T){
+		"CancelPatch": func(t *testing.T) {
+			affected, err := CancelPatch(&patch.Patch{Version: "v"}, task.AbortInfo{User: "me"}, true)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"t0", "t1", "t2"}, affected.TaskIDs)
+			assert.Equal(t, []string{"b0", "b1"}, affected.BuildIDs)
+			assert.Equal(t, []string{"v"}, affected.VersionIDs)
+
+			builds, err := build.FindBuildsByVersions([]string{"v"})
+			require.NoError(t, err)
+			for _, b := range builds {
+				assert.True(t, b.Activated)
+			}
+			t1, err := task.FindOneId("t1")
+			require.NoError(t, err)
+			require.NotNil(t, t1)
+			assert.False(t, t1.Aborted)
+		},
+		"SetTasksActiveState": func(t *testing.T) {
+			t0, err := task.FindOneId("t0")
+			require.NoError(t, err)
+			require.NotNil(t, t0)
+			affected, err := SetTasksActiveState("me", false, true, []task.Task{*t0})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"t0", "t2"}, affected.TaskIDs)
+			assert.Equal(t, []string{"b0", "b1"}, affected.BuildIDs)
+
+			for _, id := range []string{"t0", "t2"} {
+				dbTask, err := task.FindOneId(id)
+				require.NoError(t, err)
+				require.NotNil(t, dbTask)
+				assert.True(t, dbTask.Activated)
+			}
+		},
+		"MarkTasksReset": func(t *testing.T) {
+			affected, err := MarkTasksReset([]string{"t3"}, true)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"t3"}, affected.TaskIDs)
+
+			t3, err := task.FindOneId("t3")
+			require.NoError(t, err)
+			require.NotNil(t, t3)
+			assert.Equal(t, evergreen.TaskFailed, t3.Status)
+		},
+		"RestartVersion": func(t *testing.T) {
+			affected, err := restartVersion("v", []string{"t1", "t3"}, true, true, "me")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"t1", "t3"}, affected.TaskIDs)
+
+			t1, err := task.FindOneId("t1")
+			require.NoError(t, err)
+			require.NotNil(t, t1)
+			assert.False(t, t1.Aborted)
+			t3, err := task.FindOneId("t3")
+			require.NoError(t, err)
+			require.NotNil(t, t3)
+			assert.Equal(t, evergreen.TaskFailed, t3.Status)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(build.Collection, task.Collection, VersionCollection))
+			require.NoError(t, (&Version{Id: "v"}).Insert())
+			for _, b := range []build.Build{
+				{Id: "b0", Version: "v", Activated: true},
+				{Id: "b1", Version: "v", Activated: true},
+			} {
+				require.NoError(t, b.Insert())
+			}
+			for _, tsk := range []task.Task{
+				{Id: "t0", BuildId: "b0", Version: "v", Activated: true, Status: evergreen.TaskUndispatched},
+				{Id: "t1", BuildId: "b1", Version: "v", Activated: true, Status: evergreen.TaskStarted},
+				{Id: "t2", BuildId: "b1", Version: "v", Activated: true, Status: evergreen.TaskUndispatched,
+					DependsOn: []task.Dependency{{TaskId: "t0", Status: evergreen.TaskSucceeded}}},
+				{Id: "t3", BuildId: "b1", Version: "v", Activated: true, Status: evergreen.TaskFailed},
+			} {
+				require.NoError(t, tsk.Insert())
+			}
+			tCase(t)
+		})
+	}
+}
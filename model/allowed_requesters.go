@@ -0,0 +1,195 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// AllowedRequestersSuggestion is a rewritten project config that replaces the
+// legacy patchable, patch_only, allow_for_git_tag and git_tag_only flags with
+// the equivalent allowed_requesters.
+type AllowedRequestersSuggestion struct {
+	// Config is the rewritten YAML config. It is only a suggestion and is
+	// never applied to the project.
+	Config      string
+	Conversions []AllowedRequestersConversion
+	// Unconverted describes flags that could not be rewritten and were
+	// left in the config as they were.
+	Unconverted []string
+	// Equivalent is whether every task runs for exactly the same requesters
+	// in the rewritten config as in the original config.
+	Equivalent  bool
+	Differences []string
+}
+
+// AllowedRequestersConversion describes the legacy flags on a single task or
+// build variant task that were rewritten.
+type AllowedRequestersConversion struct {
+	Task string
+	// Variant is set if the flags were on a build variant's task rather
+	// than the task definition.
+	Variant           string
+	AllowedRequesters []evergreen.UserRequester
+}
+
+// AllowedRequestersFromLegacyFlags returns the requesters that a task with the
+// given legacy flags runs for.
+func AllowedRequestersFromLegacyFlags(patchable, patchOnly, allowForGitTag, gitTagOnly *bool) ([]evergreen.UserRequester, error) {
+	bvt := BuildVariantTaskUnit{
+		Patchable:      patchable,
+		PatchOnly:      patchOnly,
+		AllowForGitTag: allowForGitTag,
+		GitTagOnly:     gitTagOnly,
+	}
+	var allowed []evergreen.UserRequester
+	for _, requester := range evergreen.AllUserRequesterTypes {
+		if !bvt.SkipOnRequester(evergreen.UserRequesterToInternalRequester(requester)) {
+			allowed = append(allowed, requester)
+		}
+	}
+	if len(allowed) == 0 {
+		return nil, errors.New("flags prevent the task from running for any requester")
+	}
+	return allowed, nil
+}
+
+func hasLegacyRequesterFlags(patchable, patchOnly, allowForGitTag, gitTagOnly *bool) bool {
+	return patchable != nil || patchOnly != nil || allowForGitTag != nil || gitTagOnly != nil
+}
+
+// SuggestAllowedRequesters rewrites the project config to use
+// allowed_requesters instead of the legacy requester flags and checks that
+// the rewritten config runs the same tasks for every requester. The given
+// parser project is not modified.
+func SuggestAllowedRequesters(pp *ParserProject) (*AllowedRequestersSuggestion, error) {
+	yml, err := yaml.Marshal(pp)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling original config")
+	}
+	original, err := createIntermediateProject(yml, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "copying original config")
+	}
+	rewritten, err := createIntermediateProject(yml, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "copying config to rewrite")
+	}
+
+	suggestion := &AllowedRequestersSuggestion{}
+	tasksByName := map[string]parserTask{}
+	for _, pt := range original.Tasks {
+		tasksByName[pt.Name] = pt
+	}
+	for i := range rewritten.Tasks {
+		pt := &rewritten.Tasks[i]
+		if !hasLegacyRequesterFlags(pt.Patchable, pt.PatchOnly, pt.AllowForGitTag, pt.GitTagOnly) {
+			continue
+		}
+		allowed := pt.AllowedRequesters
+		if len(allowed) == 0 {
+			allowed, err = AllowedRequestersFromLegacyFlags(pt.Patchable, pt.PatchOnly, pt.AllowForGitTag, pt.GitTagOnly)
+			if err != nil {
+				suggestion.Unconverted = append(suggestion.Unconverted, fmt.Sprintf("task '%s': %s", pt.Name, err.Error()))
+				continue
+			}
+		}
+		pt.AllowedRequesters = allowed
+		pt.Patchable, pt.PatchOnly, pt.AllowForGitTag, pt.GitTagOnly = nil, nil, nil, nil
+		suggestion.Conversions = append(suggestion.Conversions, AllowedRequestersConversion{
+			Task:              pt.Name,
+			AllowedRequesters: allowed,
+		})
+	}
+	for i := range rewritten.BuildVariants {
+		bv := &rewritten.BuildVariants[i]
+		for j := range bv.Tasks {
+			bvt := &bv.Tasks[j]
+			if !hasLegacyRequesterFlags(bvt.Patchable, bvt.PatchOnly, bvt.AllowForGitTag, bvt.GitTagOnly) {
+				continue
+			}
+			allowed := bvt.AllowedRequesters
+			if len(allowed) == 0 {
+				// Flags that the variant task does not set are inherited
+				// from the task definition.
+				pt := tasksByName[bvt.Name]
+				allowed, err = AllowedRequestersFromLegacyFlags(
+					firstBoolPtr(bvt.Patchable, pt.Patchable),
+					firstBoolPtr(bvt.PatchOnly, pt.PatchOnly),
+					firstBoolPtr(bvt.AllowForGitTag, pt.AllowForGitTag),
+					firstBoolPtr(bvt.GitTagOnly, pt.GitTagOnly),
+				)
+				if err != nil {
+					suggestion.Unconverted = append(suggestion.Unconverted, fmt.Sprintf("task '%s' in variant '%s': %s", bvt.Name, bv.Name, err.Error()))
+					continue
+				}
+			}
+			bvt.AllowedRequesters = allowed
+			bvt.Patchable, bvt.PatchOnly, bvt.AllowForGitTag, bvt.GitTagOnly = nil, nil, nil, nil
+			suggestion.Conversions = append(suggestion.Conversions, AllowedRequestersConversion{
+				Task:              bvt.Name,
+				Variant:           bv.Name,
+				AllowedRequesters: allowed,
+			})
+		}
+	}
+
+	before, err := TranslateProject(original)
+	if err != nil {
+		return nil, errors.Wrap(err, "translating original config")
+	}
+	after, err := TranslateProject(rewritten)
+	if err != nil {
+		return nil, errors.Wrap(err, "translating rewritten config")
+	}
+	suggestion.Differences = CheckAllowedRequestersEquivalent(before, after)
+	suggestion.Equivalent = len(suggestion.Differences) == 0
+
+	// Clear fields that only Evergreen sets so that the suggestion can be
+	// used as a config file.
+	rewritten.Id = ""
+	rewritten.ConfigUpdateNumber = 0
+	rewritten.UpdatedByGenerators = nil
+	out, err := yaml.Marshal(rewritten)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling rewritten config")
+	}
+	suggestion.Config = string(out)
+
+	return suggestion, nil
+}
+
+// CheckAllowedRequestersEquivalent returns a description of each build variant
+// task that does not run for the same requesters in both projects.
+func CheckAllowedRequestersEquivalent(before, after *Project) []string {
+	afterBVTs := map[string]BuildVariantTaskUnit{}
+	for _, bvt := range after.FindAllBuildVariantTasks() {
+		afterBVTs[bvt.Variant+"/"+bvt.Name] = bvt
+	}
+
+	var differences []string
+	for _, bvt := range before.FindAllBuildVariantTasks() {
+		afterBVT, ok := afterBVTs[bvt.Variant+"/"+bvt.Name]
+		if !ok {
+			differences = append(differences, fmt.Sprintf("task '%s' in variant '%s' is missing", bvt.Name, bvt.Variant))
+			continue
+		}
+		for _, requester := range evergreen.AllRequesterTypes {
+			if bvt.SkipOnRequester(requester) != afterBVT.SkipOnRequester(requester) {
+				differences = append(differences, fmt.Sprintf("task '%s' in variant '%s' runs differently for requester '%s'", bvt.Name, bvt.Variant, requester))
+			}
+		}
+	}
+	return differences
+}
+
+func firstBoolPtr(ptrs ...*bool) *bool {
+	for _, ptr := range ptrs {
+		if ptr != nil {
+			return ptr
+		}
+	}
+	return nil
+}
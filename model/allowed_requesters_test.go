@@ -0,0 +1,126 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowedRequestersFromLegacyFlags(t *testing.T) {
+	t.Run("PatchOnlyAllowsPatchRequesters", func(t *testing.T) {
+		allowed, err := AllowedRequestersFromLegacyFlags(nil, utility.TruePtr(), nil, nil)
+		require.NoError(t, err)
+		for _, requester := range evergreen.AllUserRequesterTypes {
+			if evergreen.IsPatchRequester(evergreen.UserRequesterToInternalRequester(requester)) {
+				assert.Contains(t, allowed, requester)
+			} else {
+				assert.NotContains(t, allowed, requester)
+			}
+		}
+	})
+	t.Run("NotPatchableExcludesPatchRequesters", func(t *testing.T) {
+		allowed, err := AllowedRequestersFromLegacyFlags(utility.FalsePtr(), nil, nil, nil)
+		require.NoError(t, err)
+		assert.Contains(t, allowed, evergreen.RepotrackerVersionUserRequester)
+		assert.NotContains(t, allowed, evergreen.PatchVersionUserRequester)
+		assert.NotContains(t, allowed, evergreen.GithubPRUserRequester)
+	})
+	t.Run("ContradictoryFlagsError", func(t *testing.T) {
+		_, err := AllowedRequestersFromLegacyFlags(nil, utility.TruePtr(), nil, utility.TruePtr())
+		assert.Error(t, err)
+	})
+}
+
+func TestSkipOnRequesterWithAllowedRequesters(t *testing.T) {
+	bvt := BuildVariantTaskUnit{
+		AllowedRequesters: []evergreen.UserRequester{evergreen.GitTagUserRequester},
+		Patchable:         utility.FalsePtr(),
+	}
+	assert.False(t, bvt.SkipOnRequester(evergreen.GitTagRequester))
+	assert.True(t, bvt.SkipOnRequester(evergreen.RepotrackerVersionRequester))
+	assert.True(t, bvt.SkipOnRequester(evergreen.PatchVersionRequester))
+	assert.False(t, bvt.SkipOnNonGitTagBuild())
+}
+
+func TestUserRequesters(t *testing.T) {
+	for _, requester := range evergreen.AllUserRequesterTypes {
+		assert.NoError(t, requester.Validate())
+		internal := evergreen.UserRequesterToInternalRequester(requester)
+		assert.Contains(t, evergreen.AllRequesterTypes, internal)
+		assert.Equal(t, requester, evergreen.InternalRequesterToUserRequester(internal))
+	}
+	assert.Len(t, evergreen.AllUserRequesterTypes, len(evergreen.AllRequesterTypes))
+	assert.Error(t, evergreen.UserRequester(evergreen.PatchVersionRequester).Validate())
+	assert.Empty(t, evergreen.UserRequesterToInternalRequester("nonexistent"))
+}
+
+func TestAllowedRequestersYAML(t *testing.T) {
+	yml := `
+tasks:
+- name: t1
+  allowed_requesters: ["commit", "merge_queue"]
+buildvariants:
+- name: bv1
+  run_on: d1
+  tasks:
+  - name: t1
+`
+	pp, err := createIntermediateProject([]byte(yml), false)
+	require.NoError(t, err)
+	p, err := TranslateProject(pp)
+	require.NoError(t, err)
+	bvts := p.FindAllBuildVariantTasks()
+	require.Len(t, bvts, 1)
+	assert.False(t, bvts[0].SkipOnRequester(evergreen.RepotrackerVersionRequester))
+	assert.False(t, bvts[0].SkipOnRequester(evergreen.GithubMergeQueueRequester))
+	assert.True(t, bvts[0].SkipOnRequester(evergreen.PatchVersionRequester))
+	assert.True(t, bvts[0].SkipOnPatchBuild())
+}
+
+func TestSuggestAllowedRequesters(t *testing.T) {
+	yml := `
+tasks:
+- name: t1
+  patch_only: true
+- name: t2
+- name: t3
+  allow_for_git_tag: false
+buildvariants:
+- name: bv1
+  run_on: d1
+  tasks:
+  - name: t1
+  - name: t2
+    patchable: false
+  - name: t3
+    patchable: false
+`
+	pp, err := createIntermediateProject([]byte(yml), false)
+	require.NoError(t, err)
+
+	suggestion, err := SuggestAllowedRequesters(pp)
+	require.NoError(t, err)
+	assert.True(t, suggestion.Equivalent, suggestion.Differences)
+	assert.Empty(t, suggestion.Unconverted)
+	assert.Len(t, suggestion.Conversions, 4)
+	assert.Contains(t, suggestion.Config, "allowed_requesters")
+	assert.NotContains(t, suggestion.Config, "patch_only")
+	assert.NotContains(t, suggestion.Config, "patchable")
+	assert.NotContains(t, suggestion.Config, "allow_for_git_tag")
+
+	// The original project is not modified.
+	require.Len(t, pp.Tasks, 3)
+	assert.True(t, utility.FromBoolPtr(pp.Tasks[0].PatchOnly))
+	assert.Empty(t, pp.Tasks[0].AllowedRequesters)
+
+	rewritten, err := createIntermediateProject([]byte(suggestion.Config), false)
+	require.NoError(t, err)
+	before, err := TranslateProject(pp)
+	require.NoError(t, err)
+	after, err := TranslateProject(rewritten)
+	require.NoError(t, err)
+	assert.Empty(t, CheckAllowedRequestersEquivalent(before, after))
+}
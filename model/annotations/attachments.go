@@ -0,0 +1,333 @@
+package annotations
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/pail"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AttachmentContentsCollection stores the contents of attachments that use the
+// database backend.
+const AttachmentContentsCollection = "task_annotation_attachment_contents"
+
+// maxAttachmentNameLength is the longest name that an attachment can have.
+const maxAttachmentNameLength = 256
+
+// attachmentURLExpireTime is how long the signed URL of an attachment that
+// uses the database backend is valid for. It matches how long presigned S3
+// URLs are valid for.
+const attachmentURLExpireTime = 24 * time.Hour
+
+// Attachment is a small file, such as a screenshot or an HTML report, that's
+// attached to a task annotation. Its contents are stored separately in an
+// AttachmentStore.
+type Attachment struct {
+	Id          string `bson:"id" json:"id"`
+	Name        string `bson:"name" json:"name"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	Size        int    `bson:"size" json:"size"`
+	// Backend is the backend that stores the attachment's contents.
+	Backend string `bson:"backend" json:"backend"`
+	// Key identifies the attachment's contents in its backend.
+	Key    string  `bson:"key" json:"key"`
+	Source *Source `bson:"source,omitempty" json:"source,omitempty"`
+}
+
+// AttachmentStore stores the contents of annotation attachments.
+type AttachmentStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Remove(ctx context.Context, key string) error
+	// SignedURL returns a URL that the attachment can be downloaded from
+	// without credentials until the URL expires.
+	SignedURL(a Attachment) (string, error)
+}
+
+// PresignFunc returns a URL that the S3 object can be downloaded from without
+// credentials until the URL expires. It's passed in, rather than called
+// directly, because the thirdparty package that presigns S3 URLs depends on
+// this package.
+type PresignFunc func(bucket, key, awsKey, awsSecret string) (string, error)
+
+// NewAttachmentStore returns the store for the given attachment backend. The
+// presign function is only needed to sign URLs of attachments that use the S3
+// backend.
+func NewAttachmentStore(backend string, settings *evergreen.Settings, presign PresignFunc) (AttachmentStore, error) {
+	switch backend {
+	case evergreen.AnnotationAttachmentBackendDB:
+		return &dbAttachmentStore{
+			apiURL:     settings.ApiUrl,
+			signingKey: settings.AnnotationAttachments.SigningKey,
+		}, nil
+	case evergreen.AnnotationAttachmentBackendS3:
+		return &s3AttachmentStore{
+			creds:   settings.AnnotationAttachments.S3,
+			presign: presign,
+		}, nil
+	default:
+		return nil, errors.Errorf("unrecognized annotation attachment backend '%s'", backend)
+	}
+}
+
+// ValidateAttachment checks that the attachment is within the configured size
+// and content type limits. It returns the attachment's media type, which is
+// detected from its contents if the content type is not set.
+func ValidateAttachment(conf evergreen.AnnotationAttachmentsConfig, name, contentType string, data []byte) (string, error) {
+	if !conf.IsEnabled() {
+		return "", errors.New("annotation attachments are not enabled")
+	}
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(name == "", "attachment name cannot be empty")
+	catcher.ErrorfWhen(len(name) > maxAttachmentNameLength, "attachment name cannot be longer than %d characters", maxAttachmentNameLength)
+	catcher.NewWhen(len(data) == 0, "attachment cannot be empty")
+	catcher.ErrorfWhen(len(data) > conf.GetMaxSizeBytes(), "attachment is %d bytes, which exceeds the limit of %d bytes", len(data), conf.GetMaxSizeBytes())
+	if catcher.HasErrors() {
+		return "", catcher.Resolve()
+	}
+
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing content type '%s'", contentType)
+	}
+	if !utility.StringSliceContains(conf.GetAllowedContentTypes(), mediaType) {
+		return "", errors.Errorf("content type '%s' is not allowed for attachments", mediaType)
+	}
+	return mediaType, nil
+}
+
+// AddAttachment stores the attachment's contents in the configured backend and
+// adds the attachment to the annotation for the task execution.
+func AddAttachment(ctx context.Context, settings *evergreen.Settings, taskId string, execution int, a Attachment, data []byte) (*Attachment, error) {
+	a.Id = utility.RandomString()
+	a.Backend = settings.AnnotationAttachments.Backend
+	a.Key = fmt.Sprintf("%s/%d/%s", taskId, execution, a.Id)
+	a.Size = len(data)
+
+	store, err := NewAttachmentStore(a.Backend, settings, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting attachment store")
+	}
+	if err = store.Put(ctx, a.Key, a.ContentType, data); err != nil {
+		return nil, errors.Wrapf(err, "storing attachment '%s'", a.Name)
+	}
+
+	_, err = db.Upsert(
+		Collection,
+		ByTaskIdAndExecution(taskId, execution),
+		bson.M{
+			"$push": bson.M{AttachmentsKey: a},
+		},
+	)
+	if err != nil {
+		grip.Warning(message.WrapError(store.Remove(ctx, a.Key), message.Fields{
+			"message":       "could not clean up contents of attachment that was not added to annotation",
+			"task_id":       taskId,
+			"attachment_id": a.Id,
+		}))
+		return nil, errors.Wrapf(err, "adding attachment to annotation for task '%s'", taskId)
+	}
+	return &a, nil
+}
+
+// RemoveAttachment removes the attachment from the annotation for the task
+// execution and deletes its contents.
+func RemoveAttachment(ctx context.Context, settings *evergreen.Settings, taskId string, execution int, attachmentId string) error {
+	annotation, err := FindOneByTaskIdAndExecution(taskId, execution)
+	if err != nil {
+		return errors.Wrap(err, "finding task annotation")
+	}
+	var attachment *Attachment
+	if annotation != nil {
+		attachment = annotation.GetAttachment(attachmentId)
+	}
+	if attachment == nil {
+		return errors.Errorf("attachment '%s' not found for task '%s' execution %d", attachmentId, taskId, execution)
+	}
+
+	err = db.Update(
+		Collection,
+		ByTaskIdAndExecution(taskId, execution),
+		bson.M{"$pull": bson.M{AttachmentsKey: bson.M{AttachmentIdKey: attachmentId}}},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "removing attachment '%s' from annotation", attachmentId)
+	}
+
+	store, err := NewAttachmentStore(attachment.Backend, settings, nil)
+	if err != nil {
+		return errors.Wrap(err, "getting attachment store")
+	}
+	return errors.Wrapf(store.Remove(ctx, attachment.Key), "deleting contents of attachment '%s'", attachmentId)
+}
+
+// FindAttachment returns the attachment with the given ID, or nil if it does
+// not exist.
+func FindAttachment(attachmentId string) (*Attachment, error) {
+	annotation, err := FindOne(db.Query(bson.M{
+		bsonutil.GetDottedKeyName(AttachmentsKey, AttachmentIdKey): attachmentId,
+	}))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding annotation with attachment '%s'", attachmentId)
+	}
+	if annotation == nil {
+		return nil, nil
+	}
+	return annotation.GetAttachment(attachmentId), nil
+}
+
+// GetAttachment returns the annotation's attachment with the given ID, or nil
+// if it does not exist.
+func (a *TaskAnnotation) GetAttachment(attachmentId string) *Attachment {
+	for i := range a.Attachments {
+		if a.Attachments[i].Id == attachmentId {
+			return &a.Attachments[i]
+		}
+	}
+	return nil
+}
+
+// attachmentContents are the contents of an attachment that uses the database
+// backend.
+type attachmentContents struct {
+	Key  string `bson:"_id"`
+	Data []byte `bson:"data"`
+}
+
+// dbAttachmentStore stores attachments in the database and serves them from
+// the REST API with URLs signed by the configured signing key.
+type dbAttachmentStore struct {
+	apiURL     string
+	signingKey string
+}
+
+func (s *dbAttachmentStore) Put(ctx context.Context, key, _ string, data []byte) error {
+	return db.Insert(AttachmentContentsCollection, attachmentContents{Key: key, Data: data})
+}
+
+func (s *dbAttachmentStore) Get(ctx context.Context, key string) ([]byte, error) {
+	contents := attachmentContents{}
+	err := db.FindOneQ(AttachmentContentsCollection, db.Query(bson.M{"_id": key}), &contents)
+	if adb.ResultsNotFound(err) {
+		return nil, errors.Errorf("contents of attachment '%s' not found", key)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding contents of attachment '%s'", key)
+	}
+	return contents.Data, nil
+}
+
+func (s *dbAttachmentStore) Remove(ctx context.Context, key string) error {
+	return db.Remove(AttachmentContentsCollection, bson.M{"_id": key})
+}
+
+func (s *dbAttachmentStore) SignedURL(a Attachment) (string, error) {
+	expires := time.Now().Add(attachmentURLExpireTime).Unix()
+	signature, err := signAttachment(s.signingKey, a.Id, expires)
+	if err != nil {
+		return "", errors.Wrap(err, "signing attachment URL")
+	}
+	vals := url.Values{}
+	vals.Set("expires", strconv.FormatInt(expires, 10))
+	vals.Set("signature", signature)
+	return fmt.Sprintf("%s/rest/v2/annotation_attachments/%s?%s", s.apiURL, url.PathEscape(a.Id), vals.Encode()), nil
+}
+
+// CheckAttachmentSignature checks that the signature of a URL that an
+// attachment stored in the database is served from is valid and has not
+// expired.
+func CheckAttachmentSignature(signingKey, attachmentId string, expires int64, signature string) error {
+	if time.Now().Unix() > expires {
+		return errors.New("attachment URL has expired")
+	}
+	expected, err := signAttachment(signingKey, attachmentId, expires)
+	if err != nil {
+		return errors.Wrap(err, "signing attachment URL")
+	}
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("attachment URL signature is invalid")
+	}
+	return nil
+}
+
+func signAttachment(signingKey, attachmentId string, expires int64) (string, error) {
+	if signingKey == "" {
+		return "", errors.New("signing key is not configured")
+	}
+	return util.CalculateHMACHash([]byte(signingKey), []byte(fmt.Sprintf("%s:%d", attachmentId, expires)))
+}
+
+// s3AttachmentStore stores attachments in an S3 bucket and serves them with
+// presigned URLs.
+type s3AttachmentStore struct {
+	creds   evergreen.S3Credentials
+	presign PresignFunc
+}
+
+func (s *s3AttachmentStore) bucket(contentType string) (pail.Bucket, error) {
+	return pail.NewS3Bucket(pail.S3Options{
+		Credentials: pail.CreateAWSCredentials(s.creds.Key, s.creds.Secret, ""),
+		Region:      endpoints.UsEast1RegionID,
+		Name:        s.creds.Bucket,
+		Permissions: pail.S3PermissionsPrivate,
+		ContentType: contentType,
+	})
+}
+
+func (s *s3AttachmentStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	bucket, err := s.bucket(contentType)
+	if err != nil {
+		return errors.Wrap(err, "creating S3 bucket")
+	}
+	return bucket.Put(ctx, key, bytes.NewReader(data))
+}
+
+func (s *s3AttachmentStore) Get(ctx context.Context, key string) ([]byte, error) {
+	bucket, err := s.bucket("")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating S3 bucket")
+	}
+	r, err := bucket.Get(ctx, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting contents of attachment '%s'", key)
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func (s *s3AttachmentStore) Remove(ctx context.Context, key string) error {
+	bucket, err := s.bucket("")
+	if err != nil {
+		return errors.Wrap(err, "creating S3 bucket")
+	}
+	return bucket.Remove(ctx, key)
+}
+
+func (s *s3AttachmentStore) SignedURL(a Attachment) (string, error) {
+	if s.presign == nil {
+		return "", errors.New("programmatic error: cannot sign S3 attachment URL without a presign function")
+	}
+	return s.presign(s.creds.Bucket, a.Key, s.creds.Key, s.creds.Secret)
+}
@@ -0,0 +1,129 @@
+package annotations
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAttachment(t *testing.T) {
+	conf := evergreen.AnnotationAttachmentsConfig{
+		Backend:             evergreen.AnnotationAttachmentBackendDB,
+		MaxSizeBytes:        16,
+		AllowedContentTypes: []string{"text/plain", "image/png"},
+	}
+
+	t.Run("DetectsContentType", func(t *testing.T) {
+		mediaType, err := ValidateAttachment(conf, "notes.txt", "", []byte("hello"))
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain", mediaType)
+	})
+	t.Run("StripsContentTypeParameters", func(t *testing.T) {
+		mediaType, err := ValidateAttachment(conf, "notes.txt", "text/plain; charset=utf-8", []byte("hello"))
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain", mediaType)
+	})
+	t.Run("FailsForDisallowedContentType", func(t *testing.T) {
+		_, err := ValidateAttachment(conf, "report.html", "text/html", []byte("<html></html>"))
+		assert.Error(t, err)
+	})
+	t.Run("FailsForOversizedAttachment", func(t *testing.T) {
+		_, err := ValidateAttachment(conf, "notes.txt", "text/plain", []byte("this is more than sixteen bytes"))
+		assert.Error(t, err)
+	})
+	t.Run("FailsWithoutName", func(t *testing.T) {
+		_, err := ValidateAttachment(conf, "", "text/plain", []byte("hello"))
+		assert.Error(t, err)
+	})
+	t.Run("FailsWhenDisabled", func(t *testing.T) {
+		_, err := ValidateAttachment(evergreen.AnnotationAttachmentsConfig{}, "notes.txt", "text/plain", []byte("hello"))
+		assert.Error(t, err)
+	})
+}
+
+func TestAttachments(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, db.ClearCollections(Collection, AttachmentContentsCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(Collection, AttachmentContentsCollection))
+	}()
+
+	settings := &evergreen.Settings{
+		ApiUrl: "https://evergreen.example.com",
+		AnnotationAttachments: evergreen.AnnotationAttachmentsConfig{
+			Backend:    evergreen.AnnotationAttachmentBackendDB,
+			SigningKey: "signing_key",
+		},
+	}
+	source := &Source{Author: "annie.black", Requester: APIRequester}
+	a, err := AddAttachment(ctx, settings, "t1", 0, Attachment{Name: "notes.txt", ContentType: "text/plain", Source: source}, []byte("hello"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, a.Id)
+	assert.Equal(t, evergreen.AnnotationAttachmentBackendDB, a.Backend)
+	assert.Equal(t, 5, a.Size)
+
+	t.Run("FindsAttachment", func(t *testing.T) {
+		annotation, err := FindOneByTaskIdAndExecution("t1", 0)
+		require.NoError(t, err)
+		require.NotNil(t, annotation)
+		require.Len(t, annotation.Attachments, 1)
+		assert.Equal(t, "annie.black", annotation.Attachments[0].Source.Author)
+
+		found, err := FindAttachment(a.Id)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, "notes.txt", found.Name)
+
+		found, err = FindAttachment("nonexistent")
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+	})
+	t.Run("GetsContents", func(t *testing.T) {
+		store, err := NewAttachmentStore(a.Backend, settings, nil)
+		require.NoError(t, err)
+		data, err := store.Get(ctx, a.Key)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+	})
+	t.Run("SignsURL", func(t *testing.T) {
+		store, err := NewAttachmentStore(a.Backend, settings, nil)
+		require.NoError(t, err)
+		signedURL, err := store.SignedURL(*a)
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(signedURL)
+		require.NoError(t, err)
+		assert.Equal(t, "/rest/v2/annotation_attachments/"+a.Id, parsed.Path)
+		expires, err := strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+		require.NoError(t, err)
+		signature := parsed.Query().Get("signature")
+
+		assert.NoError(t, CheckAttachmentSignature("signing_key", a.Id, expires, signature))
+		assert.Error(t, CheckAttachmentSignature("other_key", a.Id, expires, signature))
+		assert.Error(t, CheckAttachmentSignature("signing_key", "other_attachment", expires, signature))
+		assert.Error(t, CheckAttachmentSignature("signing_key", a.Id, expires+1, signature))
+		assert.Error(t, CheckAttachmentSignature("signing_key", a.Id, 0, signature))
+	})
+	t.Run("RemovesAttachment", func(t *testing.T) {
+		require.NoError(t, RemoveAttachment(ctx, settings, "t1", 0, a.Id))
+
+		annotation, err := FindOneByTaskIdAndExecution("t1", 0)
+		require.NoError(t, err)
+		require.NotNil(t, annotation)
+		assert.Empty(t, annotation.Attachments)
+
+		store, err := NewAttachmentStore(a.Backend, settings, nil)
+		require.NoError(t, err)
+		_, err = store.Get(ctx, a.Key)
+		assert.Error(t, err)
+
+		assert.Error(t, RemoveAttachment(ctx, settings, "t1", 0, a.Id))
+	})
+}
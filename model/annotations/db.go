@@ -18,7 +18,9 @@ var (
 	IssuesKey          = bsonutil.MustHaveTag(TaskAnnotation{}, "Issues")
 	SuspectedIssuesKey = bsonutil.MustHaveTag(TaskAnnotation{}, "SuspectedIssues")
 	CreatedIssuesKey   = bsonutil.MustHaveTag(TaskAnnotation{}, "CreatedIssues")
+	AttachmentsKey     = bsonutil.MustHaveTag(TaskAnnotation{}, "Attachments")
 	IssueLinkIssueKey  = bsonutil.MustHaveTag(IssueLink{}, "IssueKey")
+	AttachmentIdKey    = bsonutil.MustHaveTag(Attachment{}, "Id")
 )
 
 const (
@@ -26,6 +28,7 @@ const (
 	UIRequester      = "ui"
 	APIRequester     = "api"
 	WebhookRequester = "webhook"
+	AgentRequester   = "agent"
 )
 
 // FindOne gets one TaskAnnotation for the given query.
@@ -25,6 +25,8 @@ type TaskAnnotation struct {
 	SuspectedIssues []IssueLink `bson:"suspected_issues,omitempty" json:"suspected_issues,omitempty"`
 	// links to tickets created from the task using a custom web hook
 	CreatedIssues []IssueLink `bson:"created_issues,omitempty" json:"created_issues,omitempty"`
+	// small files, such as screenshots or reports, attached to the annotation
+	Attachments []Attachment `bson:"attachments,omitempty" json:"attachments,omitempty"`
 }
 
 type IssueLink struct {
@@ -34,6 +34,12 @@ type Entry struct {
 	CreateTime      time.Time `json:"create_time" bson:"create_time"`
 }
 
+// TaskFiles are files to attach to a single task.
+type TaskFiles struct {
+	TaskID string `json:"task_id"`
+	Files  []File `json:"files"`
+}
+
 // Params stores file entries as key-value pairs, for easy parameter parsing.
 //  Key = Human-readable name for file
 //  Value = link for the file
@@ -1,8 +1,10 @@
 package artifact
 
 import (
+	"context"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	_ "github.com/evergreen-ci/evergreen/testutil"
 	"github.com/stretchr/testify/suite"
@@ -218,3 +220,44 @@ func (s *TestArtifactFileSuite) TestRotateSecret() {
 	s.NoError(err)
 	s.Equal(entryFromDb.Files[0].AwsSecret, "changedSecret")
 }
+
+func (s *TestArtifactFileSuite) TestUpsertEntries() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	entries := []Entry{
+		{
+			TaskId:          "task1",
+			TaskDisplayName: "Task One",
+			BuildId:         "build1",
+			Files:           []File{{Name: "batched", Link: "http://example.com/batched"}},
+			Execution:       1,
+		},
+		{
+			TaskId:          "task3",
+			TaskDisplayName: "Task Three",
+			BuildId:         "build1",
+			Files:           []File{{Name: "new", Link: "http://example.com/new"}},
+			Execution:       0,
+		},
+	}
+	s.NoError(UpsertEntries(ctx, evergreen.GetEnvironment(), entries))
+
+	count, err := db.Count(Collection, bson.M{})
+	s.NoError(err)
+	s.Equal(4, count)
+
+	entryFromDb, err := FindOne(ByTaskIdAndExecution("task1", 1))
+	s.NoError(err)
+	s.Require().NotNil(entryFromDb)
+	s.Len(entryFromDb.Files, 3)
+	s.Equal("batched", entryFromDb.Files[2].Name)
+
+	entryFromDb, err = FindOne(ByTaskIdAndExecution("task3", 0))
+	s.NoError(err)
+	s.Require().NotNil(entryFromDb)
+	s.Require().Len(entryFromDb.Files, 1)
+	s.Equal("new", entryFromDb.Files[0].Name)
+
+	s.NoError(UpsertEntries(ctx, evergreen.GetEnvironment(), nil))
+}
@@ -1,10 +1,16 @@
 package artifact
 
 import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/mongodb/anser/bsonutil"
 	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
@@ -95,26 +101,51 @@ func BySecret(secret string) db.Q {
 func (e Entry) Upsert() error {
 	_, err := db.Upsert(
 		Collection,
-		bson.M{
-			TaskIdKey:    e.TaskId,
-			TaskNameKey:  e.TaskDisplayName,
-			BuildIdKey:   e.BuildId,
-			ExecutionKey: e.Execution,
-		},
-		bson.M{
-			"$addToSet": bson.M{
-				FilesKey: bson.M{
-					"$each": e.Files,
-				},
-			},
-			"$setOnInsert": bson.M{
-				ExecutionKey: e.Execution,
-			},
-		},
+		e.upsertFilter(),
+		e.upsertUpdate(),
 	)
 	return err
 }
 
+func (e Entry) upsertFilter() bson.M {
+	return bson.M{
+		TaskIdKey:    e.TaskId,
+		TaskNameKey:  e.TaskDisplayName,
+		BuildIdKey:   e.BuildId,
+		ExecutionKey: e.Execution,
+	}
+}
+
+func (e Entry) upsertUpdate() bson.M {
+	return bson.M{
+		"$addToSet": bson.M{
+			FilesKey: bson.M{
+				"$each": e.Files,
+			},
+		},
+		"$setOnInsert": bson.M{
+			ExecutionKey: e.Execution,
+		},
+	}
+}
+
+// UpsertEntries performs the same update as (Entry).Upsert for each of the
+// entries in a single bulk write.
+func UpsertEntries(ctx context.Context, env evergreen.Environment, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ops := make([]mongo.WriteModel, 0, len(entries))
+	for _, e := range entries {
+		ops = append(ops, mongo.NewUpdateOneModel().
+			SetFilter(e.upsertFilter()).
+			SetUpdate(e.upsertUpdate()).
+			SetUpsert(true))
+	}
+	_, err := env.DB().Collection(Collection).BulkWrite(ctx, ops, options.BulkWrite().SetOrdered(false))
+	return errors.Wrap(err, "upserting artifact entries")
+}
+
 func (e Entry) Update() error {
 	update := bson.M{
 		TaskIdKey:   e.TaskId,
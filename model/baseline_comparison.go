@@ -0,0 +1,172 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+)
+
+// Classifications of a patch task or test compared to its baseline.
+const (
+	// BaselineNewFailure failed in the patch but not on the baseline.
+	BaselineNewFailure = "new_failure"
+	// BaselinePreexistingFailure failed in the patch and on the baseline.
+	BaselinePreexistingFailure = "preexisting_failure"
+	// BaselineFixed succeeded in the patch but failed on the baseline.
+	BaselineFixed = "fixed"
+	// BaselinePassing succeeded in the patch and on the baseline.
+	BaselinePassing = "passing"
+	// BaselineUnknown has no finished baseline to compare against.
+	BaselineUnknown = "unknown"
+)
+
+// BaselineComparison compares the task and test outcomes of a patch version
+// against the mainline version for the patch's base revision.
+type BaselineComparison struct {
+	VersionID     string
+	BaseVersionID string
+	Tasks         []TaskBaselineComparison
+}
+
+// TaskBaselineComparison compares a single patch task against the task with the
+// same name and variant in the baseline version.
+type TaskBaselineComparison struct {
+	TaskID         string
+	BaseTaskID     string
+	BuildVariant   string
+	DisplayName    string
+	Status         string
+	BaseStatus     string
+	Classification string
+	// NewTestFailures are tests that failed in the patch task but not in
+	// the baseline task.
+	NewTestFailures []string
+	// PreexistingTestFailures are tests that failed in both the patch task
+	// and the baseline task.
+	PreexistingTestFailures []string
+}
+
+// CompareToBaseline compares the finished tasks in the patch version to the
+// tasks in its mainline baseline version. If there is no baseline version, all
+// tasks are classified as unknown.
+func CompareToBaseline(v *Version) (*BaselineComparison, error) {
+	if !evergreen.IsPatchRequester(v.Requester) {
+		return nil, errors.Errorf("version '%s' is not a patch", v.Id)
+	}
+	comparison := &BaselineComparison{VersionID: v.Id}
+
+	tasks, err := task.FindAll(db.Query(task.ByVersion(v.Id)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding tasks for version '%s'", v.Id)
+	}
+
+	baseTasks := map[string]*task.Task{}
+	baseVersion, err := VersionFindOne(BaseVersionByProjectIdAndRevision(v.Identifier, v.Revision))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding base version for revision '%s'", v.Revision)
+	}
+	if baseVersion != nil {
+		comparison.BaseVersionID = baseVersion.Id
+		var allBaseTasks []task.Task
+		allBaseTasks, err = task.FindAll(db.Query(task.ByVersion(baseVersion.Id)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding tasks for base version '%s'", baseVersion.Id)
+		}
+		for i := range allBaseTasks {
+			baseTasks[baselineTaskKey(&allBaseTasks[i])] = &allBaseTasks[i]
+		}
+	}
+
+	for i := range tasks {
+		t := &tasks[i]
+		if t.IsPartOfDisplay() || !evergreen.IsFinishedTaskStatus(t.Status) {
+			continue
+		}
+		taskComparison, err := compareTaskToBaseline(t, baseTasks[baselineTaskKey(t)])
+		if err != nil {
+			return nil, errors.Wrapf(err, "comparing task '%s' to baseline", t.Id)
+		}
+		comparison.Tasks = append(comparison.Tasks, *taskComparison)
+	}
+	sort.SliceStable(comparison.Tasks, func(i, j int) bool {
+		if comparison.Tasks[i].BuildVariant != comparison.Tasks[j].BuildVariant {
+			return comparison.Tasks[i].BuildVariant < comparison.Tasks[j].BuildVariant
+		}
+		return comparison.Tasks[i].DisplayName < comparison.Tasks[j].DisplayName
+	})
+
+	return comparison, nil
+}
+
+func baselineTaskKey(t *task.Task) string {
+	return t.BuildVariant + "/" + t.DisplayName
+}
+
+func compareTaskToBaseline(t, baseTask *task.Task) (*TaskBaselineComparison, error) {
+	comparison := &TaskBaselineComparison{
+		TaskID:       t.Id,
+		BuildVariant: t.BuildVariant,
+		DisplayName:  t.DisplayName,
+		Status:       t.Status,
+	}
+	failed := t.Status != evergreen.TaskSucceeded
+	if baseTask == nil || !evergreen.IsFinishedTaskStatus(baseTask.Status) {
+		comparison.Classification = BaselineUnknown
+		if baseTask != nil {
+			comparison.BaseTaskID = baseTask.Id
+			comparison.BaseStatus = baseTask.Status
+		}
+		return comparison, nil
+	}
+	comparison.BaseTaskID = baseTask.Id
+	comparison.BaseStatus = baseTask.Status
+	baseFailed := baseTask.Status != evergreen.TaskSucceeded
+
+	switch {
+	case failed && baseFailed:
+		comparison.Classification = BaselinePreexistingFailure
+	case failed:
+		comparison.Classification = BaselineNewFailure
+	case baseFailed:
+		comparison.Classification = BaselineFixed
+	default:
+		comparison.Classification = BaselinePassing
+	}
+	if !failed {
+		return comparison, nil
+	}
+
+	if err := t.PopulateTestResults(); err != nil {
+		return nil, errors.Wrap(err, "getting test results")
+	}
+	if err := baseTask.PopulateTestResults(); err != nil {
+		return nil, errors.Wrap(err, "getting base test results")
+	}
+	baseFailures := map[string]bool{}
+	for _, result := range baseTask.LocalTestResults {
+		if result.Status == evergreen.TestFailedStatus {
+			baseFailures[result.GetDisplayTestName()] = true
+		}
+	}
+	for _, result := range t.LocalTestResults {
+		if result.Status != evergreen.TestFailedStatus {
+			continue
+		}
+		name := result.GetDisplayTestName()
+		if baseFailures[name] {
+			comparison.PreexistingTestFailures = append(comparison.PreexistingTestFailures, name)
+		} else {
+			comparison.NewTestFailures = append(comparison.NewTestFailures, name)
+		}
+	}
+	// A task that also failed on the baseline still introduced a new failure
+	// if any of its tests did not fail on the baseline.
+	if len(comparison.NewTestFailures) > 0 {
+		comparison.Classification = BaselineNewFailure
+	}
+
+	return comparison, nil
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareToBaseline(t *testing.T) {
+	require.NoError(t, db.ClearCollections(VersionCollection, task.Collection, testresult.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection, task.Collection, testresult.Collection))
+	}()
+
+	revision := "abcdef0123456789abcdef0123456789abcdef01"
+	baseVersion := Version{Id: "base", Identifier: "p1", Revision: revision, Requester: evergreen.RepotrackerVersionRequester}
+	require.NoError(t, baseVersion.Insert())
+	patchVersion := Version{Id: "patch", Identifier: "p1", Revision: revision, Requester: evergreen.PatchVersionRequester}
+	require.NoError(t, patchVersion.Insert())
+
+	tasks := []task.Task{
+		{Id: "base_passing", Version: "base", BuildVariant: "bv", DisplayName: "passing", Status: evergreen.TaskSucceeded},
+		{Id: "patch_passing", Version: "patch", BuildVariant: "bv", DisplayName: "passing", Status: evergreen.TaskSucceeded},
+		{Id: "base_broken", Version: "base", BuildVariant: "bv", DisplayName: "broken", Status: evergreen.TaskSucceeded},
+		{Id: "patch_broken", Version: "patch", BuildVariant: "bv", DisplayName: "broken", Status: evergreen.TaskFailed},
+		{Id: "base_flaky", Version: "base", BuildVariant: "bv", DisplayName: "flaky", Status: evergreen.TaskFailed},
+		{Id: "patch_flaky", Version: "patch", BuildVariant: "bv", DisplayName: "flaky", Status: evergreen.TaskFailed},
+		{Id: "base_fixed", Version: "base", BuildVariant: "bv", DisplayName: "fixed", Status: evergreen.TaskFailed},
+		{Id: "patch_fixed", Version: "patch", BuildVariant: "bv", DisplayName: "fixed", Status: evergreen.TaskSucceeded},
+		{Id: "patch_new", Version: "patch", BuildVariant: "bv", DisplayName: "new", Status: evergreen.TaskFailed},
+		{Id: "patch_running", Version: "patch", BuildVariant: "bv", DisplayName: "running", Status: evergreen.TaskStarted},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+	}
+	results := []testresult.TestResult{
+		{TaskID: "base_flaky", TestFile: "test1", Status: evergreen.TestFailedStatus},
+		{TaskID: "patch_flaky", TestFile: "test1", Status: evergreen.TestFailedStatus},
+		{TaskID: "base_broken", TestFile: "test2", Status: evergreen.TestSucceededStatus},
+		{TaskID: "patch_broken", TestFile: "test2", Status: evergreen.TestFailedStatus},
+	}
+	require.NoError(t, testresult.InsertMany(results))
+
+	comparison, err := CompareToBaseline(&patchVersion)
+	require.NoError(t, err)
+	assert.Equal(t, "base", comparison.BaseVersionID)
+	require.Len(t, comparison.Tasks, 5)
+
+	byName := map[string]TaskBaselineComparison{}
+	for _, c := range comparison.Tasks {
+		byName[c.DisplayName] = c
+	}
+	assert.Equal(t, BaselinePassing, byName["passing"].Classification)
+	assert.Equal(t, BaselineNewFailure, byName["broken"].Classification)
+	assert.Equal(t, []string{"test2"}, byName["broken"].NewTestFailures)
+	assert.Equal(t, BaselinePreexistingFailure, byName["flaky"].Classification)
+	assert.Equal(t, []string{"test1"}, byName["flaky"].PreexistingTestFailures)
+	assert.Equal(t, BaselineFixed, byName["fixed"].Classification)
+	assert.Equal(t, BaselineUnknown, byName["new"].Classification)
+
+	_, err = CompareToBaseline(&baseVersion)
+	assert.Error(t, err)
+}
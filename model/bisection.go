@@ -0,0 +1,703 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/annotations"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// BisectionsCollection stores the automatic bisections of mainline task
+	// failures.
+	BisectionsCollection = "bisections"
+
+	BisectionInProgress = "in-progress"
+	// BisectionFound means that the bisection found the first commit that
+	// the task fails in.
+	BisectionFound = "found"
+	// BisectionInconclusive means that the bisection narrowed the failure
+	// down to a range of commits, but couldn't tell which one in the range
+	// broke the task because the task couldn't be run or didn't pass or fail
+	// in some of them.
+	BisectionInconclusive = "inconclusive"
+
+	// BisectionStepSkipped is the status of a step whose version doesn't
+	// define the task.
+	BisectionStepSkipped = "skipped"
+	// BisectionStepAbandoned is the status of a step whose task was
+	// deactivated, blocked, or didn't finish in time, so the bisection moved
+	// on without its result.
+	BisectionStepAbandoned = "abandoned"
+
+	// bisectionStepTimeout is how long a bisection waits for the task in a
+	// step to finish before abandoning the step.
+	bisectionStepTimeout = 24 * time.Hour
+)
+
+// Bisection is a binary search for the commit that broke a mainline task.
+// When a task fails after passing in an earlier commit with commits in
+// between that didn't run it, the task is run in the commit halfway between
+// the last passing and the first failing commits, and the search continues in
+// whichever half contains the change from passing to failing.
+//
+// The search first narrows down the mainline versions. Once it finds the
+// version that the task started failing in, it goes on to search the commits
+// between that version and the previous one that never got a version, for
+// which ad hoc versions are created as needed.
+type Bisection struct {
+	Id           string `bson:"_id" json:"id"`
+	ProjectId    string `bson:"project_id" json:"project_id"`
+	BuildVariant string `bson:"build_variant" json:"build_variant"`
+	DisplayName  string `bson:"display_name" json:"display_name"`
+	Status       string `bson:"status" json:"status"`
+
+	// GoodOrder is the order number of the latest commit that the task is
+	// known to pass in, and GoodTaskId is the task in that commit.
+	GoodOrder  int    `bson:"good_order" json:"good_order"`
+	GoodTaskId string `bson:"good_task_id" json:"good_task_id"`
+	// BadOrder is the order number of the earliest commit that the task is
+	// known to fail in, and BadTaskId is the task in that commit.
+	BadOrder  int    `bson:"bad_order" json:"bad_order"`
+	BadTaskId string `bson:"bad_task_id" json:"bad_task_id"`
+
+	// PendingTaskId is the task that's running to narrow down the search.
+	PendingTaskId string          `bson:"pending_task_id,omitempty" json:"pending_task_id,omitempty"`
+	Steps         []BisectionStep `bson:"steps,omitempty" json:"steps,omitempty"`
+
+	// AwaitingCommits is set while the bisection waits for the commits
+	// between the good and bad mainline versions that never got a version to
+	// be looked up.
+	AwaitingCommits bool `bson:"awaiting_commits,omitempty" json:"awaiting_commits,omitempty"`
+	// SearchingUnversioned is set once the bisection is searching
+	// UnversionedRevisions, the commits between the good and bad mainline
+	// versions that never got a version, oldest first.
+	SearchingUnversioned bool     `bson:"searching_unversioned,omitempty" json:"searching_unversioned,omitempty"`
+	UnversionedRevisions []string `bson:"unversioned_revisions,omitempty" json:"unversioned_revisions,omitempty"`
+	// GoodPosition and BadPosition bound the search among the unversioned
+	// revisions. Position 0 is the good mainline version, position i is the
+	// ith unversioned revision, and the position after the last unversioned
+	// revision is the bad mainline version.
+	GoodPosition int `bson:"good_position,omitempty" json:"good_position,omitempty"`
+	BadPosition  int `bson:"bad_position,omitempty" json:"bad_position,omitempty"`
+	// PendingRevision is the unversioned revision that the bisection is
+	// waiting for a version to be created in.
+	PendingRevision string `bson:"pending_revision,omitempty" json:"pending_revision,omitempty"`
+
+	// CulpritVersionId and CulpritRevision are the commit that broke the
+	// task, once the bisection has found it.
+	CulpritVersionId string `bson:"culprit_version_id,omitempty" json:"culprit_version_id,omitempty"`
+	CulpritRevision  string `bson:"culprit_revision,omitempty" json:"culprit_revision,omitempty"`
+
+	CreateTime time.Time `bson:"create_time" json:"create_time"`
+	FinishTime time.Time `bson:"finish_time,omitempty" json:"finish_time,omitempty"`
+}
+
+// BisectionStep is a commit that a bisection ran the task in.
+type BisectionStep struct {
+	VersionId string `bson:"version_id" json:"version_id"`
+	Revision  string `bson:"revision,omitempty" json:"revision,omitempty"`
+	// RevisionOrderNumber is the order of the step's mainline version. It's
+	// not set for steps in unversioned revisions.
+	RevisionOrderNumber int    `bson:"order,omitempty" json:"order,omitempty"`
+	TaskId              string `bson:"task_id,omitempty" json:"task_id,omitempty"`
+	// Status is the task's status once it finished, or skipped if the
+	// version doesn't define the task. It's empty while the task is running.
+	Status string `bson:"status,omitempty" json:"status,omitempty"`
+	// StartTime is when the bisection started waiting for the task.
+	StartTime time.Time `bson:"start_time,omitempty" json:"start_time,omitempty"`
+}
+
+var (
+	bisectionIdKey              = bsonutil.MustHaveTag(Bisection{}, "Id")
+	bisectionProjectIdKey       = bsonutil.MustHaveTag(Bisection{}, "ProjectId")
+	bisectionBuildVariantKey    = bsonutil.MustHaveTag(Bisection{}, "BuildVariant")
+	bisectionDisplayNameKey     = bsonutil.MustHaveTag(Bisection{}, "DisplayName")
+	bisectionStatusKey          = bsonutil.MustHaveTag(Bisection{}, "Status")
+	bisectionPendingTaskIdKey   = bsonutil.MustHaveTag(Bisection{}, "PendingTaskId")
+	bisectionAwaitingCommitsKey = bsonutil.MustHaveTag(Bisection{}, "AwaitingCommits")
+	bisectionPendingRevisionKey = bsonutil.MustHaveTag(Bisection{}, "PendingRevision")
+	bisectionCreateTimeKey      = bsonutil.MustHaveTag(Bisection{}, "CreateTime")
+)
+
+// FindBisection returns the bisection with the given ID, or nil if there
+// isn't one.
+func FindBisection(id string) (*Bisection, error) {
+	return findOneBisection(bson.M{bisectionIdKey: id})
+}
+
+// FindBisectionsByProject returns the project's most recent bisections,
+// newest first. If status is given, only bisections with that status are
+// returned.
+func FindBisectionsByProject(projectId, status string, limit int) ([]Bisection, error) {
+	q := bson.M{bisectionProjectIdKey: projectId}
+	if status != "" {
+		q[bisectionStatusKey] = status
+	}
+	bisections := []Bisection{}
+	err := db.FindAllQ(BisectionsCollection, db.Query(q).Sort([]string{"-" + bisectionCreateTimeKey}).Limit(limit), &bisections)
+	return bisections, errors.Wrapf(err, "finding bisections for project '%s'", projectId)
+}
+
+// FindBisectionsAwaitingVersions returns the in-progress bisections that are
+// waiting for the unversioned commits in their search range to be looked up,
+// or for a version to be created in one of those commits.
+func FindBisectionsAwaitingVersions() ([]Bisection, error) {
+	bisections := []Bisection{}
+	err := db.FindAllQ(BisectionsCollection, db.Query(bson.M{
+		bisectionStatusKey: BisectionInProgress,
+		"$or": []bson.M{
+			{bisectionAwaitingCommitsKey: true},
+			{bisectionPendingRevisionKey: bson.M{"$nin": []interface{}{nil, ""}}},
+		},
+	}), &bisections)
+	return bisections, errors.Wrap(err, "finding bisections awaiting versions")
+}
+
+func findOneBisection(q bson.M) (*Bisection, error) {
+	b := &Bisection{}
+	err := db.FindOneQ(BisectionsCollection, db.Query(q), b)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "finding bisection")
+	}
+	return b, nil
+}
+
+func (b *Bisection) save() error {
+	return errors.Wrapf(db.UpdateId(BisectionsCollection, b.Id, b), "saving bisection '%s'", b.Id)
+}
+
+// maybeStartBisection bisects the commits between the failed task and the
+// last commit that it passed in, if the project bisects mainline failures
+// automatically. It returns whether automatic bisection handles the failure,
+// in which case the task shouldn't be stepped back.
+func maybeStartBisection(t *task.Task) (bool, error) {
+	if t.Requester != evergreen.RepotrackerVersionRequester {
+		return false, nil
+	}
+	pRef, err := FindMergedProjectRef(t.Project, t.Version, true)
+	if err != nil {
+		return false, errors.Wrapf(err, "finding project ref for task '%s'", t.Id)
+	}
+	if pRef == nil || !pRef.IsAutoBisectEnabled() {
+		return false, nil
+	}
+
+	prevTask, err := t.PreviousCompletedTask(t.Project, nil)
+	if err != nil {
+		return true, errors.Wrap(err, "finding previous completed task")
+	}
+	// Only a change from passing to failing with commits in between needs
+	// to be bisected.
+	if prevTask == nil || prevTask.Status != evergreen.TaskSucceeded || t.RevisionOrderNumber-prevTask.RevisionOrderNumber < 2 {
+		return true, nil
+	}
+
+	existing, err := findOneBisection(bson.M{
+		bisectionProjectIdKey:    t.Project,
+		bisectionBuildVariantKey: t.BuildVariant,
+		bisectionDisplayNameKey:  t.DisplayName,
+		bisectionStatusKey:       BisectionInProgress,
+	})
+	if err != nil {
+		return true, err
+	}
+	if existing != nil {
+		return true, nil
+	}
+
+	b := &Bisection{
+		Id:           primitive.NewObjectID().Hex(),
+		ProjectId:    t.Project,
+		BuildVariant: t.BuildVariant,
+		DisplayName:  t.DisplayName,
+		Status:       BisectionInProgress,
+		GoodOrder:    prevTask.RevisionOrderNumber,
+		GoodTaskId:   prevTask.Id,
+		BadOrder:     t.RevisionOrderNumber,
+		BadTaskId:    t.Id,
+		CreateTime:   time.Now(),
+	}
+	if err = db.Insert(BisectionsCollection, b); err != nil {
+		return true, errors.Wrap(err, "inserting bisection")
+	}
+	grip.Info(message.Fields{
+		"message":       "starting bisection",
+		"bisection":     b.Id,
+		"project":       b.ProjectId,
+		"build_variant": b.BuildVariant,
+		"task":          b.DisplayName,
+		"good_order":    b.GoodOrder,
+		"bad_order":     b.BadOrder,
+	})
+	return true, b.next()
+}
+
+// advanceBisection records the result of a task that a bisection ran and
+// runs the next step of the bisection. It returns whether the task was part
+// of a bisection.
+func advanceBisection(t *task.Task, status string) (bool, error) {
+	if t.Requester != evergreen.RepotrackerVersionRequester && t.Requester != evergreen.AdHocRequester {
+		return false, nil
+	}
+	b, err := findOneBisection(bson.M{
+		bisectionPendingTaskIdKey: t.Id,
+		bisectionStatusKey:        BisectionInProgress,
+	})
+	if err != nil {
+		return false, err
+	}
+	if b == nil {
+		return false, nil
+	}
+	if !evergreen.IsFinishedTaskStatus(status) {
+		// A display task isn't done until all of its execution tasks are.
+		return true, nil
+	}
+
+	b.PendingTaskId = ""
+	b.recordResult(t, status)
+	return true, b.next()
+}
+
+// recordResult narrows the search using the result of the task in a step.
+func (b *Bisection) recordResult(t *task.Task, status string) {
+	for i := range b.Steps {
+		if b.Steps[i].TaskId == t.Id {
+			b.Steps[i].Status = status
+		}
+	}
+	switch {
+	case status == evergreen.TaskSucceeded:
+		if b.SearchingUnversioned {
+			b.GoodPosition = b.position(t.Revision)
+		} else {
+			b.GoodOrder = t.RevisionOrderNumber
+		}
+		b.GoodTaskId = t.Id
+	case status == evergreen.TaskFailed && t.Details.Type != evergreen.CommandTypeSystem && t.Details.Type != evergreen.CommandTypeSetup:
+		if b.SearchingUnversioned {
+			b.BadPosition = b.position(t.Revision)
+		} else {
+			b.BadOrder = t.RevisionOrderNumber
+		}
+		b.BadTaskId = t.Id
+	}
+	// Any other result doesn't tell whether the commit broke the task, so the
+	// commit stays in the search range but isn't tried again.
+}
+
+// position returns the position of the revision among the unversioned
+// revisions.
+func (b *Bisection) position(revision string) int {
+	for i, r := range b.UnversionedRevisions {
+		if r == revision {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// tried returns whether the bisection already tried to run the task in the
+// mainline commit.
+func (b *Bisection) tried(order int) bool {
+	for _, step := range b.Steps {
+		if step.RevisionOrderNumber == order {
+			return true
+		}
+	}
+	return false
+}
+
+// triedRevision returns whether the bisection already tried to run the task
+// in the unversioned commit.
+func (b *Bisection) triedRevision(revision string) bool {
+	for _, step := range b.Steps {
+		if step.RevisionOrderNumber == 0 && step.Revision == revision {
+			return true
+		}
+	}
+	return false
+}
+
+// next runs the task in the commit closest to the middle of the search range
+// that hasn't been tried yet, or finishes the bisection if there are none.
+func (b *Bisection) next() error {
+	if b.SearchingUnversioned {
+		return b.nextUnversioned()
+	}
+	versions, err := VersionFind(VersionByProjectIdAndOrderRange(b.ProjectId, b.GoodOrder, b.BadOrder-1).
+		WithFields(VersionIdKey, VersionRevisionOrderNumberKey, VersionBuildIdsKey))
+	if err != nil {
+		return errors.Wrapf(err, "finding versions for bisection '%s'", b.Id)
+	}
+	for {
+		var candidate *Version
+		mid := (b.GoodOrder + b.BadOrder) / 2
+		for i := range versions {
+			v := &versions[i]
+			if b.tried(v.RevisionOrderNumber) || v.RevisionOrderNumber <= b.GoodOrder || v.RevisionOrderNumber >= b.BadOrder {
+				continue
+			}
+			if candidate == nil || abs(v.RevisionOrderNumber-mid) < abs(candidate.RevisionOrderNumber-mid) {
+				candidate = v
+			}
+		}
+		if candidate == nil {
+			if !b.untestedInRange() {
+				// The failure is narrowed down to adjacent mainline versions,
+				// so the commits between them that never got a version are
+				// searched next.
+				b.AwaitingCommits = true
+				return b.save()
+			}
+			return b.finish()
+		}
+
+		pending, err := b.tryVersion(candidate)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return b.save()
+		}
+	}
+}
+
+// nextUnversioned waits for a version to be created in the unversioned
+// commit closest to the middle of the search range that hasn't been tried
+// yet, or finishes the bisection if there are none.
+func (b *Bisection) nextUnversioned() error {
+	mid := (b.GoodPosition + b.BadPosition) / 2
+	candidate := 0
+	for pos := b.GoodPosition + 1; pos < b.BadPosition; pos++ {
+		if b.triedRevision(b.UnversionedRevisions[pos-1]) {
+			continue
+		}
+		if candidate == 0 || abs(pos-mid) < abs(candidate-mid) {
+			candidate = pos
+		}
+	}
+	if candidate == 0 {
+		return b.finish()
+	}
+	b.PendingRevision = b.UnversionedRevisions[candidate-1]
+	return b.save()
+}
+
+// tryVersion adds a step that runs the task in the version. It returns
+// whether the bisection is now waiting for the task to finish.
+func (b *Bisection) tryVersion(v *Version) (bool, error) {
+	t, err := b.findOrCreateTask(v)
+	if err != nil {
+		return false, errors.Wrapf(err, "finding task for bisection '%s' in version '%s'", b.Id, v.Id)
+	}
+	step := BisectionStep{VersionId: v.Id, Revision: v.Revision}
+	if !b.SearchingUnversioned {
+		step.RevisionOrderNumber = v.RevisionOrderNumber
+	}
+	if t == nil {
+		step.Status = BisectionStepSkipped
+		b.Steps = append(b.Steps, step)
+		return false, nil
+	}
+	step.TaskId = t.Id
+	b.Steps = append(b.Steps, step)
+	if t.IsFinished() {
+		// The task already ran in this commit, so its result can be used
+		// without running it again.
+		b.recordResult(t, t.Status)
+		return false, nil
+	}
+
+	if !t.Activated {
+		if err = SetActiveState(evergreen.BisectTaskActivator, true, *t); err != nil {
+			return false, errors.Wrapf(err, "activating task '%s' for bisection '%s'", t.Id, b.Id)
+		}
+	}
+	b.Steps[len(b.Steps)-1].StartTime = time.Now()
+	b.PendingTaskId = t.Id
+	return true, nil
+}
+
+// untestedInRange returns whether any commit that the bisection tried is
+// still in the search range, which means the bisection can't tell which
+// commit in the range broke the task.
+func (b *Bisection) untestedInRange() bool {
+	for _, step := range b.Steps {
+		if step.RevisionOrderNumber > b.GoodOrder && step.RevisionOrderNumber < b.BadOrder {
+			return true
+		}
+		if b.SearchingUnversioned && step.RevisionOrderNumber == 0 {
+			if pos := b.position(step.Revision); pos > b.GoodPosition && pos < b.BadPosition {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RevisionRange returns the mainline commits that the task is known to pass
+// and fail in.
+func (b *Bisection) RevisionRange() (good string, bad string, err error) {
+	for _, id := range []string{b.GoodTaskId, b.BadTaskId} {
+		t, err := task.FindOneId(id)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "finding task '%s'", id)
+		}
+		if t == nil {
+			return "", "", errors.Errorf("task '%s' not found", id)
+		}
+		if id == b.GoodTaskId {
+			good = t.Revision
+		} else {
+			bad = t.Revision
+		}
+	}
+	return good, bad, nil
+}
+
+// SearchUnversionedRevisions continues the bisection in the commits between
+// the good and bad mainline versions that never got a version, oldest first.
+func (b *Bisection) SearchUnversionedRevisions(revisions []string) error {
+	b.AwaitingCommits = false
+	b.SearchingUnversioned = true
+	b.UnversionedRevisions = revisions
+	b.GoodPosition = 0
+	b.BadPosition = len(revisions) + 1
+	return b.next()
+}
+
+// ResumeInVersion continues the bisection in the version that was created
+// for its pending revision.
+func (b *Bisection) ResumeInVersion(versionId string) error {
+	v, err := VersionFindOneId(versionId)
+	if err != nil {
+		return errors.Wrapf(err, "finding version '%s'", versionId)
+	}
+	if v == nil {
+		return errors.Errorf("version '%s' not found", versionId)
+	}
+	b.PendingRevision = ""
+	pending, err := b.tryVersion(v)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return b.save()
+	}
+	return b.next()
+}
+
+// SkipPendingRevision continues the bisection without running the task in
+// its pending revision, because a version couldn't be created there.
+func (b *Bisection) SkipPendingRevision() error {
+	b.Steps = append(b.Steps, BisectionStep{Revision: b.PendingRevision, Status: BisectionStepSkipped})
+	b.PendingRevision = ""
+	return b.next()
+}
+
+// AbandonStalledBisectionSteps moves on from the steps of in-progress
+// bisections whose task will not finish on its own, because it was
+// deactivated, is blocked by a dependency, or has been waited on for longer
+// than the step timeout. Otherwise the bisection would never finish, and
+// stepback and new bisections of the task would stay disabled.
+func AbandonStalledBisectionSteps(now time.Time) error {
+	bisections := []Bisection{}
+	err := db.FindAllQ(BisectionsCollection, db.Query(bson.M{
+		bisectionStatusKey:        BisectionInProgress,
+		bisectionPendingTaskIdKey: bson.M{"$nin": []interface{}{nil, ""}},
+	}), &bisections)
+	if err != nil {
+		return errors.Wrap(err, "finding in-progress bisections")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for i := range bisections {
+		b := &bisections[i]
+		t, err := task.FindOneId(b.PendingTaskId)
+		if err != nil {
+			catcher.Wrapf(err, "finding pending task '%s' for bisection '%s'", b.PendingTaskId, b.Id)
+			continue
+		}
+		reason := b.stallReason(t, now)
+		if reason == "" {
+			continue
+		}
+		grip.Info(message.Fields{
+			"message":   "abandoning bisection step",
+			"bisection": b.Id,
+			"project":   b.ProjectId,
+			"task":      b.PendingTaskId,
+			"reason":    reason,
+		})
+		for j := range b.Steps {
+			if b.Steps[j].TaskId == b.PendingTaskId && b.Steps[j].Status == "" {
+				b.Steps[j].Status = BisectionStepAbandoned
+			}
+		}
+		b.PendingTaskId = ""
+		catcher.Wrapf(b.next(), "advancing bisection '%s'", b.Id)
+	}
+	return catcher.Resolve()
+}
+
+// stallReason returns why the bisection's pending task will not finish on its
+// own, or an empty string if it still might.
+func (b *Bisection) stallReason(t *task.Task, now time.Time) string {
+	switch {
+	case t == nil:
+		return "task not found"
+	case t.IsFinished():
+		return ""
+	case !t.Activated:
+		return "task was deactivated"
+	case t.Blocked():
+		return "task is blocked by a dependency"
+	}
+	for _, step := range b.Steps {
+		if step.TaskId == t.Id && !utility.IsZeroTime(step.StartTime) && now.Sub(step.StartTime) > bisectionStepTimeout {
+			return "task did not finish in time"
+		}
+	}
+	return ""
+}
+
+// findOrCreateTask returns the bisection's task in the version, creating it
+// if the version was created without it. It returns nil if the version's
+// config doesn't define the task.
+func (b *Bisection) findOrCreateTask(v *Version) (*task.Task, error) {
+	query := bson.M{
+		task.VersionKey:      v.Id,
+		task.BuildVariantKey: b.BuildVariant,
+		task.DisplayNameKey:  b.DisplayName,
+	}
+	t, err := task.FindOne(db.Query(query))
+	if err != nil || t != nil {
+		return t, err
+	}
+
+	project, err := FindProjectFromVersionID(v.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding project config")
+	}
+	pairs := TaskVariantPairs{}
+	if dt := project.GetDisplayTask(b.BuildVariant, b.DisplayName); dt != nil {
+		pairs.DisplayTasks = TVPairSet{{Variant: b.BuildVariant, TaskName: b.DisplayName}}
+		for _, execTask := range dt.ExecTasks {
+			pairs.ExecTasks = append(pairs.ExecTasks, TVPair{Variant: b.BuildVariant, TaskName: execTask})
+		}
+	} else if project.FindTaskForVariant(b.DisplayName, b.BuildVariant) != nil {
+		pairs.ExecTasks = TVPairSet{{Variant: b.BuildVariant, TaskName: b.DisplayName}}
+	} else {
+		return nil, nil
+	}
+	pairs.ExecTasks, err = IncludeDependencies(project, pairs.ExecTasks, evergreen.RepotrackerVersionRequester)
+	if err != nil {
+		return nil, errors.Wrap(err, "including dependencies")
+	}
+
+	pRef, err := FindMergedProjectRef(b.ProjectId, v.Id, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding project ref")
+	}
+	if pRef == nil {
+		return nil, errors.Errorf("project ref '%s' not found", b.ProjectId)
+	}
+	existingBuilds, err := build.Find(build.ByIds(v.BuildIds).WithFields(build.IdKey, build.BuildVariantKey, build.CreateTimeKey, build.RequesterKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding builds")
+	}
+	ctx := context.Background()
+	if _, err = addNewBuilds(ctx, specificActivationInfo{}, v, project, pairs, existingBuilds, patch.SyncAtEndOptions{}, pRef, ""); err != nil {
+		return nil, errors.Wrap(err, "adding new builds")
+	}
+	if _, err = addNewTasks(ctx, specificActivationInfo{}, v, project, pRef, pairs, existingBuilds, patch.SyncAtEndOptions{}, ""); err != nil {
+		return nil, errors.Wrap(err, "adding new tasks")
+	}
+	return task.FindOne(db.Query(query))
+}
+
+// finish ends the bisection once there are no more commits to try and
+// annotates the task in the commit that broke it.
+func (b *Bisection) finish() error {
+	b.Status = BisectionFound
+	if b.untestedInRange() {
+		b.Status = BisectionInconclusive
+	}
+	b.AwaitingCommits = false
+	b.FinishTime = time.Now()
+
+	culprit, err := task.FindOneId(b.BadTaskId)
+	if err != nil {
+		return errors.Wrapf(err, "finding task '%s'", b.BadTaskId)
+	}
+	if culprit == nil {
+		return errors.Errorf("task '%s' not found", b.BadTaskId)
+	}
+	if b.Status == BisectionFound {
+		b.CulpritVersionId = culprit.Version
+		b.CulpritRevision = culprit.Revision
+	}
+	if err = b.save(); err != nil {
+		return err
+	}
+
+	grip.Info(message.Fields{
+		"message":   "finished bisection",
+		"bisection": b.Id,
+		"project":   b.ProjectId,
+		"status":    b.Status,
+		"culprit":   b.CulpritRevision,
+	})
+	return errors.Wrapf(b.annotate(culprit), "annotating task '%s'", culprit.Id)
+}
+
+// annotate adds the bisection's result to the note on the failing task's
+// annotation, keeping any note that's already there.
+func (b *Bisection) annotate(t *task.Task) error {
+	var msg string
+	if b.Status == BisectionFound {
+		msg = fmt.Sprintf("Automatic bisection found that commit %s is the first commit that this task fails in.", t.Revision)
+	} else {
+		msg = fmt.Sprintf("Automatic bisection found that this task started failing in or before commit %s, but couldn't tell which of the preceding untested commits broke it.", t.Revision)
+	}
+
+	a, err := annotations.FindOneByTaskIdAndExecution(t.Id, t.Execution)
+	if err != nil {
+		return errors.Wrap(err, "finding task annotation")
+	}
+	var existing string
+	if a != nil && a.Note != nil {
+		existing = a.Note.Message
+	}
+	newMsg := msg
+	if existing != "" {
+		newMsg = existing + "\n\n" + msg
+	}
+	return annotations.UpdateAnnotationNote(t.Id, t.Execution, existing, newMsg, evergreen.BisectTaskActivator)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,296 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/annotations"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBisection(t *testing.T) {
+	// setup inserts a mainline commit for each order number from 1 to 7, with
+	// the task passing in the first commit, failing in the last, and not run
+	// in between.
+	setup := func(t *testing.T, autoBisect bool) []task.Task {
+		require.NoError(t, db.ClearCollections(BisectionsCollection, task.Collection, build.Collection, VersionCollection, ProjectRefCollection, annotations.Collection))
+		pRef := ProjectRef{
+			Id:         "project",
+			Identifier: "project",
+			AutoBisect: utility.ToBoolPtr(autoBisect),
+		}
+		require.NoError(t, pRef.Insert())
+
+		var tasks []task.Task
+		for order := 1; order <= 7; order++ {
+			v := Version{
+				Id:                  fmt.Sprintf("v%d", order),
+				Identifier:          pRef.Id,
+				Revision:            fmt.Sprintf("r%d", order),
+				RevisionOrderNumber: order,
+				Requester:           evergreen.RepotrackerVersionRequester,
+				BuildIds:            []string{fmt.Sprintf("b%d", order)},
+			}
+			require.NoError(t, v.Insert())
+			b := build.Build{
+				Id:           fmt.Sprintf("b%d", order),
+				Version:      v.Id,
+				BuildVariant: "bv",
+				Status:       evergreen.BuildCreated,
+			}
+			require.NoError(t, b.Insert())
+			tsk := task.Task{
+				Id:                  fmt.Sprintf("t%d", order),
+				DisplayName:         "test",
+				BuildVariant:        "bv",
+				BuildId:             b.Id,
+				Version:             v.Id,
+				Project:             pRef.Id,
+				Revision:            v.Revision,
+				RevisionOrderNumber: order,
+				Requester:           evergreen.RepotrackerVersionRequester,
+				Status:              evergreen.TaskUndispatched,
+			}
+			switch order {
+			case 1:
+				tsk.Activated = true
+				tsk.Status = evergreen.TaskSucceeded
+			case 7:
+				tsk.Activated = true
+				tsk.Status = evergreen.TaskFailed
+			}
+			require.NoError(t, tsk.Insert())
+			tasks = append(tasks, tsk)
+		}
+		return tasks
+	}
+	// finishStep finishes the bisection's pending task with the given status
+	// and returns the updated bisection.
+	finishStep := func(t *testing.T, bisectionID, status, failureType string) *Bisection {
+		b, err := FindBisection(bisectionID)
+		require.NoError(t, err)
+		require.NotNil(t, b)
+		require.NotEmpty(t, b.PendingTaskId)
+		tsk, err := task.FindOneId(b.PendingTaskId)
+		require.NoError(t, err)
+		require.NotNil(t, tsk)
+		assert.True(t, tsk.Activated)
+		assert.Equal(t, evergreen.BisectTaskActivator, tsk.ActivatedBy)
+
+		tsk.Status = status
+		tsk.Details.Type = failureType
+		isStep, err := advanceBisection(tsk, status)
+		require.NoError(t, err)
+		assert.True(t, isStep)
+
+		b, err = FindBisection(bisectionID)
+		require.NoError(t, err)
+		require.NotNil(t, b)
+		return b
+	}
+	startBisection := func(t *testing.T, failed task.Task) *Bisection {
+		bisecting, err := maybeStartBisection(&failed)
+		require.NoError(t, err)
+		require.True(t, bisecting)
+		bisections, err := FindBisectionsByProject(failed.Project, BisectionInProgress, 0)
+		require.NoError(t, err)
+		require.Len(t, bisections, 1)
+		return &bisections[0]
+	}
+
+	t.Run("FindsCulprit", func(t *testing.T) {
+		tasks := setup(t, true)
+		b := startBisection(t, tasks[6])
+		assert.Equal(t, 1, b.GoodOrder)
+		assert.Equal(t, 7, b.BadOrder)
+		assert.Equal(t, "t4", b.PendingTaskId)
+
+		b = finishStep(t, b.Id, evergreen.TaskSucceeded, "")
+		assert.Equal(t, 4, b.GoodOrder)
+		assert.Equal(t, "t5", b.PendingTaskId)
+
+		b = finishStep(t, b.Id, evergreen.TaskFailed, evergreen.CommandTypeTest)
+		assert.Equal(t, 5, b.BadOrder)
+		assert.Equal(t, BisectionInProgress, b.Status)
+		assert.True(t, b.AwaitingCommits)
+
+		awaiting, err := FindBisectionsAwaitingVersions()
+		require.NoError(t, err)
+		require.Len(t, awaiting, 1)
+		good, bad, err := b.RevisionRange()
+		require.NoError(t, err)
+		assert.Equal(t, "r4", good)
+		assert.Equal(t, "r5", bad)
+		require.NoError(t, b.SearchUnversionedRevisions(nil))
+
+		b, err = FindBisection(b.Id)
+		require.NoError(t, err)
+		require.NotNil(t, b)
+		assert.Equal(t, BisectionFound, b.Status)
+		assert.False(t, b.AwaitingCommits)
+		assert.Empty(t, b.PendingTaskId)
+		assert.Equal(t, "v5", b.CulpritVersionId)
+		assert.Equal(t, "r5", b.CulpritRevision)
+		require.Len(t, b.Steps, 2)
+		assert.Equal(t, evergreen.TaskSucceeded, b.Steps[0].Status)
+		assert.Equal(t, evergreen.TaskFailed, b.Steps[1].Status)
+
+		a, err := annotations.FindOneByTaskIdAndExecution("t5", 0)
+		require.NoError(t, err)
+		require.NotNil(t, a)
+		require.NotNil(t, a.Note)
+		assert.Contains(t, a.Note.Message, "r5")
+	})
+	t.Run("SearchesUnversionedCommits", func(t *testing.T) {
+		tasks := setup(t, true)
+		b := startBisection(t, tasks[6])
+		b = finishStep(t, b.Id, evergreen.TaskSucceeded, "")
+		b = finishStep(t, b.Id, evergreen.TaskFailed, evergreen.CommandTypeTest)
+		require.True(t, b.AwaitingCommits)
+
+		require.NoError(t, b.SearchUnversionedRevisions([]string{"u1", "u2", "u3"}))
+		b, err := FindBisection(b.Id)
+		require.NoError(t, err)
+		assert.True(t, b.SearchingUnversioned)
+		assert.Equal(t, 0, b.GoodPosition)
+		assert.Equal(t, 4, b.BadPosition)
+		assert.Equal(t, "u2", b.PendingRevision)
+
+		// createAdHocVersion stands in for the version that the bisection
+		// job creates in an unversioned commit.
+		createAdHocVersion := func(t *testing.T, revision string) string {
+			v := Version{
+				Id:                  "adhoc_" + revision,
+				Identifier:          "project",
+				Revision:            revision,
+				RevisionOrderNumber: 100,
+				Requester:           evergreen.AdHocRequester,
+				BuildIds:            []string{"b_" + revision},
+			}
+			require.NoError(t, v.Insert())
+			require.NoError(t, (&build.Build{Id: "b_" + revision, Version: v.Id, BuildVariant: "bv"}).Insert())
+			require.NoError(t, (&task.Task{
+				Id:                  "t_" + revision,
+				DisplayName:         "test",
+				BuildVariant:        "bv",
+				BuildId:             "b_" + revision,
+				Version:             v.Id,
+				Project:             "project",
+				Revision:            revision,
+				RevisionOrderNumber: v.RevisionOrderNumber,
+				Requester:           evergreen.AdHocRequester,
+				Status:              evergreen.TaskUndispatched,
+			}).Insert())
+			return v.Id
+		}
+
+		require.NoError(t, b.ResumeInVersion(createAdHocVersion(t, "u2")))
+		b = finishStep(t, b.Id, evergreen.TaskFailed, evergreen.CommandTypeTest)
+		assert.Equal(t, 2, b.BadPosition)
+		assert.Equal(t, "t_u2", b.BadTaskId)
+		assert.Equal(t, "u1", b.PendingRevision)
+
+		require.NoError(t, b.ResumeInVersion(createAdHocVersion(t, "u1")))
+		b = finishStep(t, b.Id, evergreen.TaskSucceeded, "")
+		assert.Equal(t, BisectionFound, b.Status)
+		assert.Equal(t, "adhoc_u2", b.CulpritVersionId)
+		assert.Equal(t, "u2", b.CulpritRevision)
+	})
+	t.Run("SkipsUnversionedCommitWithoutVersion", func(t *testing.T) {
+		tasks := setup(t, true)
+		b := startBisection(t, tasks[6])
+		b = finishStep(t, b.Id, evergreen.TaskSucceeded, "")
+		b = finishStep(t, b.Id, evergreen.TaskFailed, evergreen.CommandTypeTest)
+		require.NoError(t, b.SearchUnversionedRevisions([]string{"u1"}))
+		b, err := FindBisection(b.Id)
+		require.NoError(t, err)
+		require.Equal(t, "u1", b.PendingRevision)
+
+		require.NoError(t, b.SkipPendingRevision())
+		b, err = FindBisection(b.Id)
+		require.NoError(t, err)
+		assert.Equal(t, BisectionInconclusive, b.Status)
+		assert.Empty(t, b.PendingRevision)
+	})
+	t.Run("UsesExistingResults", func(t *testing.T) {
+		tasks := setup(t, true)
+		require.NoError(t, task.UpdateOne(
+			bson.M{task.IdKey: "t4"},
+			bson.M{"$set": bson.M{task.ActivatedKey: true, task.StatusKey: evergreen.TaskFailed}},
+		))
+		b := startBisection(t, tasks[6])
+		assert.Equal(t, 4, b.BadOrder)
+		assert.Equal(t, "t2", b.PendingTaskId)
+	})
+	t.Run("InconclusiveAfterSystemFailure", func(t *testing.T) {
+		tasks := setup(t, true)
+		b := startBisection(t, tasks[6])
+		b = finishStep(t, b.Id, evergreen.TaskSucceeded, "")
+		b = finishStep(t, b.Id, evergreen.TaskFailed, evergreen.CommandTypeSystem)
+		assert.Equal(t, "t6", b.PendingTaskId)
+		b = finishStep(t, b.Id, evergreen.TaskFailed, evergreen.CommandTypeTest)
+		assert.Equal(t, BisectionInconclusive, b.Status)
+		assert.Equal(t, 4, b.GoodOrder)
+		assert.Equal(t, 6, b.BadOrder)
+		assert.Empty(t, b.CulpritVersionId)
+	})
+	t.Run("AbandonsDeactivatedStep", func(t *testing.T) {
+		tasks := setup(t, true)
+		b := startBisection(t, tasks[6])
+		require.Equal(t, "t4", b.PendingTaskId)
+
+		require.NoError(t, AbandonStalledBisectionSteps(time.Now()))
+		b, err := FindBisection(b.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "t4", b.PendingTaskId, "running step should not be abandoned")
+
+		require.NoError(t, task.UpdateOne(
+			bson.M{task.IdKey: "t4"},
+			bson.M{"$set": bson.M{task.ActivatedKey: false}},
+		))
+		require.NoError(t, AbandonStalledBisectionSteps(time.Now()))
+		b, err = FindBisection(b.Id)
+		require.NoError(t, err)
+		require.Len(t, b.Steps, 2)
+		assert.Equal(t, BisectionStepAbandoned, b.Steps[0].Status)
+		assert.Equal(t, 1, b.GoodOrder)
+		assert.Equal(t, 7, b.BadOrder)
+		assert.NotEqual(t, "t4", b.PendingTaskId)
+		assert.NotEmpty(t, b.PendingTaskId)
+	})
+	t.Run("AbandonsTimedOutStep", func(t *testing.T) {
+		tasks := setup(t, true)
+		b := startBisection(t, tasks[6])
+		require.Equal(t, "t4", b.PendingTaskId)
+
+		require.NoError(t, AbandonStalledBisectionSteps(time.Now().Add(bisectionStepTimeout+time.Minute)))
+		b, err := FindBisection(b.Id)
+		require.NoError(t, err)
+		require.NotEmpty(t, b.Steps)
+		assert.Equal(t, BisectionStepAbandoned, b.Steps[0].Status)
+		assert.NotEqual(t, "t4", b.PendingTaskId)
+	})
+	t.Run("NoGap", func(t *testing.T) {
+		tasks := setup(t, true)
+		bisecting, err := maybeStartBisection(&tasks[1])
+		require.NoError(t, err)
+		assert.True(t, bisecting)
+		bisections, err := FindBisectionsByProject("project", "", 0)
+		require.NoError(t, err)
+		assert.Empty(t, bisections)
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		tasks := setup(t, false)
+		bisecting, err := maybeStartBisection(&tasks[6])
+		require.NoError(t, err)
+		assert.False(t, bisecting)
+	})
+}
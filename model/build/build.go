@@ -46,11 +46,17 @@ type Build struct {
 	ActualMakespan      time.Duration `bson:"actual_makespan" json:"actual_makespan,omitempty"`
 	Aborted             bool          `bson:"aborted" json:"aborted,omitempty"`
 
+	// Timing is the time the build's tasks spent blocked, queued, and running.
+	Timing task.TimingBreakdown `bson:"timing,omitempty" json:"timing,omitempty"`
+
 	// Tags that describe the variant
 	Tags []string `bson:"tags,omitempty" json:"tags,omitempty"`
 
 	// The status of the subset of the build that's used for github checks
 	GithubCheckStatus string `bson:"github_check_status,omitempty" json:"github_check_status,omitempty"`
+	// The github check status that was last posted to GitHub as the
+	// variant's own check, if the project posts one check per variant
+	GithubCheckPostedStatus string `bson:"github_check_posted_status,omitempty" json:"github_check_posted_status,omitempty"`
 	// does the build contain tasks considered for mainline github checks
 	IsGithubCheck bool `bson:"is_github_check,omitempty" json:"is_github_check,omitempty"`
 
@@ -73,6 +79,45 @@ type Build struct {
 	// Set to true if all tasks in the build are blocked.
 	// Should not be exposed, only for internal use.
 	AllTasksBlocked bool `bson:"all_tasks_blocked"`
+
+	// RollupCounts are the number of the build's tasks counted in each status
+	// rollup counter. RollupFlags are the version's counters the build is
+	// counted in. Both are only set for projects that use event-sourced
+	// status rollups.
+	RollupCounts map[string]int `bson:"rollup_counts,omitempty" json:"-"`
+	RollupFlags  []string       `bson:"rollup_flags,omitempty" json:"-"`
+
+	// ActivationHook is the result of the most recent activation hook that
+	// was evaluated before activating the build.
+	ActivationHook *ActivationHookResult `bson:"activation_hook,omitempty" json:"activation_hook,omitempty"`
+
+	// CanarySample is the decision on whether the build's variant was sampled
+	// for activation, if the variant only activates on a sample of mainline
+	// versions.
+	CanarySample *CanarySampleResult `bson:"canary_sample,omitempty" json:"canary_sample,omitempty"`
+}
+
+// ActivationHookResult is the decision of an external service on whether a
+// build could be activated.
+type ActivationHookResult struct {
+	URL     string `bson:"url" json:"url"`
+	Allowed bool   `bson:"allowed" json:"allowed"`
+	Message string `bson:"message,omitempty" json:"message,omitempty"`
+	// Caller is who tried to activate the build.
+	Caller string `bson:"caller" json:"caller"`
+	// Bypassed is true if the caller activated the build without evaluating
+	// the hook.
+	Bypassed    bool      `bson:"bypassed,omitempty" json:"bypassed,omitempty"`
+	EvaluatedAt time.Time `bson:"evaluated_at" json:"evaluated_at"`
+}
+
+// CanarySampleResult is the decision on whether a mainline version was in a
+// variant's canary sample.
+type CanarySampleResult struct {
+	Sampled bool `bson:"sampled" json:"sampled"`
+	// Reason explains why the version was or wasn't sampled.
+	Reason    string    `bson:"reason" json:"reason"`
+	SampledAt time.Time `bson:"sampled_at" json:"sampled_at"`
 }
 
 func (b *Build) MarshalBSON() ([]byte, error)  { return mgobson.Marshal(b) }
@@ -187,6 +232,28 @@ func (b *Build) SetAborted(aborted bool) error {
 	)
 }
 
+// SetActivationHookResult records the result of evaluating the build's
+// activation hook.
+func SetActivationHookResult(buildID string, result ActivationHookResult) error {
+	return errors.Wrapf(UpdateOne(
+		bson.M{IdKey: buildID},
+		bson.M{"$set": bson.M{ActivationHookKey: result}},
+	), "setting activation hook result for build '%s'", buildID)
+}
+
+// SetTimingBreakdown sets the time the build's tasks spent blocked, queued,
+// and running.
+func (b *Build) SetTimingBreakdown(timing task.TimingBreakdown) error {
+	if b.Timing == timing {
+		return nil
+	}
+	b.Timing = timing
+	return UpdateOne(
+		bson.M{IdKey: b.Id},
+		bson.M{"$set": bson.M{TimingKey: timing}},
+	)
+}
+
 // SetAllTasksBlocked sets the build AllTasksBlocked field to the given boolean.
 func (b *Build) SetAllTasksBlocked(blocked bool) error {
 	if b.AllTasksBlocked == blocked {
@@ -219,6 +286,27 @@ func (b *Build) UpdateGithubCheckStatus(status string) error {
 	)
 }
 
+// SetGithubCheckPostedStatus records the github check status that was posted
+// as the variant's check. It's a no-op if the build's github check status has
+// changed since it was posted, so that the new status is posted later.
+func (b *Build) SetGithubCheckPostedStatus(status string) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey:                b.Id,
+			GithubCheckStatusKey: status,
+		},
+		bson.M{"$set": bson.M{GithubCheckPostedStatusKey: status}},
+	)
+	if adb.ResultsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	b.GithubCheckPostedStatus = status
+	return nil
+}
+
 func (b *Build) SetIsGithubCheck() error {
 	if b.IsGithubCheck {
 		return nil
@@ -18,29 +18,35 @@ const Collection = "builds"
 
 var (
 	// bson fields for the build struct
-	IdKey                  = bsonutil.MustHaveTag(Build{}, "Id")
-	CreateTimeKey          = bsonutil.MustHaveTag(Build{}, "CreateTime")
-	StartTimeKey           = bsonutil.MustHaveTag(Build{}, "StartTime")
-	FinishTimeKey          = bsonutil.MustHaveTag(Build{}, "FinishTime")
-	VersionKey             = bsonutil.MustHaveTag(Build{}, "Version")
-	ProjectKey             = bsonutil.MustHaveTag(Build{}, "Project")
-	RevisionKey            = bsonutil.MustHaveTag(Build{}, "Revision")
-	BuildVariantKey        = bsonutil.MustHaveTag(Build{}, "BuildVariant")
-	StatusKey              = bsonutil.MustHaveTag(Build{}, "Status")
-	GithubCheckStatusKey   = bsonutil.MustHaveTag(Build{}, "GithubCheckStatus")
-	ActivatedKey           = bsonutil.MustHaveTag(Build{}, "Activated")
-	ActivatedByKey         = bsonutil.MustHaveTag(Build{}, "ActivatedBy")
-	ActivatedTimeKey       = bsonutil.MustHaveTag(Build{}, "ActivatedTime")
-	RevisionOrderNumberKey = bsonutil.MustHaveTag(Build{}, "RevisionOrderNumber")
-	TasksKey               = bsonutil.MustHaveTag(Build{}, "Tasks")
-	TimeTakenKey           = bsonutil.MustHaveTag(Build{}, "TimeTaken")
-	DisplayNameKey         = bsonutil.MustHaveTag(Build{}, "DisplayName")
-	RequesterKey           = bsonutil.MustHaveTag(Build{}, "Requester")
-	PredictedMakespanKey   = bsonutil.MustHaveTag(Build{}, "PredictedMakespan")
-	ActualMakespanKey      = bsonutil.MustHaveTag(Build{}, "ActualMakespan")
-	IsGithubCheckKey       = bsonutil.MustHaveTag(Build{}, "IsGithubCheck")
-	AbortedKey             = bsonutil.MustHaveTag(Build{}, "Aborted")
-	AllTasksBlockedKey     = bsonutil.MustHaveTag(Build{}, "AllTasksBlocked")
+	IdKey                      = bsonutil.MustHaveTag(Build{}, "Id")
+	CreateTimeKey              = bsonutil.MustHaveTag(Build{}, "CreateTime")
+	StartTimeKey               = bsonutil.MustHaveTag(Build{}, "StartTime")
+	FinishTimeKey              = bsonutil.MustHaveTag(Build{}, "FinishTime")
+	VersionKey                 = bsonutil.MustHaveTag(Build{}, "Version")
+	ProjectKey                 = bsonutil.MustHaveTag(Build{}, "Project")
+	RevisionKey                = bsonutil.MustHaveTag(Build{}, "Revision")
+	BuildVariantKey            = bsonutil.MustHaveTag(Build{}, "BuildVariant")
+	StatusKey                  = bsonutil.MustHaveTag(Build{}, "Status")
+	GithubCheckStatusKey       = bsonutil.MustHaveTag(Build{}, "GithubCheckStatus")
+	GithubCheckPostedStatusKey = bsonutil.MustHaveTag(Build{}, "GithubCheckPostedStatus")
+	ActivatedKey               = bsonutil.MustHaveTag(Build{}, "Activated")
+	ActivatedByKey             = bsonutil.MustHaveTag(Build{}, "ActivatedBy")
+	ActivatedTimeKey           = bsonutil.MustHaveTag(Build{}, "ActivatedTime")
+	RevisionOrderNumberKey     = bsonutil.MustHaveTag(Build{}, "RevisionOrderNumber")
+	TasksKey                   = bsonutil.MustHaveTag(Build{}, "Tasks")
+	TimeTakenKey               = bsonutil.MustHaveTag(Build{}, "TimeTaken")
+	DisplayNameKey             = bsonutil.MustHaveTag(Build{}, "DisplayName")
+	RequesterKey               = bsonutil.MustHaveTag(Build{}, "Requester")
+	PredictedMakespanKey       = bsonutil.MustHaveTag(Build{}, "PredictedMakespan")
+	ActualMakespanKey          = bsonutil.MustHaveTag(Build{}, "ActualMakespan")
+	IsGithubCheckKey           = bsonutil.MustHaveTag(Build{}, "IsGithubCheck")
+	AbortedKey                 = bsonutil.MustHaveTag(Build{}, "Aborted")
+	AllTasksBlockedKey         = bsonutil.MustHaveTag(Build{}, "AllTasksBlocked")
+	TimingKey                  = bsonutil.MustHaveTag(Build{}, "Timing")
+	RollupCountsKey            = bsonutil.MustHaveTag(Build{}, "RollupCounts")
+	RollupFlagsKey             = bsonutil.MustHaveTag(Build{}, "RollupFlags")
+	ActivationHookKey          = bsonutil.MustHaveTag(Build{}, "ActivationHook")
+	CanarySampleKey            = bsonutil.MustHaveTag(Build{}, "CanarySample")
 
 	TaskCacheIdKey = bsonutil.MustHaveTag(TaskCache{}, "Id")
 )
@@ -82,6 +88,35 @@ func ByProject(proj string) db.Q {
 	return db.Query(bson.M{ProjectKey: proj})
 }
 
+// ByRollupActiveSince creates a query that finds builds with status rollup
+// counters that are unfinished or finished after the given time.
+func ByRollupActiveSince(since time.Time) db.Q {
+	return db.Query(bson.M{
+		RollupCountsKey: bson.M{"$exists": true},
+		"$or": []bson.M{
+			{StatusKey: bson.M{"$in": []string{evergreen.BuildCreated, evergreen.BuildStarted}}},
+			{FinishTimeKey: bson.M{"$gte": since}},
+		},
+	})
+}
+
+// ByUnpostedGithubChecks creates a query that finds the mainline builds in the
+// projects created after the given time whose github check status has changed
+// since it was last posted to GitHub.
+func ByUnpostedGithubChecks(projectIDs []string, since time.Time) db.Q {
+	return db.Query(bson.M{
+		ProjectKey:           bson.M{"$in": projectIDs},
+		RequesterKey:         evergreen.RepotrackerVersionRequester,
+		IsGithubCheckKey:     true,
+		CreateTimeKey:        bson.M{"$gte": since},
+		GithubCheckStatusKey: bson.M{"$exists": true},
+		"$expr": bson.M{"$ne": bson.A{
+			"$" + GithubCheckStatusKey,
+			bson.M{"$ifNull": bson.A{"$" + GithubCheckPostedStatusKey, ""}},
+		}},
+	})
+}
+
 // ByProjectAndVariant creates a query that finds all completed builds for a given project
 // and variant, while also specifying a requester
 func ByProjectAndVariant(project, variant, requester string, statuses []string) db.Q {
@@ -45,7 +45,7 @@ type MultiSourceSuggest struct {
 
 type JiraSuggest struct {
 	BbProj      evergreen.BuildBaronSettings
-	JiraHandler thirdparty.JiraHandler
+	JiraHandler thirdparty.JiraIntegration
 }
 
 func (mss *MultiSourceSuggest) Suggest(t *task.Task) ([]thirdparty.JiraTicket, string, error) {
@@ -152,7 +152,7 @@ func GetSearchReturnInfo(taskId string, exec string) (*thirdparty.SearchReturnIn
 	}
 	bbConfig.SearchConfigured = true
 
-	jiraHandler := thirdparty.NewJiraHandler(*settings.Jira.Export())
+	jiraHandler := thirdparty.NewJiraIntegration(*settings.Jira.Export())
 	jira := &JiraSuggest{bbProj, jiraHandler}
 	multiSource := &MultiSourceSuggest{jira}
 
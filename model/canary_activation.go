@@ -0,0 +1,118 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// CanaryActivation limits the mainline versions that a build variant activates
+// on to a sample of them, for variants that are too expensive or experimental
+// to run on every commit. Versions outside of the sample are created with the
+// variant unactivated so that it can still be activated manually.
+type CanaryActivation struct {
+	// Every samples one in every N mainline versions, by revision order
+	// number.
+	Every int `yaml:"every,omitempty" bson:"every,omitempty"`
+	// StartHour and EndHour sample only the mainline versions that Evergreen
+	// creates from the start of StartHour up to the start of EndHour,
+	// regardless of when they were committed. If EndHour is before StartHour,
+	// the window wraps around midnight.
+	StartHour *int `yaml:"start_hour,omitempty" bson:"start_hour,omitempty"`
+	EndHour   *int `yaml:"end_hour,omitempty" bson:"end_hour,omitempty"`
+	// Timezone is the IANA time zone that the hours are evaluated in. If it's
+	// not set, the hours are in UTC.
+	Timezone string `yaml:"timezone,omitempty" bson:"timezone,omitempty"`
+}
+
+// HasHours returns whether the canary only samples versions created during
+// certain hours.
+func (c *CanaryActivation) HasHours() bool {
+	return c.StartHour != nil || c.EndHour != nil
+}
+
+// Validate checks that the canary samples a well-formed subset of versions.
+func (c *CanaryActivation) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(c.Every == 0 && !c.HasHours(), "must sample either one in every N versions or versions created during certain hours")
+	catcher.ErrorfWhen(c.Every < 0, "cannot sample one in every %d versions", c.Every)
+	if c.HasHours() {
+		catcher.NewWhen(c.StartHour == nil || c.EndHour == nil, "must specify both a start hour and an end hour")
+		if c.StartHour != nil && c.EndHour != nil {
+			catcher.ErrorfWhen(*c.StartHour < 0 || *c.StartHour > 23, "start hour %d must be between 0 and 23", *c.StartHour)
+			catcher.ErrorfWhen(*c.EndHour < 0 || *c.EndHour > 23, "end hour %d must be between 0 and 23", *c.EndHour)
+			catcher.NewWhen(*c.StartHour == *c.EndHour, "start hour and end hour cannot be the same")
+		}
+	}
+	if c.Timezone != "" {
+		catcher.ErrorfWhen(!c.HasHours(), "time zone '%s' is only used with a start and end hour", c.Timezone)
+		_, err := LoadCronTimezone(c.Timezone)
+		catcher.Add(err)
+	}
+	return catcher.Resolve()
+}
+
+// Sample decides whether the mainline version with the revision order number
+// that's created at the given time is in the canary's sample.
+func (c *CanaryActivation) Sample(revisionOrderNumber int, createTime time.Time) (*build.CanarySampleResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid canary")
+	}
+	result := &build.CanarySampleResult{
+		Sampled:   true,
+		SampledAt: time.Now(),
+	}
+	reasons := []string{}
+	if c.Every > 0 {
+		if revisionOrderNumber%c.Every != 0 {
+			result.Sampled = false
+		}
+		reasons = append(reasons, fmt.Sprintf("samples one in every %d versions and this is version %d", c.Every, revisionOrderNumber))
+	}
+	if c.HasHours() {
+		loc := time.UTC
+		if c.Timezone != "" {
+			var err error
+			loc, err = LoadCronTimezone(c.Timezone)
+			if err != nil {
+				return nil, errors.Wrap(err, "loading canary time zone")
+			}
+		}
+		localTime := createTime.In(loc)
+		if !hourInWindow(localTime.Hour(), *c.StartHour, *c.EndHour) {
+			result.Sampled = false
+		}
+		reasons = append(reasons, fmt.Sprintf("samples versions created from %02d:00 to %02d:00 %s and this version was created at %s",
+			*c.StartHour, *c.EndHour, loc.String(), localTime.Format("15:04")))
+	}
+	result.Reason = strings.Join(reasons, "; ")
+	return result, nil
+}
+
+// hourInWindow returns whether the hour is from the start hour up to the end
+// hour, wrapping around midnight if the end hour is before the start hour.
+func hourInWindow(hour, startHour, endHour int) bool {
+	if startHour < endHour {
+		return hour >= startHour && hour < endHour
+	}
+	return hour >= startHour || hour < endHour
+}
+
+// SampleCanary decides whether the version is in the build variant's canary
+// sample. It returns nil if the variant doesn't have a canary or the version
+// isn't a mainline version, since only mainline versions are sampled.
+func (bv *BuildVariant) SampleCanary(v *Version) (*build.CanarySampleResult, error) {
+	if bv.Canary == nil || v.Requester != evergreen.RepotrackerVersionRequester {
+		return nil, nil
+	}
+	result, err := bv.Canary.Sample(v.RevisionOrderNumber, time.Now())
+	if err != nil {
+		return nil, errors.Wrapf(err, "sampling canary for variant '%s'", bv.Name)
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanaryActivationValidate(t *testing.T) {
+	for name, canary := range map[string]CanaryActivation{
+		"Empty":            {},
+		"NegativeEvery":    {Every: -2},
+		"MissingEndHour":   {StartHour: utility.ToIntPtr(22)},
+		"HourOutOfRange":   {StartHour: utility.ToIntPtr(22), EndHour: utility.ToIntPtr(24)},
+		"SameHours":        {StartHour: utility.ToIntPtr(3), EndHour: utility.ToIntPtr(3)},
+		"TimezoneNoHours":  {Every: 2, Timezone: "UTC"},
+		"NonexistentZone":  {StartHour: utility.ToIntPtr(1), EndHour: utility.ToIntPtr(5), Timezone: "America/Nowhere"},
+		"LocalTimezoneSet": {StartHour: utility.ToIntPtr(1), EndHour: utility.ToIntPtr(5), Timezone: "Local"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, canary.Validate())
+		})
+	}
+	for name, canary := range map[string]CanaryActivation{
+		"Every":         {Every: 5},
+		"Hours":         {StartHour: utility.ToIntPtr(22), EndHour: utility.ToIntPtr(6), Timezone: "America/New_York"},
+		"EveryAndHours": {Every: 5, StartHour: utility.ToIntPtr(0), EndHour: utility.ToIntPtr(6)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, canary.Validate())
+		})
+	}
+}
+
+func TestCanaryActivationSample(t *testing.T) {
+	t.Run("Every", func(t *testing.T) {
+		canary := CanaryActivation{Every: 3}
+		for revision, expected := range map[int]bool{3: true, 4: false, 5: false, 6: true} {
+			result, err := canary.Sample(revision, time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, expected, result.Sampled, "revision %d", revision)
+			assert.Contains(t, result.Reason, "one in every 3 versions")
+		}
+	})
+	t.Run("HoursWrapAroundMidnight", func(t *testing.T) {
+		canary := CanaryActivation{StartHour: utility.ToIntPtr(22), EndHour: utility.ToIntPtr(6)}
+		for hour, expected := range map[int]bool{21: false, 22: true, 23: true, 0: true, 5: true, 6: false, 12: false} {
+			result, err := canary.Sample(1, time.Date(2022, 1, 10, hour, 30, 0, 0, time.UTC))
+			require.NoError(t, err)
+			assert.Equal(t, expected, result.Sampled, "hour %d", hour)
+		}
+	})
+	t.Run("HoursInTimezone", func(t *testing.T) {
+		canary := CanaryActivation{StartHour: utility.ToIntPtr(9), EndHour: utility.ToIntPtr(17), Timezone: "America/New_York"}
+		result, err := canary.Sample(1, time.Date(2022, 1, 10, 15, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.True(t, result.Sampled, "10:00 in New York")
+		result, err = canary.Sample(1, time.Date(2022, 1, 10, 23, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.False(t, result.Sampled, "18:00 in New York")
+		assert.Contains(t, result.Reason, "America/New_York")
+	})
+	t.Run("EveryAndHoursMustBothMatch", func(t *testing.T) {
+		canary := CanaryActivation{Every: 2, StartHour: utility.ToIntPtr(0), EndHour: utility.ToIntPtr(6)}
+		inWindow := time.Date(2022, 1, 10, 3, 0, 0, 0, time.UTC)
+		result, err := canary.Sample(2, inWindow)
+		require.NoError(t, err)
+		assert.True(t, result.Sampled)
+		result, err = canary.Sample(3, inWindow)
+		require.NoError(t, err)
+		assert.False(t, result.Sampled)
+		result, err = canary.Sample(2, inWindow.Add(6*time.Hour))
+		require.NoError(t, err)
+		assert.False(t, result.Sampled)
+	})
+	t.Run("OnlyMainlineVersions", func(t *testing.T) {
+		bv := BuildVariant{Name: "bv", Canary: &CanaryActivation{Every: 2}}
+		result, err := bv.SampleCanary(&Version{Requester: evergreen.PatchVersionRequester, RevisionOrderNumber: 3})
+		require.NoError(t, err)
+		assert.Nil(t, result)
+		result, err = bv.SampleCanary(&Version{Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 3})
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.False(t, result.Sampled)
+	})
+}
+
+func TestCanaryActivationParsing(t *testing.T) {
+	yml := `
+tasks:
+- name: t1
+buildvariants:
+- name: expensive
+  canary:
+    every: 10
+    start_hour: 22
+    end_hour: 6
+    timezone: America/New_York
+  tasks:
+  - name: t1
+`
+	p := &Project{}
+	_, err := LoadProjectInto(context.Background(), []byte(yml), nil, "id", p)
+	require.NoError(t, err)
+	require.Len(t, p.BuildVariants, 1)
+	canary := p.BuildVariants[0].Canary
+	require.NotNil(t, canary)
+	assert.Equal(t, 10, canary.Every)
+	assert.Equal(t, 22, utility.FromIntPtr(canary.StartHour))
+	assert.Equal(t, 6, utility.FromIntPtr(canary.EndHour))
+	assert.Equal(t, "America/New_York", canary.Timezone)
+}
@@ -0,0 +1,268 @@
+package model
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	CodeOwnersCollection = "code_owners"
+
+	// CodeOwnersTTL is how long a cached CODEOWNERS file is kept. Failures
+	// are rarely investigated at revisions older than this.
+	CodeOwnersTTL = 30 * 24 * time.Hour
+
+	// codeOwnersFetchTimeout bounds how long fetching a CODEOWNERS file from
+	// GitHub can hold up the notification waiting on it.
+	codeOwnersFetchTimeout = 10 * time.Second
+)
+
+// codeOwnersPaths are the locations GitHub looks for a CODEOWNERS file, in
+// the order it looks for them.
+var codeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwnersRule assigns owners to the files matching a CODEOWNERS pattern.
+type CodeOwnersRule struct {
+	Pattern string `bson:"pattern" json:"pattern"`
+	// Owners are GitHub users (@user), teams (@org/team) or email
+	// addresses. A rule without owners leaves the matching files unowned.
+	Owners []string `bson:"owners,omitempty" json:"owners,omitempty"`
+}
+
+// CodeOwners is the parsed CODEOWNERS file of a repo at a revision. It is
+// cached so that GitHub is only asked for it once per revision.
+type CodeOwners struct {
+	Id       string `bson:"_id" json:"id"`
+	Owner    string `bson:"owner" json:"owner"`
+	Repo     string `bson:"repo" json:"repo"`
+	Revision string `bson:"revision" json:"revision"`
+	// Path is the location of the CODEOWNERS file in the repo. It is empty
+	// if the repo has no CODEOWNERS file at the revision.
+	Path      string           `bson:"path,omitempty" json:"path,omitempty"`
+	Rules     []CodeOwnersRule `bson:"rules,omitempty" json:"rules,omitempty"`
+	CreatedAt time.Time        `bson:"created_at" json:"created_at"`
+}
+
+var (
+	codeOwnersIdKey        = bsonutil.MustHaveTag(CodeOwners{}, "Id")
+	codeOwnersCreatedAtKey = bsonutil.MustHaveTag(CodeOwners{}, "CreatedAt")
+)
+
+func codeOwnersId(owner, repo, revision string) string {
+	return fmt.Sprintf("%s/%s@%s", owner, repo, revision)
+}
+
+// ParseCodeOwners parses the contents of a CODEOWNERS file into its rules, in
+// the order they appear in the file.
+func ParseCodeOwners(contents string) []CodeOwnersRule {
+	var rules []CodeOwnersRule
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rules = append(rules, CodeOwnersRule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+		})
+	}
+	return rules
+}
+
+// OwnersForPath returns the owners of the file at the given path. As with
+// GitHub, the last rule matching the path takes precedence.
+func (c *CodeOwners) OwnersForPath(filePath string) []string {
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if codeOwnersPatternMatches(c.Rules[i].Pattern, filePath) {
+			return c.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// OwnersForPaths returns the owners of any of the files at the given paths,
+// without duplicates.
+func (c *CodeOwners) OwnersForPaths(filePaths []string) []string {
+	var owners []string
+	for _, filePath := range filePaths {
+		for _, owner := range c.OwnersForPath(filePath) {
+			if !utility.StringSliceContains(owners, owner) {
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// codeOwnersPatternMatches returns whether the file path matches the
+// CODEOWNERS pattern, which follows the same rules as a .gitignore pattern.
+func codeOwnersPatternMatches(pattern, filePath string) bool {
+	filePath = strings.TrimPrefix(path.Clean(strings.ReplaceAll(filePath, "\\", "/")), "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	// A pattern containing a slash other than a trailing one is relative to
+	// the root of the repo. Otherwise, it can match at any depth.
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+
+	expr := &strings.Builder{}
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+	// A pattern that matches a directory owns everything beneath it.
+	if dirOnly {
+		expr.WriteString("/.*$")
+	} else {
+		expr.WriteString("(/.*)?$")
+	}
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return false
+	}
+	return re.MatchString(filePath)
+}
+
+// FindCodeOwners returns the cached CODEOWNERS for the repo at the revision,
+// if it has been cached.
+func FindCodeOwners(owner, repo, revision string) (*CodeOwners, error) {
+	codeOwners := &CodeOwners{}
+	err := db.FindOneQ(CodeOwnersCollection, db.Query(bson.M{codeOwnersIdKey: codeOwnersId(owner, repo, revision)}), codeOwners)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "finding cached CODEOWNERS")
+	}
+	return codeOwners, nil
+}
+
+// RemoveExpiredCodeOwners deletes the CODEOWNERS files that were cached
+// before the given time.
+func RemoveExpiredCodeOwners(before time.Time) error {
+	err := db.RemoveAll(CodeOwnersCollection, bson.M{
+		codeOwnersCreatedAtKey: bson.M{"$lt": before},
+	})
+	return errors.Wrap(err, "removing expired CODEOWNERS")
+}
+
+// GetCodeOwners returns the CODEOWNERS for the repo at the revision, fetching
+// it from GitHub and caching it if it has not been cached yet. A repo without
+// a CODEOWNERS file has no rules.
+func GetCodeOwners(ctx context.Context, owner, repo, revision string) (*CodeOwners, error) {
+	codeOwners, err := FindCodeOwners(owner, repo, revision)
+	if err != nil {
+		return nil, err
+	}
+	if codeOwners != nil {
+		return codeOwners, nil
+	}
+
+	settings, err := evergreen.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting admin settings")
+	}
+	token, err := settings.GetGithubOauthToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting GitHub token")
+	}
+
+	codeOwners = &CodeOwners{
+		Id:        codeOwnersId(owner, repo, revision),
+		Owner:     owner,
+		Repo:      repo,
+		Revision:  revision,
+		CreatedAt: time.Now(),
+	}
+	ctx, cancel := context.WithTimeout(ctx, codeOwnersFetchTimeout)
+	defer cancel()
+	for _, filePath := range codeOwnersPaths {
+		file, err := thirdparty.GetGithubFile(ctx, token, owner, repo, filePath, revision)
+		if err != nil {
+			if _, ok := errors.Cause(err).(thirdparty.FileNotFoundError); ok {
+				continue
+			}
+			return nil, errors.Wrapf(err, "fetching '%s' for '%s/%s' at revision '%s'", filePath, owner, repo, revision)
+		}
+		contents, err := base64.StdEncoding.DecodeString(utility.FromStringPtr(file.Content))
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding '%s'", filePath)
+		}
+		codeOwners.Path = filePath
+		codeOwners.Rules = ParseCodeOwners(string(contents))
+		break
+	}
+
+	if _, err = db.Upsert(CodeOwnersCollection, bson.M{codeOwnersIdKey: codeOwners.Id}, codeOwners); err != nil {
+		return nil, errors.Wrap(err, "caching CODEOWNERS")
+	}
+	return codeOwners, nil
+}
+
+// FailureOwners returns the owners of the failing tests in a task, according
+// to the CODEOWNERS of the project's repo at the task's revision. It returns
+// no owners if the project does not route failures by ownership.
+func FailureOwners(ctx context.Context, pRef *ProjectRef, t *task.Task) ([]string, error) {
+	if !pRef.IsCodeOwnersRoutingEnabled() || t.Status != evergreen.TaskFailed || t.Revision == "" {
+		return nil, nil
+	}
+	if err := t.PopulateTestResults(); err != nil {
+		return nil, errors.Wrap(err, "populating test results")
+	}
+	var failedFiles []string
+	for _, result := range t.LocalTestResults {
+		if result.Status == evergreen.TestFailedStatus && result.TestFile != "" {
+			failedFiles = append(failedFiles, result.TestFile)
+		}
+	}
+	if len(failedFiles) == 0 {
+		return nil, nil
+	}
+
+	codeOwners, err := GetCodeOwners(ctx, pRef.Owner, pRef.Repo, t.Revision)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting CODEOWNERS for project '%s'", pRef.Id)
+	}
+	return codeOwners.OwnersForPaths(failedFiles), nil
+}
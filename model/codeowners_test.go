@@ -0,0 +1,119 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCodeOwners(t *testing.T) {
+	rules := ParseCodeOwners(`
+# default owners
+*       @org/everyone
+
+/docs/  docs@example.com # inline comment
+*.go    @gopher @org/go-team
+/vendor/
+`)
+	require.Len(t, rules, 4)
+	assert.Equal(t, CodeOwnersRule{Pattern: "*", Owners: []string{"@org/everyone"}}, rules[0])
+	assert.Equal(t, CodeOwnersRule{Pattern: "/docs/", Owners: []string{"docs@example.com"}}, rules[1])
+	assert.Equal(t, []string{"@gopher", "@org/go-team"}, rules[2].Owners)
+	assert.Equal(t, "/vendor/", rules[3].Pattern)
+	assert.Empty(t, rules[3].Owners)
+}
+
+func TestCodeOwnersPatternMatches(t *testing.T) {
+	for _, tCase := range []struct {
+		pattern string
+		path    string
+		matches bool
+	}{
+		{pattern: "*", path: "a/b/c.go", matches: true},
+		{pattern: "*.go", path: "a/b/c.go", matches: true},
+		{pattern: "*.go", path: "a/b/c.js", matches: false},
+		{pattern: "/docs/", path: "docs/index.md", matches: true},
+		{pattern: "/docs/", path: "src/docs/index.md", matches: false},
+		{pattern: "docs/", path: "src/docs/index.md", matches: true},
+		{pattern: "docs/", path: "docs", matches: false},
+		{pattern: "apps/", path: "apps/web/main.js", matches: true},
+		{pattern: "/build/logs", path: "build/logs/out.log", matches: true},
+		{pattern: "/build/logs", path: "build/logs", matches: true},
+		{pattern: "build/*.log", path: "build/a.log", matches: true},
+		{pattern: "build/*.log", path: "build/nested/a.log", matches: false},
+		{pattern: "**/tests/*.py", path: "a/b/tests/test_x.py", matches: true},
+		{pattern: "**/tests/*.py", path: "tests/test_x.py", matches: true},
+		{pattern: "src/**/util.go", path: "src/a/b/util.go", matches: true},
+		{pattern: "/jstests/core/", path: "/jstests/core/find.js", matches: true},
+	} {
+		assert.Equal(t, tCase.matches, codeOwnersPatternMatches(tCase.pattern, tCase.path), "pattern '%s', path '%s'", tCase.pattern, tCase.path)
+	}
+}
+
+func TestCodeOwnersOwnersForPaths(t *testing.T) {
+	codeOwners := CodeOwners{
+		Rules: ParseCodeOwners(`
+*              @org/everyone
+/src/          @alice
+/src/vendor/
+*.js           @bob @alice
+`),
+	}
+	assert.Equal(t, []string{"@org/everyone"}, codeOwners.OwnersForPath("README.md"))
+	assert.Equal(t, []string{"@alice"}, codeOwners.OwnersForPath("src/main.go"))
+	assert.Empty(t, codeOwners.OwnersForPath("src/vendor/lib.go"))
+	assert.Equal(t, []string{"@bob", "@alice"}, codeOwners.OwnersForPath("src/vendor/lib.js"))
+	assert.Equal(t, []string{"@alice", "@bob"}, codeOwners.OwnersForPaths([]string{"src/main.go", "src/vendor/lib.go", "web/app.js"}))
+}
+
+func TestGetCodeOwnersUsesCache(t *testing.T) {
+	require.NoError(t, db.Clear(CodeOwnersCollection))
+	defer func() {
+		assert.NoError(t, db.Clear(CodeOwnersCollection))
+	}()
+
+	cached := CodeOwners{
+		Id:       codeOwnersId("evergreen-ci", "evergreen", "abc123"),
+		Owner:    "evergreen-ci",
+		Repo:     "evergreen",
+		Revision: "abc123",
+		Path:     ".github/CODEOWNERS",
+		Rules:    []CodeOwnersRule{{Pattern: "*", Owners: []string{"@alice"}}},
+	}
+	require.NoError(t, db.Insert(CodeOwnersCollection, cached))
+
+	codeOwners, err := GetCodeOwners(context.Background(), "evergreen-ci", "evergreen", "abc123")
+	require.NoError(t, err)
+	require.NotNil(t, codeOwners)
+	assert.Equal(t, cached.Rules, codeOwners.Rules)
+
+	codeOwners, err = FindCodeOwners("evergreen-ci", "evergreen", "def456")
+	assert.NoError(t, err)
+	assert.Nil(t, codeOwners)
+}
+
+func TestRemoveExpiredCodeOwners(t *testing.T) {
+	require.NoError(t, db.Clear(CodeOwnersCollection))
+	defer func() {
+		assert.NoError(t, db.Clear(CodeOwnersCollection))
+	}()
+
+	now := time.Now()
+	expired := CodeOwners{Id: codeOwnersId("evergreen-ci", "evergreen", "old"), CreatedAt: now.Add(-2 * CodeOwnersTTL)}
+	current := CodeOwners{Id: codeOwnersId("evergreen-ci", "evergreen", "new"), CreatedAt: now}
+	require.NoError(t, db.Insert(CodeOwnersCollection, expired))
+	require.NoError(t, db.Insert(CodeOwnersCollection, current))
+
+	require.NoError(t, RemoveExpiredCodeOwners(now.Add(-CodeOwnersTTL)))
+
+	codeOwners, err := FindCodeOwners("evergreen-ci", "evergreen", "old")
+	assert.NoError(t, err)
+	assert.Nil(t, codeOwners)
+	codeOwners, err = FindCodeOwners("evergreen-ci", "evergreen", "new")
+	assert.NoError(t, err)
+	assert.NotNil(t, codeOwners)
+}
@@ -0,0 +1,299 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MergeTaskDependencyChange is a change to the chain of dependencies between
+// the merge tasks of the items in a commit queue.
+type MergeTaskDependencyChange struct {
+	// TaskID is the merge task whose dependencies change.
+	TaskID string
+	// RemoveDependencyOn is the merge task that the task stops depending on,
+	// if any.
+	RemoveDependencyOn string
+	// AddDependencyOn is the merge task that the task starts depending on, if
+	// any.
+	AddDependencyOn string
+}
+
+// CommitQueueGithubStatus is a commit queue status that would be sent to
+// GitHub for a patch.
+type CommitQueueGithubStatus struct {
+	PatchID     string
+	PRNumber    int
+	State       message.GithubState
+	Description string
+}
+
+// DequeueAndRestartPlan describes what DequeueAndRestartForTask would do for a
+// task without doing it.
+type DequeueAndRestartPlan struct {
+	ProjectID string
+	TaskID    string
+	// Issue is the commit queue item that would be dequeued.
+	Issue string
+	// RestartVersions are the versions of the later commit queue items that
+	// would be restarted.
+	RestartVersions []string
+	// DependencyChanges are the changes to the merge task dependencies that
+	// would take the dequeued item's merge task out of the chain.
+	DependencyChanges []MergeTaskDependencyChange
+	// GithubStatuses are the statuses that would be sent to GitHub.
+	GithubStatuses []CommitQueueGithubStatus
+	// Warnings are errors that DequeueAndRestartForTask would log without
+	// failing.
+	Warnings []string
+}
+
+// PlanDequeueAndRestartForTask returns what DequeueAndRestartForTask would do
+// for the given task without modifying anything.
+func PlanDequeueAndRestartForTask(cq *commitqueue.CommitQueue, t *task.Task, githubState message.GithubState, reason string) (*DequeueAndRestartPlan, error) {
+	if cq == nil {
+		var err error
+		cq, err = commitqueue.FindOneId(t.Project)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting commit queue for project '%s'", t.Project)
+		}
+		if cq == nil {
+			return nil, errors.Errorf("commit queue for project '%s' not found", t.Project)
+		}
+	}
+
+	p, err := patch.FindOneId(t.Version)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding patch")
+	}
+	if p == nil {
+		return nil, errors.Errorf("patch '%s' not found", t.Version)
+	}
+	issue := p.Id.Hex()
+	if cq.FindItem(issue) < 0 {
+		return nil, errors.Errorf("no commit queue entry for '%s'", issue)
+	}
+
+	plan := &DequeueAndRestartPlan{
+		ProjectID:       cq.ProjectID,
+		TaskID:          t.Id,
+		Issue:           issue,
+		RestartVersions: versionsAfterVersion(*cq, t.Version),
+	}
+
+	change, err := planRemoveNextMergeTaskDependency(*cq, issue)
+	if err != nil {
+		plan.Warnings = append(plan.Warnings, errors.Wrap(err, "removing dependency").Error())
+	}
+	if change != nil {
+		plan.DependencyChanges = append(plan.DependencyChanges, *change)
+	}
+
+	// SendCommitQueueResult only sends statuses for PR patches.
+	if p.GithubPatchData.PRNumber != 0 {
+		if p.IsPRMergePatch() {
+			plan.GithubStatuses = append(plan.GithubStatuses, CommitQueueGithubStatus{
+				PatchID:     issue,
+				PRNumber:    p.GithubPatchData.PRNumber,
+				State:       message.GithubStateFailure,
+				Description: "merge test failed",
+			})
+		}
+		plan.GithubStatuses = append(plan.GithubStatuses, CommitQueueGithubStatus{
+			PatchID:     issue,
+			PRNumber:    p.GithubPatchData.PRNumber,
+			State:       githubState,
+			Description: reason,
+		})
+	}
+
+	return plan, nil
+}
+
+// planRemoveNextMergeTaskDependency returns the change that takes the given
+// item's merge task out of the chain of merge task dependencies, or nil if no
+// change is needed.
+func planRemoveNextMergeTaskDependency(cq commitqueue.CommitQueue, currentIssue string) (*MergeTaskDependencyChange, error) {
+	currentIndex := cq.FindItem(currentIssue)
+	if currentIndex < 0 {
+		return nil, errors.New("commit queue item not found")
+	}
+	if currentIndex+1 >= len(cq.Queue) {
+		return nil, nil
+	}
+
+	nextItem := cq.Queue[currentIndex+1]
+	if nextItem.Version == "" {
+		return nil, nil
+	}
+	nextMerge, err := task.FindMergeTaskForVersion(nextItem.Version)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding next merge task")
+	}
+	if nextMerge == nil {
+		return nil, errors.New("no merge task found")
+	}
+	currentMerge, err := task.FindMergeTaskForVersion(cq.Queue[currentIndex].Version)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding current merge task")
+	}
+	if currentMerge == nil {
+		return nil, errors.New("no current merge task found")
+	}
+	change := &MergeTaskDependencyChange{
+		TaskID:             nextMerge.Id,
+		RemoveDependencyOn: currentMerge.Id,
+	}
+
+	if currentIndex > 0 {
+		prevItem := cq.Queue[currentIndex-1]
+		prevMerge, err := task.FindMergeTaskForVersion(prevItem.Version)
+		if err != nil {
+			return nil, errors.Wrap(err, "finding previous merge task")
+		}
+		if prevMerge == nil {
+			return nil, errors.New("no merge task found")
+		}
+		change.AddDependencyOn = prevMerge.Id
+	}
+
+	return change, nil
+}
+
+// applyMergeTaskDependencyChange makes the change to the merge task's
+// dependencies.
+func applyMergeTaskDependencyChange(change MergeTaskDependencyChange) error {
+	mergeTask, err := task.FindOneId(change.TaskID)
+	if err != nil {
+		return errors.Wrapf(err, "finding merge task '%s'", change.TaskID)
+	}
+	if mergeTask == nil {
+		return errors.Errorf("merge task '%s' not found", change.TaskID)
+	}
+
+	if change.RemoveDependencyOn != "" {
+		if err = mergeTask.RemoveDependency(change.RemoveDependencyOn); err != nil {
+			return errors.Wrap(err, "removing dependency")
+		}
+	}
+	if change.AddDependencyOn != "" {
+		d := task.Dependency{
+			TaskId: change.AddDependencyOn,
+			Status: AllStatuses,
+		}
+		if err = mergeTask.AddDependency(d); err != nil {
+			return errors.Wrap(err, "adding dependency")
+		}
+	}
+
+	return nil
+}
+
+// FindBrokenMergeTaskDependencies returns the changes needed to fix the chain
+// of merge task dependencies in the commit queue. Each processed item's merge
+// task should depend on the merge task of the item before it and on no other
+// merge task.
+func FindBrokenMergeTaskDependencies(cq commitqueue.CommitQueue) ([]MergeTaskDependencyChange, error) {
+	changes := []MergeTaskDependencyChange{}
+	prevMergeTask := ""
+	for _, item := range cq.Queue {
+		if item.Version == "" {
+			break
+		}
+		mergeTask, err := task.FindMergeTaskForVersion(item.Version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding merge task for version '%s'", item.Version)
+		}
+		if mergeTask == nil {
+			return nil, errors.Errorf("no merge task found for version '%s'", item.Version)
+		}
+
+		depIDs := make([]string, 0, len(mergeTask.DependsOn))
+		for _, d := range mergeTask.DependsOn {
+			depIDs = append(depIDs, d.TaskId)
+		}
+		mergeDeps := []task.Task{}
+		if len(depIDs) > 0 {
+			mergeDeps, err = task.FindWithFields(bson.M{
+				task.IdKey:               bson.M{"$in": depIDs},
+				task.CommitQueueMergeKey: true,
+			}, task.IdKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "finding merge task dependencies of '%s'", mergeTask.Id)
+			}
+		}
+
+		taskChanges := []MergeTaskDependencyChange{}
+		hasPrev := false
+		for _, d := range mergeDeps {
+			if d.Id == prevMergeTask {
+				hasPrev = true
+				continue
+			}
+			taskChanges = append(taskChanges, MergeTaskDependencyChange{
+				TaskID:             mergeTask.Id,
+				RemoveDependencyOn: d.Id,
+			})
+		}
+		if prevMergeTask != "" && !hasPrev {
+			if len(taskChanges) > 0 {
+				taskChanges[0].AddDependencyOn = prevMergeTask
+			} else {
+				taskChanges = append(taskChanges, MergeTaskDependencyChange{
+					TaskID:          mergeTask.Id,
+					AddDependencyOn: prevMergeTask,
+				})
+			}
+		}
+		changes = append(changes, taskChanges...)
+		prevMergeTask = mergeTask.Id
+	}
+
+	return changes, nil
+}
+
+// RepairMergeTaskDependencies fixes the chain of merge task dependencies in
+// the commit queue and returns the changes it made.
+func RepairMergeTaskDependencies(cq commitqueue.CommitQueue, caller string) ([]MergeTaskDependencyChange, error) {
+	changes, err := FindBrokenMergeTaskDependencies(cq)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding broken merge task dependencies")
+	}
+
+	repaired := []string{}
+	for _, change := range changes {
+		if err = applyMergeTaskDependencyChange(change); err != nil {
+			return nil, errors.Wrapf(err, "repairing dependencies of merge task '%s'", change.TaskID)
+		}
+		if !utility.StringSliceContains(repaired, change.TaskID) {
+			repaired = append(repaired, change.TaskID)
+		}
+	}
+	for _, taskID := range repaired {
+		mergeTask, err := task.FindOneId(taskID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding merge task '%s'", taskID)
+		}
+		if mergeTask == nil {
+			return nil, errors.Errorf("merge task '%s' not found", taskID)
+		}
+		if err = RecomputeNumDependents(*mergeTask); err != nil {
+			return nil, errors.Wrapf(err, "recomputing number of dependents of merge task '%s'", taskID)
+		}
+	}
+
+	grip.InfoWhen(len(changes) > 0, message.Fields{
+		"message":     "repaired commit queue merge task dependencies",
+		"project":     cq.ProjectID,
+		"num_changes": len(changes),
+		"tasks":       repaired,
+		"caller":      caller,
+	})
+
+	return changes, nil
+}
@@ -0,0 +1,187 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanDequeueAndRestartForTask(t *testing.T) {
+	require.NoError(t, db.ClearCollections(patch.Collection, task.Collection, commitqueue.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(patch.Collection, task.Collection, commitqueue.Collection))
+	}()
+
+	versions := []string{}
+	for i := 0; i < 3; i++ {
+		id := mgobson.NewObjectId()
+		versions = append(versions, id.Hex())
+		p := patch.Patch{
+			Id:      id,
+			Alias:   evergreen.CommitQueueAlias,
+			Version: id.Hex(),
+			GithubPatchData: thirdparty.GithubPatch{
+				PRNumber:       i + 1,
+				MergeCommitSHA: "abcdef",
+			},
+		}
+		require.NoError(t, p.Insert())
+	}
+	t1 := task.Task{Id: "t1", Version: versions[0], Project: "p", CommitQueueMerge: true}
+	require.NoError(t, t1.Insert())
+	t2 := task.Task{
+		Id:               "t2",
+		Version:          versions[1],
+		Project:          "p",
+		CommitQueueMerge: true,
+		DependsOn:        []task.Dependency{{TaskId: t1.Id, Status: AllStatuses}},
+	}
+	require.NoError(t, t2.Insert())
+	t3 := task.Task{
+		Id:               "t3",
+		Version:          versions[2],
+		Project:          "p",
+		CommitQueueMerge: true,
+		DependsOn:        []task.Dependency{{TaskId: t2.Id, Status: AllStatuses}},
+	}
+	require.NoError(t, t3.Insert())
+	cq := commitqueue.CommitQueue{
+		ProjectID: "p",
+		Queue: []commitqueue.CommitQueueItem{
+			{Issue: versions[0], Version: versions[0]},
+			{Issue: versions[1], Version: versions[1]},
+			{Issue: versions[2], Version: versions[2]},
+		},
+	}
+	require.NoError(t, commitqueue.InsertQueue(&cq))
+
+	plan, err := PlanDequeueAndRestartForTask(nil, &t2, message.GithubStateFailure, "new push to pull request")
+	require.NoError(t, err)
+	assert.Equal(t, versions[1], plan.Issue)
+	assert.Equal(t, []string{versions[2]}, plan.RestartVersions)
+	require.Len(t, plan.DependencyChanges, 1)
+	assert.Equal(t, MergeTaskDependencyChange{
+		TaskID:             t3.Id,
+		RemoveDependencyOn: t2.Id,
+		AddDependencyOn:    t1.Id,
+	}, plan.DependencyChanges[0])
+	require.Len(t, plan.GithubStatuses, 2)
+	assert.Equal(t, "merge test failed", plan.GithubStatuses[0].Description)
+	assert.Equal(t, "new push to pull request", plan.GithubStatuses[1].Description)
+	assert.Equal(t, 2, plan.GithubStatuses[1].PRNumber)
+	assert.Empty(t, plan.Warnings)
+
+	dbCq, err := commitqueue.FindOneId("p")
+	require.NoError(t, err)
+	assert.Len(t, dbCq.Queue, 3)
+	dbTask3, err := task.FindOneId(t3.Id)
+	require.NoError(t, err)
+	require.Len(t, dbTask3.DependsOn, 1)
+	assert.Equal(t, t2.Id, dbTask3.DependsOn[0].TaskId)
+
+	t.Run("FailsForTaskNotInQueue", func(t *testing.T) {
+		other := mgobson.NewObjectId()
+		p := patch.Patch{Id: other, Version: other.Hex()}
+		require.NoError(t, p.Insert())
+		_, err := PlanDequeueAndRestartForTask(&cq, &task.Task{Id: "other", Version: other.Hex(), Project: "p"}, message.GithubStateFailure, "")
+		assert.Error(t, err)
+	})
+	t.Run("FailsWithoutCurrentMergeTask", func(t *testing.T) {
+		noMerge := commitqueue.CommitQueue{
+			ProjectID: "p",
+			Queue: []commitqueue.CommitQueueItem{
+				{Issue: "no-merge", Version: "no-merge-version"},
+				{Issue: versions[2], Version: versions[2]},
+			},
+		}
+		_, err := planRemoveNextMergeTaskDependency(noMerge, "no-merge")
+		assert.Error(t, err)
+	})
+}
+
+func TestRepairMergeTaskDependencies(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection))
+	}()
+
+	stale := task.Task{Id: "stale", Version: "v0", Project: "p", CommitQueueMerge: true}
+	require.NoError(t, stale.Insert())
+	t1 := task.Task{
+		Id:               "t1",
+		Version:          "v1",
+		Project:          "p",
+		CommitQueueMerge: true,
+		DependsOn:        []task.Dependency{{TaskId: stale.Id, Status: AllStatuses}},
+	}
+	require.NoError(t, t1.Insert())
+	// t2 lost its dependency on t1 but still depends on a task in its own
+	// version, which isn't a merge task.
+	t2 := task.Task{
+		Id:               "t2",
+		Version:          "v2",
+		Project:          "p",
+		CommitQueueMerge: true,
+		DependsOn:        []task.Dependency{{TaskId: "compile", Status: evergreen.TaskSucceeded}},
+	}
+	require.NoError(t, t2.Insert())
+	compile := task.Task{Id: "compile", Version: "v2", Project: "p"}
+	require.NoError(t, compile.Insert())
+	// t3 depends on t1 instead of t2.
+	t3 := task.Task{
+		Id:               "t3",
+		Version:          "v3",
+		Project:          "p",
+		CommitQueueMerge: true,
+		DependsOn:        []task.Dependency{{TaskId: t1.Id, Status: AllStatuses}},
+	}
+	require.NoError(t, t3.Insert())
+	cq := commitqueue.CommitQueue{
+		ProjectID: "p",
+		Queue: []commitqueue.CommitQueueItem{
+			{Issue: "v1", Version: "v1"},
+			{Issue: "v2", Version: "v2"},
+			{Issue: "v3", Version: "v3"},
+			{Issue: "v4"},
+		},
+	}
+
+	expected := []MergeTaskDependencyChange{
+		{TaskID: t1.Id, RemoveDependencyOn: stale.Id},
+		{TaskID: t2.Id, AddDependencyOn: t1.Id},
+		{TaskID: t3.Id, RemoveDependencyOn: t1.Id, AddDependencyOn: t2.Id},
+	}
+	changes, err := FindBrokenMergeTaskDependencies(cq)
+	require.NoError(t, err)
+	assert.Equal(t, expected, changes)
+
+	changes, err = RepairMergeTaskDependencies(cq, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, expected, changes)
+
+	dbTask1, err := task.FindOneId(t1.Id)
+	require.NoError(t, err)
+	assert.Empty(t, dbTask1.DependsOn)
+	dbTask2, err := task.FindOneId(t2.Id)
+	require.NoError(t, err)
+	require.Len(t, dbTask2.DependsOn, 2)
+	assert.Equal(t, "compile", dbTask2.DependsOn[0].TaskId)
+	assert.Equal(t, t1.Id, dbTask2.DependsOn[1].TaskId)
+	dbTask3, err := task.FindOneId(t3.Id)
+	require.NoError(t, err)
+	require.Len(t, dbTask3.DependsOn, 1)
+	assert.Equal(t, t2.Id, dbTask3.DependsOn[0].TaskId)
+
+	changes, err = FindBrokenMergeTaskDependencies(cq)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -63,6 +63,18 @@ func (q *ContainerTaskQueue) populate() error {
 	if err != nil {
 		return errors.Wrap(err, "filtering candidate container tasks for allocation by project ref settings")
 	}
+	// Tasks held by external gates that have not been opened yet are not
+	// allocated a container until their gates open.
+	readyForAllocation, err = FilterExternallyGatedTasks(readyForAllocation, startAt)
+	if err != nil {
+		return errors.Wrap(err, "filtering externally gated container tasks")
+	}
+	// Quarantined tasks are skipped rather than allocated a container.
+	readyForAllocation, err = SkipQuarantinedTasks(readyForAllocation)
+	grip.Error(message.WrapError(err, message.Fields{
+		"message": "could not skip quarantined container tasks",
+		"context": "container task queue",
+	}))
 
 	grip.Info(message.Fields{
 		"message":    "generated container task queue",
@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/utility"
 	"github.com/stretchr/testify/assert"
@@ -14,7 +15,7 @@ import (
 
 func TestContainerTaskQueue(t *testing.T) {
 	defer func() {
-		assert.NoError(t, db.ClearCollections(task.Collection, ProjectRefCollection))
+		assert.NoError(t, db.ClearCollections(task.Collection, ProjectRefCollection, TaskQuarantineCollection, build.Collection, VersionCollection))
 	}()
 	getTaskThatNeedsContainerAllocation := func() task.Task {
 		return task.Task{
@@ -205,9 +206,42 @@ func TestContainerTaskQueue(t *testing.T) {
 
 			checkEmpty(t, ctq)
 		},
+		"SkipsQuarantinedTask": func(t *testing.T) {
+			ref := getProjectRef()
+			require.NoError(t, ref.Insert())
+			_, err := QuarantineTask(TaskQuarantine{
+				ProjectId:    ref.Id,
+				BuildVariant: "bv",
+				TaskName:     "flaky",
+				Reason:       "fails every other run",
+				Expires:      time.Now().Add(time.Hour),
+			})
+			require.NoError(t, err)
+			v := &Version{Id: "v", BuildIds: []string{"b"}}
+			require.NoError(t, v.Insert())
+			b := &build.Build{Id: "b", Version: v.Id, Status: evergreen.BuildCreated}
+			require.NoError(t, b.Insert())
+
+			quarantined := getTaskThatNeedsContainerAllocation()
+			quarantined.Project = ref.Id
+			quarantined.BuildVariant = "bv"
+			quarantined.DisplayName = "flaky"
+			quarantined.BuildId = b.Id
+			quarantined.Version = v.Id
+			require.NoError(t, quarantined.Insert())
+
+			ctq, err := NewContainerTaskQueue()
+			require.NoError(t, err)
+
+			checkEmpty(t, ctq)
+			dbTask, err := task.FindOneId(quarantined.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbTask)
+			assert.Equal(t, evergreen.TaskSkipped, dbTask.Status)
+		},
 	} {
 		t.Run(tName, func(t *testing.T) {
-			require.NoError(t, db.ClearCollections(task.Collection, ProjectRefCollection))
+			require.NoError(t, db.ClearCollections(task.Collection, ProjectRefCollection, TaskQuarantineCollection, build.Collection, VersionCollection))
 			tCase(t)
 		})
 	}
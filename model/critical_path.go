@@ -0,0 +1,165 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// CriticalPathTask is a task on a critical path along with the time it spent
+// blocked, queued, and running.
+type CriticalPathTask struct {
+	TaskID       string
+	DisplayName  string
+	BuildVariant string
+	BuildID      string
+	Status       string
+	StartTime    time.Time
+	FinishTime   time.Time
+	Timing       task.TimingBreakdown
+}
+
+// CriticalPath is the chain of dependent tasks that determined how long a
+// build or version took to finish, ordered from the first task to run to the
+// last task to finish. Shortening the queue or run time of any task on the
+// path shortens the build or version.
+type CriticalPath struct {
+	Tasks []CriticalPathTask
+	// Duration is the time from when the first task on the path was
+	// scheduled until the last task on the path finished.
+	Duration time.Duration
+	// QueueTime is the time that tasks on the path spent waiting to start
+	// once they were ready to run.
+	QueueTime time.Duration
+	// RunTime is the time that tasks on the path spent running.
+	RunTime time.Duration
+	// ActualMakespan is the time from when the first task started until the
+	// last task finished.
+	ActualMakespan time.Duration
+	// PredictedMakespan is how long the tasks would have taken if every task
+	// started as soon as its dependencies finished.
+	PredictedMakespan time.Duration
+	// Complete is whether every activated task has finished. If not, the
+	// critical path only covers the tasks that have finished so far.
+	Complete bool
+}
+
+var criticalPathTaskFields = []string{
+	task.IdKey,
+	task.DisplayNameKey,
+	task.BuildVariantKey,
+	task.BuildIdKey,
+	task.StatusKey,
+	task.ActivatedKey,
+	task.DisplayOnlyKey,
+	task.DependsOnKey,
+	task.ScheduledTimeKey,
+	task.ActivatedTimeKey,
+	task.DependenciesMetTimeKey,
+	task.StartTimeKey,
+	task.FinishTimeKey,
+	task.TimeTakenKey,
+}
+
+// GetVersionCriticalPath returns the critical path across all of the
+// version's builds, including dependencies between builds.
+func GetVersionCriticalPath(versionID string) (*CriticalPath, error) {
+	tasks, err := task.FindWithFields(task.ByVersion(versionID), criticalPathTaskFields...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding tasks for version '%s'", versionID)
+	}
+	return FindCriticalPath(tasks), nil
+}
+
+// GetBuildCriticalPath returns the critical path within the build. Tasks
+// outside of the build that the build's tasks depend on are not included.
+func GetBuildCriticalPath(buildID string) (*CriticalPath, error) {
+	tasks, err := task.FindWithFields(task.ByBuildId(buildID), criticalPathTaskFields...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding tasks for build '%s'", buildID)
+	}
+	return FindCriticalPath(tasks), nil
+}
+
+// FindCriticalPath finds the critical path through the given tasks. The path
+// ends at the task that finished last and follows, at each step, the
+// dependency that finished last, since that is the dependency the task was
+// waiting on. Only finished tasks are considered.
+func FindCriticalPath(tasks []task.Task) *CriticalPath {
+	cp := &CriticalPath{
+		Tasks:    []CriticalPathTask{},
+		Complete: true,
+	}
+	finishedTasks := make(map[string]task.Task, len(tasks))
+	finishedTaskList := make([]task.Task, 0, len(tasks))
+	var last *task.Task
+	for i := range tasks {
+		t := tasks[i]
+		if t.DisplayOnly {
+			continue
+		}
+		if !t.IsFinished() || utility.IsZeroTime(t.StartTime) || utility.IsZeroTime(t.FinishTime) {
+			if t.Activated {
+				cp.Complete = false
+			}
+			continue
+		}
+		finishedTasks[t.Id] = t
+		finishedTaskList = append(finishedTaskList, t)
+		if last == nil || t.FinishTime.After(last.FinishTime) {
+			last = &tasks[i]
+		}
+	}
+	if last == nil {
+		return cp
+	}
+	cp.ActualMakespan = CalculateActualMakespan(finishedTaskList)
+	cp.PredictedMakespan = FindPredictedMakespan(finishedTaskList).TotalTime
+
+	path := []task.Task{*last}
+	onPath := map[string]bool{last.Id: true}
+	for cur := *last; ; {
+		var gate task.Task
+		for _, dep := range cur.DependsOn {
+			depTask, ok := finishedTasks[dep.TaskId]
+			if !ok {
+				continue
+			}
+			if gate.Id == "" || depTask.FinishTime.After(gate.FinishTime) {
+				gate = depTask
+			}
+		}
+		if gate.Id == "" || onPath[gate.Id] {
+			break
+		}
+		onPath[gate.Id] = true
+		path = append(path, gate)
+		cur = gate
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		t := path[i]
+		timing := t.GetTimingBreakdown()
+		cp.Tasks = append(cp.Tasks, CriticalPathTask{
+			TaskID:       t.Id,
+			DisplayName:  t.DisplayName,
+			BuildVariant: t.BuildVariant,
+			BuildID:      t.BuildId,
+			Status:       t.Status,
+			StartTime:    t.StartTime,
+			FinishTime:   t.FinishTime,
+			Timing:       timing,
+		})
+		cp.QueueTime += timing.QueueTime
+		cp.RunTime += timing.RunTime
+	}
+
+	first := path[len(path)-1]
+	firstTiming := first.GetTimingBreakdown()
+	readyTime := first.StartTime.Add(-firstTiming.QueueTime - firstTiming.BlockedTime)
+	cp.Duration = last.FinishTime.Sub(readyTime)
+
+	return cp
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCriticalPath(t *testing.T) {
+	scheduled := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return scheduled.Add(time.Duration(minutes) * time.Minute)
+	}
+	// compile runs for 10 minutes, then test and lint run in parallel. The
+	// test task waits 5 minutes in the queue before running for 20 minutes,
+	// so it determines how long the version takes.
+	compile := task.Task{
+		Id:            "compile",
+		BuildVariant:  "ubuntu",
+		Status:        evergreen.TaskSucceeded,
+		Activated:     true,
+		ScheduledTime: scheduled,
+		StartTime:     at(2),
+		FinishTime:    at(12),
+		TimeTaken:     10 * time.Minute,
+	}
+	test := task.Task{
+		Id:                  "test",
+		BuildVariant:        "ubuntu",
+		Status:              evergreen.TaskFailed,
+		Activated:           true,
+		DependsOn:           []task.Dependency{{TaskId: "compile"}},
+		ScheduledTime:       scheduled,
+		DependenciesMetTime: at(12),
+		StartTime:           at(17),
+		FinishTime:          at(37),
+		TimeTaken:           20 * time.Minute,
+	}
+	lint := task.Task{
+		Id:                  "lint",
+		BuildVariant:        "windows",
+		Status:              evergreen.TaskSucceeded,
+		Activated:           true,
+		DependsOn:           []task.Dependency{{TaskId: "compile"}},
+		ScheduledTime:       scheduled,
+		DependenciesMetTime: at(12),
+		StartTime:           at(12),
+		FinishTime:          at(22),
+		TimeTaken:           10 * time.Minute,
+	}
+
+	t.Run("FollowsLatestDependencies", func(t *testing.T) {
+		cp := FindCriticalPath([]task.Task{lint, test, compile})
+		require.Len(t, cp.Tasks, 2)
+		assert.Equal(t, "compile", cp.Tasks[0].TaskID)
+		assert.Equal(t, "test", cp.Tasks[1].TaskID)
+		assert.Equal(t, 5*time.Minute, cp.Tasks[1].Timing.QueueTime)
+		assert.Equal(t, 7*time.Minute, cp.QueueTime)
+		assert.Equal(t, 30*time.Minute, cp.RunTime)
+		assert.Equal(t, 37*time.Minute, cp.Duration)
+		assert.Equal(t, 35*time.Minute, cp.ActualMakespan)
+		assert.Equal(t, 30*time.Minute, cp.PredictedMakespan)
+		assert.True(t, cp.Complete)
+	})
+	t.Run("IgnoresUnfinishedTasks", func(t *testing.T) {
+		unfinishedTest := test
+		unfinishedTest.Status = evergreen.TaskStarted
+		unfinishedTest.FinishTime = time.Time{}
+		cp := FindCriticalPath([]task.Task{lint, unfinishedTest, compile})
+		require.Len(t, cp.Tasks, 2)
+		assert.Equal(t, "compile", cp.Tasks[0].TaskID)
+		assert.Equal(t, "lint", cp.Tasks[1].TaskID)
+		assert.False(t, cp.Complete)
+	})
+	t.Run("NoFinishedTasks", func(t *testing.T) {
+		cp := FindCriticalPath([]task.Task{{Id: "t", Status: evergreen.TaskUndispatched, Activated: true}})
+		assert.Empty(t, cp.Tasks)
+		assert.False(t, cp.Complete)
+	})
+}
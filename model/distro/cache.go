@@ -0,0 +1,124 @@
+package distro
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// cacheTTL is how long the cached distros are used before they're fetched
+// again. Distros rarely change and writes through this package invalidate the
+// cache immediately, so this only bounds how stale the cache can be after a
+// distro is modified by some other process.
+const cacheTTL = time.Minute
+
+var allDistrosCache = newDistroCache(cacheTTL)
+
+// CacheStats are the lookup statistics for the in-process distro cache.
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// HitRate returns the fraction of lookups that were served from the cache.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+type distroCache struct {
+	mu          sync.RWMutex
+	ttl         time.Duration
+	distros     []Distro
+	lastUpdated time.Time
+	hits        int64
+	misses      int64
+}
+
+func newDistroCache(ttl time.Duration) *distroCache {
+	return &distroCache{ttl: ttl}
+}
+
+// FindAllCached returns all distros sorted by ID from an in-process cache that
+// is shared by all callers, such as validation requests, which look up the
+// distros much more often than they change. The returned distros must not be
+// modified.
+func FindAllCached() ([]Distro, error) {
+	return allDistrosCache.get()
+}
+
+// InvalidateCache clears the in-process distro cache so that the next lookup
+// fetches the distros from the database.
+func InvalidateCache() {
+	allDistrosCache.invalidate()
+}
+
+// GetCacheStats returns the lookup statistics for the in-process distro cache.
+func GetCacheStats() CacheStats {
+	return allDistrosCache.getStats()
+}
+
+func (c *distroCache) get() ([]Distro, error) {
+	c.mu.RLock()
+	if !c.isExpired() {
+		defer c.mu.RUnlock()
+		atomic.AddInt64(&c.hits, 1)
+		return c.distros, nil
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another lookup may have refreshed the cache while waiting for the lock.
+	if !c.isExpired() {
+		atomic.AddInt64(&c.hits, 1)
+		return c.distros, nil
+	}
+
+	distros, err := Find(All)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding all distros")
+	}
+	atomic.AddInt64(&c.misses, 1)
+	c.distros = distros
+	c.lastUpdated = time.Now()
+
+	stats := c.getStats()
+	grip.Debug(message.Fields{
+		"message":     "refreshed distro cache",
+		"num_distros": len(distros),
+		"hits":        stats.Hits,
+		"misses":      stats.Misses,
+		"hit_rate":    stats.HitRate(),
+	})
+
+	return distros, nil
+}
+
+// isExpired returns whether the cached distros must be fetched again. Callers
+// must hold the lock.
+func (c *distroCache) isExpired() bool {
+	return c.lastUpdated.IsZero() || time.Since(c.lastUpdated) > c.ttl
+}
+
+func (c *distroCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.distros = nil
+	c.lastUpdated = time.Time{}
+}
+
+func (c *distroCache) getStats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+	}
+}
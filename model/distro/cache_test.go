@@ -0,0 +1,78 @@
+package distro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistroCache(t *testing.T) {
+	require.NoError(t, db.Clear(Collection))
+	defer func() {
+		assert.NoError(t, db.Clear(Collection))
+		InvalidateCache()
+	}()
+
+	t.Run("ServesRepeatedLookupsFromCache", func(t *testing.T) {
+		c := newDistroCache(time.Hour)
+		require.NoError(t, db.Insert(Collection, &Distro{Id: "d0"}))
+
+		distros, err := c.get()
+		require.NoError(t, err)
+		require.Len(t, distros, 1)
+
+		// Writes that bypass this package are not seen until the cache
+		// expires or is invalidated.
+		require.NoError(t, db.Insert(Collection, &Distro{Id: "d1"}))
+		distros, err = c.get()
+		require.NoError(t, err)
+		assert.Len(t, distros, 1)
+		assert.Equal(t, CacheStats{Hits: 1, Misses: 1}, c.getStats())
+		assert.Equal(t, 0.5, c.getStats().HitRate())
+
+		c.invalidate()
+		distros, err = c.get()
+		require.NoError(t, err)
+		require.Len(t, distros, 2)
+		assert.Equal(t, "d0", distros[0].Id)
+		assert.Equal(t, "d1", distros[1].Id)
+		assert.Equal(t, CacheStats{Hits: 1, Misses: 2}, c.getStats())
+	})
+	t.Run("RefreshesExpiredCache", func(t *testing.T) {
+		require.NoError(t, db.Clear(Collection))
+		c := newDistroCache(time.Nanosecond)
+		_, err := c.get()
+		require.NoError(t, err)
+
+		require.NoError(t, db.Insert(Collection, &Distro{Id: "d0"}))
+		time.Sleep(time.Millisecond)
+		distros, err := c.get()
+		require.NoError(t, err)
+		assert.Len(t, distros, 1)
+		assert.Equal(t, CacheStats{Misses: 2}, c.getStats())
+	})
+	t.Run("WritesInvalidateSharedCache", func(t *testing.T) {
+		require.NoError(t, db.Clear(Collection))
+		InvalidateCache()
+		distros, err := FindAllCached()
+		require.NoError(t, err)
+		assert.Empty(t, distros)
+
+		d := Distro{Id: "d0"}
+		require.NoError(t, d.Insert())
+		distros, err = FindAllCached()
+		require.NoError(t, err)
+		assert.Len(t, distros, 1)
+
+		require.NoError(t, Remove(d.Id))
+		distros, err = FindAllCached()
+		require.NoError(t, err)
+		assert.Empty(t, distros)
+	})
+	t.Run("HitRateWithoutLookups", func(t *testing.T) {
+		assert.Zero(t, CacheStats{}.HitRate())
+	})
+}
@@ -37,6 +37,7 @@ var (
 	IsVirtualWorkstationKey  = bsonutil.MustHaveTag(Distro{}, "IsVirtualWorkstation")
 	IsClusterKey             = bsonutil.MustHaveTag(Distro{}, "IsCluster")
 	IcecreamSettingsKey      = bsonutil.MustHaveTag(Distro{}, "IcecreamSettings")
+	StuckTaskThresholdKey    = bsonutil.MustHaveTag(Distro{}, "StuckTaskThreshold")
 )
 
 var (
@@ -105,16 +106,19 @@ func FindAll() ([]Distro, error) {
 
 // Insert writes the distro to the database.
 func (d *Distro) Insert() error {
+	defer InvalidateCache()
 	return db.Insert(Collection, d)
 }
 
 // Update updates one distro.
 func (d *Distro) Update() error {
+	defer InvalidateCache()
 	return db.UpdateId(Collection, d.Id, d)
 }
 
 // Remove removes one distro.
 func Remove(id string) error {
+	defer InvalidateCache()
 	return db.Remove(Collection, bson.M{IdKey: id})
 }
 
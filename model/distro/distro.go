@@ -51,6 +51,29 @@ type Distro struct {
 	IsCluster             bool                  `bson:"is_cluster" json:"is_cluster" mapstructure:"is_cluster"`
 	HomeVolumeSettings    HomeVolumeSettings    `bson:"home_volume_settings" json:"home_volume_settings" mapstructure:"home_volume_settings"`
 	IcecreamSettings      IcecreamSettings      `bson:"icecream_settings,omitempty" json:"icecream_settings,omitempty" mapstructure:"icecream_settings,omitempty"`
+	// StuckTaskThreshold is how long a task running on the distro can go
+	// without a heartbeat before it's considered stuck. If it's not set,
+	// DefaultStuckTaskThreshold is used.
+	StuckTaskThreshold time.Duration `bson:"stuck_task_threshold,omitempty" json:"stuck_task_threshold,omitempty" mapstructure:"stuck_task_threshold,omitempty"`
+}
+
+const (
+	// DefaultStuckTaskThreshold is how long a task can go without a heartbeat
+	// before it's considered stuck if its distro doesn't set a threshold.
+	DefaultStuckTaskThreshold = 7 * time.Minute
+	// MinStuckTaskThreshold is the shortest stuck task threshold that a
+	// distro can set. Agents heartbeat much more often than this, so a
+	// healthy task can't be mistaken for a stuck one.
+	MinStuckTaskThreshold = 3 * time.Minute
+)
+
+// GetStuckTaskThreshold returns how long a task running on the distro can go
+// without a heartbeat before it's considered stuck.
+func (d *Distro) GetStuckTaskThreshold() time.Duration {
+	if d.StuckTaskThreshold <= 0 {
+		return DefaultStuckTaskThreshold
+	}
+	return d.StuckTaskThreshold
 }
 
 type DistroData struct {
@@ -0,0 +1,263 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const DistroDrainCollection = "distro_drains"
+
+const (
+	// DistroDrainModeWait lets tasks that are already running on the
+	// distro finish.
+	DistroDrainModeWait = "wait"
+	// DistroDrainModeReschedule resets tasks that are already running on
+	// the distro so that they run again on the target distro.
+	DistroDrainModeReschedule = "reschedule"
+)
+
+// DistroDrain is an in-progress or completed operation to move work off of a
+// distro. While a distro is draining, it is disabled so that no new tasks are
+// dispatched to it.
+type DistroDrain struct {
+	DistroID string `bson:"_id" json:"distro_id"`
+	// TargetDistroID is the distro that tasks are moved to. If it is not
+	// set, tasks that have not been dispatched remain on the distro.
+	TargetDistroID string    `bson:"target_distro_id,omitempty" json:"target_distro_id,omitempty"`
+	Mode           string    `bson:"mode" json:"mode"`
+	StartedBy      string    `bson:"started_by" json:"started_by"`
+	StartedAt      time.Time `bson:"started_at" json:"started_at"`
+	FinishedAt     time.Time `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+	// NumMigrated is the number of tasks that had not been dispatched and
+	// were moved to the target distro.
+	NumMigrated int `bson:"num_migrated" json:"num_migrated"`
+	// NumRescheduled is the number of running tasks that were reset to run
+	// on the target distro.
+	NumRescheduled int `bson:"num_rescheduled" json:"num_rescheduled"`
+}
+
+var (
+	distroDrainIdKey             = bsonutil.MustHaveTag(DistroDrain{}, "DistroID")
+	distroDrainFinishedAtKey     = bsonutil.MustHaveTag(DistroDrain{}, "FinishedAt")
+	distroDrainNumMigratedKey    = bsonutil.MustHaveTag(DistroDrain{}, "NumMigrated")
+	distroDrainNumRescheduledKey = bsonutil.MustHaveTag(DistroDrain{}, "NumRescheduled")
+)
+
+// DistroDrainProgress reports how much work is left before a distro is
+// drained.
+type DistroDrainProgress struct {
+	Drain DistroDrain
+	// NumRunning is the number of tasks still running on the distro's hosts.
+	NumRunning int
+	// NumUndispatched is the number of tasks still waiting to run on the
+	// distro.
+	NumUndispatched int
+}
+
+// IsFinished returns whether the distro no longer has any tasks running or
+// waiting to run on it. A drain without a target distro leaves the tasks
+// waiting to run on the distro, so it is finished once no tasks are running.
+func (p *DistroDrainProgress) IsFinished() bool {
+	if p.Drain.TargetDistroID == "" {
+		return p.NumRunning == 0
+	}
+	return p.NumRunning == 0 && p.NumUndispatched == 0
+}
+
+// FindDistroDrain returns the drain for the distro, if there is one.
+func FindDistroDrain(distroID string) (*DistroDrain, error) {
+	drain := &DistroDrain{}
+	err := db.FindOneQ(DistroDrainCollection, db.Query(bson.M{distroDrainIdKey: distroID}), drain)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding drain for distro '%s'", distroID)
+	}
+	return drain, nil
+}
+
+// FindUnfinishedDistroDrains returns all drains that still have tasks to move.
+func FindUnfinishedDistroDrains() ([]DistroDrain, error) {
+	drains := []DistroDrain{}
+	err := db.FindAllQ(DistroDrainCollection, db.Query(bson.M{distroDrainFinishedAtKey: bson.M{"$exists": false}}), &drains)
+	return drains, errors.Wrap(err, "finding unfinished distro drains")
+}
+
+// StartDistroDrain disables the distro and records that it is being drained.
+// Starting a drain for a distro that is already draining replaces the
+// previous drain.
+func StartDistroDrain(distroID, targetDistroID, mode, user string) (*DistroDrain, error) {
+	if !utility.StringSliceContains([]string{DistroDrainModeWait, DistroDrainModeReschedule}, mode) {
+		return nil, errors.Errorf("invalid drain mode '%s'", mode)
+	}
+	if mode == DistroDrainModeReschedule && targetDistroID == "" {
+		return nil, errors.New("must specify a target distro to reschedule running tasks")
+	}
+	if targetDistroID == distroID {
+		return nil, errors.New("cannot migrate tasks to the distro being drained")
+	}
+
+	d, err := distro.FindOneId(distroID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding distro '%s'", distroID)
+	}
+	if d == nil {
+		return nil, errors.Errorf("distro '%s' not found", distroID)
+	}
+	if targetDistroID != "" {
+		target, err := distro.FindOneId(targetDistroID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding target distro '%s'", targetDistroID)
+		}
+		if target == nil {
+			return nil, errors.Errorf("target distro '%s' not found", targetDistroID)
+		}
+		if target.Disabled {
+			return nil, errors.Errorf("target distro '%s' is disabled", targetDistroID)
+		}
+	}
+
+	drain := &DistroDrain{
+		DistroID:       distroID,
+		TargetDistroID: targetDistroID,
+		Mode:           mode,
+		StartedBy:      user,
+		StartedAt:      time.Now(),
+	}
+	if _, err = db.Upsert(DistroDrainCollection, bson.M{distroDrainIdKey: distroID}, drain); err != nil {
+		return nil, errors.Wrap(err, "saving distro drain")
+	}
+	if err = db.Update(distro.Collection, bson.M{distro.IdKey: distroID}, bson.M{"$set": bson.M{distro.DisabledKey: true}}); err != nil {
+		return nil, errors.Wrapf(err, "disabling distro '%s'", distroID)
+	}
+	distro.InvalidateCache()
+
+	return drain, nil
+}
+
+// Process moves the tasks off of the draining distro according to the drain
+// mode and returns the remaining progress. Once there are no tasks left, the
+// drain is marked finished. The drain's counters are incremented atomically,
+// so concurrent runs don't count the same task twice.
+func (dd *DistroDrain) Process() (*DistroDrainProgress, error) {
+	catcher := grip.NewBasicCatcher()
+	numMigrated := 0
+	numRescheduled := 0
+	if dd.TargetDistroID != "" {
+		info, err := task.UpdateAll(bson.M{
+			task.DistroIdKey: dd.DistroID,
+			task.StatusKey:   evergreen.TaskUndispatched,
+			"$and":           []bson.M{task.ByExecutionPlatform(task.ExecutionPlatformHost)},
+		}, bson.M{"$set": bson.M{task.DistroIdKey: dd.TargetDistroID}})
+		if err != nil {
+			catcher.Wrap(err, "moving undispatched tasks to the target distro")
+		} else if info != nil {
+			numMigrated = info.Updated
+		}
+	}
+
+	if dd.Mode == DistroDrainModeReschedule {
+		hosts, err := host.Find(db.Query(dd.runningHostsQuery()))
+		if err != nil {
+			catcher.Wrap(err, "finding hosts running tasks")
+		}
+		for i := range hosts {
+			h := &hosts[i]
+			taskID := h.RunningTask
+			if err := task.UpdateOne(bson.M{task.IdKey: taskID}, bson.M{"$set": bson.M{task.DistroIdKey: dd.TargetDistroID}}); err != nil {
+				catcher.Wrapf(err, "moving task '%s' to the target distro", taskID)
+				continue
+			}
+			if err := clearAndResetHostTask(h, evergreen.TaskDescriptionMigrated); err != nil {
+				catcher.Wrapf(err, "rescheduling task '%s' from host '%s'", taskID, h.Id)
+				continue
+			}
+			numRescheduled++
+			grip.Info(message.Fields{
+				"message":       "rescheduled running task onto target distro",
+				"task":          taskID,
+				"host":          h.Id,
+				"distro":        dd.DistroID,
+				"target_distro": dd.TargetDistroID,
+			})
+		}
+	}
+
+	if numMigrated > 0 || numRescheduled > 0 {
+		err := db.Update(DistroDrainCollection, bson.M{distroDrainIdKey: dd.DistroID}, bson.M{"$inc": bson.M{
+			distroDrainNumMigratedKey:    numMigrated,
+			distroDrainNumRescheduledKey: numRescheduled,
+		}})
+		catcher.Wrap(err, "updating distro drain counts")
+	}
+
+	progress, err := dd.Progress()
+	if err != nil {
+		catcher.Add(err)
+		return nil, catcher.Resolve()
+	}
+	if progress.IsFinished() && utility.IsZeroTime(dd.FinishedAt) {
+		err = db.Update(DistroDrainCollection, bson.M{
+			distroDrainIdKey:         dd.DistroID,
+			distroDrainFinishedAtKey: bson.M{"$exists": false},
+		}, bson.M{"$set": bson.M{distroDrainFinishedAtKey: time.Now()}})
+		if !adb.ResultsNotFound(err) {
+			catcher.Wrap(err, "marking distro drain finished")
+		}
+	}
+
+	updated, err := FindDistroDrain(dd.DistroID)
+	if err != nil {
+		catcher.Add(err)
+		return nil, catcher.Resolve()
+	}
+	if updated != nil {
+		*dd = *updated
+	}
+	progress.Drain = *dd
+
+	return progress, catcher.Resolve()
+}
+
+// Progress returns how many tasks are left on the draining distro.
+func (dd *DistroDrain) Progress() (*DistroDrainProgress, error) {
+	numRunning, err := host.Count(db.Query(dd.runningHostsQuery()))
+	if err != nil {
+		return nil, errors.Wrap(err, "counting hosts running tasks")
+	}
+	numUndispatched, err := task.Count(db.Query(bson.M{
+		task.DistroIdKey:  dd.DistroID,
+		task.StatusKey:    evergreen.TaskUndispatched,
+		task.ActivatedKey: true,
+		"$and":            []bson.M{task.ByExecutionPlatform(task.ExecutionPlatformHost)},
+	}))
+	if err != nil {
+		return nil, errors.Wrap(err, "counting undispatched tasks")
+	}
+
+	return &DistroDrainProgress{
+		Drain:           *dd,
+		NumRunning:      numRunning,
+		NumUndispatched: numUndispatched,
+	}, nil
+}
+
+func (dd *DistroDrain) runningHostsQuery() bson.M {
+	return bson.M{
+		bsonutil.GetDottedKeyName(host.DistroKey, distro.IdKey): dd.DistroID,
+		host.RunningTaskKey: bson.M{"$exists": true},
+	}
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistroDrain(t *testing.T) {
+	for tName, tCase := range map[string]func(t *testing.T){
+		"StartFailsWithInvalidMode": func(t *testing.T) {
+			_, err := StartDistroDrain("d1", "d2", "bogus", "me")
+			assert.Error(t, err)
+		},
+		"StartFailsToRescheduleWithoutTarget": func(t *testing.T) {
+			_, err := StartDistroDrain("d1", "", DistroDrainModeReschedule, "me")
+			assert.Error(t, err)
+		},
+		"StartFailsWithNonexistentTarget": func(t *testing.T) {
+			_, err := StartDistroDrain("d1", "nonexistent", DistroDrainModeWait, "me")
+			assert.Error(t, err)
+		},
+		"StartDisablesDistro": func(t *testing.T) {
+			drain, err := StartDistroDrain("d1", "d2", DistroDrainModeWait, "me")
+			require.NoError(t, err)
+			assert.Equal(t, "me", drain.StartedBy)
+
+			d, err := distro.FindOneId("d1")
+			require.NoError(t, err)
+			require.NotZero(t, d)
+			assert.True(t, d.Disabled)
+
+			dbDrain, err := FindDistroDrain("d1")
+			require.NoError(t, err)
+			require.NotZero(t, dbDrain)
+			assert.Equal(t, "d2", dbDrain.TargetDistroID)
+		},
+		"ProcessMovesUndispatchedTasksAndWaitsForRunningTasks": func(t *testing.T) {
+			undispatched := task.Task{Id: "t1", DistroId: "d1", Status: evergreen.TaskUndispatched, Activated: true}
+			require.NoError(t, undispatched.Insert())
+			running := task.Task{Id: "t2", DistroId: "d1", Status: evergreen.TaskStarted, Activated: true}
+			require.NoError(t, running.Insert())
+			h := host.Host{Id: "h1", Distro: distro.Distro{Id: "d1"}, Status: evergreen.HostRunning, RunningTask: running.Id}
+			require.NoError(t, h.Insert())
+
+			drain, err := StartDistroDrain("d1", "d2", DistroDrainModeWait, "me")
+			require.NoError(t, err)
+			progress, err := drain.Process()
+			require.NoError(t, err)
+			assert.Equal(t, 1, progress.Drain.NumMigrated)
+			assert.Zero(t, progress.Drain.NumRescheduled)
+			assert.Equal(t, 1, progress.NumRunning)
+			assert.Zero(t, progress.NumUndispatched)
+			assert.False(t, progress.IsFinished())
+
+			dbTask, err := task.FindOneId(undispatched.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbTask)
+			assert.Equal(t, "d2", dbTask.DistroId)
+
+			dbTask, err = task.FindOneId(running.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbTask)
+			assert.Equal(t, "d1", dbTask.DistroId)
+
+			unfinished, err := FindUnfinishedDistroDrains()
+			require.NoError(t, err)
+			assert.Len(t, unfinished, 1)
+		},
+		"ProcessFinishesUntargetedDrainOnceNoTasksAreRunning": func(t *testing.T) {
+			undispatched := task.Task{Id: "t1", DistroId: "d1", Status: evergreen.TaskUndispatched, Activated: true}
+			require.NoError(t, undispatched.Insert())
+
+			drain, err := StartDistroDrain("d1", "", DistroDrainModeWait, "me")
+			require.NoError(t, err)
+			progress, err := drain.Process()
+			require.NoError(t, err)
+			assert.Equal(t, 1, progress.NumUndispatched)
+			assert.True(t, progress.IsFinished())
+			assert.False(t, progress.Drain.FinishedAt.IsZero())
+		},
+		"ProcessIncrementsCountsAcrossRuns": func(t *testing.T) {
+			drain, err := StartDistroDrain("d1", "d2", DistroDrainModeWait, "me")
+			require.NoError(t, err)
+			stale := *drain
+			for _, id := range []string{"t1", "t2"} {
+				undispatched := task.Task{Id: id, DistroId: "d1", Status: evergreen.TaskUndispatched, Activated: true}
+				require.NoError(t, undispatched.Insert())
+				_, err = drain.Process()
+				require.NoError(t, err)
+			}
+			undispatched := task.Task{Id: "t3", DistroId: "d1", Status: evergreen.TaskUndispatched, Activated: true}
+			require.NoError(t, undispatched.Insert())
+			progress, err := stale.Process()
+			require.NoError(t, err)
+			assert.Equal(t, 3, progress.Drain.NumMigrated)
+
+			dbDrain, err := FindDistroDrain("d1")
+			require.NoError(t, err)
+			require.NotZero(t, dbDrain)
+			assert.Equal(t, 3, dbDrain.NumMigrated)
+		},
+		"ProcessFinishesDrainWithNoTasksLeft": func(t *testing.T) {
+			drain, err := StartDistroDrain("d1", "", DistroDrainModeWait, "me")
+			require.NoError(t, err)
+			progress, err := drain.Process()
+			require.NoError(t, err)
+			assert.True(t, progress.IsFinished())
+
+			dbDrain, err := FindDistroDrain("d1")
+			require.NoError(t, err)
+			require.NotZero(t, dbDrain)
+			assert.False(t, dbDrain.FinishedAt.IsZero())
+
+			unfinished, err := FindUnfinishedDistroDrains()
+			require.NoError(t, err)
+			assert.Empty(t, unfinished)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(DistroDrainCollection, distro.Collection, task.Collection, host.Collection))
+			defer func() {
+				assert.NoError(t, db.ClearCollections(DistroDrainCollection, distro.Collection, task.Collection, host.Collection))
+			}()
+			for _, id := range []string{"d1", "d2"} {
+				d := distro.Distro{Id: id}
+				require.NoError(t, d.Insert())
+			}
+			tCase(t)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DefaultDistroFailureRateWindow is how far back to look at finished task
+// executions when comparing failure rates across distros.
+const DefaultDistroFailureRateWindow = 7 * 24 * time.Hour
+
+// DistroFailureRate is how often the task executions that ran on a distro
+// failed.
+type DistroFailureRate struct {
+	DistroID      string  `json:"distro_id" bson:"_id"`
+	NumExecutions int     `json:"num_executions" bson:"num_executions"`
+	NumFailed     int     `json:"num_failed" bson:"num_failed"`
+	FailureRate   float64 `json:"failure_rate" bson:"-"`
+}
+
+// DistroFailureRateOptions are the options for comparing failure rates across
+// distros. The project is required; the build variant and task name narrow the
+// comparison to the executions of a single task.
+type DistroFailureRateOptions struct {
+	Project      string
+	BuildVariant string
+	TaskName     string
+	Window       time.Duration
+}
+
+// GetDistroFailureRates compares how often the project's task executions
+// that finished within the window failed on each of the distros that they ran
+// on, which can reveal tasks that are flaky on only some of their run_on
+// distros. Each execution counts toward the distro of the host that it was
+// dispatched to. The distros are sorted from the highest failure rate to the
+// lowest.
+func GetDistroFailureRates(opts DistroFailureRateOptions) ([]DistroFailureRate, error) {
+	if opts.Project == "" {
+		return nil, errors.New("must specify a project")
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultDistroFailureRateWindow
+	}
+	match := bson.M{
+		task.ProjectKey:     opts.Project,
+		task.FinishTimeKey:  bson.M{"$gte": time.Now().Add(-opts.Window)},
+		task.StatusKey:      bson.M{"$in": evergreen.TaskCompletedStatuses},
+		task.DistroIdKey:    bson.M{"$ne": ""},
+		task.DisplayOnlyKey: bson.M{"$ne": true},
+	}
+	if opts.BuildVariant != "" {
+		match[task.BuildVariantKey] = opts.BuildVariant
+	}
+	if opts.TaskName != "" {
+		match[task.DisplayNameKey] = opts.TaskName
+	}
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id":            "$" + task.DistroIdKey,
+			"num_executions": bson.M{"$sum": 1},
+			"num_failed": bson.M{"$sum": bson.M{
+				"$cond": []interface{}{bson.M{"$eq": []string{"$" + task.StatusKey, evergreen.TaskFailed}}, 1, 0},
+			}},
+		}},
+	}
+
+	byDistro := map[string]*DistroFailureRate{}
+	// Earlier executions of restarted tasks are archived.
+	for _, coll := range []string{task.Collection, task.OldCollection} {
+		rates := []DistroFailureRate{}
+		if err := db.Aggregate(coll, pipeline, &rates); err != nil {
+			return nil, errors.Wrapf(err, "aggregating task failures by distro in collection '%s'", coll)
+		}
+		for _, r := range rates {
+			if existing, ok := byDistro[r.DistroID]; ok {
+				existing.NumExecutions += r.NumExecutions
+				existing.NumFailed += r.NumFailed
+				continue
+			}
+			rate := r
+			byDistro[r.DistroID] = &rate
+		}
+	}
+
+	res := make([]DistroFailureRate, 0, len(byDistro))
+	for _, r := range byDistro {
+		if r.NumExecutions > 0 {
+			r.FailureRate = float64(r.NumFailed) / float64(r.NumExecutions)
+		}
+		res = append(res, *r)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].FailureRate != res[j].FailureRate {
+			return res[i].FailureRate > res[j].FailureRate
+		}
+		return res[i].DistroID < res[j].DistroID
+	})
+	return res, nil
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDistroFailureRates(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, task.OldCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, task.OldCollection))
+	}()
+
+	now := time.Now()
+	for _, tsk := range []task.Task{
+		{Id: "t0", DisplayName: "compile", BuildVariant: "bv", Project: "p", DistroId: "d0", Status: evergreen.TaskSucceeded, FinishTime: now},
+		{Id: "t1", DisplayName: "compile", BuildVariant: "bv", Project: "p", DistroId: "d1", Status: evergreen.TaskFailed, FinishTime: now},
+		{Id: "t2", DisplayName: "test", BuildVariant: "bv", Project: "p", DistroId: "d1", Status: evergreen.TaskSucceeded, FinishTime: now},
+		{Id: "t3", DisplayName: "compile", BuildVariant: "bv", Project: "other", DistroId: "d0", Status: evergreen.TaskFailed, FinishTime: now},
+		{Id: "t4", DisplayName: "compile", BuildVariant: "bv", Project: "p", DistroId: "d0", Status: evergreen.TaskFailed, FinishTime: now.Add(-30 * 24 * time.Hour)},
+		{Id: "t5", DisplayName: "compile", BuildVariant: "bv", Project: "p", DistroId: "d0", Status: evergreen.TaskStarted},
+	} {
+		require.NoError(t, tsk.Insert())
+	}
+	// The earlier execution of t1 is archived.
+	oldTask := task.Task{Id: "t1_0", OldTaskId: "t1", DisplayName: "compile", BuildVariant: "bv", Project: "p", DistroId: "d1", Status: evergreen.TaskFailed, FinishTime: now}
+	require.NoError(t, db.Insert(task.OldCollection, &oldTask))
+
+	t.Run("ComparesDistrosForProject", func(t *testing.T) {
+		rates, err := GetDistroFailureRates(DistroFailureRateOptions{Project: "p"})
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.Equal(t, "d1", rates[0].DistroID)
+		assert.Equal(t, 3, rates[0].NumExecutions)
+		assert.Equal(t, 2, rates[0].NumFailed)
+		assert.InDelta(t, 2.0/3.0, rates[0].FailureRate, 0.0001)
+		assert.Equal(t, "d0", rates[1].DistroID)
+		assert.Equal(t, 1, rates[1].NumExecutions)
+		assert.Zero(t, rates[1].NumFailed)
+		assert.Zero(t, rates[1].FailureRate)
+	})
+	t.Run("NarrowsToTask", func(t *testing.T) {
+		rates, err := GetDistroFailureRates(DistroFailureRateOptions{Project: "p", BuildVariant: "bv", TaskName: "test"})
+		require.NoError(t, err)
+		require.Len(t, rates, 1)
+		assert.Equal(t, "d1", rates[0].DistroID)
+		assert.Equal(t, 1, rates[0].NumExecutions)
+		assert.Zero(t, rates[0].NumFailed)
+	})
+	t.Run("FailsWithoutProject", func(t *testing.T) {
+		_, err := GetDistroFailureRates(DistroFailureRateOptions{})
+		assert.Error(t, err)
+	})
+}
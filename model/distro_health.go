@@ -0,0 +1,164 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// DefaultDistroHealthWindow is how far back to look at finished tasks
+	// when summarizing a distro's health.
+	DefaultDistroHealthWindow = time.Hour
+	// distroUnhealthyFailureRate is the fraction of recent tasks that can
+	// fail because of their hosts before a distro is unhealthy.
+	distroUnhealthyFailureRate = 0.25
+	// distroHealthMinTasks is the number of recent tasks needed to decide
+	// that a distro is unhealthy.
+	distroHealthMinTasks = 10
+)
+
+// DistroHealth summarizes how often the tasks that recently finished on a
+// distro's hosts failed because of the hosts rather than the tasks.
+type DistroHealth struct {
+	DistroID string `bson:"distro_id" json:"distro_id"`
+	// Since is the start of the window of finished tasks that are counted.
+	Since             time.Time `bson:"since" json:"since"`
+	NumTasks          int       `bson:"num_tasks" json:"num_tasks"`
+	NumSystemFailures int       `bson:"num_system_failures" json:"num_system_failures"`
+	NumSetupFailures  int       `bson:"num_setup_failures" json:"num_setup_failures"`
+	// FailureRate is the fraction of tasks that had system or setup
+	// failures.
+	FailureRate         float64      `bson:"failure_rate" json:"failure_rate"`
+	NumQuarantinedHosts int          `bson:"num_quarantined_hosts" json:"num_quarantined_hosts"`
+	Healthy             bool         `bson:"healthy" json:"healthy"`
+	Hosts               []HostHealth `bson:"hosts" json:"hosts"`
+}
+
+// HostHealth is how often the tasks that recently finished on a host failed
+// because of the host.
+type HostHealth struct {
+	HostID            string  `bson:"host_id" json:"host_id"`
+	Status            string  `bson:"status" json:"status"`
+	NumTasks          int     `bson:"num_tasks" json:"num_tasks"`
+	NumSystemFailures int     `bson:"num_system_failures" json:"num_system_failures"`
+	NumSetupFailures  int     `bson:"num_setup_failures" json:"num_setup_failures"`
+	FailureRate       float64 `bson:"failure_rate" json:"failure_rate"`
+}
+
+// IsHostFailure returns whether the task failed because of the host it ran on
+// rather than because of the task itself.
+func IsHostFailure(t *task.Task) bool {
+	return hostFailureType(t) != ""
+}
+
+// hostFailureType returns the type of command that failed if the task failed
+// because of its host, or an empty string otherwise.
+func hostFailureType(t *task.Task) string {
+	if t.Aborted || !evergreen.IsFailedTaskStatus(t.Status) {
+		return ""
+	}
+	switch t.Details.Type {
+	case evergreen.CommandTypeSystem, evergreen.CommandTypeSetup:
+		return t.Details.Type
+	default:
+		return ""
+	}
+}
+
+// GetDistroHealth summarizes the host failures of the tasks that finished on
+// the distro within the window, both for the distro and for each of its
+// hosts that ran tasks.
+func GetDistroHealth(distroID string, window time.Duration) (*DistroHealth, error) {
+	since := time.Now().Add(-window)
+	q := db.Query(bson.M{
+		task.DistroIdKey:   distroID,
+		task.FinishTimeKey: bson.M{"$gte": since},
+		task.StatusKey:     bson.M{"$in": evergreen.TaskCompletedStatuses},
+	}).WithFields(task.IdKey, task.HostIdKey, task.StatusKey, task.DetailsKey, task.AbortedKey)
+	tasks, err := task.FindAll(q)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding recent tasks for distro '%s'", distroID)
+	}
+	// Executions that failed because of their host are often restarted
+	// automatically, which archives them.
+	oldTasks, err := task.FindAllOld(q)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding recent archived tasks for distro '%s'", distroID)
+	}
+	tasks = append(tasks, oldTasks...)
+
+	health := &DistroHealth{
+		DistroID: distroID,
+		Since:    since,
+		NumTasks: len(tasks),
+	}
+	hostHealth := map[string]*HostHealth{}
+	for i := range tasks {
+		t := &tasks[i]
+		hh, ok := hostHealth[t.HostId]
+		if !ok && t.HostId != "" {
+			hh = &HostHealth{HostID: t.HostId}
+			hostHealth[t.HostId] = hh
+		}
+		if hh != nil {
+			hh.NumTasks++
+		}
+		switch hostFailureType(t) {
+		case evergreen.CommandTypeSystem:
+			health.NumSystemFailures++
+			if hh != nil {
+				hh.NumSystemFailures++
+			}
+		case evergreen.CommandTypeSetup:
+			health.NumSetupFailures++
+			if hh != nil {
+				hh.NumSetupFailures++
+			}
+		}
+	}
+	if health.NumTasks > 0 {
+		health.FailureRate = float64(health.NumSystemFailures+health.NumSetupFailures) / float64(health.NumTasks)
+	}
+
+	hostIDs := make([]string, 0, len(hostHealth))
+	for hostID, hh := range hostHealth {
+		hh.FailureRate = float64(hh.NumSystemFailures+hh.NumSetupFailures) / float64(hh.NumTasks)
+		hostIDs = append(hostIDs, hostID)
+	}
+	hosts, err := host.Find(db.Query(bson.M{host.IdKey: bson.M{"$in": hostIDs}}).WithFields(host.IdKey, host.StatusKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding hosts for distro '%s'", distroID)
+	}
+	for _, h := range hosts {
+		hostHealth[h.Id].Status = h.Status
+	}
+	health.NumQuarantinedHosts, err = host.Count(db.Query(bson.M{
+		bsonutil.GetDottedKeyName(host.DistroKey, distro.IdKey): distroID,
+		host.StatusKey: evergreen.HostQuarantined,
+	}))
+	if err != nil {
+		return nil, errors.Wrapf(err, "counting quarantined hosts for distro '%s'", distroID)
+	}
+
+	for _, hh := range hostHealth {
+		health.Hosts = append(health.Hosts, *hh)
+	}
+	sort.Slice(health.Hosts, func(i, j int) bool {
+		if health.Hosts[i].FailureRate == health.Hosts[j].FailureRate {
+			return health.Hosts[i].HostID < health.Hosts[j].HostID
+		}
+		return health.Hosts[i].FailureRate > health.Hosts[j].FailureRate
+	})
+	health.Healthy = health.NumTasks < distroHealthMinTasks || health.FailureRate < distroUnhealthyFailureRate
+
+	return health, nil
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsHostFailure(t *testing.T) {
+	for name, tCase := range map[string]struct {
+		tsk      task.Task
+		expected bool
+	}{
+		"SystemFailure":  {tsk: task.Task{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeSystem}}, expected: true},
+		"SetupFailure":   {tsk: task.Task{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeSetup}}, expected: true},
+		"TestFailure":    {tsk: task.Task{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeTest}}},
+		"AbortedFailure": {tsk: task.Task{Status: evergreen.TaskFailed, Aborted: true, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeSystem}}},
+		"Succeeded":      {tsk: task.Task{Status: evergreen.TaskSucceeded}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tCase.expected, IsHostFailure(&tCase.tsk))
+		})
+	}
+}
+
+func TestGetDistroHealth(t *testing.T) {
+	colls := []string{task.Collection, task.OldCollection, host.Collection}
+	require.NoError(t, db.ClearCollections(colls...))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(colls...))
+	}()
+
+	now := time.Now()
+	insertTask := func(id, hostID, failureType string, finishTime time.Time) {
+		tsk := task.Task{
+			Id:         id,
+			DistroId:   "d1",
+			HostId:     hostID,
+			Status:     evergreen.TaskSucceeded,
+			FinishTime: finishTime,
+		}
+		if failureType != "" {
+			tsk.Status = evergreen.TaskFailed
+			tsk.Details = apimodels.TaskEndDetail{Type: failureType}
+		}
+		require.NoError(t, tsk.Insert())
+	}
+	insertTask("t0", "h1", evergreen.CommandTypeSystem, now.Add(-time.Minute))
+	insertTask("t1", "h1", evergreen.CommandTypeSetup, now.Add(-time.Minute))
+	insertTask("t2", "h1", evergreen.CommandTypeSystem, now.Add(-time.Minute))
+	insertTask("t3", "h2", evergreen.CommandTypeTest, now.Add(-time.Minute))
+	for i := 4; i < 10; i++ {
+		insertTask(fmt.Sprintf("t%d", i), "h2", "", now.Add(-time.Minute))
+	}
+	insertTask("old", "h2", evergreen.CommandTypeSystem, now.Add(-2*time.Hour))
+
+	h1 := host.Host{Id: "h1", Distro: distro.Distro{Id: "d1"}, Status: evergreen.HostQuarantined}
+	h2 := host.Host{Id: "h2", Distro: distro.Distro{Id: "d1"}, Status: evergreen.HostRunning}
+	require.NoError(t, h1.Insert())
+	require.NoError(t, h2.Insert())
+
+	t.Run("CountsRecentHostFailures", func(t *testing.T) {
+		health, err := GetDistroHealth("d1", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, 10, health.NumTasks)
+		assert.Equal(t, 2, health.NumSystemFailures)
+		assert.Equal(t, 1, health.NumSetupFailures)
+		assert.InDelta(t, 0.3, health.FailureRate, 0.001)
+		assert.Equal(t, 1, health.NumQuarantinedHosts)
+		assert.False(t, health.Healthy)
+
+		require.Len(t, health.Hosts, 2)
+		assert.Equal(t, "h1", health.Hosts[0].HostID)
+		assert.Equal(t, evergreen.HostQuarantined, health.Hosts[0].Status)
+		assert.Equal(t, 3, health.Hosts[0].NumTasks)
+		assert.InDelta(t, 1, health.Hosts[0].FailureRate, 0.001)
+		assert.Equal(t, "h2", health.Hosts[1].HostID)
+		assert.Equal(t, 7, health.Hosts[1].NumTasks)
+		assert.Zero(t, health.Hosts[1].FailureRate)
+	})
+	t.Run("IsHealthyWithTooFewTasks", func(t *testing.T) {
+		health, err := GetDistroHealth("d2", time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, health.NumTasks)
+		assert.Empty(t, health.Hosts)
+		assert.True(t, health.Healthy)
+	})
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// The places a project setting can come from, from highest to lowest
+// precedence.
+const (
+	ProjectSettingSourceBranch        = "branch"
+	ProjectSettingSourceRepo          = "repo"
+	ProjectSettingSourceProjectConfig = "project_config"
+	ProjectSettingSourceDefault       = "default"
+)
+
+// EffectiveProjectConfig is the project settings that applied to a version,
+// along with where each setting came from.
+type EffectiveProjectConfig struct {
+	VersionID string
+	// ProjectRef is the project's branch settings merged with its repo
+	// settings and, if version control is enabled, with the settings from the
+	// version's project config.
+	ProjectRef ProjectRef
+	// Sources maps each setting, by its dotted BSON field name, to where its
+	// value came from. A struct setting whose fields came from different
+	// places is broken down into its fields.
+	Sources map[string]string
+}
+
+// settingsLayer is one of the places project settings are merged from.
+type settingsLayer struct {
+	source   string
+	settings reflect.Value
+}
+
+// GetEffectiveProjectConfig returns the project settings that applied to the
+// version and where each of them came from.
+func GetEffectiveProjectConfig(v *Version) (*EffectiveProjectConfig, error) {
+	branchRef, err := FindBranchProjectRef(v.Identifier)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project ref '%s'", v.Identifier)
+	}
+	if branchRef == nil {
+		return nil, errors.Errorf("project ref '%s' not found", v.Identifier)
+	}
+
+	// The version's own project config is merged in below, rather than the
+	// project's latest one.
+	merged, err := FindMergedProjectRef(v.Identifier, "", false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "merging settings for project '%s'", v.Identifier)
+	}
+	if merged == nil {
+		return nil, errors.Errorf("project ref '%s' not found", v.Identifier)
+	}
+	layers := []settingsLayer{{source: ProjectSettingSourceBranch, settings: reflect.ValueOf(*branchRef)}}
+	if branchRef.UseRepoSettings() {
+		repoRef, err := FindOneRepoRef(branchRef.RepoRefId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding repo ref '%s'", branchRef.RepoRefId)
+		}
+		if repoRef != nil {
+			layers = append(layers, settingsLayer{source: ProjectSettingSourceRepo, settings: reflect.ValueOf(repoRef.ProjectRef)})
+		}
+	}
+	if merged.IsVersionControlEnabled() {
+		projectConfig, err := FindProjectConfigById(v.Id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding project config for version '%s'", v.Id)
+		}
+		if projectConfig != nil {
+			configSettings := projectConfig.projectRefSettings()
+			util.RecursivelySetUndefinedFields(reflect.ValueOf(merged).Elem(), reflect.ValueOf(configSettings))
+			layers = append(layers, settingsLayer{source: ProjectSettingSourceProjectConfig, settings: reflect.ValueOf(configSettings)})
+		}
+	}
+
+	sources := map[string]string{}
+	recordSettingSources(sources, "", layers)
+	return &EffectiveProjectConfig{
+		VersionID:  v.Id,
+		ProjectRef: *merged,
+		Sources:    sources,
+	}, nil
+}
+
+// recordSettingSources records where each field of the settings structs came
+// from, following the same precedence as util.RecursivelySetUndefinedFields:
+// a field comes from the first layer that defines it, and a struct field
+// defined in several layers is merged field by field.
+func recordSettingSources(sources map[string]string, prefix string, layers []settingsLayer) {
+	if len(layers) == 0 {
+		return
+	}
+	structType := layers[0].settings.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := settingName(field)
+		if name == "" {
+			continue
+		}
+		name = prefix + name
+
+		var definedIn []settingsLayer
+		for _, layer := range layers {
+			fieldValue := layer.settings.Field(i)
+			if util.IsFieldUndefined(fieldValue) {
+				continue
+			}
+			if util.IsFieldPtr(fieldValue) {
+				fieldValue = fieldValue.Elem()
+			}
+			definedIn = append(definedIn, settingsLayer{source: layer.source, settings: fieldValue})
+		}
+		if len(definedIn) == 0 {
+			sources[name] = ProjectSettingSourceDefault
+			continue
+		}
+		if len(definedIn) > 1 && definedIn[0].settings.Kind() == reflect.Struct && hasSettingFields(definedIn[0].settings.Type()) {
+			recordSettingSources(sources, name+".", definedIn)
+			continue
+		}
+		sources[name] = definedIn[0].source
+	}
+}
+
+// settingName returns the BSON name of a settings field, or an empty string
+// if the field is not stored.
+func settingName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	name := strings.Split(field.Tag.Get("bson"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// hasSettingFields returns whether the struct type has any stored fields to
+// break it down into.
+func hasSettingFields(structType reflect.Type) bool {
+	for i := 0; i < structType.NumField(); i++ {
+		if settingName(structType.Field(i)) != "" {
+			return true
+		}
+	}
+	return false
+}
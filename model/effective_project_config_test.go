@@ -0,0 +1,70 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEffectiveProjectConfig(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ProjectRefCollection, RepoRefCollection, ProjectConfigCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection, RepoRefCollection, ProjectConfigCollection))
+	}()
+
+	repoRef := &RepoRef{ProjectRef{
+		Id:               "repo",
+		Owner:            "mongodb",
+		Repo:             "mci",
+		PRTestingEnabled: utility.TruePtr(),
+		CommitQueue:      CommitQueueParams{Enabled: utility.TruePtr()},
+	}}
+	require.NoError(t, repoRef.Upsert())
+	pRef := &ProjectRef{
+		Id:                    "branch",
+		Owner:                 "mongodb",
+		Repo:                  "mci",
+		Branch:                "main",
+		RepoRefId:             repoRef.Id,
+		BatchTime:             10,
+		VersionControlEnabled: utility.TruePtr(),
+		CommitQueue:           CommitQueueParams{Message: "branch message"},
+	}
+	require.NoError(t, pRef.Insert())
+	projectConfig := &ProjectConfig{
+		Id: "v1",
+		ProjectConfigFields: ProjectConfigFields{
+			TaskAnnotationSettings: &evergreen.AnnotationsSettings{
+				FileTicketWebhook: evergreen.WebHook{Endpoint: "endpoint"},
+			},
+		},
+	}
+	require.NoError(t, projectConfig.Insert())
+
+	config, err := GetEffectiveProjectConfig(&Version{Id: "v1", Identifier: pRef.Id})
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.Equal(t, "v1", config.VersionID)
+
+	assert.Equal(t, 10, config.ProjectRef.BatchTime)
+	assert.True(t, config.ProjectRef.IsPRTestingEnabled())
+	assert.Equal(t, "branch message", config.ProjectRef.CommitQueue.Message)
+	assert.True(t, config.ProjectRef.CommitQueue.IsEnabled())
+	assert.Equal(t, "endpoint", config.ProjectRef.TaskAnnotationSettings.FileTicketWebhook.Endpoint)
+
+	assert.Equal(t, ProjectSettingSourceBranch, config.Sources[ProjectRefBatchTimeKey])
+	assert.Equal(t, ProjectSettingSourceRepo, config.Sources[projectRefPRTestingEnabledKey])
+	assert.Equal(t, ProjectSettingSourceBranch, config.Sources["commit_queue.message"])
+	assert.Equal(t, ProjectSettingSourceRepo, config.Sources["commit_queue.enabled"])
+	assert.Equal(t, ProjectSettingSourceProjectConfig, config.Sources[projectRefTaskAnnotationSettingsKey])
+	assert.Equal(t, ProjectSettingSourceDefault, config.Sources[projectRefDispatchingDisabledKey])
+
+	t.Run("NonexistentProject", func(t *testing.T) {
+		_, err := GetEffectiveProjectConfig(&Version{Id: "v2", Identifier: "nonexistent"})
+		assert.Error(t, err)
+	})
+}
@@ -3,8 +3,12 @@ package event
 import (
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 const (
@@ -16,6 +20,7 @@ const (
 	EventDistroModified   = "DISTRO_MODIFIED"
 	EventDistroAMIModfied = "DISTRO_AMI_MODIFIED"
 	EventDistroRemoved    = "DISTRO_REMOVED"
+	EventDistroUnhealthy  = "DISTRO_UNHEALTHY"
 )
 
 // DistroEventData implements EventData.
@@ -62,3 +67,23 @@ func LogDistroRemoved(distroId, userId string, data interface{}) {
 func LogDistroAMIModified(distroId, userId string) {
 	LogDistroEvent(distroId, EventDistroAMIModfied, DistroEventData{UserId: userId})
 }
+
+// LogDistroUnhealthy logs that many of the tasks that recently ran on the
+// distro's hosts failed because of the hosts.
+func LogDistroUnhealthy(distroId string, data interface{}) {
+	LogDistroEvent(distroId, EventDistroUnhealthy, DistroEventData{UserId: evergreen.User, Data: data})
+}
+
+// HasRecentDistroEvent returns whether an event of the given type was logged
+// for the distro since the given time.
+func HasRecentDistroEvent(distroId, eventType string, since time.Time) (bool, error) {
+	filter := ResourceTypeKeyIs(ResourceTypeDistro)
+	filter[ResourceIdKey] = distroId
+	filter[TypeKey] = eventType
+	filter[TimestampKey] = bson.M{"$gte": since}
+	count, err := db.CountQ(AllLogCollection, db.Query(filter))
+	if err != nil {
+		return false, errors.Wrapf(err, "counting '%s' events for distro '%s'", eventType, distroId)
+	}
+	return count > 0, nil
+}
@@ -146,6 +146,27 @@ func TaskEventsInOrder(id string) db.Q {
 	return TaskEventsForId(id).Sort([]string{TimestampKey})
 }
 
+// TaskLatencyEventTypes are the task events that mark the stages of a task
+// execution from being queued to finishing.
+var TaskLatencyEventTypes = []string{
+	TaskEnqueued,
+	ContainerAllocated,
+	TaskDispatched,
+	TaskAgentAccepted,
+	TaskStarted,
+	TaskFinished,
+}
+
+// TaskLatencyEventsInOrder returns a query for the latency events of the
+// tasks, from oldest to newest.
+func TaskLatencyEventsInOrder(ids []string) db.Q {
+	filter := ResourceTypeKeyIs(ResourceTypeTask)
+	filter[ResourceIdKey] = bson.M{"$in": ids}
+	filter[TypeKey] = bson.M{"$in": TaskLatencyEventTypes}
+
+	return db.Query(filter).Sort([]string{TimestampKey})
+}
+
 // Distro Events
 
 // FindLatestPrimaryDistroEvents return the most recent non-AMI events for the distro.
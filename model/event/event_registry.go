@@ -122,3 +122,11 @@ func userEventDataFactory() interface{} {
 func podEventDataFactory() interface{} {
 	return &podData{}
 }
+
+func serviceAccountEventDataFactory() interface{} {
+	return &ServiceAccountEventData{}
+}
+
+func projectDependencyEventDataFactory() interface{} {
+	return &ProjectDependencyEventData{}
+}
@@ -0,0 +1,52 @@
+package event
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	registry.AddType(ResourceTypeProjectDependency, projectDependencyEventDataFactory)
+}
+
+const (
+	// ResourceTypeProjectDependency represents a project's dependency on
+	// another project as a resource associated with events. The event's
+	// resource ID is the dependent project's ID.
+	ResourceTypeProjectDependency = "PROJECT_DEPENDENCY"
+
+	// EventUpstreamProjectRemoved represents an event where a project that
+	// the dependent project relies on was deleted or disabled.
+	EventUpstreamProjectRemoved = "UPSTREAM_PROJECT_REMOVED"
+)
+
+// ProjectDependencyEventData contains information about a change to a project
+// that other projects depend on.
+type ProjectDependencyEventData struct {
+	UpstreamProjectID  string `bson:"upstream_project_id" json:"upstream_project_id"`
+	UpstreamIdentifier string `bson:"upstream_identifier" json:"upstream_identifier"`
+	// Action is what happened to the upstream project, e.g. "deleted" or
+	// "disabled".
+	Action string   `bson:"action" json:"action"`
+	User   string   `bson:"user" json:"user"`
+	Kinds  []string `bson:"kinds" json:"kinds"`
+}
+
+// LogProjectDependencyEvent logs an event for a project that depends on
+// another project to the event log.
+func LogProjectDependencyEvent(projectID, eventType string, data ProjectDependencyEventData) error {
+	e := EventLogEntry{
+		Timestamp:    time.Now(),
+		ResourceId:   projectID,
+		ResourceType: ResourceTypeProjectDependency,
+		EventType:    eventType,
+		Data:         data,
+	}
+
+	logger := NewDBEventLogger(AllLogCollection)
+	if err := logger.LogEvent(&e); err != nil {
+		return errors.Wrapf(err, "logging dependency event for project '%s'", projectID)
+	}
+	return nil
+}
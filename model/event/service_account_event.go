@@ -0,0 +1,78 @@
+package event
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	registry.AddType(ResourceTypeServiceAccount, serviceAccountEventDataFactory)
+}
+
+// ServiceAccountEventType represents a type of event related to a project's
+// service account.
+type ServiceAccountEventType string
+
+const (
+	// ResourceTypeServiceAccount represents a project's service account as a
+	// resource associated with events.
+	ResourceTypeServiceAccount = "SERVICE_ACCOUNT"
+
+	// EventServiceAccountCreated represents an event where a service account
+	// is issued.
+	EventServiceAccountCreated ServiceAccountEventType = "CREATED"
+	// EventServiceAccountKeyRotated represents an event where a service
+	// account's API key is replaced.
+	EventServiceAccountKeyRotated ServiceAccountEventType = "KEY_ROTATED"
+	// EventServiceAccountRevoked represents an event where a service account
+	// is deleted.
+	EventServiceAccountRevoked ServiceAccountEventType = "REVOKED"
+	// EventServiceAccountAction represents a request that a service account
+	// made to modify something.
+	EventServiceAccountAction ServiceAccountEventType = "ACTION"
+)
+
+// ServiceAccountEventData contains information relevant to a service account
+// event.
+type ServiceAccountEventData struct {
+	ProjectID string `bson:"project_id" json:"project_id"`
+	// User is the user who managed the service account. It's not set for
+	// actions taken by the service account itself.
+	User        string   `bson:"user,omitempty" json:"user,omitempty"`
+	Permissions []string `bson:"permissions,omitempty" json:"permissions,omitempty"`
+	Method      string   `bson:"method,omitempty" json:"method,omitempty"`
+	Path        string   `bson:"path,omitempty" json:"path,omitempty"`
+	StatusCode  int      `bson:"status_code,omitempty" json:"status_code,omitempty"`
+}
+
+var serviceAccountEventDataProjectIDKey = bsonutil.MustHaveTag(ServiceAccountEventData{}, "ProjectID")
+
+// LogServiceAccountEvent logs an event for a service account to the event
+// log.
+func LogServiceAccountEvent(accountID string, kind ServiceAccountEventType, data ServiceAccountEventData) error {
+	e := EventLogEntry{
+		Timestamp:    time.Now(),
+		ResourceId:   accountID,
+		ResourceType: ResourceTypeServiceAccount,
+		EventType:    string(kind),
+		Data:         data,
+	}
+
+	logger := NewDBEventLogger(AllLogCollection)
+	if err := logger.LogEvent(&e); err != nil {
+		return errors.Wrapf(err, "logging event for service account '%s'", accountID)
+	}
+	return nil
+}
+
+// FindServiceAccountEventsForProject returns the most recent events for all
+// of the project's service accounts, including ones that have been revoked.
+func FindServiceAccountEventsForProject(projectID string, n int) ([]EventLogEntry, error) {
+	filter := ResourceTypeKeyIs(ResourceTypeServiceAccount)
+	filter[bsonutil.GetDottedKeyName(DataKey, serviceAccountEventDataProjectIDKey)] = projectID
+	events, err := Find(AllLogCollection, db.Query(filter).Sort([]string{"-" + TimestampKey}).Limit(n))
+	return events, errors.Wrapf(err, "finding service account events for project '%s'", projectID)
+}
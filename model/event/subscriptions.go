@@ -67,6 +67,7 @@ const (
 	GeneralSubscriptionSpawnhostExpiration           = "spawnhost-expiration"
 	GeneralSubscriptionSpawnHostOutcome              = "spawnhost-outcome"
 	GeneralSubscriptionCommitQueue                   = "commit-queue"
+	GeneralSubscriptionTasksBlocked                  = "tasks-blocked"
 
 	ObjectTask    = "task"
 	ObjectVersion = "version"
@@ -85,6 +86,8 @@ const (
 	TriggerPatchStarted              = "started"
 	TriggerTaskFirstFailureInVersion = "first-failure-in-version"
 	TriggerTaskStarted               = "task-started"
+	TriggerWarningBudgetExceeded     = "warning-budget-exceeded"
+	TriggerTasksBlocked              = "tasks-blocked"
 )
 
 type Subscription struct {
@@ -724,6 +727,8 @@ func CreateOrUpdateGeneralSubscription(resourceType string, id string,
 				temp = NewSpawnHostOutcomeByOwner(user, subscriber)
 			case GeneralSubscriptionCommitQueue:
 				temp = NewCommitQueueSubscriptionByOwner(user, subscriber)
+			case GeneralSubscriptionTasksBlocked:
+				temp = NewTasksBlockedSubscriptionByOwner(user, subscriber)
 			default:
 				return nil, errors.Errorf("unknown subscription resource type: %s", resourceType)
 			}
@@ -786,6 +791,20 @@ func NewVersionGithubCheckOutcomeSubscription(id string, sub Subscriber) Subscri
 	return subscription
 }
 
+// NewVersionWarningBudgetSubscription returns a subscription for when the
+// version's project config exceeds the project's warning budget.
+func NewVersionWarningBudgetSubscription(id string, sub Subscriber) Subscription {
+	subscription := NewSubscriptionByID(ResourceTypeVersion, TriggerWarningBudgetExceeded, id, sub)
+	subscription.LastUpdated = time.Now()
+	return subscription
+}
+
+// NewTasksBlockedSubscriptionByOwner returns a subscription for when the
+// owner's tasks are blocked by tasks that they depend on failing.
+func NewTasksBlockedSubscriptionByOwner(owner string, sub Subscriber) Subscription {
+	return NewSubscriptionByOwner(owner, sub, ResourceTypeVersion, TriggerTasksBlocked)
+}
+
 func NewExpiringPatchOutcomeSubscription(id string, sub Subscriber) Subscription {
 	subscription := NewSubscriptionByID(ResourceTypePatch, TriggerOutcome, id, sub)
 	subscription.LastUpdated = time.Now()
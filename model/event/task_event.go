@@ -30,11 +30,17 @@ const (
 	TaskActivated              = "TASK_ACTIVATED"
 	TaskDeactivated            = "TASK_DEACTIVATED"
 	TaskAbortRequest           = "TASK_ABORT_REQUEST"
+	TaskCommandAbortRequest    = "TASK_COMMAND_ABORT_REQUEST"
+	TaskSkipped                = "TASK_SKIPPED"
 	ContainerAllocated         = "CONTAINER_ALLOCATED"
 	TaskPriorityChanged        = "TASK_PRIORITY_CHANGED"
 	TaskJiraAlertCreated       = "TASK_JIRA_ALERT_CREATED"
 	TaskDependenciesOverridden = "TASK_DEPENDENCIES_OVERRIDDEN"
 	MergeTaskUnscheduled       = "MERGE_TASK_UNSCHEDULED"
+	TaskStuck                  = "TASK_STUCK"
+	TaskEnqueued               = "TASK_ENQUEUED"
+	TaskAgentAccepted          = "TASK_AGENT_ACCEPTED"
+	TaskSecretDetected         = "TASK_SECRET_DETECTED"
 
 	// TODO (EVG-16969) remove once TaskScheduled events TTL
 	TaskScheduled = "TASK_SCHEDULED"
@@ -51,6 +57,10 @@ type TaskEventData struct {
 
 	Timestamp time.Time `bson:"ts,omitempty" json:"timestamp,omitempty"`
 	Priority  int64     `bson:"pri,omitempty" json:"priority,omitempty"`
+
+	// NumSecrets is the number of secrets that were redacted from the task's
+	// uploads.
+	NumSecrets int `bson:"num_secrets,omitempty" json:"num_secrets,omitempty"`
 }
 
 func logTaskEvent(taskId string, eventType string, eventData TaskEventData) {
@@ -116,6 +126,34 @@ func LogTaskCreated(taskId string, execution int) {
 	logTaskEvent(taskId, TaskCreated, TaskEventData{Execution: execution})
 }
 
+// LogManyTasksEnqueued logs events for tasks being added to a task queue for
+// the first time in their current execution. The tasks are given as a mapping
+// of task IDs to their executions.
+func LogManyTasksEnqueued(executions map[string]int) {
+	if len(executions) == 0 {
+		return
+	}
+	events := make([]EventLogEntry, 0, len(executions))
+	now := time.Now()
+	for id, execution := range executions {
+		events = append(events, EventLogEntry{
+			Timestamp:    now,
+			ResourceId:   id,
+			EventType:    TaskEnqueued,
+			Data:         TaskEventData{Execution: execution},
+			ResourceType: ResourceTypeTask,
+		})
+	}
+	logger := NewDBEventLogger(AllLogCollection)
+	if err := logger.LogManyEvents(events); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"resource_type": ResourceTypeTask,
+			"message":       "error logging event",
+			"source":        "event-log-fail",
+		}))
+	}
+}
+
 // LogHostTaskDispatched logs an event for a host task being dispatched.
 func LogHostTaskDispatched(taskId string, execution int, hostId string) {
 	logTaskEvent(taskId, TaskDispatched, TaskEventData{Execution: execution, HostId: hostId})
@@ -138,10 +176,23 @@ func LogContainerTaskUnallocated(taskID string, execution int, podID string) {
 	logTaskEvent(taskID, TaskUndispatched, TaskEventData{Execution: execution, PodID: podID})
 }
 
+// LogTaskAgentAccepted logs an event for the agent that a task was dispatched
+// to accepting it and starting to set it up.
+func LogTaskAgentAccepted(taskID string, execution int, hostID string) {
+	logTaskEvent(taskID, TaskAgentAccepted, TaskEventData{Execution: execution, HostId: hostID})
+}
+
 func LogTaskStarted(taskId string, execution int) {
 	logTaskEvent(taskId, TaskStarted, TaskEventData{Execution: execution, Status: evergreen.TaskStarted})
 }
 
+// LogTaskStuck logs an event for a running task whose heartbeat has been
+// stale for longer than its distro's stuck task threshold. The timestamp is
+// the task's last heartbeat.
+func LogTaskStuck(taskId string, execution int, hostId string, lastHeartbeat time.Time) {
+	logTaskEvent(taskId, TaskStuck, TaskEventData{Execution: execution, HostId: hostId, Timestamp: lastHeartbeat})
+}
+
 func LogTaskFinished(taskId string, execution int, hostId, status string) {
 	logTaskEvent(taskId, TaskFinished, TaskEventData{Execution: execution, Status: status})
 	if hostId != "" {
@@ -165,11 +216,22 @@ func LogTaskDeactivated(taskId string, execution int, userId string) {
 	logTaskEvent(taskId, TaskDeactivated, TaskEventData{Execution: execution, UserId: userId})
 }
 
+func LogTaskSkipped(taskId string, execution int, userId string) {
+	logTaskEvent(taskId, TaskSkipped, TaskEventData{Execution: execution, UserId: userId})
+}
+
 func LogTaskAbortRequest(taskId string, execution int, userId string) {
 	logTaskEvent(taskId, TaskAbortRequest,
 		TaskEventData{Execution: execution, UserId: userId})
 }
 
+// LogTaskCommandAbortRequest logs an event for a user requesting that the
+// task's running command be aborted.
+func LogTaskCommandAbortRequest(taskId string, execution int, userId string) {
+	logTaskEvent(taskId, TaskCommandAbortRequest,
+		TaskEventData{Execution: execution, UserId: userId})
+}
+
 func LogManyTaskAbortRequests(taskIds []string, userId string) {
 	logManyTaskEvents(taskIds, TaskAbortRequest,
 		TaskEventData{UserId: userId})
@@ -189,3 +251,9 @@ func LogMergeTaskUnscheduled(taskId string, execution int, userID string) {
 	logTaskEvent(taskId, MergeTaskUnscheduled,
 		TaskEventData{Execution: execution, UserId: userID})
 }
+
+// LogTaskSecretDetected logs a security warning for a task that uploaded logs
+// or artifact metadata containing secrets, which were redacted.
+func LogTaskSecretDetected(taskId string, execution int, numSecrets int) {
+	logTaskEvent(taskId, TaskSecretDetected, TaskEventData{Execution: execution, NumSecrets: numSecrets})
+}
@@ -11,6 +11,10 @@ func init() {
 	registry.AddType(ResourceTypeVersion, versionEventDataFactory)
 	registry.AllowSubscription(ResourceTypeVersion, VersionStateChange)
 	registry.AllowSubscription(ResourceTypeVersion, VersionGithubCheckFinished)
+	registry.AllowSubscription(ResourceTypeVersion, VersionWarningBudgetExceeded)
+	registry.AllowSubscription(ResourceTypeVersion, VersionExternalGateOpened)
+	registry.AllowSubscription(ResourceTypeVersion, VersionExternalGateTimedOut)
+	registry.AllowSubscription(ResourceTypeVersion, VersionTasksBlocked)
 }
 
 func versionEventDataFactory() interface{} {
@@ -18,14 +22,31 @@ func versionEventDataFactory() interface{} {
 }
 
 const (
-	ResourceTypeVersion        = "VERSION"
-	VersionStateChange         = "STATE_CHANGE"
-	VersionGithubCheckFinished = "GITHUB_CHECK_FINISHED"
+	ResourceTypeVersion          = "VERSION"
+	VersionStateChange           = "STATE_CHANGE"
+	VersionGithubCheckFinished   = "GITHUB_CHECK_FINISHED"
+	VersionWarningBudgetExceeded = "WARNING_BUDGET_EXCEEDED"
+	VersionExternalGateOpened    = "EXTERNAL_GATE_OPENED"
+	VersionExternalGateTimedOut  = "EXTERNAL_GATE_TIMED_OUT"
+	VersionTasksBlocked          = "TASKS_BLOCKED"
 )
 
 type VersionEventData struct {
 	Status            string `bson:"status,omitempty" json:"status,omitempty"`
 	GithubCheckStatus string `bson:"github_check_status,omitempty" json:"github_check_status,omitempty"`
+	ExternalGate      string `bson:"external_gate,omitempty" json:"external_gate,omitempty"`
+	User              string `bson:"user,omitempty" json:"user,omitempty"`
+	// BlockedTasks are the version's tasks that a task failing just blocked.
+	BlockedTasks []BlockedTask `bson:"blocked_tasks,omitempty" json:"blocked_tasks,omitempty"`
+}
+
+// BlockedTask is a task that was blocked by a task that it depends on, either
+// directly or through other tasks.
+type BlockedTask struct {
+	TaskID string `bson:"task_id" json:"task_id"`
+	// Chain is the IDs of the tasks along the dependencies from the task that
+	// blocked this one to this one, inclusive.
+	Chain []string `bson:"chain" json:"chain"`
 }
 
 func LogVersionStateChangeEvent(id, newStatus string) {
@@ -69,3 +90,95 @@ func LogVersionGithubCheckFinishedEvent(id, newStatus string) {
 		}))
 	}
 }
+
+// LogVersionWarningBudgetExceededEvent logs that the version's project config
+// has more validation warnings than the project's warning budget allows.
+func LogVersionWarningBudgetExceededEvent(id string) {
+	event := EventLogEntry{
+		Timestamp:    time.Now().Truncate(0).Round(time.Millisecond),
+		ResourceId:   id,
+		ResourceType: ResourceTypeVersion,
+		EventType:    VersionWarningBudgetExceeded,
+		Data:         &VersionEventData{},
+	}
+
+	logger := NewDBEventLogger(AllLogCollection)
+	if err := logger.LogEvent(&event); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"resource_type": ResourceTypeVersion,
+			"message":       "error logging event",
+			"source":        "event-log-fail",
+		}))
+	}
+}
+
+// LogVersionExternalGateOpenedEvent logs that the user opened one of the
+// version's external gates.
+func LogVersionExternalGateOpenedEvent(id, gate, user string) {
+	event := EventLogEntry{
+		Timestamp:    time.Now().Truncate(0).Round(time.Millisecond),
+		ResourceId:   id,
+		ResourceType: ResourceTypeVersion,
+		EventType:    VersionExternalGateOpened,
+		Data: &VersionEventData{
+			ExternalGate: gate,
+			User:         user,
+		},
+	}
+
+	logger := NewDBEventLogger(AllLogCollection)
+	if err := logger.LogEvent(&event); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"resource_type": ResourceTypeVersion,
+			"message":       "error logging event",
+			"source":        "event-log-fail",
+		}))
+	}
+}
+
+// LogVersionExternalGateTimedOutEvent logs that one of the version's external
+// gates timed out before it was opened, so the tasks it held were deactivated.
+func LogVersionExternalGateTimedOutEvent(id, gate string) {
+	event := EventLogEntry{
+		Timestamp:    time.Now().Truncate(0).Round(time.Millisecond),
+		ResourceId:   id,
+		ResourceType: ResourceTypeVersion,
+		EventType:    VersionExternalGateTimedOut,
+		Data: &VersionEventData{
+			ExternalGate: gate,
+		},
+	}
+
+	logger := NewDBEventLogger(AllLogCollection)
+	if err := logger.LogEvent(&event); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"resource_type": ResourceTypeVersion,
+			"message":       "error logging event",
+			"source":        "event-log-fail",
+		}))
+	}
+}
+
+// LogVersionTasksBlockedEvent logs that the version's tasks were blocked by a
+// task that they depend on. All the tasks blocked at once are logged together
+// so that they can be notified about together.
+func LogVersionTasksBlockedEvent(id string, blocked []BlockedTask) {
+	event := EventLogEntry{
+		Timestamp:    time.Now().Truncate(0).Round(time.Millisecond),
+		ResourceId:   id,
+		ResourceType: ResourceTypeVersion,
+		EventType:    VersionTasksBlocked,
+		Data: &VersionEventData{
+			BlockedTasks: blocked,
+		},
+	}
+
+	logger := NewDBEventLogger(AllLogCollection)
+	if err := logger.LogEvent(&event); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"resource_type": ResourceTypeVersion,
+			"message":       "error logging event",
+			"source":        "event-log-fail",
+		}))
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+)
+
+// ValidateOverridableExpansions checks that the names of the expansions that
+// a project allows versions to override are valid.
+func ValidateOverridableExpansions(names []string) error {
+	catcher := grip.NewBasicCatcher()
+	seen := map[string]bool{}
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			catcher.New("overridable expansion name cannot be empty")
+			continue
+		}
+		catcher.ErrorfWhen(strings.ContainsAny(name, " \t\n"), "overridable expansion name '%s' cannot contain whitespace", name)
+		catcher.ErrorfWhen(seen[name], "overridable expansion '%s' is listed more than once", name)
+		seen[name] = true
+	}
+	return catcher.Resolve()
+}
+
+// ValidateExpansionOverrides checks that every expansion overridden when
+// creating a version or patch is one that the project allows to be
+// overridden.
+func (p *ProjectRef) ValidateExpansionOverrides(overrides map[string]string) error {
+	catcher := grip.NewBasicCatcher()
+	for key := range overrides {
+		if key == "" {
+			catcher.New("overridden expansion name cannot be empty")
+			continue
+		}
+		catcher.ErrorfWhen(!utility.StringSliceContains(p.OverridableExpansions, key), "project '%s' does not allow expansion '%s' to be overridden", p.Identifier, key)
+	}
+	return catcher.Resolve()
+}
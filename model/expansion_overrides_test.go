@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOverridableExpansions(t *testing.T) {
+	assert.NoError(t, ValidateOverridableExpansions(nil))
+	assert.NoError(t, ValidateOverridableExpansions([]string{"compiler", "test_flags"}))
+	assert.Error(t, ValidateOverridableExpansions([]string{""}))
+	assert.Error(t, ValidateOverridableExpansions([]string{"test flags"}))
+	assert.Error(t, ValidateOverridableExpansions([]string{"compiler", "compiler"}))
+}
+
+func TestValidateExpansionOverrides(t *testing.T) {
+	pRef := ProjectRef{Identifier: "mci", OverridableExpansions: []string{"compiler"}}
+	assert.NoError(t, pRef.ValidateExpansionOverrides(nil))
+	assert.NoError(t, pRef.ValidateExpansionOverrides(map[string]string{"compiler": "clang"}))
+	assert.Error(t, pRef.ValidateExpansionOverrides(map[string]string{"compiler": "clang", "test_flags": "-v"}))
+	assert.Error(t, pRef.ValidateExpansionOverrides(map[string]string{"": "clang"}))
+
+	pRef.OverridableExpansions = nil
+	assert.Error(t, pRef.ValidateExpansionOverrides(map[string]string{"compiler": "clang"}))
+}
@@ -0,0 +1,172 @@
+package model
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	// failureSignatureVolatileRegex matches the parts of a failure's log
+	// signature that vary between otherwise identical failures, such as
+	// hashes, addresses, durations, and line numbers.
+	failureSignatureVolatileRegex = regexp.MustCompile(`(?i)\b([0-9]+\.[0-9]+[a-z]*|0x[0-9a-f]+|[0-9a-f]*[0-9][0-9a-f]*)\b`)
+	failureSignatureSpaceRegex    = regexp.MustCompile(`\s+`)
+)
+
+// ComputeFailureFingerprint returns a fingerprint of how the task failed,
+// based on the names of its failing local test results, the type of failure,
+// and the log signature from the agent's end task details. Tasks that failed
+// with the same fingerprint likely failed for the same reason. Tasks that
+// didn't fail have no fingerprint; ending a task without a status fails it.
+func ComputeFailureFingerprint(t *task.Task, detail *apimodels.TaskEndDetail) string {
+	if !isFailureDetail(detail) {
+		return ""
+	}
+	return failureFingerprint(detail, t.LocalTestResults)
+}
+
+// NeedsCedarFailureFingerprint returns whether the ended task's failing tests
+// are only stored in Cedar, in which case its failure fingerprint is computed
+// after the task ends by UpdateCedarFailureFingerprint rather than while
+// ending the task.
+func NeedsCedarFailureFingerprint(t *task.Task) bool {
+	return isFailureDetail(&t.Details) && len(t.LocalTestResults) == 0 && t.HasCedarResults
+}
+
+// UpdateCedarFailureFingerprint computes the failure fingerprint of the ended
+// task from its failing tests in Cedar and saves it.
+func UpdateCedarFailureFingerprint(ctx context.Context, t *task.Task) error {
+	results, err := t.GetCedarFailedTestResults(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting failed test results from Cedar")
+	}
+	fingerprint := failureFingerprint(&t.Details, results)
+	err = task.UpdateOne(
+		bson.M{
+			task.IdKey:        t.Id,
+			task.ExecutionKey: t.Execution,
+		},
+		bson.M{"$set": bson.M{task.FailureFingerprintKey: fingerprint}},
+	)
+	if err != nil {
+		return errors.Wrap(err, "saving failure fingerprint")
+	}
+	t.FailureFingerprint = fingerprint
+	return nil
+}
+
+func isFailureDetail(detail *apimodels.TaskEndDetail) bool {
+	return detail.Status == "" || detail.Status == evergreen.TaskFailed
+}
+
+func failureFingerprint(detail *apimodels.TaskEndDetail, results []task.TestResult) string {
+	failedTests := []string{}
+	for _, result := range results {
+		if result.Status != evergreen.TestFailedStatus {
+			continue
+		}
+		name := result.DisplayTestName
+		if name == "" {
+			name = result.TestFile
+		}
+		failedTests = append(failedTests, name)
+	}
+	sort.Strings(failedTests)
+
+	failureType := detail.Type
+	if failureType == "" {
+		failureType = evergreen.CommandTypeTest
+	}
+	timeout := ""
+	if detail.TimedOut {
+		timeout = detail.TimeoutType
+		if timeout == "" {
+			timeout = "timeout"
+		}
+	}
+
+	h := sha256.New()
+	for _, part := range []string{
+		failureType,
+		timeout,
+		normalizeFailureSignature(detail.Description),
+		strings.Join(failedTests, "\n"),
+	} {
+		_, _ = h.Write([]byte(part))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// normalizeFailureSignature strips the parts of a failure's log signature
+// that vary between otherwise identical failures.
+func normalizeFailureSignature(signature string) string {
+	signature = failureSignatureVolatileRegex.ReplaceAllString(signature, "#")
+	signature = failureSignatureSpaceRegex.ReplaceAllString(signature, " ")
+	return strings.ToLower(strings.TrimSpace(signature))
+}
+
+// FailureGroup is a set of failed tasks in a version that have the same
+// failure fingerprint.
+type FailureGroup struct {
+	Fingerprint string
+	// Type and Description are the failure type and log signature of the
+	// first task in the group.
+	Type        string
+	Description string
+	Tasks       []task.Task
+}
+
+// GetVersionFailureGroups groups the version's failed tasks by their failure
+// fingerprint, from the largest group.
+func GetVersionFailureGroups(versionID string) ([]FailureGroup, error) {
+	tasks, err := task.FindWithFields(bson.M{
+		task.VersionKey:            versionID,
+		task.StatusKey:             evergreen.TaskFailed,
+		task.FailureFingerprintKey: bson.M{"$exists": true},
+	}, task.IdKey, task.ExecutionKey, task.DisplayNameKey, task.BuildVariantKey, task.DetailsKey, task.FailureFingerprintKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding failed tasks for version '%s'", versionID)
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].BuildVariant != tasks[j].BuildVariant {
+			return tasks[i].BuildVariant < tasks[j].BuildVariant
+		}
+		return tasks[i].DisplayName < tasks[j].DisplayName
+	})
+
+	groupsByFingerprint := map[string]*FailureGroup{}
+	fingerprints := []string{}
+	for _, t := range tasks {
+		group, ok := groupsByFingerprint[t.FailureFingerprint]
+		if !ok {
+			group = &FailureGroup{
+				Fingerprint: t.FailureFingerprint,
+				Type:        t.Details.Type,
+				Description: t.Details.Description,
+			}
+			groupsByFingerprint[t.FailureFingerprint] = group
+			fingerprints = append(fingerprints, t.FailureFingerprint)
+		}
+		group.Tasks = append(group.Tasks, t)
+	}
+
+	groups := make([]FailureGroup, 0, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		groups = append(groups, *groupsByFingerprint[fingerprint])
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].Tasks) > len(groups[j].Tasks)
+	})
+	return groups, nil
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeFailureFingerprint(t *testing.T) {
+	failedTask := func(tests ...string) *task.Task {
+		tsk := &task.Task{Id: "t"}
+		for _, name := range tests {
+			tsk.LocalTestResults = append(tsk.LocalTestResults, task.TestResult{TestFile: name, Status: evergreen.TestFailedStatus})
+		}
+		tsk.LocalTestResults = append(tsk.LocalTestResults, task.TestResult{TestFile: "passing", Status: evergreen.TestSucceededStatus})
+		return tsk
+	}
+
+	detail := &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest, Description: "exit code 1 at 0xdeadbeef"}
+	fingerprint := ComputeFailureFingerprint(failedTask("a", "b"), detail)
+	assert.NotEmpty(t, fingerprint)
+
+	t.Run("IgnoresTestOrderAndVolatileSignature", func(t *testing.T) {
+		other := &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest, Description: "exit code 2 at 0xcafe1234"}
+		assert.Equal(t, fingerprint, ComputeFailureFingerprint(failedTask("b", "a"), other))
+	})
+	t.Run("DiffersByFailingTests", func(t *testing.T) {
+		assert.NotEqual(t, fingerprint, ComputeFailureFingerprint(failedTask("a"), detail))
+	})
+	t.Run("DiffersByFailureType", func(t *testing.T) {
+		other := *detail
+		other.Type = evergreen.CommandTypeSystem
+		assert.NotEqual(t, fingerprint, ComputeFailureFingerprint(failedTask("a", "b"), &other))
+	})
+	t.Run("DiffersByTimeout", func(t *testing.T) {
+		other := *detail
+		other.TimedOut = true
+		assert.NotEqual(t, fingerprint, ComputeFailureFingerprint(failedTask("a", "b"), &other))
+	})
+	t.Run("EmptyForNonFailedTask", func(t *testing.T) {
+		assert.Empty(t, ComputeFailureFingerprint(&task.Task{}, &apimodels.TaskEndDetail{Status: evergreen.TaskSucceeded}))
+	})
+}
+
+func TestNeedsCedarFailureFingerprint(t *testing.T) {
+	failed := apimodels.TaskEndDetail{Status: evergreen.TaskFailed}
+	assert.True(t, NeedsCedarFailureFingerprint(&task.Task{HasCedarResults: true, Details: failed}))
+	assert.False(t, NeedsCedarFailureFingerprint(&task.Task{Details: failed}))
+	assert.False(t, NeedsCedarFailureFingerprint(&task.Task{
+		HasCedarResults:  true,
+		Details:          failed,
+		LocalTestResults: []task.TestResult{{TestFile: "a", Status: evergreen.TestFailedStatus}},
+	}))
+	assert.False(t, NeedsCedarFailureFingerprint(&task.Task{HasCedarResults: true, Details: apimodels.TaskEndDetail{Status: evergreen.TaskSucceeded}}))
+}
+
+func TestGetVersionFailureGroups(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection))
+	}()
+	require.NoError(t, db.ClearCollections(task.Collection))
+
+	tasks := []task.Task{
+		{Id: "t1", Version: "v", BuildVariant: "bv1", DisplayName: "t1", Status: evergreen.TaskFailed, FailureFingerprint: "f1"},
+		{Id: "t2", Version: "v", BuildVariant: "bv2", DisplayName: "t2", Status: evergreen.TaskFailed, FailureFingerprint: "f2"},
+		{Id: "t3", Version: "v", BuildVariant: "bv1", DisplayName: "t3", Status: evergreen.TaskFailed, FailureFingerprint: "f2"},
+		{Id: "t4", Version: "v", BuildVariant: "bv1", DisplayName: "t4", Status: evergreen.TaskSucceeded},
+		{Id: "t5", Version: "other", BuildVariant: "bv1", DisplayName: "t5", Status: evergreen.TaskFailed, FailureFingerprint: "f1"},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+	}
+
+	groups, err := GetVersionFailureGroups("v")
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "f2", groups[0].Fingerprint)
+	require.Len(t, groups[0].Tasks, 2)
+	assert.Equal(t, "t3", groups[0].Tasks[0].Id)
+	assert.Equal(t, "t2", groups[0].Tasks[1].Id)
+	assert.Equal(t, "f1", groups[1].Fingerprint)
+	require.Len(t, groups[1].Tasks, 1)
+	assert.Equal(t, "t1", groups[1].Tasks[0].Id)
+}
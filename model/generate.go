@@ -139,6 +139,9 @@ func (g *GeneratedProject) NewVersion(p *Project, pp *ParserProject, v *Version)
 		return nil, nil, nil, errors.Wrap(err, "creating config from generated config")
 	}
 	newPP.Id = v.Id
+	newPP.GeneratedTaskCount += len(g.Tasks)
+	newPP.GeneratedVariantCount += g.countNewVariants(cachedProject)
+	newPP.GeneratedConfigSize += g.Task.GeneratedJSONSize()
 	p, err = TranslateProject(newPP)
 	if err != nil {
 		return nil, nil, nil, errors.Wrap(err, TranslateProjectError)
@@ -310,6 +313,38 @@ func (g *GeneratedProject) CheckForCycles(v *Version, p *Project, projectRef *Pr
 	return nil
 }
 
+// CheckScheduledTaskQuota returns an error if the generated tasks would put the
+// project over its scheduled task quota.
+func (g *GeneratedProject) CheckScheduledTaskQuota(p *Project, projectRef *ProjectRef) error {
+	newTVPairs := TaskVariantPairs{}
+	for _, bv := range g.BuildVariants {
+		newTVPairs = appendTasks(newTVPairs, bv, p)
+	}
+	return errors.Wrap(CheckScheduledTaskQuota(projectRef, len(newTVPairs.ExecTasks)), "checking project quotas for generated tasks")
+}
+
+// CheckVersionLimits returns an error if the generators in the version have
+// together generated more tasks, variants, or config than the limits allow.
+func CheckVersionLimits(pp *ParserProject, projectID string, limits evergreen.GenerateTasksLimitsConfig) error {
+	if pp == nil || !limits.AppliesToProject(projectID) {
+		return nil
+	}
+	catcher := grip.NewBasicCatcher()
+	if maxTasks := limits.GetMaxTasksPerVersion(); pp.GeneratedTaskCount > maxTasks {
+		catcher.Errorf("version '%s' would have %d generated tasks, which exceeds the limit of %d generated tasks per version", pp.Id, pp.GeneratedTaskCount, maxTasks)
+	}
+	if maxVariants := limits.GetMaxVariantsPerVersion(); pp.GeneratedVariantCount > maxVariants {
+		catcher.Errorf("version '%s' would have %d generated build variants, which exceeds the limit of %d generated build variants per version", pp.Id, pp.GeneratedVariantCount, maxVariants)
+	}
+	if maxSize := limits.GetMaxConfigSizePerVersion(); pp.GeneratedConfigSize > maxSize {
+		catcher.Errorf("version '%s' would have %d bytes of generated config, which exceeds the limit of %d bytes per version", pp.Id, pp.GeneratedConfigSize, maxSize)
+	}
+	if catcher.HasErrors() {
+		catcher.New("reduce the output of 'generate.tasks' or ask an Evergreen admin to raise the limits for this project")
+	}
+	return catcher.Resolve()
+}
+
 // simulateNewTasks adds the tasks we're planning to add to the version to the graph and
 // adds simulated edges from each task that depends on the generator to each of the generated tasks.
 func (g *GeneratedProject) simulateNewTasks(graph task.DependencyGraph, v *Version, p *Project, projectRef *ProjectRef) (task.DependencyGraph, error) {
@@ -635,6 +670,18 @@ func (g *GeneratedProject) validateNoRedefine(cachedProject projectMaps) error {
 	return catcher.Resolve()
 }
 
+// countNewVariants returns the number of build variants in the GeneratedProject
+// that are not already defined in the project.
+func (g *GeneratedProject) countNewVariants(cachedProject projectMaps) int {
+	count := 0
+	for _, bv := range g.BuildVariants {
+		if _, ok := cachedProject.buildVariants[bv.Name]; !ok {
+			count++
+		}
+	}
+	return count
+}
+
 func isNonZeroBV(bv parserBV) bool {
 	if bv.DisplayName != "" || len(bv.Expansions) > 0 || len(bv.Modules) > 0 ||
 		bv.Disabled || len(bv.Tags) > 0 || bv.Push ||
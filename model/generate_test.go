@@ -435,6 +435,49 @@ func (s *GenerateSuite) TestValidateMaxTasks() {
 	s.Error(g.validateMaxTasksAndVariants())
 }
 
+func (s *GenerateSuite) TestCountNewVariants() {
+	p := &Project{}
+	_, err := LoadProjectInto(context.Background(), []byte(sampleProjYml), nil, "", p)
+	s.Require().NoError(err)
+	g := sampleGeneratedProject
+	s.Equal(2, g.countNewVariants(cacheProjectData(p)))
+}
+
+func (s *GenerateSuite) TestCheckVersionLimits() {
+	limits := evergreen.GenerateTasksLimitsConfig{
+		MaxTasksPerVersion:        2,
+		MaxVariantsPerVersion:     1,
+		MaxConfigSizeMBPerVersion: 1,
+	}
+	pp := &ParserProject{
+		Id:                    "v1",
+		GeneratedTaskCount:    2,
+		GeneratedVariantCount: 1,
+		GeneratedConfigSize:   1024 * 1024,
+	}
+	s.NoError(CheckVersionLimits(pp, "proj", limits))
+
+	pp.GeneratedTaskCount = 3
+	err := CheckVersionLimits(pp, "proj", limits)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "3 generated tasks")
+	s.NotContains(err.Error(), "build variants")
+
+	pp.GeneratedVariantCount = 2
+	pp.GeneratedConfigSize = 1024*1024 + 1
+	err = CheckVersionLimits(pp, "proj", limits)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "2 generated build variants")
+	s.Contains(err.Error(), "bytes of generated config")
+
+	limits.ExemptProjects = []string{"proj"}
+	s.NoError(CheckVersionLimits(pp, "proj", limits))
+	s.Error(CheckVersionLimits(pp, "other", limits))
+
+	limits.Disabled = true
+	s.NoError(CheckVersionLimits(pp, "other", limits))
+}
+
 func (s *GenerateSuite) TestValidateNoRedefine() {
 	g := GeneratedProject{}
 	s.NoError(g.validateNoRedefine(projectMaps{}))
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// GithubMergeGroup is a group of pull requests that GitHub's merge queue is
+// testing together before merging them into the base branch.
+type GithubMergeGroup struct {
+	// HeadSHA is the commit that merges the group's pull requests into the
+	// base branch, which is the commit that's tested.
+	HeadSHA string
+	HeadRef string
+	BaseSHA string
+	BaseRef string
+}
+
+// Branch returns the name of the branch that the merge group merges into.
+func (g GithubMergeGroup) Branch() string {
+	return strings.TrimPrefix(g.BaseRef, "refs/heads/")
+}
+
+// ValidateGithubMergeQueue checks that the project doesn't use both GitHub's
+// merge queue and the Evergreen commit queue, since only one of them can gate
+// merging into the branch.
+func ValidateGithubMergeQueue(pRef *ProjectRef) error {
+	if pRef.IsGithubMergeQueueEnabled() && pRef.CommitQueue.IsEnabled() {
+		return errors.New("cannot enable both the GitHub merge queue and the commit queue")
+	}
+	return nil
+}
+
+// FindProjectRefsForGithubMergeGroup returns the enabled projects that use
+// GitHub's merge queue for the branch.
+func FindProjectRefsForGithubMergeGroup(owner, repo, branch string) ([]ProjectRef, error) {
+	pRefs, err := FindMergedEnabledProjectRefsByRepoAndBranch(owner, repo, branch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project refs for repo '%s/%s' with branch '%s'", owner, repo, branch)
+	}
+	mergeQueueRefs := []ProjectRef{}
+	for _, pRef := range pRefs {
+		if pRef.IsGithubMergeQueueEnabled() {
+			mergeQueueRefs = append(mergeQueueRefs, pRef)
+		}
+	}
+	return mergeQueueRefs, nil
+}
+
+// GithubMergeGroupVersionID returns the ID of the project's version that tests
+// the merge group, so that the same merge group is only tested once.
+func GithubMergeGroupVersionID(projectIdentifier, headSHA string) string {
+	return util.CleanName(fmt.Sprintf("%s_merge_queue_%s", projectIdentifier, headSHA))
+}
+
+// AbortGithubMergeGroupVersion deactivates and aborts the tasks of the version
+// that tests a merge group that GitHub removed from the merge queue, if the
+// version hasn't already finished.
+func AbortGithubMergeGroupVersion(versionID string) error {
+	v, err := VersionFindOneId(versionID)
+	if err != nil {
+		return errors.Wrapf(err, "finding merge queue version '%s'", versionID)
+	}
+	if v == nil || evergreen.IsFinishedVersionStatus(v.Status) {
+		return nil
+	}
+	if err = SetVersionActivation(v.Id, false, evergreen.GithubMergeQueueUser); err != nil {
+		return errors.Wrapf(err, "deactivating merge queue version '%s'", v.Id)
+	}
+	return errors.Wrapf(task.AbortVersion(v.Id, task.AbortInfo{User: evergreen.GithubMergeQueueUser}),
+		"aborting tasks in merge queue version '%s'", v.Id)
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGithubMergeGroupBranch(t *testing.T) {
+	assert.Equal(t, "main", GithubMergeGroup{BaseRef: "refs/heads/main"}.Branch())
+	assert.Equal(t, "release/1.0", GithubMergeGroup{BaseRef: "refs/heads/release/1.0"}.Branch())
+}
+
+func TestValidateGithubMergeQueue(t *testing.T) {
+	assert.NoError(t, ValidateGithubMergeQueue(&ProjectRef{GithubMergeQueueEnabled: utility.TruePtr()}))
+	assert.NoError(t, ValidateGithubMergeQueue(&ProjectRef{CommitQueue: CommitQueueParams{Enabled: utility.TruePtr()}}))
+	assert.Error(t, ValidateGithubMergeQueue(&ProjectRef{
+		GithubMergeQueueEnabled: utility.TruePtr(),
+		CommitQueue:             CommitQueueParams{Enabled: utility.TruePtr()},
+	}))
+}
+
+func TestFindProjectRefsForGithubMergeGroup(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ProjectRefCollection, RepoRefCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection, RepoRefCollection))
+	}()
+
+	for _, pRef := range []ProjectRef{
+		{Id: "merge_queue", Identifier: "merge_queue", Owner: "evergreen-ci", Repo: "evergreen", Branch: "main", Enabled: utility.TruePtr(), GithubMergeQueueEnabled: utility.TruePtr()},
+		{Id: "other_branch", Identifier: "other_branch", Owner: "evergreen-ci", Repo: "evergreen", Branch: "release", Enabled: utility.TruePtr(), GithubMergeQueueEnabled: utility.TruePtr()},
+		{Id: "disabled", Identifier: "disabled", Owner: "evergreen-ci", Repo: "evergreen", Branch: "main", Enabled: utility.FalsePtr(), GithubMergeQueueEnabled: utility.TruePtr()},
+		{Id: "commit_queue", Identifier: "commit_queue", Owner: "evergreen-ci", Repo: "evergreen", Branch: "main", Enabled: utility.TruePtr(), CommitQueue: CommitQueueParams{Enabled: utility.TruePtr()}},
+	} {
+		require.NoError(t, pRef.Insert())
+	}
+
+	pRefs, err := FindProjectRefsForGithubMergeGroup("evergreen-ci", "evergreen", "main")
+	require.NoError(t, err)
+	require.Len(t, pRefs, 1)
+	assert.Equal(t, "merge_queue", pRefs[0].Id)
+}
+
+func TestAbortGithubMergeGroupVersion(t *testing.T) {
+	require.NoError(t, db.ClearCollections(VersionCollection, build.Collection, task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection, build.Collection, task.Collection))
+	}()
+
+	v := &Version{
+		Id:        GithubMergeGroupVersionID("proj", "abcdef"),
+		Requester: evergreen.GithubMergeQueueRequester,
+		Status:    evergreen.VersionStarted,
+		BuildIds:  []string{"b"},
+	}
+	require.NoError(t, v.Insert())
+	b := &build.Build{Id: "b", Version: v.Id, Activated: true}
+	require.NoError(t, b.Insert())
+	running := &task.Task{Id: "running", Version: v.Id, BuildId: b.Id, Status: evergreen.TaskStarted, Activated: true}
+	require.NoError(t, running.Insert())
+	waiting := &task.Task{Id: "waiting", Version: v.Id, BuildId: b.Id, Status: evergreen.TaskUndispatched, Activated: true}
+	require.NoError(t, waiting.Insert())
+
+	require.NoError(t, AbortGithubMergeGroupVersion(v.Id))
+
+	dbRunning, err := task.FindOneId(running.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbRunning)
+	assert.True(t, dbRunning.Aborted)
+	assert.Equal(t, evergreen.GithubMergeQueueUser, dbRunning.AbortInfo.User)
+
+	dbWaiting, err := task.FindOneId(waiting.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbWaiting)
+	assert.False(t, dbWaiting.Activated)
+
+	assert.NoError(t, AbortGithubMergeGroupVersion("nonexistent"))
+}
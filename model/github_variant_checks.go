@@ -0,0 +1,167 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// DefaultGithubVariantCheckNameTemplate is the name of each variant's
+	// check if the project doesn't set its own template. It matches the
+	// context of the check that's posted when a variant finishes.
+	DefaultGithubVariantCheckNameTemplate = "evergreen/{variant}"
+
+	// githubVariantCheckWindow is how long after a build is created that its
+	// variant check is still posted.
+	githubVariantCheckWindow = 24 * time.Hour
+)
+
+var githubVariantCheckPlaceholderRegex = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// The placeholders that can appear in a variant check name template.
+const (
+	githubVariantCheckPlaceholderVariant            = "variant"
+	githubVariantCheckPlaceholderVariantDisplayName = "variant_display_name"
+	githubVariantCheckPlaceholderProject            = "project"
+)
+
+// GithubVariantCheckSettings control whether a project's mainline versions
+// post one GitHub check per build variant that's updated as the variant's
+// status changes, rather than only a check for the whole version and a check
+// for each variant once it finishes.
+type GithubVariantCheckSettings struct {
+	Enabled bool `bson:"enabled,omitempty" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// NameTemplate is the name of each variant's check. It can contain the
+	// placeholders {variant}, {variant_display_name}, and {project}.
+	NameTemplate string `bson:"name_template,omitempty" json:"name_template,omitempty" yaml:"name_template,omitempty"`
+}
+
+// Validate checks that the name template only uses known placeholders.
+func (s GithubVariantCheckSettings) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	for _, match := range githubVariantCheckPlaceholderRegex.FindAllStringSubmatch(s.NameTemplate, -1) {
+		switch match[1] {
+		case githubVariantCheckPlaceholderVariant, githubVariantCheckPlaceholderVariantDisplayName, githubVariantCheckPlaceholderProject:
+		default:
+			catcher.Errorf("unrecognized placeholder '%s' in check name template", match[0])
+		}
+	}
+	return catcher.Resolve()
+}
+
+// GetNameTemplate returns the name template for each variant's check.
+func (s GithubVariantCheckSettings) GetNameTemplate() string {
+	if strings.TrimSpace(s.NameTemplate) == "" {
+		return DefaultGithubVariantCheckNameTemplate
+	}
+	return s.NameTemplate
+}
+
+// CheckName returns the name of the build's variant check.
+func (s GithubVariantCheckSettings) CheckName(b *build.Build, projectIdentifier string) string {
+	return strings.NewReplacer(
+		"{"+githubVariantCheckPlaceholderVariant+"}", b.BuildVariant,
+		"{"+githubVariantCheckPlaceholderVariantDisplayName+"}", b.DisplayName,
+		"{"+githubVariantCheckPlaceholderProject+"}", projectIdentifier,
+	).Replace(s.GetNameTemplate())
+}
+
+var githubVariantCheckSettingsEnabledKey = bsonutil.MustHaveTag(GithubVariantCheckSettings{}, "Enabled")
+
+// GithubVariantCheck is the GitHub check for one variant of a mainline
+// version whose status has changed since it was last posted.
+type GithubVariantCheck struct {
+	Build *build.Build
+	Owner string
+	Repo  string
+	Name  string
+	// State is the GitHub status state of the check.
+	State message.GithubState
+	// Description summarizes the variant's status.
+	Description string
+}
+
+// githubVariantCheckStateAndDescription returns the GitHub status state and
+// the description for the build's github check status.
+func githubVariantCheckStateAndDescription(b *build.Build) (message.GithubState, string) {
+	switch b.GithubCheckStatus {
+	case evergreen.BuildSucceeded:
+		return message.GithubStateSuccess, "variant succeeded"
+	case evergreen.BuildFailed:
+		return message.GithubStateFailure, "variant failed"
+	case evergreen.BuildStarted:
+		return message.GithubStatePending, "variant is running"
+	default:
+		return message.GithubStatePending, "variant is waiting to start"
+	}
+}
+
+// FindUnpostedGithubVariantChecks returns up to limit variant checks for
+// projects that post one check per variant whose status has changed since
+// they were last posted, from the oldest build.
+func FindUnpostedGithubVariantChecks(limit int) ([]GithubVariantCheck, error) {
+	pRefs, err := FindProjectRefsWithGithubVariantChecks()
+	if err != nil {
+		return nil, errors.Wrap(err, "finding projects that post variant checks")
+	}
+	if len(pRefs) == 0 {
+		return nil, nil
+	}
+	projects := map[string]ProjectRef{}
+	projectIDs := make([]string, 0, len(pRefs))
+	for _, pRef := range pRefs {
+		projects[pRef.Id] = pRef
+		projectIDs = append(projectIDs, pRef.Id)
+	}
+
+	q := build.ByUnpostedGithubChecks(projectIDs, time.Now().Add(-githubVariantCheckWindow)).
+		Sort([]string{build.CreateTimeKey}).
+		Limit(limit)
+	builds, err := build.Find(q)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding builds with unposted variant checks")
+	}
+
+	checks := make([]GithubVariantCheck, 0, len(builds))
+	for i := range builds {
+		b := &builds[i]
+		pRef := projects[b.Project]
+		state, description := githubVariantCheckStateAndDescription(b)
+		checks = append(checks, GithubVariantCheck{
+			Build:       b,
+			Owner:       pRef.Owner,
+			Repo:        pRef.Repo,
+			Name:        pRef.GithubVariantChecks.CheckName(b, pRef.Identifier),
+			State:       state,
+			Description: description,
+		})
+	}
+	return checks, nil
+}
+
+// FindProjectRefsWithGithubVariantChecks returns the enabled projects with
+// GitHub checks that post one check per variant.
+func FindProjectRefsWithGithubVariantChecks() ([]ProjectRef, error) {
+	pRefs, err := FindProjectRefsQ(bson.M{
+		bsonutil.GetDottedKeyName(projectRefGithubVariantChecksKey, githubVariantCheckSettingsEnabledKey): true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "finding project refs")
+	}
+	enabled := []ProjectRef{}
+	for _, pRef := range pRefs {
+		if pRef.IsEnabled() && pRef.IsGithubChecksEnabled() {
+			enabled = append(enabled, pRef)
+		}
+	}
+	return enabled, nil
+}
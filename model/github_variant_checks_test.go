@@ -0,0 +1,147 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGithubVariantCheckSettings(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		assert.NoError(t, GithubVariantCheckSettings{}.Validate())
+		assert.NoError(t, GithubVariantCheckSettings{NameTemplate: "ci/{project}/{variant} ({variant_display_name})"}.Validate())
+		assert.Error(t, GithubVariantCheckSettings{NameTemplate: "ci/{variant}/{task}"}.Validate())
+		assert.Error(t, GithubVariantCheckSettings{NameTemplate: "ci/{}"}.Validate())
+	})
+	t.Run("CheckName", func(t *testing.T) {
+		b := &build.Build{BuildVariant: "ubuntu", DisplayName: "Ubuntu 22.04"}
+		assert.Equal(t, "evergreen/ubuntu", GithubVariantCheckSettings{}.CheckName(b, "mci"))
+		assert.Equal(t, "mci: Ubuntu 22.04", GithubVariantCheckSettings{NameTemplate: "{project}: {variant_display_name}"}.CheckName(b, "mci"))
+	})
+}
+
+func TestFindUnpostedGithubVariantChecks(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection, build.Collection))
+	}()
+	require.NoError(t, db.ClearCollections(ProjectRefCollection, build.Collection))
+
+	pRef := ProjectRef{
+		Id:                  "project",
+		Identifier:          "mci",
+		Owner:               "evergreen-ci",
+		Repo:                "evergreen",
+		Enabled:             utility.TruePtr(),
+		GithubChecksEnabled: utility.TruePtr(),
+		GithubVariantChecks: GithubVariantCheckSettings{Enabled: true, NameTemplate: "ci/{variant}"},
+	}
+	require.NoError(t, pRef.Insert())
+	otherRef := ProjectRef{
+		Id:                  "other_project",
+		Enabled:             utility.TruePtr(),
+		GithubChecksEnabled: utility.TruePtr(),
+	}
+	require.NoError(t, otherRef.Insert())
+
+	now := time.Now()
+	builds := []build.Build{
+		{
+			Id:                "started",
+			Project:           pRef.Id,
+			BuildVariant:      "bv1",
+			Requester:         evergreen.RepotrackerVersionRequester,
+			IsGithubCheck:     true,
+			CreateTime:        now.Add(-time.Hour),
+			GithubCheckStatus: evergreen.BuildStarted,
+		},
+		{
+			Id:                      "failed_after_posted_started",
+			Project:                 pRef.Id,
+			BuildVariant:            "bv2",
+			Requester:               evergreen.RepotrackerVersionRequester,
+			IsGithubCheck:           true,
+			CreateTime:              now.Add(-30 * time.Minute),
+			GithubCheckStatus:       evergreen.BuildFailed,
+			GithubCheckPostedStatus: evergreen.BuildStarted,
+		},
+		{
+			Id:                      "already_posted",
+			Project:                 pRef.Id,
+			Requester:               evergreen.RepotrackerVersionRequester,
+			IsGithubCheck:           true,
+			CreateTime:              now,
+			GithubCheckStatus:       evergreen.BuildSucceeded,
+			GithubCheckPostedStatus: evergreen.BuildSucceeded,
+		},
+		{
+			Id:                "not_github_check",
+			Project:           pRef.Id,
+			Requester:         evergreen.RepotrackerVersionRequester,
+			CreateTime:        now,
+			GithubCheckStatus: evergreen.BuildStarted,
+		},
+		{
+			Id:                "patch",
+			Project:           pRef.Id,
+			Requester:         evergreen.PatchVersionRequester,
+			IsGithubCheck:     true,
+			CreateTime:        now,
+			GithubCheckStatus: evergreen.BuildStarted,
+		},
+		{
+			Id:                "too_old",
+			Project:           pRef.Id,
+			Requester:         evergreen.RepotrackerVersionRequester,
+			IsGithubCheck:     true,
+			CreateTime:        now.Add(-2 * githubVariantCheckWindow),
+			GithubCheckStatus: evergreen.BuildStarted,
+		},
+		{
+			Id:                "variant_checks_disabled",
+			Project:           otherRef.Id,
+			Requester:         evergreen.RepotrackerVersionRequester,
+			IsGithubCheck:     true,
+			CreateTime:        now,
+			GithubCheckStatus: evergreen.BuildStarted,
+		},
+	}
+	for _, b := range builds {
+		require.NoError(t, b.Insert())
+	}
+
+	checks, err := FindUnpostedGithubVariantChecks(10)
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+	assert.Equal(t, "started", checks[0].Build.Id)
+	assert.Equal(t, "ci/bv1", checks[0].Name)
+	assert.Equal(t, "evergreen-ci", checks[0].Owner)
+	assert.Equal(t, "evergreen", checks[0].Repo)
+	assert.Equal(t, message.GithubStatePending, checks[0].State)
+	assert.Equal(t, "failed_after_posted_started", checks[1].Build.Id)
+	assert.Equal(t, message.GithubStateFailure, checks[1].State)
+
+	checks, err = FindUnpostedGithubVariantChecks(1)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+
+	require.NoError(t, checks[0].Build.SetGithubCheckPostedStatus(checks[0].Build.GithubCheckStatus))
+	checks, err = FindUnpostedGithubVariantChecks(10)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	assert.Equal(t, "failed_after_posted_started", checks[0].Build.Id)
+
+	// A status that changed after it was found is not recorded as posted.
+	require.NoError(t, checks[0].Build.UpdateGithubCheckStatus(evergreen.BuildSucceeded))
+	require.NoError(t, checks[0].Build.SetGithubCheckPostedStatus(evergreen.BuildFailed))
+	checks, err = FindUnpostedGithubVariantChecks(10)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	assert.Equal(t, message.GithubStateSuccess, checks[0].State)
+}
@@ -55,30 +55,90 @@ type VersionToRestart struct {
 // SetVersionActivation updates the "active" state of all builds and tasks associated with a
 // version to the given setting. It also updates the task cache for all builds affected.
 func SetVersionActivation(versionId string, active bool, caller string) error {
+	_, err := setVersionActivation(versionId, active, false, caller)
+	return err
+}
+
+// setVersionActivation is SetVersionActivation that also returns the builds
+// and tasks whose activation changes. If dryRun is true, nothing is written.
+func setVersionActivation(versionId string, active, dryRun bool, caller string) (AffectedEntities, error) {
+	affected := AffectedEntities{VersionIDs: []string{versionId}}
 	builds, err := build.Find(
 		build.ByVersion(versionId).WithFields(build.IdKey),
 	)
 	if err != nil {
-		return errors.Wrapf(err, "getting builds for version '%s'", versionId)
+		return affected, errors.Wrapf(err, "getting builds for version '%s'", versionId)
 	}
 	buildIDs := make([]string, 0, len(builds))
 	for _, build := range builds {
 		buildIDs = append(buildIDs, build.Id)
 	}
+	affected.BuildIDs = append(affected.BuildIDs, buildIDs...)
+
+	if dryRun {
+		var tasks []task.Task
+		if active {
+			tasks, err = findTasksToActivateForBuilds(buildIDs, false, nil)
+		} else {
+			tasks, err = findTasksToDeactivateForBuilds(buildIDs, caller)
+		}
+		if err != nil {
+			return affected, errors.Wrapf(err, "finding tasks in version '%s'", versionId)
+		}
+		affected.AddTasks(tasks...)
+		affected.sortIDs()
+		return affected, nil
+	}
+
+	// Builds whose activation hooks deny the activation are left deactivated
+	// while the rest of the version is activated.
+	var denied error
+	if active {
+		buildIDs, denied, err = checkVariantActivationHooks(context.Background(), buildIDs, caller)
+		if err != nil {
+			return affected, errors.Wrapf(err, "checking activation hooks for version '%s'", versionId)
+		}
+	}
 
 	// Update activation for all builds before updating their tasks so the version won't spend
 	// time in an intermediate state where only some builds are updated
 	if err = build.UpdateActivation(buildIDs, active, caller); err != nil {
-		return errors.Wrapf(err, "setting activation for builds in version '%s'", versionId)
+		return affected, errors.Wrapf(err, "setting activation for builds in version '%s'", versionId)
 	}
 
-	return errors.Wrapf(setTaskActivationForBuilds(buildIDs, active, false, nil, caller),
-		"setting activation for tasks in version '%s'", versionId)
+	if err = setTaskActivationForBuilds(buildIDs, active, false, nil, caller); err != nil {
+		return affected, errors.Wrapf(err, "setting activation for tasks in version '%s'", versionId)
+	}
+	return affected, denied
 }
 
 // SetBuildActivation updates the "active" state of this build and all associated tasks.
-// It also updates the task cache for the build document.
+// It also updates the task cache for the build document. A build is only
+// activated if its variant's activation hook, if any, allows it.
 func SetBuildActivation(buildId string, active bool, caller string) error {
+	if active {
+		_, denied, err := checkVariantActivationHooks(context.Background(), []string{buildId}, caller)
+		if err != nil {
+			return errors.Wrapf(err, "checking activation hook for build '%s'", buildId)
+		}
+		if denied != nil {
+			return denied
+		}
+	}
+	return setBuildActivation(buildId, active, caller)
+}
+
+// ActivateBuildBypassingHooks activates the build and all associated tasks
+// without evaluating its variant's activation hook. The bypass is recorded on
+// the build.
+func ActivateBuildBypassingHooks(ctx context.Context, pRef *ProjectRef, b *build.Build, caller string) error {
+	if err := CheckVariantActivationHook(ctx, pRef, b, caller, true); err != nil {
+		return errors.Wrapf(err, "recording activation hook bypass for build '%s'", b.Id)
+	}
+	return setBuildActivation(b.Id, true, caller)
+}
+
+func setBuildActivation(buildId string, active bool, caller string) error {
 	if err := build.UpdateActivation([]string{buildId}, active, caller); err != nil {
 		return errors.Wrapf(err, "setting build activation to %t for build '%s'", active, buildId)
 	}
@@ -94,42 +154,18 @@ func SetBuildActivation(buildId string, active bool, caller string) error {
 func setTaskActivationForBuilds(buildIds []string, active, withDependencies bool, ignoreTasks []string, caller string) error {
 	// If activating a task, set the ActivatedBy field to be the caller
 	if active {
-		q := bson.M{
-			task.BuildIdKey: bson.M{"$in": buildIds},
-			task.StatusKey:  evergreen.TaskUndispatched,
-		}
-		if len(ignoreTasks) > 0 {
-			q[task.IdKey] = bson.M{"$nin": ignoreTasks}
-		}
-		tasksToActivate, err := task.FindAll(db.Query(q).WithFields(task.IdKey, task.DependsOnKey, task.ExecutionKey))
+		tasksToActivate, err := findTasksToActivateForBuilds(buildIds, withDependencies, ignoreTasks)
 		if err != nil {
-			return errors.Wrap(err, "getting tasks to deactivate")
-		}
-		if withDependencies {
-			dependOn, err := task.GetRecursiveDependenciesUp(tasksToActivate, nil)
-			if err != nil {
-				return errors.Wrap(err, "getting recursive dependencies")
-			}
-			tasksToActivate = append(tasksToActivate, dependOn...)
-
+			return errors.WithStack(err)
 		}
 		if err = task.ActivateTasks(tasksToActivate, time.Now(), withDependencies, caller); err != nil {
 			return errors.Wrap(err, "updating tasks for activation")
 		}
 
 	} else {
-		query := bson.M{
-			task.BuildIdKey: bson.M{"$in": buildIds},
-			task.StatusKey:  evergreen.TaskUndispatched,
-		}
-		// if the caller is the default task activator only deactivate tasks that have not been activated by a user
-		if evergreen.IsSystemActivator(caller) {
-			query[task.ActivatedByKey] = bson.M{"$in": evergreen.SystemActivators}
-		}
-
-		tasks, err := task.FindAll(db.Query(query).WithFields(task.IdKey, task.ExecutionKey))
+		tasks, err := findTasksToDeactivateForBuilds(buildIds, caller)
 		if err != nil {
-			return errors.Wrap(err, "getting tasks to deactivate")
+			return errors.WithStack(err)
 		}
 		if err = task.DeactivateTasks(tasks, withDependencies, caller); err != nil {
 			return errors.Wrap(err, "deactivating tasks")
@@ -143,6 +179,49 @@ func setTaskActivationForBuilds(buildIds []string, active, withDependencies bool
 	return nil
 }
 
+// findTasksToActivateForBuilds returns the tasks in the builds that
+// setTaskActivationForBuilds activates.
+func findTasksToActivateForBuilds(buildIds []string, withDependencies bool, ignoreTasks []string) ([]task.Task, error) {
+	q := bson.M{
+		task.BuildIdKey: bson.M{"$in": buildIds},
+		task.StatusKey:  evergreen.TaskUndispatched,
+	}
+	if len(ignoreTasks) > 0 {
+		q[task.IdKey] = bson.M{"$nin": ignoreTasks}
+	}
+	tasksToActivate, err := task.FindAll(db.Query(q).WithFields(task.IdKey, task.DependsOnKey, task.ExecutionKey, task.BuildIdKey, task.VersionKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "getting tasks to activate")
+	}
+	if withDependencies {
+		dependOn, err := task.GetRecursiveDependenciesUp(tasksToActivate, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting recursive dependencies")
+		}
+		tasksToActivate = append(tasksToActivate, dependOn...)
+	}
+	return tasksToActivate, nil
+}
+
+// findTasksToDeactivateForBuilds returns the tasks in the builds that
+// setTaskActivationForBuilds deactivates.
+func findTasksToDeactivateForBuilds(buildIds []string, caller string) ([]task.Task, error) {
+	query := bson.M{
+		task.BuildIdKey: bson.M{"$in": buildIds},
+		task.StatusKey:  evergreen.TaskUndispatched,
+	}
+	// if the caller is the default task activator only deactivate tasks that have not been activated by a user
+	if evergreen.IsSystemActivator(caller) {
+		query[task.ActivatedByKey] = bson.M{"$in": evergreen.SystemActivators}
+	}
+
+	tasks, err := task.FindAll(db.Query(query).WithFields(task.IdKey, task.ExecutionKey, task.BuildIdKey, task.VersionKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "getting tasks to deactivate")
+	}
+	return tasks, nil
+}
+
 // AbortBuild marks the build as deactivated and sets the abort flag on all tasks associated
 // with the build which are in an abortable state.
 func AbortBuild(buildId string, caller string) error {
@@ -278,37 +357,66 @@ func SetVersionPriority(versionId string, priority int64, caller string) error {
 // If abortInProgress is true, it also sets the abort flag on any in-progress tasks. In addition, it
 // updates all builds containing the tasks affected.
 func RestartTasksInVersion(versionId string, abortInProgress bool, caller string) error {
+	_, err := restartTasksInVersion(versionId, abortInProgress, false, caller)
+	return err
+}
+
+func restartTasksInVersion(versionId string, abortInProgress, dryRun bool, caller string) (AffectedEntities, error) {
 	tasks, err := task.Find(task.ByVersion(versionId))
 	if err != nil {
-		return errors.Wrap(err, "error finding tasks in version")
+		return AffectedEntities{}, errors.Wrap(err, "error finding tasks in version")
 	}
 	if tasks == nil {
-		return errors.New("no tasks found for version")
+		return AffectedEntities{}, errors.New("no tasks found for version")
 	}
 	var taskIds []string
 	for _, task := range tasks {
 		taskIds = append(taskIds, task.Id)
 	}
 
-	toRestart := VersionToRestart{VersionId: &versionId, TaskIds: taskIds}
-	return RestartVersions([]*VersionToRestart{&toRestart}, abortInProgress, caller)
+	affected, err := restartVersion(versionId, taskIds, abortInProgress, dryRun, caller)
+	return affected, errors.Wrapf(err, "restarting tasks for version '%s'", versionId)
 }
 
 // RestartVersion restarts completed tasks associated with a versionId.
 // If abortInProgress is true, it also sets the abort flag on any in-progress tasks.
 func RestartVersion(versionId string, taskIds []string, abortInProgress bool, caller string) error {
+	_, err := restartVersion(versionId, taskIds, abortInProgress, false, caller)
+	return err
+}
+
+// restartVersion is RestartVersion that also returns the tasks that are
+// aborted or restarted, along with their builds and versions. If dryRun is
+// true, nothing is written.
+func restartVersion(versionId string, taskIds []string, abortInProgress, dryRun bool, caller string) (AffectedEntities, error) {
+	affected := AffectedEntities{}
+	if patch.IsValidId(versionId) {
+		p, err := patch.FindOneId(versionId)
+		if err != nil {
+			return affected, errors.Wrapf(err, "finding patch '%s'", versionId)
+		}
+		if p != nil && p.IsArchived() {
+			return affected, errors.Errorf("patch '%s' was archived as stale and can't be restarted", versionId)
+		}
+	}
 	if abortInProgress {
-		if err := task.AbortTasksForVersion(versionId, taskIds, caller); err != nil {
-			return errors.WithStack(err)
+		if dryRun {
+			toAbort, err := task.FindAbortableTasksForVersion(versionId, taskIds)
+			if err != nil {
+				return affected, errors.Wrap(err, "finding tasks to abort")
+			}
+			affected.AddTasks(toAbort...)
+		} else if err := task.AbortTasksForVersion(versionId, taskIds, caller); err != nil {
+			return affected, errors.WithStack(err)
 		}
 	}
 	finishedTasks, err := task.FindAll(db.Query(task.ByIdsAndStatus(taskIds, evergreen.TaskCompletedStatuses)))
 	if err != nil {
-		return errors.WithStack(err)
+		return affected, errors.WithStack(err)
 	}
 	allFinishedTasks, err := task.AddParentDisplayTasks(finishedTasks)
 	if err != nil {
-		return errors.WithStack(err)
+		return affected, errors.WithStack(err)
 	}
 	// remove execution tasks in case the caller passed both display and execution tasks
 	// the functions below are expected to work if just the display task is passed
@@ -319,15 +427,17 @@ func RestartVersion(versionId string, taskIds []string, abortInProgress bool, ca
 		}
 	}
 
-	// archive all the finished tasks
-	toArchive := []task.Task{}
-	for _, t := range allFinishedTasks {
-		if !t.IsPartOfSingleHostTaskGroup() { // for single host task groups we don't archive until fully restarting
-			toArchive = append(toArchive, t)
+	if !dryRun {
+		// archive all the finished tasks
+		toArchive := []task.Task{}
+		for _, t := range allFinishedTasks {
+			if !t.IsPartOfSingleHostTaskGroup() { // for single host task groups we don't archive until fully restarting
+				toArchive = append(toArchive, t)
+			}
+		}
+		if err = task.ArchiveMany(toArchive); err != nil {
+			return affected, errors.Wrap(err, "archiving tasks")
 		}
-	}
-	if err = task.ArchiveMany(toArchive); err != nil {
-		return errors.Wrap(err, "archiving tasks")
 	}
 
 	type taskGroupAndBuild struct {
@@ -335,9 +445,9 @@ func RestartVersion(versionId string, taskIds []string, abortInProgress bool, ca
 		TaskGroup string
 	}
 	// Mark aborted tasks to reset when finished if not all tasks are finished.
-	if abortInProgress && len(finishedTasks) < len(taskIds) {
+	if abortInProgress && len(finishedTasks) < len(taskIds) && !dryRun {
 		if err = task.SetAbortedTasksResetWhenFinished(taskIds); err != nil {
-			return err
+			return affected, err
 		}
 	}
 
@@ -348,8 +458,12 @@ func RestartVersion(versionId string, taskIds []string, abortInProgress bool, ca
 	restartIds := []string{}
 	for _, t := range tasksToRestart {
 		if t.IsPartOfSingleHostTaskGroup() {
+			affected.AddTasks(t)
+			if dryRun {
+				continue
+			}
 			if err = t.SetResetWhenFinished(); err != nil {
-				return errors.Wrapf(err, "marking '%s' for restart when finished", t.Id)
+				return affected, errors.Wrapf(err, "marking '%s' for restart when finished", t.Id)
 			}
 			taskGroupsToCheck[taskGroupAndBuild{
 				Build:     t.BuildId,
@@ -366,13 +480,19 @@ func RestartVersion(versionId string, taskIds []string, abortInProgress bool, ca
 
 	for tg, t := range taskGroupsToCheck {
 		if err = checkResetSingleHostTaskGroup(&t, caller); err != nil {
-			return errors.Wrapf(err, "resetting task group '%s' for build '%s'", tg.TaskGroup, tg.Build)
+			return affected, errors.Wrapf(err, "resetting task group '%s' for build '%s'", tg.TaskGroup, tg.Build)
 		}
 	}
 
 	// Set all the task fields to indicate restarted
-	if err = MarkTasksReset(restartIds); err != nil {
-		return errors.WithStack(err)
+	reset, err := MarkTasksReset(restartIds, dryRun)
+	affected.Merge(reset)
+	affected.sortIDs()
+	if err != nil {
+		return affected, errors.WithStack(err)
+	}
+	if dryRun {
+		return affected, nil
 	}
 	for _, t := range tasksToRestart {
 		if !t.IsPartOfSingleHostTaskGroup() { // this will be logged separately if task group is restarted
@@ -380,13 +500,13 @@ func RestartVersion(versionId string, taskIds []string, abortInProgress bool, ca
 		}
 	}
 	if err = build.SetBuildStartedForTasks(tasksToRestart, caller); err != nil {
-		return errors.Wrap(err, "setting builds started")
+		return affected, errors.Wrap(err, "setting builds started")
 	}
 	version, err := VersionFindOneId(versionId)
 	if err != nil {
-		return errors.Wrap(err, "finding version")
+		return affected, errors.Wrap(err, "finding version")
 	}
-	return errors.Wrap(version.UpdateStatus(evergreen.VersionStarted), "changing version status")
+	return affected, errors.Wrap(version.UpdateStatus(evergreen.VersionStarted), "changing version status")
 
 }
 
@@ -463,7 +583,7 @@ func restartTasksForBuild(buildId string, tasks []task.Task, caller string) erro
 		return errors.Wrap(err, "archiving tasks")
 	}
 	// Set all the task fields to indicate restarted
-	if err := MarkTasksReset(restartIds); err != nil {
+	if _, err := MarkTasksReset(restartIds, false); err != nil {
 		return errors.WithStack(err)
 	}
 	for _, t := range tasks {
@@ -525,7 +645,7 @@ func RefreshTasksCache(buildId string) error {
 // addTasksToBuild creates/activates the tasks for the given build of a project
 func addTasksToBuild(ctx context.Context, b *build.Build, project *Project, pRef *ProjectRef, v *Version, taskNames []string,
 	displayNames []string, activationInfo specificActivationInfo, generatedBy string, tasksInBuild []task.Task,
-	syncAtEndOpts patch.SyncAtEndOptions, distroAliases map[string][]string, taskIds TaskIdConfig) (*build.Build, task.Tasks, error) {
+	syncAtEndOpts patch.SyncAtEndOptions, distroAliases map[string][]string, queueLengths *RunOnQueueLengths, taskIds TaskIdConfig) (*build.Build, task.Tasks, error) {
 	// find the build variant for this project/build
 	buildVariant := project.FindBuildVariant(b.BuildVariant)
 	if buildVariant == nil {
@@ -550,7 +670,7 @@ func addTasksToBuild(ctx context.Context, b *build.Build, project *Project, pRef
 		}))
 	}
 	tasks, err := createTasksForBuild(project, pRef, buildVariant, b, v, taskIds, taskNames, displayNames, activationInfo,
-		generatedBy, tasksInBuild, syncAtEndOpts, distroAliases, createTime, githubCheckAliases)
+		generatedBy, tasksInBuild, syncAtEndOpts, distroAliases, queueLengths, createTime, githubCheckAliases)
 	if err != nil {
 		return nil, nil, errors.Wrapf(err, "creating tasks for build '%s'", b.Id)
 	}
@@ -624,6 +744,7 @@ type BuildCreateArgs struct {
 	DefinitionID        string                  // definition ID of the trigger used to create this build
 	Aliases             ProjectAliases          // project aliases to use to filter tasks created
 	DistroAliases       distro.AliasLookupTable // map of distro aliases to names of distros
+	RunOnQueueLengths   *RunOnQueueLengths      // task queue lengths of run_on distros, shared by the version's builds
 	TaskCreateTime      time.Time               // create time of tasks in the build
 	GithubChecksAliases ProjectAliases          // project aliases to use to filter tasks to count towards the github checks, if any
 	SyncAtEndOpts       patch.SyncAtEndOptions
@@ -687,11 +808,12 @@ func CreateBuildFromVersionNoInsert(args BuildCreateArgs) (*build.Build, task.Ta
 		TriggerEvent:        args.Version.TriggerEvent,
 		Tags:                buildVariant.Tags,
 	}
+	checkVariantActivationHookForNewBuild(context.Background(), &args.ProjectRef, b, args.Version.Author)
 
 	// create all of the necessary tasks for the build
 	tasksForBuild, err := createTasksForBuild(&args.Project, &args.ProjectRef, buildVariant, b, &args.Version, args.TaskIDs,
 		args.TaskNames, args.DisplayNames, args.ActivationInfo, args.GeneratedBy,
-		nil, args.SyncAtEndOpts, args.DistroAliases, args.TaskCreateTime, args.GithubChecksAliases)
+		nil, args.SyncAtEndOpts, args.DistroAliases, args.RunOnQueueLengths, args.TaskCreateTime, args.GithubChecksAliases)
 	if err != nil {
 		return nil, nil, errors.Wrapf(err, "creating tasks for build '%s'", b.Id)
 	}
@@ -733,8 +855,8 @@ func CreateTasksFromGroup(in BuildVariantTaskUnit, proj *Project, requester stri
 // If tasksToActivate is nil, then all tasks will be activated.
 func createTasksForBuild(project *Project, pRef *ProjectRef, buildVariant *BuildVariant, b *build.Build, v *Version,
 	taskIds TaskIdConfig, taskNames []string, displayNames []string, activationInfo specificActivationInfo, generatedBy string,
-	tasksInBuild []task.Task, syncAtEndOpts patch.SyncAtEndOptions, distroAliases map[string][]string, createTime time.Time,
-	githubChecksAliases ProjectAliases) (task.Tasks, error) {
+	tasksInBuild []task.Task, syncAtEndOpts patch.SyncAtEndOptions, distroAliases map[string][]string, queueLengths *RunOnQueueLengths,
+	createTime time.Time, githubChecksAliases ProjectAliases) (task.Tasks, error) {
 
 	// The list of tasks we should create.
 	// If tasks are passed in, then use those, otherwise use the default set.
@@ -800,7 +922,7 @@ func createTasksForBuild(project *Project, pRef *ProjectRef, buildVariant *Build
 	taskMap := make(map[string]*task.Task)
 	for _, t := range tasksToCreate {
 		id := execTable.GetId(b.BuildVariant, t.Name)
-		newTask, err := createOneTask(id, t, project, pRef, buildVariant, b, v, distroAliases, createTime, activationInfo, githubChecksAliases)
+		newTask, err := createOneTask(id, t, project, pRef, buildVariant, b, v, distroAliases, queueLengths, createTime, activationInfo, githubChecksAliases)
 		if err != nil {
 			return nil, errors.Wrapf(err, "creating task '%s'", id)
 		}
@@ -808,6 +930,11 @@ func createTasksForBuild(project *Project, pRef *ProjectRef, buildVariant *Build
 		// set Tags based on the spec
 		newTask.Tags = project.GetSpecForTask(t.Name).Tags
 		newTask.DependsOn = makeDeps(t, newTask, execTable)
+		crossVersionDeps, err := makeLatestMainlineDeps(t, newTask)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting cross-version dependencies for task '%s'", id)
+		}
+		newTask.DependsOn = append(newTask.DependsOn, crossVersionDeps...)
 		newTask.GeneratedBy = generatedBy
 		if generatorIsGithubCheck {
 			newTask.IsGithubCheck = true
@@ -866,6 +993,7 @@ func createTasksForBuild(project *Project, pRef *ProjectRef, buildVariant *Build
 					displayTaskActivated = true
 				}
 				taskMap[execTaskId].DisplayTaskId = utility.ToStringPtr(id)
+				taskMap[execTaskId].DispatchLongestFirst = dt.DispatchOrder == patch.DisplayTaskDispatchOrderLongestFirst
 			} else {
 				// exec task already exists so update its parent ID in the database
 				execTasksThatNeedParentId = append(execTasksThatNeedParentId, execTaskId)
@@ -926,6 +1054,10 @@ func createTasksForBuild(project *Project, pRef *ProjectRef, buildVariant *Build
 func makeDeps(t BuildVariantTaskUnit, thisTask *task.Task, taskIds TaskIdTable) []task.Dependency {
 	dependencySet := make(map[task.Dependency]bool)
 	for _, dep := range t.DependsOn {
+		// dependencies on other versions are resolved separately
+		if dep.LatestMainline {
+			continue
+		}
 		status := evergreen.TaskSucceeded
 		if dep.Status != "" {
 			status = dep.Status
@@ -959,7 +1091,7 @@ func makeDeps(t BuildVariantTaskUnit, thisTask *task.Task, taskIds TaskIdTable)
 			if id == thisTask.Id {
 				continue
 			}
-			dependencySet[task.Dependency{TaskId: id, Status: status}] = true
+			dependencySet[task.Dependency{TaskId: id, Status: status, OmitGeneratedTasks: dep.OmitGeneratedTasks}] = true
 		}
 	}
 
@@ -971,6 +1103,64 @@ func makeDeps(t BuildVariantTaskUnit, thisTask *task.Task, taskIds TaskIdTable)
 	return dependencies
 }
 
+// makeLatestMainlineDeps makes the dependencies on the most recent mainline
+// runs of tasks in other versions. A dependency on a task that has never run
+// in the mainline is skipped, since it could never be satisfied.
+func makeLatestMainlineDeps(t BuildVariantTaskUnit, thisTask *task.Task) ([]task.Dependency, error) {
+	var dependencies []task.Dependency
+	for _, dep := range t.DependsOn {
+		if !dep.LatestMainline {
+			continue
+		}
+		status := evergreen.TaskSucceeded
+		if dep.Status != "" {
+			status = dep.Status
+		}
+		if dep.Name == "" {
+			dep.Name = thisTask.DisplayName
+		}
+		if dep.Variant == "" {
+			dep.Variant = thisTask.BuildVariant
+		}
+		// the validator requires cross-version dependencies to name a
+		// specific task
+		if dep.Name == AllDependencies || dep.Variant == AllVariants {
+			continue
+		}
+
+		depTask, err := task.FindLatestMainlineTask(thisTask.Project, dep.Variant, dep.Name, thisTask.Version)
+		if err != nil {
+			return nil, err
+		}
+		if depTask == nil {
+			grip.Warning(message.Fields{
+				"message":     "skipping cross-version dependency on task with no mainline runs",
+				"task_id":     thisTask.Id,
+				"dep_task":    dep.Name,
+				"dep_variant": dep.Variant,
+				"project":     thisTask.Project,
+				"version":     thisTask.Version,
+			})
+			continue
+		}
+		newDep := task.Dependency{
+			TaskId:             depTask.Id,
+			Status:             status,
+			OmitGeneratedTasks: dep.OmitGeneratedTasks,
+			CrossVersion:       true,
+		}
+		// the dependency may have already finished, in which case nothing
+		// will mark it finished or unattainable for this task later
+		if depTask.IsFinished() || depTask.Blocked() {
+			depState := task.Task{DependsOn: []task.Dependency{newDep}}
+			newDep.Finished = depTask.IsFinished()
+			newDep.Unattainable = !depState.SatisfiesDependency(depTask)
+		}
+		dependencies = append(dependencies, newDep)
+	}
+	return dependencies, nil
+}
+
 // shouldSyncTask returns whether or not this task in this build variant should
 // sync its task directory.
 func shouldSyncTask(syncVariantsTasks []patch.VariantTasks, bv, task string) bool {
@@ -1144,7 +1334,7 @@ func getTaskCreateTime(projectId string, v *Version) (time.Time, error) {
 
 // createOneTask is a helper to create a single task.
 func createOneTask(id string, buildVarTask BuildVariantTaskUnit, project *Project, pRef *ProjectRef, buildVariant *BuildVariant,
-	b *build.Build, v *Version, dat distro.AliasLookupTable, createTime time.Time, activationInfo specificActivationInfo,
+	b *build.Build, v *Version, dat distro.AliasLookupTable, queueLengths *RunOnQueueLengths, createTime time.Time, activationInfo specificActivationInfo,
 	githubChecksAliases ProjectAliases) (*task.Task, error) {
 
 	activateTask := b.Activated && !activationInfo.taskHasSpecificActivation(b.BuildVariant, buildVarTask.Name)
@@ -1197,6 +1387,9 @@ func createOneTask(id string, buildVarTask BuildVariantTaskUnit, project *Projec
 		Version:                 v.Id,
 		Revision:                v.Revision,
 		MustHaveResults:         utility.FromBoolPtr(project.GetSpecForTask(buildVarTask.Name).MustHaveResults),
+		Compliance:              project.GetSpecForTask(buildVarTask.Name).Compliance,
+		ExpectedArtifacts:       project.GetSpecForTask(buildVarTask.Name).ExpectedArtifacts,
+		RequiredOutputs:         RequiredTaskOutputs(project.GetSpecForTask(buildVarTask.Name).Outputs),
 		Project:                 project.Identifier,
 		Priority:                buildVarTask.Priority,
 		GenerateTask:            project.IsGenerateTask(buildVarTask.Name),
@@ -1227,8 +1420,14 @@ func createOneTask(id string, buildVarTask BuildVariantTaskUnit, project *Projec
 			return nil, errors.Wrap(err, "getting container options")
 		}
 		t.ContainerOpts = *opts
+		if buildVarTask.ContainerFallback != nil {
+			t.ContainerFallback = &task.ContainerFallbackOptions{
+				DistroID: buildVarTask.ContainerFallback.Distro,
+				After:    buildVarTask.ContainerFallback.GetAfter(),
+			}
+		}
 	} else {
-		distroID, distroAliases, err := getDistrosFromRunOn(id, buildVarTask, buildVariant, project, v)
+		distroID, distroAliases, err := getDistrosFromRunOn(id, buildVarTask, buildVariant, project, v, queueLengths)
 		if err != nil {
 			return nil, err
 		}
@@ -1254,23 +1453,30 @@ func createOneTask(id string, buildVarTask BuildVariantTaskUnit, project *Projec
 	return t, nil
 }
 
-func getDistrosFromRunOn(id string, buildVarTask BuildVariantTaskUnit, buildVariant *BuildVariant, project *Project, v *Version) (string, []string, error) {
-	if len(buildVarTask.RunOn) > 0 {
-		distroAliases := []string{}
-		distroID := buildVarTask.RunOn[0]
-		if len(buildVarTask.RunOn) > 1 {
-			distroAliases = buildVarTask.RunOn[1:]
-		}
-		return distroID, distroAliases, nil
-	} else if len(buildVariant.RunOn) > 0 {
-		distroAliases := []string{}
-		distroID := buildVariant.RunOn[0]
-		if len(buildVariant.RunOn) > 1 {
-			distroAliases = buildVariant.RunOn[1:]
+// getDistrosFromRunOn returns the distro that the task runs on, selected from
+// its run_on distros according to its run_on strategy, and the other run_on
+// distros as its secondary distros.
+func getDistrosFromRunOn(id string, buildVarTask BuildVariantTaskUnit, buildVariant *BuildVariant, project *Project, v *Version,
+	queueLengths *RunOnQueueLengths) (string, []string, error) {
+	runOn := buildVarTask.RunOn
+	if len(runOn) == 0 {
+		runOn = buildVariant.RunOn
+	}
+	if len(runOn) == 0 {
+		return "", nil, errors.Errorf("task '%s' is not runnable as there is no distro specified", id)
+	}
+
+	idx, err := selectRunOnDistro(runOn, getRunOnStrategy(buildVarTask, buildVariant), buildVarTask.Name, v, queueLengths)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "selecting distro for task '%s'", id)
+	}
+	distroAliases := []string{}
+	for i, d := range runOn {
+		if i != idx {
+			distroAliases = append(distroAliases, d)
 		}
-		return distroID, distroAliases, nil
 	}
-	return "", nil, errors.Errorf("task '%s' is not runnable as there is no distro specified", id)
+	return runOn[idx], distroAliases, nil
 }
 
 func shouldRunOnContainer(taskRunOn, buildVariantRunOn []string, containers []Container) task.ExecutionPlatform {
@@ -1319,6 +1525,7 @@ func getContainerOptions(project *Project, pRef *ProjectRef, container string) (
 			OS:             c.System.OperatingSystem,
 			Arch:           c.System.CPUArchitecture,
 			WindowsVersion: c.System.WindowsVersion,
+			RepoCredsName:  c.Credential,
 		}
 
 		if c.Resources != nil {
@@ -1538,6 +1745,7 @@ func addNewBuilds(ctx context.Context, activationInfo specificActivationInfo, v
 	if err != nil {
 		return nil, errors.Wrap(err, "getting create time for tasks")
 	}
+	queueLengths := NewRunOnQueueLengths()
 	batchTimeCatcher := grip.NewBasicCatcher()
 	for _, pair := range tasks.ExecTasks {
 		if _, ok := variantsProcessed[pair.Variant]; ok { // skip variant that was already processed
@@ -1549,18 +1757,19 @@ func addNewBuilds(ctx context.Context, activationInfo specificActivationInfo, v
 		displayNames := tasks.DisplayTasks.TaskNames(pair.Variant)
 		activateVariant := !activationInfo.variantHasSpecificActivation(pair.Variant)
 		buildArgs := BuildCreateArgs{
-			Project:        *p,
-			ProjectRef:     *projectRef,
-			Version:        *v,
-			TaskIDs:        taskIdTables,
-			BuildName:      pair.Variant,
-			ActivateBuild:  activateVariant,
-			TaskNames:      taskNames,
-			DisplayNames:   displayNames,
-			ActivationInfo: activationInfo,
-			GeneratedBy:    generatedBy,
-			TaskCreateTime: createTime,
-			SyncAtEndOpts:  syncAtEndOpts,
+			Project:           *p,
+			ProjectRef:        *projectRef,
+			Version:           *v,
+			TaskIDs:           taskIdTables,
+			BuildName:         pair.Variant,
+			ActivateBuild:     activateVariant,
+			TaskNames:         taskNames,
+			DisplayNames:      displayNames,
+			ActivationInfo:    activationInfo,
+			GeneratedBy:       generatedBy,
+			TaskCreateTime:    createTime,
+			SyncAtEndOpts:     syncAtEndOpts,
+			RunOnQueueLengths: queueLengths,
 		}
 
 		grip.Info(message.Fields{
@@ -1660,6 +1869,7 @@ func addNewTasks(ctx context.Context, activationInfo specificActivationInfo, v *
 	if err != nil {
 		return nil, err
 	}
+	queueLengths := NewRunOnQueueLengths()
 
 	taskIdTables, err := getTaskIdTables(v, p, pairs, pRef.Identifier)
 	if err != nil {
@@ -1682,7 +1892,21 @@ func addNewTasks(ctx context.Context, activationInfo specificActivationInfo, v *
 		}
 		projectBV := p.FindBuildVariant(b.BuildVariant)
 		if projectBV != nil {
-			b.Activated = utility.FromBoolTPtr(projectBV.Activate) // activate unless explicitly set otherwise
+			activate := utility.FromBoolTPtr(projectBV.Activate) // activate unless explicitly set otherwise
+			if activate && !wasActivated {
+				err = CheckVariantActivationHook(ctx, pRef, &b, evergreen.DefaultTaskActivator, false)
+				if IsVariantActivationDenied(err) {
+					grip.Info(message.WrapError(err, message.Fields{
+						"message": "not activating build for new tasks",
+						"build":   b.Id,
+						"version": v.Id,
+					}))
+					activate = false
+				} else if err != nil {
+					return nil, err
+				}
+			}
+			b.Activated = activate
 		}
 
 		// Build a list of tasks that haven't been created yet for the given variant, but have
@@ -1706,7 +1930,7 @@ func addNewTasks(ctx context.Context, activationInfo specificActivationInfo, v *
 		}
 		// Add the new set of tasks to the build.
 		_, tasks, err := addTasksToBuild(ctx, &b, p, pRef, v, tasksToAdd, displayTasksToAdd, activationInfo,
-			generatedBy, tasksInBuild, syncAtEndOpts, distroAliases, taskIdTables)
+			generatedBy, tasksInBuild, syncAtEndOpts, distroAliases, queueLengths, taskIdTables)
 		if err != nil {
 			return nil, err
 		}
@@ -1655,6 +1655,65 @@ func TestMakeDeps(t *testing.T) {
 	})
 }
 
+func TestMakeLatestMainlineDeps(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection))
+	}()
+	for _, tsk := range []task.Task{
+		{Id: "old_mainline", Project: "p", Version: "v1", BuildVariant: "bv", DisplayName: "nightly", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 1, Activated: true},
+		{Id: "latest_mainline", Project: "p", Version: "v2", BuildVariant: "bv", DisplayName: "nightly", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 2, Activated: true, Status: evergreen.TaskSucceeded},
+		{Id: "patch", Project: "p", Version: "patch_version", BuildVariant: "bv", DisplayName: "nightly", Requester: evergreen.PatchVersionRequester, RevisionOrderNumber: 3, Activated: true},
+		{Id: "same_version", Project: "p", Version: "v3", BuildVariant: "bv", DisplayName: "nightly", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 3, Activated: true},
+		{Id: "inactive_mainline", Project: "p", Version: "v4", BuildVariant: "bv", DisplayName: "nightly", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 4},
+	} {
+		require.NoError(t, tsk.Insert())
+	}
+	thisTask := &task.Task{Id: "t", Project: "p", Version: "v3", BuildVariant: "bv", DisplayName: "consumer"}
+
+	t.Run("DependsOnLatestMainlineRunInAnotherVersion", func(t *testing.T) {
+		tSpec := BuildVariantTaskUnit{DependsOn: []TaskUnitDependency{
+			{Name: "nightly", LatestMainline: true, Status: evergreen.TaskFailed},
+		}}
+		deps, err := makeLatestMainlineDeps(tSpec, thisTask)
+		require.NoError(t, err)
+		require.Len(t, deps, 1)
+		assert.Equal(t, "latest_mainline", deps[0].TaskId)
+		assert.Equal(t, evergreen.TaskFailed, deps[0].Status)
+		assert.True(t, deps[0].CrossVersion)
+		assert.True(t, deps[0].Finished)
+		assert.True(t, deps[0].Unattainable)
+
+		assert.Empty(t, makeDeps(tSpec, thisTask, TaskIdTable{}))
+	})
+	t.Run("InitializesStateOfFinishedDependency", func(t *testing.T) {
+		tSpec := BuildVariantTaskUnit{DependsOn: []TaskUnitDependency{
+			{Name: "nightly", LatestMainline: true},
+		}}
+		deps, err := makeLatestMainlineDeps(tSpec, thisTask)
+		require.NoError(t, err)
+		require.Len(t, deps, 1)
+		assert.Equal(t, "latest_mainline", deps[0].TaskId)
+		assert.True(t, deps[0].Finished)
+		assert.False(t, deps[0].Unattainable)
+	})
+	t.Run("SkipsTaskWithoutMainlineRuns", func(t *testing.T) {
+		tSpec := BuildVariantTaskUnit{DependsOn: []TaskUnitDependency{
+			{Name: "never_ran", LatestMainline: true},
+			{Name: "nightly", Variant: "other_bv", LatestMainline: true},
+		}}
+		deps, err := makeLatestMainlineDeps(tSpec, thisTask)
+		require.NoError(t, err)
+		assert.Empty(t, deps)
+	})
+	t.Run("IgnoresSameVersionDependencies", func(t *testing.T) {
+		tSpec := BuildVariantTaskUnit{DependsOn: []TaskUnitDependency{{Name: "nightly"}}}
+		deps, err := makeLatestMainlineDeps(tSpec, thisTask)
+		require.NoError(t, err)
+		assert.Empty(t, deps)
+	})
+}
+
 func TestDeletingBuild(t *testing.T) {
 
 	Convey("With a build", t, func() {
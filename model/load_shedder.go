@@ -0,0 +1,146 @@
+package model
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// loadShedderSampleInterval is how often the load shedder samples the
+	// database latency and remote queue depth.
+	loadShedderSampleInterval = 15 * time.Second
+	// loadShedderSampleTimeout is how long the load shedder waits for a
+	// sample. A database that doesn't respond in time is considered to be
+	// under pressure.
+	loadShedderSampleTimeout = 5 * time.Second
+)
+
+// LoadStatus is a sample of how loaded the app server's dependencies are.
+type LoadStatus struct {
+	DBLatency  time.Duration
+	QueueDepth int
+	// Pressure is the ratio of the most loaded dependency's load to its
+	// threshold. The app server sheds load when the pressure is at least 1.
+	Pressure float64
+	// Backoff is how long agents should wait before polling the app server
+	// again. It grows with the pressure.
+	Backoff   time.Duration
+	SampledAt time.Time
+}
+
+// Shedding returns whether the app server is shedding load.
+func (s LoadStatus) Shedding() bool {
+	return s.Pressure >= 1
+}
+
+// LoadShedder periodically samples the database latency and the remote queue
+// depth to decide whether the app server should shed load. Sampling happens
+// in the background so that callers never wait on an overloaded database.
+type LoadShedder struct {
+	mu       sync.RWMutex
+	status   LoadStatus
+	sampling bool
+}
+
+var globalLoadShedder = &LoadShedder{}
+
+// GetLoadShedder returns the app server's load shedder.
+func GetLoadShedder() *LoadShedder {
+	return globalLoadShedder
+}
+
+// Status returns the most recent load status. If it's stale, a new sample is
+// taken in the background.
+func (l *LoadShedder) Status(env evergreen.Environment) LoadStatus {
+	conf := env.Settings().LoadShedder
+	if conf.Disabled {
+		return LoadStatus{}
+	}
+
+	l.mu.Lock()
+	status := l.status
+	if !l.sampling && time.Since(status.SampledAt) > loadShedderSampleInterval {
+		l.sampling = true
+		go l.sample(env, conf)
+	}
+	l.mu.Unlock()
+
+	return status
+}
+
+// Backoff returns how long agents should wait before polling the app server
+// again. It's zero if the app server isn't shedding load.
+func (l *LoadShedder) Backoff(env evergreen.Environment) time.Duration {
+	return l.Status(env).Backoff
+}
+
+// ShouldDefer returns whether non-critical background work should be
+// deferred because the app server is shedding load.
+func (l *LoadShedder) ShouldDefer(env evergreen.Environment) bool {
+	return l.Status(env).Shedding()
+}
+
+func (l *LoadShedder) sample(env evergreen.Environment, conf evergreen.LoadShedderConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), loadShedderSampleTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := env.DB().RunCommand(ctx, bson.M{"ping": 1}).Err()
+	dbLatency := time.Since(start)
+	grip.Warning(message.WrapError(err, message.Fields{
+		"message": "load shedder could not ping the database",
+	}))
+	if err != nil {
+		dbLatency = loadShedderSampleTimeout
+	}
+	queueDepth := env.RemoteQueue().Stats(ctx).Pending
+
+	status := NewLoadStatus(conf, dbLatency, queueDepth, time.Now())
+
+	l.mu.Lock()
+	prev := l.status
+	l.status = status
+	l.sampling = false
+	l.mu.Unlock()
+
+	grip.InfoWhen(status.Shedding() || prev.Shedding(), message.Fields{
+		"message":       "load shedder sample",
+		"shedding":      status.Shedding(),
+		"was_shedding":  prev.Shedding(),
+		"db_latency_ms": status.DBLatency.Milliseconds(),
+		"queue_depth":   status.QueueDepth,
+		"pressure":      status.Pressure,
+		"backoff_secs":  status.Backoff.Seconds(),
+	})
+}
+
+// NewLoadStatus computes the load status from a sample of the database
+// latency and remote queue depth.
+func NewLoadStatus(conf evergreen.LoadShedderConfig, dbLatency time.Duration, queueDepth int, sampledAt time.Time) LoadStatus {
+	status := LoadStatus{
+		DBLatency:  dbLatency,
+		QueueDepth: queueDepth,
+		SampledAt:  sampledAt,
+	}
+	status.Pressure = math.Max(
+		float64(dbLatency)/float64(conf.GetDBLatencyThreshold()),
+		float64(queueDepth)/float64(conf.GetQueueDepthThreshold()),
+	)
+	if !status.Shedding() {
+		return status
+	}
+
+	backoff := time.Duration(float64(conf.GetMinBackoff()) * status.Pressure)
+	if maxBackoff := conf.GetMaxBackoff(); backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	status.Backoff = backoff
+	return status
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLoadStatus(t *testing.T) {
+	conf := evergreen.LoadShedderConfig{
+		DBLatencyThresholdMS: 100,
+		QueueDepthThreshold:  1000,
+		MinBackoffSecs:       10,
+		MaxBackoffSecs:       60,
+	}
+	now := time.Now()
+
+	t.Run("BelowThresholds", func(t *testing.T) {
+		status := NewLoadStatus(conf, 50*time.Millisecond, 500, now)
+		assert.False(t, status.Shedding())
+		assert.Zero(t, status.Backoff)
+		assert.Equal(t, 0.5, status.Pressure)
+		assert.Equal(t, now, status.SampledAt)
+	})
+	t.Run("DBLatencyOverThreshold", func(t *testing.T) {
+		status := NewLoadStatus(conf, 200*time.Millisecond, 0, now)
+		assert.True(t, status.Shedding())
+		assert.Equal(t, 20*time.Second, status.Backoff)
+	})
+	t.Run("QueueDepthOverThreshold", func(t *testing.T) {
+		status := NewLoadStatus(conf, 0, 3000, now)
+		assert.True(t, status.Shedding())
+		assert.Equal(t, 30*time.Second, status.Backoff)
+	})
+	t.Run("BackoffIsCapped", func(t *testing.T) {
+		status := NewLoadStatus(conf, 0, 100000, now)
+		assert.True(t, status.Shedding())
+		assert.Equal(t, time.Minute, status.Backoff)
+	})
+	t.Run("DefaultThresholds", func(t *testing.T) {
+		status := NewLoadStatus(evergreen.LoadShedderConfig{}, evergreen.DefaultLoadShedderDBLatencyThreshold, 0, now)
+		assert.True(t, status.Shedding())
+		assert.Equal(t, evergreen.DefaultLoadShedderMinBackoff, status.Backoff)
+	})
+}
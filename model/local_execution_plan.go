@@ -0,0 +1,220 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// Blocks that commands in a local execution plan can come from, in the order
+// that they run.
+const (
+	LocalPlanBlockPre           = "pre"
+	LocalPlanBlockSetupGroup    = "setup_group"
+	LocalPlanBlockSetupTask     = "setup_task"
+	LocalPlanBlockTask          = "task"
+	LocalPlanBlockTeardownTask  = "teardown_task"
+	LocalPlanBlockTeardownGroup = "teardown_group"
+	LocalPlanBlockPost          = "post"
+)
+
+var planExpansionRegex = regexp.MustCompile(`\$\{.*?\}`)
+
+// LocalExecutionPlan is a flattened, ordered list of the commands that make
+// up a task, which can be run outside of Evergreen (e.g. on a developer's
+// workstation).
+type LocalExecutionPlan struct {
+	Variant    string
+	Task       string
+	TaskGroup  string
+	Expansions map[string]string
+	Steps      []LocalExecutionStep
+	// UnresolvedExpansions are the expansions referenced by the plan that
+	// have no known value and no default. They must be provided locally,
+	// or set by a command (e.g. expansions.update) before they are used.
+	UnresolvedExpansions []string
+}
+
+// LocalExecutionStep is a single command in a local execution plan.
+type LocalExecutionStep struct {
+	Block       string
+	Function    string
+	Command     string
+	DisplayName string
+	Type        string
+	// Params are the command's parameters with all known expansions
+	// resolved.
+	Params               map[string]interface{}
+	UnresolvedExpansions []string
+}
+
+// CompileLocalExecutionPlan flattens the commands that the task runs on the
+// build variant into a local execution plan. Functions are expanded and
+// expansions are resolved using the build variant's expansions, the given
+// expansions (which take precedence) and the vars passed to functions.
+func (p *Project) CompileLocalExecutionPlan(variant, taskName string, expansions map[string]string) (*LocalExecutionPlan, error) {
+	bv := p.FindBuildVariant(variant)
+	if bv == nil {
+		return nil, errors.Errorf("build variant '%s' not found", variant)
+	}
+	bvt := p.FindTaskForVariant(taskName, variant)
+	if bvt == nil {
+		return nil, errors.Errorf("task '%s' does not run on build variant '%s'", taskName, variant)
+	}
+	pt := p.FindProjectTask(taskName)
+	if pt == nil {
+		return nil, errors.Errorf("task '%s' not found", taskName)
+	}
+
+	exp := util.NewExpansions(map[string]string{
+		"build_variant": variant,
+		"task_name":     taskName,
+	})
+	exp.Update(bv.GetExpansions())
+	exp.Update(expansions)
+
+	plan := &LocalExecutionPlan{
+		Variant:    variant,
+		Task:       taskName,
+		Expansions: exp.Map(),
+	}
+	var tg *TaskGroup
+	if bvt.Name != taskName {
+		tg = p.FindTaskGroup(bvt.Name)
+	}
+
+	type block struct {
+		name string
+		cmds *YAMLCommandSet
+	}
+	var blocks []block
+	if tg != nil {
+		plan.TaskGroup = tg.Name
+		blocks = []block{
+			{name: LocalPlanBlockSetupGroup, cmds: tg.SetupGroup},
+			{name: LocalPlanBlockSetupTask, cmds: tg.SetupTask},
+			{name: LocalPlanBlockTask, cmds: &YAMLCommandSet{MultiCommand: pt.Commands}},
+			{name: LocalPlanBlockTeardownTask, cmds: tg.TeardownTask},
+			{name: LocalPlanBlockTeardownGroup, cmds: tg.TeardownGroup},
+		}
+	} else {
+		blocks = []block{
+			{name: LocalPlanBlockPre, cmds: p.Pre},
+			{name: LocalPlanBlockTask, cmds: &YAMLCommandSet{MultiCommand: pt.Commands}},
+			{name: LocalPlanBlockPost, cmds: p.Post},
+		}
+	}
+
+	for _, b := range blocks {
+		if b.cmds == nil {
+			continue
+		}
+		for _, cmd := range b.cmds.List() {
+			if !cmd.RunOnVariant(variant) {
+				continue
+			}
+			if cmd.Function == "" {
+				plan.Steps = append(plan.Steps, newLocalExecutionStep(b.name, "", cmd, *exp))
+				continue
+			}
+
+			f, ok := p.Functions[cmd.Function]
+			if !ok || f == nil {
+				return nil, errors.Errorf("function '%s' not found", cmd.Function)
+			}
+			funcExp := util.NewExpansions(exp.Map())
+			funcExp.Update(cmd.Vars)
+			for _, funcCmd := range f.List() {
+				if !funcCmd.RunOnVariant(variant) {
+					continue
+				}
+				step := newLocalExecutionStep(b.name, cmd.Function, funcCmd, *funcExp)
+				if step.DisplayName == "" {
+					step.DisplayName = cmd.DisplayName
+				}
+				plan.Steps = append(plan.Steps, step)
+			}
+		}
+	}
+
+	unresolved := map[string]bool{}
+	for _, step := range plan.Steps {
+		for _, name := range step.UnresolvedExpansions {
+			unresolved[name] = true
+		}
+	}
+	for name := range unresolved {
+		plan.UnresolvedExpansions = append(plan.UnresolvedExpansions, name)
+	}
+	sort.Strings(plan.UnresolvedExpansions)
+
+	return plan, nil
+}
+
+func newLocalExecutionStep(block, function string, cmd PluginCommandConf, exp util.Expansions) LocalExecutionStep {
+	unresolved := map[string]bool{}
+	params, _ := expandKnownValues(cmd.Params, exp, unresolved).(map[string]interface{})
+	step := LocalExecutionStep{
+		Block:       block,
+		Function:    function,
+		Command:     cmd.Command,
+		DisplayName: cmd.DisplayName,
+		Type:        cmd.Type,
+		Params:      params,
+	}
+	for name := range unresolved {
+		step.UnresolvedExpansions = append(step.UnresolvedExpansions, name)
+	}
+	sort.Strings(step.UnresolvedExpansions)
+	return step
+}
+
+// expandKnownValues returns a copy of the value with every expansion that has
+// a value or a default resolved. Expansions that cannot be resolved are left
+// as they are and added to unresolved.
+func expandKnownValues(val interface{}, exp util.Expansions, unresolved map[string]bool) interface{} {
+	switch v := val.(type) {
+	case string:
+		return planExpansionRegex.ReplaceAllStringFunc(v, func(match string) string {
+			name := match[2 : len(match)-1]
+			if idx := strings.Index(name, "|"); idx != -1 {
+				if exp.Exists(name[:idx]) {
+					return exp.Get(name[:idx])
+				}
+				return name[idx+1:]
+			}
+			if exp.Exists(name) {
+				return exp.Get(name)
+			}
+			unresolved[name] = true
+			return match
+		})
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			out[key] = expandKnownValues(elem, exp, unresolved)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			keyString, ok := key.(string)
+			if !ok {
+				continue
+			}
+			out[keyString] = expandKnownValues(elem, exp, unresolved)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, elem := range v {
+			out = append(out, expandKnownValues(elem, exp, unresolved))
+		}
+		return out
+	default:
+		return val
+	}
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileLocalExecutionPlan(t *testing.T) {
+	p := &Project{
+		Pre: &YAMLCommandSet{SingleCommand: &PluginCommandConf{
+			Command: "shell.exec",
+			Params:  map[string]interface{}{"script": "echo ${project}"},
+		}},
+		Post: &YAMLCommandSet{SingleCommand: &PluginCommandConf{
+			Command:  "shell.exec",
+			Variants: []string{"other"},
+		}},
+		Functions: map[string]*YAMLCommandSet{
+			"compile": {MultiCommand: []PluginCommandConf{
+				{Command: "git.get_project", Params: map[string]interface{}{"directory": "${workdir}/src"}},
+				{Command: "shell.exec", Params: map[string]interface{}{
+					"script": "make ${target|all} ${flags}",
+					"env":    map[string]interface{}{"SECRET": "${secret}"},
+				}},
+			}},
+		},
+		Tasks: []ProjectTask{
+			{Name: "t1", Commands: []PluginCommandConf{
+				{Function: "compile", DisplayName: "compile it", Vars: map[string]string{"flags": "-j4"}},
+				{Command: "attach.results", Params: map[string]interface{}{"file_location": "${build_variant}.json"}},
+			}},
+			{Name: "t2", Commands: []PluginCommandConf{{Command: "shell.exec"}}},
+		},
+		TaskGroups: []TaskGroup{
+			{
+				Name:       "tg",
+				Tasks:      []string{"t2"},
+				SetupGroup: &YAMLCommandSet{SingleCommand: &PluginCommandConf{Command: "s3.get"}},
+			},
+		},
+		BuildVariants: []BuildVariant{
+			{
+				Name:       "bv",
+				Expansions: map[string]string{"workdir": "/data"},
+				Tasks:      []BuildVariantTaskUnit{{Name: "t1"}, {Name: "tg"}},
+			},
+		},
+	}
+
+	t.Run("FlattensFunctionsAndResolvesExpansions", func(t *testing.T) {
+		plan, err := p.CompileLocalExecutionPlan("bv", "t1", map[string]string{"project": "evg"})
+		require.NoError(t, err)
+		require.Len(t, plan.Steps, 4)
+
+		assert.Equal(t, LocalPlanBlockPre, plan.Steps[0].Block)
+		assert.Equal(t, "echo evg", plan.Steps[0].Params["script"])
+
+		assert.Equal(t, "compile", plan.Steps[1].Function)
+		assert.Equal(t, "compile it", plan.Steps[1].DisplayName)
+		assert.Equal(t, "/data/src", plan.Steps[1].Params["directory"])
+
+		assert.Equal(t, "make all -j4", plan.Steps[2].Params["script"])
+		assert.Equal(t, map[string]interface{}{"SECRET": "${secret}"}, plan.Steps[2].Params["env"])
+		assert.Equal(t, []string{"secret"}, plan.Steps[2].UnresolvedExpansions)
+
+		assert.Equal(t, LocalPlanBlockTask, plan.Steps[3].Block)
+		assert.Equal(t, "bv.json", plan.Steps[3].Params["file_location"])
+
+		assert.Equal(t, []string{"secret"}, plan.UnresolvedExpansions)
+	})
+	t.Run("UsesTaskGroupBlocks", func(t *testing.T) {
+		plan, err := p.CompileLocalExecutionPlan("bv", "t2", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "tg", plan.TaskGroup)
+		require.Len(t, plan.Steps, 2)
+		assert.Equal(t, LocalPlanBlockSetupGroup, plan.Steps[0].Block)
+		assert.Equal(t, "s3.get", plan.Steps[0].Command)
+		assert.Equal(t, LocalPlanBlockTask, plan.Steps[1].Block)
+	})
+	t.Run("ErrorsForTaskNotInVariant", func(t *testing.T) {
+		_, err := p.CompileLocalExecutionPlan("bv", "nonexistent", nil)
+		assert.Error(t, err)
+	})
+}
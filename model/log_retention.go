@@ -0,0 +1,303 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DefaultLogRetentionDays matches how long test logs are kept when a project
+// does not configure its own retention.
+const DefaultLogRetentionDays = 180
+
+// logStorageSampleSize is the number of recent tasks whose logs are counted
+// to estimate a project's total log storage.
+const logStorageSampleSize = 50
+
+// LogRetentionPolicy determines how long the task and test logs that
+// Evergreen stores are kept for a project's tasks. Logs sent to Cedar are
+// subject to Cedar's own retention. A zero value for a tier falls back to
+// the default.
+type LogRetentionPolicy struct {
+	// TaskLogDays is how long logs for tasks that succeeded are kept. It is
+	// also the default for the other tiers.
+	TaskLogDays int `bson:"task_log_days,omitempty" json:"task_log_days,omitempty" yaml:"task_log_days,omitempty"`
+	// FailedTaskLogDays is how long logs for failed tasks are kept, which is
+	// typically longer than for tasks that succeeded.
+	FailedTaskLogDays int `bson:"failed_task_log_days,omitempty" json:"failed_task_log_days,omitempty" yaml:"failed_task_log_days,omitempty"`
+	// SystemLogDays is how long system log messages are kept, which is
+	// typically shorter than the task's own output.
+	SystemLogDays int `bson:"system_log_days,omitempty" json:"system_log_days,omitempty" yaml:"system_log_days,omitempty"`
+}
+
+// IsSet returns whether any retention tier has been configured.
+func (p LogRetentionPolicy) IsSet() bool {
+	return p != LogRetentionPolicy{}
+}
+
+// Validate checks that the retention tiers are sensible.
+func (p LogRetentionPolicy) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(p.TaskLogDays < 0, "task log retention cannot be negative")
+	catcher.NewWhen(p.FailedTaskLogDays < 0, "failed task log retention cannot be negative")
+	catcher.NewWhen(p.SystemLogDays < 0, "system log retention cannot be negative")
+	return catcher.Resolve()
+}
+
+// GetLogRetentionPolicy returns the project's log retention policy with
+// defaults filled in for the tiers that are not set. Failed task logs and
+// system logs default to the retention for all task logs.
+func (p *ProjectRef) GetLogRetentionPolicy() LogRetentionPolicy {
+	policy := p.LogRetention
+	if policy.TaskLogDays == 0 {
+		policy.TaskLogDays = DefaultLogRetentionDays
+	}
+	if policy.FailedTaskLogDays == 0 {
+		policy.FailedTaskLogDays = policy.TaskLogDays
+	}
+	if policy.SystemLogDays == 0 {
+		policy.SystemLogDays = policy.TaskLogDays
+	}
+	return policy
+}
+
+// LogRetentionCleanupResult summarizes the logs removed for a project.
+type LogRetentionCleanupResult struct {
+	TaskLogs        int
+	TestLogs        int
+	SystemLogChunks int
+	TasksConsidered int
+}
+
+const (
+	LogRetentionCursorsCollection = "log_retention_cursors"
+
+	logRetentionTierTaskLogs       = "task_logs"
+	logRetentionTierFailedTaskLogs = "failed_task_logs"
+	logRetentionTierSystemLogs     = "system_logs"
+)
+
+// logRetentionCursor records the last task whose logs were removed for a
+// project's retention tier, so that each run continues where the previous
+// one stopped. Tasks are processed in order of finish time and then ID.
+type logRetentionCursor struct {
+	Id         string    `bson:"_id"`
+	FinishTime time.Time `bson:"finish_time"`
+	TaskId     string    `bson:"task_id"`
+}
+
+var (
+	logRetentionCursorFinishTimeKey = bsonutil.MustHaveTag(logRetentionCursor{}, "FinishTime")
+	logRetentionCursorTaskIdKey     = bsonutil.MustHaveTag(logRetentionCursor{}, "TaskId")
+)
+
+func logRetentionCursorId(projectId, tier string) string {
+	return fmt.Sprintf("%s.%s", projectId, tier)
+}
+
+func findLogRetentionCursor(id string) (*logRetentionCursor, error) {
+	cursor := &logRetentionCursor{}
+	err := db.FindOneQ(LogRetentionCursorsCollection, db.Query(bson.M{"_id": id}), cursor)
+	if adb.ResultsNotFound(err) {
+		return &logRetentionCursor{Id: id}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding log retention cursor '%s'", id)
+	}
+	return cursor, nil
+}
+
+func (c *logRetentionCursor) advance(t task.Task) error {
+	c.FinishTime = t.FinishTime
+	c.TaskId = t.Id
+	_, err := db.Upsert(LogRetentionCursorsCollection, bson.M{"_id": c.Id}, bson.M{"$set": bson.M{
+		logRetentionCursorFinishTimeKey: c.FinishTime,
+		logRetentionCursorTaskIdKey:     c.TaskId,
+	}})
+	return errors.Wrapf(err, "updating log retention cursor '%s'", c.Id)
+}
+
+// ApplyLogRetentionPolicy removes the logs for the project's tasks that
+// finished before the cutoff of each retention tier. Each tier pages through
+// the tasks from where the previous call stopped, pageSize tasks at a time,
+// until it reaches the cutoff or the context is done.
+func ApplyLogRetentionPolicy(ctx context.Context, env evergreen.Environment, pRef *ProjectRef, now time.Time, pageSize int) (LogRetentionCleanupResult, error) {
+	policy := pRef.GetLogRetentionPolicy()
+	res := LogRetentionCleanupResult{}
+	catcher := grip.NewBasicCatcher()
+
+	tiers := []struct {
+		name     string
+		days     int
+		statuses []string
+		system   bool
+	}{
+		{name: logRetentionTierTaskLogs, days: policy.TaskLogDays, statuses: []string{evergreen.TaskSucceeded}},
+		{name: logRetentionTierFailedTaskLogs, days: policy.FailedTaskLogDays, statuses: []string{evergreen.TaskFailed}},
+		{name: logRetentionTierSystemLogs, days: policy.SystemLogDays, statuses: evergreen.TaskCompletedStatuses, system: true},
+	}
+	for _, tier := range tiers {
+		cutoff := now.Add(-time.Duration(tier.days) * 24 * time.Hour)
+		cursor, err := findLogRetentionCursor(logRetentionCursorId(pRef.Id, tier.name))
+		if err != nil {
+			catcher.Add(err)
+			continue
+		}
+
+		for ctx.Err() == nil {
+			tasks, err := task.FindAll(db.Query(bson.M{
+				task.ProjectKey:    pRef.Id,
+				task.StatusKey:     bson.M{"$in": tier.statuses},
+				task.FinishTimeKey: bson.M{"$lt": cutoff},
+				"$or": []bson.M{
+					{task.FinishTimeKey: bson.M{"$gt": cursor.FinishTime}},
+					{
+						task.FinishTimeKey: cursor.FinishTime,
+						task.IdKey:         bson.M{"$gt": cursor.TaskId},
+					},
+				},
+			}).WithFields(task.IdKey, task.FinishTimeKey).Sort([]string{task.FinishTimeKey, task.IdKey}).Limit(pageSize))
+			if err != nil {
+				catcher.Wrapf(err, "finding tasks that finished before %s", cutoff)
+				break
+			}
+			if len(tasks) == 0 {
+				break
+			}
+			res.TasksConsidered += len(tasks)
+			taskIds := make([]string, 0, len(tasks))
+			for _, t := range tasks {
+				taskIds = append(taskIds, t.Id)
+			}
+
+			if err = removeLogsForRetention(ctx, env, taskIds, tier.system, &res); err != nil {
+				catcher.Wrapf(err, "removing logs for retention tier '%s'", tier.name)
+				break
+			}
+			if err = cursor.advance(tasks[len(tasks)-1]); err != nil {
+				catcher.Add(err)
+				break
+			}
+			if len(tasks) < pageSize {
+				break
+			}
+		}
+	}
+	catcher.Add(ctx.Err())
+
+	return res, catcher.Resolve()
+}
+
+// removeLogsForRetention removes the system log messages of the tasks if
+// system is set, or else all of the tasks' task and test logs.
+func removeLogsForRetention(ctx context.Context, env evergreen.Environment, taskIds []string, system bool, res *LogRetentionCleanupResult) error {
+	taskLogs := env.Client().Database(TaskLogDB).Collection(TaskLogCollection)
+	if system {
+		updateRes, err := taskLogs.UpdateMany(ctx,
+			bson.M{
+				TaskLogTaskIdKey: bson.M{"$in": taskIds},
+				bsonutil.GetDottedKeyName(TaskLogMessagesKey, LogMessageTypeKey): apimodels.SystemLogPrefix,
+			},
+			bson.M{"$pull": bson.M{TaskLogMessagesKey: bson.M{LogMessageTypeKey: apimodels.SystemLogPrefix}}},
+		)
+		if err != nil {
+			return errors.Wrap(err, "removing system logs")
+		}
+		res.SystemLogChunks += int(updateRes.ModifiedCount)
+		return nil
+	}
+
+	deleteRes, err := taskLogs.DeleteMany(ctx, bson.M{TaskLogTaskIdKey: bson.M{"$in": taskIds}})
+	if err != nil {
+		return errors.Wrap(err, "removing task logs")
+	}
+	res.TaskLogs += int(deleteRes.DeletedCount)
+	deleteRes, err = env.DB().Collection(TestLogCollection).DeleteMany(ctx, bson.M{TestLogTaskKey: bson.M{"$in": taskIds}})
+	if err != nil {
+		return errors.Wrap(err, "removing test logs")
+	}
+	res.TestLogs += int(deleteRes.DeletedCount)
+	return nil
+}
+
+// LogStorageEstimate is an estimate of how much space the logs for a
+// project's tasks take up, extrapolated from a sample of recent tasks.
+type LogStorageEstimate struct {
+	TasksInWindow int
+	SampledTasks  int
+	TaskLogBytes  int64
+	TestLogBytes  int64
+}
+
+// EstimateProjectLogStorage estimates the storage used by the logs of the
+// project's tasks that finished since the given time.
+func EstimateProjectLogStorage(ctx context.Context, env evergreen.Environment, projectId string, since time.Time) (*LogStorageEstimate, error) {
+	query := bson.M{
+		task.ProjectKey:    projectId,
+		task.FinishTimeKey: bson.M{"$gte": since},
+	}
+	numTasks, err := task.Count(db.Query(query))
+	if err != nil {
+		return nil, errors.Wrap(err, "counting tasks")
+	}
+	estimate := &LogStorageEstimate{TasksInWindow: numTasks}
+	if numTasks == 0 {
+		return estimate, nil
+	}
+
+	sample, err := task.FindAll(db.Query(query).WithFields(task.IdKey).Sort([]string{"-" + task.FinishTimeKey}).Limit(logStorageSampleSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding sample tasks")
+	}
+	estimate.SampledTasks = len(sample)
+	if len(sample) == 0 {
+		return estimate, nil
+	}
+	taskIds := make([]string, 0, len(sample))
+	for _, t := range sample {
+		taskIds = append(taskIds, t.Id)
+	}
+
+	numTaskLogs, err := env.Client().Database(TaskLogDB).Collection(TaskLogCollection).CountDocuments(ctx, bson.M{TaskLogTaskIdKey: bson.M{"$in": taskIds}})
+	if err != nil {
+		return nil, errors.Wrap(err, "counting task logs")
+	}
+	taskLogSize, err := averageDocumentSize(ctx, env, TaskLogDB, TaskLogCollection)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting task log size")
+	}
+	numTestLogs, err := env.DB().Collection(TestLogCollection).CountDocuments(ctx, bson.M{TestLogTaskKey: bson.M{"$in": taskIds}})
+	if err != nil {
+		return nil, errors.Wrap(err, "counting test logs")
+	}
+	testLogSize, err := averageDocumentSize(ctx, env, env.DB().Name(), TestLogCollection)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting test log size")
+	}
+
+	scale := float64(numTasks) / float64(len(sample))
+	estimate.TaskLogBytes = int64(float64(numTaskLogs) * taskLogSize * scale)
+	estimate.TestLogBytes = int64(float64(numTestLogs) * testLogSize * scale)
+	return estimate, nil
+}
+
+func averageDocumentSize(ctx context.Context, env evergreen.Environment, database, collection string) (float64, error) {
+	stats := struct {
+		AvgObjSize float64 `bson:"avgObjSize"`
+	}{}
+	res := env.Client().Database(database).RunCommand(ctx, bson.D{{Key: "collStats", Value: collection}})
+	if err := res.Decode(&stats); err != nil {
+		return 0, errors.Wrapf(err, "getting stats for collection '%s'", collection)
+	}
+	return stats.AvgObjSize, nil
+}
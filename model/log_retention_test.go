@@ -0,0 +1,123 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLogRetentionPolicy(t *testing.T) {
+	pRef := ProjectRef{}
+	assert.Equal(t, LogRetentionPolicy{
+		TaskLogDays:       DefaultLogRetentionDays,
+		FailedTaskLogDays: DefaultLogRetentionDays,
+		SystemLogDays:     DefaultLogRetentionDays,
+	}, pRef.GetLogRetentionPolicy())
+
+	pRef.LogRetention = LogRetentionPolicy{TaskLogDays: 30, SystemLogDays: 14}
+	assert.Equal(t, LogRetentionPolicy{
+		TaskLogDays:       30,
+		FailedTaskLogDays: 30,
+		SystemLogDays:     14,
+	}, pRef.GetLogRetentionPolicy())
+
+	assert.Error(t, LogRetentionPolicy{FailedTaskLogDays: -1}.Validate())
+}
+
+func TestApplyLogRetentionPolicy(t *testing.T) {
+	env := evergreen.GetEnvironment()
+	ctx, cancel := env.Context()
+	defer cancel()
+
+	require.NoError(t, db.ClearCollections(task.Collection, TestLogCollection, LogRetentionCursorsCollection))
+	require.NoError(t, cleanUpLogDB())
+
+	now := time.Now()
+	pRef := &ProjectRef{
+		Id:           "p1",
+		LogRetention: LogRetentionPolicy{TaskLogDays: 30, FailedTaskLogDays: 90, SystemLogDays: 14},
+	}
+	tasks := []task.Task{
+		{Id: "old_success", Project: "p1", Status: evergreen.TaskSucceeded, FinishTime: now.Add(-31 * 24 * time.Hour)},
+		{Id: "older_success", Project: "p1", Status: evergreen.TaskSucceeded, FinishTime: now.Add(-365 * 24 * time.Hour)},
+		{Id: "old_failure", Project: "p1", Status: evergreen.TaskFailed, FinishTime: now.Add(-31 * 24 * time.Hour)},
+		{Id: "recent_success", Project: "p1", Status: evergreen.TaskSucceeded, FinishTime: now.Add(-20 * 24 * time.Hour)},
+		{Id: "other_project", Project: "p2", Status: evergreen.TaskSucceeded, FinishTime: now.Add(-31 * 24 * time.Hour)},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+		taskLog := &TaskLog{
+			TaskId: tsk.Id,
+			Messages: []apimodels.LogMessage{
+				{Type: apimodels.SystemLogPrefix, Message: "system"},
+				{Type: apimodels.TaskLogPrefix, Message: "task"},
+			},
+		}
+		require.NoError(t, taskLog.Insert())
+		testLog := &TestLog{Name: "test", Task: tsk.Id}
+		require.NoError(t, testLog.Insert())
+	}
+
+	res, err := ApplyLogRetentionPolicy(ctx, env, pRef, now, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.TaskLogs)
+	assert.Equal(t, 2, res.TestLogs)
+	assert.Equal(t, 2, res.SystemLogChunks)
+
+	cursor, err := findLogRetentionCursor(logRetentionCursorId("p1", logRetentionTierTaskLogs))
+	require.NoError(t, err)
+	assert.Equal(t, "old_success", cursor.TaskId)
+
+	for _, taskId := range []string{"old_failure", "recent_success", "other_project"} {
+		logs, err := FindAllTaskLogs(taskId, 0)
+		require.NoError(t, err)
+		assert.Len(t, logs, 1, taskId)
+		testLog, err := FindOneTestLog("test", taskId, 0)
+		require.NoError(t, err)
+		assert.NotNil(t, testLog, taskId)
+	}
+	for _, taskId := range []string{"old_success", "older_success"} {
+		logs, err := FindAllTaskLogs(taskId, 0)
+		require.NoError(t, err)
+		assert.Empty(t, logs, taskId)
+	}
+
+	logs, err := FindAllTaskLogs("recent_success", 0)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Len(t, logs[0].Messages, 1)
+	assert.Equal(t, apimodels.TaskLogPrefix, logs[0].Messages[0].Type)
+
+	t.Run("ContinuesFromCursor", func(t *testing.T) {
+		newLog := &TaskLog{
+			TaskId:   "old_success",
+			Messages: []apimodels.LogMessage{{Type: apimodels.TaskLogPrefix, Message: "task"}},
+		}
+		require.NoError(t, newLog.Insert())
+		newTask := task.Task{Id: "new_old_success", Project: "p1", Status: evergreen.TaskSucceeded, FinishTime: now.Add(-30*24*time.Hour - time.Minute)}
+		require.NoError(t, newTask.Insert())
+		taskLog := &TaskLog{
+			TaskId:   newTask.Id,
+			Messages: []apimodels.LogMessage{{Type: apimodels.TaskLogPrefix, Message: "task"}},
+		}
+		require.NoError(t, taskLog.Insert())
+
+		res, err := ApplyLogRetentionPolicy(ctx, env, pRef, now, 100)
+		require.NoError(t, err)
+		assert.Equal(t, 1, res.TaskLogs, "only tasks after the cursor should be cleaned up")
+
+		logs, err := FindAllTaskLogs(newTask.Id, 0)
+		require.NoError(t, err)
+		assert.Empty(t, logs)
+		logs, err = FindAllTaskLogs("old_success", 0)
+		require.NoError(t, err)
+		assert.Len(t, logs, 1)
+	})
+
+}
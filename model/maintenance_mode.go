@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+)
+
+// DrainStatus is how far along the app servers are in draining running tasks
+// while in maintenance mode.
+type DrainStatus struct {
+	MaintenanceMode evergreen.MaintenanceModeConfig
+	// InFlightTasks is the number of tasks that agents have picked up but not
+	// yet finished.
+	InFlightTasks          int
+	InFlightTasksByProject map[string]int
+}
+
+// Drained returns whether maintenance mode is on and no tasks are still
+// running.
+func (s DrainStatus) Drained() bool {
+	return s.MaintenanceMode.Enabled && s.InFlightTasks == 0
+}
+
+// GetDrainStatus returns the current maintenance mode settings along with the
+// tasks that are still running.
+func GetDrainStatus(env evergreen.Environment) (*DrainStatus, error) {
+	status := &DrainStatus{}
+	if err := status.MaintenanceMode.Get(env); err != nil {
+		return nil, errors.Wrap(err, "getting maintenance mode settings")
+	}
+	byProject, err := task.CountInFlightTasksByProject()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	status.InFlightTasksByProject = byProject
+	for _, count := range byProject {
+		status.InFlightTasks += count
+	}
+	return status, nil
+}
+
+// SetMaintenanceMode turns maintenance mode on or off. While it's on, the app
+// servers stop dispatching new tasks and ask agents to retry after the given
+// number of seconds.
+func SetMaintenanceMode(env evergreen.Environment, enabled bool, retryAfterSecs int, caller string) error {
+	before := &evergreen.MaintenanceModeConfig{}
+	if err := before.Get(env); err != nil {
+		return errors.Wrap(err, "getting maintenance mode settings")
+	}
+	after := &evergreen.MaintenanceModeConfig{
+		Enabled:        enabled,
+		RetryAfterSecs: retryAfterSecs,
+		StartedAt:      before.StartedAt,
+	}
+	if enabled && !before.Enabled {
+		after.StartedAt = time.Now()
+	}
+	if err := after.ValidateAndDefault(); err != nil {
+		return errors.Wrap(err, "invalid maintenance mode settings")
+	}
+	if err := after.Set(); err != nil {
+		return errors.Wrap(err, "saving maintenance mode settings")
+	}
+	return errors.Wrap(event.LogAdminEvent(after.SectionId(), before, after, caller), "logging maintenance mode change")
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMaintenanceModeDrainStatus(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, evergreen.ConfigCollection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, evergreen.ConfigCollection, event.AllLogCollection))
+	}()
+	env := evergreen.GetEnvironment()
+
+	tasks := []task.Task{
+		{Id: "t1", Project: "p1", Status: evergreen.TaskStarted},
+		{Id: "t2", Project: "p1", Status: evergreen.TaskDispatched},
+		{Id: "t3", Project: "p2", Status: evergreen.TaskStarted},
+		{Id: "t4", Project: "p2", Status: evergreen.TaskSucceeded},
+		{Id: "t5", Project: "p3", Status: evergreen.TaskUndispatched},
+		{Id: "display", Project: "p2", Status: evergreen.TaskStarted, DisplayOnly: true},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+	}
+
+	status, err := GetDrainStatus(env)
+	require.NoError(t, err)
+	assert.False(t, status.MaintenanceMode.Enabled)
+	assert.False(t, status.Drained())
+	assert.Equal(t, 3, status.InFlightTasks)
+	assert.Equal(t, map[string]int{"p1": 2, "p2": 1}, status.InFlightTasksByProject)
+
+	require.NoError(t, SetMaintenanceMode(env, true, 10, "me"))
+	status, err = GetDrainStatus(env)
+	require.NoError(t, err)
+	assert.True(t, status.MaintenanceMode.Enabled)
+	assert.Equal(t, 10, status.MaintenanceMode.RetryAfterSecs)
+	startedAt := status.MaintenanceMode.StartedAt
+	assert.False(t, startedAt.IsZero())
+
+	// Changing the retry interval doesn't restart maintenance mode.
+	require.NoError(t, SetMaintenanceMode(env, true, 20, "me"))
+	status, err = GetDrainStatus(env)
+	require.NoError(t, err)
+	assert.Equal(t, 20, status.MaintenanceMode.RetryAfterSecs)
+	assert.True(t, startedAt.Equal(status.MaintenanceMode.StartedAt))
+
+	_, err = task.UpdateAll(bson.M{}, bson.M{"$set": bson.M{task.StatusKey: evergreen.TaskSucceeded}})
+	require.NoError(t, err)
+	status, err = GetDrainStatus(env)
+	require.NoError(t, err)
+	assert.True(t, status.Drained())
+	assert.Empty(t, status.InFlightTasksByProject)
+
+	assert.Error(t, SetMaintenanceMode(env, true, -1, "me"))
+}
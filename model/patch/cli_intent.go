@@ -45,6 +45,10 @@ type cliIntent struct {
 	// Parameters is a list of parameters to use with the task.
 	Parameters []Parameter `bson:"parameters,omitempty"`
 
+	// ExpansionOverrides override the value of the expansions for all of the
+	// patch's tasks.
+	ExpansionOverrides map[string]string `bson:"expansion_overrides,omitempty"`
+
 	// SyncAtEndOpts describe behavior for task sync at the end of the task.
 	SyncAtEndOpts SyncAtEndOptions `bson:"sync_at_end_opts,omitempty"`
 
@@ -194,6 +198,7 @@ func (c *cliIntent) NewPatch() *Patch {
 		BuildVariants:      c.BuildVariants,
 		RegexBuildVariants: c.RegexBuildVariants,
 		Parameters:         c.Parameters,
+		ExpansionOverrides: c.ExpansionOverrides,
 		Alias:              c.Alias,
 		Triggers:           TriggerInfo{Aliases: c.TriggerAliases},
 		Tasks:              c.Tasks,
@@ -217,26 +222,27 @@ func (c *cliIntent) NewPatch() *Patch {
 }
 
 type CLIIntentParams struct {
-	User             string
-	Path             string
-	Project          string
-	BaseGitHash      string
-	Module           string
-	PatchContent     string
-	Description      string
-	Finalize         bool
-	BackportOf       BackportInfo
-	GitInfo          *GitMetadata
-	Parameters       []Parameter
-	Variants         []string
-	Tasks            []string
-	RegexVariants    []string
-	RegexTasks       []string
-	Alias            string
-	TriggerAliases   []string
-	RepeatDefinition bool
-	RepeatFailed     bool
-	SyncParams       SyncAtEndOptions
+	User               string
+	Path               string
+	Project            string
+	BaseGitHash        string
+	Module             string
+	PatchContent       string
+	Description        string
+	Finalize           bool
+	BackportOf         BackportInfo
+	GitInfo            *GitMetadata
+	Parameters         []Parameter
+	ExpansionOverrides map[string]string
+	Variants           []string
+	Tasks              []string
+	RegexVariants      []string
+	RegexTasks         []string
+	Alias              string
+	TriggerAliases     []string
+	RepeatDefinition   bool
+	RepeatFailed       bool
+	SyncParams         SyncAtEndOptions
 }
 
 func NewCliIntent(params CLIIntentParams) (Intent, error) {
@@ -286,6 +292,7 @@ func NewCliIntent(params CLIIntentParams) (Intent, error) {
 		RegexBuildVariants: params.RegexVariants,
 		RegexTasks:         params.RegexTasks,
 		Parameters:         params.Parameters,
+		ExpansionOverrides: params.ExpansionOverrides,
 		SyncAtEndOpts:      params.SyncParams,
 		User:               params.User,
 		ProjectID:          params.Project,
@@ -41,6 +41,7 @@ var (
 	SyncAtEndOptionsKey     = bsonutil.MustHaveTag(Patch{}, "SyncAtEndOpts")
 	PatchesKey              = bsonutil.MustHaveTag(Patch{}, "Patches")
 	ParametersKey           = bsonutil.MustHaveTag(Patch{}, "Parameters")
+	LabelsKey               = bsonutil.MustHaveTag(Patch{}, "Labels")
 	ActivatedKey            = bsonutil.MustHaveTag(Patch{}, "Activated")
 	PatchedParserProjectKey = bsonutil.MustHaveTag(Patch{}, "PatchedParserProject")
 	PatchedProjectConfigKey = bsonutil.MustHaveTag(Patch{}, "PatchedProjectConfig")
@@ -48,6 +49,7 @@ var (
 	githubPatchDataKey      = bsonutil.MustHaveTag(Patch{}, "GithubPatchData")
 	MergePatchKey           = bsonutil.MustHaveTag(Patch{}, "MergePatch")
 	TriggersKey             = bsonutil.MustHaveTag(Patch{}, "Triggers")
+	StaleCleanupKey         = bsonutil.MustHaveTag(Patch{}, "StaleCleanup")
 
 	// BSON fields for sync at end struct
 	SyncAtEndOptionsBuildVariantsKey = bsonutil.MustHaveTag(SyncAtEndOptions{}, "BuildVariants")
@@ -56,6 +58,11 @@ var (
 	SyncAtEndOptionsStatusesKey      = bsonutil.MustHaveTag(SyncAtEndOptions{}, "Statuses")
 	SyncAtEndOptionsTimeoutKey       = bsonutil.MustHaveTag(SyncAtEndOptions{}, "Timeout")
 
+	// BSON fields for the stale cleanup struct
+	StaleCleanupWarningSentAtKey  = bsonutil.MustHaveTag(StaleCleanupInfo{}, "WarningSentAt")
+	StaleCleanupKeepAliveUntilKey = bsonutil.MustHaveTag(StaleCleanupInfo{}, "KeepAliveUntil")
+	StaleCleanupArchivedAtKey     = bsonutil.MustHaveTag(StaleCleanupInfo{}, "ArchivedAt")
+
 	// BSON fields for the module patch struct
 	ModulePatchNameKey    = bsonutil.MustHaveTag(ModulePatch{}, "ModuleName")
 	ModulePatchGithashKey = bsonutil.MustHaveTag(ModulePatch{}, "Githash")
@@ -110,6 +117,10 @@ func ByStringIds(ids []string) db.Q {
 
 var commitQueueFilter = bson.M{"$ne": evergreen.CommitQueueAlias}
 
+// archivedKey is the key that is only set on patches that were archived as
+// stale, which are left out of patch listings.
+var archivedKey = bsonutil.GetDottedKeyName(StaleCleanupKey, StaleCleanupArchivedAtKey)
+
 // ByProject produces a query that returns projects with the given identifier.
 func ByProjectAndCommitQueue(project string, filterCommitQueue bool) db.Q {
 	q := bson.M{ProjectKey: project}
@@ -148,7 +159,7 @@ func ByPatchNameStatusesCommitQueuePaginated(opts ByPatchNameStatusesCommitQueue
 		return nil, 0, errors.New("can't set both project and author")
 	}
 	pipeline := []bson.M{}
-	match := bson.M{}
+	match := bson.M{archivedKey: bson.M{"$exists": false}}
 	// Conditionally add the commit queue filter if the user is explicitly filtering on it.
 	// This is only used on the project patches page when we want to conditionally only show the commit queue patches.
 	if utility.FromBoolPtr(opts.OnlyCommitQueue) {
@@ -232,6 +243,7 @@ func ByUserPaginated(user string, ts time.Time, limit int) db.Q {
 	return db.Query(bson.M{
 		AuthorKey:     user,
 		CreateTimeKey: bson.M{"$lte": ts},
+		archivedKey:   bson.M{"$exists": false},
 	}).Sort([]string{"-" + CreateTimeKey}).Limit(limit)
 }
 
@@ -315,6 +327,7 @@ func PatchesByProject(projectId string, ts time.Time, limit int) db.Q {
 	return db.Query(bson.M{
 		CreateTimeKey: bson.M{"$lte": ts},
 		ProjectKey:    projectId,
+		archivedKey:   bson.M{"$exists": false},
 	}).Sort([]string{"-" + CreateTimeKey}).Limit(limit)
 }
 
@@ -351,6 +364,27 @@ func ByGithubPRAndCreatedBefore(t time.Time, owner, repo string, prNumber int) d
 	})
 }
 
+// ByProjectStaleCandidates returns the project's patches that were created
+// before the given time and haven't been archived as stale. If finalized is
+// true, it returns the finalized patches that haven't finished; otherwise, it
+// returns the patches that were never finalized. Commit queue patches are
+// excluded since the commit queue manages them.
+func ByProjectStaleCandidates(project string, createdBefore time.Time, finalized bool) db.Q {
+	q := bson.M{
+		ProjectKey:    project,
+		CreateTimeKey: bson.M{"$lt": createdBefore},
+		AliasKey:      bson.M{"$ne": evergreen.CommitQueueAlias},
+		archivedKey:   bson.M{"$exists": false},
+	}
+	if finalized {
+		q[VersionKey] = bson.M{"$nin": []interface{}{"", nil}}
+		q[StatusKey] = bson.M{"$in": []string{evergreen.PatchCreated, evergreen.PatchStarted}}
+	} else {
+		q[VersionKey] = bson.M{"$in": []interface{}{"", nil}}
+	}
+	return db.Query(q)
+}
+
 func FindProjectForPatch(patchID mgobson.ObjectId) (string, error) {
 	p, err := FindOne(ById(patchID).Project(bson.M{ProjectKey: 1}))
 	if err != nil {
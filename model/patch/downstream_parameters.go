@@ -0,0 +1,188 @@
+package patch
+
+import (
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	DownstreamParameterTypeString = "string"
+	DownstreamParameterTypeInt    = "int"
+	DownstreamParameterTypeBool   = "bool"
+)
+
+var (
+	parameterKeyKey       = bsonutil.MustHaveTag(Parameter{}, "Key")
+	parameterValueKey     = bsonutil.MustHaveTag(Parameter{}, "Value")
+	parameterSetByTaskKey = bsonutil.MustHaveTag(Parameter{}, "SetByTask")
+)
+
+var validDownstreamParameterTypes = []string{
+	DownstreamParameterTypeString,
+	DownstreamParameterTypeInt,
+	DownstreamParameterTypeBool,
+}
+
+const (
+	// DownstreamParameterCollisionLastWins replaces an existing value with
+	// the most recently set one. This is the default.
+	DownstreamParameterCollisionLastWins = "last_wins"
+	// DownstreamParameterCollisionFirstWins keeps the value that was set
+	// first and ignores later values.
+	DownstreamParameterCollisionFirstWins = "first_wins"
+	// DownstreamParameterCollisionError rejects a value if a different
+	// task already set the key.
+	DownstreamParameterCollisionError = "error"
+)
+
+var validDownstreamParameterCollisionPolicies = []string{
+	"",
+	DownstreamParameterCollisionLastWins,
+	DownstreamParameterCollisionFirstWins,
+	DownstreamParameterCollisionError,
+}
+
+// DownstreamParameterSchema restricts the parameters that tasks may pass to
+// downstream patches.
+type DownstreamParameterSchema struct {
+	// Parameters are the keys tasks may set. If empty, any key is allowed.
+	Parameters []DownstreamParameterDefinition `bson:"parameters,omitempty" json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	// CollisionPolicy determines what happens when more than one task sets
+	// the same key.
+	CollisionPolicy string `bson:"collision_policy,omitempty" json:"collision_policy,omitempty" yaml:"collision_policy,omitempty"`
+}
+
+// DownstreamParameterDefinition is a key that tasks may set for downstream
+// patches, along with the type its value must have.
+type DownstreamParameterDefinition struct {
+	Key  string `bson:"key" json:"key" yaml:"key"`
+	Type string `bson:"type,omitempty" json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// Validate checks that the schema is well-formed.
+func (s *DownstreamParameterSchema) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.ErrorfWhen(!utility.StringSliceContains(validDownstreamParameterCollisionPolicies, s.CollisionPolicy), "invalid collision policy '%s'", s.CollisionPolicy)
+	keys := map[string]bool{}
+	for _, def := range s.Parameters {
+		catcher.NewWhen(def.Key == "", "downstream parameter key cannot be empty")
+		catcher.ErrorfWhen(keys[def.Key], "downstream parameter '%s' is defined more than once", def.Key)
+		catcher.ErrorfWhen(def.Type != "" && !utility.StringSliceContains(validDownstreamParameterTypes, def.Type), "invalid type '%s' for downstream parameter '%s'", def.Type, def.Key)
+		keys[def.Key] = true
+	}
+	return catcher.Resolve()
+}
+
+// ValidateParameters checks that the given parameters are allowed by the
+// schema.
+func (s *DownstreamParameterSchema) ValidateParameters(params []Parameter) error {
+	catcher := grip.NewBasicCatcher()
+	for _, param := range params {
+		catcher.Add(s.validateParameter(param))
+	}
+	return catcher.Resolve()
+}
+
+func (s *DownstreamParameterSchema) validateParameter(param Parameter) error {
+	if len(s.Parameters) == 0 {
+		return nil
+	}
+	for _, def := range s.Parameters {
+		if def.Key != param.Key {
+			continue
+		}
+		switch def.Type {
+		case DownstreamParameterTypeInt:
+			if _, err := strconv.Atoi(param.Value); err != nil {
+				return errors.Errorf("downstream parameter '%s' must be an integer", param.Key)
+			}
+		case DownstreamParameterTypeBool:
+			if _, err := strconv.ParseBool(param.Value); err != nil {
+				return errors.Errorf("downstream parameter '%s' must be a boolean", param.Key)
+			}
+		}
+		return nil
+	}
+	return errors.Errorf("downstream parameter '%s' is not allowed by the project", param.Key)
+}
+
+// SetDownstreamParameters sets the parameters from the given task that
+// should be passed to downstream patches. Each parameter is pushed atomically
+// so that concurrent tasks don't overwrite each other's parameters; a
+// parameter that another task already set is resolved according to the
+// schema's collision policy.
+func (p *Patch) SetDownstreamParameters(parameters []Parameter, taskId string, schema DownstreamParameterSchema) error {
+	if err := schema.ValidateParameters(parameters); err != nil {
+		return errors.Wrap(err, "invalid downstream parameters")
+	}
+
+	for _, param := range parameters {
+		param.SetByTask = taskId
+		if err := p.setDownstreamParameter(param, schema.CollisionPolicy); err != nil {
+			return errors.Wrapf(err, "setting downstream parameter '%s'", param.Key)
+		}
+	}
+
+	dbPatch, err := FindOneId(p.Id.Hex())
+	if err != nil {
+		return errors.Wrap(err, "finding updated patch")
+	}
+	if dbPatch == nil {
+		return errors.Errorf("patch '%s' not found", p.Id.Hex())
+	}
+	p.Triggers.DownstreamParameters = dbPatch.Triggers.DownstreamParameters
+	return nil
+}
+
+func (p *Patch) setDownstreamParameter(param Parameter, collisionPolicy string) error {
+	paramsKey := bsonutil.GetDottedKeyName(TriggersKey, TriggerInfoDownstreamParametersKey)
+	paramKeyKey := bsonutil.GetDottedKeyName(paramsKey, parameterKeyKey)
+
+	err := UpdateOne(
+		bson.M{
+			IdKey:       p.Id,
+			paramKeyKey: bson.M{"$ne": param.Key},
+		},
+		bson.M{"$push": bson.M{paramsKey: param}},
+	)
+	if !adb.ResultsNotFound(err) {
+		return err
+	}
+
+	// The parameter is already set.
+	switch collisionPolicy {
+	case DownstreamParameterCollisionFirstWins:
+		return nil
+	case DownstreamParameterCollisionError:
+		conflicts, err := Count(db.Query(bson.M{
+			IdKey: p.Id,
+			paramsKey: bson.M{"$elemMatch": bson.M{
+				parameterKeyKey:       param.Key,
+				parameterValueKey:     bson.M{"$ne": param.Value},
+				parameterSetByTaskKey: bson.M{"$ne": param.SetByTask},
+			}},
+		}))
+		if err != nil {
+			return errors.Wrap(err, "checking for conflicting parameter")
+		}
+		if conflicts > 0 {
+			return errors.New("parameter was already set by another task")
+		}
+		return nil
+	default:
+		return UpdateOne(
+			bson.M{
+				IdKey:       p.Id,
+				paramKeyKey: param.Key,
+			},
+			bson.M{"$set": bson.M{bsonutil.GetDottedKeyName(paramsKey, "$"): param}},
+		)
+	}
+}
@@ -0,0 +1,141 @@
+package patch
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// MaxLabels is the most labels that a version or patch can have.
+	MaxLabels = 20
+
+	maxLabelKeyLength   = 63
+	maxLabelValueLength = 255
+)
+
+var labelKeyRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.\-/]*$`)
+
+// Label is a key/value pair that marks a version or patch, such as a release
+// candidate or a hotfix.
+type Label struct {
+	Key   string `bson:"key" json:"key" yaml:"key"`
+	Value string `bson:"value" json:"value" yaml:"value"`
+}
+
+var (
+	LabelKeyKey   = bsonutil.MustHaveTag(Label{}, "Key")
+	LabelValueKey = bsonutil.MustHaveTag(Label{}, "Value")
+)
+
+// ValidateLabels checks that the labels have valid, unique keys and are
+// within the limits on labels.
+func ValidateLabels(labels []Label) error {
+	catcher := grip.NewBasicCatcher()
+	catcher.ErrorfWhen(len(labels) > MaxLabels, "cannot have more than %d labels", MaxLabels)
+	keys := map[string]bool{}
+	for _, l := range labels {
+		catcher.ErrorfWhen(!labelKeyRegex.MatchString(l.Key), "invalid label key '%s': must start with a letter or number and contain only letters, numbers, '_', '.', '-', and '/'", l.Key)
+		catcher.ErrorfWhen(len(l.Key) > maxLabelKeyLength, "label key '%s' cannot be longer than %d characters", l.Key, maxLabelKeyLength)
+		catcher.ErrorfWhen(len(l.Value) > maxLabelValueLength, "value of label '%s' cannot be longer than %d characters", l.Key, maxLabelValueLength)
+		catcher.ErrorfWhen(keys[l.Key], "duplicate label key '%s'", l.Key)
+		keys[l.Key] = true
+	}
+	return catcher.Resolve()
+}
+
+// LabelsFromMap returns the labels for the key/value pairs, sorted by key.
+func LabelsFromMap(m map[string]string) []Label {
+	if len(m) == 0 {
+		return nil
+	}
+	labels := make([]Label, 0, len(m))
+	for k, v := range m {
+		labels = append(labels, Label{Key: k, Value: v})
+	}
+	SortLabels(labels)
+	return labels
+}
+
+// ApplyLabelChanges returns the labels with the labels to set added or
+// replacing the existing labels with the same keys, and with the labels to
+// remove removed. The returned labels are sorted by key.
+func ApplyLabelChanges(labels []Label, set map[string]string, remove []string) []Label {
+	merged := map[string]string{}
+	for _, l := range labels {
+		merged[l.Key] = l.Value
+	}
+	for _, k := range remove {
+		delete(merged, k)
+	}
+	for k, v := range set {
+		merged[k] = v
+	}
+	return LabelsFromMap(merged)
+}
+
+// LabelUpdates returns the updates to the labels field that remove the labels
+// with the keys to remove and set the labels to set, replacing any existing
+// labels with the same keys. Each update is atomic, so concurrent changes to
+// other labels are not lost; the updates must be applied in order.
+func LabelUpdates(labelsKey string, set map[string]string, remove []string) []bson.M {
+	pullKeys := append([]string{}, remove...)
+	for k := range set {
+		pullKeys = append(pullKeys, k)
+	}
+	var updates []bson.M
+	if len(pullKeys) > 0 {
+		updates = append(updates, bson.M{"$pull": bson.M{labelsKey: bson.M{LabelKeyKey: bson.M{"$in": pullKeys}}}})
+	}
+	if len(set) > 0 {
+		updates = append(updates, bson.M{"$addToSet": bson.M{labelsKey: bson.M{"$each": LabelsFromMap(set)}}})
+	}
+	return updates
+}
+
+// SortLabels sorts the labels by key.
+func SortLabels(labels []Label) {
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Key < labels[j].Key })
+}
+
+// ParseLabelSelector parses a comma-separated list of labels to match, each
+// either "key:value" to match a label's key and value or "key" to match any
+// label with the key.
+func ParseLabelSelector(selector string) ([]Label, error) {
+	var labels []Label
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		parts := strings.SplitN(term, ":", 2)
+		l := Label{Key: parts[0]}
+		if len(parts) == 2 {
+			l.Value = parts[1]
+		}
+		if !labelKeyRegex.MatchString(l.Key) {
+			return nil, errors.Errorf("invalid label key '%s'", l.Key)
+		}
+		labels = append(labels, l)
+	}
+	return labels, nil
+}
+
+// LabelsMatch returns a query that matches documents whose labels field has
+// all of the labels. Labels without a value match any label with the key.
+func LabelsMatch(labels []Label) bson.M {
+	elemMatches := make([]bson.M, 0, len(labels))
+	for _, l := range labels {
+		elemMatch := bson.M{LabelKeyKey: l.Key}
+		if l.Value != "" {
+			elemMatch[LabelValueKey] = l.Value
+		}
+		elemMatches = append(elemMatches, bson.M{"$elemMatch": elemMatch})
+	}
+	return bson.M{"$all": elemMatches}
+}
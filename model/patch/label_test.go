@@ -0,0 +1,51 @@
+package patch
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLabels(t *testing.T) {
+	assert.NoError(t, ValidateLabels(nil))
+	assert.NoError(t, ValidateLabels([]Label{{Key: "release", Value: "1.0"}, {Key: "team/infra.hotfix"}}))
+
+	assert.Error(t, ValidateLabels([]Label{{Key: ""}}))
+	assert.Error(t, ValidateLabels([]Label{{Key: "-release"}}))
+	assert.Error(t, ValidateLabels([]Label{{Key: "release candidate"}}))
+	assert.Error(t, ValidateLabels([]Label{{Key: strings.Repeat("k", maxLabelKeyLength+1)}}))
+	assert.Error(t, ValidateLabels([]Label{{Key: "release", Value: strings.Repeat("v", maxLabelValueLength+1)}}))
+	assert.Error(t, ValidateLabels([]Label{{Key: "release", Value: "1.0"}, {Key: "release", Value: "2.0"}}))
+
+	tooMany := []Label{}
+	for i := 0; i <= MaxLabels; i++ {
+		tooMany = append(tooMany, Label{Key: fmt.Sprintf("label%d", i)})
+	}
+	assert.Error(t, ValidateLabels(tooMany))
+}
+
+func TestApplyLabelChanges(t *testing.T) {
+	labels := []Label{{Key: "release", Value: "1.0"}, {Key: "hotfix", Value: "true"}}
+
+	updated := ApplyLabelChanges(labels, map[string]string{"release": "1.1", "candidate": ""}, []string{"hotfix", "nonexistent"})
+	assert.Equal(t, []Label{{Key: "candidate"}, {Key: "release", Value: "1.1"}}, updated)
+
+	assert.Empty(t, ApplyLabelChanges(labels, nil, []string{"release", "hotfix"}))
+	assert.Equal(t, []Label{{Key: "hotfix", Value: "true"}, {Key: "release", Value: "1.0"}}, ApplyLabelChanges(labels, nil, nil))
+}
+
+func TestParseLabelSelector(t *testing.T) {
+	labels, err := ParseLabelSelector("release:1.0, hotfix,url:http://example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []Label{{Key: "release", Value: "1.0"}, {Key: "hotfix"}, {Key: "url", Value: "http://example.com"}}, labels)
+
+	labels, err = ParseLabelSelector("")
+	require.NoError(t, err)
+	assert.Empty(t, labels)
+
+	_, err = ParseLabelSelector(":1.0")
+	assert.Error(t, err)
+}
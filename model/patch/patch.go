@@ -103,12 +103,35 @@ func mergeDisplayTasks(current DisplayTask, toMerge DisplayTask) DisplayTask {
 type DisplayTask struct {
 	Name      string   `yaml:"name,omitempty" bson:"name,omitempty"`
 	ExecTasks []string `yaml:"execution_tasks,omitempty" bson:"execution_tasks,omitempty"`
+	// DispatchOrder determines the order in which the execution tasks are
+	// dispatched relative to each other.
+	DispatchOrder string `yaml:"dispatch_order,omitempty" bson:"dispatch_order,omitempty"`
+}
+
+const (
+	// DisplayTaskDispatchOrderDefault dispatches execution tasks in the
+	// same order as any other tasks.
+	DisplayTaskDispatchOrderDefault = ""
+	// DisplayTaskDispatchOrderLongestFirst dispatches the execution tasks
+	// that historically take the longest first, to minimize the display
+	// task's total wall time.
+	DisplayTaskDispatchOrderLongestFirst = "longest_first"
+)
+
+// ValidDisplayTaskDispatchOrders are the dispatch orders that display tasks
+// can use.
+var ValidDisplayTaskDispatchOrders = []string{
+	DisplayTaskDispatchOrderDefault,
+	DisplayTaskDispatchOrderLongestFirst,
 }
 
 // Parameter defines a key/value pair to be used as an expansion.
 type Parameter struct {
 	Key   string `yaml:"key" bson:"key"`
 	Value string `yaml:"value" bson:"value"`
+	// SetByTask is the task that set the parameter, if it was passed from
+	// an upstream task.
+	SetByTask string `yaml:"set_by_task,omitempty" bson:"set_by_task,omitempty"`
 }
 
 // SyncAtEndOptions describes when and how tasks perform sync at the end of a
@@ -158,7 +181,11 @@ type Patch struct {
 	SyncAtEndOpts      SyncAtEndOptions `bson:"sync_at_end_opts,omitempty"`
 	Patches            []ModulePatch    `bson:"patches"`
 	Parameters         []Parameter      `bson:"parameters,omitempty"`
-	Activated          bool             `bson:"activated"`
+	Labels             []Label          `bson:"labels,omitempty"`
+	// ExpansionOverrides override the value of the expansions for all of the
+	// patch's tasks.
+	ExpansionOverrides map[string]string `bson:"expansion_overrides,omitempty"`
+	Activated          bool              `bson:"activated"`
 	// PatchedParserProject is mismatched with its BSON tag since the tag already exists in the DB.
 	// Struct property has been renamed to convey that only parser project configs are stored in it.
 	PatchedParserProject string                 `bson:"patched_config"`
@@ -177,6 +204,17 @@ type Patch struct {
 	// MergedFrom is populated with the patch id of the existing patch
 	// the merged patch is based off of, if applicable.
 	MergedFrom string `bson:"merged_from,omitempty"`
+	// StaleCleanup tracks the patch's progress through its project's stale
+	// patch cleanup policy.
+	StaleCleanup StaleCleanupInfo `bson:"stale_cleanup,omitempty"`
+}
+
+// StaleCleanupInfo records when a patch's author was warned that the patch is
+// stale, how long the author asked to keep it, and when it was archived.
+type StaleCleanupInfo struct {
+	WarningSentAt  time.Time `bson:"warning_sent_at,omitempty"`
+	KeepAliveUntil time.Time `bson:"keep_alive_until,omitempty"`
+	ArchivedAt     time.Time `bson:"archived_at,omitempty"`
 }
 
 func (p *Patch) MarshalBSON() ([]byte, error)  { return mgobson.Marshal(p) }
@@ -245,6 +283,66 @@ func (p *Patch) SetMergePatch(newPatchID string) error {
 	)
 }
 
+// SetStaleWarningSent records that the patch's author was warned that the
+// patch will be cleaned up.
+func (p *Patch) SetStaleWarningSent(ts time.Time) error {
+	if err := UpdateOne(
+		bson.M{IdKey: p.Id},
+		bson.M{
+			"$set": bson.M{
+				bsonutil.GetDottedKeyName(StaleCleanupKey, StaleCleanupWarningSentAtKey): ts,
+			},
+		},
+	); err != nil {
+		return err
+	}
+	p.StaleCleanup.WarningSentAt = ts
+	return nil
+}
+
+// KeepAlive keeps the patch from being cleaned up as stale until the given
+// time. Any earlier warning is cleared so that the author is warned again
+// before the patch is cleaned up.
+func (p *Patch) KeepAlive(until time.Time) error {
+	if err := UpdateOne(
+		bson.M{IdKey: p.Id},
+		bson.M{
+			"$set": bson.M{
+				bsonutil.GetDottedKeyName(StaleCleanupKey, StaleCleanupKeepAliveUntilKey): until,
+			},
+			"$unset": bson.M{
+				bsonutil.GetDottedKeyName(StaleCleanupKey, StaleCleanupWarningSentAtKey): 1,
+			},
+		},
+	); err != nil {
+		return err
+	}
+	p.StaleCleanup.KeepAliveUntil = until
+	p.StaleCleanup.WarningSentAt = time.Time{}
+	return nil
+}
+
+// MarkArchived records that the patch was archived as stale.
+func (p *Patch) MarkArchived(ts time.Time) error {
+	if err := UpdateOne(
+		bson.M{IdKey: p.Id},
+		bson.M{
+			"$set": bson.M{
+				bsonutil.GetDottedKeyName(StaleCleanupKey, StaleCleanupArchivedAtKey): ts,
+			},
+		},
+	); err != nil {
+		return err
+	}
+	p.StaleCleanup.ArchivedAt = ts
+	return nil
+}
+
+// IsArchived returns whether the patch was archived as stale.
+func (p *Patch) IsArchived() bool {
+	return !p.StaleCleanup.ArchivedAt.IsZero()
+}
+
 func (p *Patch) GetCommitQueueURL(uiHost string) string {
 	return uiHost + "/commit-queue/" + p.Project
 }
@@ -338,16 +436,24 @@ func (p *Patch) SetParameters(parameters []Parameter) error {
 	)
 }
 
-func (p *Patch) SetDownstreamParameters(parameters []Parameter) error {
-	p.Triggers.DownstreamParameters = append(p.Triggers.DownstreamParameters, parameters...)
-
-	triggersKey := bsonutil.GetDottedKeyName(TriggersKey, TriggerInfoDownstreamParametersKey)
-	return UpdateOne(
-		bson.M{IdKey: p.Id},
-		bson.M{
-			"$push": bson.M{triggersKey: bson.M{"$each": parameters}},
-		},
-	)
+// UpdateLabels removes the labels with the keys to remove and sets the labels
+// to set on the patch, replacing any existing labels with the same keys.
+func (p *Patch) UpdateLabels(set map[string]string, remove []string) error {
+	for _, update := range LabelUpdates(LabelsKey, set, remove) {
+		if err := UpdateOne(bson.M{IdKey: p.Id}, update); err != nil {
+			return err
+		}
+	}
+	dbPatch, err := FindOne(ByStringId(p.Id.Hex()).WithFields(LabelsKey))
+	if err != nil {
+		return errors.Wrap(err, "finding updated labels")
+	}
+	if dbPatch == nil {
+		return errors.Errorf("patch '%s' not found", p.Id.Hex())
+	}
+	p.Labels = dbPatch.Labels
+	SortLabels(p.Labels)
+	return nil
 }
 
 // ResolveVariantTasks returns a set of all build variants and a set of all
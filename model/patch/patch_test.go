@@ -889,10 +889,53 @@ func TestSetDownstreamParameters(t *testing.T) {
 		},
 	}
 
-	assert.NoError(p.SetDownstreamParameters(paramsToAdd))
+	assert.NoError(p.SetDownstreamParameters(paramsToAdd, "t1", DownstreamParameterSchema{}))
 	assert.Equal(p.Triggers.DownstreamParameters[0].Key, "key_0")
 	assert.Equal(p.Triggers.DownstreamParameters[1].Key, "key_1")
+	assert.Equal(p.Triggers.DownstreamParameters[1].SetByTask, "t1")
 	assert.Equal(p.Triggers.DownstreamParameters[2].Key, "key_2")
+
+	dbPatch, err := FindOneId(p.Id.Hex())
+	assert.NoError(err)
+	assert.Equal(p.Triggers.DownstreamParameters, dbPatch.Triggers.DownstreamParameters)
+
+	stale := *dbPatch
+	stale.Triggers.DownstreamParameters = stale.Triggers.DownstreamParameters[:1]
+	assert.NoError(stale.SetDownstreamParameters([]Parameter{{Key: "key_3", Value: "value_3"}, {Key: "key_1", Value: "new_value_1"}}, "t2", DownstreamParameterSchema{}))
+	dbPatch, err = FindOneId(p.Id.Hex())
+	assert.NoError(err)
+	assert.Equal([]Parameter{
+		{Key: "key_0", Value: "value_0"},
+		{Key: "key_1", Value: "new_value_1", SetByTask: "t2"},
+		{Key: "key_2", Value: "value_2", SetByTask: "t1"},
+		{Key: "key_3", Value: "value_3", SetByTask: "t2"},
+	}, dbPatch.Triggers.DownstreamParameters)
+	assert.Equal(dbPatch.Triggers.DownstreamParameters, stale.Triggers.DownstreamParameters)
+
+	assert.NoError(stale.SetDownstreamParameters([]Parameter{{Key: "key_0", Value: "value_0"}}, "t3", DownstreamParameterSchema{CollisionPolicy: DownstreamParameterCollisionError}))
+	assert.Error(stale.SetDownstreamParameters([]Parameter{{Key: "key_1", Value: "value_1"}}, "t3", DownstreamParameterSchema{CollisionPolicy: DownstreamParameterCollisionError}))
+	assert.NoError(stale.SetDownstreamParameters([]Parameter{{Key: "key_1", Value: "value_1"}}, "t3", DownstreamParameterSchema{CollisionPolicy: DownstreamParameterCollisionFirstWins}))
+	assert.Equal("new_value_1", stale.Triggers.DownstreamParameters[1].Value)
+}
+
+func TestDownstreamParameterSchemaValidateParameter(t *testing.T) {
+	schema := DownstreamParameterSchema{Parameters: []DownstreamParameterDefinition{
+		{Key: "version", Type: DownstreamParameterTypeInt},
+		{Key: "debug", Type: DownstreamParameterTypeBool},
+		{Key: "name"},
+	}}
+	assert.NoError(t, schema.validateParameter(Parameter{Key: "version", Value: "2"}))
+	assert.NoError(t, schema.validateParameter(Parameter{Key: "debug", Value: "true"}))
+	assert.NoError(t, schema.validateParameter(Parameter{Key: "name", Value: "anything"}))
+	assert.Error(t, schema.validateParameter(Parameter{Key: "version", Value: "two"}))
+	assert.Error(t, schema.validateParameter(Parameter{Key: "debug", Value: "maybe"}))
+	assert.Error(t, schema.validateParameter(Parameter{Key: "other", Value: "2"}))
+
+	anyKey := DownstreamParameterSchema{}
+	assert.NoError(t, anyKey.validateParameter(Parameter{Key: "other", Value: "2"}))
+
+	assert.NoError(t, schema.ValidateParameters([]Parameter{{Key: "version", Value: "2"}, {Key: "debug", Value: "false"}}))
+	assert.Error(t, schema.ValidateParameters([]Parameter{{Key: "version", Value: "2"}, {Key: "other", Value: "2"}}))
 }
 
 func TestSetTriggerAliases(t *testing.T) {
@@ -172,6 +172,57 @@ func ConfigurePatch(ctx context.Context, p *patch.Patch, version *Version, proj
 	return http.StatusOK, nil
 }
 
+// ExtendPatch adds variants and tasks to a patch that was already finalized,
+// so that a patch can be finalized with some of its tasks and extended with
+// more later. Only the builds and tasks that don't exist yet are created, so
+// the results of the patch's existing tasks are kept. Returns an http status
+// code and error.
+func ExtendPatch(ctx context.Context, p *patch.Patch, v *Version, project *Project, pRef *ProjectRef, additions []patch.VariantTasks) (int, error) {
+	if p.Version == "" || v == nil {
+		return http.StatusBadRequest, errors.Errorf("patch '%s' has not been finalized", p.Id.Hex())
+	}
+	if p.IsCommitQueuePatch() {
+		return http.StatusBadRequest, errors.New("can't extend commit queue patch")
+	}
+	if p.IsArchived() {
+		return http.StatusBadRequest, errors.Errorf("patch '%s' was archived", p.Id.Hex())
+	}
+
+	req := PatchUpdate{VariantsTasks: additions}
+	addDisplayTasksToPatchReq(&req, *project)
+	tasks := VariantTasksToTVPairs(req.VariantsTasks)
+	var err error
+	tasks.ExecTasks, err = IncludeDependencies(project, tasks.ExecTasks, p.GetRequester())
+	grip.Warning(message.WrapError(err, message.Fields{
+		"message": "error including dependencies for patch",
+		"patch":   p.Id,
+	}))
+	if err = ValidateTVPairs(project, tasks.ExecTasks); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	if err = addNewTasksAndBuildsForPatch(ctx, p.SyncAtEndOpts, v, project, tasks, pRef); err != nil {
+		return http.StatusInternalServerError, errors.Wrapf(err, "creating new tasks/builds for version '%s'", v.Id)
+	}
+	if err = p.SetVariantsTasks(patch.MergeVariantsTasks(p.VariantsTasks, tasks.TVPairsToVariantTasks())); err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "setting patch variants and tasks")
+	}
+
+	// The version may have already finished, so its status and the statuses
+	// of its builds must account for the new tasks.
+	v, err = VersionFindOneId(v.Id)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrapf(err, "finding version '%s'", p.Version)
+	}
+	if v == nil {
+		return http.StatusInternalServerError, errors.Errorf("version '%s' not found", p.Version)
+	}
+	if err = UpdateVersionAndPatchStatusForBuilds(v.BuildIds); err != nil {
+		return http.StatusInternalServerError, errors.Wrapf(err, "updating statuses for version '%s'", v.Id)
+	}
+	return http.StatusOK, nil
+}
+
 func addDisplayTasksToPatchReq(req *PatchUpdate, p Project) {
 	for i, vt := range req.VariantsTasks {
 		bv := p.FindBuildVariant(vt.Variant)
@@ -450,9 +501,13 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 		RevisionOrderNumber: p.PatchNumber,
 		AuthorID:            p.Author,
 		Parameters:          p.Parameters,
+		Labels:              p.Labels,
+		ExpansionOverrides:  p.ExpansionOverrides,
 		Activated:           utility.TruePtr(),
 	}
 	intermediateProject.CreateTime = patchVersion.CreateTime
+	patchVersion.ExternalGates = project.NewVersionExternalGates(patchVersion.CreateTime)
+	patchVersion.Stages = project.NewVersionStages()
 
 	tasks := TaskVariantPairs{}
 	if len(p.VariantsTasks) > 0 {
@@ -485,6 +540,7 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 		return nil, errors.Wrapf(err, "getting create time for tasks in '%s', githash '%s'", p.Project, p.Githash)
 	}
 
+	queueLengths := NewRunOnQueueLengths()
 	buildsToInsert := build.Builds{}
 	tasksToInsert := task.Tasks{}
 	for _, vt := range p.VariantsTasks {
@@ -499,17 +555,18 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 		}
 		taskNames := tasks.ExecTasks.TaskNames(vt.Variant)
 		buildArgs := BuildCreateArgs{
-			Project:        *project,
-			ProjectRef:     *projectRef,
-			Version:        *patchVersion,
-			TaskIDs:        taskIds,
-			BuildName:      vt.Variant,
-			ActivateBuild:  true,
-			TaskNames:      taskNames,
-			DisplayNames:   displayNames,
-			DistroAliases:  distroAliases,
-			TaskCreateTime: createTime,
-			SyncAtEndOpts:  p.SyncAtEndOpts,
+			Project:           *project,
+			ProjectRef:        *projectRef,
+			Version:           *patchVersion,
+			TaskIDs:           taskIds,
+			BuildName:         vt.Variant,
+			ActivateBuild:     true,
+			TaskNames:         taskNames,
+			DisplayNames:      displayNames,
+			DistroAliases:     distroAliases,
+			RunOnQueueLengths: queueLengths,
+			TaskCreateTime:    createTime,
+			SyncAtEndOpts:     p.SyncAtEndOpts,
 		}
 		var build *build.Build
 		var tasks task.Tasks
@@ -538,6 +595,18 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 			},
 		)
 	}
+	numActivatedTasks := 0
+	for _, t := range tasksToInsert {
+		if t.Activated {
+			numActivatedTasks++
+		}
+	}
+	if numActivatedTasks > 0 {
+		if err = CheckScheduledTaskQuota(projectRef, numActivatedTasks); err != nil {
+			return nil, errors.Wrap(err, "checking project quotas")
+		}
+	}
+
 	mongoClient := evergreen.GetEnvironment().Client()
 	session, err := mongoClient.StartSession()
 	if err != nil {
@@ -686,15 +755,32 @@ func SubscribeOnParentOutcome(parentStatus string, childPatchId string, parentPa
 	return nil
 }
 
-func CancelPatch(p *patch.Patch, reason task.AbortInfo) error {
-	if p.Version != "" {
-		if err := SetVersionActivation(p.Version, false, reason.User); err != nil {
-			return errors.WithStack(err)
+// CancelPatch deactivates the patch's version and aborts its running tasks,
+// or removes the patch if it has not been finalized. It returns the tasks,
+// builds, and versions that are deactivated or aborted. If dryRun is true,
+// nothing is written.
+func CancelPatch(p *patch.Patch, reason task.AbortInfo, dryRun bool) (AffectedEntities, error) {
+	if p.Version == "" {
+		if dryRun {
+			return AffectedEntities{}, nil
 		}
-		return errors.WithStack(task.AbortVersion(p.Version, reason))
+		return AffectedEntities{}, errors.WithStack(patch.Remove(patch.ById(p.Id)))
 	}
 
-	return errors.WithStack(patch.Remove(patch.ById(p.Id)))
+	affected, err := setVersionActivation(p.Version, false, dryRun, reason.User)
+	if err != nil {
+		return affected, errors.WithStack(err)
+	}
+	if dryRun {
+		toAbort, err := task.FindAbortableTaskIDsForVersion(p.Version, reason)
+		if err != nil {
+			return affected, errors.Wrap(err, "finding tasks to abort")
+		}
+		err = affected.AddTaskIDs(toAbort...)
+		affected.sortIDs()
+		return affected, err
+	}
+	return affected, errors.WithStack(task.AbortVersion(p.Version, reason))
 }
 
 // AbortPatchesWithGithubPatchData runs CancelPatch on patches created before
@@ -727,7 +813,7 @@ func AbortPatchesWithGithubPatchData(createdBefore time.Time, closed bool, newPa
 					return errors.New("no merge task found")
 				}
 				catcher.Add(DequeueAndRestartForTask(nil, mergeTask, message.GithubStateFailure, evergreen.APIServerTaskActivator, "new push to pull request"))
-			} else if err = CancelPatch(&p, task.AbortInfo{User: evergreen.GithubPatchUser, NewVersion: newPatch, PRClosed: closed}); err != nil {
+			} else if _, err = CancelPatch(&p, task.AbortInfo{User: evergreen.GithubPatchUser, NewVersion: newPatch, PRClosed: closed}, false); err != nil {
 				grip.Error(message.WrapError(err, message.Fields{
 					"source":         "github hook",
 					"created_before": createdBefore.String(),
@@ -1038,6 +1124,9 @@ func SendCommitQueueResult(p *patch.Patch, status message.GithubState, descripti
 	if err != nil {
 		return errors.Wrap(err, "getting GitHub sender")
 	}
+	if err = thirdparty.ReserveGithubStatus(thirdparty.GithubPriorityCritical); err != nil {
+		return errors.Wrap(err, "reserving GitHub budget for commit queue status")
+	}
 	sender.Send(message.NewGithubStatusMessageWithRepo(level.Notice, msg))
 
 	return nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/db"
 	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
 	"github.com/evergreen-ci/evergreen/model/build"
@@ -715,6 +717,99 @@ func TestAddNewPatch(t *testing.T) {
 	}
 }
 
+func TestExtendPatch(t *testing.T) {
+	require.NoError(t, db.ClearCollections(patch.Collection, VersionCollection, build.Collection, task.Collection, ProjectRefCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(patch.Collection, VersionCollection, build.Collection, task.Collection, ProjectRefCollection))
+	}()
+
+	p := &patch.Patch{
+		Id:            mgobson.NewObjectId(),
+		Version:       "version",
+		Activated:     true,
+		Status:        evergreen.PatchSucceeded,
+		VariantsTasks: []patch.VariantTasks{{Variant: "variant", Tasks: []string{"task1"}}},
+	}
+	require.NoError(t, p.Insert())
+	v := &Version{
+		Id:         "version",
+		Revision:   "1234",
+		Requester:  evergreen.PatchVersionRequester,
+		Identifier: "project",
+		Status:     evergreen.VersionSucceeded,
+		CreateTime: time.Now(),
+	}
+	require.NoError(t, v.Insert())
+	ref := ProjectRef{
+		Id:         "project",
+		Identifier: "project_name",
+	}
+	require.NoError(t, ref.Insert())
+	proj := &Project{
+		Identifier: "project",
+		BuildVariants: []BuildVariant{
+			{Name: "variant", Tasks: []BuildVariantTaskUnit{{Name: "task1"}, {Name: "task2"}}, RunOn: []string{"arch"}},
+			{Name: "variant2", Tasks: []BuildVariantTaskUnit{{Name: "task1"}}, RunOn: []string{"arch"}},
+		},
+		Tasks: []ProjectTask{{Name: "task1"}, {Name: "task2"}},
+	}
+
+	// Finalize the patch with only the first task, which has already finished.
+	_, err := addNewBuilds(context.Background(), specificActivationInfo{}, v, proj, VariantTasksToTVPairs(p.VariantsTasks), nil, p.SyncAtEndOpts, &ref, "")
+	require.NoError(t, err)
+	finishedTask, err := task.FindOne(db.Query(bson.M{task.DisplayNameKey: "task1", task.BuildVariantKey: "variant"}))
+	require.NoError(t, err)
+	require.NotNil(t, finishedTask)
+	require.NoError(t, finishedTask.MarkEnd(time.Now(), &apimodels.TaskEndDetail{Status: evergreen.TaskSucceeded}))
+
+	t.Run("FailsForUnfinalizedPatch", func(t *testing.T) {
+		unfinalized := &patch.Patch{Id: mgobson.NewObjectId()}
+		code, err := ExtendPatch(context.Background(), unfinalized, nil, proj, &ref, p.VariantsTasks)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+	t.Run("FailsForNonexistentTask", func(t *testing.T) {
+		v, err := VersionFindOneId("version")
+		require.NoError(t, err)
+		code, err := ExtendPatch(context.Background(), p, v, proj, &ref, []patch.VariantTasks{{Variant: "variant", Tasks: []string{"nonexistent"}}})
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+	t.Run("AddsOnlyMissingTasksAndBuilds", func(t *testing.T) {
+		v, err := VersionFindOneId("version")
+		require.NoError(t, err)
+		additions := []patch.VariantTasks{
+			{Variant: "variant", Tasks: []string{"task1", "task2"}},
+			{Variant: "variant2", Tasks: []string{"task1"}},
+		}
+		code, err := ExtendPatch(context.Background(), p, v, proj, &ref, additions)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, code)
+
+		tasks, err := task.FindAll(db.Query(bson.M{task.VersionKey: "version"}))
+		require.NoError(t, err)
+		assert.Len(t, tasks, 3)
+		for _, tsk := range tasks {
+			if tsk.Id == finishedTask.Id {
+				assert.Equal(t, evergreen.TaskSucceeded, tsk.Status, "existing results should be kept")
+			} else {
+				assert.Equal(t, evergreen.TaskUndispatched, tsk.Status)
+				assert.True(t, tsk.Activated)
+			}
+		}
+
+		v, err = VersionFindOneId("version")
+		require.NoError(t, err)
+		assert.Len(t, v.BuildIds, 2)
+		assert.False(t, evergreen.IsFinishedVersionStatus(v.Status))
+
+		dbPatch, err := patch.FindOneId(p.Id.Hex())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"variant", "variant2"}, dbPatch.BuildVariants)
+		assert.ElementsMatch(t, []string{"task1", "task2"}, dbPatch.Tasks)
+	})
+}
+
 func TestAddNewPatchWithMissingBaseVersion(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,228 @@
+package model
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Reasons that a patch can be rejected by a project's patch policy.
+const (
+	PatchPolicyDiffTooLarge = "diff_too_large"
+	PatchPolicyBannedFile   = "banned_file"
+	PatchPolicyStaleBase    = "stale_base"
+)
+
+// PatchPolicy restricts the patches that can be submitted to a project so that
+// problems are reported when the patch is created rather than when the diff is
+// applied on the host. Zero values are not enforced.
+type PatchPolicy struct {
+	// MaxDiffBytes is the largest diff that can be submitted.
+	MaxDiffBytes int `bson:"max_diff_bytes,omitempty" json:"max_diff_bytes,omitempty" yaml:"max_diff_bytes,omitempty"`
+	// BannedFiles are files that cannot be changed by patches, or can only
+	// be changed if their diff is small enough.
+	BannedFiles []PatchFilePolicy `bson:"banned_files,omitempty" json:"banned_files,omitempty" yaml:"banned_files,omitempty"`
+	// MaxBaseCommitsBehind is the most mainline commits the patch's base
+	// commit can be behind the latest mainline commit.
+	MaxBaseCommitsBehind int `bson:"max_base_commits_behind,omitempty" json:"max_base_commits_behind,omitempty" yaml:"max_base_commits_behind,omitempty"`
+}
+
+// PatchFilePolicy matches files that a patch cannot change.
+type PatchFilePolicy struct {
+	// Pattern is a glob matched against the file's path and its base name.
+	Pattern string `bson:"pattern" json:"pattern" yaml:"pattern"`
+	// MaxBytes, if set, allows the file to be changed as long as its diff
+	// is no larger than this.
+	MaxBytes int `bson:"max_bytes,omitempty" json:"max_bytes,omitempty" yaml:"max_bytes,omitempty"`
+}
+
+// PatchPolicyViolation is a reason that a patch was rejected.
+type PatchPolicyViolation struct {
+	Reason  string `json:"reason"`
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
+
+// PatchPolicyError is returned when a patch violates its project's patch
+// policy.
+type PatchPolicyError struct {
+	Violations []PatchPolicyViolation
+}
+
+func (e *PatchPolicyError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Message)
+	}
+	return fmt.Sprintf("patch violates the project's patch policy: %s", strings.Join(msgs, "; "))
+}
+
+// Validate checks that the policy is well-formed.
+func (p PatchPolicy) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(p.MaxDiffBytes < 0, "max diff bytes cannot be negative")
+	catcher.NewWhen(p.MaxBaseCommitsBehind < 0, "max base commits behind cannot be negative")
+	for _, f := range p.BannedFiles {
+		catcher.NewWhen(f.Pattern == "", "banned file pattern cannot be empty")
+		_, err := path.Match(f.Pattern, "")
+		catcher.Wrapf(err, "invalid banned file pattern '%s'", f.Pattern)
+		catcher.ErrorfWhen(f.MaxBytes < 0, "max bytes for banned file pattern '%s' cannot be negative", f.Pattern)
+	}
+	return catcher.Resolve()
+}
+
+// Check returns a PatchPolicyError with every way that the patch violates the
+// policy.
+func (p PatchPolicy) Check(projectID, diff, githash string) error {
+	var violations []PatchPolicyViolation
+	for _, err := range []error{p.CheckDiff(diff), p.CheckBase(projectID, githash)} {
+		if err == nil {
+			continue
+		}
+		policyErr, ok := err.(*PatchPolicyError)
+		if !ok {
+			return err
+		}
+		violations = append(violations, policyErr.Violations...)
+	}
+	if len(violations) > 0 {
+		return &PatchPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// CheckDiff returns a PatchPolicyError if the diff violates the size or file
+// restrictions of the policy.
+func (p PatchPolicy) CheckDiff(diff string) error {
+	var violations []PatchPolicyViolation
+	if p.MaxDiffBytes > 0 && len(diff) > p.MaxDiffBytes {
+		violations = append(violations, PatchPolicyViolation{
+			Reason:  PatchPolicyDiffTooLarge,
+			Message: fmt.Sprintf("diff is %d bytes, which exceeds the limit of %d bytes", len(diff), p.MaxDiffBytes),
+		})
+	}
+	if len(p.BannedFiles) > 0 {
+		for file, size := range diffSizesByFile(diff) {
+			for _, f := range p.BannedFiles {
+				if !matchesFilePattern(f.Pattern, file) {
+					continue
+				}
+				if f.MaxBytes == 0 {
+					violations = append(violations, PatchPolicyViolation{
+						Reason:  PatchPolicyBannedFile,
+						File:    file,
+						Message: fmt.Sprintf("file '%s' matches banned pattern '%s'", file, f.Pattern),
+					})
+					break
+				}
+				if size > f.MaxBytes {
+					violations = append(violations, PatchPolicyViolation{
+						Reason:  PatchPolicyBannedFile,
+						File:    file,
+						Message: fmt.Sprintf("diff for file '%s' is %d bytes, which exceeds the limit of %d bytes for pattern '%s'", file, size, f.MaxBytes, f.Pattern),
+					})
+					break
+				}
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return &PatchPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// CheckBase returns a PatchPolicyError if the patch's base commit is too far
+// behind the project's latest mainline commit. Base commits that Evergreen
+// has not created a version for are not checked.
+func (p PatchPolicy) CheckBase(projectID, githash string) error {
+	if p.MaxBaseCommitsBehind == 0 || githash == "" {
+		return nil
+	}
+	base, err := VersionFindOne(VersionByProjectIdAndRevisionPrefix(projectID, githash))
+	if err != nil {
+		return errors.Wrapf(err, "finding version for base commit '%s'", githash)
+	}
+	if base == nil {
+		return nil
+	}
+	numBehind, err := VersionCount(db.Query(bson.M{
+		VersionIdentifierKey:          projectID,
+		VersionRequesterKey:           evergreen.RepotrackerVersionRequester,
+		VersionRevisionOrderNumberKey: bson.M{"$gt": base.RevisionOrderNumber},
+	}))
+	if err != nil {
+		return errors.Wrap(err, "counting mainline commits after the base commit")
+	}
+	if numBehind > p.MaxBaseCommitsBehind {
+		return &PatchPolicyError{Violations: []PatchPolicyViolation{{
+			Reason:  PatchPolicyStaleBase,
+			Message: fmt.Sprintf("base commit '%s' is %d commits behind the latest mainline commit, which exceeds the limit of %d; rebase the patch onto a newer commit", githash, numBehind, p.MaxBaseCommitsBehind),
+		}}}
+	}
+	return nil
+}
+
+func matchesFilePattern(pattern, file string) bool {
+	if ok, _ := path.Match(pattern, file); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, path.Base(file))
+	return ok
+}
+
+// diffSizesByFile returns the number of bytes of the diff that change each
+// file.
+func diffSizesByFile(diff string) map[string]int {
+	sizes := map[string]int{}
+	var current string
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			current = diffHeaderPath(strings.TrimRight(strings.TrimPrefix(line, "diff --git "), "\r\n"))
+		}
+		if current != "" {
+			sizes[current] += len(line)
+		}
+	}
+	return sizes
+}
+
+// diffHeaderPath returns the new path of the file from the "a/<old> b/<new>"
+// part of a git diff header. Paths can contain spaces, so the header cannot
+// be split on whitespace.
+func diffHeaderPath(header string) string {
+	if strings.HasSuffix(header, `"`) {
+		// Git quotes paths that contain special characters.
+		start := strings.LastIndex(header[:len(header)-1], ` "`)
+		if start == -1 {
+			return ""
+		}
+		newPath, err := strconv.Unquote(header[start+1:])
+		if err != nil {
+			return ""
+		}
+		return strings.TrimPrefix(newPath, "b/")
+	}
+
+	// If the file was not renamed, both halves of the header are the same
+	// path, which can be found unambiguously even if it contains " b/".
+	if len(header)%2 == 1 {
+		half := len(header) / 2
+		oldPath, newPath := header[:half], header[half+1:]
+		if header[half] == ' ' && strings.HasPrefix(oldPath, "a/") && strings.HasPrefix(newPath, "b/") && oldPath[2:] == newPath[2:] {
+			return newPath[2:]
+		}
+	}
+
+	if i := strings.LastIndex(header, " b/"); i != -1 {
+		return header[i+len(" b/"):]
+	}
+	return ""
+}
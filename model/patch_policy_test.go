@@ -0,0 +1,119 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const patchPolicyTestDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-package main
++package main // changed
+diff --git a/testdata/blob.bin b/testdata/blob.bin
+new file mode 100644
+index 0000000..3333333
+GIT binary patch
+literal 16
+XcmZQzU|?oqWMXDvWn<@HWNBn(0Po%h7XSbN
+`
+
+func TestPatchPolicyCheckDiff(t *testing.T) {
+	t.Run("EmptyPolicyAllowsAnything", func(t *testing.T) {
+		assert.NoError(t, PatchPolicy{}.CheckDiff(patchPolicyTestDiff))
+	})
+	t.Run("RejectsLargeDiff", func(t *testing.T) {
+		err := PatchPolicy{MaxDiffBytes: 10}.CheckDiff(patchPolicyTestDiff)
+		require.Error(t, err)
+		policyErr, ok := err.(*PatchPolicyError)
+		require.True(t, ok)
+		require.Len(t, policyErr.Violations, 1)
+		assert.Equal(t, PatchPolicyDiffTooLarge, policyErr.Violations[0].Reason)
+	})
+	t.Run("RejectsBannedFile", func(t *testing.T) {
+		err := PatchPolicy{BannedFiles: []PatchFilePolicy{{Pattern: "*.bin"}}}.CheckDiff(patchPolicyTestDiff)
+		require.Error(t, err)
+		policyErr, ok := err.(*PatchPolicyError)
+		require.True(t, ok)
+		require.Len(t, policyErr.Violations, 1)
+		assert.Equal(t, PatchPolicyBannedFile, policyErr.Violations[0].Reason)
+		assert.Equal(t, "testdata/blob.bin", policyErr.Violations[0].File)
+	})
+	t.Run("AllowsBannedFileUnderSizeLimit", func(t *testing.T) {
+		assert.NoError(t, PatchPolicy{BannedFiles: []PatchFilePolicy{{Pattern: "testdata/*", MaxBytes: 1024}}}.CheckDiff(patchPolicyTestDiff))
+		assert.Error(t, PatchPolicy{BannedFiles: []PatchFilePolicy{{Pattern: "testdata/*", MaxBytes: 10}}}.CheckDiff(patchPolicyTestDiff))
+	})
+	t.Run("ValidateRejectsBadPattern", func(t *testing.T) {
+		assert.Error(t, PatchPolicy{BannedFiles: []PatchFilePolicy{{Pattern: "[", MaxBytes: 10}}}.Validate())
+		assert.Error(t, PatchPolicy{MaxDiffBytes: -1}.Validate())
+		assert.NoError(t, PatchPolicy{BannedFiles: []PatchFilePolicy{{Pattern: "*.bin"}}}.Validate())
+	})
+}
+
+func TestDiffSizesByFile(t *testing.T) {
+	diff := `diff --git a/docs/release notes.md b/docs/release notes.md
+index 1111111..2222222 100644
+--- a/docs/release notes.md	
++++ b/docs/release notes.md	
+@@ -1 +1 @@
+-old
++new
+diff --git a/old name.txt b/new name.txt
+similarity index 100%
+rename from old name.txt
+rename to new name.txt
+diff --git "a/tab\tname.txt" "b/tab\tname.txt"
+index 1111111..2222222 100644
+`
+	sizes := diffSizesByFile(diff)
+	assert.Len(t, sizes, 3)
+	assert.Contains(t, sizes, "docs/release notes.md")
+	assert.Contains(t, sizes, "new name.txt")
+	assert.Contains(t, sizes, "tab\tname.txt")
+
+	err := PatchPolicy{BannedFiles: []PatchFilePolicy{{Pattern: "release notes.md"}}}.CheckDiff(diff)
+	require.Error(t, err)
+	policyErr, ok := err.(*PatchPolicyError)
+	require.True(t, ok)
+	require.Len(t, policyErr.Violations, 1)
+	assert.Equal(t, "docs/release notes.md", policyErr.Violations[0].File)
+}
+
+func TestPatchPolicyCheckBase(t *testing.T) {
+	require.NoError(t, db.ClearCollections(VersionCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection))
+	}()
+	for i, c := range []string{"a", "b", "c", "d"} {
+		v := Version{
+			Id:                  "v" + c,
+			Identifier:          "p1",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			Revision:            strings.Repeat(c, 40),
+			RevisionOrderNumber: i + 1,
+		}
+		require.NoError(t, v.Insert())
+	}
+
+	policy := PatchPolicy{MaxBaseCommitsBehind: 2}
+	assert.NoError(t, policy.CheckBase("p1", strings.Repeat("b", 40)))
+	assert.NoError(t, policy.CheckBase("p1", strings.Repeat("e", 40)), "untracked base commits should not be checked")
+
+	err := policy.CheckBase("p1", strings.Repeat("a", 40))
+	require.Error(t, err)
+	policyErr, ok := err.(*PatchPolicyError)
+	require.True(t, ok)
+	require.Len(t, policyErr.Violations, 1)
+	assert.Equal(t, PatchPolicyStaleBase, policyErr.Violations[0].Reason)
+
+	err = policy.Check("p1", patchPolicyTestDiff, strings.Repeat("a", 40))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "3 commits behind")
+}
@@ -0,0 +1,288 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// PipelineStageInactive means none of the stage's tasks are activated.
+	PipelineStageInactive = "inactive"
+	// PipelineStagePending means the stage's tasks are activated but none of
+	// them have started.
+	PipelineStagePending = "pending"
+	// PipelineStageRunning means some of the stage's tasks have started and
+	// none of them have failed.
+	PipelineStageRunning = "running"
+	// PipelineStageFailed means at least one of the stage's tasks failed.
+	PipelineStageFailed = "failed"
+	// PipelineStageSuccess means all of the stage's activated tasks
+	// succeeded.
+	PipelineStageSuccess = "success"
+)
+
+// parserStage is a pipeline stage as it's written in the project YAML, before
+// its selectors are evaluated.
+type parserStage struct {
+	Name string `yaml:"name,omitempty" bson:"name,omitempty"`
+	// Tasks are the task and task group selectors for the stage.
+	Tasks parserStringSlice `yaml:"tasks,omitempty" bson:"tasks,omitempty"`
+	// Variants are the variant selectors for the stage. If none are given,
+	// the stage includes the selected tasks in every variant.
+	Variants parserStringSlice `yaml:"variants,omitempty" bson:"variants,omitempty"`
+	// After are the stages that must succeed before this stage runs. If none
+	// are given, the stage runs after the stage before it in the list.
+	After parserStringSlice `yaml:"after,omitempty" bson:"after,omitempty"`
+}
+
+// PipelineStage is an ordered group of tasks across variants, such as build,
+// test, package, and deploy. Every task in a stage depends on the tasks in its
+// own variant in the stages that it runs after, or on all of a stage's tasks if
+// that stage has none in its variant.
+type PipelineStage struct {
+	Name string `yaml:"name" bson:"name"`
+	// After are the names of the stages that this stage runs after.
+	After []string `yaml:"after,omitempty" bson:"after,omitempty"`
+	// Tasks are the build variant task units in the stage, which may be task
+	// groups.
+	Tasks []TVPair `yaml:"tasks,omitempty" bson:"tasks,omitempty"`
+}
+
+// evaluateStages evaluates the stages' task and variant selectors against the
+// translated project.
+func evaluateStages(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluator, vse *variantSelectorEvaluator, pss []parserStage, proj *Project) ([]PipelineStage, []error) {
+	var evalErrs []error
+	stages := make([]PipelineStage, 0, len(pss))
+	for i, ps := range pss {
+		stage := PipelineStage{Name: ps.Name, After: ps.After}
+		if len(stage.After) == 0 && i > 0 {
+			stage.After = []string{pss[i-1].Name}
+		}
+
+		taskNames := map[string]bool{}
+		for _, selector := range ps.Tasks {
+			var names, temp []string
+			var err1, err2 error
+			if tse != nil {
+				temp, err1 = tse.evalSelector(ParseSelector(selector))
+				names = append(names, temp...)
+			}
+			if tgse != nil {
+				temp, err2 = tgse.evalSelector(ParseSelector(selector))
+				names = append(names, temp...)
+			}
+			if err1 != nil && err2 != nil {
+				evalErrs = append(evalErrs, errors.Wrapf(err1, "evaluating tasks for stage '%s'", ps.Name), errors.Wrapf(err2, "evaluating task groups for stage '%s'", ps.Name))
+				continue
+			}
+			for _, name := range names {
+				taskNames[name] = true
+			}
+		}
+
+		variantNames := map[string]bool{}
+		if len(ps.Variants) == 0 {
+			for _, bv := range proj.BuildVariants {
+				variantNames[bv.Name] = true
+			}
+		}
+		for _, selector := range ps.Variants {
+			names, err := vse.evalSelector(&variantSelector{StringSelector: selector})
+			if err != nil {
+				evalErrs = append(evalErrs, errors.Wrapf(err, "evaluating variants for stage '%s'", ps.Name))
+				continue
+			}
+			for _, name := range names {
+				variantNames[name] = true
+			}
+		}
+
+		for _, bv := range proj.BuildVariants {
+			if !variantNames[bv.Name] {
+				continue
+			}
+			for _, bvt := range bv.Tasks {
+				if taskNames[bvt.Name] {
+					stage.Tasks = append(stage.Tasks, TVPair{Variant: bv.Name, TaskName: bvt.Name})
+				}
+			}
+		}
+		stages = append(stages, stage)
+	}
+	return stages, evalErrs
+}
+
+// addStageDependencies makes the tasks in each stage depend on the tasks of
+// the stages that they run after. A task only depends on the earlier stage's
+// tasks in its own variant unless that stage has none there, so that stages
+// spanning many variants don't make every task depend on every other. Stages
+// that run after an unknown stage are left for the validator to report.
+func addStageDependencies(proj *Project) {
+	if len(proj.Stages) == 0 {
+		return
+	}
+	stageTasksByVariant := map[string]map[string][]TVPair{}
+	for _, stage := range proj.Stages {
+		byVariant := map[string][]TVPair{}
+		for _, member := range stage.Tasks {
+			byVariant[member.Variant] = append(byVariant[member.Variant], member)
+		}
+		stageTasksByVariant[stage.Name] = byVariant
+	}
+	stagesByName := map[string]PipelineStage{}
+	for _, stage := range proj.Stages {
+		stagesByName[stage.Name] = stage
+	}
+	predecessors := map[TVPair][]TVPair{}
+	for _, stage := range proj.Stages {
+		for _, member := range stage.Tasks {
+			for _, after := range stage.After {
+				if sameVariant := stageTasksByVariant[after][member.Variant]; len(sameVariant) > 0 {
+					predecessors[member] = append(predecessors[member], sameVariant...)
+					continue
+				}
+				predecessors[member] = append(predecessors[member], stagesByName[after].Tasks...)
+			}
+		}
+	}
+
+	for i, bv := range proj.BuildVariants {
+		for j, bvt := range bv.Tasks {
+			member := TVPair{Variant: bv.Name, TaskName: bvt.Name}
+			deps := predecessors[member]
+			if len(deps) == 0 {
+				continue
+			}
+			existing := map[TVPair]bool{}
+			for _, d := range bvt.DependsOn {
+				// A dependency without a variant is on the task in the same
+				// variant.
+				variant := d.Variant
+				if variant == "" {
+					variant = bv.Name
+				}
+				existing[TVPair{Variant: variant, TaskName: d.Name}] = true
+			}
+			for _, dep := range deps {
+				if dep == member || existing[dep] {
+					continue
+				}
+				existing[dep] = true
+				proj.BuildVariants[i].Tasks[j].DependsOn = append(proj.BuildVariants[i].Tasks[j].DependsOn, TaskUnitDependency{
+					Name:    dep.TaskName,
+					Variant: dep.Variant,
+				})
+			}
+		}
+	}
+}
+
+// VersionStageStatus is the status of a pipeline stage in a version.
+type VersionStageStatus struct {
+	Name  string
+	After []string
+	// Status is the overall status of the stage's tasks.
+	Status string
+	// TaskIDs are the version's tasks that are in the stage.
+	TaskIDs []string
+	// TaskStatusCounts counts the stage's tasks by their display status.
+	TaskStatusCounts map[string]int
+}
+
+// NewVersionStages returns the project's pipeline stages to store on a new
+// version, with the task groups in each stage expanded into their tasks.
+func (p *Project) NewVersionStages() []PipelineStage {
+	if len(p.Stages) == 0 {
+		return nil
+	}
+	stages := make([]PipelineStage, 0, len(p.Stages))
+	for _, stage := range p.Stages {
+		expanded := PipelineStage{Name: stage.Name, After: stage.After}
+		for _, member := range stage.Tasks {
+			tg := p.FindTaskGroup(member.TaskName)
+			if tg == nil {
+				expanded.Tasks = append(expanded.Tasks, member)
+				continue
+			}
+			for _, name := range tg.Tasks {
+				expanded.Tasks = append(expanded.Tasks, TVPair{Variant: member.Variant, TaskName: name})
+			}
+		}
+		stages = append(stages, expanded)
+	}
+	return stages
+}
+
+// GetVersionStageStatuses returns the status of each of the version's pipeline
+// stages, in the order that the stages are defined. It returns nothing if the
+// version has no stages stored on it.
+func GetVersionStageStatuses(v *Version) ([]VersionStageStatus, error) {
+	if len(v.Stages) == 0 {
+		return nil, nil
+	}
+
+	tasks, err := task.FindWithFields(bson.M{task.VersionKey: v.Id},
+		task.IdKey, task.DisplayNameKey, task.BuildVariantKey, task.StatusKey, task.ActivatedKey, task.AbortedKey,
+		task.DetailsKey, task.DependsOnKey, task.OverrideDependenciesKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding tasks for version '%s'", v.Id)
+	}
+	return stageStatuses(v.Stages, tasks), nil
+}
+
+// stageStatuses aggregates the tasks' statuses by the version stage that each
+// task is in.
+func stageStatuses(stages []PipelineStage, tasks []task.Task) []VersionStageStatus {
+	tasksByPair := map[TVPair]task.Task{}
+	for _, t := range tasks {
+		tasksByPair[TVPair{Variant: t.BuildVariant, TaskName: t.DisplayName}] = t
+	}
+
+	statuses := make([]VersionStageStatus, 0, len(stages))
+	for _, stage := range stages {
+		status := VersionStageStatus{
+			Name:             stage.Name,
+			After:            stage.After,
+			TaskIDs:          []string{},
+			TaskStatusCounts: map[string]int{},
+		}
+		var numActivated, numStarted, numSucceeded, numFailed int
+		for _, member := range stage.Tasks {
+			t, ok := tasksByPair[member]
+			if !ok {
+				continue
+			}
+			status.TaskIDs = append(status.TaskIDs, t.Id)
+			status.TaskStatusCounts[t.GetDisplayStatus()]++
+			if !t.Activated {
+				continue
+			}
+			numActivated++
+			switch {
+			case t.Status == evergreen.TaskSucceeded || t.Status == evergreen.TaskSkipped:
+				numSucceeded++
+			case evergreen.IsFailedTaskStatus(t.Status):
+				numFailed++
+			case t.Status != evergreen.TaskUndispatched:
+				numStarted++
+			}
+		}
+
+		switch {
+		case numActivated == 0:
+			status.Status = PipelineStageInactive
+		case numFailed > 0:
+			status.Status = PipelineStageFailed
+		case numSucceeded == numActivated:
+			status.Status = PipelineStageSuccess
+		case numStarted > 0 || numSucceeded > 0:
+			status.Status = PipelineStageRunning
+		default:
+			status.Status = PipelineStagePending
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
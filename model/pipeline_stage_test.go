@@ -0,0 +1,185 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineStages(t *testing.T) {
+	yml := `
+tasks:
+- name: compile
+  tags: ["build"]
+- name: unit
+  tags: ["test"]
+- name: integration
+  tags: ["test"]
+  depends_on:
+  - name: unit
+- name: upload
+- name: deploy
+task_groups:
+- name: package_group
+  tasks:
+  - upload
+buildvariants:
+- name: linux
+  tasks:
+  - name: compile
+  - name: unit
+  - name: integration
+  - name: package_group
+  - name: deploy
+- name: windows
+  tasks:
+  - name: compile
+  - name: unit
+  - name: deploy
+stages:
+- name: build
+  tasks: [".build"]
+- name: test
+  tasks: [".test"]
+- name: package
+  tasks: ["package_group"]
+  variants: ["linux"]
+- name: deploy
+  tasks: ["deploy"]
+  after: ["test", "package"]
+`
+	p := &Project{}
+	_, err := LoadProjectInto(context.Background(), []byte(yml), nil, "id", p)
+	require.NoError(t, err)
+	require.Len(t, p.Stages, 4)
+
+	t.Run("StagesRunAfterThePreviousStageByDefault", func(t *testing.T) {
+		assert.Empty(t, p.Stages[0].After)
+		assert.Equal(t, []string{"build"}, p.Stages[1].After)
+		assert.Equal(t, []string{"test"}, p.Stages[2].After)
+		assert.Equal(t, []string{"test", "package"}, p.Stages[3].After)
+	})
+	t.Run("StagesSelectTasksAcrossVariants", func(t *testing.T) {
+		assert.ElementsMatch(t, []TVPair{{Variant: "linux", TaskName: "compile"}, {Variant: "windows", TaskName: "compile"}}, p.Stages[0].Tasks)
+		assert.ElementsMatch(t, []TVPair{
+			{Variant: "linux", TaskName: "unit"},
+			{Variant: "linux", TaskName: "integration"},
+			{Variant: "windows", TaskName: "unit"},
+		}, p.Stages[1].Tasks)
+		assert.Equal(t, []TVPair{{Variant: "linux", TaskName: "package_group"}}, p.Stages[2].Tasks)
+	})
+	t.Run("TasksDependOnThePreviousStages", func(t *testing.T) {
+		unit := p.FindTaskForVariant("unit", "windows")
+		require.NotNil(t, unit)
+		assert.ElementsMatch(t, []TaskUnitDependency{
+			{Name: "compile", Variant: "windows"},
+		}, unit.DependsOn)
+
+		compile := p.FindTaskForVariant("compile", "linux")
+		require.NotNil(t, compile)
+		assert.Empty(t, compile.DependsOn)
+	})
+	t.Run("ExplicitDependenciesAreKept", func(t *testing.T) {
+		integration := p.FindTaskForVariant("integration", "linux")
+		require.NotNil(t, integration)
+		assert.ElementsMatch(t, []TaskUnitDependency{
+			{Name: "unit"},
+			{Name: "compile", Variant: "linux"},
+		}, integration.DependsOn)
+	})
+	t.Run("DependenciesOnTaskGroupStagesAreExpanded", func(t *testing.T) {
+		deploy := p.FindTaskForVariant("deploy", "linux")
+		require.NotNil(t, deploy)
+		assert.ElementsMatch(t, []TaskUnitDependency{
+			{Name: "unit", Variant: "linux"},
+			{Name: "integration", Variant: "linux"},
+			{Name: "upload", Variant: "linux"},
+		}, deploy.DependsOn)
+	})
+	t.Run("TasksDependOnAllOfAStageWithoutTasksInTheirVariant", func(t *testing.T) {
+		deploy := p.FindTaskForVariant("deploy", "windows")
+		require.NotNil(t, deploy)
+		assert.ElementsMatch(t, []TaskUnitDependency{
+			{Name: "unit", Variant: "windows"},
+			{Name: "upload", Variant: "linux"},
+		}, deploy.DependsOn)
+	})
+	t.Run("VersionStagesExpandTaskGroups", func(t *testing.T) {
+		stages := p.NewVersionStages()
+		require.Len(t, stages, 4)
+		assert.Equal(t, []TVPair{{Variant: "linux", TaskName: "upload"}}, stages[2].Tasks)
+		assert.Equal(t, []string{"test"}, stages[2].After)
+		assert.Equal(t, p.Stages[0].Tasks, stages[0].Tasks)
+	})
+}
+
+func TestPipelineStagesInvalidSelector(t *testing.T) {
+	yml := `
+tasks:
+- name: compile
+buildvariants:
+- name: linux
+  tasks:
+  - name: compile
+stages:
+- name: build
+  tasks: ["nonexistent"]
+`
+	p := &Project{}
+	_, err := LoadProjectInto(context.Background(), []byte(yml), nil, "id", p)
+	assert.Error(t, err)
+}
+
+func TestMergePipelineStages(t *testing.T) {
+	main := &ParserProject{Stages: []parserStage{{Name: "build"}}}
+	assert.NoError(t, main.mergeOrderedUnique(&ParserProject{}))
+	assert.Len(t, main.Stages, 1)
+	assert.Error(t, main.mergeOrderedUnique(&ParserProject{Stages: []parserStage{{Name: "test"}}}))
+
+	empty := &ParserProject{}
+	assert.NoError(t, empty.mergeOrderedUnique(&ParserProject{Stages: []parserStage{{Name: "test"}}}))
+	require.Len(t, empty.Stages, 1)
+	assert.Equal(t, "test", empty.Stages[0].Name)
+}
+
+func TestStageStatuses(t *testing.T) {
+	stages := []PipelineStage{
+		{Name: "build", Tasks: []TVPair{{Variant: "linux", TaskName: "compile"}, {Variant: "windows", TaskName: "compile"}}},
+		{Name: "test", After: []string{"build"}, Tasks: []TVPair{{Variant: "linux", TaskName: "unit"}, {Variant: "windows", TaskName: "unit"}}},
+		{Name: "package", After: []string{"test"}, Tasks: []TVPair{{Variant: "linux", TaskName: "upload"}, {Variant: "linux", TaskName: "sign"}}},
+		{Name: "deploy", After: []string{"package"}, Tasks: []TVPair{{Variant: "linux", TaskName: "deploy"}}},
+		{Name: "publish", After: []string{"deploy"}, Tasks: []TVPair{{Variant: "linux", TaskName: "publish"}}},
+	}
+	tasks := []task.Task{
+		{Id: "compile_linux", DisplayName: "compile", BuildVariant: "linux", Activated: true, Status: evergreen.TaskSucceeded},
+		{Id: "compile_windows", DisplayName: "compile", BuildVariant: "windows", Activated: true, Status: evergreen.TaskSucceeded},
+		{Id: "unit_linux", DisplayName: "unit", BuildVariant: "linux", Activated: true, Status: evergreen.TaskFailed},
+		{Id: "unit_windows", DisplayName: "unit", BuildVariant: "windows", Activated: true, Status: evergreen.TaskStarted},
+		{Id: "upload_linux", DisplayName: "upload", BuildVariant: "linux", Activated: true, Status: evergreen.TaskSucceeded},
+		{Id: "sign_linux", DisplayName: "sign", BuildVariant: "linux", Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "deploy_linux", DisplayName: "deploy", BuildVariant: "linux", Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "publish_linux", DisplayName: "publish", BuildVariant: "linux", Status: evergreen.TaskUndispatched},
+	}
+
+	statuses := stageStatuses(stages, tasks)
+	require.Len(t, statuses, 5)
+
+	assert.Equal(t, "build", statuses[0].Name)
+	assert.Equal(t, PipelineStageSuccess, statuses[0].Status)
+	assert.ElementsMatch(t, []string{"compile_linux", "compile_windows"}, statuses[0].TaskIDs)
+	assert.Equal(t, map[string]int{evergreen.TaskSucceeded: 2}, statuses[0].TaskStatusCounts)
+
+	assert.Equal(t, PipelineStageFailed, statuses[1].Status)
+	assert.Equal(t, []string{"build"}, statuses[1].After)
+
+	assert.Equal(t, PipelineStageRunning, statuses[2].Status)
+	assert.ElementsMatch(t, []string{"upload_linux", "sign_linux"}, statuses[2].TaskIDs)
+
+	assert.Equal(t, PipelineStagePending, statuses[3].Status)
+	assert.Equal(t, PipelineStageInactive, statuses[4].Status)
+	assert.Equal(t, map[string]int{evergreen.TaskUnscheduled: 1}, statuses[4].TaskStatusCounts)
+}
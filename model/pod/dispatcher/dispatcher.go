@@ -292,6 +292,28 @@ func (pd *PodDispatcher) checkTaskIsDispatchable(ctx context.Context, env evergr
 		return false, nil
 	}
 
+	// A task that was quarantined after it was allocated a container is
+	// skipped rather than dispatched.
+	remaining, err := model.SkipQuarantinedTasks([]task.Task{*t})
+	grip.Error(message.WrapError(err, message.Fields{
+		"message":    "could not skip quarantined task",
+		"context":    "pod group task dispatcher",
+		"task":       t.Id,
+		"project":    t.Project,
+		"dispatcher": pd.ID,
+	}))
+	if len(remaining) == 0 {
+		grip.Notice(message.Fields{
+			"message":    "task is quarantined",
+			"outcome":    "task is not dispatchable",
+			"context":    "pod group task dispatcher",
+			"task":       t.Id,
+			"project":    t.Project,
+			"dispatcher": pd.ID,
+		})
+		return false, nil
+	}
+
 	return true, nil
 }
 
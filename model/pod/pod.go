@@ -51,15 +51,16 @@ type TaskIntentPodOptions struct {
 	// The remaining fields correspond to the ones in
 	// TaskContainerCreationOptions.
 
-	CPU            int
-	MemoryMB       int
-	OS             OS
-	Arch           Arch
-	WindowsVersion WindowsVersion
-	Image          string
-	WorkingDir     string
-	RepoUsername   string
-	RepoPassword   string
+	CPU                     int
+	MemoryMB                int
+	OS                      OS
+	Arch                    Arch
+	WindowsVersion          WindowsVersion
+	Image                   string
+	WorkingDir              string
+	RepoUsername            string
+	RepoPassword            string
+	RepoCredsExternalSecret string
 }
 
 // Validate checks that the options to create a task intent pod are valid and
@@ -75,6 +76,7 @@ func (o *TaskIntentPodOptions) Validate() error {
 	}
 	catcher.NewWhen(o.Image == "", "missing image")
 	catcher.NewWhen(o.WorkingDir == "", "missing working directory")
+	catcher.NewWhen(o.RepoCredsExternalSecret != "" && (o.RepoUsername != "" || o.RepoPassword != ""), "cannot specify both repository credentials and an external secret containing repository credentials")
 
 	if catcher.HasErrors() {
 		return catcher.Resolve()
@@ -111,15 +113,16 @@ func NewTaskIntentPod(opts TaskIntentPodOptions) (*Pod, error) {
 		Status: StatusInitializing,
 		Type:   TypeAgent,
 		TaskContainerCreationOpts: TaskContainerCreationOptions{
-			CPU:            opts.CPU,
-			MemoryMB:       opts.MemoryMB,
-			OS:             opts.OS,
-			Arch:           opts.Arch,
-			WindowsVersion: opts.WindowsVersion,
-			Image:          opts.Image,
-			WorkingDir:     opts.WorkingDir,
-			RepoUsername:   opts.RepoUsername,
-			RepoPassword:   opts.RepoPassword,
+			CPU:                     opts.CPU,
+			MemoryMB:                opts.MemoryMB,
+			OS:                      opts.OS,
+			Arch:                    opts.Arch,
+			WindowsVersion:          opts.WindowsVersion,
+			Image:                   opts.Image,
+			WorkingDir:              opts.WorkingDir,
+			RepoUsername:            opts.RepoUsername,
+			RepoPassword:            opts.RepoPassword,
+			RepoCredsExternalSecret: opts.RepoCredsExternalSecret,
 		},
 		TimeInfo: TimeInfo{
 			Initializing: time.Now(),
@@ -252,6 +255,12 @@ type TaskContainerCreationOptions struct {
 	// RepoPassword is the password of the repository containing the image. This
 	// is only necessary if it is a private repository.
 	RepoPassword string `bson:"repo_password,omitempty" json:"repo_password,omitempty"`
+	// RepoCredsExternalSecret is the ARN or name of an existing secret in
+	// Secrets Manager that contains the credentials for the repository
+	// containing the image. It is used instead of RepoUsername and
+	// RepoPassword for credentials that are managed outside of Evergreen, so
+	// the pod does not own the secret.
+	RepoCredsExternalSecret string `bson:"repo_creds_external_secret,omitempty" json:"repo_creds_external_secret,omitempty"`
 	// MemoryMB is the memory (in MB) that the task's container will be
 	// allocated.
 	MemoryMB int `bson:"memory_mb" json:"memory_mb"`
@@ -420,7 +429,7 @@ func ImportWindowsVersion(winVer evergreen.WindowsVersion) (WindowsVersion, erro
 // IsZero implements the bsoncodec.Zeroer interface for the sake of defining the
 // zero value for BSON marshalling.
 func (o TaskContainerCreationOptions) IsZero() bool {
-	return o.MemoryMB == 0 && o.CPU == 0 && o.OS == "" && o.Arch == "" && o.WindowsVersion == "" && o.Image == "" && o.RepoUsername == "" && o.RepoPassword == "" && o.WorkingDir == "" && len(o.EnvVars) == 0 && len(o.EnvSecrets) == 0
+	return o.MemoryMB == 0 && o.CPU == 0 && o.OS == "" && o.Arch == "" && o.WindowsVersion == "" && o.Image == "" && o.RepoUsername == "" && o.RepoPassword == "" && o.RepoCredsExternalSecret == "" && o.WorkingDir == "" && len(o.EnvVars) == 0 && len(o.EnvSecrets) == 0
 }
 
 // Secret is a sensitive secret that a pod can access. The secret is managed
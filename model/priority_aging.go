@@ -0,0 +1,206 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// defaultPriorityAgingMaxBoost is the most that aging can increase a
+	// task's priority if the project doesn't set its own limit.
+	defaultPriorityAgingMaxBoost = 50
+	// DefaultStarvationThreshold is how long a task can wait to be
+	// dispatched before it's reported as starved if the project doesn't set
+	// its own threshold.
+	DefaultStarvationThreshold = 4 * time.Hour
+)
+
+// PriorityAgingSettings control how a project's tasks gain priority while
+// they wait to be dispatched, so that low priority tasks are not starved by
+// a continuous stream of higher priority tasks.
+type PriorityAgingSettings struct {
+	// PriorityPerHour is how much a task's effective priority increases for
+	// each hour that it waits to be dispatched. Aging is disabled if this is
+	// not positive.
+	PriorityPerHour int64 `bson:"priority_per_hour,omitempty" json:"priority_per_hour,omitempty" yaml:"priority_per_hour,omitempty"`
+	// MaxBoost is the most that aging can increase a task's priority.
+	MaxBoost int64 `bson:"max_boost,omitempty" json:"max_boost,omitempty" yaml:"max_boost,omitempty"`
+	// StarvationThresholdMins is how long a task can wait to be dispatched
+	// before it's reported as starved.
+	StarvationThresholdMins int `bson:"starvation_threshold_mins,omitempty" json:"starvation_threshold_mins,omitempty" yaml:"starvation_threshold_mins,omitempty"`
+}
+
+// Validate checks that the priority aging settings are sensible.
+func (s PriorityAgingSettings) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(s.PriorityPerHour < 0, "priority per hour cannot be negative")
+	catcher.NewWhen(s.MaxBoost < 0, "max boost cannot be negative")
+	catcher.NewWhen(s.StarvationThresholdMins < 0, "starvation threshold cannot be negative")
+	return catcher.Resolve()
+}
+
+// IsEnabled returns whether the project's tasks gain priority while they
+// wait.
+func (s PriorityAgingSettings) IsEnabled() bool {
+	return s.PriorityPerHour > 0
+}
+
+// GetMaxBoost returns the most that aging can increase a task's priority.
+func (s PriorityAgingSettings) GetMaxBoost() int64 {
+	if s.MaxBoost <= 0 {
+		return defaultPriorityAgingMaxBoost
+	}
+	return s.MaxBoost
+}
+
+// GetStarvationThreshold returns how long a task can wait to be dispatched
+// before it's reported as starved.
+func (s PriorityAgingSettings) GetStarvationThreshold() time.Duration {
+	if s.StarvationThresholdMins <= 0 {
+		return DefaultStarvationThreshold
+	}
+	return time.Duration(s.StarvationThresholdMins) * time.Minute
+}
+
+// EffectivePriority returns the task's priority after it has aged for as
+// long as it has been waiting to be dispatched. Disabled tasks do not age.
+func (s PriorityAgingSettings) EffectivePriority(t *task.Task, now time.Time) int64 {
+	if !s.IsEnabled() || t.Priority <= evergreen.DisabledTaskPriority {
+		return t.Priority
+	}
+	boost := s.PriorityPerHour * int64(TaskWaitTime(t, now).Hours())
+	if maxBoost := s.GetMaxBoost(); boost > maxBoost {
+		boost = maxBoost
+	}
+	return t.Priority + boost
+}
+
+// TaskWaitTime returns how long the task has been waiting to be dispatched.
+// A task only starts waiting once it's activated and its dependencies are
+// met.
+func TaskWaitTime(t *task.Task, now time.Time) time.Duration {
+	waitingSince := t.ActivatedTime
+	if utility.IsZeroTime(waitingSince) {
+		waitingSince = t.IngestTime
+	}
+	if t.DependenciesMetTime.After(waitingSince) {
+		waitingSince = t.DependenciesMetTime
+	}
+	if utility.IsZeroTime(waitingSince) || now.Before(waitingSince) {
+		return 0
+	}
+	return now.Sub(waitingSince)
+}
+
+// ApplyPriorityAging sets the priority of each task that belongs to a
+// project with priority aging enabled to its effective priority. The tasks
+// are only modified in memory so that they're ordered by their effective
+// priority when they're planned.
+func ApplyPriorityAging(tasks []task.Task, now time.Time) ([]task.Task, error) {
+	projectIDs := []string{}
+	for _, t := range tasks {
+		if !utility.StringSliceContains(projectIDs, t.Project) {
+			projectIDs = append(projectIDs, t.Project)
+		}
+	}
+	pRefs, err := FindProjectRefsByIds(projectIDs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding projects for tasks")
+	}
+	aging := map[string]PriorityAgingSettings{}
+	for _, pRef := range pRefs {
+		if pRef.PriorityAging.IsEnabled() {
+			aging[pRef.Id] = pRef.PriorityAging
+		}
+	}
+	if len(aging) == 0 {
+		return tasks, nil
+	}
+
+	for i := range tasks {
+		settings, ok := aging[tasks[i].Project]
+		if !ok {
+			continue
+		}
+		tasks[i].Priority = settings.EffectivePriority(&tasks[i], now)
+	}
+	return tasks, nil
+}
+
+// StarvedTask is a task that has been waiting to be dispatched for longer
+// than its project's starvation threshold.
+type StarvedTask struct {
+	TaskID            string
+	DisplayName       string
+	BuildVariant      string
+	Version           string
+	Requester         string
+	DistroID          string
+	Priority          int64
+	EffectivePriority int64
+	WaitingSince      time.Time
+	WaitTime          time.Duration
+}
+
+// FindStarvedTasks returns the project's tasks that have been waiting to be
+// dispatched for at least the threshold, from the longest waiting. If the
+// threshold is zero, the project's starvation threshold is used.
+func FindStarvedTasks(pRef *ProjectRef, threshold time.Duration, limit int) ([]StarvedTask, error) {
+	if threshold <= 0 {
+		threshold = pRef.PriorityAging.GetStarvationThreshold()
+	}
+	now := time.Now()
+	q := db.Query(bson.M{
+		task.ProjectKey:       pRef.Id,
+		task.StatusKey:        evergreen.TaskUndispatched,
+		task.ActivatedKey:     true,
+		task.DisplayOnlyKey:   bson.M{"$ne": true},
+		task.PriorityKey:      bson.M{"$gt": evergreen.DisabledTaskPriority},
+		task.ActivatedTimeKey: bson.M{"$lte": now.Add(-threshold)},
+	})
+	tasks, err := task.FindAll(q)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding waiting tasks for project '%s'", pRef.Id)
+	}
+
+	starved := []StarvedTask{}
+	for i := range tasks {
+		t := &tasks[i]
+		if len(t.DependsOn) > 0 && !t.OverrideDependencies && utility.IsZeroTime(t.DependenciesMetTime) {
+			// Tasks that are waiting on their dependencies are not waiting
+			// to be dispatched.
+			continue
+		}
+		waitTime := TaskWaitTime(t, now)
+		if waitTime < threshold {
+			continue
+		}
+		starved = append(starved, StarvedTask{
+			TaskID:            t.Id,
+			DisplayName:       t.DisplayName,
+			BuildVariant:      t.BuildVariant,
+			Version:           t.Version,
+			Requester:         t.Requester,
+			DistroID:          t.DistroId,
+			Priority:          t.Priority,
+			EffectivePriority: pRef.PriorityAging.EffectivePriority(t, now),
+			WaitingSince:      now.Add(-waitTime),
+			WaitTime:          waitTime,
+		})
+	}
+	sort.SliceStable(starved, func(i, j int) bool {
+		return starved[i].WaitTime > starved[j].WaitTime
+	})
+	if limit > 0 && len(starved) > limit {
+		starved = starved[:limit]
+	}
+	return starved, nil
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityAgingEffectivePriority(t *testing.T) {
+	now := time.Now()
+	settings := PriorityAgingSettings{PriorityPerHour: 2, MaxBoost: 10}
+
+	for name, tCase := range map[string]struct {
+		settings PriorityAgingSettings
+		tsk      task.Task
+		expected int64
+	}{
+		"AgesWithWaitTime": {
+			settings: settings,
+			tsk:      task.Task{Priority: 1, ActivatedTime: now.Add(-3 * time.Hour)},
+			expected: 7,
+		},
+		"IsCappedAtMaxBoost": {
+			settings: settings,
+			tsk:      task.Task{Priority: 1, ActivatedTime: now.Add(-24 * time.Hour)},
+			expected: 11,
+		},
+		"StartsWaitingWhenDependenciesAreMet": {
+			settings: settings,
+			tsk:      task.Task{Priority: 0, ActivatedTime: now.Add(-5 * time.Hour), DependenciesMetTime: now.Add(-time.Hour)},
+			expected: 2,
+		},
+		"DisabledTaskDoesNotAge": {
+			settings: settings,
+			tsk:      task.Task{Priority: evergreen.DisabledTaskPriority, ActivatedTime: now.Add(-3 * time.Hour)},
+			expected: evergreen.DisabledTaskPriority,
+		},
+		"DoesNotAgeWhenDisabled": {
+			tsk:      task.Task{Priority: 1, ActivatedTime: now.Add(-3 * time.Hour)},
+			expected: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tCase.expected, tCase.settings.EffectivePriority(&tCase.tsk, now))
+		})
+	}
+}
+
+func TestApplyPriorityAging(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ProjectRefCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection))
+	}()
+
+	aging := ProjectRef{Id: "aging", PriorityAging: PriorityAgingSettings{PriorityPerHour: 5}}
+	require.NoError(t, aging.Insert())
+	notAging := ProjectRef{Id: "not_aging"}
+	require.NoError(t, notAging.Insert())
+
+	now := time.Now()
+	tasks := []task.Task{
+		{Id: "t1", Project: aging.Id, Priority: 1, ActivatedTime: now.Add(-2 * time.Hour)},
+		{Id: "t2", Project: notAging.Id, Priority: 1, ActivatedTime: now.Add(-2 * time.Hour)},
+	}
+	tasks, err := ApplyPriorityAging(tasks, now)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.EqualValues(t, 11, tasks[0].Priority)
+	assert.EqualValues(t, 1, tasks[1].Priority)
+}
+
+func TestFindStarvedTasks(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection))
+	}()
+
+	pRef := &ProjectRef{Id: "p1", PriorityAging: PriorityAgingSettings{PriorityPerHour: 1, StarvationThresholdMins: 60}}
+	now := time.Now()
+	waiting := func(id string, activated time.Time) task.Task {
+		return task.Task{
+			Id:            id,
+			Project:       pRef.Id,
+			Status:        evergreen.TaskUndispatched,
+			Activated:     true,
+			ActivatedTime: activated,
+		}
+	}
+	longest := waiting("longest", now.Add(-5*time.Hour))
+	starved := waiting("starved", now.Add(-2*time.Hour))
+	recent := waiting("recent", now.Add(-10*time.Minute))
+	blocked := waiting("blocked", now.Add(-5*time.Hour))
+	blocked.DependsOn = []task.Dependency{{TaskId: "dep"}}
+	recentlyUnblocked := waiting("recently_unblocked", now.Add(-5*time.Hour))
+	recentlyUnblocked.DependsOn = []task.Dependency{{TaskId: "dep"}}
+	recentlyUnblocked.DependenciesMetTime = now.Add(-10 * time.Minute)
+	disabled := waiting("disabled", now.Add(-5*time.Hour))
+	disabled.Priority = evergreen.DisabledTaskPriority
+	otherProject := waiting("other_project", now.Add(-5*time.Hour))
+	otherProject.Project = "p2"
+	for _, tsk := range []task.Task{longest, starved, recent, blocked, recentlyUnblocked, disabled, otherProject} {
+		require.NoError(t, tsk.Insert())
+	}
+
+	t.Run("UsesProjectThreshold", func(t *testing.T) {
+		tasks, err := FindStarvedTasks(pRef, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		assert.Equal(t, longest.Id, tasks[0].TaskID)
+		assert.EqualValues(t, 5, tasks[0].EffectivePriority)
+		assert.Equal(t, starved.Id, tasks[1].TaskID)
+	})
+	t.Run("UsesGivenThreshold", func(t *testing.T) {
+		tasks, err := FindStarvedTasks(pRef, 3*time.Hour, 0)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, longest.Id, tasks[0].TaskID)
+	})
+	t.Run("Limits", func(t *testing.T) {
+		tasks, err := FindStarvedTasks(pRef, 0, 1)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, longest.Id, tasks[0].TaskID)
+	})
+}
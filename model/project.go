@@ -33,6 +33,10 @@ const (
 	// differentiate between setup related commands and actual testing commands.
 	DefaultCommandType = evergreen.CommandTypeTest
 
+	// ArtifactNamespaceExpansion is the name of the expansion that resolves to
+	// a build variant's artifact namespace.
+	ArtifactNamespaceExpansion = "artifact_namespace"
+
 	waterfallTasksQueryMaxTime = 90 * time.Second
 )
 
@@ -71,12 +75,19 @@ type Project struct {
 	Tasks               []ProjectTask              `yaml:"tasks,omitempty" bson:"tasks"`
 	ExecTimeoutSecs     int                        `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs"`
 	Loggers             *LoggerConfig              `yaml:"loggers,omitempty" bson:"loggers,omitempty"`
+	ExternalGates       []ExternalGate             `yaml:"external_gates,omitempty" bson:"external_gates,omitempty"`
+	VersionGates        []string                   `yaml:"version_gates,omitempty" bson:"version_gates,omitempty"`
+	Stages              []PipelineStage            `yaml:"stages,omitempty" bson:"stages,omitempty"`
 	CommitQueueAliases  []ProjectAlias             `yaml:"commit_queue_aliases,omitempty" bson:"commit_queue_aliases,omitempty"`
 	GitHubPRAliases     []ProjectAlias             `yaml:"github_pr_aliases,omitempty" bson:"github_pr_aliases,omitempty"`
 	GitTagAliases       []ProjectAlias             `yaml:"git_tag_aliases,omitempty" bson:"git_tag_aliases,omitempty"`
 	GitHubChecksAliases []ProjectAlias             `yaml:"github_checks_aliases,omitempty" bson:"github_checks_aliases,omitempty"`
 	PatchAliases        []ProjectAlias             `yaml:"patch_aliases,omitempty" bson:"patch_aliases,omitempty"`
 
+	// ValidationSuppressions hide the project config validation warnings that
+	// the project can't act on.
+	ValidationSuppressions []ValidationSuppression `yaml:"validation_suppressions,omitempty" bson:"validation_suppressions,omitempty"`
+
 	// Flag that indicates a project as requiring user authentication
 	Private bool `yaml:"private,omitempty" bson:"private"`
 }
@@ -120,6 +131,17 @@ type BuildVariantTaskUnit struct {
 
 	// the distros that the task can be run on
 	RunOn []string `yaml:"run_on,omitempty" bson:"run_on"`
+	// RunOnStrategy is how the distro that the task runs on is selected when
+	// run_on lists more than one distro. It takes precedence over the
+	// variant's strategy.
+	RunOnStrategy string `yaml:"run_on_strategy,omitempty" bson:"run_on_strategy,omitempty"`
+	// ContainerFallback is the distro that the task runs on if it runs in a
+	// container but cannot be allocated one in time.
+	ContainerFallback *ContainerFallback `yaml:"container_fallback,omitempty" bson:"container_fallback,omitempty"`
+	// AllowedRequesters, if set, are the only requesters that the task runs
+	// for. It takes precedence over Patchable, PatchOnly, AllowForGitTag and
+	// GitTagOnly.
+	AllowedRequesters []evergreen.UserRequester `yaml:"allowed_requesters,omitempty" bson:"allowed_requesters,omitempty"`
 	// currently unsupported (TODO EVG-578)
 	ExecTimeoutSecs int   `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs"`
 	Stepback        *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
@@ -135,10 +157,37 @@ type BuildVariantTaskUnit struct {
 	// If CronBatchTime is not empty, then override the project settings with cron syntax,
 	// with BatchTime and CronBatchTime being mutually exclusive.
 	CronBatchTime string `yaml:"cron,omitempty" bson:"cron,omitempty"`
+	// CronTimezone is the IANA time zone in which CronBatchTime is evaluated.
+	// If it's not set, the cron is evaluated in the server's local time.
+	CronTimezone string `yaml:"timezone,omitempty" bson:"timezone,omitempty"`
 	// If Activate is set to false, then we don't initially activate the task.
 	Activate *bool `yaml:"activate,omitempty" bson:"activate,omitempty"`
 }
 
+// DefaultContainerFallbackMins is how long a container task waits to be
+// allocated a container before it falls back to its distro if the wait is not
+// specified.
+const DefaultContainerFallbackMins = 30
+
+// ContainerFallback allows a task that runs in a container to run on a distro
+// instead if there is not enough container capacity to run it.
+type ContainerFallback struct {
+	// Distro is the distro that the task runs on if it falls back.
+	Distro string `yaml:"distro,omitempty" bson:"distro,omitempty"`
+	// AfterMins is how many minutes the task waits to be allocated a
+	// container before falling back.
+	AfterMins int `yaml:"after_mins,omitempty" bson:"after_mins,omitempty"`
+}
+
+// GetAfter returns how long the task waits to be allocated a container
+// before falling back.
+func (f *ContainerFallback) GetAfter() time.Duration {
+	if f.AfterMins == 0 {
+		return DefaultContainerFallbackMins * time.Minute
+	}
+	return time.Duration(f.AfterMins) * time.Minute
+}
+
 func (b BuildVariant) Get(name string) (BuildVariantTaskUnit, error) {
 	for idx := range b.Tasks {
 		if b.Tasks[idx].Name == name {
@@ -149,6 +198,33 @@ func (b BuildVariant) Get(name string) (BuildVariantTaskUnit, error) {
 	return BuildVariantTaskUnit{}, errors.Errorf("could not find task '%s' in build variant '%s'", name, b.Name)
 }
 
+// GetArtifactNamespace returns the prefix that the variant's tasks should
+// upload artifacts under.
+func (b BuildVariant) GetArtifactNamespace() string {
+	expansions := b.GetExpansions()
+	return expansions.Get(ArtifactNamespaceExpansion)
+}
+
+// GetExpansions returns the variant's expansions, including the
+// artifact_namespace expansion.
+func (b BuildVariant) GetExpansions() util.Expansions {
+	return variantExpansions(b.Name, b.ArtifactNamespace, b.Expansions)
+}
+
+// variantExpansions returns a copy of a variant's expansions with the
+// artifact_namespace expansion set. An explicit namespace takes precedence
+// over an expansion of the same name, which in turn takes precedence over the
+// variant's name.
+func variantExpansions(name, namespace string, expansions map[string]string) util.Expansions {
+	exp := util.NewExpansions(expansions)
+	if namespace != "" {
+		exp.Put(ArtifactNamespaceExpansion, namespace)
+	} else if !exp.Exists(ArtifactNamespaceExpansion) {
+		exp.Put(ArtifactNamespaceExpansion, name)
+	}
+	return *exp
+}
+
 func (b BuildVariant) GetDisplayTask(name string) *patch.DisplayTask {
 	for _, dt := range b.DisplayTasks {
 		if dt.Name == name {
@@ -201,6 +277,9 @@ func (bvt *BuildVariantTaskUnit) Populate(pt ProjectTask) {
 	if bvt.GitTagOnly == nil {
 		bvt.GitTagOnly = pt.GitTagOnly
 	}
+	if len(bvt.AllowedRequesters) == 0 {
+		bvt.AllowedRequesters = pt.AllowedRequesters
+	}
 	// TODO these are copied but unused until EVG-578 is completed
 	if bvt.ExecTimeoutSecs == 0 {
 		bvt.ExecTimeoutSecs = pt.ExecTimeoutSecs
@@ -234,6 +313,9 @@ func (bvt *BuildVariantTaskUnit) UnmarshalYAML(unmarshal func(interface{}) error
 }
 
 func (bvt *BuildVariantTaskUnit) SkipOnRequester(requester string) bool {
+	if len(bvt.AllowedRequesters) != 0 {
+		return !bvt.allowsAnyRequester(func(allowed string) bool { return allowed == requester })
+	}
 	return evergreen.IsPatchRequester(requester) && bvt.SkipOnPatchBuild() ||
 		!evergreen.IsPatchRequester(requester) && bvt.SkipOnNonPatchBuild() ||
 		evergreen.IsGitTagRequester(requester) && bvt.SkipOnGitTagBuild() ||
@@ -241,21 +323,44 @@ func (bvt *BuildVariantTaskUnit) SkipOnRequester(requester string) bool {
 }
 
 func (bvt *BuildVariantTaskUnit) SkipOnPatchBuild() bool {
+	if len(bvt.AllowedRequesters) != 0 {
+		return !bvt.allowsAnyRequester(evergreen.IsPatchRequester)
+	}
 	return !utility.FromBoolTPtr(bvt.Patchable)
 }
 
 func (bvt *BuildVariantTaskUnit) SkipOnNonPatchBuild() bool {
+	if len(bvt.AllowedRequesters) != 0 {
+		return !bvt.allowsAnyRequester(func(requester string) bool { return !evergreen.IsPatchRequester(requester) })
+	}
 	return utility.FromBoolPtr(bvt.PatchOnly)
 }
 
 func (bvt *BuildVariantTaskUnit) SkipOnGitTagBuild() bool {
+	if len(bvt.AllowedRequesters) != 0 {
+		return !bvt.allowsAnyRequester(evergreen.IsGitTagRequester)
+	}
 	return !utility.FromBoolTPtr(bvt.AllowForGitTag)
 }
 
 func (bvt *BuildVariantTaskUnit) SkipOnNonGitTagBuild() bool {
+	if len(bvt.AllowedRequesters) != 0 {
+		return !bvt.allowsAnyRequester(func(requester string) bool { return !evergreen.IsGitTagRequester(requester) })
+	}
 	return utility.FromBoolPtr(bvt.GitTagOnly)
 }
 
+// allowsAnyRequester returns whether any of the task's allowed requesters
+// match the given kind of internal requester.
+func (bvt *BuildVariantTaskUnit) allowsAnyRequester(matches func(requester string) bool) bool {
+	for _, requester := range bvt.AllowedRequesters {
+		if matches(evergreen.UserRequesterToInternalRequester(requester)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (bvt *BuildVariantTaskUnit) IsDisabled() bool {
 	return utility.FromBoolPtr(bvt.Disable)
 }
@@ -290,19 +395,38 @@ type BuildVariant struct {
 	// If CronBatchTime is not empty, then override the project settings with cron syntax,
 	// with BatchTime and CronBatchTime being mutually exclusive.
 	CronBatchTime string `yaml:"cron,omitempty" bson:"cron,omitempty"`
+	// CronTimezone is the IANA time zone in which CronBatchTime is evaluated.
+	// If it's not set, the cron is evaluated in the server's local time.
+	CronTimezone string `yaml:"timezone,omitempty" bson:"timezone,omitempty"`
 
 	// If Activate is set to false, then we don't initially activate the build variant.
 	Activate *bool `yaml:"activate,omitempty" bson:"activate,omitempty"`
 
+	// Canary limits the mainline versions that the build variant activates
+	// on to a sample of them.
+	Canary *CanaryActivation `yaml:"canary,omitempty" bson:"canary,omitempty"`
+
 	// Use a *bool so that there are 3 possible states:
 	//   1. nil   = not overriding the project setting (default)
 	//   2. true  = overriding the project setting with true
 	//   3. false = overriding the project setting with false
 	Stepback *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
 
+	// ExternalGates are the names of the external gates that hold the
+	// variant's tasks.
+	ExternalGates []string `yaml:"external_gates,omitempty" bson:"external_gates,omitempty"`
+
 	// the default distros.  will be used to run a task if no distro field is
 	// provided for the task
 	RunOn []string `yaml:"run_on,omitempty" bson:"run_on"`
+	// RunOnStrategy is how the distro that the variant's tasks run on is
+	// selected when run_on lists more than one distro.
+	RunOnStrategy string `yaml:"run_on_strategy,omitempty" bson:"run_on_strategy,omitempty"`
+
+	// ArtifactNamespace is the prefix that the variant's tasks can upload
+	// artifacts under by referencing the artifact_namespace expansion. If it's
+	// not set, it defaults to the variant's name.
+	ArtifactNamespace string `yaml:"artifact_namespace,omitempty" bson:"artifact_namespace,omitempty"`
 
 	// all of the tasks/groups to be run on the build variant, compile through tests.
 	Tasks        []BuildVariantTaskUnit `yaml:"tasks,omitempty" bson:"tasks"`
@@ -542,6 +666,13 @@ type TaskUnitDependency struct {
 	Variant       string `yaml:"variant,omitempty" bson:"variant,omitempty"`
 	Status        string `yaml:"status,omitempty" bson:"status,omitempty"`
 	PatchOptional bool   `yaml:"patch_optional,omitempty" bson:"patch_optional,omitempty"`
+
+	// OmitGeneratedTasks, if set, only waits for the depended on task itself
+	// rather than also waiting for the tasks that it generates.
+	OmitGeneratedTasks bool `yaml:"omit_generated_tasks,omitempty" bson:"omit_generated_tasks,omitempty"`
+	// LatestMainline, if set, depends on the most recent mainline run of the
+	// task in another version rather than the run in the same version.
+	LatestMainline bool `yaml:"latest_mainline,omitempty" bson:"latest_mainline,omitempty"`
 }
 
 // UnmarshalYAML allows tasks to be referenced as single selector strings.
@@ -605,6 +736,23 @@ type ProjectTask struct {
 	GitTagOnly      *bool `yaml:"git_tag_only,omitempty" bson:"git_tag_only,omitempty"`
 	Stepback        *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
 	MustHaveResults *bool `yaml:"must_have_test_results,omitempty" bson:"must_have_test_results,omitempty"`
+
+	// AllowedRequesters, if set, are the only requesters that the task runs
+	// for. It takes precedence over Patchable, PatchOnly, AllowForGitTag and
+	// GitTagOnly.
+	AllowedRequesters []evergreen.UserRequester `yaml:"allowed_requesters,omitempty" bson:"allowed_requesters,omitempty"`
+
+	// Outputs declares the structured outputs that the task can publish for
+	// its dependent tasks.
+	Outputs []TaskOutputDefinition `yaml:"outputs,omitempty" bson:"outputs,omitempty"`
+
+	// Compliance describes what the task produces for release audits.
+	Compliance *task.ComplianceMetadata `yaml:"compliance,omitempty" bson:"compliance,omitempty"`
+
+	// ExpectedArtifacts are the names of the artifacts that the task must
+	// attach to succeed. Each one can be a glob pattern that matches the
+	// artifact names.
+	ExpectedArtifacts []string `yaml:"expected_artifacts,omitempty" bson:"expected_artifacts,omitempty"`
 }
 
 type LoggerConfig struct {
@@ -972,6 +1120,7 @@ func PopulateExpansions(t *task.Task, h *host.Host, oauthToken string) (util.Exp
 	expansions.Put("task_name", t.DisplayName)
 	expansions.Put("build_id", t.BuildId)
 	expansions.Put("build_variant", t.BuildVariant)
+	expansions.Update(taskTraceExpansions(t))
 	expansions.Put("revision", t.Revision)
 	expansions.Put("github_commit", t.Revision)
 	expansions.Put(evergreen.GlobalGitHubTokenExpansion, oauthToken)
@@ -1092,6 +1241,12 @@ func PopulateExpansions(t *task.Task, h *host.Host, oauthToken string) (util.Exp
 		expansions.Put("revision_order_id", strconv.Itoa(v.RevisionOrderNumber))
 	}
 
+	outputExpansions, err := dependencyOutputExpansions(t)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting dependencies' outputs")
+	}
+	expansions.Update(outputExpansions)
+
 	for _, e := range h.Distro.Expansions {
 		expansions.Put(e.Key, e.Value)
 	}
@@ -1101,6 +1256,10 @@ func PopulateExpansions(t *task.Task, h *host.Host, oauthToken string) (util.Exp
 		return nil, errors.Wrap(err, "getting expansions for variant")
 	}
 	expansions.Update(bvExpansions)
+
+	// Expansions overridden when the version was created take precedence
+	// over every other source of expansions.
+	expansions.Update(v.ExpansionOverrides)
 	return expansions, nil
 }
 
@@ -1243,6 +1402,13 @@ func (p *Project) FindDistroNameForTask(t *task.Task) (string, error) {
 }
 
 func FindLatestVersionWithValidProject(projectId string) (*Version, *Project, error) {
+	return FindLatestVersionWithValidProjectProjection(projectId, ProjectProjectionFull)
+}
+
+// FindLatestVersionWithValidProjectProjection is the same as
+// FindLatestVersionWithValidProject but only loads the parts of the project
+// needed for the projection.
+func FindLatestVersionWithValidProjectProjection(projectId string, projection ProjectProjection) (*Version, *Project, error) {
 	const retryCount = 5
 	if projectId == "" {
 		return nil, nil, errors.WithStack(errors.New("cannot pass empty projectId to FindLatestVersionWithValidProject"))
@@ -1261,7 +1427,7 @@ func FindLatestVersionWithValidProject(projectId string) (*Version, *Project, er
 			continue
 		}
 		if lastGoodVersion != nil {
-			projectInfo, err := LoadProjectForVersion(lastGoodVersion, projectId, true)
+			p, err := LoadProjectProjectionForVersion(lastGoodVersion, projectId, projection)
 			if err != nil {
 				grip.Critical(message.WrapError(err, message.Fields{
 					"message": "last known good version has malformed config",
@@ -1271,7 +1437,7 @@ func FindLatestVersionWithValidProject(projectId string) (*Version, *Project, er
 				revisionOrderNum = lastGoodVersion.RevisionOrderNumber // look for an older version if the returned version is malformed
 				continue
 			}
-			project = projectInfo.Project
+			project = p
 		}
 		return lastGoodVersion, project, nil
 	}
@@ -1495,11 +1661,14 @@ func (p *Project) tasksFromGroup(bvTaskGroup BuildVariantTaskUnit) []BuildVarian
 			Priority:         bvTaskGroup.Priority,
 			DependsOn:        bvTaskGroup.DependsOn,
 			RunOn:            bvTaskGroup.RunOn,
+			RunOnStrategy:    bvTaskGroup.RunOnStrategy,
 			ExecTimeoutSecs:  bvTaskGroup.ExecTimeoutSecs,
 			Stepback:         bvTaskGroup.Stepback,
 			Activate:         bvTaskGroup.Activate,
 			CommitQueueMerge: bvTaskGroup.CommitQueueMerge,
 		}
+		bvt.ContainerFallback = bvTaskGroup.ContainerFallback
+		bvt.AllowedRequesters = bvTaskGroup.AllowedRequesters
 		// Default to project task settings when unspecified
 		bvt.Populate(taskMap[t])
 		tasks = append(tasks, bvt)
@@ -1675,6 +1844,24 @@ func (p *Project) ResolvePatchVTs(patchDoc *patch.Patch, requester, alias string
 	return bvs, tasks, vts
 }
 
+// ResolveAliasVariantTasks returns the variants and tasks in the project that
+// are selected by the given alias definitions, including those selected by
+// tags.
+func (p *Project) ResolveAliasVariantTasks(aliases []ProjectAlias, requester string, includeDeps bool) ([]patch.VariantTasks, error) {
+	execPairs, displayPairs, err := p.BuildProjectTVPairsWithAlias(aliases)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting task/variant pairs for alias")
+	}
+	pairs := p.extractDisplayTasks(TaskVariantPairs{ExecTasks: execPairs, DisplayTasks: displayPairs})
+	if includeDeps {
+		pairs.ExecTasks, err = IncludeDependencies(p, pairs.ExecTasks, requester)
+		if err != nil {
+			return nil, errors.Wrap(err, "including dependencies")
+		}
+	}
+	return pairs.TVPairsToVariantTasks(), nil
+}
+
 // GetVariantTasks returns all the build variants and all tasks specified for
 // each build variant.
 func (p *Project) GetAllVariantTasks() []patch.VariantTasks {
@@ -1822,6 +2009,22 @@ func (p *Project) BuildProjectTVPairsWithAlias(vars []ProjectAlias) ([]TVPair, [
 	pairs := []TVPair{}
 	displayTaskPairs := []TVPair{}
 	for _, v := range vars {
+		if v.Selector != "" {
+			expr, err := ParseSelectionExpression(v.Selector)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "parsing selector '%s'", v.Selector)
+			}
+			selectedPairs, selectedDisplayTaskPairs := p.SelectTVPairs(expr)
+			for _, pair := range selectedPairs {
+				if task := p.FindProjectTask(pair.TaskName); task != nil && task.Patchable != nil && !(*task.Patchable) {
+					continue
+				}
+				pairs = append(pairs, pair)
+			}
+			displayTaskPairs = append(displayTaskPairs, selectedDisplayTaskPairs...)
+			continue
+		}
+
 		var variantRegex *regexp.Regexp
 		variantRegex, err := regexp.Compile(v.Variant)
 		if err != nil {
@@ -1865,6 +2068,33 @@ func (p *Project) BuildProjectTVPairsWithAlias(vars []ProjectAlias) ([]TVPair, [
 	return pairs, displayTaskPairs, nil
 }
 
+// SelectTVPairs returns the variants' tasks and display tasks that the
+// selection expression selects. Display tasks have no tags.
+func (p *Project) SelectTVPairs(expr *SelectionExpression) ([]TVPair, []TVPair) {
+	pairs := []TVPair{}
+	displayTaskPairs := []TVPair{}
+	for _, variant := range p.BuildVariants {
+		candidate := NewVariantSelectionCandidate(variant.Name, variant.Tags)
+		if !expr.CouldMatch(candidate) {
+			continue
+		}
+		for _, task := range p.Tasks {
+			if !expr.Matches(candidate.WithTask(task.Name, task.Tags)) {
+				continue
+			}
+			if p.FindTaskForVariant(task.Name, variant.Name) != nil {
+				pairs = append(pairs, TVPair{variant.Name, task.Name})
+			}
+		}
+		for _, displayTask := range variant.DisplayTasks {
+			if expr.Matches(candidate.WithTask(displayTask.Name, nil)) {
+				displayTaskPairs = append(displayTaskPairs, TVPair{variant.Name, displayTask.Name})
+			}
+		}
+	}
+	return pairs, displayTaskPairs
+}
+
 func (p *Project) VariantTasksForSelectors(definitions []patch.PatchTriggerDefinition, requester string) ([]patch.VariantTasks, error) {
 	projectAliases := []ProjectAlias{}
 	for _, definition := range definitions {
@@ -1965,6 +2195,11 @@ func dependenciesForTaskUnit(taskUnits []BuildVariantTaskUnit) []task.Dependency
 	var dependencies []task.DependencyEdge
 	for _, dependentTask := range taskUnits {
 		for _, dep := range dependentTask.DependsOn {
+			// Dependencies on other versions are not part of this version's
+			// dependency graph.
+			if dep.LatestMainline {
+				continue
+			}
 			// Use the current variant if none is specified.
 			if dep.Variant == "" {
 				dep.Variant = dependentTask.Variant
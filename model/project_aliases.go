@@ -25,6 +25,7 @@ var (
 	taskKey        = bsonutil.MustHaveTag(ProjectAlias{}, "Task")
 	variantTagsKey = bsonutil.MustHaveTag(ProjectAlias{}, "VariantTags")
 	taskTagsKey    = bsonutil.MustHaveTag(ProjectAlias{}, "TaskTags")
+	selectorKey    = bsonutil.MustHaveTag(ProjectAlias{}, "Selector")
 )
 
 const (
@@ -56,6 +57,10 @@ const (
 // Git tags use a special alias "__git_tag" and create a new version for the matching
 // variants/tasks, assuming the tag matches the defined git_tag regex.
 // In this way, users can define different behavior for different kind of tags.
+//
+// Instead of the variant and task regexes or tags, an alias can define a
+// selector, which is a selection expression (see ParseSelectionExpression)
+// over the variants' and tasks' names and tags.
 type ProjectAlias struct {
 	ID          mgobson.ObjectId `bson:"_id,omitempty" json:"_id" yaml:"id"`
 	ProjectID   string           `bson:"project_id" json:"project_id" yaml:"project_id"`
@@ -66,6 +71,12 @@ type ProjectAlias struct {
 	VariantTags []string         `bson:"variant_tags,omitempty" json:"variant_tags" yaml:"variant_tags"`
 	Task        string           `bson:"task,omitempty" json:"task" yaml:"task"`
 	TaskTags    []string         `bson:"tags,omitempty" json:"tags" yaml:"task_tags"`
+	Selector    string           `bson:"selector,omitempty" json:"selector,omitempty" yaml:"selector,omitempty"`
+
+	// matchedVariant is the variant that a selector alias was matched
+	// against by AliasesMatchingVariant, so that its selector can be
+	// evaluated against the variant's tasks.
+	matchedVariant SelectionCandidate
 }
 
 type ProjectAliases []ProjectAlias
@@ -377,6 +388,7 @@ func (p *ProjectAlias) Upsert() error {
 		variantTagsKey: p.VariantTags,
 		taskTagsKey:    p.TaskTags,
 		taskKey:        p.Task,
+		selectorKey:    p.Selector,
 	}
 
 	_, err := db.Upsert(ProjectAliasCollection, bson.M{
@@ -442,6 +454,18 @@ func aliasesMatchingGitTag(a ProjectAliases, tag string) (ProjectAliases, error)
 func (a ProjectAliases) AliasesMatchingVariant(variant string, variantTags []string) (ProjectAliases, error) {
 	res := []ProjectAlias{}
 	for _, alias := range a {
+		if alias.Selector != "" {
+			expr, err := ParseSelectionExpression(alias.Selector)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing selector '%s'", alias.Selector)
+			}
+			candidate := NewVariantSelectionCandidate(variant, variantTags)
+			if expr.CouldMatch(candidate) {
+				alias.matchedVariant = candidate
+				res = append(res, alias)
+			}
+			continue
+		}
 		variantRegex, err := regexp.Compile(alias.Variant)
 		if err != nil {
 			return nil, errors.Wrapf(err, "compiling variant regex '%s'", alias.Variant)
@@ -456,6 +480,16 @@ func (a ProjectAliases) AliasesMatchingVariant(variant string, variantTags []str
 // HasMatchingTask assumes that the aliases given already match the preferred variant.
 func (a ProjectAliases) HasMatchingTask(taskName string, taskTags []string) (bool, error) {
 	for _, alias := range a {
+		if alias.Selector != "" {
+			expr, err := ParseSelectionExpression(alias.Selector)
+			if err != nil {
+				return false, errors.Wrapf(err, "parsing selector '%s'", alias.Selector)
+			}
+			if expr.Matches(alias.matchedVariant.WithTask(taskName, taskTags)) {
+				return true, nil
+			}
+			continue
+		}
 		taskRegex, err := regexp.Compile(alias.Task)
 		if err != nil {
 			return false, errors.Wrapf(err, "compiling task regex '%s'", alias.Task)
@@ -506,6 +540,15 @@ func ValidateProjectAliases(aliases []ProjectAlias, aliasType string) []string {
 
 func validateAliasPatchDefinition(pd ProjectAlias, aliasType string, lineNum int) []string {
 	errs := []string{}
+	if strings.TrimSpace(pd.Selector) != "" {
+		if strings.TrimSpace(pd.Variant) != "" || strings.TrimSpace(pd.Task) != "" || len(pd.VariantTags) != 0 || len(pd.TaskTags) != 0 {
+			errs = append(errs, fmt.Sprintf("%s: cannot define both a selector and variant/task regexes or tags on line #%d", aliasType, lineNum))
+		}
+		if _, err := ParseSelectionExpression(pd.Selector); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: selector #%d is invalid: %s", aliasType, lineNum, err.Error()))
+		}
+		return errs
+	}
 	if (strings.TrimSpace(pd.Variant) == "") == (len(pd.VariantTags) == 0) {
 		errs = append(errs, fmt.Sprintf("%s: must specify exactly one of variant regex or variant tags on line #%d", aliasType, lineNum))
 	}
@@ -542,5 +585,5 @@ func validateGitTagAlias(pd ProjectAlias, aliasType string, lineNum int) []strin
 
 func populatedPatchDefinition(pd ProjectAlias) bool {
 	return strings.TrimSpace(pd.Variant) != "" || strings.TrimSpace(pd.Task) != "" ||
-		len(pd.VariantTags) != 0 || len(pd.Task) != 0
+		len(pd.VariantTags) != 0 || len(pd.Task) != 0 || strings.TrimSpace(pd.Selector) != ""
 }
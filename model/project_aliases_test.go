@@ -8,6 +8,7 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -487,6 +488,59 @@ func TestMatching(t *testing.T) {
 	assert.False(match)
 }
 
+func TestMatchingSelector(t *testing.T) {
+	aliases := ProjectAliases{
+		{Alias: "one", Selector: "variant_tag:linux and (task:/^compile/ or task_tag:smoke) and not task_tag:slow"},
+		{Alias: "two", Selector: "variant:windows and task:lint"},
+	}
+
+	linuxMatches, err := aliases.AliasesMatchingVariant("ubuntu", []string{"linux"})
+	require.NoError(t, err)
+	require.Len(t, linuxMatches, 1)
+	assert.Equal(t, "one", linuxMatches[0].Alias)
+
+	windowsMatches, err := aliases.AliasesMatchingVariant("windows", nil)
+	require.NoError(t, err)
+	require.Len(t, windowsMatches, 1)
+	assert.Equal(t, "two", windowsMatches[0].Alias)
+
+	matches, err := aliases.AliasesMatchingVariant("macos", []string{"osx"})
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	match, err := linuxMatches.HasMatchingTask("compile_all", nil)
+	require.NoError(t, err)
+	assert.True(t, match)
+	match, err = linuxMatches.HasMatchingTask("unit_tests", []string{"smoke"})
+	require.NoError(t, err)
+	assert.True(t, match)
+	match, err = linuxMatches.HasMatchingTask("compile_slow", []string{"slow"})
+	require.NoError(t, err)
+	assert.False(t, match)
+	match, err = linuxMatches.HasMatchingTask("lint", nil)
+	require.NoError(t, err)
+	assert.False(t, match)
+
+	// The selector is evaluated against the variant it was matched against,
+	// so the same task can match for one variant and not another.
+	match, err = windowsMatches.HasMatchingTask("lint", nil)
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	_, err = ProjectAliases{{Alias: "bad", Selector: "variant:("}}.AliasesMatchingVariant("ubuntu", nil)
+	assert.Error(t, err)
+}
+
+func TestValidateAliasSelector(t *testing.T) {
+	errs := ValidateProjectAliases([]ProjectAlias{{Alias: "a", Selector: "variant_tag:linux and task:compile"}}, "Patch Aliases")
+	assert.Empty(t, errs)
+
+	errs = ValidateProjectAliases([]ProjectAlias{{Alias: "a", Selector: "variant_tag:linux and", Task: "compile"}}, "Patch Aliases")
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0], "cannot define both a selector")
+	assert.Contains(t, errs[1], "selector #1 is invalid")
+}
+
 func TestValidateGitTagAlias(t *testing.T) {
 	a := ProjectAlias{Alias: "one"}
 	errs := validateGitTagAlias(a, "gitTag", 1)
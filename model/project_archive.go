@@ -0,0 +1,259 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const ProjectArchiveCollection = "project_archives"
+
+// ProjectArchive is the snapshot of a project's settings taken when the
+// project was archived, which is used to restore the project when it is
+// resurrected.
+type ProjectArchive struct {
+	ProjectID  string    `bson:"_id" json:"project_id"`
+	ArchivedAt time.Time `bson:"archived_at" json:"archived_at"`
+	ArchivedBy string    `bson:"archived_by" json:"archived_by"`
+	Reason     string    `bson:"reason,omitempty" json:"reason,omitempty"`
+	// Snapshot is the project ref as it was before it was archived.
+	Snapshot ProjectRef `bson:"snapshot" json:"snapshot"`
+}
+
+var (
+	projectArchiveProjectIDKey = bsonutil.MustHaveTag(ProjectArchive{}, "ProjectID")
+)
+
+// FindProjectArchive returns the archive of the project, or nil if the project
+// is not archived.
+func FindProjectArchive(projectID string) (*ProjectArchive, error) {
+	archive := &ProjectArchive{}
+	err := db.FindOneQ(ProjectArchiveCollection, db.Query(bson.M{projectArchiveProjectIDKey: projectID}), archive)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding archive for project '%s'", projectID)
+	}
+	return archive, nil
+}
+
+// ArchiveProject snapshots the project's settings and then disables the
+// project along with its repotracker, dispatching, patching, GitHub
+// integrations, commit queue, triggers, and periodic builds in a single
+// update.
+func ArchiveProject(pRef *ProjectRef, caller, reason string) (*ProjectArchive, error) {
+	if pRef.IsArchived() {
+		return nil, errors.Errorf("project '%s' is already archived", pRef.Identifier)
+	}
+	if pRef.IsHidden() {
+		return nil, errors.Errorf("project '%s' is hidden and cannot be archived", pRef.Identifier)
+	}
+	before, err := GetProjectSettings(pRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting settings for project '%s'", pRef.Identifier)
+	}
+
+	archive := &ProjectArchive{
+		ProjectID:  pRef.Id,
+		ArchivedAt: time.Now(),
+		ArchivedBy: caller,
+		Reason:     reason,
+		Snapshot:   *pRef,
+	}
+	if err = db.Insert(ProjectArchiveCollection, archive); err != nil {
+		if db.IsDuplicateKey(err) {
+			return nil, errors.Errorf("project '%s' is already archived", pRef.Identifier)
+		}
+		return nil, errors.Wrapf(err, "saving archive for project '%s'", pRef.Identifier)
+	}
+
+	err = db.Update(ProjectRefCollection,
+		bson.M{
+			ProjectRefIdKey:       pRef.Id,
+			ProjectRefArchivedKey: bson.M{"$ne": true},
+		},
+		bson.M{
+			"$set": bson.M{
+				ProjectRefArchivedKey:               true,
+				ProjectRefEnabledKey:                false,
+				projectRefRepotrackerDisabledKey:    true,
+				projectRefDispatchingDisabledKey:    true,
+				projectRefPatchingDisabledKey:       true,
+				projectRefPRTestingEnabledKey:       false,
+				projectRefManualPRTestingEnabledKey: false,
+				projectRefGithubChecksEnabledKey:    false,
+				projectRefGitTagVersionsEnabledKey:  false,
+				bsonutil.GetDottedKeyName(projectRefCommitQueueKey, commitQueueEnabledKey): false,
+				projectRefTriggersKey:       []TriggerDefinition{},
+				projectRefPeriodicBuildsKey: []PeriodicBuildDefinition{},
+			},
+		})
+	if err != nil {
+		// Don't leave behind an archive for a project that isn't archived.
+		grip.Error(message.WrapError(db.Remove(ProjectArchiveCollection, bson.M{projectArchiveProjectIDKey: pRef.Id}), message.Fields{
+			"message": "could not remove archive for project that failed to archive",
+			"project": pRef.Id,
+		}))
+		if adb.ResultsNotFound(err) {
+			return nil, errors.Errorf("project '%s' is already archived", pRef.Identifier)
+		}
+		return nil, errors.Wrapf(err, "archiving project '%s'", pRef.Identifier)
+	}
+
+	grip.Error(message.WrapError(logProjectArchivalEvent(EventTypeProjectArchived, pRef.Id, caller, before), message.Fields{
+		"message": "could not log project archived event",
+		"project": pRef.Id,
+	}))
+
+	return archive, nil
+}
+
+// ValidateResurrection returns the reasons that the archived project's
+// snapshot can no longer be restored as it was, given the current projects
+// and the valid GitHub organizations.
+func (a *ProjectArchive) ValidateResurrection(validOrgs []string) []string {
+	problems := []string{}
+	snapshot := a.Snapshot
+	if err := snapshot.ValidateOwnerAndRepo(validOrgs); err != nil {
+		problems = append(problems, errors.Wrap(err, "invalid owner and repo").Error())
+	}
+	for _, trigger := range snapshot.Triggers {
+		if err := trigger.Validate(snapshot.Id); err != nil {
+			problems = append(problems, errors.Wrapf(err, "invalid trigger on project '%s'", trigger.Project).Error())
+		}
+	}
+	if !snapshot.IsEnabled() {
+		return problems
+	}
+	conflicts, err := snapshot.GetGithubProjectConflicts()
+	if err != nil {
+		return append(problems, errors.Wrap(err, "finding GitHub conflicts").Error())
+	}
+	if snapshot.IsPRTestingEnabled() && len(conflicts.PRTestingIdentifiers) > 0 {
+		problems = append(problems, fmt.Sprintf("PR testing is now enabled for the same branch by project '%s'", conflicts.PRTestingIdentifiers[0]))
+	}
+	if snapshot.CommitQueue.IsEnabled() && len(conflicts.CommitQueueIdentifiers) > 0 {
+		problems = append(problems, fmt.Sprintf("the commit queue is now enabled for the same branch by project '%s'", conflicts.CommitQueueIdentifiers[0]))
+	}
+	if snapshot.IsGithubChecksEnabled() && len(conflicts.CommitCheckIdentifiers) > 0 {
+		problems = append(problems, fmt.Sprintf("GitHub checks are now enabled for the same branch by project '%s'", conflicts.CommitCheckIdentifiers[0]))
+	}
+	return problems
+}
+
+// ResurrectProject restores the settings that were disabled when the project
+// was archived from the archive's snapshot and removes the archive. Callers
+// should check ValidateResurrection first.
+func ResurrectProject(projectID, caller string) (*ProjectRef, error) {
+	archive, err := FindProjectArchive(projectID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if archive == nil {
+		return nil, errors.Errorf("project '%s' is not archived", projectID)
+	}
+	pRef, err := FindBranchProjectRef(projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project '%s'", projectID)
+	}
+	if pRef == nil {
+		return nil, errors.Errorf("project '%s' not found", projectID)
+	}
+	before, err := GetProjectSettings(pRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting settings for project '%s'", projectID)
+	}
+
+	snapshot := archive.Snapshot
+	for i := range snapshot.PeriodicBuilds {
+		// The next run time is stale, so let the periodic build job
+		// reschedule it.
+		snapshot.PeriodicBuilds[i].NextRunTime = time.Time{}
+	}
+	set := bson.M{}
+	unset := bson.M{
+		ProjectRefArchivedKey: 1,
+	}
+	// Unset the triggers and periodic builds that weren't set on the project
+	// so that it inherits them from its repo again.
+	if snapshot.Triggers == nil {
+		unset[projectRefTriggersKey] = 1
+	} else {
+		set[projectRefTriggersKey] = snapshot.Triggers
+	}
+	if snapshot.PeriodicBuilds == nil {
+		unset[projectRefPeriodicBuildsKey] = 1
+	} else {
+		set[projectRefPeriodicBuildsKey] = snapshot.PeriodicBuilds
+	}
+	for key, val := range map[string]*bool{
+		ProjectRefEnabledKey:                                                       snapshot.Enabled,
+		projectRefRepotrackerDisabledKey:                                           snapshot.RepotrackerDisabled,
+		projectRefDispatchingDisabledKey:                                           snapshot.DispatchingDisabled,
+		projectRefPatchingDisabledKey:                                              snapshot.PatchingDisabled,
+		projectRefPRTestingEnabledKey:                                              snapshot.PRTestingEnabled,
+		projectRefManualPRTestingEnabledKey:                                        snapshot.ManualPRTestingEnabled,
+		projectRefGithubChecksEnabledKey:                                           snapshot.GithubChecksEnabled,
+		projectRefGitTagVersionsEnabledKey:                                         snapshot.GitTagVersionsEnabled,
+		bsonutil.GetDottedKeyName(projectRefCommitQueueKey, commitQueueEnabledKey): snapshot.CommitQueue.Enabled,
+	} {
+		if val == nil {
+			unset[key] = 1
+		} else {
+			set[key] = utility.FromBoolPtr(val)
+		}
+	}
+	update := bson.M{"$unset": unset}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	err = db.Update(ProjectRefCollection,
+		bson.M{
+			ProjectRefIdKey:       projectID,
+			ProjectRefArchivedKey: true,
+		},
+		update)
+	if adb.ResultsNotFound(err) {
+		return nil, errors.Errorf("project '%s' is not archived", projectID)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "resurrecting project '%s'", projectID)
+	}
+	if err = db.Remove(ProjectArchiveCollection, bson.M{projectArchiveProjectIDKey: projectID}); err != nil {
+		return nil, errors.Wrapf(err, "removing archive for project '%s'", projectID)
+	}
+
+	grip.Error(message.WrapError(logProjectArchivalEvent(EventTypeProjectResurrected, projectID, caller, before), message.Fields{
+		"message": "could not log project resurrected event",
+		"project": projectID,
+	}))
+
+	pRef, err = FindBranchProjectRef(projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding resurrected project '%s'", projectID)
+	}
+	return pRef, nil
+}
+
+// logProjectArchivalEvent logs the change to the project's settings from
+// archiving or resurrecting it.
+func logProjectArchivalEvent(eventType, projectID, caller string, before *ProjectSettings) error {
+	after, err := GetProjectSettingsById(projectID, false)
+	if err != nil {
+		return errors.Wrap(err, "getting project settings after change")
+	}
+	return LogProjectEvent(eventType, projectID, ProjectChangeEvent{
+		User:   caller,
+		Before: *before,
+		After:  *after,
+	})
+}
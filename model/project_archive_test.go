@@ -0,0 +1,119 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveAndResurrectProject(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ProjectRefCollection, ProjectArchiveCollection, ProjectVarsCollection,
+		ProjectAliasCollection, event.SubscriptionsCollection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection, ProjectArchiveCollection, ProjectVarsCollection,
+			ProjectAliasCollection, event.SubscriptionsCollection, event.AllLogCollection))
+	}()
+
+	upstream := ProjectRef{Id: "upstream", Identifier: "upstream", Enabled: utility.TruePtr()}
+	require.NoError(t, upstream.Insert())
+	pRef := ProjectRef{
+		Id:               "p",
+		Identifier:       "p",
+		Owner:            "evergreen-ci",
+		Repo:             "evergreen",
+		Branch:           "main",
+		Enabled:          utility.TruePtr(),
+		PRTestingEnabled: utility.TruePtr(),
+		CommitQueue:      CommitQueueParams{Enabled: utility.TruePtr()},
+		Triggers: []TriggerDefinition{
+			{Project: upstream.Id, Level: ProjectTriggerLevelTask, ConfigFile: "evergreen.yml", DefinitionID: "trigger"},
+		},
+		PeriodicBuilds: []PeriodicBuildDefinition{{ID: "periodic", ConfigFile: "evergreen.yml", IntervalHours: 1}},
+	}
+	require.NoError(t, pRef.Insert())
+
+	archive, err := ArchiveProject(&pRef, "me", "no longer maintained")
+	require.NoError(t, err)
+	assert.Equal(t, "me", archive.ArchivedBy)
+
+	dbRef, err := FindBranchProjectRef(pRef.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbRef)
+	assert.True(t, dbRef.IsArchived())
+	assert.False(t, dbRef.IsEnabled())
+	assert.True(t, dbRef.IsRepotrackerDisabled())
+	assert.True(t, dbRef.IsDispatchingDisabled())
+	assert.False(t, dbRef.IsPRTestingEnabled())
+	assert.False(t, dbRef.CommitQueue.IsEnabled())
+	assert.Empty(t, dbRef.Triggers)
+	assert.Empty(t, dbRef.PeriodicBuilds)
+
+	_, err = ArchiveProject(dbRef, "me", "")
+	assert.Error(t, err)
+
+	tracked, err := FindAllMergedTrackedProjectRefs()
+	require.NoError(t, err)
+	require.Len(t, tracked, 1)
+	assert.Equal(t, upstream.Id, tracked[0].Id)
+	projects, err := FindProjects("", 10, 1, true)
+	require.NoError(t, err)
+	assert.Len(t, projects, 2)
+
+	dbArchive, err := FindProjectArchive(pRef.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbArchive)
+	assert.Empty(t, dbArchive.ValidateResurrection(nil))
+	assert.NotEmpty(t, dbArchive.ValidateResurrection([]string{"another-org"}))
+
+	resurrected, err := ResurrectProject(pRef.Id, "me")
+	require.NoError(t, err)
+	assert.False(t, resurrected.IsArchived())
+	assert.True(t, resurrected.IsEnabled())
+	assert.Nil(t, resurrected.RepotrackerDisabled)
+	assert.True(t, resurrected.IsPRTestingEnabled())
+	assert.True(t, resurrected.CommitQueue.IsEnabled())
+	require.Len(t, resurrected.Triggers, 1)
+	assert.Equal(t, "trigger", resurrected.Triggers[0].DefinitionID)
+	require.Len(t, resurrected.PeriodicBuilds, 1)
+	assert.True(t, resurrected.PeriodicBuilds[0].NextRunTime.IsZero())
+
+	dbArchive, err = FindProjectArchive(pRef.Id)
+	require.NoError(t, err)
+	assert.Nil(t, dbArchive)
+
+	events, err := MostRecentProjectEvents(pRef.Id, 5)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.ElementsMatch(t, []string{EventTypeProjectArchived, EventTypeProjectResurrected}, []string{events[0].EventType, events[1].EventType})
+
+	_, err = ResurrectProject(pRef.Id, "me")
+	assert.Error(t, err)
+}
+
+func TestResurrectProjectUnsetsInheritedSettings(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ProjectRefCollection, ProjectArchiveCollection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection, ProjectArchiveCollection, event.AllLogCollection))
+	}()
+
+	pRef := ProjectRef{
+		Id:         "p",
+		Identifier: "p",
+		Owner:      "evergreen-ci",
+		Repo:       "evergreen",
+		Branch:     "main",
+		Enabled:    utility.TruePtr(),
+	}
+	require.NoError(t, pRef.Insert())
+	_, err := ArchiveProject(&pRef, "me", "")
+	require.NoError(t, err)
+
+	resurrected, err := ResurrectProject(pRef.Id, "me")
+	require.NoError(t, err)
+	assert.Nil(t, resurrected.Triggers, "should inherit triggers from the repo")
+	assert.Nil(t, resurrected.PeriodicBuilds, "should inherit periodic builds from the repo")
+}
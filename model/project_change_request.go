@@ -0,0 +1,197 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const ProjectChangeRequestsCollection = "project_change_requests"
+
+const (
+	EventTypeProjectChangeRequested = "PROJECT_CHANGE_REQUESTED"
+	EventTypeProjectChangeApproved  = "PROJECT_CHANGE_APPROVED"
+	EventTypeProjectChangeRejected  = "PROJECT_CHANGE_REJECTED"
+)
+
+const (
+	ProjectChangeRequestPending  = "pending"
+	ProjectChangeRequestApproved = "approved"
+	ProjectChangeRequestRejected = "rejected"
+	ProjectChangeRequestFailed   = "failed"
+)
+
+// ProjectChangeRequest is a modification to the settings of a project that
+// requires approval by a second project admin before it is applied.
+type ProjectChangeRequest struct {
+	Id         string    `bson:"_id" json:"id"`
+	ProjectId  string    `bson:"project_id" json:"project_id"`
+	Author     string    `bson:"author" json:"author"`
+	Status     string    `bson:"status" json:"status"`
+	CreateTime time.Time `bson:"create_time" json:"create_time"`
+	Reviewer   string    `bson:"reviewer,omitempty" json:"reviewer,omitempty"`
+	ReviewTime time.Time `bson:"review_time,omitempty" json:"review_time,omitempty"`
+	// Error is set if the change was approved but could not be applied.
+	Error string `bson:"error,omitempty" json:"error,omitempty"`
+
+	// Before and After are the project settings at the time the request was
+	// made and the settings requested by the author, respectively.
+	Before ProjectRef `bson:"before" json:"before"`
+	After  ProjectRef `bson:"after" json:"after"`
+	// ChangedFields lists the settings that differ between Before and After.
+	ChangedFields []string `bson:"changed_fields" json:"changed_fields"`
+	// Body is the original request, which is replayed once the change is
+	// approved.
+	Body string `bson:"body" json:"-"`
+}
+
+var (
+	projectChangeRequestIdKey         = bsonutil.MustHaveTag(ProjectChangeRequest{}, "Id")
+	projectChangeRequestProjectIdKey  = bsonutil.MustHaveTag(ProjectChangeRequest{}, "ProjectId")
+	projectChangeRequestAuthorKey     = bsonutil.MustHaveTag(ProjectChangeRequest{}, "Author")
+	projectChangeRequestStatusKey     = bsonutil.MustHaveTag(ProjectChangeRequest{}, "Status")
+	projectChangeRequestCreateTimeKey = bsonutil.MustHaveTag(ProjectChangeRequest{}, "CreateTime")
+	projectChangeRequestReviewerKey   = bsonutil.MustHaveTag(ProjectChangeRequest{}, "Reviewer")
+	projectChangeRequestReviewTimeKey = bsonutil.MustHaveTag(ProjectChangeRequest{}, "ReviewTime")
+	projectChangeRequestErrorKey      = bsonutil.MustHaveTag(ProjectChangeRequest{}, "Error")
+)
+
+// NewProjectChangeRequest returns a pending change request for the given
+// project settings change.
+func NewProjectChangeRequest(author string, before, after ProjectRef, body string) *ProjectChangeRequest {
+	return &ProjectChangeRequest{
+		Id:            mgobson.NewObjectId().Hex(),
+		ProjectId:     before.Id,
+		Author:        author,
+		Status:        ProjectChangeRequestPending,
+		CreateTime:    time.Now(),
+		Before:        before,
+		After:         after,
+		ChangedFields: ProjectRefChangedFields(before, after),
+		Body:          body,
+	}
+}
+
+// Insert stores the change request and logs an audit event for it.
+func (r *ProjectChangeRequest) Insert() error {
+	if err := db.Insert(ProjectChangeRequestsCollection, r); err != nil {
+		return errors.Wrapf(err, "inserting change request for project '%s'", r.ProjectId)
+	}
+	return r.logEvent(EventTypeProjectChangeRequested, r.Author)
+}
+
+// Review atomically transitions a pending change request to approved or
+// rejected. The reviewer must not be the author of the change.
+func (r *ProjectChangeRequest) Review(reviewer string, approve bool) error {
+	if reviewer == r.Author {
+		return errors.New("change requests must be reviewed by someone other than the author")
+	}
+	status := ProjectChangeRequestRejected
+	eventType := EventTypeProjectChangeRejected
+	if approve {
+		status = ProjectChangeRequestApproved
+		eventType = EventTypeProjectChangeApproved
+	}
+
+	now := time.Now()
+	_, err := db.FindAndModify(ProjectChangeRequestsCollection,
+		bson.M{
+			projectChangeRequestIdKey:     r.Id,
+			projectChangeRequestStatusKey: ProjectChangeRequestPending,
+			projectChangeRequestAuthorKey: bson.M{"$ne": reviewer},
+		},
+		nil,
+		adb.Change{
+			Update: bson.M{
+				"$set": bson.M{
+					projectChangeRequestStatusKey:     status,
+					projectChangeRequestReviewerKey:   reviewer,
+					projectChangeRequestReviewTimeKey: now,
+				},
+			},
+			ReturnNew: true,
+		},
+		r,
+	)
+	if adb.ResultsNotFound(err) {
+		return errors.Errorf("change request '%s' is no longer pending", r.Id)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "reviewing change request '%s'", r.Id)
+	}
+
+	return r.logEvent(eventType, reviewer)
+}
+
+// SetFailed records that an approved change could not be applied.
+func (r *ProjectChangeRequest) SetFailed(applyErr error) error {
+	r.Status = ProjectChangeRequestFailed
+	r.Error = applyErr.Error()
+	return db.Update(ProjectChangeRequestsCollection,
+		bson.M{projectChangeRequestIdKey: r.Id},
+		bson.M{
+			"$set": bson.M{
+				projectChangeRequestStatusKey: r.Status,
+				projectChangeRequestErrorKey:  r.Error,
+			},
+		},
+	)
+}
+
+func (r *ProjectChangeRequest) logEvent(eventType, username string) error {
+	return LogProjectEvent(eventType, r.ProjectId, ProjectChangeEvent{
+		User:   username,
+		Before: ProjectSettings{ProjectRef: r.Before},
+		After:  ProjectSettings{ProjectRef: r.After},
+	})
+}
+
+// FindProjectChangeRequestById returns the change request with the given ID,
+// if it exists.
+func FindProjectChangeRequestById(id string) (*ProjectChangeRequest, error) {
+	r := &ProjectChangeRequest{}
+	err := db.FindOneQ(ProjectChangeRequestsCollection, db.Query(bson.M{projectChangeRequestIdKey: id}), r)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return r, err
+}
+
+// FindPendingProjectChangeRequests returns the change requests for the
+// project that are awaiting review, oldest first.
+func FindPendingProjectChangeRequests(projectId string) ([]ProjectChangeRequest, error) {
+	requests := []ProjectChangeRequest{}
+	q := db.Query(bson.M{
+		projectChangeRequestProjectIdKey: projectId,
+		projectChangeRequestStatusKey:    ProjectChangeRequestPending,
+	}).Sort([]string{projectChangeRequestCreateTimeKey})
+	err := db.FindAllQ(ProjectChangeRequestsCollection, q, &requests)
+	return requests, err
+}
+
+// ProjectRefChangedFields returns the BSON names of the top-level project ref
+// fields that differ between the two refs.
+func ProjectRefChangedFields(before, after ProjectRef) []string {
+	changed := []string{}
+	beforeVal := reflect.ValueOf(before)
+	afterVal := reflect.ValueOf(after)
+	for i := 0; i < beforeVal.NumField(); i++ {
+		if reflect.DeepEqual(beforeVal.Field(i).Interface(), afterVal.Field(i).Interface()) {
+			continue
+		}
+		field := beforeVal.Type().Field(i)
+		name := strings.Split(field.Tag.Get("bson"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		changed = append(changed, name)
+	}
+	return changed
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectChangeRequests(t *testing.T) {
+	for tName, tCase := range map[string]func(t *testing.T, cr *ProjectChangeRequest){
+		"RecordsChangedFields": func(t *testing.T, cr *ProjectChangeRequest) {
+			assert.Equal(t, []string{"enabled", "batch_time"}, cr.ChangedFields)
+			assert.Equal(t, ProjectChangeRequestPending, cr.Status)
+		},
+		"PendingRequestsAreFound": func(t *testing.T, cr *ProjectChangeRequest) {
+			pending, err := FindPendingProjectChangeRequests("p1")
+			require.NoError(t, err)
+			require.Len(t, pending, 1)
+			assert.Equal(t, cr.Id, pending[0].Id)
+			assert.Equal(t, cr.Body, pending[0].Body)
+		},
+		"AuthorCannotApprove": func(t *testing.T, cr *ProjectChangeRequest) {
+			assert.Error(t, cr.Review("author", true))
+			dbRequest, err := FindProjectChangeRequestById(cr.Id)
+			require.NoError(t, err)
+			require.NotNil(t, dbRequest)
+			assert.Equal(t, ProjectChangeRequestPending, dbRequest.Status)
+		},
+		"ApprovalIsOnlyAppliedOnce": func(t *testing.T, cr *ProjectChangeRequest) {
+			require.NoError(t, cr.Review("reviewer", true))
+			assert.Equal(t, ProjectChangeRequestApproved, cr.Status)
+			assert.Equal(t, "reviewer", cr.Reviewer)
+
+			dbRequest, err := FindProjectChangeRequestById(cr.Id)
+			require.NoError(t, err)
+			require.NotNil(t, dbRequest)
+			assert.Error(t, dbRequest.Review("other-reviewer", true))
+
+			pending, err := FindPendingProjectChangeRequests("p1")
+			require.NoError(t, err)
+			assert.Empty(t, pending)
+		},
+		"ReviewsAreAudited": func(t *testing.T, cr *ProjectChangeRequest) {
+			require.NoError(t, cr.Review("reviewer", false))
+			events, err := MostRecentProjectEvents("p1", 5)
+			require.NoError(t, err)
+			require.Len(t, events, 2)
+			assert.Equal(t, EventTypeProjectChangeRejected, events[0].EventType)
+			assert.Equal(t, "reviewer", events[0].Data.(*ProjectChangeEvent).User)
+			assert.Equal(t, EventTypeProjectChangeRequested, events[1].EventType)
+			assert.Equal(t, "author", events[1].Data.(*ProjectChangeEvent).User)
+		},
+		"FailedRequestsAreNotPending": func(t *testing.T, cr *ProjectChangeRequest) {
+			require.NoError(t, cr.Review("reviewer", true))
+			require.NoError(t, cr.SetFailed(assert.AnError))
+			dbRequest, err := FindProjectChangeRequestById(cr.Id)
+			require.NoError(t, err)
+			require.NotNil(t, dbRequest)
+			assert.Equal(t, ProjectChangeRequestFailed, dbRequest.Status)
+			assert.Equal(t, assert.AnError.Error(), dbRequest.Error)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(ProjectChangeRequestsCollection, event.AllLogCollection))
+			before := ProjectRef{
+				Id:         "p1",
+				Enabled:    utility.FalsePtr(),
+				Restricted: utility.TruePtr(),
+				BatchTime:  10,
+			}
+			after := before
+			after.Enabled = utility.TruePtr()
+			after.BatchTime = 20
+			cr := NewProjectChangeRequest("author", before, after, `{"enabled": true, "batch_time": 20}`)
+			require.NoError(t, cr.Insert())
+
+			tCase(t, cr)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"bytes"
+	"encoding/json"
 	"reflect"
 	"time"
 
@@ -10,12 +12,16 @@ import (
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
 )
 
 type ProjectConfig struct {
 	Id         string    `yaml:"_id" bson:"_id"`
 	CreateTime time.Time `yaml:"create_time,omitempty" bson:"create_time,omitempty"`
 	Project    string    `yaml:"project,omitempty" bson:"project,omitempty"`
+	// ConfigUpdateNumber is incremented each time the stored project config
+	// is replaced, so that concurrent replacements can be detected.
+	ConfigUpdateNumber int `yaml:"-" bson:"config_number,omitempty"`
 	// ProjectConfigFields are the properties on the project config that do not duplicate parser project's fields to allow strict unmarshalling of a full config file.
 	// Since a config file gets split into ParserProject and ProjectConfig, strict unmarshalling does not work when duplicate fields exist (e.g. Id, CreateTime).
 	ProjectConfigFields `yaml:",inline" bson:",inline"`
@@ -37,6 +43,10 @@ type ProjectConfigFields struct {
 	GithubTriggerAliases   []string                       `yaml:"github_trigger_aliases,omitempty" bson:"github_trigger_aliases,omitempty"`
 	PeriodicBuilds         []PeriodicBuildDefinition      `yaml:"periodic_builds,omitempty" bson:"periodic_builds,omitempty"`
 	ContainerSizes         map[string]ContainerResources  `yaml:"container_sizes,omitempty" bson:"container_sizes,omitempty"`
+	// ValidationSuppressions hide the project config validation warnings
+	// that the project can't act on. They're also read into the parser
+	// project so that they apply whether or not version control is enabled.
+	ValidationSuppressions []ValidationSuppression `yaml:"validation_suppressions,omitempty" bson:"validation_suppressions,omitempty"`
 }
 
 // Comment above is used by the linter to detect the end of the struct.
@@ -50,13 +60,17 @@ func (pc *ProjectConfig) MarshalBSON() ([]byte, error) {
 }
 
 func (pc *ProjectConfig) isEmpty() bool {
-	reflectedConfig := reflect.ValueOf(pc).Elem()
+	// Validation suppressions are also read into the parser project, so they
+	// apply without a project config and don't make it non-empty.
+	withoutSuppressions := *pc
+	withoutSuppressions.ValidationSuppressions = nil
+	reflectedConfig := reflect.ValueOf(&withoutSuppressions).Elem()
 	types := reflect.TypeOf(pc).Elem()
 
 	for i := 0; i < reflectedConfig.NumField(); i++ {
 		field := reflectedConfig.Field(i)
 		name := types.Field(i).Name
-		if name != "Id" && name != "Identifier" {
+		if name != "Id" && name != "Identifier" && name != "ConfigUpdateNumber" {
 			if !util.IsFieldUndefined(field) {
 				return false
 			}
@@ -82,3 +96,60 @@ func CreateProjectConfig(yml []byte, identifier string) (*ProjectConfig, error)
 	}
 	return p, nil
 }
+
+// FieldsJSON returns the project config's fields as a JSON object keyed by
+// the same names as in the project config YAML.
+func (pc *ProjectConfig) FieldsJSON() ([]byte, error) {
+	yml, err := yaml.Marshal(pc.ProjectConfigFields)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling project config fields to YAML")
+	}
+	fields := map[string]interface{}{}
+	if err = yaml.Unmarshal(yml, &fields); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling project config fields from YAML")
+	}
+	fieldsJSON, err := json.Marshal(fields)
+	return fieldsJSON, errors.Wrap(err, "marshalling project config fields to JSON")
+}
+
+// ApplyJSONPatch returns a copy of the project config with the RFC 6902 JSON
+// Patch applied to its fields. The patch paths refer to the fields by their
+// names in the project config YAML, and the patched fields must be valid
+// project config YAML.
+func (pc *ProjectConfig) ApplyJSONPatch(patch []byte) (*ProjectConfig, error) {
+	fieldsJSON, err := pc.FieldsJSON()
+	if err != nil {
+		return nil, err
+	}
+	patchedJSON, err := util.ApplyJSONPatch(fieldsJSON, patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "applying JSON patch")
+	}
+	// Numbers are kept as they were written so that integers are not
+	// converted to floats.
+	dec := json.NewDecoder(bytes.NewReader(patchedJSON))
+	dec.UseNumber()
+	var patchedFields interface{}
+	if err = dec.Decode(&patchedFields); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling patched project config fields")
+	}
+	if _, ok := patchedFields.(map[string]interface{}); !ok {
+		return nil, errors.New("patched project config must be an object")
+	}
+	// Round-trip through YAML so that the patched fields are held to the same
+	// schema as a project config file.
+	patchedYAML, err := yaml.Marshal(patchedFields)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling patched project config fields to YAML")
+	}
+	patched := &ProjectConfig{
+		Id:                 pc.Id,
+		CreateTime:         pc.CreateTime,
+		Project:            pc.Project,
+		ConfigUpdateNumber: pc.ConfigUpdateNumber,
+	}
+	if err = util.UnmarshalYAMLStrictWithFallback(patchedYAML, &patched.ProjectConfigFields); err != nil {
+		return nil, errors.Wrap(err, "patched project config is invalid")
+	}
+	return patched, nil
+}
@@ -16,6 +16,7 @@ var (
 	ProjectConfigIdKey         = bsonutil.MustHaveTag(ProjectConfig{}, "Id")
 	ProjectConfigProjectKey    = bsonutil.MustHaveTag(ProjectConfig{}, "Project")
 	ProjectConfigCreateTimeKey = bsonutil.MustHaveTag(ProjectConfig{}, "CreateTime")
+	ProjectConfigNumberKey     = bsonutil.MustHaveTag(ProjectConfig{}, "ConfigUpdateNumber")
 )
 
 // FindProjectConfigForProjectOrVersion returns a project config by id, or the most recent project config if id is empty
@@ -58,6 +59,28 @@ func FindProjectConfigById(id string) (*ProjectConfig, error) {
 	return project, err
 }
 
+// Replace replaces the stored project config with this one and increments
+// its config number, but only if the stored project config has not been
+// replaced since this one was read. If it has, the returned error is a not
+// found error and this project config is left unchanged.
+func (pc *ProjectConfig) Replace() error {
+	q := bson.M{ProjectConfigIdKey: pc.Id}
+	if pc.ConfigUpdateNumber == 0 {
+		q["$or"] = []bson.M{
+			{ProjectConfigNumberKey: bson.M{"$exists": false}},
+			{ProjectConfigNumberKey: 0},
+		}
+	} else {
+		q[ProjectConfigNumberKey] = pc.ConfigUpdateNumber
+	}
+	pc.ConfigUpdateNumber++
+	if err := db.Update(ProjectConfigCollection, q, pc); err != nil {
+		pc.ConfigUpdateNumber--
+		return err
+	}
+	return nil
+}
+
 // ProjectConfigUpsertOne updates one project config
 func ProjectConfigUpsertOne(query interface{}, update interface{}) error {
 	_, err := db.Upsert(
@@ -3,8 +3,11 @@ package model
 import (
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	adb "github.com/mongodb/anser/db"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateConfig(t *testing.T) {
@@ -42,3 +45,76 @@ commit_queue_aliases:
 	assert.NotNil(t, pc)
 	assert.Equal(t, "BF", pc.BuildBaronSettings.TicketCreateProject)
 }
+
+func TestProjectConfigApplyJSONPatch(t *testing.T) {
+	pc := &ProjectConfig{
+		Id:      "v1",
+		Project: "p1",
+		ProjectConfigFields: ProjectConfigFields{
+			BuildBaronSettings: &evergreen.BuildBaronSettings{TicketCreateProject: "BF"},
+			ContainerSizes:     map[string]ContainerResources{"small": {MemoryMB: 512, CPU: 1}},
+		},
+	}
+
+	t.Run("PatchesFieldsByYAMLName", func(t *testing.T) {
+		patched, err := pc.ApplyJSONPatch([]byte(`[
+			{"op": "test", "path": "/build_baron_settings/ticket_create_project", "value": "BF"},
+			{"op": "replace", "path": "/container_sizes/small/cpu", "value": 2},
+			{"op": "add", "path": "/github_trigger_aliases", "value": ["downstream"]}
+		]`))
+		require.NoError(t, err)
+		assert.Equal(t, pc.Id, patched.Id)
+		assert.Equal(t, pc.Project, patched.Project)
+		assert.Equal(t, "BF", patched.BuildBaronSettings.TicketCreateProject)
+		assert.Equal(t, ContainerResources{MemoryMB: 512, CPU: 2}, patched.ContainerSizes["small"])
+		assert.Equal(t, []string{"downstream"}, patched.GithubTriggerAliases)
+		assert.Equal(t, 1, pc.ContainerSizes["small"].CPU, "original config should not be modified")
+	})
+	t.Run("RemovesFields", func(t *testing.T) {
+		patched, err := pc.ApplyJSONPatch([]byte(`[{"op": "remove", "path": "/container_sizes"}]`))
+		require.NoError(t, err)
+		assert.Empty(t, patched.ContainerSizes)
+		assert.NotNil(t, patched.BuildBaronSettings)
+	})
+	t.Run("FailsWithUnknownField", func(t *testing.T) {
+		_, err := pc.ApplyJSONPatch([]byte(`[{"op": "add", "path": "/not_a_field", "value": true}]`))
+		assert.Error(t, err)
+	})
+	t.Run("FailsWithWrongType", func(t *testing.T) {
+		_, err := pc.ApplyJSONPatch([]byte(`[{"op": "replace", "path": "/container_sizes/small/cpu", "value": "two"}]`))
+		assert.Error(t, err)
+	})
+	t.Run("FailsWithFailedTest", func(t *testing.T) {
+		_, err := pc.ApplyJSONPatch([]byte(`[{"op": "test", "path": "/build_baron_settings/ticket_create_project", "value": "EVG"}]`))
+		assert.Error(t, err)
+	})
+}
+
+func TestProjectConfigReplace(t *testing.T) {
+	require.NoError(t, db.Clear(ProjectConfigCollection))
+	defer func() {
+		assert.NoError(t, db.Clear(ProjectConfigCollection))
+	}()
+
+	pc := &ProjectConfig{Id: "v1", Project: "p1"}
+	require.NoError(t, pc.Insert())
+
+	first, err := FindProjectConfigById("v1")
+	require.NoError(t, err)
+	second, err := FindProjectConfigById("v1")
+	require.NoError(t, err)
+
+	first.GithubTriggerAliases = []string{"first"}
+	require.NoError(t, first.Replace())
+	assert.Equal(t, 1, first.ConfigUpdateNumber)
+
+	second.GithubTriggerAliases = []string{"second"}
+	err = second.Replace()
+	assert.True(t, adb.ResultsNotFound(err), "replacing a stale project config should fail")
+	assert.Equal(t, 0, second.ConfigUpdateNumber)
+
+	stored, err := FindProjectConfigById("v1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first"}, stored.GithubTriggerAliases)
+	assert.Equal(t, 1, stored.ConfigUpdateNumber)
+}
@@ -0,0 +1,271 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// ProjectDependencyTrigger means the dependent project has a trigger that
+	// creates versions when the project's tasks or builds finish.
+	ProjectDependencyTrigger = "trigger"
+	// ProjectDependencyPatchTriggerAlias means the dependent project has a
+	// patch trigger alias that creates child patches in the project.
+	ProjectDependencyPatchTriggerAlias = "patch_trigger_alias"
+	// ProjectDependencyModule means the dependent project's recent configs
+	// include the project's repo and branch as a module.
+	ProjectDependencyModule = "module"
+
+	// ProjectDependencyActionDeleted and ProjectDependencyActionDisabled are
+	// the changes to a project that break the projects that depend on it.
+	ProjectDependencyActionDeleted  = "deleted"
+	ProjectDependencyActionDisabled = "disabled"
+)
+
+// projectDependentModuleLookback is how far back to look for project configs
+// that use a project's repo and branch as a module.
+const projectDependentModuleLookback = 30 * 24 * time.Hour
+
+var (
+	patchTriggerDefinitionChildProjectKey = bsonutil.MustHaveTag(patch.PatchTriggerDefinition{}, "ChildProject")
+	moduleRepoKey                         = bsonutil.MustHaveTag(Module{}, "Repo")
+	moduleBranchKey                       = bsonutil.MustHaveTag(Module{}, "Branch")
+)
+
+// ProjectDependent is an enabled project that would break if the project it
+// depends on were deleted or disabled.
+type ProjectDependent struct {
+	ProjectID  string   `json:"project_id"`
+	Identifier string   `json:"identifier"`
+	Admins     []string `json:"admins"`
+	// Kinds are the ways in which the project depends on the other project.
+	Kinds []string `json:"kinds"`
+}
+
+// FindProjectDependents returns the enabled projects that have triggers or
+// patch trigger aliases pointing to the project, or that recently used the
+// project's repo and branch as a module.
+func FindProjectDependents(pRef *ProjectRef) ([]ProjectDependent, error) {
+	dependentsByID := map[string]*ProjectDependent{}
+	addDependent := func(dependentRef *ProjectRef, kind string) {
+		dependent, ok := dependentsByID[dependentRef.Id]
+		if !ok {
+			dependent = &ProjectDependent{
+				ProjectID:  dependentRef.Id,
+				Identifier: dependentRef.Identifier,
+				Admins:     dependentRef.Admins,
+			}
+			dependentsByID[dependentRef.Id] = dependent
+		}
+		if !utility.StringSliceContains(dependent.Kinds, kind) {
+			dependent.Kinds = append(dependent.Kinds, kind)
+		}
+	}
+	isProject := func(name string) bool {
+		return name != "" && (name == pRef.Id || name == pRef.Identifier)
+	}
+
+	candidates := []ProjectRef{}
+	if err := db.Aggregate(ProjectRefCollection, projectRefPipelineForDependents(pRef), &candidates); err != nil {
+		return nil, errors.Wrapf(err, "finding projects with triggers for project '%s'", pRef.Identifier)
+	}
+	for _, candidate := range candidates {
+		mergedRef, err := FindMergedProjectRef(candidate.Id, "", false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding merged project ref for project '%s'", candidate.Id)
+		}
+		if mergedRef == nil || !mergedRef.IsEnabled() {
+			continue
+		}
+		for _, trigger := range mergedRef.Triggers {
+			if isProject(trigger.Project) {
+				addDependent(mergedRef, ProjectDependencyTrigger)
+			}
+		}
+		for _, alias := range mergedRef.PatchTriggerAliases {
+			if isProject(alias.ChildProject) {
+				addDependent(mergedRef, ProjectDependencyPatchTriggerAlias)
+			}
+		}
+	}
+
+	moduleProjectIDs, err := findProjectsUsingModule(pRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding projects using project '%s' as a module", pRef.Identifier)
+	}
+	for _, projectID := range moduleProjectIDs {
+		mergedRef, err := FindMergedProjectRef(projectID, "", false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding merged project ref for project '%s'", projectID)
+		}
+		if mergedRef == nil || !mergedRef.IsEnabled() || mergedRef.Id == pRef.Id {
+			continue
+		}
+		addDependent(mergedRef, ProjectDependencyModule)
+	}
+
+	dependents := make([]ProjectDependent, 0, len(dependentsByID))
+	for _, dependent := range dependentsByID {
+		dependents = append(dependents, *dependent)
+	}
+	sort.Slice(dependents, func(i, j int) bool {
+		return dependents[i].Identifier < dependents[j].Identifier
+	})
+	return dependents, nil
+}
+
+// ModuleDependent is an enabled project whose most recent valid config
+// includes a project's repo and branch as a module.
+type ModuleDependent struct {
+	ProjectID  string   `json:"project_id"`
+	Identifier string   `json:"identifier"`
+	Admins     []string `json:"admins"`
+	// ModuleName is the name of the module in the dependent project's config.
+	ModuleName string `json:"module_name"`
+}
+
+// FindModuleDependents returns the enabled projects that include the
+// project's repo and branch as a module in their most recent valid configs,
+// along with the name of the module in each of them.
+func FindModuleDependents(pRef *ProjectRef) ([]ModuleDependent, error) {
+	projectIDs, err := findProjectsUsingModule(pRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding projects using project '%s' as a module", pRef.Identifier)
+	}
+
+	dependents := []ModuleDependent{}
+	for _, projectID := range projectIDs {
+		mergedRef, err := FindMergedProjectRef(projectID, "", false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding merged project ref for project '%s'", projectID)
+		}
+		if mergedRef == nil || !mergedRef.IsEnabled() || mergedRef.Id == pRef.Id {
+			continue
+		}
+		_, project, err := FindLatestVersionWithValidProjectProjection(mergedRef.Id, ProjectProjectionModules)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding most recent modules for project '%s'", mergedRef.Identifier)
+		}
+		// The project may have stopped using the module since the config
+		// that matched.
+		module := project.findModuleForRepo(pRef.Owner, pRef.Repo, pRef.Branch)
+		if module == nil {
+			continue
+		}
+		dependents = append(dependents, ModuleDependent{
+			ProjectID:  mergedRef.Id,
+			Identifier: mergedRef.Identifier,
+			Admins:     mergedRef.Admins,
+			ModuleName: module.Name,
+		})
+	}
+	sort.Slice(dependents, func(i, j int) bool {
+		return dependents[i].Identifier < dependents[j].Identifier
+	})
+	return dependents, nil
+}
+
+// findModuleForRepo returns the project's module for the repo and branch, or
+// nil if the project doesn't use it as a module.
+func (p *Project) findModuleForRepo(owner, repo, branch string) *Module {
+	if p == nil {
+		return nil
+	}
+	repoRegex := regexp.MustCompile(moduleRepoRegex(owner, repo))
+	for i := range p.Modules {
+		if repoRegex.MatchString(p.Modules[i].Repo) && p.Modules[i].Branch == branch {
+			return &p.Modules[i]
+		}
+	}
+	return nil
+}
+
+// moduleRepoRegex returns a regular expression that matches the module repo
+// URLs that refer to the GitHub repo.
+func moduleRepoRegex(owner, repo string) string {
+	return fmt.Sprintf(`(?i)[:/]%s/%s(\.git)?$`, regexp.QuoteMeta(owner), regexp.QuoteMeta(repo))
+}
+
+// findProjectsUsingModule returns the IDs of the projects whose recent
+// configs include the project's repo and branch as a module.
+func findProjectsUsingModule(pRef *ProjectRef) ([]string, error) {
+	if pRef.Owner == "" || pRef.Repo == "" || pRef.Branch == "" {
+		return nil, nil
+	}
+	repoRegex := moduleRepoRegex(pRef.Owner, pRef.Repo)
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			ParserProjectCreateTimeKey: bson.M{"$gte": time.Now().Add(-projectDependentModuleLookback)},
+			ParserProjectModulesKey: bson.M{"$elemMatch": bson.M{
+				moduleRepoKey:   bson.M{"$regex": repoRegex},
+				moduleBranchKey: pRef.Branch,
+			}},
+		}},
+		{"$group": bson.M{"_id": "$" + ParserProjectIdentifierKey}},
+	}
+	results := []struct {
+		ProjectID string `bson:"_id"`
+	}{}
+	if err := db.Aggregate(ParserProjectCollection, pipeline, &results); err != nil {
+		return nil, err
+	}
+	projectIDs := make([]string, 0, len(results))
+	for _, res := range results {
+		if res.ProjectID != "" {
+			projectIDs = append(projectIDs, res.ProjectID)
+		}
+	}
+	return projectIDs, nil
+}
+
+// projectRefPipelineForDependents is an aggregation pipeline to find projects
+// other than the given project that have a trigger or patch trigger alias
+// that points to the given project, either directly or through their repo.
+func projectRefPipelineForDependents(pRef *ProjectRef) []bson.M {
+	names := utility.UniqueStrings([]string{pRef.Id, pRef.Identifier})
+	return []bson.M{
+		{"$match": bson.M{ProjectRefIdKey: bson.M{"$ne": pRef.Id}}},
+		lookupRepoStep,
+		{"$match": bson.M{
+			"$or": []bson.M{
+				{bsonutil.GetDottedKeyName(projectRefTriggersKey, triggerDefinitionProjectKey): bson.M{"$in": names}},
+				{
+					projectRefTriggersKey: nil,
+					bsonutil.GetDottedKeyName("repo_ref", RepoRefTriggersKey, triggerDefinitionProjectKey): bson.M{"$in": names},
+				},
+				{bsonutil.GetDottedKeyName(projectRefPatchTriggerAliasesKey, patchTriggerDefinitionChildProjectKey): bson.M{"$in": names}},
+				{
+					projectRefPatchTriggerAliasesKey: nil,
+					bsonutil.GetDottedKeyName("repo_ref", projectRefPatchTriggerAliasesKey, patchTriggerDefinitionChildProjectKey): bson.M{"$in": names},
+				},
+			},
+		}},
+	}
+}
+
+// LogUpstreamProjectRemoved records an event for each dependent project
+// that the project it depends on was deleted or disabled anyway.
+func LogUpstreamProjectRemoved(pRef *ProjectRef, dependents []ProjectDependent, action, caller string) error {
+	catcher := grip.NewBasicCatcher()
+	for _, dependent := range dependents {
+		catcher.Add(event.LogProjectDependencyEvent(dependent.ProjectID, event.EventUpstreamProjectRemoved, event.ProjectDependencyEventData{
+			UpstreamProjectID:  pRef.Id,
+			UpstreamIdentifier: pRef.Identifier,
+			Action:             action,
+			User:               caller,
+			Kinds:              dependent.Kinds,
+		}))
+	}
+	return catcher.Resolve()
+}
@@ -0,0 +1,172 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProjectDependents(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ProjectRefCollection, RepoRefCollection, ParserProjectCollection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection, RepoRefCollection, ParserProjectCollection, event.AllLogCollection))
+	}()
+
+	upstream := &ProjectRef{
+		Id:         "upstream_id",
+		Identifier: "upstream",
+		Owner:      "evergreen-ci",
+		Repo:       "upstream-repo",
+		Branch:     "main",
+		Enabled:    utility.TruePtr(),
+	}
+	require.NoError(t, upstream.Insert())
+	repoRef := &RepoRef{ProjectRef: ProjectRef{
+		Id:       "repo",
+		Enabled:  utility.TruePtr(),
+		Triggers: []TriggerDefinition{{Project: "upstream", Level: ProjectTriggerLevelTask}},
+	}}
+	require.NoError(t, repoRef.Upsert())
+	for _, pRef := range []ProjectRef{
+		{
+			Id:         "triggered",
+			Identifier: "triggered",
+			Admins:     []string{"admin"},
+			Enabled:    utility.TruePtr(),
+			Triggers:   []TriggerDefinition{{Project: "upstream_id", Level: ProjectTriggerLevelBuild}},
+			PatchTriggerAliases: []patch.PatchTriggerDefinition{
+				{Alias: "child", ChildProject: "upstream"},
+			},
+		},
+		{
+			Id:         "repo_triggered",
+			Identifier: "repo_triggered",
+			RepoRefId:  repoRef.Id,
+		},
+		{
+			Id:         "disabled",
+			Identifier: "disabled",
+			Enabled:    utility.FalsePtr(),
+			Triggers:   []TriggerDefinition{{Project: "upstream_id", Level: ProjectTriggerLevelTask}},
+		},
+		{
+			Id:         "module_user",
+			Identifier: "module_user",
+			Enabled:    utility.TruePtr(),
+		},
+		{
+			Id:         "unrelated",
+			Identifier: "unrelated",
+			Enabled:    utility.TruePtr(),
+			Triggers:   []TriggerDefinition{{Project: "other", Level: ProjectTriggerLevelTask}},
+		},
+	} {
+		require.NoError(t, pRef.Insert())
+	}
+	for _, pp := range []ParserProject{
+		{
+			Id:         "recent_version",
+			Identifier: utility.ToStringPtr("module_user"),
+			CreateTime: time.Now(),
+			Modules:    []Module{{Name: "upstream", Repo: "git@github.com:evergreen-ci/upstream-repo.git", Branch: "main"}},
+		},
+		{
+			Id:         "other_branch_version",
+			Identifier: utility.ToStringPtr("unrelated"),
+			CreateTime: time.Now(),
+			Modules:    []Module{{Name: "upstream", Repo: "git@github.com:evergreen-ci/upstream-repo.git", Branch: "v1"}},
+		},
+		{
+			Id:         "old_version",
+			Identifier: utility.ToStringPtr("unrelated"),
+			CreateTime: time.Now().Add(-2 * projectDependentModuleLookback),
+			Modules:    []Module{{Name: "upstream", Repo: "git@github.com:evergreen-ci/upstream-repo.git", Branch: "main"}},
+		},
+	} {
+		require.NoError(t, pp.Insert())
+	}
+
+	dependents, err := FindProjectDependents(upstream)
+	require.NoError(t, err)
+	require.Len(t, dependents, 3)
+	assert.Equal(t, "module_user", dependents[0].Identifier)
+	assert.Equal(t, []string{ProjectDependencyModule}, dependents[0].Kinds)
+	assert.Equal(t, "repo_triggered", dependents[1].Identifier)
+	assert.Equal(t, []string{ProjectDependencyTrigger}, dependents[1].Kinds)
+	assert.Equal(t, "triggered", dependents[2].Identifier)
+	assert.ElementsMatch(t, []string{ProjectDependencyTrigger, ProjectDependencyPatchTriggerAlias}, dependents[2].Kinds)
+	assert.Equal(t, []string{"admin"}, dependents[2].Admins)
+
+	require.NoError(t, LogUpstreamProjectRemoved(upstream, dependents, ProjectDependencyActionDeleted, "me"))
+	events, err := event.Find(event.AllLogCollection, db.Query(event.ResourceTypeKeyIs(event.ResourceTypeProjectDependency)))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	data, ok := events[0].Data.(*event.ProjectDependencyEventData)
+	require.True(t, ok)
+	assert.Equal(t, upstream.Id, data.UpstreamProjectID)
+	assert.Equal(t, ProjectDependencyActionDeleted, data.Action)
+}
+
+func TestFindModuleDependents(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ProjectRefCollection, ParserProjectCollection, VersionCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection, ParserProjectCollection, VersionCollection))
+	}()
+
+	upstream := &ProjectRef{
+		Id:         "upstream_id",
+		Identifier: "upstream",
+		Owner:      "evergreen-ci",
+		Repo:       "upstream-repo",
+		Branch:     "main",
+		Enabled:    utility.TruePtr(),
+	}
+	require.NoError(t, upstream.Insert())
+	for _, pRef := range []ProjectRef{
+		{Id: "module_user", Identifier: "module_user", Admins: []string{"admin"}, Enabled: utility.TruePtr()},
+		{Id: "former_module_user", Identifier: "former_module_user", Enabled: utility.TruePtr()},
+		{Id: "disabled", Identifier: "disabled", Enabled: utility.FalsePtr()},
+	} {
+		require.NoError(t, pRef.Insert())
+	}
+	upstreamModule := Module{Name: "lib", Repo: "git@github.com:evergreen-ci/upstream-repo.git", Branch: "main"}
+	for i, v := range []struct {
+		id      string
+		project string
+		modules []Module
+	}{
+		{id: "module_user_v1", project: "module_user", modules: []Module{upstreamModule}},
+		{id: "former_module_user_v1", project: "former_module_user", modules: []Module{upstreamModule}},
+		{id: "former_module_user_v2", project: "former_module_user"},
+		{id: "disabled_v1", project: "disabled", modules: []Module{upstreamModule}},
+	} {
+		version := &Version{
+			Id:                  v.id,
+			Identifier:          v.project,
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: i + 1,
+		}
+		require.NoError(t, version.Insert())
+		pp := &ParserProject{
+			Id:         v.id,
+			Identifier: utility.ToStringPtr(v.project),
+			CreateTime: time.Now(),
+			Modules:    v.modules,
+		}
+		require.NoError(t, pp.Insert())
+	}
+
+	dependents, err := FindModuleDependents(upstream)
+	require.NoError(t, err)
+	require.Len(t, dependents, 1)
+	assert.Equal(t, "module_user", dependents[0].ProjectID)
+	assert.Equal(t, "lib", dependents[0].ModuleName)
+	assert.Equal(t, []string{"admin"}, dependents[0].Admins)
+}
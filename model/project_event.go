@@ -18,6 +18,10 @@ const (
 	EventResourceTypeProject = "PROJECT"
 	EventTypeProjectModified = "PROJECT_MODIFIED"
 	EventTypeProjectAdded    = "PROJECT_ADDED"
+	// EventTypeProjectArchived and EventTypeProjectResurrected record the
+	// project's settings before and after it was archived or resurrected.
+	EventTypeProjectArchived    = "PROJECT_ARCHIVED"
+	EventTypeProjectResurrected = "PROJECT_RESURRECTED"
 )
 
 type ProjectSettings struct {
@@ -13,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/utility"
@@ -20,8 +21,13 @@ import (
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/evergreen-ci/evergreen/model")
+
 const LoadProjectError = "load project error(s)"
 const TranslateProjectError = "error translating project"
 const EmptyConfigurationError = "received empty configuration file"
@@ -57,6 +63,11 @@ type ParserProject struct {
 	ConfigUpdateNumber int    `yaml:"config_number,omitempty" bson:"config_number,omitempty"`
 	// UpdatedByGenerators is used to determine if the parser project needs to be re-saved or not.
 	UpdatedByGenerators []string `yaml:"updated_by_generators,omitempty" bson:"updated_by_generators,omitempty"`
+	// GeneratedTaskCount, GeneratedVariantCount, and GeneratedConfigSize are
+	// the totals that generators have added to the version so far.
+	GeneratedTaskCount    int `yaml:"generated_task_count,omitempty" bson:"generated_task_count,omitempty"`
+	GeneratedVariantCount int `yaml:"generated_variant_count,omitempty" bson:"generated_variant_count,omitempty"`
+	GeneratedConfigSize   int `yaml:"generated_config_size,omitempty" bson:"generated_config_size,omitempty"`
 	// List of yamls to merge
 	Include []Include `yaml:"include,omitempty" bson:"include,omitempty"`
 	Enabled *bool     `yaml:"enabled,omitempty" bson:"enabled,omitempty"`
@@ -89,8 +100,15 @@ type ParserProject struct {
 	Tasks              []parserTask               `yaml:"tasks,omitempty" bson:"tasks,omitempty"`
 	ExecTimeoutSecs    *int                       `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs,omitempty"`
 	Loggers            *LoggerConfig              `yaml:"loggers,omitempty" bson:"loggers,omitempty"`
+	ExternalGates      []ExternalGate             `yaml:"external_gates,omitempty" bson:"external_gates,omitempty"`
+	VersionGates       parserStringSlice          `yaml:"version_gates,omitempty" bson:"version_gates,omitempty"`
+	Stages             []parserStage              `yaml:"stages,omitempty" bson:"stages,omitempty"`
 	CreateTime         time.Time                  `yaml:"create_time,omitempty" bson:"create_time,omitempty"`
 
+	// ValidationSuppressions are read from the project config fields of the
+	// YAML, since the YAML key belongs to the ProjectConfig.
+	ValidationSuppressions []ValidationSuppression `yaml:"-" bson:"validation_suppressions,omitempty"`
+
 	// Matrix code
 	Axes []matrixAxis `yaml:"axes,omitempty" bson:"axes,omitempty"`
 } // End of ParserProject mergeable fields (this comment is used by the linter).
@@ -138,6 +156,14 @@ type parserTask struct {
 	GitTagOnly      *bool               `yaml:"git_tag_only,omitempty" bson:"git_tag_only,omitempty"`
 	Stepback        *bool               `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
 	MustHaveResults *bool               `yaml:"must_have_test_results,omitempty" bson:"must_have_test_results,omitempty"`
+
+	AllowedRequesters []evergreen.UserRequester `yaml:"allowed_requesters,omitempty" bson:"allowed_requesters,omitempty"`
+
+	Outputs []TaskOutputDefinition `yaml:"outputs,omitempty" bson:"outputs,omitempty"`
+
+	Compliance *task.ComplianceMetadata `yaml:"compliance,omitempty" bson:"compliance,omitempty"`
+
+	ExpectedArtifacts parserStringSlice `yaml:"expected_artifacts,omitempty" bson:"expected_artifacts,omitempty"`
 }
 
 func (pp *ParserProject) Insert() error {
@@ -164,6 +190,7 @@ func (pp *ParserProject) MarshalYAML() (interface{}, error) {
 type displayTask struct {
 	Name           string   `yaml:"name,omitempty" bson:"name,omitempty"`
 	ExecutionTasks []string `yaml:"execution_tasks,omitempty" bson:"execution_tasks,omitempty"`
+	DispatchOrder  string   `yaml:"dispatch_order,omitempty" bson:"dispatch_order,omitempty"`
 }
 
 // helper methods for task tag evaluations
@@ -175,6 +202,9 @@ type parserDependency struct {
 	TaskSelector  taskSelector `yaml:",inline"`
 	Status        string       `yaml:"status,omitempty" bson:"status,omitempty"`
 	PatchOptional bool         `yaml:"patch_optional,omitempty" bson:"patch_optional,omitempty"`
+
+	OmitGeneratedTasks bool `yaml:"omit_generated_tasks,omitempty" bson:"omit_generated_tasks,omitempty"`
+	LatestMainline     bool `yaml:"latest_mainline,omitempty" bson:"latest_mainline,omitempty"`
 }
 
 // parserDependencies is a type defined for unmarshalling both a single
@@ -209,13 +239,17 @@ func (pd *parserDependency) UnmarshalYAML(unmarshal func(interface{}) error) err
 			return err
 		}
 		otherFields := struct {
-			Status        string `yaml:"status"`
-			PatchOptional bool   `yaml:"patch_optional"`
+			Status             string `yaml:"status"`
+			PatchOptional      bool   `yaml:"patch_optional"`
+			OmitGeneratedTasks bool   `yaml:"omit_generated_tasks"`
+			LatestMainline     bool   `yaml:"latest_mainline"`
 		}{}
 		// ignore error here: expected to fail considering the single-string selector
 		_ = unmarshal(&otherFields)
 		pd.Status = otherFields.Status
 		pd.PatchOptional = otherFields.PatchOptional
+		pd.OmitGeneratedTasks = otherFields.OmitGeneratedTasks
+		pd.LatestMainline = otherFields.LatestMainline
 		return nil
 	}
 	*pd = parserDependency(copy)
@@ -298,22 +332,27 @@ func (ts *taskSelector) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 // parserBV is a helper type storing intermediary variant definitions.
 type parserBV struct {
-	Name          string             `yaml:"name,omitempty" bson:"name,omitempty"`
-	DisplayName   string             `yaml:"display_name,omitempty" bson:"display_name,omitempty"`
-	Expansions    util.Expansions    `yaml:"expansions,omitempty" bson:"expansions,omitempty"`
-	Tags          parserStringSlice  `yaml:"tags,omitempty,omitempty" bson:"tags,omitempty"`
-	Modules       parserStringSlice  `yaml:"modules,omitempty" bson:"modules,omitempty"`
-	Disabled      bool               `yaml:"disabled,omitempty" bson:"disabled,omitempty"`
-	Push          bool               `yaml:"push,omitempty" bson:"push,omitempty"`
-	BatchTime     *int               `yaml:"batchtime,omitempty" bson:"batchtime,omitempty"`
-	CronBatchTime string             `yaml:"cron,omitempty" bson:"cron,omitempty"`
-	Stepback      *bool              `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
-	RunOn         parserStringSlice  `yaml:"run_on,omitempty" bson:"run_on,omitempty"`
-	Tasks         parserBVTaskUnits  `yaml:"tasks,omitempty" bson:"tasks,omitempty"`
-	DisplayTasks  []displayTask      `yaml:"display_tasks,omitempty" bson:"display_tasks,omitempty"`
-	DependsOn     parserDependencies `yaml:"depends_on,omitempty" bson:"depends_on,omitempty"`
+	Name              string             `yaml:"name,omitempty" bson:"name,omitempty"`
+	DisplayName       string             `yaml:"display_name,omitempty" bson:"display_name,omitempty"`
+	Expansions        util.Expansions    `yaml:"expansions,omitempty" bson:"expansions,omitempty"`
+	Tags              parserStringSlice  `yaml:"tags,omitempty,omitempty" bson:"tags,omitempty"`
+	Modules           parserStringSlice  `yaml:"modules,omitempty" bson:"modules,omitempty"`
+	Disabled          bool               `yaml:"disabled,omitempty" bson:"disabled,omitempty"`
+	Push              bool               `yaml:"push,omitempty" bson:"push,omitempty"`
+	BatchTime         *int               `yaml:"batchtime,omitempty" bson:"batchtime,omitempty"`
+	CronBatchTime     string             `yaml:"cron,omitempty" bson:"cron,omitempty"`
+	CronTimezone      string             `yaml:"timezone,omitempty" bson:"timezone,omitempty"`
+	Stepback          *bool              `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
+	RunOn             parserStringSlice  `yaml:"run_on,omitempty" bson:"run_on,omitempty"`
+	RunOnStrategy     string             `yaml:"run_on_strategy,omitempty" bson:"run_on_strategy,omitempty"`
+	ArtifactNamespace string             `yaml:"artifact_namespace,omitempty" bson:"artifact_namespace,omitempty"`
+	Tasks             parserBVTaskUnits  `yaml:"tasks,omitempty" bson:"tasks,omitempty"`
+	DisplayTasks      []displayTask      `yaml:"display_tasks,omitempty" bson:"display_tasks,omitempty"`
+	DependsOn         parserDependencies `yaml:"depends_on,omitempty" bson:"depends_on,omitempty"`
+	ExternalGates     parserStringSlice  `yaml:"external_gates,omitempty" bson:"external_gates,omitempty"`
 	// If Activate is set to false, then we don't initially activate the build variant.
-	Activate *bool `yaml:"activate,omitempty" bson:"activate,omitempty"`
+	Activate *bool             `yaml:"activate,omitempty" bson:"activate,omitempty"`
+	Canary   *CanaryActivation `yaml:"canary,omitempty" bson:"canary,omitempty"`
 
 	// internal matrix stuff
 	MatrixId  string      `yaml:"matrix_id,omitempty" bson:"matrix_id,omitempty"`
@@ -374,10 +413,15 @@ func (pbv *parserBV) canMerge() bool {
 		!pbv.Push &&
 		pbv.BatchTime == nil &&
 		pbv.CronBatchTime == "" &&
+		pbv.CronTimezone == "" &&
 		pbv.Stepback == nil &&
 		pbv.RunOn == nil &&
+		pbv.RunOnStrategy == "" &&
+		pbv.ArtifactNamespace == "" &&
 		pbv.DependsOn == nil &&
+		pbv.ExternalGates == nil &&
 		pbv.Activate == nil &&
+		pbv.Canary == nil &&
 		pbv.MatrixId == "" &&
 		pbv.MatrixVal == nil &&
 		pbv.Matrix == nil &&
@@ -398,6 +442,7 @@ type parserBVTaskUnit struct {
 	Stepback         *bool              `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
 	Distros          parserStringSlice  `yaml:"distros,omitempty" bson:"distros,omitempty"`
 	RunOn            parserStringSlice  `yaml:"run_on,omitempty" bson:"run_on,omitempty"` // Alias for "Distros" TODO: deprecate Distros
+	RunOnStrategy    string             `yaml:"run_on_strategy,omitempty" bson:"run_on_strategy,omitempty"`
 	CommitQueueMerge bool               `yaml:"commit_queue_merge,omitempty" bson:"commit_queue_merge,omitempty"`
 	// Use a *int for 2 possible states
 	// nil - not overriding the project setting
@@ -406,8 +451,16 @@ type parserBVTaskUnit struct {
 	// If CronBatchTime is not empty, then override the project settings with cron syntax,
 	// with BatchTime and CronBatchTime being mutually exclusive.
 	CronBatchTime string `yaml:"cron,omitempty" bson:"cron,omitempty"`
+	// CronTimezone is the IANA time zone in which CronBatchTime is evaluated.
+	CronTimezone string `yaml:"timezone,omitempty" bson:"timezone,omitempty"`
 	// If Activate is set to false, then we don't initially activate the task.
 	Activate *bool `yaml:"activate,omitempty" bson:"activate,omitempty"`
+	// ContainerFallback is the distro to run on if the task runs in a
+	// container but cannot be allocated one in time.
+	ContainerFallback *ContainerFallback `yaml:"container_fallback,omitempty" bson:"container_fallback,omitempty"`
+	// AllowedRequesters, if set, are the only requesters that the task runs
+	// for.
+	AllowedRequesters []evergreen.UserRequester `yaml:"allowed_requesters,omitempty" bson:"allowed_requesters,omitempty"`
 }
 
 // UnmarshalYAML allows the YAML parser to read both a single selector string or
@@ -549,6 +602,67 @@ func LoadProjectForVersion(v *Version, id string, shouldSave bool) (ProjectInfo,
 	}, nil
 }
 
+// ProjectProjection describes which parts of a project config a caller needs,
+// so that callers that only need some of the config don't have to load and
+// translate all of it.
+type ProjectProjection string
+
+const (
+	// ProjectProjectionFull loads the entire project.
+	ProjectProjectionFull ProjectProjection = "full"
+	// ProjectProjectionTasks loads only the project's tasks. Task
+	// dependencies are not evaluated, since they can refer to build variants.
+	ProjectProjectionTasks ProjectProjection = "tasks"
+	// ProjectProjectionVariants loads the project's build variants along with
+	// the tasks and task groups that they refer to.
+	ProjectProjectionVariants ProjectProjection = "variants"
+	// ProjectProjectionModules loads only the project's modules.
+	ProjectProjectionModules ProjectProjection = "modules"
+)
+
+// parserProjectFields returns the parser project fields to load for the
+// projection, or nil if the whole parser project is needed.
+func (p ProjectProjection) parserProjectFields() []string {
+	switch p {
+	case ProjectProjectionTasks:
+		return []string{ParserProjectTasksKey}
+	case ProjectProjectionVariants:
+		return []string{ParserProjectBuildVariantsKey, ParserProjectAxesKey, ParserProjectTasksKey, ParserProjectTaskGroupsKey, ParserProjectContainersKey}
+	case ProjectProjectionModules:
+		return []string{ParserProjectModulesKey}
+	default:
+		return nil
+	}
+}
+
+// LoadProjectProjectionForVersion returns the parts of the version's project
+// that are needed for the projection. Versions whose parser project is not
+// stored fall back to loading the full project.
+func LoadProjectProjectionForVersion(v *Version, id string, projection ProjectProjection) (*Project, error) {
+	fields := projection.parserProjectFields()
+	if len(fields) == 0 {
+		projectInfo, err := LoadProjectForVersion(v, id, true)
+		return projectInfo.Project, err
+	}
+
+	pp, err := ParserProjectFindOne(ParserProjectById(v.Id).WithFields(append(fields, ParserProjectConfigNumberKey)...))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding parser project")
+	}
+	if pp == nil || pp.ConfigUpdateNumber < v.ConfigUpdateNumber {
+		projectInfo, err := LoadProjectForVersion(v, id, true)
+		return projectInfo.Project, err
+	}
+
+	pp.Identifier = utility.ToStringPtr(id)
+	if projection == ProjectProjectionTasks {
+		for i := range pp.Tasks {
+			pp.Tasks[i].DependsOn = nil
+		}
+	}
+	return TranslateProject(pp)
+}
+
 func GetProjectFromBSON(data []byte) (*Project, error) {
 	pp := &ParserProject{}
 	if err := bson.Unmarshal(data, pp); err != nil {
@@ -562,14 +676,23 @@ func GetProjectFromBSON(data []byte) (*Project, error) {
 // If reading from a version config, LoadProjectForVersion should be used to persist the resulting parser project.
 // opts is used to look up files on github if the main parser project has an Include.
 func LoadProjectInto(ctx context.Context, data []byte, opts *GetProjectOpts, identifier string, project *Project) (*ParserProject, error) {
+	ctx, span := tracer.Start(ctx, "LoadProjectInto", trace.WithAttributes(
+		attribute.String(evergreen.ProjectIdentifierOtelAttribute, identifier),
+		attribute.Int(evergreen.ProjectConfigSizeOtelAttribute, len(data)),
+	))
+	defer span.End()
+
 	unmarshalStrict := false
 	if opts != nil {
 		unmarshalStrict = opts.UnmarshalStrict
 	}
+	_, parseSpan := tracer.Start(ctx, "parse")
 	intermediateProject, err := createIntermediateProject(data, unmarshalStrict)
+	parseSpan.End()
 	if err != nil {
 		return nil, errors.Wrapf(err, LoadProjectError)
 	}
+	span.SetAttributes(attribute.Int(evergreen.ProjectNumIncludesOtelAttribute, len(intermediateProject.Include)))
 
 	// return intermediateProject even if we run into issues to show merge progress
 	for _, path := range intermediateProject.Include {
@@ -588,19 +711,26 @@ func LoadProjectInto(ctx context.Context, data []byte, opts *GetProjectOpts, ide
 			"read_from":   opts.ReadFileFrom,
 			"module":      path.Module,
 		})
+		includeCtx, includeSpan := tracer.Start(ctx, "parse include", trace.WithAttributes(
+			attribute.String(evergreen.ProjectIncludeOtelAttribute, path.FileName),
+		))
 		if path.Module != "" {
-			yaml, err = retrieveFileForModule(ctx, *opts, intermediateProject.Modules, path.Module)
+			yaml, err = retrieveFileForModule(includeCtx, *opts, intermediateProject.Modules, path.Module)
 		} else {
-			yaml, err = retrieveFile(ctx, *opts)
+			yaml, err = retrieveFile(includeCtx, *opts)
 		}
 		if err != nil {
+			includeSpan.End()
 			return intermediateProject, errors.Wrapf(err, "%s: retrieving file '%s'", LoadProjectError, path.FileName)
 		}
+		includeSpan.SetAttributes(attribute.Int(evergreen.ProjectConfigSizeOtelAttribute, len(yaml)))
 		add, err := createIntermediateProject(yaml, opts.UnmarshalStrict)
 		if err != nil {
+			includeSpan.End()
 			return intermediateProject, errors.Wrapf(err, "%s: loading file '%s'", LoadProjectError, path.FileName)
 		}
 		err = intermediateProject.mergeMultipleParserProjects(add)
+		includeSpan.End()
 		if err != nil {
 			return intermediateProject, errors.Wrapf(err, "%s: merging file '%s'", LoadProjectError, path.FileName)
 		}
@@ -608,9 +738,15 @@ func LoadProjectInto(ctx context.Context, data []byte, opts *GetProjectOpts, ide
 	intermediateProject.Include = nil
 
 	// return project even with errors
+	_, expandSpan := tracer.Start(ctx, "expand")
 	p, err := TranslateProject(intermediateProject)
+	expandSpan.End()
 	if p != nil {
 		*project = *p
+		span.SetAttributes(
+			attribute.Int(evergreen.ProjectNumTasksOtelAttribute, len(p.Tasks)),
+			attribute.Int(evergreen.ProjectNumBuildVariantsOtelAttribute, len(p.BuildVariants)),
+		)
 	}
 	project.Identifier = identifier
 	return intermediateProject, errors.Wrapf(err, LoadProjectError)
@@ -807,11 +943,20 @@ func createIntermediateProject(yml []byte, unmarshalStrict bool) (*ParserProject
 			return nil, err
 		}
 		p = strictProjectWithVariables.ParserProject
+		p.ValidationSuppressions = strictProjectWithVariables.ProjectConfigFields.ValidationSuppressions
 	} else {
-		if err := util.UnmarshalYAMLWithFallback(yml, &p); err != nil {
+		// The validation suppressions are the only project config field
+		// that the parser project needs.
+		projectWithSuppressions := struct {
+			ParserProject          `yaml:"pp,inline"`
+			ValidationSuppressions []ValidationSuppression `yaml:"validation_suppressions,omitempty"`
+		}{}
+		if err := util.UnmarshalYAMLWithFallback(yml, &projectWithSuppressions); err != nil {
 			yamlErr := thirdparty.YAMLFormatError{Message: err.Error()}
 			return nil, errors.Wrap(yamlErr, "unmarshalling parser project from YAML")
 		}
+		p = projectWithSuppressions.ParserProject
+		p.ValidationSuppressions = projectWithSuppressions.ValidationSuppressions
 	}
 
 	if p.Functions == nil {
@@ -851,6 +996,8 @@ func TranslateProject(pp *ParserProject) (*Project, error) {
 		Functions:          pp.Functions,
 		ExecTimeoutSecs:    utility.FromIntPtr(pp.ExecTimeoutSecs),
 		Loggers:            pp.Loggers,
+		ExternalGates:      pp.ExternalGates,
+		VersionGates:       pp.VersionGates,
 	}
 	catcher := grip.NewBasicCatcher()
 	tse := NewParserTaskSelectorEvaluator(pp.Tasks)
@@ -864,6 +1011,11 @@ func TranslateProject(pp *ParserProject) (*Project, error) {
 
 	proj.BuildVariants, errs = evaluateBuildVariants(tse, tgse, vse, buildVariants, pp.Tasks, proj.TaskGroups)
 	catcher.Extend(errs)
+	proj.Stages, errs = evaluateStages(tse, tgse, vse, pp.Stages, proj)
+	catcher.Extend(errs)
+	addStageDependencies(proj)
+	expandTaskGroupDependencies(proj)
+	proj.ValidationSuppressions = pp.ValidationSuppressions
 	return proj, errors.Wrap(catcher.Resolve(), TranslateProjectError)
 }
 
@@ -926,6 +1078,10 @@ func evaluateTaskUnits(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluator, v
 			Stepback:        pt.Stepback,
 			MustHaveResults: pt.MustHaveResults,
 		}
+		t.AllowedRequesters = pt.AllowedRequesters
+		t.Outputs = pt.Outputs
+		t.Compliance = pt.Compliance
+		t.ExpectedArtifacts = pt.ExpectedArtifacts
 		if strings.Contains(strings.TrimSpace(pt.Name), " ") {
 			evalErrs = append(evalErrs, errors.Errorf("spaces are not allowed in task names ('%s')", pt.Name))
 		}
@@ -974,18 +1130,23 @@ func evaluateBuildVariants(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluato
 	var evalErrs, errs []error
 	for _, pbv := range pbvs {
 		bv := BuildVariant{
-			DisplayName:   pbv.DisplayName,
-			Name:          pbv.Name,
-			Expansions:    pbv.Expansions,
-			Modules:       pbv.Modules,
-			Disabled:      pbv.Disabled,
-			Push:          pbv.Push,
-			BatchTime:     pbv.BatchTime,
-			CronBatchTime: pbv.CronBatchTime,
-			Activate:      pbv.Activate,
-			Stepback:      pbv.Stepback,
-			RunOn:         pbv.RunOn,
-			Tags:          pbv.Tags,
+			DisplayName:       pbv.DisplayName,
+			Name:              pbv.Name,
+			Expansions:        pbv.Expansions,
+			Modules:           pbv.Modules,
+			Disabled:          pbv.Disabled,
+			Push:              pbv.Push,
+			BatchTime:         pbv.BatchTime,
+			CronBatchTime:     pbv.CronBatchTime,
+			CronTimezone:      pbv.CronTimezone,
+			Activate:          pbv.Activate,
+			Canary:            pbv.Canary,
+			Stepback:          pbv.Stepback,
+			RunOn:             pbv.RunOn,
+			RunOnStrategy:     pbv.RunOnStrategy,
+			ArtifactNamespace: pbv.ArtifactNamespace,
+			Tags:              pbv.Tags,
+			ExternalGates:     pbv.ExternalGates,
 		}
 		bv.Tasks, errs = evaluateBVTasks(tse, tgse, vse, pbv, tasks)
 
@@ -1059,11 +1220,15 @@ func evaluateBuildVariants(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluato
 
 		// save display task if it contains valid execution tasks
 		for _, dt := range pbv.DisplayTasks {
-			projectDt := patch.DisplayTask{Name: dt.Name}
+			projectDt := patch.DisplayTask{Name: dt.Name, DispatchOrder: dt.DispatchOrder}
 			if _, exists := bvTasks[dt.Name]; exists {
 				errs = append(errs, errors.Errorf("display task '%s' cannot have the same name as an execution task", dt.Name))
 				continue
 			}
+			if !utility.StringSliceContains(patch.ValidDisplayTaskDispatchOrders, dt.DispatchOrder) {
+				errs = append(errs, errors.Errorf("display task '%s' has invalid dispatch order '%s'", dt.Name, dt.DispatchOrder))
+				continue
+			}
 
 			//resolve tags for display tasks
 			tasks := []string{}
@@ -1186,11 +1351,18 @@ func getParserBuildVariantTaskUnit(name string, pt parserTask, bvt parserBVTaskU
 		ExecTimeoutSecs:  bvt.ExecTimeoutSecs,
 		Stepback:         bvt.Stepback,
 		RunOn:            bvt.RunOn,
+		RunOnStrategy:    bvt.RunOnStrategy,
 		CommitQueueMerge: bvt.CommitQueueMerge,
 		CronBatchTime:    bvt.CronBatchTime,
+		CronTimezone:     bvt.CronTimezone,
 		BatchTime:        bvt.BatchTime,
 		Activate:         bvt.Activate,
 	}
+	res.ContainerFallback = bvt.ContainerFallback
+	res.AllowedRequesters = bvt.AllowedRequesters
+	if len(res.AllowedRequesters) == 0 {
+		res.AllowedRequesters = pt.AllowedRequesters
+	}
 	if res.Priority == 0 {
 		res.Priority = pt.Priority
 	}
@@ -1225,6 +1397,78 @@ func getParserBuildVariantTaskUnit(name string, pt parserTask, bvt parserBVTaskU
 	return res
 }
 
+// expandTaskGroupDependencies replaces each dependency on a task group with
+// dependencies on all of the tasks in the group, so that depending on a task
+// group waits for the whole group to finish. A task in the group does not
+// depend on itself.
+func expandTaskGroupDependencies(proj *Project) {
+	if len(proj.TaskGroups) == 0 {
+		return
+	}
+	groupTasks := map[string][]string{}
+	for _, tg := range proj.TaskGroups {
+		if _, ok := groupTasks[tg.Name]; !ok {
+			groupTasks[tg.Name] = tg.Tasks
+		}
+	}
+
+	for i, t := range proj.Tasks {
+		proj.Tasks[i].DependsOn = expandTaskGroupDependsOn(t.Name, t.DependsOn, groupTasks)
+	}
+	for i, bv := range proj.BuildVariants {
+		for j, bvt := range bv.Tasks {
+			proj.BuildVariants[i].Tasks[j].DependsOn = expandTaskGroupDependsOn(bvt.Name, bvt.DependsOn, groupTasks)
+		}
+	}
+}
+
+func expandTaskGroupDependsOn(dependent string, deps []TaskUnitDependency, groupTasks map[string][]string) []TaskUnitDependency {
+	hasGroupDep := false
+	for _, d := range deps {
+		if _, ok := groupTasks[d.Name]; ok {
+			hasGroupDep = true
+			break
+		}
+	}
+	if !hasGroupDep {
+		return deps
+	}
+
+	expanded := make([]TaskUnitDependency, 0, len(deps))
+	seen := map[TVPair]bool{}
+	add := func(d TaskUnitDependency) {
+		pair := TVPair{Variant: d.Variant, TaskName: d.Name}
+		if seen[pair] {
+			return
+		}
+		seen[pair] = true
+		expanded = append(expanded, d)
+	}
+	// Dependencies on tasks that are listed explicitly take precedence over
+	// the same tasks in a depended on group.
+	for _, d := range deps {
+		if _, ok := groupTasks[d.Name]; !ok {
+			add(d)
+		}
+	}
+	for _, d := range deps {
+		tasks, ok := groupTasks[d.Name]
+		if !ok {
+			continue
+		}
+		for _, name := range tasks {
+			if name == dependent {
+				continue
+			}
+			member := d
+			member.Name = name
+			add(member)
+		}
+	}
+
+	return expanded
+}
+
 // evaluateDependsOn expands any selectors in a dependency definition.
 func evaluateDependsOn(tse *tagSelectorEvaluator, tgse *tagSelectorEvaluator, vse *variantSelectorEvaluator,
 	deps []parserDependency) ([]TaskUnitDependency, []error) {
@@ -1280,6 +1524,8 @@ func evaluateDependsOn(tse *tagSelectorEvaluator, tgse *tagSelectorEvaluator, vs
 					Status:        d.Status,
 					PatchOptional: d.PatchOptional,
 				}
+				newDep.OmitGeneratedTasks = d.OmitGeneratedTasks
+				newDep.LatestMainline = d.LatestMainline
 				// add the new dep if it doesn't already exist (we must avoid conflicting status fields)
 				if oldDep, ok := newDepsByNameAndVariant[TVPair{newDep.Variant, newDep.Name}]; !ok {
 					newDeps = append(newDeps, newDep)
@@ -81,6 +81,26 @@ func ParserProjectUpsertOne(query interface{}, update interface{}) error {
 	return err
 }
 
+// FindParserProjectForVersion returns the version's parser project. If the
+// version has no up-to-date parser project, it's parsed from the version's
+// legacy config.
+func FindParserProjectForVersion(v *Version) (*ParserProject, error) {
+	pp, err := ParserProjectFindOneById(v.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding parser project")
+	}
+	if pp == nil || pp.ConfigUpdateNumber < v.ConfigUpdateNumber { // legacy case
+		pp = &ParserProject{}
+		if err = util.UnmarshalYAMLWithFallback([]byte(v.Config), pp); err != nil {
+			return nil, errors.Wrap(err, "parsing legacy config")
+		}
+	}
+	if pp.Functions == nil {
+		pp.Functions = map[string]*YAMLCommandSet{}
+	}
+	return pp, nil
+}
+
 func FindParametersForVersion(v *Version) ([]patch.Parameter, error) {
 	pp, err := ParserProjectFindOne(ParserProjectById(v.Id).WithFields(ParserProjectConfigNumberKey,
 		ParserProjectParametersKey))
@@ -124,7 +144,7 @@ func FindExpansionsForVariant(v *Version, variant string) (util.Expansions, erro
 	}
 	for _, bv := range bvs {
 		if bv.Name == variant {
-			return bv.Expansions, nil
+			return variantExpansions(bv.Name, bv.ArtifactNamespace, bv.Expansions), nil
 		}
 	}
 	return nil, errors.New("could not find variant")
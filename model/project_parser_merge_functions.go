@@ -12,7 +12,7 @@ const MergeProjectConfigError = "error merging project configs"
 
 // mergeUnorderedUnique merges fields that are lists where the order doesn't matter.
 // These fields can be defined throughout multiple yamls but cannot contain duplicate keys.
-// These fields are: [task, task group, parameter, module, function, container]
+// These fields are: [task, task group, parameter, module, function, container, external gate]
 func (pp *ParserProject) mergeUnorderedUnique(toMerge *ParserProject) error {
 	catcher := grip.NewBasicCatcher()
 
@@ -81,6 +81,19 @@ func (pp *ParserProject) mergeUnorderedUnique(toMerge *ParserProject) error {
 		containerExist[container.Name] = true
 	}
 
+	externalGateExist := map[string]bool{}
+	for _, gate := range pp.ExternalGates {
+		externalGateExist[gate.Name] = true
+	}
+	for _, gate := range toMerge.ExternalGates {
+		if _, ok := externalGateExist[gate.Name]; ok {
+			catcher.Errorf("external gate '%s' has been declared already", gate.Name)
+			continue
+		}
+		pp.ExternalGates = append(pp.ExternalGates, gate)
+		externalGateExist[gate.Name] = true
+	}
+
 	for key, val := range toMerge.Functions {
 		if _, ok := pp.Functions[key]; ok {
 			catcher.Errorf("function '%s' has been declared already", key)
@@ -94,15 +107,17 @@ func (pp *ParserProject) mergeUnorderedUnique(toMerge *ParserProject) error {
 
 // mergeUnordered merges fields that are lists where the order doesn't matter.
 // These fields can only be defined in one yaml and does not consider naming conflicts.
-// These fields include: [ignore, loggers]
+// These fields include: [ignore, loggers, version gates, validation suppressions]
 func (pp *ParserProject) mergeUnordered(toMerge *ParserProject) {
 	pp.Ignore = append(pp.Ignore, toMerge.Ignore...)
+	pp.VersionGates = append(pp.VersionGates, toMerge.VersionGates...)
+	pp.ValidationSuppressions = append(pp.ValidationSuppressions, toMerge.ValidationSuppressions...)
 	pp.Loggers = mergeAllLogs(pp.Loggers, toMerge.Loggers)
 }
 
 // mergeOrderedUnique merges fields that are lists where the order does matter.
 // These fields can only be defined in one yaml.
-// These fields are: [pre, post, timeout, early termination]
+// These fields are: [pre, post, timeout, early termination, stages]
 func (pp *ParserProject) mergeOrderedUnique(toMerge *ParserProject) error {
 	catcher := grip.NewBasicCatcher()
 
@@ -130,6 +145,12 @@ func (pp *ParserProject) mergeOrderedUnique(toMerge *ParserProject) error {
 		pp.EarlyTermination = toMerge.EarlyTermination
 	}
 
+	if pp.Stages != nil && toMerge.Stages != nil {
+		catcher.New("stages can only be defined in one YAML")
+	} else if toMerge.Stages != nil {
+		pp.Stages = toMerge.Stages
+	}
+
 	return catcher.Resolve()
 }
 
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/testutil"
@@ -1174,6 +1175,85 @@ buildvariants:
 	assert.Equal("task_2", proj.BuildVariants[0].DisplayTasks[0].ExecTasks[1])
 }
 
+func TestTaskExpectedArtifacts(t *testing.T) {
+	yml := `
+tasks:
+- name: compile
+  expected_artifacts:
+  - binaries
+  - "*.tgz"
+- name: package
+  expected_artifacts: dist.zip
+- name: test
+buildvariants:
+- name: "bv"
+  tasks:
+  - name: compile
+  - name: package
+  - name: test
+`
+	proj := &Project{}
+	_, err := LoadProjectInto(context.Background(), []byte(yml), nil, "id", proj)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"binaries", "*.tgz"}, proj.FindProjectTask("compile").ExpectedArtifacts)
+	assert.Equal(t, []string{"dist.zip"}, proj.FindProjectTask("package").ExpectedArtifacts)
+	assert.Empty(t, proj.FindProjectTask("test").ExpectedArtifacts)
+}
+
+func TestTaskGroupDependency(t *testing.T) {
+	validYml := `
+tasks:
+- name: task_1
+- name: task_2
+- name: task_3
+  depends_on:
+  - name: task_group_1
+    status: failed
+  - name: task_2
+task_groups:
+- name: task_group_1
+  tasks:
+  - task_1
+  - task_2
+buildvariants:
+- name: "bv"
+  tasks:
+  - name: task_group_1
+  - name: task_3
+- name: "bv2"
+  tasks:
+  - name: task_3
+    depends_on:
+    - name: task_group_1
+      variant: bv
+`
+	proj := &Project{}
+	_, err := LoadProjectInto(context.Background(), []byte(validYml), nil, "id", proj)
+	require.NoError(t, err)
+
+	task3 := proj.FindProjectTask("task_3")
+	require.NotNil(t, task3)
+	assert.Equal(t, []TaskUnitDependency{
+		{Name: "task_2"},
+		{Name: "task_1", Status: evergreen.TaskFailed},
+	}, task3.DependsOn)
+
+	bvt := proj.FindTaskForVariant("task_3", "bv2")
+	require.NotNil(t, bvt)
+	assert.Equal(t, []TaskUnitDependency{
+		{Name: "task_1", Variant: "bv"},
+		{Name: "task_2", Variant: "bv"},
+	}, bvt.DependsOn)
+
+	t.Run("TaskInGroupDoesNotDependOnItself", func(t *testing.T) {
+		deps := expandTaskGroupDependsOn("task_1", []TaskUnitDependency{{Name: "task_group_1"}}, map[string][]string{
+			"task_group_1": {"task_1", "task_2"},
+		})
+		assert.Equal(t, []TaskUnitDependency{{Name: "task_2"}}, deps)
+	})
+}
+
 func TestTaskGroupWithDisplayTaskWithDisplayTaskTag(t *testing.T) {
 	assert := assert.New(t)
 	validYml := `
@@ -2456,3 +2536,78 @@ func TestUpdateForFile(t *testing.T) {
 	// should be changed to patch diff because it's not a modified file
 
 }
+
+func TestLoadProjectProjectionForVersion(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ParserProjectCollection, ProjectRefCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ParserProjectCollection, ProjectRefCollection))
+	}()
+	require.NoError(t, (&ProjectRef{Id: "project"}).Insert())
+
+	config := `
+functions:
+  say_hi:
+    command: shell.exec
+modules:
+  - name: enterprise
+    repo: git@github.com:evergreen-ci/enterprise.git
+tasks:
+  - name: compile
+    commands:
+      - func: say_hi
+  - name: test
+    depends_on:
+      - name: compile
+        variant: ubuntu
+buildvariants:
+  - name: ubuntu
+    run_on: ubuntu
+    tasks:
+      - name: "*"
+`
+	pp, err := createIntermediateProject([]byte(config), false)
+	require.NoError(t, err)
+	pp.Id = "v1"
+	require.NoError(t, pp.Insert())
+	v := &Version{Id: "v1", Config: config}
+
+	t.Run("Tasks", func(t *testing.T) {
+		p, err := LoadProjectProjectionForVersion(v, "project", ProjectProjectionTasks)
+		require.NoError(t, err)
+		assert.Equal(t, "project", p.Identifier)
+		require.Len(t, p.Tasks, 2)
+		assert.Empty(t, p.Tasks[1].DependsOn)
+		assert.Empty(t, p.BuildVariants)
+		assert.Empty(t, p.Functions)
+	})
+	t.Run("Variants", func(t *testing.T) {
+		p, err := LoadProjectProjectionForVersion(v, "project", ProjectProjectionVariants)
+		require.NoError(t, err)
+		require.Len(t, p.BuildVariants, 1)
+		assert.Len(t, p.BuildVariants[0].Tasks, 2)
+		assert.Empty(t, p.Modules)
+		assert.Empty(t, p.Functions)
+	})
+	t.Run("Modules", func(t *testing.T) {
+		p, err := LoadProjectProjectionForVersion(v, "project", ProjectProjectionModules)
+		require.NoError(t, err)
+		require.Len(t, p.Modules, 1)
+		assert.Equal(t, "enterprise", p.Modules[0].Name)
+		assert.Empty(t, p.Tasks)
+	})
+	t.Run("Full", func(t *testing.T) {
+		p, err := LoadProjectProjectionForVersion(v, "project", ProjectProjectionFull)
+		require.NoError(t, err)
+		assert.Len(t, p.Tasks, 2)
+		assert.Len(t, p.BuildVariants, 1)
+		assert.Len(t, p.Modules, 1)
+		assert.Len(t, p.Functions, 1)
+	})
+	t.Run("LegacyVersionLoadsFullProject", func(t *testing.T) {
+		legacy := &Version{Id: "legacy", Config: config}
+		p, err := LoadProjectProjectionForVersion(legacy, "project", ProjectProjectionTasks)
+		require.NoError(t, err)
+		assert.Len(t, p.Tasks, 2)
+		assert.Len(t, p.BuildVariants, 1)
+	})
+}
@@ -0,0 +1,196 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ProjectQuotas limit how much work a project can have in flight at once so
+// that a single project cannot overwhelm shared infrastructure. A limit of 0
+// is not enforced.
+type ProjectQuotas struct {
+	// MaxUnfinishedVersions is the most mainline versions that can be active
+	// and unfinished at the same time.
+	MaxUnfinishedVersions int `bson:"max_unfinished_versions,omitempty" json:"max_unfinished_versions,omitempty" yaml:"max_unfinished_versions,omitempty"`
+	// MaxScheduledTasks is the most tasks that can be activated and not yet
+	// finished at the same time.
+	MaxScheduledTasks int `bson:"max_scheduled_tasks,omitempty" json:"max_scheduled_tasks,omitempty" yaml:"max_scheduled_tasks,omitempty"`
+	// OverrideUntil suspends enforcement of the quotas until the given time,
+	// without discarding the configured limits.
+	OverrideUntil time.Time `bson:"override_until,omitempty" json:"override_until,omitempty" yaml:"override_until,omitempty"`
+}
+
+// IsOverridden returns whether quota enforcement is suspended at the given
+// time.
+func (q ProjectQuotas) IsOverridden(now time.Time) bool {
+	return now.Before(q.OverrideUntil)
+}
+
+// Validate checks that the quota limits are sensible.
+func (q ProjectQuotas) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(q.MaxUnfinishedVersions < 0, "max unfinished versions cannot be negative")
+	catcher.NewWhen(q.MaxScheduledTasks < 0, "max scheduled tasks cannot be negative")
+	return catcher.Resolve()
+}
+
+// ProjectQuotaExceededError is returned when an operation would put a project
+// over one of its quotas.
+type ProjectQuotaExceededError struct {
+	ProjectID string
+	Quota     string
+	Limit     int
+	Current   int
+	Requested int
+}
+
+func (e *ProjectQuotaExceededError) Error() string {
+	msg := fmt.Sprintf("project '%s' has %d %s, which would exceed its quota of %d", e.ProjectID, e.Current, e.Quota, e.Limit)
+	if e.Requested > 0 {
+		msg = fmt.Sprintf("project '%s' has %d %s and %d more were requested, which would exceed its quota of %d", e.ProjectID, e.Current, e.Quota, e.Requested, e.Limit)
+	}
+	return msg + "; a project admin can raise the quota or temporarily override it in the project settings"
+}
+
+// IsProjectQuotaExceeded returns whether the error is due to a project quota.
+func IsProjectQuotaExceeded(err error) bool {
+	_, ok := errors.Cause(err).(*ProjectQuotaExceededError)
+	return ok
+}
+
+// CheckVersionActivationQuotas returns an error if activating the version,
+// which schedules the given number of its tasks, would put its project over
+// its quotas.
+func CheckVersionActivationQuotas(projectRef *ProjectRef, v *Version, numTasks int) error {
+	if err := checkUnfinishedVersionQuota(projectRef, v); err != nil {
+		return err
+	}
+	if numTasks == 0 {
+		return nil
+	}
+	return CheckScheduledTaskQuota(projectRef, numTasks)
+}
+
+// CountTasksToActivateForVersion returns the number of tasks that activating
+// the version would schedule.
+func CountTasksToActivateForVersion(versionId, caller string) (int, error) {
+	affected, err := setVersionActivation(versionId, true, true, caller)
+	if err != nil {
+		return 0, errors.Wrapf(err, "finding tasks to activate in version '%s'", versionId)
+	}
+	if len(affected.TaskIDs) == 0 {
+		return 0, nil
+	}
+	numTasks, err := task.Count(db.Query(bson.M{
+		task.IdKey:        bson.M{"$in": affected.TaskIDs},
+		task.ActivatedKey: false,
+	}))
+	return numTasks, errors.Wrapf(err, "counting tasks to activate in version '%s'", versionId)
+}
+
+// CheckTaskActivationQuotas returns an error if activating the tasks, along
+// with the dependencies that are activated with them, would put any of their
+// projects over its scheduled task quota.
+func CheckTaskActivationQuotas(caller string, tasks []task.Task) error {
+	affected, err := SetTasksActiveState(caller, true, true, tasks)
+	if err != nil {
+		return errors.Wrap(err, "finding tasks to activate")
+	}
+	if len(affected.TaskIDs) == 0 {
+		return nil
+	}
+	toActivate, err := task.FindAll(db.Query(bson.M{
+		task.IdKey:        bson.M{"$in": affected.TaskIDs},
+		task.ActivatedKey: false,
+	}).WithFields(task.IdKey, task.ProjectKey))
+	if err != nil {
+		return errors.Wrap(err, "finding tasks to activate")
+	}
+
+	numTasksByProject := map[string]int{}
+	for _, t := range toActivate {
+		numTasksByProject[t.Project]++
+	}
+	for projectId, numTasks := range numTasksByProject {
+		projectRef, err := FindMergedProjectRef(projectId, "", false)
+		if err != nil {
+			return errors.Wrapf(err, "finding project '%s'", projectId)
+		}
+		if projectRef == nil {
+			continue
+		}
+		if err = CheckScheduledTaskQuota(projectRef, numTasks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkUnfinishedVersionQuota(projectRef *ProjectRef, v *Version) error {
+	quotas := projectRef.Quotas
+	if quotas.MaxUnfinishedVersions == 0 || quotas.IsOverridden(time.Now()) {
+		return nil
+	}
+	// Only mainline versions that are not already counted against the quota
+	// need to be checked.
+	if !utility.StringSliceContains(evergreen.SystemVersionRequesterTypes, v.Requester) || utility.FromBoolPtr(v.Activated) {
+		return nil
+	}
+
+	numUnfinished, err := VersionCount(db.Query(bson.M{
+		VersionIdentifierKey: projectRef.Id,
+		VersionRequesterKey:  bson.M{"$in": evergreen.SystemVersionRequesterTypes},
+		VersionActivatedKey:  true,
+		VersionStatusKey:     bson.M{"$in": []string{evergreen.VersionCreated, evergreen.VersionStarted}},
+	}))
+	if err != nil {
+		return errors.Wrapf(err, "counting unfinished versions for project '%s'", projectRef.Id)
+	}
+	if numUnfinished >= quotas.MaxUnfinishedVersions {
+		return &ProjectQuotaExceededError{
+			ProjectID: projectRef.Id,
+			Quota:     "unfinished mainline versions",
+			Limit:     quotas.MaxUnfinishedVersions,
+			Current:   numUnfinished,
+		}
+	}
+	return nil
+}
+
+// CheckScheduledTaskQuota returns an error if scheduling the requested number
+// of additional tasks would put the project over its scheduled task quota.
+// If no additional tasks are requested, it returns an error if the project is
+// already at its quota.
+func CheckScheduledTaskQuota(projectRef *ProjectRef, numRequested int) error {
+	quotas := projectRef.Quotas
+	if quotas.MaxScheduledTasks == 0 || quotas.IsOverridden(time.Now()) {
+		return nil
+	}
+
+	numScheduled, err := task.Count(db.Query(bson.M{
+		task.ProjectKey:   projectRef.Id,
+		task.ActivatedKey: true,
+		task.StatusKey:    bson.M{"$in": evergreen.TaskUncompletedStatuses},
+	}))
+	if err != nil {
+		return errors.Wrapf(err, "counting scheduled tasks for project '%s'", projectRef.Id)
+	}
+	if numScheduled+numRequested > quotas.MaxScheduledTasks || (numRequested == 0 && numScheduled >= quotas.MaxScheduledTasks) {
+		return &ProjectQuotaExceededError{
+			ProjectID: projectRef.Id,
+			Quota:     "scheduled tasks",
+			Limit:     quotas.MaxScheduledTasks,
+			Current:   numScheduled,
+			Requested: numRequested,
+		}
+	}
+	return nil
+}
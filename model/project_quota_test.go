@@ -0,0 +1,133 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectQuotas(t *testing.T) {
+	for tName, tCase := range map[string]func(t *testing.T, pRef *ProjectRef){
+		"NoQuotasAreNotEnforced": func(t *testing.T, pRef *ProjectRef) {
+			pRef.Quotas = ProjectQuotas{}
+			assert.NoError(t, CheckScheduledTaskQuota(pRef, 100))
+			assert.NoError(t, CheckVersionActivationQuotas(pRef, &Version{Id: "v3", Requester: evergreen.RepotrackerVersionRequester}, 100))
+		},
+		"ScheduledTaskQuotaCountsRequestedTasks": func(t *testing.T, pRef *ProjectRef) {
+			assert.NoError(t, CheckScheduledTaskQuota(pRef, 1))
+			err := CheckScheduledTaskQuota(pRef, 2)
+			assert.Error(t, err)
+			assert.True(t, IsProjectQuotaExceeded(err))
+		},
+		"ScheduledTaskQuotaFailsWhenAlreadyAtQuota": func(t *testing.T, pRef *ProjectRef) {
+			pRef.Quotas.MaxScheduledTasks = 2
+			assert.True(t, IsProjectQuotaExceeded(CheckScheduledTaskQuota(pRef, 0)))
+		},
+		"UnfinishedVersionQuotaFailsForNewMainlineVersion": func(t *testing.T, pRef *ProjectRef) {
+			err := CheckVersionActivationQuotas(pRef, &Version{Id: "v3", Requester: evergreen.RepotrackerVersionRequester}, 0)
+			assert.True(t, IsProjectQuotaExceeded(err))
+		},
+		"UnfinishedVersionQuotaIgnoresActivatedAndPatchVersions": func(t *testing.T, pRef *ProjectRef) {
+			assert.NoError(t, CheckVersionActivationQuotas(pRef, &Version{Id: "v1", Requester: evergreen.RepotrackerVersionRequester, Activated: utility.TruePtr()}, 0))
+			assert.NoError(t, CheckVersionActivationQuotas(pRef, &Version{Id: "v3", Requester: evergreen.PatchVersionRequester}, 0))
+		},
+		"VersionActivationCountsRequestedTasks": func(t *testing.T, pRef *ProjectRef) {
+			v := &Version{Id: "v3", Requester: evergreen.PatchVersionRequester}
+			assert.NoError(t, CheckVersionActivationQuotas(pRef, v, 1))
+			assert.True(t, IsProjectQuotaExceeded(CheckVersionActivationQuotas(pRef, v, 2)))
+		},
+		"CountTasksToActivateForVersion": func(t *testing.T, pRef *ProjectRef) {
+			numTasks, err := CountTasksToActivateForVersion("v2", evergreen.DefaultTaskActivator)
+			require.NoError(t, err)
+			assert.Equal(t, 1, numTasks)
+		},
+		"TaskActivationCountsInactiveTasks": func(t *testing.T, pRef *ProjectRef) {
+			require.NoError(t, pRef.Insert())
+			t3, err := task.FindOneId("t3")
+			require.NoError(t, err)
+			require.NotNil(t, t3)
+			assert.NoError(t, CheckTaskActivationQuotas("me", []task.Task{*t3}))
+
+			pRef.Quotas.MaxScheduledTasks = 2
+			require.NoError(t, pRef.Upsert())
+			assert.True(t, IsProjectQuotaExceeded(CheckTaskActivationQuotas("me", []task.Task{*t3})))
+		},
+		"OverrideSuspendsQuotas": func(t *testing.T, pRef *ProjectRef) {
+			pRef.Quotas.OverrideUntil = time.Now().Add(time.Hour)
+			assert.NoError(t, CheckScheduledTaskQuota(pRef, 100))
+			assert.NoError(t, CheckVersionActivationQuotas(pRef, &Version{Id: "v3", Requester: evergreen.RepotrackerVersionRequester}, 100))
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(VersionCollection, ProjectRefCollection, build.Collection, task.Collection))
+			defer func() {
+				assert.NoError(t, db.ClearCollections(VersionCollection, ProjectRefCollection, build.Collection, task.Collection))
+			}()
+
+			versions := []Version{
+				{Id: "v1", Identifier: "p1", Requester: evergreen.RepotrackerVersionRequester, Activated: utility.TruePtr(), Status: evergreen.VersionStarted},
+				{Id: "v2", Identifier: "p1", Requester: evergreen.RepotrackerVersionRequester, Activated: utility.TruePtr(), Status: evergreen.VersionSucceeded},
+			}
+			for _, v := range versions {
+				require.NoError(t, v.Insert())
+			}
+			tasks := []task.Task{
+				{Id: "t1", Project: "p1", Activated: true, Status: evergreen.TaskUndispatched},
+				{Id: "t2", Project: "p1", Activated: true, Status: evergreen.TaskStarted},
+				{Id: "t3", Project: "p1", BuildId: "b2", Version: "v2", Activated: false, Status: evergreen.TaskUndispatched},
+				{Id: "t4", Project: "p1", Activated: true, Status: evergreen.TaskSucceeded},
+			}
+			for _, tsk := range tasks {
+				require.NoError(t, tsk.Insert())
+			}
+			b := build.Build{Id: "b2", Version: "v2"}
+			require.NoError(t, b.Insert())
+
+			pRef := &ProjectRef{
+				Id: "p1",
+				Quotas: ProjectQuotas{
+					MaxUnfinishedVersions: 1,
+					MaxScheduledTasks:     3,
+				},
+			}
+			tCase(t, pRef)
+		})
+	}
+}
+
+func TestDoProjectActivationSkipsProjectOverQuota(t *testing.T) {
+	require.NoError(t, db.ClearCollections(VersionCollection, ProjectRefCollection, task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection, ProjectRefCollection, task.Collection))
+	}()
+
+	pRef := &ProjectRef{
+		Id:      "p1",
+		Enabled: utility.TruePtr(),
+		Quotas:  ProjectQuotas{MaxUnfinishedVersions: 1},
+	}
+	require.NoError(t, pRef.Insert())
+	versions := []Version{
+		{Id: "v1", Identifier: "p1", Requester: evergreen.RepotrackerVersionRequester, Activated: utility.TruePtr(), Status: evergreen.VersionStarted, RevisionOrderNumber: 1, CreateTime: time.Now().Add(-time.Hour)},
+		{Id: "v2", Identifier: "p1", Requester: evergreen.RepotrackerVersionRequester, Status: evergreen.VersionCreated, RevisionOrderNumber: 2, CreateTime: time.Now().Add(-time.Minute)},
+	}
+	for _, v := range versions {
+		require.NoError(t, v.Insert())
+	}
+
+	activated, err := DoProjectActivation("p1", time.Now())
+	assert.NoError(t, err)
+	assert.False(t, activated)
+
+	dbVersion, err := VersionFindOneId("v2")
+	require.NoError(t, err)
+	require.NotNil(t, dbVersion)
+	assert.False(t, utility.FromBoolPtr(dbVersion.Activated))
+}
@@ -66,7 +66,10 @@ type ProjectRef struct {
 	// all aliases defined for the project
 	PatchTriggerAliases []patch.PatchTriggerDefinition `bson:"patch_trigger_aliases" json:"patch_trigger_aliases"`
 	// all PatchTriggerAliases applied to github patch intents
-	GithubTriggerAliases    []string                  `bson:"github_trigger_aliases" json:"github_trigger_aliases"`
+	GithubTriggerAliases []string `bson:"github_trigger_aliases" json:"github_trigger_aliases"`
+	// DownstreamParameterSchema restricts the parameters tasks can pass to child patches.
+	DownstreamParameterSchema patch.DownstreamParameterSchema `bson:"downstream_parameter_schema,omitempty" json:"downstream_parameter_schema,omitempty" yaml:"downstream_parameter_schema,omitempty"`
+
 	PeriodicBuilds          []PeriodicBuildDefinition `bson:"periodic_builds" json:"periodic_builds"`
 	CedarTestResultsEnabled *bool                     `bson:"cedar_test_results_enabled,omitempty" json:"cedar_test_results_enabled,omitempty" yaml:"cedar_test_results_enabled"`
 	CommitQueue             CommitQueueParams         `bson:"commit_queue" json:"commit_queue" yaml:"commit_queue"`
@@ -74,6 +77,9 @@ type ProjectRef struct {
 	// Admins contain a list of users who are able to access the projects page.
 	Admins []string `bson:"admins" json:"admins"`
 
+	// RequireSettingsApproval, if set on a restricted project, requires settings changes to be approved by a second admin.
+	RequireSettingsApproval *bool `bson:"require_settings_approval,omitempty" json:"require_settings_approval,omitempty" yaml:"require_settings_approval"`
+
 	// SpawnHostScriptPath is a path to a script to optionally be run by users on hosts triggered from tasks.
 	SpawnHostScriptPath string `bson:"spawn_host_script_path" json:"spawn_host_script_path" yaml:"spawn_host_script_path"`
 
@@ -84,11 +90,106 @@ type ProjectRef struct {
 	// TaskSync holds settings for synchronizing task directories to S3.
 	TaskSync TaskSyncOptions `bson:"task_sync" json:"task_sync" yaml:"task_sync"`
 
+	// LogRetention determines how long task and test logs are kept.
+	LogRetention LogRetentionPolicy `bson:"log_retention,omitempty" json:"log_retention,omitempty" yaml:"log_retention,omitempty"`
+
+	// ArchiveVersionsAfterDays, if set, is how many days after they are
+	// created finished versions have their builds and tasks moved into cold
+	// storage.
+	ArchiveVersionsAfterDays int `bson:"archive_versions_after_days,omitempty" json:"archive_versions_after_days,omitempty" yaml:"archive_versions_after_days,omitempty"`
+
+	// TaskOrder determines the order in which tasks are displayed in builds,
+	// unless a user overrides it for themself.
+	TaskOrder TaskOrder `bson:"task_order,omitempty" json:"task_order,omitempty" yaml:"task_order,omitempty"`
+
+	// MaxValidationWarnings, if set, is the most project config validation
+	// warnings a mainline version can have before it is reported as failing
+	// the project's warning budget.
+	MaxValidationWarnings *int `bson:"max_validation_warnings,omitempty" json:"max_validation_warnings,omitempty" yaml:"max_validation_warnings,omitempty"`
+
+	// ValidationSeverityOverrides maps the names of project config validation
+	// rules to the severity that their results are reported at, which is
+	// either ValidationSeverityError or ValidationSeverityWarning.
+	ValidationSeverityOverrides map[string]string `bson:"validation_severity_overrides,omitempty" json:"validation_severity_overrides,omitempty" yaml:"validation_severity_overrides,omitempty"`
+
+	// Quotas limit how many versions and tasks the project can have in
+	// flight at once.
+	Quotas ProjectQuotas `bson:"quotas,omitempty" json:"quotas,omitempty" yaml:"quotas,omitempty"`
+
+	// PatchPolicy restricts the patches that can be submitted to the project.
+	PatchPolicy PatchPolicy `bson:"patch_policy,omitempty" json:"patch_policy,omitempty" yaml:"patch_policy,omitempty"`
+
+	// CodeOwnersRouting routes failure notifications and build baron tickets
+	// to the owners of the failing files, according to the repo's CODEOWNERS.
+	CodeOwnersRouting *bool `bson:"code_owners_routing,omitempty" json:"code_owners_routing,omitempty" yaml:"code_owners_routing,omitempty"`
+
+	// AutoBisect replaces stepback for mainline tasks: when a task starts
+	// failing after skipping over commits, the commits in between are bisected
+	// to find the one that broke it.
+	AutoBisect *bool `bson:"auto_bisect,omitempty" json:"auto_bisect,omitempty" yaml:"auto_bisect,omitempty"`
+
+	// EventSourcedStatusRollup computes build and version statuses from
+	// counters that are updated as task statuses change, rather than by
+	// scanning all of a build's tasks.
+	EventSourcedStatusRollup *bool `bson:"event_sourced_status_rollup,omitempty" json:"event_sourced_status_rollup,omitempty" yaml:"event_sourced_status_rollup,omitempty"`
+
+	// FailureLogIndexing saves the last error lines from the logs of the
+	// project's failed tasks so that they can be searched.
+	FailureLogIndexing *bool `bson:"failure_log_indexing,omitempty" json:"failure_log_indexing,omitempty" yaml:"failure_log_indexing,omitempty"`
+
+	// SecretsScanning redacts secrets found in the logs and artifact
+	// metadata uploaded by the project's tasks and records where they were
+	// found.
+	SecretsScanning *bool `bson:"secrets_scanning,omitempty" json:"secrets_scanning,omitempty" yaml:"secrets_scanning,omitempty"`
+
+	// VariantActivationHooks are external services that must allow a
+	// variant to be activated, such as a change management system for
+	// variants that deploy.
+	VariantActivationHooks []VariantActivationHook `bson:"variant_activation_hooks,omitempty" json:"variant_activation_hooks,omitempty" yaml:"variant_activation_hooks,omitempty"`
+	// VariantActivationHookSecret is generated by Evergreen and used to sign
+	// the requests sent to the project's activation hooks.
+	VariantActivationHookSecret string `bson:"variant_activation_hook_secret,omitempty" json:"variant_activation_hook_secret,omitempty" yaml:"variant_activation_hook_secret,omitempty"`
+
+	// PriorityAging increases the priority of the project's tasks the longer
+	// they wait to be dispatched.
+	PriorityAging PriorityAgingSettings `bson:"priority_aging,omitempty" json:"priority_aging,omitempty" yaml:"priority_aging,omitempty"`
+
+	// StuckTaskPolicy is what the stuck task watchdog does with the project's
+	// tasks when their heartbeat goes stale.
+	StuckTaskPolicy string `bson:"stuck_task_policy,omitempty" json:"stuck_task_policy,omitempty" yaml:"stuck_task_policy,omitempty"`
+
+	// WatchedPaths are gitignore-style glob patterns for the parts of the
+	// repository that the project builds. Commits that don't touch a watched
+	// path are handled according to the WatchedPathsPolicy.
+	WatchedPaths       []string `bson:"watched_paths,omitempty" json:"watched_paths,omitempty" yaml:"watched_paths,omitempty"`
+	WatchedPathsPolicy string   `bson:"watched_paths_policy,omitempty" json:"watched_paths_policy,omitempty" yaml:"watched_paths_policy,omitempty"`
+
+	// OverridableExpansions are the names of the expansions that ad hoc
+	// versions and patches may override for all of their tasks when they're
+	// created.
+	OverridableExpansions []string `bson:"overridable_expansions,omitempty" json:"overridable_expansions,omitempty" yaml:"overridable_expansions,omitempty"`
+
+	// GithubVariantChecks posts a GitHub check for each build variant in the
+	// project's mainline versions as the variant's status changes.
+	GithubVariantChecks GithubVariantCheckSettings `bson:"github_variant_checks,omitempty" json:"github_variant_checks,omitempty" yaml:"github_variant_checks,omitempty"`
+
+	// StalePatchPolicy aborts and archives the project's patches that have
+	// gone unfinalized or unfinished for too long.
+	StalePatchPolicy StalePatchPolicy `bson:"stale_patch_policy,omitempty" json:"stale_patch_policy,omitempty" yaml:"stale_patch_policy,omitempty"`
+
+	// PublicStatus allows anyone to read the statuses of the project's
+	// versions, builds, and tasks through the status API without a key.
+	PublicStatus *bool `bson:"public_status,omitempty" json:"public_status,omitempty" yaml:"public_status,omitempty"`
+
 	// GitTagAuthorizedUsers contains a list of users who are able to create versions from git tags.
 	GitTagAuthorizedUsers []string `bson:"git_tag_authorized_users" json:"git_tag_authorized_users"`
 	GitTagAuthorizedTeams []string `bson:"git_tag_authorized_teams" json:"git_tag_authorized_teams"`
 	GitTagVersionsEnabled *bool    `bson:"git_tag_versions_enabled,omitempty" json:"git_tag_versions_enabled,omitempty"`
 
+	// GithubMergeQueueEnabled makes Evergreen a required check for GitHub's
+	// native merge queue, instead of running its own commit queue.
+	GithubMergeQueueEnabled *bool `bson:"github_merge_queue_enabled,omitempty" json:"github_merge_queue_enabled,omitempty"`
+
 	// RepoDetails contain the details of the status of the consistency
 	// between what is in GitHub and what is in Evergreen
 	RepotrackerError *RepositoryErrorDetails `bson:"repotracker_error" json:"repotracker_error"`
@@ -116,6 +217,10 @@ type ProjectRef struct {
 	// The following fields are used by Evergreen and are not discoverable.
 	// Hidden determines whether or not the project is discoverable/tracked in the UI
 	Hidden *bool `bson:"hidden,omitempty" json:"hidden,omitempty"`
+	// Archived determines whether the project has been archived. Archived
+	// projects are disabled and are not listed by default until they are
+	// resurrected.
+	Archived *bool `bson:"archived,omitempty" json:"archived,omitempty"`
 }
 
 type CommitQueueParams struct {
@@ -163,6 +268,11 @@ type ContainerResources struct {
 type ContainerCredential struct {
 	Username string `bson:"username,omitempty" json:"username" yaml:"username"`
 	Password string `bson:"password,omitempty" json:"password" yaml:"password"`
+	// ExternalSecret is the ARN or name of an existing secret in AWS Secrets
+	// Manager that holds the username and password. It is used instead of
+	// the username and password for credentials that are managed and rotated
+	// outside of Evergreen.
+	ExternalSecret string `bson:"external_secret,omitempty" json:"external_secret,omitempty" yaml:"external_secret,omitempty"`
 }
 
 type TriggerDefinition struct {
@@ -181,6 +291,12 @@ type TriggerDefinition struct {
 	// definitions for tasks to run for this trigger
 	ConfigFile string `bson:"config_file,omitempty" json:"config_file,omitempty"`
 	Alias      string `bson:"alias,omitempty" json:"alias,omitempty"`
+
+	// labels to add to versions created by this trigger
+	Labels map[string]string `bson:"labels,omitempty" json:"labels,omitempty"`
+	// Parameters are passed to versions created by this trigger. Values can
+	// reference the upstream version and task or build with expansions.
+	Parameters []patch.Parameter `bson:"parameters,omitempty" json:"parameters,omitempty"`
 }
 
 type PeriodicBuildDefinition struct {
@@ -190,6 +306,12 @@ type PeriodicBuildDefinition struct {
 	Alias         string    `bson:"alias,omitempty" json:"alias,omitempty"`
 	Message       string    `bson:"message,omitempty" json:"message,omitempty"`
 	NextRunTime   time.Time `bson:"next_run_time,omitempty" json:"next_run_time,omitempty"`
+	// Cron schedules the periodic build using cron syntax instead of a fixed
+	// interval.
+	Cron string `bson:"cron,omitempty" json:"cron,omitempty"`
+	// Timezone is the IANA time zone in which the cron is evaluated. If it's
+	// not set, the cron is evaluated in the server's local time.
+	Timezone string `bson:"timezone,omitempty" json:"timezone,omitempty"`
 }
 
 type WorkstationConfig struct {
@@ -229,6 +351,7 @@ var (
 	ProjectRefEnabledKey                 = bsonutil.MustHaveTag(ProjectRef{}, "Enabled")
 	ProjectRefPrivateKey                 = bsonutil.MustHaveTag(ProjectRef{}, "Private")
 	ProjectRefRestrictedKey              = bsonutil.MustHaveTag(ProjectRef{}, "Restricted")
+	projectRefRequireSettingsApprovalKey = bsonutil.MustHaveTag(ProjectRef{}, "RequireSettingsApproval")
 	ProjectRefBatchTimeKey               = bsonutil.MustHaveTag(ProjectRef{}, "BatchTime")
 	ProjectRefIdentifierKey              = bsonutil.MustHaveTag(ProjectRef{}, "Identifier")
 	ProjectRefRepoRefIdKey               = bsonutil.MustHaveTag(ProjectRef{}, "RepoRefId")
@@ -236,6 +359,7 @@ var (
 	ProjectRefDeactivatePreviousKey      = bsonutil.MustHaveTag(ProjectRef{}, "DeactivatePrevious")
 	ProjectRefRemotePathKey              = bsonutil.MustHaveTag(ProjectRef{}, "RemotePath")
 	ProjectRefHiddenKey                  = bsonutil.MustHaveTag(ProjectRef{}, "Hidden")
+	ProjectRefArchivedKey                = bsonutil.MustHaveTag(ProjectRef{}, "Archived")
 	ProjectRefRepotrackerError           = bsonutil.MustHaveTag(ProjectRef{}, "RepotrackerError")
 	ProjectRefFilesIgnoredFromCacheKey   = bsonutil.MustHaveTag(ProjectRef{}, "FilesIgnoredFromCache")
 	ProjectRefDisabledStatsCacheKey      = bsonutil.MustHaveTag(ProjectRef{}, "DisabledStatsCache")
@@ -248,9 +372,32 @@ var (
 	projectRefManualPRTestingEnabledKey  = bsonutil.MustHaveTag(ProjectRef{}, "ManualPRTestingEnabled")
 	projectRefGithubChecksEnabledKey     = bsonutil.MustHaveTag(ProjectRef{}, "GithubChecksEnabled")
 	projectRefGitTagVersionsEnabledKey   = bsonutil.MustHaveTag(ProjectRef{}, "GitTagVersionsEnabled")
+	projectRefGithubMergeQueueEnabledKey = bsonutil.MustHaveTag(ProjectRef{}, "GithubMergeQueueEnabled")
 	projectRefRepotrackerDisabledKey     = bsonutil.MustHaveTag(ProjectRef{}, "RepotrackerDisabled")
 	projectRefCommitQueueKey             = bsonutil.MustHaveTag(ProjectRef{}, "CommitQueue")
 	projectRefTaskSyncKey                = bsonutil.MustHaveTag(ProjectRef{}, "TaskSync")
+	projectRefLogRetentionKey            = bsonutil.MustHaveTag(ProjectRef{}, "LogRetention")
+	projectRefArchiveVersionsAfterKey    = bsonutil.MustHaveTag(ProjectRef{}, "ArchiveVersionsAfterDays")
+	projectRefTaskOrderKey               = bsonutil.MustHaveTag(ProjectRef{}, "TaskOrder")
+	projectRefMaxWarningsKey             = bsonutil.MustHaveTag(ProjectRef{}, "MaxValidationWarnings")
+	projectRefSeverityOverridesKey       = bsonutil.MustHaveTag(ProjectRef{}, "ValidationSeverityOverrides")
+	projectRefQuotasKey                  = bsonutil.MustHaveTag(ProjectRef{}, "Quotas")
+	projectRefPatchPolicyKey             = bsonutil.MustHaveTag(ProjectRef{}, "PatchPolicy")
+	projectRefCodeOwnersRoutingKey       = bsonutil.MustHaveTag(ProjectRef{}, "CodeOwnersRouting")
+	projectRefAutoBisectKey              = bsonutil.MustHaveTag(ProjectRef{}, "AutoBisect")
+	ProjectRefEventSourcedRollupKey      = bsonutil.MustHaveTag(ProjectRef{}, "EventSourcedStatusRollup")
+	projectRefFailureLogIndexingKey      = bsonutil.MustHaveTag(ProjectRef{}, "FailureLogIndexing")
+	projectRefSecretsScanningKey         = bsonutil.MustHaveTag(ProjectRef{}, "SecretsScanning")
+	projectRefVariantActivationHooksKey  = bsonutil.MustHaveTag(ProjectRef{}, "VariantActivationHooks")
+	projectRefActivationHookSecretKey    = bsonutil.MustHaveTag(ProjectRef{}, "VariantActivationHookSecret")
+	projectRefPriorityAgingKey           = bsonutil.MustHaveTag(ProjectRef{}, "PriorityAging")
+	ProjectRefStuckTaskPolicyKey         = bsonutil.MustHaveTag(ProjectRef{}, "StuckTaskPolicy")
+	projectRefWatchedPathsKey            = bsonutil.MustHaveTag(ProjectRef{}, "WatchedPaths")
+	projectRefWatchedPathsPolicyKey      = bsonutil.MustHaveTag(ProjectRef{}, "WatchedPathsPolicy")
+	projectRefOverridableExpansionsKey   = bsonutil.MustHaveTag(ProjectRef{}, "OverridableExpansions")
+	projectRefGithubVariantChecksKey     = bsonutil.MustHaveTag(ProjectRef{}, "GithubVariantChecks")
+	projectRefStalePatchPolicyKey        = bsonutil.MustHaveTag(ProjectRef{}, "StalePatchPolicy")
+	projectRefPublicStatusKey            = bsonutil.MustHaveTag(ProjectRef{}, "PublicStatus")
 	projectRefPatchingDisabledKey        = bsonutil.MustHaveTag(ProjectRef{}, "PatchingDisabled")
 	projectRefDispatchingDisabledKey     = bsonutil.MustHaveTag(ProjectRef{}, "DispatchingDisabled")
 	projectRefVersionControlEnabledKey   = bsonutil.MustHaveTag(ProjectRef{}, "VersionControlEnabled")
@@ -259,6 +406,7 @@ var (
 	projectRefTriggersKey                = bsonutil.MustHaveTag(ProjectRef{}, "Triggers")
 	projectRefPatchTriggerAliasesKey     = bsonutil.MustHaveTag(ProjectRef{}, "PatchTriggerAliases")
 	projectRefGithubTriggerAliasesKey    = bsonutil.MustHaveTag(ProjectRef{}, "GithubTriggerAliases")
+	projectRefDownstreamParamsKey        = bsonutil.MustHaveTag(ProjectRef{}, "DownstreamParameterSchema")
 	projectRefPeriodicBuildsKey          = bsonutil.MustHaveTag(ProjectRef{}, "PeriodicBuilds")
 	projectRefWorkstationConfigKey       = bsonutil.MustHaveTag(ProjectRef{}, "WorkstationConfig")
 	projectRefTaskAnnotationSettingsKey  = bsonutil.MustHaveTag(ProjectRef{}, "TaskAnnotationSettings")
@@ -281,6 +429,12 @@ func (p *ProjectRef) IsRestricted() bool {
 	return utility.FromBoolPtr(p.Restricted)
 }
 
+// SettingsChangesRequireApproval returns true if the project is restricted and
+// has opted into having settings changes approved by a second admin.
+func (p *ProjectRef) SettingsChangesRequireApproval() bool {
+	return p.IsRestricted() && utility.FromBoolPtr(p.RequireSettingsApproval)
+}
+
 func (p *ProjectRef) IsPatchingDisabled() bool {
 	return utility.FromBoolPtr(p.PatchingDisabled)
 }
@@ -324,6 +478,30 @@ func (p *ProjectRef) ShouldDeactivatePrevious() bool {
 	return utility.FromBoolPtr(p.DeactivatePrevious)
 }
 
+func (p *ProjectRef) IsCodeOwnersRoutingEnabled() bool {
+	return utility.FromBoolPtr(p.CodeOwnersRouting)
+}
+
+func (p *ProjectRef) IsAutoBisectEnabled() bool {
+	return utility.FromBoolPtr(p.AutoBisect)
+}
+
+func (p *ProjectRef) IsPublicStatusEnabled() bool {
+	return utility.FromBoolPtr(p.PublicStatus)
+}
+
+func (p *ProjectRef) IsEventSourcedStatusRollupEnabled() bool {
+	return utility.FromBoolPtr(p.EventSourcedStatusRollup)
+}
+
+func (p *ProjectRef) IsFailureLogIndexingEnabled() bool {
+	return utility.FromBoolPtr(p.FailureLogIndexing)
+}
+
+func (p *ProjectRef) IsSecretsScanningEnabled() bool {
+	return utility.FromBoolPtr(p.SecretsScanning)
+}
+
 func (p *ProjectRef) ShouldNotifyOnBuildFailure() bool {
 	return utility.FromBoolPtr(p.NotifyOnBuildFailure)
 }
@@ -336,6 +514,10 @@ func (p *ProjectRef) IsGitTagVersionsEnabled() bool {
 	return utility.FromBoolPtr(p.GitTagVersionsEnabled)
 }
 
+func (p *ProjectRef) IsGithubMergeQueueEnabled() bool {
+	return utility.FromBoolPtr(p.GithubMergeQueueEnabled)
+}
+
 func (p *ProjectRef) IsStatsCacheDisabled() bool {
 	return utility.FromBoolPtr(p.DisabledStatsCache)
 }
@@ -344,6 +526,10 @@ func (p *ProjectRef) IsHidden() bool {
 	return utility.FromBoolPtr(p.Hidden)
 }
 
+func (p *ProjectRef) IsArchived() bool {
+	return utility.FromBoolPtr(p.Archived)
+}
+
 func (p *ProjectRef) UseRepoSettings() bool {
 	return p.RepoRefId != ""
 }
@@ -377,7 +563,8 @@ func (c *WorkstationConfig) ShouldGitClone() bool {
 }
 
 func (p *ProjectRef) AliasesNeeded() bool {
-	return p.IsGithubChecksEnabled() || p.IsGitTagVersionsEnabled() || p.IsGithubChecksEnabled() || p.IsPRTestingEnabled()
+	return p.IsGithubChecksEnabled() || p.IsGitTagVersionsEnabled() || p.IsGithubChecksEnabled() || p.IsPRTestingEnabled() ||
+		p.IsGithubMergeQueueEnabled()
 }
 
 const (
@@ -388,6 +575,12 @@ const (
 	maxBatchTime             = 153722867 // math.MaxInt64 / 60 / 1_000_000_000
 )
 
+// Severities that a project can report a validation rule's results at.
+const (
+	ValidationSeverityError   = "error"
+	ValidationSeverityWarning = "warning"
+)
+
 type ProjectPageSection string
 
 // These values must remain consistent with the GraphQL enum ProjectSettingsSection
@@ -473,27 +666,17 @@ func (p *ProjectRef) MergeWithProjectConfig(version string) (err error) {
 	if err != nil {
 		return err
 	}
+	return p.MergeWithGivenProjectConfig(projectConfig)
+}
+
+// MergeWithGivenProjectConfig sets the project ref's undefined settings to the
+// ones defined in the given project config, if there is one.
+func (p *ProjectRef) MergeWithGivenProjectConfig(projectConfig *ProjectConfig) (err error) {
 	if projectConfig != nil {
 		defer func() {
 			err = recovery.HandlePanicWithError(recover(), err, "project ref and project config structures do not match")
 		}()
-		pRefToMerge := ProjectRef{
-			PeriodicBuilds:       projectConfig.PeriodicBuilds,
-			GithubTriggerAliases: projectConfig.GithubTriggerAliases,
-			ContainerSizes:       projectConfig.ContainerSizes,
-		}
-		if projectConfig.WorkstationConfig != nil {
-			pRefToMerge.WorkstationConfig = *projectConfig.WorkstationConfig
-		}
-		if projectConfig.BuildBaronSettings != nil {
-			pRefToMerge.BuildBaronSettings = *projectConfig.BuildBaronSettings
-		}
-		if projectConfig.TaskAnnotationSettings != nil {
-			pRefToMerge.TaskAnnotationSettings = *projectConfig.TaskAnnotationSettings
-		}
-		if projectConfig.TaskSync != nil {
-			pRefToMerge.TaskSync = *projectConfig.TaskSync
-		}
+		pRefToMerge := projectConfig.projectRefSettings()
 		reflectedRef := reflect.ValueOf(p).Elem()
 		reflectedConfig := reflect.ValueOf(pRefToMerge)
 		util.RecursivelySetUndefinedFields(reflectedRef, reflectedConfig)
@@ -501,6 +684,29 @@ func (p *ProjectRef) MergeWithProjectConfig(version string) (err error) {
 	return err
 }
 
+// projectRefSettings returns the project settings that are defined in the
+// project config.
+func (pc *ProjectConfig) projectRefSettings() ProjectRef {
+	pRef := ProjectRef{
+		PeriodicBuilds:       pc.PeriodicBuilds,
+		GithubTriggerAliases: pc.GithubTriggerAliases,
+		ContainerSizes:       pc.ContainerSizes,
+	}
+	if pc.WorkstationConfig != nil {
+		pRef.WorkstationConfig = *pc.WorkstationConfig
+	}
+	if pc.BuildBaronSettings != nil {
+		pRef.BuildBaronSettings = *pc.BuildBaronSettings
+	}
+	if pc.TaskAnnotationSettings != nil {
+		pRef.TaskAnnotationSettings = *pc.TaskAnnotationSettings
+	}
+	if pc.TaskSync != nil {
+		pRef.TaskSync = *pc.TaskSync
+	}
+	return pRef
+}
+
 // AddToRepoScope validates that the branch can be attached to the matching repo,
 // adds the branch to the unrestricted branches under repo scope, and
 // adds repo view permission for branch admins, and adds branch edit access for repo admins.
@@ -1004,7 +1210,7 @@ func getCommonAliases(projectIds []string) (ProjectAliases, error) {
 func aliasSliceContains(slice []ProjectAlias, item ProjectAlias) bool {
 	for _, each := range slice {
 		if each.RemotePath != item.RemotePath || each.Alias != item.Alias || each.GitTag != item.GitTag ||
-			each.Variant != item.Variant || each.Task != item.Task {
+			each.Variant != item.Variant || each.Task != item.Task || each.Selector != item.Selector {
 			continue
 		}
 
@@ -1027,6 +1233,7 @@ func getCommonProjectVariables(projectIds []string) (*ProjectVars, error) {
 	commonProjectVariables := map[string]string{}
 	commonPrivate := map[string]bool{}
 	commonAdminOnly := map[string]bool{}
+	commonRestricted := map[string]ProjectVarRestriction{}
 	for i, id := range projectIds {
 		vars, err := FindOneProjectVars(id)
 		if err != nil {
@@ -1045,6 +1252,9 @@ func getCommonProjectVariables(projectIds []string) (*ProjectVars, error) {
 			if vars.AdminOnlyVars != nil {
 				commonAdminOnly = vars.AdminOnlyVars
 			}
+			if vars.RestrictedVars != nil {
+				commonRestricted = vars.RestrictedVars
+			}
 			continue
 		}
 		for key, val := range commonProjectVariables {
@@ -1056,16 +1266,27 @@ func getCommonProjectVariables(projectIds []string) (*ProjectVars, error) {
 				if vars.AdminOnlyVars[key] {
 					commonAdminOnly[key] = true
 				}
+				// A variable that isn't restricted the same way in every
+				// project can't be shared by the repo, since one project's
+				// restriction would apply to all of them.
+				restriction, restricted := vars.RestrictedVars[key]
+				commonRestriction, commonIsRestricted := commonRestricted[key]
+				if restricted != commonIsRestricted || restriction != commonRestriction {
+					delete(commonProjectVariables, key)
+					delete(commonRestricted, key)
+				}
 			} else {
 				// remove any variables from the common set that aren't in all the project refs
 				delete(commonProjectVariables, key)
+				delete(commonRestricted, key)
 			}
 		}
 	}
 	return &ProjectVars{
-		Vars:          commonProjectVariables,
-		PrivateVars:   commonPrivate,
-		AdminOnlyVars: commonAdminOnly,
+		Vars:           commonProjectVariables,
+		PrivateVars:    commonPrivate,
+		AdminOnlyVars:  commonAdminOnly,
+		RestrictedVars: commonRestricted,
 	}, nil
 }
 
@@ -1160,11 +1381,14 @@ func FindFirstProjectRef() (*ProjectRef, error) {
 
 // FindAllMergedTrackedProjectRefs returns all project refs in the db
 // that are currently being tracked (i.e. their project files
-// still exist and the project is not hidden).
+// still exist and the project is not hidden or archived).
 // Can't hide a repo without hiding the branches, so don't need to aggregate here.
 func FindAllMergedTrackedProjectRefs() ([]ProjectRef, error) {
 	projectRefs := []ProjectRef{}
-	q := db.Query(bson.M{ProjectRefHiddenKey: bson.M{"$ne": true}})
+	q := db.Query(bson.M{
+		ProjectRefHiddenKey:   bson.M{"$ne": true},
+		ProjectRefArchivedKey: bson.M{"$ne": true},
+	})
 	err := db.FindAllQ(ProjectRefCollection, q, &projectRefs)
 	if err != nil {
 		return nil, err
@@ -1516,8 +1740,8 @@ func UpdateAdminRoles(project *ProjectRef, toAdd, toDelete []string) error {
 }
 
 // FindProjects queries the backing database for the specified projects
-func FindProjects(key string, limit int, sortDir int) ([]ProjectRef, error) {
-	projects, err := FindProjectRefs(key, limit, sortDir)
+func FindProjects(key string, limit int, sortDir int, includeArchived bool) ([]ProjectRef, error) {
+	projects, err := FindProjectRefs(key, limit, sortDir, includeArchived)
 	if err != nil {
 		return nil, errors.Wrapf(err, "fetching projects starting at project '%s'", key)
 	}
@@ -1703,9 +1927,13 @@ func FindPeriodicProjects() ([]ProjectRef, error) {
 }
 
 // FindProjectRefs returns limit refs starting at project id key in the sortDir direction.
-func FindProjectRefs(key string, limit int, sortDir int) ([]ProjectRef, error) {
+// Archived projects are only included if includeArchived is set.
+func FindProjectRefs(key string, limit int, sortDir int, includeArchived bool) ([]ProjectRef, error) {
 	projectRefs := []ProjectRef{}
 	filter := bson.M{}
+	if !includeArchived {
+		filter[ProjectRefArchivedKey] = bson.M{"$ne": true}
+	}
 	sortSpec := ProjectRefIdKey
 
 	if sortDir < 0 {
@@ -1778,6 +2006,28 @@ func SaveProjectPageForSection(projectId string, p *ProjectRef, section ProjectP
 			projectRefCedarTestResultsEnabledKey: p.CedarTestResultsEnabled,
 			projectRefPatchingDisabledKey:        p.PatchingDisabled,
 			projectRefTaskSyncKey:                p.TaskSync,
+			projectRefLogRetentionKey:            p.LogRetention,
+			projectRefArchiveVersionsAfterKey:    p.ArchiveVersionsAfterDays,
+			projectRefTaskOrderKey:               p.TaskOrder,
+			projectRefMaxWarningsKey:             p.MaxValidationWarnings,
+			projectRefSeverityOverridesKey:       p.ValidationSeverityOverrides,
+			projectRefQuotasKey:                  p.Quotas,
+			projectRefPatchPolicyKey:             p.PatchPolicy,
+			projectRefCodeOwnersRoutingKey:       p.CodeOwnersRouting,
+			projectRefAutoBisectKey:              p.AutoBisect,
+			ProjectRefEventSourcedRollupKey:      p.EventSourcedStatusRollup,
+			projectRefFailureLogIndexingKey:      p.FailureLogIndexing,
+			projectRefSecretsScanningKey:         p.SecretsScanning,
+			projectRefVariantActivationHooksKey:  p.VariantActivationHooks,
+			projectRefActivationHookSecretKey:    p.VariantActivationHookSecret,
+			projectRefPriorityAgingKey:           p.PriorityAging,
+			ProjectRefStuckTaskPolicyKey:         p.StuckTaskPolicy,
+			projectRefWatchedPathsKey:            p.WatchedPaths,
+			projectRefWatchedPathsPolicyKey:      p.WatchedPathsPolicy,
+			projectRefOverridableExpansionsKey:   p.OverridableExpansions,
+			projectRefGithubVariantChecksKey:     p.GithubVariantChecks,
+			projectRefStalePatchPolicyKey:        p.StalePatchPolicy,
+			projectRefPublicStatusKey:            p.PublicStatus,
 			ProjectRefDisabledStatsCacheKey:      p.DisabledStatsCache,
 			ProjectRefFilesIgnoredFromCacheKey:   p.FilesIgnoredFromCache,
 		}
@@ -1805,9 +2055,10 @@ func SaveProjectPageForSection(projectId string, p *ProjectRef, section ProjectP
 			bson.M{ProjectRefIdKey: projectId},
 			bson.M{
 				"$set": bson.M{
-					ProjectRefPrivateKey:    p.Private,
-					ProjectRefRestrictedKey: p.Restricted,
-					ProjectRefAdminsKey:     p.Admins,
+					ProjectRefPrivateKey:                 p.Private,
+					ProjectRefRestrictedKey:              p.Restricted,
+					projectRefRequireSettingsApprovalKey: p.RequireSettingsApproval,
+					ProjectRefAdminsKey:                  p.Admins,
 				},
 			})
 	case ProjectPageGithubAndCQSection:
@@ -1815,13 +2066,14 @@ func SaveProjectPageForSection(projectId string, p *ProjectRef, section ProjectP
 			bson.M{ProjectRefIdKey: projectId},
 			bson.M{
 				"$set": bson.M{
-					projectRefPRTestingEnabledKey:       p.PRTestingEnabled,
-					projectRefManualPRTestingEnabledKey: p.ManualPRTestingEnabled,
-					projectRefGithubChecksEnabledKey:    p.GithubChecksEnabled,
-					projectRefGitTagVersionsEnabledKey:  p.GitTagVersionsEnabled,
-					ProjectRefGitTagAuthorizedUsersKey:  p.GitTagAuthorizedUsers,
-					ProjectRefGitTagAuthorizedTeamsKey:  p.GitTagAuthorizedTeams,
-					projectRefCommitQueueKey:            p.CommitQueue,
+					projectRefPRTestingEnabledKey:        p.PRTestingEnabled,
+					projectRefManualPRTestingEnabledKey:  p.ManualPRTestingEnabled,
+					projectRefGithubChecksEnabledKey:     p.GithubChecksEnabled,
+					projectRefGitTagVersionsEnabledKey:   p.GitTagVersionsEnabled,
+					ProjectRefGitTagAuthorizedUsersKey:   p.GitTagAuthorizedUsers,
+					ProjectRefGitTagAuthorizedTeamsKey:   p.GitTagAuthorizedTeams,
+					projectRefCommitQueueKey:             p.CommitQueue,
+					projectRefGithubMergeQueueEnabledKey: p.GithubMergeQueueEnabled,
 				},
 			})
 	case ProjectPageNotificationsSection:
@@ -1852,6 +2104,7 @@ func SaveProjectPageForSection(projectId string, p *ProjectRef, section ProjectP
 				"$set": bson.M{
 					projectRefPatchTriggerAliasesKey:  p.PatchTriggerAliases,
 					projectRefGithubTriggerAliasesKey: p.GithubTriggerAliases,
+					projectRefDownstreamParamsKey:     p.DownstreamParameterSchema,
 				},
 			})
 	case ProjectPagePeriodicBuildsSection:
@@ -1975,16 +2228,117 @@ func handleBatchTimeOverflow(in int) int {
 
 // return the next valid batch time
 func GetActivationTimeWithCron(curTime time.Time, cronBatchTime string) (time.Time, error) {
+	return GetActivationTimeWithCronInTimezone(curTime, cronBatchTime, "")
+}
+
+// GetActivationTimeWithCronInTimezone returns the next time after curTime that
+// the cron batchtime fires when it's evaluated in the given IANA time zone. If
+// no time zone is given, the cron is evaluated in curTime's location.
+func GetActivationTimeWithCronInTimezone(curTime time.Time, cronBatchTime, timezone string) (time.Time, error) {
+	sched, err := parseCronBatchTime(cronBatchTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if timezone != "" {
+		var loc *time.Location
+		loc, err = LoadCronTimezone(timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+		curTime = curTime.In(loc)
+	}
+	return sched.Next(curTime), nil
+}
 
+func parseCronBatchTime(cronBatchTime string) (cron.Schedule, error) {
 	if strings.HasPrefix(cronBatchTime, intervalPrefix) {
-		return time.Time{}, errors.Errorf("cannot use interval '%s' in cron batchtime '%s'", intervalPrefix, cronBatchTime)
+		return nil, errors.Errorf("cannot use interval '%s' in cron batchtime '%s'", intervalPrefix, cronBatchTime)
 	}
 	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.DowOptional | cron.Descriptor)
 	sched, err := parser.Parse(cronBatchTime)
 	if err != nil {
-		return time.Time{}, errors.Wrapf(err, "parsing cron batchtime '%s'", cronBatchTime)
+		return nil, errors.Wrapf(err, "parsing cron batchtime '%s'", cronBatchTime)
 	}
-	return sched.Next(curTime), nil
+	return sched, nil
+}
+
+// LoadCronTimezone returns the location for the IANA time zone name (e.g.
+// "America/New_York") that a cron is evaluated in. The server's local time
+// zone is rejected because it would not be evaluated consistently.
+func LoadCronTimezone(timezone string) (*time.Location, error) {
+	if timezone == "" || timezone == "Local" {
+		return nil, errors.Errorf("invalid time zone '%s', must be an IANA time zone name such as 'America/New_York' or 'UTC'", timezone)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid time zone '%s'", timezone)
+	}
+	return loc, nil
+}
+
+// cronDSTLookahead is how far ahead to check for daylight saving time
+// transitions that affect a cron.
+const cronDSTLookahead = 366 * 24 * time.Hour
+
+// GetCronDSTConflicts returns a description of each daylight saving time
+// transition in the year after curTime during which the cron batchtime would
+// fire at a local time that is skipped or repeated in the time zone. Such a
+// cron either doesn't fire or fires at an unexpected time on those days.
+func GetCronDSTConflicts(curTime time.Time, cronBatchTime, timezone string) ([]string, error) {
+	sched, err := parseCronBatchTime(cronBatchTime)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := LoadCronTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+	spec, ok := sched.(*cron.SpecSchedule)
+	if !ok {
+		// Constant delay schedules don't depend on the local time.
+		return nil, nil
+	}
+	const allHours = 1<<24 - 1
+	if spec.Hour&allHours == allHours {
+		// A cron that fires every hour isn't affected by losing or repeating
+		// one of them.
+		return nil, nil
+	}
+
+	var conflicts []string
+	end := curTime.Add(cronDSTLookahead)
+	_, prevOffset := curTime.In(loc).Zone()
+	for t := curTime.Truncate(time.Hour).Add(time.Hour); t.Before(end); t = t.Add(time.Hour) {
+		_, offset := t.In(loc).Zone()
+		if offset == prevOffset {
+			continue
+		}
+		// Find the exact minute that the transition happened.
+		transition := t.Add(-time.Hour)
+		for ; transition.Before(t); transition = transition.Add(time.Minute) {
+			if _, transitionOffset := transition.In(loc).Zone(); transitionOffset == offset {
+				break
+			}
+		}
+
+		// Check the local wall clock times affected by the transition against
+		// the cron. The wall clock times are represented in UTC so that the
+		// cron can be evaluated without any transitions of its own.
+		kind := "skipped"
+		wallStart := time.Unix(transition.Unix()+int64(prevOffset), 0).UTC()
+		window := time.Duration(offset-prevOffset) * time.Second
+		if offset < prevOffset {
+			kind = "repeated"
+			wallStart = time.Unix(transition.Unix()+int64(offset), 0).UTC()
+			window = -window
+		}
+		if next := spec.Next(wallStart.Add(-time.Second)); next.Before(wallStart.Add(window)) {
+			conflicts = append(conflicts, fmt.Sprintf("cron '%s' fires at %s in time zone '%s', which is a local time that is %s by the daylight saving time transition on %s",
+				cronBatchTime, next.Format("15:04"), timezone, kind, next.Format("2006-01-02")))
+		}
+		prevOffset = offset
+	}
+	return conflicts, nil
 }
 
 func (p *ProjectRef) GetActivationTimeForVariant(variant *BuildVariant) (time.Time, error) {
@@ -1994,7 +2348,7 @@ func (p *ProjectRef) GetActivationTimeForVariant(variant *BuildVariant) (time.Ti
 		return utility.ZeroTime, nil
 	}
 	if variant.CronBatchTime != "" {
-		return GetActivationTimeWithCron(time.Now(), variant.CronBatchTime)
+		return GetActivationTimeWithCronInTimezone(time.Now(), variant.CronBatchTime, variant.CronTimezone)
 	}
 	// if activated explicitly set to true and we don't have batchtime, then we want to just activate now
 	if utility.FromBoolPtr(variant.Activate) && variant.BatchTime == nil {
@@ -2029,7 +2383,7 @@ func (p *ProjectRef) GetActivationTimeForTask(t *BuildVariantTaskUnit) (time.Tim
 		return utility.ZeroTime, nil
 	}
 	if t.CronBatchTime != "" {
-		return GetActivationTimeWithCron(time.Now(), t.CronBatchTime)
+		return GetActivationTimeWithCronInTimezone(time.Now(), t.CronBatchTime, t.CronTimezone)
 	}
 	// if activated explicitly set to true and we don't have batchtime, then we want to just activate now
 	if utility.FromBoolPtr(t.Activate) && t.BatchTime == nil {
@@ -2487,6 +2841,12 @@ func (t *TriggerDefinition) Validate(parentProject string) error {
 	if t.ConfigFile == "" {
 		return errors.New("must provide a config file")
 	}
+	if err = patch.ValidateLabels(patch.LabelsFromMap(t.Labels)); err != nil {
+		return errors.Wrap(err, "invalid labels")
+	}
+	if err = ValidateTriggerParameters(t.Parameters); err != nil {
+		return errors.Wrap(err, "invalid parameters")
+	}
 	if t.DefinitionID == "" {
 		t.DefinitionID = utility.RandomString()
 	}
@@ -2527,7 +2887,10 @@ func ValidateContainers(pRef *ProjectRef, containers []Container) error {
 			catcher.Add(size.Validate())
 		}
 		catcher.ErrorfWhen(container.Size != "" && !ok, "size '%s' is not defined anywhere", container.Size)
-		_, ok = pRef.ContainerCredentials[container.Credential]
+		cred, ok := pRef.ContainerCredentials[container.Credential]
+		if ok {
+			catcher.Wrapf(cred.Validate(), "invalid credential '%s'", container.Credential)
+		}
 		catcher.ErrorfWhen(container.Credential != "" && !ok, "credential '%s' is not defined anywhere", container.Credential)
 		catcher.NewWhen(container.Size != "" && container.Resources != nil, "size and resources cannot both be defined")
 		catcher.NewWhen(container.Size == "" && container.Resources == nil, "either size or resources must be defined")
@@ -2564,11 +2927,44 @@ func (c ContainerResources) Validate() error {
 // Validate that essential ContainerCredential fields are properly defined.
 func (c ContainerCredential) Validate() error {
 	catcher := grip.NewSimpleCatcher()
+	if c.ExternalSecret != "" {
+		catcher.NewWhen(c.Username != "" || c.Password != "", "container credential cannot specify both an external secret and a username or password")
+		catcher.Add(ValidateExternalSecretReference(c.ExternalSecret))
+		return catcher.Resolve()
+	}
 	catcher.NewWhen(c.Username == "", "container credential username must be a non empty string")
 	catcher.NewWhen(c.Password == "", "container credential password must be a non empty string")
 	return catcher.Resolve()
 }
 
+var (
+	secretsManagerARNRegex  = regexp.MustCompile(`^arn:aws[a-z-]*:secretsmanager:[a-z0-9-]+:[0-9]{12}:secret:[A-Za-z0-9/_+=.@-]+$`)
+	secretsManagerNameRegex = regexp.MustCompile(`^[A-Za-z0-9_+=.@-]+(/[A-Za-z0-9_+=.@-]+)*$`)
+)
+
+// maxSecretsManagerNameLength is the longest name that Secrets Manager allows
+// for a secret.
+const maxSecretsManagerNameLength = 512
+
+// ValidateExternalSecretReference checks that the reference to a secret in
+// Secrets Manager is either a well-formed ARN or a well-formed secret name
+// path such as "team/registry-creds".
+func ValidateExternalSecretReference(ref string) error {
+	if strings.HasPrefix(ref, "arn:") {
+		if !secretsManagerARNRegex.MatchString(ref) {
+			return errors.Errorf("external secret '%s' is not a valid Secrets Manager secret ARN", ref)
+		}
+		return nil
+	}
+	if len(ref) > maxSecretsManagerNameLength {
+		return errors.Errorf("external secret name cannot be longer than %d characters", maxSecretsManagerNameLength)
+	}
+	if !secretsManagerNameRegex.MatchString(ref) {
+		return errors.Errorf("external secret '%s' must be a Secrets Manager secret ARN or a secret name path made of letters, numbers, and the characters '_+=.@-' separated by '/'", ref)
+	}
+	return nil
+}
+
 func ValidateTriggerDefinition(definition patch.PatchTriggerDefinition, parentProject string) (patch.PatchTriggerDefinition, error) {
 	if definition.ChildProject == parentProject {
 		return definition, errors.New("a project cannot trigger itself")
@@ -2626,7 +3022,14 @@ func ValidateTriggerDefinition(definition patch.PatchTriggerDefinition, parentPr
 
 func (d *PeriodicBuildDefinition) Validate() error {
 	catcher := grip.NewBasicCatcher()
-	catcher.NewWhen(d.IntervalHours <= 0, "interval must be a positive integer")
+	if d.Cron != "" {
+		catcher.NewWhen(d.IntervalHours != 0, "cannot specify both an interval and a cron")
+		_, err := GetActivationTimeWithCronInTimezone(time.Now(), d.Cron, d.Timezone)
+		catcher.Wrapf(err, "invalid cron '%s'", d.Cron)
+	} else {
+		catcher.NewWhen(d.IntervalHours <= 0, "interval must be a positive integer")
+		catcher.NewWhen(d.Timezone != "", "a time zone can only be specified with a cron")
+	}
 	catcher.NewWhen(d.ConfigFile == "", "a config file must be specified")
 
 	if d.ID == "" {
@@ -2636,6 +3039,23 @@ func (d *PeriodicBuildDefinition) Validate() error {
 	return catcher.Resolve()
 }
 
+// GetNextRunTime returns the time that the periodic build should run after
+// its current run.
+func (d *PeriodicBuildDefinition) GetNextRunTime(now time.Time) (time.Time, error) {
+	baseTime := d.NextRunTime
+	if utility.IsZeroTime(baseTime) {
+		baseTime = now
+	}
+	if d.Cron == "" {
+		return baseTime.Add(time.Duration(d.IntervalHours) * time.Hour), nil
+	}
+	// A cron doesn't catch up on runs that it missed.
+	if baseTime.Before(now) {
+		baseTime = now
+	}
+	return GetActivationTimeWithCronInTimezone(baseTime, d.Cron, d.Timezone)
+}
+
 // IsWebhookConfigured retrieves webhook configuration from the project settings.
 func IsWebhookConfigured(project string, version string) (evergreen.WebHook, bool, error) {
 	projectRef, err := FindMergedProjectRef(project, version, true)
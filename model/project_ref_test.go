@@ -3,6 +3,7 @@ package model
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -280,6 +281,72 @@ func TestGetActivationTimeWithCron(t *testing.T) {
 	}
 }
 
+func TestGetActivationTimeWithCronInTimezone(t *testing.T) {
+	prevTime := time.Date(2020, time.June, 9, 0, 0, 0, 0, time.UTC)
+	t.Run("EvaluatesInTimezone", func(t *testing.T) {
+		res, err := GetActivationTimeWithCronInTimezone(prevTime, "0 9 * * *", "America/New_York")
+		require.NoError(t, err)
+		// 9am EDT is 1pm UTC.
+		assert.True(t, time.Date(2020, time.June, 9, 13, 0, 0, 0, time.UTC).Equal(res))
+	})
+	t.Run("DefaultsToCurrentTimeLocation", func(t *testing.T) {
+		res, err := GetActivationTimeWithCronInTimezone(prevTime, "0 9 * * *", "")
+		require.NoError(t, err)
+		assert.True(t, time.Date(2020, time.June, 9, 9, 0, 0, 0, time.UTC).Equal(res))
+	})
+	t.Run("FailsWithInvalidTimezone", func(t *testing.T) {
+		_, err := GetActivationTimeWithCronInTimezone(prevTime, "0 9 * * *", "Not/AZone")
+		assert.Error(t, err)
+		_, err = GetActivationTimeWithCronInTimezone(prevTime, "0 9 * * *", "Local")
+		assert.Error(t, err)
+	})
+}
+
+func TestGetCronDSTConflicts(t *testing.T) {
+	curTime := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	t.Run("SkippedTime", func(t *testing.T) {
+		conflicts, err := GetCronDSTConflicts(curTime, "30 2 * * *", "America/New_York")
+		require.NoError(t, err)
+		require.Len(t, conflicts, 1)
+		assert.Contains(t, conflicts[0], "skipped")
+		assert.Contains(t, conflicts[0], "2020-03-08")
+	})
+	t.Run("RepeatedTime", func(t *testing.T) {
+		conflicts, err := GetCronDSTConflicts(curTime, "30 1 * * *", "America/New_York")
+		require.NoError(t, err)
+		require.Len(t, conflicts, 1)
+		assert.Contains(t, conflicts[0], "repeated")
+		assert.Contains(t, conflicts[0], "2020-11-01")
+	})
+	t.Run("UnaffectedTime", func(t *testing.T) {
+		conflicts, err := GetCronDSTConflicts(curTime, "0 9 * * *", "America/New_York")
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+	})
+	t.Run("NoDaylightSavingTime", func(t *testing.T) {
+		conflicts, err := GetCronDSTConflicts(curTime, "30 2 * * *", "UTC")
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+	})
+}
+
+func TestPeriodicBuildGetNextRunTime(t *testing.T) {
+	now := time.Date(2020, time.June, 9, 12, 0, 0, 0, time.UTC)
+	t.Run("Interval", func(t *testing.T) {
+		d := PeriodicBuildDefinition{IntervalHours: 2, NextRunTime: now.Add(-time.Hour)}
+		next, err := d.GetNextRunTime(now)
+		require.NoError(t, err)
+		assert.True(t, now.Add(time.Hour).Equal(next))
+	})
+	t.Run("CronInTimezone", func(t *testing.T) {
+		d := PeriodicBuildDefinition{Cron: "0 3 * * *", Timezone: "Europe/Berlin", NextRunTime: now.Add(-48 * time.Hour)}
+		next, err := d.GetNextRunTime(now)
+		require.NoError(t, err)
+		// 3am CEST is 1am UTC.
+		assert.True(t, time.Date(2020, time.June, 10, 1, 0, 0, 0, time.UTC).Equal(next))
+	})
+}
+
 func TestAttachToNewRepo(t *testing.T) {
 	require.NoError(t, db.ClearCollections(ProjectRefCollection, RepoRefCollection, evergreen.ScopeCollection,
 		evergreen.RoleCollection, user.Collection, evergreen.ConfigCollection))
@@ -2060,3 +2127,33 @@ func TestMergeWithProjectConfig(t *testing.T) {
 	assert.Equal(t, 4, projectRef.ContainerSizes["xlarge"].CPU)
 
 }
+
+func TestContainerCredentialValidate(t *testing.T) {
+	for name, tCase := range map[string]struct {
+		cred    ContainerCredential
+		isValid bool
+	}{
+		"UsernameAndPassword": {cred: ContainerCredential{Username: "user", Password: "pass"}, isValid: true},
+		"MissingPassword":     {cred: ContainerCredential{Username: "user"}},
+		"ExternalSecretARN": {
+			cred:    ContainerCredential{ExternalSecret: "arn:aws:secretsmanager:us-east-1:123456789012:secret:team/registry-creds-AbCdEf"},
+			isValid: true,
+		},
+		"ExternalSecretName": {cred: ContainerCredential{ExternalSecret: "team/registry-creds"}, isValid: true},
+		"ExternalSecretWithPassword": {
+			cred: ContainerCredential{ExternalSecret: "team/registry-creds", Password: "pass"},
+		},
+		"MalformedARN":         {cred: ContainerCredential{ExternalSecret: "arn:aws:s3:::bucket/creds"}},
+		"NameWithEmptySegment": {cred: ContainerCredential{ExternalSecret: "team//registry-creds"}},
+		"NameWithSpaces":       {cred: ContainerCredential{ExternalSecret: "registry creds"}},
+		"NameTooLong":          {cred: ContainerCredential{ExternalSecret: strings.Repeat("a", maxSecretsManagerNameLength+1)}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tCase.isValid {
+				assert.NoError(t, tCase.cred.Validate())
+			} else {
+				assert.Error(t, tCase.cred.Validate())
+			}
+		})
+	}
+}
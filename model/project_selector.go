@@ -2,6 +2,7 @@ package model
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 
 	"github.com/evergreen-ci/utility"
@@ -48,6 +49,17 @@ func (s Selector) String() string {
 	return buf.String()
 }
 
+// Tags returns the tags that the selector's criteria select by.
+func (s Selector) Tags() []string {
+	var tags []string
+	for _, sc := range s {
+		if sc.tagged {
+			tags = append(tags, sc.name)
+		}
+	}
+	return tags
+}
+
 // selectCriterions are intersected to form the results of a selector.
 type selectCriterion struct {
 	name string
@@ -395,3 +407,125 @@ func (v *variantSelectorEvaluator) evalSelector(vs *variantSelector) ([]string,
 	}
 	return results, nil
 }
+
+// Project selector logic
+
+// namedItem is a tagged item that's only identified by its name and tags.
+type namedItem struct {
+	itemName string
+	itemTags []string
+}
+
+func (ni *namedItem) name() string   { return ni.itemName }
+func (ni *namedItem) tags() []string { return ni.itemTags }
+
+// EvaluateTaskSelector returns the names of the tasks and task groups in the
+// project that the selector selects. As in build variant definitions, the
+// selector is evaluated against the tasks and the task groups separately, and
+// it's only an error if it selects neither.
+func (p *Project) EvaluateTaskSelector(selector string) (tasks []string, taskGroups []string, err error) {
+	s := ParseSelector(selector)
+
+	taskSelectees := make([]tagged, 0, len(p.Tasks))
+	for _, t := range p.Tasks {
+		taskSelectees = append(taskSelectees, &namedItem{itemName: t.Name, itemTags: t.Tags})
+	}
+	tasks, taskErr := newTagSelectorEvaluator(taskSelectees).evalSelector(s)
+
+	groupSelectees := make([]tagged, 0, len(p.TaskGroups))
+	for _, tg := range p.TaskGroups {
+		groupSelectees = append(groupSelectees, &namedItem{itemName: tg.Name, itemTags: tg.Tags})
+	}
+	taskGroups, groupErr := newTagSelectorEvaluator(groupSelectees).evalSelector(s)
+
+	if taskErr != nil && groupErr != nil {
+		return nil, nil, errors.Wrapf(taskErr, "evaluating task selector '%s'", selector)
+	}
+	return tasks, taskGroups, nil
+}
+
+// EvaluateVariantSelector returns the names of the build variants in the
+// project that the selector selects.
+func (p *Project) EvaluateVariantSelector(selector string) ([]string, error) {
+	selectees := make([]tagged, 0, len(p.BuildVariants))
+	for _, bv := range p.BuildVariants {
+		selectees = append(selectees, &namedItem{itemName: bv.Name, itemTags: bv.Tags})
+	}
+	variants, err := newTagSelectorEvaluator(selectees).evalSelector(ParseSelector(selector))
+	if err != nil {
+		return nil, errors.Wrapf(err, "evaluating variant selector '%s'", selector)
+	}
+	return variants, nil
+}
+
+// ParserSelector is a selector written in a project's YAML, along with a
+// description of where it's written.
+type ParserSelector struct {
+	Selector string
+	Location string
+}
+
+// TaskSelectors returns the task selectors in the project's YAML.
+func (pp *ParserProject) TaskSelectors() []ParserSelector {
+	var selectors []ParserSelector
+	for _, t := range pp.Tasks {
+		selectors = append(selectors, t.DependsOn.taskSelectors(fmt.Sprintf("a dependency of task '%s'", t.Name))...)
+	}
+	for _, tg := range pp.TaskGroups {
+		for _, selector := range tg.Tasks {
+			selectors = append(selectors, ParserSelector{Selector: selector, Location: fmt.Sprintf("task group '%s'", tg.Name)})
+		}
+		selectors = append(selectors, tg.DependsOn.taskSelectors(fmt.Sprintf("a dependency of task group '%s'", tg.Name))...)
+	}
+	for _, bv := range pp.BuildVariants {
+		for _, bvt := range bv.Tasks {
+			selectors = append(selectors, ParserSelector{Selector: bvt.Name, Location: fmt.Sprintf("build variant '%s'", bv.Name)})
+			selectors = append(selectors, bvt.DependsOn.taskSelectors(fmt.Sprintf("a dependency of task '%s' in build variant '%s'", bvt.Name, bv.Name))...)
+		}
+		for _, dt := range bv.DisplayTasks {
+			for _, selector := range dt.ExecutionTasks {
+				selectors = append(selectors, ParserSelector{Selector: selector, Location: fmt.Sprintf("display task '%s' in build variant '%s'", dt.Name, bv.Name)})
+			}
+		}
+		selectors = append(selectors, bv.DependsOn.taskSelectors(fmt.Sprintf("a dependency of build variant '%s'", bv.Name))...)
+	}
+	return selectors
+}
+
+// VariantSelectors returns the build variant selectors in the project's YAML.
+// Matrix selectors are omitted, since they select by axis values rather than
+// by tag.
+func (pp *ParserProject) VariantSelectors() []ParserSelector {
+	var selectors []ParserSelector
+	for _, t := range pp.Tasks {
+		selectors = append(selectors, t.DependsOn.variantSelectors(fmt.Sprintf("a dependency of task '%s'", t.Name))...)
+	}
+	for _, tg := range pp.TaskGroups {
+		selectors = append(selectors, tg.DependsOn.variantSelectors(fmt.Sprintf("a dependency of task group '%s'", tg.Name))...)
+	}
+	for _, bv := range pp.BuildVariants {
+		for _, bvt := range bv.Tasks {
+			selectors = append(selectors, bvt.DependsOn.variantSelectors(fmt.Sprintf("a dependency of task '%s' in build variant '%s'", bvt.Name, bv.Name))...)
+		}
+		selectors = append(selectors, bv.DependsOn.variantSelectors(fmt.Sprintf("a dependency of build variant '%s'", bv.Name))...)
+	}
+	return selectors
+}
+
+func (pds parserDependencies) taskSelectors(location string) []ParserSelector {
+	var selectors []ParserSelector
+	for _, pd := range pds {
+		selectors = append(selectors, ParserSelector{Selector: pd.TaskSelector.Name, Location: location})
+	}
+	return selectors
+}
+
+func (pds parserDependencies) variantSelectors(location string) []ParserSelector {
+	var selectors []ParserSelector
+	for _, pd := range pds {
+		if pd.TaskSelector.Variant != nil && pd.TaskSelector.Variant.StringSelector != "" {
+			selectors = append(selectors, ParserSelector{Selector: pd.TaskSelector.Variant.StringSelector, Location: location})
+		}
+	}
+	return selectors
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var materialTempAxes = []matrixAxis{
@@ -352,3 +354,68 @@ func TestVariantMatrixSelectorEvaluation(t *testing.T) {
 	})
 
 }
+
+func TestProjectSelectorEvaluation(t *testing.T) {
+	p := &Project{
+		Tasks: []ProjectTask{
+			{Name: "compile", Tags: []string{"primary"}},
+			{Name: "lint", Tags: []string{"primary", "quick"}},
+			{Name: "fuzz", Tags: []string{"experimental"}},
+		},
+		TaskGroups: []TaskGroup{
+			{Name: "integration", Tags: []string{"primary"}},
+			{Name: "canary", Tags: []string{"canaries"}},
+		},
+		BuildVariants: []BuildVariant{
+			{Name: "ubuntu", Tags: []string{"linux"}},
+			{Name: "rhel", Tags: []string{"linux", "enterprise"}},
+			{Name: "windows"},
+		},
+	}
+
+	t.Run("TaskTagSelectsTasksAndTaskGroups", func(t *testing.T) {
+		tasks, taskGroups, err := p.EvaluateTaskSelector(".primary")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"compile", "lint"}, tasks)
+		assert.Equal(t, []string{"integration"}, taskGroups)
+	})
+	t.Run("NegatedTaskTag", func(t *testing.T) {
+		tasks, taskGroups, err := p.EvaluateTaskSelector("!.experimental")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"compile", "lint"}, tasks)
+		assert.Empty(t, taskGroups)
+	})
+	t.Run("TaskTagOnlyOnTaskGroups", func(t *testing.T) {
+		tasks, taskGroups, err := p.EvaluateTaskSelector(".canaries")
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+		assert.Equal(t, []string{"canary"}, taskGroups)
+	})
+	t.Run("IntersectsCriteria", func(t *testing.T) {
+		tasks, _, err := p.EvaluateTaskSelector(".primary !.quick")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"compile"}, tasks)
+	})
+	t.Run("FailsForUnusedTaskTag", func(t *testing.T) {
+		_, _, err := p.EvaluateTaskSelector(".nonexistent")
+		assert.Error(t, err)
+	})
+	t.Run("FailsForEmptySelector", func(t *testing.T) {
+		_, _, err := p.EvaluateTaskSelector(" ")
+		assert.Error(t, err)
+	})
+	t.Run("VariantTag", func(t *testing.T) {
+		variants, err := p.EvaluateVariantSelector(".linux !.enterprise")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ubuntu"}, variants)
+	})
+	t.Run("FailsForUnusedVariantTag", func(t *testing.T) {
+		_, err := p.EvaluateVariantSelector(".mac")
+		assert.Error(t, err)
+	})
+}
+
+func TestSelectorTags(t *testing.T) {
+	assert.Equal(t, []string{"primary", "slow"}, ParseSelector(".primary !.slow !compile").Tags())
+	assert.Empty(t, ParseSelector("compile !test").Tags())
+}
@@ -0,0 +1,382 @@
+package model
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	ProjectTemplateCollection = "project_templates"
+
+	// ProjectTemplateIdentifierPlaceholder is the placeholder that is always
+	// available in a template and is filled in with the identifier of the
+	// project being created.
+	ProjectTemplateIdentifierPlaceholder = "identifier"
+)
+
+var (
+	projectTemplateNameKey = bsonutil.MustHaveTag(ProjectTemplate{}, "Name")
+
+	projectTemplatePlaceholderRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// ProjectTemplate is a named set of project settings that new branch projects
+// can be created from. Any string in the settings may reference one of the
+// template's placeholders as an expansion (e.g. ${branch}), which is filled in
+// when a project is created from the template.
+type ProjectTemplate struct {
+	Name        string `bson:"_id" json:"name"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+	// Placeholders are the values that are given when creating a project
+	// from the template.
+	Placeholders []ProjectTemplatePlaceholder `bson:"placeholders,omitempty" json:"placeholders,omitempty"`
+	// Settings are the project ref defaults, variables, aliases, and
+	// subscriptions of the projects created from the template. The project
+	// ref's ID and identifier are set when the project is created.
+	Settings    ProjectSettings `bson:"settings" json:"settings"`
+	LastUpdated time.Time       `bson:"last_updated" json:"last_updated"`
+	UpdatedBy   string          `bson:"updated_by" json:"updated_by"`
+}
+
+// ProjectTemplatePlaceholder is a value that is given when creating a project
+// from a template.
+type ProjectTemplatePlaceholder struct {
+	Name        string `bson:"name" json:"name"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+	// Default is the value used if none is given. A placeholder without a
+	// default must be given a value.
+	Default string `bson:"default,omitempty" json:"default,omitempty"`
+}
+
+// FindProjectTemplate returns the project template with the given name, or nil
+// if it does not exist.
+func FindProjectTemplate(name string) (*ProjectTemplate, error) {
+	template := &ProjectTemplate{}
+	err := db.FindOneQ(ProjectTemplateCollection, db.Query(bson.M{projectTemplateNameKey: name}), template)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project template '%s'", name)
+	}
+	return template, nil
+}
+
+// FindAllProjectTemplates returns all the project templates sorted by name.
+func FindAllProjectTemplates() ([]ProjectTemplate, error) {
+	templates := []ProjectTemplate{}
+	err := db.FindAllQ(ProjectTemplateCollection, db.Query(bson.M{}).Sort([]string{projectTemplateNameKey}), &templates)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding project templates")
+	}
+	return templates, nil
+}
+
+// Upsert creates the project template or replaces it if it already exists.
+func (t *ProjectTemplate) Upsert() error {
+	_, err := db.Upsert(ProjectTemplateCollection, bson.M{projectTemplateNameKey: t.Name}, t)
+	return errors.Wrapf(err, "upserting project template '%s'", t.Name)
+}
+
+// KeepPrivateVarValues fills in the values of the template's private variables
+// that were redacted or left empty with their values from the existing
+// template, so that a template that was read with redacted values can be
+// saved again without losing its secrets.
+func (t *ProjectTemplate) KeepPrivateVarValues(existing *ProjectTemplate) {
+	if existing == nil || t.Settings.Vars.Vars == nil {
+		return
+	}
+	for key, value := range existing.Settings.Vars.Vars {
+		if existing.Settings.Vars.PrivateVars[key] && t.Settings.Vars.PrivateVars[key] && t.Settings.Vars.Vars[key] == "" {
+			t.Settings.Vars.Vars[key] = value
+		}
+	}
+}
+
+// RemoveProjectTemplate deletes the project template with the given name.
+func RemoveProjectTemplate(name string) error {
+	err := db.Remove(ProjectTemplateCollection, bson.M{projectTemplateNameKey: name})
+	if adb.ResultsNotFound(err) {
+		return nil
+	}
+	return errors.Wrapf(err, "removing project template '%s'", name)
+}
+
+// Validate checks that the template's placeholders are well-formed and that
+// its settings only reference the placeholders it declares.
+func (t *ProjectTemplate) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(t.Name == "", "template name must be specified")
+	catcher.NewWhen(t.Settings.ProjectRef.Id != "", "template cannot set the project ID")
+	catcher.NewWhen(t.Settings.ProjectRef.Identifier != "", "template cannot set the project identifier")
+
+	declared := map[string]bool{ProjectTemplateIdentifierPlaceholder: true}
+	for _, p := range t.Placeholders {
+		if !projectTemplatePlaceholderRegex.MatchString(p.Name) {
+			catcher.Errorf("invalid placeholder name '%s'", p.Name)
+			continue
+		}
+		if declared[p.Name] {
+			catcher.Errorf("placeholder '%s' is declared more than once or is reserved", p.Name)
+			continue
+		}
+		declared[p.Name] = true
+	}
+
+	settings, err := t.copySettings()
+	if err != nil {
+		return errors.Wrap(err, "copying template settings")
+	}
+	undeclared := map[string]bool{}
+	err = walkProjectTemplateStrings(reflect.ValueOf(settings).Elem(), func(s string) (string, error) {
+		catcher.Wrapf(util.ValidateExpansionTransforms(s), "invalid placeholder in '%s'", s)
+		for _, name := range util.ExpansionNames(s) {
+			if !declared[name] {
+				undeclared[name] = true
+			}
+		}
+		return s, nil
+	})
+	catcher.Add(err)
+	names := make([]string, 0, len(undeclared))
+	for name := range undeclared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		catcher.Errorf("placeholder '%s' is used but not declared", name)
+	}
+
+	return catcher.Resolve()
+}
+
+// Render returns the settings for a new project with the given identifier
+// created from the template, with the placeholders filled in from the given
+// values. The new project reuses the ID of a hidden project for the same
+// branch if there is one, the same as adding the project directly.
+func (t *ProjectTemplate) Render(identifier string, values map[string]string) (*ProjectSettings, error) {
+	if identifier == "" {
+		return nil, errors.New("project identifier must be specified")
+	}
+	expansions := util.NewExpansions(map[string]string{ProjectTemplateIdentifierPlaceholder: identifier})
+	catcher := grip.NewBasicCatcher()
+	declared := map[string]bool{}
+	for _, p := range t.Placeholders {
+		declared[p.Name] = true
+		val, ok := values[p.Name]
+		if !ok {
+			val = p.Default
+		}
+		if val == "" {
+			catcher.Errorf("placeholder '%s' must be given a value", p.Name)
+			continue
+		}
+		expansions.Put(p.Name, val)
+	}
+	for name := range values {
+		catcher.ErrorfWhen(!declared[name], "'%s' is not a placeholder in template '%s'", name, t.Name)
+	}
+	if catcher.HasErrors() {
+		return nil, catcher.Resolve()
+	}
+
+	settings, err := t.copySettings()
+	if err != nil {
+		return nil, errors.Wrap(err, "copying template settings")
+	}
+	if err = walkProjectTemplateStrings(reflect.ValueOf(settings).Elem(), expansions.ExpandString); err != nil {
+		return nil, errors.Wrap(err, "filling in placeholders")
+	}
+
+	pRef := &settings.ProjectRef
+	pRef.Identifier = identifier
+	pRef.Id = mgobson.NewObjectId().Hex()
+	if pRef.Owner != "" && pRef.Repo != "" && pRef.Branch != "" {
+		hidden, err := FindHiddenProjectRefByOwnerRepoAndBranch(pRef.Owner, pRef.Repo, pRef.Branch)
+		if err != nil {
+			return nil, errors.Wrap(err, "finding hidden project")
+		}
+		if hidden != nil {
+			pRef.Id = hidden.Id
+		}
+	}
+
+	settings.Vars.Id = pRef.Id
+	for i := range settings.Aliases {
+		settings.Aliases[i].ID = ""
+		settings.Aliases[i].ProjectID = pRef.Id
+	}
+	for i := range settings.Subscriptions {
+		sub := &settings.Subscriptions[i]
+		sub.ID = ""
+		sub.Owner = pRef.Id
+		sub.OwnerType = event.OwnerTypeProject
+		sub.Filter.Project = pRef.Id
+		hasProjectSelector := false
+		for j := range sub.Selectors {
+			if sub.Selectors[j].Type == event.SelectorProject {
+				sub.Selectors[j].Data = pRef.Id
+				hasProjectSelector = true
+			}
+		}
+		if !hasProjectSelector {
+			sub.Selectors = append(sub.Selectors, event.Selector{Type: event.SelectorProject, Data: pRef.Id})
+		}
+	}
+
+	return settings, nil
+}
+
+// copySettings returns a deep copy of the template's settings.
+func (t *ProjectTemplate) copySettings() (*ProjectSettings, error) {
+	raw, err := mgobson.Marshal(t.Settings)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling settings")
+	}
+	settings := &ProjectSettings{}
+	if err = mgobson.Unmarshal(raw, settings); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling settings")
+	}
+	return settings, nil
+}
+
+// walkProjectTemplateStrings replaces every settable string reachable from
+// the value, including map values and strings inside interfaces, with the
+// result of the function. Map keys are left as is.
+func walkProjectTemplateStrings(v reflect.Value, replace func(string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		replaced, err := replace(v.String())
+		if err != nil {
+			return err
+		}
+		v.SetString(replaced)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return walkProjectTemplateStrings(v.Elem(), replace)
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return nil
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		if err := walkProjectTemplateStrings(elem, replace); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).CanSet() {
+				continue
+			}
+			if err := walkProjectTemplateStrings(v.Field(i), replace); err != nil {
+				return errors.Wrapf(err, "field '%s'", v.Type().Field(i).Name)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkProjectTemplateStrings(v.Index(i), replace); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			if err := walkProjectTemplateStrings(elem, replace); err != nil {
+				return errors.Wrapf(err, "key '%v'", key.Interface())
+			}
+			v.SetMapIndex(key, elem)
+		}
+	}
+	return nil
+}
+
+// AddProjectWithSettings adds the project along with its variables, aliases,
+// and subscriptions. The project ref is added last so that the project does
+// not exist until everything else is saved, and everything that was saved is
+// removed if any part fails.
+func AddProjectWithSettings(settings *ProjectSettings, creator *user.DBUser) (err error) {
+	pRef := &settings.ProjectRef
+	if pRef.Id == "" {
+		return errors.New("project ID must be set")
+	}
+	existingVars, err := FindOneProjectVars(pRef.Id)
+	if err != nil {
+		return errors.Wrapf(err, "finding existing variables for project '%s'", pRef.Id)
+	}
+
+	var addedAliases, addedSubscriptions []string
+	added := false
+	defer func() {
+		if err == nil || added {
+			return
+		}
+		catcher := grip.NewBasicCatcher()
+		for _, id := range addedAliases {
+			catcher.Add(RemoveProjectAlias(id))
+		}
+		for _, id := range addedSubscriptions {
+			catcher.Add(event.RemoveSubscription(id))
+		}
+		if existingVars == nil {
+			catcher.Add(db.Remove(ProjectVarsCollection, bson.M{projectVarIdKey: pRef.Id}))
+		} else {
+			_, upsertErr := existingVars.Upsert()
+			catcher.Add(upsertErr)
+		}
+		grip.Error(message.WrapError(catcher.Resolve(), message.Fields{
+			"message": "could not roll back settings for project that failed to be added",
+			"project": pRef.Id,
+		}))
+	}()
+
+	settings.Vars.Id = pRef.Id
+	if _, err = settings.Vars.Upsert(); err != nil {
+		return errors.Wrapf(err, "saving variables for project '%s'", pRef.Id)
+	}
+	for i := range settings.Aliases {
+		settings.Aliases[i].ProjectID = pRef.Id
+		if err = settings.Aliases[i].Upsert(); err != nil {
+			return errors.Wrapf(err, "saving alias '%s' for project '%s'", settings.Aliases[i].Alias, pRef.Id)
+		}
+		addedAliases = append(addedAliases, settings.Aliases[i].ID.Hex())
+	}
+	for i := range settings.Subscriptions {
+		settings.Subscriptions[i].Owner = pRef.Id
+		if err = settings.Subscriptions[i].Upsert(); err != nil {
+			return errors.Wrapf(err, "saving subscription for project '%s'", pRef.Id)
+		}
+		addedSubscriptions = append(addedSubscriptions, settings.Subscriptions[i].ID)
+	}
+
+	if err = pRef.Add(creator); err != nil {
+		// The project ref may have been saved even though its permissions
+		// could not be set up, in which case the project should keep its
+		// settings.
+		existing, findErr := FindBranchProjectRef(pRef.Id)
+		added = findErr == nil && existing != nil && existing.Identifier == pRef.Identifier
+		return errors.Wrapf(err, "adding project '%s'", pRef.Identifier)
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestProjectTemplate() ProjectTemplate {
+	return ProjectTemplate{
+		Name: "release",
+		Placeholders: []ProjectTemplatePlaceholder{
+			{Name: "branch"},
+			{Name: "team", Default: "server"},
+		},
+		Settings: ProjectSettings{
+			ProjectRef: ProjectRef{
+				Owner:       "evergreen-ci",
+				Repo:        "evergreen",
+				Branch:      "${branch}",
+				DisplayName: "${team::upper} ${branch}",
+				RemotePath:  "evergreen.yml",
+			},
+			Vars: ProjectVars{
+				Vars:        map[string]string{"branch_name": "${branch}", "project": "${identifier}", "secret": "shh"},
+				PrivateVars: map[string]bool{"secret": true},
+			},
+			Aliases: []ProjectAlias{
+				{Alias: evergreen.GithubPRAlias, Variant: "${team}-.*", Task: ".*"},
+			},
+			Subscriptions: []event.Subscription{
+				{
+					ResourceType: event.ResourceTypeTask,
+					Trigger:      "outcome",
+					Selectors:    []event.Selector{{Type: event.SelectorProject, Data: "placeholder"}},
+					Subscriber: event.Subscriber{
+						Type:   event.EmailSubscriberType,
+						Target: "${team}@example.com",
+					},
+					OwnerType: event.OwnerTypeProject,
+				},
+			},
+		},
+	}
+}
+
+func TestProjectTemplateValidate(t *testing.T) {
+	t.Run("Succeeds", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		assert.NoError(t, template.Validate())
+	})
+	t.Run("FailsWithUndeclaredPlaceholder", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		template.Settings.Vars.Vars["other"] = "${version}"
+		err := template.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "'version'")
+	})
+	t.Run("FailsWithDuplicatePlaceholder", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		template.Placeholders = append(template.Placeholders, ProjectTemplatePlaceholder{Name: "branch"})
+		assert.Error(t, template.Validate())
+	})
+	t.Run("FailsWithReservedPlaceholder", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		template.Placeholders = append(template.Placeholders, ProjectTemplatePlaceholder{Name: ProjectTemplateIdentifierPlaceholder})
+		assert.Error(t, template.Validate())
+	})
+	t.Run("FailsWithInvalidTransform", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		template.Settings.ProjectRef.DisplayName = "${branch|nonexistent}"
+		assert.Error(t, template.Validate())
+	})
+	t.Run("FailsWithIdentifier", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		template.Settings.ProjectRef.Identifier = "identifier"
+		assert.Error(t, template.Validate())
+	})
+}
+
+func TestProjectTemplateKeepPrivateVarValues(t *testing.T) {
+	existing := makeTestProjectTemplate()
+
+	t.Run("KeepsRedactedValues", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		template.Settings.Vars = *template.Settings.Vars.RedactPrivateVars()
+		require.Empty(t, template.Settings.Vars.Vars["secret"])
+
+		template.KeepPrivateVarValues(&existing)
+		assert.Equal(t, "shh", template.Settings.Vars.Vars["secret"])
+		assert.Equal(t, "${branch}", template.Settings.Vars.Vars["branch_name"])
+	})
+	t.Run("UsesNewValues", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		template.Settings.Vars.Vars["secret"] = "new"
+
+		template.KeepPrivateVarValues(&existing)
+		assert.Equal(t, "new", template.Settings.Vars.Vars["secret"])
+	})
+	t.Run("DoesNotKeepRemovedOrPublicVars", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		delete(template.Settings.Vars.Vars, "secret")
+		template.Settings.Vars.Vars["other"] = ""
+		template.Settings.Vars.PrivateVars = map[string]bool{"other": true}
+
+		template.KeepPrivateVarValues(&existing)
+		_, ok := template.Settings.Vars.Vars["secret"]
+		assert.False(t, ok)
+		assert.Empty(t, template.Settings.Vars.Vars["other"])
+	})
+	t.Run("NoopWithoutExistingTemplate", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		template.Settings.Vars.Vars["secret"] = ""
+
+		template.KeepPrivateVarValues(nil)
+		assert.Empty(t, template.Settings.Vars.Vars["secret"])
+	})
+}
+
+func TestProjectTemplateRender(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ProjectRefCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection))
+	}()
+
+	t.Run("FillsInPlaceholders", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		settings, err := template.Render("release-1.0", map[string]string{"branch": "v1.0"})
+		require.NoError(t, err)
+
+		pRef := settings.ProjectRef
+		assert.NotEmpty(t, pRef.Id)
+		assert.Equal(t, "release-1.0", pRef.Identifier)
+		assert.Equal(t, "v1.0", pRef.Branch)
+		assert.Equal(t, "SERVER v1.0", pRef.DisplayName)
+		assert.Equal(t, pRef.Id, settings.Vars.Id)
+		assert.Equal(t, map[string]string{"branch_name": "v1.0", "project": "release-1.0", "secret": "shh"}, settings.Vars.Vars)
+		require.Len(t, settings.Aliases, 1)
+		assert.Equal(t, "server-.*", settings.Aliases[0].Variant)
+		assert.Equal(t, pRef.Id, settings.Aliases[0].ProjectID)
+		require.Len(t, settings.Subscriptions, 1)
+		sub := settings.Subscriptions[0]
+		assert.Equal(t, pRef.Id, sub.Owner)
+		assert.Equal(t, pRef.Id, sub.Filter.Project)
+		assert.Equal(t, []event.Selector{{Type: event.SelectorProject, Data: pRef.Id}}, sub.Selectors)
+		assert.Equal(t, "server@example.com", sub.Subscriber.Target)
+
+		// The template itself is unchanged.
+		assert.Equal(t, "${branch}", template.Settings.ProjectRef.Branch)
+		assert.Equal(t, "${branch}", template.Settings.Vars.Vars["branch_name"])
+	})
+	t.Run("OverridesDefaults", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		settings, err := template.Render("release-1.0", map[string]string{"branch": "v1.0", "team": "tools"})
+		require.NoError(t, err)
+		assert.Equal(t, "TOOLS v1.0", settings.ProjectRef.DisplayName)
+	})
+	t.Run("FailsWithMissingValue", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		_, err := template.Render("release-1.0", nil)
+		assert.Error(t, err)
+	})
+	t.Run("FailsWithUnknownValue", func(t *testing.T) {
+		template := makeTestProjectTemplate()
+		_, err := template.Render("release-1.0", map[string]string{"branch": "v1.0", "other": "value"})
+		assert.Error(t, err)
+	})
+	t.Run("ReusesHiddenProjectID", func(t *testing.T) {
+		hidden := ProjectRef{Id: "hidden", Owner: "evergreen-ci", Repo: "evergreen", Branch: "v2.0", Hidden: utility.TruePtr()}
+		require.NoError(t, hidden.Insert())
+
+		template := makeTestProjectTemplate()
+		settings, err := template.Render("release-2.0", map[string]string{"branch": "v2.0"})
+		require.NoError(t, err)
+		assert.Equal(t, hidden.Id, settings.ProjectRef.Id)
+	})
+}
+
+func TestAddProjectWithSettings(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	collections := []string{ProjectRefCollection, ProjectVarsCollection, ProjectAliasCollection, event.SubscriptionsCollection,
+		user.Collection, evergreen.ScopeCollection, evergreen.RoleCollection}
+	_ = testutil.NewEnvironment(ctx, t)
+
+	for tName, tCase := range map[string]func(t *testing.T, settings *ProjectSettings, u *user.DBUser){
+		"AddsProjectAndSettings": func(t *testing.T, settings *ProjectSettings, u *user.DBUser) {
+			require.NoError(t, AddProjectWithSettings(settings, u))
+
+			pRef, err := FindBranchProjectRef(settings.ProjectRef.Id)
+			require.NoError(t, err)
+			require.NotNil(t, pRef)
+			assert.Equal(t, "release-1.0", pRef.Identifier)
+			assert.False(t, pRef.IsEnabled())
+
+			vars, err := FindOneProjectVars(pRef.Id)
+			require.NoError(t, err)
+			require.NotNil(t, vars)
+			assert.Equal(t, "v1.0", vars.Vars["branch_name"])
+
+			aliases, err := FindAliasesForProjectFromDb(pRef.Id)
+			require.NoError(t, err)
+			assert.Len(t, aliases, 1)
+
+			subs, err := event.FindSubscriptionsByOwner(pRef.Id, event.OwnerTypeProject)
+			require.NoError(t, err)
+			assert.Len(t, subs, 1)
+		},
+		"RemovesSettingsWhenProjectCannotBeAdded": func(t *testing.T, settings *ProjectSettings, u *user.DBUser) {
+			existing := ProjectRef{Id: settings.ProjectRef.Id, Identifier: "existing"}
+			require.NoError(t, existing.Insert())
+
+			assert.Error(t, AddProjectWithSettings(settings, u))
+
+			vars, err := FindOneProjectVars(settings.ProjectRef.Id)
+			require.NoError(t, err)
+			assert.Nil(t, vars)
+
+			aliases, err := FindAliasesForProjectFromDb(settings.ProjectRef.Id)
+			require.NoError(t, err)
+			assert.Empty(t, aliases)
+
+			subs, err := event.FindSubscriptionsByOwner(settings.ProjectRef.Id, event.OwnerTypeProject)
+			require.NoError(t, err)
+			assert.Empty(t, subs)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(collections...))
+			defer func() {
+				assert.NoError(t, db.ClearCollections(collections...))
+			}()
+			require.NoError(t, db.CreateCollections(evergreen.ScopeCollection))
+			u := &user.DBUser{Id: "me"}
+			require.NoError(t, u.Insert())
+
+			template := makeTestProjectTemplate()
+			settings, err := template.Render("release-1.0", map[string]string{"branch": "v1.0"})
+			require.NoError(t, err)
+
+			tCase(t, settings, u)
+		})
+	}
+}
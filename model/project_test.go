@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -342,12 +343,15 @@ buildvariants:
 	assert.NoError(err)
 	expansions, err := PopulateExpansions(taskDoc, &h, oauthToken)
 	assert.NoError(err)
-	assert.Len(map[string]string(expansions), 23)
+	assert.Len(map[string]string(expansions), 26)
 	assert.Equal("0", expansions.Get("execution"))
 	assert.Equal("v1", expansions.Get("version_id"))
 	assert.Equal("t1", expansions.Get("task_id"))
 	assert.Equal("magical task", expansions.Get("task_name"))
 	assert.Equal("b1", expansions.Get("build_id"))
+	assert.Equal(evergreen.TraceIDFor("v1").String(), expansions.Get(TraceIDExpansion))
+	assert.Equal(taskSpanID("t1", 0).String(), expansions.Get(ParentSpanExpansion))
+	assert.Equal(fmt.Sprintf("00-%s-%s-01", evergreen.TraceIDFor("v1"), taskSpanID("t1", 0)), expansions.Get(TraceparentExpansion))
 	assert.Equal("magic", expansions.Get("build_variant"))
 	assert.Equal("0ed7cbd33263043fa95aadb3f6068ef8d076854a", expansions.Get("revision"))
 	assert.Equal("0ed7cbd33263043fa95aadb3f6068ef8d076854a", expansions.Get("github_commit"))
@@ -380,7 +384,7 @@ buildvariants:
 
 	expansions, err = PopulateExpansions(taskDoc, &h, oauthToken)
 	assert.NoError(err)
-	assert.Len(map[string]string(expansions), 24)
+	assert.Len(map[string]string(expansions), 27)
 	assert.Equal("true", expansions.Get("is_patch"))
 	assert.Equal("patch", expansions.Get("requester"))
 	assert.False(expansions.Exists("is_commit_queue"))
@@ -400,7 +404,7 @@ buildvariants:
 	require.NoError(t, p.Insert())
 	expansions, err = PopulateExpansions(taskDoc, &h, oauthToken)
 	assert.NoError(err)
-	assert.Len(map[string]string(expansions), 26)
+	assert.Len(map[string]string(expansions), 29)
 	assert.Equal("true", expansions.Get("is_patch"))
 	assert.Equal("true", expansions.Get("is_commit_queue"))
 	assert.Equal("commit queue message", expansions.Get("commit_message"))
@@ -415,7 +419,7 @@ buildvariants:
 	require.NoError(t, p.Insert())
 	expansions, err = PopulateExpansions(taskDoc, &h, oauthToken)
 	assert.NoError(err)
-	assert.Len(map[string]string(expansions), 27)
+	assert.Len(map[string]string(expansions), 30)
 	assert.Equal("true", expansions.Get("is_patch"))
 	assert.Equal("github_pr", expansions.Get("requester"))
 	assert.False(expansions.Exists("is_commit_queue"))
@@ -440,7 +444,7 @@ buildvariants:
 
 	expansions, err = PopulateExpansions(taskDoc, &h, oauthToken)
 	assert.NoError(err)
-	assert.Len(map[string]string(expansions), 27)
+	assert.Len(map[string]string(expansions), 30)
 	assert.Equal("github_pr", expansions.Get("requester"))
 	assert.Equal("true", expansions.Get("is_patch"))
 	assert.Equal("evergreen", expansions.Get("github_repo"))
@@ -465,12 +469,21 @@ buildvariants:
 	taskDoc.TriggerType = ProjectTriggerLevelTask
 	expansions, err = PopulateExpansions(taskDoc, &h, oauthToken)
 	assert.NoError(err)
-	assert.Len(map[string]string(expansions), 35)
+	assert.Len(map[string]string(expansions), 38)
 	assert.Equal(taskDoc.TriggerID, expansions.Get("trigger_event_identifier"))
 	assert.Equal(taskDoc.TriggerType, expansions.Get("trigger_event_type"))
 	assert.Equal(upstreamTask.Revision, expansions.Get("trigger_revision"))
 	assert.Equal(upstreamTask.Status, expansions.Get("trigger_status"))
 	assert.Equal(upstreamProject.Branch, expansions.Get("trigger_branch"))
+
+	assert.NoError(VersionUpdateOne(bson.M{VersionIdKey: v.Id}, bson.M{
+		"$set": bson.M{VersionExpansionOverridesKey: map[string]string{"cake": "cheesecake", "flavor": "lemon"}},
+	}))
+	expansions, err = PopulateExpansions(taskDoc, &h, oauthToken)
+	assert.NoError(err)
+	assert.Len(map[string]string(expansions), 39)
+	assert.Equal("cheesecake", expansions.Get("cake"))
+	assert.Equal("lemon", expansions.Get("flavor"))
 }
 
 type projectSuite struct {
@@ -691,6 +704,18 @@ func (s *projectSuite) SetupTest() {
 	}
 }
 
+func (s *projectSuite) TestResolveAliasVariantTasks() {
+	vts, err := s.project.ResolveAliasVariantTasks([]ProjectAlias{s.aliases[8]}, evergreen.PatchVersionRequester, false)
+	s.Require().NoError(err)
+	s.Require().Len(vts, 1)
+	s.Equal("bv_2", vts[0].Variant)
+	s.ElementsMatch([]string{"a_task_1", "a_task_2"}, vts[0].Tasks)
+	s.Empty(vts[0].DisplayTasks)
+
+	_, err = s.project.ResolveAliasVariantTasks([]ProjectAlias{{Alias: "bad", Variant: "[", Task: ".*"}}, evergreen.PatchVersionRequester, false)
+	s.Error(err)
+}
+
 func (s *projectSuite) TestAliasResolution() {
 	// test that .* on variants and tasks selects everything
 	pairs, displayTaskPairs, err := s.project.BuildProjectTVPairsWithAlias([]ProjectAlias{s.aliases[0]})
@@ -1469,60 +1494,60 @@ func (s *FindProjectsSuite) TearDownSuite() {
 }
 
 func (s *FindProjectsSuite) TestFetchTooManyAsc() {
-	projects, err := FindProjects("", 8, 1)
+	projects, err := FindProjects("", 8, 1, false)
 	s.NoError(err)
 	s.NotNil(projects)
 	s.Len(projects, 7)
 }
 
 func (s *FindProjectsSuite) TestFetchTooManyDesc() {
-	projects, err := FindProjects("zzz", 8, -1)
+	projects, err := FindProjects("zzz", 8, -1, false)
 	s.NoError(err)
 	s.NotNil(projects)
 	s.Len(projects, 7)
 }
 
 func (s *FindProjectsSuite) TestFetchExactNumber() {
-	projects, err := FindProjects("", 3, 1)
+	projects, err := FindProjects("", 3, 1, false)
 	s.NoError(err)
 	s.NotNil(projects)
 	s.Len(projects, 3)
 }
 
 func (s *FindProjectsSuite) TestFetchTooFewAsc() {
-	projects, err := FindProjects("", 2, 1)
+	projects, err := FindProjects("", 2, 1, false)
 	s.NoError(err)
 	s.NotNil(projects)
 	s.Len(projects, 2)
 }
 
 func (s *FindProjectsSuite) TestFetchTooFewDesc() {
-	projects, err := FindProjects("zzz", 2, -1)
+	projects, err := FindProjects("zzz", 2, -1, false)
 	s.NoError(err)
 	s.NotNil(projects)
 	s.Len(projects, 2)
 }
 
 func (s *FindProjectsSuite) TestFetchKeyWithinBoundAsc() {
-	projects, err := FindProjects("projectB", 1, 1)
+	projects, err := FindProjects("projectB", 1, 1, false)
 	s.NoError(err)
 	s.Len(projects, 1)
 }
 
 func (s *FindProjectsSuite) TestFetchKeyWithinBoundDesc() {
-	projects, err := FindProjects("projectD", 1, -1)
+	projects, err := FindProjects("projectD", 1, -1, false)
 	s.NoError(err)
 	s.Len(projects, 1)
 }
 
 func (s *FindProjectsSuite) TestFetchKeyOutOfBoundAsc() {
-	projects, err := FindProjects("zzz", 1, 1)
+	projects, err := FindProjects("zzz", 1, 1, false)
 	s.NoError(err)
 	s.Len(projects, 0)
 }
 
 func (s *FindProjectsSuite) TestFetchKeyOutOfBoundDesc() {
-	projects, err := FindProjects("aaa", 1, -1)
+	projects, err := FindProjects("aaa", 1, -1, false)
 	s.NoError(err)
 	s.Len(projects, 0)
 }
@@ -2434,3 +2459,22 @@ tasks:
 	assert.NoError(t, err)
 	assert.Len(t, variantsAndTasks.Variants["bv1"].Tasks, 1)
 }
+
+func TestBuildVariantArtifactNamespace(t *testing.T) {
+	bv := BuildVariant{Name: "bv", Expansions: map[string]string{"foo": "bar"}}
+	assert.Equal(t, "bv", bv.GetArtifactNamespace())
+	expansions := bv.GetExpansions()
+	assert.Equal(t, "bv", expansions.Get(ArtifactNamespaceExpansion))
+	assert.Equal(t, "bar", expansions.Get("foo"))
+	assert.NotContains(t, bv.Expansions, ArtifactNamespaceExpansion)
+
+	bv.Expansions[ArtifactNamespaceExpansion] = "from_expansion"
+	assert.Equal(t, "from_expansion", bv.GetArtifactNamespace())
+	expansions = bv.GetExpansions()
+	assert.Equal(t, "from_expansion", expansions.Get(ArtifactNamespaceExpansion))
+
+	bv.ArtifactNamespace = "linux/x86_64"
+	assert.Equal(t, "linux/x86_64", bv.GetArtifactNamespace())
+	expansions = bv.GetExpansions()
+	assert.Equal(t, "linux/x86_64", expansions.Get(ArtifactNamespaceExpansion))
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ProjectTriggerEdge is a project trigger definition that creates versions
+// in the downstream project when the upstream project's tasks or builds
+// finish.
+type ProjectTriggerEdge struct {
+	UpstreamProject   string `json:"upstream_project"`
+	DownstreamProject string `json:"downstream_project"`
+	DefinitionID      string `json:"definition_id"`
+	Level             string `json:"level"`
+}
+
+// ProjectTriggerGraph is the graph of cross-project triggers between all
+// enabled projects.
+type ProjectTriggerGraph struct {
+	// Projects are the IDs of the projects that appear in at least one edge.
+	Projects []string
+	Edges    []ProjectTriggerEdge
+}
+
+// FindProjectTriggerGraph builds the trigger graph from every enabled
+// project's triggers, including triggers inherited from repo settings.
+func FindProjectTriggerGraph() (*ProjectTriggerGraph, error) {
+	projectRefs, err := FindAllMergedProjectRefs()
+	if err != nil {
+		return nil, errors.Wrap(err, "finding project refs")
+	}
+	return NewProjectTriggerGraph(projectRefs), nil
+}
+
+// NewProjectTriggerGraph builds the trigger graph from the triggers defined
+// on the given enabled project refs.
+func NewProjectTriggerGraph(projectRefs []ProjectRef) *ProjectTriggerGraph {
+	g := &ProjectTriggerGraph{}
+	for _, pRef := range projectRefs {
+		if !pRef.IsEnabled() {
+			continue
+		}
+		g.addTriggers(pRef.Id, pRef.Triggers)
+	}
+	return g
+}
+
+// ReplaceTriggers replaces the triggers for the downstream project with the
+// given triggers, so that a change can be checked before it is saved.
+func (g *ProjectTriggerGraph) ReplaceTriggers(downstreamProject string, triggers []TriggerDefinition) {
+	edges := []ProjectTriggerEdge{}
+	for _, edge := range g.Edges {
+		if edge.DownstreamProject != downstreamProject {
+			edges = append(edges, edge)
+		}
+	}
+	g.Edges = edges
+	g.Projects = nil
+	for _, edge := range g.Edges {
+		g.addProject(edge.UpstreamProject)
+		g.addProject(edge.DownstreamProject)
+	}
+	g.addTriggers(downstreamProject, triggers)
+}
+
+func (g *ProjectTriggerGraph) addTriggers(downstreamProject string, triggers []TriggerDefinition) {
+	for _, t := range triggers {
+		g.Edges = append(g.Edges, ProjectTriggerEdge{
+			UpstreamProject:   t.Project,
+			DownstreamProject: downstreamProject,
+			DefinitionID:      t.DefinitionID,
+			Level:             t.Level,
+		})
+		g.addProject(t.Project)
+		g.addProject(downstreamProject)
+	}
+	sort.Strings(g.Projects)
+}
+
+func (g *ProjectTriggerGraph) addProject(projectId string) {
+	for _, p := range g.Projects {
+		if p == projectId {
+			return
+		}
+	}
+	g.Projects = append(g.Projects, projectId)
+}
+
+// FindCycle returns a chain of triggers that starts and ends at the given
+// project, or nil if triggering the project can never lead back to itself.
+func (g *ProjectTriggerGraph) FindCycle(projectId string) []string {
+	downstream := map[string][]string{}
+	for _, edge := range g.Edges {
+		downstream[edge.UpstreamProject] = append(downstream[edge.UpstreamProject], edge.DownstreamProject)
+	}
+
+	visited := map[string]bool{}
+	var search func(current string, path []string) []string
+	search = func(current string, path []string) []string {
+		for _, next := range downstream[current] {
+			if next == projectId {
+				return append(path, next)
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			if cycle := search(next, append(path, next)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return search(projectId, []string{projectId})
+}
+
+// ValidateProjectTriggerCycles returns an error if saving the given triggers
+// for the project would create a chain of triggers that leads back to the
+// project.
+func ValidateProjectTriggerCycles(projectId string, triggers []TriggerDefinition) error {
+	g, err := FindProjectTriggerGraph()
+	if err != nil {
+		return errors.Wrap(err, "finding project trigger graph")
+	}
+	g.ReplaceTriggers(projectId, triggers)
+	if cycle := g.FindCycle(projectId); cycle != nil {
+		return errors.Errorf("triggers would create a cycle between projects: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
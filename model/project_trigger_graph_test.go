@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectTriggerGraph(t *testing.T) {
+	projectRefs := []ProjectRef{
+		{
+			Id:       "b",
+			Enabled:  utility.TruePtr(),
+			Triggers: []TriggerDefinition{{Project: "a", DefinitionID: "a-to-b", Level: ProjectTriggerLevelTask}},
+		},
+		{
+			Id:       "c",
+			Enabled:  utility.TruePtr(),
+			Triggers: []TriggerDefinition{{Project: "b", DefinitionID: "b-to-c", Level: ProjectTriggerLevelBuild}},
+		},
+		{
+			Id:       "disabled",
+			Enabled:  utility.FalsePtr(),
+			Triggers: []TriggerDefinition{{Project: "c", DefinitionID: "c-to-disabled"}},
+		},
+	}
+
+	for tName, tCase := range map[string]func(t *testing.T, g *ProjectTriggerGraph){
+		"IgnoresDisabledProjects": func(t *testing.T, g *ProjectTriggerGraph) {
+			assert.Equal(t, []string{"a", "b", "c"}, g.Projects)
+			assert.Len(t, g.Edges, 2)
+		},
+		"AcyclicGraphHasNoCycle": func(t *testing.T, g *ProjectTriggerGraph) {
+			for _, p := range g.Projects {
+				assert.Nil(t, g.FindCycle(p))
+			}
+		},
+		"DetectsTransitiveCycle": func(t *testing.T, g *ProjectTriggerGraph) {
+			g.ReplaceTriggers("a", []TriggerDefinition{{Project: "c", DefinitionID: "c-to-a"}})
+			assert.Equal(t, []string{"a", "b", "c", "a"}, g.FindCycle("a"))
+		},
+		"ReplacingTriggersRemovesCycle": func(t *testing.T, g *ProjectTriggerGraph) {
+			g.ReplaceTriggers("a", []TriggerDefinition{{Project: "c", DefinitionID: "c-to-a"}})
+			g.ReplaceTriggers("a", nil)
+			assert.Nil(t, g.FindCycle("a"))
+			assert.Len(t, g.Edges, 2)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			tCase(t, NewProjectTriggerGraph(projectRefs))
+		})
+	}
+}
@@ -2,8 +2,10 @@ package model
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
@@ -22,6 +24,7 @@ var (
 	projectVarsMapKey   = bsonutil.MustHaveTag(ProjectVars{}, "Vars")
 	privateVarsMapKey   = bsonutil.MustHaveTag(ProjectVars{}, "PrivateVars")
 	adminOnlyVarsMapKey = bsonutil.MustHaveTag(ProjectVars{}, "AdminOnlyVars")
+	restrictedVarsKey   = bsonutil.MustHaveTag(ProjectVars{}, "RestrictedVars")
 )
 
 const (
@@ -48,6 +51,48 @@ type ProjectVars struct {
 
 	// AdminOnlyVars keeps track of variables that are only accessible by project admins
 	AdminOnlyVars map[string]bool `bson:"admin_only_vars" json:"admin_only_vars"`
+
+	// RestrictedVars keeps track of variables that are only given to the
+	// tasks that match their restriction.
+	RestrictedVars map[string]ProjectVarRestriction `bson:"restricted_vars,omitempty" json:"restricted_vars,omitempty"`
+}
+
+// ProjectVarRestriction limits a variable to the tasks whose names and build
+// variants match the regexps. An empty regexp matches everything.
+type ProjectVarRestriction struct {
+	TaskRegex    string `bson:"task_regex,omitempty" json:"task_regex,omitempty"`
+	VariantRegex string `bson:"variant_regex,omitempty" json:"variant_regex,omitempty"`
+}
+
+// Validate checks that the restriction's regexps compile and that it
+// restricts something.
+func (r ProjectVarRestriction) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(r.TaskRegex == "" && r.VariantRegex == "", "restriction must specify a task or variant regexp")
+	if _, err := regexp.Compile(r.TaskRegex); err != nil {
+		catcher.Wrapf(err, "invalid task regexp '%s'", r.TaskRegex)
+	}
+	if _, err := regexp.Compile(r.VariantRegex); err != nil {
+		catcher.Wrapf(err, "invalid variant regexp '%s'", r.VariantRegex)
+	}
+	return catcher.Resolve()
+}
+
+// Matches returns whether a task with the given name in the given build
+// variant may use the variable. Invalid regexps match nothing.
+func (r ProjectVarRestriction) Matches(taskName, variant string) bool {
+	return matchesVarRestrictionRegex(r.TaskRegex, taskName) && matchesVarRestrictionRegex(r.VariantRegex, variant)
+}
+
+func matchesVarRestrictionRegex(expr, s string) bool {
+	if expr == "" {
+		return true
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
 }
 
 type AWSSSHKey struct {
@@ -102,6 +147,55 @@ func FindMergedProjectVars(projectID string) (*ProjectVars, error) {
 	return projectVars, nil
 }
 
+// FindExpansionVarsForTask returns the project variables and parameters that
+// the task can use. From lowest to highest precedence, they are the project
+// variables, the defaults of the project's parameters, the parameters given for
+// the version, and the expansions overridden when the version was created.
+func FindExpansionVarsForTask(t *task.Task) (*apimodels.ExpansionVars, error) {
+	res := &apimodels.ExpansionVars{
+		Vars:        map[string]string{},
+		PrivateVars: map[string]bool{},
+	}
+	projectVars, err := FindMergedProjectVars(t.Project)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding project vars")
+	}
+	if projectVars == nil {
+		return res, nil
+	}
+	res.Vars = projectVars.GetVars(t)
+	if projectVars.PrivateVars != nil {
+		res.PrivateVars = projectVars.PrivateVars
+	}
+
+	v, err := VersionFindOne(VersionById(t.Version))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding version '%s'", t.Version)
+	}
+	if v == nil {
+		return nil, errors.Errorf("version '%s' not found", t.Version)
+	}
+	projParams, err := FindParametersForVersion(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding parameters for version")
+	}
+	for _, param := range projParams {
+		// If the key doesn't exist the value will default to "" anyway; this prevents
+		// an un-specified parameter from overwriting lower-priority expansions.
+		if param.Value != "" {
+			res.Vars[param.Key] = param.Value
+		}
+	}
+	for _, param := range v.Parameters {
+		// We will overwrite empty values here since these were explicitly user-specified.
+		res.Vars[param.Key] = param.Value
+	}
+	for key, value := range v.ExpansionOverrides {
+		res.Vars[key] = value
+	}
+	return res, nil
+}
+
 func UpdateProjectVarsByValue(toReplace, replacement, username string, dryRun bool) (map[string][]string, error) {
 	catcher := grip.NewBasicCatcher()
 	matchingProjects, err := GetVarsByValue(toReplace)
@@ -212,6 +306,7 @@ func (projectVars *ProjectVars) Upsert() (*adb.ChangeInfo, error) {
 				projectVarsMapKey:   projectVars.Vars,
 				privateVarsMapKey:   projectVars.PrivateVars,
 				adminOnlyVarsMapKey: projectVars.AdminOnlyVars,
+				restrictedVarsKey:   projectVars.RestrictedVars,
 			},
 		},
 	)
@@ -229,7 +324,7 @@ func (projectVars *ProjectVars) FindAndModify(varsToDelete []string) (*adb.Chang
 	unsetUpdate := bson.M{}
 	update := bson.M{}
 	if len(projectVars.Vars) == 0 && len(projectVars.PrivateVars) == 0 &&
-		len(projectVars.AdminOnlyVars) == 0 && len(varsToDelete) == 0 {
+		len(projectVars.AdminOnlyVars) == 0 && len(projectVars.RestrictedVars) == 0 && len(varsToDelete) == 0 {
 		return nil, nil
 	}
 	for key, val := range projectVars.Vars {
@@ -241,6 +336,15 @@ func (projectVars *ProjectVars) FindAndModify(varsToDelete []string) (*adb.Chang
 	for key, val := range projectVars.AdminOnlyVars {
 		setUpdate[bsonutil.GetDottedKeyName(adminOnlyVarsMapKey, key)] = val
 	}
+	for key, val := range projectVars.RestrictedVars {
+		setUpdate[bsonutil.GetDottedKeyName(restrictedVarsKey, key)] = val
+	}
+	// A variable that is sent without a restriction is no longer restricted.
+	for key := range projectVars.Vars {
+		if _, ok := projectVars.RestrictedVars[key]; !ok {
+			unsetUpdate[bsonutil.GetDottedKeyName(restrictedVarsKey, key)] = 1
+		}
+	}
 	if len(setUpdate) > 0 {
 		update["$set"] = setUpdate
 	}
@@ -249,6 +353,7 @@ func (projectVars *ProjectVars) FindAndModify(varsToDelete []string) (*adb.Chang
 		unsetUpdate[bsonutil.GetDottedKeyName(projectVarsMapKey, val)] = 1
 		unsetUpdate[bsonutil.GetDottedKeyName(privateVarsMapKey, val)] = 1
 		unsetUpdate[bsonutil.GetDottedKeyName(adminOnlyVarsMapKey, val)] = 1
+		unsetUpdate[bsonutil.GetDottedKeyName(restrictedVarsKey, val)] = 1
 	}
 	if len(unsetUpdate) > 0 {
 		update["$unset"] = unsetUpdate
@@ -266,17 +371,33 @@ func (projectVars *ProjectVars) FindAndModify(varsToDelete []string) (*adb.Chang
 	)
 }
 
+// GetVars returns the variables that the task has access to. Admin-only
+// variables are only given to tasks activated by project admins, and
+// restricted variables are only given to tasks that match the restriction.
 func (projectVars *ProjectVars) GetVars(t *task.Task) map[string]string {
 	vars := map[string]string{}
 	isAdmin := projectVars.ShouldGetAdminOnlyVars(t)
 	for k, v := range projectVars.Vars {
-		if !projectVars.AdminOnlyVars[k] || isAdmin {
-			vars[k] = v
+		if projectVars.AdminOnlyVars[k] && !isAdmin {
+			continue
 		}
+		if restriction, ok := projectVars.RestrictedVars[k]; ok && !restriction.Matches(t.DisplayName, t.BuildVariant) {
+			continue
+		}
+		vars[k] = v
 	}
 	return vars
 }
 
+// ValidateRestrictions checks that every variable restriction is valid.
+func (projectVars *ProjectVars) ValidateRestrictions() error {
+	catcher := grip.NewBasicCatcher()
+	for key, restriction := range projectVars.RestrictedVars {
+		catcher.Wrapf(restriction.Validate(), "restriction for variable '%s'", key)
+	}
+	return catcher.Resolve()
+}
+
 func (projectVars *ProjectVars) ShouldGetAdminOnlyVars(t *task.Task) bool {
 	if utility.StringSliceContains(evergreen.SystemVersionRequesterTypes, t.Requester) {
 		return true
@@ -305,9 +426,10 @@ func (projectVars *ProjectVars) ShouldGetAdminOnlyVars(t *task.Task) bool {
 
 func (projectVars *ProjectVars) RedactPrivateVars() *ProjectVars {
 	res := &ProjectVars{
-		Vars:          map[string]string{},
-		PrivateVars:   map[string]bool{},
-		AdminOnlyVars: map[string]bool{},
+		Vars:           map[string]string{},
+		PrivateVars:    map[string]bool{},
+		AdminOnlyVars:  map[string]bool{},
+		RestrictedVars: map[string]ProjectVarRestriction{},
 	}
 	if projectVars == nil {
 		return res
@@ -333,6 +455,9 @@ func (projectVars *ProjectVars) RedactPrivateVars() *ProjectVars {
 		if val, ok := projectVars.AdminOnlyVars[k]; ok && val {
 			res.AdminOnlyVars[k] = projectVars.AdminOnlyVars[k]
 		}
+		if restriction, ok := projectVars.RestrictedVars[k]; ok {
+			res.RestrictedVars[k] = restriction
+		}
 	}
 
 	return res
@@ -364,6 +489,9 @@ func (projectVars *ProjectVars) MergeWithRepoVars(repoVars *ProjectVars) {
 	if projectVars.AdminOnlyVars == nil {
 		projectVars.AdminOnlyVars = map[string]bool{}
 	}
+	if projectVars.RestrictedVars == nil {
+		projectVars.RestrictedVars = map[string]ProjectVarRestriction{}
+	}
 	if repoVars == nil {
 		return
 	}
@@ -377,6 +505,9 @@ func (projectVars *ProjectVars) MergeWithRepoVars(repoVars *ProjectVars) {
 			if v, ok := repoVars.AdminOnlyVars[key]; ok {
 				projectVars.AdminOnlyVars[key] = v
 			}
+			if v, ok := repoVars.RestrictedVars[key]; ok {
+				projectVars.RestrictedVars[key] = v
+			}
 		}
 	}
 }
@@ -3,7 +3,9 @@ package model
 import (
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -201,6 +203,67 @@ func TestRedactPrivateVars(t *testing.T) {
 	assert.NotEqual("", projectVars.Vars["a"], "original vars should not be modified")
 }
 
+func TestGetVarsWithRestrictions(t *testing.T) {
+	require.NoError(t, db.Clear(ProjectVarsCollection))
+	defer func() {
+		assert.NoError(t, db.Clear(ProjectVarsCollection))
+	}()
+
+	vars := &ProjectVars{
+		Id:            "project",
+		Vars:          map[string]string{"normal": "1", "admin": "2", "deploy_key": "3"},
+		AdminOnlyVars: map[string]bool{"admin": true},
+		RestrictedVars: map[string]ProjectVarRestriction{
+			"deploy_key": {TaskRegex: "^deploy", VariantRegex: "^release-"},
+		},
+	}
+	require.NoError(t, vars.ValidateRestrictions())
+	_, err := vars.FindAndModify(nil)
+	require.NoError(t, err)
+	dbVars, err := FindOneProjectVars("project")
+	require.NoError(t, err)
+	require.NotNil(t, dbVars)
+	assert.Equal(t, vars.RestrictedVars, dbVars.RestrictedVars)
+
+	deployTask := &task.Task{DisplayName: "deploy_prod", BuildVariant: "release-linux", Requester: evergreen.RepotrackerVersionRequester}
+	assert.Equal(t, map[string]string{"normal": "1", "admin": "2", "deploy_key": "3"}, dbVars.GetVars(deployTask))
+	otherVariantTask := &task.Task{DisplayName: "deploy_prod", BuildVariant: "linux", Requester: evergreen.RepotrackerVersionRequester}
+	assert.Equal(t, map[string]string{"normal": "1", "admin": "2"}, dbVars.GetVars(otherVariantTask))
+	patchTask := &task.Task{DisplayName: "deploy_prod", BuildVariant: "release-linux", Requester: evergreen.PatchVersionRequester}
+	assert.Equal(t, map[string]string{"normal": "1", "deploy_key": "3"}, dbVars.GetVars(patchTask))
+
+	redacted := dbVars.RedactPrivateVars()
+	assert.Equal(t, vars.RestrictedVars, redacted.RestrictedVars)
+
+	_, err = (&ProjectVars{Id: "project", Vars: map[string]string{"deploy_key": "4"}}).FindAndModify(nil)
+	require.NoError(t, err)
+	dbVars, err = FindOneProjectVars("project")
+	require.NoError(t, err)
+	require.NotNil(t, dbVars)
+	assert.Empty(t, dbVars.RestrictedVars, "sending a variable without a restriction should lift its restriction")
+	_, err = vars.FindAndModify(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, (&ProjectVars{Id: "unrestricted", Vars: map[string]string{"normal": "1", "deploy_key": "3"}}).Insert())
+	common, err := getCommonProjectVariables([]string{"project", "unrestricted"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"normal": "1"}, common.Vars, "a variable restricted in only some projects should not be shared")
+	assert.Empty(t, common.RestrictedVars)
+
+	_, err = (&ProjectVars{Id: "project"}).FindAndModify([]string{"deploy_key"})
+	require.NoError(t, err)
+	dbVars, err = FindOneProjectVars("project")
+	require.NoError(t, err)
+	require.NotNil(t, dbVars)
+	assert.Empty(t, dbVars.RestrictedVars)
+
+	invalid := &ProjectVars{RestrictedVars: map[string]ProjectVarRestriction{
+		"a": {},
+		"b": {TaskRegex: "("},
+	}}
+	assert.Error(t, invalid.ValidateRestrictions())
+}
+
 func TestGetVarsByValue(t *testing.T) {
 	assert := assert.New(t)
 
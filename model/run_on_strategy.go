@@ -0,0 +1,131 @@
+package model
+
+import (
+	"hash/fnv"
+	"math/rand"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RunOnStrategyFirst runs the task on the first distro in run_on. The
+	// other distros are only used if hosts in the first distro are not
+	// available. This is the default strategy.
+	RunOnStrategyFirst = "first"
+	// RunOnStrategyRoundRobin rotates the distro that a task runs on from one
+	// version to the next. Tasks in the same version are offset from one
+	// another by their names so that they are spread across the distros.
+	RunOnStrategyRoundRobin = "round_robin"
+	// RunOnStrategyWeighted runs the task on a randomly selected distro,
+	// favoring the distros with the shortest task queues.
+	RunOnStrategyWeighted = "weighted"
+)
+
+// ValidRunOnStrategies are the strategies for selecting the distro that a task
+// runs on from its run_on distros.
+var ValidRunOnStrategies = []string{RunOnStrategyFirst, RunOnStrategyRoundRobin, RunOnStrategyWeighted}
+
+// getRunOnStrategy returns the strategy used to select the distro that the
+// task runs on. The task's strategy takes precedence over the variant's.
+func getRunOnStrategy(bvt BuildVariantTaskUnit, bv *BuildVariant) string {
+	if bvt.RunOnStrategy != "" {
+		return bvt.RunOnStrategy
+	}
+	if bv.RunOnStrategy != "" {
+		return bv.RunOnStrategy
+	}
+	return RunOnStrategyFirst
+}
+
+// RunOnQueueLengths caches the task queue lengths of run_on distros so that
+// they are fetched once per version rather than once per task.
+type RunOnQueueLengths struct {
+	lengths map[string]int
+}
+
+// NewRunOnQueueLengths returns an empty cache of run_on queue lengths.
+func NewRunOnQueueLengths() *RunOnQueueLengths {
+	return &RunOnQueueLengths{lengths: map[string]int{}}
+}
+
+// get returns the task queue lengths of the given distros, fetching the ones
+// that are not already cached in a single query.
+func (q *RunOnQueueLengths) get(distroIDs []string) (map[string]int, error) {
+	missing := []string{}
+	for _, d := range distroIDs {
+		if _, ok := q.lengths[d]; !ok {
+			missing = append(missing, d)
+		}
+	}
+	if len(missing) == 0 {
+		return q.lengths, nil
+	}
+	lengths, err := FindTaskQueueLengths(missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range missing {
+		q.lengths[d] = lengths[d]
+	}
+	return q.lengths, nil
+}
+
+// selectRunOnDistro returns the index of the distro in run_on that the task
+// should run on according to the strategy.
+func selectRunOnDistro(runOn []string, strategy, taskName string, v *Version, queueLengths *RunOnQueueLengths) (int, error) {
+	if len(runOn) <= 1 {
+		return 0, nil
+	}
+	switch strategy {
+	case "", RunOnStrategyFirst:
+		return 0, nil
+	case RunOnStrategyRoundRobin:
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(taskName))
+		offset := int(h.Sum32() % uint32(len(runOn)))
+		order := v.RevisionOrderNumber % len(runOn)
+		if order < 0 {
+			order += len(runOn)
+		}
+		return (order + offset) % len(runOn), nil
+	case RunOnStrategyWeighted:
+		if queueLengths == nil {
+			queueLengths = NewRunOnQueueLengths()
+		}
+		lengths, err := queueLengths.get(runOn)
+		if err != nil {
+			return 0, errors.Wrap(err, "finding task queue lengths for run_on distros")
+		}
+		depths := make([]int, 0, len(runOn))
+		for _, d := range runOn {
+			depths = append(depths, lengths[d])
+		}
+		return selectWeightedDistro(depths, rand.Float64()), nil
+	default:
+		return 0, errors.Errorf("invalid run_on strategy '%s'", strategy)
+	}
+}
+
+// selectWeightedDistro returns the index of the distro selected by r, a number
+// in [0, 1). Each distro is weighted in inverse proportion to the number of
+// tasks in its queue.
+func selectWeightedDistro(queueDepths []int, r float64) int {
+	weights := make([]float64, 0, len(queueDepths))
+	var total float64
+	for _, depth := range queueDepths {
+		if depth < 0 {
+			depth = 0
+		}
+		w := 1 / float64(depth+1)
+		weights = append(weights, w)
+		total += w
+	}
+	target := r * total
+	for i, w := range weights {
+		if target < w {
+			return i
+		}
+		target -= w
+	}
+	return len(weights) - 1
+}
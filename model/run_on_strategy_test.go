@@ -0,0 +1,105 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectRunOnDistro(t *testing.T) {
+	runOn := []string{"d0", "d1", "d2"}
+
+	t.Run("FirstStrategySelectsFirstDistro", func(t *testing.T) {
+		for _, strategy := range []string{"", RunOnStrategyFirst} {
+			idx, err := selectRunOnDistro(runOn, strategy, "task", &Version{RevisionOrderNumber: 5}, nil)
+			require.NoError(t, err)
+			assert.Zero(t, idx)
+		}
+	})
+	t.Run("SingleDistroIsAlwaysSelected", func(t *testing.T) {
+		idx, err := selectRunOnDistro([]string{"d0"}, RunOnStrategyRoundRobin, "task", &Version{RevisionOrderNumber: 5}, nil)
+		require.NoError(t, err)
+		assert.Zero(t, idx)
+	})
+	t.Run("RoundRobinRotatesAcrossVersions", func(t *testing.T) {
+		seen := map[int]bool{}
+		for order := 1; order <= len(runOn); order++ {
+			idx, err := selectRunOnDistro(runOn, RunOnStrategyRoundRobin, "task", &Version{RevisionOrderNumber: order}, nil)
+			require.NoError(t, err)
+			seen[idx] = true
+		}
+		assert.Len(t, seen, len(runOn))
+	})
+	t.Run("RoundRobinIsStableForVersion", func(t *testing.T) {
+		v := &Version{RevisionOrderNumber: 7}
+		first, err := selectRunOnDistro(runOn, RunOnStrategyRoundRobin, "task", v, nil)
+		require.NoError(t, err)
+		second, err := selectRunOnDistro(runOn, RunOnStrategyRoundRobin, "task", v, nil)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+	})
+	t.Run("WeightedSelectsDistroWithQueueInfo", func(t *testing.T) {
+		require.NoError(t, db.ClearCollections(TaskQueuesCollection))
+		defer func() {
+			assert.NoError(t, db.ClearCollections(TaskQueuesCollection))
+		}()
+		require.NoError(t, NewTaskQueue("d0", nil, DistroQueueInfo{Length: 100}).Save())
+
+		lengths, err := FindTaskQueueLengths(runOn)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"d0": 100}, lengths)
+
+		idx, err := selectRunOnDistro(runOn, RunOnStrategyWeighted, "task", &Version{}, nil)
+		require.NoError(t, err)
+		assert.True(t, idx >= 0 && idx < len(runOn))
+	})
+	t.Run("FailsWithInvalidStrategy", func(t *testing.T) {
+		_, err := selectRunOnDistro(runOn, "random", "task", &Version{}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestRunOnQueueLengths(t *testing.T) {
+	require.NoError(t, db.ClearCollections(TaskQueuesCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(TaskQueuesCollection))
+	}()
+	require.NoError(t, NewTaskQueue("d0", nil, DistroQueueInfo{Length: 100}).Save())
+
+	queueLengths := NewRunOnQueueLengths()
+	lengths, err := queueLengths.get([]string{"d0", "d1"})
+	require.NoError(t, err)
+	assert.Equal(t, 100, lengths["d0"])
+	assert.Zero(t, lengths["d1"])
+
+	// Cached lengths are not fetched again for the same version.
+	require.NoError(t, NewTaskQueue("d0", nil, DistroQueueInfo{Length: 5}).Save())
+	require.NoError(t, NewTaskQueue("d2", nil, DistroQueueInfo{Length: 7}).Save())
+	lengths, err = queueLengths.get([]string{"d0", "d2"})
+	require.NoError(t, err)
+	assert.Equal(t, 100, lengths["d0"])
+	assert.Equal(t, 7, lengths["d2"])
+}
+
+func TestSelectWeightedDistro(t *testing.T) {
+	// The weights are 1, 1/2, and 1/4, so the distros are selected by 4/7,
+	// 2/7, and 1/7 of the range respectively.
+	depths := []int{0, 1, 3}
+	assert.Equal(t, 0, selectWeightedDistro(depths, 0))
+	assert.Equal(t, 0, selectWeightedDistro(depths, 0.5))
+	assert.Equal(t, 1, selectWeightedDistro(depths, 0.6))
+	assert.Equal(t, 1, selectWeightedDistro(depths, 0.8))
+	assert.Equal(t, 2, selectWeightedDistro(depths, 0.9))
+	assert.Equal(t, 2, selectWeightedDistro(depths, 0.999))
+}
+
+func TestGetRunOnStrategy(t *testing.T) {
+	bv := &BuildVariant{}
+	assert.Equal(t, RunOnStrategyFirst, getRunOnStrategy(BuildVariantTaskUnit{}, bv))
+
+	bv.RunOnStrategy = RunOnStrategyWeighted
+	assert.Equal(t, RunOnStrategyWeighted, getRunOnStrategy(BuildVariantTaskUnit{}, bv))
+	assert.Equal(t, RunOnStrategyRoundRobin, getRunOnStrategy(BuildVariantTaskUnit{RunOnStrategy: RunOnStrategyRoundRobin}, bv))
+}
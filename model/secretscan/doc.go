@@ -0,0 +1,4 @@
+// Package secretscan detects high-confidence secrets, such as access keys and
+// tokens, in task logs and artifact metadata, and records what was found
+// without storing the secrets themselves.
+package secretscan
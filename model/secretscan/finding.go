@@ -0,0 +1,108 @@
+package secretscan
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Collection is the collection that holds the secrets found in tasks.
+const Collection = "secret_scan_findings"
+
+// Sources of scanned content.
+const (
+	SourceTaskLog  = "task_log"
+	SourceArtifact = "artifact"
+)
+
+// Finding is a secret that was found and redacted in content uploaded by a
+// task. The secret itself is never stored.
+type Finding struct {
+	ID        string `bson:"_id" json:"id"`
+	TaskID    string `bson:"task_id" json:"task_id"`
+	Execution int    `bson:"execution" json:"execution"`
+	ProjectID string `bson:"project_id" json:"project_id"`
+	// Source is the kind of content that the secret was found in.
+	Source string `bson:"source" json:"source"`
+	// Location describes where in the content the secret was found, such as
+	// the artifact file and field.
+	Location    string    `bson:"location" json:"location"`
+	Rule        string    `bson:"rule" json:"rule"`
+	Fingerprint string    `bson:"fingerprint" json:"fingerprint"`
+	CreateTime  time.Time `bson:"create_time" json:"create_time"`
+}
+
+var (
+	IDKey         = bsonutil.MustHaveTag(Finding{}, "ID")
+	TaskIDKey     = bsonutil.MustHaveTag(Finding{}, "TaskID")
+	ExecutionKey  = bsonutil.MustHaveTag(Finding{}, "Execution")
+	ProjectIDKey  = bsonutil.MustHaveTag(Finding{}, "ProjectID")
+	CreateTimeKey = bsonutil.MustHaveTag(Finding{}, "CreateTime")
+)
+
+// NewFindings returns the findings for the matches in the task's content.
+func NewFindings(taskID string, execution int, projectID, source, location string, matches []Match) []Finding {
+	findings := make([]Finding, 0, len(matches))
+	for _, match := range matches {
+		findings = append(findings, Finding{
+			ID:          primitive.NewObjectID().Hex(),
+			TaskID:      taskID,
+			Execution:   execution,
+			ProjectID:   projectID,
+			Source:      source,
+			Location:    location,
+			Rule:        match.Rule,
+			Fingerprint: match.Fingerprint,
+			CreateTime:  time.Now(),
+		})
+	}
+	return findings
+}
+
+// InsertFindings inserts the findings.
+func InsertFindings(findings []Finding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, 0, len(findings))
+	for _, f := range findings {
+		docs = append(docs, f)
+	}
+	return errors.Wrap(db.InsertMany(Collection, docs...), "inserting secret scan findings")
+}
+
+// FindOptions filters the findings to return.
+type FindOptions struct {
+	ProjectID string
+	TaskID    string
+	// Since only returns findings created at or after the time.
+	Since time.Time
+	Limit int
+}
+
+// Find returns the findings that match the options, most recent first.
+func Find(opts FindOptions) ([]Finding, error) {
+	query := bson.M{}
+	if opts.ProjectID != "" {
+		query[ProjectIDKey] = opts.ProjectID
+	}
+	if opts.TaskID != "" {
+		query[TaskIDKey] = opts.TaskID
+	}
+	if !opts.Since.IsZero() {
+		query[CreateTimeKey] = bson.M{"$gte": opts.Since}
+	}
+	q := db.Query(query).Sort([]string{"-" + CreateTimeKey})
+	if opts.Limit > 0 {
+		q = q.Limit(opts.Limit)
+	}
+	findings := []Finding{}
+	if err := db.FindAllQ(Collection, q, &findings); err != nil {
+		return nil, errors.Wrap(err, "finding secret scan findings")
+	}
+	return findings, nil
+}
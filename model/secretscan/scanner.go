@@ -0,0 +1,90 @@
+package secretscan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+)
+
+// DefaultMaxBytes is how much of the content uploaded in a single request is
+// scanned for secrets.
+const DefaultMaxBytes = 64 * 1024
+
+// rule is a pattern that matches a kind of secret with few false positives.
+type rule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+var rules = []rule{
+	{name: "aws_access_key_id", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "github_token", pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,255}\b`)},
+	{name: "github_fine_grained_token", pattern: regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{82}\b`)},
+	{name: "slack_token", pattern: regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z-]{10,}`)},
+	{name: "google_api_key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{name: "stripe_live_key", pattern: regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`)},
+	{name: "private_key", pattern: regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY(?: BLOCK)?-----`)},
+	// Signed URLs, such as presigned S3 links to artifacts, grant access to
+	// anyone who has the URL until they expire.
+	{name: "url_signature", pattern: regexp.MustCompile(`(?i)\b(?:X-Amz-Signature|X-Goog-Signature|X-Amz-Security-Token|Signature|sig)=[^&\s"']{16,}`)},
+	{name: "url_credentials", pattern: regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@"']+:[^/\s@"']+@`)},
+}
+
+// Match is a secret found in scanned content.
+type Match struct {
+	// Rule is the name of the kind of secret that was found.
+	Rule string
+	// Fingerprint identifies the secret without revealing it, so that
+	// findings of the same secret can be grouped together.
+	Fingerprint string
+}
+
+// Scanner scans a sequence of content for secrets, up to a total number of
+// bytes.
+type Scanner struct {
+	remaining int
+}
+
+// NewScanner returns a scanner that scans up to the first maxBytes of the
+// content it's given.
+func NewScanner(maxBytes int) *Scanner {
+	return &Scanner{remaining: maxBytes}
+}
+
+// Scan returns the content with any secrets in the part of it that's within
+// the scanner's limit redacted, along with the secrets that were found.
+// Content past the limit is returned unchanged.
+func (s *Scanner) Scan(content string) (string, []Match) {
+	if s.remaining <= 0 || content == "" {
+		return content, nil
+	}
+	end := len(content)
+	if end > s.remaining {
+		end = s.remaining
+	}
+	s.remaining -= end
+
+	scanned := content[:end]
+	var matches []Match
+	for _, r := range rules {
+		scanned = r.pattern.ReplaceAllStringFunc(scanned, func(secret string) string {
+			matches = append(matches, Match{Rule: r.name, Fingerprint: fingerprint(secret)})
+			return fmt.Sprintf("[REDACTED:%s]", r.name)
+		})
+	}
+	if len(matches) == 0 {
+		return content, nil
+	}
+	return scanned + content[end:], matches
+}
+
+// Done returns whether the scanner has reached its limit.
+func (s *Scanner) Done() bool {
+	return s.remaining <= 0
+}
+
+func fingerprint(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:8])
+}
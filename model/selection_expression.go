@@ -0,0 +1,456 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// A selection expression selects tasks and variants by their names and tags.
+// It's a boolean combination of terms, each of which is a dimension and a
+// value separated by a colon, for example:
+//
+//	variant_tag:linux and not (task:lint or task_tag:nightly)
+//	variant:/^ubuntu.*$/ and task:"compile"
+//
+// A value is an exact name or tag, which can be quoted if it contains
+// whitespace or parentheses, or a regex between slashes. A term matches if any
+// of the candidate's values for its dimension match. Terms are combined with
+// "and", "or", "not", and parentheses, where "not" binds tightest and "or"
+// binds loosest.
+
+// Dimensions that selection expressions can select on.
+const (
+	SelectionVariant    = "variant"
+	SelectionVariantTag = "variant_tag"
+	SelectionTask       = "task"
+	SelectionTaskTag    = "task_tag"
+)
+
+var selectionDimensions = []string{
+	SelectionVariant,
+	SelectionVariantTag,
+	SelectionTask,
+	SelectionTaskTag,
+}
+
+// SelectionCandidate is something that a selection expression can select,
+// given as its values for each dimension. A dimension that's missing from the
+// candidate is unknown, as opposed to a dimension with no values.
+type SelectionCandidate map[string][]string
+
+// NewVariantSelectionCandidate returns a candidate for a build variant. Its
+// task dimensions are unknown.
+func NewVariantSelectionCandidate(name string, tags []string) SelectionCandidate {
+	return SelectionCandidate{
+		SelectionVariant:    []string{name},
+		SelectionVariantTag: tags,
+	}
+}
+
+// WithTask returns a copy of the candidate for the given task.
+func (c SelectionCandidate) WithTask(name string, tags []string) SelectionCandidate {
+	out := SelectionCandidate{}
+	for dimension, values := range c {
+		out[dimension] = values
+	}
+	out[SelectionTask] = []string{name}
+	out[SelectionTaskTag] = tags
+	return out
+}
+
+// SelectionExpression is a parsed selection expression.
+type SelectionExpression struct {
+	source string
+	root   selectionNode
+}
+
+// ParseSelectionExpression parses a selection expression.
+func ParseSelectionExpression(expr string) (*SelectionExpression, error) {
+	tokens, err := lexSelectionExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("selection expression is empty")
+	}
+	p := &selectionParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, errors.Errorf("unexpected %s at offset %d", p.peek(), p.peek().offset)
+	}
+	return &SelectionExpression{source: expr, root: root}, nil
+}
+
+// String returns the expression as it was written.
+func (e *SelectionExpression) String() string { return e.source }
+
+// Matches returns whether the expression selects the candidate. If the
+// expression's result depends on a dimension that the candidate doesn't have,
+// it doesn't match.
+func (e *SelectionExpression) Matches(c SelectionCandidate) bool {
+	return e.root.eval(c) == selectionTrue
+}
+
+// CouldMatch returns whether the expression could select the candidate once
+// the candidate's unknown dimensions are known.
+func (e *SelectionExpression) CouldMatch(c SelectionCandidate) bool {
+	return e.root.eval(c) != selectionFalse
+}
+
+// Dimensions returns the dimensions that the expression selects on, in
+// sorted order.
+func (e *SelectionExpression) Dimensions() []string {
+	seen := map[string]bool{}
+	e.root.dimensions(seen)
+	out := make([]string, 0, len(seen))
+	for dimension := range seen {
+		out = append(out, dimension)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// selectionResult is the result of evaluating an expression on a candidate,
+// which is unknown if it depends on one of the candidate's unknown
+// dimensions.
+type selectionResult int
+
+const (
+	selectionFalse selectionResult = iota
+	selectionTrue
+	selectionUnknown
+)
+
+type selectionNode interface {
+	eval(SelectionCandidate) selectionResult
+	dimensions(map[string]bool)
+}
+
+type selectionTerm struct {
+	dimension string
+	value     string
+	regex     *regexp.Regexp
+}
+
+func (t *selectionTerm) eval(c SelectionCandidate) selectionResult {
+	values, ok := c[t.dimension]
+	if !ok {
+		return selectionUnknown
+	}
+	for _, value := range values {
+		if t.matches(value) {
+			return selectionTrue
+		}
+	}
+	return selectionFalse
+}
+
+func (t *selectionTerm) matches(value string) bool {
+	if t.regex != nil {
+		return t.regex.MatchString(value)
+	}
+	return value == t.value
+}
+
+func (t *selectionTerm) dimensions(seen map[string]bool) { seen[t.dimension] = true }
+
+type selectionNot struct {
+	operand selectionNode
+}
+
+func (n *selectionNot) eval(c SelectionCandidate) selectionResult {
+	switch n.operand.eval(c) {
+	case selectionTrue:
+		return selectionFalse
+	case selectionFalse:
+		return selectionTrue
+	default:
+		return selectionUnknown
+	}
+}
+
+func (n *selectionNot) dimensions(seen map[string]bool) { n.operand.dimensions(seen) }
+
+type selectionAnd struct {
+	operands []selectionNode
+}
+
+func (n *selectionAnd) eval(c SelectionCandidate) selectionResult {
+	result := selectionTrue
+	for _, operand := range n.operands {
+		switch operand.eval(c) {
+		case selectionFalse:
+			return selectionFalse
+		case selectionUnknown:
+			result = selectionUnknown
+		}
+	}
+	return result
+}
+
+func (n *selectionAnd) dimensions(seen map[string]bool) {
+	for _, operand := range n.operands {
+		operand.dimensions(seen)
+	}
+}
+
+type selectionOr struct {
+	operands []selectionNode
+}
+
+func (n *selectionOr) eval(c SelectionCandidate) selectionResult {
+	result := selectionFalse
+	for _, operand := range n.operands {
+		switch operand.eval(c) {
+		case selectionTrue:
+			return selectionTrue
+		case selectionUnknown:
+			result = selectionUnknown
+		}
+	}
+	return result
+}
+
+func (n *selectionOr) dimensions(seen map[string]bool) {
+	for _, operand := range n.operands {
+		operand.dimensions(seen)
+	}
+}
+
+type selectionTokenKind int
+
+const (
+	selectionTokenTerm selectionTokenKind = iota
+	selectionTokenAnd
+	selectionTokenOr
+	selectionTokenNot
+	selectionTokenOpen
+	selectionTokenClose
+)
+
+type selectionToken struct {
+	kind   selectionTokenKind
+	offset int
+	term   *selectionTerm
+}
+
+func (t selectionToken) String() string {
+	switch t.kind {
+	case selectionTokenAnd:
+		return "'and'"
+	case selectionTokenOr:
+		return "'or'"
+	case selectionTokenNot:
+		return "'not'"
+	case selectionTokenOpen:
+		return "'('"
+	case selectionTokenClose:
+		return "')'"
+	default:
+		return fmt.Sprintf("term '%s:%s'", t.term.dimension, t.term.value)
+	}
+}
+
+func isSelectionDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == ')'
+}
+
+func lexSelectionExpression(expr string) ([]selectionToken, error) {
+	var tokens []selectionToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, selectionToken{kind: selectionTokenOpen, offset: i})
+			i++
+			continue
+		case r == ')':
+			tokens = append(tokens, selectionToken{kind: selectionTokenClose, offset: i})
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && runes[i] != ':' && !isSelectionDelimiter(runes[i]) {
+			i++
+		}
+		word := string(runes[start:i])
+		if i >= len(runes) || runes[i] != ':' {
+			switch word {
+			case "and":
+				tokens = append(tokens, selectionToken{kind: selectionTokenAnd, offset: start})
+			case "or":
+				tokens = append(tokens, selectionToken{kind: selectionTokenOr, offset: start})
+			case "not":
+				tokens = append(tokens, selectionToken{kind: selectionTokenNot, offset: start})
+			default:
+				return nil, errors.Errorf("expected a term of the form '<dimension>:<value>' but got '%s' at offset %d", word, start)
+			}
+			continue
+		}
+
+		dimension := word
+		if !isSelectionDimension(dimension) {
+			return nil, errors.Errorf("unknown dimension '%s' at offset %d, must be one of: %s", dimension, start, strings.Join(selectionDimensions, ", "))
+		}
+		i++ // the colon
+
+		term := &selectionTerm{dimension: dimension}
+		var err error
+		switch {
+		case i < len(runes) && runes[i] == '"':
+			term.value, i, err = lexSelectionDelimited(runes, i, '"')
+			if err != nil {
+				return nil, err
+			}
+		case i < len(runes) && runes[i] == '/':
+			term.value, i, err = lexSelectionDelimited(runes, i, '/')
+			if err != nil {
+				return nil, err
+			}
+			if term.regex, err = regexp.Compile(term.value); err != nil {
+				return nil, errors.Wrapf(err, "compiling regex for dimension '%s' at offset %d", dimension, start)
+			}
+		default:
+			valueStart := i
+			for i < len(runes) && !isSelectionDelimiter(runes[i]) {
+				i++
+			}
+			term.value = string(runes[valueStart:i])
+		}
+		if term.value == "" {
+			return nil, errors.Errorf("dimension '%s' at offset %d has no value", dimension, start)
+		}
+		tokens = append(tokens, selectionToken{kind: selectionTokenTerm, offset: start, term: term})
+	}
+	return tokens, nil
+}
+
+// lexSelectionDelimited reads a value between two delimiters, starting at the
+// opening delimiter. A backslash escapes the delimiter. In quoted values it
+// also escapes a backslash; in regexes other escapes are left for the regex.
+func lexSelectionDelimited(runes []rune, i int, delim rune) (string, int, error) {
+	start := i
+	var value strings.Builder
+	for i++; i < len(runes); i++ {
+		r := runes[i]
+		if r == delim {
+			return value.String(), i + 1, nil
+		}
+		if r == '\\' && i+1 < len(runes) && (runes[i+1] == delim || delim == '"' && runes[i+1] == '\\') {
+			i++
+			r = runes[i]
+		}
+		value.WriteRune(r)
+	}
+	return "", 0, errors.Errorf("unterminated %c at offset %d", delim, start)
+}
+
+func isSelectionDimension(dimension string) bool {
+	for _, d := range selectionDimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+type selectionParser struct {
+	tokens []selectionToken
+	pos    int
+}
+
+func (p *selectionParser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *selectionParser) peek() selectionToken { return p.tokens[p.pos] }
+
+func (p *selectionParser) accept(kind selectionTokenKind) bool {
+	if !p.done() && p.peek().kind == kind {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *selectionParser) parseOr() (selectionNode, error) {
+	var operands []selectionNode
+	for {
+		operand, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		if !p.accept(selectionTokenOr) {
+			break
+		}
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &selectionOr{operands: operands}, nil
+}
+
+func (p *selectionParser) parseAnd() (selectionNode, error) {
+	var operands []selectionNode
+	for {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		if !p.accept(selectionTokenAnd) {
+			break
+		}
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &selectionAnd{operands: operands}, nil
+}
+
+func (p *selectionParser) parseNot() (selectionNode, error) {
+	if p.accept(selectionTokenNot) {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &selectionNot{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *selectionParser) parsePrimary() (selectionNode, error) {
+	if p.done() {
+		return nil, errors.New("selection expression ends unexpectedly")
+	}
+	tok := p.peek()
+	switch tok.kind {
+	case selectionTokenTerm:
+		p.pos++
+		return tok.term, nil
+	case selectionTokenOpen:
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(selectionTokenClose) {
+			return nil, errors.Errorf("unclosed '(' at offset %d", tok.offset)
+		}
+		return node, nil
+	default:
+		return nil, errors.Errorf("unexpected %s at offset %d", tok, tok.offset)
+	}
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelectionExpression(t *testing.T) {
+	for name, expr := range map[string]string{
+		"Term":              "task:compile",
+		"QuotedValue":       `task:"compile all" and variant:"a\"b"`,
+		"Regex":             `variant:/^ubuntu\/[0-9]+(\.[0-9]+)?$/`,
+		"NestedParentheses": "not (variant:a or (task:b and not task_tag:c))",
+	} {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := ParseSelectionExpression(expr)
+			require.NoError(t, err)
+			assert.Equal(t, expr, parsed.String())
+		})
+	}
+
+	for name, expr := range map[string]string{
+		"Empty":             "  ",
+		"UnknownDimension":  "distro:ubuntu",
+		"MissingValue":      "task:",
+		"BareWord":          "compile",
+		"TrailingOperator":  "task:a and",
+		"UnclosedParen":     "(task:a or task:b",
+		"UnexpectedParen":   "task:a)",
+		"UnterminatedQuote": `task:"compile`,
+		"UnterminatedRegex": "task:/compile",
+		"InvalidRegex":      "task:/(/",
+		"MissingOperator":   "task:a task:b",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSelectionExpression(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSelectionExpressionMatches(t *testing.T) {
+	variant := NewVariantSelectionCandidate("ubuntu2004", []string{"linux", "primary"})
+	for name, testCase := range map[string]struct {
+		expr       string
+		candidate  SelectionCandidate
+		matches    bool
+		couldMatch bool
+	}{
+		"ExactName": {
+			expr:       "variant:ubuntu2004",
+			candidate:  variant,
+			matches:    true,
+			couldMatch: true,
+		},
+		"ExactNameIsNotASubstring": {
+			expr:      "variant:ubuntu",
+			candidate: variant,
+		},
+		"Regex": {
+			expr:       "variant:/^ubuntu/",
+			candidate:  variant,
+			matches:    true,
+			couldMatch: true,
+		},
+		"AnyTag": {
+			expr:       "variant_tag:primary",
+			candidate:  variant,
+			matches:    true,
+			couldMatch: true,
+		},
+		"Not": {
+			expr:      "not variant_tag:linux",
+			candidate: variant,
+		},
+		"AndBindsTighterThanOr": {
+			expr:       "variant_tag:windows and variant_tag:primary or variant_tag:linux",
+			candidate:  variant,
+			matches:    true,
+			couldMatch: true,
+		},
+		"Parentheses": {
+			expr:      "variant_tag:windows and (variant_tag:primary or variant_tag:linux)",
+			candidate: variant,
+		},
+		"UnknownTaskCouldMatch": {
+			expr:       "variant_tag:linux and task:compile",
+			candidate:  variant,
+			couldMatch: true,
+		},
+		"UnknownTaskCannotMatch": {
+			expr:      "variant_tag:windows and task:compile",
+			candidate: variant,
+		},
+		"UnknownTaskIsIrrelevant": {
+			expr:       "variant_tag:linux or task:compile",
+			candidate:  variant,
+			matches:    true,
+			couldMatch: true,
+		},
+		"KnownTask": {
+			expr:       "variant_tag:linux and task:compile and not task_tag:slow",
+			candidate:  variant.WithTask("compile", []string{"fast"}),
+			matches:    true,
+			couldMatch: true,
+		},
+		"TaskWithoutTags": {
+			expr:      "task_tag:slow",
+			candidate: variant.WithTask("compile", nil),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			expr, err := ParseSelectionExpression(testCase.expr)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.matches, expr.Matches(testCase.candidate))
+			assert.Equal(t, testCase.couldMatch, expr.CouldMatch(testCase.candidate))
+		})
+	}
+}
+
+func TestSelectionExpressionDimensions(t *testing.T) {
+	expr, err := ParseSelectionExpression("task:a or (variant_tag:b and not task:c)")
+	require.NoError(t, err)
+	assert.Equal(t, []string{SelectionTask, SelectionVariantTag}, expr.Dimensions())
+}
+
+func TestProjectSelectTVPairs(t *testing.T) {
+	p := &Project{
+		BuildVariants: BuildVariants{
+			{
+				Name: "ubuntu",
+				Tags: []string{"linux"},
+				Tasks: []BuildVariantTaskUnit{
+					{Name: "compile", Variant: "ubuntu"},
+					{Name: "test", Variant: "ubuntu"},
+				},
+				DisplayTasks: []patch.DisplayTask{{Name: "compile_and_test", ExecTasks: []string{"compile", "test"}}},
+			},
+			{
+				Name: "windows",
+				Tasks: []BuildVariantTaskUnit{
+					{Name: "compile", Variant: "windows"},
+				},
+			},
+		},
+		Tasks: []ProjectTask{
+			{Name: "compile"},
+			{Name: "test", Tags: []string{"slow"}},
+		},
+	}
+
+	expr, err := ParseSelectionExpression("variant_tag:linux and not task_tag:slow")
+	require.NoError(t, err)
+	pairs, displayTaskPairs := p.SelectTVPairs(expr)
+	assert.Equal(t, []TVPair{{Variant: "ubuntu", TaskName: "compile"}}, pairs)
+	assert.Equal(t, []TVPair{{Variant: "ubuntu", TaskName: "compile_and_test"}}, displayTaskPairs)
+
+	pairs, displayTaskPairs, err = p.BuildProjectTVPairsWithAlias([]ProjectAlias{{Selector: "task:compile"}})
+	require.NoError(t, err)
+	assert.Equal(t, []TVPair{{Variant: "ubuntu", TaskName: "compile"}, {Variant: "windows", TaskName: "compile"}}, pairs)
+	assert.Empty(t, displayTaskPairs)
+}
@@ -0,0 +1,235 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// ServiceAccountPermissionReadOnly allows a service account to view the
+	// project's settings, tasks, logs, and annotations. Every service account
+	// has these permissions.
+	ServiceAccountPermissionReadOnly = "read_only"
+	// ServiceAccountPermissionSubmitPatches allows a service account to submit
+	// patches to the project.
+	ServiceAccountPermissionSubmitPatches = "submit_patches"
+	// ServiceAccountPermissionRestartTasks allows a service account to
+	// restart, schedule, and abort the project's tasks.
+	ServiceAccountPermissionRestartTasks = "restart_tasks"
+)
+
+// ValidServiceAccountPermissions are the kinds of actions that a service
+// account can be allowed to take in its project.
+var ValidServiceAccountPermissions = []string{
+	ServiceAccountPermissionReadOnly,
+	ServiceAccountPermissionSubmitPatches,
+	ServiceAccountPermissionRestartTasks,
+}
+
+var serviceAccountNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// serviceAccountRoleID returns the ID of the role that grants the service
+// account its permissions.
+func serviceAccountRoleID(accountID string) string {
+	return fmt.Sprintf("service_account_%s", accountID)
+}
+
+// serviceAccountRolePermissions returns the project permissions of a service
+// account that is allowed to take the given kinds of actions.
+func serviceAccountRolePermissions(permissions []string) (gimlet.Permissions, error) {
+	rolePermissions := gimlet.Permissions{
+		evergreen.PermissionProjectSettings: evergreen.ProjectSettingsView.Value,
+		evergreen.PermissionTasks:           evergreen.TasksView.Value,
+		evergreen.PermissionLogs:            evergreen.LogsView.Value,
+		evergreen.PermissionAnnotations:     evergreen.AnnotationsView.Value,
+	}
+	for _, permission := range permissions {
+		switch permission {
+		case ServiceAccountPermissionReadOnly:
+		case ServiceAccountPermissionSubmitPatches:
+			rolePermissions[evergreen.PermissionPatches] = evergreen.PatchSubmit.Value
+		case ServiceAccountPermissionRestartTasks:
+			rolePermissions[evergreen.PermissionTasks] = evergreen.TasksBasic.Value
+		default:
+			return nil, errors.Errorf("invalid service account permission '%s', must be one of: %v", permission, ValidServiceAccountPermissions)
+		}
+	}
+	return rolePermissions, nil
+}
+
+// ValidateServiceAccount checks that the service account's name can be used
+// in its ID and that it only requests valid permissions.
+func ValidateServiceAccount(name string, permissions []string) error {
+	if !serviceAccountNameRegex.MatchString(name) {
+		return errors.Errorf("service account name '%s' must only contain letters, numbers, underscores, and dashes", name)
+	}
+	_, err := serviceAccountRolePermissions(permissions)
+	return err
+}
+
+// ServiceAccountID returns the user ID of the project's service account with
+// the given name. Names can't contain a dot, so the name ends at the first dot
+// after the prefix and no two projects' accounts can share an ID.
+func ServiceAccountID(projectID, name string) string {
+	return fmt.Sprintf("svc.%s.%s", name, projectID)
+}
+
+// CreateServiceAccount issues a new API-only user that can only take the
+// given kinds of actions in the project. The returned user holds the service
+// account's API key.
+func CreateServiceAccount(pRef *ProjectRef, name string, permissions []string, caller string) (*user.DBUser, error) {
+	if err := ValidateServiceAccount(name, permissions); err != nil {
+		return nil, err
+	}
+	if len(permissions) == 0 {
+		permissions = []string{ServiceAccountPermissionReadOnly}
+	}
+	permissions = utility.UniqueStrings(permissions)
+	rolePermissions, err := serviceAccountRolePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	id := ServiceAccountID(pRef.Id, name)
+	role := gimlet.Role{
+		ID:          serviceAccountRoleID(id),
+		Name:        fmt.Sprintf("service account '%s' for project '%s'", name, pRef.Identifier),
+		Scope:       fmt.Sprintf("project_%s", pRef.Id),
+		Permissions: rolePermissions,
+	}
+	u := &user.DBUser{
+		Id:          id,
+		DispName:    fmt.Sprintf("%s (%s service account)", name, pRef.Identifier),
+		APIKey:      utility.RandomString(),
+		SystemRoles: []string{role.ID},
+		OnlyAPI:     true,
+		ServiceAccount: &user.ServiceAccountInfo{
+			ProjectID:   pRef.Id,
+			Permissions: permissions,
+			CreatedBy:   caller,
+		},
+	}
+	if err = u.Insert(); err != nil {
+		if db.IsDuplicateKey(err) {
+			return nil, errors.Errorf("service account '%s' already exists in project '%s'", name, pRef.Identifier)
+		}
+		return nil, errors.Wrapf(err, "inserting service account '%s'", name)
+	}
+	if err = evergreen.GetEnvironment().RoleManager().UpdateRole(role); err != nil {
+		grip.Error(message.WrapError(user.DeleteServiceUser(id), message.Fields{
+			"message":         "could not remove service account without a role",
+			"service_account": id,
+			"project":         pRef.Id,
+		}))
+		return nil, errors.Wrapf(err, "adding role for service account '%s'", name)
+	}
+
+	grip.Error(message.WrapError(event.LogServiceAccountEvent(id, event.EventServiceAccountCreated, event.ServiceAccountEventData{
+		ProjectID:   pRef.Id,
+		User:        caller,
+		Permissions: permissions,
+	}), message.Fields{
+		"message":         "could not log service account created event",
+		"service_account": id,
+		"project":         pRef.Id,
+	}))
+
+	return u, nil
+}
+
+// RotateServiceAccountKey replaces the service account's API key and returns
+// the updated service account. The old key stops working immediately.
+func RotateServiceAccountKey(projectID, accountID, caller string) (*user.DBUser, error) {
+	u, err := findServiceAccount(projectID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	newKey := utility.RandomString()
+	err = user.UpdateOne(bson.M{user.IdKey: u.Id}, bson.M{
+		"$set": bson.M{
+			user.APIKeyKey: newKey,
+			bsonutil.GetDottedKeyName(user.ServiceAccountKey, user.ServiceAccountKeyRotatedAtKey): now,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "rotating API key for service account '%s'", accountID)
+	}
+	u.APIKey = newKey
+	u.ServiceAccount.KeyRotatedAt = now
+
+	grip.Error(message.WrapError(event.LogServiceAccountEvent(u.Id, event.EventServiceAccountKeyRotated, event.ServiceAccountEventData{
+		ProjectID: projectID,
+		User:      caller,
+	}), message.Fields{
+		"message":         "could not log service account key rotated event",
+		"service_account": u.Id,
+		"project":         projectID,
+	}))
+
+	return u, nil
+}
+
+// RevokeServiceAccount deletes the service account and its role so that its
+// API key can no longer be used.
+func RevokeServiceAccount(projectID, accountID, caller string) error {
+	u, err := findServiceAccount(projectID, accountID)
+	if err != nil {
+		return err
+	}
+	if err = user.DeleteServiceUser(u.Id); err != nil {
+		return errors.Wrapf(err, "deleting service account '%s'", accountID)
+	}
+	if err = evergreen.GetEnvironment().RoleManager().DeleteRole(serviceAccountRoleID(u.Id)); err != nil {
+		return errors.Wrapf(err, "deleting role for service account '%s'", accountID)
+	}
+
+	grip.Error(message.WrapError(event.LogServiceAccountEvent(u.Id, event.EventServiceAccountRevoked, event.ServiceAccountEventData{
+		ProjectID: projectID,
+		User:      caller,
+	}), message.Fields{
+		"message":         "could not log service account revoked event",
+		"service_account": u.Id,
+		"project":         projectID,
+	}))
+
+	return nil
+}
+
+// LogServiceAccountAction records a request made by the service account that
+// modified something in Evergreen.
+func LogServiceAccountAction(u *user.DBUser, method, path string, statusCode int) error {
+	if !u.IsServiceAccount() {
+		return errors.Errorf("user '%s' is not a service account", u.Id)
+	}
+	return event.LogServiceAccountEvent(u.Id, event.EventServiceAccountAction, event.ServiceAccountEventData{
+		ProjectID:  u.ServiceAccount.ProjectID,
+		Method:     method,
+		Path:       path,
+		StatusCode: statusCode,
+	})
+}
+
+func findServiceAccount(projectID, accountID string) (*user.DBUser, error) {
+	u, err := user.FindServiceAccount(projectID, accountID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding service account '%s'", accountID)
+	}
+	if u == nil || u.ServiceAccount == nil {
+		return nil, errors.Errorf("service account '%s' not found in project '%s'", accountID, projectID)
+	}
+	return u, nil
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceAccounts(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ProjectRefCollection, user.Collection, evergreen.ScopeCollection,
+		evergreen.RoleCollection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection, user.Collection, evergreen.ScopeCollection,
+			evergreen.RoleCollection, event.AllLogCollection))
+	}()
+
+	pRef := &ProjectRef{Id: "project", Identifier: "identifier"}
+	require.NoError(t, pRef.Insert())
+	rm := evergreen.GetEnvironment().RoleManager()
+	require.NoError(t, rm.AddScope(gimlet.Scope{
+		ID:        "project_project",
+		Resources: []string{pRef.Id},
+		Type:      evergreen.ProjectResourceType,
+	}))
+
+	account, err := CreateServiceAccount(pRef, "ci-bot", []string{ServiceAccountPermissionRestartTasks}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, ServiceAccountID(pRef.Id, "ci-bot"), account.Id)
+	assert.True(t, account.IsServiceAccount())
+	assert.NotEmpty(t, account.APIKey)
+
+	dbAccount, err := user.FindServiceAccount(pRef.Id, account.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbAccount)
+	assert.Equal(t, account.APIKey, dbAccount.APIKey)
+	canRestart := dbAccount.HasPermission(gimlet.PermissionOpts{
+		Resource:      pRef.Id,
+		ResourceType:  evergreen.ProjectResourceType,
+		Permission:    evergreen.PermissionTasks,
+		RequiredLevel: evergreen.TasksBasic.Value,
+	})
+	assert.True(t, canRestart)
+	canSubmitPatches := dbAccount.HasPermission(gimlet.PermissionOpts{
+		Resource:      pRef.Id,
+		ResourceType:  evergreen.ProjectResourceType,
+		Permission:    evergreen.PermissionPatches,
+		RequiredLevel: evergreen.PatchSubmit.Value,
+	})
+	assert.False(t, canSubmitPatches)
+	canRestartOtherProject := dbAccount.HasPermission(gimlet.PermissionOpts{
+		Resource:      "other",
+		ResourceType:  evergreen.ProjectResourceType,
+		Permission:    evergreen.PermissionTasks,
+		RequiredLevel: evergreen.TasksView.Value,
+	})
+	assert.False(t, canRestartOtherProject)
+
+	t.Run("FailsForDuplicateName", func(t *testing.T) {
+		_, err := CreateServiceAccount(pRef, "ci-bot", nil, "admin")
+		assert.Error(t, err)
+	})
+	t.Run("FailsForInvalidPermission", func(t *testing.T) {
+		_, err := CreateServiceAccount(pRef, "other", []string{"delete_everything"}, "admin")
+		assert.Error(t, err)
+	})
+	t.Run("FailsForInvalidName", func(t *testing.T) {
+		_, err := CreateServiceAccount(pRef, "has spaces", nil, "admin")
+		assert.Error(t, err)
+	})
+
+	rotated, err := RotateServiceAccountKey(pRef.Id, account.Id, "admin")
+	require.NoError(t, err)
+	assert.NotEqual(t, account.APIKey, rotated.APIKey)
+	dbAccount, err = user.FindServiceAccount(pRef.Id, account.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbAccount)
+	assert.Equal(t, rotated.APIKey, dbAccount.APIKey)
+	assert.False(t, dbAccount.ServiceAccount.KeyRotatedAt.IsZero())
+
+	require.NoError(t, LogServiceAccountAction(dbAccount, "POST", "/rest/v2/tasks/t1/restart", 200))
+
+	require.NoError(t, RevokeServiceAccount(pRef.Id, account.Id, "admin"))
+	dbAccount, err = user.FindServiceAccount(pRef.Id, account.Id)
+	require.NoError(t, err)
+	assert.Nil(t, dbAccount)
+	roles, err := rm.GetRoles([]string{serviceAccountRoleID(account.Id)})
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+	assert.Error(t, RevokeServiceAccount(pRef.Id, account.Id, "admin"))
+
+	events, err := event.FindServiceAccountEventsForProject(pRef.Id, 10)
+	require.NoError(t, err)
+	eventTypes := []string{}
+	for _, e := range events {
+		eventTypes = append(eventTypes, e.EventType)
+	}
+	assert.ElementsMatch(t, []string{
+		string(event.EventServiceAccountCreated),
+		string(event.EventServiceAccountKeyRotated),
+		string(event.EventServiceAccountAction),
+		string(event.EventServiceAccountRevoked),
+	}, eventTypes)
+}
+
+func TestServiceAccountID(t *testing.T) {
+	assert.Equal(t, "svc.ci-bot.project", ServiceAccountID("project", "ci-bot"))
+	assert.NotEqual(t, ServiceAccountID("a_b", "c"), ServiceAccountID("a", "b_c"))
+	assert.NotEqual(t, ServiceAccountID("a-b", "c"), ServiceAccountID("a", "b-c"))
+}
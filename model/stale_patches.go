@@ -0,0 +1,190 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// defaultStalePatchWarning is how long before a stale patch is cleaned up
+// that its author is warned if the project doesn't set its own warning time.
+const defaultStalePatchWarning = 24 * time.Hour
+
+// StalePatchPolicy controls how a project's patches are cleaned up once they
+// go stale, so that abandoned patches don't hold their place in the task
+// queues indefinitely. Authors are warned before their patches are cleaned up
+// and can keep them alive for longer.
+type StalePatchPolicy struct {
+	// UnfinalizedAgeHours is how many hours after it's created that a patch
+	// that was never finalized is archived. Disabled if not positive.
+	UnfinalizedAgeHours int `bson:"unfinalized_age_hours,omitempty" json:"unfinalized_age_hours,omitempty" yaml:"unfinalized_age_hours,omitempty"`
+	// UnfinishedAgeHours is how many hours after it's created that a
+	// finalized patch that hasn't finished is aborted and archived. Disabled
+	// if not positive.
+	UnfinishedAgeHours int `bson:"unfinished_age_hours,omitempty" json:"unfinished_age_hours,omitempty" yaml:"unfinished_age_hours,omitempty"`
+	// WarningHours is how many hours before a patch is cleaned up that its
+	// author is warned.
+	WarningHours int `bson:"warning_hours,omitempty" json:"warning_hours,omitempty" yaml:"warning_hours,omitempty"`
+}
+
+var (
+	stalePatchPolicyUnfinalizedAgeHoursKey = bsonutil.MustHaveTag(StalePatchPolicy{}, "UnfinalizedAgeHours")
+	stalePatchPolicyUnfinishedAgeHoursKey  = bsonutil.MustHaveTag(StalePatchPolicy{}, "UnfinishedAgeHours")
+)
+
+// Validate checks that the stale patch policy is sensible.
+func (s StalePatchPolicy) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(s.UnfinalizedAgeHours < 0, "unfinalized patch age cannot be negative")
+	catcher.NewWhen(s.UnfinishedAgeHours < 0, "unfinished patch age cannot be negative")
+	catcher.NewWhen(s.WarningHours < 0, "warning time cannot be negative")
+	return catcher.Resolve()
+}
+
+// IsEnabled returns whether any of the project's stale patches are cleaned up.
+func (s StalePatchPolicy) IsEnabled() bool {
+	return s.UnfinalizedAgeHours > 0 || s.UnfinishedAgeHours > 0
+}
+
+// GetWarning returns how long before a patch is cleaned up that its author is
+// warned.
+func (s StalePatchPolicy) GetWarning() time.Duration {
+	if s.WarningHours <= 0 {
+		return defaultStalePatchWarning
+	}
+	return time.Duration(s.WarningHours) * time.Hour
+}
+
+// GetMaxAge returns how long after it's created that the patch goes stale, or
+// zero if the policy doesn't clean up patches like it.
+func (s StalePatchPolicy) GetMaxAge(p *patch.Patch) time.Duration {
+	if p.Version == "" {
+		return time.Duration(s.UnfinalizedAgeHours) * time.Hour
+	}
+	return time.Duration(s.UnfinishedAgeHours) * time.Hour
+}
+
+// CleanupTime returns the earliest time that the patch can be cleaned up, or
+// the zero time if the policy doesn't clean up patches like it.
+func (s StalePatchPolicy) CleanupTime(p *patch.Patch) time.Time {
+	maxAge := s.GetMaxAge(p)
+	if maxAge <= 0 || p.IsArchived() || p.IsCommitQueuePatch() {
+		return time.Time{}
+	}
+	if p.Version != "" && evergreen.IsFinishedPatchStatus(p.Status) {
+		return time.Time{}
+	}
+	cleanupTime := p.CreateTime.Add(maxAge)
+	if p.StaleCleanup.KeepAliveUntil.After(cleanupTime) {
+		cleanupTime = p.StaleCleanup.KeepAliveUntil
+	}
+	return cleanupTime
+}
+
+// ShouldWarn returns whether the patch's author should now be warned that the
+// patch will be cleaned up.
+func (s StalePatchPolicy) ShouldWarn(p *patch.Patch, now time.Time) bool {
+	cleanupTime := s.CleanupTime(p)
+	if cleanupTime.IsZero() || !p.StaleCleanup.WarningSentAt.IsZero() {
+		return false
+	}
+	return !now.Before(cleanupTime.Add(-s.GetWarning()))
+}
+
+// ShouldCleanUp returns whether the patch should now be cleaned up. A patch is
+// only cleaned up once its author has been warned for the full warning time,
+// even if it went stale sooner than that.
+func (s StalePatchPolicy) ShouldCleanUp(p *patch.Patch, now time.Time) bool {
+	cleanupTime := s.CleanupTime(p)
+	if cleanupTime.IsZero() || p.StaleCleanup.WarningSentAt.IsZero() {
+		return false
+	}
+	return !now.Before(cleanupTime) && !now.Before(p.StaleCleanup.WarningSentAt.Add(s.GetWarning()))
+}
+
+// FindProjectRefsWithStalePatchPolicy returns the enabled projects that clean
+// up their stale patches.
+func FindProjectRefsWithStalePatchPolicy() ([]ProjectRef, error) {
+	pRefs, err := FindProjectRefsQ(bson.M{
+		"$or": []bson.M{
+			{bsonutil.GetDottedKeyName(projectRefStalePatchPolicyKey, stalePatchPolicyUnfinalizedAgeHoursKey): bson.M{"$gt": 0}},
+			{bsonutil.GetDottedKeyName(projectRefStalePatchPolicyKey, stalePatchPolicyUnfinishedAgeHoursKey): bson.M{"$gt": 0}},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "finding project refs")
+	}
+	enabled := []ProjectRef{}
+	for _, pRef := range pRefs {
+		if pRef.IsEnabled() {
+			enabled = append(enabled, pRef)
+		}
+	}
+	return enabled, nil
+}
+
+// FindStalePatchCandidates returns the project's patches that are stale or
+// will be within the warning time.
+func FindStalePatchCandidates(pRef *ProjectRef, now time.Time) ([]patch.Patch, error) {
+	policy := pRef.StalePatchPolicy
+	candidates := []patch.Patch{}
+	for _, finalized := range []bool{false, true} {
+		maxAge := time.Duration(policy.UnfinalizedAgeHours) * time.Hour
+		if finalized {
+			maxAge = time.Duration(policy.UnfinishedAgeHours) * time.Hour
+		}
+		if maxAge <= 0 {
+			continue
+		}
+		patches, err := patch.Find(patch.ByProjectStaleCandidates(pRef.Id, now.Add(policy.GetWarning()-maxAge), finalized))
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding stale patch candidates for project '%s'", pRef.Id)
+		}
+		candidates = append(candidates, patches...)
+	}
+	return candidates, nil
+}
+
+// CleanUpStalePatch aborts the stale patch's tasks, if it was finalized, and
+// archives it. It returns the tasks that were deactivated or aborted, which no
+// longer take up space in the task queues.
+func CleanUpStalePatch(p *patch.Patch, now time.Time) (AffectedEntities, error) {
+	var affected AffectedEntities
+	if p.Version != "" {
+		reason := task.AbortInfo{User: evergreen.StalePatchCleanupUser}
+		// Find the tasks before they're deactivated, since only a dry run
+		// returns them.
+		var err error
+		affected, err = CancelPatch(p, reason, true)
+		if err != nil {
+			return affected, errors.Wrapf(err, "finding tasks to abort for stale patch '%s'", p.Id.Hex())
+		}
+		if _, err = CancelPatch(p, reason, false); err != nil {
+			return affected, errors.Wrapf(err, "aborting stale patch '%s'", p.Id.Hex())
+		}
+	}
+	return affected, errors.Wrapf(p.MarkArchived(now), "archiving stale patch '%s'", p.Id.Hex())
+}
+
+// KeepStalePatchAlive keeps the patch from being cleaned up as stale for
+// another full max age from now and returns the time it's kept until.
+func KeepStalePatchAlive(p *patch.Patch, policy StalePatchPolicy, now time.Time) (time.Time, error) {
+	if p.IsArchived() {
+		return time.Time{}, errors.Errorf("patch '%s' was already archived", p.Id.Hex())
+	}
+	maxAge := policy.GetMaxAge(p)
+	if maxAge <= 0 {
+		return time.Time{}, errors.Errorf("project '%s' does not clean up patches like patch '%s'", p.Project, p.Id.Hex())
+	}
+	until := now.Add(maxAge)
+	if err := p.KeepAlive(until); err != nil {
+		return time.Time{}, errors.Wrapf(err, "keeping patch '%s' alive", p.Id.Hex())
+	}
+	return until, nil
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStalePatchPolicy(t *testing.T) {
+	now := time.Now()
+	policy := StalePatchPolicy{UnfinalizedAgeHours: 24, UnfinishedAgeHours: 72, WarningHours: 12}
+
+	t.Run("Validate", func(t *testing.T) {
+		assert.NoError(t, policy.Validate())
+		assert.NoError(t, StalePatchPolicy{}.Validate())
+		assert.Error(t, StalePatchPolicy{UnfinalizedAgeHours: -1}.Validate())
+		assert.Error(t, StalePatchPolicy{UnfinishedAgeHours: -1}.Validate())
+		assert.Error(t, StalePatchPolicy{WarningHours: -1}.Validate())
+	})
+	t.Run("IsEnabled", func(t *testing.T) {
+		assert.True(t, policy.IsEnabled())
+		assert.True(t, StalePatchPolicy{UnfinishedAgeHours: 1}.IsEnabled())
+		assert.False(t, StalePatchPolicy{WarningHours: 1}.IsEnabled())
+	})
+	t.Run("GetWarningDefaults", func(t *testing.T) {
+		assert.Equal(t, 12*time.Hour, policy.GetWarning())
+		assert.Equal(t, defaultStalePatchWarning, StalePatchPolicy{}.GetWarning())
+	})
+	t.Run("CleanupTimeUsesAgeForPatchKind", func(t *testing.T) {
+		unfinalized := &patch.Patch{CreateTime: now}
+		assert.Equal(t, now.Add(24*time.Hour), policy.CleanupTime(unfinalized))
+		unfinished := &patch.Patch{CreateTime: now, Version: "v", Status: evergreen.PatchStarted}
+		assert.Equal(t, now.Add(72*time.Hour), policy.CleanupTime(unfinished))
+	})
+	t.Run("CleanupTimeIsZeroForExemptPatches", func(t *testing.T) {
+		assert.Zero(t, policy.CleanupTime(&patch.Patch{CreateTime: now, Version: "v", Status: evergreen.PatchSucceeded}))
+		assert.Zero(t, policy.CleanupTime(&patch.Patch{CreateTime: now, Alias: evergreen.CommitQueueAlias}))
+		assert.Zero(t, policy.CleanupTime(&patch.Patch{CreateTime: now, StaleCleanup: patch.StaleCleanupInfo{ArchivedAt: now}}))
+		assert.Zero(t, StalePatchPolicy{UnfinishedAgeHours: 1}.CleanupTime(&patch.Patch{CreateTime: now}))
+	})
+	t.Run("CleanupTimeHonorsKeepAlive", func(t *testing.T) {
+		p := &patch.Patch{CreateTime: now, StaleCleanup: patch.StaleCleanupInfo{KeepAliveUntil: now.Add(48 * time.Hour)}}
+		assert.Equal(t, now.Add(48*time.Hour), policy.CleanupTime(p))
+	})
+	t.Run("WarnsWithinWarningTime", func(t *testing.T) {
+		p := &patch.Patch{CreateTime: now.Add(-11 * time.Hour)}
+		assert.False(t, policy.ShouldWarn(p, now))
+		p.CreateTime = now.Add(-13 * time.Hour)
+		assert.True(t, policy.ShouldWarn(p, now))
+		assert.False(t, policy.ShouldCleanUp(p, now))
+		p.StaleCleanup.WarningSentAt = now
+		assert.False(t, policy.ShouldWarn(p, now))
+	})
+	t.Run("CleansUpOnlyAfterFullWarning", func(t *testing.T) {
+		p := &patch.Patch{CreateTime: now.Add(-48 * time.Hour)}
+		assert.True(t, policy.ShouldWarn(p, now))
+		assert.False(t, policy.ShouldCleanUp(p, now))
+
+		p.StaleCleanup.WarningSentAt = now.Add(-time.Hour)
+		assert.False(t, policy.ShouldCleanUp(p, now))
+		p.StaleCleanup.WarningSentAt = now.Add(-12 * time.Hour)
+		assert.True(t, policy.ShouldCleanUp(p, now))
+	})
+}
+
+func TestStalePatchCleanup(t *testing.T) {
+	require.NoError(t, db.ClearCollections(patch.Collection, ProjectRefCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(patch.Collection, ProjectRefCollection))
+	}()
+
+	now := time.Now()
+	pRef := ProjectRef{
+		Id:               "p",
+		Identifier:       "p",
+		Enabled:          utility.TruePtr(),
+		StalePatchPolicy: StalePatchPolicy{UnfinalizedAgeHours: 24, WarningHours: 12},
+	}
+	require.NoError(t, pRef.Insert())
+	disabled := ProjectRef{Id: "disabled", Identifier: "disabled", Enabled: utility.TruePtr()}
+	require.NoError(t, disabled.Insert())
+
+	stale := patch.Patch{Id: mgobson.NewObjectId(), Project: "p", Status: evergreen.PatchCreated, CreateTime: now.Add(-20 * time.Hour)}
+	fresh := patch.Patch{Id: mgobson.NewObjectId(), Project: "p", Status: evergreen.PatchCreated, CreateTime: now}
+	finalized := patch.Patch{Id: mgobson.NewObjectId(), Project: "p", Version: "v", Status: evergreen.PatchStarted, CreateTime: now.Add(-48 * time.Hour)}
+	for _, p := range []patch.Patch{stale, fresh, finalized} {
+		require.NoError(t, p.Insert())
+	}
+
+	t.Run("FindsProjectsWithPolicy", func(t *testing.T) {
+		pRefs, err := FindProjectRefsWithStalePatchPolicy()
+		require.NoError(t, err)
+		require.Len(t, pRefs, 1)
+		assert.Equal(t, "p", pRefs[0].Id)
+	})
+	t.Run("FindsCandidatesWithinWarningTime", func(t *testing.T) {
+		candidates, err := FindStalePatchCandidates(&pRef, now)
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		assert.Equal(t, stale.Id, candidates[0].Id)
+	})
+	t.Run("KeepAliveClearsWarning", func(t *testing.T) {
+		p, err := patch.FindOneId(stale.Id.Hex())
+		require.NoError(t, err)
+		require.NoError(t, p.SetStaleWarningSent(now))
+
+		until, err := KeepStalePatchAlive(p, pRef.StalePatchPolicy, now)
+		require.NoError(t, err)
+		assert.True(t, until.Equal(now.Add(24*time.Hour)))
+
+		dbPatch, err := patch.FindOneId(stale.Id.Hex())
+		require.NoError(t, err)
+		assert.Zero(t, dbPatch.StaleCleanup.WarningSentAt)
+		assert.WithinDuration(t, until, dbPatch.StaleCleanup.KeepAliveUntil, time.Second)
+		assert.False(t, pRef.StalePatchPolicy.ShouldWarn(dbPatch, now))
+	})
+	t.Run("KeepAliveFailsWithoutApplicablePolicy", func(t *testing.T) {
+		p, err := patch.FindOneId(finalized.Id.Hex())
+		require.NoError(t, err)
+		_, err = KeepStalePatchAlive(p, pRef.StalePatchPolicy, now)
+		assert.Error(t, err)
+	})
+	t.Run("CleanUpArchivesUnfinalizedPatch", func(t *testing.T) {
+		p, err := patch.FindOneId(fresh.Id.Hex())
+		require.NoError(t, err)
+		affected, err := CleanUpStalePatch(p, now)
+		require.NoError(t, err)
+		assert.Empty(t, affected.TaskIDs)
+
+		dbPatch, err := patch.FindOneId(fresh.Id.Hex())
+		require.NoError(t, err)
+		require.NotNil(t, dbPatch)
+		assert.True(t, dbPatch.IsArchived())
+
+		_, err = KeepStalePatchAlive(dbPatch, pRef.StalePatchPolicy, now)
+		assert.Error(t, err)
+
+		candidates, err := FindStalePatchCandidates(&pRef, now.Add(48*time.Hour))
+		require.NoError(t, err)
+		for _, candidate := range candidates {
+			assert.NotEqual(t, fresh.Id, candidate.Id)
+		}
+
+		listed, err := patch.Find(patch.PatchesByProject("p", now.Add(time.Hour), 10))
+		require.NoError(t, err)
+		for _, listedPatch := range listed {
+			assert.NotEqual(t, fresh.Id, listedPatch.Id)
+		}
+		assert.Error(t, RestartVersion(fresh.Id.Hex(), nil, false, "me"))
+	})
+}
@@ -0,0 +1,180 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	StatusAPIKeysCollection = "status_api_keys"
+
+	// defaultStatusAPIKeyRequestsPerMinute is how many requests a status API
+	// key can make per minute if it doesn't set its own limit.
+	defaultStatusAPIKeyRequestsPerMinute = 60
+	// maxStatusAPIKeyRequestsPerMinute is the most requests per minute that a
+	// status API key can be allowed to make.
+	maxStatusAPIKeyRequestsPerMinute = 6000
+)
+
+// StatusAPIKey is a key for the read-only status API that's scoped to a list
+// of projects. Only a hash of the key is stored, so the key itself is only
+// known when it's created.
+type StatusAPIKey struct {
+	Id      string `bson:"_id" json:"id"`
+	Name    string `bson:"name" json:"name"`
+	KeyHash string `bson:"key_hash" json:"-"`
+	// Projects are the IDs of the projects whose statuses the key can read.
+	Projects []string `bson:"projects" json:"projects"`
+	// RequestsPerMinute is how many requests the key can make per minute.
+	RequestsPerMinute int       `bson:"requests_per_minute,omitempty" json:"requests_per_minute,omitempty"`
+	CreatedBy         string    `bson:"created_by" json:"created_by"`
+	CreateTime        time.Time `bson:"create_time" json:"create_time"`
+
+	// WindowStart and WindowCount track the requests made in the current
+	// minute to enforce the key's rate limit.
+	WindowStart time.Time `bson:"window_start,omitempty" json:"-"`
+	WindowCount int       `bson:"window_count,omitempty" json:"-"`
+}
+
+var (
+	statusAPIKeyIdKey          = bsonutil.MustHaveTag(StatusAPIKey{}, "Id")
+	statusAPIKeyKeyHashKey     = bsonutil.MustHaveTag(StatusAPIKey{}, "KeyHash")
+	statusAPIKeyWindowStartKey = bsonutil.MustHaveTag(StatusAPIKey{}, "WindowStart")
+	statusAPIKeyWindowCountKey = bsonutil.MustHaveTag(StatusAPIKey{}, "WindowCount")
+)
+
+// NewStatusAPIKey returns a new status API key for the projects along with
+// the key itself.
+func NewStatusAPIKey(name string, projects []string, requestsPerMinute int, createdBy string) (*StatusAPIKey, string) {
+	key := utility.RandomString()
+	return &StatusAPIKey{
+		Id:                utility.RandomString(),
+		Name:              name,
+		KeyHash:           hashStatusAPIKey(key),
+		Projects:          projects,
+		RequestsPerMinute: requestsPerMinute,
+		CreatedBy:         createdBy,
+		CreateTime:        time.Now(),
+	}, key
+}
+
+func hashStatusAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// Validate checks that the key has a name, can read at least one project, and
+// has a sensible rate limit.
+func (k *StatusAPIKey) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(k.Name == "", "name must be specified")
+	catcher.NewWhen(len(k.Projects) == 0, "must allow at least one project")
+	catcher.NewWhen(k.RequestsPerMinute < 0, "requests per minute cannot be negative")
+	catcher.ErrorfWhen(k.RequestsPerMinute > maxStatusAPIKeyRequestsPerMinute, "requests per minute cannot exceed %d", maxStatusAPIKeyRequestsPerMinute)
+	return catcher.Resolve()
+}
+
+// GetRequestsPerMinute returns how many requests the key can make per minute.
+func (k *StatusAPIKey) GetRequestsPerMinute() int {
+	if k.RequestsPerMinute <= 0 {
+		return defaultStatusAPIKeyRequestsPerMinute
+	}
+	return k.RequestsPerMinute
+}
+
+// CanAccessProject returns whether the key can read the project's statuses.
+func (k *StatusAPIKey) CanAccessProject(projectID string) bool {
+	return utility.StringSliceContains(k.Projects, projectID)
+}
+
+// Insert inserts the status API key.
+func (k *StatusAPIKey) Insert() error {
+	return db.Insert(StatusAPIKeysCollection, k)
+}
+
+// ConsumeRequest counts a request against the key's rate limit for the
+// current minute. It returns false if the key has already made as many
+// requests as it's allowed to this minute.
+func (k *StatusAPIKey) ConsumeRequest(now time.Time) (bool, error) {
+	windowStart := now.Truncate(time.Minute)
+	counted, err := k.countRequestInWindow(windowStart)
+	if err != nil || counted {
+		return counted, err
+	}
+
+	// This is either the first request in a new window or the key has
+	// exceeded its limit in the current window.
+	err = db.Update(StatusAPIKeysCollection,
+		bson.M{
+			statusAPIKeyIdKey:          k.Id,
+			statusAPIKeyWindowStartKey: bson.M{"$ne": windowStart},
+		},
+		bson.M{"$set": bson.M{
+			statusAPIKeyWindowStartKey: windowStart,
+			statusAPIKeyWindowCountKey: 1,
+		}},
+	)
+	if adb.ResultsNotFound(err) {
+		// A concurrent request may have just started the new window.
+		return k.countRequestInWindow(windowStart)
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "starting new request window")
+	}
+	return true, nil
+}
+
+// countRequestInWindow counts a request in the window if the window is the
+// key's current window and the key is under its limit.
+func (k *StatusAPIKey) countRequestInWindow(windowStart time.Time) (bool, error) {
+	err := db.Update(StatusAPIKeysCollection,
+		bson.M{
+			statusAPIKeyIdKey:          k.Id,
+			statusAPIKeyWindowStartKey: windowStart,
+			statusAPIKeyWindowCountKey: bson.M{"$lt": k.GetRequestsPerMinute()},
+		},
+		bson.M{"$inc": bson.M{statusAPIKeyWindowCountKey: 1}},
+	)
+	if adb.ResultsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "counting request in current window")
+	}
+	return true, nil
+}
+
+// FindStatusAPIKeyByKey returns the status API key, or nil if it doesn't
+// exist.
+func FindStatusAPIKeyByKey(key string) (*StatusAPIKey, error) {
+	k := &StatusAPIKey{}
+	err := db.FindOneQ(StatusAPIKeysCollection, db.Query(bson.M{statusAPIKeyKeyHashKey: hashStatusAPIKey(key)}), k)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "finding status API key")
+	}
+	return k, nil
+}
+
+// FindAllStatusAPIKeys returns all status API keys.
+func FindAllStatusAPIKeys() ([]StatusAPIKey, error) {
+	keys := []StatusAPIKey{}
+	err := db.FindAllQ(StatusAPIKeysCollection, db.Query(bson.M{}).Sort([]string{statusAPIKeyIdKey}), &keys)
+	return keys, errors.Wrap(err, "finding status API keys")
+}
+
+// RemoveStatusAPIKey removes the status API key with the ID.
+func RemoveStatusAPIKey(id string) error {
+	return db.Remove(StatusAPIKeysCollection, bson.M{statusAPIKeyIdKey: id})
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusAPIKey(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(StatusAPIKeysCollection))
+	}()
+
+	t.Run("Validate", func(t *testing.T) {
+		k, _ := NewStatusAPIKey("dashboard", []string{"project"}, 0, "me")
+		assert.NoError(t, k.Validate())
+		assert.Equal(t, defaultStatusAPIKeyRequestsPerMinute, k.GetRequestsPerMinute())
+
+		k, _ = NewStatusAPIKey("", nil, maxStatusAPIKeyRequestsPerMinute+1, "me")
+		assert.Error(t, k.Validate())
+	})
+	t.Run("FindByKey", func(t *testing.T) {
+		require.NoError(t, db.ClearCollections(StatusAPIKeysCollection))
+		k, key := NewStatusAPIKey("dashboard", []string{"project"}, 10, "me")
+		require.NoError(t, k.Insert())
+		assert.NotEqual(t, key, k.KeyHash)
+
+		found, err := FindStatusAPIKeyByKey(key)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, k.Id, found.Id)
+		assert.True(t, found.CanAccessProject("project"))
+		assert.False(t, found.CanAccessProject("other_project"))
+
+		found, err = FindStatusAPIKeyByKey("not_a_key")
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+
+		require.NoError(t, RemoveStatusAPIKey(k.Id))
+		found, err = FindStatusAPIKeyByKey(key)
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+	})
+	t.Run("ConsumeRequest", func(t *testing.T) {
+		require.NoError(t, db.ClearCollections(StatusAPIKeysCollection))
+		k, _ := NewStatusAPIKey("dashboard", []string{"project"}, 3, "me")
+		require.NoError(t, k.Insert())
+
+		now := time.Now().Truncate(time.Minute)
+		for i := 0; i < 3; i++ {
+			allowed, err := k.ConsumeRequest(now.Add(time.Duration(i) * time.Second))
+			require.NoError(t, err)
+			assert.True(t, allowed, "request %d should be allowed", i)
+		}
+		allowed, err := k.ConsumeRequest(now.Add(30 * time.Second))
+		require.NoError(t, err)
+		assert.False(t, allowed, "request over the limit should not be allowed")
+
+		allowed, err = k.ConsumeRequest(now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, allowed, "request in the next window should be allowed")
+	})
+}
+
+func TestGetLatestMainlineStatus(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection, build.Collection))
+	}()
+	require.NoError(t, db.ClearCollections(VersionCollection, build.Collection))
+
+	status, err := GetLatestMainlineStatus("project", "")
+	require.NoError(t, err)
+	assert.Empty(t, status)
+
+	versions := []Version{
+		{Id: "v1", Identifier: "project", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 1, Status: evergreen.VersionSucceeded},
+		{Id: "v2", Identifier: "project", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 2, Status: evergreen.VersionFailed},
+		{Id: "v3", Identifier: "project", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 3, Status: evergreen.VersionStarted},
+		{Id: "patch", Identifier: "project", Requester: evergreen.PatchVersionRequester, RevisionOrderNumber: 4, Status: evergreen.VersionSucceeded},
+	}
+	for _, v := range versions {
+		require.NoError(t, v.Insert())
+	}
+	builds := []build.Build{
+		{Id: "b1", Project: "project", BuildVariant: "bv", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 1, Status: evergreen.BuildFailed},
+		{Id: "b2", Project: "project", BuildVariant: "bv", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 2, Status: evergreen.BuildSucceeded},
+		{Id: "b3", Project: "project", BuildVariant: "bv", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 3, Status: evergreen.BuildStarted},
+	}
+	for _, b := range builds {
+		require.NoError(t, b.Insert())
+	}
+
+	status, err = GetLatestMainlineStatus("project", "")
+	require.NoError(t, err)
+	assert.Equal(t, evergreen.VersionFailed, status)
+
+	status, err = GetLatestMainlineStatus("project", "bv")
+	require.NoError(t, err)
+	assert.Equal(t, evergreen.BuildSucceeded, status)
+
+	status, err = GetLatestMainlineStatus("project", "other_bv")
+	require.NoError(t, err)
+	assert.Empty(t, status)
+}
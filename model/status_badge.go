@@ -0,0 +1,43 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GetLatestMainlineStatus returns the status of the project's most recent
+// finished mainline version, or of the variant's build in the most recent
+// mainline version where the variant finished if a variant is given. It
+// returns an empty status if nothing has finished yet.
+func GetLatestMainlineStatus(projectID, variant string) (string, error) {
+	if variant != "" {
+		q := build.ByProjectAndVariant(projectID, variant, evergreen.RepotrackerVersionRequester, build.CompletedStatuses).
+			WithFields(build.StatusKey).
+			Sort([]string{"-" + build.RevisionOrderNumberKey})
+		b, err := build.FindOne(q)
+		if err != nil {
+			return "", errors.Wrapf(err, "finding latest finished build for variant '%s'", variant)
+		}
+		if b == nil {
+			return "", nil
+		}
+		return b.Status, nil
+	}
+
+	q := db.Query(bson.M{
+		VersionIdentifierKey: projectID,
+		VersionRequesterKey:  evergreen.RepotrackerVersionRequester,
+		VersionStatusKey:     bson.M{"$in": []string{evergreen.VersionSucceeded, evergreen.VersionFailed}},
+	}).WithFields(VersionStatusKey).Sort([]string{"-" + VersionRevisionOrderNumberKey})
+	v, err := VersionFindOne(q)
+	if err != nil {
+		return "", errors.Wrap(err, "finding latest finished version")
+	}
+	if v == nil {
+		return "", nil
+	}
+	return v.Status, nil
+}
@@ -0,0 +1,601 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Event-sourced status rollups compute a build's status from counters that
+// are updated as its tasks change status, and a version's status from
+// counters that are updated as its builds change status, instead of scanning
+// every task or build. Each task or build is counted in every counter whose
+// condition it meets, which is enough to compute the same status that
+// getBuildStatus and getVersionStatus compute from a scan.
+const (
+	rollupTotal            = "total"
+	rollupCreated          = "created"
+	rollupUnstarted        = "unstarted"
+	rollupUnstartedBlocked = "unstarted_blocked"
+	rollupInProgress       = "in_progress"
+	rollupFailed           = "failed"
+	rollupAborted          = "aborted"
+	rollupFailedNotAborted = "failed_not_aborted"
+	// rollupSkipped counts skipped tasks, which aren't counted in any other
+	// counter because they don't affect the build's status unless every task
+	// in the build is skipped.
+	rollupSkipped = "skipped"
+)
+
+// rollupTaskFields are the task fields needed to count a task in its build's
+// status rollup.
+var rollupTaskFields = []string{task.BuildIdKey, task.StatusKey, task.ActivatedKey, task.DependsOnKey, task.AbortedKey, task.IsGithubCheckKey, task.RollupFlagsKey}
+
+// taskRollupFlags returns the build status rollup counters that the task is
+// counted in.
+func taskRollupFlags(t *task.Task) []string {
+	if t.Status == evergreen.TaskSkipped {
+		return []string{rollupSkipped}
+	}
+	flags := []string{rollupTotal}
+	unstarted := evergreen.IsUnstartedTaskStatus(t.Status)
+	if unstarted {
+		flags = append(flags, rollupUnstarted)
+		if t.Blocked() {
+			flags = append(flags, rollupUnstartedBlocked)
+		}
+	}
+	if t.Status == evergreen.TaskStarted || (t.Activated && !t.Blocked() && !t.IsFinished()) {
+		flags = append(flags, rollupInProgress)
+	}
+	if evergreen.IsFailedTaskStatus(t.Status) || t.Aborted {
+		flags = append(flags, rollupFailed)
+	}
+	if t.Aborted {
+		flags = append(flags, rollupAborted)
+	} else if evergreen.IsFailedTaskStatus(t.Status) {
+		flags = append(flags, rollupFailedNotAborted)
+	}
+	return flags
+}
+
+// buildRollupFlags returns the version status rollup counters that the build
+// is counted in.
+func buildRollupFlags(b *build.Build) []string {
+	flags := []string{rollupTotal}
+	if b.Status == evergreen.BuildCreated {
+		flags = append(flags, rollupCreated)
+	}
+	if b.Activated && !evergreen.IsFinishedBuildStatus(b.Status) && !b.AllTasksBlocked {
+		flags = append(flags, rollupInProgress)
+	}
+	if b.Status == evergreen.BuildFailed || b.Aborted {
+		flags = append(flags, rollupFailed)
+	}
+	if b.Aborted {
+		flags = append(flags, rollupAborted)
+	}
+	return flags
+}
+
+// rollupDeltas returns how much each counter changes when an item moves from
+// the old counters to the new ones.
+func rollupDeltas(oldFlags, newFlags []string) map[string]int {
+	deltas := map[string]int{}
+	for _, flag := range oldFlags {
+		if !utility.StringSliceContains(newFlags, flag) {
+			deltas[flag]--
+		}
+	}
+	for _, flag := range newFlags {
+		if !utility.StringSliceContains(oldFlags, flag) {
+			deltas[flag]++
+		}
+	}
+	return deltas
+}
+
+// buildStatusFromRollup returns the build status, whether all of the build's
+// tasks are blocked, and whether the build is aborted according to the
+// build's status rollup counters.
+func buildStatusFromRollup(counts map[string]int) (string, bool, bool) {
+	total := counts[rollupTotal]
+	aborted := counts[rollupAborted] > 0 && counts[rollupFailedNotAborted] == 0
+	if total == 0 && counts[rollupSkipped] > 0 {
+		return evergreen.BuildSucceeded, false, false
+	}
+	if counts[rollupUnstarted] == total {
+		return evergreen.BuildCreated, counts[rollupUnstartedBlocked] == total, aborted
+	}
+	if counts[rollupInProgress] > 0 {
+		return evergreen.BuildStarted, false, aborted
+	}
+	if counts[rollupFailed] > 0 {
+		return evergreen.BuildFailed, false, aborted
+	}
+	return evergreen.BuildSucceeded, false, aborted
+}
+
+// versionStatusFromRollup returns the version status and whether the version
+// is aborted according to the version's status rollup counters.
+func versionStatusFromRollup(counts map[string]int) (string, bool) {
+	aborted := counts[rollupAborted] > 0
+	if counts[rollupCreated] == counts[rollupTotal] {
+		return evergreen.VersionCreated, aborted
+	}
+	if counts[rollupInProgress] > 0 {
+		return evergreen.VersionStarted, aborted
+	}
+	if counts[rollupFailed] > 0 {
+		return evergreen.VersionFailed, aborted
+	}
+	return evergreen.VersionSucceeded, aborted
+}
+
+// isEventSourcedStatusRollupEnabled returns whether the project computes its
+// build and version statuses from status rollup counters.
+func isEventSourcedStatusRollupEnabled(projectID string) bool {
+	pRef, err := FindMergedProjectRef(projectID, "", false)
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "could not find project to check for event-sourced status rollups",
+			"project": projectID,
+		}))
+		return false
+	}
+	return pRef != nil && pRef.IsEventSourcedStatusRollupEnabled()
+}
+
+// withRollupTransaction runs the status rollup updates in a transaction, so
+// that an item's rollup flags and its parent's counters are always written
+// together, and so that concurrent updates to the same item or parent
+// conflict and are retried instead of being lost.
+func withRollupTransaction(txFunc func(sessCtx mongo.SessionContext) error) error {
+	env := evergreen.GetEnvironment()
+	ctx, cancel := env.Context()
+	defer cancel()
+	session, err := env.Client().StartSession()
+	if err != nil {
+		return errors.Wrap(err, "starting DB session")
+	}
+	defer session.EndSession(ctx)
+
+	txOpts := options.Transaction().SetReadConcern(readconcern.Snapshot()).SetReadPreference(readpref.Primary())
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, txFunc(sessCtx)
+	}, txOpts)
+	return err
+}
+
+// setRollupFlags sets the status rollup counters that the item in the
+// collection is counted in, if they are still the given old flags. It
+// returns false if the item's flags were changed by someone else.
+func setRollupFlags(sessCtx mongo.SessionContext, collection, id, flagsKey string, oldFlags, newFlags []string) (bool, error) {
+	query := bson.M{"_id": id}
+	if len(oldFlags) == 0 {
+		query[flagsKey] = bson.M{"$exists": false}
+	} else {
+		query[flagsKey] = oldFlags
+	}
+	res, err := evergreen.GetEnvironment().DB().Collection(collection).UpdateOne(sessCtx, query, bson.M{"$set": bson.M{flagsKey: newFlags}})
+	if err != nil {
+		return false, errors.Wrapf(err, "setting rollup flags for '%s'", id)
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// moveRollup moves the item in the collection from the old status rollup
+// counters to the new ones and applies the change to its parent's counters
+// in the same transaction. It returns false if the item's flags were changed
+// by someone else, in which case nothing is written.
+func moveRollup(collection, id, flagsKey string, oldFlags, newFlags []string, parentCollection, parentID, countsKey string) (bool, error) {
+	inc := bson.M{}
+	for counter, delta := range rollupDeltas(oldFlags, newFlags) {
+		inc[bsonutil.GetDottedKeyName(countsKey, counter)] = delta
+	}
+	var moved bool
+	err := withRollupTransaction(func(sessCtx mongo.SessionContext) error {
+		var err error
+		moved, err = setRollupFlags(sessCtx, collection, id, flagsKey, oldFlags, newFlags)
+		if err != nil || !moved {
+			return err
+		}
+		_, err = evergreen.GetEnvironment().DB().Collection(parentCollection).UpdateOne(sessCtx, bson.M{"_id": parentID}, bson.M{"$inc": inc})
+		return errors.Wrapf(err, "updating rollup counts for '%s'", parentID)
+	})
+	return moved, err
+}
+
+// recountRollup recounts the parent's status rollup counters from its items
+// and sets each item's flags to the counters it's counted in, all in one
+// transaction so that it can't overwrite items that are moved concurrently.
+func recountRollup(collection string, query bson.M, fields []string, flagsKey string, parentCollection, parentID, countsKey string, itemFlags func(*mongo.Cursor) (string, []string, []string, error)) (map[string]int, error) {
+	projection := bson.M{}
+	for _, field := range fields {
+		projection[field] = 1
+	}
+	var counts map[string]int
+	err := withRollupTransaction(func(sessCtx mongo.SessionContext) error {
+		database := evergreen.GetEnvironment().DB()
+		cursor, err := database.Collection(collection).Find(sessCtx, query, options.Find().SetProjection(projection))
+		if err != nil {
+			return errors.Wrapf(err, "finding items counted in '%s'", parentID)
+		}
+		defer cursor.Close(sessCtx)
+
+		counts = map[string]int{rollupTotal: 0}
+		for cursor.Next(sessCtx) {
+			id, oldFlags, flags, err := itemFlags(cursor)
+			if err != nil {
+				return err
+			}
+			for _, flag := range flags {
+				counts[flag]++
+			}
+			if len(rollupDeltas(oldFlags, flags)) == 0 {
+				continue
+			}
+			if _, err = database.Collection(collection).UpdateOne(sessCtx, bson.M{"_id": id}, bson.M{"$set": bson.M{flagsKey: flags}}); err != nil {
+				return errors.Wrapf(err, "setting rollup flags for '%s'", id)
+			}
+		}
+		if err = cursor.Err(); err != nil {
+			return errors.Wrapf(err, "iterating over items counted in '%s'", parentID)
+		}
+		_, err = database.Collection(parentCollection).UpdateOne(sessCtx, bson.M{"_id": parentID}, bson.M{"$set": bson.M{countsKey: counts}})
+		return errors.Wrapf(err, "setting rollup counts for '%s'", parentID)
+	})
+	return counts, err
+}
+
+// resetBuildRollup recounts the build's status rollup counters by scanning
+// its tasks.
+func resetBuildRollup(b *build.Build) (map[string]int, error) {
+	counts, err := recountRollup(task.Collection, task.ByBuildId(b.Id), rollupTaskFields, task.RollupFlagsKey, build.Collection, b.Id, build.RollupCountsKey,
+		func(cursor *mongo.Cursor) (string, []string, []string, error) {
+			t := task.Task{}
+			if err := cursor.Decode(&t); err != nil {
+				return "", nil, nil, errors.Wrap(err, "decoding task")
+			}
+			return t.Id, t.RollupFlags, taskRollupFlags(&t), nil
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "recounting status rollup for build '%s'", b.Id)
+	}
+	b.RollupCounts = counts
+	return counts, nil
+}
+
+// resetVersionRollup recounts the version's status rollup counters by
+// scanning its builds.
+func resetVersionRollup(v *Version) (map[string]int, error) {
+	fields := []string{build.ActivatedKey, build.StatusKey, build.AbortedKey, build.AllTasksBlockedKey, build.RollupFlagsKey}
+	counts, err := recountRollup(build.Collection, bson.M{build.VersionKey: v.Id}, fields, build.RollupFlagsKey, VersionCollection, v.Id, VersionRollupCountsKey,
+		func(cursor *mongo.Cursor) (string, []string, []string, error) {
+			b := build.Build{}
+			if err := cursor.Decode(&b); err != nil {
+				return "", nil, nil, errors.Wrap(err, "decoding build")
+			}
+			return b.Id, b.RollupFlags, buildRollupFlags(&b), nil
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "recounting status rollup for version '%s'", v.Id)
+	}
+	v.RollupCounts = counts
+	return counts, nil
+}
+
+// syncTaskRollup moves the task to the build status rollup counters that
+// match its current state and returns the build's updated counters. Moving
+// the task is atomic, so concurrent updates for the same task change the
+// counters only once.
+func syncTaskRollup(b *build.Build, taskID string) (map[string]int, error) {
+	if b.RollupCounts == nil {
+		return resetBuildRollup(b)
+	}
+	t, err := task.FindOne(db.Query(task.ById(taskID)).WithFields(rollupTaskFields...))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding task '%s'", taskID)
+	}
+	if t == nil {
+		return nil, errors.Errorf("task '%s' not found", taskID)
+	}
+	flags := taskRollupFlags(t)
+	if len(rollupDeltas(t.RollupFlags, flags)) == 0 {
+		return b.RollupCounts, nil
+	}
+	if _, err = moveRollup(task.Collection, t.Id, task.RollupFlagsKey, t.RollupFlags, flags, build.Collection, b.Id, build.RollupCountsKey); err != nil {
+		return nil, errors.Wrapf(err, "moving task '%s' in the status rollup for build '%s'", t.Id, b.Id)
+	}
+	updated, err := build.FindOneId(b.Id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding build '%s'", b.Id)
+	}
+	if updated == nil {
+		return nil, errors.Errorf("build '%s' not found", b.Id)
+	}
+	b.RollupCounts = updated.RollupCounts
+	return b.RollupCounts, nil
+}
+
+// syncBuildRollup moves the build to the version status rollup counters that
+// match its current state and returns the version's updated counters.
+func syncBuildRollup(v *Version, b *build.Build) (map[string]int, error) {
+	if v.RollupCounts == nil {
+		return resetVersionRollup(v)
+	}
+	flags := buildRollupFlags(b)
+	if len(rollupDeltas(b.RollupFlags, flags)) == 0 {
+		return v.RollupCounts, nil
+	}
+	moved, err := moveRollup(build.Collection, b.Id, build.RollupFlagsKey, b.RollupFlags, flags, VersionCollection, v.Id, VersionRollupCountsKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "moving build '%s' in the status rollup for version '%s'", b.Id, v.Id)
+	}
+	if moved {
+		b.RollupFlags = flags
+	}
+	updated, err := VersionFindOneId(v.Id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding version '%s'", v.Id)
+	}
+	if updated == nil {
+		return nil, errors.Errorf("version '%s' not found", v.Id)
+	}
+	v.RollupCounts = updated.RollupCounts
+	return v.RollupCounts, nil
+}
+
+// syncDependentRollups updates the builds and versions of the tasks whose
+// dependencies were just marked attainable or unattainable, since that
+// changes whether they're counted as blocked. Only projects that use
+// event-sourced status rollups need this, since scanning a build's tasks
+// already sees their current state.
+func syncDependentRollups(dependents map[string]task.Task) error {
+	rollupProjects := map[string]bool{}
+	catcher := grip.NewBasicCatcher()
+	for id := range dependents {
+		t := dependents[id]
+		if _, ok := rollupProjects[t.Project]; !ok {
+			rollupProjects[t.Project] = isEventSourcedStatusRollupEnabled(t.Project)
+		}
+		if !rollupProjects[t.Project] {
+			continue
+		}
+		catcher.Wrapf(UpdateBuildAndVersionStatusForTask(&t), "updating build and version status for dependent task '%s'", t.Id)
+	}
+	return catcher.Resolve()
+}
+
+// setBuildStatusFromRollup updates the build with the status from its status
+// rollup counters. The build's tasks are only scanned when its status
+// changes, to update its makespans, timing and GitHub check status. It
+// returns true if the build's status changed or if all of the build's tasks
+// became blocked.
+func setBuildStatusFromRollup(b *build.Build, counts map[string]int) (bool, error) {
+	buildStatus, allTasksBlocked, isAborted := buildStatusFromRollup(counts)
+	blockedChanged := allTasksBlocked != b.AllTasksBlocked
+	if err := b.SetAllTasksBlocked(allTasksBlocked); err != nil {
+		return false, errors.Wrapf(err, "setting build '%s' as blocked", b.Id)
+	}
+	if buildStatus == b.Status {
+		return blockedChanged, nil
+	}
+	if err := b.SetAborted(isAborted); err != nil {
+		return false, errors.Wrapf(err, "setting build '%s' as aborted", b.Id)
+	}
+
+	event.LogBuildStateChangeEvent(b.Id, buildStatus)
+
+	buildTasks, err := task.FindWithFields(task.ByBuildId(b.Id), task.StatusKey, task.ActivatedKey, task.DependsOnKey, task.IsGithubCheckKey, task.AbortedKey,
+		task.DisplayOnlyKey, task.ActivatedTimeKey, task.ScheduledTimeKey, task.DependenciesMetTimeKey, task.StartTimeKey, task.FinishTimeKey)
+	if err != nil {
+		return true, errors.Wrapf(err, "getting tasks in build '%s'", b.Id)
+	}
+	if err = b.SetTimingBreakdown(task.GetTimingBreakdown(buildTasks)); err != nil {
+		return true, errors.Wrapf(err, "setting timing breakdown for build '%s'", b.Id)
+	}
+	if evergreen.IsFinishedBuildStatus(buildStatus) {
+		if err = b.MarkFinished(buildStatus, time.Now()); err != nil {
+			return true, errors.Wrapf(err, "marking build as finished with status '%s'", buildStatus)
+		}
+		if err = updateMakespans(b, buildTasks); err != nil {
+			return true, errors.Wrapf(err, "updating makespan information for '%s'", b.Id)
+		}
+	} else {
+		if err = b.UpdateStatus(buildStatus); err != nil {
+			return true, errors.Wrap(err, "updating build status")
+		}
+	}
+	if err = updateBuildGithubStatus(b, buildTasks); err != nil {
+		return true, errors.Wrap(err, "updating build GitHub status")
+	}
+
+	return true, nil
+}
+
+// setVersionStatusFromRollup updates the version with the status from its
+// status rollup counters and returns the version's status. As with builds,
+// the version's builds are only scanned when its status changes.
+func setVersionStatusFromRollup(v *Version, counts map[string]int) (string, error) {
+	versionStatus, isAborted := versionStatusFromRollup(counts)
+	if versionStatus == v.Status {
+		return versionStatus, nil
+	}
+	if isAborted != v.Aborted {
+		if err := v.SetAborted(isAborted); err != nil {
+			return "", errors.Wrapf(err, "setting version '%s' as aborted", v.Id)
+		}
+	}
+
+	event.LogVersionStateChangeEvent(v.Id, versionStatus)
+
+	builds, err := build.Find(build.ByVersion(v.Id).WithFields(build.ActivatedKey, build.StatusKey,
+		build.IsGithubCheckKey, build.GithubCheckStatusKey, build.AbortedKey, build.TimingKey))
+	if err != nil {
+		return "", errors.Wrapf(err, "getting builds for version '%s'", v.Id)
+	}
+	var timing task.TimingBreakdown
+	for _, b := range builds {
+		timing = timing.Add(b.Timing)
+	}
+	if err = v.SetTimingBreakdown(timing); err != nil {
+		return "", errors.Wrapf(err, "setting timing breakdown for version '%s'", v.Id)
+	}
+	if err = updateVersionGithubStatus(v, builds); err != nil {
+		return "", errors.Wrap(err, "updating version GitHub status")
+	}
+
+	if evergreen.IsFinishedVersionStatus(versionStatus) {
+		if err = v.MarkFinished(versionStatus, time.Now()); err != nil {
+			return "", errors.Wrapf(err, "marking version '%s' as finished with status '%s'", v.Id, versionStatus)
+		}
+	} else {
+		if err = v.UpdateStatus(versionStatus); err != nil {
+			return "", errors.Wrapf(err, "updating version '%s' with status '%s'", v.Id, versionStatus)
+		}
+	}
+
+	return versionStatus, nil
+}
+
+// updateBuildStatusForTaskFromRollup updates the status of the task's build
+// from the build's status rollup counters after the task has changed.
+func updateBuildStatusForTaskFromRollup(b *build.Build, taskID string) (bool, error) {
+	counts, err := syncTaskRollup(b, taskID)
+	if err != nil {
+		return false, errors.Wrapf(err, "updating status rollup for task '%s'", taskID)
+	}
+	return setBuildStatusFromRollup(b, counts)
+}
+
+// updateVersionStatusForBuildFromRollup updates the status of the build's
+// version from the version's status rollup counters after the build has
+// changed.
+func updateVersionStatusForBuildFromRollup(v *Version, b *build.Build) (string, error) {
+	updatedBuild, err := build.FindOneId(b.Id)
+	if err != nil {
+		return "", errors.Wrapf(err, "finding build '%s'", b.Id)
+	}
+	if updatedBuild == nil {
+		return "", errors.Errorf("build '%s' not found", b.Id)
+	}
+	counts, err := syncBuildRollup(v, updatedBuild)
+	if err != nil {
+		return "", errors.Wrapf(err, "updating status rollup for build '%s'", b.Id)
+	}
+	return setVersionStatusFromRollup(v, counts)
+}
+
+// updateBuildStatusFromRecountedRollup recounts the build's status rollup
+// counters and updates the build's status from them.
+func updateBuildStatusFromRecountedRollup(b *build.Build) (bool, error) {
+	counts, err := resetBuildRollup(b)
+	if err != nil {
+		return false, err
+	}
+	return setBuildStatusFromRollup(b, counts)
+}
+
+// updateVersionStatusFromRecountedRollup recounts the version's status
+// rollup counters and updates the version's status from them.
+func updateVersionStatusFromRecountedRollup(v *Version) (string, error) {
+	counts, err := resetVersionRollup(v)
+	if err != nil {
+		return "", err
+	}
+	return setVersionStatusFromRollup(v, counts)
+}
+
+// RollupReconciliation compares a build's event-sourced status rollup to the
+// status computed by scanning its tasks.
+type RollupReconciliation struct {
+	BuildID string
+	// RollupStatus is the build status according to its stored counters and
+	// ScanStatus is the status according to its tasks.
+	RollupStatus string
+	ScanStatus   string
+	// CountsMatched is whether the stored counters matched the counters
+	// recounted from the build's tasks.
+	CountsMatched bool
+	// RollupLatency and ScanLatency are how long it took to compute the
+	// build's status each way.
+	RollupLatency time.Duration
+	ScanLatency   time.Duration
+}
+
+// ReconcileBuildRollup compares the build's status rollup counters against
+// a scan of its tasks and replaces them with the recounted counters if they
+// disagree. If the build's counters were corrected, its status and its
+// version's counters and status are updated as well.
+func ReconcileBuildRollup(buildID string) (*RollupReconciliation, error) {
+	rollupStart := time.Now()
+	b, err := build.FindOneId(buildID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding build '%s'", buildID)
+	}
+	if b == nil {
+		return nil, errors.Errorf("build '%s' not found", buildID)
+	}
+	stored := b.RollupCounts
+	rollupStatus, _, _ := buildStatusFromRollup(stored)
+	rec := &RollupReconciliation{
+		BuildID:       b.Id,
+		RollupStatus:  rollupStatus,
+		RollupLatency: time.Since(rollupStart),
+	}
+
+	scanStart := time.Now()
+	buildTasks, err := task.FindWithFields(task.ByBuildId(b.Id), rollupTaskFields...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting tasks in build '%s'", b.Id)
+	}
+	rec.ScanStatus, _ = getBuildStatus(buildTasks)
+	rec.ScanLatency = time.Since(scanStart)
+
+	recounted := map[string]int{rollupTotal: 0}
+	for i := range buildTasks {
+		for _, flag := range taskRollupFlags(&buildTasks[i]) {
+			recounted[flag]++
+		}
+	}
+	rec.CountsMatched = rollupCountsEqual(stored, recounted)
+	if rec.CountsMatched {
+		return rec, nil
+	}
+
+	// Recounting the build's counters can change its status, which in turn
+	// can change its version's and patch's statuses.
+	if err = UpdateVersionAndPatchStatusForBuilds([]string{b.Id}); err != nil {
+		return rec, errors.Wrapf(err, "updating statuses from recounted status rollup for build '%s'", b.Id)
+	}
+	return rec, nil
+}
+
+func rollupCountsEqual(a, b map[string]int) bool {
+	for counter, count := range a {
+		if b[counter] != count {
+			return false
+		}
+	}
+	for counter, count := range b {
+		if a[counter] != count {
+			return false
+		}
+	}
+	return true
+}
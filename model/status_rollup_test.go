@@ -0,0 +1,162 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestRollupStatusMatchesScan(t *testing.T) {
+	blocked := []task.Dependency{{Unattainable: true}}
+	for name, buildTasks := range map[string][]task.Task{
+		"Empty":         {},
+		"Unstarted":     {{Status: evergreen.TaskUndispatched}, {Status: evergreen.TaskUndispatched}},
+		"Started":       {{Status: evergreen.TaskUndispatched, Activated: true}, {Status: evergreen.TaskStarted}},
+		"Pending":       {{Status: evergreen.TaskUndispatched, Activated: true}, {Status: evergreen.TaskSucceeded}},
+		"Unactivated":   {{Status: evergreen.TaskUndispatched}, {Status: evergreen.TaskFailed}},
+		"PartlyBlocked": {{Status: evergreen.TaskUndispatched, DependsOn: blocked}, {Status: evergreen.TaskSucceeded}},
+		"AllBlocked":    {{Status: evergreen.TaskUndispatched, DependsOn: blocked}, {Status: evergreen.TaskUndispatched, DependsOn: blocked}},
+		"Aborted":       {{Status: evergreen.TaskFailed, Aborted: true}, {Status: evergreen.TaskSucceeded}},
+		"AbortedFailed": {{Status: evergreen.TaskFailed, Aborted: true}, {Status: evergreen.TaskFailed}},
+		"PartlySkipped": {{Status: evergreen.TaskSkipped}, {Status: evergreen.TaskUndispatched, Activated: true}},
+		"SkippedFailed": {{Status: evergreen.TaskSkipped}, {Status: evergreen.TaskFailed}},
+		"AllSkipped":    {{Status: evergreen.TaskSkipped}, {Status: evergreen.TaskSkipped}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			counts := map[string]int{rollupTotal: 0}
+			for i := range buildTasks {
+				for _, flag := range taskRollupFlags(&buildTasks[i]) {
+					counts[flag]++
+				}
+			}
+			expectedStatus, expectedBlocked := getBuildStatus(buildTasks)
+			status, allTasksBlocked, _ := buildStatusFromRollup(counts)
+			assert.Equal(t, expectedStatus, status)
+			assert.Equal(t, expectedBlocked, allTasksBlocked)
+		})
+	}
+
+	for name, versionBuilds := range map[string][]build.Build{
+		"Unstarted":   {{Status: evergreen.BuildCreated}, {Status: evergreen.BuildCreated}},
+		"Started":     {{Status: evergreen.BuildCreated, Activated: true}, {Status: evergreen.BuildStarted}},
+		"Unactivated": {{Status: evergreen.BuildCreated}, {Status: evergreen.BuildFailed}},
+		"Blocked":     {{Status: evergreen.BuildCreated, Activated: true, AllTasksBlocked: true}, {Status: evergreen.BuildSucceeded}},
+	} {
+		t.Run("Version"+name, func(t *testing.T) {
+			counts := map[string]int{rollupTotal: 0}
+			for i := range versionBuilds {
+				for _, flag := range buildRollupFlags(&versionBuilds[i]) {
+					counts[flag]++
+				}
+			}
+			status, _ := versionStatusFromRollup(counts)
+			assert.Equal(t, getVersionStatus(versionBuilds), status)
+		})
+	}
+}
+
+func TestEventSourcedStatusRollup(t *testing.T) {
+	colls := []string{task.Collection, task.OldCollection, build.Collection, VersionCollection, ProjectRefCollection, event.AllLogCollection}
+	require.NoError(t, db.ClearCollections(colls...))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(colls...))
+	}()
+
+	pRef := ProjectRef{Id: "sample", EventSourcedStatusRollup: utility.TruePtr()}
+	require.NoError(t, pRef.Insert())
+	b1 := &build.Build{Id: "b1", Project: pRef.Id, Status: evergreen.BuildCreated, Version: "v1", Activated: true}
+	b2 := &build.Build{Id: "b2", Project: pRef.Id, Status: evergreen.BuildCreated, Version: "v1", Activated: true}
+	v := &Version{Id: "v1", Identifier: pRef.Id, Status: evergreen.VersionCreated}
+	require.NoError(t, b1.Insert())
+	require.NoError(t, b2.Insert())
+	require.NoError(t, v.Insert())
+	t1 := task.Task{Id: "t1", Activated: true, BuildId: b1.Id, Version: v.Id, Project: pRef.Id, Status: evergreen.TaskUndispatched}
+	t2 := task.Task{Id: "t2", Activated: true, BuildId: b1.Id, Version: v.Id, Project: pRef.Id, Status: evergreen.TaskUndispatched}
+	t3 := task.Task{Id: "t3", Activated: true, BuildId: b2.Id, Version: v.Id, Project: pRef.Id, Status: evergreen.TaskUndispatched}
+	require.NoError(t, t1.Insert())
+	require.NoError(t, t2.Insert())
+	require.NoError(t, t3.Insert())
+
+	require.NoError(t, t1.MarkStart(time.Now()))
+	require.NoError(t, UpdateBuildAndVersionStatusForTask(&t1))
+	dbBuild, err := build.FindOneId(b1.Id)
+	require.NoError(t, err)
+	assert.Equal(t, evergreen.BuildStarted, dbBuild.Status)
+	assert.Equal(t, 2, dbBuild.RollupCounts[rollupTotal])
+	assert.Equal(t, 2, dbBuild.RollupCounts[rollupInProgress])
+	dbVersion, err := VersionFindOneId(v.Id)
+	require.NoError(t, err)
+	assert.Equal(t, evergreen.VersionStarted, dbVersion.Status)
+	assert.Equal(t, 2, dbVersion.RollupCounts[rollupTotal])
+
+	// Repeating the update for the same change should not count it twice.
+	require.NoError(t, UpdateBuildAndVersionStatusForTask(&t1))
+	dbBuild, err = build.FindOneId(b1.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dbBuild.RollupCounts[rollupInProgress])
+
+	for _, tsk := range []task.Task{t1, t2} {
+		require.NoError(t, tsk.MarkFailed())
+		require.NoError(t, UpdateBuildAndVersionStatusForTask(&tsk))
+	}
+	dbBuild, err = build.FindOneId(b1.Id)
+	require.NoError(t, err)
+	assert.Equal(t, evergreen.BuildFailed, dbBuild.Status)
+	assert.Zero(t, dbBuild.RollupCounts[rollupInProgress])
+	assert.Equal(t, 2, dbBuild.RollupCounts[rollupFailed])
+
+	t.Run("ReconcileMatchingRollup", func(t *testing.T) {
+		rec, err := ReconcileBuildRollup(b1.Id)
+		require.NoError(t, err)
+		assert.True(t, rec.CountsMatched)
+		assert.Equal(t, evergreen.BuildFailed, rec.RollupStatus)
+		assert.Equal(t, evergreen.BuildFailed, rec.ScanStatus)
+	})
+	t.Run("ReconcileDriftedRollup", func(t *testing.T) {
+		require.NoError(t, build.UpdateOne(
+			bson.M{build.IdKey: b1.Id},
+			bson.M{
+				"$inc": bson.M{bsonutil.GetDottedKeyName(build.RollupCountsKey, rollupInProgress): 1},
+				"$set": bson.M{build.StatusKey: evergreen.BuildStarted},
+			},
+		))
+		rec, err := ReconcileBuildRollup(b1.Id)
+		require.NoError(t, err)
+		assert.False(t, rec.CountsMatched)
+		assert.Equal(t, evergreen.BuildStarted, rec.RollupStatus)
+		assert.Equal(t, evergreen.BuildFailed, rec.ScanStatus)
+
+		dbBuild, err := build.FindOneId(b1.Id)
+		require.NoError(t, err)
+		assert.Zero(t, dbBuild.RollupCounts[rollupInProgress])
+		assert.Equal(t, evergreen.BuildFailed, dbBuild.Status, "should update the build status from the recounted rollup")
+	})
+	t.Run("BlockedDependentsAreResynced", func(t *testing.T) {
+		t4 := task.Task{
+			Id:        "t4",
+			Activated: true,
+			BuildId:   b2.Id,
+			Version:   v.Id,
+			Project:   pRef.Id,
+			Status:    evergreen.TaskUndispatched,
+			DependsOn: []task.Dependency{{TaskId: t1.Id, Status: evergreen.TaskSucceeded}},
+		}
+		require.NoError(t, t4.Insert())
+		require.NoError(t, UpdateBlockedDependencies(&t1))
+
+		dbBuild, err := build.FindOneId(b2.Id)
+		require.NoError(t, err)
+		assert.Equal(t, 1, dbBuild.RollupCounts[rollupUnstartedBlocked])
+		assert.Equal(t, 1, dbBuild.RollupCounts[rollupInProgress])
+	})
+}
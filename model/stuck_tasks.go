@@ -0,0 +1,172 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// StuckTaskPolicyReset system-fails stuck tasks and resets them so they
+	// can run again. This is the default policy.
+	StuckTaskPolicyReset = "reset"
+	// StuckTaskPolicyAlert flags stuck tasks and alerts on them, but leaves
+	// them running.
+	StuckTaskPolicyAlert = "alert"
+)
+
+// ValidateStuckTaskPolicy checks that the stuck task policy is valid. An empty
+// policy uses the default policy.
+func ValidateStuckTaskPolicy(policy string) error {
+	switch policy {
+	case "", StuckTaskPolicyReset, StuckTaskPolicyAlert:
+		return nil
+	default:
+		return errors.Errorf("invalid stuck task policy '%s', must be one of '%s' or '%s'", policy, StuckTaskPolicyReset, StuckTaskPolicyAlert)
+	}
+}
+
+// GetStuckTaskPolicy returns what the stuck task watchdog does with the
+// project's tasks when they're stuck.
+func (p *ProjectRef) GetStuckTaskPolicy() string {
+	if p.StuckTaskPolicy == "" {
+		return StuckTaskPolicyReset
+	}
+	return p.StuckTaskPolicy
+}
+
+// StuckTask is a started task whose heartbeat has been stale for longer than
+// its distro's stuck task threshold.
+type StuckTask struct {
+	Task task.Task
+	// Threshold is the stuck task threshold of the task's distro.
+	Threshold time.Duration
+	// Policy is the stuck task policy of the task's project.
+	Policy string
+	// StaleFor is how long it has been since the task's last heartbeat.
+	StaleFor time.Duration
+}
+
+// FindStuckTasks returns the started tasks whose heartbeat has been stale for
+// longer than their distro's stuck task threshold as of the given time, from
+// the longest stale. If a project is given, only that project's tasks are
+// returned.
+func FindStuckTasks(projectID string, now time.Time) ([]StuckTask, error) {
+	// No distro's threshold is shorter than the minimum, so anything that
+	// heartbeat more recently than that can't be stuck.
+	q := bson.M{
+		task.StatusKey:        evergreen.TaskStarted,
+		task.LastHeartbeatKey: bson.M{"$lte": now.Add(-distro.MinStuckTaskThreshold)},
+	}
+	if projectID != "" {
+		q[task.ProjectKey] = projectID
+	}
+	candidates, err := task.Find(q)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding tasks with stale heartbeats")
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	distroIDs := map[string]bool{}
+	projectIDs := map[string]bool{}
+	for _, t := range candidates {
+		distroIDs[t.DistroId] = true
+		projectIDs[t.Project] = true
+	}
+	thresholds, err := getStuckTaskThresholds(distroIDs)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := getStuckTaskPolicies(projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	stuck := []StuckTask{}
+	for _, t := range candidates {
+		threshold, ok := thresholds[t.DistroId]
+		if !ok {
+			threshold = distro.DefaultStuckTaskThreshold
+		}
+		staleFor := now.Sub(t.LastHeartbeat)
+		if staleFor < threshold {
+			continue
+		}
+		policy, ok := policies[t.Project]
+		if !ok {
+			policy = StuckTaskPolicyReset
+		}
+		stuck = append(stuck, StuckTask{
+			Task:      t,
+			Threshold: threshold,
+			Policy:    policy,
+			StaleFor:  staleFor,
+		})
+	}
+	sort.SliceStable(stuck, func(i, j int) bool {
+		return stuck[i].StaleFor > stuck[j].StaleFor
+	})
+	return stuck, nil
+}
+
+func getStuckTaskThresholds(distroIDs map[string]bool) (map[string]time.Duration, error) {
+	ids := make([]string, 0, len(distroIDs))
+	for id := range distroIDs {
+		ids = append(ids, id)
+	}
+	distros, err := distro.Find(db.Query(bson.M{distro.IdKey: bson.M{"$in": ids}}).WithFields(distro.IdKey, distro.StuckTaskThresholdKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding distros")
+	}
+	thresholds := make(map[string]time.Duration, len(distros))
+	for _, d := range distros {
+		thresholds[d.Id] = d.GetStuckTaskThreshold()
+	}
+	return thresholds, nil
+}
+
+func getStuckTaskPolicies(projectIDs map[string]bool) (map[string]string, error) {
+	ids := make([]string, 0, len(projectIDs))
+	for id := range projectIDs {
+		ids = append(ids, id)
+	}
+	projectRefs, err := FindProjectRefsByIds(ids...)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding project refs")
+	}
+	policies := make(map[string]string, len(projectRefs))
+	for _, pRef := range projectRefs {
+		policies[pRef.Id] = pRef.GetStuckTaskPolicy()
+	}
+	return policies, nil
+}
+
+// GetStuckTaskSettings returns the stuck task threshold of the task's distro
+// and the stuck task policy of the task's project.
+func GetStuckTaskSettings(t *task.Task) (time.Duration, string, error) {
+	thresholds, err := getStuckTaskThresholds(map[string]bool{t.DistroId: true})
+	if err != nil {
+		return 0, "", err
+	}
+	threshold, ok := thresholds[t.DistroId]
+	if !ok {
+		threshold = distro.DefaultStuckTaskThreshold
+	}
+	policies, err := getStuckTaskPolicies(map[string]bool{t.Project: true})
+	if err != nil {
+		return 0, "", err
+	}
+	policy, ok := policies[t.Project]
+	if !ok {
+		policy = StuckTaskPolicyReset
+	}
+	return threshold, policy, nil
+}
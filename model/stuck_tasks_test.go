@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStuckTasks(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, distro.Collection, ProjectRefCollection))
+	}()
+	require.NoError(t, db.ClearCollections(task.Collection, distro.Collection, ProjectRefCollection))
+
+	now := time.Now()
+	distros := []distro.Distro{
+		{Id: "default_distro"},
+		{Id: "slow_distro", StuckTaskThreshold: 30 * time.Minute},
+	}
+	for _, d := range distros {
+		require.NoError(t, d.Insert())
+	}
+	projectRefs := []ProjectRef{
+		{Id: "p1"},
+		{Id: "p2", StuckTaskPolicy: StuckTaskPolicyAlert},
+	}
+	for _, pRef := range projectRefs {
+		require.NoError(t, pRef.Insert())
+	}
+	tasks := []task.Task{
+		{Id: "stuck", Project: "p1", DistroId: "default_distro", Status: evergreen.TaskStarted, LastHeartbeat: now.Add(-10 * time.Minute)},
+		{Id: "very_stuck", Project: "p2", DistroId: "default_distro", Status: evergreen.TaskStarted, LastHeartbeat: now.Add(-20 * time.Minute)},
+		{Id: "healthy", Project: "p1", DistroId: "default_distro", Status: evergreen.TaskStarted, LastHeartbeat: now.Add(-time.Minute)},
+		{Id: "under_distro_threshold", Project: "p1", DistroId: "slow_distro", Status: evergreen.TaskStarted, LastHeartbeat: now.Add(-10 * time.Minute)},
+		{Id: "finished", Project: "p1", DistroId: "default_distro", Status: evergreen.TaskFailed, LastHeartbeat: now.Add(-time.Hour)},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+	}
+
+	stuck, err := FindStuckTasks("", now)
+	require.NoError(t, err)
+	require.Len(t, stuck, 2)
+	assert.Equal(t, "very_stuck", stuck[0].Task.Id)
+	assert.Equal(t, StuckTaskPolicyAlert, stuck[0].Policy)
+	assert.Equal(t, distro.DefaultStuckTaskThreshold, stuck[0].Threshold)
+	assert.Equal(t, "stuck", stuck[1].Task.Id)
+	assert.Equal(t, StuckTaskPolicyReset, stuck[1].Policy)
+
+	stuck, err = FindStuckTasks("p1", now)
+	require.NoError(t, err)
+	require.Len(t, stuck, 1)
+	assert.Equal(t, "stuck", stuck[0].Task.Id)
+
+	flagged, err := stuck[0].Task.MarkStuck(now)
+	require.NoError(t, err)
+	assert.True(t, flagged)
+	flagged, err = stuck[0].Task.MarkStuck(now)
+	require.NoError(t, err)
+	assert.False(t, flagged, "task should only be flagged once")
+
+	assert.NoError(t, ValidateStuckTaskPolicy(""))
+	assert.NoError(t, ValidateStuckTaskPolicy(StuckTaskPolicyAlert))
+	assert.Error(t, ValidateStuckTaskPolicy("ignore"))
+}
@@ -0,0 +1,14 @@
+package task
+
+// ComplianceMetadata describes what a task produces so that releases can be
+// audited for license compliance.
+type ComplianceMetadata struct {
+	// ProducesDistributable is whether the task produces artifacts that are
+	// distributed to users.
+	ProducesDistributable bool `yaml:"produces_distributable,omitempty" bson:"produces_distributable,omitempty" json:"produces_distributable,omitempty"`
+	// LicenseScanRequired is whether the task's artifacts must be scanned
+	// for license compliance before they're released.
+	LicenseScanRequired bool `yaml:"license_scan_required,omitempty" bson:"license_scan_required,omitempty" json:"license_scan_required,omitempty"`
+	// License is the SPDX license expression of the task's artifacts.
+	License string `yaml:"license,omitempty" bson:"license,omitempty" json:"license,omitempty"`
+}
@@ -47,6 +47,7 @@ var (
 	LastHeartbeatKey            = bsonutil.MustHaveTag(Task{}, "LastHeartbeat")
 	ActivatedKey                = bsonutil.MustHaveTag(Task{}, "Activated")
 	ContainerAllocatedKey       = bsonutil.MustHaveTag(Task{}, "ContainerAllocated")
+	ContainerFallbackKey        = bsonutil.MustHaveTag(Task{}, "ContainerFallback")
 	DeactivatedForDependencyKey = bsonutil.MustHaveTag(Task{}, "DeactivatedForDependency")
 	BuildIdKey                  = bsonutil.MustHaveTag(Task{}, "BuildId")
 	DistroIdKey                 = bsonutil.MustHaveTag(Task{}, "DistroId")
@@ -68,6 +69,8 @@ var (
 	DetailsKey                  = bsonutil.MustHaveTag(Task{}, "Details")
 	AbortedKey                  = bsonutil.MustHaveTag(Task{}, "Aborted")
 	AbortInfoKey                = bsonutil.MustHaveTag(Task{}, "AbortInfo")
+	CommandAbortKey             = bsonutil.MustHaveTag(Task{}, "CommandAbort")
+	SkipReasonKey               = bsonutil.MustHaveTag(Task{}, "SkipReason")
 	TimeTakenKey                = bsonutil.MustHaveTag(Task{}, "TimeTaken")
 	ExpectedDurationKey         = bsonutil.MustHaveTag(Task{}, "ExpectedDuration")
 	ExpectedDurationStddevKey   = bsonutil.MustHaveTag(Task{}, "ExpectedDurationStdDev")
@@ -88,6 +91,13 @@ var (
 	CedarResultsFailedKey       = bsonutil.MustHaveTag(Task{}, "CedarResultsFailed")
 	IsGithubCheckKey            = bsonutil.MustHaveTag(Task{}, "IsGithubCheck")
 	HostCreateDetailsKey        = bsonutil.MustHaveTag(Task{}, "HostCreateDetails")
+	EndTaskRequestKey           = bsonutil.MustHaveTag(Task{}, "EndTaskRequest")
+	FinalizingUntilKey          = bsonutil.MustHaveTag(Task{}, "FinalizingUntil")
+	OutputsKey                  = bsonutil.MustHaveTag(Task{}, "Outputs")
+	StuckTimeKey                = bsonutil.MustHaveTag(Task{}, "StuckTime")
+	FailureFingerprintKey       = bsonutil.MustHaveTag(Task{}, "FailureFingerprint")
+	ComplianceKey               = bsonutil.MustHaveTag(Task{}, "Compliance")
+	RollupFlagsKey              = bsonutil.MustHaveTag(Task{}, "RollupFlags")
 
 	// GeneratedJSONKey is no longer used but must be kept for old tasks.
 	GeneratedJSONKey            = bsonutil.MustHaveTag(Task{}, "GeneratedJSON")
@@ -111,6 +121,9 @@ var (
 	TestResultExitCodeKey  = bsonutil.MustHaveTag(TestResult{}, "ExitCode")
 	TestResultStartTimeKey = bsonutil.MustHaveTag(TestResult{}, "StartTime")
 	TestResultEndTimeKey   = bsonutil.MustHaveTag(TestResult{}, "EndTime")
+
+	// BSON fields for the compliance metadata struct
+	ComplianceProducesDistributableKey = bsonutil.MustHaveTag(ComplianceMetadata{}, "ProducesDistributable")
 )
 
 var (
@@ -127,6 +140,14 @@ var (
 	DependencyStatusKey       = bsonutil.MustHaveTag(Dependency{}, "Status")
 	DependencyUnattainableKey = bsonutil.MustHaveTag(Dependency{}, "Unattainable")
 	DependencyFinishedKey     = bsonutil.MustHaveTag(Dependency{}, "Finished")
+
+	DependencyOmitGeneratedTasksKey = bsonutil.MustHaveTag(Dependency{}, "OmitGeneratedTasks")
+)
+
+var (
+	// BSON fields for the end task request struct
+	EndTaskRequestIdempotencyKeyKey = bsonutil.MustHaveTag(EndTaskRequest{}, "IdempotencyKey")
+	EndTaskRequestResponseKey       = bsonutil.MustHaveTag(EndTaskRequest{}, "Response")
 )
 
 var BaseTaskStatusKey = bsonutil.GetDottedKeyName(BaseTaskKey, StatusKey)
@@ -192,6 +213,12 @@ var (
 					},
 					"then": evergreen.TaskSucceeded,
 				},
+				{
+					"case": bson.M{
+						"$eq": []string{"$" + StatusKey, evergreen.TaskSkipped},
+					},
+					"then": evergreen.TaskSkipped,
+				},
 				{
 					"case": bson.M{
 						"$eq": []string{"$" + bsonutil.GetDottedKeyName(DetailsKey, TaskEndDetailType), evergreen.CommandTypeSetup},
@@ -1354,6 +1381,33 @@ func CountActivatedTasksForVersion(versionId string) (int, error) {
 	}))
 }
 
+// CountInFlightTasksByProject returns the number of tasks in each project that
+// have been picked up by an agent but have not finished running.
+func CountInFlightTasksByProject() (map[string]int, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			StatusKey:      bson.M{"$in": evergreen.TaskAbortableStatuses},
+			DisplayOnlyKey: bson.M{"$ne": true},
+		}},
+		{"$group": bson.M{
+			"_id":   "$" + ProjectKey,
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+	results := []struct {
+		Project string `bson:"_id"`
+		Count   int    `bson:"count"`
+	}{}
+	if err := Aggregate(pipeline, &results); err != nil {
+		return nil, errors.Wrap(err, "counting in-flight tasks by project")
+	}
+	counts := make(map[string]int, len(results))
+	for _, res := range results {
+		counts[res.Project] = res.Count
+	}
+	return counts, nil
+}
+
 func FindTaskGroupFromBuild(buildId, taskGroup string) ([]Task, error) {
 	tasks, err := FindWithSort(bson.M{
 		BuildIdKey:   buildId,
@@ -1421,6 +1475,103 @@ func FindOldWithDisplayTasks(filter bson.M) ([]Task, error) {
 	return tasks, err
 }
 
+// FindLatestMainlineTask returns the activated task with the given name and
+// variant from the most recent mainline version of the project other than the
+// given version.
+func FindLatestMainlineTask(projectID, variant, displayName, excludeVersion string) (*Task, error) {
+	query := db.Query(bson.M{
+		ProjectKey:      projectID,
+		BuildVariantKey: variant,
+		DisplayNameKey:  displayName,
+		RequesterKey:    evergreen.RepotrackerVersionRequester,
+		VersionKey:      bson.M{"$ne": excludeVersion},
+		ActivatedKey:    true,
+	}).Sort([]string{"-" + RevisionOrderNumberKey})
+	t, err := FindOne(query)
+	return t, errors.Wrapf(err, "finding latest mainline run of task '%s' in variant '%s'", displayName, variant)
+}
+
+// ArchivedExecutionsOptions filter the archived executions of a task.
+type ArchivedExecutionsOptions struct {
+	TaskID string
+	// Statuses, if set, only includes executions that finished with one of
+	// the statuses.
+	Statuses []string
+	// MinExecution and MaxExecution, if set, bound the executions included.
+	MinExecution *int
+	MaxExecution *int
+	// FinishedAfter and FinishedBefore, if set, bound when the included
+	// executions finished.
+	FinishedAfter  time.Time
+	FinishedBefore time.Time
+	Limit          int
+}
+
+// FindArchivedExecutions returns the archived executions of a task that match
+// the options, in order of execution. The query is covered by the old tasks
+// collection's index on old task ID and execution.
+func FindArchivedExecutions(opts ArchivedExecutionsOptions) ([]Task, error) {
+	if opts.TaskID == "" {
+		return nil, errors.New("task ID must be specified")
+	}
+	filter := bson.M{OldTaskIdKey: opts.TaskID}
+	if len(opts.Statuses) > 0 {
+		filter[StatusKey] = bson.M{"$in": opts.Statuses}
+	}
+	executionFilter := bson.M{}
+	if opts.MinExecution != nil {
+		executionFilter["$gte"] = *opts.MinExecution
+	}
+	if opts.MaxExecution != nil {
+		executionFilter["$lte"] = *opts.MaxExecution
+	}
+	if len(executionFilter) > 0 {
+		filter[ExecutionKey] = executionFilter
+	}
+	finishTimeFilter := bson.M{}
+	if !utility.IsZeroTime(opts.FinishedAfter) {
+		finishTimeFilter["$gte"] = opts.FinishedAfter
+	}
+	if !utility.IsZeroTime(opts.FinishedBefore) {
+		finishTimeFilter["$lt"] = opts.FinishedBefore
+	}
+	if len(finishTimeFilter) > 0 {
+		filter[FinishTimeKey] = finishTimeFilter
+	}
+
+	tasks := []Task{}
+	query := db.Query(filter).Sort([]string{ExecutionKey}).Limit(opts.Limit)
+	err := db.FindAllQ(OldCollection, query, &tasks)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return tasks, errors.Wrapf(err, "finding archived executions of task '%s'", opts.TaskID)
+}
+
+// Matches returns whether the archived execution passes the options' filters.
+// It is used for executions that are no longer in the old tasks collection.
+func (opts ArchivedExecutionsOptions) Matches(t *Task) bool {
+	if t.OldTaskId != opts.TaskID {
+		return false
+	}
+	if len(opts.Statuses) > 0 && !utility.StringSliceContains(opts.Statuses, t.Status) {
+		return false
+	}
+	if opts.MinExecution != nil && t.Execution < *opts.MinExecution {
+		return false
+	}
+	if opts.MaxExecution != nil && t.Execution > *opts.MaxExecution {
+		return false
+	}
+	if !utility.IsZeroTime(opts.FinishedAfter) && t.FinishTime.Before(opts.FinishedAfter) {
+		return false
+	}
+	if !utility.IsZeroTime(opts.FinishedBefore) && !t.FinishTime.Before(opts.FinishedBefore) {
+		return false
+	}
+	return true
+}
+
 // FindOneIdOldOrNew returns a single task with the given ID and execution,
 // first looking in the old tasks collection, then the tasks collection.
 func FindOneIdOldOrNew(id string, execution int) (*Task, error) {
@@ -1642,11 +1793,7 @@ func AbortTasksForBuild(buildId string, taskIds []string, caller string) error {
 
 func AbortTasksForVersion(versionId string, taskIds []string, caller string) error {
 	_, err := UpdateAll(
-		bson.M{
-			VersionKey: versionId,
-			IdKey:      bson.M{"$in": taskIds},
-			StatusKey:  bson.M{"$in": evergreen.TaskAbortableStatuses},
-		},
+		abortableTasksForVersionQuery(versionId, taskIds),
 		bson.M{"$set": bson.M{
 			AbortedKey:   true,
 			AbortInfoKey: AbortInfo{User: caller},
@@ -1655,6 +1802,20 @@ func AbortTasksForVersion(versionId string, taskIds []string, caller string) err
 	return err
 }
 
+// FindAbortableTasksForVersion returns the tasks that AbortTasksForVersion
+// would abort.
+func FindAbortableTasksForVersion(versionId string, taskIds []string) ([]Task, error) {
+	return FindAll(db.Query(abortableTasksForVersionQuery(versionId, taskIds)).WithFields(IdKey, BuildIdKey, VersionKey))
+}
+
+func abortableTasksForVersionQuery(versionId string, taskIds []string) bson.M {
+	return bson.M{
+		VersionKey: versionId,
+		IdKey:      bson.M{"$in": taskIds},
+		StatusKey:  bson.M{"$in": evergreen.TaskAbortableStatuses},
+	}
+}
+
 // HasUnfinishedTaskForVersion returns true if there are any scheduled but
 // unfinished tasks matching the given conditions.
 func HasUnfinishedTaskForVersions(versionIds []string, taskName, variantName string) (bool, error) {
@@ -331,6 +331,81 @@ func TestFindOldTasksByID(t *testing.T) {
 	assert.Equal("task", tasks[1].OldTaskId)
 }
 
+func TestFindArchivedExecutions(t *testing.T) {
+	require.NoError(t, db.ClearCollections(Collection, OldCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(Collection, OldCollection))
+	}()
+
+	start := time.Now().Round(time.Second)
+	var allArchived []Task
+	for i, status := range []string{evergreen.TaskFailed, evergreen.TaskSucceeded, evergreen.TaskFailed, evergreen.TaskFailed} {
+		archived := Task{
+			Id:         MakeOldID("task", i),
+			OldTaskId:  "task",
+			Execution:  i,
+			Status:     status,
+			FinishTime: start.Add(time.Duration(i) * time.Hour),
+			Archived:   true,
+		}
+		require.NoError(t, db.Insert(OldCollection, archived))
+		allArchived = append(allArchived, archived)
+	}
+	other := Task{Id: MakeOldID("other", 0), OldTaskId: "other", Status: evergreen.TaskFailed}
+	require.NoError(t, db.Insert(OldCollection, other))
+	allArchived = append(allArchived, other)
+
+	for tName, tCase := range map[string]struct {
+		opts       ArchivedExecutionsOptions
+		executions []int
+	}{
+		"AllExecutions": {
+			opts:       ArchivedExecutionsOptions{TaskID: "task"},
+			executions: []int{0, 1, 2, 3},
+		},
+		"Status": {
+			opts:       ArchivedExecutionsOptions{TaskID: "task", Statuses: []string{evergreen.TaskFailed}},
+			executions: []int{0, 2, 3},
+		},
+		"ExecutionRange": {
+			opts:       ArchivedExecutionsOptions{TaskID: "task", MinExecution: utility.ToIntPtr(1), MaxExecution: utility.ToIntPtr(2)},
+			executions: []int{1, 2},
+		},
+		"FinishTimeRange": {
+			opts:       ArchivedExecutionsOptions{TaskID: "task", FinishedAfter: start.Add(time.Hour), FinishedBefore: start.Add(3 * time.Hour)},
+			executions: []int{1, 2},
+		},
+		"Limit": {
+			opts:       ArchivedExecutionsOptions{TaskID: "task", Statuses: []string{evergreen.TaskFailed}, Limit: 2},
+			executions: []int{0, 2},
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			tasks, err := FindArchivedExecutions(tCase.opts)
+			require.NoError(t, err)
+			var executions []int
+			for _, archived := range tasks {
+				assert.Equal(t, "task", archived.OldTaskId)
+				executions = append(executions, archived.Execution)
+			}
+			assert.Equal(t, tCase.executions, executions)
+
+			if tCase.opts.Limit == 0 {
+				var matched []int
+				for i := range allArchived {
+					if tCase.opts.Matches(&allArchived[i]) {
+						matched = append(matched, allArchived[i].Execution)
+					}
+				}
+				assert.Equal(t, tCase.executions, matched, "in-memory filter should match the query")
+			}
+		})
+	}
+
+	_, err := FindArchivedExecutions(ArchivedExecutionsOptions{})
+	assert.Error(t, err)
+}
+
 func TestFindAllFirstExecution(t *testing.T) {
 	require.NoError(t, db.ClearCollections(Collection, OldCollection))
 	tasks := []Task{
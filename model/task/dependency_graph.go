@@ -96,6 +96,10 @@ func (g *DependencyGraph) buildFromTasks(tasks []Task) {
 	for _, task := range tasks {
 		dependentTaskNode := task.ToTaskNode()
 		for _, dep := range task.DependsOn {
+			// Tasks in other versions are not part of this graph.
+			if dep.CrossVersion {
+				continue
+			}
 			dependedOnTaskNode := taskIDToNode[dep.TaskId]
 			g.AddEdge(dependentTaskNode, dependedOnTaskNode, dep.Status)
 		}
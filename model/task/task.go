@@ -121,6 +121,10 @@ type Task struct {
 	NumDependents           int              `bson:"num_dependents,omitempty" json:"num_dependents,omitempty"`
 	OverrideDependencies    bool             `bson:"override_dependencies,omitempty" json:"override_dependencies,omitempty"`
 
+	// ContainerFallback, if set, is the distro that a container task should
+	// run on instead if it cannot be allocated a container in time.
+	ContainerFallback *ContainerFallbackOptions `bson:"container_fallback,omitempty" json:"container_fallback,omitempty"`
+
 	// DistroAliases refer to the optional secondary distros that can be
 	// associated with a task. This is used for running tasks in case there are
 	// idle hosts in a distro with an empty primary queue. Despite the variable
@@ -169,9 +173,57 @@ type Task struct {
 	Details   apimodels.TaskEndDetail `bson:"details" json:"task_end_details"`
 	Aborted   bool                    `bson:"abort,omitempty" json:"abort"`
 	AbortInfo AbortInfo               `bson:"abort_info,omitempty" json:"abort_info,omitempty"`
+	// CommandAbort is who asked for the task's running command to be skipped.
+	// It's cleared once the agent is told to skip the command.
+	CommandAbort *AbortInfo `bson:"command_abort,omitempty" json:"command_abort,omitempty"`
+	// SkipReason is why the task was intentionally not run, if its status is
+	// skipped.
+	SkipReason string `bson:"skip_reason,omitempty" json:"skip_reason,omitempty"`
 
 	// HostCreateDetails stores information about why host.create failed for this task
 	HostCreateDetails []HostCreateDetail `bson:"host_create_details,omitempty" json:"host_create_details,omitempty"`
+
+	// EndTaskRequest is the request the agent made to end this execution of
+	// the task. It lets retries of the same request be answered without
+	// ending the task again.
+	EndTaskRequest *EndTaskRequest `bson:"end_task_request,omitempty" json:"end_task_request,omitempty"`
+
+	// FinalizingUntil is when the agent's window to finish ending this
+	// execution of the task closes. The agent may stop heartbeating while it
+	// ends the task, so the task isn't reset for missing heartbeats until
+	// then.
+	FinalizingUntil time.Time `bson:"finalizing_until,omitempty" json:"finalizing_until,omitempty"`
+
+	// Outputs are the structured outputs that this execution of the task
+	// published, keyed by output name.
+	Outputs map[string]string `bson:"outputs,omitempty" json:"outputs,omitempty"`
+	// RequiredOutputs are the names of the outputs that the task must publish
+	// to succeed. It's copied from the task's definition in the project
+	// config.
+	RequiredOutputs []string `bson:"required_outputs,omitempty" json:"required_outputs,omitempty"`
+
+	// Compliance describes what the task produces for release audits. It's
+	// copied from the task's definition in the project config.
+	Compliance *ComplianceMetadata `bson:"compliance,omitempty" json:"compliance,omitempty"`
+
+	// ExpectedArtifacts are the names or glob patterns of the artifacts that
+	// the task must attach to succeed. It's copied from the task's definition
+	// in the project config.
+	ExpectedArtifacts []string `bson:"expected_artifacts,omitempty" json:"expected_artifacts,omitempty"`
+
+	// StuckTime is when the stuck task watchdog flagged this execution of the
+	// task as stuck because its heartbeat went stale.
+	StuckTime time.Time `bson:"stuck_time,omitempty" json:"stuck_time,omitempty"`
+
+	// FailureFingerprint identifies how this execution of the task failed.
+	// Failed tasks with the same fingerprint likely failed for the same
+	// reason. It's only set for failed tasks.
+	FailureFingerprint string `bson:"failure_fingerprint,omitempty" json:"failure_fingerprint,omitempty"`
+
+	// RollupFlags are the status rollup counters of the task's build that the
+	// task is currently counted in. They are only set for projects that use
+	// event-sourced status rollups.
+	RollupFlags []string `bson:"rollup_flags,omitempty" json:"-"`
 	// DisplayStatus is not persisted to the db. It is the status to display in the UI.
 	// It may be added via aggregation
 	DisplayStatus string `bson:"display_status,omitempty" json:"display_status,omitempty"`
@@ -203,6 +255,10 @@ type Task struct {
 	// DisplayTaskId is set to the display task ID if the task is an execution task, the empty string if it's not an execution task,
 	// and is nil if we haven't yet checked whether or not this task has a display task.
 	DisplayTaskId *string `bson:"display_task_id,omitempty" json:"display_task_id,omitempty"`
+	// DispatchLongestFirst indicates that this execution task's display task
+	// dispatches its execution tasks in order of descending expected
+	// duration.
+	DispatchLongestFirst bool `bson:"dispatch_longest_first,omitempty" json:"dispatch_longest_first,omitempty"`
 
 	// GenerateTask indicates that the task generates other tasks, which the
 	// scheduler will use to prioritize this task.
@@ -256,6 +312,9 @@ type ContainerOptions struct {
 	OS             evergreen.ContainerOS
 	Arch           evergreen.ContainerArch
 	WindowsVersion evergreen.WindowsVersion
+	// RepoCredsName is the name of the project's container credential used
+	// to pull the image from a private repository.
+	RepoCredsName string
 }
 
 // IsZero implements the bsoncodec.Zeroer interface for the sake of defining the
@@ -264,6 +323,16 @@ func (o ContainerOptions) IsZero() bool {
 	return o == ContainerOptions{}
 }
 
+// ContainerFallbackOptions represent the distro that a container task falls
+// back to if a container cannot be allocated to it in time.
+type ContainerFallbackOptions struct {
+	// DistroID is the distro that the task runs on if it falls back.
+	DistroID string `bson:"distro_id" json:"distro_id"`
+	// After is how long the task waits to be allocated a container before
+	// falling back.
+	After time.Duration `bson:"after" json:"after"`
+}
+
 func (t *Task) MarshalBSON() ([]byte, error)  { return mgobson.Marshal(t) }
 func (t *Task) UnmarshalBSON(in []byte) error { return mgobson.Unmarshal(in, t) }
 
@@ -290,6 +359,13 @@ type Dependency struct {
 	Unattainable bool   `bson:"unattainable" json:"unattainable"`
 	// Finished indicates if the task's dependency has finished running or not.
 	Finished bool `bson:"finished" json:"finished"`
+	// OmitGeneratedTasks indicates that the task does not also depend on the
+	// tasks that its dependency generates.
+	OmitGeneratedTasks bool `bson:"omit_generated_tasks,omitempty" json:"omit_generated_tasks,omitempty"`
+	// CrossVersion indicates that the dependency is in a different version
+	// than the task. Cross-version dependencies are never activated along
+	// with the task.
+	CrossVersion bool `bson:"cross_version,omitempty" json:"cross_version,omitempty"`
 }
 
 // BaseTaskInfo is a subset of task fields that should be returned for patch tasks.
@@ -304,6 +380,14 @@ type HostCreateDetail struct {
 	Error  string `bson:"error" json:"error"`
 }
 
+// EndTaskRequest identifies a request from the agent to end a task execution.
+type EndTaskRequest struct {
+	IdempotencyKey string `bson:"idempotency_key" json:"idempotency_key"`
+	// Response is the response to the request. It is not set until the
+	// request has finished ending the task.
+	Response *apimodels.EndTaskResponse `bson:"response,omitempty" json:"response,omitempty"`
+}
+
 func (d *Dependency) UnmarshalBSON(in []byte) error {
 	return mgobson.Unmarshal(in, d)
 }
@@ -358,6 +442,11 @@ type TestResult struct {
 	TaskID          string  `json:"task_id" bson:"task_id"`
 	Execution       int     `json:"execution" bson:"execution"`
 
+	// Artifacts are files produced by the test.
+	Artifacts []testresult.Artifact `json:"artifacts,omitempty" bson:"artifacts,omitempty"`
+	// TaskLogRange points to the lines of the task log that the test wrote.
+	TaskLogRange *testresult.LogRange `json:"task_log_range,omitempty" bson:"task_log_range,omitempty"`
+
 	// LogRaw and LogTestName are not saved in the task
 	LogRaw      string `json:"log_raw" bson:"log_raw,omitempty"`
 	LogTestName string `json:"log_test_name" bson:"log_test_name"`
@@ -599,7 +688,7 @@ func (t *Task) SatisfiesDependency(depTask *Task) bool {
 			case evergreen.TaskFailed:
 				return depTask.Status == evergreen.TaskFailed
 			case AllStatuses:
-				return depTask.Status == evergreen.TaskFailed || depTask.Status == evergreen.TaskSucceeded || depTask.Blocked()
+				return depTask.Status == evergreen.TaskFailed || depTask.Status == evergreen.TaskSucceeded || depTask.Status == evergreen.TaskSkipped || depTask.Blocked()
 			}
 		}
 	}
@@ -1005,9 +1094,10 @@ func (t *Task) MarkAsHostDispatched(hostId, distroId, agentRevision string,
 				AgentVersionKey:  agentRevision,
 			},
 			"$unset": bson.M{
-				AbortedKey:   "",
-				AbortInfoKey: "",
-				DetailsKey:   "",
+				AbortedKey:      "",
+				AbortInfoKey:    "",
+				CommandAbortKey: "",
+				DetailsKey:      "",
 			},
 		},
 	)
@@ -1044,6 +1134,7 @@ func (t *Task) MarkAsHostUndispatched() error {
 				HostIdKey:        "",
 				AbortedKey:       "",
 				AbortInfoKey:     "",
+				CommandAbortKey:  "",
 				DetailsKey:       "",
 			},
 		},
@@ -1224,6 +1315,19 @@ func (t *Task) SetGeneratedJSON(json []json.RawMessage) error {
 	)
 }
 
+// GeneratedJSONSize returns the total size in bytes of the JSON data to
+// generate tasks from.
+func (t *Task) GeneratedJSONSize() int {
+	size := 0
+	for _, s := range t.GeneratedJSONAsString {
+		size += len(s)
+	}
+	for _, j := range t.GeneratedJSON {
+		size += len(j)
+	}
+	return size
+}
+
 // SetGeneratedTasksToActivate adds a task to stepback after activation
 func (t *Task) SetGeneratedTasksToActivate(buildVariantName, taskName string) error {
 	return UpdateOne(
@@ -1239,10 +1343,15 @@ func (t *Task) SetGeneratedTasksToActivate(buildVariantName, taskName string) er
 }
 
 // SetTasksScheduledTime takes a list of tasks and a time, and then sets
-// the scheduled time in the database for the tasks if it is currently unset
+// the scheduled time in the database for the tasks if it is currently unset.
+// Tasks whose scheduled time was unset are logged as enqueued.
 func SetTasksScheduledTime(tasks []Task, scheduledTime time.Time) error {
 	ids := []string{}
+	enqueued := map[string]int{}
 	for i := range tasks {
+		if utility.IsZeroTime(tasks[i].ScheduledTime) {
+			enqueued[tasks[i].Id] = tasks[i].Execution
+		}
 		tasks[i].ScheduledTime = scheduledTime
 		ids = append(ids, tasks[i].Id)
 
@@ -1269,6 +1378,7 @@ func SetTasksScheduledTime(tasks []Task, scheduledTime time.Time) error {
 	if err != nil {
 		return err
 	}
+	event.LogManyTasksEnqueued(enqueued)
 
 	return nil
 }
@@ -1328,6 +1438,108 @@ func DisableStaleContainerTasks(caller string) error {
 	return nil
 }
 
+// FallBackContainerTasksToHosts switches container tasks that have been
+// waiting too long to be allocated a container over to run on their fallback
+// distro instead.
+func FallBackContainerTasksToHosts(caller string) error {
+	query := needsContainerAllocation()
+	query[ContainerFallbackKey] = bson.M{"$exists": true}
+	tasks, err := FindAll(db.Query(query))
+	if err != nil {
+		return errors.Wrap(err, "finding container tasks with a fallback distro")
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	distroAliases, err := distro.NewDistroAliasesLookupTable()
+	if err != nil {
+		return errors.Wrap(err, "getting distro alias lookup table")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	numFellBack := 0
+	for _, t := range tasks {
+		if !t.ShouldFallBackToHost(time.Now()) {
+			continue
+		}
+		if err := t.FallBackToHost(distroAliases); err != nil {
+			catcher.Wrapf(err, "falling back task '%s' to a host", t.Id)
+			continue
+		}
+		numFellBack++
+	}
+
+	grip.InfoWhen(numFellBack > 0, message.Fields{
+		"message":   "container tasks fell back to running on hosts",
+		"num_tasks": numFellBack,
+		"caller":    caller,
+	})
+
+	return catcher.Resolve()
+}
+
+// ShouldFallBackToHost returns whether the container task has been waiting
+// longer than its fallback threshold to be allocated a container. The wait
+// begins once the task is activated and all of its dependencies are met.
+func (t *Task) ShouldFallBackToHost(now time.Time) bool {
+	if t.ContainerFallback == nil || t.ContainerFallback.DistroID == "" {
+		return false
+	}
+	if !t.ShouldAllocateContainer() {
+		return false
+	}
+	waitingSince := t.ActivatedTime
+	if t.DependenciesMetTime.After(waitingSince) {
+		waitingSince = t.DependenciesMetTime
+	}
+	if utility.IsZeroTime(waitingSince) {
+		return false
+	}
+	return now.Sub(waitingSince) >= t.ContainerFallback.After
+}
+
+// FallBackToHost switches a container task that has not yet been allocated a
+// container to run on its fallback distro. If the fallback distro is an alias,
+// the task runs on the first distro that the alias resolves to and the others
+// become its secondary distros, the same as for a task's run_on distros.
+func (t *Task) FallBackToHost(distroAliases distro.AliasLookupTable) error {
+	if t.ContainerFallback == nil || t.ContainerFallback.DistroID == "" {
+		return errors.New("task has no fallback distro")
+	}
+	resolved := distroAliases.Expand([]string{t.ContainerFallback.DistroID})
+	distroID := resolved[0]
+	secondaryDistros := resolved[1:]
+
+	q := needsContainerAllocation()
+	q[IdKey] = t.Id
+	err := UpdateOne(q, bson.M{
+		"$set": bson.M{
+			ExecutionPlatformKey: ExecutionPlatformHost,
+			DistroIdKey:          distroID,
+			DistroAliasesKey:     secondaryDistros,
+		},
+	})
+	if adb.ResultsNotFound(err) {
+		return errors.New("task no longer needs a container to be allocated")
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	grip.Info(message.Fields{
+		"message":         "container task fell back to running on a host",
+		"task":            t.Id,
+		"container":       t.Container,
+		"fallback_distro": t.ContainerFallback.DistroID,
+		"distro":          distroID,
+	})
+	t.ExecutionPlatform = ExecutionPlatformHost
+	t.DistroId = distroID
+	t.DistroAliases = secondaryDistros
+
+	return nil
+}
+
 // DeactivateStepbackTasksForProjects deactivates and aborts any scheduled/running tasks
 // for this project that were activated by stepback.
 func DeactivateStepbackTasksForProject(projectId, caller string) error {
@@ -1438,6 +1650,61 @@ func (t *Task) SetAborted(reason AbortInfo) error {
 	)
 }
 
+// SetCommandAbortRequested requests that the agent skip the command that's
+// running in the task and continue with the rest of the task. It errors if
+// the task isn't running.
+func (t *Task) SetCommandAbortRequested(reason AbortInfo) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey:        t.Id,
+			ExecutionKey: t.Execution,
+			StatusKey:    evergreen.TaskStarted,
+		},
+		bson.M{
+			"$set": bson.M{
+				CommandAbortKey: reason,
+			},
+		},
+	)
+	if adb.ResultsNotFound(err) {
+		return errors.Errorf("task '%s' is not running a command", t.Id)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "requesting command abort for task '%s'", t.Id)
+	}
+	t.CommandAbort = &reason
+	return nil
+}
+
+// ClaimCommandAbort clears the task's pending command abort request and
+// returns whether there was one, so that the agent only skips one command for
+// each request.
+func (t *Task) ClaimCommandAbort() (bool, error) {
+	if t.CommandAbort == nil {
+		return false, nil
+	}
+	err := UpdateOne(
+		bson.M{
+			IdKey:           t.Id,
+			CommandAbortKey: bson.M{"$exists": true},
+		},
+		bson.M{
+			"$unset": bson.M{
+				CommandAbortKey: 1,
+			},
+		},
+	)
+	if adb.ResultsNotFound(err) {
+		// Another heartbeat already claimed the request.
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "claiming command abort for task '%s'", t.Id)
+	}
+	t.CommandAbort = nil
+	return true, nil
+}
+
 // SetHasCedarResults sets the HasCedarResults field of the task to
 // hasCedarResults and, if failedResults is true, sets CedarResultsFailed to
 // true. If the task is part of a display task, the display tasks's fields are
@@ -1562,6 +1829,42 @@ func ActivateTasksByIdsWithDependencies(ids []string, caller string) error {
 // ActivateDeactivatedDependencies activates tasks that depend on these tasks which were deactivated because a task
 // they depended on was deactivated. Only activate when all their dependencies are activated or are being activated
 func ActivateDeactivatedDependencies(tasks []string, caller string) error {
+	tasksToActivate, err := FindDeactivatedDependenciesToActivate(tasks)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(tasksToActivate) == 0 {
+		return nil
+	}
+
+	taskIDsToActivate := make([]string, 0, len(tasksToActivate))
+	for _, t := range tasksToActivate {
+		taskIDsToActivate = append(taskIDsToActivate, t.Id)
+	}
+	_, err = UpdateAll(
+		bson.M{IdKey: bson.M{"$in": taskIDsToActivate}},
+		bson.M{"$set": bson.M{
+			ActivatedKey:                true,
+			DeactivatedForDependencyKey: false,
+			ActivatedByKey:              caller,
+			ActivatedTimeKey:            time.Now(),
+		}},
+	)
+	if err != nil {
+		return errors.Wrap(err, "updating activation for dependencies")
+	}
+
+	for _, t := range tasksToActivate {
+		event.LogTaskActivated(t.Id, t.Execution, caller)
+	}
+
+	return nil
+}
+
+// FindDeactivatedDependenciesToActivate returns the tasks that depend on the
+// given tasks and were deactivated because of them, which are activated
+// again once the given tasks are activated.
+func FindDeactivatedDependenciesToActivate(tasks []string) ([]Task, error) {
 	taskMap := make(map[string]bool)
 	for _, t := range tasks {
 		taskMap[t] = true
@@ -1569,14 +1872,14 @@ func ActivateDeactivatedDependencies(tasks []string, caller string) error {
 
 	tasksDependingOnTheseTasks, err := getRecursiveDependenciesDown(tasks, nil)
 	if err != nil {
-		return errors.Wrap(err, "getting recursive dependencies down")
+		return nil, errors.Wrap(err, "getting recursive dependencies down")
 	}
 
 	// do a topological sort so we've dealt with
 	// all a task's dependencies by the time we get up to it
 	sortedDependencies, err := topologicalSort(tasksDependingOnTheseTasks)
 	if err != nil {
-		return errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 
 	// get dependencies we don't have yet and add them to a map
@@ -1590,6 +1893,9 @@ func ActivateDeactivatedDependencies(tasks []string, caller string) error {
 		}
 
 		for _, dep := range t.DependsOn {
+			if dep.CrossVersion {
+				continue
+			}
 			if !taskMap[dep.TaskId] && !depTaskMap[dep.TaskId] {
 				tasksToGet = append(tasksToGet, dep.TaskId)
 			}
@@ -1601,7 +1907,7 @@ func ActivateDeactivatedDependencies(tasks []string, caller string) error {
 		var missingTasks []Task
 		missingTasks, err = FindAll(db.Query(bson.M{IdKey: bson.M{"$in": tasksToGet}}).WithFields(ActivatedKey))
 		if err != nil {
-			return errors.Wrap(err, "getting missing tasks")
+			return nil, errors.Wrap(err, "getting missing tasks")
 		}
 		for _, t := range missingTasks {
 			missingTaskMap[t.Id] = t
@@ -1616,6 +1922,10 @@ func ActivateDeactivatedDependencies(tasks []string, caller string) error {
 
 		depsSatisfied := true
 		for _, dep := range t.DependsOn {
+			// tasks in other versions are not activated with this one
+			if dep.CrossVersion {
+				continue
+			}
 			// not being activated now
 			if _, ok := tasksToActivate[dep.TaskId]; !ok && !taskMap[dep.TaskId] {
 				// and not already activated
@@ -1630,32 +1940,11 @@ func ActivateDeactivatedDependencies(tasks []string, caller string) error {
 		}
 	}
 
-	if len(tasksToActivate) == 0 {
-		return nil
-	}
-
-	taskIDsToActivate := make([]string, 0, len(tasksToActivate))
+	activated := make([]Task, 0, len(tasksToActivate))
 	for _, t := range tasksToActivate {
-		taskIDsToActivate = append(taskIDsToActivate, t.Id)
+		activated = append(activated, t)
 	}
-	_, err = UpdateAll(
-		bson.M{IdKey: bson.M{"$in": taskIDsToActivate}},
-		bson.M{"$set": bson.M{
-			ActivatedKey:                true,
-			DeactivatedForDependencyKey: false,
-			ActivatedByKey:              caller,
-			ActivatedTimeKey:            time.Now(),
-		}},
-	)
-	if err != nil {
-		return errors.Wrap(err, "updating activation for dependencies")
-	}
-
-	for _, t := range tasksToActivate {
-		event.LogTaskActivated(t.Id, t.Execution, caller)
-	}
-
-	return nil
+	return activated, nil
 }
 
 func topologicalSort(tasks []Task) ([]Task, error) {
@@ -1734,25 +2023,37 @@ func DeactivateTasks(tasks []Task, updateDependencies bool, caller string) error
 	return nil
 }
 
-func DeactivateDependencies(tasks []string, caller string) error {
+// FindDependenciesToDeactivate returns the activated tasks that depend on the
+// given tasks, which are deactivated along with them.
+func FindDependenciesToDeactivate(tasks []string) ([]Task, error) {
 	tasksDependingOnTheseTasks, err := getRecursiveDependenciesDown(tasks, nil)
 	if err != nil {
-		return errors.Wrap(err, "getting recursive dependencies down")
+		return nil, errors.Wrap(err, "getting recursive dependencies down")
 	}
 
 	tasksToUpdate := make([]Task, 0, len(tasksDependingOnTheseTasks))
-	taskIDsToUpdate := make([]string, 0, len(tasksDependingOnTheseTasks))
 	for _, t := range tasksDependingOnTheseTasks {
 		if t.Activated {
 			tasksToUpdate = append(tasksToUpdate, t)
-			taskIDsToUpdate = append(taskIDsToUpdate, t.Id)
 		}
 	}
+	return tasksToUpdate, nil
+}
 
+func DeactivateDependencies(tasks []string, caller string) error {
+	tasksToUpdate, err := FindDependenciesToDeactivate(tasks)
+	if err != nil {
+		return errors.WithStack(err)
+	}
 	if len(tasksToUpdate) == 0 {
 		return nil
 	}
 
+	taskIDsToUpdate := make([]string, 0, len(tasksToUpdate))
+	for _, t := range tasksToUpdate {
+		taskIDsToUpdate = append(taskIDsToUpdate, t.Id)
+	}
+
 	_, err = UpdateAll(
 		bson.M{
 			IdKey: bson.M{"$in": taskIDsToUpdate},
@@ -1802,22 +2103,128 @@ func (t *Task) MarkEnd(finishTime time.Time, detail *apimodels.TaskEndDetail) er
 	t.Status = detail.Status
 	t.FinishTime = finishTime
 	t.Details = *detail
+	set := bson.M{
+		FinishTimeKey:       finishTime,
+		StatusKey:           detail.Status,
+		TimeTakenKey:        t.TimeTaken,
+		DetailsKey:          detail,
+		StartTimeKey:        t.StartTime,
+		LogsKey:             detail.Logs,
+		HasLegacyResultsKey: t.HasLegacyResults,
+	}
+	if t.FailureFingerprint != "" {
+		set[FailureFingerprintKey] = t.FailureFingerprint
+	}
 	return UpdateOne(
 		bson.M{
 			IdKey: t.Id,
 		},
+		bson.M{
+			"$set": set,
+		})
+
+}
+
+// ClaimEndTaskRequest records that the end task request with the given
+// idempotency key is ending the task execution. It returns false if another
+// request already claimed the execution, in which case the request should not
+// end the task again.
+func ClaimEndTaskRequest(taskID string, execution int, idempotencyKey string) (bool, error) {
+	err := UpdateOne(
+		bson.M{
+			IdKey:             taskID,
+			ExecutionKey:      execution,
+			EndTaskRequestKey: bson.M{"$exists": false},
+		},
 		bson.M{
 			"$set": bson.M{
-				FinishTimeKey:       finishTime,
-				StatusKey:           detail.Status,
-				TimeTakenKey:        t.TimeTaken,
-				DetailsKey:          detail,
-				StartTimeKey:        t.StartTime,
-				LogsKey:             detail.Logs,
-				HasLegacyResultsKey: t.HasLegacyResults,
+				EndTaskRequestKey: EndTaskRequest{IdempotencyKey: idempotencyKey},
 			},
-		})
+		},
+	)
+	if adb.ResultsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "claiming end task request for task '%s' execution %d", taskID, execution)
+	}
+	return true, nil
+}
+
+// ReleaseEndTaskRequest removes the claim that the end task request with the
+// given idempotency key made on the task execution, so that a retry of the
+// request can end the task.
+func ReleaseEndTaskRequest(taskID string, execution int, idempotencyKey string) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey:        taskID,
+			ExecutionKey: execution,
+			bsonutil.GetDottedKeyName(EndTaskRequestKey, EndTaskRequestIdempotencyKeyKey): idempotencyKey,
+		},
+		bson.M{
+			"$unset": bson.M{
+				EndTaskRequestKey: 1,
+			},
+		},
+	)
+	if err != nil && !adb.ResultsNotFound(err) {
+		return errors.Wrapf(err, "releasing end task request for task '%s' execution %d", taskID, execution)
+	}
+	return nil
+}
+
+// SetEndTaskResponse records the response to the end task request with the
+// given idempotency key so that it can be replayed to retries of the request.
+func (t *Task) SetEndTaskResponse(idempotencyKey string, resp apimodels.EndTaskResponse) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey:        t.Id,
+			ExecutionKey: t.Execution,
+			bsonutil.GetDottedKeyName(EndTaskRequestKey, EndTaskRequestIdempotencyKeyKey): idempotencyKey,
+		},
+		bson.M{
+			"$set": bson.M{
+				bsonutil.GetDottedKeyName(EndTaskRequestKey, EndTaskRequestResponseKey): resp,
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "setting end task response for task '%s' execution %d", t.Id, t.Execution)
+	}
+	t.EndTaskRequest = &EndTaskRequest{IdempotencyKey: idempotencyKey, Response: &resp}
+	return nil
+}
 
+// SetFinalizingUntil records that the agent has started ending the task
+// execution and can finish ending it until the given time. It returns false if
+// the execution is no longer in progress.
+func (t *Task) SetFinalizingUntil(until time.Time) (bool, error) {
+	err := UpdateOne(
+		bson.M{
+			IdKey:        t.Id,
+			ExecutionKey: t.Execution,
+			StatusKey:    bson.M{"$in": evergreen.TaskAbortableStatuses},
+		},
+		bson.M{
+			"$set": bson.M{
+				FinalizingUntilKey: until,
+			},
+		},
+	)
+	if adb.ResultsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "setting finalizing time for task '%s' execution %d", t.Id, t.Execution)
+	}
+	t.FinalizingUntil = until
+	return true, nil
+}
+
+// IsFinalizing returns whether the agent is still within its window to finish
+// ending the task execution.
+func (t *Task) IsFinalizing() bool {
+	return time.Now().Before(t.FinalizingUntil)
 }
 
 // GetDisplayStatus should reflect the statuses assigned during the addDisplayStatus aggregation step
@@ -1840,8 +2247,8 @@ func (t *Task) GetDisplayStatus() string {
 	if !t.IsFinished() {
 		return t.Status
 	}
-	if t.Status == evergreen.TaskSucceeded {
-		return evergreen.TaskSucceeded
+	if t.Status == evergreen.TaskSucceeded || t.Status == evergreen.TaskSkipped {
+		return t.Status
 	}
 	if t.Details.Type == evergreen.CommandTypeSystem {
 		if t.Details.TimedOut && t.Details.Description == evergreen.TaskDescriptionHeartbeat {
@@ -1888,6 +2295,8 @@ func (t *Task) displayTaskPriority() int {
 		return 100
 	case evergreen.TaskSucceeded:
 		return 110
+	case evergreen.TaskSkipped:
+		return 120
 	}
 	return 1000
 }
@@ -1945,6 +2354,12 @@ func resetTaskUpdate(t *Task) bson.M {
 		t.AgentVersion = ""
 		t.HostCreateDetails = []HostCreateDetail{}
 		t.OverrideDependencies = false
+		t.EndTaskRequest = nil
+		t.FinalizingUntil = time.Time{}
+		t.Outputs = nil
+		t.StuckTime = utility.ZeroTime
+		t.FailureFingerprint = ""
+		t.SkipReason = ""
 	}
 	update := bson.M{
 		"$set": bson.M{
@@ -1969,24 +2384,81 @@ func resetTaskUpdate(t *Task) bson.M {
 			HostIdKey:               "",
 			HostCreateDetailsKey:    "",
 			OverrideDependenciesKey: "",
+			EndTaskRequestKey:       "",
+			FinalizingUntilKey:      "",
+			OutputsKey:              "",
+			StuckTimeKey:            "",
+			FailureFingerprintKey:   "",
+			SkipReasonKey:           "",
 		},
 	}
 	return update
 }
 
+// SetOutputs sets the outputs that the task published. Outputs that the task
+// published previously are kept unless they're set again.
+func (t *Task) SetOutputs(outputs map[string]string) error {
+	if len(outputs) == 0 {
+		return nil
+	}
+	set := bson.M{}
+	for name, value := range outputs {
+		set[bsonutil.GetDottedKeyName(OutputsKey, name)] = value
+	}
+	if err := UpdateOne(
+		bson.M{IdKey: t.Id},
+		bson.M{"$set": set},
+	); err != nil {
+		return err
+	}
+	if t.Outputs == nil {
+		t.Outputs = map[string]string{}
+	}
+	for name, value := range outputs {
+		t.Outputs[name] = value
+	}
+	return nil
+}
+
+// MarkStuck flags the task as stuck if it's still running and hasn't already
+// been flagged. It returns whether the task was newly flagged.
+func (t *Task) MarkStuck(stuckTime time.Time) (bool, error) {
+	res, err := evergreen.GetEnvironment().DB().Collection(Collection).UpdateOne(context.Background(),
+		bson.M{
+			IdKey:        t.Id,
+			ExecutionKey: t.Execution,
+			StatusKey:    evergreen.TaskStarted,
+			StuckTimeKey: bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{StuckTimeKey: stuckTime}},
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "marking task stuck")
+	}
+	if res.ModifiedCount == 0 {
+		return false, nil
+	}
+	t.StuckTime = stuckTime
+	return true, nil
+}
+
 // UpdateHeartbeat updates the heartbeat to be the current time
 func (t *Task) UpdateHeartbeat() error {
 	t.LastHeartbeat = time.Now()
-	return UpdateOne(
-		bson.M{
-			IdKey: t.Id,
-		},
-		bson.M{
-			"$set": bson.M{
-				LastHeartbeatKey: t.LastHeartbeat,
-			},
+	update := bson.M{
+		"$set": bson.M{
+			LastHeartbeatKey: t.LastHeartbeat,
 		},
-	)
+	}
+	// A task that heartbeats again after being flagged is no longer stuck.
+	if !utility.IsZeroTime(t.StuckTime) {
+		update["$unset"] = bson.M{StuckTimeKey: 1}
+	}
+	if err := UpdateOne(bson.M{IdKey: t.Id}, update); err != nil {
+		return err
+	}
+	t.StuckTime = utility.ZeroTime
+	return nil
 }
 
 // SetDisabledPriority sets the priority of a task so it will never run. If it's
@@ -2095,6 +2567,11 @@ func GetRecursiveDependenciesUp(tasks []Task, depCache map[string]Task) ([]Task,
 	tasksToFind := []string{}
 	for _, t := range tasks {
 		for _, dep := range t.DependsOn {
+			// Tasks in other versions are not activated along with
+			// their dependents.
+			if dep.CrossVersion {
+				continue
+			}
 			if _, ok := depCache[dep.TaskId]; !ok {
 				tasksToFind = append(tasksToFind, dep.TaskId)
 			}
@@ -2146,7 +2623,7 @@ func getRecursiveDependenciesDown(tasks []string, taskMap map[string]bool) ([]Ta
 	// find the tasks that depend on these tasks
 	query := db.Query(bson.M{
 		bsonutil.GetDottedKeyName(DependsOnKey, DependencyTaskIdKey): bson.M{"$in": tasks},
-	}).WithFields(IdKey, ActivatedKey, DeactivatedForDependencyKey, ExecutionKey, DependsOnKey, BuildIdKey)
+	}).WithFields(IdKey, ActivatedKey, DeactivatedForDependencyKey, ExecutionKey, DependsOnKey, BuildIdKey, VersionKey)
 	dependOnUsTasks, err := FindAll(query)
 	if err != nil {
 		return nil, errors.Wrap(err, "can't get dependencies")
@@ -2217,6 +2694,19 @@ func (t *Task) SetResults(results []TestResult) error {
 	return errors.Wrap(testresult.InsertMany(docs), "inserting test results")
 }
 
+// GetNewStyleTestResults returns the task's test results, whether they are
+// stored in Evergreen or in Cedar, with the task's fields copied into them.
+func (t *Task) GetNewStyleTestResults() ([]testresult.TestResult, error) {
+	if err := t.PopulateTestResults(); err != nil {
+		return nil, errors.Wrap(err, "populating test results")
+	}
+	results := make([]testresult.TestResult, 0, len(t.LocalTestResults))
+	for _, result := range t.LocalTestResults {
+		results = append(results, result.convertToNewStyleTestResult(t))
+	}
+	return results, nil
+}
+
 func (t TestResult) convertToNewStyleTestResult(task *Task) testresult.TestResult {
 	ExecutionDisplayName := ""
 	if displayTask, _ := task.GetDisplayTask(); displayTask != nil {
@@ -2235,6 +2725,8 @@ func (t TestResult) convertToNewStyleTestResult(task *Task) testresult.TestResul
 		ExitCode:        t.ExitCode,
 		StartTime:       t.StartTime,
 		EndTime:         t.EndTime,
+		Artifacts:       t.Artifacts,
+		TaskLogRange:    t.TaskLogRange,
 
 		// copy field values from enclosing tasks.
 		TaskID:               task.Id,
@@ -2266,6 +2758,8 @@ func ConvertToOld(in *testresult.TestResult) TestResult {
 		ExitCode:        in.ExitCode,
 		StartTime:       in.StartTime,
 		EndTime:         in.EndTime,
+		Artifacts:       in.Artifacts,
+		TaskLogRange:    in.TaskLogRange,
 		LogRaw:          in.LogRaw,
 		TaskID:          in.TaskID,
 		Execution:       in.Execution,
@@ -2288,6 +2782,43 @@ func (t *Task) MarkUnscheduled() error {
 
 }
 
+// MarkSkipped marks a task that has not been dispatched as skipped with the
+// reason that it was intentionally not run.
+func (t *Task) MarkSkipped(reason, caller string) error {
+	if t.Status != evergreen.TaskUndispatched {
+		return errors.Errorf("task '%s' cannot be skipped because its status is '%s'", t.Id, t.Status)
+	}
+	if reason == "" {
+		return errors.New("must specify a reason for skipping the task")
+	}
+	finishTime := time.Now()
+	err := UpdateOne(
+		bson.M{
+			IdKey:     t.Id,
+			StatusKey: evergreen.TaskUndispatched,
+		},
+		bson.M{
+			"$set": bson.M{
+				StatusKey:     evergreen.TaskSkipped,
+				SkipReasonKey: reason,
+				FinishTimeKey: finishTime,
+			},
+		},
+	)
+	if adb.ResultsNotFound(err) {
+		return errors.Errorf("task '%s' was dispatched before it could be skipped", t.Id)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "marking task '%s' skipped", t.Id)
+	}
+	t.Status = evergreen.TaskSkipped
+	t.SkipReason = reason
+	t.FinishTime = finishTime
+	event.LogTaskSkipped(t.Id, t.Execution, caller)
+
+	return nil
+}
+
 // MarkUnattainableDependency updates the unattainable field for the dependency in the task's dependency list,
 // and logs if the task is newly blocked.
 func (t *Task) MarkUnattainableDependency(dependencyId string, unattainable bool) error {
@@ -2348,9 +2879,9 @@ func AbortBuild(buildId string, reason AbortInfo) error {
 	return nil
 }
 
-// AbortVersion sets the abort flag on all tasks associated with the version which are in an
-// abortable state
-func AbortVersion(versionId string, reason AbortInfo) error {
+// FindAbortableTaskIDsForVersion returns the IDs of the version's tasks that
+// AbortVersion would abort for the given reason.
+func FindAbortableTaskIDsForVersion(versionId string, reason AbortInfo) ([]string, error) {
 	q := bson.M{
 		VersionKey: versionId,
 		StatusKey:  bson.M{"$in": evergreen.TaskAbortableStatuses},
@@ -2360,7 +2891,13 @@ func AbortVersion(versionId string, reason AbortInfo) error {
 		// if the aborting task is part of a display task, we also don't want to mark it as aborted
 		q[ExecutionTasksKey] = bson.M{"$ne": reason.TaskID}
 	}
-	ids, err := findAllTaskIDs(db.Query(q))
+	return findAllTaskIDs(db.Query(q))
+}
+
+// AbortVersion sets the abort flag on all tasks associated with the version which are in an
+// abortable state
+func AbortVersion(versionId string, reason AbortInfo) error {
+	ids, err := FindAbortableTaskIDsForVersion(versionId, reason)
 	if err != nil {
 		return errors.Wrap(err, "finding updated tasks")
 	}
@@ -4152,8 +4689,9 @@ func (t *Task) UpdateDependsOn(status string, newDependencyIDs []string) error {
 	_, err := UpdateAll(
 		bson.M{
 			DependsOnKey: bson.M{"$elemMatch": bson.M{
-				DependencyTaskIdKey: t.Id,
-				DependencyStatusKey: status,
+				DependencyTaskIdKey:             t.Id,
+				DependencyStatusKey:             status,
+				DependencyOmitGeneratedTasksKey: bson.M{"$ne": true},
 			}},
 		},
 		bson.M{"$push": bson.M{DependsOnKey: bson.M{"$each": newDependencies}}},
@@ -4251,6 +4789,16 @@ func (t *Task) getCedarTestResults() ([]TestResult, error) {
 	ctx, cancel := evergreen.GetEnvironment().Context()
 	defer cancel()
 
+	return t.getCedarTestResultsWithStatuses(ctx, nil)
+}
+
+// GetCedarFailedTestResults returns the task's failed test results from
+// Cedar, if it has any.
+func (t *Task) GetCedarFailedTestResults(ctx context.Context) ([]TestResult, error) {
+	return t.getCedarTestResultsWithStatuses(ctx, []string{evergreen.TestFailedStatus})
+}
+
+func (t *Task) getCedarTestResultsWithStatuses(ctx context.Context, statuses []string) ([]TestResult, error) {
 	if !t.hasCedarResults() {
 		return nil, nil
 	}
@@ -4265,6 +4813,7 @@ func (t *Task) getCedarTestResults() ([]TestResult, error) {
 		TaskID:      taskID,
 		Execution:   utility.ToIntPtr(t.Execution),
 		DisplayTask: t.DisplayOnly,
+		Statuses:    statuses,
 	}
 
 	cedarResults, err := apimodels.GetCedarTestResultsWithStatusError(ctx, opts)
@@ -4276,10 +4825,39 @@ func (t *Task) getCedarTestResults() ([]TestResult, error) {
 	for i, result := range cedarResults.Results {
 		results[i] = ConvertCedarTestResult(result)
 	}
+	if err = addCedarTestResultAttachments(results); err != nil {
+		return nil, errors.Wrap(err, "adding attachments to test results from cedar")
+	}
 
 	return results, nil
 }
 
+// addCedarTestResultAttachments adds the artifacts and task log ranges that
+// are stored in Evergreen to the test results from Cedar.
+func addCedarTestResultAttachments(results []TestResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	var taskIDs []string
+	for _, result := range results {
+		if !utility.StringSliceContains(taskIDs, result.TaskID) {
+			taskIDs = append(taskIDs, result.TaskID)
+		}
+	}
+	attachments, err := testresult.FindAttachmentsForTasks(taskIDs)
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		// The Cedar test name is converted to the test file.
+		if a, ok := attachments[testresult.AttachmentsID(result.TaskID, result.Execution, result.TestFile)]; ok {
+			results[i].Artifacts = a.Artifacts
+			results[i].TaskLogRange = a.TaskLogRange
+		}
+	}
+	return nil
+}
+
 func (t *Task) hasCedarResults() bool {
 	if !t.DisplayOnly || t.HasCedarResults {
 		return t.HasCedarResults
@@ -1035,6 +1035,29 @@ func TestPopulateTestResultsForDisplayTask(t *testing.T) {
 	assert.Equal("myTest", dt.LocalTestResults[0].TestFile)
 }
 
+func TestGetNewStyleTestResults(t *testing.T) {
+	require.NoError(t, db.ClearCollections(Collection, testresult.Collection))
+	tsk := Task{
+		Id:           "t1",
+		Execution:    1,
+		Status:       evergreen.TaskFailed,
+		Project:      "p",
+		BuildVariant: "bv",
+		DisplayName:  "compile",
+	}
+	require.NoError(t, tsk.Insert())
+	require.NoError(t, (&testresult.TestResult{TaskID: "t1", Execution: 1, TestFile: "myTest", Status: evergreen.TestFailedStatus}).Insert())
+
+	results, err := tsk.GetNewStyleTestResults()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "myTest", results[0].TestFile)
+	assert.Equal(t, "p", results[0].Project)
+	assert.Equal(t, "bv", results[0].BuildVariant)
+	assert.Equal(t, "compile", results[0].DisplayName)
+	assert.Equal(t, 1, results[0].Execution)
+}
+
 func TestBlocked(t *testing.T) {
 	for name, test := range map[string]func(*testing.T){
 		"Blocked": func(*testing.T) {
@@ -1334,6 +1357,92 @@ func TestDisableStaleContainerTasks(t *testing.T) {
 	}
 }
 
+func TestFallBackContainerTasksToHosts(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(Collection, distro.Collection))
+	}()
+	for tName, tCase := range map[string]func(t *testing.T, tsk Task){
+		"FallsBackTaskWaitingPastThreshold": func(t *testing.T, tsk Task) {
+			tsk.ActivatedTime = time.Now().Add(-time.Hour)
+			require.NoError(t, tsk.Insert())
+
+			require.NoError(t, FallBackContainerTasksToHosts(t.Name()))
+
+			dbTask, err := FindOneId(tsk.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbTask)
+			assert.True(t, dbTask.IsHostTask())
+			assert.Equal(t, "fallback_distro", dbTask.DistroId)
+			assert.Empty(t, dbTask.DistroAliases)
+		},
+		"ResolvesFallbackDistroAlias": func(t *testing.T, tsk Task) {
+			tsk.ActivatedTime = time.Now().Add(-time.Hour)
+			tsk.ContainerFallback.DistroID = "fallback_alias"
+			require.NoError(t, tsk.Insert())
+			d1 := distro.Distro{Id: "d1", Aliases: []string{"fallback_alias"}, HostAllocatorSettings: distro.HostAllocatorSettings{MaximumHosts: 10}}
+			require.NoError(t, d1.Insert())
+			d2 := distro.Distro{Id: "d2", Aliases: []string{"fallback_alias"}, HostAllocatorSettings: distro.HostAllocatorSettings{MaximumHosts: 5}}
+			require.NoError(t, d2.Insert())
+
+			require.NoError(t, FallBackContainerTasksToHosts(t.Name()))
+
+			dbTask, err := FindOneId(tsk.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbTask)
+			assert.True(t, dbTask.IsHostTask())
+			assert.Equal(t, "d1", dbTask.DistroId)
+			assert.Equal(t, []string{"d2"}, dbTask.DistroAliases)
+		},
+		"IgnoresTaskWaitingWithinThreshold": func(t *testing.T, tsk Task) {
+			tsk.ActivatedTime = time.Now().Add(-time.Hour)
+			tsk.DependenciesMetTime = time.Now().Add(-time.Minute)
+			require.NoError(t, tsk.Insert())
+
+			require.NoError(t, FallBackContainerTasksToHosts(t.Name()))
+
+			dbTask, err := FindOneId(tsk.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbTask)
+			assert.True(t, dbTask.IsContainerTask())
+			assert.Zero(t, dbTask.DistroId)
+		},
+		"IgnoresTaskWithoutFallback": func(t *testing.T, tsk Task) {
+			tsk.ActivatedTime = time.Now().Add(-time.Hour)
+			tsk.ContainerFallback = nil
+			require.NoError(t, tsk.Insert())
+
+			require.NoError(t, FallBackContainerTasksToHosts(t.Name()))
+
+			dbTask, err := FindOneId(tsk.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbTask)
+			assert.True(t, dbTask.IsContainerTask())
+		},
+		"IgnoresAllocatedTask": func(t *testing.T, tsk Task) {
+			tsk.ActivatedTime = time.Now().Add(-time.Hour)
+			tsk.ContainerAllocated = true
+			require.NoError(t, tsk.Insert())
+
+			require.NoError(t, FallBackContainerTasksToHosts(t.Name()))
+
+			dbTask, err := FindOneId(tsk.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbTask)
+			assert.True(t, dbTask.IsContainerTask())
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(Collection, distro.Collection))
+			tsk := getTaskThatNeedsContainerAllocation()
+			tsk.ContainerFallback = &ContainerFallbackOptions{
+				DistroID: "fallback_distro",
+				After:    30 * time.Minute,
+			}
+			tCase(t, tsk)
+		})
+	}
+}
+
 func TestDeactivateStepbackTasksForProject(t *testing.T) {
 	require.NoError(t, db.ClearCollections(Collection, event.AllLogCollection))
 
@@ -1811,6 +1920,62 @@ func TestUpdateDependsOn(t *testing.T) {
 	assert.Len(t, t2.DependsOn, 4)
 }
 
+func TestUpdateDependsOnOmitGeneratedTasks(t *testing.T) {
+	require.NoError(t, db.ClearCollections(Collection))
+	generator := &Task{Id: "generator"}
+	assert.NoError(t, generator.Insert())
+	omitting := &Task{
+		Id: "omitting",
+		DependsOn: []Dependency{
+			{TaskId: "generator", Status: evergreen.TaskSucceeded, OmitGeneratedTasks: true},
+		},
+	}
+	assert.NoError(t, omitting.Insert())
+	waiting := &Task{
+		Id: "waiting",
+		DependsOn: []Dependency{
+			{TaskId: "generator", Status: evergreen.TaskSucceeded},
+		},
+	}
+	assert.NoError(t, waiting.Insert())
+
+	assert.NoError(t, generator.UpdateDependsOn(evergreen.TaskSucceeded, []string{"generated"}))
+
+	dbOmitting, err := FindOneId("omitting")
+	require.NoError(t, err)
+	require.NotNil(t, dbOmitting)
+	assert.Len(t, dbOmitting.DependsOn, 1)
+
+	dbWaiting, err := FindOneId("waiting")
+	require.NoError(t, err)
+	require.NotNil(t, dbWaiting)
+	require.Len(t, dbWaiting.DependsOn, 2)
+	assert.Equal(t, "generated", dbWaiting.DependsOn[1].TaskId)
+}
+
+func TestGetRecursiveDependenciesUpSkipsCrossVersion(t *testing.T) {
+	require.NoError(t, db.ClearCollections(Collection))
+	for _, tsk := range []Task{
+		{Id: "same_version_dep", Version: "v2"},
+		{Id: "cross_version_dep", Version: "v1"},
+	} {
+		require.NoError(t, tsk.Insert())
+	}
+	dependent := Task{
+		Id:      "dependent",
+		Version: "v2",
+		DependsOn: []Dependency{
+			{TaskId: "same_version_dep", Status: evergreen.TaskSucceeded},
+			{TaskId: "cross_version_dep", Status: evergreen.TaskSucceeded, CrossVersion: true},
+		},
+	}
+
+	deps, err := GetRecursiveDependenciesUp([]Task{dependent}, nil)
+	require.NoError(t, err)
+	require.Len(t, deps, 1)
+	assert.Equal(t, "same_version_dep", deps[0].Id)
+}
+
 func TestDisplayTaskCache(t *testing.T) {
 	assert := assert.New(t)
 	require.NoError(t, db.Clear(Collection))
@@ -4177,3 +4342,122 @@ func assertTasksAreEqual(t *testing.T, expected, actual Task, exectedExecution i
 	assert.Equal(t, expected.Status, actual.Status)
 	assert.Equal(t, exectedExecution, actual.Execution)
 }
+
+func TestEndTaskRequest(t *testing.T) {
+	require.NoError(t, db.ClearCollections(Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(Collection))
+	}()
+
+	tsk := Task{Id: "t1", Execution: 1, Status: evergreen.TaskStarted}
+	require.NoError(t, tsk.Insert())
+
+	claimed, err := ClaimEndTaskRequest(tsk.Id, 0, "key")
+	require.NoError(t, err)
+	assert.False(t, claimed, "should not claim a different execution")
+
+	claimed, err = ClaimEndTaskRequest(tsk.Id, tsk.Execution, "key")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = ClaimEndTaskRequest(tsk.Id, tsk.Execution, "key")
+	require.NoError(t, err)
+	assert.False(t, claimed, "should not claim an execution twice")
+	claimed, err = ClaimEndTaskRequest(tsk.Id, tsk.Execution, "other")
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	assert.Error(t, tsk.SetEndTaskResponse("other", apimodels.EndTaskResponse{ShouldExit: true}))
+	require.NoError(t, tsk.SetEndTaskResponse("key", apimodels.EndTaskResponse{ShouldExit: true}))
+	dbTask, err := FindOneId(tsk.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbTask.EndTaskRequest)
+	assert.Equal(t, "key", dbTask.EndTaskRequest.IdempotencyKey)
+	require.NotNil(t, dbTask.EndTaskRequest.Response)
+	assert.True(t, dbTask.EndTaskRequest.Response.ShouldExit)
+
+	require.NoError(t, ReleaseEndTaskRequest(tsk.Id, tsk.Execution, "other"))
+	dbTask, err = FindOneId(tsk.Id)
+	require.NoError(t, err)
+	assert.NotNil(t, dbTask.EndTaskRequest, "should not release another request's claim")
+
+	require.NoError(t, ReleaseEndTaskRequest(tsk.Id, tsk.Execution, "key"))
+	claimed, err = ClaimEndTaskRequest(tsk.Id, tsk.Execution, "other")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	require.NoError(t, dbTask.Reset())
+	dbTask, err = FindOneId(tsk.Id)
+	require.NoError(t, err)
+	assert.Nil(t, dbTask.EndTaskRequest)
+}
+
+func TestSetFinalizingUntil(t *testing.T) {
+	require.NoError(t, db.ClearCollections(Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(Collection))
+	}()
+
+	finished := Task{Id: "finished", Status: evergreen.TaskSucceeded}
+	require.NoError(t, finished.Insert())
+	ok, err := finished.SetFinalizingUntil(time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, finished.IsFinalizing())
+
+	tsk := Task{Id: "t1", Execution: 1, Status: evergreen.TaskStarted}
+	require.NoError(t, tsk.Insert())
+	assert.False(t, tsk.IsFinalizing())
+	stale := tsk
+	stale.Execution = 0
+	ok, err = stale.SetFinalizingUntil(time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, ok, "should not finalize a different execution")
+
+	ok, err = tsk.SetFinalizingUntil(time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	dbTask, err := FindOneId(tsk.Id)
+	require.NoError(t, err)
+	assert.True(t, dbTask.IsFinalizing())
+
+	require.NoError(t, dbTask.Reset())
+	dbTask, err = FindOneId(tsk.Id)
+	require.NoError(t, err)
+	assert.False(t, dbTask.IsFinalizing())
+}
+
+func TestCommandAbort(t *testing.T) {
+	require.NoError(t, db.ClearCollections(Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(Collection))
+	}()
+
+	unstarted := Task{Id: "unstarted", Status: evergreen.TaskUndispatched}
+	require.NoError(t, unstarted.Insert())
+	assert.Error(t, unstarted.SetCommandAbortRequested(AbortInfo{User: "me"}))
+
+	tsk := Task{Id: "t1", Status: evergreen.TaskStarted}
+	require.NoError(t, tsk.Insert())
+	claimed, err := tsk.ClaimCommandAbort()
+	require.NoError(t, err)
+	assert.False(t, claimed, "should not claim without a request")
+
+	require.NoError(t, tsk.SetCommandAbortRequested(AbortInfo{User: "me"}))
+	dbTask, err := FindOneId(tsk.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbTask.CommandAbort)
+	assert.Equal(t, "me", dbTask.CommandAbort.User)
+
+	otherHeartbeat := *dbTask
+	claimed, err = dbTask.ClaimCommandAbort()
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = otherHeartbeat.ClaimCommandAbort()
+	require.NoError(t, err)
+	assert.False(t, claimed, "should only claim a request once")
+
+	dbTask, err = FindOneId(tsk.Id)
+	require.NoError(t, err)
+	assert.Nil(t, dbTask.CommandAbort)
+}
@@ -0,0 +1,66 @@
+package task
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/utility"
+)
+
+// TimingBreakdown is the total time a group of tasks spent in each phase of
+// their lifecycle. It distinguishes slowness caused by a lack of capacity
+// (queue time) from slowness caused by the tasks themselves (run time).
+type TimingBreakdown struct {
+	// BlockedTime is the time tasks spent waiting for their dependencies to
+	// finish after they were scheduled.
+	BlockedTime time.Duration `bson:"blocked_time" json:"blocked_time"`
+	// QueueTime is the time tasks spent waiting to start once they were
+	// ready to run.
+	QueueTime time.Duration `bson:"queue_time" json:"queue_time"`
+	// RunTime is the time tasks spent running.
+	RunTime time.Duration `bson:"run_time" json:"run_time"`
+}
+
+// Add returns the sum of the two timing breakdowns.
+func (tb TimingBreakdown) Add(other TimingBreakdown) TimingBreakdown {
+	return TimingBreakdown{
+		BlockedTime: tb.BlockedTime + other.BlockedTime,
+		QueueTime:   tb.QueueTime + other.QueueTime,
+		RunTime:     tb.RunTime + other.RunTime,
+	}
+}
+
+// GetTimingBreakdown sums the time the given tasks spent blocked, queued, and
+// running. Display tasks are skipped since their execution tasks are counted
+// individually, and tasks that have not started do not contribute.
+func GetTimingBreakdown(tasks []Task) TimingBreakdown {
+	var tb TimingBreakdown
+	for _, t := range tasks {
+		tb = tb.Add(t.GetTimingBreakdown())
+	}
+	return tb
+}
+
+// GetTimingBreakdown returns the time the task spent blocked, queued, and
+// running.
+func (t *Task) GetTimingBreakdown() TimingBreakdown {
+	var tb TimingBreakdown
+	if t.DisplayOnly || utility.IsZeroTime(t.StartTime) {
+		return tb
+	}
+
+	readyTime := t.ScheduledTime
+	if utility.IsZeroTime(readyTime) {
+		readyTime = t.ActivatedTime
+	}
+	if t.DependenciesMetTime.After(readyTime) && !utility.IsZeroTime(readyTime) {
+		tb.BlockedTime = t.DependenciesMetTime.Sub(readyTime)
+		readyTime = t.DependenciesMetTime
+	}
+	if !utility.IsZeroTime(readyTime) && t.StartTime.After(readyTime) {
+		tb.QueueTime = t.StartTime.Sub(readyTime)
+	}
+	if t.FinishTime.After(t.StartTime) {
+		tb.RunTime = t.FinishTime.Sub(t.StartTime)
+	}
+	return tb
+}
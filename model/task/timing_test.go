@@ -0,0 +1,95 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTimingBreakdown(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	for tName, tCase := range map[string]struct {
+		tasks    []Task
+		expected TimingBreakdown
+	}{
+		"UnstartedTasksAreIgnored": {
+			tasks: []Task{
+				{ScheduledTime: start},
+			},
+		},
+		"DisplayTasksAreIgnored": {
+			tasks: []Task{
+				{
+					DisplayOnly:   true,
+					ScheduledTime: start,
+					StartTime:     start.Add(time.Minute),
+					FinishTime:    start.Add(2 * time.Minute),
+				},
+			},
+		},
+		"TaskWithoutDependencies": {
+			tasks: []Task{
+				{
+					ScheduledTime: start,
+					StartTime:     start.Add(time.Minute),
+					FinishTime:    start.Add(3 * time.Minute),
+				},
+			},
+			expected: TimingBreakdown{
+				QueueTime: time.Minute,
+				RunTime:   2 * time.Minute,
+			},
+		},
+		"TaskBlockedOnDependencies": {
+			tasks: []Task{
+				{
+					ScheduledTime:       start,
+					DependenciesMetTime: start.Add(5 * time.Minute),
+					StartTime:           start.Add(6 * time.Minute),
+					FinishTime:          start.Add(10 * time.Minute),
+				},
+			},
+			expected: TimingBreakdown{
+				BlockedTime: 5 * time.Minute,
+				QueueTime:   time.Minute,
+				RunTime:     4 * time.Minute,
+			},
+		},
+		"FallsBackToActivatedTime": {
+			tasks: []Task{
+				{
+					ActivatedTime: start,
+					StartTime:     start.Add(2 * time.Minute),
+				},
+			},
+			expected: TimingBreakdown{
+				QueueTime: 2 * time.Minute,
+			},
+		},
+		"SumsMultipleTasks": {
+			tasks: []Task{
+				{
+					ScheduledTime: start,
+					StartTime:     start.Add(time.Minute),
+					FinishTime:    start.Add(2 * time.Minute),
+				},
+				{
+					ScheduledTime:       start,
+					DependenciesMetTime: start.Add(2 * time.Minute),
+					StartTime:           start.Add(4 * time.Minute),
+					FinishTime:          start.Add(5 * time.Minute),
+				},
+			},
+			expected: TimingBreakdown{
+				BlockedTime: 2 * time.Minute,
+				QueueTime:   3 * time.Minute,
+				RunTime:     2 * time.Minute,
+			},
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			assert.Equal(t, tCase.expected, GetTimingBreakdown(tCase.tasks))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// spdxLicenseIDRegex matches a single SPDX license identifier, such as
+// "Apache-2.0" or "LicenseRef-Proprietary".
+var spdxLicenseIDRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.\-+]*$`)
+
+// ValidateTaskCompliance checks that a task's compliance metadata is
+// well-formed.
+func ValidateTaskCompliance(c *task.ComplianceMetadata) error {
+	if c == nil {
+		return nil
+	}
+	if c.LicenseScanRequired && !c.ProducesDistributable {
+		return errors.New("license scans can only be required for tasks that produce distributables")
+	}
+	if c.License != "" && !isValidSPDXExpression(c.License) {
+		return errors.Errorf("license '%s' is not a valid SPDX license expression", c.License)
+	}
+	return nil
+}
+
+// isValidSPDXExpression checks that the license is a simple SPDX license
+// expression, which is license identifiers joined by AND, OR, or WITH.
+func isValidSPDXExpression(license string) bool {
+	expr := strings.NewReplacer("(", " ", ")", " ").Replace(license)
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return false
+	}
+	expectOperand := true
+	for _, field := range fields {
+		if expectOperand {
+			if !spdxLicenseIDRegex.MatchString(field) {
+				return false
+			}
+		} else if field != "AND" && field != "OR" && field != "WITH" {
+			return false
+		}
+		expectOperand = !expectOperand
+	}
+	// The expression can't end with an operator.
+	return !expectOperand
+}
+
+// ComplianceReportTask is a task that produces distributables and the
+// artifacts that it attached.
+type ComplianceReportTask struct {
+	Task      task.Task
+	Artifacts []artifact.File
+}
+
+// GetVersionComplianceReport returns the version's tasks that produce
+// distributables along with the artifacts that their latest executions
+// attached.
+func GetVersionComplianceReport(versionID string) ([]ComplianceReportTask, error) {
+	tasks, err := task.FindWithFields(bson.M{
+		task.VersionKey: versionID,
+		bsonutil.GetDottedKeyName(task.ComplianceKey, task.ComplianceProducesDistributableKey): true,
+	}, task.IdKey, task.ExecutionKey, task.DisplayNameKey, task.BuildVariantKey, task.StatusKey, task.ActivatedKey, task.FinishTimeKey, task.ComplianceKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding distributable tasks for version '%s'", versionID)
+	}
+	if len(tasks) == 0 {
+		return []ComplianceReportTask{}, nil
+	}
+
+	taskExecutions := make([]artifact.TaskIDAndExecution, 0, len(tasks))
+	for _, t := range tasks {
+		taskExecutions = append(taskExecutions, artifact.TaskIDAndExecution{TaskID: t.Id, Execution: t.Execution})
+	}
+	entries, err := artifact.FindAll(artifact.ByTaskIdsAndExecutions(taskExecutions))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding artifacts for distributable tasks")
+	}
+	artifactsByTask := map[string][]artifact.File{}
+	for _, entry := range entries {
+		artifactsByTask[entry.TaskId] = append(artifactsByTask[entry.TaskId], entry.Files...)
+	}
+
+	report := make([]ComplianceReportTask, 0, len(tasks))
+	for _, t := range tasks {
+		report = append(report, ComplianceReportTask{
+			Task:      t,
+			Artifacts: artifactsByTask[t.Id],
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTaskCompliance(t *testing.T) {
+	assert.NoError(t, ValidateTaskCompliance(nil))
+	assert.NoError(t, ValidateTaskCompliance(&task.ComplianceMetadata{ProducesDistributable: true, LicenseScanRequired: true, License: "Apache-2.0"}))
+	assert.NoError(t, ValidateTaskCompliance(&task.ComplianceMetadata{License: "(MIT OR Apache-2.0) AND GPL-2.0-only WITH Classpath-exception-2.0"}))
+	assert.Error(t, ValidateTaskCompliance(&task.ComplianceMetadata{LicenseScanRequired: true}))
+	assert.Error(t, ValidateTaskCompliance(&task.ComplianceMetadata{License: "MIT OR"}))
+	assert.Error(t, ValidateTaskCompliance(&task.ComplianceMetadata{License: "MIT XOR Apache-2.0"}))
+	assert.Error(t, ValidateTaskCompliance(&task.ComplianceMetadata{License: "not/a license"}))
+}
+
+func TestGetVersionComplianceReport(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, artifact.Collection))
+	}()
+	require.NoError(t, db.ClearCollections(task.Collection, artifact.Collection))
+
+	tasks := []task.Task{
+		{Id: "dist", Version: "v1", DisplayName: "package", Execution: 1, Status: evergreen.TaskSucceeded, Compliance: &task.ComplianceMetadata{ProducesDistributable: true, License: "Apache-2.0"}},
+		{Id: "not_run", Version: "v1", DisplayName: "package_arm", Status: evergreen.TaskUndispatched, Compliance: &task.ComplianceMetadata{ProducesDistributable: true}},
+		{Id: "test", Version: "v1", DisplayName: "test", Status: evergreen.TaskSucceeded},
+		{Id: "other_version", Version: "v2", DisplayName: "package", Status: evergreen.TaskSucceeded, Compliance: &task.ComplianceMetadata{ProducesDistributable: true}},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+	}
+	entries := []artifact.Entry{
+		{TaskId: "dist", Execution: 1, Files: []artifact.File{{Name: "tarball", Link: "https://example.com/dist.tgz"}}},
+		{TaskId: "dist", Execution: 0, Files: []artifact.File{{Name: "old tarball", Link: "https://example.com/old.tgz"}}},
+	}
+	for _, entry := range entries {
+		require.NoError(t, entry.Upsert())
+	}
+
+	report, err := GetVersionComplianceReport("v1")
+	require.NoError(t, err)
+	require.Len(t, report, 2)
+	for _, reportTask := range report {
+		switch reportTask.Task.Id {
+		case "dist":
+			require.Len(t, reportTask.Artifacts, 1)
+			assert.Equal(t, "tarball", reportTask.Artifacts[0].Name)
+			assert.Equal(t, "Apache-2.0", reportTask.Task.Compliance.License)
+		case "not_run":
+			assert.Empty(t, reportTask.Artifacts)
+			assert.Equal(t, evergreen.TaskUndispatched, reportTask.Task.Status)
+		default:
+			assert.Fail(t, "unexpected task in report", reportTask.Task.Id)
+		}
+	}
+
+	report, err = GetVersionComplianceReport("nonexistent")
+	require.NoError(t, err)
+	assert.Empty(t, report)
+}
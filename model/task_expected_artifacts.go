@@ -0,0 +1,62 @@
+package model
+
+import (
+	"path"
+
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// ValidateExpectedArtifacts checks that the names of a task's expected
+// artifacts are valid glob patterns.
+func ValidateExpectedArtifacts(patterns []string) error {
+	catcher := grip.NewBasicCatcher()
+	for _, pattern := range patterns {
+		if pattern == "" {
+			catcher.New("expected artifact name cannot be empty")
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			catcher.Errorf("expected artifact '%s' is not a valid glob pattern", pattern)
+		}
+	}
+	return catcher.Resolve()
+}
+
+// FindMissingExpectedArtifacts returns the task's expected artifacts that
+// don't match the name of any artifact that this execution of the task
+// attached.
+func FindMissingExpectedArtifacts(t *task.Task) ([]string, error) {
+	if len(t.ExpectedArtifacts) == 0 {
+		return nil, nil
+	}
+	entries, err := artifact.FindAll(artifact.ByTaskIdAndExecution(t.Id, t.Execution))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding artifacts for task '%s'", t.Id)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		for _, file := range entry.Files {
+			names = append(names, file.Name)
+		}
+	}
+
+	var missing []string
+	for _, pattern := range t.ExpectedArtifacts {
+		if !matchesAnyArtifact(pattern, names) {
+			missing = append(missing, pattern)
+		}
+	}
+	return missing, nil
+}
+
+func matchesAnyArtifact(pattern string, names []string) bool {
+	for _, name := range names {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateExpectedArtifacts(t *testing.T) {
+	assert.NoError(t, ValidateExpectedArtifacts(nil))
+	assert.NoError(t, ValidateExpectedArtifacts([]string{"binaries", "*.tgz", "report-[0-9]"}))
+	assert.Error(t, ValidateExpectedArtifacts([]string{""}))
+	assert.Error(t, ValidateExpectedArtifacts([]string{"report-[0-9"}))
+}
+
+func TestFindMissingExpectedArtifacts(t *testing.T) {
+	require.NoError(t, db.ClearCollections(artifact.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(artifact.Collection))
+	}()
+
+	entries := []artifact.Entry{
+		{TaskId: "t", Execution: 1, Files: []artifact.File{{Name: "binaries"}, {Name: "dist.tgz"}}},
+		{TaskId: "t", Execution: 0, Files: []artifact.File{{Name: "coverage"}}},
+	}
+	for _, entry := range entries {
+		require.NoError(t, entry.Upsert())
+	}
+
+	t.Run("NoExpectedArtifacts", func(t *testing.T) {
+		missing, err := FindMissingExpectedArtifacts(&task.Task{Id: "t", Execution: 1})
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+	})
+	t.Run("MatchesNamesAndPatterns", func(t *testing.T) {
+		missing, err := FindMissingExpectedArtifacts(&task.Task{Id: "t", Execution: 1, ExpectedArtifacts: []string{"binaries", "*.tgz"}})
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+	})
+	t.Run("OnlyChecksCurrentExecution", func(t *testing.T) {
+		missing, err := FindMissingExpectedArtifacts(&task.Task{Id: "t", Execution: 1, ExpectedArtifacts: []string{"binaries", "coverage", "*.zip"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"coverage", "*.zip"}, missing)
+	})
+	t.Run("NoArtifactsAttached", func(t *testing.T) {
+		missing, err := FindMissingExpectedArtifacts(&task.Task{Id: "other", ExpectedArtifacts: []string{"binaries"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"binaries"}, missing)
+	})
+}
@@ -0,0 +1,207 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const TaskFailureLogCollection = "task_failure_logs"
+
+const (
+	// TaskFailureLogTailSize is how many of the last lines of a failed
+	// task's log are scanned for failure output.
+	TaskFailureLogTailSize = 500
+
+	maxTaskFailureLogLines      = 30
+	maxTaskFailureLogLineLength = 1024
+	maxTaskFailureLogBytes      = 16 * 1024
+
+	// DefaultTaskFailureLogSearchWindow is how far back failure log searches
+	// look when the caller does not say.
+	DefaultTaskFailureLogSearchWindow = 7 * 24 * time.Hour
+	// MaxTaskFailureLogSearchLimit is the most failure logs a search returns.
+	MaxTaskFailureLogSearchLimit = 500
+	// TaskFailureLogTTL is how long after a task finishes its failure log is
+	// kept, so searches cannot look further back than this.
+	TaskFailureLogTTL = 30 * 24 * time.Hour
+)
+
+// TaskFailureLog is the failure output of a failed task, kept so that
+// failures can be searched across a project without reading full task logs.
+type TaskFailureLog struct {
+	Id             string    `bson:"_id" json:"id"`
+	TaskID         string    `bson:"task_id" json:"task_id"`
+	Execution      int       `bson:"execution" json:"execution"`
+	Project        string    `bson:"project" json:"project"`
+	Version        string    `bson:"version" json:"version"`
+	Requester      string    `bson:"requester" json:"requester"`
+	BuildVariant   string    `bson:"build_variant" json:"build_variant"`
+	DisplayName    string    `bson:"display_name" json:"display_name"`
+	DistroID       string    `bson:"distro_id" json:"distro_id"`
+	FailureType    string    `bson:"failure_type" json:"failure_type"`
+	FailingCommand string    `bson:"failing_command" json:"failing_command"`
+	FinishTime     time.Time `bson:"finish_time" json:"finish_time"`
+	// Lines are the error lines from the end of the task's log, or its last
+	// lines if it did not log any errors.
+	Lines []string `bson:"lines" json:"lines"`
+}
+
+var (
+	taskFailureLogIdKey             = bsonutil.MustHaveTag(TaskFailureLog{}, "Id")
+	taskFailureLogProjectKey        = bsonutil.MustHaveTag(TaskFailureLog{}, "Project")
+	taskFailureLogRequesterKey      = bsonutil.MustHaveTag(TaskFailureLog{}, "Requester")
+	taskFailureLogFailingCommandKey = bsonutil.MustHaveTag(TaskFailureLog{}, "FailingCommand")
+	taskFailureLogFinishTimeKey     = bsonutil.MustHaveTag(TaskFailureLog{}, "FinishTime")
+	taskFailureLogLinesKey          = bsonutil.MustHaveTag(TaskFailureLog{}, "Lines")
+)
+
+// NewTaskFailureLog returns the failure log for the task built from the tail
+// of its log, which must be in chronological order.
+func NewTaskFailureLog(t *task.Task, tail []apimodels.LogMessage) *TaskFailureLog {
+	return &TaskFailureLog{
+		Id:             fmt.Sprintf("%s_%d", t.Id, t.Execution),
+		TaskID:         t.Id,
+		Execution:      t.Execution,
+		Project:        t.Project,
+		Version:        t.Version,
+		Requester:      t.Requester,
+		BuildVariant:   t.BuildVariant,
+		DisplayName:    t.DisplayName,
+		DistroID:       t.DistroId,
+		FailureType:    t.Details.Type,
+		FailingCommand: t.Details.Description,
+		FinishTime:     t.FinishTime,
+		Lines:          extractFailureLines(tail),
+	}
+}
+
+// extractFailureLines returns the last error lines of the log, falling back
+// to the last lines of the log if there are none. The result is bounded in
+// both number of lines and total size, keeping the lines closest to the end.
+func extractFailureLines(tail []apimodels.LogMessage) []string {
+	candidates := make([]string, 0, len(tail))
+	for _, msg := range tail {
+		if msg.Severity == apimodels.LogErrorPrefix {
+			candidates = append(candidates, msg.Message)
+		}
+	}
+	if len(candidates) == 0 {
+		for _, msg := range tail {
+			candidates = append(candidates, msg.Message)
+		}
+	}
+
+	var lines []string
+	size := 0
+	for i := len(candidates) - 1; i >= 0 && len(lines) < maxTaskFailureLogLines; i-- {
+		line := candidates[i]
+		if len(line) > maxTaskFailureLogLineLength {
+			line = line[:maxTaskFailureLogLineLength]
+		}
+		if line == "" {
+			continue
+		}
+		if size+len(line) > maxTaskFailureLogBytes {
+			break
+		}
+		size += len(line)
+		lines = append(lines, line)
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// Upsert saves the failure log, replacing any existing one for the same task
+// execution.
+func (l *TaskFailureLog) Upsert() error {
+	_, err := db.Upsert(TaskFailureLogCollection, bson.M{taskFailureLogIdKey: l.Id}, l)
+	return errors.Wrapf(err, "upserting failure log for task '%s'", l.TaskID)
+}
+
+// RemoveExpiredTaskFailureLogs deletes the failure logs of tasks that finished
+// before the given time.
+func RemoveExpiredTaskFailureLogs(before time.Time) error {
+	err := db.RemoveAll(TaskFailureLogCollection, bson.M{
+		taskFailureLogFinishTimeKey: bson.M{"$lt": before},
+	})
+	return errors.Wrap(err, "removing expired task failure logs")
+}
+
+// TaskFailureLogSearchOptions filter a search of a project's failure logs.
+type TaskFailureLogSearchOptions struct {
+	ProjectID string
+	// Pattern is a regular expression matched against the failure lines and
+	// the failing command.
+	Pattern    string
+	Since      time.Time
+	Requesters []string
+	Limit      int
+}
+
+// Validate checks that the search options are valid.
+func (opts *TaskFailureLogSearchOptions) Validate() error {
+	if opts.ProjectID == "" {
+		return errors.New("project ID must be specified")
+	}
+	if opts.Pattern == "" {
+		return errors.New("pattern must be specified")
+	}
+	if _, err := regexp.Compile(opts.Pattern); err != nil {
+		return errors.Wrapf(err, "invalid pattern '%s'", opts.Pattern)
+	}
+	for _, requester := range opts.Requesters {
+		if !utility.StringSliceContains(evergreen.AllRequesterTypes, requester) {
+			return errors.Errorf("invalid requester '%s'", requester)
+		}
+	}
+	if opts.Limit < 0 || opts.Limit > MaxTaskFailureLogSearchLimit {
+		return errors.Errorf("limit must be between 0 and %d", MaxTaskFailureLogSearchLimit)
+	}
+	return nil
+}
+
+// SearchTaskFailureLogs returns the project's most recent failure logs that
+// match the pattern, newest first.
+func SearchTaskFailureLogs(opts TaskFailureLogSearchOptions) ([]TaskFailureLog, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid search options")
+	}
+	if opts.Since.IsZero() {
+		opts.Since = time.Now().Add(-DefaultTaskFailureLogSearchWindow)
+	}
+	if opts.Limit == 0 {
+		opts.Limit = MaxTaskFailureLogSearchLimit
+	}
+
+	regex := bson.M{"$regex": opts.Pattern}
+	filter := bson.M{
+		taskFailureLogProjectKey:    opts.ProjectID,
+		taskFailureLogFinishTimeKey: bson.M{"$gte": opts.Since},
+		"$or": []bson.M{
+			{taskFailureLogLinesKey: regex},
+			{taskFailureLogFailingCommandKey: regex},
+		},
+	}
+	if len(opts.Requesters) > 0 {
+		filter[taskFailureLogRequesterKey] = bson.M{"$in": opts.Requesters}
+	}
+
+	logs := []TaskFailureLog{}
+	q := db.Query(filter).Sort([]string{"-" + taskFailureLogFinishTimeKey}).Limit(opts.Limit)
+	if err := db.FindAllQ(TaskFailureLogCollection, q, &logs); err != nil {
+		return nil, errors.Wrapf(err, "searching failure logs for project '%s'", opts.ProjectID)
+	}
+	return logs, nil
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractFailureLines(t *testing.T) {
+	t.Run("PrefersErrorLines", func(t *testing.T) {
+		lines := extractFailureLines([]apimodels.LogMessage{
+			{Severity: apimodels.LogInfoPrefix, Message: "running tests"},
+			{Severity: apimodels.LogErrorPrefix, Message: "connection refused"},
+			{Severity: apimodels.LogInfoPrefix, Message: "retrying"},
+			{Severity: apimodels.LogErrorPrefix, Message: "command failed"},
+		})
+		assert.Equal(t, []string{"connection refused", "command failed"}, lines)
+	})
+	t.Run("FallsBackToLastLines", func(t *testing.T) {
+		lines := extractFailureLines([]apimodels.LogMessage{
+			{Severity: apimodels.LogInfoPrefix, Message: "first"},
+			{Severity: apimodels.LogInfoPrefix, Message: ""},
+			{Severity: apimodels.LogInfoPrefix, Message: "last"},
+		})
+		assert.Equal(t, []string{"first", "last"}, lines)
+	})
+	t.Run("IsBounded", func(t *testing.T) {
+		tail := []apimodels.LogMessage{}
+		for i := 0; i < 2*maxTaskFailureLogLines; i++ {
+			tail = append(tail, apimodels.LogMessage{
+				Severity: apimodels.LogErrorPrefix,
+				Message:  strings.Repeat("x", 2*maxTaskFailureLogLineLength),
+			})
+		}
+		tail = append(tail, apimodels.LogMessage{Severity: apimodels.LogErrorPrefix, Message: "final error"})
+		lines := extractFailureLines(tail)
+		require.NotEmpty(t, lines)
+		assert.Equal(t, "final error", lines[len(lines)-1])
+		size := 0
+		for _, line := range lines {
+			assert.LessOrEqual(t, len(line), maxTaskFailureLogLineLength)
+			size += len(line)
+		}
+		assert.LessOrEqual(t, len(lines), maxTaskFailureLogLines)
+		assert.LessOrEqual(t, size, maxTaskFailureLogBytes)
+	})
+}
+
+func TestSearchTaskFailureLogs(t *testing.T) {
+	require.NoError(t, db.ClearCollections(TaskFailureLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(TaskFailureLogCollection))
+	}()
+
+	now := time.Now()
+	for _, tsk := range []task.Task{
+		{Id: "dns", Project: "p", Requester: evergreen.RepotrackerVersionRequester, FinishTime: now.Add(-time.Hour)},
+		{Id: "dns_patch", Project: "p", Requester: evergreen.PatchVersionRequester, FinishTime: now.Add(-time.Minute)},
+		{Id: "old_dns", Project: "p", Requester: evergreen.RepotrackerVersionRequester, FinishTime: now.Add(-2 * DefaultTaskFailureLogSearchWindow)},
+		{Id: "other_project", Project: "other", Requester: evergreen.RepotrackerVersionRequester, FinishTime: now},
+		{Id: "compile", Project: "p", Requester: evergreen.RepotrackerVersionRequester, FinishTime: now, Details: apimodels.TaskEndDetail{Description: "shell.exec"}},
+	} {
+		msg := "dial tcp: lookup s3.amazonaws.com: no such host"
+		if tsk.Id == "compile" {
+			msg = "undefined: foo"
+		}
+		failureLog := NewTaskFailureLog(&tsk, []apimodels.LogMessage{{Severity: apimodels.LogErrorPrefix, Message: msg}})
+		require.NoError(t, failureLog.Upsert())
+	}
+
+	t.Run("MatchesLinesNewestFirst", func(t *testing.T) {
+		logs, err := SearchTaskFailureLogs(TaskFailureLogSearchOptions{ProjectID: "p", Pattern: "no such host"})
+		require.NoError(t, err)
+		require.Len(t, logs, 2)
+		assert.Equal(t, "dns_patch", logs[0].TaskID)
+		assert.Equal(t, "dns", logs[1].TaskID)
+	})
+	t.Run("FiltersByRequesterAndLimit", func(t *testing.T) {
+		logs, err := SearchTaskFailureLogs(TaskFailureLogSearchOptions{
+			ProjectID:  "p",
+			Pattern:    "no such host",
+			Requesters: []string{evergreen.RepotrackerVersionRequester},
+			Since:      now.Add(-3 * DefaultTaskFailureLogSearchWindow),
+			Limit:      1,
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, "dns", logs[0].TaskID)
+	})
+	t.Run("MatchesFailingCommand", func(t *testing.T) {
+		logs, err := SearchTaskFailureLogs(TaskFailureLogSearchOptions{ProjectID: "p", Pattern: `^shell\.exec$`})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, "compile", logs[0].TaskID)
+	})
+	t.Run("RejectsInvalidOptions", func(t *testing.T) {
+		_, err := SearchTaskFailureLogs(TaskFailureLogSearchOptions{ProjectID: "p", Pattern: "("})
+		assert.Error(t, err)
+		_, err = SearchTaskFailureLogs(TaskFailureLogSearchOptions{ProjectID: "p"})
+		assert.Error(t, err)
+		_, err = SearchTaskFailureLogs(TaskFailureLogSearchOptions{ProjectID: "p", Pattern: "x", Requesters: []string{"nonsense"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestRemoveExpiredTaskFailureLogs(t *testing.T) {
+	require.NoError(t, db.Clear(TaskFailureLogCollection))
+	defer func() {
+		assert.NoError(t, db.Clear(TaskFailureLogCollection))
+	}()
+
+	now := time.Now()
+	require.NoError(t, (&TaskFailureLog{Id: "old", Project: "p", FinishTime: now.Add(-2 * TaskFailureLogTTL), Lines: []string{"error"}}).Upsert())
+	require.NoError(t, (&TaskFailureLog{Id: "new", Project: "p", FinishTime: now, Lines: []string{"error"}}).Upsert())
+
+	require.NoError(t, RemoveExpiredTaskFailureLogs(now.Add(-TaskFailureLogTTL)))
+
+	logs, err := SearchTaskFailureLogs(TaskFailureLogSearchOptions{ProjectID: "p", Pattern: "error", Since: now.Add(-3 * TaskFailureLogTTL)})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "new", logs[0].Id)
+}
@@ -0,0 +1,171 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DefaultMaxHostsRecommendationVersions is the number of recent mainline runs
+// of a task group that are analyzed when none is given.
+const DefaultMaxHostsRecommendationVersions = 20
+
+// TaskGroupMaxHostsRecommendation is a suggested max_hosts for a task group
+// based on how the group ran in recent mainline versions.
+type TaskGroupMaxHostsRecommendation struct {
+	TaskGroup string
+	// BuildVariant is the variant that was analyzed. If it is empty, runs
+	// of the group in every variant were analyzed.
+	BuildVariant        string
+	NumTasks            int
+	CurrentMaxHosts     int
+	RecommendedMaxHosts int
+	// NumRunsSampled is the number of runs of the whole group, across
+	// versions and variants, that the recommendation is based on.
+	NumRunsSampled int
+	// AvgTotalDuration is the average time it took to run every task in the
+	// group, one after another.
+	AvgTotalDuration time.Duration
+	// AvgLongestTaskDuration is the average duration of the longest task in
+	// each run of the group.
+	AvgLongestTaskDuration time.Duration
+	// AvgQueueWait is the average time between a task being ready to run and
+	// starting.
+	AvgQueueWait time.Duration
+	Reasons      []string
+}
+
+// RecommendTaskGroupMaxHosts analyzes the runtimes and queue waits of a task
+// group's tasks in the project's recent mainline versions and recommends a
+// max_hosts for it. The group cannot finish faster than its longest task, so
+// the recommendation is the fewest hosts that can run all of the group's work
+// in about the time its longest task takes.
+func RecommendTaskGroupMaxHosts(project *Project, projectID, variant, taskGroupName string, numVersions int) (*TaskGroupMaxHostsRecommendation, error) {
+	tg := project.FindTaskGroup(taskGroupName)
+	if tg == nil {
+		return nil, errors.Errorf("task group '%s' not found in project config", taskGroupName)
+	}
+	if numVersions <= 0 {
+		numVersions = DefaultMaxHostsRecommendationVersions
+	}
+	rec := &TaskGroupMaxHostsRecommendation{
+		TaskGroup:       tg.Name,
+		BuildVariant:    variant,
+		NumTasks:        len(tg.Tasks),
+		CurrentMaxHosts: tg.MaxHosts,
+	}
+	if rec.NumTasks == 0 {
+		rec.RecommendedMaxHosts = 1
+		rec.Reasons = append(rec.Reasons, "the task group has no tasks")
+		return rec, nil
+	}
+
+	filter := bson.M{
+		task.ProjectKey:   projectID,
+		task.TaskGroupKey: tg.Name,
+		task.RequesterKey: evergreen.RepotrackerVersionRequester,
+		task.StatusKey:    bson.M{"$in": evergreen.TaskCompletedStatuses},
+	}
+	if variant != "" {
+		filter[task.BuildVariantKey] = variant
+	}
+	tasks, err := task.FindAll(db.Query(filter).
+		WithFields(task.VersionKey, task.BuildVariantKey, task.ScheduledTimeKey, task.DependenciesMetTimeKey, task.StartTimeKey, task.FinishTimeKey).
+		Sort([]string{"-" + task.RevisionOrderNumberKey}).
+		Limit(numVersions * rec.NumTasks))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding recent runs of task group '%s'", tg.Name)
+	}
+
+	type groupRun struct {
+		total   time.Duration
+		longest time.Duration
+	}
+	runs := map[string]*groupRun{}
+	var totalWait time.Duration
+	numWaits := 0
+	for _, t := range tasks {
+		if utility.IsZeroTime(t.StartTime) || t.FinishTime.Before(t.StartTime) {
+			continue
+		}
+		key := t.Version + "/" + t.BuildVariant
+		run, ok := runs[key]
+		if !ok {
+			run = &groupRun{}
+			runs[key] = run
+		}
+		duration := t.FinishTime.Sub(t.StartTime)
+		run.total += duration
+		if duration > run.longest {
+			run.longest = duration
+		}
+
+		readyTime := t.ScheduledTime
+		if t.DependenciesMetTime.After(readyTime) {
+			readyTime = t.DependenciesMetTime
+		}
+		if !utility.IsZeroTime(readyTime) && t.StartTime.After(readyTime) {
+			totalWait += t.StartTime.Sub(readyTime)
+			numWaits++
+		}
+	}
+
+	if rec.CurrentMaxHosts > rec.NumTasks {
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("max_hosts (%d) is greater than the number of tasks in the group (%d), so some hosts can never be used",
+			rec.CurrentMaxHosts, rec.NumTasks))
+	}
+
+	rec.NumRunsSampled = len(runs)
+	if rec.NumRunsSampled == 0 {
+		rec.RecommendedMaxHosts = clampMaxHosts(rec.CurrentMaxHosts, rec.NumTasks)
+		rec.Reasons = append(rec.Reasons, "the task group has no finished mainline runs to analyze")
+		return rec, nil
+	}
+
+	var sumTotal, sumLongest time.Duration
+	for _, run := range runs {
+		sumTotal += run.total
+		sumLongest += run.longest
+	}
+	rec.AvgTotalDuration = sumTotal / time.Duration(rec.NumRunsSampled)
+	rec.AvgLongestTaskDuration = sumLongest / time.Duration(rec.NumRunsSampled)
+	if numWaits > 0 {
+		rec.AvgQueueWait = totalWait / time.Duration(numWaits)
+	}
+
+	recommended := 1
+	if rec.AvgLongestTaskDuration > 0 {
+		recommended = int(math.Ceil(float64(rec.AvgTotalDuration) / float64(rec.AvgLongestTaskDuration)))
+	}
+	rec.RecommendedMaxHosts = clampMaxHosts(recommended, rec.NumTasks)
+
+	switch {
+	case rec.RecommendedMaxHosts < rec.CurrentMaxHosts:
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("the longest task takes %s on average, so more than %d hosts cannot finish the group's %s of work sooner",
+			rec.AvgLongestTaskDuration, rec.RecommendedMaxHosts, rec.AvgTotalDuration))
+	case rec.RecommendedMaxHosts > rec.CurrentMaxHosts:
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("tasks waited %s on average to start; %d hosts could run the group's %s of work in about the %s its longest task takes",
+			rec.AvgQueueWait, rec.RecommendedMaxHosts, rec.AvgTotalDuration, rec.AvgLongestTaskDuration))
+	default:
+		rec.Reasons = append(rec.Reasons, "the current max_hosts matches the group's recent runtimes")
+	}
+
+	return rec, nil
+}
+
+func clampMaxHosts(maxHosts, numTasks int) int {
+	if maxHosts < 1 {
+		return 1
+	}
+	if maxHosts > numTasks {
+		return numTasks
+	}
+	return maxHosts
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendTaskGroupMaxHosts(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection))
+	}()
+
+	project := &Project{
+		Identifier: "p1",
+		TaskGroups: []TaskGroup{
+			{Name: "tg", MaxHosts: 1, Tasks: []string{"t1", "t2", "t3", "t4"}},
+			{Name: "oversized", MaxHosts: 5, Tasks: []string{"t1", "t2"}},
+		},
+	}
+
+	_, err := RecommendTaskGroupMaxHosts(project, "p1", "", "nonexistent", 0)
+	assert.Error(t, err)
+
+	t.Run("NoHistory", func(t *testing.T) {
+		rec, err := RecommendTaskGroupMaxHosts(project, "p1", "", "oversized", 0)
+		require.NoError(t, err)
+		assert.Equal(t, 5, rec.CurrentMaxHosts)
+		assert.Equal(t, 2, rec.RecommendedMaxHosts)
+		assert.Zero(t, rec.NumRunsSampled)
+		assert.Len(t, rec.Reasons, 2)
+	})
+
+	start := time.Now().Add(-time.Hour)
+	// Each version runs four 10 minute tasks that waited 5 minutes to start,
+	// so two hosts can finish the group about as fast as more hosts could.
+	for i, version := range []string{"v1", "v2"} {
+		for _, name := range []string{"t1", "t2", "t3", "t4"} {
+			tsk := task.Task{
+				Id:                  version + name,
+				Version:             version,
+				Project:             "p1",
+				BuildVariant:        "bv",
+				DisplayName:         name,
+				TaskGroup:           "tg",
+				Requester:           evergreen.RepotrackerVersionRequester,
+				Status:              evergreen.TaskSucceeded,
+				RevisionOrderNumber: i,
+				ScheduledTime:       start,
+				StartTime:           start.Add(5 * time.Minute),
+				FinishTime:          start.Add(15 * time.Minute),
+			}
+			if name == "t4" {
+				tsk.FinishTime = start.Add(25 * time.Minute)
+			}
+			require.NoError(t, tsk.Insert())
+		}
+	}
+	patchTask := task.Task{
+		Id:         "patch",
+		Version:    "patch",
+		Project:    "p1",
+		TaskGroup:  "tg",
+		Requester:  evergreen.PatchVersionRequester,
+		Status:     evergreen.TaskSucceeded,
+		StartTime:  start,
+		FinishTime: start.Add(10 * time.Hour),
+	}
+	require.NoError(t, patchTask.Insert())
+
+	t.Run("RecommendsMoreHosts", func(t *testing.T) {
+		rec, err := RecommendTaskGroupMaxHosts(project, "p1", "", "tg", 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, rec.NumRunsSampled)
+		assert.Equal(t, 50*time.Minute, rec.AvgTotalDuration)
+		assert.Equal(t, 20*time.Minute, rec.AvgLongestTaskDuration)
+		assert.Equal(t, 5*time.Minute, rec.AvgQueueWait)
+		assert.Equal(t, 3, rec.RecommendedMaxHosts)
+		require.Len(t, rec.Reasons, 1)
+		assert.Contains(t, rec.Reasons[0], "waited")
+	})
+	t.Run("FiltersByVariant", func(t *testing.T) {
+		rec, err := RecommendTaskGroupMaxHosts(project, "p1", "other", "tg", 0)
+		require.NoError(t, err)
+		assert.Zero(t, rec.NumRunsSampled)
+		assert.Equal(t, 1, rec.RecommendedMaxHosts)
+	})
+	t.Run("RecommendsFewerHosts", func(t *testing.T) {
+		project.TaskGroups[0].MaxHosts = 4
+		defer func() {
+			project.TaskGroups[0].MaxHosts = 1
+		}()
+		rec, err := RecommendTaskGroupMaxHosts(project, "p1", "bv", "tg", 0)
+		require.NoError(t, err)
+		assert.Equal(t, 3, rec.RecommendedMaxHosts)
+		require.Len(t, rec.Reasons, 1)
+		assert.Contains(t, rec.Reasons[0], "longest task")
+	})
+}
@@ -0,0 +1,292 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// DefaultTaskLatencyWindow is how far back to look at finished tasks
+	// when summarizing task latency.
+	DefaultTaskLatencyWindow = 24 * time.Hour
+	// maxTaskLatencyStatsTasks is the most recently finished tasks that
+	// are summarized, to bound the cost of looking up their events.
+	maxTaskLatencyStatsTasks = 5000
+)
+
+// TaskLatency is when a task execution reached each stage from being queued
+// to finishing, and how long it spent between the stages. A stage that the
+// execution has not reached, or that predates the events that track it, is
+// unset, as are the latencies that depend on it.
+type TaskLatency struct {
+	TaskID    string `json:"task_id"`
+	Execution int    `json:"execution"`
+	DistroID  string `json:"distro_id"`
+	Project   string `json:"project"`
+
+	EnqueuedAt      time.Time `json:"enqueued_at"`
+	DispatchedAt    time.Time `json:"dispatched_at"`
+	AgentAcceptedAt time.Time `json:"agent_accepted_at"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+
+	// SchedulingLatency is how long the task waited in the queue to be
+	// dispatched.
+	SchedulingLatency time.Duration `json:"scheduling_latency"`
+	// AgentAcceptLatency is how long the agent took to accept the task once
+	// it was dispatched.
+	AgentAcceptLatency time.Duration `json:"agent_accept_latency"`
+	// AgentStartupLatency is how long the agent took to start the task once
+	// it was dispatched, including accepting it and setting it up.
+	AgentStartupLatency time.Duration `json:"agent_startup_latency"`
+	RunTime             time.Duration `json:"run_time"`
+}
+
+// LatencyPercentiles summarizes the distribution of one latency across tasks.
+type LatencyPercentiles struct {
+	// Count is the number of tasks that the latency is known for.
+	Count int           `json:"count"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+// TaskLatencyStats summarizes the latencies of the tasks that recently
+// finished in a distro or project.
+type TaskLatencyStats struct {
+	DistroID string `json:"distro_id,omitempty"`
+	Project  string `json:"project,omitempty"`
+	// Since is the start of the window of finished tasks that are counted.
+	Since    time.Time `json:"since"`
+	NumTasks int       `json:"num_tasks"`
+
+	SchedulingLatency   LatencyPercentiles `json:"scheduling_latency"`
+	AgentAcceptLatency  LatencyPercentiles `json:"agent_accept_latency"`
+	AgentStartupLatency LatencyPercentiles `json:"agent_startup_latency"`
+	RunTime             LatencyPercentiles `json:"run_time"`
+}
+
+// TaskLatencyStatsOptions are the options for summarizing task latency. Only
+// one of the distro or project may be set.
+type TaskLatencyStatsOptions struct {
+	DistroID string
+	Project  string
+	Window   time.Duration
+}
+
+// GetTaskLatency returns the latency breakdown of the task execution.
+func GetTaskLatency(taskID string, execution int) (*TaskLatency, error) {
+	t, err := task.FindOneIdAndExecution(taskID, execution)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding task '%s' execution %d", taskID, execution)
+	}
+	if t == nil {
+		return nil, nil
+	}
+	latencies, err := getTaskLatencies([]task.Task{*t})
+	if err != nil {
+		return nil, err
+	}
+	return &latencies[0], nil
+}
+
+// GetTaskLatencyStats summarizes the latencies of the most recent tasks that
+// finished in the distro or project within the window.
+func GetTaskLatencyStats(opts TaskLatencyStatsOptions) (*TaskLatencyStats, error) {
+	if (opts.DistroID == "") == (opts.Project == "") {
+		return nil, errors.New("must specify exactly one of a distro or project")
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultTaskLatencyWindow
+	}
+	since := time.Now().Add(-opts.Window)
+	filter := bson.M{
+		task.FinishTimeKey:  bson.M{"$gte": since},
+		task.StatusKey:      bson.M{"$in": evergreen.TaskCompletedStatuses},
+		task.DisplayOnlyKey: bson.M{"$ne": true},
+	}
+	if opts.DistroID != "" {
+		filter[task.DistroIdKey] = opts.DistroID
+	} else {
+		filter[task.ProjectKey] = opts.Project
+	}
+	q := db.Query(filter).
+		WithFields(task.IdKey, task.OldTaskIdKey, task.ExecutionKey, task.DistroIdKey, task.ProjectKey,
+			task.ScheduledTimeKey, task.DispatchTimeKey, task.StartTimeKey, task.FinishTimeKey).
+		Sort([]string{"-" + task.FinishTimeKey}).
+		Limit(maxTaskLatencyStatsTasks)
+	tasks, err := task.FindAll(q)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding recently finished tasks")
+	}
+	// Earlier executions of restarted tasks are archived.
+	oldTasks, err := task.FindAllOld(q)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding recently finished archived tasks")
+	}
+	tasks = append(tasks, oldTasks...)
+
+	latencies, err := getTaskLatencies(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &TaskLatencyStats{
+		DistroID: opts.DistroID,
+		Project:  opts.Project,
+		Since:    since,
+		NumTasks: len(latencies),
+	}
+	var scheduling, accept, startup, run []time.Duration
+	for _, l := range latencies {
+		scheduling = appendKnownLatency(scheduling, l.SchedulingLatency)
+		accept = appendKnownLatency(accept, l.AgentAcceptLatency)
+		startup = appendKnownLatency(startup, l.AgentStartupLatency)
+		run = appendKnownLatency(run, l.RunTime)
+	}
+	stats.SchedulingLatency = newLatencyPercentiles(scheduling)
+	stats.AgentAcceptLatency = newLatencyPercentiles(accept)
+	stats.AgentStartupLatency = newLatencyPercentiles(startup)
+	stats.RunTime = newLatencyPercentiles(run)
+
+	return stats, nil
+}
+
+// getTaskLatencies returns the latency breakdown of each of the task
+// executions from their events. Stages without events fall back to the times
+// recorded on the task.
+func getTaskLatencies(tasks []task.Task) ([]TaskLatency, error) {
+	latencies := make([]TaskLatency, 0, len(tasks))
+	byExecution := map[string]map[int]*TaskLatency{}
+	ids := []string{}
+	for _, t := range tasks {
+		id := t.Id
+		if t.OldTaskId != "" {
+			id = t.OldTaskId
+		}
+		latencies = append(latencies, TaskLatency{
+			TaskID:    id,
+			Execution: t.Execution,
+			DistroID:  t.DistroId,
+			Project:   t.Project,
+		})
+		if _, ok := byExecution[id]; !ok {
+			byExecution[id] = map[int]*TaskLatency{}
+			ids = append(ids, id)
+		}
+	}
+	for i := range latencies {
+		byExecution[latencies[i].TaskID][latencies[i].Execution] = &latencies[i]
+	}
+	if len(ids) == 0 {
+		return latencies, nil
+	}
+
+	events, err := event.Find(event.AllLogCollection, event.TaskLatencyEventsInOrder(ids))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding task latency events")
+	}
+	for _, e := range events {
+		data, ok := e.Data.(*event.TaskEventData)
+		if !ok {
+			continue
+		}
+		l := byExecution[e.ResourceId][data.Execution]
+		if l == nil {
+			continue
+		}
+		switch e.EventType {
+		case event.TaskEnqueued, event.ContainerAllocated:
+			// The task may be requeued if it's undispatched, but it's been
+			// waiting since it was first queued.
+			if utility.IsZeroTime(l.EnqueuedAt) {
+				l.EnqueuedAt = e.Timestamp
+			}
+		case event.TaskDispatched:
+			// A task that's undispatched is dispatched again, so only the
+			// last dispatch counts.
+			l.DispatchedAt = e.Timestamp
+		case event.TaskAgentAccepted:
+			l.AgentAcceptedAt = e.Timestamp
+		case event.TaskStarted:
+			if utility.IsZeroTime(l.StartedAt) {
+				l.StartedAt = e.Timestamp
+			}
+		case event.TaskFinished:
+			if utility.IsZeroTime(l.FinishedAt) {
+				l.FinishedAt = e.Timestamp
+			}
+		}
+	}
+
+	for i, t := range tasks {
+		l := &latencies[i]
+		if utility.IsZeroTime(l.EnqueuedAt) {
+			l.EnqueuedAt = t.ScheduledTime
+		}
+		if utility.IsZeroTime(l.DispatchedAt) {
+			l.DispatchedAt = t.DispatchTime
+		}
+		if utility.IsZeroTime(l.StartedAt) {
+			l.StartedAt = t.StartTime
+		}
+		if utility.IsZeroTime(l.FinishedAt) {
+			l.FinishedAt = t.FinishTime
+		}
+		l.SchedulingLatency = latencyBetween(l.EnqueuedAt, l.DispatchedAt)
+		l.AgentAcceptLatency = latencyBetween(l.DispatchedAt, l.AgentAcceptedAt)
+		l.AgentStartupLatency = latencyBetween(l.DispatchedAt, l.StartedAt)
+		l.RunTime = latencyBetween(l.StartedAt, l.FinishedAt)
+	}
+
+	return latencies, nil
+}
+
+// latencyBetween returns the time between the two stages, or zero if either
+// stage is unknown.
+func latencyBetween(start, end time.Time) time.Duration {
+	if utility.IsZeroTime(start) || utility.IsZeroTime(end) || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+func appendKnownLatency(latencies []time.Duration, latency time.Duration) []time.Duration {
+	if latency <= 0 {
+		return latencies
+	}
+	return append(latencies, latency)
+}
+
+func newLatencyPercentiles(latencies []time.Duration) LatencyPercentiles {
+	if len(latencies) == 0 {
+		return LatencyPercentiles{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return LatencyPercentiles{
+		Count: len(latencies),
+		P50:   latencyPercentile(latencies, 50),
+		P90:   latencyPercentile(latencies, 90),
+		P99:   latencyPercentile(latencies, 99),
+		Max:   latencies[len(latencies)-1],
+	}
+}
+
+// latencyPercentile returns the nearest-rank percentile of the sorted
+// latencies.
+func latencyPercentile(sorted []time.Duration, percentile int) time.Duration {
+	rank := (percentile*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLatency(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, task.OldCollection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, task.OldCollection, event.AllLogCollection))
+	}()
+
+	now := time.Now().Round(time.Millisecond)
+	logAt := func(taskID, eventType string, execution int, ts time.Time) {
+		e := event.EventLogEntry{
+			Timestamp:    ts,
+			ResourceId:   taskID,
+			ResourceType: event.ResourceTypeTask,
+			EventType:    eventType,
+			Data:         event.TaskEventData{Execution: execution},
+		}
+		require.NoError(t, event.NewDBEventLogger(event.AllLogCollection).LogEvent(&e))
+	}
+
+	// t0 has events for every stage and was dispatched twice.
+	t0 := task.Task{Id: "t0", Execution: 1, DistroId: "d", Project: "p", Status: evergreen.TaskSucceeded, FinishTime: now}
+	require.NoError(t, t0.Insert())
+	logAt("t0", event.TaskEnqueued, 1, now.Add(-20*time.Minute))
+	logAt("t0", event.TaskDispatched, 1, now.Add(-18*time.Minute))
+	logAt("t0", event.TaskEnqueued, 1, now.Add(-17*time.Minute))
+	logAt("t0", event.TaskDispatched, 1, now.Add(-15*time.Minute))
+	logAt("t0", event.TaskAgentAccepted, 1, now.Add(-14*time.Minute))
+	logAt("t0", event.TaskStarted, 1, now.Add(-10*time.Minute))
+	logAt("t0", event.TaskFinished, 1, now)
+	// Events from the earlier execution are not counted.
+	logAt("t0", event.TaskEnqueued, 0, now.Add(-time.Hour))
+
+	// t1 predates the events, so its times come from the task.
+	t1 := task.Task{
+		Id:            "t1",
+		DistroId:      "d",
+		Project:       "other",
+		Status:        evergreen.TaskFailed,
+		ScheduledTime: now.Add(-4 * time.Minute),
+		DispatchTime:  now.Add(-3 * time.Minute),
+		StartTime:     now.Add(-2 * time.Minute),
+		FinishTime:    now.Add(-time.Minute),
+	}
+	require.NoError(t, t1.Insert())
+
+	t.Run("GetTaskLatency", func(t *testing.T) {
+		latency, err := GetTaskLatency("t0", 1)
+		require.NoError(t, err)
+		require.NotNil(t, latency)
+		assert.True(t, now.Add(-20*time.Minute).Equal(latency.EnqueuedAt))
+		assert.True(t, now.Add(-15*time.Minute).Equal(latency.DispatchedAt))
+		assert.Equal(t, 5*time.Minute, latency.SchedulingLatency)
+		assert.Equal(t, time.Minute, latency.AgentAcceptLatency)
+		assert.Equal(t, 5*time.Minute, latency.AgentStartupLatency)
+		assert.Equal(t, 10*time.Minute, latency.RunTime)
+
+		latency, err = GetTaskLatency("t1", 0)
+		require.NoError(t, err)
+		require.NotNil(t, latency)
+		assert.Equal(t, time.Minute, latency.SchedulingLatency)
+		assert.Zero(t, latency.AgentAcceptLatency)
+		assert.Equal(t, time.Minute, latency.AgentStartupLatency)
+		assert.Equal(t, time.Minute, latency.RunTime)
+
+		latency, err = GetTaskLatency("nonexistent", 0)
+		assert.NoError(t, err)
+		assert.Nil(t, latency)
+	})
+	t.Run("GetTaskLatencyStatsForDistro", func(t *testing.T) {
+		stats, err := GetTaskLatencyStats(TaskLatencyStatsOptions{DistroID: "d", Window: time.Hour})
+		require.NoError(t, err)
+		assert.Equal(t, 2, stats.NumTasks)
+		assert.Equal(t, 2, stats.SchedulingLatency.Count)
+		assert.Equal(t, time.Minute, stats.SchedulingLatency.P50)
+		assert.Equal(t, 5*time.Minute, stats.SchedulingLatency.P99)
+		assert.Equal(t, 5*time.Minute, stats.SchedulingLatency.Max)
+		assert.Equal(t, 1, stats.AgentAcceptLatency.Count)
+	})
+	t.Run("GetTaskLatencyStatsForProject", func(t *testing.T) {
+		stats, err := GetTaskLatencyStats(TaskLatencyStatsOptions{Project: "p"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.NumTasks)
+		assert.Equal(t, 10*time.Minute, stats.RunTime.P50)
+	})
+	t.Run("RequiresExactlyOneOfDistroOrProject", func(t *testing.T) {
+		_, err := GetTaskLatencyStats(TaskLatencyStatsOptions{})
+		assert.Error(t, err)
+		_, err = GetTaskLatencyStats(TaskLatencyStatsOptions{DistroID: "d", Project: "p"})
+		assert.Error(t, err)
+	})
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	latencies := []time.Duration{}
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Second)
+	}
+	percentiles := newLatencyPercentiles(latencies)
+	assert.Equal(t, 100, percentiles.Count)
+	assert.Equal(t, 50*time.Second, percentiles.P50)
+	assert.Equal(t, 90*time.Second, percentiles.P90)
+	assert.Equal(t, 99*time.Second, percentiles.P99)
+	assert.Equal(t, 100*time.Second, percentiles.Max)
+
+	assert.Zero(t, newLatencyPercentiles(nil))
+}
@@ -31,7 +31,20 @@ type StatusChanges struct {
 	BuildComplete    bool
 }
 
+// SetActiveState activates or deactivates the tasks, along with the tasks
+// that they depend on when activating and the tasks that depend on them when
+// deactivating.
 func SetActiveState(caller string, active bool, tasks ...task.Task) error {
+	_, err := SetTasksActiveState(caller, active, false, tasks)
+	return err
+}
+
+// SetTasksActiveState is SetActiveState for many tasks that also returns the
+// tasks, builds, and versions whose activation changes. If dryRun is true,
+// nothing is written. Commit queue items that deactivating a merge task would
+// dequeue are not included.
+func SetTasksActiveState(caller string, active, dryRun bool, tasks []task.Task) (AffectedEntities, error) {
+	affected := AffectedEntities{}
 	tasksToActivate := []task.Task{}
 	versionIdsSet := map[string]bool{}
 	buildToTaskMap := map[string]task.Task{}
@@ -55,7 +68,10 @@ func SetActiveState(caller string, active bool, tasks ...task.Task) error {
 					for _, dep := range deps {
 						// reset any already finished tasks in the same task group
 						if dep.TaskGroup == t.TaskGroup && t.TaskGroup != "" && dep.IsFinished() {
-							catcher.Wrapf(resetTask(dep.Id, caller, false), "resetting dependency '%s'", dep.Id)
+							affected.AddTasks(dep)
+							if !dryRun {
+								catcher.Wrapf(resetTask(dep.Id, caller, false), "resetting dependency '%s'", dep.Id)
+							}
 						} else {
 							tasksToActivate = append(tasksToActivate, dep)
 						}
@@ -67,7 +83,10 @@ func SetActiveState(caller string, active bool, tasks ...task.Task) error {
 
 			// Investigating strange dispatch state as part of EVG-13144
 			if t.IsHostTask() && !utility.IsZeroTime(t.DispatchTime) && t.Status == evergreen.TaskUndispatched {
-				catcher.Wrapf(resetTask(t.Id, caller, false), "resetting task '%s'", t.Id)
+				affected.AddTasks(t)
+				if !dryRun {
+					catcher.Wrapf(resetTask(t.Id, caller, false), "resetting task '%s'", t.Id)
+				}
 			} else {
 				tasksToActivate = append(tasksToActivate, originalTasks...)
 			}
@@ -85,43 +104,76 @@ func SetActiveState(caller string, active bool, tasks ...task.Task) error {
 					}
 				}
 			}
-			if t.Requester == evergreen.MergeTestRequester {
+			if t.Requester == evergreen.MergeTestRequester && !dryRun {
 				catcher.Wrapf(DequeueAndRestartForTask(nil, &t, message.GithubStateError, caller, fmt.Sprintf("deactivated by '%s'", caller)), "dequeueing and restarting task '%s'", t.Id)
 			}
 			tasksToActivate = append(tasksToActivate, originalTasks...)
 		} else {
 			continue
 		}
-		if t.IsPartOfDisplay() {
+		if t.IsPartOfDisplay() && !dryRun {
 			catcher.Wrap(UpdateDisplayTaskForTask(&t), "updating display task")
 		}
 	}
 
+	affected.AddTasks(tasksToActivate...)
+	taskIDs := make([]string, 0, len(tasksToActivate))
+	for _, t := range tasksToActivate {
+		taskIDs = append(taskIDs, t.Id)
+	}
+	var dependents []task.Task
+	var err error
 	if active {
+		dependents, err = task.FindDeactivatedDependenciesToActivate(taskIDs)
+	} else {
+		dependents, err = task.FindDependenciesToDeactivate(taskIDs)
+	}
+	if err != nil {
+		return affected, errors.Wrap(err, "finding dependent tasks")
+	}
+	affected.AddTasks(dependents...)
+	affected.sortIDs()
+	if dryRun {
+		return affected, catcher.Resolve()
+	}
+
+	var denied error
+	if active {
+		// Tasks in builds whose activation hooks deny activating them are
+		// left deactivated.
+		tasksToActivate, denied, err = filterTasksByActivationHooks(tasksToActivate, caller)
+		if err != nil {
+			return affected, errors.Wrap(err, "checking activation hooks")
+		}
 		if err := task.ActivateTasks(tasksToActivate, time.Now(), true, caller); err != nil {
-			return errors.Wrap(err, "activating tasks")
+			return affected, errors.Wrap(err, "activating tasks")
 		}
 		versionIdsToActivate := []string{}
-		for v := range versionIdsSet {
-			versionIdsToActivate = append(versionIdsToActivate, v)
+		for _, t := range tasksToActivate {
+			if versionIdsSet[t.Version] && !utility.StringSliceContains(versionIdsToActivate, t.Version) {
+				versionIdsToActivate = append(versionIdsToActivate, t.Version)
+			}
 		}
 		if err := ActivateVersions(versionIdsToActivate); err != nil {
-			return errors.Wrap(err, "marking version as activated")
+			return affected, errors.Wrap(err, "marking version as activated")
 		}
 	} else {
 		if err := task.DeactivateTasks(tasksToActivate, true, caller); err != nil {
-			return errors.Wrap(err, "deactivating task")
+			return affected, errors.Wrap(err, "deactivating task")
 		}
 	}
 
 	for b, item := range buildToTaskMap {
 		t := buildToTaskMap[b]
 		if err := UpdateBuildAndVersionStatusForTask(&item); err != nil {
-			return errors.Wrapf(err, "updating build and version status for task '%s'", t.Id)
+			return affected, errors.Wrapf(err, "updating build and version status for task '%s'", t.Id)
 		}
 	}
 
-	return catcher.Resolve()
+	if catcher.HasErrors() {
+		return affected, catcher.Resolve()
+	}
+	return affected, denied
 }
 
 func SetActiveStateById(id, user string, active bool) error {
@@ -333,6 +385,70 @@ func AbortTask(taskId, caller string) error {
 	return t.SetAborted(task.AbortInfo{User: caller})
 }
 
+// AbortTaskCommand requests that the agent abort the command that the task is
+// currently running and continue running the rest of the task.
+func AbortTaskCommand(taskId, caller string) error {
+	t, err := task.FindOneId(taskId)
+	if err != nil {
+		return errors.Wrapf(err, "finding task '%s'", taskId)
+	}
+	if t == nil {
+		return errors.Errorf("task '%s' not found", taskId)
+	}
+	if t.DisplayOnly {
+		return errors.Errorf("cannot abort a command in display task '%s'", t.Id)
+	}
+	if t.Aborted {
+		return errors.Errorf("task '%s' is already being aborted", t.Id)
+	}
+	if err = t.SetCommandAbortRequested(task.AbortInfo{User: caller}); err != nil {
+		return err
+	}
+	event.LogTaskCommandAbortRequest(t.Id, t.Execution, caller)
+	return nil
+}
+
+// SkipTask marks an undispatched task as intentionally not run with the given
+// reason. Tasks that depend on the skipped task succeeding are blocked. For a
+// display task, its undispatched execution tasks are skipped instead.
+func SkipTask(taskId, reason, caller string) error {
+	t, err := task.FindOneId(taskId)
+	if err != nil {
+		return errors.Wrapf(err, "finding task '%s'", taskId)
+	}
+	if t == nil {
+		return errors.Errorf("task '%s' not found", taskId)
+	}
+	if t.DisplayOnly {
+		var execTasks []task.Task
+		execTasks, err = task.Find(task.ByIds(t.ExecutionTasks))
+		if err != nil {
+			return errors.Wrapf(err, "finding execution tasks for display task '%s'", t.Id)
+		}
+		catcher := grip.NewBasicCatcher()
+		for _, et := range execTasks {
+			if et.Status != evergreen.TaskUndispatched {
+				continue
+			}
+			catcher.Add(SkipTask(et.Id, reason, caller))
+		}
+		return catcher.Resolve()
+	}
+
+	if err = t.MarkSkipped(reason, caller); err != nil {
+		return errors.WithStack(err)
+	}
+	if err = UpdateBlockedDependencies(t); err != nil {
+		return errors.Wrapf(err, "blocking tasks that depend on skipped task '%s'", t.Id)
+	}
+	if t.IsPartOfDisplay() {
+		if err = UpdateDisplayTaskForTask(t); err != nil {
+			return errors.Wrap(err, "updating display task")
+		}
+	}
+	return errors.Wrap(UpdateBuildAndVersionStatusForTask(t), "updating build and version status")
+}
+
 // Deactivate any previously activated but undispatched
 // tasks for the same build variant + display name + project combination
 // as the task.
@@ -493,8 +609,39 @@ func MarkEnd(t *task.Task, caller string, finishTime time.Time, detail *apimodel
 			detailsCopy.Description = evergreen.TaskDescriptionNoResults
 		}
 	}
+	if detailsCopy.Status == evergreen.TaskSucceeded && len(t.ExpectedArtifacts) > 0 {
+		missing, err := FindMissingExpectedArtifacts(t)
+		if err != nil {
+			return errors.Wrap(err, "checking for expected artifacts")
+		}
+		if len(missing) > 0 {
+			grip.Info(message.Fields{
+				"message":           "failing task that did not attach its expected artifacts",
+				"task_id":           t.Id,
+				"execution":         t.Execution,
+				"missing_artifacts": missing,
+			})
+			detailsCopy.Status = evergreen.TaskFailed
+			detailsCopy.Description = evergreen.TaskDescriptionMissingArtifact
+		}
+	}
+	if detailsCopy.Status == evergreen.TaskSucceeded {
+		if missing := FindMissingRequiredOutputs(t); len(missing) > 0 {
+			grip.Info(message.Fields{
+				"message":         "failing task that did not publish its required outputs",
+				"task_id":         t.Id,
+				"execution":       t.Execution,
+				"missing_outputs": missing,
+			})
+			detailsCopy.Status = evergreen.TaskFailed
+			detailsCopy.Description = evergreen.TaskDescriptionMissingOutput
+		}
+	}
 
 	t.Details = detailsCopy
+	if !NeedsCedarFailureFingerprint(t) {
+		t.FailureFingerprint = ComputeFailureFingerprint(t, &detailsCopy)
+	}
 	if utility.IsZeroTime(t.StartTime) {
 		grip.Warning(message.Fields{
 			"message":      "task is missing start time",
@@ -528,6 +675,7 @@ func MarkEnd(t *task.Task, caller string, finishTime time.Time, detail *apimodel
 
 	status := t.GetDisplayStatus()
 	event.LogTaskFinished(t.Id, t.Execution, t.HostId, status)
+	traceTaskFinished(t)
 	grip.Info(message.Fields{
 		"message":   "marking task finished",
 		"task_id":   t.Id,
@@ -584,18 +732,47 @@ func MarkEnd(t *task.Task, caller string, finishTime time.Time, detail *apimodel
 }
 
 // UpdateBlockedDependencies traverses the dependency graph and recursively sets each
-// parent dependency as unattainable in depending tasks.
+// parent dependency as unattainable in depending tasks. The activated tasks
+// that are newly blocked are added to their version's pending blocked tasks,
+// along with the chain of dependencies that blocked them, so their owners can
+// be notified once per version.
 func UpdateBlockedDependencies(t *task.Task) error {
+	blocked := map[string][]event.BlockedTask{}
+	dependents := map[string]task.Task{}
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(updateBlockedDependencies(t, []string{t.Id}, blocked, dependents))
+	now := time.Now()
+	for versionID, blockedTasks := range blocked {
+		catcher.Wrapf(addPendingBlockedTasks(versionID, blockedTasks, now), "adding pending blocked tasks to version '%s'", versionID)
+	}
+	catcher.Wrap(syncDependentRollups(dependents), "updating status rollups for blocked tasks")
+	return catcher.Resolve()
+}
+
+// updateBlockedDependencies marks the task as unattainable in the tasks that
+// depend on it, and recursively in the tasks that depend on those. The chain
+// is the IDs of the tasks from the task that first blocked them to this task.
+// The tasks that are marked are added to the dependents.
+func updateBlockedDependencies(t *task.Task, chain []string, blocked map[string][]event.BlockedTask, dependents map[string]task.Task) error {
 	dependentTasks, err := t.FindAllUnmarkedBlockedDependencies()
 	if err != nil {
 		return errors.Wrapf(err, "getting tasks depending on task '%s'", t.Id)
 	}
 
 	for _, dependentTask := range dependentTasks {
+		wasBlocked := dependentTask.Blocked()
 		if err = dependentTask.MarkUnattainableDependency(t.Id, true); err != nil {
 			return errors.Wrap(err, "marking dependency unattainable")
 		}
-		if err = UpdateBlockedDependencies(&dependentTask); err != nil {
+		dependents[dependentTask.Id] = dependentTask
+		dependentChain := append(append([]string{}, chain...), dependentTask.Id)
+		if !wasBlocked && dependentTask.Activated {
+			blocked[dependentTask.Version] = append(blocked[dependentTask.Version], event.BlockedTask{
+				TaskID: dependentTask.Id,
+				Chain:  dependentChain,
+			})
+		}
+		if err = updateBlockedDependencies(&dependentTask, dependentChain, blocked, dependents); err != nil {
 			return errors.Wrapf(err, "updating blocked dependencies for '%s'", t.Id)
 		}
 	}
@@ -604,6 +781,17 @@ func UpdateBlockedDependencies(t *task.Task) error {
 
 // UpdateUnblockedDependencies recursively marks all unattainable dependencies as attainable.
 func UpdateUnblockedDependencies(t *task.Task) error {
+	dependents := map[string]task.Task{}
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(updateUnblockedDependencies(t, dependents))
+	catcher.Wrap(syncDependentRollups(dependents), "updating status rollups for unblocked tasks")
+	return catcher.Resolve()
+}
+
+// updateUnblockedDependencies marks the task as attainable in the tasks that
+// depend on it, and recursively in the tasks that depend on those. The tasks
+// that are marked are added to the dependents.
+func updateUnblockedDependencies(t *task.Task, dependents map[string]task.Task) error {
 	blockedTasks, err := t.FindAllMarkedUnattainableDependencies()
 	if err != nil {
 		return errors.Wrap(err, "getting dependencies marked unattainable")
@@ -613,8 +801,9 @@ func UpdateUnblockedDependencies(t *task.Task) error {
 		if err = blockedTask.MarkUnattainableDependency(t.Id, false); err != nil {
 			return errors.Wrap(err, "marking dependency attainable")
 		}
+		dependents[blockedTask.Id] = blockedTask
 
-		if err := UpdateUnblockedDependencies(&blockedTask); err != nil {
+		if err := updateUnblockedDependencies(&blockedTask, dependents); err != nil {
 			return errors.WithStack(err)
 		}
 	}
@@ -622,40 +811,58 @@ func UpdateUnblockedDependencies(t *task.Task) error {
 	return nil
 }
 
-func RestartItemsAfterVersion(cq *commitqueue.CommitQueue, project, version, caller string) error {
+// RestartItemsAfterVersion restarts the tasks of the commit queue items after
+// the item with the given version, and returns the tasks that are aborted or
+// restarted along with their builds and versions. If dryRun is true, nothing
+// is written.
+func RestartItemsAfterVersion(cq *commitqueue.CommitQueue, project, version, caller string, dryRun bool) (AffectedEntities, error) {
+	affected := AffectedEntities{}
 	if cq == nil {
 		var err error
 		cq, err = commitqueue.FindOneId(project)
 		if err != nil {
-			return errors.Wrapf(err, "getting commit queue for project '%s'", project)
+			return affected, errors.Wrapf(err, "getting commit queue for project '%s'", project)
 		}
 		if cq == nil {
-			return errors.Errorf("commit queue for project '%s' not found", project)
+			return affected, errors.Errorf("commit queue for project '%s' not found", project)
 		}
 	}
 
-	foundItem := false
 	catcher := grip.NewBasicCatcher()
+	for _, restartVersion := range versionsAfterVersion(*cq, version) {
+		grip.InfoWhen(!dryRun, message.Fields{
+			"message":            "restarting items due to commit queue failure",
+			"failing_version":    version,
+			"restarting_version": restartVersion,
+			"project":            project,
+			"caller":             caller,
+		})
+		restarted, err := restartTasksInVersion(restartVersion, true, dryRun, caller)
+		catcher.Add(err)
+		affected.Merge(restarted)
+	}
+	affected.sortIDs()
+
+	return affected, catcher.Resolve()
+}
+
+// versionsAfterVersion returns the versions of the commit queue items after
+// the item with the given version, up to the first item that hasn't been
+// processed yet.
+func versionsAfterVersion(cq commitqueue.CommitQueue, version string) []string {
+	foundItem := false
+	versions := []string{}
 	for _, item := range cq.Queue {
 		if item.Version == "" {
-			return nil
+			break
 		}
 		if item.Version == version {
 			foundItem = true
-		} else if foundItem && item.Version != "" {
-			grip.Info(message.Fields{
-				"message":            "restarting items due to commit queue failure",
-				"failing_version":    version,
-				"restarting_version": item.Version,
-				"project":            project,
-				"caller":             caller,
-			})
-			// this block executes on all items after the given task
-			catcher.Add(RestartTasksInVersion(item.Version, true, caller))
+		} else if foundItem {
+			versions = append(versions, item.Version)
 		}
 	}
-
-	return catcher.Resolve()
+	return versions
 }
 
 // DequeueAndRestartForTask restarts all items after the given task's version, aborts/dequeues the current version,
@@ -672,7 +879,7 @@ func DequeueAndRestartForTask(cq *commitqueue.CommitQueue, t *task.Task, githubS
 		}
 	}
 	// this must be done before dequeuing so that we know which entries to restart
-	if err := RestartItemsAfterVersion(cq, t.Project, t.Version, caller); err != nil {
+	if _, err := RestartItemsAfterVersion(cq, t.Project, t.Version, caller, false); err != nil {
 		return errors.Wrapf(err, "restarting items after version '%s'", t.Version)
 	}
 
@@ -728,65 +935,36 @@ func tryDequeueAndAbortCommitQueueVersion(p *patch.Patch, cq commitqueue.CommitQ
 	}
 
 	event.LogCommitQueueConcludeTest(p.Id.Hex(), evergreen.MergeTestFailed)
-	return errors.Wrap(CancelPatch(p, task.AbortInfo{TaskID: taskId, User: caller}), "aborting failed commit queue patch")
+	_, err = CancelPatch(p, task.AbortInfo{TaskID: taskId, User: caller}, false)
+	return errors.Wrap(err, "aborting failed commit queue patch")
 }
 
 // removeNextMergeTaskDependency basically removes the given merge task from a linked list of
 // merge task dependencies. It makes the next merge not depend on the current one and also makes
 // the next merge depend on the previous one, if there is one
 func removeNextMergeTaskDependency(cq commitqueue.CommitQueue, currentIssue string) error {
-	currentIndex := cq.FindItem(currentIssue)
-	if currentIndex < 0 {
-		return errors.New("commit queue item not found")
+	change, err := planRemoveNextMergeTaskDependency(cq, currentIssue)
+	if err != nil {
+		return err
 	}
-	if currentIndex+1 >= len(cq.Queue) {
+	if change == nil {
 		return nil
 	}
+	return applyMergeTaskDependencyChange(*change)
+}
 
-	nextItem := cq.Queue[currentIndex+1]
-	if nextItem.Version == "" {
-		return nil
-	}
-	nextMerge, err := task.FindMergeTaskForVersion(nextItem.Version)
-	if err != nil {
-		return errors.Wrap(err, "finding next merge task")
-	}
-	if nextMerge == nil {
-		return errors.New("no merge task found")
-	}
-	currentMerge, err := task.FindMergeTaskForVersion(cq.Queue[currentIndex].Version)
+func evalStepback(t *task.Task, caller, status string, deactivatePrevious bool) error {
+	isBisectionStep, err := advanceBisection(t, status)
 	if err != nil {
-		return errors.Wrap(err, "finding current merge task")
-	}
-	if err = nextMerge.RemoveDependency(currentMerge.Id); err != nil {
-		return errors.Wrap(err, "removing dependency")
+		return errors.Wrap(err, "advancing bisection")
 	}
-
-	if currentIndex > 0 {
-		prevItem := cq.Queue[currentIndex-1]
-		prevMerge, err := task.FindMergeTaskForVersion(prevItem.Version)
-		if err != nil {
-			return errors.Wrap(err, "finding previous merge task")
-		}
-		if prevMerge == nil {
-			return errors.New("no merge task found")
-		}
-		d := task.Dependency{
-			TaskId: prevMerge.Id,
-			Status: AllStatuses,
-		}
-		if err = nextMerge.AddDependency(d); err != nil {
-			return errors.Wrap(err, "adding dependency")
-		}
+	if isBisectionStep {
+		return nil
 	}
 
-	return nil
-}
-
-func evalStepback(t *task.Task, caller, status string, deactivatePrevious bool) error {
 	if status == evergreen.TaskFailed && !t.Aborted {
 		var shouldStepBack bool
-		shouldStepBack, err := getStepback(t.Id)
+		shouldStepBack, err = getStepback(t.Id)
 		if err != nil {
 			return errors.WithStack(err)
 		}
@@ -813,6 +991,14 @@ func evalStepback(t *task.Task, caller, status string, deactivatePrevious bool)
 
 			return catcher.Resolve()
 		}
+
+		bisecting, err := maybeStartBisection(t)
+		if err != nil {
+			return errors.Wrap(err, "starting bisection")
+		}
+		if bisecting {
+			return nil
+		}
 		return errors.Wrap(doStepback(t), "performing stepback")
 
 	} else if status == evergreen.TaskSucceeded && deactivatePrevious && t.Requester == evergreen.RepotrackerVersionRequester {
@@ -833,8 +1019,21 @@ func updateMakespans(b *build.Build, buildTasks []task.Task) error {
 }
 
 // getBuildStatus returns a string denoting the status of the build and
-// a boolean denoting if all tasks in the build are blocked.
+// a boolean denoting if all tasks in the build are blocked. Skipped tasks
+// neither fail the build nor keep it from succeeding, so a build whose tasks
+// were all skipped succeeds.
 func getBuildStatus(buildTasks []task.Task) (string, bool) {
+	tasksToRun := make([]task.Task, 0, len(buildTasks))
+	for _, t := range buildTasks {
+		if t.Status != evergreen.TaskSkipped {
+			tasksToRun = append(tasksToRun, t)
+		}
+	}
+	if len(buildTasks) > 0 && len(tasksToRun) == 0 {
+		return evergreen.BuildSucceeded, false
+	}
+	buildTasks = tasksToRun
+
 	// Check if no tasks have started and if all tasks are blocked.
 	noStartedTasks := true
 	allTasksBlocked := true
@@ -900,11 +1099,16 @@ func updateBuildGithubStatus(b *build.Build, buildTasks []task.Task) error {
 // updateBuildStatus updates the status of the build based on its tasks' statuses
 // Returns true if the build's status has changed or if all of the build's tasks become blocked.
 func updateBuildStatus(b *build.Build) (bool, error) {
-	buildTasks, err := task.FindWithFields(task.ByBuildId(b.Id), task.StatusKey, task.ActivatedKey, task.DependsOnKey, task.IsGithubCheckKey, task.AbortedKey)
+	buildTasks, err := task.FindWithFields(task.ByBuildId(b.Id), task.StatusKey, task.ActivatedKey, task.DependsOnKey, task.IsGithubCheckKey, task.AbortedKey,
+		task.DisplayOnlyKey, task.ActivatedTimeKey, task.ScheduledTimeKey, task.DependenciesMetTimeKey, task.StartTimeKey, task.FinishTimeKey)
 	if err != nil {
 		return false, errors.Wrapf(err, "getting tasks in build '%s'", b.Id)
 	}
 
+	if err = b.SetTimingBreakdown(task.GetTimingBreakdown(buildTasks)); err != nil {
+		return false, errors.Wrapf(err, "setting timing breakdown for build '%s'", b.Id)
+	}
+
 	buildStatus, allTasksBlocked := getBuildStatus(buildTasks)
 	blockedChanged := allTasksBlocked != b.AllTasksBlocked
 
@@ -939,6 +1143,7 @@ func updateBuildStatus(b *build.Build) (bool, error) {
 		if err = b.MarkFinished(buildStatus, time.Now()); err != nil {
 			return true, errors.Wrapf(err, "marking build as finished with status '%s'", buildStatus)
 		}
+		traceBuildFinished(b)
 		if err = updateMakespans(b, buildTasks); err != nil {
 			return true, errors.Wrapf(err, "updating makespan information for '%s'", b.Id)
 		}
@@ -1009,11 +1214,19 @@ func updateVersionGithubStatus(v *Version, builds []build.Build) error {
 // Update the status of the version based on its constituent builds
 func updateVersionStatus(v *Version) (string, error) {
 	builds, err := build.Find(build.ByVersion(v.Id).WithFields(build.ActivatedKey, build.StatusKey,
-		build.IsGithubCheckKey, build.GithubCheckStatusKey, build.AbortedKey))
+		build.IsGithubCheckKey, build.GithubCheckStatusKey, build.AbortedKey, build.TimingKey))
 	if err != nil {
 		return "", errors.Wrapf(err, "getting builds for version '%s'", v.Id)
 	}
 
+	var timing task.TimingBreakdown
+	for _, b := range builds {
+		timing = timing.Add(b.Timing)
+	}
+	if err = v.SetTimingBreakdown(timing); err != nil {
+		return "", errors.Wrapf(err, "setting timing breakdown for version '%s'", v.Id)
+	}
+
 	// Regardless of whether the overall version status has changed, the Github status subset may have changed.
 	if err = updateVersionGithubStatus(v, builds); err != nil {
 		return "", errors.Wrap(err, "updating version GitHub status")
@@ -1044,6 +1257,7 @@ func updateVersionStatus(v *Version) (string, error) {
 		if err = v.MarkFinished(versionStatus, time.Now()); err != nil {
 			return "", errors.Wrapf(err, "marking version '%s' as finished with status '%s'", v.Id, versionStatus)
 		}
+		traceVersionFinished(v)
 	} else {
 		if err = v.UpdateStatus(versionStatus); err != nil {
 			return "", errors.Wrapf(err, "updating version '%s' with status '%s'", v.Id, versionStatus)
@@ -1088,7 +1302,13 @@ func UpdateBuildAndVersionStatusForTask(t *task.Task) error {
 	if taskBuild == nil {
 		return errors.Errorf("no build '%s' found for task '%s'", t.BuildId, t.Id)
 	}
-	buildStatusChanged, err := updateBuildStatus(taskBuild)
+	useRollup := isEventSourcedStatusRollupEnabled(t.Project)
+	var buildStatusChanged bool
+	if useRollup {
+		buildStatusChanged, err = updateBuildStatusForTaskFromRollup(taskBuild, t.Id)
+	} else {
+		buildStatusChanged, err = updateBuildStatus(taskBuild)
+	}
 	if err != nil {
 		return errors.Wrapf(err, "updating build '%s' status", taskBuild.Id)
 	}
@@ -1104,7 +1324,12 @@ func UpdateBuildAndVersionStatusForTask(t *task.Task) error {
 	if taskVersion == nil {
 		return errors.Errorf("no version '%s' found for task '%s'", t.Version, t.Id)
 	}
-	newVersionStatus, err := updateVersionStatus(taskVersion)
+	var newVersionStatus string
+	if useRollup {
+		newVersionStatus, err = updateVersionStatusForBuildFromRollup(taskVersion, taskBuild)
+	} else {
+		newVersionStatus, err = updateVersionStatus(taskVersion)
+	}
 	if err != nil {
 		return errors.Wrapf(err, "updating version '%s' status", taskVersion.Id)
 	}
@@ -1131,9 +1356,20 @@ func UpdateVersionAndPatchStatusForBuilds(buildIds []string) error {
 		return errors.Wrapf(err, "fetching builds")
 	}
 
+	// The builds' tasks may have changed in any way, so builds in projects
+	// that use event-sourced status rollups are recounted.
+	rollupProjects := map[string]bool{}
 	versionsToUpdate := make(map[string]string)
 	for _, build := range builds {
-		buildStatusChanged, err := updateBuildStatus(&build)
+		if _, ok := rollupProjects[build.Project]; !ok {
+			rollupProjects[build.Project] = isEventSourcedStatusRollupEnabled(build.Project)
+		}
+		var buildStatusChanged bool
+		if rollupProjects[build.Project] {
+			buildStatusChanged, err = updateBuildStatusFromRecountedRollup(&build)
+		} else {
+			buildStatusChanged, err = updateBuildStatus(&build)
+		}
 		if err != nil {
 			return errors.Wrapf(err, "updating build '%s' status", build.Id)
 		}
@@ -1152,7 +1388,12 @@ func UpdateVersionAndPatchStatusForBuilds(buildIds []string) error {
 		if buildVersion == nil {
 			return errors.Errorf("no version '%s' found for build '%s'", versionId, buildId)
 		}
-		newVersionStatus, err := updateVersionStatus(buildVersion)
+		var newVersionStatus string
+		if rollupProjects[buildVersion.Identifier] {
+			newVersionStatus, err = updateVersionStatusFromRecountedRollup(buildVersion)
+		} else {
+			newVersionStatus, err = updateVersionStatus(buildVersion)
+		}
 		if err != nil {
 			return errors.Wrapf(err, "updating version '%s' status", buildVersion.Id)
 		}
@@ -1259,6 +1500,7 @@ func MarkOneTaskReset(t *task.Task, logIDs bool) error {
 		}
 	}
 
+	traceTaskReset(t)
 	if err := t.Reset(); err != nil {
 		return errors.Wrap(err, "resetting task in database")
 	}
@@ -1275,19 +1517,29 @@ func MarkOneTaskReset(t *task.Task, logIDs bool) error {
 }
 
 // MarkTasksReset resets many tasks by their IDs. For execution tasks, this also
-// resets their parent display tasks.
-func MarkTasksReset(taskIds []string) error {
+// resets their parent display tasks. It returns the tasks that are reset along
+// with their builds and versions. If dryRun is true, nothing is written.
+func MarkTasksReset(taskIds []string, dryRun bool) (AffectedEntities, error) {
+	affected := AffectedEntities{}
 	tasks, err := task.FindAll(db.Query(task.ByIds(taskIds)))
 	if err != nil {
-		return errors.WithStack(err)
+		return affected, errors.WithStack(err)
 	}
 	tasks, err = task.AddParentDisplayTasks(tasks)
 	if err != nil {
-		return errors.WithStack(err)
+		return affected, errors.WithStack(err)
+	}
+	affected.AddTasks(tasks...)
+	affected.sortIDs()
+	if dryRun {
+		return affected, nil
 	}
 
+	for i := range tasks {
+		traceTaskReset(&tasks[i])
+	}
 	if err = task.ResetTasks(tasks); err != nil {
-		return errors.Wrap(err, "resetting tasks in database")
+		return affected, errors.Wrap(err, "resetting tasks in database")
 	}
 
 	catcher := grip.NewBasicCatcher()
@@ -1296,7 +1548,7 @@ func MarkTasksReset(taskIds []string) error {
 		catcher.Wrapf(t.MarkDependenciesFinished(false), "marking direct dependencies unfinished for task '%s'", t.Id)
 	}
 
-	return catcher.Resolve()
+	return affected, catcher.Resolve()
 }
 
 // RestartFailedTasks attempts to restart failed tasks that started between 2 times
@@ -1402,6 +1654,12 @@ func doRestartFailedTasks(tasks []string, user string, results RestartResults) R
 }
 
 func ClearAndResetStrandedTask(h *host.Host) error {
+	return clearAndResetHostTask(h, evergreen.TaskDescriptionStranded)
+}
+
+// clearAndResetHostTask clears the host's running task and system fails it
+// with the given description, resetting it so that it can run again.
+func clearAndResetHostTask(h *host.Host, description string) error {
 	if h.RunningTask == "" {
 		return nil
 	}
@@ -1436,7 +1694,7 @@ func ClearAndResetStrandedTask(h *host.Host) error {
 		return nil
 	}
 
-	if err = t.MarkSystemFailed(evergreen.TaskDescriptionStranded); err != nil {
+	if err = t.MarkSystemFailed(description); err != nil {
 		return errors.Wrap(err, "marking task failed")
 	}
 
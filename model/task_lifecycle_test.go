@@ -12,6 +12,7 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/db/mgo/bson"
 	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
+	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/commitqueue"
 	"github.com/evergreen-ci/evergreen/model/distro"
@@ -968,6 +969,93 @@ func TestGetBuildStatus(t *testing.T) {
 	assert.Equal(t, evergreen.BuildCreated, status)
 	assert.Equal(t, true, allTasksBlocked)
 
+	// Skipped tasks shouldn't fail the build or prevent it from succeeding.
+	buildTasks = []task.Task{
+		{Status: evergreen.TaskSkipped},
+		{Status: evergreen.TaskSucceeded},
+	}
+	status, allTasksBlocked = getBuildStatus(buildTasks)
+	assert.Equal(t, evergreen.BuildSucceeded, status)
+	assert.Equal(t, false, allTasksBlocked)
+
+	buildTasks = []task.Task{
+		{Status: evergreen.TaskSkipped},
+		{Status: evergreen.TaskUndispatched},
+	}
+	status, allTasksBlocked = getBuildStatus(buildTasks)
+	assert.Equal(t, evergreen.BuildCreated, status)
+	assert.Equal(t, false, allTasksBlocked)
+
+	buildTasks = []task.Task{
+		{Status: evergreen.TaskSkipped},
+		{Status: evergreen.TaskSkipped},
+	}
+	status, allTasksBlocked = getBuildStatus(buildTasks)
+	assert.Equal(t, evergreen.BuildSucceeded, status)
+	assert.Equal(t, false, allTasksBlocked)
+}
+
+func TestSkipTask(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, build.Collection, VersionCollection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, build.Collection, VersionCollection, event.AllLogCollection))
+	}()
+
+	v := &Version{Id: "v", BuildIds: []string{"b"}}
+	require.NoError(t, v.Insert())
+	b := &build.Build{Id: "b", Version: v.Id, Status: evergreen.BuildCreated}
+	require.NoError(t, b.Insert())
+	skipped := task.Task{Id: "skipped", BuildId: b.Id, Version: v.Id, Status: evergreen.TaskUndispatched}
+	require.NoError(t, skipped.Insert())
+	dependent := task.Task{
+		Id:        "dependent",
+		BuildId:   b.Id,
+		Version:   v.Id,
+		Status:    evergreen.TaskUndispatched,
+		DependsOn: []task.Dependency{{TaskId: skipped.Id, Status: evergreen.TaskSucceeded}},
+	}
+	require.NoError(t, dependent.Insert())
+	anyStatus := task.Task{
+		Id:        "any_status",
+		BuildId:   b.Id,
+		Version:   v.Id,
+		Status:    evergreen.TaskSucceeded,
+		DependsOn: []task.Dependency{{TaskId: skipped.Id, Status: AllStatuses}},
+	}
+	require.NoError(t, anyStatus.Insert())
+
+	require.NoError(t, SkipTask(skipped.Id, "no changes to watched paths", "user"))
+
+	dbTask, err := task.FindOneId(skipped.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbTask)
+	assert.Equal(t, evergreen.TaskSkipped, dbTask.Status)
+	assert.Equal(t, evergreen.TaskSkipped, dbTask.GetDisplayStatus())
+	assert.Equal(t, "no changes to watched paths", dbTask.SkipReason)
+	assert.True(t, dbTask.IsFinished())
+	assert.False(t, utility.IsZeroTime(dbTask.FinishTime))
+
+	dbDependent, err := task.FindOneId(dependent.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbDependent)
+	assert.True(t, dbDependent.Blocked())
+	assert.Equal(t, evergreen.TaskStatusBlocked, dbDependent.GetDisplayStatus())
+	assert.True(t, anyStatus.SatisfiesDependency(dbTask))
+
+	// The only task left to run is blocked, so the build doesn't fail.
+	dbBuild, err := build.FindOneId(b.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbBuild)
+	assert.NotEqual(t, evergreen.BuildFailed, dbBuild.Status)
+
+	t.Run("FailsForDispatchedTask", func(t *testing.T) {
+		assert.Error(t, SkipTask(anyStatus.Id, "reason", "user"))
+	})
+	t.Run("FailsWithoutReason", func(t *testing.T) {
+		other := task.Task{Id: "other", BuildId: b.Id, Version: v.Id, Status: evergreen.TaskUndispatched}
+		require.NoError(t, other.Insert())
+		assert.Error(t, SkipTask(other.Id, "", "user"))
+	})
 }
 
 func TestGetVersionStatus(t *testing.T) {
@@ -3345,6 +3433,125 @@ func TestMarkEndWithNoResults(t *testing.T) {
 	assert.Equal(t, evergreen.TaskSucceeded, dbTask.Status)
 }
 
+func TestMarkEndWithMissingExpectedArtifacts(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, build.Collection, VersionCollection, event.AllLogCollection, testresult.Collection, artifact.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, build.Collection, VersionCollection, event.AllLogCollection, testresult.Collection, artifact.Collection))
+	}()
+	missingTask := task.Task{
+		Id:                "t1",
+		Status:            evergreen.TaskStarted,
+		Activated:         true,
+		ActivatedTime:     time.Now(),
+		BuildId:           "b",
+		Version:           "v",
+		ExpectedArtifacts: []string{"binaries", "*.tgz"},
+	}
+	require.NoError(t, missingTask.Insert())
+	attachedTask := task.Task{
+		Id:                "t2",
+		Status:            evergreen.TaskStarted,
+		Activated:         true,
+		ActivatedTime:     time.Now(),
+		BuildId:           "b",
+		Version:           "v",
+		ExpectedArtifacts: []string{"binaries", "*.tgz"},
+	}
+	require.NoError(t, attachedTask.Insert())
+	b := build.Build{
+		Id:      "b",
+		Version: "v",
+	}
+	require.NoError(t, b.Insert())
+	v := &Version{
+		Id:        "v",
+		Requester: evergreen.RepotrackerVersionRequester,
+		Status:    evergreen.VersionStarted,
+		Config:    "identifier: sample",
+	}
+	require.NoError(t, v.Insert())
+	entries := []artifact.Entry{
+		{TaskId: missingTask.Id, Files: []artifact.File{{Name: "binaries", Link: "https://example.com/bin"}}},
+		{TaskId: attachedTask.Id, Files: []artifact.File{{Name: "binaries", Link: "https://example.com/bin"}, {Name: "dist.tgz", Link: "https://example.com/dist.tgz"}}},
+	}
+	for _, entry := range entries {
+		require.NoError(t, entry.Upsert())
+	}
+	details := &apimodels.TaskEndDetail{
+		Status: evergreen.TaskSucceeded,
+		Type:   "test",
+	}
+
+	require.NoError(t, MarkEnd(&missingTask, "", time.Now(), details, false))
+	dbTask, err := task.FindOneId(missingTask.Id)
+	require.NoError(t, err)
+	assert.Equal(t, evergreen.TaskFailed, dbTask.Status)
+	assert.Equal(t, evergreen.TaskDescriptionMissingArtifact, dbTask.Details.Description)
+
+	require.NoError(t, MarkEnd(&attachedTask, "", time.Now(), details, false))
+	dbTask, err = task.FindOneId(attachedTask.Id)
+	require.NoError(t, err)
+	assert.Equal(t, evergreen.TaskSucceeded, dbTask.Status)
+}
+
+func TestMarkEndWithMissingRequiredOutputs(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, build.Collection, VersionCollection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, build.Collection, VersionCollection, event.AllLogCollection))
+	}()
+	missingTask := task.Task{
+		Id:              "t1",
+		Status:          evergreen.TaskStarted,
+		Activated:       true,
+		ActivatedTime:   time.Now(),
+		BuildId:         "b",
+		Version:         "v",
+		RequiredOutputs: []string{"binary_path", "version"},
+	}
+	require.NoError(t, missingTask.Insert())
+	require.NoError(t, missingTask.SetOutputs(map[string]string{"binary_path": "/bin/evergreen"}))
+	publishedTask := task.Task{
+		Id:              "t2",
+		Status:          evergreen.TaskStarted,
+		Activated:       true,
+		ActivatedTime:   time.Now(),
+		BuildId:         "b",
+		Version:         "v",
+		RequiredOutputs: []string{"binary_path", "version"},
+	}
+	require.NoError(t, publishedTask.Insert())
+	require.NoError(t, publishedTask.SetOutputs(map[string]string{"binary_path": "/bin/evergreen"}))
+	require.NoError(t, publishedTask.SetOutputs(map[string]string{"version": "1.0"}))
+	b := build.Build{
+		Id:      "b",
+		Version: "v",
+	}
+	require.NoError(t, b.Insert())
+	v := &Version{
+		Id:        "v",
+		Requester: evergreen.RepotrackerVersionRequester,
+		Status:    evergreen.VersionStarted,
+		Config:    "identifier: sample",
+	}
+	require.NoError(t, v.Insert())
+	details := &apimodels.TaskEndDetail{
+		Status: evergreen.TaskSucceeded,
+		Type:   "test",
+	}
+
+	require.NoError(t, MarkEnd(&missingTask, "", time.Now(), details, false))
+	dbTask, err := task.FindOneId(missingTask.Id)
+	require.NoError(t, err)
+	assert.Equal(t, evergreen.TaskFailed, dbTask.Status)
+	assert.Equal(t, evergreen.TaskDescriptionMissingOutput, dbTask.Details.Description)
+
+	require.NoError(t, MarkEnd(&publishedTask, "", time.Now(), details, false))
+	dbTask, err = task.FindOneId(publishedTask.Id)
+	require.NoError(t, err)
+	assert.Equal(t, evergreen.TaskSucceeded, dbTask.Status)
+	assert.Equal(t, map[string]string{"binary_path": "/bin/evergreen", "version": "1.0"}, dbTask.Outputs)
+}
+
 func TestClearAndResetStaleStrandedTask(t *testing.T) {
 	require.NoError(t, db.ClearCollections(host.Collection, task.Collection, task.OldCollection, build.Collection))
 	assert := assert.New(t)
@@ -4307,6 +4514,82 @@ func TestUpdateBlockedDependencies(t *testing.T) {
 
 }
 
+func TestUpdateBlockedDependenciesLogsBlockedChains(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, VersionCollection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, VersionCollection, event.AllLogCollection))
+	}()
+
+	require.NoError(t, (&Version{Id: "v1"}).Insert())
+	tasks := []task.Task{
+		{Id: "compile", Version: "v1", Activated: true, Status: evergreen.TaskFailed},
+		{Id: "lint", Version: "v1", Activated: true, Status: evergreen.TaskFailed},
+		{
+			Id:        "test",
+			Version:   "v1",
+			Activated: true,
+			Status:    evergreen.TaskUndispatched,
+			DependsOn: []task.Dependency{{TaskId: "compile", Status: evergreen.TaskSucceeded}},
+		},
+		{
+			Id:        "e2e",
+			Version:   "v1",
+			Activated: true,
+			Status:    evergreen.TaskUndispatched,
+			DependsOn: []task.Dependency{{TaskId: "test", Status: evergreen.TaskSucceeded}},
+		},
+		{
+			Id:        "inactive",
+			Version:   "v1",
+			Status:    evergreen.TaskUndispatched,
+			DependsOn: []task.Dependency{{TaskId: "compile", Status: evergreen.TaskSucceeded}},
+		},
+		{
+			Id:        "format",
+			Version:   "v1",
+			Activated: true,
+			Status:    evergreen.TaskUndispatched,
+			DependsOn: []task.Dependency{{TaskId: "lint", Status: evergreen.TaskSucceeded}},
+		},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+	}
+
+	require.NoError(t, UpdateBlockedDependencies(&tasks[0]))
+	require.NoError(t, UpdateBlockedDependencies(&tasks[1]))
+
+	events, err := event.Find(event.AllLogCollection, db.Query(bson.M{event.ResourceTypeKey: event.ResourceTypeVersion}))
+	require.NoError(t, err)
+	assert.Empty(t, events, "should wait to log the blocked tasks")
+
+	versions, err := FindVersionsWithPendingBlockedTasks(time.Now().Add(-TasksBlockedNotificationDelay))
+	require.NoError(t, err)
+	assert.Empty(t, versions, "should not log the blocked tasks before the delay")
+	versions, err = FindVersionsWithPendingBlockedTasks(time.Now())
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.NoError(t, LogPendingBlockedTasks(versions[0].Id))
+	require.NoError(t, LogPendingBlockedTasks(versions[0].Id))
+
+	events, err = event.Find(event.AllLogCollection, db.Query(bson.M{event.ResourceTypeKey: event.ResourceTypeVersion}))
+	require.NoError(t, err)
+	require.Len(t, events, 1, "should log one event for the version")
+	assert.Equal(t, "v1", events[0].ResourceId)
+	assert.Equal(t, event.VersionTasksBlocked, events[0].EventType)
+	data, ok := events[0].Data.(*event.VersionEventData)
+	require.True(t, ok)
+	assert.ElementsMatch(t, []event.BlockedTask{
+		{TaskID: "test", Chain: []string{"compile", "test"}},
+		{TaskID: "e2e", Chain: []string{"compile", "test", "e2e"}},
+		{TaskID: "format", Chain: []string{"lint", "format"}},
+	}, data.BlockedTasks)
+
+	versions, err = FindVersionsWithPendingBlockedTasks(time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, versions, "should clear the logged blocked tasks")
+}
+
 func TestUpdateUnblockedDependencies(t *testing.T) {
 	assert := assert.New(t)
 	assert.NoError(db.ClearCollections(task.Collection, build.Collection))
@@ -69,7 +69,11 @@ func (self *TaskLog) Insert() error {
 
 	self.Id = mgobson.NewObjectId().Hex()
 
-	return db.C(TaskLogCollection).Insert(self)
+	if err = db.C(TaskLogCollection).Insert(self); err != nil {
+		return err
+	}
+	taskLogAppends.notify(self.TaskId)
+	return nil
 }
 
 func (self *TaskLog) AddLogMessage(msg apimodels.LogMessage) error {
@@ -85,7 +89,7 @@ func (self *TaskLog) AddLogMessage(msg apimodels.LogMessage) error {
 	self.Messages = append(self.Messages, msg)
 	self.MessageCount = self.MessageCount + 1
 
-	return db.C(TaskLogCollection).UpdateId(self.Id,
+	err = db.C(TaskLogCollection).UpdateId(self.Id,
 		bson.M{
 			"$inc": bson.M{
 				TaskLogMessageCountKey: 1,
@@ -95,6 +99,11 @@ func (self *TaskLog) AddLogMessage(msg apimodels.LogMessage) error {
 			},
 		},
 	)
+	if err != nil {
+		return err
+	}
+	taskLogAppends.notify(self.TaskId)
+	return nil
 }
 
 func FindAllTaskLogs(taskId string, execution int) ([]TaskLog, error) {
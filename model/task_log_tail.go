@@ -0,0 +1,180 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/utility"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TaskLogTailToken marks how far a reader has read into a task's logs so that
+// tailing can resume from where it left off. Task log documents are read in
+// order of the timestamps that the agent gave them, with the document ID
+// breaking ties, since IDs generated by different app servers don't follow
+// the order that the documents were sent in. The offset is the number of
+// messages already read from the document with LogID.
+type TaskLogTailToken struct {
+	Timestamp time.Time
+	LogID     string
+	Offset    int
+}
+
+// String returns the token in the form that ParseTaskLogTailToken accepts.
+func (t TaskLogTailToken) String() string {
+	if t.LogID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d:%s:%d", utility.UnixMilli(t.Timestamp), t.LogID, t.Offset)
+}
+
+// ParseTaskLogTailToken parses a token returned from TaskLogTailToken.String.
+// An empty string is the token for the beginning of the logs.
+func ParseTaskLogTailToken(s string) (TaskLogTailToken, error) {
+	if s == "" {
+		return TaskLogTailToken{}, nil
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 || parts[1] == "" {
+		return TaskLogTailToken{}, errors.Errorf("malformed task log resume token '%s'", s)
+	}
+	millis, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return TaskLogTailToken{}, errors.Errorf("malformed timestamp in task log resume token '%s'", s)
+	}
+	offset, err := strconv.Atoi(parts[2])
+	if err != nil || offset < 0 {
+		return TaskLogTailToken{}, errors.Errorf("malformed offset in task log resume token '%s'", s)
+	}
+	return TaskLogTailToken{
+		Timestamp: time.Unix(millis/1000, (millis%1000)*int64(time.Millisecond)),
+		LogID:     parts[1],
+		Offset:    offset,
+	}, nil
+}
+
+// FindTaskLogsFromToken returns up to limit of the task's log documents
+// starting with the one that the token is in, in the order that they were sent.
+func FindTaskLogsFromToken(taskId string, execution int, token TaskLogTailToken, limit int) ([]TaskLog, error) {
+	session, db, err := getSessionAndDB()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	query := bson.M{
+		TaskLogTaskIdKey:    taskId,
+		TaskLogExecutionKey: execution,
+	}
+	if token.LogID != "" {
+		query["$or"] = []bson.M{
+			{TaskLogTimestampKey: bson.M{"$gt": token.Timestamp}},
+			{
+				TaskLogTimestampKey: token.Timestamp,
+				TaskLogIdKey:        bson.M{"$gte": token.LogID},
+			},
+		}
+	}
+
+	result := []TaskLog{}
+	err = db.C(TaskLogCollection).Find(query).Sort(TaskLogTimestampKey, TaskLogIdKey).Limit(limit).All(&result)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return result, err
+}
+
+// TailTaskLogMessages returns up to limit of the task's log messages that come
+// after the token along with the token to pass in to read the messages after
+// those. If there are no new messages, the same token is returned.
+func TailTaskLogMessages(taskId string, execution int, token TaskLogTailToken, limit int) ([]apimodels.LogMessage, TaskLogTailToken, error) {
+	msgs := []apimodels.LogMessage{}
+	for len(msgs) < limit {
+		taskLogs, err := FindTaskLogsFromToken(taskId, execution, token, limit/MessagesPerLog+2)
+		if err != nil {
+			return nil, token, errors.Wrapf(err, "finding logs for task '%s'", taskId)
+		}
+
+		progressed := false
+		for _, taskLog := range taskLogs {
+			offset := 0
+			if taskLog.Id == token.LogID {
+				offset = token.Offset
+			}
+			if offset >= len(taskLog.Messages) {
+				continue
+			}
+
+			end := len(taskLog.Messages)
+			if remaining := limit - len(msgs); end-offset > remaining {
+				end = offset + remaining
+			}
+			msgs = append(msgs, taskLog.Messages[offset:end]...)
+			token = TaskLogTailToken{Timestamp: taskLog.Timestamp, LogID: taskLog.Id, Offset: end}
+			progressed = true
+			if len(msgs) >= limit {
+				break
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	return msgs, token, nil
+}
+
+// taskLogAppendNotifier wakes up the readers tailing a task's logs when new
+// log messages for the task are stored by this process. Readers must still
+// poll for messages stored by other app servers.
+type taskLogAppendNotifier struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]bool
+}
+
+var taskLogAppends = &taskLogAppendNotifier{subscribers: map[string]map[chan struct{}]bool{}}
+
+// SubscribeTaskLogAppends returns a channel that receives a value whenever
+// new log messages are stored for the task and a function to stop the
+// subscription. Notifications are coalesced, so a single value may stand for
+// several appends.
+func SubscribeTaskLogAppends(taskId string) (<-chan struct{}, func()) {
+	return taskLogAppends.subscribe(taskId)
+}
+
+func (n *taskLogAppendNotifier) subscribe(taskId string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.subscribers[taskId] == nil {
+		n.subscribers[taskId] = map[chan struct{}]bool{}
+	}
+	n.subscribers[taskId][ch] = true
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.subscribers[taskId], ch)
+		if len(n.subscribers[taskId]) == 0 {
+			delete(n.subscribers, taskId)
+		}
+	}
+}
+
+func (n *taskLogAppendNotifier) notify(taskId string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.subscribers[taskId] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
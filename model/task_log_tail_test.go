@@ -0,0 +1,119 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLogTailToken(t *testing.T) {
+	token, err := ParseTaskLogTailToken("")
+	require.NoError(t, err)
+	assert.Zero(t, token)
+	assert.Empty(t, token.String())
+
+	ts := time.Date(2022, 3, 1, 12, 0, 0, int(250*time.Millisecond), time.UTC)
+	token, err = ParseTaskLogTailToken(TaskLogTailToken{Timestamp: ts, LogID: "abc", Offset: 3}.String())
+	require.NoError(t, err)
+	assert.True(t, ts.Equal(token.Timestamp))
+	assert.Equal(t, "abc", token.LogID)
+	assert.Equal(t, 3, token.Offset)
+
+	for _, malformed := range []string{"abc", "abc:3", "1:abc", "1::3", "x:abc:3", "1:abc:", "1:abc:-1", "1:abc:x", "1:a:b:3"} {
+		_, err = ParseTaskLogTailToken(malformed)
+		assert.Error(t, err, malformed)
+	}
+}
+
+func TestTailTaskLogMessages(t *testing.T) {
+	require.NoError(t, cleanUpLogDB())
+	defer func() {
+		assert.NoError(t, cleanUpLogDB())
+	}()
+
+	makeMessages := func(start, n int) []apimodels.LogMessage {
+		msgs := []apimodels.LogMessage{}
+		for i := start; i < start+n; i++ {
+			msgs = append(msgs, apimodels.LogMessage{Type: apimodels.TaskLogPrefix, Message: fmt.Sprintf("line %d", i)})
+		}
+		return msgs
+	}
+	assertToken := func(t *testing.T, taskLog *TaskLog, offset int, token TaskLogTailToken) {
+		assert.True(t, taskLog.Timestamp.Equal(token.Timestamp))
+		assert.Equal(t, taskLog.Id, token.LogID)
+		assert.Equal(t, offset, token.Offset)
+	}
+	start := time.Now().Round(time.Millisecond)
+	first := &TaskLog{TaskId: "t0", Timestamp: start, Messages: makeMessages(0, 3)}
+	require.NoError(t, first.Insert())
+	require.NoError(t, (&TaskLog{TaskId: "t0", Execution: 1, Timestamp: start, Messages: makeMessages(100, 1)}).Insert())
+	require.NoError(t, (&TaskLog{TaskId: "t1", Timestamp: start, Messages: makeMessages(200, 1)}).Insert())
+
+	msgs, token, err := TailTaskLogMessages("t0", 0, TaskLogTailToken{}, 2)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "line 0", msgs[0].Message)
+	assert.Equal(t, "line 1", msgs[1].Message)
+	assertToken(t, first, 2, token)
+
+	second := &TaskLog{TaskId: "t0", Timestamp: start.Add(time.Second), Messages: makeMessages(3, 2)}
+	require.NoError(t, second.Insert())
+	require.NoError(t, first.AddLogMessage(apimodels.LogMessage{Message: "appended"}))
+	// A document sent later is read after the others even if another app
+	// server gave it an ID that sorts before theirs.
+	earlierID := &TaskLog{Id: "000000000000000000000000", TaskId: "t0", Timestamp: start.Add(2 * time.Second), Messages: makeMessages(5, 1)}
+	session, logDB, err := getSessionAndDB()
+	require.NoError(t, err)
+	defer session.Close()
+	require.NoError(t, logDB.C(TaskLogCollection).Insert(earlierID))
+
+	msgs, token, err = TailTaskLogMessages("t0", 0, token, 100)
+	require.NoError(t, err)
+	require.Len(t, msgs, 5)
+	assert.Equal(t, "line 2", msgs[0].Message)
+	assert.Equal(t, "appended", msgs[1].Message)
+	assert.Equal(t, "line 3", msgs[2].Message)
+	assert.Equal(t, "line 4", msgs[3].Message)
+	assert.Equal(t, "line 5", msgs[4].Message)
+	assertToken(t, earlierID, 1, token)
+
+	msgs, next, err := TailTaskLogMessages("t0", 0, token, 100)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+	assert.Equal(t, token, next)
+}
+
+func TestSubscribeTaskLogAppends(t *testing.T) {
+	require.NoError(t, cleanUpLogDB())
+	defer func() {
+		assert.NoError(t, cleanUpLogDB())
+	}()
+
+	appended, unsubscribe := SubscribeTaskLogAppends("t0")
+	other, unsubscribeOther := SubscribeTaskLogAppends("t1")
+	defer unsubscribeOther()
+
+	require.NoError(t, (&TaskLog{TaskId: "t0"}).Insert())
+	require.NoError(t, (&TaskLog{TaskId: "t0"}).Insert())
+	select {
+	case <-appended:
+	default:
+		assert.Fail(t, "should have been notified of the appended logs")
+	}
+	select {
+	case <-appended:
+		assert.Fail(t, "notifications should be coalesced")
+	case <-other:
+		assert.Fail(t, "should not be notified of other tasks' logs")
+	default:
+	}
+
+	unsubscribe()
+	taskLogAppends.mu.Lock()
+	assert.NotContains(t, taskLogAppends.subscribers, "t0")
+	taskLogAppends.mu.Unlock()
+}
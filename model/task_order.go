@@ -0,0 +1,182 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// TaskSortByName sorts tasks by their display names.
+	TaskSortByName = "name"
+	// TaskSortByStatus sorts failed tasks first, followed by running tasks,
+	// tasks that haven't started, and then tasks that succeeded.
+	TaskSortByStatus = "status"
+
+	// TaskOrderSourceProject and TaskOrderSourceUser identify whether a task
+	// order comes from the project's settings or from the user's override.
+	TaskOrderSourceProject = "project"
+	TaskOrderSourceUser    = "user"
+
+	// UserTaskOrdersCollection holds users' overrides of their projects'
+	// task orders.
+	UserTaskOrdersCollection = "user_task_orders"
+)
+
+// TaskOrder determines the order in which a build's tasks are displayed.
+// Pinned tasks are always displayed first, in the order they're listed, and
+// the rest are sorted by SortBy. If SortBy is not set, the rest keep the
+// build's own order.
+type TaskOrder struct {
+	// PinnedTasks are the display names of the tasks to display first.
+	PinnedTasks []string `bson:"pinned_tasks,omitempty" json:"pinned_tasks,omitempty" yaml:"pinned_tasks,omitempty"`
+	SortBy      string   `bson:"sort_by,omitempty" json:"sort_by,omitempty" yaml:"sort_by,omitempty"`
+}
+
+// IsSet returns whether the task order changes the build's own order.
+func (o TaskOrder) IsSet() bool {
+	return len(o.PinnedTasks) > 0 || o.SortBy != ""
+}
+
+// Validate checks that the task order is sensible.
+func (o TaskOrder) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	switch o.SortBy {
+	case "", TaskSortByName, TaskSortByStatus:
+	default:
+		catcher.Errorf("invalid task sort '%s', must be '%s' or '%s'", o.SortBy, TaskSortByName, TaskSortByStatus)
+	}
+	pinned := map[string]bool{}
+	for _, name := range o.PinnedTasks {
+		catcher.NewWhen(name == "", "pinned task name cannot be empty")
+		catcher.ErrorfWhen(pinned[name], "task '%s' is pinned more than once", name)
+		pinned[name] = true
+	}
+	return catcher.Resolve()
+}
+
+// Apply returns the IDs of the tasks in display order, along with the IDs of
+// the tasks that are pinned.
+func (o TaskOrder) Apply(tasks []task.Task) (ordered []string, pinned []string) {
+	pinnedPositions := map[string]int{}
+	for i, name := range o.PinnedTasks {
+		pinnedPositions[name] = i
+	}
+
+	var pinnedTasks, rest []task.Task
+	for _, t := range tasks {
+		if _, ok := pinnedPositions[t.DisplayName]; ok {
+			pinnedTasks = append(pinnedTasks, t)
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	sort.SliceStable(pinnedTasks, func(i, j int) bool {
+		return pinnedPositions[pinnedTasks[i].DisplayName] < pinnedPositions[pinnedTasks[j].DisplayName]
+	})
+	switch o.SortBy {
+	case TaskSortByName:
+		sort.SliceStable(rest, func(i, j int) bool {
+			return rest[i].DisplayName < rest[j].DisplayName
+		})
+	case TaskSortByStatus:
+		sort.SliceStable(rest, func(i, j int) bool {
+			if iRank, jRank := taskStatusRank(rest[i].Status), taskStatusRank(rest[j].Status); iRank != jRank {
+				return iRank < jRank
+			}
+			return rest[i].DisplayName < rest[j].DisplayName
+		})
+	}
+
+	ordered = make([]string, 0, len(tasks))
+	pinned = make([]string, 0, len(pinnedTasks))
+	for _, t := range pinnedTasks {
+		ordered = append(ordered, t.Id)
+		pinned = append(pinned, t.Id)
+	}
+	for _, t := range rest {
+		ordered = append(ordered, t.Id)
+	}
+	return ordered, pinned
+}
+
+func taskStatusRank(status string) int {
+	switch {
+	case evergreen.IsFailedTaskStatus(status):
+		return 0
+	case !evergreen.IsUnstartedTaskStatus(status) && !evergreen.IsFinishedTaskStatus(status):
+		return 1
+	case evergreen.IsUnstartedTaskStatus(status):
+		return 2
+	default:
+		return 3
+	}
+}
+
+// UserTaskOrder is a user's own task order for a project, which takes
+// precedence over the project's.
+type UserTaskOrder struct {
+	UserId    string    `bson:"user_id"`
+	ProjectId string    `bson:"project_id"`
+	Order     TaskOrder `bson:"order"`
+}
+
+var (
+	UserTaskOrderUserIdKey    = bsonutil.MustHaveTag(UserTaskOrder{}, "UserId")
+	UserTaskOrderProjectIdKey = bsonutil.MustHaveTag(UserTaskOrder{}, "ProjectId")
+	UserTaskOrderOrderKey     = bsonutil.MustHaveTag(UserTaskOrder{}, "Order")
+)
+
+func byUserAndProject(userId, projectId string) bson.M {
+	return bson.M{
+		UserTaskOrderUserIdKey:    userId,
+		UserTaskOrderProjectIdKey: projectId,
+	}
+}
+
+// FindUserTaskOrder returns the user's task order for the project, or nil if
+// the user hasn't overridden the project's task order.
+func FindUserTaskOrder(userId, projectId string) (*UserTaskOrder, error) {
+	order := &UserTaskOrder{}
+	err := db.FindOneQ(UserTaskOrdersCollection, db.Query(byUserAndProject(userId, projectId)), order)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return order, err
+}
+
+// Upsert saves the user's task order for the project.
+func (o *UserTaskOrder) Upsert() error {
+	_, err := db.Upsert(UserTaskOrdersCollection, byUserAndProject(o.UserId, o.ProjectId), bson.M{
+		"$set": bson.M{UserTaskOrderOrderKey: o.Order},
+	})
+	return err
+}
+
+// RemoveUserTaskOrder removes the user's task order for the project, so that
+// the project's task order applies to them again.
+func RemoveUserTaskOrder(userId, projectId string) error {
+	return db.RemoveAll(UserTaskOrdersCollection, byUserAndProject(userId, projectId))
+}
+
+// GetEffectiveTaskOrder returns the task order that applies to the user in
+// the project, and whether it comes from the user or the project.
+func GetEffectiveTaskOrder(userId string, pRef *ProjectRef) (TaskOrder, string, error) {
+	if userId != "" {
+		userOrder, err := FindUserTaskOrder(userId, pRef.Id)
+		if err != nil {
+			return TaskOrder{}, "", errors.Wrapf(err, "finding task order for user '%s'", userId)
+		}
+		if userOrder != nil {
+			return userOrder.Order, TaskOrderSourceUser, nil
+		}
+	}
+	return pRef.TaskOrder, TaskOrderSourceProject, nil
+}
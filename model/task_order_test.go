@@ -0,0 +1,95 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskOrderApply(t *testing.T) {
+	tasks := []task.Task{
+		{Id: "t1", DisplayName: "lint", Status: evergreen.TaskSucceeded},
+		{Id: "t2", DisplayName: "compile", Status: evergreen.TaskUndispatched},
+		{Id: "t3", DisplayName: "test", Status: evergreen.TaskFailed},
+		{Id: "t4", DisplayName: "docs", Status: evergreen.TaskStarted},
+		{Id: "t5", DisplayName: "smoke", Status: evergreen.TaskSucceeded},
+	}
+
+	t.Run("Unset", func(t *testing.T) {
+		ordered, pinned := TaskOrder{}.Apply(tasks)
+		assert.Equal(t, []string{"t1", "t2", "t3", "t4", "t5"}, ordered)
+		assert.Empty(t, pinned)
+	})
+	t.Run("PinnedKeepBuildOrder", func(t *testing.T) {
+		ordered, pinned := TaskOrder{PinnedTasks: []string{"smoke", "compile", "nonexistent"}}.Apply(tasks)
+		assert.Equal(t, []string{"t5", "t2", "t1", "t3", "t4"}, ordered)
+		assert.Equal(t, []string{"t5", "t2"}, pinned)
+	})
+	t.Run("SortByName", func(t *testing.T) {
+		ordered, pinned := TaskOrder{PinnedTasks: []string{"test"}, SortBy: TaskSortByName}.Apply(tasks)
+		assert.Equal(t, []string{"t3", "t2", "t4", "t1", "t5"}, ordered)
+		assert.Equal(t, []string{"t3"}, pinned)
+	})
+	t.Run("SortByStatus", func(t *testing.T) {
+		ordered, pinned := TaskOrder{SortBy: TaskSortByStatus}.Apply(tasks)
+		assert.Equal(t, []string{"t3", "t4", "t2", "t1", "t5"}, ordered)
+		assert.Empty(t, pinned)
+	})
+}
+
+func TestTaskOrderValidate(t *testing.T) {
+	assert.NoError(t, TaskOrder{}.Validate())
+	assert.NoError(t, TaskOrder{PinnedTasks: []string{"compile", "test"}, SortBy: TaskSortByStatus}.Validate())
+	assert.Error(t, TaskOrder{SortBy: "duration"}.Validate())
+	assert.Error(t, TaskOrder{PinnedTasks: []string{""}}.Validate())
+	assert.Error(t, TaskOrder{PinnedTasks: []string{"compile", "compile"}}.Validate())
+}
+
+func TestGetEffectiveTaskOrder(t *testing.T) {
+	require.NoError(t, db.ClearCollections(UserTaskOrdersCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(UserTaskOrdersCollection))
+	}()
+
+	pRef := &ProjectRef{
+		Id:        "p1",
+		TaskOrder: TaskOrder{PinnedTasks: []string{"compile"}},
+	}
+
+	order, source, err := GetEffectiveTaskOrder("me", pRef)
+	require.NoError(t, err)
+	assert.Equal(t, TaskOrderSourceProject, source)
+	assert.Equal(t, pRef.TaskOrder, order)
+
+	userOrder := UserTaskOrder{
+		UserId:    "me",
+		ProjectId: "p1",
+		Order:     TaskOrder{SortBy: TaskSortByStatus},
+	}
+	require.NoError(t, userOrder.Upsert())
+
+	order, source, err = GetEffectiveTaskOrder("me", pRef)
+	require.NoError(t, err)
+	assert.Equal(t, TaskOrderSourceUser, source)
+	assert.Equal(t, userOrder.Order, order)
+
+	order, source, err = GetEffectiveTaskOrder("someone_else", pRef)
+	require.NoError(t, err)
+	assert.Equal(t, TaskOrderSourceProject, source)
+	assert.Equal(t, pRef.TaskOrder, order)
+
+	order, source, err = GetEffectiveTaskOrder("", pRef)
+	require.NoError(t, err)
+	assert.Equal(t, TaskOrderSourceProject, source)
+	assert.Equal(t, pRef.TaskOrder, order)
+
+	require.NoError(t, RemoveUserTaskOrder("me", "p1"))
+	order, source, err = GetEffectiveTaskOrder("me", pRef)
+	require.NoError(t, err)
+	assert.Equal(t, TaskOrderSourceProject, source)
+	assert.Equal(t, pRef.TaskOrder, order)
+}
@@ -0,0 +1,201 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	TaskOutputTypeString = "string"
+	TaskOutputTypeInt    = "int"
+	TaskOutputTypeFloat  = "float"
+	TaskOutputTypeBool   = "bool"
+
+	// maxTaskOutputs is the most outputs that a task can declare.
+	maxTaskOutputs = 50
+	// maxTaskOutputValueSize is the largest value in bytes that a task can
+	// publish for one output.
+	maxTaskOutputValueSize = 4 * 1024
+
+	// taskOutputExpansionPrefix is the prefix of the expansions that expose
+	// the outputs of a task's dependencies.
+	taskOutputExpansionPrefix = "outputs"
+)
+
+var (
+	validTaskOutputTypes = []string{TaskOutputTypeString, TaskOutputTypeInt, TaskOutputTypeFloat, TaskOutputTypeBool}
+	taskOutputNameRegex  = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+)
+
+// TaskOutputDefinition declares a structured output that a task can publish
+// when it runs. Outputs are available to the task's dependent tasks as
+// expansions.
+type TaskOutputDefinition struct {
+	Name string `yaml:"name" bson:"name"`
+	// Type is the type of the output's value. It defaults to a string.
+	Type string `yaml:"type,omitempty" bson:"type,omitempty"`
+	// Required outputs must be published by the time the task finishes, or
+	// else the task fails.
+	Required bool `yaml:"required,omitempty" bson:"required,omitempty"`
+}
+
+// GetType returns the type of the output's value.
+func (d TaskOutputDefinition) GetType() string {
+	if d.Type == "" {
+		return TaskOutputTypeString
+	}
+	return d.Type
+}
+
+// ValidateTaskOutputDefinitions checks that a task's output declarations are
+// well-formed.
+func ValidateTaskOutputDefinitions(defs []TaskOutputDefinition) error {
+	catcher := grip.NewBasicCatcher()
+	catcher.ErrorfWhen(len(defs) > maxTaskOutputs, "cannot declare more than %d outputs", maxTaskOutputs)
+	names := map[string]bool{}
+	for _, def := range defs {
+		catcher.ErrorfWhen(!taskOutputNameRegex.MatchString(def.Name), "output name '%s' must only contain letters, numbers, underscores, and dashes", def.Name)
+		catcher.ErrorfWhen(names[def.Name], "output '%s' is declared more than once", def.Name)
+		names[def.Name] = true
+		catcher.ErrorfWhen(!isValidTaskOutputType(def.GetType()), "output '%s' has invalid type '%s', must be one of %v", def.Name, def.Type, validTaskOutputTypes)
+	}
+	return catcher.Resolve()
+}
+
+func isValidTaskOutputType(outputType string) bool {
+	for _, t := range validTaskOutputTypes {
+		if outputType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTaskOutputs checks that the outputs that a task published match the
+// task's output declarations.
+func ValidateTaskOutputs(defs []TaskOutputDefinition, outputs map[string]string) error {
+	catcher := grip.NewBasicCatcher()
+	defsByName := map[string]TaskOutputDefinition{}
+	for _, def := range defs {
+		defsByName[def.Name] = def
+	}
+	for name, value := range outputs {
+		def, ok := defsByName[name]
+		if !ok {
+			catcher.Errorf("output '%s' is not declared by the task", name)
+			continue
+		}
+		if len(value) > maxTaskOutputValueSize {
+			catcher.Errorf("output '%s' is %d bytes, which exceeds the maximum of %d bytes", name, len(value), maxTaskOutputValueSize)
+			continue
+		}
+		catcher.Wrapf(checkTaskOutputType(def.GetType(), value), "output '%s'", name)
+	}
+	return catcher.Resolve()
+}
+
+func checkTaskOutputType(outputType, value string) error {
+	var err error
+	switch outputType {
+	case TaskOutputTypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case TaskOutputTypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case TaskOutputTypeBool:
+		_, err = strconv.ParseBool(value)
+	}
+	return errors.Wrapf(err, "value '%s' is not of type '%s'", value, outputType)
+}
+
+// RequiredTaskOutputs returns the names of the outputs that must be published.
+func RequiredTaskOutputs(defs []TaskOutputDefinition) []string {
+	var required []string
+	for _, def := range defs {
+		if def.Required {
+			required = append(required, def.Name)
+		}
+	}
+	return required
+}
+
+// FindMissingRequiredOutputs returns the task's required outputs that it has
+// not published.
+func FindMissingRequiredOutputs(t *task.Task) []string {
+	var missing []string
+	for _, name := range t.RequiredOutputs {
+		if t.Outputs[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// SetTaskOutputs validates the outputs that the task published against the
+// task's output declarations in its version's project and stores them on the
+// task.
+func SetTaskOutputs(t *task.Task, outputs map[string]string) error {
+	v, err := VersionFindOneId(t.Version)
+	if err != nil {
+		return errors.Wrapf(err, "finding version '%s'", t.Version)
+	}
+	if v == nil {
+		return errors.Errorf("version '%s' not found", t.Version)
+	}
+	project, err := LoadProjectProjectionForVersion(v, t.Project, ProjectProjectionTasks)
+	if err != nil {
+		return errors.Wrapf(err, "loading project for version '%s'", t.Version)
+	}
+	spec := project.GetSpecForTask(t.DisplayName)
+	if err = ValidateTaskOutputs(spec.Outputs, outputs); err != nil {
+		return InvalidTaskOutputsError{Err: err}
+	}
+	return errors.Wrapf(t.SetOutputs(outputs), "setting outputs for task '%s'", t.Id)
+}
+
+// InvalidTaskOutputsError is returned when published task outputs don't match
+// the task's output declarations.
+type InvalidTaskOutputsError struct {
+	Err error
+}
+
+func (e InvalidTaskOutputsError) Error() string {
+	return fmt.Sprintf("invalid task outputs: %s", e.Err)
+}
+
+// dependencyOutputExpansions returns the outputs that the task's dependencies
+// published as expansions. Every output is available as
+// "outputs.<variant>.<task>.<output>", and outputs of dependencies in the
+// task's own build variant are also available as "outputs.<task>.<output>".
+func dependencyOutputExpansions(t *task.Task) (util.Expansions, error) {
+	expansions := util.Expansions{}
+	if len(t.DependsOn) == 0 {
+		return expansions, nil
+	}
+	depIDs := make([]string, 0, len(t.DependsOn))
+	for _, dep := range t.DependsOn {
+		depIDs = append(depIDs, dep.TaskId)
+	}
+	deps, err := task.FindWithFields(bson.M{
+		task.IdKey:      bson.M{"$in": depIDs},
+		task.OutputsKey: bson.M{"$exists": true},
+	}, task.DisplayNameKey, task.BuildVariantKey, task.OutputsKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding dependencies' outputs")
+	}
+	for _, dep := range deps {
+		for name, value := range dep.Outputs {
+			expansions.Put(fmt.Sprintf("%s.%s.%s.%s", taskOutputExpansionPrefix, dep.BuildVariant, dep.DisplayName, name), value)
+			if dep.BuildVariant == t.BuildVariant {
+				expansions.Put(fmt.Sprintf("%s.%s.%s", taskOutputExpansionPrefix, dep.DisplayName, name), value)
+			}
+		}
+	}
+	return expansions, nil
+}
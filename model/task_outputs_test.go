@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTaskOutputDefinitions(t *testing.T) {
+	assert.NoError(t, ValidateTaskOutputDefinitions(nil))
+	assert.NoError(t, ValidateTaskOutputDefinitions([]TaskOutputDefinition{
+		{Name: "binary_path"},
+		{Name: "test-count", Type: TaskOutputTypeInt, Required: true},
+	}))
+	assert.Error(t, ValidateTaskOutputDefinitions([]TaskOutputDefinition{{Name: "has.dots"}}))
+	assert.Error(t, ValidateTaskOutputDefinitions([]TaskOutputDefinition{{Name: "output", Type: "object"}}))
+	assert.Error(t, ValidateTaskOutputDefinitions([]TaskOutputDefinition{{Name: "output"}, {Name: "output"}}))
+}
+
+func TestValidateTaskOutputs(t *testing.T) {
+	defs := []TaskOutputDefinition{
+		{Name: "binary_path", Required: true},
+		{Name: "count", Type: TaskOutputTypeInt},
+		{Name: "ratio", Type: TaskOutputTypeFloat},
+		{Name: "passed", Type: TaskOutputTypeBool},
+	}
+	assert.NoError(t, ValidateTaskOutputs(defs, map[string]string{
+		"binary_path": "/bin/evergreen",
+		"count":       "3",
+		"ratio":       "0.5",
+		"passed":      "true",
+	}))
+	assert.NoError(t, ValidateTaskOutputs(defs, map[string]string{"count": "3"}), "required outputs can be published separately")
+	assert.Error(t, ValidateTaskOutputs(defs, map[string]string{"binary_path": "/bin", "count": "three"}), "wrong type")
+	assert.Error(t, ValidateTaskOutputs(defs, map[string]string{"binary_path": "/bin", "undeclared": "value"}), "undeclared output")
+	assert.Error(t, ValidateTaskOutputs(defs, map[string]string{"binary_path": string(make([]byte, maxTaskOutputValueSize+1))}), "value too large")
+}
+
+func TestFindMissingRequiredOutputs(t *testing.T) {
+	required := RequiredTaskOutputs([]TaskOutputDefinition{
+		{Name: "binary_path", Required: true},
+		{Name: "count", Type: TaskOutputTypeInt},
+		{Name: "version", Required: true},
+	})
+	assert.Equal(t, []string{"binary_path", "version"}, required)
+
+	tsk := &task.Task{RequiredOutputs: required, Outputs: map[string]string{"binary_path": "/bin/evergreen", "count": "3"}}
+	assert.Equal(t, []string{"version"}, FindMissingRequiredOutputs(tsk))
+	tsk.Outputs["version"] = "1.0"
+	assert.Empty(t, FindMissingRequiredOutputs(tsk))
+}
+
+func TestDependencyOutputExpansions(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection))
+	}()
+
+	tasks := []task.Task{
+		{Id: "compile", DisplayName: "compile", BuildVariant: "ubuntu", Outputs: map[string]string{"binary_path": "/bin/evergreen"}},
+		{Id: "other_compile", DisplayName: "compile", BuildVariant: "windows", Outputs: map[string]string{"binary_path": "C:/evergreen.exe"}},
+		{Id: "lint", DisplayName: "lint", BuildVariant: "ubuntu"},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+	}
+	dependent := &task.Task{
+		Id:           "test",
+		BuildVariant: "ubuntu",
+		DependsOn: []task.Dependency{
+			{TaskId: "compile"},
+			{TaskId: "other_compile"},
+			{TaskId: "lint"},
+		},
+	}
+
+	expansions, err := dependencyOutputExpansions(dependent)
+	require.NoError(t, err)
+	assert.Equal(t, "/bin/evergreen", expansions.Get("outputs.compile.binary_path"))
+	assert.Equal(t, "/bin/evergreen", expansions.Get("outputs.ubuntu.compile.binary_path"))
+	assert.Equal(t, "C:/evergreen.exe", expansions.Get("outputs.windows.compile.binary_path"))
+	assert.Len(t, expansions, 3)
+}
@@ -0,0 +1,231 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// TaskQuarantineCollection stores the tasks that are quarantined in each
+	// project.
+	TaskQuarantineCollection = "task_quarantines"
+
+	// MaxTaskQuarantineDuration is the longest that a task can be quarantined
+	// before its quarantine must be renewed.
+	MaxTaskQuarantineDuration = 90 * 24 * time.Hour
+	// TaskQuarantineExpiryWarning is how long before a quarantine expires that
+	// its creator and the project admins are warned.
+	TaskQuarantineExpiryWarning = 3 * 24 * time.Hour
+)
+
+// TaskQuarantine permanently disables a task in a build variant until the
+// quarantine expires. Quarantined tasks are never scheduled; they are skipped
+// instead.
+type TaskQuarantine struct {
+	Id           string    `bson:"_id" json:"id"`
+	ProjectId    string    `bson:"project_id" json:"project_id"`
+	BuildVariant string    `bson:"build_variant" json:"build_variant"`
+	TaskName     string    `bson:"task_name" json:"task_name"`
+	Reason       string    `bson:"reason" json:"reason"`
+	Expires      time.Time `bson:"expires" json:"expires"`
+	CreatedBy    string    `bson:"created_by" json:"created_by"`
+	CreateTime   time.Time `bson:"create_time" json:"create_time"`
+	// ExpiryWarningSent is whether the warning that the quarantine will soon
+	// expire has been sent.
+	ExpiryWarningSent bool `bson:"expiry_warning_sent,omitempty" json:"expiry_warning_sent,omitempty"`
+}
+
+var (
+	taskQuarantineIdKey                = bsonutil.MustHaveTag(TaskQuarantine{}, "Id")
+	taskQuarantineProjectIdKey         = bsonutil.MustHaveTag(TaskQuarantine{}, "ProjectId")
+	taskQuarantineBuildVariantKey      = bsonutil.MustHaveTag(TaskQuarantine{}, "BuildVariant")
+	taskQuarantineTaskNameKey          = bsonutil.MustHaveTag(TaskQuarantine{}, "TaskName")
+	taskQuarantineReasonKey            = bsonutil.MustHaveTag(TaskQuarantine{}, "Reason")
+	taskQuarantineExpiresKey           = bsonutil.MustHaveTag(TaskQuarantine{}, "Expires")
+	taskQuarantineCreatedByKey         = bsonutil.MustHaveTag(TaskQuarantine{}, "CreatedBy")
+	taskQuarantineCreateTimeKey        = bsonutil.MustHaveTag(TaskQuarantine{}, "CreateTime")
+	taskQuarantineExpiryWarningSentKey = bsonutil.MustHaveTag(TaskQuarantine{}, "ExpiryWarningSent")
+)
+
+// Validate checks that the quarantine identifies a task, explains why the task
+// is quarantined, and expires within the maximum quarantine duration.
+func (q *TaskQuarantine) Validate(now time.Time) error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(q.BuildVariant == "", "must specify the build variant of the quarantined task")
+	catcher.NewWhen(q.TaskName == "", "must specify the name of the quarantined task")
+	catcher.NewWhen(q.Reason == "", "must specify a reason for quarantining the task")
+	catcher.NewWhen(!q.Expires.After(now), "quarantine must expire in the future")
+	catcher.ErrorfWhen(q.Expires.After(now.Add(MaxTaskQuarantineDuration)), "quarantine cannot last longer than %s", MaxTaskQuarantineDuration)
+	return catcher.Resolve()
+}
+
+// IsActive returns whether the quarantine has not yet expired.
+func (q *TaskQuarantine) IsActive(now time.Time) bool {
+	return q.Expires.After(now)
+}
+
+// SkipReason returns the reason recorded on tasks that are skipped because
+// they are quarantined.
+func (q *TaskQuarantine) SkipReason() string {
+	return fmt.Sprintf("quarantined until %s: %s", q.Expires.UTC().Format(time.RFC3339), q.Reason)
+}
+
+// SetExpiryWarningSent records that the warning that the quarantine will soon
+// expire has been sent.
+func (q *TaskQuarantine) SetExpiryWarningSent() error {
+	err := db.Update(
+		TaskQuarantineCollection,
+		bson.M{taskQuarantineIdKey: q.Id},
+		bson.M{"$set": bson.M{taskQuarantineExpiryWarningSentKey: true}},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "recording expiry warning for task quarantine '%s'", q.Id)
+	}
+	q.ExpiryWarningSent = true
+	return nil
+}
+
+// QuarantineTask quarantines the task in the project's build variant, or
+// replaces the reason and expiration of its existing quarantine. It returns
+// the quarantine as stored.
+func QuarantineTask(q TaskQuarantine) (*TaskQuarantine, error) {
+	if q.CreateTime.IsZero() {
+		q.CreateTime = time.Now()
+	}
+	if err := q.Validate(q.CreateTime); err != nil {
+		return nil, errors.Wrap(err, "invalid task quarantine")
+	}
+
+	_, err := db.Upsert(
+		TaskQuarantineCollection,
+		byTaskQuarantineTask(q.ProjectId, q.BuildVariant, q.TaskName),
+		bson.M{
+			"$set": bson.M{
+				taskQuarantineReasonKey:            q.Reason,
+				taskQuarantineExpiresKey:           q.Expires,
+				taskQuarantineCreatedByKey:         q.CreatedBy,
+				taskQuarantineCreateTimeKey:        q.CreateTime,
+				taskQuarantineExpiryWarningSentKey: false,
+			},
+			"$setOnInsert": bson.M{
+				taskQuarantineIdKey: utility.RandomString(),
+			},
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "quarantining task '%s' in build variant '%s'", q.TaskName, q.BuildVariant)
+	}
+	return FindTaskQuarantine(q.ProjectId, q.BuildVariant, q.TaskName)
+}
+
+// RemoveTaskQuarantine lifts the quarantine of the task in the project's build
+// variant.
+func RemoveTaskQuarantine(projectId, buildVariant, taskName string) error {
+	return errors.Wrapf(db.Remove(TaskQuarantineCollection, byTaskQuarantineTask(projectId, buildVariant, taskName)),
+		"removing quarantine of task '%s' in build variant '%s'", taskName, buildVariant)
+}
+
+// FindTaskQuarantine returns the quarantine of the task in the project's build
+// variant, or nil if it is not quarantined. The quarantine may have expired.
+func FindTaskQuarantine(projectId, buildVariant, taskName string) (*TaskQuarantine, error) {
+	q := &TaskQuarantine{}
+	err := db.FindOneQ(TaskQuarantineCollection, db.Query(byTaskQuarantineTask(projectId, buildVariant, taskName)), q)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding quarantine of task '%s' in build variant '%s'", taskName, buildVariant)
+	}
+	return q, nil
+}
+
+// FindActiveTaskQuarantines returns the project's quarantines that have not
+// expired.
+func FindActiveTaskQuarantines(projectId string, now time.Time) ([]TaskQuarantine, error) {
+	quarantines := []TaskQuarantine{}
+	err := db.FindAllQ(TaskQuarantineCollection, db.Query(bson.M{
+		taskQuarantineProjectIdKey: projectId,
+		taskQuarantineExpiresKey:   bson.M{"$gt": now},
+	}).Sort([]string{taskQuarantineBuildVariantKey, taskQuarantineTaskNameKey}), &quarantines)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding task quarantines for project '%s'", projectId)
+	}
+	return quarantines, nil
+}
+
+// FindTaskQuarantinesToWarn returns the quarantines in all projects that will
+// expire within the expiry warning time and have not been warned about yet.
+func FindTaskQuarantinesToWarn(now time.Time) ([]TaskQuarantine, error) {
+	quarantines := []TaskQuarantine{}
+	err := db.FindAllQ(TaskQuarantineCollection, db.Query(bson.M{
+		taskQuarantineExpiresKey: bson.M{
+			"$gt":  now,
+			"$lte": now.Add(TaskQuarantineExpiryWarning),
+		},
+		taskQuarantineExpiryWarningSentKey: bson.M{"$ne": true},
+	}), &quarantines)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding task quarantines that will soon expire")
+	}
+	return quarantines, nil
+}
+
+// SkipQuarantinedTasks skips the tasks that are quarantined in their projects
+// and returns the rest of the tasks, even if it returns an error.
+func SkipQuarantinedTasks(tasks []task.Task) ([]task.Task, error) {
+	now := time.Now()
+	quarantinesByProject := map[string]map[TVPair]TaskQuarantine{}
+	remaining := make([]task.Task, 0, len(tasks))
+	catcher := grip.NewBasicCatcher()
+	for _, t := range tasks {
+		quarantines, ok := quarantinesByProject[t.Project]
+		if !ok {
+			// Tasks are scheduled as usual if their project's quarantines
+			// can't be checked.
+			projectQuarantines, err := FindActiveTaskQuarantines(t.Project, now)
+			catcher.Add(err)
+			quarantines = map[TVPair]TaskQuarantine{}
+			for _, q := range projectQuarantines {
+				quarantines[TVPair{Variant: q.BuildVariant, TaskName: q.TaskName}] = q
+			}
+			quarantinesByProject[t.Project] = quarantines
+		}
+
+		q, ok := quarantines[TVPair{Variant: t.BuildVariant, TaskName: t.DisplayName}]
+		if !ok {
+			remaining = append(remaining, t)
+			continue
+		}
+		if err := SkipTask(t.Id, q.SkipReason(), evergreen.TaskQuarantineUser); err != nil {
+			catcher.Wrapf(err, "skipping quarantined task '%s'", t.Id)
+			continue
+		}
+		grip.Info(message.Fields{
+			"message":       "skipped quarantined task",
+			"task_id":       t.Id,
+			"project":       t.Project,
+			"build_variant": t.BuildVariant,
+			"quarantine_id": q.Id,
+		})
+	}
+	return remaining, catcher.Resolve()
+}
+
+func byTaskQuarantineTask(projectId, buildVariant, taskName string) bson.M {
+	return bson.M{
+		taskQuarantineProjectIdKey:    projectId,
+		taskQuarantineBuildVariantKey: buildVariant,
+		taskQuarantineTaskNameKey:     taskName,
+	}
+}
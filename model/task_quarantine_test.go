@@ -0,0 +1,170 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskQuarantineValidate(t *testing.T) {
+	now := time.Now()
+	valid := TaskQuarantine{
+		ProjectId:    "project",
+		BuildVariant: "bv",
+		TaskName:     "flaky",
+		Reason:       "fails every other run",
+		Expires:      now.Add(24 * time.Hour),
+	}
+	assert.NoError(t, valid.Validate(now))
+
+	for name, modify := range map[string]func(q *TaskQuarantine){
+		"MissingBuildVariant": func(q *TaskQuarantine) { q.BuildVariant = "" },
+		"MissingTaskName":     func(q *TaskQuarantine) { q.TaskName = "" },
+		"MissingReason":       func(q *TaskQuarantine) { q.Reason = "" },
+		"AlreadyExpired":      func(q *TaskQuarantine) { q.Expires = now.Add(-time.Minute) },
+		"ExceedsMaxDuration":  func(q *TaskQuarantine) { q.Expires = now.Add(MaxTaskQuarantineDuration + time.Hour) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			q := valid
+			modify(&q)
+			assert.Error(t, q.Validate(now))
+		})
+	}
+}
+
+func TestTaskQuarantines(t *testing.T) {
+	require.NoError(t, db.ClearCollections(TaskQuarantineCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(TaskQuarantineCollection))
+	}()
+
+	now := time.Now()
+	q, err := QuarantineTask(TaskQuarantine{
+		ProjectId:    "project",
+		BuildVariant: "bv",
+		TaskName:     "flaky",
+		Reason:       "fails every other run",
+		Expires:      now.Add(2 * time.Hour),
+		CreatedBy:    "me",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, q)
+	assert.NotEmpty(t, q.Id)
+	assert.Equal(t, "fails every other run", q.Reason)
+
+	t.Run("RenewingKeepsId", func(t *testing.T) {
+		renewed, err := QuarantineTask(TaskQuarantine{
+			ProjectId:    "project",
+			BuildVariant: "bv",
+			TaskName:     "flaky",
+			Reason:       "still flaky",
+			Expires:      now.Add(10 * 24 * time.Hour),
+			CreatedBy:    "you",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, renewed)
+		assert.Equal(t, q.Id, renewed.Id)
+		assert.Equal(t, "still flaky", renewed.Reason)
+		assert.Equal(t, "you", renewed.CreatedBy)
+
+		quarantines, err := FindActiveTaskQuarantines("project", now)
+		require.NoError(t, err)
+		assert.Len(t, quarantines, 1)
+	})
+	t.Run("InvalidQuarantineErrors", func(t *testing.T) {
+		_, err := QuarantineTask(TaskQuarantine{ProjectId: "project", BuildVariant: "bv", TaskName: "other"})
+		assert.Error(t, err)
+	})
+	t.Run("FindActiveExcludesExpiredAndOtherProjects", func(t *testing.T) {
+		expired, err := QuarantineTask(TaskQuarantine{
+			ProjectId:    "project",
+			BuildVariant: "bv",
+			TaskName:     "expired",
+			Reason:       "reason",
+			Expires:      now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		_, err = QuarantineTask(TaskQuarantine{
+			ProjectId:    "other_project",
+			BuildVariant: "bv",
+			TaskName:     "flaky",
+			Reason:       "reason",
+			Expires:      now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+
+		quarantines, err := FindActiveTaskQuarantines("project", now.Add(90*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, quarantines, 1)
+		assert.Equal(t, "flaky", quarantines[0].TaskName)
+
+		require.NoError(t, RemoveTaskQuarantine(expired.ProjectId, expired.BuildVariant, expired.TaskName))
+		dbQuarantine, err := FindTaskQuarantine(expired.ProjectId, expired.BuildVariant, expired.TaskName)
+		require.NoError(t, err)
+		assert.Nil(t, dbQuarantine)
+	})
+	t.Run("FindToWarn", func(t *testing.T) {
+		toWarn, err := FindTaskQuarantinesToWarn(now.Add(8 * 24 * time.Hour))
+		require.NoError(t, err)
+		require.Len(t, toWarn, 1)
+		assert.Equal(t, q.Id, toWarn[0].Id)
+
+		require.NoError(t, toWarn[0].SetExpiryWarningSent())
+		assert.True(t, toWarn[0].ExpiryWarningSent)
+		toWarn, err = FindTaskQuarantinesToWarn(now.Add(8 * 24 * time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, toWarn)
+	})
+}
+
+func TestSkipQuarantinedTasks(t *testing.T) {
+	require.NoError(t, db.ClearCollections(TaskQuarantineCollection, task.Collection, build.Collection, VersionCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(TaskQuarantineCollection, task.Collection, build.Collection, VersionCollection))
+	}()
+
+	_, err := QuarantineTask(TaskQuarantine{
+		ProjectId:    "project",
+		BuildVariant: "bv",
+		TaskName:     "flaky",
+		Reason:       "fails every other run",
+		Expires:      time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	v := &Version{Id: "v", BuildIds: []string{"b"}}
+	require.NoError(t, v.Insert())
+	b := &build.Build{Id: "b", Version: v.Id, Status: evergreen.BuildCreated}
+	require.NoError(t, b.Insert())
+	tasks := []task.Task{
+		{Id: "quarantined", Project: "project", BuildVariant: "bv", DisplayName: "flaky", BuildId: b.Id, Version: v.Id, Status: evergreen.TaskUndispatched},
+		{Id: "other_variant", Project: "project", BuildVariant: "bv2", DisplayName: "flaky", BuildId: b.Id, Version: v.Id, Status: evergreen.TaskUndispatched},
+		{Id: "other_project", Project: "other", BuildVariant: "bv", DisplayName: "flaky", BuildId: b.Id, Version: v.Id, Status: evergreen.TaskUndispatched},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+	}
+
+	remaining, err := SkipQuarantinedTasks(tasks)
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	assert.Equal(t, "other_variant", remaining[0].Id)
+	assert.Equal(t, "other_project", remaining[1].Id)
+
+	dbTask, err := task.FindOneId("quarantined")
+	require.NoError(t, err)
+	require.NotNil(t, dbTask)
+	assert.Equal(t, evergreen.TaskSkipped, dbTask.Status)
+	assert.Contains(t, dbTask.SkipReason, "fails every other run")
+
+	dbTask, err = task.FindOneId("other_variant")
+	require.NoError(t, err)
+	require.NotNil(t, dbTask)
+	assert.Equal(t, evergreen.TaskUndispatched, dbTask.Status)
+}
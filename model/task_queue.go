@@ -602,6 +602,24 @@ func FindAllTaskQueues() ([]TaskQueue, error) {
 	return taskQueues, err
 }
 
+// FindTaskQueueLengths returns the number of tasks in the task queue of each
+// of the given distros. Distros without a task queue are omitted.
+func FindTaskQueueLengths(distroIDs []string) (map[string]int, error) {
+	taskQueues := []TaskQueue{}
+	q := db.Query(bson.M{taskQueueDistroKey: bson.M{"$in": distroIDs}}).Project(bson.M{
+		taskQueueDistroKey:          1,
+		taskQueueDistroQueueInfoKey: 1,
+	})
+	if err := db.FindAllQ(TaskQueuesCollection, q, &taskQueues); err != nil {
+		return nil, errors.Wrap(err, "finding task queues")
+	}
+	lengths := map[string]int{}
+	for _, queue := range taskQueues {
+		lengths[queue.Distro] = queue.DistroQueueInfo.Length
+	}
+	return lengths, nil
+}
+
 func FindDistroTaskQueue(distroID string) (TaskQueue, error) {
 	queue := TaskQueue{}
 	err := db.FindOneQ(TaskQueuesCollection, db.Query(bson.M{taskQueueDistroKey: distroID}), &queue)
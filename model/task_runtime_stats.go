@@ -0,0 +1,62 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// maxTaskRuntimeStatsTasks is the most recently finished tasks whose runtimes
+// are summarized, to bound the cost of checking a large project.
+const maxTaskRuntimeStatsTasks = 10000
+
+// TaskRuntimeStats summarizes how long a task recently took to run on a build
+// variant.
+type TaskRuntimeStats struct {
+	// NumTasks is the number of successful runs that the stats are based on.
+	NumTasks int
+	P95      time.Duration
+}
+
+// GetRecentTaskRuntimeStats summarizes the runtimes of the project's tasks that
+// succeeded within the window, keyed by build variant and task name. Failed
+// tasks are ignored since they may have stopped early or timed out.
+func GetRecentTaskRuntimeStats(projectID string, window time.Duration) (map[TVPair]TaskRuntimeStats, error) {
+	q := db.Query(bson.M{
+		task.ProjectKey:     projectID,
+		task.StatusKey:      evergreen.TaskSucceeded,
+		task.FinishTimeKey:  bson.M{"$gte": time.Now().Add(-window)},
+		task.DisplayOnlyKey: bson.M{"$ne": true},
+	}).
+		WithFields(task.DisplayNameKey, task.BuildVariantKey, task.TimeTakenKey).
+		Sort([]string{"-" + task.FinishTimeKey}).
+		Limit(maxTaskRuntimeStatsTasks)
+	tasks, err := task.FindAll(q)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding recently succeeded tasks for project '%s'", projectID)
+	}
+
+	runtimes := map[TVPair][]time.Duration{}
+	for _, t := range tasks {
+		if t.TimeTaken <= 0 {
+			continue
+		}
+		tv := TVPair{Variant: t.BuildVariant, TaskName: t.DisplayName}
+		runtimes[tv] = append(runtimes[tv], t.TimeTaken)
+	}
+
+	stats := make(map[TVPair]TaskRuntimeStats, len(runtimes))
+	for tv, durations := range runtimes {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		stats[tv] = TaskRuntimeStats{
+			NumTasks: len(durations),
+			P95:      latencyPercentile(durations, 95),
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,212 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Expansions that pass a task's trace context to the agent, so that commands
+// can record their own spans as children of the task's span.
+const (
+	TraceIDExpansion     = "otel_trace_id"
+	ParentSpanExpansion  = "otel_parent_id"
+	TraceparentExpansion = "otel_traceparent"
+)
+
+// A version's spans are recorded as one trace whose root is the version's
+// span. Each build's span is a child of its version's span, and each task
+// execution's span is a child of its build's span, with a child span for each
+// phase of the task's lifecycle. The spans are recorded when each entity
+// finishes, using IDs derived from the entities' IDs, so they can be recorded
+// by different app servers.
+
+// taskSpanID returns the span ID for the task's execution.
+func taskSpanID(taskID string, execution int) trace.SpanID {
+	return evergreen.SpanIDFor(task.MakeOldID(taskID, execution))
+}
+
+// remoteParentContext returns a context whose parent span is the span for the
+// given entity in the version's trace.
+func remoteParentContext(ctx context.Context, versionID, parentID string) context.Context {
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    evergreen.TraceIDFor(versionID),
+		SpanID:     evergreen.SpanIDFor(parentID),
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+}
+
+// taskTraceExpansions returns the expansions for the task's trace context. The
+// traceparent is in the W3C Trace Context format.
+func taskTraceExpansions(t *task.Task) map[string]string {
+	traceID := evergreen.TraceIDFor(t.Version)
+	spanID := taskSpanID(t.Id, t.Execution)
+	return map[string]string{
+		TraceIDExpansion:     traceID.String(),
+		ParentSpanExpansion:  spanID.String(),
+		TraceparentExpansion: fmt.Sprintf("00-%s-%s-01", traceID, spanID),
+	}
+}
+
+func taskSpanAttributes(t *task.Task) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String(evergreen.TaskIDOtelAttribute, t.Id),
+		attribute.Int(evergreen.TaskExecutionOtelAttribute, t.Execution),
+		attribute.String(evergreen.TaskNameOtelAttribute, t.DisplayName),
+		attribute.String(evergreen.TaskStatusOtelAttribute, t.GetDisplayStatus()),
+		attribute.String(evergreen.TaskDistroOtelAttribute, t.DistroId),
+		attribute.String(evergreen.TaskHostOtelAttribute, t.HostId),
+		attribute.String(evergreen.BuildIDOtelAttribute, t.BuildId),
+		attribute.String(evergreen.BuildVariantOtelAttribute, t.BuildVariant),
+		attribute.String(evergreen.VersionIDOtelAttribute, t.Version),
+		attribute.String(evergreen.ProjectIdentifierOtelAttribute, t.Project),
+	}
+}
+
+// previousExecutionLink links a task execution's span to the span of the
+// execution before it, if there is one.
+func previousExecutionLink(t *task.Task) []trace.Link {
+	if t.Execution == 0 {
+		return nil
+	}
+	return []trace.Link{{
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    evergreen.TraceIDFor(t.Version),
+			SpanID:     taskSpanID(t.Id, t.Execution-1),
+			TraceFlags: trace.FlagsSampled,
+		}),
+		Attributes: []attribute.KeyValue{attribute.Bool(evergreen.TaskResetOtelAttribute, true)},
+	}}
+}
+
+// taskPhase is a part of a task's lifecycle, between two of its timestamps.
+type taskPhase struct {
+	name       string
+	start, end time.Time
+}
+
+func taskPhases(t *task.Task) []taskPhase {
+	return []taskPhase{
+		{name: "task.created", start: t.CreateTime, end: t.ActivatedTime},
+		{name: "task.queued", start: t.ActivatedTime, end: t.DispatchTime},
+		{name: "task.dispatched", start: t.DispatchTime, end: t.StartTime},
+		{name: "task.started", start: t.StartTime, end: t.FinishTime},
+	}
+}
+
+// firstTime returns the earliest of the times that are set.
+func firstTime(times ...time.Time) time.Time {
+	var first time.Time
+	for _, t := range times {
+		if !isTraceTimeSet(t) {
+			continue
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+	}
+	return first
+}
+
+func isTraceTimeSet(t time.Time) bool {
+	return !t.IsZero() && t.After(time.Unix(0, 0))
+}
+
+// traceTaskFinished records the spans for the task's execution, which just
+// finished.
+func traceTaskFinished(t *task.Task) {
+	if !isTraceTimeSet(t.FinishTime) {
+		return
+	}
+	start := firstTime(t.CreateTime, t.ActivatedTime, t.DispatchTime, t.StartTime, t.FinishTime)
+	ctx := remoteParentContext(context.Background(), t.Version, t.BuildId)
+	ctx = evergreen.WithSpanIDs(ctx, evergreen.TraceIDFor(t.Version), taskSpanID(t.Id, t.Execution))
+	ctx, span := tracer.Start(ctx, "task",
+		trace.WithTimestamp(start),
+		trace.WithAttributes(taskSpanAttributes(t)...),
+		trace.WithLinks(previousExecutionLink(t)...),
+	)
+
+	for _, phase := range taskPhases(t) {
+		if !isTraceTimeSet(phase.start) || !isTraceTimeSet(phase.end) || phase.end.Before(phase.start) {
+			continue
+		}
+		_, phaseSpan := tracer.Start(ctx, phase.name, trace.WithTimestamp(phase.start))
+		phaseSpan.End(trace.WithTimestamp(phase.end))
+	}
+
+	if evergreen.IsFailedTaskStatus(t.Status) {
+		span.SetStatus(codes.Error, t.Details.Description)
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End(trace.WithTimestamp(t.FinishTime))
+}
+
+// traceTaskReset records that the task's current execution is being reset. The
+// span links to the span of the execution being reset.
+func traceTaskReset(t *task.Task) {
+	now := time.Now()
+	ctx := remoteParentContext(context.Background(), t.Version, t.BuildId)
+	_, span := tracer.Start(ctx, "task.reset",
+		trace.WithTimestamp(now),
+		trace.WithAttributes(taskSpanAttributes(t)...),
+		trace.WithLinks(trace.Link{
+			SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    evergreen.TraceIDFor(t.Version),
+				SpanID:     taskSpanID(t.Id, t.Execution),
+				TraceFlags: trace.FlagsSampled,
+			}),
+			Attributes: []attribute.KeyValue{attribute.Bool(evergreen.TaskResetOtelAttribute, true)},
+		}),
+	)
+	span.End(trace.WithTimestamp(now))
+}
+
+// traceBuildFinished records the span for the build, which just finished.
+func traceBuildFinished(b *build.Build) {
+	ctx := remoteParentContext(context.Background(), b.Version, b.Version)
+	ctx = evergreen.WithSpanIDs(ctx, evergreen.TraceIDFor(b.Version), evergreen.SpanIDFor(b.Id))
+	_, span := tracer.Start(ctx, "build",
+		trace.WithTimestamp(firstTime(b.CreateTime, b.StartTime, b.FinishTime)),
+		trace.WithAttributes(
+			attribute.String(evergreen.BuildIDOtelAttribute, b.Id),
+			attribute.String(evergreen.BuildVariantOtelAttribute, b.BuildVariant),
+			attribute.String(evergreen.BuildStatusOtelAttribute, b.Status),
+			attribute.String(evergreen.VersionIDOtelAttribute, b.Version),
+			attribute.String(evergreen.ProjectIdentifierOtelAttribute, b.Project),
+		),
+	)
+	if b.Status == evergreen.BuildFailed {
+		span.SetStatus(codes.Error, "")
+	}
+	span.End(trace.WithTimestamp(b.FinishTime))
+}
+
+// traceVersionFinished records the root span for the version, which just
+// finished.
+func traceVersionFinished(v *Version) {
+	ctx := evergreen.WithSpanIDs(context.Background(), evergreen.TraceIDFor(v.Id), evergreen.SpanIDFor(v.Id))
+	_, span := tracer.Start(ctx, "version",
+		trace.WithNewRoot(),
+		trace.WithTimestamp(firstTime(v.CreateTime, v.StartTime, v.FinishTime)),
+		trace.WithAttributes(
+			attribute.String(evergreen.VersionIDOtelAttribute, v.Id),
+			attribute.String(evergreen.VersionRequesterOtelAttribute, v.Requester),
+			attribute.String(evergreen.VersionStatusOtelAttribute, v.Status),
+			attribute.String(evergreen.ProjectIdentifierOtelAttribute, v.Identifier),
+		),
+	)
+	if v.Status == evergreen.VersionFailed {
+		span.SetStatus(codes.Error, "")
+	}
+	span.End(trace.WithTimestamp(v.FinishTime))
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTaskLifecycleSpans(t *testing.T) {
+	// The package's tracer only delegates to the first global tracer provider
+	// that's set, so the spans for each test are the ones recorded since it
+	// started.
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithIDGenerator(evergreen.NewTraceIDGenerator()),
+	))
+
+	created := time.Now().Add(-time.Hour).Round(time.Second)
+	tsk := &task.Task{
+		Id:            "t1",
+		Execution:     1,
+		DisplayName:   "compile",
+		BuildId:       "b1",
+		BuildVariant:  "linux",
+		Version:       "v1",
+		Project:       "proj",
+		Status:        evergreen.TaskFailed,
+		Activated:     true,
+		CreateTime:    created,
+		ActivatedTime: created.Add(time.Minute),
+		DispatchTime:  created.Add(2 * time.Minute),
+		StartTime:     created.Add(3 * time.Minute),
+		FinishTime:    created.Add(10 * time.Minute),
+		Details:       apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Description: "compile failed"},
+	}
+
+	t.Run("TaskSpansAreChildrenOfTheBuildSpan", func(t *testing.T) {
+		numEnded := len(recorder.Ended())
+		traceTaskFinished(tsk)
+
+		spans := recorder.Ended()[numEnded:]
+		require.Len(t, spans, 5)
+		taskSpan := spans[4]
+		assert.Equal(t, "task", taskSpan.Name())
+		assert.Equal(t, evergreen.TraceIDFor("v1"), taskSpan.SpanContext().TraceID())
+		assert.Equal(t, taskSpanID("t1", 1), taskSpan.SpanContext().SpanID())
+		assert.Equal(t, evergreen.SpanIDFor("b1"), taskSpan.Parent().SpanID())
+		assert.Equal(t, created, taskSpan.StartTime())
+		assert.Equal(t, tsk.FinishTime, taskSpan.EndTime())
+		assert.Equal(t, codes.Error, taskSpan.Status().Code)
+		assert.Equal(t, "compile failed", taskSpan.Status().Description)
+		assert.Contains(t, taskSpan.Attributes(), attribute.String(evergreen.TaskIDOtelAttribute, "t1"))
+		assert.Contains(t, taskSpan.Attributes(), attribute.Int(evergreen.TaskExecutionOtelAttribute, 1))
+
+		require.Len(t, taskSpan.Links(), 1)
+		assert.Equal(t, taskSpanID("t1", 0), taskSpan.Links()[0].SpanContext.SpanID())
+
+		for i, name := range []string{"task.created", "task.queued", "task.dispatched", "task.started"} {
+			assert.Equal(t, name, spans[i].Name())
+			assert.Equal(t, taskSpan.SpanContext().SpanID(), spans[i].Parent().SpanID())
+			assert.NotEqual(t, taskSpan.SpanContext().SpanID(), spans[i].SpanContext().SpanID())
+		}
+		assert.Equal(t, tsk.StartTime, spans[3].StartTime())
+		assert.Equal(t, tsk.FinishTime, spans[3].EndTime())
+	})
+	t.Run("UnreachedPhasesAreSkipped", func(t *testing.T) {
+		numEnded := len(recorder.Ended())
+		blocked := *tsk
+		blocked.DispatchTime = time.Time{}
+		blocked.StartTime = time.Time{}
+		traceTaskFinished(&blocked)
+
+		spans := recorder.Ended()[numEnded:]
+		require.Len(t, spans, 2)
+		assert.Equal(t, "task.created", spans[0].Name())
+		assert.Equal(t, "task", spans[1].Name())
+	})
+	t.Run("ResetSpanLinksToTheResetExecution", func(t *testing.T) {
+		numEnded := len(recorder.Ended())
+		traceTaskReset(tsk)
+
+		spans := recorder.Ended()[numEnded:]
+		require.Len(t, spans, 1)
+		assert.Equal(t, "task.reset", spans[0].Name())
+		assert.Equal(t, evergreen.SpanIDFor("b1"), spans[0].Parent().SpanID())
+		require.Len(t, spans[0].Links(), 1)
+		assert.Equal(t, taskSpanID("t1", 1), spans[0].Links()[0].SpanContext.SpanID())
+	})
+	t.Run("BuildAndVersionSpansFormTheTrace", func(t *testing.T) {
+		numEnded := len(recorder.Ended())
+		traceBuildFinished(&build.Build{Id: "b1", Version: "v1", Status: evergreen.BuildFailed, CreateTime: created, FinishTime: tsk.FinishTime})
+		traceVersionFinished(&Version{Id: "v1", Status: evergreen.VersionFailed, CreateTime: created, FinishTime: tsk.FinishTime})
+
+		spans := recorder.Ended()[numEnded:]
+		require.Len(t, spans, 2)
+		assert.Equal(t, "build", spans[0].Name())
+		assert.Equal(t, evergreen.SpanIDFor("b1"), spans[0].SpanContext().SpanID())
+		assert.Equal(t, evergreen.SpanIDFor("v1"), spans[0].Parent().SpanID())
+
+		assert.Equal(t, "version", spans[1].Name())
+		assert.Equal(t, evergreen.TraceIDFor("v1"), spans[1].SpanContext().TraceID())
+		assert.Equal(t, evergreen.SpanIDFor("v1"), spans[1].SpanContext().SpanID())
+		assert.False(t, spans[1].Parent().IsValid())
+	})
+}
@@ -0,0 +1,242 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/annotations"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// DefaultTestHistoryLimit is the default number of test results in a
+	// test's history.
+	DefaultTestHistoryLimit = 100
+	// MaxTestHistoryLimit is the maximum number of test results in a test's
+	// history.
+	MaxTestHistoryLimit = 1000
+)
+
+// TestHistoryOptions describe which results of a test are in its history.
+type TestHistoryOptions struct {
+	Project string
+	// TestName matches either the test's file or its display name.
+	TestName     string
+	BuildVariant string
+	TaskName     string
+	// Requesters are the requesters of the tasks whose results are included.
+	// Defaults to mainline commits.
+	Requesters []string
+	// Before only includes results from tasks created before this time.
+	Before time.Time
+	Limit  int
+}
+
+// TestHistoryEntry is the result of a test in one task execution.
+type TestHistoryEntry struct {
+	TaskID              string
+	Execution           int
+	TaskName            string
+	BuildVariant        string
+	Version             string
+	Revision            string
+	RevisionOrderNumber int
+	Requester           string
+	// Archived is whether the task has been restarted since this execution.
+	Archived  bool
+	Status    string
+	StartTime time.Time
+	Duration  time.Duration
+	// Issues and SuspectedIssues are linked by the task execution's
+	// annotation.
+	Issues          []annotations.IssueLink
+	SuspectedIssues []annotations.IssueLink
+}
+
+// TestDurationPoint is the average duration of a test's passing results in a
+// version.
+type TestDurationPoint struct {
+	Version             string
+	RevisionOrderNumber int
+	AverageDuration     time.Duration
+}
+
+// TestHistory is the execution history of a single test in a project,
+// including the results from executions that were since restarted.
+type TestHistory struct {
+	// Entries are ordered from the most recent task.
+	Entries []TestHistoryEntry
+	// DurationTrend is ordered from the oldest mainline version.
+	DurationTrend []TestDurationPoint
+	// FirstFailingVersion is the oldest mainline version of the test's
+	// current streak of failures, if the test is currently failing. A version
+	// only counts as failing if the test failed in the latest execution of
+	// every task it ran in, so a failure fixed by a retry doesn't count.
+	FirstFailingVersion string
+}
+
+// GetTestExecutionHistory returns the test's execution history in the project.
+func GetTestExecutionHistory(opts TestHistoryOptions) (*TestHistory, error) {
+	if opts.Project == "" || opts.TestName == "" {
+		return nil, errors.New("must specify a project and test name")
+	}
+	if len(opts.Requesters) == 0 {
+		opts.Requesters = []string{evergreen.RepotrackerVersionRequester}
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultTestHistoryLimit
+	}
+	if opts.Limit > MaxTestHistoryLimit {
+		opts.Limit = MaxTestHistoryLimit
+	}
+
+	filter := bson.M{
+		testresult.ProjectKey:   opts.Project,
+		testresult.RequesterKey: bson.M{"$in": opts.Requesters},
+		"$or": []bson.M{
+			{testresult.TestFileKey: opts.TestName},
+			{testresult.DisplayTestNameKey: opts.TestName},
+		},
+	}
+	if opts.BuildVariant != "" {
+		filter[testresult.BuildVariantKey] = opts.BuildVariant
+	}
+	if opts.TaskName != "" {
+		filter[testresult.DisplayNameKey] = opts.TaskName
+	}
+	if !opts.Before.IsZero() {
+		filter[testresult.TaskCreateTimeKey] = bson.M{"$lt": opts.Before}
+	}
+	results, err := testresult.Find(db.Query(filter).
+		Sort([]string{"-" + testresult.TaskCreateTimeKey, "-" + testresult.ExecutionKey}).
+		Limit(opts.Limit))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding results for test '%s'", opts.TestName)
+	}
+
+	taskIDs := []string{}
+	for _, result := range results {
+		taskIDs = append(taskIDs, result.TaskID)
+	}
+	tasksByID := map[string]task.Task{}
+	annotationsByExecution := map[string]map[int]annotations.TaskAnnotation{}
+	if len(taskIDs) > 0 {
+		tasks, err := task.FindWithFields(task.ByIds(taskIDs), task.IdKey, task.ExecutionKey, task.VersionKey,
+			task.RevisionKey, task.RevisionOrderNumberKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "finding tasks for test results")
+		}
+		for _, t := range tasks {
+			tasksByID[t.Id] = t
+		}
+		taskAnnotations, err := annotations.FindByTaskIds(taskIDs)
+		if err != nil {
+			return nil, errors.Wrap(err, "finding annotations for test results")
+		}
+		for _, a := range taskAnnotations {
+			if annotationsByExecution[a.TaskId] == nil {
+				annotationsByExecution[a.TaskId] = map[int]annotations.TaskAnnotation{}
+			}
+			annotationsByExecution[a.TaskId][a.TaskExecution] = a
+		}
+	}
+
+	history := &TestHistory{Entries: make([]TestHistoryEntry, 0, len(results))}
+	for _, result := range results {
+		entry := TestHistoryEntry{
+			TaskID:       result.TaskID,
+			Execution:    result.Execution,
+			TaskName:     result.DisplayName,
+			BuildVariant: result.BuildVariant,
+			Requester:    result.Requester,
+			Status:       result.Status,
+			StartTime:    result.TestStartTime,
+			Duration:     result.TestEndTime.Sub(result.TestStartTime),
+		}
+		if t, ok := tasksByID[result.TaskID]; ok {
+			entry.Version = t.Version
+			entry.Revision = t.Revision
+			entry.RevisionOrderNumber = t.RevisionOrderNumber
+			entry.Archived = result.Execution < t.Execution
+		}
+		if a, ok := annotationsByExecution[result.TaskID][result.Execution]; ok {
+			entry.Issues = a.Issues
+			entry.SuspectedIssues = a.SuspectedIssues
+		}
+		history.Entries = append(history.Entries, entry)
+	}
+
+	history.DurationTrend = getTestDurationTrend(history.Entries)
+	history.FirstFailingVersion = getFirstFailingVersion(history.Entries)
+
+	return history, nil
+}
+
+// getTestDurationTrend returns the average duration of the test's passing
+// mainline results per version, from the oldest version.
+func getTestDurationTrend(entries []TestHistoryEntry) []TestDurationPoint {
+	totals := map[string]time.Duration{}
+	counts := map[string]int{}
+	points := []TestDurationPoint{}
+	for _, entry := range entries {
+		if entry.Version == "" || entry.Requester != evergreen.RepotrackerVersionRequester || entry.Status != evergreen.TestSucceededStatus {
+			continue
+		}
+		if _, ok := counts[entry.Version]; !ok {
+			points = append(points, TestDurationPoint{
+				Version:             entry.Version,
+				RevisionOrderNumber: entry.RevisionOrderNumber,
+			})
+		}
+		totals[entry.Version] += entry.Duration
+		counts[entry.Version]++
+	}
+	for i := range points {
+		points[i].AverageDuration = totals[points[i].Version] / time.Duration(counts[points[i].Version])
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].RevisionOrderNumber < points[j].RevisionOrderNumber
+	})
+	return points
+}
+
+// getFirstFailingVersion returns the oldest mainline version of the test's
+// current streak of failing versions. Only the latest execution of each task
+// counts toward whether the version failed.
+func getFirstFailingVersion(entries []TestHistoryEntry) string {
+	failedByVersion := map[string]bool{}
+	orderByVersion := map[string]int{}
+	for _, entry := range entries {
+		if entry.Version == "" || entry.Archived || entry.Requester != evergreen.RepotrackerVersionRequester {
+			continue
+		}
+		failed := entry.Status == evergreen.TestFailedStatus
+		if prev, ok := failedByVersion[entry.Version]; ok {
+			failed = failed && prev
+		}
+		failedByVersion[entry.Version] = failed
+		orderByVersion[entry.Version] = entry.RevisionOrderNumber
+	}
+
+	versions := make([]string, 0, len(orderByVersion))
+	for version := range orderByVersion {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return orderByVersion[versions[i]] > orderByVersion[versions[j]]
+	})
+
+	firstFailing := ""
+	for _, version := range versions {
+		if !failedByVersion[version] {
+			break
+		}
+		firstFailing = version
+	}
+	return firstFailing
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/annotations"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTestExecutionHistory(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, testresult.Collection, annotations.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, testresult.Collection, annotations.Collection))
+	}()
+
+	start := time.Now().Add(-time.Hour)
+	// v1 passes, v2 fails but passes on retry, and v3 and v4 fail.
+	executions := map[int][]string{
+		1: {evergreen.TestSucceededStatus},
+		2: {evergreen.TestFailedStatus, evergreen.TestSucceededStatus},
+		3: {evergreen.TestFailedStatus},
+		4: {evergreen.TestFailedStatus},
+	}
+	for order := 1; order <= 4; order++ {
+		statuses := executions[order]
+		tsk := task.Task{
+			Id:                  fmt.Sprintf("t%d", order),
+			Execution:           len(statuses) - 1,
+			Version:             fmt.Sprintf("v%d", order),
+			Revision:            fmt.Sprintf("r%d", order),
+			RevisionOrderNumber: order,
+			Project:             "project",
+		}
+		require.NoError(t, tsk.Insert())
+		for execution, status := range statuses {
+			testStart := start.Add(time.Duration(order) * time.Minute)
+			result := testresult.TestResult{
+				TaskID:         tsk.Id,
+				Execution:      execution,
+				TestFile:       "test_file.py",
+				Status:         status,
+				Project:        "project",
+				BuildVariant:   "bv",
+				DisplayName:    "task",
+				Requester:      evergreen.RepotrackerVersionRequester,
+				TaskCreateTime: start.Add(time.Duration(order) * time.Minute),
+				TestStartTime:  testStart,
+				TestEndTime:    testStart.Add(time.Duration(order) * time.Second),
+			}
+			require.NoError(t, result.Insert())
+		}
+	}
+	other := testresult.TestResult{
+		TaskID:         "t4",
+		TestFile:       "other_test.py",
+		Status:         evergreen.TestSucceededStatus,
+		Project:        "project",
+		Requester:      evergreen.RepotrackerVersionRequester,
+		TaskCreateTime: start,
+	}
+	require.NoError(t, other.Insert())
+	annotation := annotations.TaskAnnotation{
+		Id:            "a",
+		TaskId:        "t2",
+		TaskExecution: 0,
+		Issues:        []annotations.IssueLink{{URL: "https://issues.example.com/EVG-1", IssueKey: "EVG-1"}},
+	}
+	require.NoError(t, db.Insert(annotations.Collection, annotation))
+
+	history, err := GetTestExecutionHistory(TestHistoryOptions{Project: "project", TestName: "test_file.py"})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 5)
+	assert.Equal(t, "t4", history.Entries[0].TaskID)
+	assert.Equal(t, "v4", history.Entries[0].Version)
+	assert.Equal(t, 4*time.Second, history.Entries[0].Duration)
+
+	var archived *TestHistoryEntry
+	for i, entry := range history.Entries {
+		if entry.Archived {
+			require.Nil(t, archived, "only one execution should be archived")
+			archived = &history.Entries[i]
+		}
+	}
+	require.NotNil(t, archived)
+	assert.Equal(t, "t2", archived.TaskID)
+	assert.Equal(t, 0, archived.Execution)
+	require.Len(t, archived.Issues, 1)
+	assert.Equal(t, "EVG-1", archived.Issues[0].IssueKey)
+
+	assert.Equal(t, "v3", history.FirstFailingVersion)
+	require.Len(t, history.DurationTrend, 2)
+	assert.Equal(t, "v1", history.DurationTrend[0].Version)
+	assert.Equal(t, time.Second, history.DurationTrend[0].AverageDuration)
+	assert.Equal(t, "v2", history.DurationTrend[1].Version)
+	assert.Equal(t, 2*time.Second, history.DurationTrend[1].AverageDuration)
+
+	t.Run("Limit", func(t *testing.T) {
+		history, err := GetTestExecutionHistory(TestHistoryOptions{Project: "project", TestName: "test_file.py", Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, history.Entries, 2)
+		assert.Equal(t, "v3", history.FirstFailingVersion)
+	})
+	t.Run("NoFailingVersionForPassingTest", func(t *testing.T) {
+		history, err := GetTestExecutionHistory(TestHistoryOptions{Project: "project", TestName: "other_test.py"})
+		require.NoError(t, err)
+		require.Len(t, history.Entries, 1)
+		assert.Empty(t, history.FirstFailingVersion)
+	})
+	t.Run("FailsWithoutTestName", func(t *testing.T) {
+		_, err := GetTestExecutionHistory(TestHistoryOptions{Project: "project"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,133 @@
+package testresult
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// AttachmentsCollection is the name of the collection that stores the
+	// attachments of test results stored in Cedar, which cannot store them.
+	AttachmentsCollection = "test_result_attachments"
+
+	// maxArtifactsPerTest is the most artifacts that a single test result
+	// can reference.
+	maxArtifactsPerTest = 50
+)
+
+// Artifact is a file produced by a single test, such as a screenshot or a
+// core dump.
+type Artifact struct {
+	Name string `json:"name" bson:"name"`
+	Link string `json:"link" bson:"link"`
+}
+
+// LogRange points to the lines of the task log that a single test wrote. The
+// start and end lines are both inclusive.
+type LogRange struct {
+	StartLine int `json:"start_line" bson:"start_line"`
+	EndLine   int `json:"end_line" bson:"end_line"`
+}
+
+// ValidateAttachments checks that the artifacts and task log range attached to
+// a test result are valid. The log range may be nil.
+func ValidateAttachments(artifacts []Artifact, logRange *LogRange) error {
+	catcher := grip.NewBasicCatcher()
+	catcher.ErrorfWhen(len(artifacts) > maxArtifactsPerTest, "cannot attach more than %d artifacts to a test", maxArtifactsPerTest)
+	for _, a := range artifacts {
+		if a.Name == "" {
+			catcher.New("artifact name cannot be empty")
+		}
+		if _, err := url.ParseRequestURI(a.Link); err != nil {
+			catcher.Wrapf(err, "invalid link for artifact '%s'", a.Name)
+		}
+	}
+	if logRange != nil {
+		catcher.Wrap(logRange.Validate(), "invalid task log range")
+	}
+	return catcher.Resolve()
+}
+
+// Validate checks that the log range is a valid range of lines.
+func (r LogRange) Validate() error {
+	if r.StartLine < 0 {
+		return errors.New("start line cannot be negative")
+	}
+	if r.EndLine < r.StartLine {
+		return errors.Errorf("end line %d cannot be before start line %d", r.EndLine, r.StartLine)
+	}
+	return nil
+}
+
+// TestAttachments are the artifacts and task log range of a single test result
+// stored in Cedar. They are matched to the result by its Cedar test name.
+type TestAttachments struct {
+	ID           string     `json:"-" bson:"_id"`
+	TaskID       string     `json:"task_id" bson:"task_id"`
+	Execution    int        `json:"execution" bson:"execution"`
+	TestName     string     `json:"test_name" bson:"test_name"`
+	Artifacts    []Artifact `json:"artifacts,omitempty" bson:"artifacts,omitempty"`
+	TaskLogRange *LogRange  `json:"task_log_range,omitempty" bson:"task_log_range,omitempty"`
+	CreatedAt    time.Time  `json:"-" bson:"created_at"`
+}
+
+var (
+	attachmentsIDKey        = bsonutil.MustHaveTag(TestAttachments{}, "ID")
+	attachmentsTaskIDKey    = bsonutil.MustHaveTag(TestAttachments{}, "TaskID")
+	attachmentsCreatedAtKey = bsonutil.MustHaveTag(TestAttachments{}, "CreatedAt")
+)
+
+// AttachmentsID returns the ID of the attachments of the test result with the
+// given Cedar test name.
+func AttachmentsID(taskID string, execution int, testName string) string {
+	return fmt.Sprintf("%s_%d_%s", taskID, execution, testName)
+}
+
+// Validate checks that the attachments identify a test result and are valid.
+func (a *TestAttachments) Validate() error {
+	if a.TestName == "" {
+		return errors.New("test name must be specified")
+	}
+	return errors.Wrapf(ValidateAttachments(a.Artifacts, a.TaskLogRange), "invalid attachments for test '%s'", a.TestName)
+}
+
+// UpsertAttachments saves the attachments, replacing any existing ones for the
+// same test results.
+func UpsertAttachments(attachments []TestAttachments) error {
+	catcher := grip.NewBasicCatcher()
+	for _, a := range attachments {
+		a.ID = AttachmentsID(a.TaskID, a.Execution, a.TestName)
+		a.CreatedAt = time.Now()
+		_, err := db.Upsert(AttachmentsCollection, bson.M{attachmentsIDKey: a.ID}, a)
+		catcher.Wrapf(err, "upserting attachments for test '%s'", a.TestName)
+	}
+	return catcher.Resolve()
+}
+
+// FindAttachmentsForTasks returns the attachments of the test results of the
+// given tasks, keyed by their IDs.
+func FindAttachmentsForTasks(taskIDs []string) (map[string]TestAttachments, error) {
+	attachments := []TestAttachments{}
+	if err := db.FindAllQ(AttachmentsCollection, db.Query(bson.M{attachmentsTaskIDKey: bson.M{"$in": taskIDs}}), &attachments); err != nil {
+		return nil, errors.Wrap(err, "finding test result attachments")
+	}
+	byID := make(map[string]TestAttachments, len(attachments))
+	for _, a := range attachments {
+		byID[a.ID] = a
+	}
+	return byID, nil
+}
+
+// RemoveAttachmentsCreatedBefore deletes the attachments that were saved
+// before the given time.
+func RemoveAttachmentsCreatedBefore(ts time.Time) error {
+	err := db.RemoveAll(AttachmentsCollection, bson.M{attachmentsCreatedAtKey: bson.M{"$lt": ts}})
+	return errors.Wrap(err, "removing expired test result attachments")
+}
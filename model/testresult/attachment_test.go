@@ -0,0 +1,54 @@
+package testresult
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAttachments(t *testing.T) {
+	assert.NoError(t, ValidateAttachments(nil, nil))
+	assert.NoError(t, ValidateAttachments([]Artifact{{Name: "core", Link: "https://example.com/core"}}, &LogRange{StartLine: 3, EndLine: 3}))
+	assert.Error(t, ValidateAttachments([]Artifact{{Link: "https://example.com/core"}}, nil))
+	assert.Error(t, ValidateAttachments([]Artifact{{Name: "core", Link: "core"}}, nil))
+	assert.Error(t, ValidateAttachments(nil, &LogRange{StartLine: -1, EndLine: 3}))
+	assert.Error(t, ValidateAttachments(nil, &LogRange{StartLine: 5, EndLine: 3}))
+
+	artifacts := make([]Artifact, maxArtifactsPerTest+1)
+	for i := range artifacts {
+		artifacts[i] = Artifact{Name: "core", Link: "https://example.com/core"}
+	}
+	assert.Error(t, ValidateAttachments(artifacts, nil))
+
+	assert.NoError(t, (&TestAttachments{TestName: "test", TaskLogRange: &LogRange{StartLine: 1, EndLine: 2}}).Validate())
+	assert.Error(t, (&TestAttachments{TaskLogRange: &LogRange{StartLine: 1, EndLine: 2}}).Validate())
+	assert.Error(t, (&TestAttachments{TestName: "test", TaskLogRange: &LogRange{StartLine: 2, EndLine: 1}}).Validate())
+}
+
+func TestTestAttachmentsStorage(t *testing.T) {
+	require.NoError(t, db.Clear(AttachmentsCollection))
+	defer func() {
+		assert.NoError(t, db.Clear(AttachmentsCollection))
+	}()
+
+	attachments := []TestAttachments{
+		{TaskID: "t1", Execution: 0, TestName: "a", Artifacts: []Artifact{{Name: "core", Link: "https://example.com/core"}}},
+		{TaskID: "t1", Execution: 1, TestName: "a", TaskLogRange: &LogRange{StartLine: 1, EndLine: 5}},
+		{TaskID: "t2", Execution: 0, TestName: "b", TaskLogRange: &LogRange{StartLine: 2, EndLine: 3}},
+	}
+	require.NoError(t, UpsertAttachments(attachments))
+
+	found, err := FindAttachmentsForTasks([]string{"t1"})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, attachments[0].Artifacts, found[AttachmentsID("t1", 0, "a")].Artifacts)
+	assert.Equal(t, attachments[1].TaskLogRange, found[AttachmentsID("t1", 1, "a")].TaskLogRange)
+
+	require.NoError(t, RemoveAttachmentsCreatedBefore(time.Now().Add(time.Minute)))
+	found, err = FindAttachmentsForTasks([]string{"t1", "t2"})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
@@ -0,0 +1,289 @@
+package testresult
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// FlakinessCollection is the name of the collection of per-test
+	// flakiness rollups.
+	FlakinessCollection = "test_flakiness"
+
+	// FlakinessTTL is how long a test outcome counts toward the test's
+	// flakiness score. A rollup whose outcomes are all older than this has
+	// expired and is removed by the TTL index on its ExpiresAt.
+	FlakinessTTL = 14 * 24 * time.Hour
+	// MaxFlakinessOutcomes is how many of a test's most recent mainline
+	// outcomes are scored.
+	MaxFlakinessOutcomes = 50
+
+	// maxFlakinessUpdateAttempts is how many times a rollup update is retried
+	// when another update modifies the same rollup concurrently.
+	maxFlakinessUpdateAttempts = 3
+)
+
+// FlakinessKey identifies the test that a flakiness rollup is for.
+type FlakinessKey struct {
+	Project      string `bson:"project" json:"project"`
+	BuildVariant string `bson:"build_variant" json:"build_variant"`
+	TaskName     string `bson:"task_name" json:"task_name"`
+	TestName     string `bson:"test_name" json:"test_name"`
+}
+
+// FlakinessOutcome is whether a test passed in one task execution.
+type FlakinessOutcome struct {
+	TaskID    string    `bson:"task_id" json:"task_id"`
+	Execution int       `bson:"execution" json:"execution"`
+	Passed    bool      `bson:"passed" json:"passed"`
+	Time      time.Time `bson:"time" json:"time"`
+}
+
+// TestFlakiness is the rollup of a test's recent mainline outcomes and how
+// often they alternated between passing and failing.
+type TestFlakiness struct {
+	ID FlakinessKey `bson:"_id" json:"id"`
+	// Outcomes are the test's most recent outcomes within the TTL, ordered
+	// from oldest to newest.
+	Outcomes       []FlakinessOutcome `bson:"outcomes" json:"outcomes"`
+	NumRuns        int                `bson:"num_runs" json:"num_runs"`
+	NumFailures    int                `bson:"num_failures" json:"num_failures"`
+	NumTransitions int                `bson:"num_transitions" json:"num_transitions"`
+	// Score is the fraction of consecutive outcomes that alternated between
+	// passing and failing, from 0 (stable) to 1 (alternates every run).
+	Score       float64   `bson:"score" json:"score"`
+	LastUpdated time.Time `bson:"last_updated" json:"last_updated"`
+	ExpiresAt   time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+var (
+	FlakinessIDKey          = bsonutil.MustHaveTag(TestFlakiness{}, "ID")
+	FlakinessScoreKey       = bsonutil.MustHaveTag(TestFlakiness{}, "Score")
+	FlakinessLastUpdatedKey = bsonutil.MustHaveTag(TestFlakiness{}, "LastUpdated")
+	FlakinessExpiresAtKey   = bsonutil.MustHaveTag(TestFlakiness{}, "ExpiresAt")
+
+	flakinessKeyProjectKey      = bsonutil.MustHaveTag(FlakinessKey{}, "Project")
+	flakinessKeyBuildVariantKey = bsonutil.MustHaveTag(FlakinessKey{}, "BuildVariant")
+	flakinessKeyTaskNameKey     = bsonutil.MustHaveTag(FlakinessKey{}, "TaskName")
+	flakinessKeyTestNameKey     = bsonutil.MustHaveTag(FlakinessKey{}, "TestName")
+)
+
+// flakinessKeyFor returns the key of the rollup that the test result counts
+// toward.
+func flakinessKeyFor(result TestResult) FlakinessKey {
+	testName := result.DisplayTestName
+	if testName == "" {
+		testName = result.TestFile
+	}
+	return FlakinessKey{
+		Project:      result.Project,
+		BuildVariant: result.BuildVariant,
+		TaskName:     result.DisplayName,
+		TestName:     testName,
+	}
+}
+
+// UpdateFlakiness adds the outcomes of the test results to their tests'
+// flakiness rollups and rescores them. Results that neither passed nor
+// failed are ignored.
+func UpdateFlakiness(results []TestResult) error {
+	now := time.Now()
+	outcomes := map[FlakinessKey][]FlakinessOutcome{}
+	for _, result := range results {
+		if result.Status != evergreen.TestSucceededStatus && result.Status != evergreen.TestFailedStatus {
+			continue
+		}
+		outcomeTime := result.TaskCreateTime
+		if utility.IsZeroTime(outcomeTime) {
+			outcomeTime = now
+		}
+		key := flakinessKeyFor(result)
+		outcomes[key] = append(outcomes[key], FlakinessOutcome{
+			TaskID:    result.TaskID,
+			Execution: result.Execution,
+			Passed:    result.Status == evergreen.TestSucceededStatus,
+			Time:      outcomeTime,
+		})
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for key, newOutcomes := range outcomes {
+		catcher.Wrapf(updateTestFlakiness(key, newOutcomes, now), "updating flakiness for test '%s'", key.TestName)
+	}
+	return catcher.Resolve()
+}
+
+// updateTestFlakiness merges the outcomes into the test's rollup. The rollup
+// is only replaced if no other update changed it in the meantime.
+func updateTestFlakiness(key FlakinessKey, newOutcomes []FlakinessOutcome, now time.Time) error {
+	for attempt := 0; attempt < maxFlakinessUpdateAttempts; attempt++ {
+		existing, err := FindOneFlakiness(db.Query(bson.M{FlakinessIDKey: key}))
+		if err != nil {
+			return errors.Wrap(err, "finding flakiness rollup")
+		}
+
+		query := bson.M{FlakinessIDKey: key}
+		var outcomes []FlakinessOutcome
+		if existing != nil {
+			query[FlakinessLastUpdatedKey] = existing.LastUpdated
+			outcomes = existing.Outcomes
+		}
+		rollup := newTestFlakiness(key, mergeFlakinessOutcomes(outcomes, newOutcomes, now), now)
+
+		if existing == nil {
+			err = db.Insert(FlakinessCollection, rollup)
+		} else {
+			err = db.Update(FlakinessCollection, query, rollup)
+		}
+		if db.IsDuplicateKey(err) || adb.ResultsNotFound(err) {
+			continue
+		}
+		return errors.Wrap(err, "saving flakiness rollup")
+	}
+	return errors.Errorf("flakiness rollup was modified concurrently %d times", maxFlakinessUpdateAttempts)
+}
+
+// mergeFlakinessOutcomes adds the new outcomes to the existing ones, replacing
+// outcomes for the same task execution, and keeps the most recent outcomes
+// that are within the TTL.
+func mergeFlakinessOutcomes(existing, newOutcomes []FlakinessOutcome, now time.Time) []FlakinessOutcome {
+	type execution struct {
+		taskID    string
+		execution int
+	}
+	byExecution := map[execution]FlakinessOutcome{}
+	for _, outcome := range append(append([]FlakinessOutcome{}, existing...), newOutcomes...) {
+		e := execution{taskID: outcome.TaskID, execution: outcome.Execution}
+		if prev, ok := byExecution[e]; ok && prev.Passed != outcome.Passed {
+			// A test that both passed and failed in the same execution,
+			// such as a retried test, counts as a failure.
+			outcome.Passed = false
+		}
+		byExecution[e] = outcome
+	}
+
+	merged := make([]FlakinessOutcome, 0, len(byExecution))
+	for _, outcome := range byExecution {
+		if now.Sub(outcome.Time) > FlakinessTTL {
+			continue
+		}
+		merged = append(merged, outcome)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Time.Equal(merged[j].Time) {
+			if merged[i].TaskID == merged[j].TaskID {
+				return merged[i].Execution < merged[j].Execution
+			}
+			return merged[i].TaskID < merged[j].TaskID
+		}
+		return merged[i].Time.Before(merged[j].Time)
+	})
+	if len(merged) > MaxFlakinessOutcomes {
+		merged = merged[len(merged)-MaxFlakinessOutcomes:]
+	}
+	return merged
+}
+
+// newTestFlakiness scores the outcomes.
+func newTestFlakiness(key FlakinessKey, outcomes []FlakinessOutcome, now time.Time) *TestFlakiness {
+	rollup := &TestFlakiness{
+		ID:          key,
+		Outcomes:    outcomes,
+		NumRuns:     len(outcomes),
+		LastUpdated: now,
+		ExpiresAt:   now.Add(FlakinessTTL),
+	}
+	for i, outcome := range outcomes {
+		if !outcome.Passed {
+			rollup.NumFailures++
+		}
+		if i > 0 && outcomes[i-1].Passed != outcome.Passed {
+			rollup.NumTransitions++
+		}
+	}
+	if len(outcomes) > 0 {
+		rollup.ExpiresAt = outcomes[len(outcomes)-1].Time.Add(FlakinessTTL)
+	}
+	if len(outcomes) > 1 {
+		rollup.Score = float64(rollup.NumTransitions) / float64(len(outcomes)-1)
+	}
+	return rollup
+}
+
+// FlakinessFilter selects flakiness rollups. Only the project is required.
+type FlakinessFilter struct {
+	Project      string
+	BuildVariant string
+	TaskName     string
+	TestNames    []string
+	MinScore     float64
+	Limit        int
+}
+
+// FindFlakiness returns the unexpired flakiness rollups that match the
+// filter, from flakiest to least flaky.
+func FindFlakiness(filter FlakinessFilter) ([]TestFlakiness, error) {
+	if filter.Project == "" {
+		return nil, errors.New("project must be specified")
+	}
+	query := bson.M{
+		bsonutil.GetDottedKeyName(FlakinessIDKey, flakinessKeyProjectKey): filter.Project,
+		FlakinessExpiresAtKey: bson.M{"$gt": time.Now()},
+	}
+	if filter.BuildVariant != "" {
+		query[bsonutil.GetDottedKeyName(FlakinessIDKey, flakinessKeyBuildVariantKey)] = filter.BuildVariant
+	}
+	if filter.TaskName != "" {
+		query[bsonutil.GetDottedKeyName(FlakinessIDKey, flakinessKeyTaskNameKey)] = filter.TaskName
+	}
+	if len(filter.TestNames) > 0 {
+		query[bsonutil.GetDottedKeyName(FlakinessIDKey, flakinessKeyTestNameKey)] = bson.M{"$in": filter.TestNames}
+	}
+	if filter.MinScore > 0 {
+		query[FlakinessScoreKey] = bson.M{"$gte": filter.MinScore}
+	}
+
+	q := db.Query(query).Sort([]string{"-" + FlakinessScoreKey})
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	rollups := []TestFlakiness{}
+	err := db.FindAllQ(FlakinessCollection, q, &rollups)
+	return rollups, err
+}
+
+// FindOneFlakiness returns the flakiness rollup that matches the query, or
+// nil if there is none.
+func FindOneFlakiness(query db.Q) (*TestFlakiness, error) {
+	rollup := &TestFlakiness{}
+	err := db.FindOneQ(FlakinessCollection, query, rollup)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return rollup, err
+}
+
+// GetFlakinessScore returns the flakiness score of the test, or zero if it
+// has no unexpired rollup.
+func GetFlakinessScore(key FlakinessKey) (float64, error) {
+	rollup, err := FindOneFlakiness(db.Query(bson.M{
+		FlakinessIDKey:        key,
+		FlakinessExpiresAtKey: bson.M{"$gt": time.Now()},
+	}))
+	if err != nil {
+		return 0, errors.Wrap(err, "finding flakiness rollup")
+	}
+	if rollup == nil {
+		return 0, nil
+	}
+	return rollup.Score, nil
+}
@@ -0,0 +1,96 @@
+package testresult
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateFlakiness(t *testing.T) {
+	require.NoError(t, db.Clear(FlakinessCollection))
+	defer func() {
+		assert.NoError(t, db.Clear(FlakinessCollection))
+	}()
+
+	start := time.Now().Add(-time.Hour)
+	makeResult := func(i int, testFile, status string) TestResult {
+		return TestResult{
+			TaskID:         fmt.Sprintf("t%d", i),
+			TestFile:       testFile,
+			Status:         status,
+			Project:        "project",
+			BuildVariant:   "bv",
+			DisplayName:    "task",
+			TaskCreateTime: start.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	// The flaky test alternates every run and the stable test always
+	// passes.
+	for i, status := range []string{evergreen.TestSucceededStatus, evergreen.TestFailedStatus, evergreen.TestSucceededStatus, evergreen.TestFailedStatus, evergreen.TestSucceededStatus} {
+		require.NoError(t, UpdateFlakiness([]TestResult{
+			makeResult(i, "flaky", status),
+			makeResult(i, "stable", evergreen.TestSucceededStatus),
+			makeResult(i, "skipped", evergreen.TestSkippedStatus),
+		}))
+	}
+
+	rollups, err := FindFlakiness(FlakinessFilter{Project: "project"})
+	require.NoError(t, err)
+	require.Len(t, rollups, 2)
+	assert.Equal(t, "flaky", rollups[0].ID.TestName)
+	assert.Equal(t, 5, rollups[0].NumRuns)
+	assert.Equal(t, 2, rollups[0].NumFailures)
+	assert.Equal(t, 4, rollups[0].NumTransitions)
+	assert.EqualValues(t, 1, rollups[0].Score)
+	assert.Equal(t, "stable", rollups[1].ID.TestName)
+	assert.Zero(t, rollups[1].Score)
+
+	t.Run("ReingestedResultsAreNotCountedTwice", func(t *testing.T) {
+		require.NoError(t, UpdateFlakiness([]TestResult{makeResult(4, "flaky", evergreen.TestSucceededStatus)}))
+		score, err := GetFlakinessScore(rollups[0].ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, score)
+	})
+	t.Run("FiltersByScore", func(t *testing.T) {
+		flaky, err := FindFlakiness(FlakinessFilter{Project: "project", MinScore: 0.5})
+		require.NoError(t, err)
+		require.Len(t, flaky, 1)
+		assert.Equal(t, "flaky", flaky[0].ID.TestName)
+	})
+	t.Run("OutcomesOutsideTTLAreDropped", func(t *testing.T) {
+		old := makeResult(10, "old", evergreen.TestFailedStatus)
+		old.TaskCreateTime = time.Now().Add(-2 * FlakinessTTL)
+		require.NoError(t, UpdateFlakiness([]TestResult{old}))
+		rollups, err := FindFlakiness(FlakinessFilter{Project: "project", TestNames: []string{"old"}})
+		require.NoError(t, err)
+		assert.Empty(t, rollups)
+	})
+}
+
+func TestMergeFlakinessOutcomes(t *testing.T) {
+	now := time.Now()
+	existing := []FlakinessOutcome{
+		{TaskID: "t1", Passed: true, Time: now.Add(-2 * time.Minute)},
+	}
+	merged := mergeFlakinessOutcomes(existing, []FlakinessOutcome{
+		{TaskID: "t2", Passed: true, Time: now.Add(-time.Minute)},
+		{TaskID: "t2", Passed: false, Time: now.Add(-time.Minute)},
+	}, now)
+	require.Len(t, merged, 2)
+	assert.Equal(t, "t1", merged[0].TaskID)
+	assert.Equal(t, "t2", merged[1].TaskID)
+	assert.False(t, merged[1].Passed, "a test that passed and failed in the same execution should count as failed")
+
+	var many []FlakinessOutcome
+	for i := 0; i < MaxFlakinessOutcomes+10; i++ {
+		many = append(many, FlakinessOutcome{TaskID: fmt.Sprintf("t%d", i), Time: now.Add(time.Duration(i-100) * time.Second)})
+	}
+	merged = mergeFlakinessOutcomes(nil, many, now)
+	require.Len(t, merged, MaxFlakinessOutcomes)
+	assert.Equal(t, "t10", merged[0].TaskID)
+}
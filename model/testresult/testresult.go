@@ -36,6 +36,11 @@ type TestResult struct {
 	StartTime       float64          `json:"start" bson:"start"`
 	EndTime         float64          `json:"end" bson:"end"`
 
+	// Artifacts are files produced by the test.
+	Artifacts []Artifact `json:"artifacts,omitempty" bson:"artifacts,omitempty"`
+	// TaskLogRange points to the lines of the task log that the test wrote.
+	TaskLogRange *LogRange `json:"task_log_range,omitempty" bson:"task_log_range,omitempty"`
+
 	// Together, TaskID and Execution identify the task which created this TestResult
 	TaskID    string `bson:"task_id" json:"task_id"`
 	Execution int    `bson:"task_execution" json:"task_execution"`
@@ -72,6 +77,8 @@ var (
 	ExitCodeKey        = bsonutil.MustHaveTag(TestResult{}, "ExitCode")
 	StartTimeKey       = bsonutil.MustHaveTag(TestResult{}, "StartTime")
 	EndTimeKey         = bsonutil.MustHaveTag(TestResult{}, "EndTime")
+	ArtifactsKey       = bsonutil.MustHaveTag(TestResult{}, "Artifacts")
+	TaskLogRangeKey    = bsonutil.MustHaveTag(TestResult{}, "TaskLogRange")
 	TaskIDKey          = bsonutil.MustHaveTag(TestResult{}, "TaskID")
 	ExecutionKey       = bsonutil.MustHaveTag(TestResult{}, "Execution")
 
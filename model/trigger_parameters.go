@@ -0,0 +1,82 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// maxTriggerParameters is the most parameters that a project trigger can pass
+// to the versions it creates.
+const maxTriggerParameters = 50
+
+// ValidateTriggerParameters checks that the parameters a project trigger
+// passes to downstream versions are well-formed.
+func ValidateTriggerParameters(params []patch.Parameter) error {
+	catcher := grip.NewBasicCatcher()
+	catcher.ErrorfWhen(len(params) > maxTriggerParameters, "cannot pass more than %d parameters", maxTriggerParameters)
+	keys := map[string]bool{}
+	empty := util.Expansions{}
+	for _, param := range params {
+		catcher.NewWhen(param.Key == "", "parameter key cannot be empty")
+		catcher.ErrorfWhen(keys[param.Key], "duplicate parameter key '%s'", param.Key)
+		keys[param.Key] = true
+		_, err := empty.ExpandString(param.Value)
+		catcher.Wrapf(err, "invalid value for parameter '%s'", param.Key)
+	}
+	return catcher.Resolve()
+}
+
+// TriggerUpstream describes the upstream version and task or build that
+// triggered a downstream version.
+type TriggerUpstream struct {
+	Version *Version
+	// Level is whether a task or a build triggered the downstream version.
+	Level        string
+	ID           string
+	Status       string
+	BuildVariant string
+	// TaskName is only set if a task triggered the downstream version.
+	TaskName string
+}
+
+// Expansions returns the expansions that trigger parameter values can
+// reference. They're named the same as the expansions that tasks in the
+// downstream version get.
+func (u TriggerUpstream) Expansions() util.Expansions {
+	expansions := util.Expansions{}
+	expansions.Put("trigger_event_identifier", u.ID)
+	expansions.Put("trigger_event_type", u.Level)
+	expansions.Put("trigger_status", u.Status)
+	expansions.Put("trigger_build_variant", u.BuildVariant)
+	if u.TaskName != "" {
+		expansions.Put("trigger_task_name", u.TaskName)
+	}
+	if u.Version != nil {
+		expansions.Put("trigger_version_id", u.Version.Id)
+		expansions.Put("trigger_revision", u.Version.Revision)
+		expansions.Put("trigger_project_id", u.Version.Identifier)
+		expansions.Put("trigger_author", u.Version.Author)
+	}
+	return expansions
+}
+
+// ExpandParameters returns the parameters that the trigger passes to the
+// downstream version, with their values expanded using the upstream version
+// and task or build.
+func (t *TriggerDefinition) ExpandParameters(upstream TriggerUpstream) ([]patch.Parameter, error) {
+	if len(t.Parameters) == 0 {
+		return nil, nil
+	}
+	expansions := upstream.Expansions()
+	params := make([]patch.Parameter, 0, len(t.Parameters))
+	for _, param := range t.Parameters {
+		value, err := expansions.ExpandString(param.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "expanding value of parameter '%s'", param.Key)
+		}
+		params = append(params, patch.Parameter{Key: param.Key, Value: value})
+	}
+	return params, nil
+}
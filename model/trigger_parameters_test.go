@@ -0,0 +1,50 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTriggerParameters(t *testing.T) {
+	assert.NoError(t, ValidateTriggerParameters(nil))
+	assert.NoError(t, ValidateTriggerParameters([]patch.Parameter{
+		{Key: "static", Value: "value"},
+		{Key: "templated", Value: "${trigger_revision}"},
+	}))
+	assert.Error(t, ValidateTriggerParameters([]patch.Parameter{{Key: "", Value: "value"}}))
+	assert.Error(t, ValidateTriggerParameters([]patch.Parameter{{Key: "k", Value: "a"}, {Key: "k", Value: "b"}}))
+	assert.Error(t, ValidateTriggerParameters([]patch.Parameter{{Key: "k", Value: "${unterminated"}}))
+}
+
+func TestTriggerDefinitionExpandParameters(t *testing.T) {
+	def := TriggerDefinition{
+		Parameters: []patch.Parameter{
+			{Key: "static", Value: "value"},
+			{Key: "source", Value: "${trigger_project_id}@${trigger_revision}"},
+			{Key: "task", Value: "${trigger_build_variant}/${trigger_task_name}:${trigger_status}"},
+			{Key: "missing", Value: "${trigger_nonexistent|default}"},
+		},
+	}
+	params, err := def.ExpandParameters(TriggerUpstream{
+		Version:      &Version{Id: "v", Revision: "abc", Identifier: "upstream"},
+		Level:        ProjectTriggerLevelTask,
+		ID:           "t",
+		Status:       evergreen.TaskSucceeded,
+		BuildVariant: "bv",
+		TaskName:     "compile",
+	})
+	require.NoError(t, err)
+	require.Len(t, params, 4)
+	assert.Equal(t, "value", params[0].Value)
+	assert.Equal(t, "upstream@abc", params[1].Value)
+	assert.Equal(t, "bv/compile:success", params[2].Value)
+	assert.Equal(t, "default", params[3].Value)
+
+	params, err = (&TriggerDefinition{}).ExpandParameters(TriggerUpstream{})
+	assert.NoError(t, err)
+	assert.Empty(t, params)
+}
@@ -32,6 +32,7 @@ var (
 	PubKeysKey                = bsonutil.MustHaveTag(DBUser{}, "PubKeys")
 	LoginCacheKey             = bsonutil.MustHaveTag(DBUser{}, "LoginCache")
 	RolesKey                  = bsonutil.MustHaveTag(DBUser{}, "SystemRoles")
+	ServiceAccountKey         = bsonutil.MustHaveTag(DBUser{}, "ServiceAccount")
 	LoginCacheTokenKey        = bsonutil.MustHaveTag(LoginCache{}, "Token")
 	LoginCacheTTLKey          = bsonutil.MustHaveTag(LoginCache{}, "TTL")
 	LoginCacheAccessTokenKey  = bsonutil.MustHaveTag(LoginCache{}, "AccessToken")
@@ -42,6 +43,11 @@ var (
 	FavoriteProjectsKey       = bsonutil.MustHaveTag(DBUser{}, "FavoriteProjects")
 )
 
+var (
+	ServiceAccountProjectIDKey    = bsonutil.MustHaveTag(ServiceAccountInfo{}, "ProjectID")
+	ServiceAccountKeyRotatedAtKey = bsonutil.MustHaveTag(ServiceAccountInfo{}, "KeyRotatedAt")
+)
+
 //nolint: deadcode, megacheck, unused
 var (
 	githubUserUID         = bsonutil.MustHaveTag(GithubUser{}, "UID")
@@ -334,6 +340,30 @@ func FindNeedsReauthorization(reauthorizeAfter time.Duration) ([]DBUser, error)
 	return users, errors.Wrap(err, "finding users who need reauthorization")
 }
 
+// FindServiceAccountsForProject returns the project's service accounts.
+func FindServiceAccountsForProject(projectID string) ([]DBUser, error) {
+	res := []DBUser{}
+	err := db.FindAllQ(
+		Collection,
+		db.Query(bson.M{
+			OnlyAPIKey: true,
+			bsonutil.GetDottedKeyName(ServiceAccountKey, ServiceAccountProjectIDKey): projectID,
+		}).Sort([]string{IdKey}),
+		&res,
+	)
+	return res, errors.Wrapf(err, "finding service accounts for project '%s'", projectID)
+}
+
+// FindServiceAccount returns the project's service account with the given ID,
+// or nil if it doesn't exist.
+func FindServiceAccount(projectID, id string) (*DBUser, error) {
+	return FindOne(db.Query(bson.M{
+		IdKey:      id,
+		OnlyAPIKey: true,
+		bsonutil.GetDottedKeyName(ServiceAccountKey, ServiceAccountProjectIDKey): projectID,
+	}))
+}
+
 // FindServiceUsers returns all API-only users.
 func FindServiceUsers() ([]DBUser, error) {
 	query := bson.M{
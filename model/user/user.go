@@ -33,6 +33,20 @@ type DBUser struct {
 	LoginCache       LoginCache   `bson:"login_cache,omitempty"`
 	FavoriteProjects []string     `bson:"favorite_projects"`
 	OnlyAPI          bool         `bson:"only_api,omitempty"`
+	// ServiceAccount is set for API-only users that automate a single
+	// project.
+	ServiceAccount *ServiceAccountInfo `bson:"service_account,omitempty"`
+}
+
+// ServiceAccountInfo describes an API-only user that can only act on a single
+// project.
+type ServiceAccountInfo struct {
+	ProjectID string `bson:"project_id" json:"project_id"`
+	// Permissions are the kinds of actions the service account can take in
+	// the project.
+	Permissions  []string  `bson:"permissions" json:"permissions"`
+	CreatedBy    string    `bson:"created_by" json:"created_by"`
+	KeyRotatedAt time.Time `bson:"key_rotated_at,omitempty" json:"key_rotated_at,omitempty"`
 }
 
 func (u *DBUser) MarshalBSON() ([]byte, error)  { return mgobson.Marshal(u) }
@@ -85,6 +99,8 @@ type NotificationPreferences struct {
 	SpawnHostOutcomeID    string                     `bson:"spawn_host_outcome_id,omitempty" json:"-"`
 	CommitQueue           UserSubscriptionPreference `bson:"commit_queue" json:"commit_queue"`
 	CommitQueueID         string                     `bson:"commit_queue_id,omitempty" json:"-"`
+	TasksBlocked          UserSubscriptionPreference `bson:"tasks_blocked,omitempty" json:"tasks_blocked"`
+	TasksBlockedID        string                     `bson:"tasks_blocked_id,omitempty" json:"-"`
 }
 
 type UserSubscriptionPreference string
@@ -130,6 +146,12 @@ func (u *DBUser) GetPublicKey(keyname string) (string, error) {
 	return "", errors.Errorf("Unable to find public key '%v' for user '%v'", keyname, u.Username())
 }
 
+// IsServiceAccount returns whether the user is a project-scoped service
+// account.
+func (u *DBUser) IsServiceAccount() bool {
+	return u.OnlyAPI && u.ServiceAccount != nil
+}
+
 // UpdateAPIKey updates the API key stored for the user.
 func (u *DBUser) UpdateAPIKey(newKey string) error {
 	update := bson.M{"$set": bson.M{APIKeyKey: newKey}}
@@ -441,6 +463,9 @@ func (u *DBUser) GeneralSubscriptionIDs() []string {
 	if id := u.Settings.Notifications.CommitQueueID; id != "" {
 		ids = append(ids, id)
 	}
+	if id := u.Settings.Notifications.TasksBlockedID; id != "" {
+		ids = append(ids, id)
+	}
 
 	return ids
 }
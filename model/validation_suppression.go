@@ -0,0 +1,17 @@
+package model
+
+// ValidationSuppression hides the project config validation warnings with the
+// given codes. If it has task or build variant selectors, it only hides the
+// warnings about the tasks and build variants that they select.
+type ValidationSuppression struct {
+	// Codes are the codes of the validation warnings to hide.
+	Codes []string `yaml:"codes,omitempty" bson:"codes,omitempty"`
+	// Tasks are selectors for the tasks and task groups whose warnings are
+	// hidden.
+	Tasks []string `yaml:"tasks,omitempty" bson:"tasks,omitempty"`
+	// Variants are selectors for the build variants whose warnings are
+	// hidden.
+	Variants []string `yaml:"variants,omitempty" bson:"variants,omitempty"`
+	// Reason explains why the warnings can't be fixed.
+	Reason string `yaml:"reason,omitempty" bson:"reason,omitempty"`
+}
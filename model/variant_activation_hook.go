@@ -0,0 +1,415 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultVariantActivationHookTimeout is how long to wait for an
+	// activation hook that doesn't set its own timeout.
+	defaultVariantActivationHookTimeout = 10 * time.Second
+
+	// VariantActivationHookSignatureHeader is the header that contains the
+	// HMAC signature of the request body, made with the project's activation
+	// hook secret.
+	VariantActivationHookSignatureHeader = "X-Evergreen-Signature"
+)
+
+// VariantActivationHook is an external service that decides whether the
+// project's matching variants can be activated.
+type VariantActivationHook struct {
+	// BuildVariants are the names of the variants the hook applies to.
+	BuildVariants []string `bson:"build_variants" json:"build_variants" yaml:"build_variants"`
+	// URL is the endpoint that is called before activating a matching
+	// variant.
+	URL string `bson:"url" json:"url" yaml:"url"`
+	// TimeoutSecs is how long to wait for the endpoint to respond before
+	// denying the activation.
+	TimeoutSecs int `bson:"timeout_secs,omitempty" json:"timeout_secs,omitempty" yaml:"timeout_secs,omitempty"`
+}
+
+// GetTimeout returns how long to wait for the hook to respond.
+func (h *VariantActivationHook) GetTimeout() time.Duration {
+	if h.TimeoutSecs <= 0 {
+		return defaultVariantActivationHookTimeout
+	}
+	return time.Duration(h.TimeoutSecs) * time.Second
+}
+
+// Validate checks that the hook can be called. Hooks are called by the app
+// server, so they can only use HTTP(S) URLs for public hosts.
+func (h VariantActivationHook) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(ValidateVariantActivationHookURL(h.URL))
+	catcher.NewWhen(h.TimeoutSecs < 0, "timeout cannot be negative")
+	catcher.NewWhen(len(h.BuildVariants) == 0, "must apply to at least one build variant")
+	return catcher.Resolve()
+}
+
+// ValidateVariantActivationHooks checks that each of the activation hooks can
+// be called.
+func ValidateVariantActivationHooks(hooks []VariantActivationHook) error {
+	catcher := grip.NewBasicCatcher()
+	for i, hook := range hooks {
+		catcher.Wrapf(hook.Validate(), "invalid activation hook %d", i)
+	}
+	return catcher.Resolve()
+}
+
+// ValidateVariantActivationHookURL checks that the URL is an HTTP(S) URL that
+// doesn't refer to an internal address.
+func ValidateVariantActivationHookURL(hookURL string) error {
+	u, err := url.ParseRequestURI(hookURL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid URL '%s'", hookURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf("URL '%s' must use HTTP or HTTPS", hookURL)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return errors.Errorf("URL '%s' must have a host", hookURL)
+	}
+	if host == "localhost" {
+		return errors.Errorf("URL '%s' cannot refer to an internal address", hookURL)
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return errors.Errorf("URL '%s' cannot refer to an internal address", hookURL)
+	}
+	return nil
+}
+
+// privateIPNets are the IPv4 and IPv6 private address ranges.
+var privateIPNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7"} {
+		_, ipNet, _ := net.ParseCIDR(cidr)
+		nets = append(nets, ipNet)
+	}
+	return nets
+}()
+
+// isPublicIP returns whether the IP address is routable on the public
+// internet.
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, ipNet := range privateIPNets {
+		if ipNet.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// EnsureVariantActivationHookSecret generates the secret that activation hook
+// requests are signed with if the project has hooks but no secret yet.
+func (p *ProjectRef) EnsureVariantActivationHookSecret() {
+	if len(p.VariantActivationHooks) > 0 && p.VariantActivationHookSecret == "" {
+		p.VariantActivationHookSecret = utility.RandomString()
+	}
+}
+
+// VariantActivationHookRequest is the payload sent to an activation hook.
+type VariantActivationHookRequest struct {
+	Project      string `json:"project"`
+	Version      string `json:"version"`
+	Revision     string `json:"revision"`
+	Requester    string `json:"requester"`
+	BuildID      string `json:"build_id"`
+	BuildVariant string `json:"build_variant"`
+	Caller       string `json:"caller"`
+}
+
+// VariantActivationHookResponse is the decision that an activation hook
+// responds with.
+type VariantActivationHookResponse struct {
+	Allow   bool   `json:"allow"`
+	Message string `json:"message"`
+}
+
+// VariantActivationDeniedError is returned when an activation hook does not
+// allow a build to be activated.
+type VariantActivationDeniedError struct {
+	BuildID      string
+	BuildVariant string
+	Message      string
+}
+
+func (e *VariantActivationDeniedError) Error() string {
+	msg := fmt.Sprintf("activation hook denied activating variant '%s' for build '%s'", e.BuildVariant, e.BuildID)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// IsVariantActivationDenied returns whether the error is because an
+// activation hook denied activating a build.
+func IsVariantActivationDenied(err error) bool {
+	_, ok := errors.Cause(err).(*VariantActivationDeniedError)
+	return ok
+}
+
+// GetVariantActivationHook returns the activation hook for the variant, or
+// nil if it has none.
+func (p *ProjectRef) GetVariantActivationHook(variant string) *VariantActivationHook {
+	for i, hook := range p.VariantActivationHooks {
+		if utility.StringSliceContains(hook.BuildVariants, variant) {
+			return &p.VariantActivationHooks[i]
+		}
+	}
+	return nil
+}
+
+// CheckVariantActivationHook evaluates the activation hook for the build's
+// variant before the build is activated and records the result on the build.
+// It returns a VariantActivationDeniedError if the hook does not allow the
+// activation. If bypass is set, the activation is allowed without calling
+// the hook. Builds that are already activated are not checked.
+func CheckVariantActivationHook(ctx context.Context, pRef *ProjectRef, b *build.Build, caller string, bypass bool) error {
+	if pRef == nil || b.Activated {
+		return nil
+	}
+	hook := pRef.GetVariantActivationHook(b.BuildVariant)
+	if hook == nil {
+		return nil
+	}
+
+	result := evaluateVariantActivationHook(ctx, hook, pRef.VariantActivationHookSecret, b, caller, bypass)
+	if err := build.SetActivationHookResult(b.Id, result); err != nil {
+		return errors.Wrapf(err, "recording activation hook result for build '%s'", b.Id)
+	}
+	b.ActivationHook = &result
+	if !result.Allowed {
+		return &VariantActivationDeniedError{
+			BuildID:      b.Id,
+			BuildVariant: b.BuildVariant,
+			Message:      result.Message,
+		}
+	}
+	return nil
+}
+
+// checkVariantActivationHookForNewBuild evaluates the activation hook for a
+// build that is about to be created activated. If the hook does not allow the
+// activation, the build is created deactivated instead. The result is
+// recorded on the build before it is inserted.
+func checkVariantActivationHookForNewBuild(ctx context.Context, pRef *ProjectRef, b *build.Build, caller string) {
+	if pRef == nil || !b.Activated {
+		return
+	}
+	hook := pRef.GetVariantActivationHook(b.BuildVariant)
+	if hook == nil {
+		return
+	}
+
+	result := evaluateVariantActivationHook(ctx, hook, pRef.VariantActivationHookSecret, b, caller, false)
+	b.ActivationHook = &result
+	if !result.Allowed {
+		grip.Info(message.Fields{
+			"message":       "activation hook denied activating new build",
+			"project":       b.Project,
+			"version":       b.Version,
+			"build":         b.Id,
+			"build_variant": b.BuildVariant,
+			"hook_message":  result.Message,
+		})
+		b.Activated = false
+		b.ActivatedTime = utility.ZeroTime
+	}
+}
+
+// checkVariantActivationHooks evaluates the activation hooks for the builds
+// that aren't activated yet and returns the IDs of the builds that can be
+// activated. If any of the hooks did not allow the activation, it also
+// returns the VariantActivationDeniedError for the first denied build.
+func checkVariantActivationHooks(ctx context.Context, buildIDs []string, caller string) (allowed []string, denied error, err error) {
+	if len(buildIDs) == 0 {
+		return nil, nil, nil
+	}
+	builds, err := build.Find(build.ByIds(buildIDs))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "finding builds to check activation hooks for")
+	}
+
+	projectRefs := map[string]*ProjectRef{}
+	deniedIDs := map[string]bool{}
+	for i := range builds {
+		b := &builds[i]
+		if b.Activated {
+			continue
+		}
+		pRef, ok := projectRefs[b.Version]
+		if !ok {
+			pRef, err = FindMergedProjectRef(b.Project, b.Version, false)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "finding project '%s'", b.Project)
+			}
+			projectRefs[b.Version] = pRef
+		}
+		hookErr := CheckVariantActivationHook(ctx, pRef, b, caller, false)
+		if IsVariantActivationDenied(hookErr) {
+			deniedIDs[b.Id] = true
+			if denied == nil {
+				denied = hookErr
+			}
+			continue
+		}
+		if hookErr != nil {
+			return nil, nil, hookErr
+		}
+	}
+
+	for _, id := range buildIDs {
+		if !deniedIDs[id] {
+			allowed = append(allowed, id)
+		}
+	}
+	return allowed, denied, nil
+}
+
+// filterTasksByActivationHooks returns the tasks that are not in builds whose
+// activation hooks deny activating them. If any of the hooks did not allow the
+// activation, it also returns the VariantActivationDeniedError for the first
+// denied build.
+func filterTasksByActivationHooks(tasks []task.Task, caller string) ([]task.Task, error, error) {
+	buildIDs := []string{}
+	for _, t := range tasks {
+		if t.BuildId != "" && !utility.StringSliceContains(buildIDs, t.BuildId) {
+			buildIDs = append(buildIDs, t.BuildId)
+		}
+	}
+	allowed, denied, err := checkVariantActivationHooks(context.Background(), buildIDs, caller)
+	if err != nil || denied == nil {
+		return tasks, denied, err
+	}
+
+	filtered := make([]task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.BuildId == "" || utility.StringSliceContains(allowed, t.BuildId) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered, denied, nil
+}
+
+// evaluateVariantActivationHook calls the activation hook for the build and
+// returns its decision.
+func evaluateVariantActivationHook(ctx context.Context, hook *VariantActivationHook, secret string, b *build.Build, caller string, bypass bool) build.ActivationHookResult {
+	result := build.ActivationHookResult{
+		URL:         hook.URL,
+		Caller:      caller,
+		EvaluatedAt: time.Now(),
+	}
+	if bypass {
+		result.Allowed = true
+		result.Bypassed = true
+	} else {
+		resp, err := callVariantActivationHook(ctx, hook, secret, VariantActivationHookRequest{
+			Project:      b.Project,
+			Version:      b.Version,
+			Revision:     b.Revision,
+			Requester:    b.Requester,
+			BuildID:      b.Id,
+			BuildVariant: b.BuildVariant,
+			Caller:       caller,
+		})
+		if err != nil {
+			// Activations are denied when the hook can't make a decision.
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message":       "activation hook failed",
+				"project":       b.Project,
+				"build":         b.Id,
+				"build_variant": b.BuildVariant,
+				"url":           hook.URL,
+			}))
+			result.Message = err.Error()
+		} else {
+			result.Allowed = resp.Allow
+			result.Message = resp.Message
+		}
+	}
+	return result
+}
+
+// callVariantActivationHook sends the payload to the hook, signed with the
+// project's secret.
+func callVariantActivationHook(ctx context.Context, hook *VariantActivationHook, secret string, payload VariantActivationHookRequest) (*VariantActivationHookResponse, error) {
+	if secret == "" {
+		return nil, errors.New("project has no activation hook secret; save the project's activation hooks to generate one")
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling activation hook request")
+	}
+	signature, err := util.CalculateHMACHash([]byte(secret), body)
+	if err != nil {
+		return nil, errors.Wrap(err, "signing activation hook request")
+	}
+	ctx, cancel := context.WithTimeout(ctx, hook.GetTimeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating activation hook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(VariantActivationHookSignatureHeader, signature)
+
+	resp, err := variantActivationHookClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "calling activation hook")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("activation hook responded with status %d", resp.StatusCode)
+	}
+
+	hookResp := &VariantActivationHookResponse{}
+	if err = utility.ReadJSON(resp.Body, hookResp); err != nil {
+		return nil, errors.Wrap(err, "reading activation hook response")
+	}
+	return hookResp, nil
+}
+
+// variantActivationHookClient only connects to public addresses, so that a
+// hook's host can't resolve to an internal service. It doesn't follow
+// redirects for the same reason.
+var variantActivationHookClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: defaultVariantActivationHookTimeout,
+			Control: func(_, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return errors.Wrapf(err, "parsing address '%s'", address)
+				}
+				if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+					return errors.Errorf("activation hook cannot connect to internal address '%s'", host)
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout: defaultVariantActivationHookTimeout,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
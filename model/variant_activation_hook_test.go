@@ -0,0 +1,259 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateVariantActivationHook(t *testing.T) {
+	for tName, tCase := range map[string]struct {
+		hook    VariantActivationHook
+		isValid bool
+	}{
+		"PublicHost":        {hook: VariantActivationHook{BuildVariants: []string{"deploy"}, URL: "https://change.example.com/approve"}, isValid: true},
+		"PublicIP":          {hook: VariantActivationHook{BuildVariants: []string{"deploy"}, URL: "http://8.8.8.8/approve"}, isValid: true},
+		"NoVariants":        {hook: VariantActivationHook{URL: "https://change.example.com/approve"}},
+		"NegativeTimeout":   {hook: VariantActivationHook{BuildVariants: []string{"deploy"}, URL: "https://change.example.com/approve", TimeoutSecs: -1}},
+		"NotHTTP":           {hook: VariantActivationHook{BuildVariants: []string{"deploy"}, URL: "file:///etc/passwd"}},
+		"RelativeURL":       {hook: VariantActivationHook{BuildVariants: []string{"deploy"}, URL: "approve"}},
+		"Localhost":         {hook: VariantActivationHook{BuildVariants: []string{"deploy"}, URL: "http://localhost:8080/approve"}},
+		"LoopbackIP":        {hook: VariantActivationHook{BuildVariants: []string{"deploy"}, URL: "http://127.0.0.1/approve"}},
+		"PrivateIP":         {hook: VariantActivationHook{BuildVariants: []string{"deploy"}, URL: "http://10.1.2.3/approve"}},
+		"LinkLocalMetadata": {hook: VariantActivationHook{BuildVariants: []string{"deploy"}, URL: "http://169.254.169.254/latest/meta-data"}},
+		"IPv6Loopback":      {hook: VariantActivationHook{BuildVariants: []string{"deploy"}, URL: "http://[::1]/approve"}},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			err := tCase.hook.Validate()
+			if tCase.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestCallVariantActivationHookRefusesInternalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	_, err := callVariantActivationHook(context.Background(), &VariantActivationHook{URL: server.URL}, "secret", VariantActivationHookRequest{})
+	assert.Error(t, err)
+	assert.False(t, called)
+}
+
+// useTestVariantActivationHookClient allows activation hooks to call the test
+// server, which listens on a loopback address.
+func useTestVariantActivationHookClient(t *testing.T, server *httptest.Server) {
+	originalClient := variantActivationHookClient
+	variantActivationHookClient = server.Client()
+	t.Cleanup(func() {
+		variantActivationHookClient = originalClient
+	})
+}
+
+func TestCheckVariantActivationHook(t *testing.T) {
+	ctx := context.Background()
+	var received VariantActivationHookRequest
+	allow := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		signature, err := util.CalculateHMACHash([]byte("secret"), body)
+		assert.NoError(t, err)
+		assert.Equal(t, signature, r.Header.Get(VariantActivationHookSignatureHeader))
+		assert.NoError(t, json.Unmarshal(body, &received))
+		assert.NoError(t, json.NewEncoder(w).Encode(VariantActivationHookResponse{Allow: allow, Message: "change ticket"}))
+	}))
+	defer server.Close()
+	useTestVariantActivationHookClient(t, server)
+
+	pRef := &ProjectRef{
+		Id: "project",
+		VariantActivationHooks: []VariantActivationHook{
+			{BuildVariants: []string{"deploy"}, URL: server.URL},
+		},
+		VariantActivationHookSecret: "secret",
+	}
+
+	defer func() {
+		assert.NoError(t, db.Clear(build.Collection))
+	}()
+	for tName, tCase := range map[string]func(t *testing.T, b *build.Build){
+		"AllowsActivation": func(t *testing.T, b *build.Build) {
+			require.NoError(t, CheckVariantActivationHook(ctx, pRef, b, "me", false))
+			assert.Equal(t, b.Id, received.BuildID)
+			assert.Equal(t, "me", received.Caller)
+
+			dbBuild, err := build.FindOneId(b.Id)
+			require.NoError(t, err)
+			require.NotNil(t, dbBuild.ActivationHook)
+			assert.True(t, dbBuild.ActivationHook.Allowed)
+			assert.Equal(t, "change ticket", dbBuild.ActivationHook.Message)
+			assert.Equal(t, server.URL, dbBuild.ActivationHook.URL)
+		},
+		"DeniesActivation": func(t *testing.T, b *build.Build) {
+			allow = false
+			defer func() { allow = true }()
+			err := CheckVariantActivationHook(ctx, pRef, b, "me", false)
+			assert.True(t, IsVariantActivationDenied(err))
+
+			dbBuild, err := build.FindOneId(b.Id)
+			require.NoError(t, err)
+			require.NotNil(t, dbBuild.ActivationHook)
+			assert.False(t, dbBuild.ActivationHook.Allowed)
+		},
+		"DeniesActivationWhenHookFails": func(t *testing.T, b *build.Build) {
+			failingRef := &ProjectRef{
+				Id: "project",
+				VariantActivationHooks: []VariantActivationHook{
+					{BuildVariants: []string{"deploy"}, URL: server.URL + "/fail"},
+				},
+				VariantActivationHookSecret: "secret",
+			}
+			err := CheckVariantActivationHook(ctx, failingRef, b, "me", false)
+			assert.True(t, IsVariantActivationDenied(err))
+		},
+		"DeniesActivationWithoutSecret": func(t *testing.T, b *build.Build) {
+			unsignedRef := &ProjectRef{
+				Id:                     "project",
+				VariantActivationHooks: pRef.VariantActivationHooks,
+			}
+			err := CheckVariantActivationHook(ctx, unsignedRef, b, "me", false)
+			assert.True(t, IsVariantActivationDenied(err))
+		},
+		"BypassesHook": func(t *testing.T, b *build.Build) {
+			allow = false
+			defer func() { allow = true }()
+			require.NoError(t, CheckVariantActivationHook(ctx, pRef, b, "me", true))
+
+			dbBuild, err := build.FindOneId(b.Id)
+			require.NoError(t, err)
+			require.NotNil(t, dbBuild.ActivationHook)
+			assert.True(t, dbBuild.ActivationHook.Allowed)
+			assert.True(t, dbBuild.ActivationHook.Bypassed)
+		},
+		"IgnoresVariantsWithoutHooks": func(t *testing.T, b *build.Build) {
+			b.BuildVariant = "compile"
+			require.NoError(t, CheckVariantActivationHook(ctx, pRef, b, "me", false))
+			assert.Nil(t, b.ActivationHook)
+		},
+		"IgnoresActivatedBuilds": func(t *testing.T, b *build.Build) {
+			b.Activated = true
+			require.NoError(t, CheckVariantActivationHook(ctx, pRef, b, "me", false))
+			assert.Nil(t, b.ActivationHook)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.Clear(build.Collection))
+			b := &build.Build{Id: "b", Project: pRef.Id, BuildVariant: "deploy", Status: evergreen.BuildCreated}
+			require.NoError(t, b.Insert())
+			tCase(t, b)
+		})
+	}
+}
+
+func TestActivationPathsEvaluateVariantActivationHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(VariantActivationHookResponse{Allow: false, Message: "change freeze"}))
+	}))
+	defer server.Close()
+	useTestVariantActivationHookClient(t, server)
+
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ProjectRefCollection, VersionCollection, build.Collection, task.Collection))
+	}()
+	for tName, tCase := range map[string]func(t *testing.T){
+		"SetBuildActivationDeniesBuild": func(t *testing.T) {
+			err := SetBuildActivation("deploy_build", true, "me")
+			assert.True(t, IsVariantActivationDenied(err))
+
+			checkBuildAndTaskActivated(t, "deploy_build", "deploy_task", false)
+		},
+		"SetVersionActivationSkipsDeniedBuilds": func(t *testing.T) {
+			err := SetVersionActivation("v", true, "me")
+			assert.True(t, IsVariantActivationDenied(err))
+
+			checkBuildAndTaskActivated(t, "deploy_build", "deploy_task", false)
+			checkBuildAndTaskActivated(t, "compile_build", "compile_task", true)
+		},
+		"SetActiveStateSkipsTasksInDeniedBuilds": func(t *testing.T) {
+			tasks, err := task.Find(task.ByIds([]string{"deploy_task", "compile_task"}))
+			require.NoError(t, err)
+			err = SetActiveState("me", true, tasks...)
+			assert.True(t, IsVariantActivationDenied(err))
+
+			dbTask, err := task.FindOneId("deploy_task")
+			require.NoError(t, err)
+			require.NotNil(t, dbTask)
+			assert.False(t, dbTask.Activated)
+			dbTask, err = task.FindOneId("compile_task")
+			require.NoError(t, err)
+			require.NotNil(t, dbTask)
+			assert.True(t, dbTask.Activated)
+		},
+		"NewBuildIsCreatedDeactivatedWhenDenied": func(t *testing.T) {
+			pRef, err := FindBranchProjectRef("project")
+			require.NoError(t, err)
+			b := &build.Build{Id: "new_build", Project: "project", BuildVariant: "deploy", Activated: true, ActivatedTime: time.Now()}
+			checkVariantActivationHookForNewBuild(context.Background(), pRef, b, "me")
+			assert.False(t, b.Activated)
+			assert.True(t, utility.IsZeroTime(b.ActivatedTime))
+			require.NotNil(t, b.ActivationHook)
+			assert.False(t, b.ActivationHook.Allowed)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(ProjectRefCollection, VersionCollection, build.Collection, task.Collection))
+			pRef := &ProjectRef{
+				Id: "project",
+				VariantActivationHooks: []VariantActivationHook{
+					{BuildVariants: []string{"deploy"}, URL: server.URL},
+				},
+				VariantActivationHookSecret: "secret",
+			}
+			require.NoError(t, pRef.Insert())
+			v := &Version{Id: "v", Identifier: "project", BuildIds: []string{"deploy_build", "compile_build"}}
+			require.NoError(t, v.Insert())
+			for _, variant := range []string{"deploy", "compile"} {
+				b := &build.Build{Id: variant + "_build", Project: "project", Version: "v", BuildVariant: variant, Status: evergreen.BuildCreated}
+				require.NoError(t, b.Insert())
+				tsk := &task.Task{Id: variant + "_task", Project: "project", Version: "v", BuildId: b.Id, BuildVariant: variant, Status: evergreen.TaskUndispatched}
+				require.NoError(t, tsk.Insert())
+			}
+			tCase(t)
+		})
+	}
+}
+
+func checkBuildAndTaskActivated(t *testing.T, buildID, taskID string, activated bool) {
+	dbBuild, err := build.FindOneId(buildID)
+	require.NoError(t, err)
+	require.NotNil(t, dbBuild)
+	assert.Equal(t, activated, dbBuild.Activated)
+	dbTask, err := task.FindOneId(taskID)
+	require.NoError(t, err)
+	require.NotNil(t, dbTask)
+	assert.Equal(t, activated, dbTask.Activated)
+}
@@ -9,6 +9,7 @@ import (
 	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
@@ -39,6 +40,18 @@ type Version struct {
 	PeriodicBuildID     string               `bson:"periodic_build_id,omitempty" json:"periodic_build_id,omitempty"`
 	Aborted             bool                 `bson:"aborted,omitempty" json:"aborted,omitempty"`
 
+	// ArchivedAt is when the version's builds and tasks were moved into cold
+	// storage. It is only set for archived versions.
+	ArchivedAt time.Time `bson:"archived_at,omitempty" json:"archived_at,omitempty"`
+
+	// Timing is the time the version's tasks spent blocked, queued, and running.
+	Timing task.TimingBreakdown `bson:"timing,omitempty" json:"timing,omitempty"`
+
+	// RollupCounts are the number of the version's builds counted in each
+	// status rollup counter. They are only set for projects that use
+	// event-sourced status rollups.
+	RollupCounts map[string]int `bson:"rollup_counts,omitempty" json:"-"`
+
 	// This stores whether or not a version has tasks which were activated.
 	// We use a bool ptr in order to to distinguish the unset value from the default value
 	Activated *bool `bson:"activated,omitempty" json:"activated,omitempty"`
@@ -49,6 +62,12 @@ type Version struct {
 
 	// Parameters stores user-defined parameters
 	Parameters []patch.Parameter `bson:"parameters,omitempty" json:"parameters,omitempty"`
+	// ExpansionOverrides are expansions given when the version was created
+	// that override the value of the expansion for all of its tasks.
+	ExpansionOverrides map[string]string `bson:"expansion_overrides,omitempty" json:"expansion_overrides,omitempty"`
+	// Labels are user-defined key/value pairs that mark the version, such as
+	// a release candidate.
+	Labels []patch.Label `bson:"labels,omitempty" json:"labels,omitempty"`
 	// This is technically redundant, but a lot of code relies on it, so I'm going to leave it
 	BuildIds []string `bson:"builds" json:"builds,omitempty"`
 
@@ -71,6 +90,25 @@ type Version struct {
 	// this field is omitted in the database
 	Errors   []string `bson:"errors,omitempty" json:"errors,omitempty"`
 	Warnings []string `bson:"warnings,omitempty" json:"warnings,omitempty"`
+	// WarningBudget compares the version's warnings against the project's
+	// warning budget. It is only set if the project has a warning budget.
+	WarningBudget *VersionWarningBudget `bson:"warning_budget,omitempty" json:"warning_budget,omitempty"`
+	// MatchedPaths are the files changed by the version's commit that match
+	// the project's watched paths. It is only set if the project watches
+	// paths.
+	MatchedPaths []string `bson:"matched_paths,omitempty" json:"matched_paths,omitempty"`
+	// ExternalGates are the gates that must be opened before the version's
+	// tasks, or the tasks of some of its build variants, can be dispatched.
+	ExternalGates []VersionExternalGate `bson:"external_gates,omitempty" json:"external_gates,omitempty"`
+	// PendingBlockedTasks are the tasks blocked by failed dependencies whose
+	// owners haven't been notified yet.
+	PendingBlockedTasks []event.BlockedTask `bson:"pending_blocked_tasks,omitempty" json:"-"`
+	// PendingBlockedTasksSince is when the first of the pending blocked tasks
+	// was blocked.
+	PendingBlockedTasksSince time.Time `bson:"pending_blocked_tasks_since,omitempty" json:"-"`
+	// Stages are the project's pipeline stages when the version was created,
+	// with task groups expanded into their tasks.
+	Stages []PipelineStage `bson:"stages,omitempty" json:"stages,omitempty"`
 
 	// AuthorID is an optional reference to the Evergreen user that authored
 	// this comment, if they can be identified
@@ -81,11 +119,31 @@ type Version struct {
 	TriggerID    string `bson:"trigger_id,omitempty" json:"trigger_id,omitempty"`
 	TriggerType  string `bson:"trigger_type,omitempty" json:"trigger_type,omitempty"`
 	TriggerEvent string `bson:"trigger_event,omitempty" json:"trigger_event,omitempty"`
+	// TriggerDepth is the number of upstream versions in the chain of
+	// project triggers that created this version.
+	TriggerDepth int `bson:"trigger_depth,omitempty" json:"trigger_depth,omitempty"`
+	// UpstreamVersionID is the version whose task or build triggered this
+	// version.
+	UpstreamVersionID string `bson:"upstream_version_id,omitempty" json:"upstream_version_id,omitempty"`
+	// DownstreamVersionIDs are the versions that this version's tasks and
+	// builds triggered.
+	DownstreamVersionIDs []string `bson:"downstream_version_ids,omitempty" json:"downstream_version_ids,omitempty"`
 
 	// this is only used for aggregations, and is not stored in the DB
 	Builds []build.Build `bson:"build_variants,omitempty" json:"build_variants,omitempty"`
 }
 
+// GetTriggerDepth returns the number of project triggers between this version
+// and the mainline commit that started the trigger chain.
+func (v *Version) GetTriggerDepth() int {
+	// Triggered versions created before the depth was tracked only ever came
+	// from mainline commits.
+	if v.Requester == evergreen.TriggerRequester && v.TriggerDepth == 0 {
+		return 1
+	}
+	return v.TriggerDepth
+}
+
 func (v *Version) MarshalBSON() ([]byte, error)  { return mgobson.Marshal(v) }
 func (v *Version) UnmarshalBSON(in []byte) error { return mgobson.Unmarshal(in, v) }
 
@@ -104,6 +162,9 @@ type GetVersionsOptions struct {
 	IncludeTasks   bool   `json:"include_tasks"`
 	ByBuildVariant string `json:"by_build_variant"`
 	ByTask         string `json:"by_task"`
+	// Labels filters the versions to those that have all of the labels.
+	// Labels without a value match any label with the key.
+	Labels []patch.Label `json:"labels"`
 }
 
 func (v *Version) LastSuccessful() (*Version, error) {
@@ -173,6 +234,43 @@ func (v *Version) SetAborted(aborted bool) error {
 	)
 }
 
+// SetTimingBreakdown sets the time the version's tasks spent blocked,
+// queued, and running.
+func (v *Version) SetTimingBreakdown(timing task.TimingBreakdown) error {
+	if v.Timing == timing {
+		return nil
+	}
+	v.Timing = timing
+	return VersionUpdateOne(
+		bson.M{VersionIdKey: v.Id},
+		bson.M{
+			"$set": bson.M{
+				VersionTimingKey: timing,
+			},
+		},
+	)
+}
+
+// UpdateLabels removes the labels with the keys to remove and sets the labels
+// to set on the version, replacing any existing labels with the same keys.
+func (v *Version) UpdateLabels(set map[string]string, remove []string) error {
+	for _, update := range patch.LabelUpdates(VersionLabelsKey, set, remove) {
+		if err := VersionUpdateOne(bson.M{VersionIdKey: v.Id}, update); err != nil {
+			return err
+		}
+	}
+	dbVersion, err := VersionFindOne(VersionById(v.Id).WithFields(VersionLabelsKey))
+	if err != nil {
+		return errors.Wrap(err, "finding updated labels")
+	}
+	if dbVersion == nil {
+		return errors.Errorf("version '%s' not found", v.Id)
+	}
+	v.Labels = dbVersion.Labels
+	patch.SortLabels(v.Labels)
+	return nil
+}
+
 func (v *Version) Insert() error {
 	return db.Insert(VersionCollection, v)
 }
@@ -202,6 +300,21 @@ func (v *Version) AddSatisfiedTrigger(definitionID string) error {
 	return errors.Wrap(AddSatisfiedTrigger(v.Id, definitionID), "adding satisfied trigger")
 }
 
+// AddDownstreamVersion records that the version triggered the given
+// downstream version.
+func (v *Version) AddDownstreamVersion(downstreamVersionID string) error {
+	if utility.StringSliceContains(v.DownstreamVersionIDs, downstreamVersionID) {
+		return nil
+	}
+	v.DownstreamVersionIDs = append(v.DownstreamVersionIDs, downstreamVersionID)
+	return errors.Wrap(VersionUpdateOne(bson.M{VersionIdKey: v.Id},
+		bson.M{
+			"$addToSet": bson.M{
+				VersionDownstreamVersionsKey: downstreamVersionID,
+			},
+		}), "adding downstream version")
+}
+
 func (v *Version) UpdateStatus(newStatus string) error {
 	if v.Status == newStatus {
 		return nil
@@ -296,6 +409,11 @@ type VersionMetadata struct {
 	PeriodicBuildID     string
 	RemotePath          string
 	GitTag              GitTag
+	GithubMergeGroup    GithubMergeGroup
+	Labels              []patch.Label
+	MatchedPaths        []string
+	Parameters          []patch.Parameter
+	ExpansionOverrides  map[string]string
 }
 
 var (
@@ -515,6 +633,9 @@ func GetVersionsWithOptions(projectName string, opts GetVersionsOptions) ([]Vers
 	if opts.ByBuildVariant != "" {
 		match[bsonutil.GetDottedKeyName(VersionBuildVariantsKey, VersionBuildStatusVariantKey)] = opts.ByBuildVariant
 	}
+	if len(opts.Labels) > 0 {
+		match[VersionLabelsKey] = patch.LabelsMatch(opts.Labels)
+	}
 
 	if opts.StartAfter > 0 {
 		match[VersionRevisionOrderNumberKey] = bson.M{"$lt": opts.StartAfter}
@@ -536,6 +657,7 @@ func GetVersionsWithOptions(projectName string, opts GetVersionsOptions) ([]Vers
 		VersionErrorsKey:              1,
 		VersionRevisionOrderNumberKey: 1,
 		VersionRequesterKey:           1,
+		VersionLabelsKey:              1,
 	}
 
 	pipeline = append(pipeline, bson.M{"$project": project})
@@ -601,6 +723,12 @@ func GetVersionsWithOptions(projectName string, opts GetVersionsOptions) ([]Vers
 
 	res := []Version{}
 
+	if len(opts.Labels) > 0 {
+		if err := db.AggregateWithHint(VersionCollection, pipeline, VersionLabelsIndex, &res); err != nil {
+			return nil, errors.Wrap(err, "aggregating versions and builds by label")
+		}
+		return res, nil
+	}
 	if err := db.Aggregate(VersionCollection, pipeline, &res); err != nil {
 		return nil, errors.Wrap(err, "aggregating versions and builds")
 	}
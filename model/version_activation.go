@@ -1,14 +1,17 @@
 package model
 
 import (
+	"context"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func DoProjectActivation(id string, ts time.Time) (bool, error) {
@@ -25,6 +28,31 @@ func DoProjectActivation(id string, ts time.Time) (bool, error) {
 		})
 		return false, nil
 	}
+	projectRef, err := FindMergedProjectRef(id, activateVersion.Id, false)
+	if err != nil {
+		return false, errors.Wrapf(err, "finding project '%s'", id)
+	}
+	if projectRef != nil {
+		numTasks, err := countElapsedTasksToActivate(activateVersion, time.Now())
+		if err != nil {
+			return false, errors.Wrapf(err, "counting tasks to activate in version '%s'", activateVersion.Id)
+		}
+		if err = CheckVersionActivationQuotas(projectRef, activateVersion, numTasks); err != nil {
+			if !IsProjectQuotaExceeded(err) {
+				return false, errors.Wrapf(err, "checking quotas for version '%s'", activateVersion.Id)
+			}
+			// Being over quota is expected to happen, so the project is
+			// skipped until it has room for the version rather than failing
+			// the activation pass.
+			grip.Info(message.WrapError(err, message.Fields{
+				"message":   "skipping activation for project over its quota",
+				"project":   id,
+				"version":   activateVersion.Id,
+				"operation": "project-activation",
+			}))
+			return false, nil
+		}
+	}
 	activated, err := ActivateElapsedBuildsAndTasks(activateVersion)
 	if err != nil {
 		return false, errors.WithStack(err)
@@ -34,11 +62,53 @@ func DoProjectActivation(id string, ts time.Time) (bool, error) {
 
 }
 
+// countElapsedTasksToActivate returns the number of tasks that
+// ActivateElapsedBuildsAndTasks would schedule in the version.
+func countElapsedTasksToActivate(v *Version, now time.Time) (int, error) {
+	numTasks := 0
+	for _, bv := range v.BuildVariants {
+		ignoreTasks := []string{}
+		readyTasks := []string{}
+		for _, t := range bv.BatchTimeTasks {
+			if t.ShouldActivate(now) {
+				readyTasks = append(readyTasks, t.TaskId)
+			} else {
+				ignoreTasks = append(ignoreTasks, t.TaskId)
+			}
+		}
+
+		var q bson.M
+		if bv.ShouldActivate(now) {
+			q = bson.M{
+				task.BuildIdKey: bv.BuildId,
+				task.IdKey:      bson.M{"$nin": ignoreTasks},
+			}
+		} else if len(readyTasks) > 0 {
+			q = bson.M{task.IdKey: bson.M{"$in": readyTasks}}
+		} else {
+			continue
+		}
+		q[task.ActivatedKey] = false
+		q[task.StatusKey] = evergreen.TaskUndispatched
+		n, err := task.Count(db.Query(q))
+		if err != nil {
+			return 0, errors.Wrapf(err, "counting tasks to activate in build '%s'", bv.BuildId)
+		}
+		numTasks += n
+	}
+	return numTasks, nil
+}
+
 // Activates any builds/tasks if their BatchTimes have elapsed.
 func ActivateElapsedBuildsAndTasks(v *Version) (bool, error) {
 	hasActivated := false
 	now := time.Now()
 
+	projectRef, err := FindMergedProjectRef(v.Identifier, v.Id, false)
+	if err != nil {
+		return false, errors.Wrapf(err, "finding project '%s'", v.Identifier)
+	}
+
 	for i, bv := range v.BuildVariants {
 		// if there are batchtime tasks, consider if these should/shouldn't be activated, regardless of build
 		ignoreTasks := []string{}
@@ -65,6 +135,25 @@ func ActivateElapsedBuildsAndTasks(v *Version) (bool, error) {
 			})
 			continue
 		}
+		if !bv.Activated && projectRef != nil && projectRef.GetVariantActivationHook(bv.BuildVariant) != nil {
+			b, err := build.FindOneId(bv.BuildId)
+			if err != nil {
+				return false, errors.Wrapf(err, "finding build '%s'", bv.BuildId)
+			}
+			if b == nil {
+				return false, errors.Errorf("build '%s' not found", bv.BuildId)
+			}
+			if err = CheckVariantActivationHook(context.Background(), projectRef, b, evergreen.DefaultTaskActivator, false); err != nil {
+				grip.Error(message.WrapError(err, message.Fields{
+					"message":   "not activating build",
+					"operation": "project-activation",
+					"variant":   bv.BuildVariant,
+					"build":     bv.BuildId,
+					"project":   v.Identifier,
+				}))
+				continue
+			}
+		}
 		hasActivated = true
 		grip.Info(message.Fields{
 			"message":   "activating revision",
@@ -0,0 +1,601 @@
+package model
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// ArchivedBuildsCollection is the cold storage collection for the builds
+	// of versions that have been archived.
+	ArchivedBuildsCollection = "archived_builds"
+	// ArchivedTasksCollection is the cold storage collection for the tasks of
+	// versions that have been archived.
+	ArchivedTasksCollection = "archived_tasks"
+)
+
+// ArchivedBuild holds a build after it has been moved out of the build
+// collection. The build's tasks are archived separately as ArchivedTasks so
+// that a build with many tasks can't exceed the maximum document size. The
+// document is stored compressed, so only the fields needed to find an archived
+// build are kept uncompressed.
+type ArchivedBuild struct {
+	Id         string    `bson:"_id"`
+	Version    string    `bson:"version"`
+	Project    string    `bson:"project"`
+	ArchivedAt time.Time `bson:"archived_at"`
+	// Data is the gzip-compressed BSON of the build document.
+	Data []byte `bson:"data"`
+}
+
+// ArchivedTask holds a task and all of its previous executions after they
+// have been moved out of the task collections.
+type ArchivedTask struct {
+	Id         string    `bson:"_id"`
+	BuildId    string    `bson:"build_id"`
+	Version    string    `bson:"version"`
+	Project    string    `bson:"project"`
+	Status     string    `bson:"status"`
+	ArchivedAt time.Time `bson:"archived_at"`
+	// Data is the gzip-compressed BSON of the archived documents.
+	Data []byte `bson:"data"`
+}
+
+var (
+	ArchivedBuildIdKey      = bsonutil.MustHaveTag(ArchivedBuild{}, "Id")
+	ArchivedBuildProjectKey = bsonutil.MustHaveTag(ArchivedBuild{}, "Project")
+
+	ArchivedTaskIdKey      = bsonutil.MustHaveTag(ArchivedTask{}, "Id")
+	ArchivedTaskBuildIdKey = bsonutil.MustHaveTag(ArchivedTask{}, "BuildId")
+	ArchivedTaskProjectKey = bsonutil.MustHaveTag(ArchivedTask{}, "Project")
+	ArchivedTaskStatusKey  = bsonutil.MustHaveTag(ArchivedTask{}, "Status")
+)
+
+// archivedTaskDocuments are the original documents of an archived task.
+// They are kept as raw BSON so that archiving does not drop any fields.
+type archivedTaskDocuments struct {
+	Task     bson.Raw   `bson:"task"`
+	OldTasks []bson.Raw `bson:"old_tasks"`
+}
+
+// ArchivedBuildContents are the decompressed documents of an archived build
+// and its tasks.
+type ArchivedBuildContents struct {
+	Build build.Build
+	Tasks []task.Task
+	// OldTasks are the previous executions of the build's tasks.
+	OldTasks []task.Task
+}
+
+// VersionArchiveResult summarizes the documents moved into cold storage for
+// a version.
+type VersionArchiveResult struct {
+	Builds   int
+	Tasks    int
+	OldTasks int
+}
+
+// GetArchiveVersionsAfter returns how long after they are created finished
+// versions are archived, or zero if the project does not archive versions.
+func (p *ProjectRef) GetArchiveVersionsAfter() time.Duration {
+	if p.ArchiveVersionsAfterDays <= 0 {
+		return 0
+	}
+	return time.Duration(p.ArchiveVersionsAfterDays) * 24 * time.Hour
+}
+
+// FindVersionsToArchive returns up to limit of the project's finished
+// versions that were created before the cutoff and have not been archived.
+func FindVersionsToArchive(projectId string, cutoff time.Time, limit int) ([]Version, error) {
+	return VersionFind(db.Query(bson.M{
+		VersionIdentifierKey: projectId,
+		VersionCreateTimeKey: bson.M{"$lt": cutoff},
+		VersionStatusKey:     bson.M{"$in": []string{evergreen.VersionSucceeded, evergreen.VersionFailed}},
+		VersionArchivedAtKey: bson.M{"$exists": false},
+	}).WithFields(VersionIdKey, VersionIdentifierKey, VersionStatusKey, VersionBuildIdsKey).Sort([]string{VersionCreateTimeKey}).Limit(limit))
+}
+
+// ArchiveVersion moves the builds and tasks of a finished version into cold
+// storage. Each document is written to cold storage before it's removed, so
+// archiving a version that was partially archived picks up where it left off.
+// Documents that change while they're being archived, such as a task that is
+// restarted, are left in place and the version is not marked archived, so
+// it's archived again once it finishes.
+func ArchiveVersion(ctx context.Context, env evergreen.Environment, v *Version, now time.Time) (VersionArchiveResult, error) {
+	res := VersionArchiveResult{}
+	if !evergreen.IsFinishedVersionStatus(v.Status) {
+		return res, errors.Errorf("version '%s' is not finished", v.Id)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for _, buildId := range v.BuildIds {
+		if ctx.Err() != nil {
+			catcher.Add(ctx.Err())
+			break
+		}
+		buildRes, err := archiveBuild(ctx, env, v, buildId, now)
+		if err != nil {
+			catcher.Wrapf(err, "archiving build '%s'", buildId)
+			continue
+		}
+		res.Builds += buildRes.Builds
+		res.Tasks += buildRes.Tasks
+		res.OldTasks += buildRes.OldTasks
+	}
+	if catcher.HasErrors() {
+		return res, catcher.Resolve()
+	}
+
+	_, err := env.DB().Collection(VersionCollection).UpdateOne(ctx,
+		bson.M{VersionIdKey: v.Id},
+		bson.M{"$set": bson.M{VersionArchivedAtKey: now}},
+	)
+	return res, errors.Wrap(err, "marking version archived")
+}
+
+func archiveBuild(ctx context.Context, env evergreen.Environment, v *Version, buildId string, now time.Time) (VersionArchiveResult, error) {
+	res := VersionArchiveResult{}
+	buildDoc, err := env.DB().Collection(build.Collection).FindOne(ctx, bson.M{build.IdKey: buildId}).DecodeBytes()
+	if err == mongo.ErrNoDocuments {
+		// The build was already archived.
+		return res, nil
+	}
+	if err != nil {
+		return res, errors.Wrap(err, "finding build")
+	}
+
+	data, err := compressArchivedDocuments(buildDoc)
+	if err != nil {
+		return res, errors.Wrap(err, "compressing build")
+	}
+	archived := ArchivedBuild{
+		Id:         buildId,
+		Version:    v.Id,
+		Project:    v.Identifier,
+		ArchivedAt: now,
+		Data:       data,
+	}
+	if _, err = env.DB().Collection(ArchivedBuildsCollection).ReplaceOne(ctx, bson.M{ArchivedBuildIdKey: buildId}, archived, options.Replace().SetUpsert(true)); err != nil {
+		return res, errors.Wrap(err, "writing build to cold storage")
+	}
+
+	// Tasks that an earlier attempt already moved into cold storage may
+	// still have previous executions in the hot collection, so they're
+	// archived again too.
+	taskIds, err := env.DB().Collection(task.Collection).Distinct(ctx, task.IdKey, bson.M{task.BuildIdKey: buildId})
+	if err != nil {
+		return res, errors.Wrap(err, "finding tasks")
+	}
+	archivedTaskIds, err := env.DB().Collection(ArchivedTasksCollection).Distinct(ctx, ArchivedTaskIdKey, bson.M{ArchivedTaskBuildIdKey: buildId})
+	if err != nil {
+		return res, errors.Wrap(err, "finding previously archived tasks")
+	}
+	seen := map[string]bool{}
+	changed := 0
+	for _, id := range append(taskIds, archivedTaskIds...) {
+		taskId, ok := id.(string)
+		if !ok || seen[taskId] {
+			continue
+		}
+		seen[taskId] = true
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+		taskRes, taskChanged, err := archiveTask(ctx, env, v, buildId, taskId, now)
+		if err != nil {
+			return res, errors.Wrapf(err, "archiving task '%s'", taskId)
+		}
+		res.Tasks += taskRes.Tasks
+		res.OldTasks += taskRes.OldTasks
+		changed += taskChanged
+	}
+	if changed > 0 {
+		return res, errors.Errorf("%d tasks and previous task executions changed while the build was being archived", changed)
+	}
+
+	// The build is removed last, so a build that still has documents in the
+	// hot collections is archived again on the next attempt.
+	changedBuilds, err := removeUnchangedDocuments(ctx, env.DB().Collection(build.Collection), []bson.Raw{buildDoc})
+	if err != nil {
+		return res, errors.Wrap(err, "removing build")
+	}
+	if changedBuilds > 0 {
+		return res, errors.New("build changed while it was being archived")
+	}
+
+	res.Builds = 1
+	return res, nil
+}
+
+// archiveTask moves a task and its previous executions into cold storage. It
+// returns how many of the task's documents changed while they were being
+// archived and were left in place.
+func archiveTask(ctx context.Context, env evergreen.Environment, v *Version, buildId, taskId string, now time.Time) (VersionArchiveResult, int, error) {
+	res := VersionArchiveResult{}
+	liveTask, err := env.DB().Collection(task.Collection).FindOne(ctx, bson.M{task.IdKey: taskId}).DecodeBytes()
+	if err != nil && err != mongo.ErrNoDocuments {
+		return res, 0, errors.Wrap(err, "finding task")
+	}
+	liveOldTasks, err := findRawDocuments(ctx, env.DB().Collection(task.OldCollection), bson.M{task.OldTaskIdKey: taskId})
+	if err != nil {
+		return res, 0, errors.Wrap(err, "finding previous task executions")
+	}
+	if liveTask == nil && len(liveOldTasks) == 0 {
+		return res, 0, nil
+	}
+
+	// Documents that an earlier attempt already moved into cold storage are
+	// only in the archived task, so they're kept unless they're also still
+	// in the hot collections.
+	docs := archivedTaskDocuments{Task: liveTask, OldTasks: liveOldTasks}
+	previous, err := findOneArchivedTask(db.Query(bson.M{ArchivedTaskIdKey: taskId}))
+	if err != nil {
+		return res, 0, errors.Wrap(err, "finding previously archived task")
+	}
+	if previous != nil {
+		previousDocs, err := previous.unpackDocuments()
+		if err != nil {
+			return res, 0, errors.Wrap(err, "unpacking previously archived task")
+		}
+		if docs.Task == nil {
+			docs.Task = previousDocs.Task
+		}
+		docs.OldTasks = mergeRawDocuments(previousDocs.OldTasks, docs.OldTasks)
+	}
+	if docs.Task == nil {
+		return res, 0, errors.New("task has previous executions but no latest execution")
+	}
+
+	data, err := compressArchivedDocuments(docs)
+	if err != nil {
+		return res, 0, errors.Wrap(err, "compressing task")
+	}
+	status, _ := docs.Task.Lookup(task.StatusKey).StringValueOK()
+	archived := ArchivedTask{
+		Id:         taskId,
+		BuildId:    buildId,
+		Version:    v.Id,
+		Project:    v.Identifier,
+		Status:     status,
+		ArchivedAt: now,
+		Data:       data,
+	}
+	if _, err = env.DB().Collection(ArchivedTasksCollection).ReplaceOne(ctx, bson.M{ArchivedTaskIdKey: taskId}, archived, options.Replace().SetUpsert(true)); err != nil {
+		return res, 0, errors.Wrap(err, "writing task to cold storage")
+	}
+
+	// Each document is only removed if it hasn't changed since it was read,
+	// so that a change made in the meantime isn't lost.
+	changed := 0
+	if liveTask != nil {
+		changed, err = removeUnchangedDocuments(ctx, env.DB().Collection(task.Collection), []bson.Raw{liveTask})
+		if err != nil {
+			return res, changed, errors.Wrap(err, "removing task")
+		}
+		res.Tasks = 1
+	}
+	changedOldTasks, err := removeUnchangedDocuments(ctx, env.DB().Collection(task.OldCollection), liveOldTasks)
+	if err != nil {
+		return res, changed + changedOldTasks, errors.Wrap(err, "removing previous task executions")
+	}
+	res.OldTasks = len(liveOldTasks)
+	return res, changed + changedOldTasks, nil
+}
+
+// removeUnchangedDocuments removes each of the documents from the collection
+// if it still matches the given copy of it exactly, and returns how many of
+// the documents had changed.
+func removeUnchangedDocuments(ctx context.Context, coll *mongo.Collection, docs []bson.Raw) (int, error) {
+	changed := 0
+	for _, doc := range docs {
+		res, err := coll.DeleteOne(ctx, doc)
+		if err != nil {
+			return changed, err
+		}
+		if res.DeletedCount == 0 {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// mergeRawDocuments returns the archived documents along with the live ones,
+// where a live document replaces the archived document with the same ID.
+func mergeRawDocuments(archived, live []bson.Raw) []bson.Raw {
+	liveIds := map[string]bool{}
+	for _, doc := range live {
+		if id, ok := doc.Lookup("_id").StringValueOK(); ok {
+			liveIds[id] = true
+		}
+	}
+	merged := make([]bson.Raw, 0, len(archived)+len(live))
+	for _, doc := range archived {
+		if id, ok := doc.Lookup("_id").StringValueOK(); ok && liveIds[id] {
+			continue
+		}
+		merged = append(merged, doc)
+	}
+	return append(merged, live...)
+}
+
+func findRawDocuments(ctx context.Context, coll *mongo.Collection, filter bson.M) ([]bson.Raw, error) {
+	cur, err := coll.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	docs := []bson.Raw{}
+	for cur.Next(ctx) {
+		// The cursor reuses its buffer, so each document must be copied.
+		docs = append(docs, append(bson.Raw{}, cur.Current...))
+	}
+	return docs, cur.Err()
+}
+
+func compressArchivedDocuments(docs interface{}) ([]byte, error) {
+	raw, err := bson.Marshal(docs)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling documents")
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err = w.Write(raw); err != nil {
+		return nil, errors.Wrap(err, "writing compressed documents")
+	}
+	if err = w.Close(); err != nil {
+		return nil, errors.Wrap(err, "closing compressed writer")
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressArchivedDocuments(data []byte, out interface{}) error {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "reading compressed documents")
+	}
+	defer r.Close()
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return errors.Wrap(err, "decompressing documents")
+	}
+	return errors.Wrap(bson.Unmarshal(raw, out), "unmarshalling documents")
+}
+
+func (t *ArchivedTask) unpackDocuments() (*archivedTaskDocuments, error) {
+	docs := &archivedTaskDocuments{}
+	if err := decompressArchivedDocuments(t.Data, docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// Unpack decompresses the task and its previous executions.
+func (t *ArchivedTask) Unpack() (*task.Task, []task.Task, error) {
+	docs, err := t.unpackDocuments()
+	if err != nil {
+		return nil, nil, err
+	}
+	latest := &task.Task{}
+	if err = bson.Unmarshal(docs.Task, latest); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshalling task")
+	}
+	oldTasks, err := unmarshalArchivedTasks(docs.OldTasks)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshalling previous task executions")
+	}
+	return latest, oldTasks, nil
+}
+
+// Unpack decompresses the archived build.
+func (b *ArchivedBuild) Unpack() (*build.Build, error) {
+	archivedBuild := &build.Build{}
+	if err := decompressArchivedDocuments(b.Data, archivedBuild); err != nil {
+		return nil, err
+	}
+	return archivedBuild, nil
+}
+
+func unmarshalArchivedTasks(docs []bson.Raw) ([]task.Task, error) {
+	tasks := make([]task.Task, 0, len(docs))
+	for _, doc := range docs {
+		t := task.Task{}
+		if err := bson.Unmarshal(doc, &t); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// findOneArchivedBuild returns the archived build matching the query, or nil
+// if there is none.
+func findOneArchivedBuild(query db.Q) (*ArchivedBuild, error) {
+	archived := &ArchivedBuild{}
+	err := db.FindOneQ(ArchivedBuildsCollection, query, archived)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return archived, err
+}
+
+// findOneArchivedTask returns the archived task matching the query, or nil if
+// there is none.
+func findOneArchivedTask(query db.Q) (*ArchivedTask, error) {
+	archived := &ArchivedTask{}
+	err := db.FindOneQ(ArchivedTasksCollection, query, archived)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return archived, err
+}
+
+// FindArchivedBuild returns the contents of the build and all of its tasks
+// from cold storage, or nil if the build has not been archived.
+func FindArchivedBuild(buildId string) (*ArchivedBuildContents, error) {
+	archived, err := findOneArchivedBuild(db.Query(bson.M{ArchivedBuildIdKey: buildId}))
+	if err != nil || archived == nil {
+		return nil, err
+	}
+	archivedBuild, err := archived.Unpack()
+	if err != nil {
+		return nil, errors.Wrap(err, "unpacking build")
+	}
+	contents := &ArchivedBuildContents{Build: *archivedBuild}
+
+	archivedTasks := []ArchivedTask{}
+	if err = db.FindAllQ(ArchivedTasksCollection, db.Query(bson.M{ArchivedTaskBuildIdKey: buildId}).Sort([]string{ArchivedTaskIdKey}), &archivedTasks); err != nil {
+		return nil, errors.Wrap(err, "finding archived tasks")
+	}
+	for _, archivedTask := range archivedTasks {
+		latest, oldTasks, err := archivedTask.Unpack()
+		if err != nil {
+			return nil, errors.Wrapf(err, "unpacking task '%s'", archivedTask.Id)
+		}
+		contents.Tasks = append(contents.Tasks, *latest)
+		contents.OldTasks = append(contents.OldTasks, oldTasks...)
+	}
+	return contents, nil
+}
+
+// FindArchivedTask returns the task from cold storage, or nil if the task has
+// not been archived. If execution is negative, the latest execution is
+// returned.
+func FindArchivedTask(taskId string, execution int) (*task.Task, error) {
+	archived, err := findOneArchivedTask(db.Query(bson.M{ArchivedTaskIdKey: taskId}))
+	if err != nil || archived == nil {
+		return nil, err
+	}
+	latest, oldTasks, err := archived.Unpack()
+	if err != nil {
+		return nil, err
+	}
+	if execution < 0 || latest.Execution == execution {
+		return latest, nil
+	}
+	for _, t := range oldTasks {
+		if t.Execution == execution {
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindArchivedTaskExecutions returns the previous executions of the task from
+// cold storage.
+func FindArchivedTaskExecutions(taskId string) ([]task.Task, error) {
+	archived, err := findOneArchivedTask(db.Query(bson.M{ArchivedTaskIdKey: taskId}))
+	if err != nil || archived == nil {
+		return nil, err
+	}
+	_, oldTasks, err := archived.Unpack()
+	return oldTasks, err
+}
+
+// FindArchivedTaskExecutionsMatching returns the previous executions of the
+// task from cold storage that match the options, in order of execution.
+func FindArchivedTaskExecutionsMatching(opts task.ArchivedExecutionsOptions) ([]task.Task, error) {
+	executions, err := FindArchivedTaskExecutions(opts.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	matching := []task.Task{}
+	for _, t := range executions {
+		if opts.Matches(&t) {
+			matching = append(matching, t)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].Execution < matching[j].Execution })
+	if opts.Limit > 0 && len(matching) > opts.Limit {
+		matching = matching[:opts.Limit]
+	}
+	return matching, nil
+}
+
+// FindArchivedBuildTasks returns the tasks of the build from cold storage
+// that match the status, if one is given, sorted by ID starting at startAt.
+// It pages through archived tasks the same way TasksByBuildIdPipeline pages
+// through the tasks collection. It also returns whether the build has been
+// archived.
+func FindArchivedBuildTasks(buildId, startAt, status string, limit int) ([]task.Task, bool, error) {
+	isArchived, err := IsBuildArchived(buildId)
+	if err != nil || !isArchived {
+		return nil, false, err
+	}
+	filter := bson.M{
+		ArchivedTaskBuildIdKey: buildId,
+		ArchivedTaskIdKey:      bson.M{"$gte": startAt},
+	}
+	if status != "" {
+		filter[ArchivedTaskStatusKey] = status
+	}
+	q := db.Query(filter).Sort([]string{ArchivedTaskIdKey})
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	archivedTasks := []ArchivedTask{}
+	if err = db.FindAllQ(ArchivedTasksCollection, q, &archivedTasks); err != nil {
+		return nil, true, errors.Wrap(err, "finding archived tasks")
+	}
+	tasks := make([]task.Task, 0, len(archivedTasks))
+	for _, archivedTask := range archivedTasks {
+		latest, _, err := archivedTask.Unpack()
+		if err != nil {
+			return nil, true, errors.Wrapf(err, "unpacking task '%s'", archivedTask.Id)
+		}
+		tasks = append(tasks, *latest)
+	}
+	return tasks, true, nil
+}
+
+// IsBuildArchived returns whether the build has been moved into cold storage.
+func IsBuildArchived(buildId string) (bool, error) {
+	archived, err := findOneArchivedBuild(db.Query(bson.M{ArchivedBuildIdKey: buildId}).WithFields(ArchivedBuildIdKey))
+	return archived != nil, err
+}
+
+// IsTaskArchived returns whether the task has been moved into cold storage.
+func IsTaskArchived(taskId string) (bool, error) {
+	archived, err := findOneArchivedTask(db.Query(bson.M{ArchivedTaskIdKey: taskId}).WithFields(ArchivedTaskIdKey))
+	return archived != nil, err
+}
+
+// FindProjectForArchivedBuild returns the project of a build in cold storage.
+func FindProjectForArchivedBuild(buildId string) (string, error) {
+	archived, err := findOneArchivedBuild(db.Query(bson.M{ArchivedBuildIdKey: buildId}).WithFields(ArchivedBuildProjectKey))
+	if err != nil {
+		return "", err
+	}
+	if archived == nil {
+		return "", errors.New("build not found")
+	}
+	return archived.Project, nil
+}
+
+// FindProjectForArchivedTask returns the project of a task in cold storage.
+func FindProjectForArchivedTask(taskId string) (string, error) {
+	archived, err := findOneArchivedTask(db.Query(bson.M{ArchivedTaskIdKey: taskId}).WithFields(ArchivedTaskProjectKey))
+	if err != nil {
+		return "", err
+	}
+	if archived == nil {
+		return "", errors.New("task not found")
+	}
+	return archived.Project, nil
+}
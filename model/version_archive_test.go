@@ -0,0 +1,225 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestArchiveVersion(t *testing.T) {
+	env := evergreen.GetEnvironment()
+	ctx, cancel := env.Context()
+	defer cancel()
+
+	require.NoError(t, db.ClearCollections(VersionCollection, build.Collection, task.Collection, task.OldCollection, ArchivedBuildsCollection, ArchivedTasksCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection, build.Collection, task.Collection, task.OldCollection, ArchivedBuildsCollection, ArchivedTasksCollection))
+	}()
+
+	now := time.Now()
+	versions := []Version{
+		{Id: "old", Identifier: "p1", Status: evergreen.VersionSucceeded, CreateTime: now.Add(-40 * 24 * time.Hour), BuildIds: []string{"b1"}},
+		{Id: "recent", Identifier: "p1", Status: evergreen.VersionSucceeded, CreateTime: now.Add(-time.Hour)},
+		{Id: "unfinished", Identifier: "p1", Status: evergreen.VersionStarted, CreateTime: now.Add(-40 * 24 * time.Hour)},
+		{Id: "other_project", Identifier: "p2", Status: evergreen.VersionFailed, CreateTime: now.Add(-40 * 24 * time.Hour)},
+	}
+	for _, v := range versions {
+		require.NoError(t, v.Insert())
+	}
+	b := build.Build{Id: "b1", Version: "old", Project: "p1", BuildVariant: "bv"}
+	require.NoError(t, b.Insert())
+	for _, tsk := range []task.Task{
+		{Id: "t1", BuildId: "b1", Version: "old", Project: "p1", Execution: 1, Status: evergreen.TaskSucceeded},
+		{Id: "t2", BuildId: "b1", Version: "old", Project: "p1", Status: evergreen.TaskFailed},
+	} {
+		require.NoError(t, tsk.Insert())
+	}
+	oldTsk := task.Task{Id: "t1_0", OldTaskId: "t1", BuildId: "b1", Version: "old", Project: "p1", Execution: 0, Archived: true, Status: evergreen.TaskFailed}
+	require.NoError(t, db.Insert(task.OldCollection, oldTsk))
+
+	pRef := &ProjectRef{Id: "p1", ArchiveVersionsAfterDays: 30}
+	toArchive, err := FindVersionsToArchive(pRef.Id, now.Add(-pRef.GetArchiveVersionsAfter()), 10)
+	require.NoError(t, err)
+	require.Len(t, toArchive, 1)
+	assert.Equal(t, "old", toArchive[0].Id)
+
+	_, err = ArchiveVersion(ctx, env, &versions[2], now)
+	assert.Error(t, err, "unfinished versions should not be archived")
+
+	res, err := ArchiveVersion(ctx, env, &toArchive[0], now)
+	require.NoError(t, err)
+	assert.Equal(t, VersionArchiveResult{Builds: 1, Tasks: 2, OldTasks: 1}, res)
+
+	t.Run("RemovesHotDocuments", func(t *testing.T) {
+		dbBuild, err := build.FindOneId("b1")
+		require.NoError(t, err)
+		assert.Nil(t, dbBuild)
+		dbTask, err := task.FindOneId("t1")
+		require.NoError(t, err)
+		assert.Nil(t, dbTask)
+		oldTasks, err := task.FindAllOld(db.Query(task.ByOldTaskID("t1")))
+		require.NoError(t, err)
+		assert.Empty(t, oldTasks)
+
+		dbVersion, err := VersionFindOneId("old")
+		require.NoError(t, err)
+		require.NotNil(t, dbVersion)
+		assert.False(t, dbVersion.ArchivedAt.IsZero())
+		toArchive, err := FindVersionsToArchive(pRef.Id, now.Add(-pRef.GetArchiveVersionsAfter()), 10)
+		require.NoError(t, err)
+		assert.Empty(t, toArchive)
+	})
+	t.Run("ReadsThroughToColdStorage", func(t *testing.T) {
+		archivedBuild, err := FindArchivedBuild("b1")
+		require.NoError(t, err)
+		require.NotNil(t, archivedBuild)
+		assert.Equal(t, "bv", archivedBuild.Build.BuildVariant)
+		require.Len(t, archivedBuild.Tasks, 2)
+		assert.Equal(t, "t1", archivedBuild.Tasks[0].Id)
+		assert.Equal(t, "t2", archivedBuild.Tasks[1].Id)
+		require.Len(t, archivedBuild.OldTasks, 1)
+		assert.Equal(t, "t1_0", archivedBuild.OldTasks[0].Id)
+
+		latest, err := FindArchivedTask("t1", -1)
+		require.NoError(t, err)
+		require.NotNil(t, latest)
+		assert.Equal(t, 1, latest.Execution)
+		prev, err := FindArchivedTask("t1", 0)
+		require.NoError(t, err)
+		require.NotNil(t, prev)
+		assert.Equal(t, evergreen.TaskFailed, prev.Status)
+		executions, err := FindArchivedTaskExecutions("t1")
+		require.NoError(t, err)
+		assert.Len(t, executions, 1)
+
+		tasks, isArchived, err := FindArchivedBuildTasks("b1", "", "", 1)
+		require.NoError(t, err)
+		assert.True(t, isArchived)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "t1", tasks[0].Id)
+		tasks, _, err = FindArchivedBuildTasks("b1", "t2", "", 1)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "t2", tasks[0].Id)
+		tasks, _, err = FindArchivedBuildTasks("b1", "", evergreen.TaskFailed, 0)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "t2", tasks[0].Id)
+		_, isArchived, err = FindArchivedBuildTasks("nonexistent", "", "", 0)
+		require.NoError(t, err)
+		assert.False(t, isArchived)
+
+		isArchived, err = IsTaskArchived("t2")
+		require.NoError(t, err)
+		assert.True(t, isArchived)
+
+		missing, err := FindArchivedTask("nonexistent", -1)
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+
+		project, err := FindProjectForArchivedTask("t1")
+		require.NoError(t, err)
+		assert.Equal(t, "p1", project)
+		project, err = FindProjectForArchivedBuild("b1")
+		require.NoError(t, err)
+		assert.Equal(t, "p1", project)
+	})
+	t.Run("StoresEachTaskSeparately", func(t *testing.T) {
+		count, err := db.Count(ArchivedTasksCollection, bson.M{ArchivedTaskBuildIdKey: "b1"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+	t.Run("IsIdempotent", func(t *testing.T) {
+		res, err := ArchiveVersion(ctx, env, &toArchive[0], now)
+		require.NoError(t, err)
+		assert.Zero(t, res)
+		archivedBuild, err := FindArchivedBuild("b1")
+		require.NoError(t, err)
+		assert.NotNil(t, archivedBuild)
+	})
+	t.Run("FinishesPartialArchive", func(t *testing.T) {
+		// Simulate an earlier attempt that archived the task but left its
+		// previous execution and the build behind.
+		require.NoError(t, b.Insert())
+		require.NoError(t, db.Insert(task.OldCollection, oldTsk))
+
+		res, err := ArchiveVersion(ctx, env, &toArchive[0], now)
+		require.NoError(t, err)
+		assert.Equal(t, VersionArchiveResult{Builds: 1, OldTasks: 1}, res)
+		oldTasks, err := task.FindAllOld(db.Query(task.ByOldTaskID("t1")))
+		require.NoError(t, err)
+		assert.Empty(t, oldTasks)
+
+		latest, err := FindArchivedTask("t1", -1)
+		require.NoError(t, err)
+		require.NotNil(t, latest)
+		assert.Equal(t, 1, latest.Execution)
+		executions, err := FindArchivedTaskExecutions("t1")
+		require.NoError(t, err)
+		assert.Len(t, executions, 1)
+	})
+}
+
+func TestRemoveUnchangedDocuments(t *testing.T) {
+	env := evergreen.GetEnvironment()
+	ctx, cancel := env.Context()
+	defer cancel()
+
+	require.NoError(t, db.ClearCollections(task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection))
+	}()
+
+	for _, tsk := range []task.Task{
+		{Id: "unchanged", Status: evergreen.TaskSucceeded},
+		{Id: "restarted", Status: evergreen.TaskFailed},
+	} {
+		require.NoError(t, tsk.Insert())
+	}
+	docs, err := findRawDocuments(ctx, env.DB().Collection(task.Collection), bson.M{})
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	require.NoError(t, task.UpdateOne(bson.M{task.IdKey: "restarted"}, bson.M{"$set": bson.M{task.StatusKey: evergreen.TaskUndispatched}}))
+
+	changed, err := removeUnchangedDocuments(ctx, env.DB().Collection(task.Collection), docs)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	dbTask, err := task.FindOneId("unchanged")
+	require.NoError(t, err)
+	assert.Nil(t, dbTask)
+	dbTask, err = task.FindOneId("restarted")
+	require.NoError(t, err)
+	require.NotNil(t, dbTask)
+	assert.Equal(t, evergreen.TaskUndispatched, dbTask.Status)
+}
+
+func TestMergeRawDocuments(t *testing.T) {
+	marshal := func(id, status string) bson.Raw {
+		raw, err := bson.Marshal(bson.M{"_id": id, "status": status})
+		require.NoError(t, err)
+		return raw
+	}
+	archived := []bson.Raw{marshal("t1", evergreen.TaskSucceeded), marshal("t2", evergreen.TaskFailed)}
+	live := []bson.Raw{marshal("t2", evergreen.TaskSucceeded), marshal("t3", evergreen.TaskSucceeded)}
+
+	merged := mergeRawDocuments(archived, live)
+	require.Len(t, merged, 3)
+	statuses := map[string]string{}
+	for _, doc := range merged {
+		statuses[doc.Lookup("_id").StringValue()] = doc.Lookup("status").StringValue()
+	}
+	assert.Equal(t, map[string]string{
+		"t1": evergreen.TaskSucceeded,
+		"t2": evergreen.TaskSucceeded,
+		"t3": evergreen.TaskSucceeded,
+	}, statuses)
+}
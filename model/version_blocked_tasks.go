@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TasksBlockedNotificationDelay is how long the tasks blocked in a version
+// are collected before they're logged together, so that a version with
+// several failing dependencies notifies the tasks' owners once.
+const TasksBlockedNotificationDelay = 5 * time.Minute
+
+var (
+	VersionPendingBlockedTasksKey      = bsonutil.MustHaveTag(Version{}, "PendingBlockedTasks")
+	VersionPendingBlockedTasksSinceKey = bsonutil.MustHaveTag(Version{}, "PendingBlockedTasksSince")
+)
+
+// addPendingBlockedTasks records that the tasks were blocked in the version so
+// they're logged with the rest of the version's blocked tasks once the
+// notification delay has passed.
+func addPendingBlockedTasks(versionID string, blocked []event.BlockedTask, now time.Time) error {
+	if versionID == "" || len(blocked) == 0 {
+		return nil
+	}
+	return VersionUpdateOne(
+		bson.M{VersionIdKey: versionID},
+		bson.M{
+			"$push": bson.M{VersionPendingBlockedTasksKey: bson.M{"$each": blocked}},
+			"$min":  bson.M{VersionPendingBlockedTasksSinceKey: now},
+		},
+	)
+}
+
+// FindVersionsWithPendingBlockedTasks returns the versions whose pending
+// blocked tasks have been collecting since before the given time.
+func FindVersionsWithPendingBlockedTasks(before time.Time) ([]Version, error) {
+	versions, err := VersionFind(db.Query(bson.M{
+		VersionPendingBlockedTasksSinceKey: bson.M{"$lte": before},
+	}).WithFields(VersionIdKey))
+	return versions, errors.Wrap(err, "finding versions with pending blocked tasks")
+}
+
+// LogPendingBlockedTasks clears the version's pending blocked tasks and logs
+// them in a single event. Tasks that are blocked while this runs are kept for
+// the next event.
+func LogPendingBlockedTasks(versionID string) error {
+	v := &Version{}
+	_, err := db.FindAndModify(
+		VersionCollection,
+		bson.M{
+			VersionIdKey:                       versionID,
+			VersionPendingBlockedTasksSinceKey: bson.M{"$exists": true},
+		},
+		nil,
+		adb.Change{
+			Update: bson.M{
+				"$unset": bson.M{
+					VersionPendingBlockedTasksKey:      1,
+					VersionPendingBlockedTasksSinceKey: 1,
+				},
+			},
+		},
+		v,
+	)
+	if adb.ResultsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "clearing pending blocked tasks for version '%s'", versionID)
+	}
+	if len(v.PendingBlockedTasks) > 0 {
+		event.LogVersionTasksBlockedEvent(versionID, v.PendingBlockedTasks)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package model
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// MaxChangelogCommits is the most commits that are listed in a changelog.
+const MaxChangelogCommits = MaxMainlineCommitVersionLimit
+
+// VersionChangelog summarizes what changed between two mainline versions of a
+// project.
+type VersionChangelog struct {
+	VersionID     string
+	BaseVersionID string
+	// Commits are the commits after the base version, up to and including the
+	// version, from newest to oldest.
+	Commits []ChangelogCommit
+	// CommitsTruncated is true if there were more than MaxChangelogCommits
+	// commits, in which case only the newest ones are listed.
+	CommitsTruncated bool
+	Modules          []ChangelogModule
+	// NewFailures are tasks that failed in the version but succeeded in the
+	// base version.
+	NewFailures []ChangelogTask
+	// Fixed are tasks that succeeded in the version but failed in the base
+	// version.
+	Fixed         []ChangelogTask
+	ConfigChanges ChangelogConfigChanges
+}
+
+// ChangelogCommit is a commit in a changelog.
+type ChangelogCommit struct {
+	VersionID   string
+	Revision    string
+	Author      string
+	AuthorEmail string
+	Message     string
+	CreateTime  time.Time
+}
+
+// ChangelogModule is a module whose revision changed between the versions. A
+// module that was added or removed has no base revision or no revision,
+// respectively.
+type ChangelogModule struct {
+	Name         string
+	Owner        string
+	Repo         string
+	Branch       string
+	BaseRevision string
+	Revision     string
+}
+
+// ChangelogTask is a task whose outcome changed between the versions.
+type ChangelogTask struct {
+	TaskID       string
+	BaseTaskID   string
+	BuildVariant string
+	DisplayName  string
+	Status       string
+	BaseStatus   string
+}
+
+// ChangelogConfigChanges are the build variants and tasks that changed in the
+// project config between the versions.
+type ChangelogConfigChanges struct {
+	AddedBuildVariants    []string
+	RemovedBuildVariants  []string
+	ModifiedBuildVariants []string
+	AddedTasks            []string
+	RemovedTasks          []string
+	ModifiedTasks         []string
+}
+
+// FindPreviousMainlineVersion returns the mainline version of the project that
+// precedes the given version, or nil if there is none.
+func FindPreviousMainlineVersion(v *Version) (*Version, error) {
+	if !utility.StringSliceContains(evergreen.SystemVersionRequesterTypes, v.Requester) {
+		return nil, errors.Errorf("version '%s' is not a mainline version", v.Id)
+	}
+	prev, err := VersionFindOne(VersionBySystemRequesterOrdered(v.Identifier, v.RevisionOrderNumber))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding mainline version before version '%s'", v.Id)
+	}
+	return prev, nil
+}
+
+// GetVersionChangelog returns the changes from the base version to the version,
+// both of which must be mainline versions of the same project.
+func GetVersionChangelog(v, baseVersion *Version) (*VersionChangelog, error) {
+	for _, mainline := range []*Version{v, baseVersion} {
+		if !utility.StringSliceContains(evergreen.SystemVersionRequesterTypes, mainline.Requester) {
+			return nil, errors.Errorf("version '%s' is not a mainline version", mainline.Id)
+		}
+	}
+	if v.Identifier != baseVersion.Identifier {
+		return nil, errors.Errorf("versions '%s' and '%s' are not in the same project", v.Id, baseVersion.Id)
+	}
+	if baseVersion.RevisionOrderNumber >= v.RevisionOrderNumber {
+		return nil, errors.Errorf("base version '%s' must be older than version '%s'", baseVersion.Id, v.Id)
+	}
+
+	changelog := &VersionChangelog{
+		VersionID:     v.Id,
+		BaseVersionID: baseVersion.Id,
+	}
+	var err error
+	if changelog.Commits, changelog.CommitsTruncated, err = changelogCommits(v, baseVersion); err != nil {
+		return nil, errors.Wrap(err, "getting commits")
+	}
+	if changelog.Modules, err = changelogModules(v, baseVersion); err != nil {
+		return nil, errors.Wrap(err, "getting module changes")
+	}
+	if changelog.NewFailures, changelog.Fixed, err = changelogTasks(v, baseVersion); err != nil {
+		return nil, errors.Wrap(err, "getting task changes")
+	}
+	if changelog.ConfigChanges, err = changelogConfigChanges(v, baseVersion); err != nil {
+		return nil, errors.Wrap(err, "getting config changes")
+	}
+
+	return changelog, nil
+}
+
+func changelogCommits(v, baseVersion *Version) ([]ChangelogCommit, bool, error) {
+	versions, err := VersionFind(VersionByProjectIdAndOrderRange(v.Identifier, baseVersion.RevisionOrderNumber, v.RevisionOrderNumber).
+		WithoutFields(VersionConfigKey).
+		Limit(MaxChangelogCommits + 1))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "finding versions between base version and version")
+	}
+	truncated := len(versions) > MaxChangelogCommits
+	if truncated {
+		versions = versions[:MaxChangelogCommits]
+	}
+
+	commits := make([]ChangelogCommit, 0, len(versions))
+	for _, commit := range versions {
+		commits = append(commits, ChangelogCommit{
+			VersionID:   commit.Id,
+			Revision:    commit.Revision,
+			Author:      commit.Author,
+			AuthorEmail: commit.AuthorEmail,
+			Message:     commit.Message,
+			CreateTime:  commit.CreateTime,
+		})
+	}
+	return commits, truncated, nil
+}
+
+func changelogModules(v, baseVersion *Version) ([]ChangelogModule, error) {
+	mfest, err := manifest.FindFromVersion(v.Id, v.Identifier, v.Revision, v.Requester)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding manifest for version '%s'", v.Id)
+	}
+	baseManifest, err := manifest.FindFromVersion(baseVersion.Id, baseVersion.Identifier, baseVersion.Revision, baseVersion.Requester)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding manifest for base version '%s'", baseVersion.Id)
+	}
+
+	modules := map[string]*manifest.Module{}
+	baseModules := map[string]*manifest.Module{}
+	if mfest != nil {
+		modules = mfest.Modules
+	}
+	if baseManifest != nil {
+		baseModules = baseManifest.Modules
+	}
+
+	var changes []ChangelogModule
+	for name, module := range modules {
+		change := ChangelogModule{
+			Name:     name,
+			Owner:    module.Owner,
+			Repo:     module.Repo,
+			Branch:   module.Branch,
+			Revision: module.Revision,
+		}
+		if baseModule := baseModules[name]; baseModule != nil {
+			if baseModule.Revision == module.Revision {
+				continue
+			}
+			change.BaseRevision = baseModule.Revision
+		}
+		changes = append(changes, change)
+	}
+	for name, baseModule := range baseModules {
+		if modules[name] != nil {
+			continue
+		}
+		changes = append(changes, ChangelogModule{
+			Name:         name,
+			Owner:        baseModule.Owner,
+			Repo:         baseModule.Repo,
+			Branch:       baseModule.Branch,
+			BaseRevision: baseModule.Revision,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+
+	return changes, nil
+}
+
+func changelogTasks(v, baseVersion *Version) ([]ChangelogTask, []ChangelogTask, error) {
+	tasks, err := task.FindAll(db.Query(task.ByVersion(v.Id)))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "finding tasks for version '%s'", v.Id)
+	}
+	baseTasks, err := task.FindAll(db.Query(task.ByVersion(baseVersion.Id)))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "finding tasks for base version '%s'", baseVersion.Id)
+	}
+	baseTasksByKey := map[string]*task.Task{}
+	for i := range baseTasks {
+		baseTasksByKey[baselineTaskKey(&baseTasks[i])] = &baseTasks[i]
+	}
+
+	var newFailures, fixed []ChangelogTask
+	for i := range tasks {
+		t := &tasks[i]
+		baseTask := baseTasksByKey[baselineTaskKey(t)]
+		if t.IsPartOfDisplay() || baseTask == nil {
+			continue
+		}
+		change := ChangelogTask{
+			TaskID:       t.Id,
+			BaseTaskID:   baseTask.Id,
+			BuildVariant: t.BuildVariant,
+			DisplayName:  t.DisplayName,
+			Status:       t.Status,
+			BaseStatus:   baseTask.Status,
+		}
+		switch {
+		case evergreen.IsFailedTaskStatus(t.Status) && baseTask.Status == evergreen.TaskSucceeded:
+			newFailures = append(newFailures, change)
+		case t.Status == evergreen.TaskSucceeded && evergreen.IsFailedTaskStatus(baseTask.Status):
+			fixed = append(fixed, change)
+		}
+	}
+	sortChangelogTasks(newFailures)
+	sortChangelogTasks(fixed)
+
+	return newFailures, fixed, nil
+}
+
+func sortChangelogTasks(tasks []ChangelogTask) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].BuildVariant != tasks[j].BuildVariant {
+			return tasks[i].BuildVariant < tasks[j].BuildVariant
+		}
+		return tasks[i].DisplayName < tasks[j].DisplayName
+	})
+}
+
+func changelogConfigChanges(v, baseVersion *Version) (ChangelogConfigChanges, error) {
+	changes := ChangelogConfigChanges{}
+	projectInfo, err := LoadProjectForVersion(v, v.Identifier, false)
+	if err != nil {
+		return changes, errors.Wrapf(err, "loading project config for version '%s'", v.Id)
+	}
+	baseProjectInfo, err := LoadProjectForVersion(baseVersion, baseVersion.Identifier, false)
+	if err != nil {
+		return changes, errors.Wrapf(err, "loading project config for base version '%s'", baseVersion.Id)
+	}
+	p := projectInfo.Project
+	baseProject := baseProjectInfo.Project
+
+	for _, bv := range p.BuildVariants {
+		baseBV := baseProject.FindBuildVariant(bv.Name)
+		if baseBV == nil {
+			changes.AddedBuildVariants = append(changes.AddedBuildVariants, bv.Name)
+		} else if !reflect.DeepEqual(bv, *baseBV) {
+			changes.ModifiedBuildVariants = append(changes.ModifiedBuildVariants, bv.Name)
+		}
+	}
+	for _, baseBV := range baseProject.BuildVariants {
+		if p.FindBuildVariant(baseBV.Name) == nil {
+			changes.RemovedBuildVariants = append(changes.RemovedBuildVariants, baseBV.Name)
+		}
+	}
+	for _, pt := range p.Tasks {
+		basePT := baseProject.FindProjectTask(pt.Name)
+		if basePT == nil {
+			changes.AddedTasks = append(changes.AddedTasks, pt.Name)
+		} else if !reflect.DeepEqual(pt, *basePT) {
+			changes.ModifiedTasks = append(changes.ModifiedTasks, pt.Name)
+		}
+	}
+	for _, basePT := range baseProject.Tasks {
+		if p.FindProjectTask(basePT.Name) == nil {
+			changes.RemovedTasks = append(changes.RemovedTasks, basePT.Name)
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVersionChangelog(t *testing.T) {
+	require.NoError(t, db.ClearCollections(VersionCollection, task.Collection, manifest.Collection, ParserProjectCollection, ProjectRefCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection, task.Collection, manifest.Collection, ParserProjectCollection, ProjectRefCollection))
+	}()
+
+	pRef := ProjectRef{Id: "p1", Identifier: "p1"}
+	require.NoError(t, pRef.Insert())
+
+	versions := []Version{
+		{Id: "v1", Identifier: "p1", Revision: "r1", RevisionOrderNumber: 1, Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "v2", Identifier: "p1", Revision: "r2", RevisionOrderNumber: 2, Requester: evergreen.RepotrackerVersionRequester, Message: "second commit"},
+		{Id: "v3", Identifier: "p1", Revision: "r3", RevisionOrderNumber: 3, Requester: evergreen.RepotrackerVersionRequester, Message: "third commit"},
+		{Id: "patch", Identifier: "p1", Revision: "r3", RevisionOrderNumber: 4, Requester: evergreen.PatchVersionRequester},
+	}
+	for _, v := range versions {
+		require.NoError(t, v.Insert())
+	}
+
+	baseProject := ParserProject{
+		Id: "v1",
+		Tasks: []parserTask{
+			{Name: "unchanged"},
+			{Name: "modified"},
+			{Name: "removed"},
+		},
+		BuildVariants: []parserBV{
+			{Name: "bv", Tasks: parserBVTaskUnits{{Name: "unchanged"}, {Name: "modified"}, {Name: "removed"}}},
+			{Name: "old_bv", Tasks: parserBVTaskUnits{{Name: "unchanged"}}},
+		},
+	}
+	require.NoError(t, baseProject.Insert())
+	project := ParserProject{
+		Id: "v3",
+		Tasks: []parserTask{
+			{Name: "unchanged"},
+			{Name: "modified", Priority: 10},
+			{Name: "added"},
+		},
+		BuildVariants: []parserBV{
+			{Name: "bv", Tasks: parserBVTaskUnits{{Name: "unchanged"}, {Name: "modified"}, {Name: "added"}}},
+		},
+	}
+	require.NoError(t, project.Insert())
+
+	manifests := []manifest.Manifest{
+		{
+			Id:          "v1",
+			ProjectName: "p1",
+			Revision:    "r1",
+			Modules: map[string]*manifest.Module{
+				"unchanged": {Repo: "unchanged", Revision: "m1"},
+				"bumped":    {Repo: "bumped", Revision: "m1"},
+				"dropped":   {Repo: "dropped", Revision: "m1"},
+			},
+		},
+		{
+			Id:          "v3",
+			ProjectName: "p1",
+			Revision:    "r3",
+			Modules: map[string]*manifest.Module{
+				"unchanged": {Repo: "unchanged", Revision: "m1"},
+				"bumped":    {Repo: "bumped", Revision: "m2"},
+			},
+		},
+	}
+	for _, m := range manifests {
+		_, err := m.TryInsert()
+		require.NoError(t, err)
+	}
+
+	tasks := []task.Task{
+		{Id: "v1_passing", Version: "v1", BuildVariant: "bv", DisplayName: "passing", Status: evergreen.TaskSucceeded},
+		{Id: "v3_passing", Version: "v3", BuildVariant: "bv", DisplayName: "passing", Status: evergreen.TaskSucceeded},
+		{Id: "v1_broken", Version: "v1", BuildVariant: "bv", DisplayName: "broken", Status: evergreen.TaskSucceeded},
+		{Id: "v3_broken", Version: "v3", BuildVariant: "bv", DisplayName: "broken", Status: evergreen.TaskFailed},
+		{Id: "v1_fixed", Version: "v1", BuildVariant: "bv", DisplayName: "fixed", Status: evergreen.TaskFailed},
+		{Id: "v3_fixed", Version: "v3", BuildVariant: "bv", DisplayName: "fixed", Status: evergreen.TaskSucceeded},
+		{Id: "v3_new", Version: "v3", BuildVariant: "bv", DisplayName: "new", Status: evergreen.TaskFailed},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+	}
+
+	t.Run("FindsPreviousMainlineVersion", func(t *testing.T) {
+		prev, err := FindPreviousMainlineVersion(&versions[2])
+		require.NoError(t, err)
+		require.NotNil(t, prev)
+		assert.Equal(t, "v2", prev.Id)
+
+		prev, err = FindPreviousMainlineVersion(&versions[0])
+		assert.NoError(t, err)
+		assert.Nil(t, prev)
+
+		_, err = FindPreviousMainlineVersion(&versions[3])
+		assert.Error(t, err)
+	})
+	t.Run("SummarizesChanges", func(t *testing.T) {
+		changelog, err := GetVersionChangelog(&versions[2], &versions[0])
+		require.NoError(t, err)
+		assert.Equal(t, "v3", changelog.VersionID)
+		assert.Equal(t, "v1", changelog.BaseVersionID)
+
+		require.Len(t, changelog.Commits, 2)
+		assert.Equal(t, "third commit", changelog.Commits[0].Message)
+		assert.Equal(t, "second commit", changelog.Commits[1].Message)
+		assert.False(t, changelog.CommitsTruncated)
+
+		require.Len(t, changelog.Modules, 2)
+		assert.Equal(t, ChangelogModule{Name: "bumped", Repo: "bumped", BaseRevision: "m1", Revision: "m2"}, changelog.Modules[0])
+		assert.Equal(t, ChangelogModule{Name: "dropped", Repo: "dropped", BaseRevision: "m1"}, changelog.Modules[1])
+
+		require.Len(t, changelog.NewFailures, 1)
+		assert.Equal(t, "v3_broken", changelog.NewFailures[0].TaskID)
+		assert.Equal(t, "v1_broken", changelog.NewFailures[0].BaseTaskID)
+		require.Len(t, changelog.Fixed, 1)
+		assert.Equal(t, "v3_fixed", changelog.Fixed[0].TaskID)
+
+		assert.Equal(t, []string{"added"}, changelog.ConfigChanges.AddedTasks)
+		assert.Equal(t, []string{"removed"}, changelog.ConfigChanges.RemovedTasks)
+		assert.Equal(t, []string{"modified"}, changelog.ConfigChanges.ModifiedTasks)
+		assert.Equal(t, []string{"old_bv"}, changelog.ConfigChanges.RemovedBuildVariants)
+		assert.Equal(t, []string{"bv"}, changelog.ConfigChanges.ModifiedBuildVariants)
+		assert.Empty(t, changelog.ConfigChanges.AddedBuildVariants)
+	})
+	t.Run("FailsForInvalidBaseVersion", func(t *testing.T) {
+		_, err := GetVersionChangelog(&versions[0], &versions[2])
+		assert.Error(t, err)
+		_, err = GetVersionChangelog(&versions[3], &versions[0])
+		assert.Error(t, err)
+
+		otherProject := Version{Id: "other", Identifier: utility.RandomString(), RevisionOrderNumber: 1, Requester: evergreen.RepotrackerVersionRequester}
+		_, err = GetVersionChangelog(&versions[2], &otherProject)
+		assert.Error(t, err)
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/mongodb/anser/bsonutil"
 	adb "github.com/mongodb/anser/db"
 	"github.com/pkg/errors"
@@ -16,6 +17,14 @@ const (
 	VersionCollection = "versions"
 )
 
+// VersionLabelsIndex is the index used to find a project's versions by their
+// labels.
+var VersionLabelsIndex = bson.D{
+	{Key: VersionIdentifierKey, Value: 1},
+	{Key: bsonutil.GetDottedKeyName(VersionLabelsKey, patch.LabelKeyKey), Value: 1},
+	{Key: bsonutil.GetDottedKeyName(VersionLabelsKey, patch.LabelValueKey), Value: 1},
+}
+
 var (
 	// bson fields for the version struct
 	VersionIdKey                  = bsonutil.MustHaveTag(Version{}, "Id")
@@ -28,6 +37,8 @@ var (
 	VersionMessageKey             = bsonutil.MustHaveTag(Version{}, "Message")
 	VersionStatusKey              = bsonutil.MustHaveTag(Version{}, "Status")
 	VersionParametersKey          = bsonutil.MustHaveTag(Version{}, "Parameters")
+	VersionLabelsKey              = bsonutil.MustHaveTag(Version{}, "Labels")
+	VersionExpansionOverridesKey  = bsonutil.MustHaveTag(Version{}, "ExpansionOverrides")
 	VersionBuildIdsKey            = bsonutil.MustHaveTag(Version{}, "BuildIds")
 	VersionBuildVariantsKey       = bsonutil.MustHaveTag(Version{}, "BuildVariants")
 	VersionRevisionOrderNumberKey = bsonutil.MustHaveTag(Version{}, "RevisionOrderNumber")
@@ -47,10 +58,16 @@ var (
 	VersionTriggerIDKey           = bsonutil.MustHaveTag(Version{}, "TriggerID")
 	VersionTriggerTypeKey         = bsonutil.MustHaveTag(Version{}, "TriggerType")
 	VersionSatisfiedTriggersKey   = bsonutil.MustHaveTag(Version{}, "SatisfiedTriggers")
+	VersionUpstreamVersionIDKey   = bsonutil.MustHaveTag(Version{}, "UpstreamVersionID")
+	VersionDownstreamVersionsKey  = bsonutil.MustHaveTag(Version{}, "DownstreamVersionIDs")
 	VersionPeriodicBuildIDKey     = bsonutil.MustHaveTag(Version{}, "PeriodicBuildID")
 	VersionActivatedKey           = bsonutil.MustHaveTag(Version{}, "Activated")
 	VersionAbortedKey             = bsonutil.MustHaveTag(Version{}, "Aborted")
+	VersionArchivedAtKey          = bsonutil.MustHaveTag(Version{}, "ArchivedAt")
 	VersionAuthorIDKey            = bsonutil.MustHaveTag(Version{}, "AuthorID")
+	VersionTimingKey              = bsonutil.MustHaveTag(Version{}, "Timing")
+	VersionRollupCountsKey        = bsonutil.MustHaveTag(Version{}, "RollupCounts")
+	VersionExternalGatesKey       = bsonutil.MustHaveTag(Version{}, "ExternalGates")
 )
 
 // ById returns a db.Q object which will filter on {_id : <the id param>}
@@ -131,6 +148,21 @@ func VersionByProjectIdAndOrder(projectId string, revisionOrderNumber int) db.Q
 		}).Sort([]string{"-" + VersionRevisionOrderNumberKey})
 }
 
+// VersionByProjectIdAndOrderRange finds the repotracker versions for the given
+// project with revision order numbers after afterOrder, up to and including
+// throughOrder, ordered from newest to oldest.
+func VersionByProjectIdAndOrderRange(projectId string, afterOrder, throughOrder int) db.Q {
+	return db.Query(
+		bson.M{
+			VersionIdentifierKey: projectId,
+			VersionRevisionOrderNumberKey: bson.M{
+				"$gt":  afterOrder,
+				"$lte": throughOrder,
+			},
+			VersionRequesterKey: evergreen.RepotrackerVersionRequester,
+		}).Sort([]string{"-" + VersionRevisionOrderNumberKey})
+}
+
 // ByLastVariantActivation finds the most recent non-patch, non-ignored
 // versions in a project that have a particular variant activated.
 func VersionByLastVariantActivation(projectId, variant string) db.Q {
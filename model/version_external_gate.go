@@ -0,0 +1,282 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// ExternalGateOnTimeoutOpen lets the gated tasks run once the gate times
+	// out.
+	ExternalGateOnTimeoutOpen = "open"
+	// ExternalGateOnTimeoutFail keeps the gated tasks from running once the
+	// gate times out. This is the default.
+	ExternalGateOnTimeoutFail = "fail"
+
+	ExternalGateStateClosed   = "closed"
+	ExternalGateStateOpen     = "open"
+	ExternalGateStateTimedOut = "timed_out"
+)
+
+// ExternalGateOnTimeoutValues are the valid actions for a gate that times out.
+var ExternalGateOnTimeoutValues = []string{ExternalGateOnTimeoutOpen, ExternalGateOnTimeoutFail}
+
+// ExternalGate is a named gate declared in the project config that a system
+// outside of Evergreen opens once something that the project's tasks need is
+// ready, such as an artifact repository.
+type ExternalGate struct {
+	Name string `yaml:"name" bson:"name"`
+	// TimeoutSecs is how long the gate waits to be opened after the version
+	// is created. If it's not set, the gate waits indefinitely.
+	TimeoutSecs int `yaml:"timeout_secs,omitempty" bson:"timeout_secs,omitempty"`
+	// OnTimeout is what happens to the gated tasks if the gate is not opened
+	// in time.
+	OnTimeout string `yaml:"on_timeout,omitempty" bson:"on_timeout,omitempty"`
+}
+
+// VersionExternalGate is the state of an external gate in a version.
+type VersionExternalGate struct {
+	Name string `bson:"name" json:"name"`
+	// BuildVariants are the variants whose tasks the gate holds. If it's
+	// empty, the gate holds all of the version's tasks.
+	BuildVariants []string `bson:"build_variants,omitempty" json:"build_variants,omitempty"`
+	// Deadline is when the gate times out. It's not set if the gate waits
+	// indefinitely.
+	Deadline  time.Time `bson:"deadline,omitempty" json:"deadline,omitempty"`
+	OnTimeout string    `bson:"on_timeout,omitempty" json:"on_timeout,omitempty"`
+	OpenedAt  time.Time `bson:"opened_at,omitempty" json:"opened_at,omitempty"`
+	OpenedBy  string    `bson:"opened_by,omitempty" json:"opened_by,omitempty"`
+	// TimedOutAt is when the tasks that the gate held were deactivated
+	// because it timed out before it was opened.
+	TimedOutAt time.Time `bson:"timed_out_at,omitempty" json:"timed_out_at,omitempty"`
+}
+
+var (
+	versionExternalGateNameKey       = bsonutil.MustHaveTag(VersionExternalGate{}, "Name")
+	versionExternalGateDeadlineKey   = bsonutil.MustHaveTag(VersionExternalGate{}, "Deadline")
+	versionExternalGateOnTimeoutKey  = bsonutil.MustHaveTag(VersionExternalGate{}, "OnTimeout")
+	versionExternalGateOpenedAtKey   = bsonutil.MustHaveTag(VersionExternalGate{}, "OpenedAt")
+	versionExternalGateOpenedByKey   = bsonutil.MustHaveTag(VersionExternalGate{}, "OpenedBy")
+	versionExternalGateTimedOutAtKey = bsonutil.MustHaveTag(VersionExternalGate{}, "TimedOutAt")
+)
+
+// State returns whether the gate is open, closed, or timed out at the given
+// time. A gate that times out is open if it lets its tasks run on timeout.
+func (g *VersionExternalGate) State(now time.Time) string {
+	if !utility.IsZeroTime(g.OpenedAt) {
+		return ExternalGateStateOpen
+	}
+	if utility.IsZeroTime(g.Deadline) || now.Before(g.Deadline) {
+		return ExternalGateStateClosed
+	}
+	if g.OnTimeout == ExternalGateOnTimeoutOpen {
+		return ExternalGateStateOpen
+	}
+	return ExternalGateStateTimedOut
+}
+
+// Holds returns whether the gate applies to the build variant's tasks.
+func (g *VersionExternalGate) Holds(variant string) bool {
+	return len(g.BuildVariants) == 0 || utility.StringSliceContains(g.BuildVariants, variant)
+}
+
+// GetExternalGate returns the version's external gate with the given name, or
+// nil if it has none.
+func (v *Version) GetExternalGate(name string) *VersionExternalGate {
+	for i := range v.ExternalGates {
+		if v.ExternalGates[i].Name == name {
+			return &v.ExternalGates[i]
+		}
+	}
+	return nil
+}
+
+// BlockingExternalGates returns the names of the version's gates that are
+// keeping the build variant's tasks from being dispatched.
+func (v *Version) BlockingExternalGates(variant string, now time.Time) []string {
+	var names []string
+	for i := range v.ExternalGates {
+		gate := &v.ExternalGates[i]
+		if gate.Holds(variant) && gate.State(now) != ExternalGateStateOpen {
+			names = append(names, gate.Name)
+		}
+	}
+	return names
+}
+
+// NewVersionExternalGates returns the closed external gates for a new version
+// created at the given time. Gates that neither the version nor any of its
+// build variants use are left out.
+func (p *Project) NewVersionExternalGates(createTime time.Time) []VersionExternalGate {
+	var gates []VersionExternalGate
+	for _, def := range p.ExternalGates {
+		gate := VersionExternalGate{
+			Name:      def.Name,
+			OnTimeout: def.OnTimeout,
+		}
+		if gate.OnTimeout == "" {
+			gate.OnTimeout = ExternalGateOnTimeoutFail
+		}
+		if def.TimeoutSecs > 0 {
+			gate.Deadline = createTime.Add(time.Duration(def.TimeoutSecs) * time.Second)
+		}
+		if !utility.StringSliceContains(p.VersionGates, def.Name) {
+			for _, bv := range p.BuildVariants {
+				if utility.StringSliceContains(bv.ExternalGates, def.Name) {
+					gate.BuildVariants = append(gate.BuildVariants, bv.Name)
+				}
+			}
+			if len(gate.BuildVariants) == 0 {
+				continue
+			}
+		}
+		gates = append(gates, gate)
+	}
+	return gates
+}
+
+// OpenVersionExternalGate opens the version's external gate so that the tasks
+// it holds can be dispatched. Opening a gate that is already open does
+// nothing.
+func OpenVersionExternalGate(versionID, name, user string) error {
+	now := time.Now()
+	err := VersionUpdateOne(
+		bson.M{
+			VersionIdKey: versionID,
+			VersionExternalGatesKey: bson.M{"$elemMatch": bson.M{
+				versionExternalGateNameKey:     name,
+				versionExternalGateOpenedAtKey: bson.M{"$exists": false},
+			}},
+		},
+		bson.M{"$set": bson.M{
+			bsonutil.GetDottedKeyName(VersionExternalGatesKey, "$", versionExternalGateOpenedAtKey): now,
+			bsonutil.GetDottedKeyName(VersionExternalGatesKey, "$", versionExternalGateOpenedByKey): user,
+		}},
+	)
+	if adb.ResultsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "opening external gate '%s' for version '%s'", name, versionID)
+	}
+	event.LogVersionExternalGateOpenedEvent(versionID, name, user)
+	return nil
+}
+
+// FilterExternallyGatedTasks removes the tasks whose versions have external
+// gates that are keeping them from being dispatched.
+func FilterExternallyGatedTasks(tasks []task.Task, now time.Time) ([]task.Task, error) {
+	versionIDs := []string{}
+	for _, t := range tasks {
+		if !utility.StringSliceContains(versionIDs, t.Version) {
+			versionIDs = append(versionIDs, t.Version)
+		}
+	}
+	versions, err := VersionFind(db.Query(bson.M{
+		VersionIdKey: bson.M{"$in": versionIDs},
+		bsonutil.GetDottedKeyName(VersionExternalGatesKey, "0"): bson.M{"$exists": true},
+	}).WithFields(VersionIdKey, VersionExternalGatesKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding versions with external gates")
+	}
+	if len(versions) == 0 {
+		return tasks, nil
+	}
+	gated := map[string]*Version{}
+	for i := range versions {
+		gated[versions[i].Id] = &versions[i]
+	}
+
+	filtered := make([]task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if v, ok := gated[t.Version]; ok && len(v.BlockingExternalGates(t.BuildVariant, now)) > 0 {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered, nil
+}
+
+// FindVersionsWithTimedOutExternalGates returns the versions that have an
+// external gate which timed out before it was opened and keeps its tasks from
+// running, and whose tasks haven't been deactivated yet.
+func FindVersionsWithTimedOutExternalGates(now time.Time) ([]Version, error) {
+	versions, err := VersionFind(db.Query(bson.M{
+		VersionExternalGatesKey: bson.M{"$elemMatch": timedOutExternalGateQuery(now)},
+	}).WithFields(VersionIdKey, VersionExternalGatesKey))
+	return versions, errors.Wrap(err, "finding versions with timed out external gates")
+}
+
+func timedOutExternalGateQuery(now time.Time) bson.M {
+	return bson.M{
+		versionExternalGateDeadlineKey:   bson.M{"$lte": now},
+		versionExternalGateOnTimeoutKey:  ExternalGateOnTimeoutFail,
+		versionExternalGateOpenedAtKey:   bson.M{"$exists": false},
+		versionExternalGateTimedOutAtKey: bson.M{"$exists": false},
+	}
+}
+
+// DeactivateTimedOutExternalGateTasks deactivates the version's undispatched
+// tasks that are held by gates which timed out before they were opened, and
+// records that the gates timed out so that they're only handled once.
+func (v *Version) DeactivateTimedOutExternalGateTasks(now time.Time) error {
+	catcher := grip.NewBasicCatcher()
+	for i := range v.ExternalGates {
+		gate := &v.ExternalGates[i]
+		if gate.State(now) != ExternalGateStateTimedOut || !utility.IsZeroTime(gate.TimedOutAt) {
+			continue
+		}
+
+		query := bson.M{
+			task.VersionKey:   v.Id,
+			task.ActivatedKey: true,
+			task.StatusKey:    evergreen.TaskUndispatched,
+		}
+		if len(gate.BuildVariants) > 0 {
+			query[task.BuildVariantKey] = bson.M{"$in": gate.BuildVariants}
+		}
+		tasks, err := task.Find(query)
+		if err != nil {
+			catcher.Wrapf(err, "finding tasks held by external gate '%s'", gate.Name)
+			continue
+		}
+		if len(tasks) > 0 {
+			if err = SetActiveState(evergreen.ExternalGateTimeoutUser, false, tasks...); err != nil {
+				catcher.Wrapf(err, "deactivating tasks held by external gate '%s'", gate.Name)
+				continue
+			}
+		}
+
+		gateQuery := timedOutExternalGateQuery(now)
+		gateQuery[versionExternalGateNameKey] = gate.Name
+		err = VersionUpdateOne(
+			bson.M{
+				VersionIdKey:            v.Id,
+				VersionExternalGatesKey: bson.M{"$elemMatch": gateQuery},
+			},
+			bson.M{"$set": bson.M{
+				bsonutil.GetDottedKeyName(VersionExternalGatesKey, "$", versionExternalGateTimedOutAtKey): now,
+			}},
+		)
+		if adb.ResultsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			catcher.Wrapf(err, "marking external gate '%s' timed out", gate.Name)
+			continue
+		}
+		gate.TimedOutAt = now
+		event.LogVersionExternalGateTimedOutEvent(v.Id, gate.Name)
+	}
+	return errors.Wrapf(catcher.Resolve(), "handling timed out external gates for version '%s'", v.Id)
+}
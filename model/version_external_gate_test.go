@@ -0,0 +1,171 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionExternalGateState(t *testing.T) {
+	now := time.Now()
+	for name, testCase := range map[string]struct {
+		gate     VersionExternalGate
+		expected string
+	}{
+		"ClosedWithoutTimeout": {
+			gate:     VersionExternalGate{Name: "gate"},
+			expected: ExternalGateStateClosed,
+		},
+		"ClosedBeforeDeadline": {
+			gate:     VersionExternalGate{Name: "gate", Deadline: now.Add(time.Hour), OnTimeout: ExternalGateOnTimeoutFail},
+			expected: ExternalGateStateClosed,
+		},
+		"Opened": {
+			gate:     VersionExternalGate{Name: "gate", Deadline: now.Add(-time.Hour), OpenedAt: now.Add(-2 * time.Hour)},
+			expected: ExternalGateStateOpen,
+		},
+		"TimedOutAndOpens": {
+			gate:     VersionExternalGate{Name: "gate", Deadline: now.Add(-time.Hour), OnTimeout: ExternalGateOnTimeoutOpen},
+			expected: ExternalGateStateOpen,
+		},
+		"TimedOutAndFails": {
+			gate:     VersionExternalGate{Name: "gate", Deadline: now.Add(-time.Hour), OnTimeout: ExternalGateOnTimeoutFail},
+			expected: ExternalGateStateTimedOut,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.gate.State(now))
+		})
+	}
+}
+
+func TestNewVersionExternalGates(t *testing.T) {
+	project := &Project{
+		ExternalGates: []ExternalGate{
+			{Name: "artifact-repo-ready", TimeoutSecs: 60, OnTimeout: ExternalGateOnTimeoutOpen},
+			{Name: "release-approved"},
+			{Name: "unused"},
+		},
+		VersionGates: []string{"release-approved"},
+		BuildVariants: []BuildVariant{
+			{Name: "bv1", ExternalGates: []string{"artifact-repo-ready"}},
+			{Name: "bv2"},
+			{Name: "bv3", ExternalGates: []string{"artifact-repo-ready", "release-approved"}},
+		},
+	}
+	now := time.Now()
+	gates := project.NewVersionExternalGates(now)
+	require.Len(t, gates, 2)
+	assert.Equal(t, "artifact-repo-ready", gates[0].Name)
+	assert.Equal(t, []string{"bv1", "bv3"}, gates[0].BuildVariants)
+	assert.Equal(t, now.Add(time.Minute), gates[0].Deadline)
+	assert.Equal(t, ExternalGateOnTimeoutOpen, gates[0].OnTimeout)
+	assert.Equal(t, "release-approved", gates[1].Name)
+	assert.Empty(t, gates[1].BuildVariants)
+	assert.True(t, gates[1].Deadline.IsZero())
+	assert.Equal(t, ExternalGateOnTimeoutFail, gates[1].OnTimeout)
+}
+
+func TestFilterExternallyGatedTasks(t *testing.T) {
+	require.NoError(t, db.ClearCollections(VersionCollection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection, event.AllLogCollection))
+	}()
+
+	gated := &Version{
+		Id: "gated",
+		ExternalGates: []VersionExternalGate{
+			{Name: "artifact-repo-ready", BuildVariants: []string{"bv1"}, OnTimeout: ExternalGateOnTimeoutFail},
+		},
+	}
+	require.NoError(t, gated.Insert())
+	ungated := &Version{Id: "ungated"}
+	require.NoError(t, ungated.Insert())
+
+	tasks := []task.Task{
+		{Id: "t1", Version: "gated", BuildVariant: "bv1"},
+		{Id: "t2", Version: "gated", BuildVariant: "bv2"},
+		{Id: "t3", Version: "ungated", BuildVariant: "bv1"},
+	}
+	filtered, err := FilterExternallyGatedTasks(tasks, time.Now())
+	require.NoError(t, err)
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "t2", filtered[0].Id)
+	assert.Equal(t, "t3", filtered[1].Id)
+
+	require.NoError(t, OpenVersionExternalGate("gated", "artifact-repo-ready", "me"))
+	// Opening an open gate does nothing.
+	require.NoError(t, OpenVersionExternalGate("gated", "artifact-repo-ready", "someone_else"))
+	dbVersion, err := VersionFindOneId("gated")
+	require.NoError(t, err)
+	require.NotNil(t, dbVersion)
+	gate := dbVersion.GetExternalGate("artifact-repo-ready")
+	require.NotNil(t, gate)
+	assert.Equal(t, "me", gate.OpenedBy)
+	assert.Equal(t, ExternalGateStateOpen, gate.State(time.Now()))
+
+	filtered, err = FilterExternallyGatedTasks(tasks, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, filtered, 3)
+}
+
+func TestDeactivateTimedOutExternalGateTasks(t *testing.T) {
+	require.NoError(t, db.ClearCollections(VersionCollection, build.Collection, task.Collection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection, build.Collection, task.Collection, event.AllLogCollection))
+	}()
+
+	now := time.Now()
+	v := &Version{
+		Id:       "v",
+		BuildIds: []string{"b1", "b2"},
+		ExternalGates: []VersionExternalGate{
+			{Name: "timed-out", BuildVariants: []string{"bv1"}, Deadline: now.Add(-time.Minute), OnTimeout: ExternalGateOnTimeoutFail},
+			{Name: "opens-on-timeout", BuildVariants: []string{"bv2"}, Deadline: now.Add(-time.Minute), OnTimeout: ExternalGateOnTimeoutOpen},
+		},
+	}
+	require.NoError(t, v.Insert())
+	for _, b := range []build.Build{
+		{Id: "b1", Version: v.Id, BuildVariant: "bv1", Activated: true, Status: evergreen.BuildCreated},
+		{Id: "b2", Version: v.Id, BuildVariant: "bv2", Activated: true, Status: evergreen.BuildCreated},
+	} {
+		require.NoError(t, b.Insert())
+	}
+	for _, tsk := range []task.Task{
+		{Id: "held", Version: v.Id, BuildId: "b1", BuildVariant: "bv1", Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "not_held", Version: v.Id, BuildId: "b2", BuildVariant: "bv2", Activated: true, Status: evergreen.TaskUndispatched},
+	} {
+		require.NoError(t, tsk.Insert())
+	}
+
+	versions, err := FindVersionsWithTimedOutExternalGates(now)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.NoError(t, versions[0].DeactivateTimedOutExternalGateTasks(now))
+
+	held, err := task.FindOneId("held")
+	require.NoError(t, err)
+	require.NotNil(t, held)
+	assert.False(t, held.Activated)
+	assert.Equal(t, evergreen.ExternalGateTimeoutUser, held.ActivatedBy)
+	notHeld, err := task.FindOneId("not_held")
+	require.NoError(t, err)
+	require.NotNil(t, notHeld)
+	assert.True(t, notHeld.Activated)
+
+	dbVersion, err := VersionFindOneId(v.Id)
+	require.NoError(t, err)
+	require.NotNil(t, dbVersion)
+	assert.False(t, dbVersion.GetExternalGate("timed-out").TimedOutAt.IsZero())
+
+	versions, err = FindVersionsWithTimedOutExternalGates(now)
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// UpdateVersionLabels sets and removes labels on the version and on the patch
+// that created it, if any. If there's no version yet because the patch has
+// not been finalized, only the patch's labels are updated. Each label change
+// is applied atomically, so concurrent changes to other labels are kept. It
+// returns the updated labels along with an HTTP status code describing any
+// error.
+func UpdateVersionLabels(versionID string, set map[string]string, remove []string) ([]patch.Label, int, error) {
+	v, err := VersionFindOneId(versionID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrapf(err, "finding version '%s'", versionID)
+	}
+	var p *patch.Patch
+	if patch.IsValidId(versionID) {
+		p, err = patch.FindOneId(versionID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrapf(err, "finding patch '%s'", versionID)
+		}
+	}
+	if v == nil && p == nil {
+		return nil, http.StatusNotFound, errors.Errorf("version '%s' not found", versionID)
+	}
+
+	var current []patch.Label
+	if v != nil {
+		current = v.Labels
+	} else {
+		current = p.Labels
+	}
+	if err = patch.ValidateLabels(patch.ApplyLabelChanges(current, set, remove)); err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "invalid labels")
+	}
+
+	var labels []patch.Label
+	if p != nil {
+		if err = p.UpdateLabels(set, remove); err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrapf(err, "updating labels for patch '%s'", versionID)
+		}
+		labels = p.Labels
+	}
+	if v != nil {
+		if err = v.UpdateLabels(set, remove); err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrapf(err, "updating labels for version '%s'", versionID)
+		}
+		labels = v.Labels
+	}
+	return labels, http.StatusOK, nil
+}
+
+// FindBuildsByVersionLabels returns the builds of the project's most recent
+// versions that match the options, which must filter by label. If a build
+// variant is given, only builds of that variant are returned. Builds are
+// ordered from the most recent version to the least recent.
+func FindBuildsByVersionLabels(projectName string, opts GetVersionsOptions) ([]build.Build, error) {
+	if len(opts.Labels) == 0 {
+		return nil, errors.New("must specify labels to filter builds by")
+	}
+	variant := opts.ByBuildVariant
+	opts.ByBuildVariant = ""
+	opts.IncludeBuilds = false
+	opts.IncludeTasks = false
+	versions, err := GetVersionsWithOptions(projectName, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding versions by label")
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	versionOrder := make(map[string]int, len(versions))
+	versionIDs := make([]string, 0, len(versions))
+	for _, v := range versions {
+		versionOrder[v.Id] = v.RevisionOrderNumber
+		versionIDs = append(versionIDs, v.Id)
+	}
+	query := bson.M{build.VersionKey: bson.M{"$in": versionIDs}}
+	if variant != "" {
+		query[build.BuildVariantKey] = variant
+	}
+	builds, err := build.Find(db.Query(query))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding builds of versions")
+	}
+	sort.SliceStable(builds, func(i, j int) bool {
+		if versionOrder[builds[i].Version] != versionOrder[builds[j].Version] {
+			return versionOrder[builds[i].Version] > versionOrder[builds[j].Version]
+		}
+		return builds[i].BuildVariant < builds[j].BuildVariant
+	})
+	return builds, nil
+}
@@ -39,7 +39,28 @@ func ModifyVersion(version Version, user user.DBUser, modifications VersionModif
 		if version.Requester == evergreen.MergeTestRequester && modifications.Active {
 			return http.StatusBadRequest, errors.New("commit queue merges cannot be manually scheduled")
 		}
+		if modifications.Active {
+			projectRef, err := FindMergedProjectRef(version.Identifier, version.Id, false)
+			if err != nil {
+				return http.StatusInternalServerError, errors.Wrapf(err, "finding project '%s'", version.Identifier)
+			}
+			if projectRef != nil {
+				numTasks, err := CountTasksToActivateForVersion(version.Id, user.Id)
+				if err != nil {
+					return http.StatusInternalServerError, errors.Wrap(err, "counting tasks to activate")
+				}
+				if err = CheckVersionActivationQuotas(projectRef, &version, numTasks); err != nil {
+					if IsProjectQuotaExceeded(err) {
+						return http.StatusBadRequest, err
+					}
+					return http.StatusInternalServerError, errors.Wrap(err, "checking project quotas")
+				}
+			}
+		}
 		if err := SetVersionActivation(version.Id, modifications.Active, user.Id); err != nil {
+			if IsVariantActivationDenied(err) {
+				return http.StatusForbidden, err
+			}
 			return http.StatusInternalServerError, errors.Wrap(err, "activating patch")
 		}
 		// abort after deactivating the version so we aren't bombarded with failing tasks while
@@ -50,7 +71,7 @@ func ModifyVersion(version Version, user user.DBUser, modifications VersionModif
 			}
 		}
 		if !modifications.Active && version.Requester == evergreen.MergeTestRequester {
-			err := RestartItemsAfterVersion(nil, version.Identifier, version.Id, user.Id)
+			_, err := RestartItemsAfterVersion(nil, version.Identifier, version.Id, user.Id, false)
 			if err != nil {
 				return http.StatusInternalServerError, errors.Wrap(err, "restarting later commit queue items")
 			}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// VersionSnapshot is the state of a version, its builds, and its tasks as of
+// a single point in time. Task updates are rolled up into their build and
+// version in separate writes, so the builds' and version's statuses are
+// recomputed from the snapshot's tasks rather than read as stored, so that
+// they never contradict the tasks.
+type VersionSnapshot struct {
+	// AsOf is the logical time of the snapshot. Reading a snapshot as of this
+	// time returns a snapshot that is at least as recent.
+	AsOf    primitive.Timestamp
+	Version Version
+	Builds  []build.Build
+	Tasks   []task.Task
+}
+
+// GetVersionSnapshot reads the version, its builds, and its tasks from a
+// single snapshot of the database that's at least as recent as the given
+// logical time, if one is given. It returns nil if the version doesn't exist.
+func GetVersionSnapshot(ctx context.Context, versionID string, asOf *primitive.Timestamp) (*VersionSnapshot, error) {
+	env := evergreen.GetEnvironment()
+	session, err := env.Client().StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		return nil, errors.Wrap(err, "starting DB session")
+	}
+	defer session.EndSession(ctx)
+	if asOf != nil {
+		if err = session.AdvanceOperationTime(asOf); err != nil {
+			return nil, errors.Wrap(err, "advancing session to snapshot time")
+		}
+	}
+
+	snapshot := &VersionSnapshot{}
+	found := true
+	txFunc := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		snapshot.Builds = nil
+		snapshot.Tasks = nil
+		db := env.DB()
+		err := db.Collection(VersionCollection).FindOne(sessCtx, bson.M{VersionIdKey: versionID},
+			options.FindOne().SetProjection(bson.M{VersionConfigKey: 0})).Decode(&snapshot.Version)
+		if err == mongo.ErrNoDocuments {
+			found = false
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding version '%s'", versionID)
+		}
+
+		cursor, err := db.Collection(build.Collection).Find(sessCtx, bson.M{build.VersionKey: versionID},
+			options.Find().SetProjection(bson.M{
+				build.IdKey:              1,
+				build.BuildVariantKey:    1,
+				build.DisplayNameKey:     1,
+				build.StatusKey:          1,
+				build.ActivatedKey:       1,
+				build.AbortedKey:         1,
+				build.AllTasksBlockedKey: 1,
+			}))
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding builds for version '%s'", versionID)
+		}
+		if err = cursor.All(sessCtx, &snapshot.Builds); err != nil {
+			return nil, errors.Wrapf(err, "decoding builds for version '%s'", versionID)
+		}
+
+		cursor, err = db.Collection(task.Collection).Find(sessCtx, bson.M{task.VersionKey: versionID},
+			options.Find().SetProjection(bson.M{
+				task.IdKey:                   1,
+				task.ExecutionKey:            1,
+				task.DisplayNameKey:          1,
+				task.BuildIdKey:              1,
+				task.BuildVariantKey:         1,
+				task.StatusKey:               1,
+				task.ActivatedKey:            1,
+				task.AbortedKey:              1,
+				task.DetailsKey:              1,
+				task.DependsOnKey:            1,
+				task.OverrideDependenciesKey: 1,
+				task.DisplayOnlyKey:          1,
+			}))
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding tasks for version '%s'", versionID)
+		}
+		if err = cursor.All(sessCtx, &snapshot.Tasks); err != nil {
+			return nil, errors.Wrapf(err, "decoding tasks for version '%s'", versionID)
+		}
+		return nil, nil
+	}
+
+	txOpts := options.Transaction().SetReadConcern(readconcern.Snapshot()).SetReadPreference(readpref.Primary())
+	if _, err = session.WithTransaction(ctx, txFunc, txOpts); err != nil {
+		return nil, errors.Wrapf(err, "reading snapshot of version '%s'", versionID)
+	}
+	if !found {
+		return nil, nil
+	}
+	if opTime := session.OperationTime(); opTime != nil {
+		snapshot.AsOf = *opTime
+	}
+
+	snapshot.rollUpStatuses()
+	return snapshot, nil
+}
+
+// rollUpStatuses recomputes the builds' statuses from the snapshot's tasks and
+// the version's status from its builds, the same way that task updates are
+// rolled up.
+func (s *VersionSnapshot) rollUpStatuses() {
+	tasksByBuild := map[string][]task.Task{}
+	for _, t := range s.Tasks {
+		tasksByBuild[t.BuildId] = append(tasksByBuild[t.BuildId], t)
+	}
+	for i, b := range s.Builds {
+		buildTasks, ok := tasksByBuild[b.Id]
+		if !ok {
+			continue
+		}
+		s.Builds[i].Status, s.Builds[i].AllTasksBlocked = getBuildStatus(buildTasks)
+	}
+	if len(s.Builds) > 0 {
+		s.Version.Status = getVersionStatus(s.Builds)
+	}
+}
+
+// FormatSnapshotTime formats a snapshot's logical time as
+// "<seconds>.<increment>".
+func FormatSnapshotTime(ts primitive.Timestamp) string {
+	return fmt.Sprintf("%d.%d", ts.T, ts.I)
+}
+
+// ParseSnapshotTime parses a logical time formatted by FormatSnapshotTime.
+func ParseSnapshotTime(s string) (*primitive.Timestamp, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 2 {
+		return nil, errors.Errorf("snapshot time '%s' must be formatted as '<seconds>.<increment>'", s)
+	}
+	t, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing seconds of snapshot time '%s'", s)
+	}
+	i, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing increment of snapshot time '%s'", s)
+	}
+	return &primitive.Timestamp{T: uint32(t), I: uint32(i)}, nil
+}
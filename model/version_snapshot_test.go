@@ -0,0 +1,123 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSnapshotTime(t *testing.T) {
+	ts := primitive.Timestamp{T: 1700000000, I: 12}
+	assert.Equal(t, "1700000000.12", FormatSnapshotTime(ts))
+
+	parsed, err := ParseSnapshotTime(FormatSnapshotTime(ts))
+	require.NoError(t, err)
+	assert.Equal(t, ts, *parsed)
+
+	for _, invalid := range []string{"", "1700000000", "1700000000.12.1", "abc.12", "1700000000.-1", "99999999999.1"} {
+		_, err = ParseSnapshotTime(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestVersionSnapshotRollUpStatuses(t *testing.T) {
+	t.Run("StaleBuildAndVersionStatusesAreRecomputed", func(t *testing.T) {
+		// The task finished but its build and version weren't updated yet.
+		snapshot := VersionSnapshot{
+			Version: Version{Id: "v", Status: evergreen.VersionStarted, Activated: utility.TruePtr()},
+			Builds: []build.Build{
+				{Id: "b1", Status: evergreen.BuildStarted, Activated: true},
+				{Id: "b2", Status: evergreen.BuildSucceeded, Activated: true},
+			},
+			Tasks: []task.Task{
+				{Id: "t1", BuildId: "b1", Status: evergreen.TaskFailed, Activated: true},
+				{Id: "t2", BuildId: "b1", Status: evergreen.TaskSucceeded, Activated: true},
+				{Id: "t3", BuildId: "b2", Status: evergreen.TaskSucceeded, Activated: true},
+			},
+		}
+		snapshot.rollUpStatuses()
+		assert.Equal(t, evergreen.BuildFailed, snapshot.Builds[0].Status)
+		assert.Equal(t, evergreen.BuildSucceeded, snapshot.Builds[1].Status)
+		assert.Equal(t, evergreen.VersionFailed, snapshot.Version.Status)
+	})
+	t.Run("BuildsWithoutTasksKeepTheirStatus", func(t *testing.T) {
+		snapshot := VersionSnapshot{
+			Version: Version{Id: "v", Status: evergreen.VersionCreated},
+			Builds:  []build.Build{{Id: "b1", Status: evergreen.BuildStarted, Activated: true}},
+		}
+		snapshot.rollUpStatuses()
+		assert.Equal(t, evergreen.BuildStarted, snapshot.Builds[0].Status)
+		assert.Equal(t, evergreen.VersionStarted, snapshot.Version.Status)
+	})
+}
+
+func TestGetVersionSnapshot(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	colls := []string{VersionCollection, build.Collection, task.Collection}
+	require.NoError(t, db.ClearCollections(colls...))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(colls...))
+	}()
+
+	v := &Version{Id: "v", Status: evergreen.VersionStarted, Activated: utility.TruePtr(), BuildIds: []string{"b1"}}
+	require.NoError(t, v.Insert())
+	b := &build.Build{Id: "b1", Version: "v", BuildVariant: "bv", Status: evergreen.BuildStarted, Activated: true}
+	require.NoError(t, b.Insert())
+	for _, tsk := range []task.Task{
+		{Id: "t1", Version: "v", BuildId: "b1", BuildVariant: "bv", Status: evergreen.TaskFailed, Activated: true},
+		{Id: "t2", Version: "v", BuildId: "b1", BuildVariant: "bv", Status: evergreen.TaskSucceeded, Activated: true},
+		{Id: "other", Version: "other", BuildId: "other", Status: evergreen.TaskSucceeded, Activated: true},
+	} {
+		require.NoError(t, tsk.Insert())
+	}
+
+	t.Run("ReadsVersionBuildsAndTasks", func(t *testing.T) {
+		snapshot, err := GetVersionSnapshot(ctx, "v", nil)
+		require.NoError(t, err)
+		require.NotNil(t, snapshot)
+		assert.Equal(t, "v", snapshot.Version.Id)
+		require.Len(t, snapshot.Builds, 1)
+		assert.Equal(t, "b1", snapshot.Builds[0].Id)
+		assert.Len(t, snapshot.Tasks, 2)
+		assert.NotZero(t, snapshot.AsOf.T)
+
+		// The stored build and version statuses are stale, so they're
+		// recomputed from the tasks.
+		assert.Equal(t, evergreen.BuildFailed, snapshot.Builds[0].Status)
+		assert.Equal(t, evergreen.VersionFailed, snapshot.Version.Status)
+	})
+	t.Run("ReadsAsOfPreviousSnapshot", func(t *testing.T) {
+		first, err := GetVersionSnapshot(ctx, "v", nil)
+		require.NoError(t, err)
+		require.NotNil(t, first)
+
+		require.NoError(t, task.UpdateOne(task.ById("t1"), bson.M{"$set": bson.M{task.StatusKey: evergreen.TaskSucceeded}}))
+		defer func() {
+			assert.NoError(t, task.UpdateOne(task.ById("t1"), bson.M{"$set": bson.M{task.StatusKey: evergreen.TaskFailed}}))
+		}()
+
+		asOf := first.AsOf
+		snapshot, err := GetVersionSnapshot(ctx, "v", &asOf)
+		require.NoError(t, err)
+		require.NotNil(t, snapshot)
+		assert.Equal(t, evergreen.BuildSucceeded, snapshot.Builds[0].Status)
+		assert.Equal(t, evergreen.VersionSucceeded, snapshot.Version.Status)
+		assert.True(t, primitive.CompareTimestamp(snapshot.AsOf, first.AsOf) >= 0)
+	})
+	t.Run("ReturnsNilForNonexistentVersion", func(t *testing.T) {
+		snapshot, err := GetVersionSnapshot(ctx, "nonexistent", nil)
+		require.NoError(t, err)
+		assert.Nil(t, snapshot)
+	})
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"sort"
 	"testing"
 	"time"
@@ -12,11 +13,14 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
 	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/utility"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func TestLastKnownGoodConfig(t *testing.T) {
@@ -594,3 +598,143 @@ func TestVersionSetNotActivated(t *testing.T) {
 	}
 
 }
+
+func TestUpdateVersionLabels(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection, patch.Collection))
+	}()
+	for tName, tCase := range map[string]func(t *testing.T){
+		"UpdatesVersionAndPatch": func(t *testing.T) {
+			patchID := mgobson.NewObjectId()
+			p := patch.Patch{Id: patchID, Labels: []patch.Label{{Key: "hotfix"}}}
+			require.NoError(t, p.Insert())
+			v := Version{Id: patchID.Hex(), Labels: []patch.Label{{Key: "hotfix"}}}
+			require.NoError(t, v.Insert())
+
+			labels, status, err := UpdateVersionLabels(v.Id, map[string]string{"release": "1.0"}, []string{"hotfix"})
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, status)
+			expected := []patch.Label{{Key: "release", Value: "1.0"}}
+			assert.Equal(t, expected, labels)
+
+			dbVersion, err := VersionFindOneId(v.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbVersion)
+			assert.Equal(t, expected, dbVersion.Labels)
+			dbPatch, err := patch.FindOneId(v.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbPatch)
+			assert.Equal(t, expected, dbPatch.Labels)
+		},
+		"UpdatesUnfinalizedPatch": func(t *testing.T) {
+			patchID := mgobson.NewObjectId()
+			p := patch.Patch{Id: patchID}
+			require.NoError(t, p.Insert())
+
+			labels, _, err := UpdateVersionLabels(patchID.Hex(), map[string]string{"release": "1.0"}, nil)
+			require.NoError(t, err)
+			assert.Equal(t, []patch.Label{{Key: "release", Value: "1.0"}}, labels)
+		},
+		"FailsWithInvalidLabel": func(t *testing.T) {
+			v := Version{Id: "version"}
+			require.NoError(t, v.Insert())
+
+			_, status, err := UpdateVersionLabels(v.Id, map[string]string{"not a key": ""}, nil)
+			assert.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, status)
+
+			dbVersion, err := VersionFindOneId(v.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbVersion)
+			assert.Empty(t, dbVersion.Labels)
+		},
+		"KeepsConcurrentChanges": func(t *testing.T) {
+			v := Version{Id: "version", Labels: []patch.Label{{Key: "hotfix"}}}
+			require.NoError(t, v.Insert())
+			stale := v
+
+			_, _, err := UpdateVersionLabels(v.Id, map[string]string{"release": "1.0"}, nil)
+			require.NoError(t, err)
+			require.NoError(t, stale.UpdateLabels(map[string]string{"candidate": "rc1"}, []string{"hotfix"}))
+
+			expected := []patch.Label{{Key: "candidate", Value: "rc1"}, {Key: "release", Value: "1.0"}}
+			assert.Equal(t, expected, stale.Labels)
+			dbVersion, err := VersionFindOneId(v.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbVersion)
+			patch.SortLabels(dbVersion.Labels)
+			assert.Equal(t, expected, dbVersion.Labels)
+		},
+		"FailsWithNonexistentVersion": func(t *testing.T) {
+			_, status, err := UpdateVersionLabels("nonexistent", map[string]string{"release": ""}, nil)
+			assert.Error(t, err)
+			assert.Equal(t, http.StatusNotFound, status)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(VersionCollection, patch.Collection))
+			tCase(t)
+		})
+	}
+}
+
+func TestGetVersionsWithLabels(t *testing.T) {
+	assert.NoError(t, db.ClearCollections(VersionCollection, ProjectRefCollection, build.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(VersionCollection, ProjectRefCollection, build.Collection))
+	}()
+	require.NoError(t, db.EnsureIndex(VersionCollection, mongo.IndexModel{Keys: VersionLabelsIndex}))
+	pRef := ProjectRef{Id: "project", Identifier: "identifier"}
+	require.NoError(t, pRef.Insert())
+	for i, labels := range [][]patch.Label{
+		nil,
+		{{Key: "release", Value: "1.0"}},
+		{{Key: "release", Value: "1.1"}, {Key: "hotfix"}},
+	} {
+		v := Version{
+			Id:                  fmt.Sprintf("v%d", i),
+			Identifier:          pRef.Id,
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: i + 1,
+			Labels:              labels,
+		}
+		require.NoError(t, v.Insert())
+		for _, variant := range []string{"ubuntu", "windows"} {
+			b := build.Build{Id: fmt.Sprintf("%s_%s", v.Id, variant), Version: v.Id, BuildVariant: variant}
+			require.NoError(t, b.Insert())
+		}
+	}
+
+	getIDs := func(labels []patch.Label) []string {
+		versions, err := GetVersionsWithOptions(pRef.Identifier, GetVersionsOptions{Requester: evergreen.RepotrackerVersionRequester, Limit: 10, Labels: labels})
+		require.NoError(t, err)
+		ids := []string{}
+		for _, v := range versions {
+			ids = append(ids, v.Id)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"v2", "v1", "v0"}, getIDs(nil))
+	assert.Equal(t, []string{"v2", "v1"}, getIDs([]patch.Label{{Key: "release"}}))
+	assert.Equal(t, []string{"v1"}, getIDs([]patch.Label{{Key: "release", Value: "1.0"}}))
+	assert.Equal(t, []string{"v2"}, getIDs([]patch.Label{{Key: "release"}, {Key: "hotfix"}}))
+	assert.Empty(t, getIDs([]patch.Label{{Key: "release", Value: "2.0"}}))
+
+	t.Run("FiltersBuilds", func(t *testing.T) {
+		builds, err := FindBuildsByVersionLabels(pRef.Identifier, GetVersionsOptions{Requester: evergreen.RepotrackerVersionRequester, Limit: 10, Labels: []patch.Label{{Key: "release"}}})
+		require.NoError(t, err)
+		ids := []string{}
+		for _, b := range builds {
+			ids = append(ids, b.Id)
+		}
+		assert.Equal(t, []string{"v2_ubuntu", "v2_windows", "v1_ubuntu", "v1_windows"}, ids)
+
+		builds, err = FindBuildsByVersionLabels(pRef.Identifier, GetVersionsOptions{Requester: evergreen.RepotrackerVersionRequester, Limit: 10, Labels: []patch.Label{{Key: "hotfix"}}, ByBuildVariant: "windows"})
+		require.NoError(t, err)
+		require.Len(t, builds, 1)
+		assert.Equal(t, "v2_windows", builds[0].Id)
+
+		_, err = FindBuildsByVersionLabels(pRef.Identifier, GetVersionsOptions{Requester: evergreen.RepotrackerVersionRequester, Limit: 10})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+// VersionWarningBudget records how a mainline version's project config
+// validation warnings compare to the project's warning budget and to the
+// warnings of the previous mainline version.
+type VersionWarningBudget struct {
+	MaxWarnings int `bson:"max_warnings" json:"max_warnings"`
+	NumWarnings int `bson:"num_warnings" json:"num_warnings"`
+	// PreviousVersion is the mainline version that the warnings are compared
+	// against, if there is one.
+	PreviousVersion     string `bson:"previous_version,omitempty" json:"previous_version,omitempty"`
+	PreviousNumWarnings int    `bson:"previous_num_warnings" json:"previous_num_warnings"`
+	// NewWarnings are the warnings that the previous version did not have.
+	NewWarnings []string `bson:"new_warnings,omitempty" json:"new_warnings,omitempty"`
+	// ResolvedWarnings are the warnings that the previous version had which
+	// this version no longer has.
+	ResolvedWarnings []string `bson:"resolved_warnings,omitempty" json:"resolved_warnings,omitempty"`
+}
+
+// Exceeded returns whether the version has more warnings than the budget
+// allows.
+func (b *VersionWarningBudget) Exceeded() bool {
+	return b.NumWarnings > b.MaxWarnings
+}
+
+// Delta returns the change in the number of warnings since the previous
+// version.
+func (b *VersionWarningBudget) Delta() int {
+	return b.NumWarnings - b.PreviousNumWarnings
+}
+
+// NewVersionWarningBudget compares the version's warnings to the budget and to
+// the warnings of the most recent mainline version before it.
+func NewVersionWarningBudget(v *Version, maxWarnings int) (*VersionWarningBudget, error) {
+	budget := &VersionWarningBudget{
+		MaxWarnings: maxWarnings,
+		NumWarnings: len(v.Warnings),
+	}
+
+	var previousWarnings []string
+	if v.RevisionOrderNumber > 1 {
+		previous, err := VersionFindOne(VersionsByRequesterOrdered(v.Identifier, evergreen.RepotrackerVersionRequester, 1, v.RevisionOrderNumber))
+		if err != nil {
+			return nil, errors.Wrap(err, "finding previous mainline version")
+		}
+		if previous != nil {
+			budget.PreviousVersion = previous.Id
+			budget.PreviousNumWarnings = len(previous.Warnings)
+			previousWarnings = previous.Warnings
+		}
+	}
+
+	budget.NewWarnings, budget.ResolvedWarnings = diffWarnings(previousWarnings, v.Warnings)
+
+	return budget, nil
+}
+
+// diffWarnings returns the warnings that were added and removed between the
+// old and new warnings.
+func diffWarnings(oldWarnings, newWarnings []string) (added, removed []string) {
+	oldCounts := map[string]int{}
+	for _, w := range oldWarnings {
+		oldCounts[w]++
+	}
+	for _, w := range newWarnings {
+		if oldCounts[w] > 0 {
+			oldCounts[w]--
+			continue
+		}
+		added = append(added, w)
+	}
+	for _, w := range oldWarnings {
+		if oldCounts[w] > 0 {
+			oldCounts[w]--
+			removed = append(removed, w)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVersionWarningBudget(t *testing.T) {
+	require.NoError(t, db.Clear(VersionCollection))
+	defer func() {
+		assert.NoError(t, db.Clear(VersionCollection))
+	}()
+
+	previous := Version{
+		Id:                  "v1",
+		Identifier:          "p1",
+		Requester:           evergreen.RepotrackerVersionRequester,
+		RevisionOrderNumber: 1,
+		Warnings:            []string{"a", "b"},
+	}
+	require.NoError(t, previous.Insert())
+	patchVersion := Version{
+		Id:                  "patch",
+		Identifier:          "p1",
+		Requester:           evergreen.PatchVersionRequester,
+		RevisionOrderNumber: 2,
+	}
+	require.NoError(t, patchVersion.Insert())
+
+	t.Run("ComparesToPreviousMainlineVersion", func(t *testing.T) {
+		v := &Version{
+			Id:                  "v2",
+			Identifier:          "p1",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: 3,
+			Warnings:            []string{"b", "c", "d"},
+		}
+		budget, err := NewVersionWarningBudget(v, 2)
+		require.NoError(t, err)
+		assert.True(t, budget.Exceeded())
+		assert.Equal(t, "v1", budget.PreviousVersion)
+		assert.Equal(t, 1, budget.Delta())
+		assert.Equal(t, []string{"c", "d"}, budget.NewWarnings)
+		assert.Equal(t, []string{"a"}, budget.ResolvedWarnings)
+	})
+	t.Run("WithinBudget", func(t *testing.T) {
+		v := &Version{
+			Id:                  "v2",
+			Identifier:          "p1",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: 3,
+			Warnings:            []string{"a"},
+		}
+		budget, err := NewVersionWarningBudget(v, 1)
+		require.NoError(t, err)
+		assert.False(t, budget.Exceeded())
+		assert.Equal(t, -1, budget.Delta())
+		assert.Empty(t, budget.NewWarnings)
+		assert.Equal(t, []string{"b"}, budget.ResolvedWarnings)
+	})
+	t.Run("FirstVersion", func(t *testing.T) {
+		v := &Version{
+			Id:                  "v0",
+			Identifier:          "p1",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: 1,
+			Warnings:            []string{"a"},
+		}
+		budget, err := NewVersionWarningBudget(v, 0)
+		require.NoError(t, err)
+		assert.True(t, budget.Exceeded())
+		assert.Empty(t, budget.PreviousVersion)
+		assert.Equal(t, []string{"a"}, budget.NewWarnings)
+	})
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/mongodb/grip"
+	ignore "github.com/sabhiram/go-gitignore"
+)
+
+const (
+	// WatchedPathsPolicyActivate creates versions for commits that don't
+	// touch a watched path, but doesn't activate them. This is the default
+	// policy.
+	WatchedPathsPolicyActivate = "activate"
+	// WatchedPathsPolicyCreate doesn't create versions for commits that don't
+	// touch a watched path.
+	WatchedPathsPolicyCreate = "create"
+
+	// maxVersionMatchedPaths is the most matched paths stored on a version.
+	maxVersionMatchedPaths = 100
+)
+
+// ValidateWatchedPaths checks that the project's watched paths are valid
+// gitignore-style glob patterns and that its watched paths policy is valid.
+func ValidateWatchedPaths(paths []string, policy string) error {
+	catcher := grip.NewBasicCatcher()
+	for _, path := range paths {
+		pattern := strings.TrimPrefix(strings.TrimSpace(path), "!")
+		if pattern == "" {
+			catcher.New("watched path cannot be empty")
+			continue
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			catcher.Errorf("watched path '%s' is not a valid glob pattern", path)
+		}
+	}
+	switch policy {
+	case "", WatchedPathsPolicyActivate, WatchedPathsPolicyCreate:
+	default:
+		catcher.Errorf("invalid watched paths policy '%s', must be one of '%s' or '%s'", policy, WatchedPathsPolicyActivate, WatchedPathsPolicyCreate)
+	}
+	catcher.NewWhen(len(paths) == 0 && policy != "", "cannot set a watched paths policy without watched paths")
+	return catcher.Resolve()
+}
+
+// GetWatchedPathsPolicy returns what the repotracker does with commits that
+// don't touch any of the project's watched paths.
+func (p *ProjectRef) GetWatchedPathsPolicy() string {
+	if p.WatchedPathsPolicy == "" {
+		return WatchedPathsPolicyActivate
+	}
+	return p.WatchedPathsPolicy
+}
+
+// MatchWatchedPaths returns the files that match the project's watched paths.
+// If the project doesn't watch any paths, every file matches.
+func (p *ProjectRef) MatchWatchedPaths(files []string) []string {
+	if len(p.WatchedPaths) == 0 {
+		return files
+	}
+	// CompileIgnoreLines always returns a nil error.
+	matcher := ignore.CompileIgnoreLines(p.WatchedPaths...)
+	var matched []string
+	for _, f := range files {
+		if matcher.MatchesPath(f) {
+			matched = append(matched, f)
+		}
+	}
+	return matched
+}
+
+// TruncateMatchedPaths limits the matched paths to the number that can be
+// stored on a version.
+func TruncateMatchedPaths(paths []string) []string {
+	if len(paths) > maxVersionMatchedPaths {
+		return paths[:maxVersionMatchedPaths]
+	}
+	return paths
+}
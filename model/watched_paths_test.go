@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWatchedPaths(t *testing.T) {
+	assert.NoError(t, ValidateWatchedPaths(nil, ""))
+	assert.NoError(t, ValidateWatchedPaths([]string{"src/server/**", "*.proto", "!docs/"}, WatchedPathsPolicyCreate))
+	assert.Error(t, ValidateWatchedPaths([]string{""}, ""))
+	assert.Error(t, ValidateWatchedPaths([]string{"src/[server"}, ""))
+	assert.Error(t, ValidateWatchedPaths([]string{"src/**"}, "ignore"))
+	assert.Error(t, ValidateWatchedPaths(nil, WatchedPathsPolicyActivate))
+}
+
+func TestMatchWatchedPaths(t *testing.T) {
+	files := []string{"src/server/main.go", "src/client/main.go", "README.md"}
+
+	pRef := ProjectRef{}
+	assert.Equal(t, files, pRef.MatchWatchedPaths(files))
+	assert.Equal(t, WatchedPathsPolicyActivate, pRef.GetWatchedPathsPolicy())
+
+	pRef.WatchedPaths = []string{"src/server/", "*.md"}
+	assert.Equal(t, []string{"src/server/main.go", "README.md"}, pRef.MatchWatchedPaths(files))
+
+	pRef.WatchedPaths = []string{"src/", "!src/client/"}
+	assert.Equal(t, []string{"src/server/main.go"}, pRef.MatchWatchedPaths(files))
+
+	pRef.WatchedPaths = []string{"docs/"}
+	assert.Empty(t, pRef.MatchWatchedPaths(files))
+}
@@ -39,6 +39,7 @@ func Admin() cli.Command {
 			updateServiceUser(),
 			getServiceUsers(),
 			deleteServiceUser(),
+			repairCommitQueue(),
 		},
 	}
 }
@@ -604,3 +605,56 @@ func deleteServiceUser() cli.Command {
 		},
 	}
 }
+
+func repairCommitQueue() cli.Command {
+	const dryRunFlagName = "dry-run"
+	return cli.Command{
+		Name:  "repair-commit-queue",
+		Usage: "fix broken merge task dependency chains in a project's commit queue",
+		Flags: addProjectFlag(cli.BoolFlag{
+			Name:  dryRunFlagName,
+			Usage: "only report the broken dependencies without fixing them",
+		}),
+		Before: requireStringFlag(projectFlagName),
+		Action: func(c *cli.Context) error {
+			projectID := c.String(projectFlagName)
+			dryRun := c.Bool(dryRunFlagName)
+
+			confPath := c.Parent().Parent().String(confFlagName)
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			client := conf.setupRestCommunicator(ctx)
+			defer client.Close()
+
+			result, err := client.RepairCommitQueue(ctx, projectID, dryRun)
+			if err != nil {
+				return errors.Wrapf(err, "repairing commit queue for project '%s'", projectID)
+			}
+			if len(result.Changes) == 0 {
+				fmt.Println("No broken merge task dependencies found.")
+				return nil
+			}
+			if dryRun {
+				fmt.Println("Would make the following changes:")
+			} else {
+				fmt.Println("Made the following changes:")
+			}
+			for _, change := range result.Changes {
+				line := fmt.Sprintf("merge task '%s'", utility.FromStringPtr(change.TaskID))
+				if change.RemoveDependencyOn != nil {
+					line += fmt.Sprintf(": remove dependency on '%s'", utility.FromStringPtr(change.RemoveDependencyOn))
+				}
+				if change.AddDependencyOn != nil {
+					line += fmt.Sprintf(": add dependency on '%s'", utility.FromStringPtr(change.AddDependencyOn))
+				}
+				fmt.Println(line)
+			}
+
+			return nil
+		},
+	}
+}
@@ -23,6 +23,7 @@ const (
 	tasksFlagName             = "tasks"
 	regexTasksFlagName        = "regex_tasks"
 	parameterFlagName         = "param"
+	expansionOverrideFlagName = "expansion-override"
 	patchAliasFlagName        = "alias"
 	patchFinalizeFlagName     = "finalize"
 	patchBrowseFlagName       = "browse"
@@ -106,6 +107,13 @@ func addParameterFlag(flags ...cli.Flag) []cli.Flag {
 	})
 }
 
+func addExpansionOverrideFlag(flags ...cli.Flag) []cli.Flag {
+	return append(flags, cli.StringSliceFlag{
+		Name:  expansionOverrideFlagName,
+		Usage: "override an expansion for all tasks as a KEY=VALUE pair (the project must allow the expansion to be overridden)",
+	})
+}
+
 func addPatchFinalizeFlag(flags ...cli.Flag) []cli.Flag {
 	return append(flags, cli.BoolFlag{
 		Name:  joinFlagNames(patchFinalizeFlagName, "f"),
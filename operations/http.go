@@ -528,51 +528,53 @@ func (ac *legacyClient) PutPatch(incomingPatch patchSubmission) (*patch.Patch, e
 	// Because marshalling a byte slice to JSON will base64 encode it, the patch will be sent over the wire in base64
 	// and non utf-8 characters will be preserved.
 	data := struct {
-		Description       string             `json:"desc"`
-		Project           string             `json:"project"`
-		Path              string             `json:"path"`
-		PatchBytes        []byte             `json:"patch_bytes"`
-		Githash           string             `json:"githash"`
-		Alias             string             `json:"alias"`
-		Variants          []string           `json:"buildvariants_new"`
-		Tasks             []string           `json:"tasks"`
-		RegexVariants     []string           `json:"regex_buildvariants"`
-		RegexTasks        []string           `json:"regex_tasks"`
-		SyncTasks         []string           `json:"sync_tasks"`
-		SyncBuildVariants []string           `json:"sync_build_variants"`
-		SyncStatuses      []string           `json:"sync_statuses"`
-		SyncTimeout       time.Duration      `json:"sync_timeout"`
-		Finalize          bool               `json:"finalize"`
-		BackportInfo      patch.BackportInfo `json:"backport_info"`
-		TriggerAliases    []string           `json:"trigger_aliases"`
-		Parameters        []patch.Parameter  `json:"parameters"`
-		GitMetadata       patch.GitMetadata  `json:"git_metadata"`
-		RepeatDefinition  bool               `json:"reuse_definition"`
-		RepeatFailed      bool               `json:"repeat_failed"`
-		GithubAuthor      string             `json:"github_author"`
+		Description        string             `json:"desc"`
+		Project            string             `json:"project"`
+		Path               string             `json:"path"`
+		PatchBytes         []byte             `json:"patch_bytes"`
+		Githash            string             `json:"githash"`
+		Alias              string             `json:"alias"`
+		Variants           []string           `json:"buildvariants_new"`
+		Tasks              []string           `json:"tasks"`
+		RegexVariants      []string           `json:"regex_buildvariants"`
+		RegexTasks         []string           `json:"regex_tasks"`
+		SyncTasks          []string           `json:"sync_tasks"`
+		SyncBuildVariants  []string           `json:"sync_build_variants"`
+		SyncStatuses       []string           `json:"sync_statuses"`
+		SyncTimeout        time.Duration      `json:"sync_timeout"`
+		Finalize           bool               `json:"finalize"`
+		BackportInfo       patch.BackportInfo `json:"backport_info"`
+		TriggerAliases     []string           `json:"trigger_aliases"`
+		Parameters         []patch.Parameter  `json:"parameters"`
+		ExpansionOverrides map[string]string  `json:"expansion_overrides,omitempty"`
+		GitMetadata        patch.GitMetadata  `json:"git_metadata"`
+		RepeatDefinition   bool               `json:"reuse_definition"`
+		RepeatFailed       bool               `json:"repeat_failed"`
+		GithubAuthor       string             `json:"github_author"`
 	}{
-		Description:       incomingPatch.description,
-		Project:           incomingPatch.projectName,
-		Path:              incomingPatch.path,
-		PatchBytes:        []byte(incomingPatch.patchData),
-		Githash:           incomingPatch.base,
-		Alias:             incomingPatch.alias,
-		Variants:          incomingPatch.variants,
-		Tasks:             incomingPatch.tasks,
-		RegexVariants:     incomingPatch.regexVariants,
-		RegexTasks:        incomingPatch.regexTasks,
-		SyncBuildVariants: incomingPatch.syncBuildVariants,
-		SyncTasks:         incomingPatch.syncTasks,
-		SyncStatuses:      incomingPatch.syncStatuses,
-		SyncTimeout:       incomingPatch.syncTimeout,
-		Finalize:          incomingPatch.finalize,
-		BackportInfo:      incomingPatch.backportOf,
-		TriggerAliases:    incomingPatch.triggerAliases,
-		Parameters:        incomingPatch.parameters,
-		GitMetadata:       incomingPatch.gitMetadata,
-		RepeatDefinition:  incomingPatch.repeatDefinition,
-		RepeatFailed:      incomingPatch.repeatFailed,
-		GithubAuthor:      incomingPatch.githubAuthor,
+		Description:        incomingPatch.description,
+		Project:            incomingPatch.projectName,
+		Path:               incomingPatch.path,
+		PatchBytes:         []byte(incomingPatch.patchData),
+		Githash:            incomingPatch.base,
+		Alias:              incomingPatch.alias,
+		Variants:           incomingPatch.variants,
+		Tasks:              incomingPatch.tasks,
+		RegexVariants:      incomingPatch.regexVariants,
+		RegexTasks:         incomingPatch.regexTasks,
+		SyncBuildVariants:  incomingPatch.syncBuildVariants,
+		SyncTasks:          incomingPatch.syncTasks,
+		SyncStatuses:       incomingPatch.syncStatuses,
+		SyncTimeout:        incomingPatch.syncTimeout,
+		Finalize:           incomingPatch.finalize,
+		BackportInfo:       incomingPatch.backportOf,
+		TriggerAliases:     incomingPatch.triggerAliases,
+		Parameters:         incomingPatch.parameters,
+		ExpansionOverrides: incomingPatch.expansionOverrides,
+		GitMetadata:        incomingPatch.gitMetadata,
+		RepeatDefinition:   incomingPatch.repeatDefinition,
+		RepeatFailed:       incomingPatch.repeatFailed,
+		GithubAuthor:       incomingPatch.githubAuthor,
 	}
 
 	rPipe, wPipe := io.Pipe()
@@ -596,12 +598,16 @@ func (ac *legacyClient) PutPatch(incomingPatch patchSubmission) (*patch.Patch, e
 	}
 
 	reply := struct {
-		Patch *patch.Patch `json:"patch"`
+		Patch    *patch.Patch `json:"patch"`
+		Warnings []string     `json:"warnings"`
 	}{}
 
 	if err := utility.ReadJSON(resp.Body, &reply); err != nil {
 		return nil, err
 	}
+	for _, warning := range reply.Warnings {
+		grip.Warning(warning)
+	}
 
 	return reply.Patch, nil
 }
@@ -27,6 +27,7 @@ func getPatchFlags(flags ...cli.Flag) []cli.Flag {
 		addPatchFinalizeFlag(),
 		addVariantsFlag(),
 		addParameterFlag(),
+		addExpansionOverrideFlag(),
 		addPatchBrowseFlag(),
 		addSyncBuildVariantsFlag(),
 		addSyncTasksFlag(),
@@ -136,6 +137,10 @@ func Patch() cli.Command {
 			if err != nil {
 				return err
 			}
+			params.ExpansionOverrides, err = getExpansionOverridesFromInput(c.StringSlice(expansionOverrideFlagName))
+			if err != nil {
+				return err
+			}
 
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -218,6 +223,21 @@ func getParametersFromInput(params []string) ([]patch.Parameter, error) {
 	return res, catcher.Resolve()
 }
 
+func getExpansionOverridesFromInput(overrides []string) (map[string]string, error) {
+	params, err := getParametersFromInput(overrides)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing expansion overrides")
+	}
+	if len(params) == 0 {
+		return nil, nil
+	}
+	res := map[string]string{}
+	for _, param := range params {
+		res[param.Key] = param.Value
+	}
+	return res, nil
+}
+
 func PatchFile() cli.Command {
 	const (
 		baseFlagName     = "base"
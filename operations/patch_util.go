@@ -54,83 +54,86 @@ type localDiff struct {
 }
 
 type patchParams struct {
-	Project           string
-	Path              string
-	Alias             string
-	Variants          []string
-	Tasks             []string
-	RegexVariants     []string
-	RegexTasks        []string
-	SyncBuildVariants []string
-	SyncTasks         []string
-	SyncStatuses      []string
-	SyncTimeout       time.Duration
-	Description       string
-	SkipConfirm       bool
-	Finalize          bool
-	Browse            bool
-	Large             bool
-	ShowSummary       bool
-	Uncommitted       bool
-	PreserveCommits   bool
-	Ref               string
-	BackportOf        patch.BackportInfo
-	TriggerAliases    []string
-	Parameters        []patch.Parameter
-	RepeatDefinition  bool
-	RepeatFailed      bool
-	GithubAuthor      string
+	Project            string
+	Path               string
+	Alias              string
+	Variants           []string
+	Tasks              []string
+	RegexVariants      []string
+	RegexTasks         []string
+	SyncBuildVariants  []string
+	SyncTasks          []string
+	SyncStatuses       []string
+	SyncTimeout        time.Duration
+	Description        string
+	SkipConfirm        bool
+	Finalize           bool
+	Browse             bool
+	Large              bool
+	ShowSummary        bool
+	Uncommitted        bool
+	PreserveCommits    bool
+	Ref                string
+	BackportOf         patch.BackportInfo
+	TriggerAliases     []string
+	Parameters         []patch.Parameter
+	ExpansionOverrides map[string]string
+	RepeatDefinition   bool
+	RepeatFailed       bool
+	GithubAuthor       string
 }
 
 type patchSubmission struct {
-	projectName       string
-	patchData         string
-	description       string
-	base              string
-	alias             string
-	path              string
-	variants          []string
-	tasks             []string
-	regexVariants     []string
-	regexTasks        []string
-	syncBuildVariants []string
-	syncTasks         []string
-	syncStatuses      []string
-	syncTimeout       time.Duration
-	finalize          bool
-	parameters        []patch.Parameter
-	triggerAliases    []string
-	backportOf        patch.BackportInfo
-	gitMetadata       patch.GitMetadata
-	repeatDefinition  bool
-	repeatFailed      bool
-	githubAuthor      string
+	projectName        string
+	patchData          string
+	description        string
+	base               string
+	alias              string
+	path               string
+	variants           []string
+	tasks              []string
+	regexVariants      []string
+	regexTasks         []string
+	syncBuildVariants  []string
+	syncTasks          []string
+	syncStatuses       []string
+	syncTimeout        time.Duration
+	finalize           bool
+	parameters         []patch.Parameter
+	expansionOverrides map[string]string
+	triggerAliases     []string
+	backportOf         patch.BackportInfo
+	gitMetadata        patch.GitMetadata
+	repeatDefinition   bool
+	repeatFailed       bool
+	githubAuthor       string
 }
 
 func (p *patchParams) createPatch(ac *legacyClient, diffData *localDiff) (*patch.Patch, error) {
 	patchSub := patchSubmission{
-		projectName:       p.Project,
-		patchData:         diffData.fullPatch,
-		description:       p.Description,
-		base:              diffData.base,
-		variants:          p.Variants,
-		tasks:             p.Tasks,
-		regexVariants:     p.RegexVariants,
-		regexTasks:        p.RegexTasks,
-		alias:             p.Alias,
-		syncBuildVariants: p.SyncBuildVariants,
-		syncTasks:         p.SyncTasks,
-		syncStatuses:      p.SyncStatuses,
-		syncTimeout:       p.SyncTimeout,
-		finalize:          p.Finalize,
-		backportOf:        p.BackportOf,
-		parameters:        p.Parameters,
-		triggerAliases:    p.TriggerAliases,
-		gitMetadata:       diffData.gitMetadata,
-		repeatDefinition:  p.RepeatDefinition,
-		repeatFailed:      p.RepeatFailed,
-		path:              p.Path,
-		githubAuthor:      p.GithubAuthor,
+		projectName:        p.Project,
+		patchData:          diffData.fullPatch,
+		description:        p.Description,
+		base:               diffData.base,
+		variants:           p.Variants,
+		tasks:              p.Tasks,
+		regexVariants:      p.RegexVariants,
+		regexTasks:         p.RegexTasks,
+		alias:              p.Alias,
+		syncBuildVariants:  p.SyncBuildVariants,
+		syncTasks:          p.SyncTasks,
+		syncStatuses:       p.SyncStatuses,
+		syncTimeout:        p.SyncTimeout,
+		finalize:           p.Finalize,
+		backportOf:         p.BackportOf,
+		parameters:         p.Parameters,
+		expansionOverrides: p.ExpansionOverrides,
+		triggerAliases:     p.TriggerAliases,
+		gitMetadata:        diffData.gitMetadata,
+		repeatDefinition:   p.RepeatDefinition,
+		repeatFailed:       p.RepeatFailed,
+		path:               p.Path,
+		githubAuthor:       p.GithubAuthor,
 	}
 
 	newPatch, err := ac.PutPatch(patchSub)
@@ -393,7 +396,7 @@ func (p *patchParams) loadVariants(conf *ClientSettings) error {
 	return nil
 }
 
-//Option to set default parameters no longer supported to prevent unwanted parameters from persisting within future patches
+// Option to set default parameters no longer supported to prevent unwanted parameters from persisting within future patches
 func (p *patchParams) loadParameters(conf *ClientSettings) error {
 	if len(p.Parameters) == 0 {
 		p.Parameters = conf.FindDefaultParameters(p.Project)
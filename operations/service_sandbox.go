@@ -0,0 +1,121 @@
+package operations
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	sandboxFlagName      = "sandbox"
+	sandboxReposFlagName = "sandbox-repos"
+)
+
+// sandboxSenderKeys are the notification senders that sandbox mode logs
+// instead of sending.
+var sandboxSenderKeys = []evergreen.SenderKey{
+	evergreen.SenderGithubStatus,
+	evergreen.SenderEvergreenWebhook,
+	evergreen.SenderSlack,
+	evergreen.SenderJIRAIssue,
+	evergreen.SenderJIRAComment,
+	evergreen.SenderEmail,
+}
+
+func addSandboxFlags(flags ...cli.Flag) []cli.Flag {
+	return append(flags,
+		cli.BoolFlag{
+			Name: sandboxFlagName,
+			Usage: "run against a local single-node database with GitHub, Jira, S3, and notifications " +
+				"stubbed out, for local development",
+		},
+		cli.StringFlag{
+			Name: sandboxReposFlagName,
+			Usage: "in sandbox mode, the directory that GitHub repositories are served from, " +
+				"where the repository owner/repo is the directory owner/repo (can be a git checkout)",
+		})
+}
+
+// configureSandbox replaces the environment's external integrations with ones
+// that work without network access or credentials, and logs notifications
+// instead of sending them. It must be called before any jobs or web services
+// start.
+func configureSandbox(env evergreen.Environment, reposDir string) error {
+	settings := env.Settings()
+	if err := checkSandboxDatabase(settings.Database.Url); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if reposDir != "" {
+		github, err := thirdparty.NewSandboxGithubIntegration(reposDir)
+		if err != nil {
+			return errors.Wrap(err, "setting up sandbox GitHub integration")
+		}
+		thirdparty.SetGithubIntegration(github)
+	}
+	thirdparty.SetJiraIntegration(thirdparty.NewSandboxJiraIntegration())
+
+	// Annotation attachments are the only contents that the app server
+	// stores in S3 itself.
+	settings.AnnotationAttachments.Backend = evergreen.AnnotationAttachmentBackendDB
+	if settings.AnnotationAttachments.SigningKey == "" {
+		settings.AnnotationAttachments.SigningKey = utility.RandomString()
+	}
+
+	sender, err := send.NewNativeLogger("evergreen.sandbox", send.LevelInfo{Default: level.Info, Threshold: level.Info})
+	if err != nil {
+		return errors.Wrap(err, "creating sandbox notification sender")
+	}
+	catcher := grip.NewBasicCatcher()
+	for _, key := range sandboxSenderKeys {
+		catcher.Wrapf(env.SetSender(key, sender), "replacing sender %d", key)
+	}
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	grip.Notice(message.Fields{
+		"message":  "running in sandbox mode",
+		"database": settings.Database.DB,
+		"repos":    reposDir,
+	})
+	return nil
+}
+
+// checkSandboxDatabase makes sure that sandbox mode only runs against a
+// database on the local machine, since stubbed integrations would otherwise
+// silently drop work for a shared deployment.
+func checkSandboxDatabase(dbURL string) error {
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		return errors.Wrap(err, "parsing database URL")
+	}
+	if u.Scheme != "mongodb" {
+		return errors.Errorf("sandbox mode requires a local database, but the database URL scheme is '%s'", u.Scheme)
+	}
+	for _, host := range strings.Split(u.Host, ",") {
+		hostname, _, err := net.SplitHostPort(host)
+		if err != nil {
+			hostname = host
+		}
+		if hostname != "localhost" && !isLoopback(hostname) {
+			return errors.Errorf("sandbox mode requires a local database, but the database host is '%s'", hostname)
+		}
+	}
+	return nil
+}
+
+func isLoopback(hostname string) bool {
+	ip := net.ParseIP(hostname)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,26 @@
+package operations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSandboxDatabase(t *testing.T) {
+	for _, url := range []string{
+		"mongodb://localhost:27017",
+		"mongodb://127.0.0.1:27017/?replicaSet=evg",
+		"mongodb://localhost",
+		"mongodb://[::1]:27017",
+	} {
+		assert.NoError(t, checkSandboxDatabase(url), url)
+	}
+	for _, url := range []string{
+		"mongodb://db.example.com:27017",
+		"mongodb://localhost:27017,db.example.com:27017",
+		"mongodb+srv://cluster.example.com",
+		"://",
+	} {
+		assert.Error(t, checkSandboxDatabase(url), url)
+	}
+}
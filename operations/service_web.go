@@ -29,7 +29,7 @@ func startWebService() cli.Command {
 	return cli.Command{
 		Name:  "web",
 		Usage: "start web services for API and UI",
-		Flags: mergeFlagSlices(serviceConfigFlags(), addDbSettingsFlags()),
+		Flags: mergeFlagSlices(serviceConfigFlags(), addDbSettingsFlags(), addSandboxFlags()),
 		Action: func(c *cli.Context) error {
 			confPath := c.String(confFlagName)
 			db := parseDB(c)
@@ -41,6 +41,10 @@ func startWebService() cli.Command {
 			if c.Bool(overwriteConfFlagName) {
 				grip.EmergencyFatal(errors.Wrap(env.SaveConfig(), "problem saving config"))
 			}
+
+			if c.Bool(sandboxFlagName) {
+				grip.EmergencyFatal(errors.Wrap(configureSandbox(env, c.String(sandboxReposFlagName)), "problem configuring sandbox mode"))
+			}
 			grip.EmergencyFatal(errors.Wrap(env.RemoteQueue().Start(ctx), "problem starting remote queue"))
 
 			settings := env.Settings()
@@ -132,6 +132,14 @@ func validateFile(path string, ac *legacyClient, quiet, includeLong bool, localM
 	if err != nil {
 		return errors.Wrapf(err, "Could not marshal parser project into yaml")
 	}
+	// Validation suppressions are only marshalled with the project config
+	// fields, including the ones from included files.
+	if len(pp.ValidationSuppressions) > 0 {
+		if pc == nil {
+			pc = &model.ProjectConfig{}
+		}
+		pc.ValidationSuppressions = pp.ValidationSuppressions
+	}
 
 	if pc != nil {
 		projectConfigYaml, err := yaml.Marshal(pc.ProjectConfigFields)
@@ -168,6 +176,7 @@ func loadProjectIntoWithValidation(ctx context.Context, data []byte, opts *model
 	if err != nil {
 		errs = append(errs, validator.ValidationError{
 			Level:   validator.Error,
+			Code:    validator.CodeProjectConfigInvalid,
 			Message: err.Error(),
 		})
 	}
@@ -181,6 +190,7 @@ func loadProjectIntoWithValidation(ctx context.Context, data []byte, opts *model
 			if err2 == nil {
 				errs = append(errs, validator.ValidationError{
 					Level:   validator.Warning,
+					Code:    validator.CodeProjectConfigNotStrict,
 					Message: fmt.Sprintf("error unmarshalling strictly: %s", err.Error()),
 				})
 				return pp, pc, errs
@@ -188,6 +198,7 @@ func loadProjectIntoWithValidation(ctx context.Context, data []byte, opts *model
 		}
 		errs = append(errs, validator.ValidationError{
 			Level:   validator.Error,
+			Code:    validator.CodeProjectConfigInvalid,
 			Message: err.Error(),
 		})
 	}
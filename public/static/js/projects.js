@@ -820,13 +820,14 @@ mciModule.controller(
     $scope.addContainerCredential = function () {
       if (!$scope.validContainerCredential($scope.container_credential)) {
           $scope.invalidContainerCredentialMessage =
-              "A valid container credential must have a valid username and password.";
+              "A valid container credential must have either a valid username and password or an external secret.";
           return;
       }
       var item = Object.assign({}, $scope.container_credential);
       $scope.settingsFormData.container_credentials[item.name] = {
           "username": item.username,
           "password": item.password,
+          "external_secret": item.external_secret,
       }
       delete $scope.container_credential;
       $scope.invalidContainerCredentialMessage = "";
@@ -1148,7 +1149,13 @@ mciModule.controller(
     };
 
     $scope.validContainerCredential = function (container_credential) {
-      return container_credential && container_credential.username && container_credential.password
+      if (!container_credential) {
+        return false;
+      }
+      if (container_credential.external_secret) {
+        return !container_credential.username && !container_credential.password;
+      }
+      return container_credential.username && container_credential.password
         && container_credential.username !== "" && container_credential.password !== "";
     };
 
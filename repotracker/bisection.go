@@ -0,0 +1,126 @@
+package repotracker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/google/go-github/v34/github"
+	"github.com/pkg/errors"
+)
+
+// maxBisectionRevisions is the most commits between a bisection's good and
+// bad mainline commits that are searched for commits without a version.
+const maxBisectionRevisions = 50
+
+// GetUnversionedRevisions returns the commits after the good commit and
+// before the bad commit that never got a mainline version, for example
+// because the repotracker skipped them, oldest first.
+func GetUnversionedRevisions(ctx context.Context, conf *evergreen.Settings, project model.ProjectRef, good, bad string) ([]string, error) {
+	token, err := conf.GetGithubOauthToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting GitHub token")
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// Commits are listed newest first, starting at the bad commit.
+	var between []string
+	var foundGood bool
+	var commitPage int
+	for !foundGood && len(between) <= maxBisectionRevisions {
+		var commits []*github.RepositoryCommit
+		commits, commitPage, err = thirdparty.GetGithubCommits(ctx, token, project.Owner, project.Repo, bad, time.Time{}, commitPage)
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing commits before '%s'", bad)
+		}
+		for _, commit := range commits {
+			if commit == nil || commit.SHA == nil {
+				return nil, errors.Errorf("GitHub returned commit history with missing information for project '%s'", project.Id)
+			}
+			if isLastRevision(good, commit) {
+				foundGood = true
+				break
+			}
+			if isLastRevision(bad, commit) {
+				continue
+			}
+			between = append(between, *commit.SHA)
+		}
+		if commitPage == 0 {
+			break
+		}
+	}
+	if !foundGood {
+		return nil, errors.Errorf("good commit '%s' is not within %d commits of bad commit '%s'", good, maxBisectionRevisions, bad)
+	}
+
+	unversioned := []string{}
+	for i := len(between) - 1; i >= 0; i-- {
+		v, err := model.VersionFindOne(model.BaseVersionByProjectIdAndRevision(project.Id, between[i]).WithFields(model.VersionIdKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding version for revision '%s'", between[i])
+		}
+		if v == nil {
+			unversioned = append(unversioned, between[i])
+		}
+	}
+	return unversioned, nil
+}
+
+// CreateBisectionVersion creates an ad hoc version in a commit that never got
+// a mainline version so that a bisection can run its task there. None of the
+// version's tasks are activated.
+func CreateBisectionVersion(ctx context.Context, conf *evergreen.Settings, project model.ProjectRef, revision string) (*model.Version, error) {
+	tracker, err := getTracker(conf, project)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting repotracker")
+	}
+	token, err := conf.GetGithubOauthToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting GitHub token")
+	}
+	commit, err := thirdparty.GetCommitEvent(ctx, token, project.Owner, project.Repo, revision)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting commit '%s'", revision)
+	}
+	if commit == nil || commit.Commit == nil || commit.Commit.Author == nil ||
+		commit.Commit.Author.Name == nil ||
+		commit.Commit.Author.Email == nil ||
+		commit.Commit.Message == nil ||
+		commit.SHA == nil ||
+		commit.Commit.Committer == nil ||
+		commit.Commit.Committer.Date == nil {
+		return nil, errors.Errorf("GitHub returned commit '%s' with missing information", revision)
+	}
+
+	pInfo, err := tracker.GetProjectConfig(ctx, revision)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting project config for revision '%s'", revision)
+	}
+	if pInfo.Project == nil {
+		return nil, errors.Errorf("project config for revision '%s' not found", revision)
+	}
+	projectInfo := &model.ProjectInfo{
+		Ref:                 &project,
+		Project:             pInfo.Project,
+		IntermediateProject: pInfo.IntermediateProject,
+		Config:              pInfo.Config,
+	}
+	metadata := model.VersionMetadata{
+		IsAdHoc:  true,
+		Revision: githubCommitToRevision(commit),
+		Message:  fmt.Sprintf("Automatic bisection of commit %s: %s", revision, *commit.Commit.Message),
+	}
+	v, err := CreateVersionFromConfig(ctx, projectInfo, metadata, false, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating version for revision '%s'", revision)
+	}
+	if len(v.Errors) > 0 {
+		return nil, errors.Errorf("project config for revision '%s' has errors", revision)
+	}
+	return v, nil
+}
@@ -27,8 +27,13 @@ import (
 	"github.com/mongodb/grip/sometimes"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/evergreen-ci/evergreen/repotracker")
+
 const (
 	// determines the default maximum number of revisions to fetch for a newly tracked repo
 	// if not specified in configuration file
@@ -217,7 +222,10 @@ func (repoTracker *RepoTracker) FetchRevisions(ctx context.Context) error {
 // The return value is the most recent version created as a result of storing the revisions.
 // This function is idempotent with regard to storing the same version multiple times.
 func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []model.Revision) error {
-	var newestVersion *model.Version
+	// lastRevision is the newest revision that was processed, including
+	// revisions that were skipped because they didn't touch any of the
+	// project's watched paths.
+	var lastRevision string
 	ref := repoTracker.ProjectRef
 	for i := len(revisions) - 1; i >= 0; i-- {
 		revision := revisions[i].Revision
@@ -241,9 +249,9 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 				"project_identifier": ref.Identifier,
 				"revision":           revision,
 			})
-			// We bind newestVersion here since we still need to return the most recent
-			// version, even if it already exists
-			newestVersion = existingVersion
+			// We bind lastRevision here since we still need to record the most recent
+			// revision, even if its version already exists
+			lastRevision = existingVersion.Revision
 			continue
 		}
 
@@ -278,7 +286,7 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 						"project_identifier": ref.Identifier,
 						"revision":           revision,
 					}))
-					newestVersion = stubVersion
+					lastRevision = stubVersion.Revision
 					continue
 				}
 			} else {
@@ -301,9 +309,11 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 			return err
 		}
 
-		// "Ignore" a version if all changes are to ignored files
+		// "Ignore" a version if all changes are to ignored files or if no
+		// changes are to watched paths.
 		var ignore bool
-		if len(pInfo.Project.Ignore) > 0 {
+		var matchedPaths []string
+		if len(pInfo.Project.Ignore) > 0 || len(ref.WatchedPaths) > 0 {
 			var filenames []string
 			filenames, err = repoTracker.GetChangedFiles(ctx, revision)
 			if err != nil {
@@ -319,10 +329,28 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 			if pInfo.Project.IgnoresAllFiles(filenames) {
 				ignore = true
 			}
+			if len(ref.WatchedPaths) > 0 {
+				matchedPaths = ref.MatchWatchedPaths(filenames)
+				if len(matchedPaths) == 0 {
+					if ref.GetWatchedPathsPolicy() == model.WatchedPathsPolicyCreate {
+						grip.Info(message.Fields{
+							"message":            "skipping creating version because no changes are to watched paths",
+							"runner":             RunnerName,
+							"project":            ref.Id,
+							"project_identifier": ref.Identifier,
+							"revision":           revision,
+						})
+						lastRevision = revision
+						continue
+					}
+					ignore = true
+				}
+			}
 		}
 
 		metadata := model.VersionMetadata{
-			Revision: revisions[i],
+			Revision:     revisions[i],
+			MatchedPaths: model.TruncateMatchedPaths(matchedPaths),
 		}
 		projectInfo := &model.ProjectInfo{
 			Ref:                 ref,
@@ -352,7 +380,7 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 			continue
 		}
 		if ref.IsGithubChecksEnabled() {
-			if err = addGithubCheckSubscriptions(v); err != nil {
+			if err = AddGithubCheckSubscriptions(v, ref); err != nil {
 				grip.Error(message.WrapError(err, message.Fields{
 					"message":            "error adding github check subscriptions",
 					"runner":             RunnerName,
@@ -362,6 +390,20 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 				}))
 			}
 		}
+		if v.WarningBudget != nil && v.WarningBudget.Exceeded() {
+			grip.Info(message.Fields{
+				"message":            "version exceeded project config warning budget",
+				"runner":             RunnerName,
+				"project":            ref.Id,
+				"project_identifier": ref.Identifier,
+				"revision":           revision,
+				"version":            v.Id,
+				"num_warnings":       v.WarningBudget.NumWarnings,
+				"max_warnings":       v.WarningBudget.MaxWarnings,
+				"delta":              v.WarningBudget.Delta(),
+			})
+			event.LogVersionWarningBudgetExceededEvent(v.Id)
+		}
 
 		_, err = CreateManifest(*v, pInfo.Project, ref, repoTracker.Settings)
 		if err != nil {
@@ -375,10 +417,10 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 			continue
 		}
 
-		newestVersion = v
+		lastRevision = v.Revision
 	}
-	if newestVersion != nil {
-		err := model.UpdateLastRevision(newestVersion.Identifier, newestVersion.Revision)
+	if lastRevision != "" {
+		err := model.UpdateLastRevision(ref.Id, lastRevision)
 		if err != nil {
 			grip.Error(message.WrapError(err, message.Fields{
 				"message":            "problem updating last revision for repository",
@@ -459,8 +501,10 @@ func (repoTracker *RepoTracker) GetProjectConfig(ctx context.Context, revision s
 	return projectInfo, nil
 }
 
-// addGithubCheckSubscriptions adds subscriptions to send the status of the version to Github.
-func addGithubCheckSubscriptions(v *model.Version) error {
+// AddGithubCheckSubscriptions adds subscriptions to send the status of the version to Github.
+// If the project posts a check for each variant as its status changes, the variant checks
+// are posted separately rather than by subscription.
+func AddGithubCheckSubscriptions(v *model.Version, ref *model.ProjectRef) error {
 	catcher := grip.NewBasicCatcher()
 	ghSub := event.NewGithubCheckAPISubscriber(event.GithubCheckSubscriber{
 		Owner: v.Owner,
@@ -472,9 +516,17 @@ func addGithubCheckSubscriptions(v *model.Version) error {
 	if err := versionSub.Upsert(); err != nil {
 		catcher.Wrap(err, "failed to insert version github check subscription")
 	}
-	buildSub := event.NewGithubCheckBuildOutcomeSubscriptionByVersion(v.Id, ghSub)
-	if err := buildSub.Upsert(); err != nil {
-		catcher.Wrap(err, "failed to insert build github check subscription")
+	if !ref.GithubVariantChecks.Enabled {
+		buildSub := event.NewGithubCheckBuildOutcomeSubscriptionByVersion(v.Id, ghSub)
+		if err := buildSub.Upsert(); err != nil {
+			catcher.Wrap(err, "failed to insert build github check subscription")
+		}
+	}
+	if v.WarningBudget != nil {
+		warningSub := event.NewVersionWarningBudgetSubscription(v.Id, ghSub)
+		if err := warningSub.Upsert(); err != nil {
+			catcher.Wrap(err, "failed to insert warning budget github check subscription")
+		}
 	}
 	flags, err := evergreen.GetServiceFlags()
 	if err != nil {
@@ -524,6 +576,10 @@ func addGithubCheckSubscriptions(v *model.Version) error {
 		catcher.Add(err)
 		return catcher.Resolve()
 	}
+	if err = thirdparty.ReserveGithubStatus(thirdparty.GithubPriorityNormal); err != nil {
+		catcher.Wrap(err, "reserving GitHub budget for version status")
+		return catcher.Resolve()
+	}
 
 	sender.Send(c)
 	return catcher.Resolve()
@@ -708,6 +764,10 @@ func CreateVersionFromConfig(ctx context.Context, projectInfo *model.ProjectInfo
 	if projectInfo.NotPopulated() {
 		return nil, errors.New("project ref and parser project cannot be nil")
 	}
+	ctx, span := tracer.Start(ctx, "CreateVersionFromConfig", trace.WithAttributes(
+		attribute.String(evergreen.ProjectIdentifierOtelAttribute, projectInfo.Ref.Identifier),
+	))
+	defer span.End()
 
 	// create a version document
 	v, err := ShellVersionFromRevision(ctx, projectInfo.Ref, metadata)
@@ -718,8 +778,11 @@ func CreateVersionFromConfig(ctx context.Context, projectInfo *model.ProjectInfo
 		return nil, errors.Wrap(err, "inconsistent version order")
 	}
 
+	span.SetAttributes(attribute.String(evergreen.VersionIDOtelAttribute, v.Id))
 	if projectInfo.Project == nil {
+		_, expandSpan := tracer.Start(ctx, "expand")
 		projectInfo.Project, err = model.TranslateProject(projectInfo.IntermediateProject)
+		expandSpan.End()
 		if err != nil {
 			return nil, errors.Wrap(err, "error translating intermediate project")
 		}
@@ -731,13 +794,21 @@ func CreateVersionFromConfig(ctx context.Context, projectInfo *model.ProjectInfo
 	}
 	v.Ignored = ignore
 	v.Activated = utility.FalsePtr()
+	v.ExternalGates = projectInfo.Project.NewVersionExternalGates(time.Now())
+	v.Stages = projectInfo.Project.NewVersionStages()
 
 	// validate the project
 	isConfigDefined := projectInfo.Config != nil
-	verrs := validator.CheckProjectErrors(projectInfo.Project, true)
-	verrs = append(verrs, validator.CheckProjectSettings(projectInfo.Project, projectInfo.Ref, isConfigDefined)...)
-	verrs = append(verrs, validator.CheckProjectConfigErrors(projectInfo.Config)...)
-	verrs = append(verrs, validator.CheckProjectWarnings(projectInfo.Project)...)
+	validateCtx, validateSpan := tracer.Start(ctx, "validate")
+	verrs := validator.CheckProjectErrors(validateCtx, projectInfo.Project, projectInfo.Ref, true)
+	verrs = append(verrs, validator.CheckProjectSettings(validateCtx, projectInfo.Project, projectInfo.Ref, isConfigDefined)...)
+	verrs = append(verrs, validator.CheckProjectConfigErrors(validateCtx, projectInfo.Config)...)
+	verrs = append(verrs, validator.CheckProjectWarnings(validateCtx, projectInfo.Project, projectInfo.Ref)...)
+	validateSpan.SetAttributes(
+		attribute.Int(evergreen.ValidationNumErrorsOtelAttribute, len(verrs.AtLevel(validator.Error))),
+		attribute.Int(evergreen.ValidationNumWarningsOtelAttribute, len(verrs.AtLevel(validator.Warning))),
+	)
+	validateSpan.End()
 	if len(verrs) > 0 || versionErrs != nil {
 		// We have errors in the project.
 		// Format them, as we need to store + display them to the user
@@ -768,6 +839,12 @@ func CreateVersionFromConfig(ctx context.Context, projectInfo *model.ProjectInfo
 
 		}
 	}
+	if projectInfo.Ref.MaxValidationWarnings != nil && v.Requester == evergreen.RepotrackerVersionRequester {
+		v.WarningBudget, err = model.NewVersionWarningBudget(v, utility.FromIntPtr(projectInfo.Ref.MaxValidationWarnings))
+		if err != nil {
+			return nil, errors.Wrap(err, "checking warning budget")
+		}
+	}
 	var aliases model.ProjectAliases
 	if metadata.Alias == evergreen.GitTagAlias {
 		aliases, err = model.FindMatchingGitTagAliasesInProject(projectInfo.Ref.Id, metadata.GitTag.Tag)
@@ -826,10 +903,16 @@ func ShellVersionFromRevision(ctx context.Context, ref *model.ProjectRef, metada
 		TriggerType:         metadata.TriggerType,
 		TriggerEvent:        metadata.EventID,
 		PeriodicBuildID:     metadata.PeriodicBuildID,
+		Labels:              metadata.Labels,
+		ExpansionOverrides:  metadata.ExpansionOverrides,
+		MatchedPaths:        metadata.MatchedPaths,
+		Parameters:          metadata.Parameters,
 	}
 	if metadata.TriggerType != "" {
 		v.Id = util.CleanName(fmt.Sprintf("%s_%s_%s", ref.Identifier, metadata.SourceVersion.Revision, metadata.TriggerDefinitionID))
 		v.Requester = evergreen.TriggerRequester
+		v.UpstreamVersionID = metadata.SourceVersion.Id
+		v.TriggerDepth = metadata.SourceVersion.GetTriggerDepth() + 1
 		v.CreateTime = metadata.SourceVersion.CreateTime
 	} else if metadata.IsAdHoc {
 		v.Id = mgobson.NewObjectId().Hex()
@@ -870,6 +953,14 @@ func ShellVersionFromRevision(ctx context.Context, ref *model.ProjectRef, metada
 		if metadata.RemotePath != "" {
 			v.RemotePath = metadata.RemotePath
 		}
+	} else if metadata.GithubMergeGroup.HeadSHA != "" {
+		if !ref.IsGithubMergeQueueEnabled() {
+			return nil, errors.Errorf("GitHub merge queue is not enabled for project '%s'", ref.Id)
+		}
+		v.Id = model.GithubMergeGroupVersionID(ref.Identifier, metadata.GithubMergeGroup.HeadSHA)
+		v.Requester = evergreen.GithubMergeQueueRequester
+		v.CreateTime = time.Now()
+		v.Message = fmt.Sprintf("Triggered From GitHub Merge Queue '%s': %s", metadata.GithubMergeGroup.HeadRef, v.Message)
 	} else {
 		v.Id = makeVersionId(ref.Identifier, metadata.Revision.Revision)
 	}
@@ -989,6 +1080,7 @@ func createVersionItems(ctx context.Context, v *model.Version, metadata model.Ve
 			"version": v.Id,
 		}))
 	}
+	queueLengths := model.NewRunOnQueueLengths()
 	for _, buildvariant := range projectInfo.Project.BuildVariants {
 		taskNames := pairsToCreate.TaskNames(buildvariant.Name)
 		var aliasesMatchingVariant model.ProjectAliases
@@ -1012,6 +1104,7 @@ func createVersionItems(ctx context.Context, v *model.Version, metadata model.Ve
 			DefinitionID:        metadata.TriggerDefinitionID,
 			Aliases:             aliases,
 			DistroAliases:       distroAliases,
+			RunOnQueueLengths:   queueLengths,
 			TaskCreateTime:      v.CreateTime,
 			GithubChecksAliases: aliasesMatchingVariant,
 		}
@@ -1031,6 +1124,11 @@ func createVersionItems(ctx context.Context, v *model.Version, metadata model.Ve
 			debuggingData[buildvariant.Name] = "no tasks for buildvariant"
 			continue
 		}
+		considerBatchTime := metadata.TriggerID == "" && evergreen.ShouldConsiderBatchtime(v.Requester)
+		if considerBatchTime {
+			b.CanarySample, err = buildvariant.SampleCanary(v)
+			batchTimeCatcher.Add(err)
+		}
 		buildsToCreate = append(buildsToCreate, *b)
 		taskNameToId := map[string]string{}
 		for _, t := range tasks {
@@ -1040,9 +1138,16 @@ func createVersionItems(ctx context.Context, v *model.Version, metadata model.Ve
 
 		activateVariantAt := time.Now()
 		taskStatuses := []model.BatchTimeTaskStatus{}
-		if metadata.TriggerID == "" && evergreen.ShouldConsiderBatchtime(v.Requester) {
-			activateVariantAt, err = projectInfo.Ref.GetActivationTimeForVariant(&buildvariant)
-			batchTimeCatcher.Add(errors.Wrapf(err, "unable to get activation time for variant '%s'", buildvariant.Name))
+		if considerBatchTime {
+			canarySkipped := b.CanarySample != nil && !b.CanarySample.Sampled
+			if canarySkipped {
+				// Versions outside of the canary sample don't activate the
+				// variant or any of its tasks.
+				activateVariantAt = utility.ZeroTime
+			} else {
+				activateVariantAt, err = projectInfo.Ref.GetActivationTimeForVariant(&buildvariant)
+				batchTimeCatcher.Add(errors.Wrapf(err, "unable to get activation time for variant '%s'", buildvariant.Name))
+			}
 			// add only tasks that require activation times
 			for _, bvt := range buildvariant.Tasks {
 				tId, ok := taskNameToId[bvt.Name]
@@ -1050,8 +1155,11 @@ func createVersionItems(ctx context.Context, v *model.Version, metadata model.Ve
 					continue
 				}
 				bvt.Variant = buildvariant.Name
-				activateTaskAt, err := projectInfo.Ref.GetActivationTimeForTask(&bvt)
-				batchTimeCatcher.Add(errors.Wrapf(err, "unable to get activation time for task '%s' (variant '%s')", bvt.Name, buildvariant.Name))
+				activateTaskAt := utility.ZeroTime
+				if !canarySkipped {
+					activateTaskAt, err = projectInfo.Ref.GetActivationTimeForTask(&bvt)
+					batchTimeCatcher.Add(errors.Wrapf(err, "unable to get activation time for task '%s' (variant '%s')", bvt.Name, buildvariant.Name))
+				}
 
 				taskStatuses = append(taskStatuses,
 					model.BatchTimeTaskStatus{
@@ -1105,6 +1213,10 @@ func createVersionItems(ctx context.Context, v *model.Version, metadata model.Ve
 		}
 		return errors.Errorf("version '%s' in project '%s' using alias '%s' has no variants", v.Id, projectInfo.Ref.Identifier, aliasString)
 	}
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int(evergreen.VersionNumBuildsOtelAttribute, len(buildsToCreate)),
+		attribute.Int(evergreen.VersionNumTasksOtelAttribute, len(tasksToCreate)),
+	)
 	grip.Error(message.WrapError(batchTimeCatcher.Resolve(), message.Fields{
 		"message": "unable to get all activation times",
 		"runner":  RunnerName,
@@ -1203,6 +1315,8 @@ func createVersionItems(ctx context.Context, v *model.Version, metadata model.Ve
 		return nil
 	}
 
+	ctx, persistSpan := tracer.Start(ctx, "persist")
+	defer persistSpan.End()
 	return transactionWithRetries(ctx, v.Id, txFunc)
 }
 
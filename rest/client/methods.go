@@ -704,6 +704,35 @@ func (c *communicatorImpl) ExecuteOnDistro(ctx context.Context, distro string, o
 	return result.HostIDs, nil
 }
 
+func (c *communicatorImpl) RepairCommitQueue(ctx context.Context, projectID string, dryRun bool) (*model.APICommitQueueRepairResult, error) {
+	info := requestInfo{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/admin/commit_queues/%s/repair", projectID),
+	}
+	body := struct {
+		DryRun bool `json:"dry_run"`
+	}{DryRun: dryRun}
+
+	resp, err := c.request(ctx, info, body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "sending request to repair commit queue for project '%s'", projectID)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, utility.RespErrorf(resp, AuthError)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, utility.RespErrorf(resp, "repairing commit queue for project '%s'", projectID)
+	}
+
+	result := &model.APICommitQueueRepairResult{}
+	if err = utility.ReadJSON(resp.Body, result); err != nil {
+		return nil, errors.Wrap(err, "reading JSON response body")
+	}
+	return result, nil
+}
+
 func (c *communicatorImpl) GetServiceUsers(ctx context.Context) ([]model.APIDBUser, error) {
 	info := requestInfo{
 		method: http.MethodGet,
@@ -161,6 +161,9 @@ func (c *Mock) RevertSettings(ctx context.Context, guid string) error { return n
 func (c *Mock) ExecuteOnDistro(context.Context, string, model.APIDistroScriptOptions) ([]string, error) {
 	return nil, nil
 }
+func (c *Mock) RepairCommitQueue(context.Context, string, bool) (*model.APICommitQueueRepairResult, error) {
+	return nil, nil
+}
 
 func (c *Mock) GetDistrosList(ctx context.Context) ([]model.APIDistro, error) {
 	mockDistros := []model.APIDistro{
@@ -164,6 +164,9 @@ func validateFeaturesHaveAliases(pRef *model.ProjectRef, aliases []restModel.API
 	if pRef.CommitQueue.IsEnabled() && !aliasesMap[evergreen.CommitQueueAlias] {
 		catcher.Errorf(msg, "Commit queue")
 	}
+	if pRef.IsGithubMergeQueueEnabled() && !aliasesMap[evergreen.CommitQueueAlias] {
+		catcher.Errorf(msg, "GitHub merge queue")
+	}
 	if pRef.IsGitTagVersionsEnabled() && !aliasesMap[evergreen.GitTagAlias] {
 		catcher.Errorf(msg, "Git tag versions")
 	}
@@ -72,11 +72,14 @@ func (pc *DBCommitQueueConnector) AddPatchForPr(ctx context.Context, projectRef
 	if err != nil {
 		return "", err
 	}
+	if err = projectRef.PatchPolicy.Check(projectRef.Id, p, patchDoc.Githash); err != nil {
+		return "", errors.Wrap(err, "checking patch policy")
+	}
 
-	errs := validator.CheckProjectErrors(projectConfig, false)
+	errs := validator.CheckProjectErrors(ctx, projectConfig, &projectRef, false)
 	isConfigDefined := projectConfig != nil
-	errs = append(errs, validator.CheckProjectSettings(projectConfig, &projectRef, isConfigDefined)...)
-	errs = append(errs, validator.CheckPatchedProjectConfigErrors(patchDoc.PatchedProjectConfig)...)
+	errs = append(errs, validator.CheckProjectSettings(ctx, projectConfig, &projectRef, isConfigDefined)...)
+	errs = append(errs, validator.CheckPatchedProjectConfigErrors(ctx, patchDoc.PatchedProjectConfig)...)
 	catcher := grip.NewBasicCatcher()
 	for _, validationErr := range errs.AtLevel(validator.Error) {
 		catcher.Add(validationErr)
@@ -117,7 +120,7 @@ func (pc *DBCommitQueueConnector) AddPatchForPr(ctx context.Context, projectRef
 	// populate tasks/variants matching the commitqueue alias
 	projectConfig.BuildProjectTVPairs(patchDoc, patchDoc.Alias)
 
-	if err = units.AddMergeTaskAndVariant(patchDoc, projectConfig, &projectRef, commitqueue.SourcePullRequest); err != nil {
+	if err = units.AddMergeTaskAndVariant(ctx, patchDoc, projectConfig, &projectRef, commitqueue.SourcePullRequest); err != nil {
 		return "", err
 	}
 
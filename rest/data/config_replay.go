@@ -0,0 +1,110 @@
+package data
+
+import (
+	"context"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/validator"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ReplayVersionConfig validates the version's project config against the
+// current validation rules, and reports the rules that the config now fails.
+// If includeSettings is set, the config is also checked against the project's
+// current settings, which queries the database. If includeConfig is set, the
+// version's parser project is also returned as YAML.
+func ReplayVersionConfig(ctx context.Context, v *model.Version, pRef *model.ProjectRef, includeConfig, includeSettings bool) (*restModel.APIConfigReplay, error) {
+	replay := &restModel.APIConfigReplay{}
+	replay.BuildFromService(*v)
+
+	projectInfo, err := model.LoadProjectForVersion(v, pRef.Id, false)
+	if err != nil {
+		// A config that can't be loaded anymore is itself breakage.
+		replay.LoadError = utility.ToStringPtr(err.Error())
+		return replay, nil
+	}
+	if includeSettings {
+		projectInfo.Ref = pRef
+	}
+	if includeConfig && projectInfo.IntermediateProject != nil {
+		config, err := yaml.Marshal(projectInfo.IntermediateProject)
+		if err != nil {
+			return nil, errors.Wrapf(err, "marshalling parser project for version '%s'", v.Id)
+		}
+		replay.ParserProject = utility.ToStringPtr(string(config))
+	}
+
+	for _, failure := range validator.ReplayProjectValidation(ctx, projectInfo, false) {
+		apiFailure := restModel.APIRuleFailure{
+			Rule:     utility.ToStringPtr(failure.Rule),
+			Errors:   []string{},
+			Warnings: []string{},
+		}
+		for _, validationErr := range failure.Errors {
+			if validationErr.Level == validator.Error {
+				apiFailure.Errors = append(apiFailure.Errors, validationErr.Message)
+			} else {
+				apiFailure.Warnings = append(apiFailure.Warnings, validationErr.Message)
+			}
+		}
+		replay.Failures = append(replay.Failures, apiFailure)
+	}
+	return replay, nil
+}
+
+// ReplayProjectConfigs validates the project configs of the project's most
+// recent mainline versions against the current validation rules, and reports
+// how many of the versions fail each rule. The rules that check a config
+// against the project's settings are skipped, since they query the database
+// for every version replayed.
+func ReplayProjectConfigs(ctx context.Context, pRef *model.ProjectRef, limit int) (*restModel.APIProjectConfigReplay, error) {
+	versions, err := model.VersionFind(model.VersionsByRequesterOrdered(pRef.Id, evergreen.RepotrackerVersionRequester, limit, 0))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding recent versions for project '%s'", pRef.Identifier)
+	}
+
+	result := &restModel.APIProjectConfigReplay{
+		Versions: make([]restModel.APIConfigReplay, 0, len(versions)),
+		Rules:    []restModel.APIRuleBreakage{},
+	}
+	breakageByRule := map[string]*restModel.APIRuleBreakage{}
+	for i := range versions {
+		replay, err := ReplayVersionConfig(ctx, &versions[i], pRef, false, false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "replaying project config for version '%s'", versions[i].Id)
+		}
+		result.Versions = append(result.Versions, *replay)
+		if replay.LoadError != nil {
+			result.NumVersionsUnloadable++
+		}
+
+		for _, failure := range replay.Failures {
+			rule := utility.FromStringPtr(failure.Rule)
+			breakage, ok := breakageByRule[rule]
+			if !ok {
+				breakage = &restModel.APIRuleBreakage{Rule: failure.Rule}
+				breakageByRule[rule] = breakage
+			}
+			if len(failure.Errors) > 0 {
+				breakage.NumVersionsErrored++
+			}
+			if len(failure.Warnings) > 0 {
+				breakage.NumVersionsWarned++
+			}
+		}
+	}
+
+	for _, breakage := range breakageByRule {
+		breakage.NumVersionsReplayed = len(versions)
+		result.Rules = append(result.Rules, *breakage)
+	}
+	sort.Slice(result.Rules, func(i, j int) bool {
+		return utility.FromStringPtr(result.Rules[i].Rule) < utility.FromStringPtr(result.Rules[j].Rule)
+	})
+	return result, nil
+}
@@ -192,17 +192,17 @@ func makeProjectAndExpansionsFromTask(t *task.Task) (*model.Project, *util.Expan
 		projectInfo.Project = &model.Project{}
 	}
 	params := append(projectInfo.Project.GetParameters(), v.Parameters...)
-	if err = updateExpansions(&expansions, t.Project, params); err != nil {
+	if err = updateExpansions(&expansions, t, params); err != nil {
 		return nil, nil, errors.Wrap(err, "updating expansions")
 	}
 
 	return projectInfo.Project, &expansions, nil
 }
 
-// updateExpansions updates expansions with project variables and patch
-// parameters.
-func updateExpansions(expansions *util.Expansions, projectId string, params []patch.Parameter) error {
-	projVars, err := model.FindMergedProjectVars(projectId)
+// updateExpansions updates expansions with the project variables that the task
+// can access and patch parameters.
+func updateExpansions(expansions *util.Expansions, t *task.Task, params []patch.Parameter) error {
+	projVars, err := model.FindMergedProjectVars(t.Project)
 	if err != nil {
 		return errors.Wrap(err, "finding project variables")
 	}
@@ -210,7 +210,7 @@ func updateExpansions(expansions *util.Expansions, projectId string, params []pa
 		return errors.New("project variables not found")
 	}
 
-	expansions.Update(projVars.Vars)
+	expansions.Update(projVars.GetVars(t))
 
 	for _, param := range params {
 		expansions.Put(param.Key, param.Value)
@@ -13,8 +13,10 @@ import (
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -312,6 +314,38 @@ buildvariants:
 	})
 }
 
+func TestUpdateExpansions(t *testing.T) {
+	require.NoError(t, db.ClearCollections(model.ProjectRefCollection, model.ProjectVarsCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(model.ProjectRefCollection, model.ProjectVarsCollection))
+	}()
+	pRef := model.ProjectRef{Id: "p"}
+	require.NoError(t, pRef.Insert())
+	pvars := model.ProjectVars{
+		Id:   "p",
+		Vars: map[string]string{"open": "o", "restricted": "r"},
+		RestrictedVars: map[string]model.ProjectVarRestriction{
+			"restricted": {TaskRegex: "^allowed$"},
+		},
+	}
+	require.NoError(t, pvars.Insert())
+	params := []patch.Parameter{{Key: "param", Value: "v"}}
+
+	t.Run("ExcludesVariablesRestrictedFromTask", func(t *testing.T) {
+		expansions := util.Expansions{}
+		require.NoError(t, updateExpansions(&expansions, &task.Task{Project: "p", DisplayName: "other", BuildVariant: "bv"}, params))
+		assert.Equal(t, "o", expansions.Get("open"))
+		assert.False(t, expansions.Exists("restricted"))
+		assert.Equal(t, "v", expansions.Get("param"))
+	})
+	t.Run("IncludesVariablesRestrictedToTask", func(t *testing.T) {
+		expansions := util.Expansions{}
+		require.NoError(t, updateExpansions(&expansions, &task.Task{Project: "p", DisplayName: "allowed", BuildVariant: "bv"}, params))
+		assert.Equal(t, "o", expansions.Get("open"))
+		assert.Equal(t, "r", expansions.Get("restricted"))
+	})
+}
+
 func TestCreateContainerFromTask(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -0,0 +1,193 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/units"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// GetModuleImpact previews the child patches of the patch that would run in
+// the projects using the patch's project as a module, with the patch applied
+// to the module. Projects that the user can't view are left out, unless they
+// were asked for by name. If opts.Run is set, the child patches are also
+// created in the projects that the user can submit patches to, and finalized
+// right away if the patch has already been finalized.
+func GetModuleImpact(ctx context.Context, env evergreen.Environment, u gimlet.User, p *patch.Patch, pRef *model.ProjectRef, opts restModel.APIModuleImpactOptions) (*restModel.APIModuleImpact, error) {
+	specifiers := opts.ToService()
+	if len(specifiers) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify the tasks to run in each project using the module",
+		}
+	}
+
+	dependents, err := model.FindModuleDependents(pRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding projects using project '%s' as a module", pRef.Identifier)
+	}
+	dependents, err = filterModuleDependents(dependents, opts.ChildProjects)
+	if err != nil {
+		return nil, err
+	}
+
+	impact := &restModel.APIModuleImpact{
+		PatchID:  utility.ToStringPtr(p.Id.Hex()),
+		Preview:  !opts.Run,
+		Projects: make([]restModel.APIModuleImpactProject, 0, len(dependents)),
+	}
+	definitions := []patch.PatchTriggerDefinition{}
+	for _, dependent := range dependents {
+		if !canViewTasks(u, dependent.ProjectID) {
+			if len(opts.ChildProjects) == 0 {
+				continue
+			}
+			impactedProject := restModel.APIModuleImpactProject{}
+			impactedProject.BuildFromService(dependent, nil)
+			impactedProject.Error = utility.ToStringPtr("insufficient permissions to view project")
+			impact.Projects = append(impact.Projects, impactedProject)
+			continue
+		}
+		definition, variantsTasks, err := getModuleImpactForProject(pRef, dependent, specifiers, p.GetRequester())
+		impactedProject := restModel.APIModuleImpactProject{}
+		impactedProject.BuildFromService(dependent, variantsTasks)
+		if err == nil && opts.Run && !canSubmitPatch(u, dependent.ProjectID) {
+			err = errors.New("insufficient permissions to submit patches to project")
+		}
+		if err != nil {
+			impactedProject.Error = utility.ToStringPtr(err.Error())
+		} else if len(variantsTasks) > 0 {
+			definitions = append(definitions, definition)
+		}
+		impact.Projects = append(impact.Projects, impactedProject)
+	}
+	if !opts.Run || len(definitions) == 0 {
+		return impact, nil
+	}
+
+	childPatchIDs, err := units.ProcessTriggerDefinitions(ctx, p, env, definitions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating child patches of patch '%s'", p.Id.Hex())
+	}
+	childPatchIDsByProject := map[string]string{}
+	for _, childPatchID := range childPatchIDs {
+		childPatch, err := finalizeModuleImpactChildPatch(ctx, env, p, childPatchID)
+		if err != nil {
+			return nil, err
+		}
+		if childPatch != nil {
+			childPatchIDsByProject[childPatch.Project] = childPatchID
+		}
+	}
+	for i, impactedProject := range impact.Projects {
+		if childPatchID, ok := childPatchIDsByProject[utility.FromStringPtr(impactedProject.ProjectID)]; ok {
+			impact.Projects[i].ChildPatchID = utility.ToStringPtr(childPatchID)
+		}
+	}
+	return impact, nil
+}
+
+func canViewTasks(u gimlet.User, projectID string) bool {
+	return u.HasPermission(gimlet.PermissionOpts{
+		Resource:      projectID,
+		ResourceType:  evergreen.ProjectResourceType,
+		Permission:    evergreen.PermissionTasks,
+		RequiredLevel: evergreen.TasksView.Value,
+	})
+}
+
+func canSubmitPatch(u gimlet.User, projectID string) bool {
+	return u.HasPermission(gimlet.PermissionOpts{
+		Resource:      projectID,
+		ResourceType:  evergreen.ProjectResourceType,
+		Permission:    evergreen.PermissionPatches,
+		RequiredLevel: evergreen.PatchSubmit.Value,
+	})
+}
+
+// filterModuleDependents returns the dependents that are in the given
+// projects, or all of them if no projects are given.
+func filterModuleDependents(dependents []model.ModuleDependent, projects []string) ([]model.ModuleDependent, error) {
+	if len(projects) == 0 {
+		return dependents, nil
+	}
+	filtered := []model.ModuleDependent{}
+	for _, project := range projects {
+		var found bool
+		for _, dependent := range dependents {
+			if project == dependent.ProjectID || project == dependent.Identifier {
+				filtered = append(filtered, dependent)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("project '%s' does not use this project as a module", project),
+			}
+		}
+	}
+	return filtered, nil
+}
+
+// getModuleImpactForProject returns the patch trigger definition that
+// creates a child patch in the project using the module, along with the tasks
+// the child patch would run.
+func getModuleImpactForProject(pRef *model.ProjectRef, dependent model.ModuleDependent, specifiers []patch.TaskSpecifier, requester string) (patch.PatchTriggerDefinition, []patch.VariantTasks, error) {
+	definition, err := model.ValidateTriggerDefinition(patch.PatchTriggerDefinition{
+		ChildProject:   dependent.ProjectID,
+		TaskSpecifiers: specifiers,
+		ParentAsModule: dependent.ModuleName,
+	}, pRef.Id)
+	if err != nil {
+		return definition, nil, errors.Wrap(err, "invalid tasks for project")
+	}
+	_, project, err := model.FindLatestVersionWithValidProject(dependent.ProjectID)
+	if err != nil {
+		return definition, nil, errors.Wrap(err, "finding most recent valid config")
+	}
+	variantsTasks, err := project.VariantTasksForSelectors([]patch.PatchTriggerDefinition{definition}, requester)
+	if err != nil {
+		return definition, nil, errors.Wrap(err, "matching tasks")
+	}
+	return definition, variantsTasks, nil
+}
+
+// finalizeModuleImpactChildPatch finalizes the child patch if its parent has
+// already been finalized, since otherwise the child patch is finalized along
+// with its parent. It returns nil if the child patch wasn't created.
+func finalizeModuleImpactChildPatch(ctx context.Context, env evergreen.Environment, parent *patch.Patch, childPatchID string) (*patch.Patch, error) {
+	childPatch, err := patch.FindOneId(childPatchID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding child patch '%s'", childPatchID)
+	}
+	if childPatch == nil || parent.Version == "" {
+		return childPatch, nil
+	}
+
+	token, err := env.Settings().GetGithubOauthToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting GitHub OAuth token from admin settings")
+	}
+	if _, err = model.FinalizePatch(ctx, childPatch, parent.GetRequester(), token); err != nil {
+		return nil, errors.Wrapf(err, "finalizing child patch '%s'", childPatchID)
+	}
+	grip.Info(message.Fields{
+		"message":      "finalized module impact child patch",
+		"patch_id":     childPatchID,
+		"parent_patch": parent.Id.Hex(),
+		"project":      childPatch.Project,
+	})
+	return childPatch, nil
+}
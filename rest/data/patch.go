@@ -99,7 +99,8 @@ func AbortPatch(patchId string, user string) error {
 			Message:    fmt.Sprintf("patch '%s' not found", patchId),
 		}
 	}
-	return model.CancelPatch(p, task.AbortInfo{User: user})
+	_, err = model.CancelPatch(p, task.AbortInfo{User: user}, false)
+	return err
 }
 
 // SetPatchActivated attempts to activate the patch and create a new version (if activated is set to true)
@@ -108,6 +109,11 @@ func SetPatchActivated(ctx context.Context, patchId string, user string, activat
 	if err != nil {
 		return err
 	}
+	if activated && p.Version != "" {
+		if err = checkPatchActivationQuotas(p, user); err != nil {
+			return err
+		}
+	}
 	err = p.SetActivation(activated)
 	if err != nil {
 		return err
@@ -146,6 +152,30 @@ func SetPatchActivated(ctx context.Context, patchId string, user string, activat
 	return model.SetVersionActivation(patchId, activated, user)
 }
 
+// checkPatchActivationQuotas returns an error if activating the patch's
+// version would put its project over its quotas.
+func checkPatchActivationQuotas(p *patch.Patch, user string) error {
+	v, err := model.VersionFindOneId(p.Version)
+	if err != nil {
+		return errors.Wrapf(err, "finding version '%s'", p.Version)
+	}
+	if v == nil {
+		return errors.Errorf("version '%s' not found", p.Version)
+	}
+	projectRef, err := model.FindMergedProjectRef(p.Project, p.Version, false)
+	if err != nil {
+		return errors.Wrapf(err, "finding project '%s'", p.Project)
+	}
+	if projectRef == nil {
+		return nil
+	}
+	numTasks, err := model.CountTasksToActivateForVersion(v.Id, user)
+	if err != nil {
+		return errors.Wrap(err, "counting tasks to activate")
+	}
+	return model.CheckVersionActivationQuotas(projectRef, v, numTasks)
+}
+
 // FindPatchesByUser finds patches for the input user as ordered by creation time
 func FindPatchesByUser(user string, ts time.Time, limit int) ([]restModel.APIPatch, error) {
 	patches, err := patch.Find(patch.ByUserPaginated(user, ts, limit))
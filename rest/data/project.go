@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -12,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/user"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
@@ -48,6 +50,22 @@ func FindProjectById(id string, includeRepo bool, includeProjectConfig bool) (*m
 	return p, nil
 }
 
+// CheckDirectSettingsWrite returns an error if settings changes for the
+// project must go through a change request rather than being written directly.
+func CheckDirectSettingsWrite(projectId string) error {
+	p, err := model.FindMergedProjectRef(projectId, "", false)
+	if err != nil {
+		return errors.Wrapf(err, "finding project '%s'", projectId)
+	}
+	if p != nil && p.SettingsChangesRequireApproval() {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusForbidden,
+			Message:    fmt.Sprintf("settings changes for project '%s' require approval and must be submitted as a change request", projectId),
+		}
+	}
+	return nil
+}
+
 // CreateProject inserts the given model.ProjectRef.
 func CreateProject(projectRef *model.ProjectRef, u *user.DBUser) error {
 	if projectRef.Identifier != "" {
@@ -183,6 +201,9 @@ func UpdateProjectVars(projectId string, varsModel *restModel.APIProjectVars, ov
 	}
 	vars := v.(*model.ProjectVars)
 	vars.Id = projectId
+	if err = vars.ValidateRestrictions(); err != nil {
+		return errors.Wrap(err, "invalid variable restrictions")
+	}
 
 	if overwrite {
 		if _, err = vars.Upsert(); err != nil {
@@ -196,9 +217,9 @@ func UpdateProjectVars(projectId string, varsModel *restModel.APIProjectVars, ov
 	}
 
 	vars = vars.RedactPrivateVars()
-	varsModel.Vars = vars.Vars
-	varsModel.PrivateVars = vars.PrivateVars
-	varsModel.AdminOnlyVars = vars.AdminOnlyVars
+	if err = varsModel.BuildFromService(vars); err != nil {
+		return errors.Wrap(err, "converting project variables to API model")
+	}
 	varsModel.VarsToDelete = []string{}
 	return nil
 }
@@ -261,6 +282,182 @@ func GetProjectAliasResults(p *model.Project, alias string, includeDeps bool) ([
 	return matches, nil
 }
 
+// ProjectAliasTestOpts describe an alias to match against a project's
+// config.
+type ProjectAliasTestOpts struct {
+	// Alias is the name of an alias defined for the project.
+	Alias string
+	// Definition is an inline alias definition, used instead of Alias.
+	Definition *model.ProjectAlias
+	// Revision is the git ref to read the project config from. If it is not
+	// set, the config from the most recent mainline version is used.
+	Revision    string
+	IncludeDeps bool
+}
+
+// TestProjectAlias returns the variants and tasks that the alias would select
+// from the project's config.
+func TestProjectAlias(ctx context.Context, pRef *model.ProjectRef, opts ProjectAliasTestOpts) ([]restModel.APIVariantTasks, error) {
+	var aliases []model.ProjectAlias
+	if opts.Definition != nil {
+		aliases = []model.ProjectAlias{*opts.Definition}
+		if errs := model.ValidateProjectAliases(aliases, "inline alias"); len(errs) > 0 {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    strings.Join(errs, ", "),
+			}
+		}
+	} else {
+		var err error
+		aliases, err = model.FindAliasInProjectRepoOrConfig(pRef.Id, opts.Alias)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding alias '%s' for project '%s'", opts.Alias, pRef.Identifier)
+		}
+		if len(aliases) == 0 {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("no alias named '%s' for project '%s'", opts.Alias, pRef.Identifier),
+			}
+		}
+	}
+
+	project, err := findProjectConfig(ctx, pRef, opts.Revision)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	requester := getRequesterFromAlias(aliases[0].Alias)
+	variantTasks, err := project.ResolveAliasVariantTasks(aliases, requester, opts.IncludeDeps)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolving variants and tasks for alias")
+	}
+	matches := []restModel.APIVariantTasks{}
+	for _, variantTask := range variantTasks {
+		matches = append(matches, restModel.APIVariantTasksBuildFromService(variantTask))
+	}
+	return matches, nil
+}
+
+// ProjectSelectorOpts describe a task or variant selector to evaluate against
+// a project's config.
+type ProjectSelectorOpts struct {
+	Selector string
+	// Type is the kind of items that the selector selects, either
+	// SelectorTypeTask or SelectorTypeVariant.
+	Type string
+	// Revision is the git ref to read the project config from. If it is not
+	// set, the config from the most recent mainline version is used.
+	Revision string
+}
+
+const (
+	SelectorTypeTask    = "task"
+	SelectorTypeVariant = "variant"
+)
+
+// EvaluateProjectSelector returns the tasks and task groups, or the variants,
+// that the selector selects from the project's config.
+func EvaluateProjectSelector(ctx context.Context, pRef *model.ProjectRef, opts ProjectSelectorOpts) (*restModel.APISelectorMatches, error) {
+	project, err := findProjectConfig(ctx, pRef, opts.Revision)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	matches := &restModel.APISelectorMatches{
+		Selector: utility.ToStringPtr(opts.Selector),
+		Type:     utility.ToStringPtr(opts.Type),
+	}
+	switch opts.Type {
+	case SelectorTypeTask:
+		matches.Tasks, matches.TaskGroups, err = project.EvaluateTaskSelector(opts.Selector)
+	case SelectorTypeVariant:
+		matches.BuildVariants, err = project.EvaluateVariantSelector(opts.Selector)
+	default:
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid selector type '%s'", opts.Type),
+		}
+	}
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return matches, nil
+}
+
+// findProjectConfig returns the project's config at the given revision, or
+// the config for its most recent valid version if no revision is given.
+func findProjectConfig(ctx context.Context, pRef *model.ProjectRef, revision string) (*model.Project, error) {
+	if revision == "" {
+		_, project, err := model.FindLatestVersionWithValidProject(pRef.Id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding latest project config for project '%s'", pRef.Identifier)
+		}
+		return project, nil
+	}
+
+	token, err := evergreen.GetEnvironment().Settings().GetGithubOauthToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting GitHub token")
+	}
+	projectInfo, err := model.GetProjectFromFile(ctx, model.GetProjectOpts{
+		Ref:        pRef,
+		Revision:   revision,
+		RemotePath: pRef.RemotePath,
+		Token:      token,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading project config at revision '%s'", revision)
+	}
+	return projectInfo.Project, nil
+}
+
+// LocalExecutionPlanOpts are the options to compile a task into a plan that
+// can be run locally.
+type LocalExecutionPlanOpts struct {
+	Variant  string
+	Task     string
+	Revision string
+	// Expansions are additional expansions provided by the user, which
+	// take precedence over those known to Evergreen.
+	Expansions map[string]string
+}
+
+// CompileLocalExecutionPlan compiles the task from the project's config into
+// a plan that the CLI can run on a workstation.
+func CompileLocalExecutionPlan(ctx context.Context, pRef *model.ProjectRef, opts LocalExecutionPlanOpts) (*model.LocalExecutionPlan, error) {
+	project, err := findProjectConfig(ctx, pRef, opts.Revision)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	expansions := map[string]string{
+		"project":            pRef.Id,
+		"project_id":         pRef.Id,
+		"project_identifier": pRef.Identifier,
+		"branch_name":        pRef.Branch,
+		"github_org":         pRef.Owner,
+		"github_repo":        pRef.Repo,
+	}
+	if opts.Revision != "" {
+		expansions["revision"] = opts.Revision
+	}
+	for k, v := range opts.Expansions {
+		expansions[k] = v
+	}
+
+	plan, err := project.CompileLocalExecutionPlan(opts.Variant, opts.Task, expansions)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return plan, nil
+}
+
 func getRequesterFromAlias(alias string) string {
 	if alias == evergreen.GithubPRAlias {
 		return evergreen.GithubPRRequester
@@ -0,0 +1,29 @@
+package data
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/validator"
+)
+
+// CheckProjectResurrection returns the reasons that the archived project
+// can't be resurrected as it was, including errors that its most recent
+// project config now has against the current distros and project settings.
+func CheckProjectResurrection(ctx context.Context, archive *model.ProjectArchive, validOrgs []string) []string {
+	problems := archive.ValidateResurrection(validOrgs)
+
+	_, p, err := model.FindLatestVersionWithValidProject(archive.ProjectID)
+	if err != nil || p == nil {
+		// A project without any valid versions has no config to check.
+		return problems
+	}
+	for _, validationErr := range validator.CheckProjectErrors(ctx, p, &archive.Snapshot, false).AtLevel(validator.Error) {
+		problems = append(problems, fmt.Sprintf("project config: %s", validationErr.Message))
+	}
+	for _, validationErr := range validator.CheckProjectSettings(ctx, p, &archive.Snapshot, false).AtLevel(validator.Error) {
+		problems = append(problems, fmt.Sprintf("project settings: %s", validationErr.Message))
+	}
+	return problems
+}
@@ -0,0 +1,63 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// NotifyProjectDependents records that the project was deleted or disabled
+// despite other projects depending on it and emails the admins of each
+// dependent project.
+func NotifyProjectDependents(pRef *model.ProjectRef, dependents []model.ProjectDependent, action, caller string) error {
+	if len(dependents) == 0 {
+		return nil
+	}
+	catcher := grip.NewBasicCatcher()
+	catcher.Wrap(model.LogUpstreamProjectRemoved(pRef, dependents, action, caller), "logging events for dependent projects")
+
+	notifications := []notification.Notification{}
+	for _, dependent := range dependents {
+		if len(dependent.Admins) == 0 {
+			continue
+		}
+		admins, err := user.Find(user.ByIds(dependent.Admins...))
+		if err != nil {
+			catcher.Wrapf(err, "finding admins of project '%s'", dependent.Identifier)
+			continue
+		}
+		payload := &message.Email{
+			Subject: fmt.Sprintf("Evergreen project '%s' was %s", pRef.Identifier, action),
+			Body: fmt.Sprintf("Evergreen project '%s' was %s by %s. Your project '%s' depends on it through its %s, which will no longer work.",
+				pRef.Identifier, action, caller, dependent.Identifier, strings.Join(dependent.Kinds, ", ")),
+			PlainTextContents: true,
+		}
+		for _, admin := range admins {
+			if admin.Email() == "" {
+				continue
+			}
+			sub := event.Subscriber{
+				Type:   event.EmailSubscriberType,
+				Target: utility.ToStringPtr(admin.Email()),
+			}
+			n, err := notification.New("", utility.RandomString(), &sub, payload)
+			if err != nil {
+				catcher.Wrapf(err, "creating notification for admin '%s' of project '%s'", admin.Id, dependent.Identifier)
+				continue
+			}
+			notifications = append(notifications, *n)
+		}
+	}
+	if len(notifications) > 0 {
+		catcher.Wrap(notification.InsertMany(notifications...), "inserting notifications for dependent project admins")
+	}
+	return errors.Wrapf(catcher.Resolve(), "notifying dependents of project '%s'", pRef.Identifier)
+}
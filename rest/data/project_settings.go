@@ -91,6 +91,11 @@ func disableStartingSettings(p *model.ProjectRef) {
 // RepoRef related functions and collection instead of ProjectRef.
 func SaveProjectSettingsForSection(ctx context.Context, projectId string, changes *restModel.APIProjectSettings,
 	section model.ProjectPageSection, isRepo bool, userId string) (*restModel.APIProjectSettings, error) {
+	if !isRepo {
+		if err := CheckDirectSettingsWrite(projectId); err != nil {
+			return nil, err
+		}
+	}
 	before, err := model.GetProjectSettingsById(projectId, isRepo)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting before project settings event")
@@ -123,6 +128,49 @@ func SaveProjectSettingsForSection(ctx context.Context, projectId string, change
 	modified := false
 	switch section {
 	case model.ProjectPageGeneralSection:
+		if err = mergedProjectRef.LogRetention.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid log retention policy")
+		}
+		if mergedProjectRef.ArchiveVersionsAfterDays < 0 {
+			return nil, errors.New("version archival age cannot be negative")
+		}
+		if err = mergedProjectRef.TaskOrder.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid task order")
+		}
+		if utility.FromIntPtr(mergedProjectRef.MaxValidationWarnings) < 0 {
+			return nil, errors.New("max validation warnings cannot be negative")
+		}
+		if err = mergedProjectRef.Quotas.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid project quotas")
+		}
+		if err = mergedProjectRef.PatchPolicy.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid patch policy")
+		}
+		if err = mergedProjectRef.PriorityAging.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid priority aging settings")
+		}
+		if err = model.ValidateStuckTaskPolicy(mergedProjectRef.StuckTaskPolicy); err != nil {
+			return nil, errors.Wrap(err, "invalid stuck task policy")
+		}
+		if err = model.ValidateWatchedPaths(mergedProjectRef.WatchedPaths, mergedProjectRef.WatchedPathsPolicy); err != nil {
+			return nil, errors.Wrap(err, "invalid watched paths")
+		}
+		if err = model.ValidateOverridableExpansions(mergedProjectRef.OverridableExpansions); err != nil {
+			return nil, errors.Wrap(err, "invalid overridable expansions")
+		}
+		if err = mergedProjectRef.GithubVariantChecks.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid GitHub variant check settings")
+		}
+		if err = mergedProjectRef.StalePatchPolicy.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid stale patch policy")
+		}
+		if err = model.ValidateVariantActivationHooks(mergedProjectRef.VariantActivationHooks); err != nil {
+			return nil, errors.Wrap(err, "invalid activation hooks")
+		}
+		// The activation hook secret is generated by Evergreen, so it can't be
+		// changed by users.
+		newProjectRef.VariantActivationHookSecret = before.ProjectRef.VariantActivationHookSecret
+		newProjectRef.EnsureVariantActivationHookSecret()
 		if mergedProjectRef.Identifier != mergedBeforeRef.Identifier {
 			if err = handleIdentifierConflict(mergedProjectRef); err != nil {
 				return nil, err
@@ -210,6 +258,9 @@ func SaveProjectSettingsForSection(ctx context.Context, projectId string, change
 		if err = handleGithubConflicts(mergedProjectRef, "Toggling GitHub features"); err != nil {
 			return nil, err
 		}
+		if err = model.ValidateGithubMergeQueue(mergedProjectRef); err != nil {
+			return nil, errors.Wrap(err, "invalid merge queue settings")
+		}
 		if err = validateFeaturesHaveAliases(mergedProjectRef, changes.Aliases); err != nil {
 			return nil, err
 		}
@@ -220,6 +271,7 @@ func SaveProjectSettingsForSection(ctx context.Context, projectId string, change
 			mergedProjectRef.PatchTriggerAliases[i], err = model.ValidateTriggerDefinition(mergedProjectRef.PatchTriggerAliases[i], projectId)
 			catcher.Add(err)
 		}
+		catcher.Wrap(mergedProjectRef.DownstreamParameterSchema.Validate(), "invalid downstream parameter schema")
 		if catcher.HasErrors() {
 			return nil, errors.Wrap(catcher.Resolve(), "invalid patch trigger aliases")
 		}
@@ -256,6 +308,9 @@ func SaveProjectSettingsForSection(ctx context.Context, projectId string, change
 			err = mergedProjectRef.Triggers[i].Validate(projectId)
 			catcher.Add(err)
 		}
+		if !isRepo && !catcher.HasErrors() {
+			catcher.Add(model.ValidateProjectTriggerCycles(projectId, mergedProjectRef.Triggers))
+		}
 		if catcher.HasErrors() {
 			return nil, errors.Wrap(catcher.Resolve(), "invalid project trigger")
 		}
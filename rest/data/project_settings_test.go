@@ -260,6 +260,40 @@ func TestSaveProjectSettingsForSection(t *testing.T) {
 			assert.NotEmpty(t, pRefFromDB.SpawnHostScriptPath)
 			assert.NotEqual(t, pRefFromDB.Owner, "something different") // because use repo settings is true, we don't change this
 		},
+		"activation hooks are validated and get a secret": func(t *testing.T, ref model.ProjectRef) {
+			ref.VariantActivationHooks = []model.VariantActivationHook{
+				{BuildVariants: []string{"deploy"}, URL: "http://169.254.169.254/latest/meta-data"},
+			}
+			apiProjectRef := restModel.APIProjectRef{}
+			assert.NoError(t, apiProjectRef.BuildFromService(ref))
+			apiChanges := &restModel.APIProjectSettings{
+				ProjectRef: apiProjectRef,
+			}
+			_, err := SaveProjectSettingsForSection(ctx, ref.Id, apiChanges, model.ProjectPageGeneralSection, false, "me")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid activation hooks")
+
+			ref.VariantActivationHooks[0].URL = "https://change-management.example.com/approve"
+			ref.VariantActivationHookSecret = "user provided"
+			assert.NoError(t, apiProjectRef.BuildFromService(ref))
+			apiChanges.ProjectRef = apiProjectRef
+			_, err = SaveProjectSettingsForSection(ctx, ref.Id, apiChanges, model.ProjectPageGeneralSection, false, "me")
+			require.NoError(t, err)
+			pRefFromDB, err := model.FindBranchProjectRef(ref.Id)
+			require.NoError(t, err)
+			require.NotNil(t, pRefFromDB)
+			require.Len(t, pRefFromDB.VariantActivationHooks, 1)
+			secret := pRefFromDB.VariantActivationHookSecret
+			assert.NotEmpty(t, secret)
+			assert.NotEqual(t, "user provided", secret)
+
+			_, err = SaveProjectSettingsForSection(ctx, ref.Id, apiChanges, model.ProjectPageGeneralSection, false, "me")
+			require.NoError(t, err)
+			pRefFromDB, err = model.FindBranchProjectRef(ref.Id)
+			require.NoError(t, err)
+			require.NotNil(t, pRefFromDB)
+			assert.Equal(t, secret, pRefFromDB.VariantActivationHookSecret, "secret should not change once generated")
+		},
 		"github conflicts with enabling": func(t *testing.T, ref model.ProjectRef) {
 			conflictingRef := model.ProjectRef{
 				Owner:               ref.Owner,
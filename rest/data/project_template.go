@@ -0,0 +1,135 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/trigger"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// CreateProjectFromTemplate creates a new project with the given identifier
+// from the template, filling in the template's placeholders with the given
+// values. The rendered settings are validated before anything is saved, and
+// the project along with its variables, aliases, and subscriptions is either
+// created in full or not at all.
+func CreateProjectFromTemplate(templateName, identifier string, values map[string]string, u *user.DBUser, validOrgs []string) (*model.ProjectRef, error) {
+	template, err := model.FindProjectTemplate(templateName)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+	if template == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project template '%s' not found", templateName),
+		}
+	}
+	if err = VerifyUniqueProject(identifier); err != nil {
+		return nil, err
+	}
+
+	settings, err := template.Render(identifier, values)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrapf(err, "filling in project template '%s'", templateName).Error(),
+		}
+	}
+	if problems := validateTemplateProjectSettings(settings, validOrgs); len(problems) > 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("project created from template '%s' is invalid: %s", templateName, strings.Join(problems, "; ")),
+		}
+	}
+
+	// Each project gets its own activation hook secret.
+	settings.ProjectRef.VariantActivationHookSecret = ""
+	settings.ProjectRef.EnsureVariantActivationHookSecret()
+
+	if err = model.AddProjectWithSettings(settings, u); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "creating project '%s' from template '%s'", identifier, templateName).Error(),
+		}
+	}
+	grip.Error(message.WrapError(model.LogProjectAdded(settings.ProjectRef.Id, u.DisplayName()), message.Fields{
+		"message":            "problem logging project added",
+		"project_id":         settings.ProjectRef.Id,
+		"project_identifier": identifier,
+		"template":           templateName,
+		"user":               u.DisplayName(),
+	}))
+
+	return &settings.ProjectRef, nil
+}
+
+// validateTemplateProjectSettings returns the reasons that the settings
+// rendered from a template can't be used to create a project.
+func validateTemplateProjectSettings(settings *model.ProjectSettings, validOrgs []string) []string {
+	problems := []string{}
+	pRef := &settings.ProjectRef
+	if err := pRef.ValidateOwnerAndRepo(validOrgs); err != nil {
+		problems = append(problems, errors.Wrap(err, "invalid owner and repo").Error())
+	}
+	if err := pRef.LogRetention.Validate(); err != nil {
+		problems = append(problems, errors.Wrap(err, "invalid log retention policy").Error())
+	}
+	if pRef.ArchiveVersionsAfterDays < 0 {
+		problems = append(problems, "version archival age cannot be negative")
+	}
+	if err := pRef.TaskOrder.Validate(); err != nil {
+		problems = append(problems, errors.Wrap(err, "invalid task order").Error())
+	}
+	if err := pRef.Quotas.Validate(); err != nil {
+		problems = append(problems, errors.Wrap(err, "invalid project quotas").Error())
+	}
+	if err := pRef.PatchPolicy.Validate(); err != nil {
+		problems = append(problems, errors.Wrap(err, "invalid patch policy").Error())
+	}
+	if err := model.ValidateVariantActivationHooks(pRef.VariantActivationHooks); err != nil {
+		problems = append(problems, errors.Wrap(err, "invalid activation hooks").Error())
+	}
+	for name, size := range pRef.ContainerSizes {
+		if err := size.Validate(); err != nil {
+			problems = append(problems, errors.Wrapf(err, "invalid container size '%s'", name).Error())
+		}
+	}
+	// Validating a trigger also resolves its upstream project's identifier
+	// to its ID.
+	for i := range pRef.Triggers {
+		if err := pRef.Triggers[i].Validate(pRef.Id); err != nil {
+			problems = append(problems, errors.Wrapf(err, "invalid trigger on project '%s'", pRef.Triggers[i].Project).Error())
+		}
+	}
+	if err := settings.Vars.ValidateRestrictions(); err != nil {
+		problems = append(problems, errors.Wrap(err, "invalid variable restrictions").Error())
+	}
+	problems = append(problems, model.ValidateProjectAliases(settings.Aliases, "aliases")...)
+	for _, sub := range settings.Subscriptions {
+		problems = append(problems, validateTemplateSubscription(sub)...)
+	}
+	return problems
+}
+
+func validateTemplateSubscription(sub event.Subscription) []string {
+	if !trigger.ValidateTrigger(sub.ResourceType, sub.Trigger) {
+		return []string{fmt.Sprintf("subscription type/trigger is invalid: %s/%s", sub.ResourceType, sub.Trigger)}
+	}
+	if ok, msg := event.IsSubscriptionAllowed(sub); !ok {
+		return []string{msg}
+	}
+	if err := sub.Validate(); err != nil {
+		return []string{errors.Wrap(err, "invalid subscription").Error()}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package data
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCreateProjectFromTemplate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_ = testutil.NewEnvironment(ctx, t)
+	collections := []string{model.ProjectTemplateCollection, model.ProjectRefCollection, model.ProjectVarsCollection,
+		model.ProjectAliasCollection, event.SubscriptionsCollection, event.AllLogCollection, user.Collection,
+		evergreen.ScopeCollection, evergreen.RoleCollection}
+
+	for tName, tCase := range map[string]func(t *testing.T, u *user.DBUser){
+		"CreatesProject": func(t *testing.T, u *user.DBUser) {
+			pRef, err := CreateProjectFromTemplate("release", "release-1.0", map[string]string{"branch": "v1.0"}, u, []string{"evergreen-ci"})
+			require.NoError(t, err)
+			require.NotNil(t, pRef)
+
+			dbRef, err := model.FindBranchProjectRef("release-1.0")
+			require.NoError(t, err)
+			require.NotNil(t, dbRef)
+			assert.Equal(t, "v1.0", dbRef.Branch)
+			aliases, err := model.FindAliasesForProjectFromDb(dbRef.Id)
+			require.NoError(t, err)
+			require.Len(t, aliases, 1)
+			assert.Equal(t, "v1.0-.*", aliases[0].Variant)
+		},
+		"FailsWithNonexistentTemplate": func(t *testing.T, u *user.DBUser) {
+			_, err := CreateProjectFromTemplate("nonexistent", "release-1.0", nil, u, nil)
+			require.Error(t, err)
+			apiErr, ok := err.(gimlet.ErrorResponse)
+			require.True(t, ok)
+			assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		},
+		"FailsWithExistingIdentifier": func(t *testing.T, u *user.DBUser) {
+			existing := model.ProjectRef{Id: "existing", Identifier: "release-1.0"}
+			require.NoError(t, existing.Insert())
+
+			_, err := CreateProjectFromTemplate("release", "release-1.0", map[string]string{"branch": "v1.0"}, u, nil)
+			assert.Error(t, err)
+		},
+		"DoesNotCreateInvalidProject": func(t *testing.T, u *user.DBUser) {
+			_, err := CreateProjectFromTemplate("release", "release-1.0", map[string]string{"branch": "("}, u, []string{"evergreen-ci"})
+			require.Error(t, err)
+			apiErr, ok := err.(gimlet.ErrorResponse)
+			require.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+			dbRef, err := model.FindBranchProjectRef("release-1.0")
+			assert.NoError(t, err)
+			assert.Nil(t, dbRef)
+			numVars, err := db.Count(model.ProjectVarsCollection, bson.M{})
+			require.NoError(t, err)
+			assert.Zero(t, numVars)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(collections...))
+			defer func() {
+				assert.NoError(t, db.ClearCollections(collections...))
+			}()
+			require.NoError(t, db.CreateCollections(evergreen.ScopeCollection))
+			u := &user.DBUser{Id: "me"}
+			require.NoError(t, u.Insert())
+
+			template := model.ProjectTemplate{
+				Name:         "release",
+				Placeholders: []model.ProjectTemplatePlaceholder{{Name: "branch"}},
+				Settings: model.ProjectSettings{
+					ProjectRef: model.ProjectRef{
+						Owner:  "evergreen-ci",
+						Repo:   "evergreen",
+						Branch: "${branch}",
+					},
+					Vars: model.ProjectVars{Vars: map[string]string{"branch_name": "${branch}"}},
+					Aliases: []model.ProjectAlias{
+						{Alias: evergreen.GithubPRAlias, Variant: "${branch}-.*", Task: ".*"},
+					},
+				},
+			}
+			require.NoError(t, template.Validate())
+			require.NoError(t, template.Upsert())
+
+			tCase(t, u)
+		})
+	}
+}
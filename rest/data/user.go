@@ -26,6 +26,7 @@ func UpdateSettings(dbUser *user.DBUser, settings user.UserSettings) error {
 	settings.Notifications.SpawnHostOutcomeID = dbUser.Settings.Notifications.SpawnHostOutcomeID
 	settings.Notifications.SpawnHostExpirationID = dbUser.Settings.Notifications.SpawnHostExpirationID
 	settings.Notifications.CommitQueueID = dbUser.Settings.Notifications.CommitQueueID
+	settings.Notifications.TasksBlockedID = dbUser.Settings.Notifications.TasksBlockedID
 
 	var patchSubscriber event.Subscriber
 	switch settings.Notifications.PatchFinish {
@@ -135,6 +136,24 @@ func UpdateSettings(dbUser *user.DBUser, settings user.UserSettings) error {
 		settings.Notifications.CommitQueueID = ""
 	}
 
+	var tasksBlockedSubscriber event.Subscriber
+	switch settings.Notifications.TasksBlocked {
+	case user.PreferenceSlack:
+		tasksBlockedSubscriber = event.NewSlackSubscriber(fmt.Sprintf("@%s", settings.SlackUsername))
+	case user.PreferenceEmail:
+		tasksBlockedSubscriber = event.NewEmailSubscriber(dbUser.Email())
+	}
+	tasksBlockedSubscription, err := event.CreateOrUpdateGeneralSubscription(event.GeneralSubscriptionTasksBlocked,
+		dbUser.Settings.Notifications.TasksBlockedID, tasksBlockedSubscriber, dbUser.Id)
+	if err != nil {
+		return errors.Wrap(err, "creating tasks blocked subscription")
+	}
+	if tasksBlockedSubscription != nil {
+		settings.Notifications.TasksBlockedID = tasksBlockedSubscription.ID
+	} else {
+		settings.Notifications.TasksBlockedID = ""
+	}
+
 	return dbUser.UpdateSettings(settings)
 }
 
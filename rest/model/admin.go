@@ -3,6 +3,7 @@ package model
 import (
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/utility"
@@ -12,83 +13,93 @@ import (
 
 func NewConfigModel() *APIAdminSettings {
 	return &APIAdminSettings{
-		Alerts:            &APIAlertsConfig{},
-		Amboy:             &APIAmboyConfig{},
-		Api:               &APIapiConfig{},
-		AuthConfig:        &APIAuthConfig{},
-		Cedar:             &APICedarConfig{},
-		CommitQueue:       &APICommitQueueConfig{},
-		ContainerPools:    &APIContainerPoolsConfig{},
-		Credentials:       map[string]string{},
-		Expansions:        map[string]string{},
-		HostInit:          &APIHostInitConfig{},
-		HostJasper:        &APIHostJasperConfig{},
-		Jira:              &APIJiraConfig{},
-		JIRANotifications: &APIJIRANotificationsConfig{},
-		Keys:              map[string]string{},
-		LDAPRoleMap:       &APILDAPRoleMap{},
-		LoggerConfig:      &APILoggerConfig{},
-		NewRelic:          &APINewRelicConfig{},
-		Notify:            &APINotifyConfig{},
-		Plugins:           map[string]map[string]interface{}{},
-		PodInit:           &APIPodInitConfig{},
-		Providers:         &APICloudProviders{},
-		RepoTracker:       &APIRepoTrackerConfig{},
-		Scheduler:         &APISchedulerConfig{},
-		ServiceFlags:      &APIServiceFlags{},
-		Slack:             &APISlackConfig{},
-		Splunk:            &APISplunkConnectionInfo{},
-		Triggers:          &APITriggerConfig{},
-		Ui:                &APIUIConfig{},
-		Spawnhost:         &APISpawnHostConfig{},
+		Alerts:                &APIAlertsConfig{},
+		Amboy:                 &APIAmboyConfig{},
+		AnnotationAttachments: &APIAnnotationAttachmentsConfig{},
+		Api:                   &APIapiConfig{},
+		AuthConfig:            &APIAuthConfig{},
+		Cedar:                 &APICedarConfig{},
+		CommitQueue:           &APICommitQueueConfig{},
+		ContainerPools:        &APIContainerPoolsConfig{},
+		Credentials:           map[string]string{},
+		Expansions:            map[string]string{},
+		GenerateTasksLimits:   &APIGenerateTasksLimitsConfig{},
+		HostInit:              &APIHostInitConfig{},
+		HostJasper:            &APIHostJasperConfig{},
+		Jira:                  &APIJiraConfig{},
+		JIRANotifications:     &APIJIRANotificationsConfig{},
+		Keys:                  map[string]string{},
+		LDAPRoleMap:           &APILDAPRoleMap{},
+		LoadShedder:           &APILoadShedderConfig{},
+		LoggerConfig:          &APILoggerConfig{},
+		MaintenanceMode:       &APIMaintenanceModeConfig{},
+		NewRelic:              &APINewRelicConfig{},
+		Notify:                &APINotifyConfig{},
+		Plugins:               map[string]map[string]interface{}{},
+		PodInit:               &APIPodInitConfig{},
+		Providers:             &APICloudProviders{},
+		RepoTracker:           &APIRepoTrackerConfig{},
+		Scheduler:             &APISchedulerConfig{},
+		ServiceFlags:          &APIServiceFlags{},
+		Slack:                 &APISlackConfig{},
+		Splunk:                &APISplunkConnectionInfo{},
+		Tracer:                &APITracerConfig{},
+		Triggers:              &APITriggerConfig{},
+		Ui:                    &APIUIConfig{},
+		Spawnhost:             &APISpawnHostConfig{},
 	}
 }
 
 // APIAdminSettings is the structure of a response to the admin route
 type APIAdminSettings struct {
-	Alerts              *APIAlertsConfig                  `json:"alerts,omitempty"`
-	Amboy               *APIAmboyConfig                   `json:"amboy,omitempty"`
-	Api                 *APIapiConfig                     `json:"api,omitempty"`
-	ApiUrl              *string                           `json:"api_url,omitempty"`
-	AuthConfig          *APIAuthConfig                    `json:"auth,omitempty"`
-	Banner              *string                           `json:"banner,omitempty"`
-	BannerTheme         *string                           `json:"banner_theme,omitempty"`
-	Cedar               *APICedarConfig                   `json:"cedar,omitempty"`
-	ClientBinariesDir   *string                           `json:"client_binaries_dir,omitempty"`
-	CommitQueue         *APICommitQueueConfig             `json:"commit_queue,omitempty"`
-	ConfigDir           *string                           `json:"configdir,omitempty"`
-	ContainerPools      *APIContainerPoolsConfig          `json:"container_pools,omitempty"`
-	Credentials         map[string]string                 `json:"credentials,omitempty"`
-	DomainName          *string                           `json:"domain_name,omitempty"`
-	Expansions          map[string]string                 `json:"expansions,omitempty"`
-	GithubPRCreatorOrg  *string                           `json:"github_pr_creator_org,omitempty"`
-	GithubOrgs          []string                          `json:"github_orgs,omitempty"`
-	DisabledGQLQueries  []string                          `json:"disabled_gql_queries"`
-	HostInit            *APIHostInitConfig                `json:"hostinit,omitempty"`
-	HostJasper          *APIHostJasperConfig              `json:"host_jasper,omitempty"`
-	Jira                *APIJiraConfig                    `json:"jira,omitempty"`
-	JIRANotifications   *APIJIRANotificationsConfig       `json:"jira_notifications,omitempty"`
-	Keys                map[string]string                 `json:"keys,omitempty"`
-	LDAPRoleMap         *APILDAPRoleMap                   `json:"ldap_role_map,omitempty"`
-	LoggerConfig        *APILoggerConfig                  `json:"logger_config,omitempty"`
-	LogPath             *string                           `json:"log_path,omitempty"`
-	NewRelic            *APINewRelicConfig                `json:"newrelic,omitempty"`
-	Notify              *APINotifyConfig                  `json:"notify,omitempty"`
-	Plugins             map[string]map[string]interface{} `json:"plugins,omitempty"`
-	PodInit             *APIPodInitConfig                 `json:"pod_init,omitempty"`
-	PprofPort           *string                           `json:"pprof_port,omitempty"`
-	Providers           *APICloudProviders                `json:"providers,omitempty"`
-	RepoTracker         *APIRepoTrackerConfig             `json:"repotracker,omitempty"`
-	Scheduler           *APISchedulerConfig               `json:"scheduler,omitempty"`
-	ServiceFlags        *APIServiceFlags                  `json:"service_flags,omitempty"`
-	Slack               *APISlackConfig                   `json:"slack,omitempty"`
-	SSHKeyDirectory     *string                           `json:"ssh_key_directory,omitempty"`
-	SSHKeyPairs         []APISSHKeyPair                   `json:"ssh_key_pairs,omitempty"`
-	Splunk              *APISplunkConnectionInfo          `json:"splunk,omitempty"`
-	Triggers            *APITriggerConfig                 `json:"triggers,omitempty"`
-	Ui                  *APIUIConfig                      `json:"ui,omitempty"`
-	Spawnhost           *APISpawnHostConfig               `json:"spawnhost,omitempty"`
-	ShutdownWaitSeconds *int                              `json:"shutdown_wait_seconds,omitempty"`
+	Alerts                *APIAlertsConfig                  `json:"alerts,omitempty"`
+	Amboy                 *APIAmboyConfig                   `json:"amboy,omitempty"`
+	AnnotationAttachments *APIAnnotationAttachmentsConfig   `json:"annotation_attachments,omitempty"`
+	Api                   *APIapiConfig                     `json:"api,omitempty"`
+	ApiUrl                *string                           `json:"api_url,omitempty"`
+	AuthConfig            *APIAuthConfig                    `json:"auth,omitempty"`
+	Banner                *string                           `json:"banner,omitempty"`
+	BannerTheme           *string                           `json:"banner_theme,omitempty"`
+	Cedar                 *APICedarConfig                   `json:"cedar,omitempty"`
+	ClientBinariesDir     *string                           `json:"client_binaries_dir,omitempty"`
+	CommitQueue           *APICommitQueueConfig             `json:"commit_queue,omitempty"`
+	ConfigDir             *string                           `json:"configdir,omitempty"`
+	ContainerPools        *APIContainerPoolsConfig          `json:"container_pools,omitempty"`
+	Credentials           map[string]string                 `json:"credentials,omitempty"`
+	DomainName            *string                           `json:"domain_name,omitempty"`
+	Expansions            map[string]string                 `json:"expansions,omitempty"`
+	GenerateTasksLimits   *APIGenerateTasksLimitsConfig     `json:"generate_tasks_limits,omitempty"`
+	GithubPRCreatorOrg    *string                           `json:"github_pr_creator_org,omitempty"`
+	GithubOrgs            []string                          `json:"github_orgs,omitempty"`
+	DisabledGQLQueries    []string                          `json:"disabled_gql_queries"`
+	HostInit              *APIHostInitConfig                `json:"hostinit,omitempty"`
+	HostJasper            *APIHostJasperConfig              `json:"host_jasper,omitempty"`
+	Jira                  *APIJiraConfig                    `json:"jira,omitempty"`
+	JIRANotifications     *APIJIRANotificationsConfig       `json:"jira_notifications,omitempty"`
+	Keys                  map[string]string                 `json:"keys,omitempty"`
+	LDAPRoleMap           *APILDAPRoleMap                   `json:"ldap_role_map,omitempty"`
+	LoadShedder           *APILoadShedderConfig             `json:"load_shedder,omitempty"`
+	LoggerConfig          *APILoggerConfig                  `json:"logger_config,omitempty"`
+	LogPath               *string                           `json:"log_path,omitempty"`
+	MaintenanceMode       *APIMaintenanceModeConfig         `json:"maintenance_mode,omitempty"`
+	NewRelic              *APINewRelicConfig                `json:"newrelic,omitempty"`
+	Notify                *APINotifyConfig                  `json:"notify,omitempty"`
+	Plugins               map[string]map[string]interface{} `json:"plugins,omitempty"`
+	PodInit               *APIPodInitConfig                 `json:"pod_init,omitempty"`
+	PprofPort             *string                           `json:"pprof_port,omitempty"`
+	Providers             *APICloudProviders                `json:"providers,omitempty"`
+	RepoTracker           *APIRepoTrackerConfig             `json:"repotracker,omitempty"`
+	Scheduler             *APISchedulerConfig               `json:"scheduler,omitempty"`
+	ServiceFlags          *APIServiceFlags                  `json:"service_flags,omitempty"`
+	Slack                 *APISlackConfig                   `json:"slack,omitempty"`
+	SSHKeyDirectory       *string                           `json:"ssh_key_directory,omitempty"`
+	SSHKeyPairs           []APISSHKeyPair                   `json:"ssh_key_pairs,omitempty"`
+	Splunk                *APISplunkConnectionInfo          `json:"splunk,omitempty"`
+	Tracer                *APITracerConfig                  `json:"tracer,omitempty"`
+	Triggers              *APITriggerConfig                 `json:"triggers,omitempty"`
+	Ui                    *APIUIConfig                      `json:"ui,omitempty"`
+	Spawnhost             *APISpawnHostConfig               `json:"spawnhost,omitempty"`
+	ShutdownWaitSeconds   *int                              `json:"shutdown_wait_seconds,omitempty"`
 }
 
 // BuildFromService builds a model from the service layer
@@ -1635,6 +1646,10 @@ type APIECSConfig struct {
 	AWSVPC               *APIAWSVPCConfig         `json:"awsvpc"`
 	Clusters             []APIECSClusterConfig    `json:"clusters"`
 	CapacityProviders    []APIECSCapacityProvider `json:"capacity_providers"`
+	MaxCPU               *int                     `json:"max_cpu"`
+	MaxMemoryMB          *int                     `json:"max_memory_mb"`
+	PoolCPU              *int                     `json:"pool_cpu"`
+	PoolMemoryMB         *int                     `json:"pool_memory_mb"`
 }
 
 func (a *APIECSConfig) BuildFromService(conf evergreen.ECSConfig) {
@@ -1654,6 +1669,10 @@ func (a *APIECSConfig) BuildFromService(conf evergreen.ECSConfig) {
 		apiProvider.BuildFromService(cp)
 		a.CapacityProviders = append(a.CapacityProviders, apiProvider)
 	}
+	a.MaxCPU = utility.ToIntPtr(conf.MaxCPU)
+	a.MaxMemoryMB = utility.ToIntPtr(conf.MaxMemoryMB)
+	a.PoolCPU = utility.ToIntPtr(conf.PoolCPU)
+	a.PoolMemoryMB = utility.ToIntPtr(conf.PoolMemoryMB)
 }
 
 func (a *APIECSConfig) ToService() (*evergreen.ECSConfig, error) {
@@ -1685,6 +1704,10 @@ func (a *APIECSConfig) ToService() (*evergreen.ECSConfig, error) {
 		AWSVPC:               a.AWSVPC.ToService(),
 		Clusters:             clusters,
 		CapacityProviders:    providers,
+		MaxCPU:               utility.FromIntPtr(a.MaxCPU),
+		MaxMemoryMB:          utility.FromIntPtr(a.MaxMemoryMB),
+		PoolCPU:              utility.FromIntPtr(a.PoolCPU),
+		PoolMemoryMB:         utility.FromIntPtr(a.PoolMemoryMB),
 	}, nil
 }
 
@@ -2475,13 +2498,17 @@ func (j *APIJIRANotificationsProject) ToService() (interface{}, error) {
 }
 
 type APITriggerConfig struct {
-	GenerateTaskDistro *string `json:"generate_distro"`
+	GenerateTaskDistro  *string `json:"generate_distro"`
+	MaxDownstreamDepth  int     `json:"max_downstream_depth"`
+	MaxDownstreamFanOut int     `json:"max_downstream_fan_out"`
 }
 
 func (c *APITriggerConfig) BuildFromService(h interface{}) error {
 	switch v := h.(type) {
 	case evergreen.TriggerConfig:
 		c.GenerateTaskDistro = utility.ToStringPtr(v.GenerateTaskDistro)
+		c.MaxDownstreamDepth = v.MaxDownstreamDepth
+		c.MaxDownstreamFanOut = v.MaxDownstreamFanOut
 	default:
 		return errors.Errorf("programmatic error: expected downstream task trigger config but got type %T", h)
 	}
@@ -2489,10 +2516,184 @@ func (c *APITriggerConfig) BuildFromService(h interface{}) error {
 }
 func (c *APITriggerConfig) ToService() (interface{}, error) {
 	return evergreen.TriggerConfig{
-		GenerateTaskDistro: utility.FromStringPtr(c.GenerateTaskDistro),
+		GenerateTaskDistro:  utility.FromStringPtr(c.GenerateTaskDistro),
+		MaxDownstreamDepth:  c.MaxDownstreamDepth,
+		MaxDownstreamFanOut: c.MaxDownstreamFanOut,
 	}, nil
 }
 
+type APILoadShedderConfig struct {
+	Disabled             bool `json:"disabled"`
+	DBLatencyThresholdMS int  `json:"db_latency_threshold_ms"`
+	QueueDepthThreshold  int  `json:"queue_depth_threshold"`
+	MinBackoffSecs       int  `json:"min_backoff_secs"`
+	MaxBackoffSecs       int  `json:"max_backoff_secs"`
+}
+
+func (c *APILoadShedderConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.LoadShedderConfig:
+		c.Disabled = v.Disabled
+		c.DBLatencyThresholdMS = v.DBLatencyThresholdMS
+		c.QueueDepthThreshold = v.QueueDepthThreshold
+		c.MinBackoffSecs = v.MinBackoffSecs
+		c.MaxBackoffSecs = v.MaxBackoffSecs
+	default:
+		return errors.Errorf("programmatic error: expected load shedder config but got type %T", h)
+	}
+	return nil
+}
+
+func (c *APILoadShedderConfig) ToService() (interface{}, error) {
+	return evergreen.LoadShedderConfig{
+		Disabled:             c.Disabled,
+		DBLatencyThresholdMS: c.DBLatencyThresholdMS,
+		QueueDepthThreshold:  c.QueueDepthThreshold,
+		MinBackoffSecs:       c.MinBackoffSecs,
+		MaxBackoffSecs:       c.MaxBackoffSecs,
+	}, nil
+}
+
+type APIGenerateTasksLimitsConfig struct {
+	Disabled                  bool     `json:"disabled"`
+	MaxTasksPerVersion        int      `json:"max_tasks_per_version"`
+	MaxVariantsPerVersion     int      `json:"max_variants_per_version"`
+	MaxConfigSizeMBPerVersion int      `json:"max_config_size_mb_per_version"`
+	ExemptProjects            []string `json:"exempt_projects"`
+}
+
+func (c *APIGenerateTasksLimitsConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.GenerateTasksLimitsConfig:
+		c.Disabled = v.Disabled
+		c.MaxTasksPerVersion = v.MaxTasksPerVersion
+		c.MaxVariantsPerVersion = v.MaxVariantsPerVersion
+		c.MaxConfigSizeMBPerVersion = v.MaxConfigSizeMBPerVersion
+		c.ExemptProjects = v.ExemptProjects
+	default:
+		return errors.Errorf("programmatic error: expected generate tasks limits config but got type %T", h)
+	}
+	return nil
+}
+
+func (c *APIGenerateTasksLimitsConfig) ToService() (interface{}, error) {
+	return evergreen.GenerateTasksLimitsConfig{
+		Disabled:                  c.Disabled,
+		MaxTasksPerVersion:        c.MaxTasksPerVersion,
+		MaxVariantsPerVersion:     c.MaxVariantsPerVersion,
+		MaxConfigSizeMBPerVersion: c.MaxConfigSizeMBPerVersion,
+		ExemptProjects:            c.ExemptProjects,
+	}, nil
+}
+
+type APIMaintenanceModeConfig struct {
+	Enabled        bool       `json:"enabled"`
+	RetryAfterSecs int        `json:"retry_after_secs"`
+	StartedAt      *time.Time `json:"started_at"`
+}
+
+func (c *APIMaintenanceModeConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.MaintenanceModeConfig:
+		c.Enabled = v.Enabled
+		c.RetryAfterSecs = v.RetryAfterSecs
+		c.StartedAt = ToTimePtr(v.StartedAt)
+	default:
+		return errors.Errorf("programmatic error: expected maintenance mode config but got type %T", h)
+	}
+	return nil
+}
+
+func (c *APIMaintenanceModeConfig) ToService() (interface{}, error) {
+	startedAt, err := FromTimePtr(c.StartedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing maintenance mode start time")
+	}
+	return evergreen.MaintenanceModeConfig{
+		Enabled:        c.Enabled,
+		RetryAfterSecs: c.RetryAfterSecs,
+		StartedAt:      startedAt,
+	}, nil
+}
+
+type APITracerConfig struct {
+	Enabled      bool              `json:"enabled"`
+	Exporter     *string           `json:"exporter"`
+	SampleRatio  float64           `json:"sample_ratio"`
+	OTLPEndpoint *string           `json:"otlp_endpoint"`
+	OTLPHeaders  map[string]string `json:"otlp_headers"`
+}
+
+func (c *APITracerConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.TracerConfig:
+		c.Enabled = v.Enabled
+		c.Exporter = utility.ToStringPtr(v.Exporter)
+		c.SampleRatio = v.SampleRatio
+		c.OTLPEndpoint = utility.ToStringPtr(v.OTLPEndpoint)
+		c.OTLPHeaders = v.OTLPHeaders
+	default:
+		return errors.Errorf("programmatic error: expected tracer config but got type %T", h)
+	}
+	return nil
+}
+
+func (c *APITracerConfig) ToService() (interface{}, error) {
+	return evergreen.TracerConfig{
+		Enabled:      c.Enabled,
+		Exporter:     utility.FromStringPtr(c.Exporter),
+		SampleRatio:  c.SampleRatio,
+		OTLPEndpoint: utility.FromStringPtr(c.OTLPEndpoint),
+		OTLPHeaders:  c.OTLPHeaders,
+	}, nil
+}
+
+type APIAnnotationAttachmentsConfig struct {
+	Backend             *string           `json:"backend"`
+	S3                  *APIS3Credentials `json:"s3"`
+	SigningKey          *string           `json:"signing_key"`
+	MaxSizeBytes        int               `json:"max_size_bytes"`
+	AllowedContentTypes []string          `json:"allowed_content_types"`
+}
+
+func (c *APIAnnotationAttachmentsConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.AnnotationAttachmentsConfig:
+		c.Backend = utility.ToStringPtr(v.Backend)
+		c.S3 = &APIS3Credentials{}
+		if err := c.S3.BuildFromService(v.S3); err != nil {
+			return errors.Wrap(err, "converting S3 credentials to API model")
+		}
+		c.SigningKey = utility.ToStringPtr(v.SigningKey)
+		c.MaxSizeBytes = v.MaxSizeBytes
+		c.AllowedContentTypes = v.AllowedContentTypes
+	default:
+		return errors.Errorf("programmatic error: expected annotation attachments config but got type %T", h)
+	}
+	return nil
+}
+
+func (c *APIAnnotationAttachmentsConfig) ToService() (interface{}, error) {
+	config := evergreen.AnnotationAttachmentsConfig{
+		Backend:             utility.FromStringPtr(c.Backend),
+		SigningKey:          utility.FromStringPtr(c.SigningKey),
+		MaxSizeBytes:        c.MaxSizeBytes,
+		AllowedContentTypes: c.AllowedContentTypes,
+	}
+	if c.S3 != nil {
+		i, err := c.S3.ToService()
+		if err != nil {
+			return nil, errors.Wrap(err, "converting S3 credentials to service model")
+		}
+		s3, ok := i.(evergreen.S3Credentials)
+		if !ok {
+			return nil, errors.Errorf("programmatic error: expected S3 credentials but got type %T", i)
+		}
+		config.S3 = s3
+	}
+	return config, nil
+}
+
 type APIHostJasperConfig struct {
 	BinaryName       *string `json:"binary_name,omitempty"`
 	DownloadFileName *string `json:"download_file_name,omitempty"`
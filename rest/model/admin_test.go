@@ -192,6 +192,18 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Slack.Options.Channel, utility.FromStringPtr(apiSettings.Slack.Options.Channel))
 	assert.EqualValues(testSettings.Splunk.Channel, utility.FromStringPtr(apiSettings.Splunk.Channel))
 	assert.EqualValues(testSettings.Triggers.GenerateTaskDistro, utility.FromStringPtr(apiSettings.Triggers.GenerateTaskDistro))
+	assert.Equal(testSettings.GenerateTasksLimits.MaxTasksPerVersion, apiSettings.GenerateTasksLimits.MaxTasksPerVersion)
+	assert.Equal(testSettings.GenerateTasksLimits.ExemptProjects, apiSettings.GenerateTasksLimits.ExemptProjects)
+	assert.Equal(testSettings.LoadShedder.DBLatencyThresholdMS, apiSettings.LoadShedder.DBLatencyThresholdMS)
+	assert.Equal(testSettings.Tracer.Exporter, utility.FromStringPtr(apiSettings.Tracer.Exporter))
+	assert.Equal(testSettings.Tracer.SampleRatio, apiSettings.Tracer.SampleRatio)
+	assert.Equal(testSettings.Tracer.OTLPEndpoint, utility.FromStringPtr(apiSettings.Tracer.OTLPEndpoint))
+	assert.Equal(testSettings.Tracer.OTLPHeaders, apiSettings.Tracer.OTLPHeaders)
+	assert.Equal(testSettings.AnnotationAttachments.Backend, utility.FromStringPtr(apiSettings.AnnotationAttachments.Backend))
+	assert.Equal(testSettings.AnnotationAttachments.S3.Bucket, utility.FromStringPtr(apiSettings.AnnotationAttachments.S3.Bucket))
+	assert.Equal(testSettings.AnnotationAttachments.MaxSizeBytes, apiSettings.AnnotationAttachments.MaxSizeBytes)
+	assert.Equal(testSettings.AnnotationAttachments.AllowedContentTypes, apiSettings.AnnotationAttachments.AllowedContentTypes)
+	assert.Equal(testSettings.LoadShedder.QueueDepthThreshold, apiSettings.LoadShedder.QueueDepthThreshold)
 	assert.EqualValues(testSettings.Ui.HttpListenAddr, utility.FromStringPtr(apiSettings.Ui.HttpListenAddr))
 	assert.Equal(testSettings.Spawnhost.SpawnHostsPerUser, *apiSettings.Spawnhost.SpawnHostsPerUser)
 	assert.Equal(testSettings.Spawnhost.UnexpirableHostsPerUser, *apiSettings.Spawnhost.UnexpirableHostsPerUser)
@@ -282,6 +294,11 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Slack.Options.Channel, dbSettings.Slack.Options.Channel)
 	assert.EqualValues(testSettings.Splunk.Channel, dbSettings.Splunk.Channel)
 	assert.EqualValues(testSettings.Triggers.GenerateTaskDistro, dbSettings.Triggers.GenerateTaskDistro)
+	assert.EqualValues(testSettings.GenerateTasksLimits, dbSettings.GenerateTasksLimits)
+	assert.EqualValues(testSettings.LoadShedder, dbSettings.LoadShedder)
+	assert.EqualValues(testSettings.Tracer, dbSettings.Tracer)
+	assert.EqualValues(testSettings.AnnotationAttachments, dbSettings.AnnotationAttachments)
+	assert.Equal(testSettings.MaintenanceMode.RetryAfterSecs, dbSettings.MaintenanceMode.RetryAfterSecs)
 	assert.EqualValues(testSettings.Ui.HttpListenAddr, dbSettings.Ui.HttpListenAddr)
 	assert.EqualValues(testSettings.Spawnhost.SpawnHostsPerUser, dbSettings.Spawnhost.SpawnHostsPerUser)
 	assert.EqualValues(testSettings.Spawnhost.UnexpirableHostsPerUser, dbSettings.Spawnhost.UnexpirableHostsPerUser)
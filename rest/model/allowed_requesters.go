@@ -0,0 +1,47 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIAllowedRequestersSuggestion is a project config rewritten to use allowed
+// requesters instead of the legacy requester flags.
+type APIAllowedRequestersSuggestion struct {
+	// VersionID is the version whose config was rewritten.
+	VersionID   *string                          `json:"version_id"`
+	Config      *string                          `json:"config"`
+	Conversions []APIAllowedRequestersConversion `json:"conversions"`
+	Unconverted []string                         `json:"unconverted"`
+	Equivalent  bool                             `json:"equivalent"`
+	Differences []string                         `json:"differences"`
+}
+
+// APIAllowedRequestersConversion describes the legacy flags that were
+// rewritten on a single task or build variant task.
+type APIAllowedRequestersConversion struct {
+	Task              *string                   `json:"task"`
+	Variant           *string                   `json:"variant,omitempty"`
+	AllowedRequesters []evergreen.UserRequester `json:"allowed_requesters"`
+}
+
+// BuildFromService converts from a service level allowed requesters
+// suggestion.
+func (s *APIAllowedRequestersSuggestion) BuildFromService(suggestion model.AllowedRequestersSuggestion) {
+	s.Config = utility.ToStringPtr(suggestion.Config)
+	s.Conversions = []APIAllowedRequestersConversion{}
+	for _, c := range suggestion.Conversions {
+		conversion := APIAllowedRequestersConversion{
+			Task:              utility.ToStringPtr(c.Task),
+			AllowedRequesters: c.AllowedRequesters,
+		}
+		if c.Variant != "" {
+			conversion.Variant = utility.ToStringPtr(c.Variant)
+		}
+		s.Conversions = append(s.Conversions, conversion)
+	}
+	s.Unconverted = suggestion.Unconverted
+	s.Equivalent = suggestion.Equivalent
+	s.Differences = suggestion.Differences
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+)
+
+// APIArchivedExecution is a previous execution of a task that has since been
+// restarted.
+type APIArchivedExecution struct {
+	Task        APITask               `json:"task"`
+	TestResults APITestResultsSummary `json:"test_results"`
+}
+
+// APITestResultsSummary counts a task's test results by status.
+type APITestResultsSummary struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// BuildFromService converts from an archived task whose test results have been
+// populated.
+func (e *APIArchivedExecution) BuildFromService(t *task.Task) error {
+	if err := e.Task.BuildFromService(t); err != nil {
+		return errors.Wrap(err, "converting archived task to API model")
+	}
+	e.TestResults.BuildFromService(t.LocalTestResults)
+	return nil
+}
+
+// BuildFromService counts the given test results.
+func (s *APITestResultsSummary) BuildFromService(results []task.TestResult) {
+	*s = APITestResultsSummary{Total: len(results)}
+	for _, result := range results {
+		switch result.Status {
+		case evergreen.TestSucceededStatus:
+			s.Passed++
+		case evergreen.TestFailedStatus, evergreen.TestSilentlyFailedStatus:
+			s.Failed++
+		case evergreen.TestSkippedStatus:
+			s.Skipped++
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIBaselineComparison compares the outcomes of a patch version against its
+// mainline baseline version.
+type APIBaselineComparison struct {
+	VersionID     *string                     `json:"version_id"`
+	BaseVersionID *string                     `json:"base_version_id"`
+	Tasks         []APITaskBaselineComparison `json:"tasks"`
+}
+
+// APITaskBaselineComparison compares a patch task against its baseline task.
+type APITaskBaselineComparison struct {
+	TaskID                  *string  `json:"task_id"`
+	BaseTaskID              *string  `json:"base_task_id"`
+	BuildVariant            *string  `json:"build_variant"`
+	DisplayName             *string  `json:"display_name"`
+	Status                  *string  `json:"status"`
+	BaseStatus              *string  `json:"base_status"`
+	Classification          *string  `json:"classification"`
+	NewTestFailures         []string `json:"new_test_failures"`
+	PreexistingTestFailures []string `json:"preexisting_test_failures"`
+}
+
+// BuildFromService converts from a service level baseline comparison.
+func (c *APIBaselineComparison) BuildFromService(comparison model.BaselineComparison) {
+	c.VersionID = utility.ToStringPtr(comparison.VersionID)
+	c.BaseVersionID = utility.ToStringPtr(comparison.BaseVersionID)
+	c.Tasks = []APITaskBaselineComparison{}
+	for _, t := range comparison.Tasks {
+		c.Tasks = append(c.Tasks, APITaskBaselineComparison{
+			TaskID:                  utility.ToStringPtr(t.TaskID),
+			BaseTaskID:              utility.ToStringPtr(t.BaseTaskID),
+			BuildVariant:            utility.ToStringPtr(t.BuildVariant),
+			DisplayName:             utility.ToStringPtr(t.DisplayName),
+			Status:                  utility.ToStringPtr(t.Status),
+			BaseStatus:              utility.ToStringPtr(t.BaseStatus),
+			Classification:          utility.ToStringPtr(t.Classification),
+			NewTestFailures:         t.NewTestFailures,
+			PreexistingTestFailures: t.PreexistingTestFailures,
+		})
+	}
+}
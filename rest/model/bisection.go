@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIBisection is an automatic search for the commit that broke a mainline
+// task.
+type APIBisection struct {
+	ID           *string `json:"id"`
+	ProjectID    *string `json:"project_id"`
+	BuildVariant *string `json:"build_variant"`
+	DisplayName  *string `json:"display_name"`
+	Status       *string `json:"status"`
+	// GoodOrder and GoodTaskID are the latest commit that the task is known
+	// to pass in.
+	GoodOrder  int     `json:"good_order"`
+	GoodTaskID *string `json:"good_task_id"`
+	// BadOrder and BadTaskID are the earliest commit that the task is known
+	// to fail in.
+	BadOrder      int     `json:"bad_order"`
+	BadTaskID     *string `json:"bad_task_id"`
+	PendingTaskID *string `json:"pending_task_id"`
+	// UnversionedRevisions are the commits between the good and bad
+	// mainline versions that never got a version, which the bisection
+	// searches once it has found the version that the task started failing
+	// in. PendingRevision is the one that a version is being created in.
+	UnversionedRevisions []string           `json:"unversioned_revisions"`
+	PendingRevision      *string            `json:"pending_revision"`
+	Steps                []APIBisectionStep `json:"steps"`
+	CulpritVersionID     *string            `json:"culprit_version_id"`
+	CulpritRevision      *string            `json:"culprit_revision"`
+	CreateTime           *time.Time         `json:"create_time"`
+	FinishTime           *time.Time         `json:"finish_time"`
+}
+
+// APIBisectionStep is a commit that a bisection ran the task in.
+type APIBisectionStep struct {
+	VersionID *string    `json:"version_id"`
+	Revision  *string    `json:"revision"`
+	Order     int        `json:"order"`
+	TaskID    *string    `json:"task_id"`
+	Status    *string    `json:"status"`
+	StartTime *time.Time `json:"start_time"`
+}
+
+// BuildFromService converts from a service level bisection.
+func (b *APIBisection) BuildFromService(bisection model.Bisection) {
+	b.ID = utility.ToStringPtr(bisection.Id)
+	b.ProjectID = utility.ToStringPtr(bisection.ProjectId)
+	b.BuildVariant = utility.ToStringPtr(bisection.BuildVariant)
+	b.DisplayName = utility.ToStringPtr(bisection.DisplayName)
+	b.Status = utility.ToStringPtr(bisection.Status)
+	b.GoodOrder = bisection.GoodOrder
+	b.GoodTaskID = utility.ToStringPtr(bisection.GoodTaskId)
+	b.BadOrder = bisection.BadOrder
+	b.BadTaskID = utility.ToStringPtr(bisection.BadTaskId)
+	b.PendingTaskID = utility.ToStringPtr(bisection.PendingTaskId)
+	b.UnversionedRevisions = bisection.UnversionedRevisions
+	b.PendingRevision = utility.ToStringPtr(bisection.PendingRevision)
+	b.Steps = make([]APIBisectionStep, 0, len(bisection.Steps))
+	for _, step := range bisection.Steps {
+		b.Steps = append(b.Steps, APIBisectionStep{
+			VersionID: utility.ToStringPtr(step.VersionId),
+			Revision:  utility.ToStringPtr(step.Revision),
+			Order:     step.RevisionOrderNumber,
+			TaskID:    utility.ToStringPtr(step.TaskId),
+			Status:    utility.ToStringPtr(step.Status),
+			StartTime: ToTimePtr(step.StartTime),
+		})
+	}
+	b.CulpritVersionID = utility.ToStringPtr(bisection.CulpritVersionId)
+	b.CulpritRevision = utility.ToStringPtr(bisection.CulpritRevision)
+	b.CreateTime = ToTimePtr(bisection.CreateTime)
+	b.FinishTime = ToTimePtr(bisection.FinishTime)
+}
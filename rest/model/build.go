@@ -47,6 +47,44 @@ type APIBuild struct {
 	ActualMakespan    APIDuration          `json:"actual_makespan_ms"`
 	Origin            *string              `json:"origin"`
 	StatusCounts      task.TaskStatusCount `json:"status_counts,omitempty"`
+	Timing            APITimingBreakdown   `json:"timing"`
+	// CanarySample is whether the build's variant was sampled for activation,
+	// if the variant only activates on a sample of mainline versions.
+	CanarySample *APICanarySample `json:"canary_sample,omitempty"`
+	// TaskOrder is the order in which to display the build's tasks.
+	TaskOrder *APIBuildTaskOrder `json:"task_order,omitempty"`
+}
+
+// APICanarySample is the decision on whether a mainline version was in a
+// variant's canary sample.
+type APICanarySample struct {
+	Sampled   bool       `json:"sampled"`
+	Reason    *string    `json:"reason"`
+	SampledAt *time.Time `json:"sampled_at"`
+}
+
+// BuildFromService converts a service level canary sample result to an
+// APICanarySample.
+func (s *APICanarySample) BuildFromService(result build.CanarySampleResult) {
+	s.Sampled = result.Sampled
+	s.Reason = utility.ToStringPtr(result.Reason)
+	s.SampledAt = ToTimePtr(result.SampledAt)
+}
+
+// APITimingBreakdown is the time a build or version's tasks spent blocked on
+// dependencies, waiting in the task queue, and running.
+type APITimingBreakdown struct {
+	BlockedTime APIDuration `json:"blocked_time_ms"`
+	QueueTime   APIDuration `json:"queue_time_ms"`
+	RunTime     APIDuration `json:"run_time_ms"`
+}
+
+// BuildFromService converts a service level timing breakdown to an
+// APITimingBreakdown.
+func (tb *APITimingBreakdown) BuildFromService(timing task.TimingBreakdown) {
+	tb.BlockedTime = NewAPIDuration(timing.BlockedTime)
+	tb.QueueTime = NewAPIDuration(timing.QueueTime)
+	tb.RunTime = NewAPIDuration(timing.RunTime)
 }
 
 // BuildFromService converts from service level structs to an APIBuild.
@@ -76,6 +114,11 @@ func (apiBuild *APIBuild) BuildFromService(h interface{}) error {
 	apiBuild.DisplayName = utility.ToStringPtr(v.DisplayName)
 	apiBuild.PredictedMakespan = NewAPIDuration(v.PredictedMakespan)
 	apiBuild.ActualMakespan = NewAPIDuration(v.ActualMakespan)
+	apiBuild.Timing.BuildFromService(v.Timing)
+	if v.CanarySample != nil {
+		apiBuild.CanarySample = &APICanarySample{}
+		apiBuild.CanarySample.BuildFromService(*v.CanarySample)
+	}
 	apiBuild.Tags = utility.ToStringPtrSlice(v.Tags)
 	var origin string
 	switch v.Requester {
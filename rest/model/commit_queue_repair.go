@@ -0,0 +1,84 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIMergeTaskDependencyChange is a change to the chain of dependencies
+// between the merge tasks of the items in a commit queue.
+type APIMergeTaskDependencyChange struct {
+	TaskID             *string `json:"task_id"`
+	RemoveDependencyOn *string `json:"remove_dependency_on,omitempty"`
+	AddDependencyOn    *string `json:"add_dependency_on,omitempty"`
+}
+
+// BuildFromService converts from a service level merge task dependency change.
+func (c *APIMergeTaskDependencyChange) BuildFromService(change model.MergeTaskDependencyChange) {
+	c.TaskID = utility.ToStringPtr(change.TaskID)
+	if change.RemoveDependencyOn != "" {
+		c.RemoveDependencyOn = utility.ToStringPtr(change.RemoveDependencyOn)
+	}
+	if change.AddDependencyOn != "" {
+		c.AddDependencyOn = utility.ToStringPtr(change.AddDependencyOn)
+	}
+}
+
+// APICommitQueueGithubStatus is a commit queue status that would be sent to
+// GitHub for a patch.
+type APICommitQueueGithubStatus struct {
+	PatchID     *string `json:"patch_id"`
+	PRNumber    int     `json:"pr_number"`
+	State       *string `json:"state"`
+	Description *string `json:"description"`
+}
+
+// APIDequeueAndRestartPlan describes what dequeuing a commit queue item and
+// restarting the items after it would do.
+type APIDequeueAndRestartPlan struct {
+	ProjectID         *string                        `json:"project_id"`
+	TaskID            *string                        `json:"task_id"`
+	Issue             *string                        `json:"issue"`
+	RestartVersions   []string                       `json:"restart_versions"`
+	DependencyChanges []APIMergeTaskDependencyChange `json:"dependency_changes"`
+	GithubStatuses    []APICommitQueueGithubStatus   `json:"github_statuses"`
+	Warnings          []string                       `json:"warnings,omitempty"`
+}
+
+// BuildFromService converts from a service level dequeue and restart plan.
+func (p *APIDequeueAndRestartPlan) BuildFromService(plan model.DequeueAndRestartPlan) {
+	p.ProjectID = utility.ToStringPtr(plan.ProjectID)
+	p.TaskID = utility.ToStringPtr(plan.TaskID)
+	p.Issue = utility.ToStringPtr(plan.Issue)
+	p.RestartVersions = plan.RestartVersions
+	p.DependencyChanges = BuildMergeTaskDependencyChanges(plan.DependencyChanges)
+	p.GithubStatuses = make([]APICommitQueueGithubStatus, 0, len(plan.GithubStatuses))
+	for _, status := range plan.GithubStatuses {
+		p.GithubStatuses = append(p.GithubStatuses, APICommitQueueGithubStatus{
+			PatchID:     utility.ToStringPtr(status.PatchID),
+			PRNumber:    status.PRNumber,
+			State:       utility.ToStringPtr(string(status.State)),
+			Description: utility.ToStringPtr(status.Description),
+		})
+	}
+	p.Warnings = plan.Warnings
+}
+
+// BuildMergeTaskDependencyChanges converts from service level merge task
+// dependency changes.
+func BuildMergeTaskDependencyChanges(changes []model.MergeTaskDependencyChange) []APIMergeTaskDependencyChange {
+	apiChanges := make([]APIMergeTaskDependencyChange, 0, len(changes))
+	for _, change := range changes {
+		apiChange := APIMergeTaskDependencyChange{}
+		apiChange.BuildFromService(change)
+		apiChanges = append(apiChanges, apiChange)
+	}
+	return apiChanges
+}
+
+// APICommitQueueRepairResult is the result of repairing the chain of merge
+// task dependencies in a commit queue.
+type APICommitQueueRepairResult struct {
+	DryRun  bool                           `json:"dry_run"`
+	Changes []APIMergeTaskDependencyChange `json:"changes"`
+}
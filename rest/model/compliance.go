@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIComplianceReportTask is a task that produces distributables and the
+// artifacts that it attached.
+type APIComplianceReportTask struct {
+	TaskID                *string    `json:"task_id"`
+	Execution             int        `json:"execution"`
+	DisplayName           *string    `json:"display_name"`
+	BuildVariant          *string    `json:"build_variant"`
+	Status                *string    `json:"status"`
+	Activated             bool       `json:"activated"`
+	FinishTime            *time.Time `json:"finish_time"`
+	ProducesDistributable bool       `json:"produces_distributable"`
+	LicenseScanRequired   bool       `json:"license_scan_required"`
+	License               *string    `json:"license"`
+	Artifacts             []APIFile  `json:"artifacts"`
+}
+
+// BuildFromService converts from a service level compliance report task.
+func (t *APIComplianceReportTask) BuildFromService(reportTask model.ComplianceReportTask) {
+	t.TaskID = utility.ToStringPtr(reportTask.Task.Id)
+	t.Execution = reportTask.Task.Execution
+	t.DisplayName = utility.ToStringPtr(reportTask.Task.DisplayName)
+	t.BuildVariant = utility.ToStringPtr(reportTask.Task.BuildVariant)
+	t.Status = utility.ToStringPtr(reportTask.Task.Status)
+	t.Activated = reportTask.Task.Activated
+	t.FinishTime = ToTimePtr(reportTask.Task.FinishTime)
+	if c := reportTask.Task.Compliance; c != nil {
+		t.ProducesDistributable = c.ProducesDistributable
+		t.LicenseScanRequired = c.LicenseScanRequired
+		t.License = utility.ToStringPtr(c.License)
+	}
+	t.Artifacts = []APIFile{}
+	for _, file := range reportTask.Artifacts {
+		apiFile := APIFile{}
+		// Artifact files are the only supported type, so this can't fail.
+		_ = apiFile.BuildFromService(file)
+		t.Artifacts = append(t.Artifacts, apiFile)
+	}
+}
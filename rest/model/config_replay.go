@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIConfigReplay is the result of validating a version's project config
+// against the current validation rules.
+type APIConfigReplay struct {
+	VersionID  *string    `json:"version_id"`
+	Revision   *string    `json:"revision"`
+	CreateTime *time.Time `json:"create_time"`
+	// LoadError is set if the version's project config can no longer be
+	// loaded, in which case no rules could be run against it.
+	LoadError *string `json:"load_error,omitempty"`
+	// ParserProject is the version's parser project as YAML, if requested.
+	ParserProject *string `json:"parser_project,omitempty"`
+	// Failures are the rules that the project config now fails.
+	Failures []APIRuleFailure `json:"failures"`
+}
+
+// APIRuleFailure is a validation rule that a project config fails.
+type APIRuleFailure struct {
+	Rule     *string  `json:"rule"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// BuildFromService converts the version whose project config was replayed.
+// The rules that the project config fails are set separately.
+func (r *APIConfigReplay) BuildFromService(v model.Version) {
+	r.VersionID = utility.ToStringPtr(v.Id)
+	r.Revision = utility.ToStringPtr(v.Revision)
+	r.CreateTime = ToTimePtr(v.CreateTime)
+	r.Failures = []APIRuleFailure{}
+}
+
+// APIProjectConfigReplay is the result of validating the project configs of a
+// project's recent versions against the current validation rules.
+type APIProjectConfigReplay struct {
+	Versions []APIConfigReplay `json:"versions"`
+	// NumVersionsUnloadable is how many of the versions have project configs
+	// that can no longer be loaded.
+	NumVersionsUnloadable int `json:"num_versions_unloadable"`
+	// Rules are the rules that any of the versions fail, with how many of the
+	// versions fail each rule, so that the breakage of enforcing a rule can be
+	// estimated.
+	Rules []APIRuleBreakage `json:"rules"`
+}
+
+// APIRuleBreakage is how many of the replayed versions fail a rule.
+type APIRuleBreakage struct {
+	Rule                *string `json:"rule"`
+	NumVersionsErrored  int     `json:"num_versions_errored"`
+	NumVersionsWarned   int     `json:"num_versions_warned"`
+	NumVersionsReplayed int     `json:"num_versions_replayed"`
+}
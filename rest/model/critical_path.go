@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APICriticalPathTask is a task on the critical path of a build or version.
+type APICriticalPathTask struct {
+	TaskID       *string            `json:"task_id"`
+	DisplayName  *string            `json:"display_name"`
+	BuildVariant *string            `json:"build_variant"`
+	BuildID      *string            `json:"build_id"`
+	Status       *string            `json:"status"`
+	StartTime    *time.Time         `json:"start_time"`
+	FinishTime   *time.Time         `json:"finish_time"`
+	Timing       APITimingBreakdown `json:"timing"`
+}
+
+// APICriticalPath is the chain of dependent tasks that determined how long a
+// build or version took to finish.
+type APICriticalPath struct {
+	Tasks             []APICriticalPathTask `json:"tasks"`
+	Duration          APIDuration           `json:"duration_ms"`
+	QueueTime         APIDuration           `json:"queue_time_ms"`
+	RunTime           APIDuration           `json:"run_time_ms"`
+	ActualMakespan    APIDuration           `json:"actual_makespan_ms"`
+	PredictedMakespan APIDuration           `json:"predicted_makespan_ms"`
+	Complete          bool                  `json:"complete"`
+}
+
+// BuildFromService converts from a service level critical path.
+func (cp *APICriticalPath) BuildFromService(path model.CriticalPath) {
+	cp.Tasks = make([]APICriticalPathTask, 0, len(path.Tasks))
+	for _, t := range path.Tasks {
+		apiTask := APICriticalPathTask{
+			TaskID:       utility.ToStringPtr(t.TaskID),
+			DisplayName:  utility.ToStringPtr(t.DisplayName),
+			BuildVariant: utility.ToStringPtr(t.BuildVariant),
+			BuildID:      utility.ToStringPtr(t.BuildID),
+			Status:       utility.ToStringPtr(t.Status),
+			StartTime:    ToTimePtr(t.StartTime),
+			FinishTime:   ToTimePtr(t.FinishTime),
+		}
+		apiTask.Timing.BuildFromService(t.Timing)
+		cp.Tasks = append(cp.Tasks, apiTask)
+	}
+	cp.Duration = NewAPIDuration(path.Duration)
+	cp.QueueTime = NewAPIDuration(path.QueueTime)
+	cp.RunTime = NewAPIDuration(path.RunTime)
+	cp.ActualMakespan = NewAPIDuration(path.ActualMakespan)
+	cp.PredictedMakespan = NewAPIDuration(path.PredictedMakespan)
+	cp.Complete = path.Complete
+}
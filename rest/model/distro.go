@@ -465,6 +465,7 @@ type APIDistro struct {
 	IsCluster             bool                     `json:"is_cluster"`
 	Note                  *string                  `json:"note"`
 	ValidProjects         []*string                `json:"valid_projects"`
+	StuckTaskThreshold    APIDuration              `json:"stuck_task_threshold"`
 }
 
 // BuildFromService converts from service level distro.Distro to an APIDistro
@@ -548,6 +549,7 @@ func (apiDistro *APIDistro) BuildFromService(h interface{}) error {
 	apiDistro.IcecreamSettings = icecreamSettings
 	apiDistro.IsVirtualWorkstation = d.IsVirtualWorkstation
 	apiDistro.IsCluster = d.IsCluster
+	apiDistro.StuckTaskThreshold = NewAPIDuration(d.StuckTaskThreshold)
 
 	return nil
 }
@@ -656,6 +658,7 @@ func (apiDistro *APIDistro) ToService() (interface{}, error) {
 	d.IcecreamSettings = icecreamSettings
 	d.IsVirtualWorkstation = apiDistro.IsVirtualWorkstation
 	d.IsCluster = apiDistro.IsCluster
+	d.StuckTaskThreshold = apiDistro.StuckTaskThreshold.ToDuration()
 
 	return &d, nil
 }
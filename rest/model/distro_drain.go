@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIDistroDrainProgress describes a distro drain and how much work is left
+// on the distro.
+type APIDistroDrainProgress struct {
+	DistroID        *string    `json:"distro_id"`
+	TargetDistroID  *string    `json:"target_distro_id"`
+	Mode            *string    `json:"mode"`
+	StartedBy       *string    `json:"started_by"`
+	StartedAt       *time.Time `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+	NumMigrated     int        `json:"num_migrated"`
+	NumRescheduled  int        `json:"num_rescheduled"`
+	NumRunning      int        `json:"num_running"`
+	NumUndispatched int        `json:"num_undispatched"`
+	Finished        bool       `json:"finished"`
+}
+
+// BuildFromService converts from service level distro drain progress.
+func (p *APIDistroDrainProgress) BuildFromService(progress model.DistroDrainProgress) {
+	p.DistroID = utility.ToStringPtr(progress.Drain.DistroID)
+	p.TargetDistroID = utility.ToStringPtr(progress.Drain.TargetDistroID)
+	p.Mode = utility.ToStringPtr(progress.Drain.Mode)
+	p.StartedBy = utility.ToStringPtr(progress.Drain.StartedBy)
+	p.StartedAt = ToTimePtr(progress.Drain.StartedAt)
+	p.FinishedAt = ToTimePtr(progress.Drain.FinishedAt)
+	p.NumMigrated = progress.Drain.NumMigrated
+	p.NumRescheduled = progress.Drain.NumRescheduled
+	p.NumRunning = progress.NumRunning
+	p.NumUndispatched = progress.NumUndispatched
+	p.Finished = progress.IsFinished()
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIDistroFailureRate is how often the task executions that ran on a distro
+// failed.
+type APIDistroFailureRate struct {
+	DistroID      *string `json:"distro_id"`
+	NumExecutions int     `json:"num_executions"`
+	NumFailed     int     `json:"num_failed"`
+	FailureRate   float64 `json:"failure_rate"`
+}
+
+// BuildFromService converts from a service level distro failure rate.
+func (r *APIDistroFailureRate) BuildFromService(rate model.DistroFailureRate) {
+	r.DistroID = utility.ToStringPtr(rate.DistroID)
+	r.NumExecutions = rate.NumExecutions
+	r.NumFailed = rate.NumFailed
+	r.FailureRate = rate.FailureRate
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIDistroHealth summarizes the recent host failures on a distro.
+type APIDistroHealth struct {
+	DistroID            *string         `json:"distro_id"`
+	Since               *time.Time      `json:"since"`
+	NumTasks            int             `json:"num_tasks"`
+	NumSystemFailures   int             `json:"num_system_failures"`
+	NumSetupFailures    int             `json:"num_setup_failures"`
+	FailureRate         float64         `json:"failure_rate"`
+	NumQuarantinedHosts int             `json:"num_quarantined_hosts"`
+	Healthy             bool            `json:"healthy"`
+	Hosts               []APIHostHealth `json:"hosts"`
+}
+
+// APIHostHealth summarizes the recent host failures on one host.
+type APIHostHealth struct {
+	HostID            *string `json:"host_id"`
+	Status            *string `json:"status"`
+	NumTasks          int     `json:"num_tasks"`
+	NumSystemFailures int     `json:"num_system_failures"`
+	NumSetupFailures  int     `json:"num_setup_failures"`
+	FailureRate       float64 `json:"failure_rate"`
+}
+
+// BuildFromService converts from service level distro health.
+func (h *APIDistroHealth) BuildFromService(health model.DistroHealth) {
+	h.DistroID = utility.ToStringPtr(health.DistroID)
+	h.Since = ToTimePtr(health.Since)
+	h.NumTasks = health.NumTasks
+	h.NumSystemFailures = health.NumSystemFailures
+	h.NumSetupFailures = health.NumSetupFailures
+	h.FailureRate = health.FailureRate
+	h.NumQuarantinedHosts = health.NumQuarantinedHosts
+	h.Healthy = health.Healthy
+	h.Hosts = make([]APIHostHealth, 0, len(health.Hosts))
+	for _, hostHealth := range health.Hosts {
+		h.Hosts = append(h.Hosts, APIHostHealth{
+			HostID:            utility.ToStringPtr(hostHealth.HostID),
+			Status:            utility.ToStringPtr(hostHealth.Status),
+			NumTasks:          hostHealth.NumTasks,
+			NumSystemFailures: hostHealth.NumSystemFailures,
+			NumSetupFailures:  hostHealth.NumSetupFailures,
+			FailureRate:       hostHealth.FailureRate,
+		})
+	}
+}
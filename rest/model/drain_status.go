@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+)
+
+// APIDrainStatus is how far along the app servers are in draining running
+// tasks while in maintenance mode.
+type APIDrainStatus struct {
+	MaintenanceMode        bool           `json:"maintenance_mode"`
+	RetryAfterSecs         float64        `json:"retry_after_secs"`
+	StartedAt              *time.Time     `json:"started_at,omitempty"`
+	Drained                bool           `json:"drained"`
+	InFlightTasks          int            `json:"in_flight_tasks"`
+	InFlightTasksByProject map[string]int `json:"in_flight_tasks_by_project"`
+}
+
+// BuildFromService converts from a service level drain status.
+func (s *APIDrainStatus) BuildFromService(status model.DrainStatus) {
+	s.MaintenanceMode = status.MaintenanceMode.Enabled
+	s.RetryAfterSecs = status.MaintenanceMode.GetRetryAfter().Seconds()
+	if !status.MaintenanceMode.StartedAt.IsZero() {
+		s.StartedAt = ToTimePtr(status.MaintenanceMode.StartedAt)
+	}
+	s.Drained = status.Drained()
+	s.InFlightTasks = status.InFlightTasks
+	s.InFlightTasksByProject = status.InFlightTasksByProject
+}
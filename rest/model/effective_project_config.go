@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APIEffectiveProjectConfig is the project settings that applied to a
+// version, along with where each setting came from.
+type APIEffectiveProjectConfig struct {
+	VersionID *string       `json:"version_id"`
+	Settings  APIProjectRef `json:"settings"`
+	// Sources maps each setting to whether it came from the branch project,
+	// the repo, the version's project config, or is unset.
+	Sources map[string]string `json:"sources"`
+}
+
+// BuildFromService converts from a service level effective project config.
+func (c *APIEffectiveProjectConfig) BuildFromService(config model.EffectiveProjectConfig) error {
+	c.VersionID = utility.ToStringPtr(config.VersionID)
+	if err := c.Settings.BuildFromService(config.ProjectRef); err != nil {
+		return errors.Wrap(err, "converting project settings to API model")
+	}
+	c.Sources = config.Sources
+	return nil
+}
@@ -28,6 +28,8 @@ type TaskEventData struct {
 	JiraLink  *string    `bson:"jira_link,omitempty" json:"jira_link,omitempty"`
 	Timestamp *time.Time `bson:"ts,omitempty" json:"timestamp,omitempty"`
 	Priority  int64      `bson:"pri,omitempty" json:"priority,omitempty"`
+	// NumSecrets is the number of secrets redacted from the task's uploads.
+	NumSecrets int `bson:"num_secrets,omitempty" json:"num_secrets,omitempty"`
 }
 
 type HostAPIEventLogEntry struct {
@@ -77,6 +79,7 @@ func (el *TaskEventData) BuildFromService(v *event.TaskEventData) error {
 	el.Status = utility.ToStringPtr(v.Status)
 	el.Timestamp = ToTimePtr(v.Timestamp)
 	el.Priority = v.Priority
+	el.NumSecrets = v.NumSecrets
 	return nil
 }
 
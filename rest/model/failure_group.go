@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIFailureGroup is a set of failed tasks in a version that failed with the
+// same signature.
+type APIFailureGroup struct {
+	Fingerprint *string `json:"fingerprint"`
+	// Count is the number of failed tasks in the group.
+	Count       int     `json:"count"`
+	Type        *string `json:"type"`
+	Description *string `json:"description"`
+	// Summary describes the group, e.g. "12 tasks failed with the same
+	// signature".
+	Summary *string   `json:"summary"`
+	TaskIDs []*string `json:"task_ids"`
+}
+
+// BuildFromService converts from a service level failure group.
+func (g *APIFailureGroup) BuildFromService(group model.FailureGroup) {
+	g.Fingerprint = utility.ToStringPtr(group.Fingerprint)
+	g.Count = len(group.Tasks)
+	g.Type = utility.ToStringPtr(group.Type)
+	g.Description = utility.ToStringPtr(group.Description)
+	summary := "1 task failed with this signature"
+	if g.Count != 1 {
+		summary = fmt.Sprintf("%d tasks failed with the same signature", g.Count)
+	}
+	g.Summary = utility.ToStringPtr(summary)
+	g.TaskIDs = []*string{}
+	for _, t := range group.Tasks {
+		g.TaskIDs = append(g.TaskIDs, utility.ToStringPtr(t.Id))
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIGithubBudget is the GitHub rate limit budget of an installation that
+// Evergreen calls GitHub with.
+type APIGithubBudget struct {
+	Installation *string    `json:"installation"`
+	Limit        int        `json:"limit"`
+	Remaining    int        `json:"remaining"`
+	Reset        *time.Time `json:"reset"`
+	// Estimated is true if the budget is counted by Evergreen because GitHub
+	// hasn't reported the installation's rate limit.
+	Estimated bool       `json:"estimated"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	// Calls are the number of calls allowed and deferred by priority.
+	Calls map[string]APIGithubCallCounts `json:"calls"`
+}
+
+// APIGithubCallCounts are the number of calls of a priority that were made or
+// deferred.
+type APIGithubCallCounts struct {
+	Allowed  int `json:"allowed"`
+	Deferred int `json:"deferred"`
+}
+
+// BuildFromService converts from a service level GitHub budget status.
+func (b *APIGithubBudget) BuildFromService(status thirdparty.GithubBudgetStatus) {
+	b.Installation = utility.ToStringPtr(status.Installation)
+	b.Limit = status.Limit
+	b.Remaining = status.Remaining
+	b.Reset = ToTimePtr(status.Reset)
+	b.Estimated = status.Estimated
+	b.UpdatedAt = ToTimePtr(status.UpdatedAt)
+	b.Calls = map[string]APIGithubCallCounts{}
+	for priority, counts := range status.Calls {
+		b.Calls[priority] = APIGithubCallCounts{
+			Allowed:  counts.Allowed,
+			Deferred: counts.Deferred,
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+)
+
+// APILoadStatus is a sample of how loaded the app server's dependencies are.
+type APILoadStatus struct {
+	Shedding    bool       `json:"shedding"`
+	DBLatencyMS int64      `json:"db_latency_ms"`
+	QueueDepth  int        `json:"queue_depth"`
+	Pressure    float64    `json:"pressure"`
+	BackoffSecs float64    `json:"backoff_secs"`
+	SampledAt   *time.Time `json:"sampled_at"`
+}
+
+// BuildFromService converts from a service level load status.
+func (s *APILoadStatus) BuildFromService(status model.LoadStatus) {
+	s.Shedding = status.Shedding()
+	s.DBLatencyMS = status.DBLatency.Milliseconds()
+	s.QueueDepth = status.QueueDepth
+	s.Pressure = status.Pressure
+	s.BackoffSecs = status.Backoff.Seconds()
+	s.SampledAt = ToTimePtr(status.SampledAt)
+}
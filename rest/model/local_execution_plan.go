@@ -0,0 +1,61 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APILocalExecutionPlan is a flattened, ordered list of the commands that a
+// task runs, which the CLI can run on a workstation.
+type APILocalExecutionPlan struct {
+	Variant              *string                 `json:"build_variant"`
+	Task                 *string                 `json:"task"`
+	TaskGroup            *string                 `json:"task_group,omitempty"`
+	Expansions           map[string]string       `json:"expansions"`
+	Steps                []APILocalExecutionStep `json:"steps"`
+	UnresolvedExpansions []string                `json:"unresolved_expansions"`
+}
+
+// APILocalExecutionStep is a single command in a local execution plan.
+type APILocalExecutionStep struct {
+	Block                *string                `json:"block"`
+	Function             *string                `json:"function,omitempty"`
+	Command              *string                `json:"command"`
+	DisplayName          *string                `json:"display_name,omitempty"`
+	Type                 *string                `json:"type,omitempty"`
+	Params               map[string]interface{} `json:"params,omitempty"`
+	UnresolvedExpansions []string               `json:"unresolved_expansions,omitempty"`
+}
+
+// BuildFromService converts from a service level local execution plan.
+func (p *APILocalExecutionPlan) BuildFromService(plan model.LocalExecutionPlan) {
+	p.Variant = utility.ToStringPtr(plan.Variant)
+	p.Task = utility.ToStringPtr(plan.Task)
+	if plan.TaskGroup != "" {
+		p.TaskGroup = utility.ToStringPtr(plan.TaskGroup)
+	}
+	p.Expansions = plan.Expansions
+	p.UnresolvedExpansions = plan.UnresolvedExpansions
+	if p.UnresolvedExpansions == nil {
+		p.UnresolvedExpansions = []string{}
+	}
+	p.Steps = []APILocalExecutionStep{}
+	for _, step := range plan.Steps {
+		apiStep := APILocalExecutionStep{
+			Block:                utility.ToStringPtr(step.Block),
+			Command:              utility.ToStringPtr(step.Command),
+			Params:               step.Params,
+			UnresolvedExpansions: step.UnresolvedExpansions,
+		}
+		if step.Function != "" {
+			apiStep.Function = utility.ToStringPtr(step.Function)
+		}
+		if step.DisplayName != "" {
+			apiStep.DisplayName = utility.ToStringPtr(step.DisplayName)
+		}
+		if step.Type != "" {
+			apiStep.Type = utility.ToStringPtr(step.Type)
+		}
+		p.Steps = append(p.Steps, apiStep)
+	}
+}
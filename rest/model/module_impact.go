@@ -0,0 +1,74 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIModuleImpactOptions chooses which projects that use a patch's project
+// as a module to check the patch against, and which of their tasks to run.
+type APIModuleImpactOptions struct {
+	// ChildProjects limits the projects to check to the given project IDs or
+	// identifiers. If empty, every project using the module is checked.
+	ChildProjects []string `json:"child_projects"`
+	// TaskSpecifiers select the tasks to run in each child patch.
+	TaskSpecifiers []APITaskSpecifier `json:"task_specifiers"`
+	// Run creates the child patches. Otherwise, the child patches are only
+	// previewed.
+	Run bool `json:"run"`
+}
+
+// ToService returns the task specifiers to run in each child patch.
+func (o *APIModuleImpactOptions) ToService() []patch.TaskSpecifier {
+	specifiers := make([]patch.TaskSpecifier, 0, len(o.TaskSpecifiers))
+	for _, specifier := range o.TaskSpecifiers {
+		specifiers = append(specifiers, patch.TaskSpecifier{
+			PatchAlias:   utility.FromStringPtr(specifier.PatchAlias),
+			TaskRegex:    utility.FromStringPtr(specifier.TaskRegex),
+			VariantRegex: utility.FromStringPtr(specifier.VariantRegex),
+		})
+	}
+	return specifiers
+}
+
+// APIModuleImpact is the impact of a patch on the projects that use the
+// patch's project as a module.
+type APIModuleImpact struct {
+	PatchID  *string                  `json:"patch_id"`
+	Preview  bool                     `json:"preview"`
+	Projects []APIModuleImpactProject `json:"projects"`
+}
+
+// APIModuleImpactProject is a project that uses the patch's project as a
+// module, along with the tasks that a child patch in it would run.
+type APIModuleImpactProject struct {
+	ProjectID     *string       `json:"project_id"`
+	Identifier    *string       `json:"identifier"`
+	ModuleName    *string       `json:"module_name"`
+	VariantsTasks []VariantTask `json:"variants_tasks"`
+	// ChildPatchID is the child patch created in the project, if the child
+	// patches were run.
+	ChildPatchID *string `json:"child_patch_id,omitempty"`
+	// Error explains why the tasks in the project couldn't be determined.
+	Error *string `json:"error,omitempty"`
+}
+
+// BuildFromService converts the project that uses the module and the tasks
+// that a child patch in it would run.
+func (p *APIModuleImpactProject) BuildFromService(dependent model.ModuleDependent, variantsTasks []patch.VariantTasks) {
+	p.ProjectID = utility.ToStringPtr(dependent.ProjectID)
+	p.Identifier = utility.ToStringPtr(dependent.Identifier)
+	p.ModuleName = utility.ToStringPtr(dependent.ModuleName)
+	p.VariantsTasks = []VariantTask{}
+	for _, vt := range variantsTasks {
+		tasks := make([]*string, 0, len(vt.Tasks))
+		for _, t := range vt.Tasks {
+			tasks = append(tasks, utility.ToStringPtr(t))
+		}
+		p.VariantsTasks = append(p.VariantsTasks, VariantTask{
+			Name:  utility.ToStringPtr(vt.Variant),
+			Tasks: tasks,
+		})
+	}
+}
@@ -39,6 +39,8 @@ type APIPatch struct {
 	GithubPatchData         githubPatch          `json:"github_patch_data,omitempty"`
 	ModuleCodeChanges       []APIModulePatch     `json:"module_code_changes"`
 	Parameters              []APIParameter       `json:"parameters"`
+	DownstreamParameters    []APIParameter       `json:"downstream_parameters"`
+	Labels                  []APILabel           `json:"labels"`
 	PatchedParserProject    *string              `json:"patched_config"`
 	CanEnqueueToCommitQueue bool                 `json:"can_enqueue_to_commit_queue"`
 	ChildPatches            []APIPatch           `json:"child_patches"`
@@ -87,6 +89,69 @@ type APIModulePatch struct {
 type APIParameter struct {
 	Key   *string `json:"key"`
 	Value *string `json:"value"`
+	// SetByTask is the upstream task that set the parameter, if any.
+	SetByTask *string `json:"set_by_task,omitempty"`
+}
+
+func apiParametersFromService(params []patch.Parameter) []APIParameter {
+	apiParams := []APIParameter{}
+	for _, param := range params {
+		apiParam := APIParameter{
+			Key:   utility.ToStringPtr(param.Key),
+			Value: utility.ToStringPtr(param.Value),
+		}
+		if param.SetByTask != "" {
+			apiParam.SetByTask = utility.ToStringPtr(param.SetByTask)
+		}
+		apiParams = append(apiParams, apiParam)
+	}
+	return apiParams
+}
+
+func parametersToService(apiParams []APIParameter) []patch.Parameter {
+	if len(apiParams) == 0 {
+		return nil
+	}
+	params := make([]patch.Parameter, 0, len(apiParams))
+	for _, apiParam := range apiParams {
+		params = append(params, patch.Parameter{
+			Key:       utility.FromStringPtr(apiParam.Key),
+			Value:     utility.FromStringPtr(apiParam.Value),
+			SetByTask: utility.FromStringPtr(apiParam.SetByTask),
+		})
+	}
+	return params
+}
+
+// APILabel is a key/value label on a version or patch.
+type APILabel struct {
+	Key   *string `json:"key"`
+	Value *string `json:"value"`
+}
+
+func apiLabelsFromService(labels []patch.Label) []APILabel {
+	apiLabels := []APILabel{}
+	for _, l := range labels {
+		apiLabels = append(apiLabels, APILabel{
+			Key:   utility.ToStringPtr(l.Key),
+			Value: utility.ToStringPtr(l.Value),
+		})
+	}
+	return apiLabels
+}
+
+func labelsToService(apiLabels []APILabel) []patch.Label {
+	if apiLabels == nil {
+		return nil
+	}
+	labels := []patch.Label{}
+	for _, l := range apiLabels {
+		labels = append(labels, patch.Label{
+			Key:   utility.FromStringPtr(l.Key),
+			Value: utility.FromStringPtr(l.Value),
+		})
+	}
+	return labels
 }
 
 // ToService converts a service layer parameter using the data from APIParameter
@@ -94,6 +159,7 @@ func (p *APIParameter) ToService() patch.Parameter {
 	res := patch.Parameter{}
 	res.Key = utility.FromStringPtr(p.Key)
 	res.Value = utility.FromStringPtr(p.Value)
+	res.SetByTask = utility.FromStringPtr(p.SetByTask)
 	return res
 }
 
@@ -144,14 +210,10 @@ func (apiPatch *APIPatch) BuildFromService(h interface{}) error {
 	apiPatch.Requester = utility.ToStringPtr(v.GetRequester())
 
 	if v.Parameters != nil {
-		apiPatch.Parameters = []APIParameter{}
-		for _, param := range v.Parameters {
-			apiPatch.Parameters = append(apiPatch.Parameters, APIParameter{
-				Key:   utility.ToStringPtr(param.Key),
-				Value: utility.ToStringPtr(param.Value),
-			})
-		}
+		apiPatch.Parameters = apiParametersFromService(v.Parameters)
 	}
+	apiPatch.DownstreamParameters = apiParametersFromService(v.Triggers.DownstreamParameters)
+	apiPatch.Labels = apiLabelsFromService(v.Labels)
 
 	projectIdentifier := v.Project
 	if v.Project != "" {
@@ -311,11 +373,13 @@ func (apiPatch *APIPatch) ToService() (interface{}, error) {
 		res.Parameters = []patch.Parameter{}
 		for _, param := range apiPatch.Parameters {
 			res.Parameters = append(res.Parameters, patch.Parameter{
-				Key:   utility.FromStringPtr(param.Key),
-				Value: utility.FromStringPtr(param.Value),
+				Key:       utility.FromStringPtr(param.Key),
+				Value:     utility.FromStringPtr(param.Value),
+				SetByTask: utility.FromStringPtr(param.SetByTask),
 			})
 		}
 	}
+	res.Labels = labelsToService(apiPatch.Labels)
 
 	i, err := apiPatch.GithubPatchData.ToService()
 	catcher.Add(err)
@@ -10,17 +10,18 @@ import (
 
 // APICreatePod is the model to create a new pod.
 type APICreatePod struct {
-	Name           *string              `json:"name"`
-	Memory         *int                 `json:"memory"`
-	CPU            *int                 `json:"cpu"`
-	Image          *string              `json:"image"`
-	RepoUsername   *string              `json:"repo_username"`
-	RepoPassword   *string              `json:"repo_password"`
-	OS             APIPodOS             `json:"os"`
-	Arch           APIPodArch           `json:"arch"`
-	WindowsVersion APIPodWindowsVersion `json:"windows_version"`
-	Secret         *string              `json:"secret"`
-	WorkingDir     *string              `json:"working_dir"`
+	Name                    *string              `json:"name"`
+	Memory                  *int                 `json:"memory"`
+	CPU                     *int                 `json:"cpu"`
+	Image                   *string              `json:"image"`
+	RepoUsername            *string              `json:"repo_username"`
+	RepoPassword            *string              `json:"repo_password"`
+	RepoCredsExternalSecret *string              `json:"repo_creds_external_secret"`
+	OS                      APIPodOS             `json:"os"`
+	Arch                    APIPodArch           `json:"arch"`
+	WindowsVersion          APIPodWindowsVersion `json:"windows_version"`
+	Secret                  *string              `json:"secret"`
+	WorkingDir              *string              `json:"working_dir"`
 }
 
 type APICreatePodResponse struct {
@@ -43,16 +44,17 @@ func (p *APICreatePod) ToService() (*pod.Pod, error) {
 	}
 
 	return pod.NewTaskIntentPod(pod.TaskIntentPodOptions{
-		Secret:         utility.FromStringPtr(p.Secret),
-		CPU:            utility.FromIntPtr(p.CPU),
-		MemoryMB:       utility.FromIntPtr(p.Memory),
-		OS:             *os,
-		Arch:           *arch,
-		WindowsVersion: *winVer,
-		Image:          utility.FromStringPtr(p.Image),
-		WorkingDir:     utility.FromStringPtr(p.WorkingDir),
-		RepoUsername:   utility.FromStringPtr(p.RepoUsername),
-		RepoPassword:   utility.FromStringPtr(p.RepoPassword),
+		Secret:                  utility.FromStringPtr(p.Secret),
+		CPU:                     utility.FromIntPtr(p.CPU),
+		MemoryMB:                utility.FromIntPtr(p.Memory),
+		OS:                      *os,
+		Arch:                    *arch,
+		WindowsVersion:          *winVer,
+		Image:                   utility.FromStringPtr(p.Image),
+		WorkingDir:              utility.FromStringPtr(p.WorkingDir),
+		RepoUsername:            utility.FromStringPtr(p.RepoUsername),
+		RepoPassword:            utility.FromStringPtr(p.RepoPassword),
+		RepoCredsExternalSecret: utility.FromStringPtr(p.RepoCredsExternalSecret),
 	})
 }
 
@@ -195,17 +197,18 @@ func (s APIPodStatus) ToService() (*pod.Status, error) {
 // APIPodTaskContainerCreationOptions represents options to apply to the task's
 // container when creating a pod.
 type APIPodTaskContainerCreationOptions struct {
-	Image          *string                 `json:"image,omitempty"`
-	RepoUsername   *string                 `json:"repo_username,omitempty"`
-	RepoPassword   *string                 `json:"repo_password,omitempty"`
-	MemoryMB       *int                    `json:"memory_mb,omitempty"`
-	CPU            *int                    `json:"cpu,omitempty"`
-	OS             APIPodOS                `json:"os,omitempty"`
-	Arch           APIPodArch              `json:"arch,omitempty"`
-	WindowsVersion APIPodWindowsVersion    `json:"windows_version,omitempty"`
-	EnvVars        map[string]string       `json:"env_vars,omitempty"`
-	EnvSecrets     map[string]APIPodSecret `json:"env_secrets,omitempty"`
-	WorkingDir     *string                 `json:"working_dir,omitempty"`
+	Image                   *string                 `json:"image,omitempty"`
+	RepoUsername            *string                 `json:"repo_username,omitempty"`
+	RepoPassword            *string                 `json:"repo_password,omitempty"`
+	RepoCredsExternalSecret *string                 `json:"repo_creds_external_secret,omitempty"`
+	MemoryMB                *int                    `json:"memory_mb,omitempty"`
+	CPU                     *int                    `json:"cpu,omitempty"`
+	OS                      APIPodOS                `json:"os,omitempty"`
+	Arch                    APIPodArch              `json:"arch,omitempty"`
+	WindowsVersion          APIPodWindowsVersion    `json:"windows_version,omitempty"`
+	EnvVars                 map[string]string       `json:"env_vars,omitempty"`
+	EnvSecrets              map[string]APIPodSecret `json:"env_secrets,omitempty"`
+	WorkingDir              *string                 `json:"working_dir,omitempty"`
 }
 
 // BuildFromService converts service-layer task container creation options into
@@ -214,6 +217,7 @@ func (o *APIPodTaskContainerCreationOptions) BuildFromService(opts pod.TaskConta
 	o.Image = utility.ToStringPtr(opts.Image)
 	o.RepoUsername = utility.ToStringPtr(opts.RepoUsername)
 	o.RepoPassword = utility.ToStringPtr(opts.RepoPassword)
+	o.RepoCredsExternalSecret = utility.ToStringPtr(opts.RepoCredsExternalSecret)
 	o.MemoryMB = utility.ToIntPtr(opts.MemoryMB)
 	o.CPU = utility.ToIntPtr(opts.CPU)
 	o.OS.BuildFromService(&opts.OS)
@@ -250,17 +254,18 @@ func (o *APIPodTaskContainerCreationOptions) ToService() (*pod.TaskContainerCrea
 		envSecrets[name] = secret.ToService()
 	}
 	return &pod.TaskContainerCreationOptions{
-		Image:          utility.FromStringPtr(o.Image),
-		RepoUsername:   utility.FromStringPtr(o.RepoUsername),
-		RepoPassword:   utility.FromStringPtr(o.RepoPassword),
-		MemoryMB:       utility.FromIntPtr(o.MemoryMB),
-		CPU:            utility.FromIntPtr(o.CPU),
-		OS:             *os,
-		Arch:           *arch,
-		WindowsVersion: *winVer,
-		EnvVars:        o.EnvVars,
-		EnvSecrets:     envSecrets,
-		WorkingDir:     utility.FromStringPtr(o.WorkingDir),
+		Image:                   utility.FromStringPtr(o.Image),
+		RepoUsername:            utility.FromStringPtr(o.RepoUsername),
+		RepoPassword:            utility.FromStringPtr(o.RepoPassword),
+		RepoCredsExternalSecret: utility.FromStringPtr(o.RepoCredsExternalSecret),
+		MemoryMB:                utility.FromIntPtr(o.MemoryMB),
+		CPU:                     utility.FromIntPtr(o.CPU),
+		OS:                      *os,
+		Arch:                    *arch,
+		WindowsVersion:          *winVer,
+		EnvVars:                 o.EnvVars,
+		EnvSecrets:              envSecrets,
+		WorkingDir:              utility.FromStringPtr(o.WorkingDir),
 	}, nil
 }
 
@@ -26,15 +26,19 @@ type UIProjectFields struct {
 }
 
 type APITriggerDefinition struct {
-	Project           *string `json:"project"`
-	Level             *string `json:"level"` //build or task
-	DefinitionID      *string `json:"definition_id"`
-	BuildVariantRegex *string `json:"variant_regex"`
-	TaskRegex         *string `json:"task_regex"`
-	Status            *string `json:"status"`
-	DateCutoff        *int    `json:"date_cutoff"`
-	ConfigFile        *string `json:"config_file"`
-	Alias             *string `json:"alias"`
+	Project           *string           `json:"project"`
+	Level             *string           `json:"level"` //build or task
+	DefinitionID      *string           `json:"definition_id"`
+	BuildVariantRegex *string           `json:"variant_regex"`
+	TaskRegex         *string           `json:"task_regex"`
+	Status            *string           `json:"status"`
+	DateCutoff        *int              `json:"date_cutoff"`
+	ConfigFile        *string           `json:"config_file"`
+	Alias             *string           `json:"alias"`
+	Labels            map[string]string `json:"labels"`
+	// Parameters are passed to the downstream versions. Values can reference
+	// the upstream version and task or build with expansions.
+	Parameters []APIParameter `json:"parameters"`
 }
 
 func (t *APITriggerDefinition) ToService() (interface{}, error) {
@@ -48,6 +52,8 @@ func (t *APITriggerDefinition) ToService() (interface{}, error) {
 		ConfigFile:        utility.FromStringPtr(t.ConfigFile),
 		Alias:             utility.FromStringPtr(t.Alias),
 		DateCutoff:        t.DateCutoff,
+		Labels:            t.Labels,
+		Parameters:        parametersToService(t.Parameters),
 	}, nil
 }
 
@@ -70,6 +76,8 @@ func (t *APITriggerDefinition) BuildFromService(h interface{}) error {
 	t.ConfigFile = utility.ToStringPtr(triggerDef.ConfigFile)
 	t.Alias = utility.ToStringPtr(triggerDef.Alias)
 	t.DateCutoff = triggerDef.DateCutoff
+	t.Labels = triggerDef.Labels
+	t.Parameters = apiParametersFromService(triggerDef.Parameters)
 	return nil
 }
 
@@ -176,6 +184,8 @@ type APIPeriodicBuildDefinition struct {
 	Alias         *string    `json:"alias,omitempty"`
 	Message       *string    `json:"message,omitempty"`
 	NextRunTime   *time.Time `json:"next_run_time,omitempty"`
+	Cron          *string    `json:"cron,omitempty"`
+	Timezone      *string    `json:"timezone,omitempty"`
 }
 
 type APICommitQueueParams struct {
@@ -193,6 +203,8 @@ func (bd *APIPeriodicBuildDefinition) ToService() (interface{}, error) {
 	buildDef.Alias = utility.FromStringPtr(bd.Alias)
 	buildDef.Message = utility.FromStringPtr(bd.Message)
 	buildDef.NextRunTime = utility.FromTimePtr(bd.NextRunTime)
+	buildDef.Cron = utility.FromStringPtr(bd.Cron)
+	buildDef.Timezone = utility.FromStringPtr(bd.Timezone)
 	return buildDef, nil
 }
 
@@ -212,6 +224,8 @@ func (bd *APIPeriodicBuildDefinition) BuildFromService(h interface{}) error {
 	bd.Alias = utility.ToStringPtr(params.Alias)
 	bd.Message = utility.ToStringPtr(params.Message)
 	bd.NextRunTime = utility.ToTimePtr(params.NextRunTime)
+	bd.Cron = utility.ToStringPtr(params.Cron)
+	bd.Timezone = utility.ToStringPtr(params.Timezone)
 	return nil
 }
 
@@ -362,25 +376,277 @@ func (opts *APITaskSyncOptions) ToService() (interface{}, error) {
 	}, nil
 }
 
+// APIDownstreamParameterSchema restricts the parameters tasks can pass to
+// downstream patches.
+type APIDownstreamParameterSchema struct {
+	Parameters      []APIDownstreamParameterDefinition `json:"parameters"`
+	CollisionPolicy *string                            `json:"collision_policy"`
+}
+
+type APIDownstreamParameterDefinition struct {
+	Key  *string `json:"key"`
+	Type *string `json:"type"`
+}
+
+// BuildFromService converts from a service level downstream parameter schema.
+func (s *APIDownstreamParameterSchema) BuildFromService(schema patch.DownstreamParameterSchema) {
+	s.CollisionPolicy = utility.ToStringPtr(schema.CollisionPolicy)
+	s.Parameters = []APIDownstreamParameterDefinition{}
+	for _, def := range schema.Parameters {
+		s.Parameters = append(s.Parameters, APIDownstreamParameterDefinition{
+			Key:  utility.ToStringPtr(def.Key),
+			Type: utility.ToStringPtr(def.Type),
+		})
+	}
+}
+
+// ToService returns a service level downstream parameter schema.
+func (s *APIDownstreamParameterSchema) ToService() patch.DownstreamParameterSchema {
+	schema := patch.DownstreamParameterSchema{
+		CollisionPolicy: utility.FromStringPtr(s.CollisionPolicy),
+	}
+	for _, def := range s.Parameters {
+		schema.Parameters = append(schema.Parameters, patch.DownstreamParameterDefinition{
+			Key:  utility.FromStringPtr(def.Key),
+			Type: utility.FromStringPtr(def.Type),
+		})
+	}
+	return schema
+}
+
+// APILogRetentionPolicy is how long task and test logs are kept, in days.
+type APILogRetentionPolicy struct {
+	TaskLogDays       int `json:"task_log_days"`
+	FailedTaskLogDays int `json:"failed_task_log_days"`
+	SystemLogDays     int `json:"system_log_days"`
+}
+
+// BuildFromService converts from a service level log retention policy to an
+// API log retention policy.
+func (p *APILogRetentionPolicy) BuildFromService(policy model.LogRetentionPolicy) {
+	p.TaskLogDays = policy.TaskLogDays
+	p.FailedTaskLogDays = policy.FailedTaskLogDays
+	p.SystemLogDays = policy.SystemLogDays
+}
+
+// ToService returns a service level log retention policy.
+func (p *APILogRetentionPolicy) ToService() model.LogRetentionPolicy {
+	return model.LogRetentionPolicy{
+		TaskLogDays:       p.TaskLogDays,
+		FailedTaskLogDays: p.FailedTaskLogDays,
+		SystemLogDays:     p.SystemLogDays,
+	}
+}
+
+// APIProjectQuotas limit how much work a project can have in flight at once.
+type APIProjectQuotas struct {
+	MaxUnfinishedVersions int        `json:"max_unfinished_versions"`
+	MaxScheduledTasks     int        `json:"max_scheduled_tasks"`
+	OverrideUntil         *time.Time `json:"override_until"`
+}
+
+// BuildFromService converts from service level project quotas.
+func (q *APIProjectQuotas) BuildFromService(quotas model.ProjectQuotas) {
+	q.MaxUnfinishedVersions = quotas.MaxUnfinishedVersions
+	q.MaxScheduledTasks = quotas.MaxScheduledTasks
+	q.OverrideUntil = ToTimePtr(quotas.OverrideUntil)
+}
+
+// ToService returns service level project quotas.
+func (q *APIProjectQuotas) ToService() model.ProjectQuotas {
+	quotas := model.ProjectQuotas{
+		MaxUnfinishedVersions: q.MaxUnfinishedVersions,
+		MaxScheduledTasks:     q.MaxScheduledTasks,
+	}
+	if q.OverrideUntil != nil {
+		quotas.OverrideUntil = *q.OverrideUntil
+	}
+	return quotas
+}
+
+// APIPatchPolicy restricts the patches that can be submitted to a project.
+type APIPatchPolicy struct {
+	MaxDiffBytes         int                  `json:"max_diff_bytes"`
+	BannedFiles          []APIPatchFilePolicy `json:"banned_files"`
+	MaxBaseCommitsBehind int                  `json:"max_base_commits_behind"`
+}
+
+// APIPatchFilePolicy matches files that a patch cannot change.
+type APIPatchFilePolicy struct {
+	Pattern  *string `json:"pattern"`
+	MaxBytes int     `json:"max_bytes"`
+}
+
+// BuildFromService converts from a service level patch policy.
+func (p *APIPatchPolicy) BuildFromService(policy model.PatchPolicy) {
+	p.MaxDiffBytes = policy.MaxDiffBytes
+	p.MaxBaseCommitsBehind = policy.MaxBaseCommitsBehind
+	p.BannedFiles = nil
+	for _, f := range policy.BannedFiles {
+		p.BannedFiles = append(p.BannedFiles, APIPatchFilePolicy{
+			Pattern:  utility.ToStringPtr(f.Pattern),
+			MaxBytes: f.MaxBytes,
+		})
+	}
+}
+
+// ToService returns a service level patch policy.
+func (p *APIPatchPolicy) ToService() model.PatchPolicy {
+	policy := model.PatchPolicy{
+		MaxDiffBytes:         p.MaxDiffBytes,
+		MaxBaseCommitsBehind: p.MaxBaseCommitsBehind,
+	}
+	for _, f := range p.BannedFiles {
+		policy.BannedFiles = append(policy.BannedFiles, model.PatchFilePolicy{
+			Pattern:  utility.FromStringPtr(f.Pattern),
+			MaxBytes: f.MaxBytes,
+		})
+	}
+	return policy
+}
+
+// APIPriorityAgingSettings control how a project's tasks gain priority while
+// they wait to be dispatched.
+type APIPriorityAgingSettings struct {
+	PriorityPerHour         int64 `json:"priority_per_hour"`
+	MaxBoost                int64 `json:"max_boost"`
+	StarvationThresholdMins int   `json:"starvation_threshold_mins"`
+}
+
+// BuildFromService converts from service level priority aging settings.
+func (s *APIPriorityAgingSettings) BuildFromService(settings model.PriorityAgingSettings) {
+	s.PriorityPerHour = settings.PriorityPerHour
+	s.MaxBoost = settings.MaxBoost
+	s.StarvationThresholdMins = settings.StarvationThresholdMins
+}
+
+// ToService returns service level priority aging settings.
+func (s *APIPriorityAgingSettings) ToService() model.PriorityAgingSettings {
+	return model.PriorityAgingSettings{
+		PriorityPerHour:         s.PriorityPerHour,
+		MaxBoost:                s.MaxBoost,
+		StarvationThresholdMins: s.StarvationThresholdMins,
+	}
+}
+
+// APIGithubVariantCheckSettings control whether a project posts a GitHub
+// check for each build variant.
+type APIGithubVariantCheckSettings struct {
+	Enabled      bool    `json:"enabled"`
+	NameTemplate *string `json:"name_template"`
+}
+
+// BuildFromService converts from service level variant check settings.
+func (s *APIGithubVariantCheckSettings) BuildFromService(settings model.GithubVariantCheckSettings) {
+	s.Enabled = settings.Enabled
+	s.NameTemplate = utility.ToStringPtr(settings.NameTemplate)
+}
+
+// ToService returns service level variant check settings.
+func (s *APIGithubVariantCheckSettings) ToService() model.GithubVariantCheckSettings {
+	return model.GithubVariantCheckSettings{
+		Enabled:      s.Enabled,
+		NameTemplate: utility.FromStringPtr(s.NameTemplate),
+	}
+}
+
+// APIStalePatchPolicy controls how a project's stale patches are cleaned up.
+type APIStalePatchPolicy struct {
+	UnfinalizedAgeHours int `json:"unfinalized_age_hours"`
+	UnfinishedAgeHours  int `json:"unfinished_age_hours"`
+	WarningHours        int `json:"warning_hours"`
+}
+
+// BuildFromService converts from a service level stale patch policy.
+func (s *APIStalePatchPolicy) BuildFromService(policy model.StalePatchPolicy) {
+	s.UnfinalizedAgeHours = policy.UnfinalizedAgeHours
+	s.UnfinishedAgeHours = policy.UnfinishedAgeHours
+	s.WarningHours = policy.WarningHours
+}
+
+// ToService returns a service level stale patch policy.
+func (s *APIStalePatchPolicy) ToService() model.StalePatchPolicy {
+	return model.StalePatchPolicy{
+		UnfinalizedAgeHours: s.UnfinalizedAgeHours,
+		UnfinishedAgeHours:  s.UnfinishedAgeHours,
+		WarningHours:        s.WarningHours,
+	}
+}
+
+// APIVariantActivationHook is an external service that decides whether
+// variants can be activated.
+type APIVariantActivationHook struct {
+	BuildVariants []string `json:"build_variants"`
+	URL           *string  `json:"url"`
+	TimeoutSecs   int      `json:"timeout_secs"`
+}
+
+// BuildFromService converts from a service level activation hook.
+func (h *APIVariantActivationHook) BuildFromService(hook model.VariantActivationHook) {
+	h.BuildVariants = hook.BuildVariants
+	h.URL = utility.ToStringPtr(hook.URL)
+	h.TimeoutSecs = hook.TimeoutSecs
+}
+
+// ToService returns a service level activation hook.
+func (h *APIVariantActivationHook) ToService() model.VariantActivationHook {
+	return model.VariantActivationHook{
+		BuildVariants: h.BuildVariants,
+		URL:           utility.FromStringPtr(h.URL),
+		TimeoutSecs:   h.TimeoutSecs,
+	}
+}
+
+// APIProjectLogRetention describes the log retention that applies to a
+// project and how much storage its logs use.
+type APIProjectLogRetention struct {
+	// Configured is the policy set on the project, where zero values fall
+	// back to defaults.
+	Configured APILogRetentionPolicy `json:"configured"`
+	// Effective is the policy that is enforced for the project.
+	Effective       APILogRetentionPolicy `json:"effective"`
+	StorageEstimate APILogStorageEstimate `json:"storage_estimate"`
+}
+
+// APILogStorageEstimate is an estimate of how much storage a project's logs
+// use, extrapolated from a sample of its recent tasks.
+type APILogStorageEstimate struct {
+	TasksInWindow int   `json:"tasks_in_window"`
+	SampledTasks  int   `json:"sampled_tasks"`
+	TaskLogBytes  int64 `json:"task_log_bytes"`
+	TestLogBytes  int64 `json:"test_log_bytes"`
+}
+
+// BuildFromService converts from a service level log storage estimate.
+func (e *APILogStorageEstimate) BuildFromService(estimate model.LogStorageEstimate) {
+	e.TasksInWindow = estimate.TasksInWindow
+	e.SampledTasks = estimate.SampledTasks
+	e.TaskLogBytes = estimate.TaskLogBytes
+	e.TestLogBytes = estimate.TestLogBytes
+}
+
 type APIWorkstationConfig struct {
 	SetupCommands []APIWorkstationSetupCommand `bson:"setup_commands" json:"setup_commands"`
 	GitClone      *bool                        `bson:"git_clone" json:"git_clone"`
 }
 
 type APIContainerCredential struct {
-	Username *string `bson:"username" json:"username"`
-	Password *string `bson:"password" json:"password"`
+	Username       *string `bson:"username" json:"username"`
+	Password       *string `bson:"password" json:"password"`
+	ExternalSecret *string `bson:"external_secret" json:"external_secret"`
 }
 
 func (cr *APIContainerCredential) BuildFromService(h model.ContainerCredential) {
 	cr.Username = utility.ToStringPtr(h.Username)
 	cr.Password = utility.ToStringPtr(h.Password)
+	cr.ExternalSecret = utility.ToStringPtr(h.ExternalSecret)
 }
 
 func (cr *APIContainerCredential) ToService() model.ContainerCredential {
 	return model.ContainerCredential{
-		Username: utility.FromStringPtr(cr.Username),
-		Password: utility.FromStringPtr(cr.Password),
+		Username:       utility.FromStringPtr(cr.Username),
+		Password:       utility.FromStringPtr(cr.Password),
+		ExternalSecret: utility.FromStringPtr(cr.ExternalSecret),
 	}
 }
 
@@ -491,16 +757,31 @@ type APIProjectRef struct {
 	ManualPRTestingEnabled      *bool                     `json:"manual_pr_testing_enabled"`
 	GitTagVersionsEnabled       *bool                     `json:"git_tag_versions_enabled"`
 	GithubChecksEnabled         *bool                     `json:"github_checks_enabled"`
+	GithubMergeQueueEnabled     *bool                     `json:"github_merge_queue_enabled"`
 	CedarTestResultsEnabled     *bool                     `json:"cedar_test_results_enabled"`
 	UseRepoSettings             *bool                     `json:"use_repo_settings"`
 	RepoRefId                   *string                   `json:"repo_ref_id"`
 	DefaultLogger               *string                   `json:"default_logger"`
 	CommitQueue                 APICommitQueueParams      `json:"commit_queue"`
 	TaskSync                    APITaskSyncOptions        `json:"task_sync"`
+	LogRetention                APILogRetentionPolicy     `json:"log_retention"`
+	ArchiveVersionsAfterDays    *int                      `json:"archive_versions_after_days"`
+	TaskOrder                   APITaskOrder              `json:"task_order"`
+	MaxValidationWarnings       *int                      `json:"max_validation_warnings"`
+	ValidationSeverityOverrides map[string]string         `json:"validation_severity_overrides"`
+	Quotas                      APIProjectQuotas          `json:"quotas"`
+	PatchPolicy                 APIPatchPolicy            `json:"patch_policy"`
+	CodeOwnersRouting           *bool                     `json:"code_owners_routing"`
+	AutoBisect                  *bool                     `json:"auto_bisect"`
+	EventSourcedStatusRollup    *bool                     `json:"event_sourced_status_rollup"`
+	FailureLogIndexing          *bool                     `json:"failure_log_indexing"`
+	SecretsScanning             *bool                     `json:"secrets_scanning"`
+	PublicStatus                *bool                     `json:"public_status"`
 	TaskAnnotationSettings      APITaskAnnotationSettings `json:"task_annotation_settings"`
 	BuildBaronSettings          APIBuildBaronSettings     `json:"build_baron_settings"`
 	PerfEnabled                 *bool                     `json:"perf_enabled"`
 	Hidden                      *bool                     `json:"hidden"`
+	Archived                    *bool                     `json:"archived"`
 	PatchingDisabled            *bool                     `json:"patching_disabled"`
 	RepotrackerDisabled         *bool                     `json:"repotracker_disabled"`
 	DispatchingDisabled         *bool                     `json:"dispatching_disabled"`
@@ -515,17 +796,43 @@ type APIProjectRef struct {
 	DeleteGitTagAuthorizedTeams []*string                 `json:"delete_git_tag_authorized_teams,omitempty" bson:"delete_git_tag_authorized_teams,omitempty"`
 	NotifyOnBuildFailure        *bool                     `json:"notify_on_failure"`
 	Restricted                  *bool                     `json:"restricted"`
+	RequireSettingsApproval     *bool                     `json:"require_settings_approval"`
 	Revision                    *string                   `json:"revision"`
 
 	Triggers             []APITriggerDefinition       `json:"triggers"`
 	GithubTriggerAliases []*string                    `json:"github_trigger_aliases"`
 	PatchTriggerAliases  []APIPatchTriggerDefinition  `json:"patch_trigger_aliases"`
+	DownstreamParams     APIDownstreamParameterSchema `json:"downstream_parameter_schema"`
 	Aliases              []APIProjectAlias            `json:"aliases"`
 	Variables            APIProjectVars               `json:"variables"`
 	WorkstationConfig    APIWorkstationConfig         `json:"workstation_config"`
 	Subscriptions        []APISubscription            `json:"subscriptions"`
 	DeleteSubscriptions  []*string                    `json:"delete_subscriptions,omitempty"`
 	PeriodicBuilds       []APIPeriodicBuildDefinition `json:"periodic_builds,omitempty"`
+
+	VariantActivationHooks []APIVariantActivationHook    `json:"variant_activation_hooks"`
+	PriorityAging          APIPriorityAgingSettings      `json:"priority_aging"`
+	StuckTaskPolicy        *string                       `json:"stuck_task_policy"`
+	WatchedPaths           []*string                     `json:"watched_paths"`
+	WatchedPathsPolicy     *string                       `json:"watched_paths_policy"`
+	OverridableExpansions  []*string                     `json:"overridable_expansions"`
+	GithubVariantChecks    APIGithubVariantCheckSettings `json:"github_variant_checks"`
+	StalePatchPolicy       APIStalePatchPolicy           `json:"stale_patch_policy"`
+
+	// VariantActivationHookSecret is generated by Evergreen, so it's ignored
+	// when converting to the service model.
+	VariantActivationHookSecret *string `json:"variant_activation_hook_secret"`
+}
+
+func copySeverityOverrides(overrides map[string]string) map[string]string {
+	if overrides == nil {
+		return nil
+	}
+	out := make(map[string]string, len(overrides))
+	for rule, severity := range overrides {
+		out[rule] = severity
+	}
+	return out
 }
 
 // ToService returns a service layer ProjectRef using the data from APIProjectRef
@@ -579,6 +886,7 @@ func (p *APIProjectRef) ToService() (interface{}, error) {
 		Enabled:                 utility.BoolPtrCopy(p.Enabled),
 		Private:                 utility.BoolPtrCopy(p.Private),
 		Restricted:              utility.BoolPtrCopy(p.Restricted),
+		RequireSettingsApproval: utility.BoolPtrCopy(p.RequireSettingsApproval),
 		BatchTime:               p.BatchTime,
 		RemotePath:              utility.FromStringPtr(p.RemotePath),
 		Id:                      utility.FromStringPtr(p.Id),
@@ -591,15 +899,23 @@ func (p *APIProjectRef) ToService() (interface{}, error) {
 		ManualPRTestingEnabled:  utility.BoolPtrCopy(p.ManualPRTestingEnabled),
 		GitTagVersionsEnabled:   utility.BoolPtrCopy(p.GitTagVersionsEnabled),
 		GithubChecksEnabled:     utility.BoolPtrCopy(p.GithubChecksEnabled),
+		GithubMergeQueueEnabled: utility.BoolPtrCopy(p.GithubMergeQueueEnabled),
 		CedarTestResultsEnabled: utility.BoolPtrCopy(p.CedarTestResultsEnabled),
 		RepoRefId:               utility.FromStringPtr(p.RepoRefId),
 		CommitQueue:             commitQueue.(model.CommitQueueParams),
 		TaskSync:                taskSync,
+		LogRetention:            p.LogRetention.ToService(),
+		MaxValidationWarnings:   p.MaxValidationWarnings,
+		Quotas:                  p.Quotas.ToService(),
+		PatchPolicy:             p.PatchPolicy.ToService(),
+		CodeOwnersRouting:       utility.BoolPtrCopy(p.CodeOwnersRouting),
+		AutoBisect:              utility.BoolPtrCopy(p.AutoBisect),
 		WorkstationConfig:       workstationConfig,
 		BuildBaronSettings:      buildBaronConfig,
 		TaskAnnotationSettings:  taskAnnotationConfig,
 		PerfEnabled:             utility.BoolPtrCopy(p.PerfEnabled),
 		Hidden:                  utility.BoolPtrCopy(p.Hidden),
+		Archived:                utility.BoolPtrCopy(p.Archived),
 		PatchingDisabled:        utility.BoolPtrCopy(p.PatchingDisabled),
 		RepotrackerDisabled:     utility.BoolPtrCopy(p.RepotrackerDisabled),
 		DispatchingDisabled:     utility.BoolPtrCopy(p.DispatchingDisabled),
@@ -613,6 +929,26 @@ func (p *APIProjectRef) ToService() (interface{}, error) {
 		GitTagAuthorizedTeams:   utility.FromStringPtrSlice(p.GitTagAuthorizedTeams),
 		GithubTriggerAliases:    utility.FromStringPtrSlice(p.GithubTriggerAliases),
 	}
+	projectRef.EventSourcedStatusRollup = utility.BoolPtrCopy(p.EventSourcedStatusRollup)
+	projectRef.FailureLogIndexing = utility.BoolPtrCopy(p.FailureLogIndexing)
+	projectRef.SecretsScanning = utility.BoolPtrCopy(p.SecretsScanning)
+	projectRef.PublicStatus = utility.BoolPtrCopy(p.PublicStatus)
+	projectRef.ArchiveVersionsAfterDays = utility.FromIntPtr(p.ArchiveVersionsAfterDays)
+	projectRef.TaskOrder = p.TaskOrder.ToService()
+	projectRef.PriorityAging = p.PriorityAging.ToService()
+	projectRef.StuckTaskPolicy = utility.FromStringPtr(p.StuckTaskPolicy)
+	projectRef.WatchedPaths = utility.FromStringPtrSlice(p.WatchedPaths)
+	projectRef.WatchedPathsPolicy = utility.FromStringPtr(p.WatchedPathsPolicy)
+	projectRef.OverridableExpansions = utility.FromStringPtrSlice(p.OverridableExpansions)
+	projectRef.GithubVariantChecks = p.GithubVariantChecks.ToService()
+	projectRef.StalePatchPolicy = p.StalePatchPolicy.ToService()
+	projectRef.ValidationSeverityOverrides = copySeverityOverrides(p.ValidationSeverityOverrides)
+	if p.VariantActivationHooks != nil {
+		projectRef.VariantActivationHooks = []model.VariantActivationHook{}
+		for _, hook := range p.VariantActivationHooks {
+			projectRef.VariantActivationHooks = append(projectRef.VariantActivationHooks, hook.ToService())
+		}
+	}
 
 	// Copy triggers
 	if p.Triggers != nil {
@@ -663,6 +999,7 @@ func (p *APIProjectRef) ToService() (interface{}, error) {
 		}
 		projectRef.PatchTriggerAliases = patchTriggers
 	}
+	projectRef.DownstreamParameterSchema = p.DownstreamParams.ToService()
 	return &projectRef, nil
 }
 
@@ -684,6 +1021,7 @@ func (p *APIProjectRef) BuildFromService(h interface{}) error {
 	p.Enabled = utility.BoolPtrCopy(projectRef.Enabled)
 	p.Private = utility.BoolPtrCopy(projectRef.Private)
 	p.Restricted = utility.BoolPtrCopy(projectRef.Restricted)
+	p.RequireSettingsApproval = utility.BoolPtrCopy(projectRef.RequireSettingsApproval)
 	p.BatchTime = projectRef.BatchTime
 	p.RemotePath = utility.ToStringPtr(projectRef.RemotePath)
 	p.Id = utility.ToStringPtr(projectRef.Id)
@@ -696,11 +1034,13 @@ func (p *APIProjectRef) BuildFromService(h interface{}) error {
 	p.ManualPRTestingEnabled = utility.BoolPtrCopy(projectRef.ManualPRTestingEnabled)
 	p.GitTagVersionsEnabled = utility.BoolPtrCopy(projectRef.GitTagVersionsEnabled)
 	p.GithubChecksEnabled = utility.BoolPtrCopy(projectRef.GithubChecksEnabled)
+	p.GithubMergeQueueEnabled = utility.BoolPtrCopy(projectRef.GithubMergeQueueEnabled)
 	p.CedarTestResultsEnabled = utility.BoolPtrCopy(projectRef.CedarTestResultsEnabled)
 	p.UseRepoSettings = utility.ToBoolPtr(projectRef.UseRepoSettings())
 	p.RepoRefId = utility.ToStringPtr(projectRef.RepoRefId)
 	p.PerfEnabled = utility.BoolPtrCopy(projectRef.PerfEnabled)
 	p.Hidden = utility.BoolPtrCopy(projectRef.Hidden)
+	p.Archived = utility.BoolPtrCopy(projectRef.Archived)
 	p.PatchingDisabled = utility.BoolPtrCopy(projectRef.PatchingDisabled)
 	p.RepotrackerDisabled = utility.BoolPtrCopy(projectRef.RepotrackerDisabled)
 	p.DispatchingDisabled = utility.BoolPtrCopy(projectRef.DispatchingDisabled)
@@ -725,6 +1065,33 @@ func (p *APIProjectRef) BuildFromService(h interface{}) error {
 		return errors.Wrap(err, "converting task sync options to API model")
 	}
 	p.TaskSync = taskSync
+	p.LogRetention.BuildFromService(projectRef.LogRetention)
+	p.ArchiveVersionsAfterDays = utility.ToIntPtr(projectRef.ArchiveVersionsAfterDays)
+	p.TaskOrder.BuildFromService(projectRef.TaskOrder)
+	p.MaxValidationWarnings = projectRef.MaxValidationWarnings
+	p.ValidationSeverityOverrides = copySeverityOverrides(projectRef.ValidationSeverityOverrides)
+	p.Quotas.BuildFromService(projectRef.Quotas)
+	p.PatchPolicy.BuildFromService(projectRef.PatchPolicy)
+	p.CodeOwnersRouting = utility.BoolPtrCopy(projectRef.CodeOwnersRouting)
+	p.AutoBisect = utility.BoolPtrCopy(projectRef.AutoBisect)
+	p.EventSourcedStatusRollup = utility.BoolPtrCopy(projectRef.EventSourcedStatusRollup)
+	p.FailureLogIndexing = utility.BoolPtrCopy(projectRef.FailureLogIndexing)
+	p.SecretsScanning = utility.BoolPtrCopy(projectRef.SecretsScanning)
+	p.PublicStatus = utility.BoolPtrCopy(projectRef.PublicStatus)
+	p.PriorityAging.BuildFromService(projectRef.PriorityAging)
+	p.StuckTaskPolicy = utility.ToStringPtr(projectRef.StuckTaskPolicy)
+	p.WatchedPaths = utility.ToStringPtrSlice(projectRef.WatchedPaths)
+	p.WatchedPathsPolicy = utility.ToStringPtr(projectRef.WatchedPathsPolicy)
+	p.OverridableExpansions = utility.ToStringPtrSlice(projectRef.OverridableExpansions)
+	p.GithubVariantChecks.BuildFromService(projectRef.GithubVariantChecks)
+	p.StalePatchPolicy.BuildFromService(projectRef.StalePatchPolicy)
+	p.VariantActivationHookSecret = utility.ToStringPtr(projectRef.VariantActivationHookSecret)
+	p.VariantActivationHooks = nil
+	for _, hook := range projectRef.VariantActivationHooks {
+		apiHook := APIVariantActivationHook{}
+		apiHook.BuildFromService(hook)
+		p.VariantActivationHooks = append(p.VariantActivationHooks, apiHook)
+	}
 
 	workstationConfig := APIWorkstationConfig{}
 	if err := workstationConfig.BuildFromService(projectRef.WorkstationConfig); err != nil {
@@ -781,6 +1148,7 @@ func (p *APIProjectRef) BuildFromService(h interface{}) error {
 		}
 		p.PatchTriggerAliases = patchTriggers
 	}
+	p.DownstreamParams.BuildFromService(projectRef.DownstreamParameterSchema)
 
 	return nil
 }
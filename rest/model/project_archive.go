@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIProjectArchive describes when and why a project was archived.
+type APIProjectArchive struct {
+	ProjectID  *string    `json:"project_id"`
+	ArchivedAt *time.Time `json:"archived_at"`
+	ArchivedBy *string    `json:"archived_by"`
+	Reason     *string    `json:"reason,omitempty"`
+}
+
+// BuildFromService converts from a service level project archive.
+func (a *APIProjectArchive) BuildFromService(archive model.ProjectArchive) {
+	a.ProjectID = utility.ToStringPtr(archive.ProjectID)
+	a.ArchivedAt = ToTimePtr(archive.ArchivedAt)
+	a.ArchivedBy = utility.ToStringPtr(archive.ArchivedBy)
+	if archive.Reason != "" {
+		a.Reason = utility.ToStringPtr(archive.Reason)
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APIProjectChangeRequest is a pending or reviewed change to the settings of
+// a project that requires approval.
+type APIProjectChangeRequest struct {
+	Id            *string       `json:"id"`
+	ProjectId     *string       `json:"project_id"`
+	Author        *string       `json:"author"`
+	Status        *string       `json:"status"`
+	CreateTime    *time.Time    `json:"create_time"`
+	Reviewer      *string       `json:"reviewer,omitempty"`
+	ReviewTime    *time.Time    `json:"review_time,omitempty"`
+	Error         *string       `json:"error,omitempty"`
+	ChangedFields []string      `json:"changed_fields"`
+	Before        APIProjectRef `json:"before"`
+	After         APIProjectRef `json:"after"`
+}
+
+// BuildFromService converts from a service level change request to an API
+// change request.
+func (r *APIProjectChangeRequest) BuildFromService(cr model.ProjectChangeRequest) error {
+	r.Id = utility.ToStringPtr(cr.Id)
+	r.ProjectId = utility.ToStringPtr(cr.ProjectId)
+	r.Author = utility.ToStringPtr(cr.Author)
+	r.Status = utility.ToStringPtr(cr.Status)
+	r.CreateTime = ToTimePtr(cr.CreateTime)
+	if cr.Reviewer != "" {
+		r.Reviewer = utility.ToStringPtr(cr.Reviewer)
+		r.ReviewTime = ToTimePtr(cr.ReviewTime)
+	}
+	if cr.Error != "" {
+		r.Error = utility.ToStringPtr(cr.Error)
+	}
+	r.ChangedFields = cr.ChangedFields
+	if err := r.Before.BuildFromService(cr.Before); err != nil {
+		return errors.Wrap(err, "converting original project ref to API model")
+	}
+	if err := r.After.BuildFromService(cr.After); err != nil {
+		return errors.Wrap(err, "converting requested project ref to API model")
+	}
+	return nil
+}
@@ -40,11 +40,20 @@ type APIProjectVars struct {
 	AdminOnlyVars map[string]bool   `json:"admin_only_vars"`
 	VarsToDelete  []string          `json:"vars_to_delete,omitempty"`
 
+	// RestrictedVars limits variables to the tasks and build variants that
+	// match the restriction.
+	RestrictedVars map[string]APIProjectVarRestriction `json:"restricted_vars,omitempty"`
+
 	// to use for the UI
 	PrivateVarsList   []string `json:"-"`
 	AdminOnlyVarsList []string `json:"-"`
 }
 
+type APIProjectVarRestriction struct {
+	TaskRegex    *string `json:"task_regex"`
+	VariantRegex *string `json:"variant_regex"`
+}
+
 type APIProjectAlias struct {
 	Alias       *string   `json:"alias"`
 	GitTag      *string   `json:"git_tag"`
@@ -53,6 +62,7 @@ type APIProjectAlias struct {
 	RemotePath  *string   `json:"remote_path"`
 	VariantTags []*string `json:"variant_tags,omitempty"`
 	TaskTags    []*string `json:"tags,omitempty"`
+	Selector    *string   `json:"selector,omitempty"`
 	Delete      bool      `json:"delete,omitempty"`
 	ID          *string   `json:"_id,omitempty"`
 }
@@ -137,10 +147,21 @@ func (p *APIProjectVars) ToService() (interface{}, error) {
 	for _, each := range p.AdminOnlyVarsList {
 		adminOnlyVars[each] = true
 	}
+	var restrictedVars map[string]model.ProjectVarRestriction
+	if p.RestrictedVars != nil {
+		restrictedVars = map[string]model.ProjectVarRestriction{}
+		for key, restriction := range p.RestrictedVars {
+			restrictedVars[key] = model.ProjectVarRestriction{
+				TaskRegex:    utility.FromStringPtr(restriction.TaskRegex),
+				VariantRegex: utility.FromStringPtr(restriction.VariantRegex),
+			}
+		}
+	}
 	return &model.ProjectVars{
-		Vars:          p.Vars,
-		AdminOnlyVars: adminOnlyVars,
-		PrivateVars:   privateVars,
+		Vars:           p.Vars,
+		AdminOnlyVars:  adminOnlyVars,
+		PrivateVars:    privateVars,
+		RestrictedVars: restrictedVars,
 	}, nil
 }
 
@@ -150,6 +171,16 @@ func (p *APIProjectVars) BuildFromService(h interface{}) error {
 		p.PrivateVars = v.PrivateVars
 		p.Vars = v.Vars
 		p.AdminOnlyVars = v.AdminOnlyVars
+		p.RestrictedVars = nil
+		if v.RestrictedVars != nil {
+			p.RestrictedVars = map[string]APIProjectVarRestriction{}
+			for key, restriction := range v.RestrictedVars {
+				p.RestrictedVars[key] = APIProjectVarRestriction{
+					TaskRegex:    utility.ToStringPtr(restriction.TaskRegex),
+					VariantRegex: utility.ToStringPtr(restriction.VariantRegex),
+				}
+			}
+		}
 	default:
 		return errors.Errorf("programmatic error: expected project variables but got type %T", h)
 	}
@@ -165,6 +196,7 @@ func (a *APIProjectAlias) ToService() (interface{}, error) {
 		RemotePath:  utility.FromStringPtr(a.RemotePath),
 		TaskTags:    utility.FromStringPtrSlice(a.TaskTags),
 		VariantTags: utility.FromStringPtrSlice(a.VariantTags),
+		Selector:    utility.FromStringPtr(a.Selector),
 	}
 	if model.IsValidId(utility.FromStringPtr(a.ID)) {
 		res.ID = model.NewId(utility.FromStringPtr(a.ID))
@@ -185,6 +217,7 @@ func (a *APIProjectAlias) BuildFromService(h interface{}) error {
 		a.Task = utility.ToStringPtr(v.Task)
 		a.VariantTags = APIVariantTags
 		a.TaskTags = APITaskTags
+		a.Selector = utility.ToStringPtr(v.Selector)
 		a.ID = utility.ToStringPtr(v.ID.Hex())
 	case model.ProjectAlias:
 		APITaskTags := utility.ToStringPtrSlice(v.TaskTags)
@@ -197,6 +230,7 @@ func (a *APIProjectAlias) BuildFromService(h interface{}) error {
 		a.Task = utility.ToStringPtr(v.Task)
 		a.VariantTags = APIVariantTags
 		a.TaskTags = APITaskTags
+		a.Selector = utility.ToStringPtr(v.Selector)
 		a.ID = utility.ToStringPtr(v.ID.Hex())
 	default:
 		return errors.Errorf("programmatic error: expected project alias but got type %T", h)
@@ -216,6 +250,7 @@ func dbProjectAliasesToRestModel(aliases []model.ProjectAlias) []APIProjectAlias
 			GitTag:      utility.ToStringPtr(alias.GitTag),
 			TaskTags:    utility.ToStringPtrSlice(alias.TaskTags),
 			VariantTags: utility.ToStringPtrSlice(alias.VariantTags),
+			Selector:    utility.ToStringPtr(alias.Selector),
 		}
 		result = append(result, apiAlias)
 	}
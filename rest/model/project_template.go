@@ -0,0 +1,125 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APIProjectTemplate is a named set of project settings that new branch
+// projects can be created from.
+type APIProjectTemplate struct {
+	Name          *string                         `json:"name"`
+	Description   *string                         `json:"description"`
+	Placeholders  []APIProjectTemplatePlaceholder `json:"placeholders"`
+	ProjectRef    APIProjectRef                   `json:"project_ref"`
+	Vars          APIProjectVars                  `json:"vars"`
+	Aliases       []APIProjectAlias               `json:"aliases"`
+	Subscriptions []APISubscription               `json:"subscriptions"`
+	LastUpdated   *time.Time                      `json:"last_updated"`
+	UpdatedBy     *string                         `json:"updated_by"`
+}
+
+// APIProjectTemplatePlaceholder is a value that is given when creating a
+// project from a template.
+type APIProjectTemplatePlaceholder struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Default     *string `json:"default"`
+}
+
+// BuildFromService converts from a service level project template. The values
+// of private variables are redacted.
+func (t *APIProjectTemplate) BuildFromService(template model.ProjectTemplate) error {
+	t.Name = utility.ToStringPtr(template.Name)
+	t.Description = utility.ToStringPtr(template.Description)
+	t.Placeholders = []APIProjectTemplatePlaceholder{}
+	for _, p := range template.Placeholders {
+		t.Placeholders = append(t.Placeholders, APIProjectTemplatePlaceholder{
+			Name:        utility.ToStringPtr(p.Name),
+			Description: utility.ToStringPtr(p.Description),
+			Default:     utility.ToStringPtr(p.Default),
+		})
+	}
+
+	settings := template.Settings
+	settings.Vars = *settings.Vars.RedactPrivateVars()
+	apiSettings, err := DbProjectSettingsToRestModel(settings)
+	if err != nil {
+		return errors.Wrap(err, "converting template settings to API model")
+	}
+	t.ProjectRef = apiSettings.ProjectRef
+	t.Vars = apiSettings.Vars
+	t.Aliases = apiSettings.Aliases
+	t.Subscriptions = apiSettings.Subscriptions
+	t.LastUpdated = ToTimePtr(template.LastUpdated)
+	t.UpdatedBy = utility.ToStringPtr(template.UpdatedBy)
+	return nil
+}
+
+// ToService converts to a service level project template.
+func (t *APIProjectTemplate) ToService() (*model.ProjectTemplate, error) {
+	template := &model.ProjectTemplate{
+		Name:        utility.FromStringPtr(t.Name),
+		Description: utility.FromStringPtr(t.Description),
+	}
+	for _, p := range t.Placeholders {
+		template.Placeholders = append(template.Placeholders, model.ProjectTemplatePlaceholder{
+			Name:        utility.FromStringPtr(p.Name),
+			Description: utility.FromStringPtr(p.Description),
+			Default:     utility.FromStringPtr(p.Default),
+		})
+	}
+
+	i, err := t.ProjectRef.ToService()
+	if err != nil {
+		return nil, errors.Wrap(err, "converting project ref to service model")
+	}
+	pRef, ok := i.(*model.ProjectRef)
+	if !ok {
+		return nil, errors.Errorf("programmatic error: expected project ref but got type %T", i)
+	}
+	template.Settings.ProjectRef = *pRef
+
+	i, err = t.Vars.ToService()
+	if err != nil {
+		return nil, errors.Wrap(err, "converting project vars to service model")
+	}
+	vars, ok := i.(*model.ProjectVars)
+	if !ok {
+		return nil, errors.Errorf("programmatic error: expected project vars but got type %T", i)
+	}
+	template.Settings.Vars = *vars
+
+	for _, apiAlias := range t.Aliases {
+		i, err = apiAlias.ToService()
+		if err != nil {
+			return nil, errors.Wrap(err, "converting alias to service model")
+		}
+		alias, ok := i.(model.ProjectAlias)
+		if !ok {
+			return nil, errors.Errorf("programmatic error: expected project alias but got type %T", i)
+		}
+		// Aliases are given new IDs when a project is created from the
+		// template.
+		alias.ID = ""
+		template.Settings.Aliases = append(template.Settings.Aliases, alias)
+	}
+	for _, apiSub := range t.Subscriptions {
+		i, err = apiSub.ToService()
+		if err != nil {
+			return nil, errors.Wrap(err, "converting subscription to service model")
+		}
+		sub, ok := i.(event.Subscription)
+		if !ok {
+			return nil, errors.Errorf("programmatic error: expected subscription but got type %T", i)
+		}
+		sub.ID = ""
+		template.Settings.Subscriptions = append(template.Settings.Subscriptions, sub)
+	}
+
+	return template, nil
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIProjectTriggerGraph is the graph of cross-project triggers.
+type APIProjectTriggerGraph struct {
+	Projects []string                `json:"projects"`
+	Edges    []APIProjectTriggerEdge `json:"edges"`
+	// MaxDepth is the longest chain of triggers that can be started from a
+	// single mainline commit.
+	MaxDepth int `json:"max_depth"`
+	// MaxFanOut is the maximum number of downstream versions a single
+	// version can trigger.
+	MaxFanOut int `json:"max_fan_out"`
+}
+
+// APIProjectTriggerEdge is a trigger from an upstream project to a
+// downstream project.
+type APIProjectTriggerEdge struct {
+	UpstreamProject   *string `json:"upstream_project"`
+	DownstreamProject *string `json:"downstream_project"`
+	DefinitionID      *string `json:"definition_id"`
+	Level             *string `json:"level"`
+}
+
+// BuildFromService converts from a service level trigger graph to an API
+// trigger graph.
+func (g *APIProjectTriggerGraph) BuildFromService(graph model.ProjectTriggerGraph) {
+	g.Projects = graph.Projects
+	if g.Projects == nil {
+		g.Projects = []string{}
+	}
+	g.Edges = []APIProjectTriggerEdge{}
+	for _, edge := range graph.Edges {
+		g.Edges = append(g.Edges, APIProjectTriggerEdge{
+			UpstreamProject:   utility.ToStringPtr(edge.UpstreamProject),
+			DownstreamProject: utility.ToStringPtr(edge.DownstreamProject),
+			DefinitionID:      utility.ToStringPtr(edge.DefinitionID),
+			Level:             utility.ToStringPtr(edge.Level),
+		})
+	}
+}
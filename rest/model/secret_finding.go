@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/secretscan"
+	"github.com/evergreen-ci/utility"
+)
+
+// APISecretFinding is a secret that was found and redacted in the logs or
+// artifact metadata uploaded by a task.
+type APISecretFinding struct {
+	ID          *string    `json:"id"`
+	TaskID      *string    `json:"task_id"`
+	Execution   int        `json:"execution"`
+	ProjectID   *string    `json:"project_id"`
+	Source      *string    `json:"source"`
+	Location    *string    `json:"location"`
+	Rule        *string    `json:"rule"`
+	Fingerprint *string    `json:"fingerprint"`
+	CreateTime  *time.Time `json:"create_time"`
+}
+
+// BuildFromService converts from a service level secret scan finding to an
+// APISecretFinding.
+func (f *APISecretFinding) BuildFromService(finding secretscan.Finding) {
+	f.ID = utility.ToStringPtr(finding.ID)
+	f.TaskID = utility.ToStringPtr(finding.TaskID)
+	f.Execution = finding.Execution
+	f.ProjectID = utility.ToStringPtr(finding.ProjectID)
+	f.Source = utility.ToStringPtr(finding.Source)
+	f.Location = utility.ToStringPtr(finding.Location)
+	f.Rule = utility.ToStringPtr(finding.Rule)
+	f.Fingerprint = utility.ToStringPtr(finding.Fingerprint)
+	f.CreateTime = ToTimePtr(finding.CreateTime)
+}
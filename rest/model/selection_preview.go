@@ -0,0 +1,57 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APISelectionPreview is what a selection expression selects from a
+// project's config.
+type APISelectionPreview struct {
+	// VersionID is the version whose config the expression was evaluated
+	// against.
+	VersionID  *string `json:"version_id"`
+	Expression *string `json:"expression"`
+	// Dimensions are the dimensions that the expression selects on.
+	Dimensions []string             `json:"dimensions"`
+	Variants   []APISelectedVariant `json:"variants"`
+}
+
+// APISelectedVariant is a build variant's tasks and display tasks that a
+// selection expression selected.
+type APISelectedVariant struct {
+	Variant      *string  `json:"variant"`
+	Tasks        []string `json:"tasks"`
+	DisplayTasks []string `json:"display_tasks"`
+}
+
+// BuildFromService converts from the selected tasks and display tasks,
+// grouping them by variant in the order that the variants are first selected.
+func (p *APISelectionPreview) BuildFromService(expr *model.SelectionExpression, pairs, displayTaskPairs []model.TVPair) {
+	p.Expression = utility.ToStringPtr(expr.String())
+	p.Dimensions = expr.Dimensions()
+	p.Variants = []APISelectedVariant{}
+
+	variantIndex := map[string]int{}
+	variant := func(name string) *APISelectedVariant {
+		i, ok := variantIndex[name]
+		if !ok {
+			i = len(p.Variants)
+			variantIndex[name] = i
+			p.Variants = append(p.Variants, APISelectedVariant{
+				Variant:      utility.ToStringPtr(name),
+				Tasks:        []string{},
+				DisplayTasks: []string{},
+			})
+		}
+		return &p.Variants[i]
+	}
+	for _, pair := range pairs {
+		v := variant(pair.Variant)
+		v.Tasks = append(v.Tasks, pair.TaskName)
+	}
+	for _, pair := range displayTaskPairs {
+		v := variant(pair.Variant)
+		v.DisplayTasks = append(v.DisplayTasks, pair.TaskName)
+	}
+}
@@ -0,0 +1,11 @@
+package model
+
+// APISelectorMatches are the items in a project's config that a task or
+// variant selector selects.
+type APISelectorMatches struct {
+	Selector      *string  `json:"selector"`
+	Type          *string  `json:"type"`
+	Tasks         []string `json:"tasks,omitempty"`
+	TaskGroups    []string `json:"task_groups,omitempty"`
+	BuildVariants []string `json:"build_variants,omitempty"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIServiceAccount is an API-only user that can only act on a single
+// project.
+type APIServiceAccount struct {
+	ID           *string    `json:"id"`
+	ProjectID    *string    `json:"project_id"`
+	Permissions  []string   `json:"permissions"`
+	CreatedBy    *string    `json:"created_by"`
+	CreatedAt    *time.Time `json:"created_at"`
+	KeyRotatedAt *time.Time `json:"key_rotated_at,omitempty"`
+	// APIKey is only returned when the service account is created or its
+	// key is rotated.
+	APIKey *string `json:"api_key,omitempty"`
+}
+
+// BuildFromService converts from a service level user that is a service
+// account. The API key is only included if includeKey is set.
+func (a *APIServiceAccount) BuildFromService(u user.DBUser, includeKey bool) {
+	a.ID = utility.ToStringPtr(u.Id)
+	a.CreatedAt = ToTimePtr(u.CreatedAt)
+	if includeKey {
+		a.APIKey = utility.ToStringPtr(u.APIKey)
+	}
+	if u.ServiceAccount == nil {
+		return
+	}
+	a.ProjectID = utility.ToStringPtr(u.ServiceAccount.ProjectID)
+	a.Permissions = u.ServiceAccount.Permissions
+	a.CreatedBy = utility.ToStringPtr(u.ServiceAccount.CreatedBy)
+	a.KeyRotatedAt = ToTimePtr(u.ServiceAccount.KeyRotatedAt)
+}
+
+// APIServiceAccountEvent is an audit record of a change to a service account
+// or an action that a service account took.
+type APIServiceAccountEvent struct {
+	ServiceAccountID *string    `json:"service_account_id"`
+	EventType        *string    `json:"event_type"`
+	Timestamp        *time.Time `json:"timestamp"`
+	User             *string    `json:"user,omitempty"`
+	Permissions      []string   `json:"permissions,omitempty"`
+	Method           *string    `json:"method,omitempty"`
+	Path             *string    `json:"path,omitempty"`
+	StatusCode       int        `json:"status_code,omitempty"`
+}
+
+// BuildFromService converts from a service level service account event.
+func (e *APIServiceAccountEvent) BuildFromService(entry event.EventLogEntry) {
+	e.ServiceAccountID = utility.ToStringPtr(entry.ResourceId)
+	e.EventType = utility.ToStringPtr(entry.EventType)
+	e.Timestamp = ToTimePtr(entry.Timestamp)
+	data, ok := entry.Data.(*event.ServiceAccountEventData)
+	if !ok {
+		return
+	}
+	if data.User != "" {
+		e.User = utility.ToStringPtr(data.User)
+	}
+	e.Permissions = data.Permissions
+	if data.Method != "" {
+		e.Method = utility.ToStringPtr(data.Method)
+		e.Path = utility.ToStringPtr(data.Path)
+	}
+	e.StatusCode = data.StatusCode
+}
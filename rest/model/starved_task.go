@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIStarvedTask is a task that has been waiting to be dispatched for longer
+// than its project's starvation threshold.
+type APIStarvedTask struct {
+	TaskID            *string    `json:"task_id"`
+	DisplayName       *string    `json:"display_name"`
+	BuildVariant      *string    `json:"build_variant"`
+	Version           *string    `json:"version_id"`
+	Requester         *string    `json:"requester"`
+	DistroID          *string    `json:"distro_id"`
+	Priority          int64      `json:"priority"`
+	EffectivePriority int64      `json:"effective_priority"`
+	WaitingSince      *time.Time `json:"waiting_since"`
+	WaitTimeSecs      float64    `json:"wait_time_secs"`
+}
+
+// BuildFromService converts from a service level starved task.
+func (t *APIStarvedTask) BuildFromService(starved model.StarvedTask) {
+	t.TaskID = utility.ToStringPtr(starved.TaskID)
+	t.DisplayName = utility.ToStringPtr(starved.DisplayName)
+	t.BuildVariant = utility.ToStringPtr(starved.BuildVariant)
+	t.Version = utility.ToStringPtr(starved.Version)
+	t.Requester = utility.ToStringPtr(starved.Requester)
+	t.DistroID = utility.ToStringPtr(starved.DistroID)
+	t.Priority = starved.Priority
+	t.EffectivePriority = starved.EffectivePriority
+	t.WaitingSince = ToTimePtr(starved.WaitingSince)
+	t.WaitTimeSecs = starved.WaitTime.Seconds()
+}
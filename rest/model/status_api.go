@@ -0,0 +1,112 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIStatusVersion is the status of a version as exposed by the read-only
+// status API.
+type APIStatusVersion struct {
+	Id                  *string          `json:"version_id"`
+	ProjectIdentifier   *string          `json:"project_identifier"`
+	Revision            *string          `json:"revision"`
+	RevisionOrderNumber int              `json:"order"`
+	CreateTime          *time.Time       `json:"create_time"`
+	FinishTime          *time.Time       `json:"finish_time"`
+	Status              *string          `json:"status"`
+	Builds              []APIStatusBuild `json:"builds"`
+}
+
+// BuildFromService converts from a service level version and its builds.
+func (v *APIStatusVersion) BuildFromService(version model.Version, projectIdentifier string, builds []build.Build) {
+	v.Id = utility.ToStringPtr(version.Id)
+	v.ProjectIdentifier = utility.ToStringPtr(projectIdentifier)
+	v.Revision = utility.ToStringPtr(version.Revision)
+	v.RevisionOrderNumber = version.RevisionOrderNumber
+	v.CreateTime = ToTimePtr(version.CreateTime)
+	v.FinishTime = ToTimePtr(version.FinishTime)
+	v.Status = utility.ToStringPtr(version.Status)
+	v.Builds = make([]APIStatusBuild, 0, len(builds))
+	for _, b := range builds {
+		apiBuild := APIStatusBuild{}
+		apiBuild.BuildFromService(b, nil)
+		v.Builds = append(v.Builds, apiBuild)
+	}
+}
+
+// APIStatusBuild is the status of a build as exposed by the read-only status
+// API.
+type APIStatusBuild struct {
+	Id           *string         `json:"build_id"`
+	Version      *string         `json:"version_id"`
+	BuildVariant *string         `json:"build_variant"`
+	DisplayName  *string         `json:"display_name"`
+	Status       *string         `json:"status"`
+	Tasks        []APIStatusTask `json:"tasks,omitempty"`
+}
+
+// BuildFromService converts from a service level build and its tasks.
+func (b *APIStatusBuild) BuildFromService(dbBuild build.Build, tasks []task.Task) {
+	b.Id = utility.ToStringPtr(dbBuild.Id)
+	b.Version = utility.ToStringPtr(dbBuild.Version)
+	b.BuildVariant = utility.ToStringPtr(dbBuild.BuildVariant)
+	b.DisplayName = utility.ToStringPtr(dbBuild.DisplayName)
+	b.Status = utility.ToStringPtr(dbBuild.Status)
+	for _, t := range tasks {
+		apiTask := APIStatusTask{}
+		apiTask.BuildFromService(t)
+		b.Tasks = append(b.Tasks, apiTask)
+	}
+}
+
+// APIStatusTask is the status of a task as exposed by the read-only status
+// API.
+type APIStatusTask struct {
+	Id           *string    `json:"task_id"`
+	Execution    int        `json:"execution"`
+	DisplayName  *string    `json:"display_name"`
+	BuildVariant *string    `json:"build_variant"`
+	Version      *string    `json:"version_id"`
+	Status       *string    `json:"status"`
+	StartTime    *time.Time `json:"start_time"`
+	FinishTime   *time.Time `json:"finish_time"`
+}
+
+// BuildFromService converts from a service level task.
+func (t *APIStatusTask) BuildFromService(dbTask task.Task) {
+	t.Id = utility.ToStringPtr(dbTask.Id)
+	t.Execution = dbTask.Execution
+	t.DisplayName = utility.ToStringPtr(dbTask.DisplayName)
+	t.BuildVariant = utility.ToStringPtr(dbTask.BuildVariant)
+	t.Version = utility.ToStringPtr(dbTask.Version)
+	t.Status = utility.ToStringPtr(dbTask.GetDisplayStatus())
+	t.StartTime = ToTimePtr(dbTask.StartTime)
+	t.FinishTime = ToTimePtr(dbTask.FinishTime)
+}
+
+// APIStatusAPIKey is a key for the read-only status API. The key itself is
+// only set when the key is created.
+type APIStatusAPIKey struct {
+	Id                *string    `json:"id"`
+	Name              *string    `json:"name"`
+	Key               *string    `json:"key,omitempty"`
+	Projects          []string   `json:"projects"`
+	RequestsPerMinute int        `json:"requests_per_minute"`
+	CreatedBy         *string    `json:"created_by"`
+	CreateTime        *time.Time `json:"create_time"`
+}
+
+// BuildFromService converts from a service level status API key.
+func (k *APIStatusAPIKey) BuildFromService(key model.StatusAPIKey) {
+	k.Id = utility.ToStringPtr(key.Id)
+	k.Name = utility.ToStringPtr(key.Name)
+	k.Projects = key.Projects
+	k.RequestsPerMinute = key.GetRequestsPerMinute()
+	k.CreatedBy = utility.ToStringPtr(key.CreatedBy)
+	k.CreateTime = ToTimePtr(key.CreateTime)
+}
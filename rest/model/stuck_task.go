@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIStuckTask is a started task whose heartbeat has been stale for longer
+// than its distro's stuck task threshold.
+type APIStuckTask struct {
+	TaskID        *string    `json:"task_id"`
+	Execution     int        `json:"execution"`
+	DisplayName   *string    `json:"display_name"`
+	BuildVariant  *string    `json:"build_variant"`
+	Version       *string    `json:"version_id"`
+	ProjectID     *string    `json:"project_id"`
+	DistroID      *string    `json:"distro_id"`
+	HostID        *string    `json:"host_id"`
+	LastHeartbeat *time.Time `json:"last_heartbeat"`
+	StaleSecs     float64    `json:"stale_secs"`
+	ThresholdSecs float64    `json:"threshold_secs"`
+	Policy        *string    `json:"policy"`
+	// FlaggedAt is when the watchdog flagged the task as stuck. It's only set
+	// for tasks in projects that alert on stuck tasks.
+	FlaggedAt *time.Time `json:"flagged_at"`
+}
+
+// BuildFromService converts from a service level stuck task.
+func (t *APIStuckTask) BuildFromService(stuck model.StuckTask) {
+	t.TaskID = utility.ToStringPtr(stuck.Task.Id)
+	t.Execution = stuck.Task.Execution
+	t.DisplayName = utility.ToStringPtr(stuck.Task.DisplayName)
+	t.BuildVariant = utility.ToStringPtr(stuck.Task.BuildVariant)
+	t.Version = utility.ToStringPtr(stuck.Task.Version)
+	t.ProjectID = utility.ToStringPtr(stuck.Task.Project)
+	t.DistroID = utility.ToStringPtr(stuck.Task.DistroId)
+	t.HostID = utility.ToStringPtr(stuck.Task.HostId)
+	t.LastHeartbeat = ToTimePtr(stuck.Task.LastHeartbeat)
+	t.StaleSecs = stuck.StaleFor.Seconds()
+	t.ThresholdSecs = stuck.Threshold.Seconds()
+	t.Policy = utility.ToStringPtr(stuck.Policy)
+	t.FlaggedAt = ToTimePtr(stuck.Task.StuckTime)
+}
@@ -71,11 +71,14 @@ type APITask struct {
 	TestResults             []APITest           `json:"test_results"`
 	Aborted                 bool                `json:"aborted"`
 	AbortInfo               APIAbortInfo        `json:"abort_info,omitempty"`
+	SkipReason              *string             `json:"skip_reason,omitempty"`
 	CanSync                 bool                `json:"can_sync,omitempty"`
 	SyncAtEndOpts           APISyncAtEndOptions `json:"sync_at_end_opts"`
 	AMI                     *string             `json:"ami"`
 	MustHaveResults         bool                `json:"must_have_test_results"`
+	ExpectedArtifacts       []string            `json:"expected_artifacts,omitempty"`
 	BaseTask                APIBaseTaskInfo     `json:"base_task"`
+	Outputs                 map[string]string   `json:"outputs,omitempty"`
 	// These fields are used by graphql gen, but do not need to be exposed
 	// via Evergreen's user-facing API.
 	OverrideDependencies bool `json:"-"`
@@ -241,10 +244,13 @@ func (at *APITask) BuildFromService(t interface{}) error {
 			Blocked:                 v.Blocked(),
 			Requester:               utility.ToStringPtr(v.Requester),
 			Aborted:                 v.Aborted,
+			SkipReason:              utility.ToStringPtr(v.SkipReason),
 			CanSync:                 v.CanSync,
 			HasCedarResults:         v.HasCedarResults,
 			CedarResultsFailed:      v.CedarResultsFailed,
 			MustHaveResults:         v.MustHaveResults,
+			ExpectedArtifacts:       v.ExpectedArtifacts,
+			Outputs:                 v.Outputs,
 			ParentTaskId:            utility.FromStringPtr(v.DisplayTaskId),
 			SyncAtEndOpts: APISyncAtEndOptions{
 				Enabled:  v.SyncAtEndOpts.Enabled,
@@ -416,6 +422,8 @@ func (ad *APITask) ToService() (interface{}, error) {
 		HasCedarResults:         ad.HasCedarResults,
 		CedarResultsFailed:      ad.CedarResultsFailed,
 		MustHaveResults:         ad.MustHaveResults,
+		ExpectedArtifacts:       ad.ExpectedArtifacts,
+		Outputs:                 ad.Outputs,
 		SyncAtEndOpts: task.SyncAtEndOptions{
 			Enabled:  ad.SyncAtEndOpts.Enabled,
 			Statuses: ad.SyncAtEndOpts.Statuses,
@@ -427,6 +435,7 @@ func (ad *APITask) ToService() (interface{}, error) {
 		},
 		DisplayTaskId: utility.ToStringPtr(ad.ParentTaskId),
 		Aborted:       ad.Aborted,
+		SkipReason:    utility.FromStringPtr(ad.SkipReason),
 	}
 	catcher := grip.NewBasicCatcher()
 	serviceDetails, err := ad.Details.ToService()
@@ -24,6 +24,7 @@ type APITaskAnnotation struct {
 	Issues          []APIIssueLink  `bson:"issues,omitempty" json:"issues,omitempty"`
 	SuspectedIssues []APIIssueLink  `bson:"suspected_issues,omitempty" json:"suspected_issues,omitempty"`
 	CreatedIssues   []APIIssueLink  `bson:"created_issues,omitempty" json:"created_issues,omitempty"`
+	Attachments     []APIAttachment `bson:"attachments,omitempty" json:"attachments,omitempty"`
 }
 
 type APINote struct {
@@ -35,6 +36,18 @@ type APISource struct {
 	Time      *time.Time `bson:"time,omitempty" json:"time,omitempty"`
 	Requester *string    `bson:"requester,omitempty" json:"requester,omitempty"`
 }
+
+// APIAttachment is a file attached to a task annotation. URL is a signed URL
+// that the file can be downloaded from until it expires.
+type APIAttachment struct {
+	Id          *string    `bson:"id" json:"id"`
+	Name        *string    `bson:"name" json:"name"`
+	ContentType *string    `bson:"content_type" json:"content_type"`
+	Size        int        `bson:"size" json:"size"`
+	URL         *string    `bson:"url,omitempty" json:"url,omitempty"`
+	Source      *APISource `bson:"source,omitempty" json:"source,omitempty"`
+}
+
 type APIIssueLink struct {
 	URL             *string    `bson:"url" json:"url"`
 	IssueKey        *string    `bson:"issue_key,omitempty" json:"issue_key,omitempty"`
@@ -94,6 +107,19 @@ func APIIssueLinkToService(m APIIssueLink) *annotations.IssueLink {
 	return out
 }
 
+// APIAttachmentBuildFromService takes the annotations.Attachment DB struct and
+// returns the REST struct *APIAttachment with the corresponding fields
+// populated, except for the URL.
+func APIAttachmentBuildFromService(t annotations.Attachment) *APIAttachment {
+	m := APIAttachment{}
+	m.Id = StringStringPtr(t.Id)
+	m.Name = StringStringPtr(t.Name)
+	m.ContentType = StringStringPtr(t.ContentType)
+	m.Size = t.Size
+	m.Source = APISourceBuildFromService(t.Source)
+	return &m
+}
+
 // APINoteBuildFromService takes the annotations.Note DB struct and
 // returns the REST struct *APINote with the corresponding fields populated
 func APINoteBuildFromService(t *annotations.Note) *APINote {
@@ -130,9 +156,32 @@ func APITaskAnnotationBuildFromService(t annotations.TaskAnnotation) *APITaskAnn
 	m.SuspectedIssues = ArrtaskannotationsIssueLinkArrAPIIssueLink(t.SuspectedIssues)
 	m.CreatedIssues = ArrtaskannotationsIssueLinkArrAPIIssueLink(t.CreatedIssues)
 	m.Note = APINoteBuildFromService(t.Note)
+	for _, a := range t.Attachments {
+		m.Attachments = append(m.Attachments, *APIAttachmentBuildFromService(a))
+	}
 	return &m
 }
 
+// SetAttachmentURLs sets the signed URLs that the annotation's attachments can
+// be downloaded from. The annotation must have been built from t.
+func (m *APITaskAnnotation) SetAttachmentURLs(t annotations.TaskAnnotation, settings *evergreen.Settings) error {
+	if len(m.Attachments) != len(t.Attachments) {
+		return errors.New("programmatic error: annotation attachments do not match")
+	}
+	for i, a := range t.Attachments {
+		store, err := annotations.NewAttachmentStore(a.Backend, settings, thirdparty.PreSignObject)
+		if err != nil {
+			return errors.Wrapf(err, "getting store for attachment '%s'", a.Id)
+		}
+		signedURL, err := store.SignedURL(a)
+		if err != nil {
+			return errors.Wrapf(err, "signing URL for attachment '%s'", a.Id)
+		}
+		m.Attachments[i].URL = utility.ToStringPtr(signedURL)
+	}
+	return nil
+}
+
 // APITaskAnnotationToService takes the APITaskAnnotation REST struct and returns the DB struct
 // *annotations.TaskAnnotation with the corresponding fields populated
 func APITaskAnnotationToService(m APITaskAnnotation) *annotations.TaskAnnotation {
@@ -172,7 +221,7 @@ func ArrAPIIssueLinkArrtaskannotationsIssueLink(t []APIIssueLink) []annotations.
 
 func GetJiraTicketFromURL(jiraURL string) (*thirdparty.JiraTicket, error) {
 	settings := evergreen.GetEnvironment().Settings()
-	jiraHandler := thirdparty.NewJiraHandler(*settings.Jira.Export())
+	jiraHandler := thirdparty.NewJiraIntegration(*settings.Jira.Export())
 
 	parsedURL, err := url.Parse(jiraURL)
 	if err != nil {
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+)
+
+// TaskBootstrapSchemaVersion is the version of the task bootstrap response.
+// It must be incremented whenever a field is removed from or changes meaning
+// in APITaskBootstrap so that agents know not to trust a response they don't
+// understand.
+const TaskBootstrapSchemaVersion = 1
+
+// APITaskBootstrap is all the configuration that an agent needs from the app
+// server to set up a task, so that the agent can get it in a single request.
+type APITaskBootstrap struct {
+	// SchemaVersion is the version of the response's format.
+	SchemaVersion int        `json:"schema_version"`
+	Task          *task.Task `json:"task"`
+	// ParserProject is the BSON-encoded parser project for the task's
+	// version.
+	ParserProject []byte                  `json:"parser_project"`
+	ProjectRef    *model.ProjectRef       `json:"project_ref"`
+	DistroView    apimodels.DistroView    `json:"distro_view"`
+	Expansions    util.Expansions         `json:"expansions"`
+	ExpansionVars apimodels.ExpansionVars `json:"expansion_vars"`
+	Cedar         apimodels.CedarConfig   `json:"cedar"`
+}
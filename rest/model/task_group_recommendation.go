@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APITaskGroupMaxHostsRecommendation is a suggested max_hosts for a task group
+// based on its recent runtimes and queue waits.
+type APITaskGroupMaxHostsRecommendation struct {
+	VersionID              *string     `json:"version_id"`
+	TaskGroup              *string     `json:"task_group"`
+	BuildVariant           *string     `json:"build_variant,omitempty"`
+	NumTasks               int         `json:"num_tasks"`
+	CurrentMaxHosts        int         `json:"current_max_hosts"`
+	RecommendedMaxHosts    int         `json:"recommended_max_hosts"`
+	NumRunsSampled         int         `json:"num_runs_sampled"`
+	AvgTotalDuration       APIDuration `json:"avg_total_duration_ms"`
+	AvgLongestTaskDuration APIDuration `json:"avg_longest_task_duration_ms"`
+	AvgQueueWait           APIDuration `json:"avg_queue_wait_ms"`
+	Reasons                []string    `json:"reasons"`
+}
+
+// BuildFromService converts from a service level task group max_hosts
+// recommendation.
+func (r *APITaskGroupMaxHostsRecommendation) BuildFromService(rec model.TaskGroupMaxHostsRecommendation) {
+	r.TaskGroup = utility.ToStringPtr(rec.TaskGroup)
+	if rec.BuildVariant != "" {
+		r.BuildVariant = utility.ToStringPtr(rec.BuildVariant)
+	}
+	r.NumTasks = rec.NumTasks
+	r.CurrentMaxHosts = rec.CurrentMaxHosts
+	r.RecommendedMaxHosts = rec.RecommendedMaxHosts
+	r.NumRunsSampled = rec.NumRunsSampled
+	r.AvgTotalDuration = NewAPIDuration(rec.AvgTotalDuration)
+	r.AvgLongestTaskDuration = NewAPIDuration(rec.AvgLongestTaskDuration)
+	r.AvgQueueWait = NewAPIDuration(rec.AvgQueueWait)
+	r.Reasons = rec.Reasons
+	if r.Reasons == nil {
+		r.Reasons = []string{}
+	}
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APITaskLatency is the latency breakdown of a task execution. Latencies are
+// in milliseconds.
+type APITaskLatency struct {
+	TaskID              *string     `json:"task_id"`
+	Execution           int         `json:"execution"`
+	DistroID            *string     `json:"distro_id"`
+	Project             *string     `json:"project"`
+	EnqueuedAt          *time.Time  `json:"enqueued_at"`
+	DispatchedAt        *time.Time  `json:"dispatched_at"`
+	AgentAcceptedAt     *time.Time  `json:"agent_accepted_at"`
+	StartedAt           *time.Time  `json:"started_at"`
+	FinishedAt          *time.Time  `json:"finished_at"`
+	SchedulingLatency   APIDuration `json:"scheduling_latency_ms"`
+	AgentAcceptLatency  APIDuration `json:"agent_accept_latency_ms"`
+	AgentStartupLatency APIDuration `json:"agent_startup_latency_ms"`
+	RunTime             APIDuration `json:"run_time_ms"`
+}
+
+// BuildFromService converts from a service level task latency.
+func (l *APITaskLatency) BuildFromService(latency model.TaskLatency) {
+	l.TaskID = utility.ToStringPtr(latency.TaskID)
+	l.Execution = latency.Execution
+	l.DistroID = utility.ToStringPtr(latency.DistroID)
+	l.Project = utility.ToStringPtr(latency.Project)
+	l.EnqueuedAt = ToTimePtr(latency.EnqueuedAt)
+	l.DispatchedAt = ToTimePtr(latency.DispatchedAt)
+	l.AgentAcceptedAt = ToTimePtr(latency.AgentAcceptedAt)
+	l.StartedAt = ToTimePtr(latency.StartedAt)
+	l.FinishedAt = ToTimePtr(latency.FinishedAt)
+	l.SchedulingLatency = NewAPIDuration(latency.SchedulingLatency)
+	l.AgentAcceptLatency = NewAPIDuration(latency.AgentAcceptLatency)
+	l.AgentStartupLatency = NewAPIDuration(latency.AgentStartupLatency)
+	l.RunTime = NewAPIDuration(latency.RunTime)
+}
+
+// APILatencyPercentiles summarizes the distribution of one latency across
+// tasks. Latencies are in milliseconds.
+type APILatencyPercentiles struct {
+	Count int         `json:"count"`
+	P50   APIDuration `json:"p50_ms"`
+	P90   APIDuration `json:"p90_ms"`
+	P99   APIDuration `json:"p99_ms"`
+	Max   APIDuration `json:"max_ms"`
+}
+
+// BuildFromService converts from service level latency percentiles.
+func (p *APILatencyPercentiles) BuildFromService(percentiles model.LatencyPercentiles) {
+	p.Count = percentiles.Count
+	p.P50 = NewAPIDuration(percentiles.P50)
+	p.P90 = NewAPIDuration(percentiles.P90)
+	p.P99 = NewAPIDuration(percentiles.P99)
+	p.Max = NewAPIDuration(percentiles.Max)
+}
+
+// APITaskLatencyStats summarizes the latencies of the tasks that recently
+// finished in a distro or project.
+type APITaskLatencyStats struct {
+	DistroID            *string               `json:"distro_id,omitempty"`
+	Project             *string               `json:"project,omitempty"`
+	Since               *time.Time            `json:"since"`
+	NumTasks            int                   `json:"num_tasks"`
+	SchedulingLatency   APILatencyPercentiles `json:"scheduling_latency"`
+	AgentAcceptLatency  APILatencyPercentiles `json:"agent_accept_latency"`
+	AgentStartupLatency APILatencyPercentiles `json:"agent_startup_latency"`
+	RunTime             APILatencyPercentiles `json:"run_time"`
+}
+
+// BuildFromService converts from service level task latency stats.
+func (s *APITaskLatencyStats) BuildFromService(stats model.TaskLatencyStats) {
+	if stats.DistroID != "" {
+		s.DistroID = utility.ToStringPtr(stats.DistroID)
+	}
+	if stats.Project != "" {
+		s.Project = utility.ToStringPtr(stats.Project)
+	}
+	s.Since = ToTimePtr(stats.Since)
+	s.NumTasks = stats.NumTasks
+	s.SchedulingLatency.BuildFromService(stats.SchedulingLatency)
+	s.AgentAcceptLatency.BuildFromService(stats.AgentAcceptLatency)
+	s.AgentStartupLatency.BuildFromService(stats.AgentStartupLatency)
+	s.RunTime.BuildFromService(stats.RunTime)
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+)
+
+// APITaskOrder is the order in which a build's tasks are displayed.
+type APITaskOrder struct {
+	PinnedTasks []string `json:"pinned_tasks"`
+	SortBy      *string  `json:"sort_by"`
+}
+
+// BuildFromService converts from a service level task order to an API task
+// order.
+func (o *APITaskOrder) BuildFromService(order model.TaskOrder) {
+	o.PinnedTasks = order.PinnedTasks
+	o.SortBy = utility.ToStringPtr(order.SortBy)
+}
+
+// ToService returns a service level task order.
+func (o *APITaskOrder) ToService() model.TaskOrder {
+	return model.TaskOrder{
+		PinnedTasks: o.PinnedTasks,
+		SortBy:      utility.FromStringPtr(o.SortBy),
+	}
+}
+
+// APIUserTaskOrder describes the task order that applies to a user in a
+// project.
+type APIUserTaskOrder struct {
+	ProjectId *string `json:"project_id"`
+	// Project is the project's task order.
+	Project APITaskOrder `json:"project"`
+	// User is the user's override of the project's task order, if they have
+	// one.
+	User *APITaskOrder `json:"user,omitempty"`
+	// Source is whether the project's or the user's task order applies.
+	Source *string `json:"source"`
+}
+
+// APIBuildTaskOrder is the display order of a build's tasks. It's returned
+// alongside the build's tasks rather than reordering them so that every UI
+// displays them the same way.
+type APIBuildTaskOrder struct {
+	// Source is whether the project's or the user's task order applies.
+	Source *string `json:"source"`
+	SortBy *string `json:"sort_by"`
+	// TaskIds are the IDs of all of the build's tasks in display order.
+	TaskIds []string `json:"task_ids"`
+	// PinnedTaskIds are the IDs of the tasks that are pinned to the top.
+	PinnedTaskIds []string `json:"pinned_task_ids"`
+}
+
+// BuildFromService applies the task order to the build's tasks.
+func (o *APIBuildTaskOrder) BuildFromService(order model.TaskOrder, source string, tasks []task.Task) {
+	o.Source = utility.ToStringPtr(source)
+	o.SortBy = utility.ToStringPtr(order.SortBy)
+	o.TaskIds, o.PinnedTaskIds = order.Apply(tasks)
+}
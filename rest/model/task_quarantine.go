@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APITaskQuarantine disables a task in a build variant until it expires.
+type APITaskQuarantine struct {
+	ID           *string    `json:"id"`
+	ProjectID    *string    `json:"project_id"`
+	BuildVariant *string    `json:"build_variant"`
+	TaskName     *string    `json:"task_name"`
+	Reason       *string    `json:"reason"`
+	Expires      *time.Time `json:"expires"`
+	CreatedBy    *string    `json:"created_by"`
+	CreateTime   *time.Time `json:"create_time"`
+}
+
+// BuildFromService converts from a service level task quarantine.
+func (q *APITaskQuarantine) BuildFromService(quarantine model.TaskQuarantine) {
+	q.ID = utility.ToStringPtr(quarantine.Id)
+	q.ProjectID = utility.ToStringPtr(quarantine.ProjectId)
+	q.BuildVariant = utility.ToStringPtr(quarantine.BuildVariant)
+	q.TaskName = utility.ToStringPtr(quarantine.TaskName)
+	q.Reason = utility.ToStringPtr(quarantine.Reason)
+	q.Expires = ToTimePtr(quarantine.Expires)
+	q.CreatedBy = utility.ToStringPtr(quarantine.CreatedBy)
+	q.CreateTime = ToTimePtr(quarantine.CreateTime)
+}
+
+// ToService converts to a service level task quarantine.
+func (q *APITaskQuarantine) ToService() model.TaskQuarantine {
+	return model.TaskQuarantine{
+		Id:           utility.FromStringPtr(q.ID),
+		ProjectId:    utility.FromStringPtr(q.ProjectID),
+		BuildVariant: utility.FromStringPtr(q.BuildVariant),
+		TaskName:     utility.FromStringPtr(q.TaskName),
+		Reason:       utility.FromStringPtr(q.Reason),
+		Expires:      utility.FromTimePtr(q.Expires),
+		CreatedBy:    utility.FromStringPtr(q.CreatedBy),
+		CreateTime:   utility.FromTimePtr(q.CreateTime),
+	}
+}
@@ -28,6 +28,8 @@ type APITest struct {
 	StartTime       *time.Time `json:"start_time"`
 	EndTime         *time.Time `json:"end_time"`
 	Duration        float64    `json:"duration"`
+	// Artifacts are files produced by the test.
+	Artifacts []APITestArtifact `json:"artifacts,omitempty"`
 }
 
 // TestLogs is a struct for storing the information about logs that will be
@@ -38,6 +40,20 @@ type TestLogs struct {
 	URLLobster *string `json:"url_lobster,omitempty"`
 	LineNum    int     `json:"line_num"`
 	LogID      *string `json:"log_id,omitempty"`
+	// TaskLogRange points to the lines of the task log that the test wrote.
+	TaskLogRange *APITestLogRange `json:"task_log_range,omitempty"`
+}
+
+// APITestArtifact is a file produced by a single test.
+type APITestArtifact struct {
+	Name *string `json:"name"`
+	Link *string `json:"link"`
+}
+
+// APITestLogRange is an inclusive range of lines in a task log.
+type APITestLogRange struct {
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
 }
 
 func (at *APITest) BuildFromService(st interface{}) error {
@@ -69,6 +85,7 @@ func (at *APITest) BuildFromService(st interface{}) error {
 		if v.LogID != "" {
 			at.Logs.LogID = utility.ToStringPtr(v.LogID)
 		}
+		at.BuildAttachmentsFromService(v.Artifacts, v.TaskLogRange)
 	case *apimodels.CedarTestResult:
 		at.ID = utility.ToStringPtr(v.TestName)
 		at.Execution = v.Execution
@@ -102,6 +119,24 @@ func (at *APITest) BuildFromService(st interface{}) error {
 	return nil
 }
 
+// BuildAttachmentsFromService adds the test's artifacts and task log range,
+// which are stored separately from test results that are stored in Cedar.
+func (at *APITest) BuildAttachmentsFromService(artifacts []testresult.Artifact, logRange *testresult.LogRange) {
+	if logRange != nil {
+		at.Logs.TaskLogRange = &APITestLogRange{
+			StartLine: logRange.StartLine,
+			EndLine:   logRange.EndLine,
+		}
+	}
+	at.Artifacts = nil
+	for _, a := range artifacts {
+		at.Artifacts = append(at.Artifacts, APITestArtifact{
+			Name: utility.ToStringPtr(a.Name),
+			Link: utility.ToStringPtr(a.Link),
+		})
+	}
+}
+
 func (at *APITest) ToService() (interface{}, error) {
 	// It is not valid translate an APITest object to a TestResult object
 	// due to data loss.
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/utility"
+)
+
+// APITestFlakiness is how often a test's recent mainline outcomes alternated
+// between passing and failing.
+type APITestFlakiness struct {
+	BuildVariant   *string    `json:"build_variant"`
+	TaskName       *string    `json:"task_name"`
+	TestName       *string    `json:"test_name"`
+	Score          float64    `json:"score"`
+	NumRuns        int        `json:"num_runs"`
+	NumFailures    int        `json:"num_failures"`
+	NumTransitions int        `json:"num_transitions"`
+	LastUpdated    *time.Time `json:"last_updated"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+// BuildFromService converts from a service level test flakiness rollup.
+func (f *APITestFlakiness) BuildFromService(rollup testresult.TestFlakiness) {
+	f.BuildVariant = utility.ToStringPtr(rollup.ID.BuildVariant)
+	f.TaskName = utility.ToStringPtr(rollup.ID.TaskName)
+	f.TestName = utility.ToStringPtr(rollup.ID.TestName)
+	f.Score = rollup.Score
+	f.NumRuns = rollup.NumRuns
+	f.NumFailures = rollup.NumFailures
+	f.NumTransitions = rollup.NumTransitions
+	f.LastUpdated = ToTimePtr(rollup.LastUpdated)
+	f.ExpiresAt = ToTimePtr(rollup.ExpiresAt)
+}
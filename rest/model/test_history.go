@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APITestHistory is the execution history of a single test in a project.
+type APITestHistory struct {
+	TestName            *string                `json:"test_name"`
+	Entries             []APITestHistoryEntry  `json:"entries"`
+	DurationTrend       []APITestDurationPoint `json:"duration_trend"`
+	FirstFailingVersion *string                `json:"first_failing_version,omitempty"`
+}
+
+// APITestHistoryEntry is the result of a test in one task execution.
+type APITestHistoryEntry struct {
+	TaskID              *string        `json:"task_id"`
+	Execution           int            `json:"execution"`
+	TaskName            *string        `json:"task_name"`
+	BuildVariant        *string        `json:"build_variant"`
+	Version             *string        `json:"version"`
+	Revision            *string        `json:"revision"`
+	RevisionOrderNumber int            `json:"order"`
+	Requester           *string        `json:"requester"`
+	Archived            bool           `json:"archived"`
+	Status              *string        `json:"status"`
+	StartTime           *time.Time     `json:"start_time"`
+	DurationSecs        float64        `json:"duration_secs"`
+	Issues              []APIIssueLink `json:"issues,omitempty"`
+	SuspectedIssues     []APIIssueLink `json:"suspected_issues,omitempty"`
+}
+
+// APITestDurationPoint is the average duration of a test's passing results in
+// a version.
+type APITestDurationPoint struct {
+	Version             *string `json:"version"`
+	RevisionOrderNumber int     `json:"order"`
+	AverageDurationSecs float64 `json:"average_duration_secs"`
+}
+
+// BuildFromService converts from a service level test history.
+func (h *APITestHistory) BuildFromService(testName string, history model.TestHistory) {
+	h.TestName = utility.ToStringPtr(testName)
+	h.Entries = make([]APITestHistoryEntry, 0, len(history.Entries))
+	for _, entry := range history.Entries {
+		h.Entries = append(h.Entries, APITestHistoryEntry{
+			TaskID:              utility.ToStringPtr(entry.TaskID),
+			Execution:           entry.Execution,
+			TaskName:            utility.ToStringPtr(entry.TaskName),
+			BuildVariant:        utility.ToStringPtr(entry.BuildVariant),
+			Version:             utility.ToStringPtr(entry.Version),
+			Revision:            utility.ToStringPtr(entry.Revision),
+			RevisionOrderNumber: entry.RevisionOrderNumber,
+			Requester:           utility.ToStringPtr(entry.Requester),
+			Archived:            entry.Archived,
+			Status:              utility.ToStringPtr(entry.Status),
+			StartTime:           ToTimePtr(entry.StartTime),
+			DurationSecs:        entry.Duration.Seconds(),
+			Issues:              ArrtaskannotationsIssueLinkArrAPIIssueLink(entry.Issues),
+			SuspectedIssues:     ArrtaskannotationsIssueLinkArrAPIIssueLink(entry.SuspectedIssues),
+		})
+	}
+	h.DurationTrend = make([]APITestDurationPoint, 0, len(history.DurationTrend))
+	for _, point := range history.DurationTrend {
+		h.DurationTrend = append(h.DurationTrend, APITestDurationPoint{
+			Version:             utility.ToStringPtr(point.Version),
+			RevisionOrderNumber: point.RevisionOrderNumber,
+			AverageDurationSecs: point.AverageDuration.Seconds(),
+		})
+	}
+	if history.FirstFailingVersion != "" {
+		h.FirstFailingVersion = utility.ToStringPtr(history.FirstFailingVersion)
+	}
+}
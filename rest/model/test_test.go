@@ -72,6 +72,8 @@ func TestTestBuildFromService(t *testing.T) {
 					ExitCode:        1,
 					StartTime:       utility.ToPythonTime(start),
 					EndTime:         utility.ToPythonTime(end),
+					Artifacts:       []testresult.Artifact{{Name: "screenshot", Link: "https://example.com/screenshot.png"}},
+					TaskLogRange:    &testresult.LogRange{StartLine: 10, EndLine: 20},
 				}
 				otr := task.ConvertToOld(input)
 
@@ -87,11 +89,21 @@ func TestTestBuildFromService(t *testing.T) {
 						URLLobster: nil,
 						LineNum:    15,
 						LogID:      utility.ToStringPtr(input.LogID),
+						TaskLogRange: &APITestLogRange{
+							StartLine: 10,
+							EndLine:   20,
+						},
 					},
 					ExitCode:  1,
 					StartTime: utility.ToTimePtr(start),
 					EndTime:   utility.ToTimePtr(end),
 					Duration:  input.EndTime - input.StartTime,
+					Artifacts: []APITestArtifact{
+						{
+							Name: utility.ToStringPtr("screenshot"),
+							Link: utility.ToStringPtr("https://example.com/screenshot.png"),
+						},
+					},
 				}
 
 				return input, output
@@ -155,6 +155,8 @@ type APINotificationPreferences struct {
 	SpawnHostOutcomeID    *string `json:"spawn_host_outcome_id,omitempty"`
 	CommitQueue           *string `json:"commit_queue"`
 	CommitQueueID         *string `json:"commit_queue_id,omitempty"`
+	TasksBlocked          *string `json:"tasks_blocked"`
+	TasksBlockedID        *string `json:"tasks_blocked_id,omitempty"`
 }
 
 func (n *APINotificationPreferences) BuildFromService(h interface{}) error {
@@ -169,6 +171,7 @@ func (n *APINotificationPreferences) BuildFromService(h interface{}) error {
 		n.SpawnHostOutcome = utility.ToStringPtr(string(v.SpawnHostOutcome))
 		n.SpawnHostExpiration = utility.ToStringPtr(string(v.SpawnHostExpiration))
 		n.CommitQueue = utility.ToStringPtr(string(v.CommitQueue))
+		n.TasksBlocked = utility.ToStringPtr(string(v.TasksBlocked))
 		if v.BuildBreakID != "" {
 			n.BuildBreakID = utility.ToStringPtr(v.BuildBreakID)
 		}
@@ -187,6 +190,9 @@ func (n *APINotificationPreferences) BuildFromService(h interface{}) error {
 		if v.CommitQueueID != "" {
 			n.CommitQueueID = utility.ToStringPtr(v.CommitQueueID)
 		}
+		if v.TasksBlockedID != "" {
+			n.TasksBlockedID = utility.ToStringPtr(v.TasksBlockedID)
+		}
 	default:
 		return errors.Errorf("programmatic error: expected notification preferences but got type %T", h)
 	}
@@ -203,6 +209,7 @@ func (n *APINotificationPreferences) ToService() (interface{}, error) {
 	spawnHostExpiration := utility.FromStringPtr(n.SpawnHostExpiration)
 	spawnHostOutcome := utility.FromStringPtr(n.SpawnHostOutcome)
 	commitQueue := utility.FromStringPtr(n.CommitQueue)
+	tasksBlocked := utility.FromStringPtr(n.TasksBlocked)
 	if !user.IsValidSubscriptionPreference(buildBreak) {
 		return nil, errors.Errorf("invalid build break subscription preference '%s'", buildBreak)
 	}
@@ -221,6 +228,9 @@ func (n *APINotificationPreferences) ToService() (interface{}, error) {
 	if !user.IsValidSubscriptionPreference(commitQueue) {
 		return nil, errors.Errorf("invalid commit queue subscription preference '%s'", commitQueue)
 	}
+	if !user.IsValidSubscriptionPreference(tasksBlocked) {
+		return nil, errors.Errorf("invalid tasks blocked subscription preference '%s'", tasksBlocked)
+	}
 	preferences := user.NotificationPreferences{
 		BuildBreak:          user.UserSubscriptionPreference(buildBreak),
 		PatchFinish:         user.UserSubscriptionPreference(patchFinish),
@@ -228,6 +238,7 @@ func (n *APINotificationPreferences) ToService() (interface{}, error) {
 		SpawnHostOutcome:    user.UserSubscriptionPreference(spawnHostOutcome),
 		SpawnHostExpiration: user.UserSubscriptionPreference(spawnHostExpiration),
 		CommitQueue:         user.UserSubscriptionPreference(commitQueue),
+		TasksBlocked:        user.UserSubscriptionPreference(tasksBlocked),
 	}
 	preferences.BuildBreakID = utility.FromStringPtr(n.BuildBreakID)
 	preferences.PatchFinishID = utility.FromStringPtr(n.PatchFinishID)
@@ -235,6 +246,7 @@ func (n *APINotificationPreferences) ToService() (interface{}, error) {
 	preferences.SpawnHostOutcomeID = utility.FromStringPtr(n.SpawnHostOutcomeID)
 	preferences.SpawnHostExpirationID = utility.FromStringPtr(n.SpawnHostExpirationID)
 	preferences.CommitQueueID = utility.FromStringPtr(n.CommitQueueID)
+	preferences.TasksBlockedID = utility.FromStringPtr(n.TasksBlockedID)
 	return preferences, nil
 }
 
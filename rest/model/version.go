@@ -10,27 +10,90 @@ import (
 
 // APIVersion is the model to be returned by the API whenever versions are fetched.
 type APIVersion struct {
-	Id                 *string        `json:"version_id"`
-	CreateTime         *time.Time     `json:"create_time"`
-	StartTime          *time.Time     `json:"start_time"`
-	FinishTime         *time.Time     `json:"finish_time"`
-	Revision           *string        `json:"revision"`
-	Order              int            `json:"order"`
-	Project            *string        `json:"project"`
-	ProjectIdentifier  *string        `json:"project_identifier"`
-	Author             *string        `json:"author"`
-	AuthorEmail        *string        `json:"author_email"`
-	Message            *string        `json:"message"`
-	Status             *string        `json:"status"`
-	Repo               *string        `json:"repo"`
-	Branch             *string        `json:"branch"`
-	Parameters         []APIParameter `json:"parameters"`
-	BuildVariantStatus []buildDetail  `json:"build_variants_status"`
-	Builds             []APIBuild     `json:"builds,omitempty"`
-	Requester          *string        `json:"requester"`
-	Errors             []*string      `json:"errors"`
-	Activated          *bool          `json:"activated"`
-	Aborted            *bool          `json:"aborted"`
+	Id                 *string            `json:"version_id"`
+	CreateTime         *time.Time         `json:"create_time"`
+	StartTime          *time.Time         `json:"start_time"`
+	FinishTime         *time.Time         `json:"finish_time"`
+	Revision           *string            `json:"revision"`
+	Order              int                `json:"order"`
+	Project            *string            `json:"project"`
+	ProjectIdentifier  *string            `json:"project_identifier"`
+	Author             *string            `json:"author"`
+	AuthorEmail        *string            `json:"author_email"`
+	Message            *string            `json:"message"`
+	Status             *string            `json:"status"`
+	Repo               *string            `json:"repo"`
+	Branch             *string            `json:"branch"`
+	Parameters         []APIParameter     `json:"parameters"`
+	Labels             []APILabel         `json:"labels"`
+	BuildVariantStatus []buildDetail      `json:"build_variants_status"`
+	Builds             []APIBuild         `json:"builds,omitempty"`
+	Requester          *string            `json:"requester"`
+	Errors             []*string          `json:"errors"`
+	Activated          *bool              `json:"activated"`
+	Aborted            *bool              `json:"aborted"`
+	Timing             APITimingBreakdown `json:"timing"`
+	MatchedPaths       []*string          `json:"matched_paths,omitempty"`
+	// TriggerID and TriggerType are the upstream task or build that
+	// triggered the version, if any.
+	TriggerID         *string `json:"trigger_id,omitempty"`
+	TriggerType       *string `json:"trigger_type,omitempty"`
+	UpstreamVersionID *string `json:"upstream_version_id,omitempty"`
+	// DownstreamVersionIDs are the versions that this version triggered.
+	DownstreamVersionIDs []*string `json:"downstream_version_ids,omitempty"`
+	// FailureGroups groups the version's failed tasks by failure signature.
+	// It's only populated when fetching a single version.
+	FailureGroups []APIFailureGroup `json:"failure_groups,omitempty"`
+	// ExternalGates are the gates that must be opened before the version's
+	// tasks can be dispatched.
+	ExternalGates []APIVersionExternalGate `json:"external_gates,omitempty"`
+	// Stages are the statuses of the version's pipeline stages, in the order
+	// they're defined. It's only populated when fetching a single version.
+	Stages []APIVersionStage `json:"stages,omitempty"`
+}
+
+// APIVersionExternalGate is the state of one of a version's external gates.
+type APIVersionExternalGate struct {
+	Name          *string    `json:"name"`
+	BuildVariants []*string  `json:"build_variants,omitempty"`
+	State         *string    `json:"state"`
+	Deadline      *time.Time `json:"deadline,omitempty"`
+	OnTimeout     *string    `json:"on_timeout,omitempty"`
+	OpenedAt      *time.Time `json:"opened_at,omitempty"`
+	OpenedBy      *string    `json:"opened_by,omitempty"`
+}
+
+// BuildFromService converts a version's external gate to an
+// APIVersionExternalGate as of the given time.
+func (g *APIVersionExternalGate) BuildFromService(gate model.VersionExternalGate, now time.Time) {
+	g.Name = utility.ToStringPtr(gate.Name)
+	g.BuildVariants = utility.ToStringPtrSlice(gate.BuildVariants)
+	g.State = utility.ToStringPtr(gate.State(now))
+	g.Deadline = ToTimePtr(gate.Deadline)
+	g.OnTimeout = utility.ToStringPtr(gate.OnTimeout)
+	g.OpenedAt = ToTimePtr(gate.OpenedAt)
+	if gate.OpenedBy != "" {
+		g.OpenedBy = utility.ToStringPtr(gate.OpenedBy)
+	}
+}
+
+// APIVersionStage is the status of one of a version's pipeline stages.
+type APIVersionStage struct {
+	Name  *string   `json:"name"`
+	After []*string `json:"after"`
+	// Status is one of inactive, pending, running, failed, or success.
+	Status           *string        `json:"status"`
+	TaskIDs          []*string      `json:"task_ids"`
+	TaskStatusCounts map[string]int `json:"task_status_counts"`
+}
+
+// BuildFromService converts from a service level version stage status.
+func (s *APIVersionStage) BuildFromService(stage model.VersionStageStatus) {
+	s.Name = utility.ToStringPtr(stage.Name)
+	s.After = utility.ToStringPtrSlice(stage.After)
+	s.Status = utility.ToStringPtr(stage.Status)
+	s.TaskIDs = utility.ToStringPtrSlice(stage.TaskIDs)
+	s.TaskStatusCounts = stage.TaskStatusCounts
 }
 
 type buildDetail struct {
@@ -62,6 +125,22 @@ func (apiVersion *APIVersion) BuildFromService(h interface{}) error {
 	apiVersion.Errors = utility.ToStringPtrSlice(v.Errors)
 	apiVersion.Activated = v.Activated
 	apiVersion.Aborted = utility.ToBoolPtr(v.Aborted)
+	apiVersion.Timing.BuildFromService(v.Timing)
+	apiVersion.MatchedPaths = utility.ToStringPtrSlice(v.MatchedPaths)
+	if v.TriggerID != "" {
+		apiVersion.TriggerID = utility.ToStringPtr(v.TriggerID)
+		apiVersion.TriggerType = utility.ToStringPtr(v.TriggerType)
+	}
+	if v.UpstreamVersionID != "" {
+		apiVersion.UpstreamVersionID = utility.ToStringPtr(v.UpstreamVersionID)
+	}
+	apiVersion.DownstreamVersionIDs = utility.ToStringPtrSlice(v.DownstreamVersionIDs)
+	now := time.Now()
+	for _, gate := range v.ExternalGates {
+		apiGate := APIVersionExternalGate{}
+		apiGate.BuildFromService(gate, now)
+		apiVersion.ExternalGates = append(apiVersion.ExternalGates, apiGate)
+	}
 
 	var bd buildDetail
 	for _, t := range v.BuildVariants {
@@ -85,6 +164,7 @@ func (apiVersion *APIVersion) BuildFromService(h interface{}) error {
 			Value: utility.ToStringPtr(param.Value),
 		})
 	}
+	apiVersion.Labels = apiLabelsFromService(v.Labels)
 	if v.Identifier != "" {
 		identifier, err := model.GetIdentifierForProject(v.Identifier)
 		if err == nil {
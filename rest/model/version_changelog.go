@@ -0,0 +1,119 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIVersionChangelog summarizes what changed between two mainline versions.
+type APIVersionChangelog struct {
+	VersionID        *string                   `json:"version_id"`
+	BaseVersionID    *string                   `json:"base_version_id"`
+	Commits          []APIChangelogCommit      `json:"commits"`
+	CommitsTruncated bool                      `json:"commits_truncated"`
+	Modules          []APIChangelogModule      `json:"modules"`
+	NewFailures      []APIChangelogTask        `json:"new_failures"`
+	Fixed            []APIChangelogTask        `json:"fixed"`
+	ConfigChanges    APIChangelogConfigChanges `json:"config_changes"`
+}
+
+// APIChangelogCommit is a commit in a changelog.
+type APIChangelogCommit struct {
+	VersionID   *string    `json:"version_id"`
+	Revision    *string    `json:"revision"`
+	Author      *string    `json:"author"`
+	AuthorEmail *string    `json:"author_email"`
+	Message     *string    `json:"message"`
+	CreateTime  *time.Time `json:"create_time"`
+}
+
+// APIChangelogModule is a module whose revision changed between the versions.
+type APIChangelogModule struct {
+	Name         *string `json:"name"`
+	Owner        *string `json:"owner"`
+	Repo         *string `json:"repo"`
+	Branch       *string `json:"branch"`
+	BaseRevision *string `json:"base_revision"`
+	Revision     *string `json:"revision"`
+}
+
+// APIChangelogTask is a task whose outcome changed between the versions.
+type APIChangelogTask struct {
+	TaskID       *string `json:"task_id"`
+	BaseTaskID   *string `json:"base_task_id"`
+	BuildVariant *string `json:"build_variant"`
+	DisplayName  *string `json:"display_name"`
+	Status       *string `json:"status"`
+	BaseStatus   *string `json:"base_status"`
+}
+
+// APIChangelogConfigChanges are the build variants and tasks that changed in
+// the project config between the versions.
+type APIChangelogConfigChanges struct {
+	AddedBuildVariants    []string `json:"added_build_variants"`
+	RemovedBuildVariants  []string `json:"removed_build_variants"`
+	ModifiedBuildVariants []string `json:"modified_build_variants"`
+	AddedTasks            []string `json:"added_tasks"`
+	RemovedTasks          []string `json:"removed_tasks"`
+	ModifiedTasks         []string `json:"modified_tasks"`
+}
+
+// BuildFromService converts from a service level version changelog.
+func (c *APIVersionChangelog) BuildFromService(changelog model.VersionChangelog) {
+	c.VersionID = utility.ToStringPtr(changelog.VersionID)
+	c.BaseVersionID = utility.ToStringPtr(changelog.BaseVersionID)
+	c.CommitsTruncated = changelog.CommitsTruncated
+
+	c.Commits = []APIChangelogCommit{}
+	for _, commit := range changelog.Commits {
+		c.Commits = append(c.Commits, APIChangelogCommit{
+			VersionID:   utility.ToStringPtr(commit.VersionID),
+			Revision:    utility.ToStringPtr(commit.Revision),
+			Author:      utility.ToStringPtr(commit.Author),
+			AuthorEmail: utility.ToStringPtr(commit.AuthorEmail),
+			Message:     utility.ToStringPtr(commit.Message),
+			CreateTime:  ToTimePtr(commit.CreateTime),
+		})
+	}
+
+	c.Modules = []APIChangelogModule{}
+	for _, module := range changelog.Modules {
+		c.Modules = append(c.Modules, APIChangelogModule{
+			Name:         utility.ToStringPtr(module.Name),
+			Owner:        utility.ToStringPtr(module.Owner),
+			Repo:         utility.ToStringPtr(module.Repo),
+			Branch:       utility.ToStringPtr(module.Branch),
+			BaseRevision: utility.ToStringPtr(module.BaseRevision),
+			Revision:     utility.ToStringPtr(module.Revision),
+		})
+	}
+
+	c.NewFailures = buildAPIChangelogTasks(changelog.NewFailures)
+	c.Fixed = buildAPIChangelogTasks(changelog.Fixed)
+
+	c.ConfigChanges = APIChangelogConfigChanges{
+		AddedBuildVariants:    changelog.ConfigChanges.AddedBuildVariants,
+		RemovedBuildVariants:  changelog.ConfigChanges.RemovedBuildVariants,
+		ModifiedBuildVariants: changelog.ConfigChanges.ModifiedBuildVariants,
+		AddedTasks:            changelog.ConfigChanges.AddedTasks,
+		RemovedTasks:          changelog.ConfigChanges.RemovedTasks,
+		ModifiedTasks:         changelog.ConfigChanges.ModifiedTasks,
+	}
+}
+
+func buildAPIChangelogTasks(tasks []model.ChangelogTask) []APIChangelogTask {
+	apiTasks := []APIChangelogTask{}
+	for _, t := range tasks {
+		apiTasks = append(apiTasks, APIChangelogTask{
+			TaskID:       utility.ToStringPtr(t.TaskID),
+			BaseTaskID:   utility.ToStringPtr(t.BaseTaskID),
+			BuildVariant: utility.ToStringPtr(t.BuildVariant),
+			DisplayName:  utility.ToStringPtr(t.DisplayName),
+			Status:       utility.ToStringPtr(t.Status),
+			BaseStatus:   utility.ToStringPtr(t.BaseStatus),
+		})
+	}
+	return apiTasks
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIVersionSnapshot is a consistent view of a version's, builds', and
+// tasks' statuses as of a single point in time.
+type APIVersionSnapshot struct {
+	// AsOf is the logical time of the snapshot. Passing it as the as_of
+	// parameter of the next request returns a snapshot that is at least as
+	// recent as this one.
+	AsOf      *string                   `json:"as_of"`
+	VersionID *string                   `json:"version_id"`
+	Status    *string                   `json:"status"`
+	Activated *bool                     `json:"activated"`
+	Aborted   bool                      `json:"aborted"`
+	Builds    []APIVersionSnapshotBuild `json:"builds"`
+	Tasks     []APIVersionSnapshotTask  `json:"tasks"`
+}
+
+// APIVersionSnapshotBuild is the state of a build in a version snapshot.
+type APIVersionSnapshotBuild struct {
+	Id              *string `json:"id"`
+	BuildVariant    *string `json:"build_variant"`
+	DisplayName     *string `json:"display_name"`
+	Status          *string `json:"status"`
+	Activated       bool    `json:"activated"`
+	Aborted         bool    `json:"aborted"`
+	AllTasksBlocked bool    `json:"all_tasks_blocked"`
+}
+
+// APIVersionSnapshotTask is the state of a task in a version snapshot.
+type APIVersionSnapshotTask struct {
+	Id            *string `json:"id"`
+	Execution     int     `json:"execution"`
+	DisplayName   *string `json:"display_name"`
+	BuildId       *string `json:"build_id"`
+	BuildVariant  *string `json:"build_variant"`
+	Status        *string `json:"status"`
+	DisplayStatus *string `json:"display_status"`
+	Activated     bool    `json:"activated"`
+	DisplayOnly   bool    `json:"display_only"`
+}
+
+// BuildFromService converts from a service level version snapshot.
+func (s *APIVersionSnapshot) BuildFromService(snapshot model.VersionSnapshot) {
+	s.AsOf = utility.ToStringPtr(model.FormatSnapshotTime(snapshot.AsOf))
+	s.VersionID = utility.ToStringPtr(snapshot.Version.Id)
+	s.Status = utility.ToStringPtr(snapshot.Version.Status)
+	s.Activated = snapshot.Version.Activated
+	s.Aborted = snapshot.Version.Aborted
+
+	s.Builds = make([]APIVersionSnapshotBuild, 0, len(snapshot.Builds))
+	for _, b := range snapshot.Builds {
+		s.Builds = append(s.Builds, APIVersionSnapshotBuild{
+			Id:              utility.ToStringPtr(b.Id),
+			BuildVariant:    utility.ToStringPtr(b.BuildVariant),
+			DisplayName:     utility.ToStringPtr(b.DisplayName),
+			Status:          utility.ToStringPtr(b.Status),
+			Activated:       b.Activated,
+			Aborted:         b.Aborted,
+			AllTasksBlocked: b.AllTasksBlocked,
+		})
+	}
+
+	s.Tasks = make([]APIVersionSnapshotTask, 0, len(snapshot.Tasks))
+	for _, t := range snapshot.Tasks {
+		s.Tasks = append(s.Tasks, APIVersionSnapshotTask{
+			Id:            utility.ToStringPtr(t.Id),
+			Execution:     t.Execution,
+			DisplayName:   utility.ToStringPtr(t.DisplayName),
+			BuildId:       utility.ToStringPtr(t.BuildId),
+			BuildVariant:  utility.ToStringPtr(t.BuildVariant),
+			Status:        utility.ToStringPtr(t.Status),
+			DisplayStatus: utility.ToStringPtr(t.GetDisplayStatus()),
+			Activated:     t.Activated,
+			DisplayOnly:   t.DisplayOnly,
+		})
+	}
+}
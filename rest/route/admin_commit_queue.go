@@ -0,0 +1,168 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const defaultAdminDequeueReason = "dequeued by an admin"
+
+// findCommitQueueForAdmin returns the commit queue of the project with the
+// given ID or identifier.
+func findCommitQueueForAdmin(projectID string) (*commitqueue.CommitQueue, error) {
+	id, err := dbModel.GetIdForProject(projectID)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", projectID),
+		}
+	}
+	cq, err := commitqueue.FindOneId(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding commit queue for project '%s'", id)
+	}
+	if cq == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("commit queue for project '%s' not found", id),
+		}
+	}
+	return cq, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/commit_queues/{project_id}/dequeue
+
+type adminCommitQueueDequeueHandler struct {
+	TaskID string `json:"task_id"`
+	Reason string `json:"reason"`
+	DryRun bool   `json:"dry_run"`
+
+	projectID string
+}
+
+func makeAdminCommitQueueDequeue() gimlet.RouteHandler {
+	return &adminCommitQueueDequeueHandler{}
+}
+
+func (h *adminCommitQueueDequeueHandler) Factory() gimlet.RouteHandler {
+	return &adminCommitQueueDequeueHandler{}
+}
+
+func (h *adminCommitQueueDequeueHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	if err := gimlet.GetJSON(r.Body, h); err != nil {
+		return errors.Wrap(err, "parsing request body")
+	}
+	if h.TaskID == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify the task to dequeue the commit queue item for",
+		}
+	}
+	if h.Reason == "" {
+		h.Reason = defaultAdminDequeueReason
+	}
+	return nil
+}
+
+// Run dequeues the commit queue item of the task's version and restarts the
+// items after it. In a dry run, it only reports what it would do.
+func (h *adminCommitQueueDequeueHandler) Run(ctx context.Context) gimlet.Responder {
+	cq, err := findCommitQueueForAdmin(h.projectID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	t, err := task.FindOneId(h.TaskID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", h.TaskID))
+	}
+	if t == nil || t.Project != cq.ProjectID {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found in project '%s'", h.TaskID, cq.ProjectID),
+		})
+	}
+
+	plan, err := dbModel.PlanDequeueAndRestartForTask(cq, t, message.GithubStateFailure, h.Reason)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "planning dequeue and restart").Error(),
+		})
+	}
+	if !h.DryRun {
+		u := MustHaveUser(ctx)
+		if err = dbModel.DequeueAndRestartForTask(cq, t, message.GithubStateFailure, u.Username(), h.Reason); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "dequeuing and restarting for task '%s'", t.Id))
+		}
+	}
+
+	apiPlan := model.APIDequeueAndRestartPlan{}
+	apiPlan.BuildFromService(*plan)
+	return gimlet.NewJSONResponse(struct {
+		DryRun bool                           `json:"dry_run"`
+		Plan   model.APIDequeueAndRestartPlan `json:"plan"`
+	}{h.DryRun, apiPlan})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/commit_queues/{project_id}/repair
+
+type adminCommitQueueRepairHandler struct {
+	DryRun bool `json:"dry_run"`
+
+	projectID string
+}
+
+func makeAdminCommitQueueRepair() gimlet.RouteHandler {
+	return &adminCommitQueueRepairHandler{}
+}
+
+func (h *adminCommitQueueRepairHandler) Factory() gimlet.RouteHandler {
+	return &adminCommitQueueRepairHandler{}
+}
+
+func (h *adminCommitQueueRepairHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	if r.ContentLength == 0 {
+		return nil
+	}
+	return errors.Wrap(gimlet.GetJSON(r.Body, h), "parsing request body")
+}
+
+// Run fixes broken merge task dependency chains in the project's commit
+// queue. In a dry run, it only reports the broken dependencies.
+func (h *adminCommitQueueRepairHandler) Run(ctx context.Context) gimlet.Responder {
+	cq, err := findCommitQueueForAdmin(h.projectID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	var changes []dbModel.MergeTaskDependencyChange
+	if h.DryRun {
+		changes, err = dbModel.FindBrokenMergeTaskDependencies(*cq)
+	} else {
+		u := MustHaveUser(ctx)
+		changes, err = dbModel.RepairMergeTaskDependencies(*cq, u.Username())
+	}
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "repairing merge task dependencies for commit queue '%s'", cq.ProjectID))
+	}
+
+	return gimlet.NewJSONResponse(model.APICommitQueueRepairResult{
+		DryRun:  h.DryRun,
+		Changes: model.BuildMergeTaskDependencyChanges(changes),
+	})
+}
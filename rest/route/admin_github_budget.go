@@ -0,0 +1,47 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/github_budget
+
+type githubBudgetHandler struct{}
+
+func makeGetGithubBudget() gimlet.RouteHandler {
+	return &githubBudgetHandler{}
+}
+
+func (h *githubBudgetHandler) Factory() gimlet.RouteHandler {
+	return &githubBudgetHandler{}
+}
+
+func (h *githubBudgetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+// Run returns the shared budget of the GitHub status sender and the remaining
+// GitHub rate limit of each installation as tracked by the app server that
+// handles the request, along with how many calls of each priority were made
+// and deferred.
+func (h *githubBudgetHandler) Run(ctx context.Context) gimlet.Responder {
+	budgets, err := thirdparty.GetGithubBudgetStatus()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting GitHub budgets"))
+	}
+	resp := make([]model.APIGithubBudget, 0, len(budgets))
+	for _, budget := range budgets {
+		apiBudget := model.APIGithubBudget{}
+		apiBudget.BuildFromService(budget)
+		resp = append(resp, apiBudget)
+	}
+	return gimlet.NewJSONResponse(resp)
+}
@@ -0,0 +1,39 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/load_shedder
+
+type loadShedderStatusHandler struct {
+	env evergreen.Environment
+}
+
+func makeGetLoadShedderStatus(env evergreen.Environment) gimlet.RouteHandler {
+	return &loadShedderStatusHandler{env: env}
+}
+
+func (h *loadShedderStatusHandler) Factory() gimlet.RouteHandler {
+	return &loadShedderStatusHandler{env: h.env}
+}
+
+func (h *loadShedderStatusHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+// Run returns the app server's most recent load sample and whether it's
+// shedding load.
+func (h *loadShedderStatusHandler) Run(ctx context.Context) gimlet.Responder {
+	status := model.APILoadStatus{}
+	status.BuildFromService(dbModel.GetLoadShedder().Status(h.env))
+	return gimlet.NewJSONResponse(status)
+}
@@ -0,0 +1,91 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/maintenance_mode
+
+type maintenanceModeGetHandler struct {
+	env evergreen.Environment
+}
+
+func makeGetMaintenanceMode(env evergreen.Environment) gimlet.RouteHandler {
+	return &maintenanceModeGetHandler{env: env}
+}
+
+func (h *maintenanceModeGetHandler) Factory() gimlet.RouteHandler {
+	return &maintenanceModeGetHandler{env: h.env}
+}
+
+func (h *maintenanceModeGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+// Run returns whether the app servers are in maintenance mode and the tasks
+// that are still running.
+func (h *maintenanceModeGetHandler) Run(ctx context.Context) gimlet.Responder {
+	status, err := dbModel.GetDrainStatus(h.env)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting drain status"))
+	}
+	apiStatus := model.APIDrainStatus{}
+	apiStatus.BuildFromService(*status)
+	return gimlet.NewJSONResponse(apiStatus)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/maintenance_mode
+
+type maintenanceModePostHandler struct {
+	Enabled        bool `json:"enabled"`
+	RetryAfterSecs int  `json:"retry_after_secs"`
+
+	env evergreen.Environment
+}
+
+func makeSetMaintenanceMode(env evergreen.Environment) gimlet.RouteHandler {
+	return &maintenanceModePostHandler{env: env}
+}
+
+func (h *maintenanceModePostHandler) Factory() gimlet.RouteHandler {
+	return &maintenanceModePostHandler{env: h.env}
+}
+
+func (h *maintenanceModePostHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := gimlet.GetJSON(r.Body, h); err != nil {
+		return errors.Wrap(err, "parsing request body")
+	}
+	if h.RetryAfterSecs < 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "retry interval cannot be negative",
+		}
+	}
+	return nil
+}
+
+// Run turns maintenance mode on or off and returns the drain status.
+func (h *maintenanceModePostHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	if err := dbModel.SetMaintenanceMode(h.env, h.Enabled, h.RetryAfterSecs, u.Username()); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "setting maintenance mode"))
+	}
+	status, err := dbModel.GetDrainStatus(h.env)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting drain status"))
+	}
+	apiStatus := model.APIDrainStatus{}
+	apiStatus.BuildFromService(*status)
+	return gimlet.NewJSONResponse(apiStatus)
+}
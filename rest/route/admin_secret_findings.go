@@ -0,0 +1,92 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/secretscan"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSecretFindingsLimit = 100
+	maxSecretFindingsLimit     = 1000
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/secret_findings
+
+type secretFindingsHandler struct {
+	opts secretscan.FindOptions
+}
+
+func makeGetSecretFindings() gimlet.RouteHandler {
+	return &secretFindingsHandler{}
+}
+
+func (h *secretFindingsHandler) Factory() gimlet.RouteHandler {
+	return &secretFindingsHandler{}
+}
+
+func (h *secretFindingsHandler) Parse(ctx context.Context, r *http.Request) error {
+	vals := r.URL.Query()
+	h.opts = secretscan.FindOptions{
+		TaskID: vals.Get("task_id"),
+		Limit:  defaultSecretFindingsLimit,
+	}
+	if project := vals.Get("project_id"); project != "" {
+		projectID, err := dbModel.GetIdForProject(project)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    errors.Wrapf(err, "finding project '%s'", project).Error(),
+			}
+		}
+		h.opts.ProjectID = projectID
+	}
+	if since := vals.Get("since"); since != "" {
+		var err error
+		h.opts.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    errors.Wrapf(err, "parsing since time '%s', must be in RFC3339 format", since).Error(),
+			}
+		}
+	}
+	if limit := vals.Get("limit"); limit != "" {
+		var err error
+		h.opts.Limit, err = strconv.Atoi(limit)
+		if err != nil || h.opts.Limit <= 0 || h.opts.Limit > maxSecretFindingsLimit {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("limit must be a positive integer no greater than %d", maxSecretFindingsLimit),
+			}
+		}
+	}
+	return nil
+}
+
+// Run returns the secrets that were found and redacted in task uploads, most
+// recent first. The secrets themselves are never returned.
+func (h *secretFindingsHandler) Run(ctx context.Context) gimlet.Responder {
+	findings, err := secretscan.Find(h.opts)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "finding secret scan findings"))
+	}
+
+	resp := make([]model.APISecretFinding, 0, len(findings))
+	for _, finding := range findings {
+		apiFinding := model.APISecretFinding{}
+		apiFinding.BuildFromService(finding)
+		resp = append(resp, apiFinding)
+	}
+	return gimlet.NewJSONResponse(resp)
+}
@@ -2,11 +2,19 @@ package route
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // GET /rest/v2/agent/cedar_config
@@ -32,11 +40,128 @@ func (h *agentCedarConfig) Parse(ctx context.Context, r *http.Request) error {
 }
 
 func (h *agentCedarConfig) Run(ctx context.Context) gimlet.Responder {
-	data := apimodels.CedarConfig{
-		BaseURL:  h.settings.Cedar.BaseURL,
-		RPCPort:  h.settings.Cedar.RPCPort,
-		Username: h.settings.Cedar.User,
-		APIKey:   h.settings.Cedar.APIKey,
+	return gimlet.NewJSONResponse(cedarConfigFromSettings(h.settings))
+}
+
+func cedarConfigFromSettings(settings *evergreen.Settings) apimodels.CedarConfig {
+	return apimodels.CedarConfig{
+		BaseURL:  settings.Cedar.BaseURL,
+		RPCPort:  settings.Cedar.RPCPort,
+		Username: settings.Cedar.User,
+		APIKey:   settings.Cedar.APIKey,
+	}
+}
+
+// GET /rest/v2/tasks/{task_id}/bootstrap
+
+type taskBootstrapHandler struct {
+	taskID   string
+	settings *evergreen.Settings
+}
+
+func makeTaskBootstrapHandler(settings *evergreen.Settings) gimlet.RouteHandler {
+	return &taskBootstrapHandler{
+		settings: settings,
+	}
+}
+
+func (rh *taskBootstrapHandler) Factory() gimlet.RouteHandler {
+	return &taskBootstrapHandler{
+		settings: rh.settings,
+	}
+}
+
+func (rh *taskBootstrapHandler) Parse(ctx context.Context, r *http.Request) error {
+	rh.taskID = gimlet.GetVars(r)["task_id"]
+	return nil
+}
+
+// Run returns everything the agent needs from the app server to set up the
+// task, which would otherwise take a separate request for each of the task,
+// its project, its expansions, its host's distro and the Cedar config.
+func (rh *taskBootstrapHandler) Run(ctx context.Context) gimlet.Responder {
+	t, err := task.FindOneId(rh.taskID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", rh.taskID))
 	}
-	return gimlet.NewJSONResponse(data)
+	if t == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found", rh.taskID),
+		})
+	}
+
+	v, err := dbModel.VersionFindOne(dbModel.VersionById(t.Version))
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version '%s'", t.Version))
+	}
+	if v == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' not found", t.Version),
+		})
+	}
+	pp, err := dbModel.FindParserProjectForVersion(v)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding parser project for version '%s'", v.Id))
+	}
+	ppBytes, err := bson.Marshal(pp)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "marshalling parser project to BSON"))
+	}
+
+	projectRef, err := dbModel.FindMergedProjectRef(t.Project, t.Version, true)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project ref '%s'", t.Project))
+	}
+	if projectRef == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project ref '%s' not found", t.Project),
+		})
+	}
+
+	h, err := host.FindOneId(t.HostId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding host '%s'", t.HostId))
+	}
+	if h == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("host '%s' running task '%s' not found", t.HostId, t.Id),
+		})
+	}
+
+	oauthToken, err := rh.settings.GetGithubOauthToken()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting GitHub OAuth token"))
+	}
+	expansions, err := dbModel.PopulateExpansions(t, h, oauthToken)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "populating expansions"))
+	}
+	expVars, err := dbModel.FindExpansionVarsForTask(t)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding expansion vars for task '%s'", t.Id))
+	}
+
+	// The agent bootstraps the task before it starts setting it up.
+	if t.Status == evergreen.TaskDispatched {
+		event.LogTaskAgentAccepted(t.Id, t.Execution, t.HostId)
+	}
+
+	return gimlet.NewJSONResponse(model.APITaskBootstrap{
+		SchemaVersion: model.TaskBootstrapSchemaVersion,
+		Task:          t,
+		ParserProject: ppBytes,
+		ProjectRef:    projectRef,
+		DistroView: apimodels.DistroView{
+			CloneMethod:         h.Distro.CloneMethod,
+			DisableShallowClone: h.Distro.DisableShallowClone,
+			WorkDir:             h.Distro.WorkDir,
+		},
+		Expansions:    expansions,
+		ExpansionVars: *expVars,
+		Cedar:         cedarConfigFromSettings(rh.settings),
+	})
 }
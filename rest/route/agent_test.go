@@ -7,9 +7,17 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/mongodb/grip/send"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func TestAgentCedarConfig(t *testing.T) {
@@ -84,3 +92,111 @@ func TestAgentCedarConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskBootstrap(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, dbModel.VersionCollection, dbModel.ParserProjectCollection,
+		dbModel.ProjectRefCollection, dbModel.ProjectVarsCollection, host.Collection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, dbModel.VersionCollection, dbModel.ParserProjectCollection,
+			dbModel.ProjectRefCollection, dbModel.ProjectVarsCollection, host.Collection, event.AllLogCollection))
+	}()
+
+	tsk := &task.Task{
+		Id:           "t1",
+		Project:      "p1",
+		Version:      "v1",
+		BuildVariant: "bv",
+		DisplayName:  "compile",
+		HostId:       "h1",
+		Status:       evergreen.TaskDispatched,
+	}
+	require.NoError(t, tsk.Insert())
+	v := &dbModel.Version{
+		Id: "v1",
+		Config: `
+tasks:
+- name: compile
+buildvariants:
+- name: bv
+  run_on: d1
+  tasks:
+  - name: compile
+`,
+		ExpansionOverrides: map[string]string{"overridden": "from_version"},
+	}
+	require.NoError(t, v.Insert())
+	pRef := &dbModel.ProjectRef{
+		Id:         "p1",
+		Identifier: "project",
+		Owner:      "owner",
+		Repo:       "repo",
+		Branch:     "main",
+	}
+	require.NoError(t, pRef.Insert())
+	vars := &dbModel.ProjectVars{
+		Id:          "p1",
+		Vars:        map[string]string{"secret": "shh", "overridden": "from_vars"},
+		PrivateVars: map[string]bool{"secret": true},
+	}
+	_, err := vars.Upsert()
+	require.NoError(t, err)
+	h := &host.Host{
+		Id: "h1",
+		Distro: distro.Distro{
+			Id:          "d1",
+			CloneMethod: distro.CloneMethodOAuth,
+			WorkDir:     "/data",
+		},
+	}
+	require.NoError(t, h.Insert())
+
+	settings := &evergreen.Settings{
+		Credentials: map[string]string{"github": "token abc"},
+		Cedar: evergreen.CedarConfig{
+			BaseURL: "cedar.example.com",
+			RPCPort: "7070",
+		},
+	}
+	rh, ok := makeTaskBootstrapHandler(settings).(*taskBootstrapHandler)
+	require.True(t, ok)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("ReturnsAllTaskConfig", func(t *testing.T) {
+		rh.taskID = tsk.Id
+		resp := rh.Run(ctx)
+		require.NotZero(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		bootstrap, ok := resp.Data().(model.APITaskBootstrap)
+		require.True(t, ok)
+
+		assert.Equal(t, model.TaskBootstrapSchemaVersion, bootstrap.SchemaVersion)
+		require.NotNil(t, bootstrap.Task)
+		assert.Equal(t, tsk.Id, bootstrap.Task.Id)
+		require.NotNil(t, bootstrap.ProjectRef)
+		assert.Equal(t, pRef.Identifier, bootstrap.ProjectRef.Identifier)
+		assert.Equal(t, apimodels.DistroView{CloneMethod: distro.CloneMethodOAuth, WorkDir: "/data"}, bootstrap.DistroView)
+		assert.Equal(t, "d1", bootstrap.Expansions.Get("distro_id"))
+		assert.Equal(t, "shh", bootstrap.ExpansionVars.Vars["secret"])
+		assert.Equal(t, "from_version", bootstrap.ExpansionVars.Vars["overridden"])
+		assert.True(t, bootstrap.ExpansionVars.PrivateVars["secret"])
+		assert.Equal(t, "cedar.example.com", bootstrap.Cedar.BaseURL)
+
+		project, err := dbModel.GetProjectFromBSON(bootstrap.ParserProject)
+		require.NoError(t, err)
+		assert.NotNil(t, project.FindProjectTask("compile"))
+	})
+	t.Run("FailsWithNonexistentTask", func(t *testing.T) {
+		rh.taskID = "nonexistent"
+		resp := rh.Run(ctx)
+		require.NotZero(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.Status())
+	})
+	t.Run("FailsWithNonexistentHost", func(t *testing.T) {
+		require.NoError(t, db.Update(task.Collection, bson.M{task.IdKey: tsk.Id}, bson.M{"$set": bson.M{task.HostIdKey: "nonexistent"}}))
+		rh.taskID = tsk.Id
+		resp := rh.Run(ctx)
+		require.NotZero(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
@@ -0,0 +1,311 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/annotations"
+	"github.com/evergreen-ci/evergreen/model/task"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/tasks/{task_id}/annotation/attachments
+
+type annotationAttachmentPostHandler struct {
+	taskId     string
+	execution  *int
+	attachment apimodels.AnnotationAttachment
+	user       gimlet.User
+}
+
+func makePostAnnotationAttachment() gimlet.RouteHandler {
+	return &annotationAttachmentPostHandler{}
+}
+
+func (h *annotationAttachmentPostHandler) Factory() gimlet.RouteHandler {
+	return &annotationAttachmentPostHandler{}
+}
+
+func (h *annotationAttachmentPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.taskId = gimlet.GetVars(r)["task_id"]
+	if h.taskId == "" {
+		return errors.New("task ID cannot be empty")
+	}
+	if h.execution, err = parseAnnotationAttachmentExecution(r); err != nil {
+		return err
+	}
+
+	body := utility.NewRequestReader(r)
+	defer body.Close()
+	if err = gimlet.GetJSON(body, &h.attachment); err != nil {
+		return errors.Wrap(err, "reading attachment from JSON request body")
+	}
+
+	h.user = MustHaveUser(ctx)
+	return nil
+}
+
+func (h *annotationAttachmentPostHandler) Run(ctx context.Context) gimlet.Responder {
+	t, resp := findAnnotationAttachmentTask(h.taskId, h.execution)
+	if resp != nil {
+		return resp
+	}
+	if !evergreen.IsFailedTaskStatus(t.Status) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("cannot add attachment to annotation when task status is '%s'", t.Status),
+		})
+	}
+
+	return addAnnotationAttachment(ctx, h.taskId, t.Execution, h.attachment, &annotations.Source{
+		Author:    h.user.DisplayName(),
+		Time:      time.Now(),
+		Requester: annotations.APIRequester,
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/tasks/{task_id}/annotation_attachments
+
+// annotationAttachmentAgentPostHandler attaches a file to the annotation for
+// the current execution of the agent's task.
+type annotationAttachmentAgentPostHandler struct {
+	taskId     string
+	attachment apimodels.AnnotationAttachment
+}
+
+func makeAgentPostAnnotationAttachment() gimlet.RouteHandler {
+	return &annotationAttachmentAgentPostHandler{}
+}
+
+func (h *annotationAttachmentAgentPostHandler) Factory() gimlet.RouteHandler {
+	return &annotationAttachmentAgentPostHandler{}
+}
+
+func (h *annotationAttachmentAgentPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.taskId = gimlet.GetVars(r)["task_id"]
+
+	body := utility.NewRequestReader(r)
+	defer body.Close()
+	if err := gimlet.GetJSON(body, &h.attachment); err != nil {
+		return errors.Wrap(err, "reading attachment from JSON request body")
+	}
+	return nil
+}
+
+func (h *annotationAttachmentAgentPostHandler) Run(ctx context.Context) gimlet.Responder {
+	t, resp := findAnnotationAttachmentTask(h.taskId, nil)
+	if resp != nil {
+		return resp
+	}
+
+	return addAnnotationAttachment(ctx, t.Id, t.Execution, h.attachment, &annotations.Source{
+		Time:      time.Now(),
+		Requester: annotations.AgentRequester,
+	})
+}
+
+// addAnnotationAttachment adds the attachment to the annotation for the task
+// execution. Old executions are identified by the ID of the latest execution,
+// like the annotations themselves.
+func addAnnotationAttachment(ctx context.Context, taskId string, execution int, attachment apimodels.AnnotationAttachment, source *annotations.Source) gimlet.Responder {
+	settings := evergreen.GetEnvironment().Settings()
+	contentType, err := annotations.ValidateAttachment(settings.AnnotationAttachments, attachment.Name, attachment.ContentType, attachment.Data)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid attachment").Error(),
+		})
+	}
+
+	a, err := annotations.AddAttachment(ctx, settings, taskId, execution, annotations.Attachment{
+		Name:        attachment.Name,
+		ContentType: contentType,
+		Source:      source,
+	}, attachment.Data)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "adding attachment to annotation for task '%s'", taskId))
+	}
+
+	apiAttachment := restModel.APIAttachmentBuildFromService(*a)
+	store, err := annotations.NewAttachmentStore(a.Backend, settings, thirdparty.PreSignObject)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting attachment store"))
+	}
+	signedURL, err := store.SignedURL(*a)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "signing URL for attachment '%s'", a.Id))
+	}
+	apiAttachment.URL = utility.ToStringPtr(signedURL)
+
+	return gimlet.NewJSONResponse(apiAttachment)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/tasks/{task_id}/annotation/attachments/{attachment_id}
+
+type annotationAttachmentDeleteHandler struct {
+	taskId       string
+	attachmentId string
+	execution    *int
+}
+
+func makeDeleteAnnotationAttachment() gimlet.RouteHandler {
+	return &annotationAttachmentDeleteHandler{}
+}
+
+func (h *annotationAttachmentDeleteHandler) Factory() gimlet.RouteHandler {
+	return &annotationAttachmentDeleteHandler{}
+}
+
+func (h *annotationAttachmentDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	vars := gimlet.GetVars(r)
+	h.taskId = vars["task_id"]
+	if h.taskId == "" {
+		return errors.New("task ID cannot be empty")
+	}
+	h.attachmentId = vars["attachment_id"]
+	if h.attachmentId == "" {
+		return errors.New("attachment ID cannot be empty")
+	}
+	h.execution, err = parseAnnotationAttachmentExecution(r)
+	return err
+}
+
+func (h *annotationAttachmentDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	t, resp := findAnnotationAttachmentTask(h.taskId, h.execution)
+	if resp != nil {
+		return resp
+	}
+
+	annotation, err := annotations.FindOneByTaskIdAndExecution(h.taskId, t.Execution)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding annotation for task '%s'", h.taskId))
+	}
+	if annotation == nil || annotation.GetAttachment(h.attachmentId) == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("attachment '%s' not found for task '%s' execution %d", h.attachmentId, h.taskId, t.Execution),
+		})
+	}
+
+	if err = annotations.RemoveAttachment(ctx, evergreen.GetEnvironment().Settings(), h.taskId, t.Execution, h.attachmentId); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "removing attachment '%s'", h.attachmentId))
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/annotation_attachments/{attachment_id}
+
+// annotationAttachmentGetHandler serves the contents of attachments that are
+// stored in the database. The request is authorized by the URL's signature
+// rather than by a user, so that the URL can be used directly by a browser.
+type annotationAttachmentGetHandler struct {
+	attachmentId string
+	expires      int64
+	signature    string
+}
+
+func makeGetAnnotationAttachment() gimlet.RouteHandler {
+	return &annotationAttachmentGetHandler{}
+}
+
+func (h *annotationAttachmentGetHandler) Factory() gimlet.RouteHandler {
+	return &annotationAttachmentGetHandler{}
+}
+
+func (h *annotationAttachmentGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.attachmentId = gimlet.GetVars(r)["attachment_id"]
+	vals := r.URL.Query()
+	h.signature = vals.Get("signature")
+	if h.signature == "" {
+		return errors.New("signature must be specified")
+	}
+	h.expires, err = strconv.ParseInt(vals.Get("expires"), 10, 64)
+	return errors.Wrap(err, "parsing expiration time")
+}
+
+func (h *annotationAttachmentGetHandler) Run(ctx context.Context) gimlet.Responder {
+	settings := evergreen.GetEnvironment().Settings()
+	if err := annotations.CheckAttachmentSignature(settings.AnnotationAttachments.SigningKey, h.attachmentId, h.expires, h.signature); err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    err.Error(),
+		})
+	}
+
+	a, err := annotations.FindAttachment(h.attachmentId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding attachment '%s'", h.attachmentId))
+	}
+	if a == nil || a.Backend != evergreen.AnnotationAttachmentBackendDB {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("attachment '%s' not found", h.attachmentId),
+		})
+	}
+
+	store, err := annotations.NewAttachmentStore(a.Backend, settings, thirdparty.PreSignObject)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting attachment store"))
+	}
+	data, err := store.Get(ctx, a.Key)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting contents of attachment '%s'", h.attachmentId))
+	}
+
+	return gimlet.NewBinaryResponse(data)
+}
+
+// parseAnnotationAttachmentExecution returns the task execution requested in
+// the query parameters, or nil if the latest execution is requested.
+func parseAnnotationAttachmentExecution(r *http.Request) (*int, error) {
+	executionString := r.URL.Query().Get("execution")
+	if executionString == "" {
+		return nil, nil
+	}
+	execution, err := strconv.Atoi(executionString)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing task execution")
+	}
+	return &execution, nil
+}
+
+func findAnnotationAttachmentTask(taskId string, execution *int) (*task.Task, gimlet.Responder) {
+	var t *task.Task
+	var err error
+	if execution == nil {
+		t, err = task.FindOneId(taskId)
+	} else {
+		t, err = task.FindOneIdAndExecution(taskId, *execution)
+	}
+	if err != nil {
+		return nil, gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", taskId))
+	}
+	if t == nil {
+		return nil, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found", taskId),
+		})
+	}
+	return t, nil
+}
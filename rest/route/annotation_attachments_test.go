@@ -0,0 +1,139 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/annotations"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationAttachmentHandlers(t *testing.T) {
+	require.NoError(t, db.ClearCollections(annotations.Collection, annotations.AttachmentContentsCollection, task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(annotations.Collection, annotations.AttachmentContentsCollection, task.Collection))
+	}()
+
+	settings := evergreen.GetEnvironment().Settings()
+	oldConf := settings.AnnotationAttachments
+	settings.AnnotationAttachments = evergreen.AnnotationAttachmentsConfig{
+		Backend:             evergreen.AnnotationAttachmentBackendDB,
+		SigningKey:          "signing_key",
+		MaxSizeBytes:        64,
+		AllowedContentTypes: []string{"text/plain"},
+	}
+	defer func() {
+		settings.AnnotationAttachments = oldConf
+	}()
+
+	failed := task.Task{Id: "failed", Execution: 0, Status: evergreen.TaskFailed}
+	require.NoError(t, failed.Insert())
+	running := task.Task{Id: "running", Execution: 0, Status: evergreen.TaskStarted}
+	require.NoError(t, running.Insert())
+
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "annotation_user"})
+
+	var attachment *restModel.APIAttachment
+	t.Run("UserAddsAttachment", func(t *testing.T) {
+		h := &annotationAttachmentPostHandler{
+			taskId:     failed.Id,
+			attachment: apimodels.AnnotationAttachment{Name: "notes.txt", Data: []byte("hello")},
+			user:       &user.DBUser{Id: "annotation_user"},
+		}
+		resp := h.Run(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var ok bool
+		attachment, ok = resp.Data().(*restModel.APIAttachment)
+		require.True(t, ok)
+		assert.Equal(t, "text/plain", utility.FromStringPtr(attachment.ContentType))
+		assert.Equal(t, 5, attachment.Size)
+		assert.NotEmpty(t, utility.FromStringPtr(attachment.URL))
+
+		annotation, err := annotations.FindOneByTaskIdAndExecution(failed.Id, 0)
+		require.NoError(t, err)
+		require.NotNil(t, annotation)
+		require.Len(t, annotation.Attachments, 1)
+		assert.Equal(t, "annotation_user", annotation.Attachments[0].Source.Author)
+	})
+	t.Run("UserCannotAttachToRunningTask", func(t *testing.T) {
+		h := &annotationAttachmentPostHandler{
+			taskId:     running.Id,
+			attachment: apimodels.AnnotationAttachment{Name: "notes.txt", Data: []byte("hello")},
+			user:       &user.DBUser{Id: "annotation_user"},
+		}
+		assert.Equal(t, http.StatusBadRequest, h.Run(ctx).Status())
+	})
+	t.Run("AgentAddsAttachmentToRunningTask", func(t *testing.T) {
+		h := &annotationAttachmentAgentPostHandler{
+			taskId:     running.Id,
+			attachment: apimodels.AnnotationAttachment{Name: "log.txt", ContentType: "text/plain", Data: []byte("output")},
+		}
+		require.Equal(t, http.StatusOK, h.Run(ctx).Status())
+
+		annotation, err := annotations.FindOneByTaskIdAndExecution(running.Id, 0)
+		require.NoError(t, err)
+		require.NotNil(t, annotation)
+		require.Len(t, annotation.Attachments, 1)
+		assert.Equal(t, annotations.AgentRequester, annotation.Attachments[0].Source.Requester)
+	})
+	t.Run("RejectsInvalidAttachment", func(t *testing.T) {
+		h := &annotationAttachmentAgentPostHandler{
+			taskId:     running.Id,
+			attachment: apimodels.AnnotationAttachment{Name: "report.html", ContentType: "text/html", Data: []byte("<html></html>")},
+		}
+		assert.Equal(t, http.StatusBadRequest, h.Run(ctx).Status())
+	})
+	t.Run("ServesAttachmentWithSignedURL", func(t *testing.T) {
+		require.NotNil(t, attachment)
+		parsed, err := url.Parse(utility.FromStringPtr(attachment.URL))
+		require.NoError(t, err)
+		expires, err := strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+		require.NoError(t, err)
+
+		h := &annotationAttachmentGetHandler{
+			attachmentId: utility.FromStringPtr(attachment.Id),
+			expires:      expires,
+			signature:    parsed.Query().Get("signature"),
+		}
+		resp := h.Run(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		assert.Equal(t, []byte("hello"), resp.Data())
+
+		h.signature = "sha256=invalid"
+		assert.Equal(t, http.StatusUnauthorized, h.Run(ctx).Status())
+	})
+	t.Run("ListsAttachmentsWithURLs", func(t *testing.T) {
+		h := &annotationByTaskGetHandler{taskId: failed.Id, execution: -1}
+		resp := h.Run(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		res, ok := resp.Data().([]restModel.APITaskAnnotation)
+		require.True(t, ok)
+		require.Len(t, res, 1)
+		require.Len(t, res[0].Attachments, 1)
+		assert.NotEmpty(t, utility.FromStringPtr(res[0].Attachments[0].URL))
+	})
+	t.Run("UserRemovesAttachment", func(t *testing.T) {
+		require.NotNil(t, attachment)
+		h := &annotationAttachmentDeleteHandler{taskId: failed.Id, attachmentId: utility.FromStringPtr(attachment.Id)}
+		require.Equal(t, http.StatusOK, h.Run(ctx).Status())
+
+		annotation, err := annotations.FindOneByTaskIdAndExecution(failed.Id, 0)
+		require.NoError(t, err)
+		require.NotNil(t, annotation)
+		assert.Empty(t, annotation.Attachments)
+
+		assert.Equal(t, http.StatusNotFound, h.Run(ctx).Status())
+	})
+}
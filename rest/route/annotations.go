@@ -103,13 +103,27 @@ func getAPIAnnotationsForTaskIds(taskIds []string, allExecutions bool) gimlet.Re
 	if !allExecutions {
 		annotationsToReturn = annotations.GetLatestExecutions(allAnnotations)
 	}
+	res, err := buildAPITaskAnnotations(annotationsToReturn)
+	if err != nil {
+		return gimlet.NewJSONInternalErrorResponse(err)
+	}
+
+	return gimlet.NewJSONResponse(res)
+}
+
+// buildAPITaskAnnotations converts the annotations to their API models,
+// including signed URLs for their attachments.
+func buildAPITaskAnnotations(taskAnnotations []annotations.TaskAnnotation) ([]restModel.APITaskAnnotation, error) {
+	settings := evergreen.GetEnvironment().Settings()
 	var res []restModel.APITaskAnnotation
-	for _, a := range annotationsToReturn {
+	for _, a := range taskAnnotations {
 		apiAnnotation := restModel.APITaskAnnotationBuildFromService(a)
+		if err := apiAnnotation.SetAttachmentURLs(a, settings); err != nil {
+			return nil, errors.Wrapf(err, "setting attachment URLs for annotation for task '%s'", a.TaskId)
+		}
 		res = append(res, *apiAnnotation)
 	}
-
-	return gimlet.NewJSONResponse(res)
+	return res, nil
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -170,8 +184,11 @@ func (h *annotationByTaskGetHandler) Run(ctx context.Context) gimlet.Responder {
 		if a == nil {
 			return gimlet.NewJSONResponse([]restModel.APITaskAnnotation{})
 		}
-		taskAnnotation := restModel.APITaskAnnotationBuildFromService(*a)
-		return gimlet.NewJSONResponse([]restModel.APITaskAnnotation{*taskAnnotation})
+		res, err := buildAPITaskAnnotations([]annotations.TaskAnnotation{*a})
+		if err != nil {
+			return gimlet.NewJSONInternalErrorResponse(err)
+		}
+		return gimlet.NewJSONResponse(res)
 	}
 
 	allAnnotations, err := annotations.FindByTaskId(h.taskId)
@@ -184,10 +201,9 @@ func (h *annotationByTaskGetHandler) Run(ctx context.Context) gimlet.Responder {
 		annotationsToReturn = annotations.GetLatestExecutions(allAnnotations)
 	}
 
-	var res []restModel.APITaskAnnotation
-	for _, a := range annotationsToReturn {
-		apiAnnotation := restModel.APITaskAnnotationBuildFromService(a)
-		res = append(res, *apiAnnotation)
+	res, err := buildAPITaskAnnotations(annotationsToReturn)
+	if err != nil {
+		return gimlet.NewJSONInternalErrorResponse(err)
 	}
 
 	return gimlet.NewJSONResponse(res)
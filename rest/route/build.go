@@ -43,6 +43,17 @@ func (b *buildGetHandler) Run(ctx context.Context) gimlet.Responder {
 	if err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding build '%s'", b.buildId))
 	}
+	if foundBuild == nil {
+		// Builds of archived versions are only in cold storage.
+		var archived *serviceModel.ArchivedBuildContents
+		archived, err = serviceModel.FindArchivedBuild(b.buildId)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding archived build '%s'", b.buildId))
+		}
+		if archived != nil {
+			return b.makeBuildResponse(ctx, archived.Build, archived.Tasks)
+		}
+	}
 	if foundBuild == nil {
 		return gimlet.MakeJSONInternalErrorResponder(gimlet.ErrorResponse{
 			StatusCode: http.StatusNotFound,
@@ -64,13 +75,22 @@ func (b *buildGetHandler) Run(ctx context.Context) gimlet.Responder {
 		}
 	}
 
+	return b.makeBuildResponse(ctx, *foundBuild, tasks)
+}
+
+func (b *buildGetHandler) makeBuildResponse(ctx context.Context, foundBuild build.Build, tasks []task.Task) gimlet.Responder {
 	buildModel := &model.APIBuild{}
-	err = buildModel.BuildFromService(*foundBuild)
-	if err != nil {
+	if err := buildModel.BuildFromService(foundBuild); err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "converting build to API model"))
 	}
 	buildModel.SetTaskCache(tasks)
 
+	order, source, err := getEffectiveTaskOrder(ctx, foundBuild.Project, foundBuild.Version)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting task order for build '%s'", foundBuild.Id))
+	}
+	buildModel.TaskOrder = makeBuildTaskOrder(order, source, foundBuild, tasks)
+
 	return gimlet.NewJSONResponse(buildModel)
 }
 
@@ -81,6 +101,9 @@ func (b *buildGetHandler) Run(ctx context.Context) gimlet.Responder {
 type buildChangeStatusHandler struct {
 	Activated *bool  `json:"activated"`
 	Priority  *int64 `json:"priority"`
+	// BypassActivationHooks activates the build without evaluating its
+	// variant's activation hook.
+	BypassActivationHooks bool `json:"bypass_activation_hooks"`
 
 	buildId string
 }
@@ -142,7 +165,11 @@ func (b *buildChangeStatusHandler) Run(ctx context.Context) gimlet.Responder {
 	}
 
 	if b.Activated != nil {
-		if err = serviceModel.SetBuildActivation(b.buildId, *b.Activated, user.Username()); err != nil {
+		if *b.Activated {
+			if resp := b.activateBuild(ctx, foundBuild, user); resp != nil {
+				return resp
+			}
+		} else if err = serviceModel.SetBuildActivation(b.buildId, false, user.Username()); err != nil {
 			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "setting build activation"))
 		}
 	}
@@ -166,6 +193,47 @@ func (b *buildChangeStatusHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(buildModel)
 }
 
+// activateBuild activates the build, returning an error responder if the
+// build's activation hook, if any, does not allow the user to activate it.
+func (b *buildChangeStatusHandler) activateBuild(ctx context.Context, foundBuild *build.Build, u gimlet.User) gimlet.Responder {
+	if b.BypassActivationHooks {
+		canBypass := u.HasPermission(gimlet.PermissionOpts{
+			Resource:      foundBuild.Project,
+			ResourceType:  evergreen.ProjectResourceType,
+			Permission:    evergreen.PermissionActivationHooks,
+			RequiredLevel: evergreen.ActivationHooksBypass.Value,
+		})
+		if !canBypass {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusForbidden,
+				Message:    fmt.Sprintf("insufficient permissions to bypass activation hooks for project '%s'", foundBuild.Project),
+			})
+		}
+	}
+
+	var err error
+	if b.BypassActivationHooks {
+		var pRef *serviceModel.ProjectRef
+		pRef, err = serviceModel.FindMergedProjectRef(foundBuild.Project, foundBuild.Version, false)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project '%s'", foundBuild.Project))
+		}
+		err = serviceModel.ActivateBuildBypassingHooks(ctx, pRef, foundBuild, u.Username())
+	} else {
+		err = serviceModel.SetBuildActivation(foundBuild.Id, true, u.Username())
+	}
+	if serviceModel.IsVariantActivationDenied(err) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusForbidden,
+			Message:    err.Error(),
+		})
+	}
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "setting build activation"))
+	}
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////
 //
 // Handler for aborting build by id
@@ -239,8 +307,19 @@ func (b *buildRestartHandler) Parse(ctx context.Context, r *http.Request) error
 }
 
 func (b *buildRestartHandler) Run(ctx context.Context) gimlet.Responder {
+	archived, err := serviceModel.IsBuildArchived(b.buildId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "checking if build '%s' is archived", b.buildId))
+	}
+	if archived {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("build '%s' has been archived to cold storage and can't be restarted", b.buildId),
+		})
+	}
+
 	usr := MustHaveUser(ctx)
-	err := serviceModel.RestartAllBuildTasks(b.buildId, usr.Id)
+	err = serviceModel.RestartAllBuildTasks(b.buildId, usr.Id)
 	if err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "restarting all tasks in build '%s'", b.buildId))
 	}
@@ -263,3 +342,50 @@ func (b *buildRestartHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return gimlet.NewJSONResponse(buildModel)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// Handler for getting the critical path of a build
+//
+//    /builds/{build_id}/critical_path
+
+type buildCriticalPathHandler struct {
+	buildId string
+}
+
+func makeGetBuildCriticalPath() gimlet.RouteHandler {
+	return &buildCriticalPathHandler{}
+}
+
+func (b *buildCriticalPathHandler) Factory() gimlet.RouteHandler {
+	return &buildCriticalPathHandler{}
+}
+
+func (b *buildCriticalPathHandler) Parse(ctx context.Context, r *http.Request) error {
+	b.buildId = gimlet.GetVars(r)["build_id"]
+	return nil
+}
+
+// Run returns the chain of tasks in the build that determined how long the
+// build took to finish.
+func (b *buildCriticalPathHandler) Run(ctx context.Context) gimlet.Responder {
+	foundBuild, err := build.FindOneId(b.buildId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding build '%s'", b.buildId))
+	}
+	if foundBuild == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("build '%s' not found", b.buildId),
+		})
+	}
+
+	criticalPath, err := serviceModel.GetBuildCriticalPath(foundBuild.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting critical path for build '%s'", b.buildId))
+	}
+
+	apiCriticalPath := model.APICriticalPath{}
+	apiCriticalPath.BuildFromService(*criticalPath)
+	return gimlet.NewJSONResponse(apiCriticalPath)
+}
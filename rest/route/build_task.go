@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/utility"
 
+	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
@@ -64,6 +65,19 @@ func (tbh *tasksByBuildHandler) Run(ctx context.Context) gimlet.Responder {
 	// calculating information about the next page. Here the limit is multiplied
 	// by two to fetch the next page.
 	tasks, err := data.FindTasksByBuildId(tbh.buildId, tbh.key, tbh.status, tbh.limit+1, 1)
+	var isArchived bool
+	if err != nil || len(tasks) == 0 {
+		// Tasks of archived versions are only in cold storage.
+		var archivedTasks []task.Task
+		var archivedErr error
+		archivedTasks, isArchived, archivedErr = dbModel.FindArchivedBuildTasks(tbh.buildId, tbh.key, tbh.status, tbh.limit+1)
+		if archivedErr != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(archivedErr, "finding archived tasks for build '%s'", tbh.buildId))
+		}
+		if isArchived {
+			tasks, err = archivedTasks, nil
+		}
+	}
 	if err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding tasks for build '%s'", tbh.buildId))
 	}
@@ -107,9 +121,16 @@ func (tbh *tasksByBuildHandler) Run(ctx context.Context) gimlet.Responder {
 		if tbh.fetchAllExecutions {
 			var oldTasks []task.Task
 
-			oldTasks, err = task.FindOldWithDisplayTasks(task.ByOldTaskID(tasks[i].Id))
-			if err != nil {
-				return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding archived task '%s'", tasks[i].Id))
+			if isArchived {
+				oldTasks, err = dbModel.FindArchivedTaskExecutions(tasks[i].Id)
+				if err != nil {
+					return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding archived executions of task '%s'", tasks[i].Id))
+				}
+			} else {
+				oldTasks, err = task.FindOldWithDisplayTasks(task.ByOldTaskID(tasks[i].Id))
+				if err != nil {
+					return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding archived task '%s'", tasks[i].Id))
+				}
 			}
 
 			if err = taskModel.BuildPreviousExecutions(oldTasks, tbh.url); err != nil {
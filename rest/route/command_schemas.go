@@ -0,0 +1,34 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/agent/command"
+	"github.com/evergreen-ci/gimlet"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/commands
+
+// commandSchemasGetHandler lists the commands that projects can use along with
+// the parameters that each command accepts, so that tools that check project
+// configurations stay in sync with what the server validates.
+type commandSchemasGetHandler struct{}
+
+func makeGetCommandSchemas() gimlet.RouteHandler {
+	return &commandSchemasGetHandler{}
+}
+
+func (h *commandSchemasGetHandler) Factory() gimlet.RouteHandler {
+	return &commandSchemasGetHandler{}
+}
+
+func (h *commandSchemasGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *commandSchemasGetHandler) Run(ctx context.Context) gimlet.Responder {
+	return gimlet.NewJSONResponse(command.RegisteredCommandSchemas())
+}
@@ -0,0 +1,102 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultConfigReplayLimit = 10
+	maxConfigReplayLimit     = 25
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/config_replay
+
+type versionConfigReplayHandler struct {
+	version       *dbModel.Version
+	includeConfig bool
+}
+
+func makeGetVersionConfigReplay() gimlet.RouteHandler {
+	return &versionConfigReplayHandler{}
+}
+
+func (h *versionConfigReplayHandler) Factory() gimlet.RouteHandler {
+	return &versionConfigReplayHandler{}
+}
+
+func (h *versionConfigReplayHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.version = MustHaveProjectContext(ctx).Version
+	if h.version == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "version not found",
+		}
+	}
+	h.includeConfig = r.URL.Query().Get("include_config") == "true"
+	return nil
+}
+
+// Run validates the version's project config against the current validation
+// rules and project settings, and returns the rules that it now fails.
+func (h *versionConfigReplayHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	replay, err := data.ReplayVersionConfig(ctx, h.version, pRef, h.includeConfig, true)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "replaying project config for version '%s'", h.version.Id))
+	}
+	return gimlet.NewJSONResponse(replay)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/config_replay
+
+type projectConfigReplayHandler struct {
+	limit int
+}
+
+func makeGetProjectConfigReplay() gimlet.RouteHandler {
+	return &projectConfigReplayHandler{}
+}
+
+func (h *projectConfigReplayHandler) Factory() gimlet.RouteHandler {
+	return &projectConfigReplayHandler{}
+}
+
+func (h *projectConfigReplayHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.limit = defaultConfigReplayLimit
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		var err error
+		h.limit, err = strconv.Atoi(limit)
+		if err != nil || h.limit <= 0 || h.limit > maxConfigReplayLimit {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("limit must be a positive integer no greater than %d", maxConfigReplayLimit),
+			}
+		}
+	}
+	return nil
+}
+
+// Run validates the project configs of the project's most recent mainline
+// versions against the current validation rules, and returns how many of the
+// versions fail each rule. The rules that check the configs against the
+// project's settings are only replayed for single versions.
+func (h *projectConfigReplayHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	replay, err := data.ReplayProjectConfigs(ctx, pRef, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "replaying project configs for project '%s'", pRef.Identifier))
+	}
+	return gimlet.NewJSONResponse(replay)
+}
@@ -0,0 +1,121 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+///////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/distros/{distro_id}/drain
+
+type distroDrainPostHandler struct {
+	TargetDistro string `json:"target_distro"`
+	Mode         string `json:"mode"`
+	distroID     string
+}
+
+func makePostDistroDrain() gimlet.RouteHandler {
+	return &distroDrainPostHandler{}
+}
+
+func (h *distroDrainPostHandler) Factory() gimlet.RouteHandler {
+	return &distroDrainPostHandler{}
+}
+
+// Parse fetches the distroId and drain options from the http request.
+func (h *distroDrainPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+	body := utility.NewRequestReader(r)
+	defer body.Close()
+
+	if err := utility.ReadJSON(body, h); err != nil {
+		return errors.Wrap(err, "reading distro drain options from request body")
+	}
+	if h.Mode == "" {
+		h.Mode = dbModel.DistroDrainModeWait
+	}
+	if h.Mode != dbModel.DistroDrainModeWait && h.Mode != dbModel.DistroDrainModeReschedule {
+		return errors.Errorf("invalid drain mode '%s'", h.Mode)
+	}
+	if h.Mode == dbModel.DistroDrainModeReschedule && h.TargetDistro == "" {
+		return errors.New("must specify a target distro to reschedule running tasks")
+	}
+
+	return nil
+}
+
+// Run disables the distro and moves its tasks to the target distro. Tasks
+// that cannot be moved immediately are handled by a background job.
+func (h *distroDrainPostHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	drain, err := dbModel.StartDistroDrain(h.distroID, h.TargetDistro, h.Mode, u.Username())
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrapf(err, "starting drain for distro '%s'", h.distroID).Error(),
+		})
+	}
+
+	progress, err := drain.Process()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "draining distro '%s'", h.distroID))
+	}
+
+	apiProgress := model.APIDistroDrainProgress{}
+	apiProgress.BuildFromService(*progress)
+	return gimlet.NewJSONResponse(apiProgress)
+}
+
+///////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/distros/{distro_id}/drain
+
+type distroDrainGetHandler struct {
+	distroID string
+}
+
+func makeGetDistroDrain() gimlet.RouteHandler {
+	return &distroDrainGetHandler{}
+}
+
+func (h *distroDrainGetHandler) Factory() gimlet.RouteHandler {
+	return &distroDrainGetHandler{}
+}
+
+// Parse fetches the distroId from the http request.
+func (h *distroDrainGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+
+	return nil
+}
+
+// Run returns the progress of the distro's drain.
+func (h *distroDrainGetHandler) Run(ctx context.Context) gimlet.Responder {
+	drain, err := dbModel.FindDistroDrain(h.distroID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding drain for distro '%s'", h.distroID))
+	}
+	if drain == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("distro '%s' is not being drained", h.distroID),
+		})
+	}
+
+	progress, err := drain.Progress()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting progress for distro '%s' drain", h.distroID))
+	}
+
+	apiProgress := model.APIDistroDrainProgress{}
+	apiProgress.BuildFromService(*progress)
+	return gimlet.NewJSONResponse(apiProgress)
+}
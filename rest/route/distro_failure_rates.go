@@ -0,0 +1,69 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+///////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/distro_failure_rates
+
+type distroFailureRatesGetHandler struct {
+	opts dbModel.DistroFailureRateOptions
+}
+
+func makeGetDistroFailureRates() gimlet.RouteHandler {
+	return &distroFailureRatesGetHandler{}
+}
+
+func (h *distroFailureRatesGetHandler) Factory() gimlet.RouteHandler {
+	return &distroFailureRatesGetHandler{}
+}
+
+// Parse fetches the optional build variant and task name to narrow the
+// comparison to, and the window of finished task executions to compare. The
+// project is taken from the project context.
+func (h *distroFailureRatesGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	vals := r.URL.Query()
+	h.opts.BuildVariant = vals.Get("build_variant")
+	h.opts.TaskName = vals.Get("task_name")
+	h.opts.Window = dbModel.DefaultDistroFailureRateWindow
+	if windowHours := vals.Get("window_hours"); windowHours != "" {
+		hours, err := strconv.Atoi(windowHours)
+		if err != nil || hours <= 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "window_hours must be a positive integer",
+			}
+		}
+		h.opts.Window = time.Duration(hours) * time.Hour
+	}
+
+	return nil
+}
+
+// Run returns the failure rates of the project's recent task executions on
+// each of the distros that they ran on, from the highest to the lowest.
+func (h *distroFailureRatesGetHandler) Run(ctx context.Context) gimlet.Responder {
+	h.opts.Project = MustHaveProjectContext(ctx).ProjectRef.Id
+	rates, err := dbModel.GetDistroFailureRates(h.opts)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting distro failure rates"))
+	}
+
+	apiRates := make([]model.APIDistroFailureRate, 0, len(rates))
+	for _, rate := range rates {
+		apiRate := model.APIDistroFailureRate{}
+		apiRate.BuildFromService(rate)
+		apiRates = append(apiRates, apiRate)
+	}
+	return gimlet.NewJSONResponse(apiRates)
+}
@@ -0,0 +1,62 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+///////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/distros/{distro_id}/health
+
+type distroHealthGetHandler struct {
+	distroID string
+	window   time.Duration
+}
+
+func makeGetDistroHealth() gimlet.RouteHandler {
+	return &distroHealthGetHandler{}
+}
+
+func (h *distroHealthGetHandler) Factory() gimlet.RouteHandler {
+	return &distroHealthGetHandler{}
+}
+
+// Parse fetches the distroId and the window of finished tasks to summarize
+// from the http request.
+func (h *distroHealthGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+	h.window = dbModel.DefaultDistroHealthWindow
+	if windowMins := r.URL.Query().Get("window_mins"); windowMins != "" {
+		mins, err := strconv.Atoi(windowMins)
+		if err != nil || mins <= 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "window_mins must be a positive integer",
+			}
+		}
+		h.window = time.Duration(mins) * time.Minute
+	}
+
+	return nil
+}
+
+// Run returns the recent system and setup failure rates of the distro and its
+// hosts.
+func (h *distroHealthGetHandler) Run(ctx context.Context) gimlet.Responder {
+	health, err := dbModel.GetDistroHealth(h.distroID, h.window)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting health for distro '%s'", h.distroID))
+	}
+
+	apiHealth := model.APIDistroHealth{}
+	apiHealth.BuildFromService(*health)
+	return gimlet.NewJSONResponse(apiHealth)
+}
@@ -2,6 +2,7 @@ package route
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/commitqueue"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/repotracker"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/thirdparty"
@@ -32,11 +34,33 @@ const (
 	githubActionReopened    = "reopened"
 	commitUnsigned          = "unsigned"
 
+	githubMergeGroupEventType       = "merge_group"
+	githubActionChecksRequested     = "checks_requested"
+	githubActionMergeGroupDestroyed = "destroyed"
+
 	retryComment = "evergreen retry"
 	patchComment = "evergreen patch"
 	refTags      = "refs/tags/"
 )
 
+// githubMergeGroupEvent is sent when GitHub's merge queue requests checks for
+// a merge group or removes the merge group from the queue. The GitHub client
+// doesn't support merge queue events, so it's parsed separately.
+type githubMergeGroupEvent struct {
+	Action string `json:"action"`
+	// Reason is why a destroyed merge group was removed from the queue.
+	Reason     string `json:"reason"`
+	MergeGroup struct {
+		HeadSHA    string             `json:"head_sha"`
+		HeadRef    string             `json:"head_ref"`
+		BaseSHA    string             `json:"base_sha"`
+		BaseRef    string             `json:"base_ref"`
+		HeadCommit *github.HeadCommit `json:"head_commit"`
+	} `json:"merge_group"`
+	Repo   *github.Repository `json:"repository"`
+	Sender *github.User       `json:"sender"`
+}
+
 type githubHookApi struct {
 	queue  amboy.Queue
 	secret []byte
@@ -88,6 +112,15 @@ func (gh *githubHookApi) Parse(ctx context.Context, r *http.Request) error {
 		return errors.Wrap(err, "reading and validating GitHub request payload")
 	}
 
+	if gh.eventType == githubMergeGroupEventType {
+		event := &githubMergeGroupEvent{}
+		if err = json.Unmarshal(body, event); err != nil {
+			return errors.Wrap(err, "parsing merge group webhook")
+		}
+		gh.event = event
+		return nil
+	}
+
 	gh.event, err = github.ParseWebHook(gh.eventType, body)
 	if err != nil {
 		return errors.Wrap(err, "parsing webhook")
@@ -261,6 +294,33 @@ func (gh *githubHookApi) Run(ctx context.Context) gimlet.Responder {
 			}
 		}
 
+	case *githubMergeGroupEvent:
+		if err := validateMergeGroupEvent(event); err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"source": "GitHub hook",
+				"msg_id": gh.msgID,
+				"event":  gh.eventType,
+				"action": event.Action,
+			}))
+			return gimlet.NewJSONErrorResponse(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    errors.Wrap(err, "invalid merge group event").Error(),
+			})
+		}
+		if err := gh.handleMergeGroup(ctx, event); err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"source":   "GitHub hook",
+				"msg_id":   gh.msgID,
+				"event":    gh.eventType,
+				"action":   event.Action,
+				"repo":     event.Repo.GetFullName(),
+				"base_ref": event.MergeGroup.BaseRef,
+				"head_sha": event.MergeGroup.HeadSHA,
+				"message":  "can't handle merge group",
+			}))
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "handling merge group"))
+		}
+
 	case *github.MetaEvent:
 		if event.GetAction() == "deleted" {
 			hookID := event.GetHookID()
@@ -526,6 +586,124 @@ func (gh *githubHookApi) createVersionForTag(ctx context.Context, pRef model.Pro
 	return gh.sc.CreateVersionFromConfig(ctx, &projectInfo, metadata, true)
 }
 
+// handleMergeGroup creates a version to test a merge group that GitHub's
+// merge queue requested checks for, for each project that uses the merge
+// queue, and aborts the versions for merge groups that were removed from the
+// queue.
+func (gh *githubHookApi) handleMergeGroup(ctx context.Context, event *githubMergeGroupEvent) error {
+	group := model.GithubMergeGroup{
+		HeadSHA: event.MergeGroup.HeadSHA,
+		HeadRef: event.MergeGroup.HeadRef,
+		BaseSHA: event.MergeGroup.BaseSHA,
+		BaseRef: event.MergeGroup.BaseRef,
+	}
+	owner := event.Repo.GetOwner().GetLogin()
+	repo := event.Repo.GetName()
+	projectRefs, err := model.FindProjectRefsForGithubMergeGroup(owner, repo, group.Branch())
+	if err != nil {
+		return err
+	}
+	if len(projectRefs) == 0 {
+		grip.Debug(message.Fields{
+			"source":  "GitHub hook",
+			"msg_id":  gh.msgID,
+			"event":   gh.eventType,
+			"owner":   owner,
+			"repo":    repo,
+			"branch":  group.Branch(),
+			"message": "no projects use the GitHub merge queue",
+		})
+		return nil
+	}
+
+	catcher := grip.NewBasicCatcher()
+	switch event.Action {
+	case githubActionChecksRequested:
+		token, err := gh.settings.GetGithubOauthToken()
+		if err != nil {
+			return errors.Wrap(err, "getting GitHub OAuth token from admin settings")
+		}
+		for _, pRef := range projectRefs {
+			v, err := gh.createVersionForMergeGroup(ctx, pRef, group, event.MergeGroup.HeadCommit, token)
+			if err != nil {
+				catcher.Wrapf(err, "creating version for merge group '%s' in project '%s'", group.HeadRef, pRef.Identifier)
+				continue
+			}
+			grip.Info(message.Fields{
+				"source":             "GitHub hook",
+				"msg_id":             gh.msgID,
+				"event":              gh.eventType,
+				"project":            pRef.Id,
+				"project_identifier": pRef.Identifier,
+				"head_ref":           group.HeadRef,
+				"head_sha":           group.HeadSHA,
+				"version":            v.Id,
+				"message":            "triggered version from merge group",
+			})
+		}
+	case githubActionMergeGroupDestroyed:
+		for _, pRef := range projectRefs {
+			versionID := model.GithubMergeGroupVersionID(pRef.Identifier, group.HeadSHA)
+			catcher.Wrapf(model.AbortGithubMergeGroupVersion(versionID), "aborting version for merge group '%s' removed because it was %s", group.HeadRef, event.Reason)
+		}
+	}
+	return catcher.Resolve()
+}
+
+// createVersionForMergeGroup creates the version that tests the merge group
+// with the project's config at the merge group's head commit. The tasks that
+// run are the ones in the project's commit queue definition, and the version's
+// status is reported on the head commit, where the merge queue checks for it.
+func (gh *githubHookApi) createVersionForMergeGroup(ctx context.Context, pRef model.ProjectRef, group model.GithubMergeGroup,
+	headCommit *github.HeadCommit, token string) (*model.Version, error) {
+	// GitHub may send the same event more than once.
+	existingVersion, err := model.VersionFindOneId(model.GithubMergeGroupVersionID(pRef.Identifier, group.HeadSHA))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding existing version for merge group")
+	}
+	if existingVersion != nil {
+		return existingVersion, nil
+	}
+
+	projectInfo, err := model.GetProjectFromFile(ctx, model.GetProjectOpts{
+		Ref:        &pRef,
+		Revision:   group.HeadSHA,
+		RemotePath: pRef.RemotePath,
+		Token:      token,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading project config at revision '%s'", group.HeadSHA)
+	}
+	projectInfo.Ref = &pRef
+	metadata := model.VersionMetadata{
+		Revision: model.Revision{
+			Revision:        group.HeadSHA,
+			Author:          headCommit.GetAuthor().GetName(),
+			AuthorEmail:     headCommit.GetAuthor().GetEmail(),
+			RevisionMessage: headCommit.GetMessage(),
+			CreateTime:      time.Now(),
+		},
+		GithubMergeGroup: group,
+		Alias:            evergreen.CommitQueueAlias,
+	}
+	v, err := gh.sc.CreateVersionFromConfig(ctx, &projectInfo, metadata, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(v.Errors) > 0 {
+		return v, errors.Errorf("version '%s' has project config errors: %s", v.Id, strings.Join(v.Errors, "; "))
+	}
+	return v, errors.Wrapf(repotracker.AddGithubCheckSubscriptions(v, &pRef), "adding GitHub check subscriptions for version '%s'", v.Id)
+}
+
+func validateMergeGroupEvent(event *githubMergeGroupEvent) error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(event.Repo.GetOwner().GetLogin() == "" || event.Repo.GetName() == "", "repo owner and name are missing")
+	catcher.NewWhen(event.MergeGroup.HeadSHA == "", "merge group head SHA is missing")
+	catcher.NewWhen(event.MergeGroup.BaseRef == "", "merge group base ref is missing")
+	return catcher.Resolve()
+}
+
 func validatePushTagEvent(event *github.PushEvent) error {
 	if len(strings.Split(event.Repo.GetFullName(), "/")) != 2 {
 		return errors.New("repo name is invalid (expected [owner]/[repo])")
@@ -599,7 +777,11 @@ func (gh *githubHookApi) commitQueueEnqueue(ctx context.Context, event *github.I
 
 	patchId, err := gh.sc.AddPatchForPr(ctx, *projectRef, prNum, cqInfo.Modules, cqInfo.MessageOverride)
 	if err != nil {
-		sendErr := thirdparty.SendCommitQueueGithubStatus(pr, message.GithubStateFailure, "failed to create patch", "")
+		description := "failed to create patch"
+		if _, ok := errors.Cause(err).(*model.PatchPolicyError); ok {
+			description = "patch violates the project's patch policy"
+		}
+		sendErr := thirdparty.SendCommitQueueGithubStatus(pr, message.GithubStateFailure, description, "")
 		grip.Error(message.WrapError(sendErr, message.Fields{
 			"message": "error sending patch creation failure to github",
 			"owner":   userRepo.Owner,
@@ -341,6 +341,43 @@ func (s *GithubWebhookRouteSuite) TestUnknownEventType() {
 	}
 }
 
+func (s *GithubWebhookRouteSuite) TestParseMergeGroupEvent() {
+	ctx := context.Background()
+	body := []byte(`{
+		"action": "checks_requested",
+		"merge_group": {
+			"head_sha": "abcdef",
+			"head_ref": "refs/heads/gh-readonly-queue/main/pr-1-123456",
+			"base_sha": "123456",
+			"base_ref": "refs/heads/main",
+			"head_commit": {"message": "Merge pull request #1", "author": {"name": "octocat", "email": "octocat@example.com"}}
+		},
+		"repository": {"name": "repo", "full_name": "owner/repo", "owner": {"login": "owner"}}
+	}`)
+	req, err := makeRequest("1", githubMergeGroupEventType, body, []byte(s.conf.Api.GithubWebhookSecret))
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.h.Parse(ctx, req))
+	event, ok := s.h.event.(*githubMergeGroupEvent)
+	s.Require().True(ok)
+	s.Equal(githubActionChecksRequested, event.Action)
+	s.Equal("abcdef", event.MergeGroup.HeadSHA)
+	s.Equal("refs/heads/main", event.MergeGroup.BaseRef)
+	s.Equal("octocat", event.MergeGroup.HeadCommit.GetAuthor().GetName())
+	s.Equal("owner", event.Repo.GetOwner().GetLogin())
+	s.NoError(validateMergeGroupEvent(event))
+
+	// Without any projects that use the merge queue, nothing is triggered.
+	resp := s.h.Run(ctx)
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+
+	event.MergeGroup.HeadSHA = ""
+	resp = s.h.Run(ctx)
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusBadRequest, resp.Status())
+}
+
 func (s *GithubWebhookRouteSuite) TestTryDequeueCommitQueueItemForPR() {
 	s.NoError(db.ClearCollections(model.ProjectRefCollection, commitqueue.Collection))
 	projectRef := &model.ProjectRef{
@@ -3,9 +3,11 @@ package route
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db/mgo/bson"
@@ -33,8 +35,28 @@ type (
 const (
 	// These are private custom types to avoid key collisions.
 	RequestContext requestContextKey = 0
+	// requestConnKey is the key for the connection that the request arrived
+	// on.
+	requestConnKey requestContextKey = 1
 )
 
+// ConnContext attaches the connection that each request arrives on to the
+// request's context so that streaming routes can lift the server's write
+// timeout. It's meant to be used as an http.Server's ConnContext.
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, requestConnKey, c)
+}
+
+// clearWriteDeadline removes the server's write timeout for the rest of the
+// request so that the response can be streamed for longer.
+func clearWriteDeadline(ctx context.Context) error {
+	c, ok := ctx.Value(requestConnKey).(net.Conn)
+	if !ok {
+		return errors.New("request context has no connection")
+	}
+	return errors.Wrap(c.SetWriteDeadline(time.Time{}), "clearing write deadline")
+}
+
 type projCtxMiddleware struct{}
 
 func (m *projCtxMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
@@ -644,6 +666,10 @@ func urlVarsToProjectScopes(r *http.Request) ([]string, int, error) {
 	buildID := util.CoalesceStrings(append(query["build_id"], query["buildId"]...), vars["build_id"], vars["buildId"])
 	if projectID == "" && buildID != "" {
 		projectID, err = build.FindProjectForBuild(buildID)
+		if err != nil {
+			// Builds of archived versions are only in cold storage.
+			projectID, err = model.FindProjectForArchivedBuild(buildID)
+		}
 		if err != nil {
 			return nil, http.StatusNotFound, errors.Wrapf(err, "finding project for build '%s'", buildID)
 		}
@@ -669,6 +695,10 @@ func urlVarsToProjectScopes(r *http.Request) ([]string, int, error) {
 	taskID := util.CoalesceStrings(append(query["task_id"], query["taskId"]...), vars["task_id"], vars["taskId"])
 	if projectID == "" && taskID != "" {
 		projectID, err = task.FindProjectForTask(taskID)
+		if err != nil {
+			// Tasks of archived versions are only in cold storage.
+			projectID, err = model.FindProjectForArchivedTask(taskID)
+		}
 		if err != nil {
 			return nil, http.StatusNotFound, errors.Wrapf(err, "finding project for task '%s'", taskID)
 		}
@@ -884,3 +914,66 @@ func allowCORS(next http.HandlerFunc) http.HandlerFunc {
 	}
 	return AddCORSHeaders(origins, next)
 }
+
+type rejectServiceAccountMiddleware struct{}
+
+// NewRejectServiceAccountMiddleware returns a middleware that rejects
+// requests from service accounts. Service accounts can only act on their own
+// project, so routes that don't check the user's project permissions must
+// reject them.
+func NewRejectServiceAccountMiddleware() gimlet.Middleware {
+	return &rejectServiceAccountMiddleware{}
+}
+
+func (m *rejectServiceAccountMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if u, ok := gimlet.GetUser(r.Context()).(*user.DBUser); ok && u != nil && u.IsServiceAccount() {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusForbidden,
+			Message:    fmt.Sprintf("service account '%s' can only access routes for its project", u.Id),
+		}))
+		return
+	}
+
+	next(rw, r)
+}
+
+// NewServiceAccountAuditMiddleware returns a middleware that records each
+// request that a service account makes to modify something. It's for apps
+// outside of the REST v2 API, such as the legacy /api routes.
+func NewServiceAccountAuditMiddleware() gimlet.Middleware {
+	return gimlet.WrapperMiddleware(auditServiceAccountActions)
+}
+
+// auditServiceAccountActions records each request that a service account
+// makes to modify something, along with the status code of the response.
+func auditServiceAccountActions(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, ok := gimlet.GetUser(r.Context()).(*user.DBUser)
+		if !ok || u == nil || !u.IsServiceAccount() ||
+			r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next(w, r)
+			return
+		}
+
+		sw := &statusRecordingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next(sw, r)
+		grip.Error(message.WrapError(model.LogServiceAccountAction(u, r.Method, r.URL.Path, sw.statusCode), message.Fields{
+			"message":         "could not log service account action",
+			"service_account": u.Id,
+			"method":          r.Method,
+			"path":            r.URL.Path,
+		}))
+	}
+}
+
+// statusRecordingWriter is a response writer that remembers the status code
+// of the response.
+type statusRecordingWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (w *statusRecordingWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
@@ -929,3 +929,83 @@ func TestEventLogPermission(t *testing.T) {
 	assert.Equal(http.StatusOK, rw.Code)
 	assert.Equal(3, counter)
 }
+
+func TestRejectServiceAccountMiddleware(t *testing.T) {
+	serviceAccount := &user.DBUser{
+		Id:             "service_account",
+		OnlyAPI:        true,
+		ServiceAccount: &user.ServiceAccountInfo{ProjectID: "project"},
+	}
+	m := NewRejectServiceAccountMiddleware()
+
+	for tName, tCase := range map[string]struct {
+		user         gimlet.User
+		expectCalled bool
+	}{
+		"RejectsServiceAccount": {
+			user: serviceAccount,
+		},
+		"AllowsUser": {
+			user:         &user.DBUser{Id: "user"},
+			expectCalled: true,
+		},
+		"AllowsAPIOnlyUser": {
+			user:         &user.DBUser{Id: "api_user", OnlyAPI: true},
+			expectCalled: true,
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPost, "/keys", nil)
+			require.NoError(t, err)
+			r = r.WithContext(gimlet.AttachUser(r.Context(), tCase.user))
+			rw := httptest.NewRecorder()
+			var called bool
+			m.ServeHTTP(rw, r, func(rw http.ResponseWriter, r *http.Request) { called = true })
+			assert.Equal(t, tCase.expectCalled, called)
+			if !tCase.expectCalled {
+				assert.Equal(t, http.StatusForbidden, rw.Code)
+			}
+		})
+	}
+}
+
+func TestServiceAccountsCannotUseUnscopedRoutes(t *testing.T) {
+	serviceAccount := &user.DBUser{
+		Id:             "service_account",
+		OnlyAPI:        true,
+		ServiceAccount: &user.ServiceAccountInfo{ProjectID: "project"},
+	}
+	env := evergreen.GetEnvironment()
+	app := gimlet.NewApp()
+	app.SetPrefix(evergreen.RestRoutePrefix)
+	app.AddWrapper(gimlet.WrapperMiddleware(func(next http.HandlerFunc) http.HandlerFunc {
+		return func(rw http.ResponseWriter, r *http.Request) {
+			next(rw, r.WithContext(gimlet.AttachUser(r.Context(), serviceAccount)))
+		}
+	}))
+	AttachHandler(app, HandlerOpts{APIQueue: env.LocalQueue()})
+	handler, err := app.Handler()
+	require.NoError(t, err)
+
+	for _, tCase := range []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodPost, path: "/hosts"},
+		{method: http.MethodPatch, path: "/hosts/h1"},
+		{method: http.MethodPost, path: "/volumes"},
+		{method: http.MethodPost, path: "/keys"},
+		{method: http.MethodPost, path: "/notifications/email"},
+		{method: http.MethodPost, path: "/roles"},
+		{method: http.MethodPost, path: "/subscriptions"},
+		{method: http.MethodPut, path: "/versions"},
+	} {
+		t.Run(tCase.method+tCase.path, func(t *testing.T) {
+			r, err := http.NewRequest(tCase.method, "/"+evergreen.RestRoutePrefix+"/v2"+tCase.path, nil)
+			require.NoError(t, err)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, r)
+			assert.Equal(t, http.StatusForbidden, rw.Code)
+		})
+	}
+}
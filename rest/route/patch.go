@@ -80,6 +80,18 @@ func (p *patchChangeStatusHandler) Run(ctx context.Context) gimlet.Responder {
 		ctx, cancel := p.env.Context()
 		defer cancel()
 		if err := data.SetPatchActivated(ctx, p.patchId, user.Username(), *p.Activated, p.env.Settings()); err != nil {
+			if dbModel.IsVariantActivationDenied(err) {
+				return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+					StatusCode: http.StatusForbidden,
+					Message:    err.Error(),
+				})
+			}
+			if dbModel.IsProjectQuotaExceeded(err) {
+				return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+					StatusCode: http.StatusBadRequest,
+					Message:    err.Error(),
+				})
+			}
 			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "setting patch activation"))
 		}
 	}
@@ -352,6 +364,76 @@ func (p *patchAbortHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(foundPatch)
 }
 
+////////////////////////////////////////////////////////////////////////
+//
+// Handler for keeping patches from being cleaned up as stale
+//
+//    /patches/{patch_id}/keep_alive
+
+type patchKeepAliveHandler struct {
+	patchId string
+}
+
+type patchKeepAliveResponse struct {
+	PatchID        string    `json:"patch_id"`
+	KeepAliveUntil time.Time `json:"keep_alive_until"`
+}
+
+func makeKeepPatchAlive() gimlet.RouteHandler {
+	return &patchKeepAliveHandler{}
+}
+
+func (p *patchKeepAliveHandler) Factory() gimlet.RouteHandler {
+	return &patchKeepAliveHandler{}
+}
+
+func (p *patchKeepAliveHandler) Parse(ctx context.Context, r *http.Request) error {
+	p.patchId = gimlet.GetVars(r)["patch_id"]
+	if !patch.IsValidId(p.patchId) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid patch ID '%s'", p.patchId),
+		}
+	}
+	return nil
+}
+
+func (p *patchKeepAliveHandler) Run(ctx context.Context) gimlet.Responder {
+	existingPatch, err := patch.FindOneId(p.patchId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding patch '%s'", p.patchId))
+	}
+	if existingPatch == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("patch '%s' not found", p.patchId),
+		})
+	}
+	pRef, err := dbModel.FindMergedProjectRef(existingPatch.Project, "", false)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project '%s'", existingPatch.Project))
+	}
+	if pRef == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", existingPatch.Project),
+		})
+	}
+
+	until, err := dbModel.KeepStalePatchAlive(existingPatch, pRef.StalePatchPolicy, time.Now())
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		})
+	}
+
+	return gimlet.NewJSONResponse(patchKeepAliveResponse{
+		PatchID:        p.patchId,
+		KeepAliveUntil: until,
+	})
+}
+
 ////////////////////////////////////////////////////////////////////////
 //
 // Handler for restarting patches by id
@@ -372,6 +454,22 @@ func (p *patchRestartHandler) Factory() gimlet.RouteHandler {
 
 func (p *patchRestartHandler) Parse(ctx context.Context, r *http.Request) error {
 	p.patchId = gimlet.GetVars(r)["patch_id"]
+	if !patch.IsValidId(p.patchId) {
+		return nil
+	}
+	existingPatch, err := patch.FindOneId(p.patchId)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "finding patch '%s'", p.patchId).Error(),
+		}
+	}
+	if existingPatch != nil && existingPatch.IsArchived() {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("patch '%s' was archived as stale and can't be restarted", p.patchId),
+		}
+	}
 	return nil
 }
 
@@ -390,6 +488,140 @@ func (p *patchRestartHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(foundPatch)
 }
 
+// resolvePatchVariantTasks returns the variants and tasks to schedule for
+// the requested variants, where the task "*" selects all of a variant's tasks.
+func resolvePatchVariantTasks(project *dbModel.Project, variants []variant) ([]patch.VariantTasks, error) {
+	vts := []patch.VariantTasks{}
+	for _, v := range variants {
+		variantToSchedule := patch.VariantTasks{Variant: v.Id}
+		if len(v.Tasks) > 0 && v.Tasks[0] == "*" {
+			projectVariant := project.FindBuildVariant(v.Id)
+			if projectVariant == nil {
+				return nil, errors.Errorf("variant '%s' not found", v.Id)
+			}
+			variantToSchedule.DisplayTasks = projectVariant.DisplayTasks
+			for _, projectTask := range projectVariant.Tasks {
+				variantToSchedule.Tasks = append(variantToSchedule.Tasks, projectTask.Name)
+			}
+		} else {
+			for _, t := range v.Tasks {
+				dt := project.GetDisplayTask(v.Id, t)
+				if dt != nil {
+					variantToSchedule.DisplayTasks = append(variantToSchedule.DisplayTasks, *dt)
+				} else {
+					variantToSchedule.Tasks = append(variantToSchedule.Tasks, t)
+				}
+			}
+		}
+		vts = append(vts, variantToSchedule)
+	}
+	return vts, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Handler for adding variants and tasks to a finalized patch
+//
+//    /patches/{patch_id}/extend
+
+type patchExtendHandler struct {
+	variantTasks patchTasks
+
+	patchId string
+}
+
+func makeExtendPatchHandler() gimlet.RouteHandler {
+	return &patchExtendHandler{}
+}
+
+func (p *patchExtendHandler) Factory() gimlet.RouteHandler {
+	return &patchExtendHandler{}
+}
+
+func (p *patchExtendHandler) Parse(ctx context.Context, r *http.Request) error {
+	p.patchId = gimlet.GetVars(r)["patch_id"]
+	if !patch.IsValidId(p.patchId) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid patch ID '%s'", p.patchId),
+		}
+	}
+	body := utility.NewRequestReader(r)
+	defer body.Close()
+	if err := utility.ReadJSON(body, &p.variantTasks); err != nil {
+		return errors.Wrap(err, "reading tasks from JSON request body")
+	}
+	if len(p.variantTasks.Variants) == 0 {
+		return errors.New("no variants specified")
+	}
+	return nil
+}
+
+func (p *patchExtendHandler) Run(ctx context.Context) gimlet.Responder {
+	existingPatch, err := patch.FindOneId(p.patchId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding patch '%s'", p.patchId))
+	}
+	if existingPatch == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("patch '%s' not found", p.patchId),
+		})
+	}
+	if existingPatch.Version == "" {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("patch '%s' has not been finalized, configure it instead", p.patchId),
+		})
+	}
+	dbVersion, err := dbModel.VersionFindOneId(existingPatch.Version)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version for patch '%s'", p.patchId))
+	}
+	if dbVersion == nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Errorf("version for patch '%s' not found", p.patchId))
+	}
+	project, err := dbModel.FindProjectFromVersionID(dbVersion.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project for version '%s'", dbVersion.Id))
+	}
+	pRef, err := dbModel.FindMergedProjectRef(existingPatch.Project, existingPatch.Version, true)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project ref '%s'", existingPatch.Project))
+	}
+	if pRef == nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Errorf("project ref '%s' not found", existingPatch.Project))
+	}
+
+	additions, err := resolvePatchVariantTasks(project, p.variantTasks.Variants)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		})
+	}
+	code, err := dbModel.ExtendPatch(ctx, existingPatch, dbVersion, project, pRef, additions)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: code,
+			Message:    errors.Wrapf(err, "extending patch '%s'", p.patchId).Error(),
+		})
+	}
+
+	dbVersion, err = dbModel.VersionFindOneId(existingPatch.Version)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version for patch '%s'", p.patchId))
+	}
+	if dbVersion == nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Errorf("version for patch '%s' not found", p.patchId))
+	}
+	restVersion := model.APIVersion{}
+	if err = restVersion.BuildFromService(dbVersion); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting version '%s' to API model", dbVersion.Id))
+	}
+	return gimlet.NewJSONResponse(restVersion)
+}
+
 ////////////////////////////////////////////////////////////////////////
 //
 // Handler for creating a new merge patch from an existing patch
@@ -516,28 +748,9 @@ func (p *schedulePatchHandler) Run(ctx context.Context) gimlet.Responder {
 	if patchUpdateReq.Description == "" && dbVersion != nil {
 		patchUpdateReq.Description = dbVersion.Message
 	}
-	for _, v := range p.variantTasks.Variants {
-		variantToSchedule := patch.VariantTasks{Variant: v.Id}
-		if len(v.Tasks) > 0 && v.Tasks[0] == "*" {
-			projectVariant := project.FindBuildVariant(v.Id)
-			if projectVariant == nil {
-				return gimlet.MakeJSONInternalErrorResponder(errors.Errorf("variant '%s' not found", v.Id))
-			}
-			variantToSchedule.DisplayTasks = projectVariant.DisplayTasks
-			for _, projectTask := range projectVariant.Tasks {
-				variantToSchedule.Tasks = append(variantToSchedule.Tasks, projectTask.Name)
-			}
-		} else {
-			for _, t := range v.Tasks {
-				dt := project.GetDisplayTask(v.Id, t)
-				if dt != nil {
-					variantToSchedule.DisplayTasks = append(variantToSchedule.DisplayTasks, *dt)
-				} else {
-					variantToSchedule.Tasks = append(variantToSchedule.Tasks, t)
-				}
-			}
-		}
-		patchUpdateReq.VariantsTasks = append(patchUpdateReq.VariantsTasks, variantToSchedule)
+	patchUpdateReq.VariantsTasks, err = resolvePatchVariantTasks(project, p.variantTasks.Variants)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
 	}
 	code, err := units.SchedulePatch(ctx, p.patchId, dbVersion, patchUpdateReq)
 	if err != nil {
@@ -0,0 +1,91 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/patches/{patch_id}/module_impact
+
+type patchModuleImpactHandler struct {
+	patchId string
+	opts    model.APIModuleImpactOptions
+	env     evergreen.Environment
+}
+
+func makePatchModuleImpact(env evergreen.Environment) gimlet.RouteHandler {
+	return &patchModuleImpactHandler{env: env}
+}
+
+func (h *patchModuleImpactHandler) Factory() gimlet.RouteHandler {
+	return &patchModuleImpactHandler{env: h.env}
+}
+
+func (h *patchModuleImpactHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.patchId = gimlet.GetVars(r)["patch_id"]
+	if !patch.IsValidId(h.patchId) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid patch ID '%s'", h.patchId),
+		}
+	}
+	body := utility.NewRequestReader(r)
+	defer body.Close()
+	if err := utility.ReadJSON(body, &h.opts); err != nil {
+		return errors.Wrap(err, "reading module impact options from JSON request body")
+	}
+	return nil
+}
+
+// Run previews the child patches that would run the patch's changes in every
+// project using the patch's project as a module, and creates them if
+// requested.
+func (h *patchModuleImpactHandler) Run(ctx context.Context) gimlet.Responder {
+	existingPatch, err := patch.FindOneId(h.patchId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding patch '%s'", h.patchId))
+	}
+	if existingPatch == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("patch '%s' not found", h.patchId),
+		})
+	}
+	if existingPatch.IsChild() {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("patch '%s' is a child patch, use its parent patch instead", h.patchId),
+		})
+	}
+	pRef, err := dbModel.FindMergedProjectRef(existingPatch.Project, existingPatch.Version, true)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project ref '%s'", existingPatch.Project))
+	}
+	if pRef == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project ref '%s' not found", existingPatch.Project),
+		})
+	}
+
+	impact, err := data.GetModuleImpact(ctx, h.env, MustHaveUser(ctx), existingPatch, pRef, h.opts)
+	if err != nil {
+		if errResp, ok := errors.Cause(err).(gimlet.ErrorResponse); ok {
+			return gimlet.MakeJSONErrorResponder(errResp)
+		}
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting module impact of patch '%s'", h.patchId))
+	}
+	return gimlet.NewJSONResponse(impact)
+}
@@ -247,6 +247,18 @@ func (h *podAgentNextTask) Run(ctx context.Context) gimlet.Responder {
 
 	h.setAgentFirstContactTime(p)
 
+	// In maintenance mode, the pod waits for a task rather than being
+	// terminated for having nothing to run.
+	maintenanceMode := evergreen.MaintenanceModeConfig{}
+	if err = maintenanceMode.Get(h.env); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting maintenance mode settings"))
+	}
+	if maintenanceMode.Enabled {
+		return gimlet.NewJSONResponse(apimodels.NextTaskResponse{
+			BackoffSecs: int(maintenanceMode.GetRetryAfter().Seconds()),
+		})
+	}
+
 	pd, err := h.findDispatcher()
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(err)
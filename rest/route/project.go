@@ -7,12 +7,14 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/evergreen-ci/evergreen"
 	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/commitqueue"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
@@ -26,10 +28,11 @@ import (
 )
 
 type projectGetHandler struct {
-	key   string
-	limit int
-	user  *user.DBUser
-	url   string
+	key             string
+	limit           int
+	includeArchived bool
+	user            *user.DBUser
+	url             string
 }
 
 func makeFetchProjectsRoute(url string) gimlet.RouteHandler {
@@ -46,6 +49,7 @@ func (p *projectGetHandler) Parse(ctx context.Context, r *http.Request) error {
 	vals := r.URL.Query()
 
 	p.key = vals.Get("start_at")
+	p.includeArchived = vals.Get("include_archived") == "true"
 	var err error
 	p.limit, err = getLimit(vals)
 	if err != nil {
@@ -56,7 +60,7 @@ func (p *projectGetHandler) Parse(ctx context.Context, r *http.Request) error {
 }
 
 func (p *projectGetHandler) Run(ctx context.Context) gimlet.Responder {
-	projects, err := dbModel.FindProjects(p.key, p.limit+1, 1)
+	projects, err := dbModel.FindProjects(p.key, p.limit+1, 1, p.includeArchived)
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
 	}
@@ -202,6 +206,9 @@ func (h *attachProjectToRepoHandler) Parse(ctx context.Context, r *http.Request)
 }
 
 func (h *attachProjectToRepoHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := data.CheckDirectSettingsWrite(h.project.Id); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
 	if err := h.project.AttachToRepo(h.user); err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "attaching repo to project"))
 	}
@@ -243,6 +250,9 @@ func (h *detachProjectFromRepoHandler) Parse(ctx context.Context, r *http.Reques
 }
 
 func (h *detachProjectFromRepoHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := data.CheckDirectSettingsWrite(h.project.Id); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
 	if err := h.project.DetachFromRepo(h.user); err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "detaching repo from project"))
 	}
@@ -260,6 +270,12 @@ type projectIDPatchHandler struct {
 	newProjectRef    *dbModel.ProjectRef
 	originalProject  *dbModel.ProjectRef
 	apiNewProjectRef *model.APIProjectRef
+	body             []byte
+	// approved is set when replaying a change request that has already
+	// been approved.
+	approved bool
+	// force disables the project even if other projects depend on it.
+	force bool
 
 	settings *evergreen.Settings
 }
@@ -280,6 +296,7 @@ func (h *projectIDPatchHandler) Factory() gimlet.RouteHandler {
 func (h *projectIDPatchHandler) Parse(ctx context.Context, r *http.Request) error {
 	h.project = gimlet.GetVars(r)["project_id"]
 	h.user = MustHaveUser(ctx)
+	h.force = r.URL.Query().Get("force") == "true"
 	body := utility.NewRequestReader(r)
 	defer body.Close()
 	b, err := ioutil.ReadAll(body)
@@ -287,6 +304,12 @@ func (h *projectIDPatchHandler) Parse(ctx context.Context, r *http.Request) erro
 		return errors.Wrap(err, "reading JSON request body")
 	}
 
+	return h.parseProjectChanges(b)
+}
+
+// parseProjectChanges populates the handler's project refs from the requested
+// changes to the project.
+func (h *projectIDPatchHandler) parseProjectChanges(b []byte) error {
 	oldProject, err := data.FindProjectById(h.project, false, false)
 	if err != nil {
 		return errors.Wrapf(err, "finding original project '%s'", h.project)
@@ -328,10 +351,30 @@ func (h *projectIDPatchHandler) Parse(ctx context.Context, r *http.Request) erro
 		}
 	}
 	newProjectRef.RepoRefId = oldProject.RepoRefId // this can't be modified by users
+	newProjectRef.Archived = oldProject.Archived   // this can only be modified by archiving or resurrecting the project
+	newProjectRef.VariantActivationHookSecret = oldProject.VariantActivationHookSecret
+	newProjectRef.EnsureVariantActivationHookSecret()
+	if newProjectRef.IsArchived() && newProjectRef.IsEnabled() {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "cannot enable an archived project, resurrect it instead",
+		}
+	}
+	v, err := requestProjectRef.Variables.ToService()
+	if err != nil {
+		return errors.Wrap(err, "converting project variables to service model")
+	}
+	if err = v.(*dbModel.ProjectVars).ValidateRestrictions(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid variable restrictions").Error(),
+		}
+	}
 
 	h.newProjectRef = newProjectRef
 	h.originalProject = oldProject
 	h.apiNewProjectRef = requestProjectRef // needed for the delete fields
+	h.body = b
 	return nil
 }
 
@@ -371,6 +414,89 @@ func (h *projectIDPatchHandler) Run(ctx context.Context) gimlet.Responder {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "merging project ref '%s' with repo settings", h.newProjectRef.Identifier))
 	}
 
+	// validate triggers before updating project
+	catcher := grip.NewSimpleCatcher()
+	for i := range h.newProjectRef.Triggers {
+		catcher.Add(h.newProjectRef.Triggers[i].Validate(h.newProjectRef.Id))
+	}
+	for i := range h.newProjectRef.PatchTriggerAliases {
+		h.newProjectRef.PatchTriggerAliases[i], err = dbModel.ValidateTriggerDefinition(h.newProjectRef.PatchTriggerAliases[i], h.newProjectRef.Id)
+		catcher.Add(err)
+	}
+	catcher.Wrap(h.newProjectRef.DownstreamParameterSchema.Validate(), "invalid downstream parameter schema")
+	for _, buildDef := range h.newProjectRef.PeriodicBuilds {
+		catcher.Wrapf(buildDef.Validate(), "invalid periodic build definition")
+	}
+	if !catcher.HasErrors() {
+		catcher.Add(dbModel.ValidateProjectTriggerCycles(h.newProjectRef.Id, h.newProjectRef.Triggers))
+	}
+	if catcher.HasErrors() {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(catcher.Resolve(), "invalid triggers"))
+	}
+
+	err = dbModel.ValidateBbProject(h.newProjectRef.Id, h.newProjectRef.BuildBaronSettings, &h.newProjectRef.TaskAnnotationSettings.FileTicketWebhook)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "validating build baron config"))
+	}
+	if err = h.newProjectRef.LogRetention.Validate(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid log retention policy"))
+	}
+	if utility.FromIntPtr(h.newProjectRef.MaxValidationWarnings) < 0 {
+		return gimlet.MakeJSONErrorResponder(errors.New("max validation warnings cannot be negative"))
+	}
+	if err = h.newProjectRef.Quotas.Validate(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid project quotas"))
+	}
+	if err = h.newProjectRef.PatchPolicy.Validate(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid patch policy"))
+	}
+	if err = h.newProjectRef.PriorityAging.Validate(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid priority aging settings"))
+	}
+	if err = dbModel.ValidateStuckTaskPolicy(h.newProjectRef.StuckTaskPolicy); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid stuck task policy"))
+	}
+	if err = dbModel.ValidateWatchedPaths(h.newProjectRef.WatchedPaths, h.newProjectRef.WatchedPathsPolicy); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid watched paths"))
+	}
+	if err = dbModel.ValidateOverridableExpansions(h.newProjectRef.OverridableExpansions); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid overridable expansions"))
+	}
+	if err = h.newProjectRef.GithubVariantChecks.Validate(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid GitHub variant check settings"))
+	}
+	if err = h.newProjectRef.StalePatchPolicy.Validate(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid stale patch policy"))
+	}
+	if err = dbModel.ValidateVariantActivationHooks(mergedProjectRef.VariantActivationHooks); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid activation hooks"))
+	}
+	if err = dbModel.ValidateGithubMergeQueue(mergedProjectRef); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "invalid merge queue settings"))
+	}
+
+	mergedOriginalRef, err := dbModel.GetProjectRefMergedWithRepo(*h.originalProject)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "merging original project ref '%s' with repo settings", h.project))
+	}
+	disabling := mergedOriginalRef.IsEnabled() && !mergedProjectRef.IsEnabled()
+	var dependents []dbModel.ProjectDependent
+	if disabling {
+		var resp gimlet.Responder
+		// A change request was already checked for dependents when it was
+		// requested.
+		dependents, resp = checkProjectDependents(mergedOriginalRef, dbModel.ProjectDependencyActionDisabled, h.force || h.approved)
+		if resp != nil {
+			return resp
+		}
+	}
+
+	if !h.approved && mergedOriginalRef.SettingsChangesRequireApproval() {
+		return h.requestApproval()
+	}
+
+	// Enabling webhooks and the commit queue have side effects, so they're
+	// only checked once the changes don't need approval or were approved.
 	if h.newProjectRef.IsEnabled() {
 		var hasHook bool
 		hasHook, err = dbModel.EnableWebhooks(ctx, h.newProjectRef)
@@ -428,27 +554,16 @@ func (h *projectIDPatchHandler) Run(ctx context.Context) gimlet.Responder {
 				return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "enabling commit queue for project '%s'", h.project))
 			}
 		}
-	}
-
-	// validate triggers before updating project
-	catcher := grip.NewSimpleCatcher()
-	for i := range h.newProjectRef.Triggers {
-		catcher.Add(h.newProjectRef.Triggers[i].Validate(h.newProjectRef.Id))
-	}
-	for i := range h.newProjectRef.PatchTriggerAliases {
-		h.newProjectRef.PatchTriggerAliases[i], err = dbModel.ValidateTriggerDefinition(h.newProjectRef.PatchTriggerAliases[i], h.newProjectRef.Id)
-		catcher.Add(err)
-	}
-	for _, buildDef := range h.newProjectRef.PeriodicBuilds {
-		catcher.Wrapf(buildDef.Validate(), "invalid periodic build definition")
-	}
-	if catcher.HasErrors() {
-		return gimlet.MakeJSONErrorResponder(errors.Wrap(catcher.Resolve(), "invalid triggers"))
-	}
 
-	err = dbModel.ValidateBbProject(h.newProjectRef.Id, h.newProjectRef.BuildBaronSettings, &h.newProjectRef.TaskAnnotationSettings.FileTicketWebhook)
-	if err != nil {
-		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "validating build baron config"))
+		// verify enabling the GitHub merge queue is valid
+		if mergedProjectRef.IsGithubMergeQueueEnabled() && !h.originalProject.IsGithubMergeQueueEnabled() {
+			if !hasHook {
+				return gimlet.MakeJSONErrorResponder(errors.New("cannot enable GitHub merge queue without first enabling GitHub webhooks"))
+			}
+			if !hasAliasDefined(allAliases, evergreen.CommitQueueAlias) {
+				return gimlet.MakeJSONErrorResponder(errors.New("cannot enable GitHub merge queue without a commit queue patch definition"))
+			}
+		}
 	}
 
 	newRevision := utility.FromStringPtr(h.apiNewProjectRef.Revision)
@@ -520,6 +635,12 @@ func (h *projectIDPatchHandler) Run(ctx context.Context) gimlet.Responder {
 	if err = dbModel.LogProjectModified(h.newProjectRef.Id, h.user.Username(), before, after); err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "logging modification event for project '%s'", h.project))
 	}
+	if disabling {
+		grip.Error(message.WrapError(data.NotifyProjectDependents(mergedOriginalRef, dependents, dbModel.ProjectDependencyActionDisabled, h.user.Username()), message.Fields{
+			"message": "could not notify dependent projects of disabled project",
+			"project": h.newProjectRef.Id,
+		}))
+	}
 
 	// run the repotracker for the project
 	if newRevision != "" {
@@ -535,6 +656,25 @@ func (h *projectIDPatchHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(struct{}{})
 }
 
+// requestApproval stores the requested changes as a pending change request
+// instead of applying them.
+func (h *projectIDPatchHandler) requestApproval() gimlet.Responder {
+	changeRequest := dbModel.NewProjectChangeRequest(h.user.Username(), *h.originalProject, *h.newProjectRef, string(h.body))
+	if err := changeRequest.Insert(); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "creating change request for project '%s'", h.project))
+	}
+
+	apiChangeRequest := model.APIProjectChangeRequest{}
+	if err := apiChangeRequest.BuildFromService(*changeRequest); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "converting change request to API model"))
+	}
+	resp := gimlet.NewJSONResponse(apiChangeRequest)
+	if err := resp.SetStatus(http.StatusAccepted); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "setting status %d", http.StatusAccepted))
+	}
+	return resp
+}
+
 func (h projectIDPatchHandler) ownerRepoChanged() bool {
 	return h.newProjectRef.Owner != h.originalProject.Owner || h.newProjectRef.Repo != h.originalProject.Repo
 }
@@ -685,6 +825,8 @@ func (h *projectRepotrackerHandler) Run(ctx context.Context) gimlet.Responder {
 
 type projectDeleteHandler struct {
 	projectName string
+	// force deletes the project even if other projects depend on it.
+	force bool
 }
 
 func makeDeleteProject() gimlet.RouteHandler {
@@ -697,6 +839,7 @@ func (h *projectDeleteHandler) Factory() gimlet.RouteHandler {
 
 func (h *projectDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
 	h.projectName = gimlet.GetVars(r)["project_id"]
+	h.force = r.URL.Query().Get("force") == "true"
 	return nil
 }
 
@@ -718,6 +861,11 @@ func (h *projectDeleteHandler) Run(ctx context.Context) gimlet.Responder {
 			errors.Errorf("project '%s' must be attached to a repo to be eligible for deletion", h.projectName))
 	}
 
+	dependents, resp := checkProjectDependents(project, dbModel.ProjectDependencyActionDeleted, h.force)
+	if resp != nil {
+		return resp
+	}
+
 	skeletonProj := dbModel.ProjectRef{
 		Id:        project.Id,
 		Owner:     project.Owner,
@@ -753,6 +901,11 @@ func (h *projectDeleteHandler) Run(ctx context.Context) gimlet.Responder {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "updating vars for project '%s'", project.Id))
 	}
 
+	grip.Error(message.WrapError(data.NotifyProjectDependents(project, dependents, dbModel.ProjectDependencyActionDeleted, MustHaveUser(ctx).Username()), message.Fields{
+		"message": "could not notify dependent projects of deleted project",
+		"project": project.Id,
+	}))
+
 	return gimlet.NewJSONResponse(struct{}{})
 }
 
@@ -885,6 +1038,14 @@ func (h *getProjectVersionsHandler) Parse(ctx context.Context, r *http.Request)
 	if h.opts.Requester == "" {
 		h.opts.Requester = evergreen.RepotrackerVersionRequester
 	}
+
+	if labels := params.Get("labels"); labels != "" {
+		var err error
+		h.opts.Labels, err = patch.ParseLabelSelector(labels)
+		if err != nil {
+			return errors.Wrap(err, "invalid labels query parameter")
+		}
+	}
 	return nil
 }
 
@@ -1016,6 +1177,182 @@ func (p *GetProjectAliasResultsHandler) Run(ctx context.Context) gimlet.Responde
 	return gimlet.NewJSONResponse(variantTasks)
 }
 
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/test_alias
+
+type projectAliasTestHandler struct {
+	projectRef *dbModel.ProjectRef
+	opts       data.ProjectAliasTestOpts
+}
+
+type projectAliasTestInput struct {
+	Alias       string                 `json:"alias"`
+	Definition  *model.APIProjectAlias `json:"definition"`
+	Revision    string                 `json:"revision"`
+	IncludeDeps bool                   `json:"include_deps"`
+}
+
+func makeTestProjectAlias() gimlet.RouteHandler {
+	return &projectAliasTestHandler{}
+}
+
+func (h *projectAliasTestHandler) Factory() gimlet.RouteHandler {
+	return &projectAliasTestHandler{}
+}
+
+func (h *projectAliasTestHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectRef = MustHaveProjectContext(ctx).ProjectRef
+
+	input := projectAliasTestInput{}
+	if err := utility.ReadJSON(r.Body, &input); err != nil {
+		return errors.Wrap(err, "reading alias test input from JSON request body")
+	}
+	if (input.Alias == "") == (input.Definition == nil) {
+		return errors.New("must specify exactly one of an alias name or an inline alias definition")
+	}
+	h.opts = data.ProjectAliasTestOpts{
+		Alias:       input.Alias,
+		Revision:    input.Revision,
+		IncludeDeps: input.IncludeDeps,
+	}
+	if input.Definition != nil {
+		// Inline definitions don't need a name, but aliases must have one to
+		// be valid.
+		if utility.FromStringPtr(input.Definition.Alias) == "" {
+			input.Definition.Alias = utility.ToStringPtr("inline")
+		}
+		i, err := input.Definition.ToService()
+		if err != nil {
+			return errors.Wrap(err, "converting alias definition to service model")
+		}
+		definition, ok := i.(dbModel.ProjectAlias)
+		if !ok {
+			return errors.Errorf("programmatic error: expected project alias but got type %T", i)
+		}
+		h.opts.Definition = &definition
+	}
+	return nil
+}
+
+func (h *projectAliasTestHandler) Run(ctx context.Context) gimlet.Responder {
+	variantTasks, err := data.TestProjectAlias(ctx, h.projectRef, h.opts)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "testing alias for project '%s'", h.projectRef.Identifier))
+	}
+	return gimlet.NewJSONResponse(variantTasks)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/selectors
+
+type projectSelectorHandler struct {
+	projectRef *dbModel.ProjectRef
+	opts       data.ProjectSelectorOpts
+}
+
+type projectSelectorInput struct {
+	Selector string `json:"selector"`
+	Type     string `json:"type"`
+	Revision string `json:"revision"`
+}
+
+func makeEvaluateProjectSelector() gimlet.RouteHandler {
+	return &projectSelectorHandler{}
+}
+
+func (h *projectSelectorHandler) Factory() gimlet.RouteHandler {
+	return &projectSelectorHandler{}
+}
+
+func (h *projectSelectorHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectRef = MustHaveProjectContext(ctx).ProjectRef
+
+	input := projectSelectorInput{}
+	if err := utility.ReadJSON(r.Body, &input); err != nil {
+		return errors.Wrap(err, "reading selector input from JSON request body")
+	}
+	if strings.TrimSpace(input.Selector) == "" {
+		return errors.New("must specify a selector")
+	}
+	if input.Type == "" {
+		input.Type = data.SelectorTypeTask
+	}
+	if input.Type != data.SelectorTypeTask && input.Type != data.SelectorTypeVariant {
+		return errors.Errorf("selector type must be '%s' or '%s'", data.SelectorTypeTask, data.SelectorTypeVariant)
+	}
+	h.opts = data.ProjectSelectorOpts{
+		Selector: input.Selector,
+		Type:     input.Type,
+		Revision: input.Revision,
+	}
+	return nil
+}
+
+// Run returns the tasks and task groups, or the variants, that the selector
+// selects from the project's config.
+func (h *projectSelectorHandler) Run(ctx context.Context) gimlet.Responder {
+	matches, err := data.EvaluateProjectSelector(ctx, h.projectRef, h.opts)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "evaluating selector for project '%s'", h.projectRef.Identifier))
+	}
+	return gimlet.NewJSONResponse(matches)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/local_plan
+
+type projectLocalPlanHandler struct {
+	projectRef *dbModel.ProjectRef
+	opts       data.LocalExecutionPlanOpts
+}
+
+type projectLocalPlanInput struct {
+	Variant    string            `json:"build_variant"`
+	Task       string            `json:"task"`
+	Revision   string            `json:"revision"`
+	Expansions map[string]string `json:"expansions"`
+}
+
+func makeCompileLocalExecutionPlan() gimlet.RouteHandler {
+	return &projectLocalPlanHandler{}
+}
+
+func (h *projectLocalPlanHandler) Factory() gimlet.RouteHandler {
+	return &projectLocalPlanHandler{}
+}
+
+func (h *projectLocalPlanHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectRef = MustHaveProjectContext(ctx).ProjectRef
+
+	input := projectLocalPlanInput{}
+	if err := utility.ReadJSON(r.Body, &input); err != nil {
+		return errors.Wrap(err, "reading local plan input from JSON request body")
+	}
+	if input.Variant == "" || input.Task == "" {
+		return errors.New("must specify a build variant and task")
+	}
+	h.opts = data.LocalExecutionPlanOpts{
+		Variant:    input.Variant,
+		Task:       input.Task,
+		Revision:   input.Revision,
+		Expansions: input.Expansions,
+	}
+	return nil
+}
+
+func (h *projectLocalPlanHandler) Run(ctx context.Context) gimlet.Responder {
+	plan, err := data.CompileLocalExecutionPlan(ctx, h.projectRef, h.opts)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "compiling local plan for task '%s' in project '%s'", h.opts.Task, h.projectRef.Identifier))
+	}
+	apiPlan := model.APILocalExecutionPlan{}
+	apiPlan.BuildFromService(*plan)
+	return gimlet.NewJSONResponse(apiPlan)
+}
+
 ////////////////////////////////////////////////////////////////////////
 //
 // Handler for the patch trigger aliases defined for project
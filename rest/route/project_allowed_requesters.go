@@ -0,0 +1,69 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/allowed_requesters_suggestion
+
+type projectAllowedRequestersSuggestionHandler struct {
+	projectRef *dbModel.ProjectRef
+}
+
+func makeGetProjectAllowedRequestersSuggestion() gimlet.RouteHandler {
+	return &projectAllowedRequestersSuggestionHandler{}
+}
+
+func (h *projectAllowedRequestersSuggestionHandler) Factory() gimlet.RouteHandler {
+	return &projectAllowedRequestersSuggestionHandler{}
+}
+
+func (h *projectAllowedRequestersSuggestionHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectRef = MustHaveProjectContext(ctx).ProjectRef
+	return nil
+}
+
+// Run rewrites the project's latest config to use allowed requesters instead
+// of the legacy requester flags. The rewritten config is only returned and is
+// never applied to the project.
+func (h *projectAllowedRequestersSuggestionHandler) Run(ctx context.Context) gimlet.Responder {
+	v, _, err := dbModel.FindLatestVersionWithValidProject(h.projectRef.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding latest version for project '%s'", h.projectRef.Id))
+	}
+	if v == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' has no version with a valid config", h.projectRef.Id),
+		})
+	}
+	projectInfo, err := dbModel.LoadProjectForVersion(v, h.projectRef.Id, false)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "loading config for version '%s'", v.Id))
+	}
+	if projectInfo.IntermediateProject == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("config for version '%s' not found", v.Id),
+		})
+	}
+
+	suggestion, err := dbModel.SuggestAllowedRequesters(projectInfo.IntermediateProject)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "rewriting config for version '%s'", v.Id))
+	}
+
+	resp := model.APIAllowedRequestersSuggestion{}
+	resp.BuildFromService(*suggestion)
+	resp.VersionID = &v.Id
+	return gimlet.NewJSONResponse(resp)
+}
@@ -0,0 +1,157 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/archive
+
+type projectArchiveHandler struct {
+	Reason string `json:"reason"`
+	// Force archives the project even if other projects depend on it.
+	Force bool `json:"force"`
+}
+
+func makeArchiveProject() gimlet.RouteHandler {
+	return &projectArchiveHandler{}
+}
+
+func (h *projectArchiveHandler) Factory() gimlet.RouteHandler {
+	return &projectArchiveHandler{}
+}
+
+func (h *projectArchiveHandler) Parse(ctx context.Context, r *http.Request) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+	return errors.Wrap(gimlet.GetJSON(r.Body, h), "parsing request body")
+}
+
+// Run snapshots the project's settings and disables the project along with
+// everything that can create or run its tasks.
+func (h *projectArchiveHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	if pRef == nil {
+		return gimlet.MakeJSONErrorResponder(errors.New("project not found"))
+	}
+	// The project context's project ref may be merged with its repo, but
+	// only the branch's own settings should be snapshotted.
+	branchRef, err := dbModel.FindBranchProjectRef(pRef.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project '%s'", pRef.Id))
+	}
+	if branchRef == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", pRef.Id),
+		})
+	}
+	if branchRef.IsArchived() || branchRef.IsHidden() {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("project '%s' cannot be archived because it is already archived or hidden", branchRef.Identifier),
+		})
+	}
+
+	dependents, resp := checkProjectDependents(pRef, dbModel.ProjectDependencyActionDisabled, h.Force)
+	if resp != nil {
+		return resp
+	}
+
+	u := MustHaveUser(ctx)
+	archive, err := dbModel.ArchiveProject(branchRef, u.Username(), h.Reason)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "archiving project '%s'", branchRef.Identifier))
+	}
+	grip.Error(message.WrapError(data.NotifyProjectDependents(pRef, dependents, dbModel.ProjectDependencyActionDisabled, u.Username()), message.Fields{
+		"message": "could not notify dependent projects of archived project",
+		"project": pRef.Id,
+	}))
+
+	apiArchive := model.APIProjectArchive{}
+	apiArchive.BuildFromService(*archive)
+	return gimlet.NewJSONResponse(apiArchive)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/resurrect
+
+type projectResurrectHandler struct {
+	// Force resurrects the project even if its snapshot no longer passes
+	// validation.
+	Force bool `json:"force"`
+
+	settings *evergreen.Settings
+}
+
+func makeResurrectProject(settings *evergreen.Settings) gimlet.RouteHandler {
+	return &projectResurrectHandler{settings: settings}
+}
+
+func (h *projectResurrectHandler) Factory() gimlet.RouteHandler {
+	return &projectResurrectHandler{settings: h.settings}
+}
+
+func (h *projectResurrectHandler) Parse(ctx context.Context, r *http.Request) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+	return errors.Wrap(gimlet.GetJSON(r.Body, h), "parsing request body")
+}
+
+// Run validates the archived project's snapshot against the current distros
+// and settings and then restores the project from it.
+func (h *projectResurrectHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	if pRef == nil {
+		return gimlet.MakeJSONErrorResponder(errors.New("project not found"))
+	}
+	archive, err := dbModel.FindProjectArchive(pRef.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding archive for project '%s'", pRef.Id))
+	}
+	if archive == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("project '%s' is not archived", pRef.Identifier),
+		})
+	}
+
+	problems := data.CheckProjectResurrection(ctx, archive, h.settings.GithubOrgs)
+	if len(problems) > 0 && !h.Force {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("project '%s' cannot be resurrected: %s", pRef.Identifier, strings.Join(problems, "; ")),
+		})
+	}
+
+	u := MustHaveUser(ctx)
+	resurrected, err := dbModel.ResurrectProject(pRef.Id, u.Username())
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "resurrecting project '%s'", pRef.Identifier))
+	}
+
+	apiProjectRef := model.APIProjectRef{}
+	if err = apiProjectRef.BuildFromService(*resurrected); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting project '%s' to API model", resurrected.Id))
+	}
+	return gimlet.NewJSONResponse(struct {
+		Project  model.APIProjectRef `json:"project"`
+		Problems []string            `json:"problems,omitempty"`
+	}{apiProjectRef, problems})
+}
@@ -0,0 +1,101 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/bisections
+
+type getProjectBisectionsHandler struct {
+	status string
+	limit  int
+}
+
+func makeGetProjectBisections() gimlet.RouteHandler {
+	return &getProjectBisectionsHandler{}
+}
+
+func (h *getProjectBisectionsHandler) Factory() gimlet.RouteHandler {
+	return &getProjectBisectionsHandler{}
+}
+
+func (h *getProjectBisectionsHandler) Parse(ctx context.Context, r *http.Request) error {
+	vals := r.URL.Query()
+	h.status = vals.Get("status")
+	switch h.status {
+	case "", dbModel.BisectionInProgress, dbModel.BisectionFound, dbModel.BisectionInconclusive:
+	default:
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid bisection status '%s'", h.status),
+		}
+	}
+	var err error
+	h.limit, err = getLimit(vals)
+	return err
+}
+
+// Run returns the project's most recent automatic bisections, newest first.
+func (h *getProjectBisectionsHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	bisections, err := dbModel.FindBisectionsByProject(pRef.Id, h.status, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	apiBisections := make([]model.APIBisection, 0, len(bisections))
+	for _, b := range bisections {
+		apiBisection := model.APIBisection{}
+		apiBisection.BuildFromService(b)
+		apiBisections = append(apiBisections, apiBisection)
+	}
+	return gimlet.NewJSONResponse(apiBisections)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/bisections/{bisection_id}
+
+type getProjectBisectionHandler struct {
+	bisectionID string
+}
+
+func makeGetProjectBisection() gimlet.RouteHandler {
+	return &getProjectBisectionHandler{}
+}
+
+func (h *getProjectBisectionHandler) Factory() gimlet.RouteHandler {
+	return &getProjectBisectionHandler{}
+}
+
+func (h *getProjectBisectionHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.bisectionID = gimlet.GetVars(r)["bisection_id"]
+	return nil
+}
+
+// Run returns the bisection's progress, including the commits it has run the
+// task in so far.
+func (h *getProjectBisectionHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	b, err := dbModel.FindBisection(h.bisectionID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding bisection '%s'", h.bisectionID))
+	}
+	if b == nil || b.ProjectId != pRef.Id {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("bisection '%s' not found in project '%s'", h.bisectionID, pRef.Identifier),
+		})
+	}
+	apiBisection := model.APIBisection{}
+	apiBisection.BuildFromService(*b)
+	return gimlet.NewJSONResponse(apiBisection)
+}
@@ -0,0 +1,159 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/change_requests
+
+type projectChangeRequestsGetHandler struct {
+	projectId string
+}
+
+func makeFetchProjectChangeRequests() gimlet.RouteHandler {
+	return &projectChangeRequestsGetHandler{}
+}
+
+func (h *projectChangeRequestsGetHandler) Factory() gimlet.RouteHandler {
+	return &projectChangeRequestsGetHandler{}
+}
+
+func (h *projectChangeRequestsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectId = MustHaveProjectContext(ctx).ProjectRef.Id
+	return nil
+}
+
+func (h *projectChangeRequestsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	changeRequests, err := dbModel.FindPendingProjectChangeRequests(h.projectId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding pending change requests for project '%s'", h.projectId))
+	}
+
+	resp := gimlet.NewResponseBuilder()
+	for _, cr := range changeRequests {
+		apiChangeRequest := model.APIProjectChangeRequest{}
+		if err = apiChangeRequest.BuildFromService(cr); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting change request '%s' to API model", cr.Id))
+		}
+		if err = resp.AddData(apiChangeRequest); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "adding response data"))
+		}
+	}
+	return resp
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/change_requests/{change_request_id}/approve
+// POST /rest/v2/projects/{project_id}/change_requests/{change_request_id}/reject
+
+type projectChangeRequestReviewHandler struct {
+	approve       bool
+	user          *user.DBUser
+	changeRequest *dbModel.ProjectChangeRequest
+
+	settings *evergreen.Settings
+}
+
+func makeReviewProjectChangeRequest(settings *evergreen.Settings, approve bool) gimlet.RouteHandler {
+	return &projectChangeRequestReviewHandler{
+		approve:  approve,
+		settings: settings,
+	}
+}
+
+func (h *projectChangeRequestReviewHandler) Factory() gimlet.RouteHandler {
+	return &projectChangeRequestReviewHandler{
+		approve:  h.approve,
+		settings: h.settings,
+	}
+}
+
+func (h *projectChangeRequestReviewHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.user = MustHaveUser(ctx)
+	projectId := MustHaveProjectContext(ctx).ProjectRef.Id
+	changeRequestId := gimlet.GetVars(r)["change_request_id"]
+
+	changeRequest, err := dbModel.FindProjectChangeRequestById(changeRequestId)
+	if err != nil {
+		return errors.Wrapf(err, "finding change request '%s'", changeRequestId)
+	}
+	if changeRequest == nil || changeRequest.ProjectId != projectId {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("change request '%s' not found for project '%s'", changeRequestId, projectId),
+		}
+	}
+	if changeRequest.Author == h.user.Username() {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusForbidden,
+			Message:    "change requests must be reviewed by an admin other than the author",
+		}
+	}
+	h.changeRequest = changeRequest
+	return nil
+}
+
+func (h *projectChangeRequestReviewHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.changeRequest.Review(h.user.Username(), h.approve); err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusConflict,
+			Message:    err.Error(),
+		})
+	}
+
+	if h.approve {
+		if err := h.apply(ctx); err != nil {
+			if setErr := h.changeRequest.SetFailed(err); setErr != nil {
+				return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(setErr, "marking change request '%s' as failed", h.changeRequest.Id))
+			}
+			return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "applying change request '%s'", h.changeRequest.Id))
+		}
+	}
+
+	apiChangeRequest := model.APIProjectChangeRequest{}
+	if err := apiChangeRequest.BuildFromService(*h.changeRequest); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "converting change request to API model"))
+	}
+	return gimlet.NewJSONResponse(apiChangeRequest)
+}
+
+// apply replays the original project update now that it has been approved.
+func (h *projectChangeRequestReviewHandler) apply(ctx context.Context) error {
+	current, err := dbModel.FindBranchProjectRef(h.changeRequest.ProjectId)
+	if err != nil {
+		return errors.Wrap(err, "finding current project settings")
+	}
+	if current == nil {
+		return errors.Errorf("project '%s' not found", h.changeRequest.ProjectId)
+	}
+	if changed := dbModel.ProjectRefChangedFields(h.changeRequest.Before, *current); len(changed) > 0 {
+		return errors.Errorf("project settings %v have changed since the change request was made", changed)
+	}
+
+	patchHandler := &projectIDPatchHandler{
+		project:  h.changeRequest.ProjectId,
+		user:     h.user,
+		approved: true,
+		settings: h.settings,
+	}
+	if err = patchHandler.parseProjectChanges([]byte(h.changeRequest.Body)); err != nil {
+		return errors.Wrap(err, "parsing requested changes")
+	}
+	resp := patchHandler.Run(ctx)
+	if resp.Status() != http.StatusOK {
+		return errors.Errorf("updating project: %v", resp.Data())
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/validator"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+///////////////////////////////////////////////////////////////////////
+//
+// PATCH /rest/v2/projects/{project_id}/project_config
+
+type projectConfigPatchHandler struct {
+	versionID string
+	patch     []byte
+}
+
+func makePatchProjectConfig() gimlet.RouteHandler {
+	return &projectConfigPatchHandler{}
+}
+
+func (h *projectConfigPatchHandler) Factory() gimlet.RouteHandler {
+	return &projectConfigPatchHandler{}
+}
+
+// Parse fetches the version whose project config is patched and the JSON
+// Patch document from the http request. If no version is given, the
+// project's most recent project config is patched.
+func (h *projectConfigPatchHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionID = r.URL.Query().Get("version")
+	body := utility.NewRequestReader(r)
+	defer body.Close()
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return errors.Wrap(err, "reading JSON patch from request body")
+	}
+	var ops []json.RawMessage
+	if err = json.Unmarshal(b, &ops); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "request body must be a JSON patch array").Error(),
+		}
+	}
+	if len(ops) == 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "JSON patch must have at least one operation",
+		}
+	}
+	h.patch = b
+
+	return nil
+}
+
+// Run applies the JSON Patch to the project config and saves it if the
+// patched config is valid.
+func (h *projectConfigPatchHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	if !pRef.IsVersionControlEnabled() {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("version control is not enabled for project '%s'", pRef.Identifier),
+		})
+	}
+
+	pc, err := dbModel.FindProjectConfigForProjectOrVersion(pRef.Id, h.versionID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project config for project '%s'", pRef.Identifier))
+	}
+	if pc == nil || pc.Project != pRef.Id {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project config not found for project '%s'", pRef.Identifier),
+		})
+	}
+
+	patched, err := pc.ApplyJSONPatch(h.patch)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		})
+	}
+	vErrors, err := validatePatchedProjectConfig(ctx, pRef.Id, patched)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "validating patched project config '%s'", patched.Id))
+	}
+	if vErrors = vErrors.AtLevel(validator.Error); len(vErrors) != 0 {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    vErrors.String(),
+		})
+	}
+	if err = patched.Replace(); err != nil {
+		if adb.ResultsNotFound(err) {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusConflict,
+				Message:    fmt.Sprintf("project config '%s' was modified while it was being patched, try again", patched.Id),
+			})
+		}
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "saving project config '%s'", patched.Id))
+	}
+	grip.Info(message.Fields{
+		"message":        "patched project config",
+		"project":        pRef.Id,
+		"project_config": patched.Id,
+		"user":           MustHaveUser(ctx).Username(),
+		"patch":          string(h.patch),
+	})
+
+	fieldsJSON, err := patched.FieldsJSON()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	return gimlet.NewJSONResponse(json.RawMessage(fieldsJSON))
+}
+
+// validatePatchedProjectConfig validates the patched project config along with
+// the project of its version and the project settings it would merge into, as
+// if the config had been pushed with the version.
+func validatePatchedProjectConfig(ctx context.Context, projectID string, pc *dbModel.ProjectConfig) (validator.ValidationErrors, error) {
+	errs := validator.CheckProjectConfigErrors(ctx, pc)
+
+	project, err := dbModel.FindProjectFromVersionID(pc.Id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project for version '%s'", pc.Id)
+	}
+	// The project ref is merged with the patched config rather than the
+	// stored one.
+	pRef, err := dbModel.FindMergedProjectRef(projectID, "", false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project ref '%s'", projectID)
+	}
+	if pRef == nil {
+		return nil, errors.Errorf("project ref '%s' not found", projectID)
+	}
+	if err = pRef.MergeWithGivenProjectConfig(pc); err != nil {
+		return nil, errors.Wrapf(err, "merging patched project config with project ref '%s'", projectID)
+	}
+
+	errs = append(errs, validator.CheckProjectSettings(ctx, project, pRef, true)...)
+	errs = append(errs, validator.CheckProjectErrors(ctx, project, pRef, false)...)
+	return errs, nil
+}
@@ -106,6 +106,7 @@ func (p *copyVariablesHandler) Run(ctx context.Context) gimlet.Responder {
 			if isPrivate {
 				delete(varsToCopy.Vars, key)
 				delete(varsToCopy.AdminOnlyVars, key)
+				delete(varsToCopy.RestrictedVars, key)
 			}
 		}
 		varsToCopy.PrivateVars = map[string]bool{}
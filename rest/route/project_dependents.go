@@ -0,0 +1,63 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/dependents
+
+type projectDependentsGetHandler struct{}
+
+func makeGetProjectDependents() gimlet.RouteHandler {
+	return &projectDependentsGetHandler{}
+}
+
+func (h *projectDependentsGetHandler) Factory() gimlet.RouteHandler {
+	return &projectDependentsGetHandler{}
+}
+
+func (h *projectDependentsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+// Run returns the enabled projects that would break if the project were
+// deleted or disabled.
+func (h *projectDependentsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	dependents, err := dbModel.FindProjectDependents(pRef)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding dependents of project '%s'", pRef.Identifier))
+	}
+	return gimlet.NewJSONResponse(dependents)
+}
+
+// checkProjectDependents returns the projects that depend on the project. If
+// there are any and the action isn't forced, it returns an error response
+// listing them instead.
+func checkProjectDependents(pRef *dbModel.ProjectRef, action string, force bool) ([]dbModel.ProjectDependent, gimlet.Responder) {
+	dependents, err := dbModel.FindProjectDependents(pRef)
+	if err != nil {
+		return nil, gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding dependents of project '%s'", pRef.Identifier))
+	}
+	if len(dependents) == 0 || force {
+		return dependents, nil
+	}
+	descriptions := make([]string, 0, len(dependents))
+	for _, dependent := range dependents {
+		descriptions = append(descriptions, fmt.Sprintf("%s (%s)", dependent.Identifier, strings.Join(dependent.Kinds, ", ")))
+	}
+	return nil, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+		StatusCode: http.StatusBadRequest,
+		Message: fmt.Sprintf("project '%s' cannot be %s because other projects depend on it: %s; set force=true to proceed anyway",
+			pRef.Identifier, action, strings.Join(descriptions, "; ")),
+	})
+}
@@ -0,0 +1,57 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/log_retention
+
+type projectLogRetentionHandler struct {
+	projectRef *dbModel.ProjectRef
+	env        evergreen.Environment
+}
+
+func makeGetProjectLogRetention(env evergreen.Environment) gimlet.RouteHandler {
+	return &projectLogRetentionHandler{env: env}
+}
+
+func (h *projectLogRetentionHandler) Factory() gimlet.RouteHandler {
+	return &projectLogRetentionHandler{env: h.env}
+}
+
+func (h *projectLogRetentionHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectRef = MustHaveProjectContext(ctx).ProjectRef
+	return nil
+}
+
+// Run returns the project's effective log retention policy along with an
+// estimate of how much storage the logs it retains currently use.
+func (h *projectLogRetentionHandler) Run(ctx context.Context) gimlet.Responder {
+	policy := h.projectRef.GetLogRetentionPolicy()
+	longestTier := policy.TaskLogDays
+	if policy.FailedTaskLogDays > longestTier {
+		longestTier = policy.FailedTaskLogDays
+	}
+	since := time.Now().Add(-time.Duration(longestTier) * 24 * time.Hour)
+
+	estimate, err := dbModel.EstimateProjectLogStorage(ctx, h.env, h.projectRef.Id, since)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "estimating log storage for project '%s'", h.projectRef.Id))
+	}
+
+	resp := model.APIProjectLogRetention{}
+	resp.Configured.BuildFromService(h.projectRef.LogRetention)
+	resp.Effective.BuildFromService(policy)
+	resp.StorageEstimate.BuildFromService(*estimate)
+	return gimlet.NewJSONResponse(resp)
+}
@@ -0,0 +1,75 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/selection_preview
+
+type projectSelectionPreviewHandler struct {
+	projectRef *dbModel.ProjectRef
+	expr       *dbModel.SelectionExpression
+}
+
+func makePreviewProjectSelection() gimlet.RouteHandler {
+	return &projectSelectionPreviewHandler{}
+}
+
+func (h *projectSelectionPreviewHandler) Factory() gimlet.RouteHandler {
+	return &projectSelectionPreviewHandler{}
+}
+
+func (h *projectSelectionPreviewHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectRef = MustHaveProjectContext(ctx).ProjectRef
+
+	body := struct {
+		Expression string `json:"expression"`
+	}{}
+	if err := utility.ReadJSON(r.Body, &body); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "reading request body").Error(),
+		}
+	}
+	expr, err := dbModel.ParseSelectionExpression(body.Expression)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrapf(err, "parsing selection expression '%s'", body.Expression).Error(),
+		}
+	}
+	h.expr = expr
+	return nil
+}
+
+// Run evaluates the selection expression against the project's latest config
+// and returns the variants and tasks that it selects, so that an expression
+// can be checked before it's used in an alias.
+func (h *projectSelectionPreviewHandler) Run(ctx context.Context) gimlet.Responder {
+	v, project, err := dbModel.FindLatestVersionWithValidProject(h.projectRef.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding latest version for project '%s'", h.projectRef.Id))
+	}
+	if v == nil || project == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' has no version with a valid config", h.projectRef.Id),
+		})
+	}
+
+	pairs, displayTaskPairs := project.SelectTVPairs(h.expr)
+	resp := model.APISelectionPreview{}
+	resp.BuildFromService(h.expr, pairs, displayTaskPairs)
+	resp.VersionID = utility.ToStringPtr(v.Id)
+	return gimlet.NewJSONResponse(resp)
+}
@@ -0,0 +1,228 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/service_accounts
+
+type getServiceAccountsHandler struct{}
+
+func makeGetServiceAccounts() gimlet.RouteHandler {
+	return &getServiceAccountsHandler{}
+}
+
+func (h *getServiceAccountsHandler) Factory() gimlet.RouteHandler {
+	return &getServiceAccountsHandler{}
+}
+
+func (h *getServiceAccountsHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *getServiceAccountsHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	accounts, err := user.FindServiceAccountsForProject(pRef.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	apiAccounts := make([]model.APIServiceAccount, 0, len(accounts))
+	for _, account := range accounts {
+		apiAccount := model.APIServiceAccount{}
+		apiAccount.BuildFromService(account, false)
+		apiAccounts = append(apiAccounts, apiAccount)
+	}
+	return gimlet.NewJSONResponse(apiAccounts)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/service_accounts
+
+type createServiceAccountHandler struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+func makeCreateServiceAccount() gimlet.RouteHandler {
+	return &createServiceAccountHandler{}
+}
+
+func (h *createServiceAccountHandler) Factory() gimlet.RouteHandler {
+	return &createServiceAccountHandler{}
+}
+
+func (h *createServiceAccountHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := gimlet.GetJSON(r.Body, h); err != nil {
+		return errors.Wrap(err, "parsing request body")
+	}
+	if err := dbModel.ValidateServiceAccount(h.Name, h.Permissions); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return nil
+}
+
+// Run issues the service account and returns it along with its API key, which
+// isn't returned again unless the key is rotated.
+func (h *createServiceAccountHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	existing, err := user.FindOneById(dbModel.ServiceAccountID(pRef.Id, h.Name))
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "checking for existing service account"))
+	}
+	if existing != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("service account '%s' already exists in project '%s'", h.Name, pRef.Identifier),
+		})
+	}
+
+	account, err := dbModel.CreateServiceAccount(pRef, h.Name, h.Permissions, MustHaveUser(ctx).Username())
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "creating service account '%s'", h.Name))
+	}
+
+	apiAccount := model.APIServiceAccount{}
+	apiAccount.BuildFromService(*account, true)
+	return gimlet.NewJSONResponse(apiAccount)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/service_accounts/{account_id}/rotate
+
+type rotateServiceAccountKeyHandler struct {
+	accountID string
+}
+
+func makeRotateServiceAccountKey() gimlet.RouteHandler {
+	return &rotateServiceAccountKeyHandler{}
+}
+
+func (h *rotateServiceAccountKeyHandler) Factory() gimlet.RouteHandler {
+	return &rotateServiceAccountKeyHandler{}
+}
+
+func (h *rotateServiceAccountKeyHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.accountID = gimlet.GetVars(r)["account_id"]
+	return nil
+}
+
+// Run replaces the service account's API key and returns the new key.
+func (h *rotateServiceAccountKeyHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	if resp := checkServiceAccountExists(pRef, h.accountID); resp != nil {
+		return resp
+	}
+
+	account, err := dbModel.RotateServiceAccountKey(pRef.Id, h.accountID, MustHaveUser(ctx).Username())
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "rotating API key for service account '%s'", h.accountID))
+	}
+
+	apiAccount := model.APIServiceAccount{}
+	apiAccount.BuildFromService(*account, true)
+	return gimlet.NewJSONResponse(apiAccount)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/projects/{project_id}/service_accounts/{account_id}
+
+type revokeServiceAccountHandler struct {
+	accountID string
+}
+
+func makeRevokeServiceAccount() gimlet.RouteHandler {
+	return &revokeServiceAccountHandler{}
+}
+
+func (h *revokeServiceAccountHandler) Factory() gimlet.RouteHandler {
+	return &revokeServiceAccountHandler{}
+}
+
+func (h *revokeServiceAccountHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.accountID = gimlet.GetVars(r)["account_id"]
+	return nil
+}
+
+func (h *revokeServiceAccountHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	if resp := checkServiceAccountExists(pRef, h.accountID); resp != nil {
+		return resp
+	}
+
+	if err := dbModel.RevokeServiceAccount(pRef.Id, h.accountID, MustHaveUser(ctx).Username()); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "revoking service account '%s'", h.accountID))
+	}
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/service_accounts/events
+
+type getServiceAccountEventsHandler struct {
+	limit int
+}
+
+func makeGetServiceAccountEvents() gimlet.RouteHandler {
+	return &getServiceAccountEventsHandler{}
+}
+
+func (h *getServiceAccountEventsHandler) Factory() gimlet.RouteHandler {
+	return &getServiceAccountEventsHandler{}
+}
+
+func (h *getServiceAccountEventsHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.limit, err = getLimit(r.URL.Query())
+	return err
+}
+
+// Run returns the audit log of the project's service accounts, from the most
+// recent event.
+func (h *getServiceAccountEventsHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	events, err := event.FindServiceAccountEventsForProject(pRef.Id, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	apiEvents := make([]model.APIServiceAccountEvent, 0, len(events))
+	for _, e := range events {
+		apiEvent := model.APIServiceAccountEvent{}
+		apiEvent.BuildFromService(e)
+		apiEvents = append(apiEvents, apiEvent)
+	}
+	return gimlet.NewJSONResponse(apiEvents)
+}
+
+// checkServiceAccountExists returns an error response if the project doesn't
+// have the service account.
+func checkServiceAccountExists(pRef *dbModel.ProjectRef, accountID string) gimlet.Responder {
+	account, err := user.FindServiceAccount(pRef.Id, accountID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding service account '%s'", accountID))
+	}
+	if account == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("service account '%s' not found in project '%s'", accountID, pRef.Identifier),
+		})
+	}
+	return nil
+}
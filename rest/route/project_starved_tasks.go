@@ -0,0 +1,76 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/starved_tasks
+
+type projectStarvedTasksHandler struct {
+	threshold time.Duration
+	limit     int
+}
+
+func makeGetProjectStarvedTasks() gimlet.RouteHandler {
+	return &projectStarvedTasksHandler{}
+}
+
+func (h *projectStarvedTasksHandler) Factory() gimlet.RouteHandler {
+	return &projectStarvedTasksHandler{}
+}
+
+// Parse fetches the optional starvation threshold, which defaults to the
+// project's threshold, and the maximum number of tasks to return.
+func (h *projectStarvedTasksHandler) Parse(ctx context.Context, r *http.Request) error {
+	vals := r.URL.Query()
+	if thresholdMins := vals.Get("threshold_mins"); thresholdMins != "" {
+		mins, err := strconv.Atoi(thresholdMins)
+		if err != nil || mins <= 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid threshold '%s', must be a positive number of minutes", thresholdMins),
+			}
+		}
+		h.threshold = time.Duration(mins) * time.Minute
+	}
+	if limit := vals.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid limit '%s'", limit),
+			}
+		}
+		h.limit = n
+	}
+	return nil
+}
+
+// Run returns the project's tasks that have been waiting to be dispatched
+// for longer than the threshold, from the longest waiting.
+func (h *projectStarvedTasksHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	starved, err := dbModel.FindStarvedTasks(pRef, h.threshold, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding starved tasks for project '%s'", pRef.Identifier))
+	}
+
+	resp := make([]model.APIStarvedTask, 0, len(starved))
+	for _, t := range starved {
+		apiTask := model.APIStarvedTask{}
+		apiTask.BuildFromService(t)
+		resp = append(resp, apiTask)
+	}
+	return gimlet.NewJSONResponse(resp)
+}
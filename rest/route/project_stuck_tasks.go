@@ -0,0 +1,58 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/stuck_tasks
+// GET /rest/v2/admin/stuck_tasks
+
+type stuckTasksHandler struct {
+	allProjects bool
+}
+
+func makeGetProjectStuckTasks() gimlet.RouteHandler {
+	return &stuckTasksHandler{}
+}
+
+func makeGetAllStuckTasks() gimlet.RouteHandler {
+	return &stuckTasksHandler{allProjects: true}
+}
+
+func (h *stuckTasksHandler) Factory() gimlet.RouteHandler {
+	return &stuckTasksHandler{allProjects: h.allProjects}
+}
+
+func (h *stuckTasksHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+// Run returns the started tasks whose heartbeat has been stale for longer
+// than their distro's stuck task threshold, from the longest stale.
+func (h *stuckTasksHandler) Run(ctx context.Context) gimlet.Responder {
+	var projectID string
+	if !h.allProjects {
+		projectID = MustHaveProjectContext(ctx).ProjectRef.Id
+	}
+	stuck, err := dbModel.FindStuckTasks(projectID, time.Now())
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "finding stuck tasks"))
+	}
+
+	resp := make([]model.APIStuckTask, 0, len(stuck))
+	for _, t := range stuck {
+		apiTask := model.APIStuckTask{}
+		apiTask.BuildFromService(t)
+		resp = append(resp, apiTask)
+	}
+	return gimlet.NewJSONResponse(resp)
+}
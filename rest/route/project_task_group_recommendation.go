@@ -0,0 +1,82 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/task_groups/{task_group}/max_hosts_recommendation
+
+type taskGroupMaxHostsRecommendationHandler struct {
+	projectRef  *dbModel.ProjectRef
+	taskGroup   string
+	variant     string
+	numVersions int
+}
+
+func makeGetTaskGroupMaxHostsRecommendation() gimlet.RouteHandler {
+	return &taskGroupMaxHostsRecommendationHandler{}
+}
+
+func (h *taskGroupMaxHostsRecommendationHandler) Factory() gimlet.RouteHandler {
+	return &taskGroupMaxHostsRecommendationHandler{}
+}
+
+func (h *taskGroupMaxHostsRecommendationHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectRef = MustHaveProjectContext(ctx).ProjectRef
+	h.taskGroup = gimlet.GetVars(r)["task_group"]
+	vals := r.URL.Query()
+	h.variant = vals.Get("variant")
+	h.numVersions = dbModel.DefaultMaxHostsRecommendationVersions
+	if versions := vals.Get("versions"); versions != "" {
+		numVersions, err := strconv.Atoi(versions)
+		if err != nil || numVersions <= 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid number of versions '%s'", versions),
+			}
+		}
+		h.numVersions = numVersions
+	}
+	return nil
+}
+
+// Run recommends a max_hosts for the task group in the project's latest
+// config based on how the group ran in recent mainline versions.
+func (h *taskGroupMaxHostsRecommendationHandler) Run(ctx context.Context) gimlet.Responder {
+	v, project, err := dbModel.FindLatestVersionWithValidProject(h.projectRef.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding latest version for project '%s'", h.projectRef.Id))
+	}
+	if v == nil || project == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' has no version with a valid config", h.projectRef.Id),
+		})
+	}
+	if project.FindTaskGroup(h.taskGroup) == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task group '%s' not found in config for version '%s'", h.taskGroup, v.Id),
+		})
+	}
+
+	rec, err := dbModel.RecommendTaskGroupMaxHosts(project, h.projectRef.Id, h.variant, h.taskGroup, h.numVersions)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "recommending max hosts for task group '%s'", h.taskGroup))
+	}
+
+	resp := model.APITaskGroupMaxHostsRecommendation{}
+	resp.BuildFromService(*rec)
+	resp.VersionID = &v.Id
+	return gimlet.NewJSONResponse(resp)
+}
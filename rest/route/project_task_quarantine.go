@@ -0,0 +1,145 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/quarantined_tasks
+
+type getTaskQuarantinesHandler struct{}
+
+func makeGetTaskQuarantines() gimlet.RouteHandler {
+	return &getTaskQuarantinesHandler{}
+}
+
+func (h *getTaskQuarantinesHandler) Factory() gimlet.RouteHandler {
+	return &getTaskQuarantinesHandler{}
+}
+
+func (h *getTaskQuarantinesHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+// Run returns the project's quarantined tasks whose quarantines have not
+// expired.
+func (h *getTaskQuarantinesHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	quarantines, err := dbModel.FindActiveTaskQuarantines(pRef.Id, time.Now())
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	apiQuarantines := make([]model.APITaskQuarantine, 0, len(quarantines))
+	for _, q := range quarantines {
+		apiQuarantine := model.APITaskQuarantine{}
+		apiQuarantine.BuildFromService(q)
+		apiQuarantines = append(apiQuarantines, apiQuarantine)
+	}
+	return gimlet.NewJSONResponse(apiQuarantines)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/projects/{project_id}/quarantined_tasks
+
+type putTaskQuarantineHandler struct {
+	quarantine dbModel.TaskQuarantine
+}
+
+func makePutTaskQuarantine() gimlet.RouteHandler {
+	return &putTaskQuarantineHandler{}
+}
+
+func (h *putTaskQuarantineHandler) Factory() gimlet.RouteHandler {
+	return &putTaskQuarantineHandler{}
+}
+
+func (h *putTaskQuarantineHandler) Parse(ctx context.Context, r *http.Request) error {
+	apiQuarantine := model.APITaskQuarantine{}
+	if err := utility.ReadJSON(r.Body, &apiQuarantine); err != nil {
+		return errors.Wrap(err, "reading task quarantine from JSON request body")
+	}
+	h.quarantine = apiQuarantine.ToService()
+	h.quarantine.CreateTime = time.Now()
+	if err := h.quarantine.Validate(h.quarantine.CreateTime); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return nil
+}
+
+// Run quarantines the task in the build variant, replacing the reason and
+// expiration if the task is already quarantined.
+func (h *putTaskQuarantineHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	h.quarantine.ProjectId = pRef.Id
+	h.quarantine.CreatedBy = MustHaveUser(ctx).Username()
+
+	quarantine, err := dbModel.QuarantineTask(h.quarantine)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "quarantining task '%s' in build variant '%s'", h.quarantine.TaskName, h.quarantine.BuildVariant))
+	}
+	if quarantine == nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Errorf("quarantine of task '%s' in build variant '%s' not found after it was created", h.quarantine.TaskName, h.quarantine.BuildVariant))
+	}
+
+	apiQuarantine := model.APITaskQuarantine{}
+	apiQuarantine.BuildFromService(*quarantine)
+	return gimlet.NewJSONResponse(apiQuarantine)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/projects/{project_id}/quarantined_tasks/{variant}/{task_name}
+
+type deleteTaskQuarantineHandler struct {
+	buildVariant string
+	taskName     string
+}
+
+func makeDeleteTaskQuarantine() gimlet.RouteHandler {
+	return &deleteTaskQuarantineHandler{}
+}
+
+func (h *deleteTaskQuarantineHandler) Factory() gimlet.RouteHandler {
+	return &deleteTaskQuarantineHandler{}
+}
+
+func (h *deleteTaskQuarantineHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.buildVariant = vars["variant"]
+	h.taskName = vars["task_name"]
+	return nil
+}
+
+// Run lifts the quarantine of the task in the build variant so that it is
+// scheduled again.
+func (h *deleteTaskQuarantineHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	quarantine, err := dbModel.FindTaskQuarantine(pRef.Id, h.buildVariant, h.taskName)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	if quarantine == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' in build variant '%s' is not quarantined in project '%s'", h.taskName, h.buildVariant, pRef.Identifier),
+		})
+	}
+	if err = dbModel.RemoveTaskQuarantine(pRef.Id, h.buildVariant, h.taskName); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	return gimlet.NewJSONResponse(struct{}{})
+}
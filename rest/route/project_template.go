@@ -0,0 +1,240 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/project_templates
+
+type projectTemplatesGetHandler struct{}
+
+func makeGetProjectTemplates() gimlet.RouteHandler {
+	return &projectTemplatesGetHandler{}
+}
+
+func (h *projectTemplatesGetHandler) Factory() gimlet.RouteHandler {
+	return &projectTemplatesGetHandler{}
+}
+
+func (h *projectTemplatesGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+// Run returns all the project templates.
+func (h *projectTemplatesGetHandler) Run(ctx context.Context) gimlet.Responder {
+	templates, err := dbModel.FindAllProjectTemplates()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "finding project templates"))
+	}
+	apiTemplates := []model.APIProjectTemplate{}
+	for _, template := range templates {
+		apiTemplate := model.APIProjectTemplate{}
+		if err = apiTemplate.BuildFromService(template); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting project template '%s' to API model", template.Name))
+		}
+		apiTemplates = append(apiTemplates, apiTemplate)
+	}
+	return gimlet.NewJSONResponse(apiTemplates)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/project_templates/{template_name}
+
+type projectTemplateGetHandler struct {
+	name string
+}
+
+func makeGetProjectTemplate() gimlet.RouteHandler {
+	return &projectTemplateGetHandler{}
+}
+
+func (h *projectTemplateGetHandler) Factory() gimlet.RouteHandler {
+	return &projectTemplateGetHandler{}
+}
+
+func (h *projectTemplateGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.name = gimlet.GetVars(r)["template_name"]
+	return nil
+}
+
+// Run returns the project template. The values of private variables are
+// redacted.
+func (h *projectTemplateGetHandler) Run(ctx context.Context) gimlet.Responder {
+	template, err := dbModel.FindProjectTemplate(h.name)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project template '%s'", h.name))
+	}
+	if template == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project template '%s' not found", h.name),
+		})
+	}
+	apiTemplate := model.APIProjectTemplate{}
+	if err = apiTemplate.BuildFromService(*template); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting project template '%s' to API model", h.name))
+	}
+	return gimlet.NewJSONResponse(apiTemplate)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/project_templates/{template_name}
+
+type projectTemplatePutHandler struct {
+	name     string
+	template *dbModel.ProjectTemplate
+}
+
+func makePutProjectTemplate() gimlet.RouteHandler {
+	return &projectTemplatePutHandler{}
+}
+
+func (h *projectTemplatePutHandler) Factory() gimlet.RouteHandler {
+	return &projectTemplatePutHandler{}
+}
+
+// Parse reads the template from the request body. The template replaces any
+// existing template with the same name in full.
+func (h *projectTemplatePutHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.name = gimlet.GetVars(r)["template_name"]
+	apiTemplate := model.APIProjectTemplate{}
+	if err := gimlet.GetJSON(r.Body, &apiTemplate); err != nil {
+		return errors.Wrap(err, "reading project template from JSON request body")
+	}
+	template, err := apiTemplate.ToService()
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "converting project template to service model").Error(),
+		}
+	}
+	template.Name = h.name
+	if err = template.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrapf(err, "invalid project template '%s'", h.name).Error(),
+		}
+	}
+	h.template = template
+	return nil
+}
+
+// Run saves the project template. Private variables that were given without a
+// value keep the value they have in the existing template.
+func (h *projectTemplatePutHandler) Run(ctx context.Context) gimlet.Responder {
+	existing, err := dbModel.FindProjectTemplate(h.name)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project template '%s'", h.name))
+	}
+	h.template.KeepPrivateVarValues(existing)
+	h.template.LastUpdated = time.Now()
+	h.template.UpdatedBy = MustHaveUser(ctx).Username()
+	if err := h.template.Upsert(); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "saving project template '%s'", h.name))
+	}
+	apiTemplate := model.APIProjectTemplate{}
+	if err := apiTemplate.BuildFromService(*h.template); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting project template '%s' to API model", h.name))
+	}
+	return gimlet.NewJSONResponse(apiTemplate)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/project_templates/{template_name}
+
+type projectTemplateDeleteHandler struct {
+	name string
+}
+
+func makeDeleteProjectTemplate() gimlet.RouteHandler {
+	return &projectTemplateDeleteHandler{}
+}
+
+func (h *projectTemplateDeleteHandler) Factory() gimlet.RouteHandler {
+	return &projectTemplateDeleteHandler{}
+}
+
+func (h *projectTemplateDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.name = gimlet.GetVars(r)["template_name"]
+	return nil
+}
+
+// Run deletes the project template. Projects that were already created from
+// the template are unaffected.
+func (h *projectTemplateDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := dbModel.RemoveProjectTemplate(h.name); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "deleting project template '%s'", h.name))
+	}
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/project_templates/{template_name}/projects
+
+type projectFromTemplateHandler struct {
+	// Identifier is the identifier of the new project.
+	Identifier string `json:"identifier"`
+	// Values fill in the template's placeholders.
+	Values map[string]string `json:"values"`
+
+	templateName string
+	settings     *evergreen.Settings
+}
+
+func makeCreateProjectFromTemplate(settings *evergreen.Settings) gimlet.RouteHandler {
+	return &projectFromTemplateHandler{settings: settings}
+}
+
+func (h *projectFromTemplateHandler) Factory() gimlet.RouteHandler {
+	return &projectFromTemplateHandler{settings: h.settings}
+}
+
+func (h *projectFromTemplateHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.templateName = gimlet.GetVars(r)["template_name"]
+	if err := gimlet.GetJSON(r.Body, h); err != nil {
+		return errors.Wrap(err, "parsing request body")
+	}
+	if h.Identifier == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must provide an identifier for the new project",
+		}
+	}
+	return nil
+}
+
+// Run creates a project from the template with its placeholders filled in,
+// along with its variables, aliases, and subscriptions. Nothing is created if
+// the filled in settings are invalid.
+func (h *projectFromTemplateHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef, err := data.CreateProjectFromTemplate(h.templateName, h.Identifier, h.Values, MustHaveUser(ctx), h.settings.GithubOrgs)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "creating project '%s' from template '%s'", h.Identifier, h.templateName))
+	}
+
+	apiProjectRef := model.APIProjectRef{}
+	if err = apiProjectRef.BuildFromService(*pRef); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting project '%s' to API model", pRef.Id))
+	}
+	responder := gimlet.NewJSONResponse(apiProjectRef)
+	if err = responder.SetStatus(http.StatusCreated); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "setting response HTTP status code to %d", http.StatusCreated))
+	}
+	return responder
+}
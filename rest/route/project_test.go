@@ -167,6 +167,31 @@ func (s *ProjectPatchByIDSuite) TestRunWithCommitQueueEnabled() {
 	s.Equal("cannot enable commit queue without first enabling GitHub webhooks", errResp.Message)
 }
 
+func (s *ProjectPatchByIDSuite) TestRunRequestsApprovalBeforeEnablingCommitQueue() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.NoError(db.ClearCollections(serviceModel.ProjectChangeRequestsCollection))
+	defer func() {
+		s.NoError(db.ClearCollections(serviceModel.ProjectChangeRequestsCollection))
+	}()
+	pRef, err := serviceModel.FindBranchProjectRef("dimoxinil")
+	s.Require().NoError(err)
+	s.Require().NotNil(pRef)
+	pRef.Restricted = utility.TruePtr()
+	pRef.RequireSettingsApproval = utility.TruePtr()
+	s.Require().NoError(pRef.Update())
+
+	ctx = gimlet.AttachUser(ctx, &user.DBUser{Id: "Test1"})
+	jsonBody := []byte(`{"enabled": true, "commit_queue": {"enabled": true}}`)
+	req, _ := http.NewRequest(http.MethodPatch, "http://example.com/api/rest/v2/projects/dimoxinil", bytes.NewBuffer(jsonBody))
+	req = gimlet.SetURLVars(req, map[string]string{"project_id": "dimoxinil"})
+	s.Require().NoError(s.rm.Parse(ctx, req))
+
+	resp := s.rm.Run(ctx)
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusAccepted, resp.Status(), "should request approval before checking the webhooks")
+}
+
 func (s *ProjectPatchByIDSuite) TestRunWithValidBbConfig() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
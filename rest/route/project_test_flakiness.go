@@ -0,0 +1,78 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/test_flakiness
+
+type projectTestFlakinessHandler struct {
+	filter testresult.FlakinessFilter
+}
+
+func makeGetProjectTestFlakiness() gimlet.RouteHandler {
+	return &projectTestFlakinessHandler{}
+}
+
+func (h *projectTestFlakinessHandler) Factory() gimlet.RouteHandler {
+	return &projectTestFlakinessHandler{}
+}
+
+func (h *projectTestFlakinessHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.filter = testresult.FlakinessFilter{Project: MustHaveProjectContext(ctx).ProjectRef.Id}
+	vals := r.URL.Query()
+	h.filter.BuildVariant = vals.Get("variant")
+	h.filter.TaskName = vals.Get("task")
+	if tests := vals.Get("tests"); tests != "" {
+		h.filter.TestNames = strings.Split(tests, ",")
+	}
+	if minScore := vals.Get("min_score"); minScore != "" {
+		score, err := strconv.ParseFloat(minScore, 64)
+		if err != nil || score < 0 || score > 1 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid minimum score '%s', must be between 0 and 1", minScore),
+			}
+		}
+		h.filter.MinScore = score
+	}
+	if limit := vals.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid limit '%s'", limit),
+			}
+		}
+		h.filter.Limit = n
+	}
+	return nil
+}
+
+// Run returns the flakiness scores of the project's tests, from flakiest to
+// least flaky.
+func (h *projectTestFlakinessHandler) Run(ctx context.Context) gimlet.Responder {
+	rollups, err := testresult.FindFlakiness(h.filter)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding test flakiness for project '%s'", h.filter.Project))
+	}
+
+	resp := make([]model.APITestFlakiness, 0, len(rollups))
+	for _, rollup := range rollups {
+		apiRollup := model.APITestFlakiness{}
+		apiRollup.BuildFromService(rollup)
+		resp = append(resp, apiRollup)
+	}
+	return gimlet.NewJSONResponse(resp)
+}
@@ -0,0 +1,82 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/test_history
+
+type projectTestHistoryHandler struct {
+	opts dbModel.TestHistoryOptions
+}
+
+func makeGetProjectTestHistory() gimlet.RouteHandler {
+	return &projectTestHistoryHandler{}
+}
+
+func (h *projectTestHistoryHandler) Factory() gimlet.RouteHandler {
+	return &projectTestHistoryHandler{}
+}
+
+func (h *projectTestHistoryHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.opts = dbModel.TestHistoryOptions{Project: MustHaveProjectContext(ctx).ProjectRef.Id}
+	vals := r.URL.Query()
+	h.opts.TestName = vals.Get("test")
+	if h.opts.TestName == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a test",
+		}
+	}
+	h.opts.BuildVariant = vals.Get("variant")
+	h.opts.TaskName = vals.Get("task")
+	if requesters := vals.Get("requesters"); requesters != "" {
+		h.opts.Requesters = strings.Split(requesters, ",")
+	}
+	if before := vals.Get("before"); before != "" {
+		ts, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid time '%s', must be in RFC3339 format", before),
+			}
+		}
+		h.opts.Before = ts
+	}
+	if limit := vals.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 || n > dbModel.MaxTestHistoryLimit {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid limit '%s', must be between 0 and %d", limit, dbModel.MaxTestHistoryLimit),
+			}
+		}
+		h.opts.Limit = n
+	}
+	return nil
+}
+
+// Run returns the results of the test in the project's task executions,
+// including executions that have since been restarted, from the most recent.
+func (h *projectTestHistoryHandler) Run(ctx context.Context) gimlet.Responder {
+	history, err := dbModel.GetTestExecutionHistory(h.opts)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting history of test '%s' for project '%s'", h.opts.TestName, h.opts.Project))
+	}
+
+	resp := model.APITestHistory{}
+	resp.BuildFromService(h.opts.TestName, *history)
+	return gimlet.NewJSONResponse(resp)
+}
@@ -0,0 +1,46 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/trigger_graph
+
+type projectTriggerGraphHandler struct {
+	settings *evergreen.Settings
+}
+
+func makeGetProjectTriggerGraph(settings *evergreen.Settings) gimlet.RouteHandler {
+	return &projectTriggerGraphHandler{settings: settings}
+}
+
+func (h *projectTriggerGraphHandler) Factory() gimlet.RouteHandler {
+	return &projectTriggerGraphHandler{settings: h.settings}
+}
+
+func (h *projectTriggerGraphHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *projectTriggerGraphHandler) Run(ctx context.Context) gimlet.Responder {
+	graph, err := dbModel.FindProjectTriggerGraph()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "finding project trigger graph"))
+	}
+
+	apiGraph := model.APIProjectTriggerGraph{
+		MaxDepth:  h.settings.Triggers.GetMaxDownstreamDepth(),
+		MaxFanOut: h.settings.Triggers.GetMaxDownstreamFanOut(),
+	}
+	apiGraph.BuildFromService(*graph)
+	return gimlet.NewJSONResponse(apiGraph)
+}
@@ -40,6 +40,8 @@ func AttachHandler(app *gimlet.APIApp, opts HandlerOpts) {
 	requireProjectAdmin := NewProjectAdminMiddleware()
 	requireRepoAdmin := NewRepoAdminMiddleware()
 	requireCommitQueueItemOwner := NewCommitQueueItemOwnerMiddleware()
+	rejectServiceAccounts := NewRejectServiceAccountMiddleware()
+	statusAPI := NewStatusAPIMiddleware()
 	adminSettings := RequiresSuperUserPermission(evergreen.PermissionAdminSettings, evergreen.AdminSettingsEdit)
 	createProject := RequiresSuperUserPermission(evergreen.PermissionProjectCreate, evergreen.ProjectCreate)
 	createDistro := RequiresSuperUserPermission(evergreen.PermissionDistroCreate, evergreen.DistroCreate)
@@ -48,50 +50,67 @@ func AttachHandler(app *gimlet.APIApp, opts HandlerOpts) {
 	editTasks := RequiresProjectPermission(evergreen.PermissionTasks, evergreen.TasksBasic)
 	editAnnotations := RequiresProjectPermission(evergreen.PermissionAnnotations, evergreen.AnnotationsModify)
 	viewAnnotations := RequiresProjectPermission(evergreen.PermissionAnnotations, evergreen.AnnotationsView)
+	viewLogs := RequiresProjectPermission(evergreen.PermissionLogs, evergreen.LogsView)
 	submitPatches := RequiresProjectPermission(evergreen.PermissionPatches, evergreen.PatchSubmit)
 	viewProjectSettings := RequiresProjectPermission(evergreen.PermissionProjectSettings, evergreen.ProjectSettingsView)
 	editProjectSettings := RequiresProjectPermission(evergreen.PermissionProjectSettings, evergreen.ProjectSettingsEdit)
 	editDistroSettings := RequiresDistroPermission(evergreen.PermissionDistroSettings, evergreen.DistroSettingsEdit)
 	removeDistroSettings := RequiresDistroPermission(evergreen.PermissionDistroSettings, evergreen.DistroSettingsAdmin)
+	viewHosts := RequiresDistroPermission(evergreen.PermissionHosts, evergreen.HostsView)
 	editHosts := RequiresDistroPermission(evergreen.PermissionHosts, evergreen.HostsEdit)
 	cedarTestStats := checkCedarTestStats(settings)
 
 	app.AddWrapper(gimlet.WrapperMiddleware(allowCORS))
+	app.AddWrapper(NewServiceAccountAuditMiddleware())
 
 	// Routes
 	app.AddRoute("/").Version(2).Get().RouteHandler(makePlaceHolder())
-	app.AddRoute("/admin/banner").Version(2).Get().Wrap(requireUser).RouteHandler(makeFetchAdminBanner())
+	app.AddRoute("/admin/banner").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeFetchAdminBanner())
 	app.AddRoute("/admin/banner").Version(2).Post().Wrap(adminSettings).RouteHandler(makeSetAdminBanner())
-	app.AddRoute("/admin/uiv2_url").Version(2).Get().Wrap(requireUser).RouteHandler(makeFetchAdminUIV2Url())
+	app.AddRoute("/admin/uiv2_url").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeFetchAdminUIV2Url())
 	app.AddRoute("/admin/events").Version(2).Get().Wrap(adminSettings).RouteHandler(makeFetchAdminEvents(opts.URL))
 	app.AddRoute("/admin/spawn_hosts").Version(2).Get().Wrap(adminSettings).RouteHandler(makeFetchSpawnHostUsage())
+	app.AddRoute("/admin/load_shedder").Version(2).Get().Wrap(adminSettings).RouteHandler(makeGetLoadShedderStatus(env))
+	app.AddRoute("/admin/maintenance_mode").Version(2).Get().Wrap(adminSettings).RouteHandler(makeGetMaintenanceMode(env))
+	app.AddRoute("/admin/maintenance_mode").Version(2).Post().Wrap(adminSettings).RouteHandler(makeSetMaintenanceMode(env))
+	app.AddRoute("/admin/stuck_tasks").Version(2).Get().Wrap(adminSettings).RouteHandler(makeGetAllStuckTasks())
+	app.AddRoute("/admin/secret_findings").Version(2).Get().Wrap(adminSettings).RouteHandler(makeGetSecretFindings())
+	app.AddRoute("/admin/github_budget").Version(2).Get().Wrap(adminSettings).RouteHandler(makeGetGithubBudget())
 	app.AddRoute("/admin/restart/versions").Version(2).Post().Wrap(adminSettings).RouteHandler(makeRestartRoute(evergreen.RestartVersions, nil))
 	app.AddRoute("/admin/restart/tasks").Version(2).Post().Wrap(adminSettings).RouteHandler(makeRestartRoute(evergreen.RestartTasks, opts.APIQueue))
 	app.AddRoute("/admin/revert").Version(2).Post().Wrap(adminSettings).RouteHandler(makeRevertRouteManager())
 	app.AddRoute("/admin/service_flags").Version(2).Post().Wrap(adminSettings).RouteHandler(makeSetServiceFlagsRouteManager())
+	app.AddRoute("/admin/status_api_keys").Version(2).Get().Wrap(adminSettings).RouteHandler(makeGetStatusAPIKeys())
+	app.AddRoute("/admin/status_api_keys").Version(2).Post().Wrap(adminSettings).RouteHandler(makeCreateStatusAPIKey())
+	app.AddRoute("/admin/status_api_keys/{key_id}").Version(2).Delete().Wrap(adminSettings).RouteHandler(makeDeleteStatusAPIKey())
 	app.AddRoute("/admin/settings").Version(2).Get().Wrap(adminSettings).RouteHandler(makeFetchAdminSettings())
 	app.AddRoute("/admin/settings").Version(2).Post().Wrap(adminSettings).RouteHandler(makeSetAdminSettings())
 	app.AddRoute("/admin/task_queue").Version(2).Delete().Wrap(adminSettings).RouteHandler(makeClearTaskQueueHandler())
 	app.AddRoute("/admin/commit_queues").Version(2).Delete().Wrap(adminSettings).RouteHandler(makeClearCommitQueuesHandler())
+	app.AddRoute("/admin/commit_queues/{project_id}/dequeue").Version(2).Post().Wrap(adminSettings).RouteHandler(makeAdminCommitQueueDequeue())
+	app.AddRoute("/admin/commit_queues/{project_id}/repair").Version(2).Post().Wrap(adminSettings).RouteHandler(makeAdminCommitQueueRepair())
 	app.AddRoute("/admin/service_users").Version(2).Get().Wrap(adminSettings).RouteHandler(makeGetServiceUsers())
 	app.AddRoute("/admin/service_users").Version(2).Post().Wrap(adminSettings).RouteHandler(makeUpdateServiceUser())
 	app.AddRoute("/admin/service_users").Version(2).Delete().Wrap(adminSettings).RouteHandler(makeDeleteServiceUser())
 	app.AddRoute("/agent/cedar_config").Version(2).Get().Wrap(requirePodOrHost).RouteHandler(makeAgentCedarConfig(env.Settings()))
 	app.AddRoute("/alias/{name}").Version(2).Get().RouteHandler(makeFetchAliases())
-	app.AddRoute("/auth").Version(2).Get().Wrap(requireUser).RouteHandler(&authPermissionGetHandler{})
+	app.AddRoute("/annotation_attachments/{attachment_id}").Version(2).Get().RouteHandler(makeGetAnnotationAttachment())
+	app.AddRoute("/auth").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(&authPermissionGetHandler{})
 	app.AddRoute("/builds/{build_id}").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetBuildByID())
 	app.AddRoute("/builds/{build_id}").Version(2).Patch().Wrap(requireUser, editTasks).RouteHandler(makeChangeStatusForBuild())
 	app.AddRoute("/builds/{build_id}/abort").Version(2).Post().Wrap(requireUser, editTasks).RouteHandler(makeAbortBuild())
 	app.AddRoute("/builds/{build_id}/restart").Version(2).Post().Wrap(requireUser, editTasks).RouteHandler(makeRestartBuild())
 	app.AddRoute("/builds/{build_id}/tasks").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeFetchTasksByBuild(opts.URL))
+	app.AddRoute("/builds/{build_id}/critical_path").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetBuildCriticalPath())
 	app.AddRoute("/builds/{build_id}/annotations").Version(2).Get().Wrap(requireUser, viewAnnotations).RouteHandler(makeFetchAnnotationsByBuild())
+	app.AddRoute("/commands").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetCommandSchemas())
 	app.AddRoute("/commit_queue/{project_id}").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeGetCommitQueueItems())
 	app.AddRoute("/commit_queue/{patch_id}").Version(2).Delete().Wrap(requireUser, addProject, requireCommitQueueItemOwner, editTasks).RouteHandler(makeDeleteCommitQueueItems(env))
 	app.AddRoute("/commit_queue/{patch_id}").Version(2).Put().Wrap(requireUser, addProject, requireCommitQueueItemOwner, editTasks).RouteHandler(makeCommitQueueEnqueueItem())
 	app.AddRoute("/commit_queue/{patch_id}/additional").Version(2).Get().Wrap(requireTask).RouteHandler(makeCommitQueueAdditionalPatches())
 	app.AddRoute("/commit_queue/{patch_id}/conclude_merge").Version(2).Post().Wrap(requireTask).RouteHandler(makeCommitQueueConcludeMerge())
-	app.AddRoute("/commit_queue/{patch_id}/message").Version(2).Get().Wrap(requireUser).RouteHandler(makecqMessageForPatch())
-	app.AddRoute("/distros").Version(2).Get().Wrap(requireUser).RouteHandler(makeDistroRoute())
+	app.AddRoute("/commit_queue/{patch_id}/message").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makecqMessageForPatch())
+	app.AddRoute("/distros").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeDistroRoute())
 	app.AddRoute("/distros/settings").Version(2).Patch().Wrap(createDistro).RouteHandler(makeModifyDistrosSettings())
 	app.AddRoute("/distros/{distro_id}").Version(2).Get().Wrap(editDistroSettings).RouteHandler(makeGetDistroByID())
 	app.AddRoute("/distros/{distro_id}").Version(2).Patch().Wrap(editDistroSettings).RouteHandler(makePatchDistroByID())
@@ -99,127 +118,204 @@ func AttachHandler(app *gimlet.APIApp, opts HandlerOpts) {
 	app.AddRoute("/distros/{distro_id}").Version(2).Put().Wrap(createDistro).RouteHandler(makePutDistro())
 	app.AddRoute("/distros/{distro_id}/ami").Version(2).Get().Wrap(requireTask).RouteHandler(makeGetDistroAMI())
 	app.AddRoute("/distros/{distro_id}/client_urls").Version(2).Get().RouteHandler(makeGetDistroClientURLs(env))
+	app.AddRoute("/distros/{distro_id}/drain").Version(2).Get().Wrap(editDistroSettings).RouteHandler(makeGetDistroDrain())
+	app.AddRoute("/distros/{distro_id}/drain").Version(2).Post().Wrap(editDistroSettings).RouteHandler(makePostDistroDrain())
 	app.AddRoute("/distros/{distro_id}/execute").Version(2).Patch().Wrap(editHosts).RouteHandler(makeDistroExecute(env))
+	app.AddRoute("/distros/{distro_id}/health").Version(2).Get().Wrap(viewHosts).RouteHandler(makeGetDistroHealth())
 	app.AddRoute("/distros/{distro_id}/icecream_config").Version(2).Patch().Wrap(editHosts).RouteHandler(makeDistroIcecreamConfig(env))
 	app.AddRoute("/distros/{distro_id}/setup").Version(2).Get().Wrap(editDistroSettings).RouteHandler(makeGetDistroSetup())
 	app.AddRoute("/distros/{distro_id}/setup").Version(2).Patch().Wrap(editDistroSettings).RouteHandler(makeChangeDistroSetup())
+	app.AddRoute("/distros/{distro_id}/task_latency").Version(2).Get().Wrap(viewHosts).RouteHandler(makeGetDistroTaskLatencyStats())
 
 	app.AddRoute("/hooks/github").Version(2).Post().RouteHandler(makeGithubHooksRoute(sc, opts.APIQueue, opts.GithubSecret, settings))
 	app.AddRoute("/hooks/aws").Version(2).Post().RouteHandler(makeEC2SNS(env, opts.APIQueue))
 	app.AddRoute("/hooks/aws/ecs").Version(2).Post().RouteHandler(makeECSSNS(env, opts.APIQueue))
-	app.AddRoute("/host/filter").Version(2).Get().Wrap(requireUser).RouteHandler(makeFetchHostFilter())
-	app.AddRoute("/host/start_processes").Version(2).Post().Wrap(requireUser).RouteHandler(makeHostStartProcesses(env))
-	app.AddRoute("/host/get_processes").Version(2).Get().Wrap(requireUser).RouteHandler(makeHostGetProcesses(env))
+	app.AddRoute("/host/filter").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeFetchHostFilter())
+	app.AddRoute("/host/start_processes").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeHostStartProcesses(env))
+	app.AddRoute("/host/get_processes").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeHostGetProcesses(env))
 	app.AddRoute("/hosts").Version(2).Get().RouteHandler(makeFetchHosts(opts.URL))
-	app.AddRoute("/hosts").Version(2).Post().Wrap(requireUser).RouteHandler(makeSpawnHostCreateRoute(env.Settings()))
-	app.AddRoute("/hosts").Version(2).Patch().Wrap(requireUser).RouteHandler(makeChangeHostsStatuses())
-	app.AddRoute("/hosts/{host_id}").Version(2).Get().Wrap(requireUser).RouteHandler(makeGetHostByID())
-	app.AddRoute("/hosts/{host_id}").Version(2).Patch().Wrap(requireUser).RouteHandler(makeHostModifyRouteManager(env))
+	app.AddRoute("/hosts").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeSpawnHostCreateRoute(env.Settings()))
+	app.AddRoute("/hosts").Version(2).Patch().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeChangeHostsStatuses())
+	app.AddRoute("/hosts/{host_id}").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetHostByID())
+	app.AddRoute("/hosts/{host_id}").Version(2).Patch().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeHostModifyRouteManager(env))
 	app.AddRoute("/hosts/{host_id}/disable").Version(2).Post().Wrap(requireHost).RouteHandler(makeDisableHostHandler(env))
-	app.AddRoute("/hosts/{host_id}/stop").Version(2).Post().Wrap(requireUser).RouteHandler(makeHostStopManager(env))
-	app.AddRoute("/hosts/{host_id}/start").Version(2).Post().Wrap(requireUser).RouteHandler(makeHostStartManager(env))
-	app.AddRoute("/hosts/{host_id}/change_password").Version(2).Post().Wrap(requireUser).RouteHandler(makeHostChangePassword(env))
-	app.AddRoute("/hosts/{host_id}/extend_expiration").Version(2).Post().Wrap(requireUser).RouteHandler(makeExtendHostExpiration())
-	app.AddRoute("/hosts/{host_id}/terminate").Version(2).Post().Wrap(requireUser).RouteHandler(makeTerminateHostRoute())
+	app.AddRoute("/hosts/{host_id}/stop").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeHostStopManager(env))
+	app.AddRoute("/hosts/{host_id}/start").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeHostStartManager(env))
+	app.AddRoute("/hosts/{host_id}/change_password").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeHostChangePassword(env))
+	app.AddRoute("/hosts/{host_id}/extend_expiration").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeExtendHostExpiration())
+	app.AddRoute("/hosts/{host_id}/terminate").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeTerminateHostRoute())
 	app.AddRoute("/hosts/{host_id}/status").Version(2).Get().Wrap(requireTaskHost).RouteHandler(makeContainerStatusManager())
 	app.AddRoute("/hosts/{host_id}/logs/output").Version(2).Get().Wrap(requireTaskHost).RouteHandler(makeContainerLogsRouteManager(false))
 	app.AddRoute("/hosts/{host_id}/logs/error").Version(2).Get().Wrap(requireTaskHost).RouteHandler(makeContainerLogsRouteManager(true))
 	app.AddRoute("/hosts/{task_id}/create").Version(2).Post().Wrap(requireTask).RouteHandler(makeHostCreateRouteManager())
 	app.AddRoute("/hosts/{task_id}/list").Version(2).Get().Wrap(requireTask).RouteHandler(makeHostListRouteManager())
-	app.AddRoute("/hosts/{host_id}/attach").Version(2).Post().Wrap(requireUser).RouteHandler(makeAttachVolume(env))
-	app.AddRoute("/hosts/{host_id}/detach").Version(2).Post().Wrap(requireUser).RouteHandler(makeDetachVolume(env))
+	app.AddRoute("/hosts/{host_id}/attach").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeAttachVolume(env))
+	app.AddRoute("/hosts/{host_id}/detach").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeDetachVolume(env))
 	app.AddRoute("/hosts/{host_id}/provisioning_options").Version(2).Get().Wrap(requireHost).RouteHandler(makeHostProvisioningOptionsGetHandler(env))
-	app.AddRoute("/hosts/ip_address/{ip_address}").Version(2).Get().Wrap(requireUser).RouteHandler(makeGetHostByIpAddress())
-	app.AddRoute("/volumes").Version(2).Get().Wrap(requireUser).RouteHandler(makeGetVolumes())
-	app.AddRoute("/volumes").Version(2).Post().Wrap(requireUser).RouteHandler(makeCreateVolume(env))
-	app.AddRoute("/volumes/{volume_id}").Version(2).Wrap(requireUser).Delete().RouteHandler(makeDeleteVolume(env))
-	app.AddRoute("/volumes/{volume_id}").Version(2).Wrap(requireUser).Patch().RouteHandler(makeModifyVolume(env))
-	app.AddRoute("/volumes/{volume_id}").Version(2).Get().Wrap(requireUser).RouteHandler(makeGetVolumeByID())
-	app.AddRoute("/keys").Version(2).Get().Wrap(requireUser).RouteHandler(makeFetchKeys())
-	app.AddRoute("/keys").Version(2).Post().Wrap(requireUser).RouteHandler(makeSetKey())
-	app.AddRoute("/keys/{key_name}").Version(2).Delete().Wrap(requireUser).RouteHandler(makeDeleteKeys())
-	app.AddRoute("/notifications/{type}").Version(2).Post().Wrap(requireUser).RouteHandler(makeNotification(env))
+	app.AddRoute("/hosts/ip_address/{ip_address}").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetHostByIpAddress())
+	app.AddRoute("/volumes").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetVolumes())
+	app.AddRoute("/volumes").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeCreateVolume(env))
+	app.AddRoute("/volumes/{volume_id}").Version(2).Wrap(requireUser, rejectServiceAccounts).Delete().RouteHandler(makeDeleteVolume(env))
+	app.AddRoute("/volumes/{volume_id}").Version(2).Wrap(requireUser, rejectServiceAccounts).Patch().RouteHandler(makeModifyVolume(env))
+	app.AddRoute("/volumes/{volume_id}").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetVolumeByID())
+	app.AddRoute("/keys").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeFetchKeys())
+	app.AddRoute("/keys").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeSetKey())
+	app.AddRoute("/keys/{key_name}").Version(2).Delete().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeDeleteKeys())
+	app.AddRoute("/notifications/{type}").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeNotification(env))
 	app.AddRoute("/patches/{patch_id}").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeFetchPatchByID())
 	app.AddRoute("/patches/{patch_id}").Version(2).Patch().Wrap(requireUser, submitPatches).RouteHandler(makeChangePatchStatus(env))
 	app.AddRoute("/patches/{patch_id}/abort").Version(2).Post().Wrap(requireUser, submitPatches).RouteHandler(makeAbortPatch())
+	app.AddRoute("/patches/{patch_id}/extend").Version(2).Post().Wrap(requireUser, submitPatches).RouteHandler(makeExtendPatchHandler())
+	app.AddRoute("/patches/{patch_id}/keep_alive").Version(2).Post().Wrap(requireUser, submitPatches).RouteHandler(makeKeepPatchAlive())
 	app.AddRoute("/patches/{patch_id}/configure").Version(2).Post().Wrap(requireUser, submitPatches).RouteHandler(makeSchedulePatchHandler())
 	app.AddRoute("/patches/{patch_id}/raw").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makePatchRawHandler())
 	app.AddRoute("/patches/{patch_id}/restart").Version(2).Post().Wrap(requireUser, submitPatches).RouteHandler(makeRestartPatch())
 	app.AddRoute("/patches/{patch_id}/merge_patch").Version(2).Put().Wrap(requireUser, addProject, submitPatches, requireCommitQueueItemOwner).RouteHandler(makeMergePatch())
+	app.AddRoute("/patches/{patch_id}/module_impact").Version(2).Post().Wrap(requireUser, submitPatches).RouteHandler(makePatchModuleImpact(env))
 	app.AddRoute("/pods/{pod_id}/agent/setup").Version(2).Get().Wrap(requirePod).RouteHandler(makePodAgentSetup(env.Settings()))
 	app.AddRoute("/pods/{pod_id}/agent/next_task").Version(2).Get().Wrap(requirePod).RouteHandler(makePodAgentNextTask(env))
 	app.AddRoute("/pods").Version(2).Post().Wrap(adminSettings).RouteHandler(makePostPod(env))
 	app.AddRoute("/pods/{pod_id}").Version(2).Get().Wrap(adminSettings).RouteHandler(makeGetPod(env))
 	app.AddRoute("/pods/{pod_id}/provisioning_script").Version(2).Get().Wrap(requirePod).RouteHandler(makePodProvisioningScript(env.Settings()))
-	app.AddRoute("/projects").Version(2).Get().Wrap(requireUser).RouteHandler(makeFetchProjectsRoute(opts.URL))
-	app.AddRoute("/projects/test_alias").Version(2).Get().Wrap(requireUser).RouteHandler(makeGetProjectAliasResultsHandler())
+	app.AddRoute("/projects").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeFetchProjectsRoute(opts.URL))
+	app.AddRoute("/projects/test_alias").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetProjectAliasResultsHandler())
+	app.AddRoute("/projects/trigger_graph").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetProjectTriggerGraph(env.Settings()))
 	app.AddRoute("/projects/{project_id}").Version(2).Delete().Wrap(requireUser, requireProjectAdmin, editProjectSettings).RouteHandler(makeDeleteProject())
 	app.AddRoute("/projects/{project_id}").Version(2).Get().Wrap(requireUser, addProject, viewProjectSettings).RouteHandler(makeGetProjectByID())
 	app.AddRoute("/projects/{project_id}").Version(2).Patch().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makePatchProjectByID(env.Settings()))
+	app.AddRoute("/projects/{project_id}/dependents").Version(2).Get().Wrap(requireUser, addProject, viewProjectSettings).RouteHandler(makeGetProjectDependents())
+	app.AddRoute("/projects/{project_id}/archive").Version(2).Post().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeArchiveProject())
+	app.AddRoute("/projects/{project_id}/resurrect").Version(2).Post().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeResurrectProject(env.Settings()))
+	app.AddRoute("/projects/{project_id}/service_accounts").Version(2).Get().Wrap(requireUser, addProject, requireProjectAdmin, viewProjectSettings).RouteHandler(makeGetServiceAccounts())
+	app.AddRoute("/projects/{project_id}/service_accounts").Version(2).Post().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeCreateServiceAccount())
+	app.AddRoute("/projects/{project_id}/service_accounts/events").Version(2).Get().Wrap(requireUser, addProject, requireProjectAdmin, viewProjectSettings).RouteHandler(makeGetServiceAccountEvents())
+	app.AddRoute("/projects/{project_id}/service_accounts/{account_id}").Version(2).Delete().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeRevokeServiceAccount())
+	app.AddRoute("/projects/{project_id}/service_accounts/{account_id}/rotate").Version(2).Post().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeRotateServiceAccountKey())
 	app.AddRoute("/projects/{project_id}/attach_to_repo").Version(2).Post().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeAttachProjectToRepoHandler())
 	app.AddRoute("/projects/{project_id}/detach_from_repo").Version(2).Post().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeDetachProjectFromRepoHandler())
-	app.AddRoute("/projects/{project_id}/repotracker").Version(2).Post().Wrap(requireUser, addProject).RouteHandler(makeRunRepotrackerForProject())
+	app.AddRoute("/projects/{project_id}/repotracker").Version(2).Post().Wrap(requireUser, addProject, rejectServiceAccounts).RouteHandler(makeRunRepotrackerForProject())
 	app.AddRoute("/projects/{project_id}").Version(2).Put().Wrap(createProject).RouteHandler(makePutProjectByID())
+	app.AddRoute("/projects/{project_id}/change_requests").Version(2).Get().Wrap(requireUser, addProject, requireProjectAdmin, viewProjectSettings).RouteHandler(makeFetchProjectChangeRequests())
+	app.AddRoute("/projects/{project_id}/change_requests/{change_request_id}/approve").Version(2).Post().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeReviewProjectChangeRequest(env.Settings(), true))
+	app.AddRoute("/projects/{project_id}/change_requests/{change_request_id}/reject").Version(2).Post().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeReviewProjectChangeRequest(env.Settings(), false))
 	app.AddRoute("/projects/{project_id}/copy").Version(2).Post().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeCopyProject())
 	app.AddRoute("/projects/{project_id}/copy/variables").Version(2).Post().Wrap(requireUser, addProject, requireProjectAdmin, editProjectSettings).RouteHandler(makeCopyVariables())
 	app.AddRoute("/projects/{project_id}/events").Version(2).Get().Wrap(requireUser, addProject, requireProjectAdmin, viewProjectSettings).RouteHandler(makeFetchProjectEvents(opts.URL))
+	app.AddRoute("/projects/{project_id}/quarantined_tasks").Version(2).Get().Wrap(requireUser, addProject, viewProjectSettings).RouteHandler(makeGetTaskQuarantines())
+	app.AddRoute("/projects/{project_id}/quarantined_tasks").Version(2).Put().Wrap(requireUser, addProject, editProjectSettings).RouteHandler(makePutTaskQuarantine())
+	app.AddRoute("/projects/{project_id}/quarantined_tasks/{variant}/{task_name}").Version(2).Delete().Wrap(requireUser, addProject, editProjectSettings).RouteHandler(makeDeleteTaskQuarantine())
+	app.AddRoute("/projects/{project_id}/bisections").Version(2).Get().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeGetProjectBisections())
+	app.AddRoute("/projects/{project_id}/bisections/{bisection_id}").Version(2).Get().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeGetProjectBisection())
+	app.AddRoute("/projects/{project_id}/failure_search").Version(2).Get().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeSearchTaskFailures())
+	app.AddRoute("/projects/{project_id}/local_plan").Version(2).Post().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeCompileLocalExecutionPlan())
+	app.AddRoute("/projects/{project_id}/selection_preview").Version(2).Post().Wrap(requireUser, addProject, viewProjectSettings).RouteHandler(makePreviewProjectSelection())
+	app.AddRoute("/projects/{project_id}/allowed_requesters_suggestion").Version(2).Get().Wrap(requireUser, addProject, viewProjectSettings).RouteHandler(makeGetProjectAllowedRequestersSuggestion())
+	app.AddRoute("/projects/{project_id}/task_groups/{task_group}/max_hosts_recommendation").Version(2).Get().Wrap(requireUser, addProject, viewProjectSettings).RouteHandler(makeGetTaskGroupMaxHostsRecommendation())
+	app.AddRoute("/projects/{project_id}/starved_tasks").Version(2).Get().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeGetProjectStarvedTasks())
+	app.AddRoute("/projects/{project_id}/task_latency").Version(2).Get().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeGetProjectTaskLatencyStats())
+	app.AddRoute("/projects/{project_id}/distro_failure_rates").Version(2).Get().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeGetDistroFailureRates())
+	app.AddRoute("/projects/{project_id}/stuck_tasks").Version(2).Get().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeGetProjectStuckTasks())
+	app.AddRoute("/projects/{project_id}/test_flakiness").Version(2).Get().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeGetProjectTestFlakiness())
+	app.AddRoute("/projects/{project_id}/test_history").Version(2).Get().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeGetProjectTestHistory())
+	app.AddRoute("/projects/{project_id}/config_replay").Version(2).Get().Wrap(requireUser, addProject, viewProjectSettings).RouteHandler(makeGetProjectConfigReplay())
+	app.AddRoute("/projects/{project_id}/project_config").Version(2).Patch().Wrap(requireUser, addProject, editProjectSettings).RouteHandler(makePatchProjectConfig())
+	app.AddRoute("/projects/{project_id}/log_retention").Version(2).Get().Wrap(requireUser, addProject, viewProjectSettings).RouteHandler(makeGetProjectLogRetention(env))
+	app.AddRoute("/projects/{project_id}/task_order").Version(2).Get().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeGetTaskOrder())
+	app.AddRoute("/projects/{project_id}/task_order").Version(2).Put().Wrap(requireUser, addProject, viewTasks).RouteHandler(makePutTaskOrder())
+	app.AddRoute("/projects/{project_id}/task_order").Version(2).Delete().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeDeleteTaskOrder())
 	app.AddRoute("/projects/{project_id}/patches").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makePatchesByProjectRoute(opts.URL))
 	app.AddRoute("/projects/{project_id}/recent_versions").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeFetchProjectVersionsLegacy())
 	app.AddRoute("/projects/{project_id}/revisions/{commit_hash}/tasks").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeTasksByProjectAndCommitHandler(opts.URL))
-	app.AddRoute("/projects/{project_id}/task_reliability").Version(2).Get().Wrap(requireUser).RouteHandler(makeGetProjectTaskReliability(opts.URL))
+	app.AddRoute("/projects/{project_id}/task_reliability").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetProjectTaskReliability(opts.URL))
 	app.AddRoute("/projects/{project_id}/task_stats").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeGetProjectTaskStats(opts.URL))
 	app.AddRoute("/projects/{project_id}/test_stats").Version(2).Get().Wrap(requireUser, viewTasks, cedarTestStats).RouteHandler(makeGetProjectTestStats(opts.URL))
 	app.AddRoute("/projects/{project_id}/versions").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeGetProjectVersionsHandler(opts.URL))
+	app.AddRoute("/projects/{project_id}/builds").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeGetProjectBuildsByLabel())
 	app.AddRoute("/projects/{project_id}/tasks/{task_name}").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeGetProjectTasksHandler(opts.URL))
+	app.AddRoute("/projects/{project_id}/test_alias").Version(2).Post().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeTestProjectAlias())
+	app.AddRoute("/projects/{project_id}/selectors").Version(2).Post().Wrap(requireUser, addProject, viewTasks).RouteHandler(makeEvaluateProjectSelector())
 	app.AddRoute("/projects/{project_id}/patch_trigger_aliases").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeFetchPatchTriggerAliases())
 	app.AddRoute("/projects/{project_id}/parameters").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeFetchParameters())
 	app.AddRoute("/projects/variables/rotate").Version(2).Put().Wrap(requireUser, createProject).RouteHandler(makeProjectVarsPut())
+	app.AddRoute("/project_templates").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetProjectTemplates())
+	app.AddRoute("/project_templates/{template_name}").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetProjectTemplate())
+	app.AddRoute("/project_templates/{template_name}").Version(2).Put().Wrap(requireUser, adminSettings).RouteHandler(makePutProjectTemplate())
+	app.AddRoute("/project_templates/{template_name}").Version(2).Delete().Wrap(requireUser, adminSettings).RouteHandler(makeDeleteProjectTemplate())
+	app.AddRoute("/project_templates/{template_name}/projects").Version(2).Post().Wrap(requireUser, createProject).RouteHandler(makeCreateProjectFromTemplate(env.Settings()))
 	app.AddRoute("/permissions").Version(2).Get().RouteHandler(&permissionsGetHandler{})
 	app.AddRoute("/repos/{repo_id}").Version(2).Get().Wrap(requireUser, viewProjectSettings).RouteHandler(makeGetRepoByID())
 	app.AddRoute("/repos/{repo_id}").Version(2).Patch().Wrap(requireUser, requireRepoAdmin, editProjectSettings).RouteHandler(makePatchRepoByID(env.Settings()))
-	app.AddRoute("/roles").Version(2).Get().Wrap(requireUser).RouteHandler(acl.NewGetAllRolesHandler(env.RoleManager()))
-	app.AddRoute("/roles").Version(2).Post().Wrap(requireUser).RouteHandler(acl.NewUpdateRoleHandler(env.RoleManager()))
-	app.AddRoute("/roles/{role_id}/users").Version(2).Get().Wrap(requireUser).RouteHandler(makeGetUsersWithRole())
-	app.AddRoute("/scheduler/compare_tasks").Version(2).Post().Wrap(requireUser).RouteHandler(makeCompareTasksRoute())
+	app.AddRoute("/roles").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(acl.NewGetAllRolesHandler(env.RoleManager()))
+	app.AddRoute("/roles").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(acl.NewUpdateRoleHandler(env.RoleManager()))
+	app.AddRoute("/roles/{role_id}/users").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetUsersWithRole())
+	app.AddRoute("/scheduler/compare_tasks").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeCompareTasksRoute())
 	app.AddRoute("/status/cli_version").Version(2).Get().RouteHandler(makeFetchCLIVersionRoute())
-	app.AddRoute("/status/hosts/distros").Version(2).Get().Wrap(requireUser).RouteHandler(makeHostStatusByDistroRoute())
-	app.AddRoute("/status/notifications").Version(2).Get().Wrap(requireUser).RouteHandler(makeFetchNotifcationStatusRoute())
+	app.AddRoute("/status/hosts/distros").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeHostStatusByDistroRoute())
+	app.AddRoute("/status/builds/{build_id}").Version(2).Get().Wrap(statusAPI).RouteHandler(makeGetStatusBuild())
+	app.AddRoute("/status/notifications").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeFetchNotifcationStatusRoute())
+	app.AddRoute("/status/projects/{project_id}/badge.svg").Version(2).Get().Wrap(statusAPI).Handler(statusBadgeHandler)
+	app.AddRoute("/status/projects/{project_id}/versions").Version(2).Get().Wrap(statusAPI).RouteHandler(makeGetStatusProjectVersions())
 	app.AddRoute("/status/recent_tasks").Version(2).Get().RouteHandler(makeRecentTaskStatusHandler())
-	app.AddRoute("/subscriptions").Version(2).Delete().Wrap(requireUser).RouteHandler(makeDeleteSubscription())
-	app.AddRoute("/subscriptions").Version(2).Get().Wrap(requireUser).RouteHandler(makeFetchSubscription())
-	app.AddRoute("/subscriptions").Version(2).Post().Wrap(requireUser).RouteHandler(makeSetSubscription())
+	app.AddRoute("/status/tasks/{task_id}").Version(2).Get().Wrap(statusAPI).RouteHandler(makeGetStatusTask())
+	app.AddRoute("/status/versions/{version_id}").Version(2).Get().Wrap(statusAPI).RouteHandler(makeGetStatusVersion())
+	app.AddRoute("/subscriptions").Version(2).Delete().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeDeleteSubscription())
+	app.AddRoute("/subscriptions").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeFetchSubscription())
+	app.AddRoute("/subscriptions").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeSetSubscription())
 	app.AddRoute("/tasks/{task_id}").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeGetTaskRoute(opts.URL))
 	app.AddRoute("/tasks/{task_id}").Version(2).Patch().Wrap(requireUser, addProject, editTasks).RouteHandler(makeModifyTaskRoute())
 	app.AddRoute("/tasks/{task_id}/annotations").Version(2).Get().Wrap(requireUser, viewAnnotations).RouteHandler(makeFetchAnnotationsByTask())
 	app.AddRoute("/tasks/{task_id}/annotation").Version(2).Put().Wrap(requireUser, editAnnotations).RouteHandler(makePutAnnotationsByTask())
 	app.AddRoute("/tasks/annotations").Version(2).Patch().Wrap(requireUser, editAnnotations).RouteHandler(makeBulkPatchAnnotations())
 	app.AddRoute("/tasks/{task_id}/annotation").Version(2).Patch().Wrap(requireUser, editAnnotations).RouteHandler(makePatchAnnotationsByTask())
+	app.AddRoute("/tasks/{task_id}/annotation/attachments").Version(2).Post().Wrap(requireUser, editAnnotations).RouteHandler(makePostAnnotationAttachment())
+	app.AddRoute("/tasks/{task_id}/annotation/attachments/{attachment_id}").Version(2).Delete().Wrap(requireUser, editAnnotations).RouteHandler(makeDeleteAnnotationAttachment())
+	app.AddRoute("/tasks/{task_id}/bootstrap").Version(2).Get().Wrap(requireTask).RouteHandler(makeTaskBootstrapHandler(env.Settings()))
+	app.AddRoute("/tasks/{task_id}/annotation_attachments").Version(2).Post().Wrap(requireTask).RouteHandler(makeAgentPostAnnotationAttachment())
 	app.AddRoute("/tasks/{task_id}/created_ticket").Version(2).Put().Wrap(requireUser, editAnnotations).RouteHandler(makeCreatedTicketByTask())
+	app.AddRoute("/tasks/{task_id}/archived_executions").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeGetArchivedExecutions())
+	app.AddRoute("/tasks/{task_id}/archived_executions/{execution}").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeGetArchivedExecution())
 	app.AddRoute("/tasks/{task_id}/abort").Version(2).Post().Wrap(requireUser, editTasks).RouteHandler(makeTaskAbortHandler())
+	app.AddRoute("/tasks/{task_id}/abort_command").Version(2).Post().Wrap(requireUser, editTasks).RouteHandler(makeTaskAbortCommandHandler())
 	app.AddRoute("/tasks/{task_id}/display_task").Version(2).Get().Wrap(requireTask).RouteHandler(makeGetDisplayTaskHandler())
+	app.AddRoute("/tasks/{task_id}/finalizing").Version(2).Post().Wrap(requireTask).RouteHandler(makeTaskFinalizingHandler())
 	app.AddRoute("/tasks/{task_id}/generate").Version(2).Post().Wrap(requireTask).RouteHandler(makeGenerateTasksHandler(opts.QueueGroup))
 	app.AddRoute("/tasks/{task_id}/generate").Version(2).Get().Wrap(requireTask).RouteHandler(makeGenerateTasksPollHandler(opts.QueueGroup))
+	app.AddRoute("/tasks/{task_id}/latency").Version(2).Get().Wrap(requireUser, viewTasks).RouteHandler(makeGetTaskLatency())
+	app.AddRoute("/tasks/{task_id}/log_tail").Version(2).Get().Wrap(requireUser, addProject, viewLogs).Handler(taskLogTailHandler)
 	app.AddRoute("/tasks/{task_id}/manifest").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetManifestHandler())
 	app.AddRoute("/tasks/{task_id}/restart").Version(2).Post().Wrap(addProject, requireUser, editTasks).RouteHandler(makeTaskRestartHandler())
 	app.AddRoute("/tasks/{task_id}/tests").Version(2).Get().Wrap(addProject, viewTasks).RouteHandler(makeFetchTestsForTask(sc))
 	app.AddRoute("/tasks/{task_id}/tests/count").Version(2).Get().Wrap(addProject, viewTasks).RouteHandler(makeFetchTestCountForTask())
-	app.AddRoute("/tasks/{task_id}/sync_path").Version(2).Get().Wrap(requireUser).RouteHandler(makeTaskSyncPathGetHandler())
-	app.AddRoute("/tasks/{task_id}/set_has_cedar_results").Version(2).Post().Wrap(requireTask).RouteHandler(makeTaskSetHasCedarResultsHandler())
-	app.AddRoute("/task/sync_read_credentials").Version(2).Get().Wrap(requireUser).RouteHandler(makeTaskSyncReadCredentialsGetHandler())
-	app.AddRoute("/user/settings").Version(2).Get().Wrap(requireUser).RouteHandler(makeFetchUserConfig())
-	app.AddRoute("/user/settings").Version(2).Post().Wrap(requireUser).RouteHandler(makeSetUserConfig())
-	app.AddRoute("/users/{user_id}/hosts").Version(2).Get().Wrap(requireUser).RouteHandler(makeFetchHosts(opts.URL))
-	app.AddRoute("/users/{user_id}/patches").Version(2).Get().Wrap(requireUser).RouteHandler(makeUserPatchHandler(opts.URL))
+	app.AddRoute("/tasks/{task_id}/skip").Version(2).Post().Wrap(requireUser, editTasks).RouteHandler(makeTaskSkipHandler())
+	app.AddRoute("/tasks/{task_id}/sync_path").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeTaskSyncPathGetHandler())
+	app.AddRoute("/tasks/{task_id}/outputs").Version(2).Post().Wrap(requireTask).RouteHandler(makeTaskOutputsPostHandler())
+	app.AddRoute("/tasks/{task_id}/set_has_cedar_results").Version(2).Post().Wrap(requireTask).RouteHandler(makeTaskSetHasCedarResultsHandler(env))
+	app.AddRoute("/tasks/{task_id}/test_result_attachments").Version(2).Post().Wrap(requireTask).RouteHandler(makeTaskTestResultAttachmentsPostHandler())
+	app.AddRoute("/task/sync_read_credentials").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeTaskSyncReadCredentialsGetHandler())
+	app.AddRoute("/user/settings").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeFetchUserConfig())
+	app.AddRoute("/user/settings").Version(2).Post().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeSetUserConfig())
+	app.AddRoute("/users/{user_id}/hosts").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeFetchHosts(opts.URL))
+	app.AddRoute("/users/{user_id}/patches").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeUserPatchHandler(opts.URL))
 	app.AddRoute("/users/offboard_user").Version(2).Post().Wrap(requireUser, editRoles).RouteHandler(makeOffboardUser(env))
-	app.AddRoute("/users/{user_id}/permissions").Version(2).Get().Wrap(requireUser).RouteHandler(makeGetUserPermissions(evergreen.GetEnvironment().RoleManager()))
+	app.AddRoute("/users/{user_id}/permissions").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetUserPermissions(evergreen.GetEnvironment().RoleManager()))
 	app.AddRoute("/users/{user_id}/permissions").Version(2).Post().Wrap(requireUser, editRoles).RouteHandler(makeModifyUserPermissions(evergreen.GetEnvironment().RoleManager()))
 	app.AddRoute("/users/{user_id}/permissions").Version(2).Delete().Wrap(requireUser, editRoles).RouteHandler(makeDeleteUserPermissions(evergreen.GetEnvironment().RoleManager()))
 	app.AddRoute("/users/{user_id}/roles").Version(2).Post().Wrap(requireUser, editRoles).RouteHandler(makeModifyUserRoles(evergreen.GetEnvironment().RoleManager()))
-	app.AddRoute("/users/permissions").Version(2).Get().Wrap(requireUser).RouteHandler(makeGetAllUsersPermissions(evergreen.GetEnvironment().RoleManager()))
-	app.AddRoute("/versions").Version(2).Put().Wrap(requireUser).RouteHandler(makeVersionCreateHandler())
+	app.AddRoute("/users/permissions").Version(2).Get().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeGetAllUsersPermissions(evergreen.GetEnvironment().RoleManager()))
+	app.AddRoute("/versions").Version(2).Put().Wrap(requireUser, rejectServiceAccounts).RouteHandler(makeVersionCreateHandler())
 	app.AddRoute("/versions/{version_id}").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetVersionByID())
 	app.AddRoute("/versions/{version_id}/abort").Version(2).Post().Wrap(requireUser, editTasks).RouteHandler(makeAbortVersion())
+	app.AddRoute("/versions/{version_id}/baseline_comparison").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetVersionBaselineComparison())
 	app.AddRoute("/versions/{version_id}/builds").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetVersionBuilds())
+	app.AddRoute("/versions/{version_id}/changelog").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetVersionChangelog())
+	app.AddRoute("/versions/{version_id}/config_replay").Version(2).Get().Wrap(requireUser, addProject, viewProjectSettings).RouteHandler(makeGetVersionConfigReplay())
+	app.AddRoute("/versions/{version_id}/critical_path").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetVersionCriticalPath())
+	app.AddRoute("/versions/{version_id}/compliance").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetVersionCompliance())
+	app.AddRoute("/versions/{version_id}/gates").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetVersionExternalGates())
+	app.AddRoute("/versions/{version_id}/snapshot").Version(2).Get().Wrap(viewTasks).RouteHandler(makeGetVersionSnapshot())
+	app.AddRoute("/versions/{version_id}/gates/{gate_name}/open").Version(2).Post().Wrap(requireUser, editTasks).RouteHandler(makeOpenVersionExternalGate())
+	app.AddRoute("/versions/{version_id}/labels").Version(2).Patch().Wrap(requireUser, editTasks).RouteHandler(makeUpdateVersionLabels())
+	app.AddRoute("/versions/{version_id}/effective_project_config").Version(2).Get().Wrap(requireUser, addProject, viewProjectSettings).RouteHandler(makeGetVersionEffectiveProjectConfig())
 	app.AddRoute("/versions/{version_id}/restart").Version(2).Post().Wrap(requireUser, editTasks).RouteHandler(makeRestartVersion())
 	app.AddRoute("/versions/{version_id}/annotations").Version(2).Get().Wrap(requireUser, viewAnnotations).RouteHandler(makeFetchAnnotationsByVersion())
 
@@ -0,0 +1,476 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// statusAPIKeyHeader is the header that holds a status API key. Badges
+	// that are embedded as images can pass the key in the "key" query
+	// parameter instead.
+	statusAPIKeyHeader = "Status-Api-Key"
+
+	// statusAPICacheMaxAge is how long clients and proxies can cache status
+	// API responses.
+	statusAPICacheMaxAge = time.Minute
+
+	defaultStatusVersionsLimit = 10
+	maxStatusVersionsLimit     = 100
+)
+
+// statusAPIMiddleware allows read-only access to the statuses of a project's
+// versions, builds, and tasks to requests with a status API key that's scoped
+// to the project, to users who can view the project's tasks, and to anyone if
+// the project's statuses are public. Requests with a key are rate limited.
+type statusAPIMiddleware struct{}
+
+// NewStatusAPIMiddleware returns middleware that checks access to the status
+// API and attaches the project context to the request.
+func NewStatusAPIMiddleware() gimlet.Middleware {
+	return &statusAPIMiddleware{}
+}
+
+func (m *statusAPIMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	ctx := r.Context()
+	vars := gimlet.GetVars(r)
+	opCtx, err := dbModel.LoadContext(vars["task_id"], vars["build_id"], vars["version_id"], "", vars["project_id"])
+	if err != nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "loading resources from context")))
+		return
+	}
+	notFound := gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    "not found",
+	})
+	pRef := opCtx.ProjectRef
+	if pRef == nil {
+		gimlet.WriteResponse(rw, notFound)
+		return
+	}
+
+	cacheScope := "private"
+	key := r.Header.Get(statusAPIKeyHeader)
+	if key == "" {
+		key = r.URL.Query().Get("key")
+	}
+	if key != "" {
+		apiKey, err := dbModel.FindStatusAPIKeyByKey(key)
+		if err != nil {
+			gimlet.WriteResponse(rw, gimlet.MakeJSONInternalErrorResponder(err))
+			return
+		}
+		if apiKey == nil {
+			gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusUnauthorized,
+				Message:    "invalid status API key",
+			}))
+			return
+		}
+		if !apiKey.CanAccessProject(pRef.Id) {
+			gimlet.WriteResponse(rw, notFound)
+			return
+		}
+		allowed, err := apiKey.ConsumeRequest(time.Now())
+		if err != nil {
+			gimlet.WriteResponse(rw, gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "checking rate limit for status API key '%s'", apiKey.Id)))
+			return
+		}
+		if !allowed {
+			gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusTooManyRequests,
+				Message:    fmt.Sprintf("status API key '%s' exceeded its limit of %d requests per minute", apiKey.Name, apiKey.GetRequestsPerMinute()),
+			}))
+			return
+		}
+	} else if u := gimlet.GetUser(ctx); u != nil && u.HasPermission(gimlet.PermissionOpts{
+		Resource:      pRef.Id,
+		ResourceType:  evergreen.ProjectResourceType,
+		Permission:    evergreen.PermissionTasks,
+		RequiredLevel: evergreen.TasksView.Value,
+	}) {
+		// Users who can view the project's tasks can read their statuses.
+	} else if pRef.IsPublicStatusEnabled() {
+		cacheScope = "public"
+	} else {
+		gimlet.WriteResponse(rw, notFound)
+		return
+	}
+
+	rw.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", cacheScope, int(statusAPICacheMaxAge.Seconds())))
+	r = r.WithContext(context.WithValue(ctx, RequestContext, &opCtx))
+	next(rw, r)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/status/projects/{project_id}/versions
+
+type statusProjectVersionsHandler struct {
+	limit int
+}
+
+func makeGetStatusProjectVersions() gimlet.RouteHandler {
+	return &statusProjectVersionsHandler{}
+}
+
+func (h *statusProjectVersionsHandler) Factory() gimlet.RouteHandler {
+	return &statusProjectVersionsHandler{}
+}
+
+// Parse fetches the number of versions to return from the http request.
+func (h *statusProjectVersionsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.limit = defaultStatusVersionsLimit
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		var err error
+		h.limit, err = strconv.Atoi(limit)
+		if err != nil || h.limit <= 0 || h.limit > maxStatusVersionsLimit {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("limit must be a positive integer no greater than %d", maxStatusVersionsLimit),
+			}
+		}
+	}
+	return nil
+}
+
+// Run returns the statuses of the project's most recent mainline versions
+// and their builds.
+func (h *statusProjectVersionsHandler) Run(ctx context.Context) gimlet.Responder {
+	pRef := MustHaveProjectContext(ctx).ProjectRef
+	versions, err := dbModel.VersionFind(dbModel.VersionByMostRecentSystemRequester(pRef.Id).Limit(h.limit))
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding versions for project '%s'", pRef.Identifier))
+	}
+	versionIDs := make([]string, 0, len(versions))
+	for _, v := range versions {
+		versionIDs = append(versionIDs, v.Id)
+	}
+	builds, err := findStatusBuilds(build.ByVersions(versionIDs))
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding builds for project '%s'", pRef.Identifier))
+	}
+	buildsByVersion := map[string][]build.Build{}
+	for _, b := range builds {
+		buildsByVersion[b.Version] = append(buildsByVersion[b.Version], b)
+	}
+
+	apiVersions := make([]model.APIStatusVersion, 0, len(versions))
+	for _, v := range versions {
+		apiVersion := model.APIStatusVersion{}
+		apiVersion.BuildFromService(v, pRef.Identifier, buildsByVersion[v.Id])
+		apiVersions = append(apiVersions, apiVersion)
+	}
+	return gimlet.NewJSONResponse(apiVersions)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/status/versions/{version_id}
+
+type statusVersionHandler struct{}
+
+func makeGetStatusVersion() gimlet.RouteHandler {
+	return &statusVersionHandler{}
+}
+
+func (h *statusVersionHandler) Factory() gimlet.RouteHandler                     { return h }
+func (h *statusVersionHandler) Parse(ctx context.Context, r *http.Request) error { return nil }
+
+// Run returns the status of the version and its builds.
+func (h *statusVersionHandler) Run(ctx context.Context) gimlet.Responder {
+	opCtx := MustHaveProjectContext(ctx)
+	if opCtx.Version == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "version not found",
+		})
+	}
+	builds, err := findStatusBuilds(build.ByVersion(opCtx.Version.Id))
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding builds for version '%s'", opCtx.Version.Id))
+	}
+
+	apiVersion := model.APIStatusVersion{}
+	apiVersion.BuildFromService(*opCtx.Version, opCtx.ProjectRef.Identifier, builds)
+	return gimlet.NewJSONResponse(apiVersion)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/status/builds/{build_id}
+
+type statusBuildHandler struct{}
+
+func makeGetStatusBuild() gimlet.RouteHandler {
+	return &statusBuildHandler{}
+}
+
+func (h *statusBuildHandler) Factory() gimlet.RouteHandler                     { return h }
+func (h *statusBuildHandler) Parse(ctx context.Context, r *http.Request) error { return nil }
+
+// Run returns the status of the build and its tasks.
+func (h *statusBuildHandler) Run(ctx context.Context) gimlet.Responder {
+	b := MustHaveProjectContext(ctx).Build
+	if b == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "build not found",
+		})
+	}
+	buildTasks, err := task.FindAll(db.Query(task.ByBuildId(b.Id)))
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding tasks for build '%s'", b.Id))
+	}
+	tasks := make([]task.Task, 0, len(buildTasks))
+	for _, t := range buildTasks {
+		if utility.FromStringPtr(t.DisplayTaskId) != "" {
+			// Execution tasks are represented by their display task.
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+
+	apiBuild := model.APIStatusBuild{}
+	apiBuild.BuildFromService(*b, tasks)
+	return gimlet.NewJSONResponse(apiBuild)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/status/tasks/{task_id}
+
+type statusTaskHandler struct{}
+
+func makeGetStatusTask() gimlet.RouteHandler {
+	return &statusTaskHandler{}
+}
+
+func (h *statusTaskHandler) Factory() gimlet.RouteHandler                     { return h }
+func (h *statusTaskHandler) Parse(ctx context.Context, r *http.Request) error { return nil }
+
+// Run returns the status of the task.
+func (h *statusTaskHandler) Run(ctx context.Context) gimlet.Responder {
+	t := MustHaveProjectContext(ctx).Task
+	if t == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "task not found",
+		})
+	}
+	apiTask := model.APIStatusTask{}
+	apiTask.BuildFromService(*t)
+	return gimlet.NewJSONResponse(apiTask)
+}
+
+func findStatusBuilds(q db.Q) ([]build.Build, error) {
+	return build.Find(q.WithFields(build.IdKey, build.VersionKey, build.BuildVariantKey, build.DisplayNameKey, build.StatusKey))
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/status/projects/{project_id}/badge.svg
+
+// statusBadgeHandler writes an SVG badge with the status of the project's
+// most recent finished mainline version, or of a variant in it if the
+// "variant" query parameter is given.
+func statusBadgeHandler(rw http.ResponseWriter, r *http.Request) {
+	pRef := MustHaveProjectContext(r.Context()).ProjectRef
+	variant := r.URL.Query().Get("variant")
+	status, err := dbModel.GetLatestMainlineStatus(pRef.Id, variant)
+	if err != nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting status for project '%s'", pRef.Identifier)))
+		return
+	}
+
+	label := pRef.Identifier
+	if variant != "" {
+		label = fmt.Sprintf("%s/%s", pRef.Identifier, variant)
+	}
+	rw.Header().Set("Content-Type", "image/svg+xml")
+	rw.WriteHeader(http.StatusOK)
+	_, err = rw.Write([]byte(makeStatusBadgeSVG(label, status)))
+	grip.Error(message.WrapError(err, message.Fields{
+		"message": "could not write status badge",
+		"project": pRef.Id,
+		"variant": variant,
+	}))
+}
+
+const (
+	statusBadgeCharWidth = 7
+	statusBadgePadding   = 10
+	statusBadgeLabelFill = "#555"
+)
+
+// makeStatusBadgeSVG returns a flat badge with the label on the left and the
+// status on the right.
+func makeStatusBadgeSVG(label, status string) string {
+	text, fill := "unknown", "#9f9f9f"
+	switch status {
+	case evergreen.VersionSucceeded:
+		text, fill = "passing", "#4c1"
+	case evergreen.VersionFailed:
+		text, fill = "failing", "#e05d44"
+	}
+	labelWidth := len(label)*statusBadgeCharWidth + 2*statusBadgePadding
+	label = html.EscapeString(label)
+	textWidth := len(text)*statusBadgeCharWidth + 2*statusBadgePadding
+	width := labelWidth + textWidth
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[2]s: %[3]s">`+
+		`<title>%[2]s: %[3]s</title>`+
+		`<rect width="%[4]d" height="20" fill="%[6]s"/>`+
+		`<rect x="%[4]d" width="%[5]d" height="20" fill="%[7]s"/>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[8]d" y="14">%[2]s</text>`+
+		`<text x="%[9]d" y="14">%[3]s</text>`+
+		`</g></svg>`,
+		width, label, text, labelWidth, textWidth, statusBadgeLabelFill, fill, labelWidth/2, labelWidth+textWidth/2)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/status_api_keys
+
+type statusAPIKeysGetHandler struct{}
+
+func makeGetStatusAPIKeys() gimlet.RouteHandler {
+	return &statusAPIKeysGetHandler{}
+}
+
+func (h *statusAPIKeysGetHandler) Factory() gimlet.RouteHandler                     { return h }
+func (h *statusAPIKeysGetHandler) Parse(ctx context.Context, r *http.Request) error { return nil }
+
+// Run returns all status API keys, without the keys themselves.
+func (h *statusAPIKeysGetHandler) Run(ctx context.Context) gimlet.Responder {
+	keys, err := dbModel.FindAllStatusAPIKeys()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	apiKeys := make([]model.APIStatusAPIKey, 0, len(keys))
+	for _, k := range keys {
+		apiKey := model.APIStatusAPIKey{}
+		apiKey.BuildFromService(k)
+		apiKeys = append(apiKeys, apiKey)
+	}
+	return gimlet.NewJSONResponse(apiKeys)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/status_api_keys
+
+type statusAPIKeyPostHandler struct {
+	Name              string   `json:"name"`
+	Projects          []string `json:"projects"`
+	RequestsPerMinute int      `json:"requests_per_minute"`
+}
+
+func makeCreateStatusAPIKey() gimlet.RouteHandler {
+	return &statusAPIKeyPostHandler{}
+}
+
+func (h *statusAPIKeyPostHandler) Factory() gimlet.RouteHandler {
+	return &statusAPIKeyPostHandler{}
+}
+
+// Parse fetches the name, projects, and rate limit of the new key from the
+// http request.
+func (h *statusAPIKeyPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	return errors.Wrap(utility.ReadJSON(r.Body, h), "reading status API key from JSON request body")
+}
+
+// Run creates the key and returns it. This is the only time that the key
+// itself is returned.
+func (h *statusAPIKeyPostHandler) Run(ctx context.Context) gimlet.Responder {
+	projectIDs := make([]string, 0, len(h.Projects))
+	for _, identifier := range h.Projects {
+		pRef, err := dbModel.FindBranchProjectRef(identifier)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project '%s'", identifier))
+		}
+		if pRef == nil {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("project '%s' not found", identifier),
+			})
+		}
+		projectIDs = append(projectIDs, pRef.Id)
+	}
+
+	u := MustHaveUser(ctx)
+	apiKey, key := dbModel.NewStatusAPIKey(h.Name, projectIDs, h.RequestsPerMinute, u.Username())
+	if err := apiKey.Validate(); err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid status API key").Error(),
+		})
+	}
+	if err := apiKey.Insert(); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "inserting status API key"))
+	}
+	grip.Info(message.Fields{
+		"message":  "created status API key",
+		"key_id":   apiKey.Id,
+		"name":     apiKey.Name,
+		"projects": apiKey.Projects,
+		"user":     u.Username(),
+	})
+
+	resp := model.APIStatusAPIKey{}
+	resp.BuildFromService(*apiKey)
+	resp.Key = utility.ToStringPtr(key)
+	return gimlet.NewJSONResponse(resp)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/admin/status_api_keys/{key_id}
+
+type statusAPIKeyDeleteHandler struct {
+	keyID string
+}
+
+func makeDeleteStatusAPIKey() gimlet.RouteHandler {
+	return &statusAPIKeyDeleteHandler{}
+}
+
+func (h *statusAPIKeyDeleteHandler) Factory() gimlet.RouteHandler {
+	return &statusAPIKeyDeleteHandler{}
+}
+
+// Parse fetches the ID of the key to delete from the http request.
+func (h *statusAPIKeyDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.keyID = gimlet.GetVars(r)["key_id"]
+	return nil
+}
+
+// Run deletes the key.
+func (h *statusAPIKeyDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := dbModel.RemoveStatusAPIKey(h.keyID); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "deleting status API key '%s'", h.keyID))
+	}
+	grip.Info(message.Fields{
+		"message": "deleted status API key",
+		"key_id":  h.keyID,
+		"user":    MustHaveUser(ctx).Username(),
+	})
+	return gimlet.NewJSONResponse(struct{}{})
+}
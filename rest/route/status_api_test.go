@@ -0,0 +1,235 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusProjectVersionsHandlerParse(t *testing.T) {
+	for tName, tCase := range map[string]struct {
+		query         string
+		expectedLimit int
+		expectError   bool
+	}{
+		"DefaultLimit": {
+			expectedLimit: defaultStatusVersionsLimit,
+		},
+		"ValidLimit": {
+			query:         "?limit=5",
+			expectedLimit: 5,
+		},
+		"MaxLimit": {
+			query:         "?limit=100",
+			expectedLimit: maxStatusVersionsLimit,
+		},
+		"LimitTooLarge": {
+			query:       "?limit=101",
+			expectError: true,
+		},
+		"ZeroLimit": {
+			query:       "?limit=0",
+			expectError: true,
+		},
+		"NonNumericLimit": {
+			query:       "?limit=ten",
+			expectError: true,
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			rh := makeGetStatusProjectVersions().(*statusProjectVersionsHandler)
+			req, err := http.NewRequest(http.MethodGet, "/status/projects/p1/versions"+tCase.query, nil)
+			require.NoError(t, err)
+			err = rh.Parse(context.Background(), req)
+			if tCase.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tCase.expectedLimit, rh.limit)
+		})
+	}
+}
+
+func TestStatusAPIMiddleware(t *testing.T) {
+	require.NoError(t, db.ClearCollections(serviceModel.ProjectRefCollection, serviceModel.StatusAPIKeysCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(serviceModel.ProjectRefCollection, serviceModel.StatusAPIKeysCollection))
+	}()
+
+	private := serviceModel.ProjectRef{Id: "private", Identifier: "private"}
+	require.NoError(t, private.Insert())
+	public := serviceModel.ProjectRef{Id: "public", Identifier: "public", PublicStatus: utility.TruePtr()}
+	require.NoError(t, public.Insert())
+	apiKey, key := serviceModel.NewStatusAPIKey("key", []string{"private"}, 1, "me")
+	require.NoError(t, apiKey.Insert())
+
+	serve := func(t *testing.T, projectID, key string) (*httptest.ResponseRecorder, bool) {
+		r, err := http.NewRequest(http.MethodGet, "/status/projects/"+projectID+"/versions", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"project_id": projectID})
+		if key != "" {
+			r.Header.Set(statusAPIKeyHeader, key)
+		}
+		rw := httptest.NewRecorder()
+		var called bool
+		NewStatusAPIMiddleware().ServeHTTP(rw, r, func(rw http.ResponseWriter, r *http.Request) {
+			called = true
+			assert.Equal(t, projectID, MustHaveProjectContext(r.Context()).ProjectRef.Id)
+		})
+		return rw, called
+	}
+
+	t.Run("NonexistentProject", func(t *testing.T) {
+		rw, called := serve(t, "nonexistent", "")
+		assert.False(t, called)
+		assert.Equal(t, http.StatusNotFound, rw.Code)
+	})
+	t.Run("PrivateProjectWithoutKey", func(t *testing.T) {
+		rw, called := serve(t, "private", "")
+		assert.False(t, called)
+		assert.Equal(t, http.StatusNotFound, rw.Code)
+	})
+	t.Run("PublicProjectWithoutKey", func(t *testing.T) {
+		rw, called := serve(t, "public", "")
+		assert.True(t, called)
+		assert.Equal(t, "public, max-age=60", rw.Header().Get("Cache-Control"))
+	})
+	t.Run("InvalidKey", func(t *testing.T) {
+		rw, called := serve(t, "private", "invalid")
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnauthorized, rw.Code)
+	})
+	t.Run("KeyForOtherProject", func(t *testing.T) {
+		rw, called := serve(t, "public", key)
+		assert.False(t, called)
+		assert.Equal(t, http.StatusNotFound, rw.Code)
+	})
+	t.Run("KeyIsRateLimited", func(t *testing.T) {
+		rw, called := serve(t, "private", key)
+		assert.True(t, called)
+		assert.Equal(t, "private, max-age=60", rw.Header().Get("Cache-Control"))
+
+		rw, called = serve(t, "private", key)
+		assert.False(t, called)
+		assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	})
+}
+
+func TestStatusRoutes(t *testing.T) {
+	require.NoError(t, db.ClearCollections(serviceModel.ProjectRefCollection, serviceModel.VersionCollection, build.Collection, task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(serviceModel.ProjectRefCollection, serviceModel.VersionCollection, build.Collection, task.Collection))
+	}()
+
+	pRef := serviceModel.ProjectRef{Id: "p1", Identifier: "project"}
+	require.NoError(t, pRef.Insert())
+	for _, v := range []serviceModel.Version{
+		{Id: "v1", Identifier: "p1", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 1, Status: evergreen.VersionFailed},
+		{Id: "v2", Identifier: "p1", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 2, Status: evergreen.VersionSucceeded},
+		{Id: "patch", Identifier: "p1", Requester: evergreen.PatchVersionRequester, RevisionOrderNumber: 3, Status: evergreen.VersionFailed},
+	} {
+		require.NoError(t, v.Insert())
+	}
+	for _, b := range []build.Build{
+		{Id: "b1", Version: "v1", BuildVariant: "bv", Status: evergreen.BuildFailed},
+		{Id: "b2", Version: "v2", BuildVariant: "bv", Status: evergreen.BuildSucceeded},
+	} {
+		require.NoError(t, b.Insert())
+	}
+	for _, tsk := range []task.Task{
+		{Id: "t1", BuildId: "b2", Version: "v2", DisplayName: "display", Status: evergreen.TaskSucceeded, DisplayOnly: true},
+		{Id: "t2", BuildId: "b2", Version: "v2", DisplayName: "exec", Status: evergreen.TaskSucceeded, DisplayTaskId: utility.ToStringPtr("t1")},
+	} {
+		require.NoError(t, tsk.Insert())
+	}
+
+	v2, err := serviceModel.VersionFindOneId("v2")
+	require.NoError(t, err)
+	b2, err := build.FindOneId("b2")
+	require.NoError(t, err)
+	t1, err := task.FindOneId("t1")
+	require.NoError(t, err)
+
+	t.Run("ProjectVersions", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), RequestContext, &serviceModel.Context{ProjectRef: &pRef})
+		rh := makeGetStatusProjectVersions()
+		req, err := http.NewRequest(http.MethodGet, "/status/projects/p1/versions?limit=1", nil)
+		require.NoError(t, err)
+		require.NoError(t, rh.Parse(ctx, req))
+		resp := rh.Run(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		versions, ok := resp.Data().([]model.APIStatusVersion)
+		require.True(t, ok)
+		require.Len(t, versions, 1)
+		assert.Equal(t, "v2", utility.FromStringPtr(versions[0].Id))
+		assert.Equal(t, "project", utility.FromStringPtr(versions[0].ProjectIdentifier))
+		require.Len(t, versions[0].Builds, 1)
+		assert.Equal(t, "b2", utility.FromStringPtr(versions[0].Builds[0].Id))
+	})
+	t.Run("Version", func(t *testing.T) {
+		rh := makeGetStatusVersion()
+
+		ctx := context.WithValue(context.Background(), RequestContext, &serviceModel.Context{ProjectRef: &pRef})
+		assert.Equal(t, http.StatusNotFound, rh.Run(ctx).Status())
+
+		ctx = context.WithValue(context.Background(), RequestContext, &serviceModel.Context{ProjectRef: &pRef, Version: v2})
+		resp := rh.Run(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		version, ok := resp.Data().(model.APIStatusVersion)
+		require.True(t, ok)
+		assert.Equal(t, evergreen.VersionSucceeded, utility.FromStringPtr(version.Status))
+		require.Len(t, version.Builds, 1)
+		assert.Equal(t, "b2", utility.FromStringPtr(version.Builds[0].Id))
+	})
+	t.Run("Build", func(t *testing.T) {
+		rh := makeGetStatusBuild()
+
+		ctx := context.WithValue(context.Background(), RequestContext, &serviceModel.Context{ProjectRef: &pRef})
+		assert.Equal(t, http.StatusNotFound, rh.Run(ctx).Status())
+
+		ctx = context.WithValue(context.Background(), RequestContext, &serviceModel.Context{ProjectRef: &pRef, Build: b2})
+		resp := rh.Run(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		apiBuild, ok := resp.Data().(model.APIStatusBuild)
+		require.True(t, ok)
+		assert.Equal(t, evergreen.BuildSucceeded, utility.FromStringPtr(apiBuild.Status))
+		require.Len(t, apiBuild.Tasks, 1, "execution tasks should be represented by their display task")
+		assert.Equal(t, "t1", utility.FromStringPtr(apiBuild.Tasks[0].Id))
+	})
+	t.Run("Task", func(t *testing.T) {
+		rh := makeGetStatusTask()
+
+		ctx := context.WithValue(context.Background(), RequestContext, &serviceModel.Context{ProjectRef: &pRef})
+		assert.Equal(t, http.StatusNotFound, rh.Run(ctx).Status())
+
+		ctx = context.WithValue(context.Background(), RequestContext, &serviceModel.Context{ProjectRef: &pRef, Task: t1})
+		resp := rh.Run(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		apiTask, ok := resp.Data().(model.APIStatusTask)
+		require.True(t, ok)
+		assert.Equal(t, evergreen.TaskSucceeded, utility.FromStringPtr(apiTask.Status))
+	})
+	t.Run("Badge", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/status/projects/p1/badge.svg", nil)
+		require.NoError(t, err)
+		r = r.WithContext(context.WithValue(r.Context(), RequestContext, &serviceModel.Context{ProjectRef: &pRef}))
+		rw := httptest.NewRecorder()
+		statusBadgeHandler(rw, r)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "image/svg+xml", rw.Header().Get("Content-Type"))
+		assert.Contains(t, rw.Body.String(), "project: passing")
+	})
+}
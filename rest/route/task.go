@@ -5,14 +5,19 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 )
 
@@ -71,6 +76,15 @@ func (tgh *taskGetHandler) Run(ctx context.Context) gimlet.Responder {
 	if err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", tgh.taskID))
 	}
+	archived := false
+	if foundTask == nil {
+		// Tasks of archived versions are only in cold storage.
+		foundTask, err = dbModel.FindArchivedTask(tgh.taskID, tgh.execution)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding archived task '%s'", tgh.taskID))
+		}
+		archived = foundTask != nil
+	}
 	if foundTask == nil {
 		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
 			StatusCode: http.StatusNotFound,
@@ -91,7 +105,11 @@ func (tgh *taskGetHandler) Run(ctx context.Context) gimlet.Responder {
 
 	if tgh.fetchAllExecutions {
 		var tasks []task.Task
-		tasks, err = task.FindOldWithDisplayTasks(task.ByOldTaskID(tgh.taskID))
+		if archived {
+			tasks, err = dbModel.FindArchivedTaskExecutions(tgh.taskID)
+		} else {
+			tasks, err = task.FindOldWithDisplayTasks(task.ByOldTaskID(tgh.taskID))
+		}
 		if err != nil {
 			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding archived executions for task '%s'", tgh.taskID))
 		}
@@ -180,7 +198,24 @@ func (tep *taskExecutionPatchHandler) Run(ctx context.Context) gimlet.Responder
 	}
 	if tep.Activated != nil {
 		activated := *tep.Activated
+		if activated {
+			if err := dbModel.CheckTaskActivationQuotas(tep.user.Username(), []task.Task{*tep.task}); err != nil {
+				if dbModel.IsProjectQuotaExceeded(err) {
+					return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+						StatusCode: http.StatusBadRequest,
+						Message:    err.Error(),
+					})
+				}
+				return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "checking project quotas for task '%s'", tep.task.Id))
+			}
+		}
 		if err := dbModel.SetActiveStateById(tep.task.Id, tep.user.Username(), activated); err != nil {
+			if dbModel.IsVariantActivationDenied(err) {
+				return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+					StatusCode: http.StatusForbidden,
+					Message:    err.Error(),
+				})
+			}
 			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "setting activation state for task '%s'", tep.task.Id))
 		}
 	}
@@ -289,19 +324,67 @@ func (rh *taskSyncPathGetHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewTextResponse(t.S3Path(t.BuildVariant, t.DisplayName))
 }
 
+// POST /tasks/{task_id}/finalizing
+
+type taskFinalizingHandler struct {
+	taskID string
+}
+
+func makeTaskFinalizingHandler() gimlet.RouteHandler {
+	return &taskFinalizingHandler{}
+}
+
+func (rh *taskFinalizingHandler) Factory() gimlet.RouteHandler {
+	return &taskFinalizingHandler{}
+}
+
+func (rh *taskFinalizingHandler) Parse(ctx context.Context, r *http.Request) error {
+	rh.taskID = gimlet.GetVars(r)["task_id"]
+	return nil
+}
+
+// Run records that the agent has started ending the task, which gives the
+// agent a window to finish ending it even if the agent restarts in the
+// meantime.
+func (rh *taskFinalizingHandler) Run(ctx context.Context) gimlet.Responder {
+	t, err := task.FindOneId(rh.taskID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", rh.taskID))
+	}
+	if t == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found", rh.taskID),
+		})
+	}
+
+	ok, err := t.SetFinalizingUntil(time.Now().Add(evergreen.EndTaskResumeWindow))
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "starting to end task '%s'", rh.taskID))
+	}
+	if !ok {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusConflict,
+			Message:    fmt.Sprintf("task '%s' execution %d is not in progress", t.Id, t.Execution),
+		})
+	}
+	return gimlet.NewJSONResponse(apimodels.FinalizingTaskResponse{FinalizingUntil: t.FinalizingUntil})
+}
+
 // POST /tasks/{task_id}/set_has_cedar_results
 
 type taskSetHasCedarResultsHandler struct {
+	env    evergreen.Environment
 	taskID string
 	info   apimodels.CedarTestResultsTaskInfo
 }
 
-func makeTaskSetHasCedarResultsHandler() gimlet.RouteHandler {
-	return &taskSetHasCedarResultsHandler{}
+func makeTaskSetHasCedarResultsHandler(env evergreen.Environment) gimlet.RouteHandler {
+	return &taskSetHasCedarResultsHandler{env: env}
 }
 
 func (rh *taskSetHasCedarResultsHandler) Factory() gimlet.RouteHandler {
-	return &taskSetHasCedarResultsHandler{}
+	return &taskSetHasCedarResultsHandler{env: rh.env}
 }
 
 func (rh *taskSetHasCedarResultsHandler) Parse(ctx context.Context, r *http.Request) error {
@@ -329,9 +412,125 @@ func (rh *taskSetHasCedarResultsHandler) Run(ctx context.Context) gimlet.Respond
 	if err = t.SetHasCedarResults(true, rh.info.Failed); err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "setting HasCedarResults flag for task '%s'", rh.taskID))
 	}
+	if t.Requester == evergreen.RepotrackerVersionRequester {
+		// Flakiness is scored from mainline results only.
+		grip.Error(message.WrapError(rh.env.RemoteQueue().Put(ctx, units.NewTestFlakinessJob(t.Id, t.Execution)), message.Fields{
+			"message":   "could not queue job to update test flakiness",
+			"task":      t.Id,
+			"execution": t.Execution,
+		}))
+	}
 	return gimlet.NewTextResponse("HasCedarResults flag set in task")
 }
 
+// POST /tasks/{task_id}/test_result_attachments
+
+type taskTestResultAttachmentsPostHandler struct {
+	taskID      string
+	attachments []testresult.TestAttachments
+}
+
+func makeTaskTestResultAttachmentsPostHandler() gimlet.RouteHandler {
+	return &taskTestResultAttachmentsPostHandler{}
+}
+
+func (rh *taskTestResultAttachmentsPostHandler) Factory() gimlet.RouteHandler {
+	return &taskTestResultAttachmentsPostHandler{}
+}
+
+func (rh *taskTestResultAttachmentsPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	rh.taskID = gimlet.GetVars(r)["task_id"]
+
+	if err := gimlet.GetJSON(r.Body, &rh.attachments); err != nil {
+		return errors.Wrap(err, "reading test result attachments from JSON request body")
+	}
+	catcher := grip.NewBasicCatcher()
+	for i := range rh.attachments {
+		catcher.Add(rh.attachments[i].Validate())
+	}
+	if catcher.HasErrors() {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    catcher.Resolve().Error(),
+		}
+	}
+
+	return nil
+}
+
+// Run stores the artifacts and log ranges of the task's test results that are
+// stored in Cedar.
+func (rh *taskTestResultAttachmentsPostHandler) Run(ctx context.Context) gimlet.Responder {
+	t, err := task.FindOneId(rh.taskID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", rh.taskID))
+	}
+	if t == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found", rh.taskID),
+		})
+	}
+
+	for i := range rh.attachments {
+		rh.attachments[i].TaskID = t.Id
+		rh.attachments[i].Execution = t.Execution
+	}
+	if err = testresult.UpsertAttachments(rh.attachments); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "saving test result attachments for task '%s'", rh.taskID))
+	}
+	return gimlet.NewTextResponse("test result attachments saved")
+}
+
+// POST /tasks/{task_id}/outputs
+
+type taskOutputsPostHandler struct {
+	taskID  string
+	outputs map[string]string
+}
+
+func makeTaskOutputsPostHandler() gimlet.RouteHandler {
+	return &taskOutputsPostHandler{}
+}
+
+func (rh *taskOutputsPostHandler) Factory() gimlet.RouteHandler {
+	return &taskOutputsPostHandler{}
+}
+
+func (rh *taskOutputsPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	rh.taskID = gimlet.GetVars(r)["task_id"]
+
+	if err := gimlet.GetJSON(r.Body, &rh.outputs); err != nil {
+		return errors.Wrap(err, "reading task outputs from JSON request body")
+	}
+
+	return nil
+}
+
+func (rh *taskOutputsPostHandler) Run(ctx context.Context) gimlet.Responder {
+	t, err := task.FindOneId(rh.taskID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", rh.taskID))
+	}
+	if t == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found", rh.taskID),
+		})
+	}
+
+	if err = dbModel.SetTaskOutputs(t, rh.outputs); err != nil {
+		if _, ok := errors.Cause(err).(dbModel.InvalidTaskOutputsError); ok {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+			})
+		}
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "setting outputs for task '%s'", rh.taskID))
+	}
+	return gimlet.NewTextResponse("task outputs set")
+}
+
 // GET /task/sync_read_credentials
 
 type taskSyncReadCredentialsGetHandler struct{}
@@ -0,0 +1,75 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// taskAbortCommandHandler aborts the command that a task is currently running
+// while letting the rest of the task continue to run.
+type taskAbortCommandHandler struct {
+	taskId string
+}
+
+func makeTaskAbortCommandHandler() gimlet.RouteHandler {
+	return &taskAbortCommandHandler{}
+}
+
+func (t *taskAbortCommandHandler) Factory() gimlet.RouteHandler {
+	return &taskAbortCommandHandler{}
+}
+
+func (t *taskAbortCommandHandler) Parse(ctx context.Context, r *http.Request) error {
+	t.taskId = gimlet.GetVars(r)["task_id"]
+	return nil
+}
+
+func (t *taskAbortCommandHandler) Run(ctx context.Context) gimlet.Responder {
+	foundTask, err := task.FindOneId(t.taskId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", t.taskId))
+	}
+	if foundTask == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found", t.taskId),
+		})
+	}
+	if foundTask.Status != evergreen.TaskStarted || foundTask.DisplayOnly || foundTask.Aborted {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("task '%s' is not running a command that can be aborted", t.taskId),
+		})
+	}
+
+	if err = serviceModel.AbortTaskCommand(t.taskId, MustHaveUser(ctx).Id); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "aborting running command in task '%s'", t.taskId))
+	}
+
+	foundTask, err = task.FindOneId(t.taskId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding updated task '%s'", t.taskId))
+	}
+	if foundTask == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found", t.taskId),
+		})
+	}
+	taskModel := &model.APITask{}
+	if err = taskModel.BuildFromArgs(foundTask, &model.APITaskArgs{
+		IncludeProjectIdentifier: true,
+		IncludeAMI:               true,
+	}); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting task '%s' to API model", t.taskId))
+	}
+	return gimlet.NewJSONResponse(taskModel)
+}
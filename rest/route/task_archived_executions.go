@@ -0,0 +1,195 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/archived_executions
+
+type archivedExecutionsGetHandler struct {
+	opts task.ArchivedExecutionsOptions
+}
+
+func makeGetArchivedExecutions() gimlet.RouteHandler {
+	return &archivedExecutionsGetHandler{}
+}
+
+func (h *archivedExecutionsGetHandler) Factory() gimlet.RouteHandler {
+	return &archivedExecutionsGetHandler{}
+}
+
+// Parse reads the filters from the query parameters. Executions can be
+// filtered by a comma-separated list of statuses (status), an inclusive range
+// of executions (min_execution and max_execution) and RFC3339 timestamps
+// bounding when they finished (finished_after and finished_before).
+func (h *archivedExecutionsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.opts.TaskID = gimlet.GetVars(r)["task_id"]
+	if h.opts.TaskID == "" {
+		return errors.New("task ID must be specified")
+	}
+
+	vals := r.URL.Query()
+	for _, status := range vals["status"] {
+		for _, s := range strings.Split(status, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				h.opts.Statuses = append(h.opts.Statuses, s)
+			}
+		}
+	}
+
+	var err error
+	if h.opts.MinExecution, err = parseExecutionParam(vals.Get("min_execution")); err != nil {
+		return errors.Wrap(err, "invalid min execution")
+	}
+	if h.opts.MaxExecution, err = parseExecutionParam(vals.Get("max_execution")); err != nil {
+		return errors.Wrap(err, "invalid max execution")
+	}
+	if h.opts.MinExecution != nil && h.opts.MaxExecution != nil && *h.opts.MinExecution > *h.opts.MaxExecution {
+		return errors.New("min execution cannot be greater than max execution")
+	}
+
+	if after := vals.Get("finished_after"); after != "" {
+		if h.opts.FinishedAfter, err = time.Parse(time.RFC3339, after); err != nil {
+			return errors.Wrap(err, "parsing finished after time in RFC3339 format")
+		}
+	}
+	if before := vals.Get("finished_before"); before != "" {
+		if h.opts.FinishedBefore, err = time.Parse(time.RFC3339, before); err != nil {
+			return errors.Wrap(err, "parsing finished before time in RFC3339 format")
+		}
+	}
+
+	h.opts.Limit, err = getLimit(vals)
+	return errors.WithStack(err)
+}
+
+func parseExecutionParam(val string) (*int, error) {
+	if val == "" {
+		return nil, nil
+	}
+	execution, err := strconv.Atoi(val)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing execution '%s'", val)
+	}
+	if execution < 0 {
+		return nil, errors.Errorf("execution %d cannot be negative", execution)
+	}
+	return utility.ToIntPtr(execution), nil
+}
+
+func (h *archivedExecutionsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	tasks, err := task.FindArchivedExecutions(h.opts)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	if len(tasks) == 0 {
+		// Executions of tasks in archived versions are only in cold storage.
+		tasks, err = dbModel.FindArchivedTaskExecutionsMatching(h.opts)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding executions of archived task '%s'", h.opts.TaskID))
+		}
+	}
+
+	executions := []model.APIArchivedExecution{}
+	for i := range tasks {
+		execution, err := buildArchivedExecution(&tasks[i])
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		executions = append(executions, *execution)
+	}
+	return gimlet.NewJSONResponse(executions)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/archived_executions/{execution}
+
+type archivedExecutionGetHandler struct {
+	taskID    string
+	execution int
+}
+
+func makeGetArchivedExecution() gimlet.RouteHandler {
+	return &archivedExecutionGetHandler{}
+}
+
+func (h *archivedExecutionGetHandler) Factory() gimlet.RouteHandler {
+	return &archivedExecutionGetHandler{}
+}
+
+func (h *archivedExecutionGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.taskID = vars["task_id"]
+	if h.taskID == "" {
+		return errors.New("task ID must be specified")
+	}
+	execution, err := parseExecutionParam(vars["execution"])
+	if err != nil {
+		return errors.Wrap(err, "invalid execution")
+	}
+	if execution == nil {
+		return errors.New("execution must be specified")
+	}
+	h.execution = *execution
+	return nil
+}
+
+func (h *archivedExecutionGetHandler) Run(ctx context.Context) gimlet.Responder {
+	t, err := task.FindOneOldId(task.MakeOldID(h.taskID, h.execution))
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding execution %d of task '%s'", h.execution, h.taskID))
+	}
+	if t == nil {
+		// Executions of tasks in archived versions are only in cold storage.
+		var executions []task.Task
+		executions, err = dbModel.FindArchivedTaskExecutionsMatching(task.ArchivedExecutionsOptions{
+			TaskID:       h.taskID,
+			MinExecution: utility.ToIntPtr(h.execution),
+			MaxExecution: utility.ToIntPtr(h.execution),
+		})
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding execution %d of archived task '%s'", h.execution, h.taskID))
+		}
+		if len(executions) > 0 {
+			t = &executions[0]
+		}
+	}
+	if t == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("archived execution %d of task '%s' not found", h.execution, h.taskID),
+		})
+	}
+
+	execution, err := buildArchivedExecution(t)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	return gimlet.NewJSONResponse(execution)
+}
+
+func buildArchivedExecution(t *task.Task) (*model.APIArchivedExecution, error) {
+	if err := t.PopulateTestResults(); err != nil {
+		return nil, errors.Wrapf(err, "populating test results for execution %d of task '%s'", t.Execution, t.OldTaskId)
+	}
+	execution := &model.APIArchivedExecution{}
+	if err := execution.BuildFromService(t); err != nil {
+		return nil, err
+	}
+	return execution, nil
+}
@@ -0,0 +1,79 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/failure_search
+
+type taskFailureSearchHandler struct {
+	opts dbModel.TaskFailureLogSearchOptions
+}
+
+func makeSearchTaskFailures() gimlet.RouteHandler {
+	return &taskFailureSearchHandler{}
+}
+
+func (h *taskFailureSearchHandler) Factory() gimlet.RouteHandler {
+	return &taskFailureSearchHandler{}
+}
+
+func (h *taskFailureSearchHandler) Parse(ctx context.Context, r *http.Request) error {
+	vals := r.URL.Query()
+	h.opts = dbModel.TaskFailureLogSearchOptions{
+		ProjectID: MustHaveProjectContext(ctx).ProjectRef.Id,
+		Pattern:   vals.Get("pattern"),
+	}
+	if requesters := vals.Get("requesters"); requesters != "" {
+		h.opts.Requesters = strings.Split(requesters, ",")
+	}
+	if since := vals.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid since time '%s', must be in RFC3339 format", since),
+			}
+		}
+		h.opts.Since = t
+	}
+	if limit := vals.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid limit '%s'", limit),
+			}
+		}
+		h.opts.Limit = n
+	}
+	if err := h.opts.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return nil
+}
+
+// Run returns the project's recently failed tasks whose failure output
+// matches the pattern, newest first. Only failures from while the project had
+// failure log indexing enabled can be found.
+func (h *taskFailureSearchHandler) Run(ctx context.Context) gimlet.Responder {
+	logs, err := dbModel.SearchTaskFailureLogs(h.opts)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "searching task failure logs"))
+	}
+	return gimlet.NewJSONResponse(logs)
+}
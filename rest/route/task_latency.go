@@ -0,0 +1,138 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+///////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/latency
+
+type taskLatencyGetHandler struct {
+	taskID    string
+	execution *int
+}
+
+func makeGetTaskLatency() gimlet.RouteHandler {
+	return &taskLatencyGetHandler{}
+}
+
+func (h *taskLatencyGetHandler) Factory() gimlet.RouteHandler {
+	return &taskLatencyGetHandler{}
+}
+
+// Parse fetches the task ID and the optional execution, which defaults to the
+// latest execution.
+func (h *taskLatencyGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.taskID = gimlet.GetVars(r)["task_id"]
+	var err error
+	if h.execution, err = parseExecutionParam(r.URL.Query().Get("execution")); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid execution").Error(),
+		}
+	}
+	return nil
+}
+
+// Run returns when the task execution was queued, dispatched, accepted by the
+// agent, started, and finished, and how long it spent between each.
+func (h *taskLatencyGetHandler) Run(ctx context.Context) gimlet.Responder {
+	if h.execution == nil {
+		t, err := task.FindOneId(h.taskID)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", h.taskID))
+		}
+		if t == nil {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("task '%s' not found", h.taskID),
+			})
+		}
+		h.execution = &t.Execution
+	}
+
+	latency, err := dbModel.GetTaskLatency(h.taskID, *h.execution)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting latency for task '%s'", h.taskID))
+	}
+	if latency == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' execution %d not found", h.taskID, *h.execution),
+		})
+	}
+
+	apiLatency := model.APITaskLatency{}
+	apiLatency.BuildFromService(*latency)
+	return gimlet.NewJSONResponse(apiLatency)
+}
+
+///////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/distros/{distro_id}/task_latency
+// GET /rest/v2/projects/{project_id}/task_latency
+
+type taskLatencyStatsGetHandler struct {
+	forProject bool
+	opts       dbModel.TaskLatencyStatsOptions
+}
+
+func makeGetDistroTaskLatencyStats() gimlet.RouteHandler {
+	return &taskLatencyStatsGetHandler{}
+}
+
+func makeGetProjectTaskLatencyStats() gimlet.RouteHandler {
+	return &taskLatencyStatsGetHandler{forProject: true}
+}
+
+func (h *taskLatencyStatsGetHandler) Factory() gimlet.RouteHandler {
+	return &taskLatencyStatsGetHandler{forProject: h.forProject}
+}
+
+// Parse fetches the distro ID and the window of finished tasks to summarize.
+// The project is taken from the project context.
+func (h *taskLatencyStatsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	if !h.forProject {
+		h.opts.DistroID = gimlet.GetVars(r)["distro_id"]
+	}
+	h.opts.Window = dbModel.DefaultTaskLatencyWindow
+	if windowMins := r.URL.Query().Get("window_mins"); windowMins != "" {
+		mins, err := strconv.Atoi(windowMins)
+		if err != nil || mins <= 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "window_mins must be a positive integer",
+			}
+		}
+		h.opts.Window = time.Duration(mins) * time.Minute
+	}
+
+	return nil
+}
+
+// Run returns the percentiles of the scheduling, agent startup, and run time
+// latencies of the tasks that recently finished in the distro or project.
+func (h *taskLatencyStatsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	if h.forProject {
+		h.opts.Project = MustHaveProjectContext(ctx).ProjectRef.Id
+	}
+	stats, err := dbModel.GetTaskLatencyStats(h.opts)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting task latency stats"))
+	}
+
+	apiStats := model.APITaskLatencyStats{}
+	apiStats.BuildFromService(*stats)
+	return gimlet.NewJSONResponse(apiStats)
+}
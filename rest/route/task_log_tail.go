@@ -0,0 +1,283 @@
+package route
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// taskLogTailPollInterval is how often to check for new log messages that
+	// other app servers stored. Messages stored by this app server are sent as
+	// soon as they arrive.
+	taskLogTailPollInterval = 2 * time.Second
+	// taskLogTailKeepAliveInterval is how often to send a comment to keep an
+	// idle stream open through proxies.
+	taskLogTailKeepAliveInterval = 15 * time.Second
+	// taskLogTailMinSendInterval rate limits each stream by batching the log
+	// messages that arrive within the interval into a single event.
+	taskLogTailMinSendInterval = 500 * time.Millisecond
+	// taskLogTailBatchSize is the maximum number of log messages in an event.
+	taskLogTailBatchSize = 500
+	// maxTaskLogTailStreamsPerUser is the maximum number of log streams that
+	// a user can have open on an app server at once.
+	maxTaskLogTailStreamsPerUser = 5
+
+	taskLogTailEventLog   = "log"
+	taskLogTailEventEnd   = "end"
+	taskLogTailEventError = "error"
+)
+
+// taskLogTailLimiter limits the number of concurrent log streams per user.
+type taskLogTailLimiter struct {
+	mu      sync.Mutex
+	max     int
+	streams map[string]int
+}
+
+var taskLogTailStreams = &taskLogTailLimiter{
+	max:     maxTaskLogTailStreamsPerUser,
+	streams: map[string]int{},
+}
+
+func (l *taskLogTailLimiter) acquire(user string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.streams[user] >= l.max {
+		return false
+	}
+	l.streams[user]++
+	return true
+}
+
+func (l *taskLogTailLimiter) release(user string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.streams[user]--
+	if l.streams[user] <= 0 {
+		delete(l.streams, user)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/log_tail
+
+// taskLogTailHandler streams a task's log messages as server-sent events as
+// they're stored. Each "log" event holds a batch of messages and its ID is the
+// resume token, which clients pass back in the Last-Event-ID header (or the
+// resume_token query parameter) to continue from where they left off. An "end"
+// event is sent once the task has finished and all of its logs were sent.
+func taskLogTailHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	t := MustHaveProjectContext(ctx).Task
+	if t == nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "task not found",
+		}))
+		return
+	}
+
+	vals := r.URL.Query()
+	execution := t.Execution
+	if executionStr := vals.Get("execution"); executionStr != "" {
+		var err error
+		execution, err = strconv.Atoi(executionStr)
+		if err != nil || execution < 0 || execution > t.Execution {
+			gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid execution '%s'", executionStr),
+			}))
+			return
+		}
+	}
+	tokenStr := vals.Get("resume_token")
+	if tokenStr == "" {
+		tokenStr = r.Header.Get("Last-Event-ID")
+	}
+	token, err := dbModel.ParseTaskLogTailToken(tokenStr)
+	if err != nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}))
+		return
+	}
+	logTypes := vals["type"]
+
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONInternalErrorResponder(errors.New("response does not support streaming")))
+		return
+	}
+
+	user := gimlet.GetUser(ctx)
+	if user == nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "unauthorized",
+		}))
+		return
+	}
+	if !taskLogTailStreams.acquire(user.Username()) {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusTooManyRequests,
+			Message:    fmt.Sprintf("cannot have more than %d log streams open at once", maxTaskLogTailStreamsPerUser),
+		}))
+		return
+	}
+	defer taskLogTailStreams.release(user.Username())
+
+	// The stream stays open for as long as the task runs, which is longer
+	// than the server lets a response take to write.
+	if err = clearWriteDeadline(ctx); err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "could not lift write timeout for task log stream",
+			"task_id": t.Id,
+		}))
+	}
+
+	appended, unsubscribe := dbModel.SubscribeTaskLogAppends(t.Id)
+	defer unsubscribe()
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("X-Accel-Buffering", "no")
+	rw.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	poll := time.NewTicker(taskLogTailPollInterval)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(taskLogTailKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	var lastSent time.Time
+	drained := false
+	for {
+		// Wait out the rest of the interval since the last event so that
+		// messages that arrive in quick succession are sent together.
+		if wait := taskLogTailMinSendInterval - time.Since(lastSent); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		msgs, next, err := dbModel.TailTaskLogMessages(t.Id, execution, token, taskLogTailBatchSize)
+		if err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message":   "could not read task logs to stream",
+				"task_id":   t.Id,
+				"execution": execution,
+			}))
+			_ = writeTaskLogTailEvent(rw, taskLogTailEventError, "", err.Error())
+			flusher.Flush()
+			return
+		}
+		if next != token {
+			token = next
+			msgs = filterTaskLogTailMessages(msgs, logTypes)
+			if len(msgs) > 0 {
+				if err = writeTaskLogTailEvent(rw, taskLogTailEventLog, token.String(), msgs); err != nil {
+					return
+				}
+				flusher.Flush()
+				lastSent = time.Now()
+			}
+			// There may be more messages than fit in a single batch.
+			continue
+		}
+
+		finished, err := taskLogsFinished(t.Id, execution)
+		if err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message":   "could not check whether task is finished",
+				"task_id":   t.Id,
+				"execution": execution,
+			}))
+		}
+		if finished {
+			// Check once more for messages stored just before the task
+			// finished before ending the stream.
+			if !drained {
+				drained = true
+				continue
+			}
+			_ = writeTaskLogTailEvent(rw, taskLogTailEventEnd, token.String(), "")
+			flusher.Flush()
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-appended:
+		case <-poll.C:
+		case <-keepAlive.C:
+			if _, err = io.WriteString(rw, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// taskLogsFinished returns whether the task execution has finished, in which
+// case no more logs will be stored for it.
+func taskLogsFinished(taskID string, execution int) (bool, error) {
+	t, err := task.FindOneIdAndExecution(taskID, execution)
+	if err != nil {
+		return false, errors.Wrapf(err, "finding task '%s' execution %d", taskID, execution)
+	}
+	if t == nil {
+		return false, errors.Errorf("task '%s' execution %d not found", taskID, execution)
+	}
+	return t.IsFinished(), nil
+}
+
+func filterTaskLogTailMessages(msgs []apimodels.LogMessage, logTypes []string) []apimodels.LogMessage {
+	if len(logTypes) == 0 {
+		return msgs
+	}
+	filtered := make([]apimodels.LogMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		if utility.StringSliceContains(logTypes, msg.Type) {
+			filtered = append(filtered, msg)
+		}
+	}
+	return filtered
+}
+
+// writeTaskLogTailEvent writes a single server-sent event with the data
+// encoded as JSON.
+func writeTaskLogTailEvent(w io.Writer, event, id string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "marshalling event data")
+	}
+	if id != "" {
+		_, err = fmt.Fprintf(w, "event: %s\nid: %s\ndata: %s\n\n", event, id, payload)
+	} else {
+		_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	}
+	return errors.Wrap(err, "writing event")
+}
@@ -0,0 +1,47 @@
+package route
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLogTailLimiter(t *testing.T) {
+	l := &taskLogTailLimiter{max: 2, streams: map[string]int{}}
+	assert.True(t, l.acquire("u0"))
+	assert.True(t, l.acquire("u0"))
+	assert.False(t, l.acquire("u0"))
+	assert.True(t, l.acquire("u1"))
+
+	l.release("u0")
+	assert.True(t, l.acquire("u0"))
+	l.release("u0")
+	l.release("u0")
+	l.release("u1")
+	assert.Empty(t, l.streams)
+}
+
+func TestFilterTaskLogTailMessages(t *testing.T) {
+	msgs := []apimodels.LogMessage{
+		{Type: apimodels.TaskLogPrefix, Message: "task"},
+		{Type: apimodels.AgentLogPrefix, Message: "agent"},
+	}
+	assert.Equal(t, msgs, filterTaskLogTailMessages(msgs, nil))
+	assert.Equal(t, msgs[1:], filterTaskLogTailMessages(msgs, []string{apimodels.AgentLogPrefix}))
+	assert.Empty(t, filterTaskLogTailMessages(msgs, []string{apimodels.SystemLogPrefix}))
+}
+
+func TestWriteTaskLogTailEvent(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeTaskLogTailEvent(&buf, taskLogTailEventLog, "abc:1", []apimodels.LogMessage{{Message: "hello"}}))
+	assert.Contains(t, buf.String(), "event: log\nid: abc:1\ndata: [{")
+	assert.Contains(t, buf.String(), `"m":"hello"`)
+	assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte("\n\n")))
+
+	buf.Reset()
+	require.NoError(t, writeTaskLogTailEvent(&buf, taskLogTailEventError, "", "oops"))
+	assert.Equal(t, "event: error\ndata: \"oops\"\n\n", buf.String())
+}
@@ -0,0 +1,172 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// getEffectiveTaskOrder returns the task order that applies to the requesting
+// user, if there is one, for the project's builds. Builds of projects that no
+// longer exist keep their own order.
+func getEffectiveTaskOrder(ctx context.Context, projectId, versionId string) (dbModel.TaskOrder, string, error) {
+	pRef, err := dbModel.FindMergedProjectRef(projectId, versionId, false)
+	if err != nil {
+		return dbModel.TaskOrder{}, "", errors.Wrapf(err, "finding project '%s'", projectId)
+	}
+	if pRef == nil {
+		return dbModel.TaskOrder{}, dbModel.TaskOrderSourceProject, nil
+	}
+	userId := ""
+	if u := gimlet.GetUser(ctx); u != nil {
+		userId = u.Username()
+	}
+	return dbModel.GetEffectiveTaskOrder(userId, pRef)
+}
+
+// makeBuildTaskOrder applies the task order to the build's tasks. Tasks that
+// the order doesn't move keep the build's own order.
+func makeBuildTaskOrder(order dbModel.TaskOrder, source string, b build.Build, tasks []task.Task) *model.APIBuildTaskOrder {
+	taskMap := task.TaskSliceToMap(tasks)
+	buildTasks := make([]task.Task, 0, len(b.Tasks))
+	for _, tc := range b.Tasks {
+		if t, ok := taskMap[tc.Id]; ok {
+			buildTasks = append(buildTasks, t)
+		}
+	}
+	apiOrder := &model.APIBuildTaskOrder{}
+	apiOrder.BuildFromService(order, source, buildTasks)
+	return apiOrder
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/task_order
+
+type taskOrderGetHandler struct {
+	projectRef *dbModel.ProjectRef
+	userId     string
+}
+
+func makeGetTaskOrder() gimlet.RouteHandler {
+	return &taskOrderGetHandler{}
+}
+
+func (h *taskOrderGetHandler) Factory() gimlet.RouteHandler {
+	return &taskOrderGetHandler{}
+}
+
+func (h *taskOrderGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectRef = MustHaveProjectContext(ctx).ProjectRef
+	h.userId = MustHaveUser(ctx).Username()
+	return nil
+}
+
+// Run returns the project's task order, the user's override of it, and which
+// of them applies to the user.
+func (h *taskOrderGetHandler) Run(ctx context.Context) gimlet.Responder {
+	userOrder, err := dbModel.FindUserTaskOrder(h.userId, h.projectRef.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task order for user '%s'", h.userId))
+	}
+
+	resp := model.APIUserTaskOrder{
+		ProjectId: utility.ToStringPtr(h.projectRef.Id),
+		Source:    utility.ToStringPtr(dbModel.TaskOrderSourceProject),
+	}
+	resp.Project.BuildFromService(h.projectRef.TaskOrder)
+	if userOrder != nil {
+		resp.User = &model.APITaskOrder{}
+		resp.User.BuildFromService(userOrder.Order)
+		resp.Source = utility.ToStringPtr(dbModel.TaskOrderSourceUser)
+	}
+	return gimlet.NewJSONResponse(resp)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/projects/{project_id}/task_order
+
+type taskOrderPutHandler struct {
+	projectRef *dbModel.ProjectRef
+	userId     string
+	order      dbModel.TaskOrder
+}
+
+func makePutTaskOrder() gimlet.RouteHandler {
+	return &taskOrderPutHandler{}
+}
+
+func (h *taskOrderPutHandler) Factory() gimlet.RouteHandler {
+	return &taskOrderPutHandler{}
+}
+
+func (h *taskOrderPutHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectRef = MustHaveProjectContext(ctx).ProjectRef
+	h.userId = MustHaveUser(ctx).Username()
+
+	apiOrder := model.APITaskOrder{}
+	if err := gimlet.GetJSON(r.Body, &apiOrder); err != nil {
+		return errors.Wrap(err, "parsing request body")
+	}
+	h.order = apiOrder.ToService()
+	if err := h.order.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid task order").Error(),
+		}
+	}
+	return nil
+}
+
+// Run saves the user's own task order for the project.
+func (h *taskOrderPutHandler) Run(ctx context.Context) gimlet.Responder {
+	userOrder := dbModel.UserTaskOrder{
+		UserId:    h.userId,
+		ProjectId: h.projectRef.Id,
+		Order:     h.order,
+	}
+	if err := userOrder.Upsert(); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "saving task order for user '%s'", h.userId))
+	}
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/projects/{project_id}/task_order
+
+type taskOrderDeleteHandler struct {
+	projectRef *dbModel.ProjectRef
+	userId     string
+}
+
+func makeDeleteTaskOrder() gimlet.RouteHandler {
+	return &taskOrderDeleteHandler{}
+}
+
+func (h *taskOrderDeleteHandler) Factory() gimlet.RouteHandler {
+	return &taskOrderDeleteHandler{}
+}
+
+func (h *taskOrderDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectRef = MustHaveProjectContext(ctx).ProjectRef
+	h.userId = MustHaveUser(ctx).Username()
+	return nil
+}
+
+// Run removes the user's own task order for the project, so that the
+// project's task order applies to them again.
+func (h *taskOrderDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := dbModel.RemoveUserTaskOrder(h.userId, h.projectRef.Id); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "removing task order for user '%s'", h.userId))
+	}
+	return gimlet.NewJSONResponse(struct{}{})
+}
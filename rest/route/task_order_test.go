@@ -0,0 +1,120 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskOrderRoutes(t *testing.T) {
+	require.NoError(t, db.ClearCollections(serviceModel.ProjectRefCollection, serviceModel.UserTaskOrdersCollection, build.Collection, task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(serviceModel.ProjectRefCollection, serviceModel.UserTaskOrdersCollection, build.Collection, task.Collection))
+	}()
+
+	pRef := serviceModel.ProjectRef{
+		Id:        "p1",
+		TaskOrder: serviceModel.TaskOrder{PinnedTasks: []string{"lint"}},
+	}
+	require.NoError(t, pRef.Insert())
+	b := build.Build{
+		Id:      "b1",
+		Project: "p1",
+		Version: "v1",
+		Tasks: []build.TaskCache{
+			{Id: "t1"},
+			{Id: "t2"},
+			{Id: "t3"},
+		},
+	}
+	require.NoError(t, b.Insert())
+	for _, tsk := range []task.Task{
+		{Id: "t1", BuildId: "b1", Version: "v1", DisplayName: "compile", Status: evergreen.TaskSucceeded},
+		{Id: "t2", BuildId: "b1", Version: "v1", DisplayName: "test", Status: evergreen.TaskFailed},
+		{Id: "t3", BuildId: "b1", Version: "v1", DisplayName: "lint", Status: evergreen.TaskSucceeded},
+	} {
+		require.NoError(t, tsk.Insert())
+	}
+
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "me"})
+	ctx = context.WithValue(ctx, RequestContext, &serviceModel.Context{ProjectRef: &pRef})
+
+	getOrder := func(t *testing.T) model.APIUserTaskOrder {
+		rh := makeGetTaskOrder()
+		req, err := http.NewRequest(http.MethodGet, "/projects/p1/task_order", nil)
+		require.NoError(t, err)
+		require.NoError(t, rh.Parse(ctx, req))
+		resp := rh.Run(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		order, ok := resp.Data().(model.APIUserTaskOrder)
+		require.True(t, ok)
+		return order
+	}
+	getBuildOrder := func(t *testing.T) *model.APIBuildTaskOrder {
+		rh := &buildGetHandler{buildId: "b1"}
+		resp := rh.Run(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		apiBuild, ok := resp.Data().(*model.APIBuild)
+		require.True(t, ok)
+		require.NotNil(t, apiBuild.TaskOrder)
+		return apiBuild.TaskOrder
+	}
+
+	order := getOrder(t)
+	assert.Equal(t, serviceModel.TaskOrderSourceProject, utility.FromStringPtr(order.Source))
+	assert.Equal(t, []string{"lint"}, order.Project.PinnedTasks)
+	assert.Nil(t, order.User)
+
+	buildOrder := getBuildOrder(t)
+	assert.Equal(t, serviceModel.TaskOrderSourceProject, utility.FromStringPtr(buildOrder.Source))
+	assert.Equal(t, []string{"t3", "t1", "t2"}, buildOrder.TaskIds)
+	assert.Equal(t, []string{"t3"}, buildOrder.PinnedTaskIds)
+
+	t.Run("PutRejectsInvalidOrder", func(t *testing.T) {
+		rh := makePutTaskOrder()
+		req, err := http.NewRequest(http.MethodPut, "/projects/p1/task_order", bytes.NewBufferString(`{"sort_by": "duration"}`))
+		require.NoError(t, err)
+		assert.Error(t, rh.Parse(ctx, req))
+	})
+
+	rh := makePutTaskOrder()
+	req, err := http.NewRequest(http.MethodPut, "/projects/p1/task_order", bytes.NewBufferString(`{"sort_by": "status"}`))
+	require.NoError(t, err)
+	require.NoError(t, rh.Parse(ctx, req))
+	resp := rh.Run(ctx)
+	require.Equal(t, http.StatusOK, resp.Status())
+
+	order = getOrder(t)
+	assert.Equal(t, serviceModel.TaskOrderSourceUser, utility.FromStringPtr(order.Source))
+	require.NotNil(t, order.User)
+	assert.Equal(t, serviceModel.TaskSortByStatus, utility.FromStringPtr(order.User.SortBy))
+
+	buildOrder = getBuildOrder(t)
+	assert.Equal(t, serviceModel.TaskOrderSourceUser, utility.FromStringPtr(buildOrder.Source))
+	assert.Equal(t, []string{"t2", "t1", "t3"}, buildOrder.TaskIds)
+	assert.Empty(t, buildOrder.PinnedTaskIds)
+
+	rh = makeDeleteTaskOrder()
+	req, err = http.NewRequest(http.MethodDelete, "/projects/p1/task_order", nil)
+	require.NoError(t, err)
+	require.NoError(t, rh.Parse(ctx, req))
+	resp = rh.Run(ctx)
+	require.Equal(t, http.StatusOK, resp.Status())
+
+	order = getOrder(t)
+	assert.Equal(t, serviceModel.TaskOrderSourceProject, utility.FromStringPtr(order.Source))
+	assert.Nil(t, order.User)
+}
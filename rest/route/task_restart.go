@@ -34,6 +34,17 @@ func (trh *taskRestartHandler) Factory() gimlet.RouteHandler {
 func (trh *taskRestartHandler) Parse(ctx context.Context, r *http.Request) error {
 	projCtx := MustHaveProjectContext(ctx)
 	if projCtx.Task == nil {
+		taskId := gimlet.GetVars(r)["task_id"]
+		archived, err := serviceModel.IsTaskArchived(taskId)
+		if err != nil {
+			return errors.Wrapf(err, "checking if task '%s' is archived", taskId)
+		}
+		if archived {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("task '%s' has been archived to cold storage and can't be restarted", taskId),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
 		return gimlet.ErrorResponse{
 			Message:    "task not found",
 			StatusCode: http.StatusNotFound,
@@ -0,0 +1,93 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+type taskSkipRequest struct {
+	Reason string `json:"reason"`
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/tasks/{task_id}/skip
+
+// taskSkipHandler marks a task that has not been dispatched as intentionally
+// not run, recording why it was skipped.
+type taskSkipHandler struct {
+	taskId string
+	reason string
+}
+
+func makeTaskSkipHandler() gimlet.RouteHandler {
+	return &taskSkipHandler{}
+}
+
+func (h *taskSkipHandler) Factory() gimlet.RouteHandler {
+	return &taskSkipHandler{}
+}
+
+func (h *taskSkipHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.taskId = gimlet.GetVars(r)["task_id"]
+	body := taskSkipRequest{}
+	if err := utility.ReadJSON(r.Body, &body); err != nil {
+		return errors.Wrap(err, "reading skip request from JSON request body")
+	}
+	if body.Reason == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a reason for skipping the task",
+		}
+	}
+	h.reason = body.Reason
+	return nil
+}
+
+func (h *taskSkipHandler) Run(ctx context.Context) gimlet.Responder {
+	t, err := task.FindOneId(h.taskId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", h.taskId))
+	}
+	if t == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found", h.taskId),
+		})
+	}
+	if !t.DisplayOnly && t.Status != evergreen.TaskUndispatched {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("task '%s' has status '%s' and can only be skipped before it is dispatched", h.taskId, t.Status),
+		})
+	}
+
+	if err = serviceModel.SkipTask(h.taskId, h.reason, MustHaveUser(ctx).Id); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "skipping task '%s'", h.taskId))
+	}
+
+	t, err = task.FindOneId(h.taskId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding updated task '%s'", h.taskId))
+	}
+	if t == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found", h.taskId),
+		})
+	}
+	taskModel := &model.APITask{}
+	if err = taskModel.BuildFromArgs(t, &model.APITaskArgs{IncludeProjectIdentifier: true}); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting task '%s' to API model", h.taskId))
+	}
+	return gimlet.NewJSONResponse(taskModel)
+}
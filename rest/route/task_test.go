@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
@@ -156,6 +157,42 @@ func TestFetchArtifacts(t *testing.T) {
 	assert.NotZero(apiTask.PreviousExecutions[0])
 }
 
+func TestGetArchivedTask(t *testing.T) {
+	env := evergreen.GetEnvironment()
+	ctx, cancel := env.Context()
+	defer cancel()
+
+	require.NoError(t, db.ClearCollections(serviceModel.VersionCollection, build.Collection, task.Collection, task.OldCollection, serviceModel.ArchivedBuildsCollection, serviceModel.ArchivedTasksCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(serviceModel.VersionCollection, build.Collection, task.Collection, task.OldCollection, serviceModel.ArchivedBuildsCollection, serviceModel.ArchivedTasksCollection))
+	}()
+
+	v := serviceModel.Version{Id: "v1", Identifier: "p1", Status: evergreen.VersionFailed, BuildIds: []string{"b1"}}
+	require.NoError(t, v.Insert())
+	b := build.Build{Id: "b1", Version: v.Id, Project: "p1"}
+	require.NoError(t, b.Insert())
+	tsk := task.Task{Id: "t1", BuildId: b.Id, Version: v.Id, Project: "p1", Status: evergreen.TaskFailed}
+	require.NoError(t, tsk.Insert())
+	require.NoError(t, tsk.Archive())
+	_, err := serviceModel.ArchiveVersion(ctx, env, &v, time.Now())
+	require.NoError(t, err)
+
+	taskGet := taskGetHandler{taskID: tsk.Id, execution: -1, fetchAllExecutions: true}
+	resp := taskGet.Run(ctx)
+	require.NotNil(t, resp)
+	require.Equal(t, http.StatusOK, resp.Status())
+	apiTask := resp.Data().(*model.APITask)
+	assert.Equal(t, tsk.Id, utility.FromStringPtr(apiTask.Id))
+	assert.Equal(t, 1, apiTask.Execution)
+	require.Len(t, apiTask.PreviousExecutions, 1)
+	assert.Equal(t, 0, apiTask.PreviousExecutions[0].Execution)
+
+	taskGet = taskGetHandler{taskID: "nonexistent", execution: -1}
+	resp = taskGet.Run(ctx)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusNotFound, resp.Status())
+}
+
 func TestGetDisplayTask(t *testing.T) {
 	for testName, testCase := range map[string]func(context.Context, *testing.T){
 		"SucceedsWithTaskInDisplayTask": func(ctx context.Context, t *testing.T) {
@@ -33,6 +33,9 @@ type testGetHandler struct {
 	latest     bool
 
 	task *task.Task
+	// cedarAttachments are the attachments of the test results from Cedar,
+	// keyed by their IDs.
+	cedarAttachments map[string]testresult.TestAttachments
 }
 
 func makeFetchTestsForTask(sc data.Connector) gimlet.RouteHandler {
@@ -143,6 +146,17 @@ func (tgh *testGetHandler) Run(ctx context.Context) gimlet.Responder {
 			key = fmt.Sprintf("%d", page+1)
 		}
 
+		var taskIDs []string
+		for _, result := range cedarTestResults.Results {
+			if !utility.StringSliceContains(taskIDs, result.TaskID) {
+				taskIDs = append(taskIDs, result.TaskID)
+			}
+		}
+		tgh.cedarAttachments, err = testresult.FindAttachmentsForTasks(taskIDs)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting test result attachments"))
+		}
+
 		return tgh.buildResponse(cedarTestResults.Results, nil, key)
 	}
 
@@ -234,6 +248,11 @@ func (tgh *testGetHandler) addDataToResponse(resp gimlet.Responder, testResult i
 			StatusCode: http.StatusInternalServerError,
 		}
 	}
+	if cedarResult, ok := testResult.(*apimodels.CedarTestResult); ok {
+		if a, ok := tgh.cedarAttachments[testresult.AttachmentsID(cedarResult.TaskID, cedarResult.Execution, cedarResult.TestName)]; ok {
+			at.BuildAttachmentsFromService(a.Artifacts, a.TaskLogRange)
+		}
+	}
 
 	if err := resp.AddData(at); err != nil {
 		return gimlet.ErrorResponse{
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
 	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
 	"github.com/pkg/errors"
 )
 
@@ -60,6 +63,24 @@ func (vh *versionHandler) Run(ctx context.Context) gimlet.Responder {
 	if err = versionModel.BuildFromService(foundVersion); err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting version '%s' to API model", foundVersion.Id))
 	}
+	failureGroups, err := dbModel.GetVersionFailureGroups(foundVersion.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting failure groups for version '%s'", foundVersion.Id))
+	}
+	for _, group := range failureGroups {
+		apiGroup := model.APIFailureGroup{}
+		apiGroup.BuildFromService(group)
+		versionModel.FailureGroups = append(versionModel.FailureGroups, apiGroup)
+	}
+	stages, err := dbModel.GetVersionStageStatuses(foundVersion)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting pipeline stage statuses for version '%s'", foundVersion.Id))
+	}
+	for _, stage := range stages {
+		apiStage := model.APIVersionStage{}
+		apiStage.BuildFromService(stage)
+		versionModel.Stages = append(versionModel.Stages, apiStage)
+	}
 	return gimlet.NewJSONResponse(versionModel)
 }
 
@@ -109,6 +130,19 @@ func (h *buildsForVersionHandler) Run(ctx context.Context) gimlet.Responder {
 		})
 	}
 
+	order, source, err := getEffectiveTaskOrder(ctx, foundVersion.Identifier, foundVersion.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting task order for version '%s'", h.versionId))
+	}
+	versionTasks, err := task.FindAll(db.Query(task.ByVersion(foundVersion.Id)).WithFields(task.IdKey, task.DisplayNameKey, task.StatusKey, task.BuildIdKey))
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding tasks for version '%s'", h.versionId))
+	}
+	tasksByBuild := map[string][]task.Task{}
+	for _, t := range versionTasks {
+		tasksByBuild[t.BuildId] = append(tasksByBuild[t.BuildId], t)
+	}
+
 	// Then, find each build variant in the found version by its ID.
 	buildModels := []model.Model{}
 	for _, buildStatus := range foundVersion.BuildVariants {
@@ -120,6 +154,18 @@ func (h *buildsForVersionHandler) Run(ctx context.Context) gimlet.Responder {
 		if err != nil {
 			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding build '%s'", buildStatus.BuildId))
 		}
+		buildTasks := tasksByBuild[buildStatus.BuildId]
+		if foundBuild == nil {
+			// Builds of archived versions are only in cold storage.
+			archived, err := dbModel.FindArchivedBuild(buildStatus.BuildId)
+			if err != nil {
+				return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding archived build '%s'", buildStatus.BuildId))
+			}
+			if archived != nil {
+				foundBuild = &archived.Build
+				buildTasks = archived.Tasks
+			}
+		}
 		if foundBuild == nil {
 			return gimlet.MakeJSONInternalErrorResponder(gimlet.ErrorResponse{
 				StatusCode: http.StatusNotFound,
@@ -131,6 +177,7 @@ func (h *buildsForVersionHandler) Run(ctx context.Context) gimlet.Responder {
 		if err != nil {
 			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting build '%s' to API model", foundBuild.Id))
 		}
+		buildModel.TaskOrder = makeBuildTaskOrder(order, source, *foundBuild, buildTasks)
 
 		buildModels = append(buildModels, buildModel)
 	}
@@ -220,8 +267,19 @@ func (h *versionRestartHandler) Parse(ctx context.Context, r *http.Request) erro
 
 // Execute calls the data RestartVersion function to restart completed tasks of a version.
 func (h *versionRestartHandler) Run(ctx context.Context) gimlet.Responder {
+	v, err := dbModel.VersionFindOneId(h.versionId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version '%s'", h.versionId))
+	}
+	if v != nil && !utility.IsZeroTime(v.ArchivedAt) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("version '%s' has been archived to cold storage and can't be restarted", h.versionId),
+		})
+	}
+
 	// RestartAction the version
-	err := dbModel.RestartTasksInVersion(h.versionId, true, MustHaveUser(ctx).Id)
+	err = dbModel.RestartTasksInVersion(h.versionId, true, MustHaveUser(ctx).Id)
 	if err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "restarting tasks in version '%s'", h.versionId))
 	}
@@ -246,3 +304,249 @@ func (h *versionRestartHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return gimlet.NewJSONResponse(versionModel)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/compliance
+
+type versionComplianceHandler struct {
+	versionID string
+}
+
+func makeGetVersionCompliance() gimlet.RouteHandler {
+	return &versionComplianceHandler{}
+}
+
+func (h *versionComplianceHandler) Factory() gimlet.RouteHandler {
+	return &versionComplianceHandler{}
+}
+
+// Parse fetches the versionId from the http request.
+func (h *versionComplianceHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionID = gimlet.GetVars(r)["version_id"]
+	if h.versionID == "" {
+		return errors.New("missing version ID")
+	}
+	return nil
+}
+
+// Run returns the version's tasks that produce distributables, whether they
+// ran, and the artifacts that they attached.
+func (h *versionComplianceHandler) Run(ctx context.Context) gimlet.Responder {
+	v, err := dbModel.VersionFindOneId(h.versionID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version '%s'", h.versionID))
+	}
+	if v == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' not found", h.versionID),
+		})
+	}
+
+	report, err := dbModel.GetVersionComplianceReport(v.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting compliance report for version '%s'", v.Id))
+	}
+
+	resp := make([]model.APIComplianceReportTask, 0, len(report))
+	for _, reportTask := range report {
+		apiTask := model.APIComplianceReportTask{}
+		apiTask.BuildFromService(reportTask)
+		resp = append(resp, apiTask)
+	}
+	return gimlet.NewJSONResponse(resp)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/baseline_comparison
+
+type versionBaselineComparisonHandler struct {
+	versionId string
+}
+
+func makeGetVersionBaselineComparison() gimlet.RouteHandler {
+	return &versionBaselineComparisonHandler{}
+}
+
+func (h *versionBaselineComparisonHandler) Factory() gimlet.RouteHandler {
+	return &versionBaselineComparisonHandler{}
+}
+
+// Parse fetches the versionId from the http request.
+func (h *versionBaselineComparisonHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionId = gimlet.GetVars(r)["version_id"]
+	if h.versionId == "" {
+		return errors.New("missing version ID")
+	}
+	return nil
+}
+
+// Run compares the outcomes of a finished patch version's tasks and tests
+// against its mainline baseline version.
+func (h *versionBaselineComparisonHandler) Run(ctx context.Context) gimlet.Responder {
+	v, err := dbModel.VersionFindOneId(h.versionId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version '%s'", h.versionId))
+	}
+	if v == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' not found", h.versionId),
+		})
+	}
+	if !evergreen.IsPatchRequester(v.Requester) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("version '%s' is not a patch", h.versionId),
+		})
+	}
+	if !evergreen.IsFinishedVersionStatus(v.Status) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("version '%s' is not finished", h.versionId),
+		})
+	}
+
+	comparison, err := dbModel.CompareToBaseline(v)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "comparing version '%s' to its baseline", h.versionId))
+	}
+
+	apiComparison := model.APIBaselineComparison{}
+	apiComparison.BuildFromService(*comparison)
+	return gimlet.NewJSONResponse(apiComparison)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/changelog
+
+type versionChangelogHandler struct {
+	versionId     string
+	baseVersionId string
+}
+
+func makeGetVersionChangelog() gimlet.RouteHandler {
+	return &versionChangelogHandler{}
+}
+
+func (h *versionChangelogHandler) Factory() gimlet.RouteHandler {
+	return &versionChangelogHandler{}
+}
+
+// Parse fetches the versionId and the optional base version ID from the http
+// request.
+func (h *versionChangelogHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionId = gimlet.GetVars(r)["version_id"]
+	if h.versionId == "" {
+		return errors.New("missing version ID")
+	}
+	h.baseVersionId = r.URL.Query().Get("base_version_id")
+	return nil
+}
+
+// Run returns the commits, module revisions, task outcomes, and project config
+// that changed between a mainline version and the base version, which defaults
+// to the previous mainline version.
+func (h *versionChangelogHandler) Run(ctx context.Context) gimlet.Responder {
+	v, err := dbModel.VersionFindOneId(h.versionId)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version '%s'", h.versionId))
+	}
+	if v == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' not found", h.versionId),
+		})
+	}
+	if !utility.StringSliceContains(evergreen.SystemVersionRequesterTypes, v.Requester) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("version '%s' is not a mainline version", h.versionId),
+		})
+	}
+
+	var baseVersion *dbModel.Version
+	if h.baseVersionId != "" {
+		baseVersion, err = dbModel.VersionFindOneId(h.baseVersionId)
+	} else {
+		baseVersion, err = dbModel.FindPreviousMainlineVersion(v)
+	}
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding base version for version '%s'", h.versionId))
+	}
+	if baseVersion == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("base version for version '%s' not found", h.versionId),
+		})
+	}
+	if !utility.StringSliceContains(evergreen.SystemVersionRequesterTypes, baseVersion.Requester) ||
+		baseVersion.Identifier != v.Identifier ||
+		baseVersion.RevisionOrderNumber >= v.RevisionOrderNumber {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("base version '%s' must be an older mainline version of the same project as version '%s'", baseVersion.Id, h.versionId),
+		})
+	}
+
+	changelog, err := dbModel.GetVersionChangelog(v, baseVersion)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting changelog for version '%s'", h.versionId))
+	}
+
+	apiChangelog := model.APIVersionChangelog{}
+	apiChangelog.BuildFromService(*changelog)
+	return gimlet.NewJSONResponse(apiChangelog)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/critical_path
+
+type versionCriticalPathHandler struct {
+	versionID string
+}
+
+func makeGetVersionCriticalPath() gimlet.RouteHandler {
+	return &versionCriticalPathHandler{}
+}
+
+func (h *versionCriticalPathHandler) Factory() gimlet.RouteHandler {
+	return &versionCriticalPathHandler{}
+}
+
+// Parse fetches the versionId from the http request.
+func (h *versionCriticalPathHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionID = gimlet.GetVars(r)["version_id"]
+	if h.versionID == "" {
+		return errors.New("missing version ID")
+	}
+	return nil
+}
+
+// Run returns the chain of tasks across the version's builds that determined
+// how long the version took to finish.
+func (h *versionCriticalPathHandler) Run(ctx context.Context) gimlet.Responder {
+	v, err := dbModel.VersionFindOneId(h.versionID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version '%s'", h.versionID))
+	}
+	if v == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' not found", h.versionID),
+		})
+	}
+
+	criticalPath, err := dbModel.GetVersionCriticalPath(v.Id)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting critical path for version '%s'", v.Id))
+	}
+
+	apiCriticalPath := model.APICriticalPath{}
+	apiCriticalPath.BuildFromService(*criticalPath)
+	return gimlet.NewJSONResponse(apiCriticalPath)
+}
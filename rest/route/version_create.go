@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/gimlet"
@@ -18,11 +19,13 @@ func makeVersionCreateHandler() gimlet.RouteHandler {
 }
 
 type versionCreateHandler struct {
-	ProjectID string          `json:"project_id"`
-	Message   string          `json:"message"`
-	Active    bool            `json:"activate"`
-	IsAdHoc   bool            `json:"is_adhoc"`
-	Config    json.RawMessage `json:"config"`
+	ProjectID          string            `json:"project_id"`
+	Message            string            `json:"message"`
+	Active             bool              `json:"activate"`
+	IsAdHoc            bool              `json:"is_adhoc"`
+	Config             json.RawMessage   `json:"config"`
+	Labels             map[string]string `json:"labels"`
+	ExpansionOverrides map[string]string `json:"expansion_overrides"`
 
 	sc data.Connector
 }
@@ -36,15 +39,23 @@ func (h *versionCreateHandler) Parse(ctx context.Context, r *http.Request) error
 	if err != nil {
 		return errors.Wrap(err, "reading version creation options from JSON request body")
 	}
+	if err = patch.ValidateLabels(patch.LabelsFromMap(h.Labels)); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid labels").Error(),
+		}
+	}
 	return nil
 }
 
 func (h *versionCreateHandler) Run(ctx context.Context) gimlet.Responder {
 	u := gimlet.GetUser(ctx).(*user.DBUser)
 	metadata := model.VersionMetadata{
-		Message: h.Message,
-		IsAdHoc: h.IsAdHoc,
-		User:    u,
+		Message:            h.Message,
+		IsAdHoc:            h.IsAdHoc,
+		User:               u,
+		Labels:             patch.LabelsFromMap(h.Labels),
+		ExpansionOverrides: h.ExpansionOverrides,
 	}
 	projectInfo := &model.ProjectInfo{}
 	var err error
@@ -55,6 +66,12 @@ func (h *versionCreateHandler) Run(ctx context.Context) gimlet.Responder {
 	if projectInfo.Ref == nil {
 		return gimlet.NewJSONErrorResponse(errors.Errorf("project '%s' not found", h.ProjectID))
 	}
+	if err = projectInfo.Ref.ValidateExpansionOverrides(h.ExpansionOverrides); err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid expansion overrides").Error(),
+		})
+	}
 	p := &model.Project{}
 	opts := &model.GetProjectOpts{
 		Ref:          projectInfo.Ref,
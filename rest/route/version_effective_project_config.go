@@ -0,0 +1,54 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/effective_project_config
+
+type versionEffectiveProjectConfigHandler struct {
+	version *dbModel.Version
+}
+
+func makeGetVersionEffectiveProjectConfig() gimlet.RouteHandler {
+	return &versionEffectiveProjectConfigHandler{}
+}
+
+func (h *versionEffectiveProjectConfigHandler) Factory() gimlet.RouteHandler {
+	return &versionEffectiveProjectConfigHandler{}
+}
+
+func (h *versionEffectiveProjectConfigHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.version = MustHaveProjectContext(ctx).Version
+	if h.version == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "version not found",
+		}
+	}
+	return nil
+}
+
+// Run returns the project settings that applied to the version, merged from
+// the branch project, its repo and the version's project config, along with
+// where each setting came from.
+func (h *versionEffectiveProjectConfigHandler) Run(ctx context.Context) gimlet.Responder {
+	config, err := dbModel.GetEffectiveProjectConfig(h.version)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting effective project config for version '%s'", h.version.Id))
+	}
+
+	resp := model.APIEffectiveProjectConfig{}
+	if err = resp.BuildFromService(*config); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "converting effective project config to API model"))
+	}
+	return gimlet.NewJSONResponse(resp)
+}
@@ -0,0 +1,135 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+///////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/gates
+
+type versionExternalGatesGetHandler struct {
+	versionID string
+}
+
+func makeGetVersionExternalGates() gimlet.RouteHandler {
+	return &versionExternalGatesGetHandler{}
+}
+
+func (h *versionExternalGatesGetHandler) Factory() gimlet.RouteHandler {
+	return &versionExternalGatesGetHandler{}
+}
+
+func (h *versionExternalGatesGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionID = gimlet.GetVars(r)["version_id"]
+	return nil
+}
+
+// Run returns the state of each of the version's external gates.
+func (h *versionExternalGatesGetHandler) Run(ctx context.Context) gimlet.Responder {
+	v, err := dbModel.VersionFindOneId(h.versionID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version '%s'", h.versionID))
+	}
+	if v == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' not found", h.versionID),
+		})
+	}
+
+	now := time.Now()
+	apiGates := []model.APIVersionExternalGate{}
+	for _, gate := range v.ExternalGates {
+		apiGate := model.APIVersionExternalGate{}
+		apiGate.BuildFromService(gate, now)
+		apiGates = append(apiGates, apiGate)
+	}
+	return gimlet.NewJSONResponse(apiGates)
+}
+
+///////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/versions/{version_id}/gates/{gate_name}/open
+
+type versionExternalGateOpenHandler struct {
+	versionID string
+	gateName  string
+}
+
+func makeOpenVersionExternalGate() gimlet.RouteHandler {
+	return &versionExternalGateOpenHandler{}
+}
+
+func (h *versionExternalGateOpenHandler) Factory() gimlet.RouteHandler {
+	return &versionExternalGateOpenHandler{}
+}
+
+func (h *versionExternalGateOpenHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.versionID = vars["version_id"]
+	h.gateName = vars["gate_name"]
+	if h.gateName == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a gate name",
+		}
+	}
+	return nil
+}
+
+// Run opens the version's external gate so that the tasks it holds can be
+// dispatched, and returns the gate's state.
+func (h *versionExternalGateOpenHandler) Run(ctx context.Context) gimlet.Responder {
+	v, err := dbModel.VersionFindOneId(h.versionID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version '%s'", h.versionID))
+	}
+	if v == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' not found", h.versionID),
+		})
+	}
+	if v.GetExternalGate(h.gateName) == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' has no external gate '%s'", h.versionID, h.gateName),
+		})
+	}
+
+	user := MustHaveUser(ctx).Username()
+	if err = dbModel.OpenVersionExternalGate(h.versionID, h.gateName, user); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	grip.Info(message.Fields{
+		"message": "opened version external gate",
+		"version": h.versionID,
+		"gate":    h.gateName,
+		"user":    user,
+	})
+
+	v, err = dbModel.VersionFindOneId(h.versionID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding version '%s'", h.versionID))
+	}
+	if v == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' not found", h.versionID),
+		})
+	}
+	apiGate := model.APIVersionExternalGate{}
+	apiGate.BuildFromService(*v.GetExternalGate(h.gateName), time.Now())
+	return gimlet.NewJSONResponse(apiGate)
+}
@@ -0,0 +1,152 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+///////////////////////////////////////////////////////////////////////
+//
+// PATCH /rest/v2/versions/{version_id}/labels
+
+type versionLabelsUpdateHandler struct {
+	versionID string
+	Set       map[string]string `json:"set"`
+	Remove    []string          `json:"remove"`
+}
+
+func makeUpdateVersionLabels() gimlet.RouteHandler {
+	return &versionLabelsUpdateHandler{}
+}
+
+func (h *versionLabelsUpdateHandler) Factory() gimlet.RouteHandler {
+	return &versionLabelsUpdateHandler{}
+}
+
+// Parse fetches the version ID and the labels to set and remove from the
+// http request.
+func (h *versionLabelsUpdateHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionID = gimlet.GetVars(r)["version_id"]
+	if err := utility.ReadJSON(r.Body, h); err != nil {
+		return errors.Wrap(err, "reading label changes from JSON request body")
+	}
+	if len(h.Set) == 0 && len(h.Remove) == 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify at least one label to set or remove",
+		}
+	}
+	return nil
+}
+
+// Run updates the labels on the version and its patch and returns the
+// updated labels.
+func (h *versionLabelsUpdateHandler) Run(ctx context.Context) gimlet.Responder {
+	labels, status, err := dbModel.UpdateVersionLabels(h.versionID, h.Set, h.Remove)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: status,
+			Message:    err.Error(),
+		})
+	}
+	grip.Info(message.Fields{
+		"message": "updated version labels",
+		"version": h.versionID,
+		"user":    MustHaveUser(ctx).Username(),
+		"set":     h.Set,
+		"remove":  h.Remove,
+	})
+
+	apiLabels := []model.APILabel{}
+	for _, l := range labels {
+		apiLabels = append(apiLabels, model.APILabel{
+			Key:   utility.ToStringPtr(l.Key),
+			Value: utility.ToStringPtr(l.Value),
+		})
+	}
+	return gimlet.NewJSONResponse(apiLabels)
+}
+
+///////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/builds
+
+type projectBuildsByLabelHandler struct {
+	projectName string
+	opts        dbModel.GetVersionsOptions
+}
+
+func makeGetProjectBuildsByLabel() gimlet.RouteHandler {
+	return &projectBuildsByLabelHandler{}
+}
+
+func (h *projectBuildsByLabelHandler) Factory() gimlet.RouteHandler {
+	return &projectBuildsByLabelHandler{}
+}
+
+// Parse fetches the project and the labels, variant, requester, and limit to
+// filter builds by from the http request.
+func (h *projectBuildsByLabelHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectName = gimlet.GetVars(r)["project_id"]
+	params := r.URL.Query()
+
+	labels := params.Get("labels")
+	if labels == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify labels to filter builds by",
+		}
+	}
+	var err error
+	h.opts.Labels, err = patch.ParseLabelSelector(labels)
+	if err != nil {
+		return errors.Wrap(err, "invalid labels query parameter")
+	}
+
+	h.opts.ByBuildVariant = params.Get("variant")
+	h.opts.Requester = params.Get("requester")
+	if h.opts.Requester == "" {
+		h.opts.Requester = evergreen.RepotrackerVersionRequester
+	}
+	h.opts.Limit = defaultVersionLimit
+	if limitStr := params.Get("limit"); limitStr != "" {
+		h.opts.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return errors.Wrap(err, "invalid limit")
+		}
+		if h.opts.Limit < 1 {
+			return errors.New("limit must be a positive integer")
+		}
+	}
+	return nil
+}
+
+// Run returns the builds of the project's most recent versions that have all
+// of the labels. The limit applies to the number of versions.
+func (h *projectBuildsByLabelHandler) Run(ctx context.Context) gimlet.Responder {
+	builds, err := dbModel.FindBuildsByVersionLabels(h.projectName, h.opts)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding builds by label for project '%s'", h.projectName))
+	}
+
+	buildModels := []model.Model{}
+	for _, b := range builds {
+		buildModel := &model.APIBuild{}
+		if err = buildModel.BuildFromService(b); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "converting build '%s' to API model", b.Id))
+		}
+		buildModels = append(buildModels, buildModel)
+	}
+	return gimlet.NewJSONResponse(buildModels)
+}
@@ -0,0 +1,77 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxSnapshotClockSkew is how far in the future a requested snapshot time
+// can be, since reading as of a time the database hasn't reached yet waits
+// for it.
+const maxSnapshotClockSkew = time.Minute
+
+///////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/snapshot
+
+type versionSnapshotHandler struct {
+	versionID string
+	asOf      *primitive.Timestamp
+}
+
+func makeGetVersionSnapshot() gimlet.RouteHandler {
+	return &versionSnapshotHandler{}
+}
+
+func (h *versionSnapshotHandler) Factory() gimlet.RouteHandler {
+	return &versionSnapshotHandler{}
+}
+
+func (h *versionSnapshotHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionID = gimlet.GetVars(r)["version_id"]
+	if asOf := r.URL.Query().Get("as_of"); asOf != "" {
+		var err error
+		h.asOf, err = dbModel.ParseSnapshotTime(asOf)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    errors.Wrap(err, "parsing as_of").Error(),
+			}
+		}
+		if time.Unix(int64(h.asOf.T), 0).After(time.Now().Add(maxSnapshotClockSkew)) {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("snapshot time '%s' is in the future", asOf),
+			}
+		}
+	}
+	return nil
+}
+
+// Run returns the statuses of the version and its builds and tasks as of a
+// single point in time, so that polling clients never see a task's update
+// without its build's and version's.
+func (h *versionSnapshotHandler) Run(ctx context.Context) gimlet.Responder {
+	snapshot, err := dbModel.GetVersionSnapshot(ctx, h.versionID, h.asOf)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting snapshot of version '%s'", h.versionID))
+	}
+	if snapshot == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' not found", h.versionID),
+		})
+	}
+
+	apiSnapshot := model.APIVersionSnapshot{}
+	apiSnapshot.BuildFromService(*snapshot)
+	return gimlet.NewJSONResponse(apiSnapshot)
+}
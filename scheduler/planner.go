@@ -11,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 )
@@ -364,6 +365,10 @@ func (tl TaskList) Less(i, j int) bool {
 		return t1.TaskGroupOrder < t2.TaskGroupOrder
 	}
 
+	if inSameLongestFirstDisplayTask(t1, t2) {
+		return t1.FetchExpectedDuration().Average > t2.FetchExpectedDuration().Average
+	}
+
 	if t1.NumDependents != t2.NumDependents {
 		return t1.NumDependents > t2.NumDependents
 	}
@@ -403,6 +408,12 @@ func PrepareTasksForPlanning(distro *distro.Distro, tasks []task.Task) TaskPlan
 			unit = cache.Create(t.GetTaskGroupString(), t)
 			cache.AddNew(t.Id, unit)
 			cache.AddWhen(distro.PlannerSettings.ShouldGroupVersions(), t.Version, t)
+		} else if t.DispatchLongestFirst && utility.FromStringPtr(t.DisplayTaskId) != "" {
+			// keep the execution tasks of the display task together so
+			// that they are dispatched longest first.
+			unit = cache.Create(utility.FromStringPtr(t.DisplayTaskId), t)
+			cache.AddNew(t.Id, unit)
+			cache.AddWhen(distro.PlannerSettings.ShouldGroupVersions(), t.Version, t)
 		} else if distro.PlannerSettings.ShouldGroupVersions() {
 			unit = cache.Create(t.Version, t)
 			cache.AddNew(t.Id, unit)
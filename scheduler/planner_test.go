@@ -10,6 +10,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
@@ -347,6 +348,24 @@ func TestPlanner(t *testing.T) {
 
 				sort.Sort(plan)
 
+				assert.Equal(t, "first", plan[0].Id)
+				assert.Equal(t, "second", plan[1].Id)
+			})
+			t.Run("LongestFirstWithinDisplayTask", func(t *testing.T) {
+				plan := TaskList{
+					{Id: "second", NumDependents: 2, DisplayTaskId: utility.ToStringPtr("dt"), DispatchLongestFirst: true},
+					{Id: "first", DisplayTaskId: utility.ToStringPtr("dt"), DispatchLongestFirst: true},
+				}
+				plan[1].DurationPrediction.Value = time.Hour
+				plan[1].DurationPrediction.TTL = time.Hour * 24
+				plan[1].DurationPrediction.CollectedAt = time.Now()
+
+				plan[0].DurationPrediction.Value = time.Minute
+				plan[0].DurationPrediction.TTL = time.Hour * 24
+				plan[0].DurationPrediction.CollectedAt = time.Now()
+
+				sort.Sort(plan)
+
 				assert.Equal(t, "first", plan[0].Id)
 				assert.Equal(t, "second", plan[1].Id)
 			})
@@ -366,6 +385,16 @@ func TestPlanner(t *testing.T) {
 			assert.Len(t, plan, 2)
 			assert.Len(t, plan.Export(), 3)
 		})
+		t.Run("LongestFirstDisplayTasksGrouped", func(t *testing.T) {
+			plan := PrepareTasksForPlanning(&distro.Distro{}, []task.Task{
+				{Id: "one", DisplayTaskId: utility.ToStringPtr("dt"), DispatchLongestFirst: true},
+				{Id: "two", DisplayTaskId: utility.ToStringPtr("dt"), DispatchLongestFirst: true},
+				{Id: "three", DisplayTaskId: utility.ToStringPtr("dt")},
+			})
+
+			assert.Len(t, plan, 2)
+			assert.Len(t, plan.Export(), 3)
+		})
 		t.Run("VersionsGrouped", func(t *testing.T) {
 			plan := PrepareTasksForPlanning(&distro.Distro{
 				PlannerSettings: distro.PlannerSettings{
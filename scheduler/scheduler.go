@@ -26,6 +26,22 @@ type TaskPlanner func(*distro.Distro, []task.Task, TaskPlannerOptions) ([]task.T
 func PrioritizeTasks(d *distro.Distro, tasks []task.Task, opts TaskPlannerOptions) ([]task.Task, error) {
 	opts.IncludesDependencies = d.DispatcherSettings.Version == evergreen.DispatcherVersionRevisedWithDependencies
 
+	now := time.Now()
+
+	// Tasks held by external gates that have not been opened yet are not
+	// planned until their gates open.
+	tasks, err := model.FilterExternallyGatedTasks(tasks, now)
+	if err != nil {
+		return nil, errors.Wrap(err, "filtering externally gated tasks")
+	}
+
+	// Tasks are planned by their effective priority so that tasks from
+	// projects with priority aging are not starved.
+	tasks, err = model.ApplyPriorityAging(tasks, now)
+	if err != nil {
+		return nil, errors.Wrap(err, "applying priority aging")
+	}
+
 	switch d.PlannerSettings.Version {
 	case evergreen.PlannerVersionTunable:
 		return runTunablePlanner(d, tasks, opts)
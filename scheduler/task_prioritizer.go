@@ -55,6 +55,7 @@ func NewCmpBasedTaskComparator(id string) *CmpBasedTaskComparator {
 		},
 		comparators: []taskComparer{
 			&byTaskGroupOrder{},
+			&byDisplayTaskRuntime{},
 			&byCommitQueue{},
 			&byPriority{},
 			&byNumDeps{},
@@ -162,6 +162,41 @@ func (c *byTaskGroupOrder) compare(t1, t2 task.Task, _ *CmpBasedTaskComparator)
 	return -1, reason, nil
 }
 
+// byDisplayTaskRuntime takes two execution tasks in the same display task
+// that dispatches its longest execution tasks first and considers the one
+// that we expect to take longer more important, to minimize the wall time of
+// the display task.
+type byDisplayTaskRuntime struct{}
+
+func (c *byDisplayTaskRuntime) name() string { return "expected runtime within display task" }
+func (c *byDisplayTaskRuntime) compare(t1, t2 task.Task, _ *CmpBasedTaskComparator) (int, string, error) {
+	if !inSameLongestFirstDisplayTask(t1, t2) {
+		return 0, "", nil
+	}
+
+	oneExpected := t1.FetchExpectedDuration().Average
+	twoExpected := t2.FetchExpectedDuration().Average
+	if oneExpected == twoExpected {
+		return 0, "", nil
+	}
+
+	reason := fmt.Sprintf("in the same display task, %s is %s; %s is %s", t1.Id, oneExpected.String(), t2.Id, twoExpected.String())
+	if oneExpected > twoExpected {
+		return 1, reason, nil
+	}
+	return -1, reason, nil
+}
+
+// inSameLongestFirstDisplayTask returns whether both tasks are execution tasks
+// in the same display task that dispatches its longest tasks first.
+func inSameLongestFirstDisplayTask(t1, t2 task.Task) bool {
+	if !t1.DispatchLongestFirst || !t2.DispatchLongestFirst {
+		return false
+	}
+	displayTaskID := utility.FromStringPtr(t1.DisplayTaskId)
+	return displayTaskID != "" && displayTaskID == utility.FromStringPtr(t2.DisplayTaskId)
+}
+
 // byGenerateTasks schedules tasks that generate tasks ahead of tasks that do not.
 type byGenerateTasks struct{}
 
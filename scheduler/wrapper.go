@@ -88,6 +88,16 @@ func PlanDistro(ctx context.Context, conf Configuration, s *evergreen.Settings)
 	if err != nil {
 		return errors.Wrapf(err, "problem while running task finder for distro '%s'", distro.Id)
 	}
+	// Quarantined tasks are skipped rather than scheduled.
+	tasks, err = model.SkipQuarantinedTasks(tasks)
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":  "could not skip quarantined tasks",
+			"runner":   RunnerName,
+			"distro":   distro.Id,
+			"instance": schedulerInstanceID,
+		}))
+	}
 	grip.Info(message.Fields{
 		"runner":        RunnerName,
 		"distro":        distro.Id,
@@ -332,7 +332,8 @@ db.old_tasks.ensureIndex({
     "build_variant": 1
 })
 db.old_tasks.ensureIndex({
-    "old_task_id": 1
+    "old_task_id": 1,
+    "execution": 1
 })
 db.old_tasks.ensureIndex({
     "branch": 1,
@@ -342,6 +343,12 @@ db.old_tasks.createIndex({
     "execution_tasks": 1
 })
 
+//======archived_tasks======//
+db.archived_tasks.createIndex({
+    "build_id": 1,
+    "_id": 1
+})
+
 //======versions======//
 db.versions.ensureIndex({
     "order": 1
@@ -381,6 +388,11 @@ db.versions.createIndex({
     "build_variants_status.build_variant": "1",
     "build_variants_status.activated": "1"
 })
+db.versions.createIndex({
+    "identifier": 1,
+    "labels.key": 1,
+    "labels.value": 1
+})
 
 //======test_logs=====//
 db.test_logs.ensureIndex({
@@ -544,6 +556,17 @@ db.daily_task_stats.createIndex({
     "_id.date": 1
 })
 
+//======test_flakiness======//
+db.test_flakiness.createIndex({
+    "expires_at": 1
+}, {
+    expireAfterSeconds: 0
+})
+db.test_flakiness.createIndex({
+    "_id.project": 1,
+    "score": -1
+})
+
 //======manifest======//
 db.manifest.createIndex({
     "project": 1,
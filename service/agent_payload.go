@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxAttachResultsBytes       = 16 * 1024 * 1024
+	maxAttachFilesBytes         = 16 * 1024 * 1024
+	maxAppendTaskLogBytes       = 16 * 1024 * 1024
+	maxSetDownstreamParamsBytes = 256 * 1024
+
+	maxTestResultsPerRequest      = 100000
+	maxLogMessagesPerRequest      = 10000
+	maxDownstreamParamsPerRequest = 100
+)
+
+// agentPayloadSchema describes the request bodies that an agent endpoint
+// accepts.
+type agentPayloadSchema struct {
+	// endpoint names the endpoint in rejections.
+	endpoint string
+	// maxBytes is the largest request body that the endpoint accepts.
+	maxBytes int64
+	// newPayload returns a pointer to a new value to decode the request body
+	// into.
+	newPayload func() interface{}
+	// validate checks that the decoded request body is well-formed.
+	validate func(payload interface{}) error
+}
+
+var (
+	attachResultsSchema = agentPayloadSchema{
+		endpoint:   "AttachResults",
+		maxBytes:   maxAttachResultsBytes,
+		newPayload: func() interface{} { return &task.LocalTestResults{} },
+		validate: func(payload interface{}) error {
+			results := payload.(*task.LocalTestResults)
+			if len(results.Results) > maxTestResultsPerRequest {
+				return errors.Errorf("cannot attach more than %d test results at once", maxTestResultsPerRequest)
+			}
+			catcher := grip.NewBasicCatcher()
+			for i, result := range results.Results {
+				catcher.Wrapf(testresult.ValidateAttachments(result.Artifacts, result.TaskLogRange), "invalid attachments for test result %d", i)
+			}
+			return catcher.Resolve()
+		},
+	}
+	attachFilesSchema = agentPayloadSchema{
+		endpoint:   "AttachFiles",
+		maxBytes:   maxAttachFilesBytes,
+		newPayload: func() interface{} { return &[]artifact.File{} },
+		validate: func(payload interface{}) error {
+			return validateAttachedFiles(*payload.(*[]artifact.File))
+		},
+	}
+	attachFilesForTasksSchema = agentPayloadSchema{
+		endpoint:   "AttachFilesForTasks",
+		maxBytes:   maxAttachFilesBytes,
+		newPayload: func() interface{} { return &[]artifact.TaskFiles{} },
+		validate: func(payload interface{}) error {
+			batch := *payload.(*[]artifact.TaskFiles)
+			catcher := grip.NewBasicCatcher()
+			for i, taskFiles := range batch {
+				catcher.ErrorfWhen(taskFiles.TaskID == "", "entry %d must have a task ID", i)
+				catcher.Wrapf(validateAttachedFiles(taskFiles.Files), "invalid files for task '%s'", taskFiles.TaskID)
+			}
+			return catcher.Resolve()
+		},
+	}
+	appendTaskLogSchema = agentPayloadSchema{
+		endpoint:   "AppendTaskLog",
+		maxBytes:   maxAppendTaskLogBytes,
+		newPayload: func() interface{} { return &model.TaskLog{} },
+		validate: func(payload interface{}) error {
+			taskLog := payload.(*model.TaskLog)
+			if len(taskLog.Messages) > maxLogMessagesPerRequest {
+				return errors.Errorf("cannot append more than %d log messages at once", maxLogMessagesPerRequest)
+			}
+			return nil
+		},
+	}
+	setDownstreamParamsSchema = agentPayloadSchema{
+		endpoint:   "SetDownstreamParams",
+		maxBytes:   maxSetDownstreamParamsBytes,
+		newPayload: func() interface{} { return &[]patch.Parameter{} },
+		validate: func(payload interface{}) error {
+			params := *payload.(*[]patch.Parameter)
+			if len(params) > maxDownstreamParamsPerRequest {
+				return errors.Errorf("cannot set more than %d downstream parameters", maxDownstreamParamsPerRequest)
+			}
+			catcher := grip.NewBasicCatcher()
+			for i, param := range params {
+				catcher.ErrorfWhen(param.Key == "", "downstream parameter %d must have a key", i)
+			}
+			return catcher.Resolve()
+		},
+	}
+)
+
+func validateAttachedFiles(files []artifact.File) error {
+	catcher := grip.NewBasicCatcher()
+	for i, file := range files {
+		catcher.ErrorfWhen(file.Name == "", "file %d must have a name", i)
+		catcher.ErrorfWhen(file.Link == "", "file '%s' must have a link", file.Name)
+		catcher.ErrorfWhen(!utility.StringSliceContains(artifact.ValidVisibilities, file.Visibility), "file '%s' has invalid visibility '%s'", file.Name, file.Visibility)
+	}
+	return catcher.Resolve()
+}
+
+// agentPayloadStats counts the agent request payloads that each endpoint
+// accepted and rejected since the app server started.
+type agentPayloadStats struct {
+	mu       sync.Mutex
+	accepted map[string]int64
+	rejected map[string]map[int]int64
+}
+
+// agentPayloadEndpointStats are the payload counts for a single endpoint.
+type agentPayloadEndpointStats struct {
+	Endpoint string        `json:"endpoint"`
+	Accepted int64         `json:"accepted"`
+	Rejected map[int]int64 `json:"rejected_by_status,omitempty"`
+}
+
+var payloadStats = &agentPayloadStats{
+	accepted: map[string]int64{},
+	rejected: map[string]map[int]int64{},
+}
+
+func (s *agentPayloadStats) recordAccepted(endpoint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accepted[endpoint]++
+}
+
+func (s *agentPayloadStats) recordRejected(endpoint string, code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rejected[endpoint] == nil {
+		s.rejected[endpoint] = map[int]int64{}
+	}
+	s.rejected[endpoint][code]++
+}
+
+// get returns a snapshot of the counts for every endpoint that has received
+// a payload, sorted by endpoint.
+func (s *agentPayloadStats) get() []agentPayloadEndpointStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byEndpoint := map[string]*agentPayloadEndpointStats{}
+	getEndpoint := func(endpoint string) *agentPayloadEndpointStats {
+		if _, ok := byEndpoint[endpoint]; !ok {
+			byEndpoint[endpoint] = &agentPayloadEndpointStats{Endpoint: endpoint}
+		}
+		return byEndpoint[endpoint]
+	}
+	for endpoint, count := range s.accepted {
+		getEndpoint(endpoint).Accepted = count
+	}
+	for endpoint, counts := range s.rejected {
+		stats := getEndpoint(endpoint)
+		stats.Rejected = map[int]int64{}
+		for code, count := range counts {
+			stats.Rejected[code] = count
+		}
+	}
+
+	out := make([]agentPayloadEndpointStats, 0, len(byEndpoint))
+	for _, stats := range byEndpoint {
+		out = append(out, *stats)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Endpoint < out[j].Endpoint })
+	return out
+}
+
+// agentPayloadStatus returns the number of agent request payloads that each
+// endpoint accepted and rejected.
+func (as *APIServer) agentPayloadStatus(w http.ResponseWriter, r *http.Request) {
+	gimlet.WriteJSON(w, payloadStats.get())
+}
+
+// agentPayloadRejection is the response to an agent request whose body is too
+// large or does not match the endpoint's schema.
+type agentPayloadRejection struct {
+	StatusCode int    `json:"status"`
+	Endpoint   string `json:"endpoint"`
+	Message    string `json:"error"`
+	MaxBytes   int64  `json:"max_bytes,omitempty"`
+}
+
+// requireAgentPayload rejects request bodies that are larger than the
+// endpoint's limit with a 413 and bodies that are malformed or do not match
+// the endpoint's schema with a 422. Accepted bodies are decoded and attached
+// to the request for the handler to get with mustHaveAgentPayload.
+func (as *APIServer) requireAgentPayload(schema agentPayloadSchema) gimlet.Middleware {
+	return gimlet.WrapperMiddleware(func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			reject := func(code int, numBytes int64, err error) {
+				payloadStats.recordRejected(schema.endpoint, code)
+				grip.Warning(message.WrapError(err, message.Fields{
+					"message":   "rejected agent request payload",
+					"endpoint":  schema.endpoint,
+					"code":      code,
+					"num_bytes": numBytes,
+					"max_bytes": schema.maxBytes,
+					"task":      gimlet.GetVars(r)["taskId"],
+					"request":   gimlet.GetRequestID(r.Context()),
+				}))
+				rejection := agentPayloadRejection{
+					StatusCode: code,
+					Endpoint:   schema.endpoint,
+					Message:    err.Error(),
+				}
+				if code == http.StatusRequestEntityTooLarge {
+					rejection.MaxBytes = schema.maxBytes
+				}
+				gimlet.WriteJSONResponse(w, code, rejection)
+			}
+
+			if r.ContentLength > schema.maxBytes {
+				reject(http.StatusRequestEntityTooLarge, r.ContentLength, errors.Errorf("request body cannot be larger than %d bytes", schema.maxBytes))
+				return
+			}
+			defer r.Body.Close()
+			body, err := ioutil.ReadAll(io.LimitReader(r.Body, schema.maxBytes+1))
+			if err != nil {
+				as.LoggedError(w, r, http.StatusBadRequest, errors.Wrap(err, "reading request body"))
+				return
+			}
+			if int64(len(body)) > schema.maxBytes {
+				reject(http.StatusRequestEntityTooLarge, int64(len(body)), errors.Errorf("request body cannot be larger than %d bytes", schema.maxBytes))
+				return
+			}
+
+			payload := schema.newPayload()
+			if err = json.Unmarshal(body, payload); err != nil {
+				reject(http.StatusUnprocessableEntity, int64(len(body)), errors.Wrap(err, "request body is not valid JSON for the endpoint"))
+				return
+			}
+			if err = schema.validate(payload); err != nil {
+				reject(http.StatusUnprocessableEntity, int64(len(body)), errors.Wrap(err, "request body does not match the endpoint's schema"))
+				return
+			}
+
+			payloadStats.recordAccepted(schema.endpoint)
+			next(w, r.WithContext(context.WithValue(r.Context(), RequestAgentPayload, payload)))
+		}
+	})
+}
+
+// mustHaveAgentPayload returns the decoded request body attached by
+// requireAgentPayload. Panics if there is none.
+func mustHaveAgentPayload(r *http.Request) interface{} {
+	payload := r.Context().Value(RequestAgentPayload)
+	if payload == nil {
+		panic(fmt.Sprintf("no agent payload attached to request '%s'", r.URL.Path))
+	}
+	return payload
+}
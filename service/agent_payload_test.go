@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireAgentPayload(t *testing.T) {
+	env := evergreen.GetEnvironment()
+	as, err := NewAPIServer(env, env.LocalQueue())
+	require.NoError(t, err)
+
+	var attached []artifact.File
+	app := gimlet.NewApp()
+	app.NoVersions = true
+	app.AddRoute("/{taskId}/files").Wrap(as.requireAgentPayload(attachFilesSchema)).Handler(func(w http.ResponseWriter, r *http.Request) {
+		attached = *mustHaveAgentPayload(r).(*[]artifact.File)
+		gimlet.WriteJSON(w, nil)
+	}).Post()
+	handler, err := app.Handler()
+	require.NoError(t, err)
+
+	post := func(t *testing.T, body []byte) (*httptest.ResponseRecorder, agentPayloadRejection) {
+		attached = nil
+		r, err := http.NewRequest(http.MethodPost, "/t1/files", bytes.NewReader(body))
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		rejection := agentPayloadRejection{}
+		if w.Code != http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rejection))
+		}
+		return w, rejection
+	}
+
+	t.Run("AcceptsValidPayload", func(t *testing.T) {
+		body, err := json.Marshal([]artifact.File{{Name: "coverage", Link: "https://example.com/coverage.html", Visibility: artifact.Public}})
+		require.NoError(t, err)
+		w, _ := post(t, body)
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, attached, 1)
+		assert.Equal(t, "coverage", attached[0].Name)
+	})
+	t.Run("RejectsOversizedPayload", func(t *testing.T) {
+		body, err := json.Marshal([]artifact.File{{Name: "huge", Link: strings.Repeat("a", maxAttachFilesBytes)}})
+		require.NoError(t, err)
+		w, rejection := post(t, body)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rejection.StatusCode)
+		assert.Equal(t, "AttachFiles", rejection.Endpoint)
+		assert.EqualValues(t, maxAttachFilesBytes, rejection.MaxBytes)
+		assert.Nil(t, attached)
+	})
+	t.Run("RejectsMalformedJSON", func(t *testing.T) {
+		w, rejection := post(t, []byte(`{"name": "not a list"}`))
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, "AttachFiles", rejection.Endpoint)
+		assert.Zero(t, rejection.MaxBytes)
+		assert.Nil(t, attached)
+	})
+	t.Run("RejectsPayloadNotMatchingSchema", func(t *testing.T) {
+		body, err := json.Marshal([]artifact.File{{Name: "no link"}, {Name: "bad", Link: "https://example.com", Visibility: "everyone"}})
+		require.NoError(t, err)
+		w, rejection := post(t, body)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, rejection.Message, "must have a link")
+		assert.Contains(t, rejection.Message, "invalid visibility")
+		assert.Nil(t, attached)
+	})
+}
+
+func TestAttachResultsSchema(t *testing.T) {
+	t.Run("AcceptsResultWithoutTestFile", func(t *testing.T) {
+		results := &task.LocalTestResults{Results: []task.TestResult{{Status: evergreen.TestSucceededStatus}}}
+		assert.NoError(t, attachResultsSchema.validate(results))
+	})
+	t.Run("RejectsTooManyResults", func(t *testing.T) {
+		results := &task.LocalTestResults{Results: make([]task.TestResult, maxTestResultsPerRequest+1)}
+		assert.Error(t, attachResultsSchema.validate(results))
+	})
+}
+
+func TestAttachFilesForTasksSchema(t *testing.T) {
+	t.Run("AcceptsValidBatch", func(t *testing.T) {
+		batch := &[]artifact.TaskFiles{{TaskID: "t1", Files: []artifact.File{{Name: "coverage", Link: "https://example.com"}}}}
+		assert.NoError(t, attachFilesForTasksSchema.validate(batch))
+	})
+	t.Run("RejectsMissingTaskID", func(t *testing.T) {
+		batch := &[]artifact.TaskFiles{{Files: []artifact.File{{Name: "coverage", Link: "https://example.com"}}}}
+		assert.Error(t, attachFilesForTasksSchema.validate(batch))
+	})
+	t.Run("RejectsInvalidFile", func(t *testing.T) {
+		batch := &[]artifact.TaskFiles{{TaskID: "t1", Files: []artifact.File{{Name: "coverage"}}}}
+		assert.Error(t, attachFilesForTasksSchema.validate(batch))
+	})
+}
+
+func TestAgentPayloadStats(t *testing.T) {
+	stats := &agentPayloadStats{
+		accepted: map[string]int64{},
+		rejected: map[string]map[int]int64{},
+	}
+	stats.recordAccepted("AttachFiles")
+	stats.recordAccepted("AttachFiles")
+	stats.recordRejected("AttachFiles", http.StatusUnprocessableEntity)
+	stats.recordRejected("AttachResults", http.StatusRequestEntityTooLarge)
+
+	assert.Equal(t, []agentPayloadEndpointStats{
+		{Endpoint: "AttachFiles", Accepted: 2, Rejected: map[int]int64{http.StatusUnprocessableEntity: 1}},
+		{Endpoint: "AttachResults", Rejected: map[int]int64{http.StatusRequestEntityTooLarge: 1}},
+	}, stats.get())
+}
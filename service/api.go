@@ -13,11 +13,12 @@ import (
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/rest/route"
-	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
@@ -84,16 +85,6 @@ func MustHaveHost(r *http.Request) *host.Host {
 	return h
 }
 
-// MustHaveProject gets the project from the HTTP request and panics
-// if there is no project specified
-func MustHaveProject(r *http.Request) *model.Project {
-	p := GetProject(r)
-	if p == nil {
-		panic("no project attached to request")
-	}
-	return p
-}
-
 // requireTask get the task from the request header and ensures that there is a task. It checks the secret
 // in the header with the secret in the db to ensure that they are the same.
 func (as *APIServer) requireTask(next http.HandlerFunc) http.HandlerFunc {
@@ -120,40 +111,35 @@ func (as *APIServer) requireTaskStrict(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-// requireProject finds the projectId in the request and adds the
-// project and project ref to the request context.
-func (as *APIServer) requireProject(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		projectId := gimlet.GetVars(r)["projectId"]
-		if projectId == "" {
-			as.LoggedError(w, r, http.StatusBadRequest, errors.New("missing project Id"))
-			return
-		}
-
-		projectRef, err := model.FindBranchProjectRef(projectId)
-		if err != nil {
-			as.LoggedError(w, r, http.StatusInternalServerError, err)
-		}
-		if projectRef == nil {
-			as.LoggedError(w, r, http.StatusNotFound, errors.New("project not found"))
-			return
-		}
+// requireProject finds the projectId in the request and adds the project to
+// the request context. The project is only loaded when the handler gets it,
+// and only the parts of it needed for the projection are loaded.
+func (as *APIServer) requireProject(projection model.ProjectProjection) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			projectId := gimlet.GetVars(r)["projectId"]
+			if projectId == "" {
+				as.LoggedError(w, r, http.StatusBadRequest, errors.New("missing project Id"))
+				return
+			}
 
-		_, p, err := model.FindLatestVersionWithValidProject(projectRef.Id)
-		if err != nil {
-			as.LoggedError(w, r, http.StatusInternalServerError,
-				errors.Wrap(err, "Error getting patch"))
-			return
-		}
-		if p == nil {
-			as.LoggedError(w, r, http.StatusNotFound,
-				errors.Errorf("can't find config for : %s", projectRef.Id))
-			return
-		}
+			projectRef, err := model.FindBranchProjectRef(projectId)
+			if err != nil {
+				as.LoggedError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			if projectRef == nil {
+				as.LoggedError(w, r, http.StatusNotFound, errors.New("project not found"))
+				return
+			}
 
-		r = setProjectContext(r, p)
+			r = setProjectContext(r, &lazyProject{
+				projectID:  projectRef.Id,
+				projection: projection,
+			})
 
-		next(w, r)
+			next(w, r)
+		}
 	}
 }
 
@@ -195,28 +181,21 @@ func (as *APIServer) GetParserProject(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "version not found", http.StatusNotFound)
 		return
 	}
-	pp, err := model.ParserProjectFindOneById(t.Version)
+	pp, err := model.FindParserProjectForVersion(v)
 	if err != nil {
 		as.LoggedError(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	// handle legacy
-	if pp == nil || pp.ConfigUpdateNumber < v.ConfigUpdateNumber {
-		pp = &model.ParserProject{}
-		if err = util.UnmarshalYAMLWithFallback([]byte(v.Config), pp); err != nil {
-			http.Error(w, "invalid version config", http.StatusNotFound)
-			return
-		}
-	}
-	if pp.Functions == nil {
-		pp.Functions = map[string]*model.YAMLCommandSet{}
-	}
 	projBytes, err := bson.Marshal(pp)
 	if err != nil {
 		as.LoggedError(w, r, http.StatusInternalServerError, err)
 		gimlet.WriteJSONResponse(w, http.StatusInternalServerError, responseError{Message: "problem marshalling to bson"})
 		return
 	}
+	// The agent fetches the project before it starts setting up the task.
+	if t.Status == evergreen.TaskDispatched {
+		event.LogTaskAgentAccepted(t.Id, t.Execution, t.HostId)
+	}
 	gimlet.WriteBinary(w, projBytes)
 }
 
@@ -298,17 +277,20 @@ func (as *APIServer) AttachTestLog(w http.ResponseWriter, r *http.Request) {
 // AttachResults attaches the received results to the task in the database.
 func (as *APIServer) AttachResults(w http.ResponseWriter, r *http.Request) {
 	t := MustHaveTask(r)
-	results := &task.LocalTestResults{}
-	err := utility.ReadJSON(utility.NewRequestReader(r), results)
-	if err != nil {
-		as.LoggedError(w, r, http.StatusBadRequest, err)
-		return
-	}
+	results := mustHaveAgentPayload(r).(*task.LocalTestResults)
 	// set test result of task
 	if err := t.SetResults(results.Results); err != nil {
 		as.LoggedError(w, r, http.StatusInternalServerError, err)
 		return
 	}
+	if t.Requester == evergreen.RepotrackerVersionRequester {
+		// Flakiness is scored from mainline results only.
+		grip.Error(message.WrapError(as.queue.Put(r.Context(), units.NewTestFlakinessJob(t.Id, t.Execution)), message.Fields{
+			"message":   "could not queue job to update test flakiness",
+			"task":      t.Id,
+			"execution": t.Execution,
+		}))
+	}
 	gimlet.WriteJSON(w, "test results successfully attached")
 }
 
@@ -316,49 +298,11 @@ func (as *APIServer) AttachResults(w http.ResponseWriter, r *http.Request) {
 // project variables and parameters associated with a task.
 func (as *APIServer) FetchExpansionsForTask(w http.ResponseWriter, r *http.Request) {
 	t := MustHaveTask(r)
-	projectVars, err := model.FindMergedProjectVars(t.Project)
-	if err != nil {
-		as.LoggedError(w, r, http.StatusInternalServerError, err)
-		return
-	}
-	res := apimodels.ExpansionVars{
-		Vars:        map[string]string{},
-		PrivateVars: map[string]bool{},
-	}
-	if projectVars == nil {
-		gimlet.WriteJSON(w, res)
-		return
-	}
-	res.Vars = projectVars.GetVars(t)
-	if projectVars.PrivateVars != nil {
-		res.PrivateVars = projectVars.PrivateVars
-	}
-	v, err := model.VersionFindOne(model.VersionById(t.Version))
-	if err != nil {
-		as.LoggedError(w, r, http.StatusInternalServerError, err)
-		return
-	}
-	if v == nil {
-		as.LoggedError(w, r, http.StatusNotFound, errors.New("version not found"))
-		return
-	}
-	projParams, err := model.FindParametersForVersion(v)
+	res, err := model.FindExpansionVarsForTask(t)
 	if err != nil {
 		as.LoggedError(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	for _, param := range projParams {
-		// If the key doesn't exist the value will default to "" anyway; this prevents
-		// an un-specified parameter from overwriting lower-priority expansions.
-		if param.Value != "" {
-			res.Vars[param.Key] = param.Value
-		}
-	}
-	for _, param := range v.Parameters {
-		// We will overwrite empty values here since these were explicitly user-specified.
-		res.Vars[param.Key] = param.Value
-	}
-
 	gimlet.WriteJSON(w, res)
 }
 
@@ -367,20 +311,15 @@ func (as *APIServer) AttachFiles(w http.ResponseWriter, r *http.Request) {
 	t := MustHaveTask(r)
 	grip.Infoln("Attaching files to task:", t.Id)
 
+	files := *mustHaveAgentPayload(r).(*[]artifact.File)
+	redactArtifactSecrets(t, files)
 	entry := &artifact.Entry{
 		TaskId:          t.Id,
 		TaskDisplayName: t.DisplayName,
 		BuildId:         t.BuildId,
 		Execution:       t.Execution,
 		CreateTime:      time.Now(),
-	}
-
-	err := utility.ReadJSON(utility.NewRequestReader(r), &entry.Files)
-	if err != nil {
-		message := fmt.Sprintf("Error reading file definitions for task  %v: %v", t.Id, err)
-		grip.Error(message)
-		gimlet.WriteJSONError(w, message)
-		return
+		Files:           files,
 	}
 
 	if err := entry.Upsert(); err != nil {
@@ -392,15 +331,95 @@ func (as *APIServer) AttachFiles(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(w, fmt.Sprintf("Artifact files for task %v successfully attached", t.Id))
 }
 
+// AttachFilesForTasks attaches files to the task and to the other tasks in its
+// display task in a single bulk write, so that a task producing artifacts for
+// many tasks doesn't have to make a request per task.
+func (as *APIServer) AttachFilesForTasks(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+
+	batch := *mustHaveAgentPayload(r).(*[]artifact.TaskFiles)
+
+	allowedTasks, err := getTasksForAttachingFiles(t)
+	if err != nil {
+		gimlet.WriteJSONInternalError(w, err.Error())
+		return
+	}
+	filesByTask := map[string][]artifact.File{}
+	taskIDs := []string{}
+	for _, taskFiles := range batch {
+		if _, ok := allowedTasks[taskFiles.TaskID]; !ok {
+			gimlet.WriteJSONError(w, fmt.Sprintf("task '%s' cannot attach files to task '%s' because it is not the same task or in the same display task", t.Id, taskFiles.TaskID))
+			return
+		}
+		if _, ok := filesByTask[taskFiles.TaskID]; !ok {
+			taskIDs = append(taskIDs, taskFiles.TaskID)
+		}
+		filesByTask[taskFiles.TaskID] = append(filesByTask[taskFiles.TaskID], taskFiles.Files...)
+	}
+
+	// Entries for the same task are merged because concurrent upserts for the
+	// same entry could insert it more than once.
+	entries := make([]artifact.Entry, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		tsk := allowedTasks[taskID]
+		redactArtifactSecrets(&tsk, filesByTask[taskID])
+		entries = append(entries, artifact.Entry{
+			TaskId:          tsk.Id,
+			TaskDisplayName: tsk.DisplayName,
+			BuildId:         tsk.BuildId,
+			Execution:       tsk.Execution,
+			Files:           filesByTask[taskID],
+			CreateTime:      time.Now(),
+		})
+	}
+	if err = artifact.UpsertEntries(r.Context(), as.env, entries); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "could not attach artifact files for tasks",
+			"task_id": t.Id,
+			"tasks":   taskIDs,
+		}))
+		gimlet.WriteJSONInternalError(w, fmt.Sprintf("attaching artifact files for task '%s': %s", t.Id, err))
+		return
+	}
+	gimlet.WriteJSON(w, fmt.Sprintf("Artifact files for %d tasks successfully attached", len(entries)))
+}
+
+// getTasksForAttachingFiles returns the tasks, keyed by ID, that the task can
+// attach files to, which are the task itself, its display task, and the other
+// execution tasks in its display task.
+func getTasksForAttachingFiles(t *task.Task) (map[string]task.Task, error) {
+	allowedTasks := map[string]task.Task{t.Id: *t}
+	if !t.IsPartOfDisplay() {
+		return allowedTasks, nil
+	}
+	dt, err := t.GetDisplayTask()
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting display task for task '%s'", t.Id)
+	}
+	if dt == nil {
+		return allowedTasks, nil
+	}
+	allowedTasks[dt.Id] = *dt
+	execTasks, err := task.Find(task.ByIds(dt.ExecutionTasks))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding execution tasks for display task '%s'", dt.Id)
+	}
+	for _, et := range execTasks {
+		allowedTasks[et.Id] = et
+	}
+	return allowedTasks, nil
+}
+
 // SetDownstreamParams updates file mappings for a task or build
 func (as *APIServer) SetDownstreamParams(w http.ResponseWriter, r *http.Request) {
 	t := MustHaveTask(r)
 	grip.Infoln("Setting downstream expansions for task:", t.Id)
 
-	var downstreamParams []patch.Parameter
-	err := utility.ReadJSON(utility.NewRequestReader(r), &downstreamParams)
+	downstreamParams := *mustHaveAgentPayload(r).(*[]patch.Parameter)
+	p, err := patch.FindOne(patch.ByVersion(t.Version))
+
 	if err != nil {
-		errorMessage := fmt.Sprintf("Error reading downstream expansions for task %v: %v", t.Id, err)
+		errorMessage := fmt.Sprintf("error loading patch: %v: ", err)
 		grip.Error(message.Fields{
 			"message": errorMessage,
 			"task_id": t.Id,
@@ -408,10 +427,9 @@ func (as *APIServer) SetDownstreamParams(w http.ResponseWriter, r *http.Request)
 		gimlet.WriteJSONError(w, errorMessage)
 		return
 	}
-	p, err := patch.FindOne(patch.ByVersion(t.Version))
 
-	if err != nil {
-		errorMessage := fmt.Sprintf("error loading patch: %v: ", err)
+	if p == nil {
+		errorMessage := "patch not found"
 		grip.Error(message.Fields{
 			"message": errorMessage,
 			"task_id": t.Id,
@@ -420,8 +438,22 @@ func (as *APIServer) SetDownstreamParams(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if p == nil {
-		errorMessage := "patch not found"
+	pRef, err := model.FindMergedProjectRef(t.Project, t.Version, true)
+	if err != nil {
+		errorMessage := fmt.Sprintf("error loading project: %s", err)
+		grip.Error(message.Fields{
+			"message": errorMessage,
+			"task_id": t.Id,
+		})
+		gimlet.WriteJSONInternalError(w, errorMessage)
+		return
+	}
+	var schema patch.DownstreamParameterSchema
+	if pRef != nil {
+		schema = pRef.DownstreamParameterSchema
+	}
+	if err = schema.ValidateParameters(downstreamParams); err != nil {
+		errorMessage := fmt.Sprintf("invalid downstream parameters: %s", err)
 		grip.Error(message.Fields{
 			"message": errorMessage,
 			"task_id": t.Id,
@@ -430,7 +462,7 @@ func (as *APIServer) SetDownstreamParams(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err = p.SetDownstreamParameters(downstreamParams); err != nil {
+	if err = p.SetDownstreamParameters(downstreamParams, t.Id, schema); err != nil {
 		errorMessage := fmt.Sprintf("error setting patch parameters: %s", err)
 		grip.Error(message.Fields{
 			"message": errorMessage,
@@ -530,14 +562,11 @@ func (as *APIServer) AppendTaskLog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	t := MustHaveTask(r)
-	taskLog := &model.TaskLog{}
-	if err := gimlet.GetJSON(r.Body, taskLog); err != nil {
-		as.LoggedError(w, r, http.StatusBadRequest, errors.Wrap(err, "unable to read logs from request"))
-		return
-	}
+	taskLog := mustHaveAgentPayload(r).(*model.TaskLog)
 
 	taskLog.TaskId = t.Id
 	taskLog.Execution = t.Execution
+	redactTaskLogSecrets(t, taskLog)
 
 	if err := taskLog.Insert(); err != nil {
 		as.LoggedError(w, r, http.StatusInternalServerError, err)
@@ -562,11 +591,20 @@ func (as *APIServer) Heartbeat(w http.ResponseWriter, r *http.Request) {
 	if t.Aborted {
 		grip.Noticef("Sending abort signal for task %s", t.Id)
 		heartbeatResponse.Abort = true
+	} else if t.CommandAbort != nil {
+		claimed, err := t.ClaimCommandAbort()
+		if err != nil {
+			grip.Warningf("Error claiming command abort for task %s: %+v", t.Id, err)
+		} else if claimed {
+			grip.Noticef("Sending command abort signal for task %s", t.Id)
+			heartbeatResponse.AbortCommand = true
+		}
 	}
 
 	if err := t.UpdateHeartbeat(); err != nil {
 		grip.Warningf("Error updating heartbeat for task %s: %+v", t.Id, err)
 	}
+	heartbeatResponse.BackoffSecs = int(model.GetLoadShedder().Backoff(as.env).Seconds())
 	gimlet.WriteJSON(w, heartbeatResponse)
 }
 
@@ -596,7 +634,11 @@ func (as *APIServer) listProjects(w http.ResponseWriter, r *http.Request) {
 }
 
 func (as *APIServer) listTasks(w http.ResponseWriter, r *http.Request) {
-	project := MustHaveProject(r)
+	project, err := GetProject(r)
+	if err != nil {
+		as.LoggedError(w, r, getProjectErrorStatus(err), errors.Wrap(err, "getting project"))
+		return
+	}
 
 	// zero out the depends on and commands fields because they are
 	// unnecessary and may not get marshaled properly
@@ -608,7 +650,11 @@ func (as *APIServer) listTasks(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(w, project.Tasks)
 }
 func (as *APIServer) listVariants(w http.ResponseWriter, r *http.Request) {
-	project := MustHaveProject(r)
+	project, err := GetProject(r)
+	if err != nil {
+		as.LoggedError(w, r, getProjectErrorStatus(err), errors.Wrap(err, "getting project"))
+		return
+	}
 
 	gimlet.WriteJSON(w, project.BuildVariants)
 }
@@ -632,67 +678,99 @@ func (as *APIServer) validateProjectConfig(w http.ResponseWriter, r *http.Reques
 		input.IncludeLong = true // this is legacy behavior
 	}
 
+	// Incremental validations store their results, so unlike plain
+	// validations they're only available to authenticated users.
+	if input.Incremental && gimlet.GetUser(r.Context()) == nil {
+		gimlet.WriteJSONResponse(w, http.StatusUnauthorized, gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "incremental validation requires an authenticated user",
+		})
+		return
+	}
+
+	errs := getProjectConfigValidationErrors(r.Context(), input)
+	if input.Incremental {
+		result, err := validator.GetIncrementalValidationResult(input, errs)
+		if err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "getting incremental validation result"))
+			return
+		}
+		gimlet.WriteJSON(w, result)
+		return
+	}
+
+	if len(errs) > 0 {
+		gimlet.WriteJSONError(w, errs)
+		return
+	}
+	gimlet.WriteJSON(w, validator.ValidationErrors{})
+}
+
+// getProjectConfigValidationErrors returns the errors and warnings found in
+// validating the input's project configuration.
+func getProjectConfigValidationErrors(ctx context.Context, input validator.ValidationInput) validator.ValidationErrors {
 	project := &model.Project{}
 	var projectConfig *model.ProjectConfig
-	ctx := context.Background()
 	opts := &model.GetProjectOpts{
 		ReadFileFrom: model.ReadFromLocal,
 	}
-	validationErr := validator.ValidationError{}
-	if _, err = model.LoadProjectInto(ctx, input.ProjectYaml, opts, "", project); err != nil {
+	validationErr := validator.ValidationError{Code: validator.CodeProjectConfigInvalid}
+	pp, err := model.LoadProjectInto(ctx, input.ProjectYaml, opts, "", project)
+	if err != nil {
 		validationErr.Message = err.Error()
-		gimlet.WriteJSONError(w, validator.ValidationErrors{validationErr})
-		return
+		return append(validator.ValidationErrors{validationErr}, validator.CheckSelectorTags(pp)...)
 	}
 	if projectConfig, err = model.CreateProjectConfig(input.ProjectYaml, ""); err != nil {
 		validationErr.Message = err.Error()
-		gimlet.WriteJSONError(w, validator.ValidationErrors{validationErr})
-		return
+		return validator.ValidationErrors{validationErr}
 	}
 
 	errs := validator.ValidationErrors{}
+	var projectRef *model.ProjectRef
 	if input.ProjectID != "" {
-		projectRef, err := model.FindMergedProjectRef(input.ProjectID, "", false)
+		projectRef, err = model.FindMergedProjectRef(input.ProjectID, "", false)
 		if err != nil {
 			validationErr = validator.ValidationError{
+				Code:    validator.CodeProjectSettingsUnchecked,
 				Message: "error finding project; validation will proceed without checking project settings",
 				Level:   validator.Warning,
 			}
 			errs = append(errs, validationErr)
 		} else if projectRef == nil {
 			validationErr = validator.ValidationError{
+				Code:    validator.CodeProjectSettingsUnchecked,
 				Message: "project does not exist; validation will proceed without checking project settings",
 				Level:   validator.Warning,
 			}
 			errs = append(errs, validationErr)
 		} else {
 			isConfigDefined := projectConfig != nil
-			errs = append(errs, validator.CheckProjectSettings(project, projectRef, isConfigDefined)...)
+			errs = append(errs, validator.CheckProjectSettings(ctx, project, projectRef, isConfigDefined)...)
 		}
 	} else {
 		validationErr = validator.ValidationError{
+			Code:    validator.CodeProjectSettingsUnchecked,
 			Message: "no project specified; validation will proceed without checking project settings",
 			Level:   validator.Warning,
 		}
 		errs = append(errs, validationErr)
 	}
 
-	errs = append(errs, validator.CheckProjectErrors(project, input.IncludeLong)...)
+	errs = append(errs, validator.CheckProjectErrors(ctx, project, projectRef, input.IncludeLong)...)
 	if projectConfig != nil {
-		errs = append(errs, validator.CheckProjectConfigErrors(projectConfig)...)
+		errs = append(errs, validator.CheckProjectConfigErrors(ctx, projectConfig)...)
 	}
 
 	if input.Quiet {
 		errs = errs.AtLevel(validator.Error)
 	} else {
-		errs = append(errs, validator.CheckProjectWarnings(project)...)
+		errs = append(errs, validator.CheckProjectWarnings(ctx, project, projectRef)...)
+		if projectRef != nil {
+			errs = append(errs, validator.CheckProjectHistory(ctx, project, projectRef)...)
+		}
 	}
 
-	if len(errs) > 0 {
-		gimlet.WriteJSONError(w, errs)
-		return
-	}
-	gimlet.WriteJSON(w, validator.ValidationErrors{})
+	return errs
 }
 
 // LoggedError logs the given error and writes an HTTP response with its details formatted
@@ -743,7 +821,9 @@ func (as *APIServer) GetSettings() evergreen.Settings {
 
 // NewRouter returns the root router for all APIServer endpoints.
 func (as *APIServer) GetServiceApp() *gimlet.APIApp {
-	requireProject := gimlet.WrapperMiddleware(as.requireProject)
+	requireProjectTasks := gimlet.WrapperMiddleware(as.requireProject(model.ProjectProjectionTasks))
+	requireProjectVariants := gimlet.WrapperMiddleware(as.requireProject(model.ProjectProjectionVariants))
+	requireProjectModules := gimlet.WrapperMiddleware(as.requireProject(model.ProjectProjectionModules))
 	requireTaskSecret := gimlet.WrapperMiddleware(as.requireTaskStrict)
 	requireUser := gimlet.NewRequireAuthHandler()
 	requireTask := gimlet.WrapperMiddleware(as.requireTask)
@@ -755,6 +835,7 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	app.SetPrefix("/api")
 	app.NoVersions = true
 	app.SimpleVersions = true
+	app.AddWrapper(route.NewServiceAccountAuditMiddleware())
 
 	// Project lookup and validation routes
 	app.AddRoute("/ref/{identifier}").Handler(as.fetchProjectRef).Get()
@@ -764,12 +845,14 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	app.AddRoute("/status/consistent_task_assignment").Handler(as.consistentTaskAssignment).Get()
 	app.AddRoute("/status/stuck_hosts").Handler(as.getStuckHosts).Get()
 	app.AddRoute("/status/info").Handler(as.serviceStatusSimple).Get()
+	app.AddRoute("/status/agent_payloads").Handler(as.agentPayloadStatus).Get()
+	app.AddRoute("/status/distro_cache").Handler(as.distroCacheStatus).Get()
 	app.AddRoute("/task_queue").Handler(as.getTaskQueueSizes).Get()
 	app.AddRoute("/task_queue/limit").Handler(as.checkTaskQueueSize).Get()
 
 	// CLI Operation Backends
-	app.AddRoute("/tasks/{projectId}").Wrap(requireUser, requireProject, viewTasks).Handler(as.listTasks).Get()
-	app.AddRoute("/variants/{projectId}").Wrap(requireUser, requireProject, viewTasks).Handler(as.listVariants).Get()
+	app.AddRoute("/tasks/{projectId}").Wrap(requireUser, requireProjectTasks, viewTasks).Handler(as.listTasks).Get()
+	app.AddRoute("/variants/{projectId}").Wrap(requireUser, requireProjectVariants, viewTasks).Handler(as.listVariants).Get()
 	app.AddRoute("/projects").Wrap(requireUser).Handler(as.listProjects).Get()
 
 	// Patches
@@ -777,7 +860,7 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	app.PrefixRoute("/patches").Route("/mine").Wrap(requireUser).Handler(as.listPatches).Get()
 	app.PrefixRoute("/patches").Route("/{patchId:\\w+}").Wrap(requireUser, viewTasks).Handler(as.summarizePatch).Get()
 	app.PrefixRoute("/patches").Route("/{patchId:\\w+}").Wrap(requireUser, submitPatch).Handler(as.existingPatchRequest).Post()
-	app.PrefixRoute("/patches").Route("/{patchId:\\w+}/{projectId}/modules").Wrap(requireUser, requireProject, viewTasks).Handler(as.listPatchModules).Get()
+	app.PrefixRoute("/patches").Route("/{patchId:\\w+}/{projectId}/modules").Wrap(requireUser, requireProjectModules, viewTasks).Handler(as.listPatchModules).Get()
 	app.PrefixRoute("/patches").Route("/{patchId:\\w+}/modules").Wrap(requireUser, submitPatch).Handler(as.deletePatchModule).Delete()
 	app.PrefixRoute("/patches").Route("/{patchId:\\w+}/modules").Wrap(requireUser, submitPatch).Handler(as.updatePatchModule).Post()
 
@@ -797,13 +880,14 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	app.Route().Version(2).Route("/agent/cedar_config").Wrap(requireHost).Handler(as.Cedar).Get()
 	app.Route().Version(2).Route("/task/{taskId}/end").Wrap(requireTaskSecret, requireHost).Handler(as.EndTask).Post()
 	app.Route().Version(2).Route("/task/{taskId}/start").Wrap(requireTaskSecret, requireHost).Handler(as.StartTask).Post()
-	app.Route().Version(2).Route("/task/{taskId}/log").Wrap(requireTaskSecret, requireHost).Handler(as.AppendTaskLog).Post()
+	app.Route().Version(2).Route("/task/{taskId}/log").Wrap(requireTaskSecret, requireHost, as.requireAgentPayload(appendTaskLogSchema)).Handler(as.AppendTaskLog).Post()
 	app.Route().Version(2).Route("/task/{taskId}/").Wrap(requireTaskSecret).Handler(as.FetchTask).Get()
 	app.Route().Version(2).Route("/task/{taskId}/fetch_vars").Wrap(requireTaskSecret).Handler(as.FetchExpansionsForTask).Get()
 	app.Route().Version(2).Route("/task/{taskId}/heartbeat").Wrap(requireTaskSecret, requireHost).Handler(as.Heartbeat).Post()
-	app.Route().Version(2).Route("/task/{taskId}/results").Wrap(requireTaskSecret, requireHost).Handler(as.AttachResults).Post()
+	app.Route().Version(2).Route("/task/{taskId}/results").Wrap(requireTaskSecret, requireHost, as.requireAgentPayload(attachResultsSchema)).Handler(as.AttachResults).Post()
 	app.Route().Version(2).Route("/task/{taskId}/test_logs").Wrap(requireTaskSecret, requireHost).Handler(as.AttachTestLog).Post()
-	app.Route().Version(2).Route("/task/{taskId}/files").Wrap(requireTask, requireHost).Handler(as.AttachFiles).Post()
+	app.Route().Version(2).Route("/task/{taskId}/files").Wrap(requireTask, requireHost, as.requireAgentPayload(attachFilesSchema)).Handler(as.AttachFiles).Post()
+	app.Route().Version(2).Route("/task/{taskId}/files/batch").Wrap(requireTask, requireHost, as.requireAgentPayload(attachFilesForTasksSchema)).Handler(as.AttachFilesForTasks).Post()
 	app.Route().Version(2).Route("/task/{taskId}/distro_view").Wrap(requireTask, requireHost).Handler(as.GetDistroView).Get()
 	app.Route().Version(2).Route("/task/{taskId}/parser_project").Wrap(requireTaskSecret).Handler(as.GetParserProject).Get()
 	app.Route().Version(2).Route("/task/{taskId}/project_ref").Wrap(requireTaskSecret).Handler(as.GetProjectRef).Get()
@@ -816,7 +900,7 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/git/patch").Wrap(requireTaskSecret).Handler(as.gitServePatch).Get()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/keyval/inc").Wrap(requireTask).Handler(as.keyValPluginInc).Post()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/manifest/load").Wrap(requireTask).Handler(as.manifestLoadHandler).Get()
-	app.Route().Version(2).Prefix("/task/{taskId}").Route("/downstreamParams").Wrap(requireTask).Handler(as.SetDownstreamParams).Post()
+	app.Route().Version(2).Prefix("/task/{taskId}").Route("/downstreamParams").Wrap(requireTask, as.requireAgentPayload(setDownstreamParamsSchema)).Handler(as.SetDownstreamParams).Post()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/json/tags/{task_name}/{name}").Wrap(requireTask).Handler(as.getTaskJSONTagsForTask).Get()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/json/history/{task_name}/{name}").Wrap(requireTask).Handler(as.getTaskJSONTaskHistory).Get()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/json/data/{name}").Wrap(requireTask).Handler(as.insertTaskJSON).Post()
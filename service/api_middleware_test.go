@@ -9,10 +9,14 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCheckHostWrapper(t *testing.T) {
@@ -243,3 +247,49 @@ func TestCheckHostWrapper(t *testing.T) {
 		})
 	})
 }
+
+func TestListTasksProjectNotFound(t *testing.T) {
+	require.NoError(t, db.ClearCollections(model.ProjectRefCollection))
+
+	env := evergreen.GetEnvironment()
+	as, err := NewAPIServer(env, env.LocalQueue())
+	require.NoError(t, err)
+
+	app := gimlet.NewApp()
+	app.NoVersions = true
+	app.AddRoute("/tasks/{projectId}").Handler(as.requireProject(model.ProjectProjectionTasks)(as.listTasks)).Get()
+	root, err := app.Handler()
+	require.NoError(t, err)
+
+	t.Run("MissingProjectRef", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, "/tasks/nonexistent", nil)
+		require.NoError(t, err)
+		root.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("MissingProjectConfig", func(t *testing.T) {
+		lp := &lazyProject{projectID: "project"}
+		lp.once.Do(func() {
+			lp.err = errors.Wrapf(errProjectConfigNotFound, "project '%s'", lp.projectID)
+		})
+
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, "/tasks/project", nil)
+		require.NoError(t, err)
+		as.listTasks(w, setProjectContext(r, lp))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("ErrorLoadingProjectConfig", func(t *testing.T) {
+		lp := &lazyProject{projectID: "project"}
+		lp.once.Do(func() {
+			lp.err = errors.New("database error")
+		})
+
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, "/tasks/project", nil)
+		require.NoError(t, err)
+		as.listTasks(w, setProjectContext(r, lp))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -30,9 +30,10 @@ var cliOutOfDateError = errors.New("CLI is out of date: use 'evergreen get-updat
 
 // PatchAPIResponse is returned by all patch-related API calls
 type PatchAPIResponse struct {
-	Message string       `json:"message"`
-	Action  string       `json:"action"`
-	Patch   *patch.Patch `json:"patch"`
+	Message  string       `json:"message"`
+	Action   string       `json:"action"`
+	Patch    *patch.Patch `json:"patch"`
+	Warnings []string     `json:"warnings,omitempty"`
 }
 
 // submitPatch creates the Patch document, adds the patched project config to it,
@@ -41,28 +42,29 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 	dbUser := MustHaveUser(r)
 
 	data := struct {
-		Description       string             `json:"desc"`
-		Path              string             `json:"path"`
-		Project           string             `json:"project"`
-		BackportInfo      patch.BackportInfo `json:"backport_info"`
-		GitMetadata       *patch.GitMetadata `json:"git_metadata"`
-		PatchBytes        []byte             `json:"patch_bytes"`
-		Githash           string             `json:"githash"`
-		Parameters        []patch.Parameter  `json:"parameters"`
-		Variants          []string           `json:"buildvariants_new"`
-		Tasks             []string           `json:"tasks"`
-		RegexVariants     []string           `json:"regex_buildvariants"`
-		RegexTasks        []string           `json:"regex_tasks"`
-		SyncBuildVariants []string           `json:"sync_build_variants"`
-		SyncTasks         []string           `json:"sync_tasks"`
-		SyncStatuses      []string           `json:"sync_statuses"`
-		SyncTimeout       time.Duration      `json:"sync_timeout"`
-		Finalize          bool               `json:"finalize"`
-		TriggerAliases    []string           `json:"trigger_aliases"`
-		Alias             string             `json:"alias"`
-		RepeatFailed      bool               `json:"repeat_failed"`
-		RepeatDefinition  bool               `json:"reuse_definition"`
-		GithubAuthor      string             `json:"github_author"`
+		Description        string             `json:"desc"`
+		Path               string             `json:"path"`
+		Project            string             `json:"project"`
+		BackportInfo       patch.BackportInfo `json:"backport_info"`
+		GitMetadata        *patch.GitMetadata `json:"git_metadata"`
+		PatchBytes         []byte             `json:"patch_bytes"`
+		Githash            string             `json:"githash"`
+		Parameters         []patch.Parameter  `json:"parameters"`
+		ExpansionOverrides map[string]string  `json:"expansion_overrides"`
+		Variants           []string           `json:"buildvariants_new"`
+		Tasks              []string           `json:"tasks"`
+		RegexVariants      []string           `json:"regex_buildvariants"`
+		RegexTasks         []string           `json:"regex_tasks"`
+		SyncBuildVariants  []string           `json:"sync_build_variants"`
+		SyncTasks          []string           `json:"sync_tasks"`
+		SyncStatuses       []string           `json:"sync_statuses"`
+		SyncTimeout        time.Duration      `json:"sync_timeout"`
+		Finalize           bool               `json:"finalize"`
+		TriggerAliases     []string           `json:"trigger_aliases"`
+		Alias              string             `json:"alias"`
+		RepeatFailed       bool               `json:"repeat_failed"`
+		RepeatDefinition   bool               `json:"reuse_definition"`
+		GithubAuthor       string             `json:"github_author"`
 	}{}
 	if err := utility.ReadJSON(utility.NewRequestReaderWithSize(r, patch.SizeLimit), &data); err != nil {
 		as.LoggedError(w, r, http.StatusBadRequest, err)
@@ -99,6 +101,11 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = pref.PatchPolicy.Check(pref.Id, patchString, data.Githash); err != nil {
+		as.writePatchPolicyRejection(w, r, err)
+		return
+	}
+
 	if data.Alias == evergreen.CommitQueueAlias && len(patchString) != 0 && !patch.IsMailboxDiff(patchString) {
 		as.LoggedError(w, r, http.StatusBadRequest, cliOutOfDateError)
 		return
@@ -109,6 +116,11 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = pref.ValidateExpansionOverrides(data.ExpansionOverrides); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, errors.Wrap(err, "invalid expansion overrides"))
+		return
+	}
+
 	if !pref.TaskSync.IsPatchEnabled() && (len(data.SyncTasks) != 0 || len(data.SyncBuildVariants) != 0) {
 		as.LoggedError(w, r, http.StatusUnauthorized, errors.New("task sync at the end of a patched task is disabled by project settings"))
 		return
@@ -150,25 +162,26 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	intent, err := patch.NewCliIntent(patch.CLIIntentParams{
-		User:             author,
-		Project:          pref.Id,
-		Path:             data.Path,
-		BaseGitHash:      data.Githash,
-		Module:           r.FormValue("module"),
-		PatchContent:     patchString,
-		Description:      data.Description,
-		Finalize:         data.Finalize,
-		Parameters:       data.Parameters,
-		Variants:         data.Variants,
-		Tasks:            data.Tasks,
-		RegexVariants:    data.RegexVariants,
-		RegexTasks:       data.RegexTasks,
-		Alias:            data.Alias,
-		TriggerAliases:   data.TriggerAliases,
-		BackportOf:       data.BackportInfo,
-		GitInfo:          data.GitMetadata,
-		RepeatDefinition: data.RepeatDefinition,
-		RepeatFailed:     data.RepeatFailed,
+		User:               author,
+		Project:            pref.Id,
+		Path:               data.Path,
+		BaseGitHash:        data.Githash,
+		Module:             r.FormValue("module"),
+		PatchContent:       patchString,
+		Description:        data.Description,
+		Finalize:           data.Finalize,
+		Parameters:         data.Parameters,
+		ExpansionOverrides: data.ExpansionOverrides,
+		Variants:           data.Variants,
+		Tasks:              data.Tasks,
+		RegexVariants:      data.RegexVariants,
+		RegexTasks:         data.RegexTasks,
+		Alias:              data.Alias,
+		TriggerAliases:     data.TriggerAliases,
+		BackportOf:         data.BackportInfo,
+		GitInfo:            data.GitMetadata,
+		RepeatDefinition:   data.RepeatDefinition,
+		RepeatFailed:       data.RepeatFailed,
 		SyncParams: patch.SyncAtEndOptions{
 			BuildVariants: data.SyncBuildVariants,
 			Tasks:         data.SyncTasks,
@@ -219,7 +232,41 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gimlet.WriteJSONResponse(w, http.StatusCreated, PatchAPIResponse{Patch: patchDoc})
+	resp := PatchAPIResponse{Patch: patchDoc}
+	if len(pref.WatchedPaths) > 0 && len(pref.MatchWatchedPaths(patchDoc.FilesChanged())) == 0 {
+		resp.Warnings = append(resp.Warnings, fmt.Sprintf("patch does not change any of the project's watched paths (%s)", strings.Join(pref.WatchedPaths, ", ")))
+	}
+
+	gimlet.WriteJSONResponse(w, http.StatusCreated, resp)
+}
+
+// patchPolicyRejection lists the reasons that a patch was rejected by its
+// project's patch policy.
+type patchPolicyRejection struct {
+	gimlet.ErrorResponse
+	Violations []model.PatchPolicyViolation `json:"violations"`
+}
+
+// writePatchPolicyRejection writes the structured reasons that a patch
+// violated its project's patch policy.
+func (as *APIServer) writePatchPolicyRejection(w http.ResponseWriter, r *http.Request, err error) {
+	policyErr, ok := errors.Cause(err).(*model.PatchPolicyError)
+	if !ok {
+		as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "checking patch policy"))
+		return
+	}
+	grip.Info(message.Fields{
+		"message":    "rejected patch that violates project patch policy",
+		"violations": policyErr.Violations,
+		"request":    gimlet.GetRequestID(r.Context()),
+	})
+	gimlet.WriteJSONResponse(w, http.StatusBadRequest, patchPolicyRejection{
+		ErrorResponse: gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    policyErr.Error(),
+		},
+		Violations: policyErr.Violations,
+	})
 }
 
 // Get the patch with the specified request it
@@ -283,6 +330,10 @@ func (as *APIServer) updatePatchModule(w http.ResponseWriter, r *http.Request) {
 		as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrapf(err, "Error getting project ref with id %v", p.Project))
 		return
 	}
+	if err = projectRef.PatchPolicy.CheckDiff(patchContent); err != nil {
+		as.writePatchPolicyRejection(w, r, err)
+		return
+	}
 	_, project, err := model.FindLatestVersionWithValidProject(projectRef.Id)
 	if err != nil {
 		as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "Error getting patch"))
@@ -461,7 +512,7 @@ func (as *APIServer) existingPatchRequest(w http.ResponseWriter, r *http.Request
 
 		gimlet.WriteJSON(w, "patch finalized")
 	case "cancel":
-		err = model.CancelPatch(p, task.AbortInfo{User: dbUser.Id})
+		_, err = model.CancelPatch(p, task.AbortInfo{User: dbUser.Id}, false)
 		if err != nil {
 			as.LoggedError(w, r, http.StatusInternalServerError, err)
 			return
@@ -482,7 +533,11 @@ func (as *APIServer) summarizePatch(w http.ResponseWriter, r *http.Request) {
 }
 
 func (as *APIServer) listPatchModules(w http.ResponseWriter, r *http.Request) {
-	project := MustHaveProject(r)
+	project, err := GetProject(r)
+	if err != nil {
+		as.LoggedError(w, r, getProjectErrorStatus(err), errors.Wrap(err, "getting project"))
+		return
+	}
 
 	p, err := getPatchFromRequest(r)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
@@ -188,6 +189,19 @@ func (as *APIServer) serviceStatusSimple(w http.ResponseWriter, r *http.Request)
 	gimlet.WriteJSON(w, &out)
 }
 
+// distroCacheStatus reports how often distro lookups on this app server were
+// served from its in-process distro cache.
+func (as *APIServer) distroCacheStatus(w http.ResponseWriter, r *http.Request) {
+	stats := distro.GetCacheStats()
+	gimlet.WriteJSON(w, struct {
+		distro.CacheStats
+		HitRate float64 `json:"hit_rate"`
+	}{
+		CacheStats: stats,
+		HitRate:    stats.HitRate(),
+	})
+}
+
 func (as *APIServer) agentSetup(w http.ResponseWriter, r *http.Request) {
 	out := &apimodels.AgentSetupData{
 		SplunkServerURL:   as.Settings.Splunk.ServerURL,
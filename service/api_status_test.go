@@ -243,3 +243,16 @@ func TestStuckHostEndpoints(t *testing.T) {
 		})
 	})
 }
+
+func TestDistroCacheStatus(t *testing.T) {
+	as := &APIServer{}
+	w := httptest.NewRecorder()
+	as.distroCacheStatus(w, httptest.NewRequest(http.MethodGet, "/api/status/distro_cache", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	out := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out))
+	require.Contains(t, out, "hits")
+	require.Contains(t, out, "misses")
+	require.Contains(t, out, "hit_rate")
+}
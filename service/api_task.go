@@ -18,6 +18,7 @@ import (
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/mongodb/grip/sometimes"
@@ -26,6 +27,7 @@ import (
 
 // if a host encounters more than this number of system failures, then it should be disabled.
 const consecutiveSystemFailureThreshold = 3
+
 const taskDispatcherTTL = time.Minute
 
 // StartTask is the handler function that retrieves the task from the request
@@ -159,7 +161,52 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if currentHost.RunningTask == "" {
+	// An agent that did not get a response to its request retries it with the
+	// same idempotency key. The retry must not end the task again, so it is
+	// given the response to the original request instead.
+	if details.IdempotencyKey != "" {
+		claimed, err := task.ClaimEndTaskRequest(t.Id, t.Execution, details.IdempotencyKey)
+		if err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if !claimed {
+			if replayEndTaskResponse(w, t, details.IdempotencyKey) {
+				return
+			}
+		} else {
+			defer func() {
+				if t.EndTaskRequest != nil && t.EndTaskRequest.Response != nil {
+					return
+				}
+				// The request did not finish ending the task, so let a retry
+				// try again.
+				grip.Error(message.WrapError(task.ReleaseEndTaskRequest(t.Id, t.Execution, details.IdempotencyKey), message.Fields{
+					"message":         "could not release end task request",
+					"task_id":         t.Id,
+					"execution":       t.Execution,
+					"idempotency_key": details.IdempotencyKey,
+				}))
+			}()
+		}
+	}
+
+	// An agent that restarted while ending the task resumes ending it
+	// afterward. The host's running task may have been cleared in the
+	// meantime, but the execution can still be ended within the window.
+	resumed := details.Resumed && t.IsFinalizing() && t.HostId == currentHost.Id
+	if resumed {
+		grip.Info(message.Fields{
+			"message":          "agent resumed ending task",
+			"task_id":          t.Id,
+			"execution":        t.Execution,
+			"host_id":          currentHost.Id,
+			"finalizing_until": t.FinalizingUntil,
+			"status":           details.Status,
+		})
+	}
+
+	if currentHost.RunningTask == "" && !resumed {
 		grip.Notice(message.Fields{
 			"message":                 "host is not assigned task, not clearing, asking agent to exit",
 			"task_id":                 t.Id,
@@ -174,7 +221,7 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 			"distro":                  currentHost.Distro.Id,
 		})
 		endTaskResp.ShouldExit = true
-		gimlet.WriteJSON(w, endTaskResp)
+		writeEndTaskResponse(w, t, details.IdempotencyKey, endTaskResp)
 		return
 	}
 
@@ -198,11 +245,13 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Clear the running task on the host now that the task has finished.
-	if err := currentHost.ClearRunningAndSetLastTask(t); err != nil {
-		err = errors.Wrapf(err, "error clearing running task %s for host %s", t.Id, currentHost.Id)
-		grip.Errorf(err.Error())
-		as.LoggedError(w, r, http.StatusInternalServerError, err)
-		return
+	if currentHost.RunningTask != "" {
+		if err := currentHost.ClearRunningAndSetLastTask(t); err != nil {
+			err = errors.Wrapf(err, "error clearing running task %s for host %s", t.Id, currentHost.Id)
+			grip.Errorf(err.Error())
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
 	}
 
 	projectRef, err := model.FindMergedProjectRef(t.Project, t.Version, true)
@@ -240,7 +289,7 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 		abortMsg := fmt.Sprintf("task %v has been aborted and will not run", t.Id)
 		grip.Infof(abortMsg)
 		endTaskResp = &apimodels.EndTaskResponse{}
-		gimlet.WriteJSON(w, endTaskResp)
+		writeEndTaskResponse(w, t, details.IdempotencyKey, endTaskResp)
 		return
 	}
 
@@ -256,6 +305,24 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 			errors.Wrap(err, "couldn't queue job to update task stats accounting"))
 		return
 	}
+	if model.NeedsCedarFailureFingerprint(t) {
+		if err = as.queue.Put(r.Context(), units.NewTaskFailureFingerprintJob(t.Id, t.Execution)); err != nil && !amboy.IsDuplicateJobError(err) {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message":   "could not queue job to compute task failure fingerprint",
+				"task_id":   t.Id,
+				"execution": t.Execution,
+			}))
+		}
+	}
+	if t.Status == evergreen.TaskFailed && projectRef.IsFailureLogIndexingEnabled() {
+		if err = as.queue.Put(r.Context(), units.NewIndexTaskFailureLogJob(t.Id, t.Execution)); err != nil && !amboy.IsDuplicateJobError(err) {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message":   "could not queue job to index task failure log",
+				"task_id":   t.Id,
+				"execution": t.Execution,
+			}))
+		}
+	}
 
 	if checkHostHealth(currentHost) {
 		if _, err := as.prepareHostForAgentExit(r.Context(), agentExitParams{
@@ -274,17 +341,27 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 		endTaskResp.ShouldExit = true
 	}
 
+	if model.IsHostFailure(t) {
+		if err = as.queue.Put(r.Context(), units.NewDistroHealthCheckJob(t.DistroId, finishTime)); err != nil && !amboy.IsDuplicateJobError(err) {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "could not queue job to check distro health",
+				"distro":  t.DistroId,
+				"task":    t.Id,
+			}))
+		}
+	}
+
 	// we should disable hosts and prevent them from performing
 	// more work if they appear to be in a bad state
-	// (e.g. encountered 5 consecutive system failures)
+	// (e.g. encountered 3 consecutive system failures). Setup failures come
+	// from the project's own setup commands, so they don't count against the
+	// host. Static hosts are quarantined rather than terminated.
 	if event.AllRecentHostEventsMatchStatus(currentHost.Id, consecutiveSystemFailureThreshold, evergreen.TaskSystemFailed) {
 		msg := "host encountered consecutive system failures"
-		if currentHost.Provider != evergreen.ProviderNameStatic {
-			grip.Error(message.WrapError(units.HandlePoisonedHost(r.Context(), as.env, currentHost, msg), message.Fields{
-				"message": "unable to disable poisoned host",
-				"host":    currentHost.Id,
-			}))
-		}
+		grip.Error(message.WrapError(units.HandlePoisonedHost(r.Context(), as.env, currentHost, msg), message.Fields{
+			"message": "unable to disable poisoned host",
+			"host":    currentHost.Id,
+		}))
 
 		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 		defer cancel()
@@ -318,7 +395,53 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	grip.Info(msg)
-	gimlet.WriteJSON(w, endTaskResp)
+	writeEndTaskResponse(w, t, details.IdempotencyKey, endTaskResp)
+}
+
+// writeEndTaskResponse writes the response to an end task request. If the
+// request has an idempotency key, the response is recorded so that it can be
+// replayed to retries of the request.
+func writeEndTaskResponse(w http.ResponseWriter, t *task.Task, idempotencyKey string, resp *apimodels.EndTaskResponse) {
+	if idempotencyKey != "" {
+		grip.Error(message.WrapError(t.SetEndTaskResponse(idempotencyKey, *resp), message.Fields{
+			"message":         "could not record end task response",
+			"task_id":         t.Id,
+			"execution":       t.Execution,
+			"idempotency_key": idempotencyKey,
+		}))
+	}
+	gimlet.WriteJSON(w, resp)
+}
+
+// replayEndTaskResponse writes the response to the end task request with the
+// given idempotency key if that request already ended the task execution. It
+// returns false if the execution was ended by a different request.
+func replayEndTaskResponse(w http.ResponseWriter, t *task.Task, idempotencyKey string) bool {
+	current, err := task.FindOneIdAndExecution(t.Id, t.Execution)
+	if err != nil {
+		gimlet.WriteResponse(w, gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", t.Id)))
+		return true
+	}
+	if current == nil || current.EndTaskRequest == nil || current.EndTaskRequest.IdempotencyKey != idempotencyKey {
+		return false
+	}
+	if current.EndTaskRequest.Response == nil {
+		// The original request is still ending the task, so the agent should
+		// retry once it is done.
+		gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    fmt.Sprintf("task '%s' execution %d is still being ended", t.Id, t.Execution),
+		}))
+		return true
+	}
+	grip.Info(message.Fields{
+		"message":         "replaying response to repeated end task request",
+		"task_id":         t.Id,
+		"execution":       t.Execution,
+		"idempotency_key": idempotencyKey,
+	})
+	gimlet.WriteJSON(w, current.EndTaskRequest.Response)
+	return true
 }
 
 func handleEndTaskForCommitQueueTask(t *task.Task, status string) error {
@@ -1050,6 +1173,7 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 	}
 	if flags.TaskDispatchDisabled {
 		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), "task dispatch is disabled, returning no task")
+		response.BackoffSecs = int(model.GetLoadShedder().Backoff(as.env).Seconds())
 		gimlet.WriteJSON(w, response)
 		return
 	}
@@ -1060,6 +1184,22 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// In maintenance mode, hosts can finish the tasks they already have but
+	// don't get new ones.
+	maintenanceMode := evergreen.MaintenanceModeConfig{}
+	if err = maintenanceMode.Get(as.env); err != nil {
+		err = errors.Wrap(err, "error retrieving maintenance mode settings")
+		grip.Error(err)
+		gimlet.WriteResponse(w, gimlet.MakeJSONInternalErrorResponder(err))
+		return
+	}
+	if maintenanceMode.Enabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), "app server is in maintenance mode, returning no task")
+		response.BackoffSecs = int(maintenanceMode.GetRetryAfter().Seconds())
+		gimlet.WriteJSON(w, response)
+		return
+	}
+
 	var nextTask *task.Task
 	var shouldRunTeardown bool
 
@@ -1122,6 +1262,7 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 				"message": "no task to assign to host",
 				"host_id": h.Id,
 			})
+			response.BackoffSecs = int(model.GetLoadShedder().Backoff(as.env).Seconds())
 		}
 
 		gimlet.WriteJSON(w, response)
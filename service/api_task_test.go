@@ -1448,6 +1448,29 @@ func TestTaskLifecycleEndpoints(t *testing.T) {
 				})
 			})
 		})
+		Convey("with a repeated end task request with the same idempotency key", func() {
+			details := &apimodels.TaskEndDetail{
+				Status:         evergreen.TaskSucceeded,
+				IdempotencyKey: "key",
+			}
+			resp := getEndTaskEndpoint(t, as, hostId, task1.Id, details)
+			So(resp.Code, ShouldEqual, http.StatusOK)
+			t1, err := task.FindOneId(task1.Id)
+			So(err, ShouldBeNil)
+			So(t1.EndTaskRequest, ShouldNotBeNil)
+			So(t1.EndTaskRequest.IdempotencyKey, ShouldEqual, "key")
+			So(t1.EndTaskRequest.Response, ShouldNotBeNil)
+
+			Convey("the original response should be replayed without ending the task again", func() {
+				resp = getEndTaskEndpoint(t, as, hostId, task1.Id, details)
+				So(resp.Code, ShouldEqual, http.StatusOK)
+				taskResp := apimodels.EndTaskResponse{}
+				So(json.NewDecoder(resp.Body).Decode(&taskResp), ShouldBeNil)
+				// The host no longer has a running task, so the agent would
+				// be told to exit if the task were ended again.
+				So(taskResp.ShouldExit, ShouldBeFalse)
+			})
+		})
 		Convey("with a set of task end details indicating that task has failed", func() {
 			details := &apimodels.TaskEndDetail{
 				Status: evergreen.TaskFailed,
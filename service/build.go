@@ -191,7 +191,7 @@ func (uis *UIServer) modifyBuild(w http.ResponseWriter, r *http.Request) {
 				"message": "unable to send github status",
 				"patch":   projCtx.Build.Version,
 			}))
-			err = model.RestartItemsAfterVersion(nil, projCtx.Build.Project, projCtx.Build.Version, user.Id)
+			_, err = model.RestartItemsAfterVersion(nil, projCtx.Build.Project, projCtx.Build.Version, user.Id, false)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
@@ -233,6 +233,10 @@ func (uis *UIServer) modifyBuild(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "commit queue merges cannot be manually scheduled", http.StatusBadRequest)
 		}
 		err = model.SetBuildActivation(projCtx.Build.Id, putParams.Active, user.Id)
+		if model.IsVariantActivationDenied(err) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Error marking build %v as activated=%v", projCtx.Build.Id, putParams.Active),
 				http.StatusInternalServerError)
@@ -259,7 +263,7 @@ func (uis *UIServer) modifyBuild(w http.ResponseWriter, r *http.Request) {
 				"message": "unable to send github status",
 				"patch":   projCtx.Build.Version,
 			}))
-			err = model.RestartItemsAfterVersion(nil, projCtx.Build.Project, projCtx.Build.Version, user.Id)
+			_, err = model.RestartItemsAfterVersion(nil, projCtx.Build.Project, projCtx.Build.Version, user.Id, false)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
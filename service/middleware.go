@@ -29,6 +29,7 @@ const (
 	// These are private custom types to avoid key collisions.
 	RequestTask reqCtxKey = iota
 	RequestProjectContext
+	RequestAgentPayload
 )
 
 // projectContext defines the set of common fields required across most UI requests.
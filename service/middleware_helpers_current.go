@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"net/http"
+	"sync"
 
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/host"
@@ -16,7 +17,7 @@ func setAPIHostContext(r *http.Request, h *host.Host) *http.Request {
 func setAPITaskContext(r *http.Request, t *task.Task) *http.Request {
 	return r.WithContext(context.WithValue(r.Context(), model.ApiTaskKey, t))
 }
-func setProjectContext(r *http.Request, p *model.Project) *http.Request {
+func setProjectContext(r *http.Request, p *lazyProject) *http.Request {
 	return r.WithContext(context.WithValue(r.Context(), model.ApiProjectKey, p))
 }
 func setUIRequestContext(r *http.Request, p projectContext) *http.Request {
@@ -44,15 +45,50 @@ func GetHost(r *http.Request) *host.Host {
 	return nil
 }
 
+// errProjectConfigNotFound indicates that a project has no valid config to
+// load.
+var errProjectConfigNotFound = errors.New("project config not found")
+
+// lazyProject loads the project attached to a request the first time that a
+// handler asks for it, loading only the parts of the project config that its
+// route needs.
+type lazyProject struct {
+	projectID  string
+	projection model.ProjectProjection
+
+	once    sync.Once
+	project *model.Project
+	err     error
+}
+
+func (lp *lazyProject) get() (*model.Project, error) {
+	lp.once.Do(func() {
+		_, lp.project, lp.err = model.FindLatestVersionWithValidProjectProjection(lp.projectID, lp.projection)
+		if lp.err == nil && lp.project == nil {
+			lp.err = errors.Wrapf(errProjectConfigNotFound, "project '%s'", lp.projectID)
+		}
+	})
+	return lp.project, lp.err
+}
+
 // GetProject loads the project attached to a request into request
 // context.
-func GetProject(r *http.Request) *model.Project {
+func GetProject(r *http.Request) (*model.Project, error) {
 	p := r.Context().Value(model.ApiProjectKey)
 	if p == nil {
-		return nil
+		return nil, errors.New("no project attached to request")
 	}
 
-	return p.(*model.Project)
+	return p.(*lazyProject).get()
+}
+
+// getProjectErrorStatus returns the HTTP status code for an error from
+// GetProject.
+func getProjectErrorStatus(err error) int {
+	if errors.Cause(err) == errProjectConfigNotFound {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
 }
 
 // GetProjectContext fetches the projectContext associated with the request. Returns an error
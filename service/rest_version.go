@@ -404,7 +404,26 @@ func (restapi restAPI) modifyVersionInfo(w http.ResponseWriter, r *http.Request)
 	}
 
 	if input.Activated != nil {
+		if *input.Activated && projCtx.ProjectRef != nil {
+			numTasks, err := model.CountTasksToActivateForVersion(v.Id, user.Id)
+			if err != nil {
+				gimlet.WriteJSONInternalError(w, responseError{Message: "error counting tasks to activate"})
+				return
+			}
+			if err := model.CheckVersionActivationQuotas(projCtx.ProjectRef, v, numTasks); err != nil {
+				if model.IsProjectQuotaExceeded(err) {
+					gimlet.WriteJSONError(w, responseError{Message: err.Error()})
+					return
+				}
+				gimlet.WriteJSONInternalError(w, responseError{Message: "error checking project quotas"})
+				return
+			}
+		}
 		if err := model.SetVersionActivation(v.Id, *input.Activated, user.Id); err != nil {
+			if model.IsVariantActivationDenied(err) {
+				gimlet.WriteJSONResponse(w, http.StatusForbidden, responseError{Message: err.Error()})
+				return
+			}
 			state := "inactive"
 			if *input.Activated {
 				state = "active"
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/secretscan"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+// shouldScanForSecrets returns whether the task's project scans the content
+// its tasks upload for secrets. Content is not scanned if the project can't be
+// found, so that uploads aren't rejected because of the scan.
+func shouldScanForSecrets(t *task.Task) bool {
+	pRef, err := model.FindMergedProjectRef(t.Project, t.Version, false)
+	if err != nil || pRef == nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "could not find project to check whether to scan task uploads for secrets",
+			"task_id": t.Id,
+			"project": t.Project,
+		}))
+		return false
+	}
+	return pRef.IsSecretsScanningEnabled()
+}
+
+// redactTaskLogSecrets redacts the secrets in the first
+// secretscan.DefaultMaxBytes of the log messages, if the task's project scans
+// for secrets.
+func redactTaskLogSecrets(t *task.Task, taskLog *model.TaskLog) {
+	if !shouldScanForSecrets(t) {
+		return
+	}
+	scanner := secretscan.NewScanner(secretscan.DefaultMaxBytes)
+	findings := []secretscan.Finding{}
+	for i := 0; i < len(taskLog.Messages) && !scanner.Done(); i++ {
+		msg := &taskLog.Messages[i]
+		var matches []secretscan.Match
+		msg.Message, matches = scanner.Scan(msg.Message)
+		location := fmt.Sprintf("%s log message at %s", msg.Type, msg.Timestamp.Format(time.RFC3339))
+		findings = append(findings, secretscan.NewFindings(t.Id, t.Execution, t.Project, secretscan.SourceTaskLog, location, matches)...)
+	}
+	recordSecretFindings(t, findings)
+}
+
+// redactArtifactSecrets redacts the secrets in the metadata of the artifact
+// files, if the task's project scans for secrets.
+func redactArtifactSecrets(t *task.Task, files []artifact.File) {
+	if !shouldScanForSecrets(t) {
+		return
+	}
+	scanner := secretscan.NewScanner(secretscan.DefaultMaxBytes)
+	findings := []secretscan.Finding{}
+	for i := 0; i < len(files) && !scanner.Done(); i++ {
+		file := &files[i]
+		for _, field := range []struct {
+			name  string
+			value *string
+		}{
+			{name: "name", value: &file.Name},
+			{name: "link", value: &file.Link},
+			{name: "file key", value: &file.FileKey},
+		} {
+			var matches []secretscan.Match
+			*field.value, matches = scanner.Scan(*field.value)
+			location := fmt.Sprintf("%s of artifact file %d", field.name, i)
+			findings = append(findings, secretscan.NewFindings(t.Id, t.Execution, t.Project, secretscan.SourceArtifact, location, matches)...)
+		}
+	}
+	recordSecretFindings(t, findings)
+}
+
+// recordSecretFindings saves the secrets that were found in the task's
+// uploads and flags the task with an event.
+func recordSecretFindings(t *task.Task, findings []secretscan.Finding) {
+	if len(findings) == 0 {
+		return
+	}
+	rules := []string{}
+	for _, f := range findings {
+		rules = append(rules, f.Rule)
+	}
+	grip.Warning(message.Fields{
+		"message":      "redacted secrets uploaded by task",
+		"task_id":      t.Id,
+		"execution":    t.Execution,
+		"project":      t.Project,
+		"num_findings": len(findings),
+		"rules":        rules,
+	})
+	grip.Error(message.WrapError(secretscan.InsertFindings(findings), message.Fields{
+		"message":   "could not save secrets found in task uploads",
+		"task_id":   t.Id,
+		"execution": t.Execution,
+	}))
+	event.LogTaskSecretDetected(t.Id, t.Execution, len(findings))
+}
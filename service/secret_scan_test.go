@@ -0,0 +1,59 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/secretscan"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachFilesForTasksRedactsSecrets(t *testing.T) {
+	require.NoError(t, db.ClearCollections(model.ProjectRefCollection, task.Collection, artifact.Collection, secretscan.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(model.ProjectRefCollection, task.Collection, artifact.Collection, secretscan.Collection))
+	}()
+
+	env := evergreen.GetEnvironment()
+	as, err := NewAPIServer(env, env.LocalQueue())
+	require.NoError(t, err)
+
+	pRef := model.ProjectRef{Id: "p1", SecretsScanning: utility.TruePtr()}
+	require.NoError(t, pRef.Insert())
+	tsk := task.Task{Id: "t1", Project: "p1", BuildId: "b1"}
+	require.NoError(t, tsk.Insert())
+
+	const signature = "0123456789abcdef0123456789abcdef"
+	batch := []artifact.TaskFiles{{
+		TaskID: tsk.Id,
+		Files: []artifact.File{{
+			Name: "dist",
+			Link: "https://bucket.s3.amazonaws.com/dist.tgz?X-Amz-Signature=" + signature,
+		}},
+	}}
+	body, err := json.Marshal(batch)
+	require.NoError(t, err)
+	r, err := http.NewRequest(http.MethodPost, "/task/t1/files/batch", bytes.NewReader(body))
+	require.NoError(t, err)
+	r = setAPITaskContext(r, &tsk)
+	w := httptest.NewRecorder()
+	as.AttachFilesForTasks(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	entry, err := artifact.FindOne(artifact.ByTaskId(tsk.Id))
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	require.Len(t, entry.Files, 1)
+	assert.NotContains(t, entry.Files[0].Link, signature)
+	assert.Contains(t, entry.Files[0].Link, "[REDACTED:url_signature]")
+}
@@ -36,6 +36,7 @@ func GetServer(addr string, n http.Handler) *http.Server {
 		ReadTimeout:       time.Minute,
 		ReadHeaderTimeout: 30 * time.Second,
 		WriteTimeout:      time.Minute,
+		ConnContext:       route.ConnContext,
 	}
 }
 
@@ -731,7 +731,22 @@ func (uis *UIServer) taskModify(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "commit queue tasks cannot be manually scheduled", http.StatusBadRequest)
 			return
 		}
+		if active {
+			if err = model.CheckTaskActivationQuotas(authUser.Username(), []task.Task{*projCtx.Task}); err != nil {
+				if model.IsProjectQuotaExceeded(err) {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				http.Error(w, fmt.Sprintf("Error checking project quotas for task %v: %v", projCtx.Task.Id, err),
+					http.StatusInternalServerError)
+				return
+			}
+		}
 		if err = model.SetActiveState(authUser.Username(), active, *projCtx.Task); err != nil {
+			if model.IsVariantActivationDenied(err) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
 			http.Error(w, fmt.Sprintf("Error activating task %v: %v", projCtx.Task.Id, err),
 				http.StatusInternalServerError)
 			return
@@ -41,7 +41,7 @@ type UIServer struct {
 	Settings     evergreen.Settings
 	CookieStore  *sessions.CookieStore
 	clientConfig *evergreen.ClientConfig
-	jiraHandler  thirdparty.JiraHandler
+	jiraHandler  thirdparty.JiraIntegration
 
 	hostCache map[string]hostCacheItem
 
@@ -99,7 +99,7 @@ func NewUIServer(env evergreen.Environment, queue amboy.Queue, home string, fo T
 		CookieStore:  cookieStore,
 		render:       gimlet.NewHTMLRenderer(ropts),
 		renderText:   gimlet.NewTextRenderer(ropts),
-		jiraHandler:  thirdparty.NewJiraHandler(*settings.Jira.Export()),
+		jiraHandler:  thirdparty.NewJiraIntegration(*settings.Jira.Export()),
 		umconf: gimlet.UserMiddlewareConfiguration{
 			HeaderKeyName:  evergreen.APIKeyHeader,
 			HeaderUserName: evergreen.APIUserHeader,
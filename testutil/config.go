@@ -184,9 +184,15 @@ func MockConfig() *evergreen.Settings {
 				},
 			},
 		},
-		Credentials:        map[string]string{"k1": "v1"},
-		DomainName:         "example.com",
-		Expansions:         map[string]string{"k2": "v2"},
+		Credentials: map[string]string{"k1": "v1"},
+		DomainName:  "example.com",
+		Expansions:  map[string]string{"k2": "v2"},
+		GenerateTasksLimits: evergreen.GenerateTasksLimitsConfig{
+			MaxTasksPerVersion:        10000,
+			MaxVariantsPerVersion:     100,
+			MaxConfigSizeMBPerVersion: 4,
+			ExemptProjects:            []string{"exempt_project"},
+		},
 		GithubPRCreatorOrg: "org",
 		HostInit: evergreen.HostInitConfig{
 			HostThrottle:         64,
@@ -211,6 +217,15 @@ func MockConfig() *evergreen.Settings {
 			DefaultProject: "proj",
 		},
 		Keys: map[string]string{"k3": "v3"},
+		LoadShedder: evergreen.LoadShedderConfig{
+			DBLatencyThresholdMS: 500,
+			QueueDepthThreshold:  1000,
+			MinBackoffSecs:       10,
+			MaxBackoffSecs:       60,
+		},
+		MaintenanceMode: evergreen.MaintenanceModeConfig{
+			RetryAfterSecs: 30,
+		},
 		LoggerConfig: evergreen.LoggerConfig{
 			Buffer: evergreen.LogBuffering{
 				UseAsync:             true,
@@ -378,6 +393,23 @@ func MockConfig() *evergreen.Settings {
 			Token:     "token",
 			Channel:   "channel",
 		},
+		AnnotationAttachments: evergreen.AnnotationAttachmentsConfig{
+			Backend: evergreen.AnnotationAttachmentBackendS3,
+			S3: evergreen.S3Credentials{
+				Key:    "key",
+				Secret: "secret",
+				Bucket: "annotation_attachments",
+			},
+			MaxSizeBytes:        2048,
+			AllowedContentTypes: []string{"image/png", "text/html"},
+		},
+		Tracer: evergreen.TracerConfig{
+			Enabled:      true,
+			Exporter:     evergreen.TracerExporterLog,
+			SampleRatio:  0.5,
+			OTLPEndpoint: "http://localhost:4318",
+			OTLPHeaders:  map[string]string{"x-api-key": "key"},
+		},
 		Triggers: evergreen.TriggerConfig{
 			GenerateTaskDistro: "distro",
 		},
@@ -134,14 +134,14 @@ func getGithubClientRetryWith404s(token, caller string) *http.Client {
 		"message": "called getGithubClientRetryWith404s",
 		"caller":  caller,
 	})
-	return utility.GetOauth2CustomHTTPRetryableClient(
+	return withGithubBudget(utility.GetOauth2CustomHTTPRetryableClient(
 		token,
 		githubShouldRetryWith404s,
 		utility.RetryHTTPDelay(utility.RetryOptions{
 			MaxAttempts: NumGithubAttempts,
 			MinDelay:    GithubRetryMinDelay,
 		}),
-	)
+	), token)
 }
 
 func getGithubClient(token, caller string) *http.Client {
@@ -150,21 +150,21 @@ func getGithubClient(token, caller string) *http.Client {
 		"message": "called getGithubClient",
 		"caller":  caller,
 	})
-	return utility.GetOauth2CustomHTTPRetryableClient(
+	return withGithubBudget(utility.GetOauth2CustomHTTPRetryableClient(
 		token,
 		githubShouldRetry,
 		utility.RetryHTTPDelay(utility.RetryOptions{
 			MaxAttempts: NumGithubAttempts,
 			MinDelay:    GithubRetryMinDelay,
 		}),
-	)
+	), token)
 }
 
 // GetGithubCommits returns a slice of GithubCommit objects from
 // the given commitsURL when provided a valid oauth token
-func GetGithubCommits(ctx context.Context, oauthToken, owner, repo, ref string, until time.Time, commitPage int) ([]*github.RepositoryCommit, int, error) {
+func (liveGithubIntegration) GetGithubCommits(ctx context.Context, oauthToken, owner, repo, ref string, until time.Time, commitPage int) ([]*github.RepositoryCommit, int, error) {
 	httpClient := getGithubClient(oauthToken, "GetGithubCommits")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 	options := github.CommitsListOptions{
 		SHA: ref,
@@ -205,9 +205,9 @@ func parseGithubErrorResponse(resp *github.Response) error {
 
 // GetGithubFile returns a struct that contains the contents of files within
 // a repository as Base64 encoded content. Ref should be the commit hash or branch (defaults to master).
-func GetGithubFile(ctx context.Context, oauthToken, owner, repo, path, ref string) (*github.RepositoryContent, error) {
+func (liveGithubIntegration) GetGithubFile(ctx context.Context, oauthToken, owner, repo, path, ref string) (*github.RepositoryContent, error) {
 	httpClient := getGithubClient(oauthToken, "GetGithubFile")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 
 	var opt *github.RepositoryContentGetOptions
@@ -239,12 +239,12 @@ func GetGithubFile(ctx context.Context, oauthToken, owner, repo, path, ref strin
 	return file, nil
 }
 
-func GetGithubMergeBaseRevision(ctx context.Context, oauthToken, repoOwner, repo, baseRevision, currentCommitHash string) (string, error) {
+func (liveGithubIntegration) GetGithubMergeBaseRevision(ctx context.Context, oauthToken, repoOwner, repo, baseRevision, currentCommitHash string) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	httpClient := getGithubClient(oauthToken, "GetGithubMergeBaseRevision")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 
 	compare, resp, err := client.Repositories.CompareCommits(ctx,
@@ -267,9 +267,9 @@ func GetGithubMergeBaseRevision(ctx context.Context, oauthToken, repoOwner, repo
 	return *compare.MergeBaseCommit.SHA, nil
 }
 
-func GetCommitEvent(ctx context.Context, oauthToken, repoOwner, repo, githash string) (*github.RepositoryCommit, error) {
+func (liveGithubIntegration) GetCommitEvent(ctx context.Context, oauthToken, repoOwner, repo, githash string) (*github.RepositoryCommit, error) {
 	httpClient := getGithubClient(oauthToken, "GetCommitEvent")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 
 	grip.Info(message.Fields{
@@ -314,9 +314,9 @@ func GetCommitEvent(ctx context.Context, oauthToken, repoOwner, repo, githash st
 }
 
 // GetCommitDiff gets the diff of the specified commit via an API call to GitHub
-func GetCommitDiff(ctx context.Context, oauthToken, repoOwner, repo, sha string) (string, error) {
+func (liveGithubIntegration) GetCommitDiff(ctx context.Context, oauthToken, repoOwner, repo, sha string) (string, error) {
 	httpClient := getGithubClient(oauthToken, "GetCommitDiff")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 
 	commit, resp, err := client.Repositories.GetCommitRaw(ctx, repoOwner, repo, sha, github.RawOptions{Type: github.Diff})
@@ -340,9 +340,9 @@ func GetCommitDiff(ctx context.Context, oauthToken, repoOwner, repo, sha string)
 }
 
 // GetBranchEvent gets the head of the a given branch via an API call to GitHub
-func GetBranchEvent(ctx context.Context, oauthToken, repoOwner, repo, branch string) (*github.Branch, error) {
+func (liveGithubIntegration) GetBranchEvent(ctx context.Context, oauthToken, repoOwner, repo, branch string) (*github.Branch, error) {
 	httpClient := getGithubClient(oauthToken, "GetBranchEvent")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 
 	grip.Debugf("requesting github commit for '%s/%s': branch: %s\n", repoOwner, repo, branch)
@@ -495,9 +495,9 @@ func GithubAuthenticate(ctx context.Context, code, clientId, clientSecret string
 }
 
 // GetTaggedCommitFromGithub gets the commit SHA for the given tag name.
-func GetTaggedCommitFromGithub(ctx context.Context, oauthToken, owner, repo, tag string) (string, error) {
+func (liveGithubIntegration) GetTaggedCommitFromGithub(ctx context.Context, oauthToken, owner, repo, tag string) (string, error) {
 	client := getGithubClient(oauthToken, "GetTaggedCommitFromGithub")
-	defer utility.PutHTTPClient(client)
+	defer putGithubClient(client)
 
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/refs/tags/%s", owner, repo, tag)
 	resp, err := client.Get(url)
@@ -547,7 +547,7 @@ func GetTaggedCommitFromGithub(ctx context.Context, oauthToken, owner, repo, tag
 
 func IsUserInGithubTeam(ctx context.Context, teams []string, org, user, oauthToken string) bool {
 	httpClient := getGithubClient(oauthToken, "IsUserInGithubTeam")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 
 	grip.Info(message.Fields{
@@ -571,7 +571,7 @@ func IsUserInGithubTeam(ctx context.Context, teams []string, org, user, oauthTok
 // and error
 func GetGithubTokenUser(ctx context.Context, token string, requiredOrg string) (*GithubLoginUser, bool, error) {
 	httpClient := getGithubClient(fmt.Sprintf("token %s", token), "GetGithubTokenUser")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 
 	user, resp, err := client.Users.Get(ctx, "")
@@ -615,7 +615,7 @@ func GetGithubTokenUser(ctx context.Context, token string, requiredOrg string) (
 // CheckGithubAPILimit queries Github for the number of API requests remaining
 func CheckGithubAPILimit(ctx context.Context, oauthToken string) (int64, error) {
 	httpClient := getGithubClient(oauthToken, "CheckGithubAPILimit")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 
 	limits, resp, err := client.RateLimits(ctx)
@@ -640,7 +640,7 @@ func CheckGithubAPILimit(ctx context.Context, oauthToken string) (int64, error)
 // GetGithubUser fetches the github user with the given login name
 func GetGithubUser(ctx context.Context, oauthToken, loginName string) (*github.User, error) {
 	httpClient := getGithubClient(oauthToken, "GetGithubUser")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 
 	user, _, err := client.Users.Get(ctx, loginName)
@@ -660,7 +660,7 @@ func GetGithubUser(ctx context.Context, oauthToken, loginName string) (*github.U
 // visibility into organization membership, including private members
 func GithubUserInOrganization(ctx context.Context, token, requiredOrganization, username string) (bool, error) {
 	httpClient := getGithubClient(token, "GithubUserInOrganization")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 
 	client := github.NewClient(httpClient)
 
@@ -682,7 +682,7 @@ func GithubUserInOrganization(ctx context.Context, token, requiredOrganization,
 
 func GitHubUserPermissionLevel(ctx context.Context, token, owner, repo, username string) (string, error) {
 	httpClient := getGithubClientRetryWith404s(token, "GithubUserPermissionLevel")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 
 	client := github.NewClient(httpClient)
 
@@ -700,9 +700,9 @@ func GitHubUserPermissionLevel(ctx context.Context, token, owner, repo, username
 // GetPullRequestMergeBase returns the merge base hash for the given PR.
 // This function will retry up to 5 times, regardless of error response (unless
 // error is the result of hitting an api limit)
-func GetPullRequestMergeBase(ctx context.Context, token string, data GithubPatch) (string, error) {
+func (liveGithubIntegration) GetPullRequestMergeBase(ctx context.Context, token string, data GithubPatch) (string, error) {
 	httpClient := getGithubClientRetryWith404s(token, "GetPullRequestMergeBase")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 
 	client := github.NewClient(httpClient)
 
@@ -734,9 +734,9 @@ func GetPullRequestMergeBase(ctx context.Context, token string, data GithubPatch
 	return *commit.Parents[0].SHA, nil
 }
 
-func GetGithubPullRequest(ctx context.Context, token, baseOwner, baseRepo string, PRNumber int) (*github.PullRequest, error) {
+func (liveGithubIntegration) GetGithubPullRequest(ctx context.Context, token, baseOwner, baseRepo string, PRNumber int) (*github.PullRequest, error) {
 	httpClient := getGithubClientRetryWith404s(token, "GetGithubPullRequest")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 
 	client := github.NewClient(httpClient)
 
@@ -748,9 +748,9 @@ func GetGithubPullRequest(ctx context.Context, token, baseOwner, baseRepo string
 	return pr, nil
 }
 
-func GetGithubPullRequestCommits(ctx context.Context, token, owner, repo string, PRNumber int) ([]*github.RepositoryCommit, error) {
+func (liveGithubIntegration) GetGithubPullRequestCommits(ctx context.Context, token, owner, repo string, PRNumber int) ([]*github.RepositoryCommit, error) {
 	httpClient := getGithubClientRetryWith404s(token, "GetGithubPullRequestCommits")
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 
 	client := github.NewClient(httpClient)
 
@@ -766,10 +766,10 @@ func GetGithubPullRequestCommits(ctx context.Context, token, owner, repo string,
 }
 
 // GetGithubPullRequestDiff downloads a diff from a Github Pull Request diff
-func GetGithubPullRequestDiff(ctx context.Context, token string, gh GithubPatch) (string, []Summary, error) {
+func (liveGithubIntegration) GetGithubPullRequestDiff(ctx context.Context, token string, gh GithubPatch) (string, []Summary, error) {
 	httpClient := getGithubClientRetryWith404s(token, "GetGithubPullRequestDiff")
 
-	defer utility.PutHTTPClient(httpClient)
+	defer putGithubClient(httpClient)
 	client := github.NewClient(httpClient)
 
 	diff, _, err := client.PullRequests.GetRaw(ctx, gh.BaseOwner, gh.BaseRepo, gh.PRNumber, github.RawOptions{Type: github.Diff})
@@ -884,12 +884,34 @@ func SendCommitQueueGithubStatus(pr *github.PullRequest, state message.GithubSta
 		URL:         url,
 	}
 
+	if err = ReserveGithubStatus(GithubPriorityCritical); err != nil {
+		return errors.Wrap(err, "reserving GitHub budget for commit queue status")
+	}
 	c := message.NewGithubStatusMessageWithRepo(level.Notice, msg)
 	sender.Send(c)
 
 	return nil
 }
 
+// PostGithubStatus posts the status to GitHub and waits for GitHub to accept
+// it, unlike the GitHub status sender, which doesn't report failures.
+func PostGithubStatus(ctx context.Context, oauthToken string, status message.GithubStatus) error {
+	httpClient := getGithubClient(oauthToken, "PostGithubStatus")
+	defer putGithubClient(httpClient)
+	client := github.NewClient(httpClient)
+
+	repoStatus := &github.RepoStatus{
+		State:       github.String(string(status.State)),
+		Context:     github.String(status.Context),
+		Description: github.String(status.Description),
+	}
+	if status.URL != "" {
+		repoStatus.TargetURL = github.String(status.URL)
+	}
+	_, _, err := client.Repositories.CreateStatus(ctx, status.Owner, status.Repo, status.Ref, repoStatus)
+	return errors.Wrapf(err, "posting status '%s' for ref '%s' in repo '%s/%s'", status.Context, status.Ref, status.Owner, status.Repo)
+}
+
 func GetPullRequest(ctx context.Context, issue int, githubToken, owner, repo string) (*github.PullRequest, error) {
 	pr, err := GetGithubPullRequest(ctx, githubToken, owner, repo, issue)
 	if err != nil {
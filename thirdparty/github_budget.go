@@ -0,0 +1,457 @@
+package thirdparty
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/utility"
+	"github.com/google/go-github/v34/github"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GithubCallPriority is how important an outbound GitHub call is. When an
+// installation's rate limit runs low, less important calls are deferred so
+// that the remaining budget is spent on the most important ones.
+type GithubCallPriority int
+
+const (
+	// GithubPriorityCritical calls, such as posting commit queue results,
+	// are only deferred once the rate limit is exhausted.
+	GithubPriorityCritical GithubCallPriority = iota
+	// GithubPriorityNormal calls, such as fetching pull requests or posting
+	// patch statuses, are deferred when the rate limit is nearly exhausted.
+	GithubPriorityNormal
+	// GithubPriorityCosmetic calls, such as posting per-variant checks, are
+	// deferred first.
+	GithubPriorityCosmetic
+)
+
+const (
+	// GithubStatusInstallation is the installation that GitHub statuses are
+	// charged to. Statuses are posted by the GitHub status sender, whose
+	// responses aren't visible to Evergreen, so its budget is estimated.
+	// Since GitHub can't correct the estimate, it's shared by every process
+	// through the GithubStatusBudgetCollection.
+	GithubStatusInstallation = "status-sender"
+
+	// GithubStatusBudgetCollection stores the status sender's budget for
+	// each rate limit window.
+	GithubStatusBudgetCollection = "github_status_budgets"
+
+	// defaultGithubRateLimit and defaultGithubRateLimitWindow are GitHub's
+	// hourly rate limit for an OAuth token, which are assumed for
+	// installations until GitHub reports their actual rate limit.
+	defaultGithubRateLimit       = 5000
+	defaultGithubRateLimitWindow = time.Hour
+)
+
+var githubCallPriorityNames = map[GithubCallPriority]string{
+	GithubPriorityCritical: "critical",
+	GithubPriorityNormal:   "normal",
+	GithubPriorityCosmetic: "cosmetic",
+}
+
+func (p GithubCallPriority) String() string {
+	if name, ok := githubCallPriorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", int(p))
+}
+
+// reserve returns the part of the rate limit that calls of this priority
+// can't spend.
+func (p GithubCallPriority) reserve(limit int) int {
+	switch p {
+	case GithubPriorityCritical:
+		return 0
+	case GithubPriorityCosmetic:
+		return limit / 5
+	default:
+		return limit / 20
+	}
+}
+
+type githubCallPriorityKey struct{}
+
+// WithGithubCallPriority returns a context whose GitHub API calls are made
+// with the given priority. Calls are normal priority by default.
+func WithGithubCallPriority(ctx context.Context, priority GithubCallPriority) context.Context {
+	return context.WithValue(ctx, githubCallPriorityKey{}, priority)
+}
+
+func githubCallPriorityFromContext(ctx context.Context) GithubCallPriority {
+	if priority, ok := ctx.Value(githubCallPriorityKey{}).(GithubCallPriority); ok {
+		return priority
+	}
+	return GithubPriorityNormal
+}
+
+// GithubBudgetDeferredError is returned for a GitHub call that was deferred
+// because the installation doesn't have enough of its rate limit left for
+// the call's priority.
+type GithubBudgetDeferredError struct {
+	Installation string
+	Priority     GithubCallPriority
+	Remaining    int
+	// RetryAt is when the installation's rate limit resets.
+	RetryAt time.Time
+}
+
+func (e *GithubBudgetDeferredError) Error() string {
+	return fmt.Sprintf("deferred %s GitHub call for installation '%s' with %d calls remaining until %s",
+		e.Priority, e.Installation, e.Remaining, e.RetryAt.Format(time.RFC3339))
+}
+
+// IsGithubBudgetDeferred returns the deferral if the error is because a
+// GitHub call was deferred.
+func IsGithubBudgetDeferred(err error) (*GithubBudgetDeferredError, bool) {
+	var deferred *GithubBudgetDeferredError
+	if errors.As(err, &deferred) {
+		return deferred, true
+	}
+	return nil, false
+}
+
+// GithubCallCounts are the number of calls of a priority that an installation
+// has made or deferred.
+type GithubCallCounts struct {
+	Allowed  int `bson:"allowed"`
+	Deferred int `bson:"deferred"`
+}
+
+// GithubBudgetStatus is the current rate limit budget of an installation.
+type GithubBudgetStatus struct {
+	Installation string
+	Limit        int
+	Remaining    int
+	Reset        time.Time
+	// Estimated is true if GitHub hasn't reported the installation's rate
+	// limit, so the budget is counted by Evergreen.
+	Estimated bool
+	UpdatedAt time.Time
+	Calls     map[string]GithubCallCounts
+}
+
+type githubBudget struct {
+	limit     int
+	remaining int
+	reset     time.Time
+	estimated bool
+	updatedAt time.Time
+	calls     map[GithubCallPriority]GithubCallCounts
+}
+
+// githubBudgetManager tracks the remaining rate limit of each installation
+// that Evergreen calls GitHub with and decides which calls to make with it.
+// Budgets are tracked by each process separately; the rate limit that GitHub
+// reports with every response corrects for the calls of other processes.
+type githubBudgetManager struct {
+	mu      sync.Mutex
+	budgets map[string]*githubBudget
+}
+
+var githubBudgets = newGithubBudgetManager()
+
+func newGithubBudgetManager() *githubBudgetManager {
+	return &githubBudgetManager{budgets: map[string]*githubBudget{}}
+}
+
+// get returns the installation's budget, refilling it if its rate limit has
+// reset. The caller must hold the lock.
+func (m *githubBudgetManager) get(installation string, now time.Time) *githubBudget {
+	b, ok := m.budgets[installation]
+	if !ok {
+		b = &githubBudget{
+			limit:     defaultGithubRateLimit,
+			remaining: defaultGithubRateLimit,
+			reset:     now.Add(defaultGithubRateLimitWindow),
+			estimated: true,
+			updatedAt: now,
+			calls:     map[GithubCallPriority]GithubCallCounts{},
+		}
+		m.budgets[installation] = b
+	}
+	if !now.Before(b.reset) {
+		b.remaining = b.limit
+		b.reset = now.Add(defaultGithubRateLimitWindow)
+		b.estimated = true
+		b.updatedAt = now
+	}
+	return b
+}
+
+// reserve spends one call of the installation's budget on a call of the given
+// priority, or returns a GithubBudgetDeferredError if the call should wait
+// until the rate limit resets.
+func (m *githubBudgetManager) reserve(installation string, priority GithubCallPriority, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := m.get(installation, now)
+	counts := b.calls[priority]
+	if b.remaining <= priority.reserve(b.limit) {
+		counts.Deferred++
+		b.calls[priority] = counts
+		return &GithubBudgetDeferredError{
+			Installation: installation,
+			Priority:     priority,
+			Remaining:    b.remaining,
+			RetryAt:      b.reset,
+		}
+	}
+	counts.Allowed++
+	b.calls[priority] = counts
+	b.remaining--
+	return nil
+}
+
+// update records the installation's rate limit as reported by GitHub.
+func (m *githubBudgetManager) update(installation string, rate github.Rate, now time.Time) {
+	if rate.Limit == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := m.get(installation, now)
+	b.limit = rate.Limit
+	b.remaining = rate.Remaining
+	if !rate.Reset.Time.IsZero() {
+		b.reset = rate.Reset.Time
+	}
+	b.estimated = false
+	b.updatedAt = now
+}
+
+func (m *githubBudgetManager) status(now time.Time) []GithubBudgetStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]GithubBudgetStatus, 0, len(m.budgets))
+	for installation := range m.budgets {
+		b := m.get(installation, now)
+		status := GithubBudgetStatus{
+			Installation: installation,
+			Limit:        b.limit,
+			Remaining:    b.remaining,
+			Reset:        b.reset,
+			Estimated:    b.estimated,
+			UpdatedAt:    b.updatedAt,
+			Calls:        map[string]GithubCallCounts{},
+		}
+		for priority, counts := range b.calls {
+			status.Calls[priority.String()] = counts
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Installation < statuses[j].Installation
+	})
+	return statuses
+}
+
+// GetGithubBudgetStatus returns the shared budget of the status sender and
+// the current rate limit budget of every installation that this process has
+// called GitHub with.
+func GetGithubBudgetStatus() ([]GithubBudgetStatus, error) {
+	now := time.Now()
+	statusBudget, err := findGithubStatusBudget(now)
+	if err != nil {
+		return nil, err
+	}
+	return append([]GithubBudgetStatus{statusBudget.status(now)}, githubBudgets.status(now)...), nil
+}
+
+// githubStatusBudget is the status sender's budget for one rate limit window.
+type githubStatusBudget struct {
+	ID        string                      `bson:"_id"`
+	Reset     time.Time                   `bson:"reset"`
+	Spent     int                         `bson:"spent"`
+	Calls     map[string]GithubCallCounts `bson:"calls,omitempty"`
+	UpdatedAt time.Time                   `bson:"updated_at"`
+}
+
+var (
+	githubStatusBudgetIDKey        = bsonutil.MustHaveTag(githubStatusBudget{}, "ID")
+	githubStatusBudgetResetKey     = bsonutil.MustHaveTag(githubStatusBudget{}, "Reset")
+	githubStatusBudgetSpentKey     = bsonutil.MustHaveTag(githubStatusBudget{}, "Spent")
+	githubStatusBudgetCallsKey     = bsonutil.MustHaveTag(githubStatusBudget{}, "Calls")
+	githubStatusBudgetUpdatedAtKey = bsonutil.MustHaveTag(githubStatusBudget{}, "UpdatedAt")
+	githubCallCountsAllowedKey     = bsonutil.MustHaveTag(GithubCallCounts{}, "Allowed")
+	githubCallCountsDeferredKey    = bsonutil.MustHaveTag(GithubCallCounts{}, "Deferred")
+)
+
+// githubStatusBudgetWindow returns the ID and reset time of the rate limit
+// window that contains the given time.
+func githubStatusBudgetWindow(now time.Time) (string, time.Time) {
+	start := now.UTC().Truncate(defaultGithubRateLimitWindow)
+	return fmt.Sprintf("%s.%d", GithubStatusInstallation, start.Unix()), start.Add(defaultGithubRateLimitWindow)
+}
+
+func findGithubStatusBudget(now time.Time) (*githubStatusBudget, error) {
+	id, reset := githubStatusBudgetWindow(now)
+	budget := &githubStatusBudget{}
+	err := db.FindOneQ(GithubStatusBudgetCollection, db.Query(bson.M{githubStatusBudgetIDKey: id}), budget)
+	if adb.ResultsNotFound(err) {
+		return &githubStatusBudget{ID: id, Reset: reset, UpdatedAt: now}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "finding GitHub status budget")
+	}
+	return budget, nil
+}
+
+func (b *githubStatusBudget) status(now time.Time) GithubBudgetStatus {
+	status := GithubBudgetStatus{
+		Installation: GithubStatusInstallation,
+		Limit:        defaultGithubRateLimit,
+		Remaining:    defaultGithubRateLimit - b.Spent,
+		Reset:        b.Reset,
+		Estimated:    true,
+		UpdatedAt:    b.UpdatedAt,
+		Calls:        map[string]GithubCallCounts{},
+	}
+	for priority, counts := range b.Calls {
+		status.Calls[priority] = counts
+	}
+	return status
+}
+
+// reserveGithubStatusBudget atomically spends one call of the status sender's
+// budget for the current window, unless the call's priority can't spend what
+// remains.
+func reserveGithubStatusBudget(priority GithubCallPriority, now time.Time) error {
+	id, reset := githubStatusBudgetWindow(now)
+	info, err := db.Upsert(GithubStatusBudgetCollection, bson.M{githubStatusBudgetIDKey: id}, bson.M{
+		"$setOnInsert": bson.M{
+			githubStatusBudgetResetKey:     reset,
+			githubStatusBudgetSpentKey:     0,
+			githubStatusBudgetUpdatedAtKey: now,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "initializing GitHub status budget")
+	}
+	if info != nil && info.UpsertedId != nil {
+		// A new window started, so the budgets of past windows are no
+		// longer needed.
+		grip.Warning(message.WrapError(db.RemoveAll(GithubStatusBudgetCollection, bson.M{
+			githubStatusBudgetResetKey: bson.M{"$lte": now},
+		}), message.Fields{
+			"message": "could not remove past GitHub status budgets",
+		}))
+	}
+
+	countsKey := bsonutil.GetDottedKeyName(githubStatusBudgetCallsKey, priority.String())
+	allowedKey := bsonutil.GetDottedKeyName(countsKey, githubCallCountsAllowedKey)
+	deferredKey := bsonutil.GetDottedKeyName(countsKey, githubCallCountsDeferredKey)
+	err = db.Update(GithubStatusBudgetCollection, bson.M{
+		githubStatusBudgetIDKey:    id,
+		githubStatusBudgetSpentKey: bson.M{"$lt": defaultGithubRateLimit - priority.reserve(defaultGithubRateLimit)},
+	}, bson.M{
+		"$inc": bson.M{
+			githubStatusBudgetSpentKey: 1,
+			allowedKey:                 1,
+		},
+		"$set": bson.M{githubStatusBudgetUpdatedAtKey: now},
+	})
+	if err == nil {
+		return nil
+	}
+	if !adb.ResultsNotFound(err) {
+		return errors.Wrap(err, "reserving GitHub status budget")
+	}
+
+	if err = db.Update(GithubStatusBudgetCollection, bson.M{githubStatusBudgetIDKey: id}, bson.M{
+		"$inc": bson.M{deferredKey: 1},
+	}); err != nil {
+		return errors.Wrap(err, "recording deferred GitHub status")
+	}
+	budget, err := findGithubStatusBudget(now)
+	if err != nil {
+		return err
+	}
+	return &GithubBudgetDeferredError{
+		Installation: GithubStatusInstallation,
+		Priority:     priority,
+		Remaining:    defaultGithubRateLimit - budget.Spent,
+		RetryAt:      reset,
+	}
+}
+
+// ReserveGithubStatus spends the status sender's budget, which is shared by
+// every process, on posting a GitHub status of the given priority. It returns
+// a GithubBudgetDeferredError if the status should be posted after the rate
+// limit resets instead.
+func ReserveGithubStatus(priority GithubCallPriority) error {
+	err := reserveGithubStatusBudget(priority, time.Now())
+	grip.Info(message.WrapError(err, message.Fields{
+		"message":  "deferred GitHub status",
+		"priority": priority.String(),
+	}))
+	return err
+}
+
+// githubInstallation identifies the installation that a token belongs to
+// without revealing the token.
+func githubInstallation(token string) string {
+	token = strings.TrimPrefix(strings.TrimSpace(token), "token ")
+	sum := sha256.Sum256([]byte(token))
+	return "token-" + hex.EncodeToString(sum[:4])
+}
+
+// githubBudgetTransport charges each GitHub API request to its installation's
+// budget and records the rate limit that GitHub reports in the response.
+type githubBudgetTransport struct {
+	base         http.RoundTripper
+	installation string
+}
+
+func (t *githubBudgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	priority := githubCallPriorityFromContext(req.Context())
+	if err := githubBudgets.reserve(t.installation, priority, time.Now()); err != nil {
+		grip.Info(message.WrapError(err, message.Fields{
+			"message":  "deferred GitHub API call",
+			"priority": priority.String(),
+			"method":   req.Method,
+			"url":      req.URL.String(),
+		}))
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if resp != nil {
+		githubBudgets.update(t.installation, parseGithubRateLimit(resp.Header), time.Now())
+	}
+	return resp, err
+}
+
+// withGithubBudget makes the client's requests go through the installation's
+// budget. The client must be returned with putGithubClient.
+func withGithubBudget(client *http.Client, token string) *http.Client {
+	client.Transport = &githubBudgetTransport{
+		base:         client.Transport,
+		installation: githubInstallation(token),
+	}
+	return client
+}
+
+// putGithubClient returns a client from getGithubClient to the pool.
+func putGithubClient(client *http.Client) {
+	if transport, ok := client.Transport.(*githubBudgetTransport); ok {
+		client.Transport = transport.base
+	}
+	utility.PutHTTPClient(client)
+}
@@ -0,0 +1,166 @@
+package thirdparty
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/utility"
+	"github.com/google/go-github/v34/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGithubBudgetManager(t *testing.T) {
+	now := time.Now()
+	reset := now.Add(30 * time.Minute)
+
+	t.Run("DefersLowerPrioritiesFirst", func(t *testing.T) {
+		m := newGithubBudgetManager()
+		m.update("installation", github.Rate{Limit: 100, Remaining: 20, Reset: github.Timestamp{Time: reset}}, now)
+
+		err := m.reserve("installation", GithubPriorityCosmetic, now)
+		deferred, ok := IsGithubBudgetDeferred(err)
+		require.True(t, ok)
+		assert.Equal(t, reset, deferred.RetryAt)
+		assert.Equal(t, GithubPriorityCosmetic, deferred.Priority)
+
+		assert.NoError(t, m.reserve("installation", GithubPriorityNormal, now))
+		assert.NoError(t, m.reserve("installation", GithubPriorityCritical, now))
+
+		m.update("installation", github.Rate{Limit: 100, Remaining: 5, Reset: github.Timestamp{Time: reset}}, now)
+		_, ok = IsGithubBudgetDeferred(m.reserve("installation", GithubPriorityNormal, now))
+		assert.True(t, ok)
+		assert.NoError(t, m.reserve("installation", GithubPriorityCritical, now))
+
+		m.update("installation", github.Rate{Limit: 100, Remaining: 0, Reset: github.Timestamp{Time: reset}}, now)
+		_, ok = IsGithubBudgetDeferred(m.reserve("installation", GithubPriorityCritical, now))
+		assert.True(t, ok)
+
+		statuses := m.status(now)
+		require.Len(t, statuses, 1)
+		assert.False(t, statuses[0].Estimated)
+		assert.Equal(t, GithubCallCounts{Allowed: 2, Deferred: 1}, statuses[0].Calls["critical"])
+		assert.Equal(t, GithubCallCounts{Allowed: 1, Deferred: 1}, statuses[0].Calls["normal"])
+		assert.Equal(t, GithubCallCounts{Deferred: 1}, statuses[0].Calls["cosmetic"])
+	})
+	t.Run("EstimatesUnreportedBudgets", func(t *testing.T) {
+		m := newGithubBudgetManager()
+		require.NoError(t, m.reserve(GithubStatusInstallation, GithubPriorityNormal, now))
+		statuses := m.status(now)
+		require.Len(t, statuses, 1)
+		assert.True(t, statuses[0].Estimated)
+		assert.Equal(t, defaultGithubRateLimit, statuses[0].Limit)
+		assert.Equal(t, defaultGithubRateLimit-1, statuses[0].Remaining)
+	})
+	t.Run("RefillsAfterReset", func(t *testing.T) {
+		m := newGithubBudgetManager()
+		m.update("installation", github.Rate{Limit: 100, Remaining: 0, Reset: github.Timestamp{Time: reset}}, now)
+		_, ok := IsGithubBudgetDeferred(m.reserve("installation", GithubPriorityCritical, now))
+		assert.True(t, ok)
+
+		assert.NoError(t, m.reserve("installation", GithubPriorityCosmetic, reset.Add(time.Second)))
+		statuses := m.status(reset.Add(time.Second))
+		require.Len(t, statuses, 1)
+		assert.Equal(t, 99, statuses[0].Remaining)
+	})
+}
+
+func TestReserveGithubStatusBudget(t *testing.T) {
+	require.NoError(t, db.ClearCollections(GithubStatusBudgetCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(GithubStatusBudgetCollection))
+	}()
+
+	now := time.Now()
+	id, reset := githubStatusBudgetWindow(now)
+	require.NoError(t, reserveGithubStatusBudget(GithubPriorityCosmetic, now))
+
+	// Another process spending most of the budget leaves too little of it
+	// for cosmetic statuses.
+	cosmeticLimit := defaultGithubRateLimit - GithubPriorityCosmetic.reserve(defaultGithubRateLimit)
+	require.NoError(t, db.Update(GithubStatusBudgetCollection, bson.M{githubStatusBudgetIDKey: id}, bson.M{
+		"$set": bson.M{githubStatusBudgetSpentKey: cosmeticLimit},
+	}))
+	err := reserveGithubStatusBudget(GithubPriorityCosmetic, now)
+	deferred, ok := IsGithubBudgetDeferred(err)
+	require.True(t, ok, "error should be a deferral: %v", err)
+	assert.Equal(t, reset, deferred.RetryAt)
+	assert.Equal(t, defaultGithubRateLimit-cosmeticLimit, deferred.Remaining)
+	require.NoError(t, reserveGithubStatusBudget(GithubPriorityCritical, now))
+
+	budget, err := findGithubStatusBudget(now)
+	require.NoError(t, err)
+	status := budget.status(now)
+	assert.Equal(t, defaultGithubRateLimit-cosmeticLimit-1, status.Remaining)
+	assert.True(t, status.Estimated)
+	assert.Equal(t, GithubCallCounts{Allowed: 1, Deferred: 1}, status.Calls["cosmetic"])
+	assert.Equal(t, GithubCallCounts{Allowed: 1}, status.Calls["critical"])
+
+	// The next window starts with a full budget and removes the past one.
+	next := reset.Add(time.Minute)
+	require.NoError(t, reserveGithubStatusBudget(GithubPriorityCosmetic, next))
+	budget, err = findGithubStatusBudget(next)
+	require.NoError(t, err)
+	assert.Equal(t, 1, budget.Spent)
+	count, err := db.Count(GithubStatusBudgetCollection, bson.M{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestGithubBudgetTransport(t *testing.T) {
+	remaining := 100
+	var numRequests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numRequests++
+		w.Header().Set("X-Ratelimit-Limit", "1000")
+		w.Header().Set("X-Ratelimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	token := "token budget-transport-test"
+	installation := githubInstallation(token)
+	assert.NotContains(t, installation, "budget-transport-test")
+
+	client := withGithubBudget(utility.GetHTTPClient(), token)
+	defer func() {
+		delete(githubBudgets.budgets, installation)
+	}()
+
+	get := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	require.NoError(t, get(context.Background()))
+	assert.Equal(t, 1, numRequests)
+
+	// Only 100 of 1000 calls remain, so cosmetic calls are deferred without
+	// calling GitHub, but critical calls are still made.
+	err := get(WithGithubCallPriority(context.Background(), GithubPriorityCosmetic))
+	deferred, ok := IsGithubBudgetDeferred(err)
+	require.True(t, ok, "error should be a deferral: %v", err)
+	assert.Equal(t, installation, deferred.Installation)
+	assert.Equal(t, 1, numRequests)
+
+	require.NoError(t, get(WithGithubCallPriority(context.Background(), GithubPriorityCritical)))
+	assert.Equal(t, 2, numRequests)
+
+	_, ok = client.Transport.(*githubBudgetTransport)
+	assert.True(t, ok)
+	putGithubClient(client)
+	_, ok = client.Transport.(*githubBudgetTransport)
+	assert.False(t, ok)
+}
@@ -0,0 +1,146 @@
+package thirdparty
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v34/github"
+	"github.com/mongodb/grip/send"
+)
+
+// GithubIntegration is how Evergreen reads repositories, commits, and pull
+// requests from GitHub. The default integration calls the GitHub API; sandbox
+// mode replaces it with one that doesn't need network access or credentials.
+type GithubIntegration interface {
+	GetGithubCommits(ctx context.Context, oauthToken, owner, repo, ref string, until time.Time, commitPage int) ([]*github.RepositoryCommit, int, error)
+	GetGithubFile(ctx context.Context, oauthToken, owner, repo, path, ref string) (*github.RepositoryContent, error)
+	GetGithubMergeBaseRevision(ctx context.Context, oauthToken, repoOwner, repo, baseRevision, currentCommitHash string) (string, error)
+	GetCommitEvent(ctx context.Context, oauthToken, repoOwner, repo, githash string) (*github.RepositoryCommit, error)
+	GetCommitDiff(ctx context.Context, oauthToken, repoOwner, repo, sha string) (string, error)
+	GetBranchEvent(ctx context.Context, oauthToken, repoOwner, repo, branch string) (*github.Branch, error)
+	GetTaggedCommitFromGithub(ctx context.Context, oauthToken, owner, repo, tag string) (string, error)
+	GetPullRequestMergeBase(ctx context.Context, token string, data GithubPatch) (string, error)
+	GetGithubPullRequest(ctx context.Context, token, baseOwner, baseRepo string, PRNumber int) (*github.PullRequest, error)
+	GetGithubPullRequestCommits(ctx context.Context, token, owner, repo string, PRNumber int) ([]*github.RepositoryCommit, error)
+	GetGithubPullRequestDiff(ctx context.Context, token string, gh GithubPatch) (string, []Summary, error)
+}
+
+// JiraIntegration is how Evergreen reads and writes Jira tickets.
+type JiraIntegration interface {
+	JiraHost() string
+	CreateTicket(fields map[string]interface{}) (*JiraCreateTicketResponse, error)
+	UpdateTicket(key string, fields map[string]interface{}) error
+	GetJIRATicket(key string) (*JiraTicket, error)
+	JQLSearch(query string, startAt, maxResults int) (*JiraSearchResults, error)
+	JQLSearchAll(query string) ([]JiraTicket, error)
+}
+
+// liveGithubIntegration calls the GitHub API.
+type liveGithubIntegration struct{}
+
+var (
+	_ GithubIntegration = liveGithubIntegration{}
+	_ JiraIntegration   = &JiraHandler{}
+)
+
+var integrations = struct {
+	mu     sync.RWMutex
+	github GithubIntegration
+	jira   JiraIntegration
+}{github: liveGithubIntegration{}}
+
+// SetGithubIntegration replaces the integration that GitHub is called through.
+// Passing nil restores the default integration, which calls the GitHub API.
+func SetGithubIntegration(g GithubIntegration) {
+	integrations.mu.Lock()
+	defer integrations.mu.Unlock()
+	if g == nil {
+		g = liveGithubIntegration{}
+	}
+	integrations.github = g
+}
+
+// SetJiraIntegration replaces the integration that Jira is called through.
+// Passing nil restores the default integration, which calls the Jira API.
+func SetJiraIntegration(j JiraIntegration) {
+	integrations.mu.Lock()
+	defer integrations.mu.Unlock()
+	integrations.jira = j
+}
+
+func githubIntegration() GithubIntegration {
+	integrations.mu.RLock()
+	defer integrations.mu.RUnlock()
+	return integrations.github
+}
+
+// NewJiraIntegration returns the integration that Jira is called through,
+// which by default is a JiraHandler for the given options.
+func NewJiraIntegration(opts send.JiraOptions) JiraIntegration {
+	integrations.mu.RLock()
+	jira := integrations.jira
+	integrations.mu.RUnlock()
+	if jira != nil {
+		return jira
+	}
+	handler := NewJiraHandler(opts)
+	return &handler
+}
+
+// GetGithubCommits returns a slice of GithubCommit objects from
+// the given commitsURL when provided a valid oauth token
+func GetGithubCommits(ctx context.Context, oauthToken, owner, repo, ref string, until time.Time, commitPage int) ([]*github.RepositoryCommit, int, error) {
+	return githubIntegration().GetGithubCommits(ctx, oauthToken, owner, repo, ref, until, commitPage)
+}
+
+// GetGithubFile returns a struct that contains the contents of files within
+// a repository as Base64 encoded content. Ref should be the commit hash or branch (defaults to master).
+func GetGithubFile(ctx context.Context, oauthToken, owner, repo, path, ref string) (*github.RepositoryContent, error) {
+	return githubIntegration().GetGithubFile(ctx, oauthToken, owner, repo, path, ref)
+}
+
+// GetGithubMergeBaseRevision returns the merge base of the two revisions.
+func GetGithubMergeBaseRevision(ctx context.Context, oauthToken, repoOwner, repo, baseRevision, currentCommitHash string) (string, error) {
+	return githubIntegration().GetGithubMergeBaseRevision(ctx, oauthToken, repoOwner, repo, baseRevision, currentCommitHash)
+}
+
+// GetCommitEvent gets the commit with the given hash.
+func GetCommitEvent(ctx context.Context, oauthToken, repoOwner, repo, githash string) (*github.RepositoryCommit, error) {
+	return githubIntegration().GetCommitEvent(ctx, oauthToken, repoOwner, repo, githash)
+}
+
+// GetCommitDiff gets the diff of the specified commit via an API call to GitHub
+func GetCommitDiff(ctx context.Context, oauthToken, repoOwner, repo, sha string) (string, error) {
+	return githubIntegration().GetCommitDiff(ctx, oauthToken, repoOwner, repo, sha)
+}
+
+// GetBranchEvent gets the head of the a given branch via an API call to GitHub
+func GetBranchEvent(ctx context.Context, oauthToken, repoOwner, repo, branch string) (*github.Branch, error) {
+	return githubIntegration().GetBranchEvent(ctx, oauthToken, repoOwner, repo, branch)
+}
+
+// GetTaggedCommitFromGithub gets the commit SHA for the given tag name.
+func GetTaggedCommitFromGithub(ctx context.Context, oauthToken, owner, repo, tag string) (string, error) {
+	return githubIntegration().GetTaggedCommitFromGithub(ctx, oauthToken, owner, repo, tag)
+}
+
+// GetPullRequestMergeBase returns the merge base hash for the given PR.
+func GetPullRequestMergeBase(ctx context.Context, token string, data GithubPatch) (string, error) {
+	return githubIntegration().GetPullRequestMergeBase(ctx, token, data)
+}
+
+// GetGithubPullRequest gets the given pull request.
+func GetGithubPullRequest(ctx context.Context, token, baseOwner, baseRepo string, PRNumber int) (*github.PullRequest, error) {
+	return githubIntegration().GetGithubPullRequest(ctx, token, baseOwner, baseRepo, PRNumber)
+}
+
+// GetGithubPullRequestCommits gets the commits in the given pull request.
+func GetGithubPullRequestCommits(ctx context.Context, token, owner, repo string, PRNumber int) ([]*github.RepositoryCommit, error) {
+	return githubIntegration().GetGithubPullRequestCommits(ctx, token, owner, repo, PRNumber)
+}
+
+// GetGithubPullRequestDiff downloads a diff from a Github Pull Request diff
+func GetGithubPullRequestDiff(ctx context.Context, token string, gh GithubPatch) (string, []Summary, error) {
+	return githubIntegration().GetGithubPullRequestDiff(ctx, token, gh)
+}
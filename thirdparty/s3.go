@@ -45,6 +45,17 @@ func PreSign(r RequestParams) (string, error) {
 	return urlStr, err
 }
 
+// PreSignObject returns a presigned url for the object that expires in 24
+// hours.
+func PreSignObject(bucket, key, awsKey, awsSecret string) (string, error) {
+	return PreSign(RequestParams{
+		Bucket:    bucket,
+		FileKey:   key,
+		AwsKey:    awsKey,
+		AwsSecret: awsSecret,
+	})
+}
+
 // GetHeadObject fetches the metadata of an s3 object.
 func GetHeadObject(r RequestParams) (*s3.HeadObjectOutput, error) {
 	session, err := session.NewSession(&aws.Config{
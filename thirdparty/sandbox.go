@@ -0,0 +1,344 @@
+package thirdparty
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v34/github"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// sandboxCommitsPerPage is how many commits the sandbox GitHub integration
+// returns when listing a branch's commits.
+const sandboxCommitsPerPage = 50
+
+// sandboxGithubIntegration serves repositories from local directories instead
+// of GitHub. The repository owner/repo is the directory <root>/owner/repo,
+// which can be a git checkout or a plain directory of files. Commits,
+// branches, and tags are only available for git checkouts, and pull requests
+// aren't available at all.
+type sandboxGithubIntegration struct {
+	root string
+}
+
+// NewSandboxGithubIntegration returns a GitHub integration that serves the
+// repositories in the given directory instead of calling GitHub.
+func NewSandboxGithubIntegration(root string) (GithubIntegration, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "checking sandbox repositories directory '%s'", root)
+	}
+	if !info.IsDir() {
+		return nil, errors.Errorf("sandbox repositories path '%s' is not a directory", root)
+	}
+	return &sandboxGithubIntegration{root: root}, nil
+}
+
+// repoDir returns the directory for the repository, making sure that the
+// owner and repo can't refer to a directory outside of the root.
+func (s *sandboxGithubIntegration) repoDir(owner, repo string) (string, error) {
+	dir := filepath.Join(s.root, owner, repo)
+	if rel, err := filepath.Rel(s.root, dir); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", errors.Errorf("invalid repository '%s/%s'", owner, repo)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return "", errors.Wrapf(err, "repository '%s/%s' not found in sandbox", owner, repo)
+	}
+	return dir, nil
+}
+
+func (s *sandboxGithubIntegration) isGitRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// validateSandboxRefs checks that the refs can be passed to git as positional
+// arguments, since a ref that starts with "-" would be parsed as an option.
+func validateSandboxRefs(refs ...string) error {
+	for _, ref := range refs {
+		if strings.HasPrefix(ref, "-") {
+			return errors.Errorf("invalid ref '%s'", ref)
+		}
+	}
+	return nil
+}
+
+func (s *sandboxGithubIntegration) git(ctx context.Context, owner, repo string, args ...string) (string, error) {
+	dir, err := s.repoDir(owner, repo)
+	if err != nil {
+		return "", err
+	}
+	if !s.isGitRepo(dir) {
+		return "", errors.Errorf("sandbox repository '%s/%s' is not a git checkout", owner, repo)
+	}
+	out, err := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", errors.Errorf("running git %s in sandbox repository '%s/%s': %s", strings.Join(args, " "), owner, repo, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", errors.Wrapf(err, "running git %s in sandbox repository '%s/%s'", strings.Join(args, " "), owner, repo)
+	}
+	return string(out), nil
+}
+
+// sandboxCommitFormat is the git log format that parseSandboxCommits parses.
+const sandboxCommitFormat = "--format=%H%x00%an%x00%ae%x00%cI%x00%s%x00%P%x1e"
+
+func parseSandboxCommits(out string) ([]*github.RepositoryCommit, error) {
+	var commits []*github.RepositoryCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+		fields := strings.Split(record, "\x00")
+		if len(fields) != 6 {
+			return nil, errors.Errorf("malformed git log record '%s'", record)
+		}
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing date of commit '%s'", fields[0])
+		}
+		author := &github.CommitAuthor{
+			Name:  github.String(fields[1]),
+			Email: github.String(fields[2]),
+			Date:  &date,
+		}
+		commit := &github.RepositoryCommit{
+			SHA: github.String(fields[0]),
+			Commit: &github.Commit{
+				SHA:       github.String(fields[0]),
+				Message:   github.String(fields[4]),
+				Author:    author,
+				Committer: author,
+			},
+		}
+		for _, parent := range strings.Fields(fields[5]) {
+			commit.Parents = append(commit.Parents, &github.Commit{SHA: github.String(parent)})
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+func (s *sandboxGithubIntegration) GetGithubCommits(ctx context.Context, _, owner, repo, ref string, until time.Time, commitPage int) ([]*github.RepositoryCommit, int, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if err := validateSandboxRefs(ref); err != nil {
+		return nil, 0, err
+	}
+	if commitPage < 1 {
+		commitPage = 1
+	}
+	args := []string{"log", sandboxCommitFormat,
+		fmt.Sprintf("--skip=%d", (commitPage-1)*sandboxCommitsPerPage),
+		fmt.Sprintf("--max-count=%d", sandboxCommitsPerPage+1),
+	}
+	if !until.IsZero() {
+		args = append(args, "--until="+until.Format(time.RFC3339))
+	}
+	out, err := s.git(ctx, owner, repo, append(args, ref, "--")...)
+	if err != nil {
+		return nil, 0, err
+	}
+	commits, err := parseSandboxCommits(out)
+	if err != nil {
+		return nil, 0, err
+	}
+	nextPage := 0
+	if len(commits) > sandboxCommitsPerPage {
+		commits = commits[:sandboxCommitsPerPage]
+		nextPage = commitPage + 1
+	}
+	return commits, nextPage, nil
+}
+
+func (s *sandboxGithubIntegration) GetGithubFile(ctx context.Context, _, owner, repo, path, ref string) (*github.RepositoryContent, error) {
+	dir, err := s.repoDir(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateSandboxRefs(ref); err != nil {
+		return nil, err
+	}
+
+	var contents []byte
+	if ref != "" && s.isGitRepo(dir) {
+		out, err := s.git(ctx, owner, repo, "show", fmt.Sprintf("%s:%s", ref, path))
+		if err != nil {
+			return nil, FileNotFoundError{filepath: path}
+		}
+		contents = []byte(out)
+	} else {
+		filePath := filepath.Join(dir, path)
+		if rel, err := filepath.Rel(dir, filePath); err != nil || strings.HasPrefix(rel, "..") {
+			return nil, FileNotFoundError{filepath: path}
+		}
+		contents, err = ioutil.ReadFile(filePath)
+		if os.IsNotExist(err) {
+			return nil, FileNotFoundError{filepath: path}
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading '%s' from sandbox repository '%s/%s'", path, owner, repo)
+		}
+	}
+
+	return &github.RepositoryContent{
+		Type:     github.String("file"),
+		Name:     github.String(filepath.Base(path)),
+		Path:     github.String(path),
+		Encoding: github.String("base64"),
+		Content:  github.String(base64.StdEncoding.EncodeToString(contents)),
+	}, nil
+}
+
+func (s *sandboxGithubIntegration) GetGithubMergeBaseRevision(ctx context.Context, _, repoOwner, repo, baseRevision, currentCommitHash string) (string, error) {
+	if err := validateSandboxRefs(baseRevision, currentCommitHash); err != nil {
+		return "", err
+	}
+	out, err := s.git(ctx, repoOwner, repo, "merge-base", baseRevision, currentCommitHash)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (s *sandboxGithubIntegration) GetCommitEvent(ctx context.Context, _, repoOwner, repo, githash string) (*github.RepositoryCommit, error) {
+	if err := validateSandboxRefs(githash); err != nil {
+		return nil, err
+	}
+	out, err := s.git(ctx, repoOwner, repo, "log", sandboxCommitFormat, "--max-count=1", githash, "--")
+	if err != nil {
+		return nil, err
+	}
+	commits, err := parseSandboxCommits(out)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, errors.New("commit not found in sandbox repository")
+	}
+	return commits[0], nil
+}
+
+func (s *sandboxGithubIntegration) GetCommitDiff(ctx context.Context, _, repoOwner, repo, sha string) (string, error) {
+	if err := validateSandboxRefs(sha); err != nil {
+		return "", err
+	}
+	return s.git(ctx, repoOwner, repo, "show", "--format=", sha)
+}
+
+func (s *sandboxGithubIntegration) GetBranchEvent(ctx context.Context, _, repoOwner, repo, branch string) (*github.Branch, error) {
+	commit, err := s.GetCommitEvent(ctx, "", repoOwner, repo, branch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting head of branch '%s'", branch)
+	}
+	return &github.Branch{Name: github.String(branch), Commit: commit}, nil
+}
+
+func (s *sandboxGithubIntegration) GetTaggedCommitFromGithub(ctx context.Context, _, owner, repo, tag string) (string, error) {
+	if err := validateSandboxRefs(tag); err != nil {
+		return "", err
+	}
+	out, err := s.git(ctx, owner, repo, "rev-list", "--max-count=1", tag, "--")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+var errSandboxPullRequests = errors.New("pull requests are not available in sandbox mode")
+
+func (s *sandboxGithubIntegration) GetPullRequestMergeBase(context.Context, string, GithubPatch) (string, error) {
+	return "", errSandboxPullRequests
+}
+
+func (s *sandboxGithubIntegration) GetGithubPullRequest(context.Context, string, string, string, int) (*github.PullRequest, error) {
+	return nil, errSandboxPullRequests
+}
+
+func (s *sandboxGithubIntegration) GetGithubPullRequestCommits(context.Context, string, string, string, int) ([]*github.RepositoryCommit, error) {
+	return nil, errSandboxPullRequests
+}
+
+func (s *sandboxGithubIntegration) GetGithubPullRequestDiff(context.Context, string, GithubPatch) (string, []Summary, error) {
+	return "", nil, errSandboxPullRequests
+}
+
+// sandboxJiraIntegration keeps Jira tickets in memory instead of calling
+// Jira. Searches return no tickets.
+type sandboxJiraIntegration struct {
+	mu      sync.Mutex
+	tickets map[string]*JiraTicket
+}
+
+// NewSandboxJiraIntegration returns a Jira integration that keeps the tickets
+// that it creates in memory instead of calling Jira.
+func NewSandboxJiraIntegration() JiraIntegration {
+	return &sandboxJiraIntegration{tickets: map[string]*JiraTicket{}}
+}
+
+func (j *sandboxJiraIntegration) JiraHost() string { return "jira.sandbox.invalid" }
+
+func (j *sandboxJiraIntegration) CreateTicket(fields map[string]interface{}) (*JiraCreateTicketResponse, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	key := fmt.Sprintf("SANDBOX-%d", len(j.tickets)+1)
+	ticket := &JiraTicket{Key: key, Fields: &TicketFields{}}
+	if summary, ok := fields["summary"].(string); ok {
+		ticket.Fields.Summary = summary
+	}
+	j.tickets[key] = ticket
+	grip.Info(message.Fields{
+		"message": "created sandbox Jira ticket",
+		"ticket":  key,
+		"fields":  fields,
+	})
+	return &JiraCreateTicketResponse{Key: key}, nil
+}
+
+func (j *sandboxJiraIntegration) UpdateTicket(key string, fields map[string]interface{}) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, ok := j.tickets[key]; !ok {
+		return errors.Errorf("ticket '%s' not found", key)
+	}
+	grip.Info(message.Fields{
+		"message": "updated sandbox Jira ticket",
+		"ticket":  key,
+		"fields":  fields,
+	})
+	return nil
+}
+
+func (j *sandboxJiraIntegration) GetJIRATicket(key string) (*JiraTicket, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	ticket, ok := j.tickets[key]
+	if !ok {
+		return nil, errors.Errorf("ticket '%s' not found", key)
+	}
+	return ticket, nil
+}
+
+func (j *sandboxJiraIntegration) JQLSearch(string, int, int) (*JiraSearchResults, error) {
+	return &JiraSearchResults{}, nil
+}
+
+func (j *sandboxJiraIntegration) JQLSearchAll(string) ([]JiraTicket, error) {
+	return nil, nil
+}
@@ -0,0 +1,146 @@
+package thirdparty
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mongodb/grip/send"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxGithubIntegration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	root, err := ioutil.TempDir("", "sandbox-repos")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	plainDir := filepath.Join(root, "evergreen-ci", "plain")
+	require.NoError(t, os.MkdirAll(plainDir, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(plainDir, "evergreen.yml"), []byte("tasks: []"), 0644))
+
+	gh, err := NewSandboxGithubIntegration(root)
+	require.NoError(t, err)
+
+	t.Run("ReadsFilesFromPlainDirectories", func(t *testing.T) {
+		file, err := gh.GetGithubFile(ctx, "", "evergreen-ci", "plain", "evergreen.yml", "")
+		require.NoError(t, err)
+		contents, err := file.GetContent()
+		require.NoError(t, err)
+		assert.Equal(t, "tasks: []", contents)
+
+		_, err = gh.GetGithubFile(ctx, "", "evergreen-ci", "plain", "nonexistent.yml", "")
+		assert.True(t, IsFileNotFound(err))
+		_, err = gh.GetGithubFile(ctx, "", "evergreen-ci", "plain", "../../../etc/passwd", "")
+		assert.True(t, IsFileNotFound(err))
+	})
+	t.Run("RejectsRepositoriesOutsideTheRoot", func(t *testing.T) {
+		_, err := gh.GetGithubFile(ctx, "", "..", "..", "evergreen.yml", "")
+		assert.Error(t, err)
+		_, err = gh.GetGithubFile(ctx, "", "evergreen-ci", "nonexistent", "evergreen.yml", "")
+		assert.Error(t, err)
+	})
+	t.Run("CommitsRequireAGitCheckout", func(t *testing.T) {
+		_, err := gh.GetCommitEvent(ctx, "", "evergreen-ci", "plain", "HEAD")
+		assert.Error(t, err)
+	})
+	t.Run("PullRequestsAreUnavailable", func(t *testing.T) {
+		_, err := gh.GetGithubPullRequest(ctx, "", "evergreen-ci", "plain", 1)
+		assert.Error(t, err)
+	})
+	t.Run("ReadsCommitsFromGitCheckouts", func(t *testing.T) {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git is not installed")
+		}
+		gitDir := filepath.Join(root, "evergreen-ci", "checkout")
+		require.NoError(t, os.MkdirAll(gitDir, 0755))
+		git := func(args ...string) {
+			cmd := exec.CommandContext(ctx, "git", append([]string{"-C", gitDir,
+				"-c", "user.name=Sandbox", "-c", "user.email=sandbox@example.com"}, args...)...)
+			out, err := cmd.CombinedOutput()
+			require.NoError(t, err, string(out))
+		}
+		git("init", "-q", "-b", "main")
+		require.NoError(t, ioutil.WriteFile(filepath.Join(gitDir, "evergreen.yml"), []byte("v1"), 0644))
+		git("add", ".")
+		git("commit", "-q", "-m", "first")
+		require.NoError(t, ioutil.WriteFile(filepath.Join(gitDir, "evergreen.yml"), []byte("v2"), 0644))
+		git("commit", "-q", "-am", "second")
+
+		commits, nextPage, err := gh.GetGithubCommits(ctx, "", "evergreen-ci", "checkout", "main", time.Time{}, 1)
+		require.NoError(t, err)
+		assert.Zero(t, nextPage)
+		require.Len(t, commits, 2)
+		assert.Equal(t, "second", commits[0].GetCommit().GetMessage())
+		assert.Equal(t, "Sandbox", commits[0].GetCommit().GetAuthor().GetName())
+		assert.False(t, commits[0].GetCommit().GetCommitter().GetDate().IsZero())
+		require.Len(t, commits[0].Parents, 1)
+		assert.Equal(t, commits[1].GetSHA(), commits[0].Parents[0].GetSHA())
+
+		branch, err := gh.GetBranchEvent(ctx, "", "evergreen-ci", "checkout", "main")
+		require.NoError(t, err)
+		assert.Equal(t, commits[0].GetSHA(), branch.GetCommit().GetSHA())
+
+		file, err := gh.GetGithubFile(ctx, "", "evergreen-ci", "checkout", "evergreen.yml", commits[1].GetSHA())
+		require.NoError(t, err)
+		contents, err := file.GetContent()
+		require.NoError(t, err)
+		assert.Equal(t, "v1", contents)
+
+		diff, err := gh.GetCommitDiff(ctx, "", "evergreen-ci", "checkout", commits[0].GetSHA())
+		require.NoError(t, err)
+		assert.Contains(t, diff, "+v2")
+
+		output := filepath.Join(root, "output")
+		_, err = gh.GetCommitDiff(ctx, "", "evergreen-ci", "checkout", "--output="+output)
+		assert.Error(t, err)
+		_, err = os.Stat(output)
+		assert.True(t, os.IsNotExist(err))
+		_, _, err = gh.GetGithubCommits(ctx, "", "evergreen-ci", "checkout", "--all", time.Time{}, 1)
+		assert.Error(t, err)
+		_, err = gh.GetGithubFile(ctx, "", "evergreen-ci", "checkout", "evergreen.yml", "--output="+output)
+		assert.Error(t, err)
+		_, err = gh.GetGithubMergeBaseRevision(ctx, "", "evergreen-ci", "checkout", "main", "--all")
+		assert.Error(t, err)
+	})
+}
+
+func TestSandboxJiraIntegration(t *testing.T) {
+	jira := NewSandboxJiraIntegration()
+	resp, err := jira.CreateTicket(map[string]interface{}{"summary": "failing task"})
+	require.NoError(t, err)
+	assert.Equal(t, "SANDBOX-1", resp.Key)
+
+	ticket, err := jira.GetJIRATicket(resp.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "failing task", ticket.Fields.Summary)
+	assert.NoError(t, jira.UpdateTicket(resp.Key, map[string]interface{}{"summary": "still failing"}))
+
+	_, err = jira.GetJIRATicket("SANDBOX-2")
+	assert.Error(t, err)
+	assert.Error(t, jira.UpdateTicket("SANDBOX-2", nil))
+
+	results, err := jira.JQLSearch("project = EVG", 0, 50)
+	require.NoError(t, err)
+	assert.Empty(t, results.Issues)
+}
+
+func TestSetIntegrations(t *testing.T) {
+	jira := NewSandboxJiraIntegration()
+	SetJiraIntegration(jira)
+	defer SetJiraIntegration(nil)
+	assert.Equal(t, jira, NewJiraIntegration(send.JiraOptions{}))
+
+	gh, err := NewSandboxGithubIntegration(os.TempDir())
+	require.NoError(t, err)
+	SetGithubIntegration(gh)
+	defer SetGithubIntegration(nil)
+	assert.Equal(t, gh, githubIntegration())
+}
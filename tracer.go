@@ -0,0 +1,127 @@
+package evergreen
+
+import (
+	"context"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+// Attributes that are set on trace spans.
+const (
+	ProjectIdentifierOtelAttribute       = "evergreen.project.identifier"
+	ProjectConfigSizeOtelAttribute       = "evergreen.project.config_size_bytes"
+	ProjectNumIncludesOtelAttribute      = "evergreen.project.num_includes"
+	ProjectIncludeOtelAttribute          = "evergreen.project.include"
+	ProjectNumTasksOtelAttribute         = "evergreen.project.num_tasks"
+	ProjectNumBuildVariantsOtelAttribute = "evergreen.project.num_build_variants"
+	VersionIDOtelAttribute               = "evergreen.version.id"
+	VersionNumBuildsOtelAttribute        = "evergreen.version.num_builds"
+	VersionNumTasksOtelAttribute         = "evergreen.version.num_tasks"
+	ValidationNumErrorsOtelAttribute     = "evergreen.validation.num_errors"
+	ValidationNumWarningsOtelAttribute   = "evergreen.validation.num_warnings"
+	VersionRequesterOtelAttribute        = "evergreen.version.requester"
+	VersionStatusOtelAttribute           = "evergreen.version.status"
+	BuildIDOtelAttribute                 = "evergreen.build.id"
+	BuildVariantOtelAttribute            = "evergreen.build.variant"
+	BuildStatusOtelAttribute             = "evergreen.build.status"
+	TaskIDOtelAttribute                  = "evergreen.task.id"
+	TaskExecutionOtelAttribute           = "evergreen.task.execution"
+	TaskNameOtelAttribute                = "evergreen.task.name"
+	TaskStatusOtelAttribute              = "evergreen.task.status"
+	TaskDistroOtelAttribute              = "evergreen.task.distro"
+	TaskHostOtelAttribute                = "evergreen.task.host"
+	TaskResetOtelAttribute               = "evergreen.task.reset"
+)
+
+// initTracer sets up the global OpenTelemetry tracer provider if tracing is
+// enabled. If it isn't, spans are not recorded.
+func (e *envState) initTracer() error {
+	if e.settings == nil {
+		return errors.New("no settings object, cannot build tracer")
+	}
+	conf := e.settings.Tracer
+	if !conf.Enabled {
+		return nil
+	}
+
+	exporter, err := newSpanExporter(conf)
+	if err != nil {
+		return errors.Wrap(err, "making span exporter")
+	}
+	// Task lifecycle spans have remote parents so that they can be recorded
+	// by different app servers, so those are sampled by their trace ID too
+	// to keep the spans for a version together.
+	sampler := sdktrace.TraceIDRatioBased(conf.GetSampleRatio())
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler, sdktrace.WithRemoteParentSampled(sampler))),
+		sdktrace.WithIDGenerator(NewTraceIDGenerator()),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String("evergreen"),
+			semconv.ServiceVersionKey.String(BuildRevision),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+
+	e.RegisterCloser("tracer-provider", false, func(ctx context.Context) error {
+		return errors.Wrap(tp.Shutdown(ctx), "shutting down tracer provider")
+	})
+	return nil
+}
+
+func newSpanExporter(conf TracerConfig) (sdktrace.SpanExporter, error) {
+	switch conf.Exporter {
+	case TracerExporterLog, "":
+		return &logSpanExporter{}, nil
+	case TracerExporterOTLP:
+		return newOTLPSpanExporter(conf)
+	default:
+		return nil, errors.Errorf("unrecognized tracer exporter '%s'", conf.Exporter)
+	}
+}
+
+// logSpanExporter exports finished spans as structured log messages.
+type logSpanExporter struct{}
+
+func (e *logSpanExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, span := range spans {
+		attrs := message.Fields{}
+		for _, attr := range span.Attributes() {
+			attrs[string(attr.Key)] = attr.Value.AsInterface()
+		}
+		msg := message.Fields{
+			"message":     "trace span",
+			"name":        span.Name(),
+			"trace_id":    span.SpanContext().TraceID().String(),
+			"span_id":     span.SpanContext().SpanID().String(),
+			"start":       span.StartTime(),
+			"duration_ms": span.EndTime().Sub(span.StartTime()).Milliseconds(),
+			"status":      span.Status().Code.String(),
+			"attributes":  attrs,
+		}
+		if span.Parent().IsValid() {
+			msg["parent_span_id"] = span.Parent().SpanID().String()
+		}
+		if len(span.Links()) > 0 {
+			links := make([]string, 0, len(span.Links()))
+			for _, link := range span.Links() {
+				links = append(links, link.SpanContext.SpanID().String())
+			}
+			msg["linked_span_ids"] = links
+		}
+		if span.Status().Description != "" {
+			msg["status_description"] = span.Status().Description
+		}
+		grip.Info(msg)
+	}
+	return nil
+}
+
+func (e *logSpanExporter) Shutdown(context.Context) error { return nil }
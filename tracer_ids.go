@@ -0,0 +1,102 @@
+package evergreen
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	mathrand "math/rand"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans for long-running entities like versions, builds, and tasks are
+// recorded after the fact, possibly by different app servers, so their IDs
+// are derived from the entities' IDs rather than generated randomly. That
+// way, a task's span can name its build's span as its parent without either
+// being stored.
+
+// TraceIDFor returns the trace ID for the entity with the given ID.
+func TraceIDFor(id string) trace.TraceID {
+	sum := sha256.Sum256([]byte("trace:" + id))
+	var traceID trace.TraceID
+	copy(traceID[:], sum[:])
+	return traceID
+}
+
+// SpanIDFor returns the span ID for the entity with the given ID.
+func SpanIDFor(id string) trace.SpanID {
+	sum := sha256.Sum256([]byte("span:" + id))
+	var spanID trace.SpanID
+	copy(spanID[:], sum[:])
+	return spanID
+}
+
+type spanIDsKey struct{}
+
+type spanIDs struct {
+	traceID trace.TraceID
+	spanID  trace.SpanID
+}
+
+// WithSpanIDs returns a context in which the next span that's started gets the
+// given span ID, and, if it's a root span, the given trace ID. The span's
+// children get random IDs as usual.
+func WithSpanIDs(ctx context.Context, traceID trace.TraceID, spanID trace.SpanID) context.Context {
+	return context.WithValue(ctx, spanIDsKey{}, spanIDs{traceID: traceID, spanID: spanID})
+}
+
+// traceIDGenerator uses the IDs set with WithSpanIDs if there are any, and
+// otherwise generates random IDs.
+type traceIDGenerator struct {
+	mu     sync.Mutex
+	random *mathrand.Rand
+}
+
+// NewTraceIDGenerator returns the span ID generator for tracer providers,
+// which respects the IDs set with WithSpanIDs.
+func NewTraceIDGenerator() sdktrace.IDGenerator {
+	var seed int64
+	_ = binary.Read(rand.Reader, binary.LittleEndian, &seed)
+	return &traceIDGenerator{random: mathrand.New(mathrand.NewSource(seed))}
+}
+
+// requestedIDs returns the IDs set with WithSpanIDs, unless the context's
+// current span already has them, in which case the span being started is a
+// child of the span that they were meant for.
+func requestedIDs(ctx context.Context) (spanIDs, bool) {
+	ids, ok := ctx.Value(spanIDsKey{}).(spanIDs)
+	if !ok || !ids.spanID.IsValid() {
+		return spanIDs{}, false
+	}
+	if trace.SpanContextFromContext(ctx).SpanID() == ids.spanID {
+		return spanIDs{}, false
+	}
+	return ids, true
+}
+
+func (g *traceIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	if ids, ok := requestedIDs(ctx); ok && ids.traceID.IsValid() {
+		return ids.traceID, ids.spanID
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var traceID trace.TraceID
+	var spanID trace.SpanID
+	_, _ = g.random.Read(traceID[:])
+	_, _ = g.random.Read(spanID[:])
+	return traceID, spanID
+}
+
+func (g *traceIDGenerator) NewSpanID(ctx context.Context, _ trace.TraceID) trace.SpanID {
+	if ids, ok := requestedIDs(ctx); ok {
+		return ids.spanID
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var spanID trace.SpanID
+	_, _ = g.random.Read(spanID[:])
+	return spanID
+}
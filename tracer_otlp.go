@@ -0,0 +1,221 @@
+package evergreen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpTracesPath is the standard path that OTLP collectors receive spans on.
+const otlpTracesPath = "/v1/traces"
+
+// otlpSpanExporter exports finished spans to an OpenTelemetry collector using
+// the JSON encoding of OTLP over HTTP.
+type otlpSpanExporter struct {
+	endpoint string
+	headers  map[string]string
+}
+
+func newOTLPSpanExporter(conf TracerConfig) (*otlpSpanExporter, error) {
+	u, err := url.Parse(conf.OTLPEndpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing OTLP endpoint")
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpTracesPath
+	}
+	return &otlpSpanExporter{endpoint: u.String(), headers: conf.OTLPHeaders}, nil
+}
+
+func (e *otlpSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(makeOTLPTracesRequest(spans))
+	if err != nil {
+		return errors.Wrap(err, "marshalling spans")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "making OTLP request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	client := utility.GetHTTPClient()
+	defer utility.PutHTTPClient(client)
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "sending spans to OTLP collector")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("OTLP collector returned status %d: %s", resp.StatusCode, string(msg))
+	}
+	return nil
+}
+
+func (e *otlpSpanExporter) Shutdown(context.Context) error { return nil }
+
+type otlpTracesRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Links             []otlpLink     `json:"links,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpLink struct {
+	TraceID    string         `json:"traceId"`
+	SpanID     string         `json:"spanId"`
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// otlpValue is an attribute value. 64-bit integers are encoded as strings,
+// as in the protobuf JSON mapping.
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// otlpStatusCodes maps OpenTelemetry status codes, which are ordered
+// differently, to OTLP status codes.
+var otlpStatusCodes = map[codes.Code]int{
+	codes.Unset: 0,
+	codes.Ok:    1,
+	codes.Error: 2,
+}
+
+func makeOTLPTracesRequest(spans []sdktrace.ReadOnlySpan) otlpTracesRequest {
+	// Spans from the same provider share a resource, and are grouped by the
+	// tracer that recorded them.
+	var resource otlpResource
+	if res := spans[0].Resource(); res != nil {
+		resource.Attributes = makeOTLPAttributes(res.Attributes())
+	}
+	scopes := map[string]*otlpScopeSpans{}
+	var scopeNames []string
+	for _, span := range spans {
+		lib := span.InstrumentationLibrary()
+		scope, ok := scopes[lib.Name]
+		if !ok {
+			scope = &otlpScopeSpans{Scope: otlpScope{Name: lib.Name, Version: lib.Version}}
+			scopes[lib.Name] = scope
+			scopeNames = append(scopeNames, lib.Name)
+		}
+		scope.Spans = append(scope.Spans, makeOTLPSpan(span))
+	}
+
+	resourceSpans := otlpResourceSpans{Resource: resource}
+	for _, name := range scopeNames {
+		resourceSpans.ScopeSpans = append(resourceSpans.ScopeSpans, *scopes[name])
+	}
+	return otlpTracesRequest{ResourceSpans: []otlpResourceSpans{resourceSpans}}
+}
+
+func makeOTLPSpan(span sdktrace.ReadOnlySpan) otlpSpan {
+	s := otlpSpan{
+		TraceID:           span.SpanContext().TraceID().String(),
+		SpanID:            span.SpanContext().SpanID().String(),
+		Name:              span.Name(),
+		Kind:              int(span.SpanKind()),
+		StartTimeUnixNano: strconv.FormatInt(span.StartTime().UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.EndTime().UnixNano(), 10),
+		Attributes:        makeOTLPAttributes(span.Attributes()),
+		Status: otlpStatus{
+			Code:    otlpStatusCodes[span.Status().Code],
+			Message: span.Status().Description,
+		},
+	}
+	if span.Parent().IsValid() {
+		s.ParentSpanID = span.Parent().SpanID().String()
+	}
+	for _, link := range span.Links() {
+		s.Links = append(s.Links, otlpLink{
+			TraceID:    link.SpanContext.TraceID().String(),
+			SpanID:     link.SpanContext.SpanID().String(),
+			Attributes: makeOTLPAttributes(link.Attributes),
+		})
+	}
+	return s
+}
+
+func makeOTLPAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		var value otlpValue
+		switch attr.Value.Type() {
+		case attribute.BOOL:
+			b := attr.Value.AsBool()
+			value.BoolValue = &b
+		case attribute.INT64:
+			i := strconv.FormatInt(attr.Value.AsInt64(), 10)
+			value.IntValue = &i
+		case attribute.FLOAT64:
+			f := attr.Value.AsFloat64()
+			value.DoubleValue = &f
+		case attribute.STRING:
+			str := attr.Value.AsString()
+			value.StringValue = &str
+		default:
+			str := attr.Value.Emit()
+			value.StringValue = &str
+		}
+		kvs = append(kvs, otlpKeyValue{Key: string(attr.Key), Value: value})
+	}
+	return kvs
+}
@@ -0,0 +1,153 @@
+package evergreen
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceIDGenerator(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithIDGenerator(NewTraceIDGenerator()))
+	tracer := tp.Tracer("test")
+
+	assert.Equal(t, TraceIDFor("v1"), TraceIDFor("v1"))
+	assert.NotEqual(t, TraceIDFor("v1"), TraceIDFor("v2"))
+	assert.NotEqual(t, SpanIDFor("v1"), SpanIDFor("v2"))
+
+	ctx := WithSpanIDs(context.Background(), TraceIDFor("v1"), SpanIDFor("v1"))
+	ctx, root := tracer.Start(ctx, "root")
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+	root.End()
+	_, random := tracer.Start(context.Background(), "random")
+	random.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, TraceIDFor("v1"), spans[0].SpanContext().TraceID())
+	assert.NotEqual(t, SpanIDFor("v1"), spans[0].SpanContext().SpanID())
+	assert.Equal(t, SpanIDFor("v1"), spans[0].Parent().SpanID())
+
+	assert.Equal(t, "root", spans[1].Name())
+	assert.Equal(t, TraceIDFor("v1"), spans[1].SpanContext().TraceID())
+	assert.Equal(t, SpanIDFor("v1"), spans[1].SpanContext().SpanID())
+
+	assert.NotEqual(t, TraceIDFor("v1"), spans[2].SpanContext().TraceID())
+	assert.True(t, spans[2].SpanContext().IsValid())
+}
+
+func TestOTLPSpanExporter(t *testing.T) {
+	var body otlpTracesRequest
+	var header http.Header
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		header = r.Header
+		data, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	exporter, err := newOTLPSpanExporter(TracerConfig{
+		OTLPEndpoint: srv.URL,
+		OTLPHeaders:  map[string]string{"x-api-key": "key"},
+	})
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	start := time.Now().Add(-time.Minute)
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent", trace.WithTimestamp(start))
+	linked := trace.SpanContextFromContext(ctx)
+	_, child := tp.Tracer("test").Start(ctx, "child",
+		trace.WithTimestamp(start),
+		trace.WithAttributes(
+			attribute.String("string", "value"),
+			attribute.Int("int", 5),
+			attribute.Bool("bool", true),
+		),
+		trace.WithLinks(trace.Link{SpanContext: linked}),
+	)
+	child.SetStatus(codes.Error, "failed")
+	child.End(trace.WithTimestamp(start.Add(time.Second)))
+	parent.End()
+
+	require.NoError(t, exporter.ExportSpans(context.Background(), recorder.Ended()))
+	assert.Equal(t, otlpTracesPath, path)
+	assert.Equal(t, "key", header.Get("x-api-key"))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+
+	require.Len(t, body.ResourceSpans, 1)
+	require.Len(t, body.ResourceSpans[0].ScopeSpans, 1)
+	assert.Equal(t, "test", body.ResourceSpans[0].ScopeSpans[0].Scope.Name)
+	spans := body.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+
+	s := spans[0]
+	assert.Equal(t, "child", s.Name)
+	assert.Equal(t, linked.TraceID().String(), s.TraceID)
+	assert.Equal(t, linked.SpanID().String(), s.ParentSpanID)
+	assert.Equal(t, 2, s.Status.Code)
+	assert.Equal(t, "failed", s.Status.Message)
+	require.Len(t, s.Links, 1)
+	assert.Equal(t, linked.SpanID().String(), s.Links[0].SpanID)
+	startNanos, err := strconv.ParseInt(s.StartTimeUnixNano, 10, 64)
+	require.NoError(t, err)
+	endNanos, err := strconv.ParseInt(s.EndTimeUnixNano, 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second.Nanoseconds(), endNanos-startNanos)
+
+	attrs := map[string]otlpValue{}
+	for _, kv := range s.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	require.NotNil(t, attrs["string"].StringValue)
+	assert.Equal(t, "value", *attrs["string"].StringValue)
+	require.NotNil(t, attrs["int"].IntValue)
+	assert.Equal(t, "5", *attrs["int"].IntValue)
+	require.NotNil(t, attrs["bool"].BoolValue)
+	assert.True(t, *attrs["bool"].BoolValue)
+
+	assert.Empty(t, spans[1].ParentSpanID)
+
+	t.Run("ReturnsCollectorErrors", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer failing.Close()
+		exporter, err := newOTLPSpanExporter(TracerConfig{OTLPEndpoint: failing.URL + "/custom/path"})
+		require.NoError(t, err)
+		assert.Equal(t, failing.URL+"/custom/path", exporter.endpoint)
+		assert.Error(t, exporter.ExportSpans(context.Background(), recorder.Ended()))
+	})
+}
+
+func TestTracerConfigValidateAndDefault(t *testing.T) {
+	conf := TracerConfig{Enabled: true}
+	assert.NoError(t, conf.ValidateAndDefault())
+	assert.Equal(t, TracerExporterLog, conf.Exporter)
+
+	conf = TracerConfig{Enabled: true, Exporter: TracerExporterOTLP}
+	assert.Error(t, conf.ValidateAndDefault())
+	conf.OTLPEndpoint = "collector:4318"
+	assert.Error(t, conf.ValidateAndDefault())
+	conf.OTLPEndpoint = "http://collector:4318"
+	assert.NoError(t, conf.ValidateAndDefault())
+}
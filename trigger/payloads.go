@@ -48,6 +48,8 @@ type commonTemplateData struct {
 	PastTenseStatus string
 	Headers         http.Header
 	FailedTests     []task.TestResult
+	// Owners are the CODEOWNERS owners of the failing tests.
+	Owners []string
 
 	Task       *task.Task
 	ProjectRef *model.ProjectRef
@@ -371,6 +373,9 @@ func makeCommonPayload(sub *event.Subscription, eventAttributes event.Attributes
 	headerMap := eventAttributes.ToSelectorMap()
 	headerMap["trigger"] = append(headerMap["trigger"], sub.Trigger)
 	headerMap[event.SelectorStatus] = append(headerMap[event.SelectorStatus], data.PastTenseStatus)
+	if len(data.Owners) > 0 {
+		headerMap["code-owner"] = data.Owners
+	}
 	data.Headers = makeHeaders(headerMap)
 	data.SubscriptionID = sub.ID
 	if data.Task != nil {
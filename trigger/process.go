@@ -9,6 +9,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
@@ -95,6 +96,8 @@ type ProcessorArgs struct {
 	EventID           string
 	DefinitionID      string
 	Alias             string
+	Labels            map[string]string
+	Parameters        []patch.Parameter
 }
 
 // EvalProjectTriggers takes an event log entry and a processor (either the mock or TriggerDownstreamVersion)
@@ -135,7 +138,7 @@ func EvalProjectTriggers(e *event.EventLogEntry, processor projectProcessor) ([]
 }
 
 func triggerDownstreamProjectsForTask(t *task.Task, e *event.EventLogEntry, processor projectProcessor) ([]model.Version, error) {
-	if t.Requester != evergreen.RepotrackerVersionRequester {
+	if !utility.StringSliceContains(downstreamTriggerRequesters, t.Requester) {
 		return nil, nil
 	}
 	downstreamProjects, err := model.FindDownstreamProjects(t.Project)
@@ -146,6 +149,13 @@ func triggerDownstreamProjectsForTask(t *task.Task, e *event.EventLogEntry, proc
 	if err != nil {
 		return nil, errors.Wrap(err, "error finding version")
 	}
+	if sourceVersion == nil {
+		return nil, errors.Errorf("version '%s' not found", t.Version)
+	}
+	remaining, err := remainingDownstreamTriggers(sourceVersion)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 
 	catcher := grip.NewBasicCatcher()
 	versions := []model.Version{}
@@ -189,6 +199,19 @@ projectLoop:
 				}
 			}
 
+			params, err := trigger.ExpandParameters(model.TriggerUpstream{
+				Version:      sourceVersion,
+				Level:        model.ProjectTriggerLevelTask,
+				ID:           t.Id,
+				Status:       t.Status,
+				BuildVariant: t.BuildVariant,
+				TaskName:     t.DisplayName,
+			})
+			if err != nil {
+				catcher.Wrapf(err, "trigger '%s'", trigger.DefinitionID)
+				continue
+			}
+
 			args := ProcessorArgs{
 				SourceVersion:     sourceVersion,
 				DownstreamProject: ref,
@@ -198,6 +221,17 @@ projectLoop:
 				EventID:           e.ID,
 				DefinitionID:      trigger.DefinitionID,
 				Alias:             trigger.Alias,
+				Labels:            trigger.Labels,
+				Parameters:        params,
+			}
+			if len(versions) >= remaining {
+				grip.Warning(message.Fields{
+					"message":        "not triggering downstream project because the source version reached its trigger limit",
+					"source_version": sourceVersion.Id,
+					"downstream":     ref.Id,
+					"definition_id":  trigger.DefinitionID,
+				})
+				break projectLoop
 			}
 			v, err := processor(args)
 			if err != nil {
@@ -215,7 +249,7 @@ projectLoop:
 }
 
 func triggerDownstreamProjectsForBuild(b *build.Build, e *event.EventLogEntry, processor projectProcessor) ([]model.Version, error) {
-	if b.Requester != evergreen.RepotrackerVersionRequester {
+	if !utility.StringSliceContains(downstreamTriggerRequesters, b.Requester) {
 		return nil, nil
 	}
 	downstreamProjects, err := model.FindDownstreamProjects(b.Project)
@@ -226,6 +260,13 @@ func triggerDownstreamProjectsForBuild(b *build.Build, e *event.EventLogEntry, p
 	if err != nil {
 		return nil, errors.Wrap(err, "error finding version")
 	}
+	if sourceVersion == nil {
+		return nil, errors.Errorf("version '%s' not found", b.Version)
+	}
+	remaining, err := remainingDownstreamTriggers(sourceVersion)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 
 	catcher := grip.NewBasicCatcher()
 	versions := []model.Version{}
@@ -252,6 +293,18 @@ projectLoop:
 				}
 			}
 
+			params, err := trigger.ExpandParameters(model.TriggerUpstream{
+				Version:      sourceVersion,
+				Level:        model.ProjectTriggerLevelBuild,
+				ID:           b.Id,
+				Status:       b.Status,
+				BuildVariant: b.BuildVariant,
+			})
+			if err != nil {
+				catcher.Wrapf(err, "trigger '%s'", trigger.DefinitionID)
+				continue
+			}
+
 			args := ProcessorArgs{
 				SourceVersion:     sourceVersion,
 				DownstreamProject: ref,
@@ -261,6 +314,17 @@ projectLoop:
 				EventID:           e.ID,
 				DefinitionID:      trigger.DefinitionID,
 				Alias:             trigger.Alias,
+				Labels:            trigger.Labels,
+				Parameters:        params,
+			}
+			if len(versions) >= remaining {
+				grip.Warning(message.Fields{
+					"message":        "not triggering downstream project because the source version reached its trigger limit",
+					"source_version": sourceVersion.Id,
+					"downstream":     ref.Id,
+					"definition_id":  trigger.DefinitionID,
+				})
+				break projectLoop
 			}
 			v, err := processor(args)
 			if err != nil {
@@ -276,3 +340,36 @@ projectLoop:
 
 	return versions, catcher.Resolve()
 }
+
+// downstreamTriggerRequesters are the requesters whose versions can trigger
+// downstream projects. Triggered versions can continue a chain of triggers
+// until the configured depth limit is reached.
+var downstreamTriggerRequesters = []string{
+	evergreen.RepotrackerVersionRequester,
+	evergreen.TriggerRequester,
+}
+
+// remainingDownstreamTriggers returns how many more downstream versions the
+// source version is allowed to trigger, taking into account both how deep
+// it is in a chain of triggers and how many versions it has already
+// triggered.
+func remainingDownstreamTriggers(sourceVersion *model.Version) (int, error) {
+	settings, err := evergreen.GetConfig()
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting evergreen settings")
+	}
+	if sourceVersion.GetTriggerDepth() >= settings.Triggers.GetMaxDownstreamDepth() {
+		grip.Info(message.Fields{
+			"message":        "not triggering downstream projects because the source version is at the maximum trigger depth",
+			"source_version": sourceVersion.Id,
+			"trigger_depth":  sourceVersion.GetTriggerDepth(),
+			"max_depth":      settings.Triggers.GetMaxDownstreamDepth(),
+		})
+		return 0, nil
+	}
+	remaining := settings.Triggers.GetMaxDownstreamFanOut() - len(sourceVersion.SatisfiedTriggers)
+	if remaining < 0 {
+		return 0, nil
+	}
+	return remaining, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/utility"
@@ -285,7 +286,12 @@ func TestProjectTriggerIntegration(t *testing.T) {
 		RemotePath: "self-tests.yml",
 		Branch:     "main",
 		Triggers: []model.TriggerDefinition{
-			{Project: "upstream", Level: "task", DefinitionID: "def1", TaskRegex: "upstream*", Status: evergreen.TaskSucceeded, ConfigFile: "trigger/testdata/downstream_config.yml", Alias: "a1"},
+			{Project: "upstream", Level: "task", DefinitionID: "def1", TaskRegex: "upstream*", Status: evergreen.TaskSucceeded, ConfigFile: "trigger/testdata/downstream_config.yml", Alias: "a1",
+				Parameters: []patch.Parameter{
+					{Key: "static", Value: "value"},
+					{Key: "upstream_revision", Value: "${trigger_revision}"},
+				},
+			},
 		},
 	}
 	assert.NoError(downstreamProjectRef.Insert())
@@ -327,6 +333,10 @@ func TestProjectTriggerIntegration(t *testing.T) {
 		assert.Equal(upstreamTask.Id, v.TriggerID)
 		assert.Equal("task", v.TriggerType)
 		assert.Equal(e.ID, v.TriggerEvent)
+		assert.Equal(upstreamVersion.Id, v.UpstreamVersionID)
+		require.Len(v.Parameters, 2)
+		assert.Equal("value", v.Parameters[0].Value)
+		assert.Equal(upstreamVersion.Revision, v.Parameters[1].Value)
 	}
 	builds, err := build.Find(build.ByVersion(downstreamVersions[0].Id))
 	assert.NoError(err)
@@ -362,6 +372,7 @@ func TestProjectTriggerIntegration(t *testing.T) {
 	upstreamVersionFromDB, err := model.VersionFindOneId(upstreamVersion.Id)
 	assert.NoError(err)
 	assert.Contains(upstreamVersionFromDB.SatisfiedTriggers, "def1")
+	assert.Equal([]string{"downstream_abc_def1"}, upstreamVersionFromDB.DownstreamVersionIDs)
 	downstreamVersions, err = EvalProjectTriggers(&e, TriggerDownstreamVersion)
 	assert.NoError(err)
 	assert.Len(downstreamVersions, 0)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/repotracker"
 	"github.com/pkg/errors"
@@ -28,6 +29,8 @@ func TriggerDownstreamVersion(args ProcessorArgs) (*model.Version, error) {
 	metadata.EventID = args.EventID
 	metadata.TriggerDefinitionID = args.DefinitionID
 	metadata.Alias = args.Alias
+	metadata.Labels = patch.LabelsFromMap(args.Labels)
+	metadata.Parameters = args.Parameters
 
 	// get the downstream config
 	projectInfo := model.ProjectInfo{}
@@ -50,6 +53,9 @@ func TriggerDownstreamVersion(args ProcessorArgs) (*model.Version, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = args.SourceVersion.AddDownstreamVersion(v.Id); err != nil {
+		return nil, err
+	}
 	settings, err := evergreen.GetConfig()
 	if err != nil {
 		return nil, errors.Wrap(err, "error getting evergreen settings")
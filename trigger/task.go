@@ -138,6 +138,10 @@ type taskTriggers struct {
 	task     *task.Task
 	owner    string
 	uiConfig evergreen.UIConfig
+	// failureOwners are the CODEOWNERS owners of the task's failing tests,
+	// and failureOwnerUsers are the Evergreen users they map to.
+	failureOwners     []string
+	failureOwnerUsers []string
 
 	oldTestResults map[string]*task.TestResult
 
@@ -182,6 +186,15 @@ func (t *taskTriggers) Fetch(e *event.EventLogEntry) error {
 	}
 	t.owner = author
 
+	if t.task.Status == evergreen.TaskFailed {
+		projectRef, err := model.FindMergedProjectRef(t.task.Project, t.task.Version, true)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch project ref")
+		}
+		t.failureOwners = failureOwners(projectRef, t.task)
+		t.failureOwnerUsers = ownerUsers(t.failureOwners)
+	}
+
 	t.event = e
 
 	return nil
@@ -208,6 +221,13 @@ func (t *taskTriggers) Attributes() event.Attributes {
 	if t.owner != "" {
 		attributes.Owner = append(attributes.Owner, t.owner)
 	}
+	// Owners of the failing tests are treated as owners of the task so that
+	// their subscriptions to their own tasks are notified of the failure.
+	for _, userID := range t.failureOwnerUsers {
+		if !utility.StringSliceContains(attributes.Owner, userID) {
+			attributes.Owner = append(attributes.Owner, userID)
+		}
+	}
 
 	return attributes
 }
@@ -307,6 +327,14 @@ func (t *taskTriggers) makeData(sub *event.Subscription, pastTenseOverride, test
 		},
 	}
 
+	data.Owners = t.failureOwners
+	if len(data.Owners) > 0 {
+		data.slack[0].Fields = append(data.slack[0].Fields, &message.SlackAttachmentField{
+			Title: "Owners",
+			Value: strings.Join(data.Owners, ", "),
+		})
+	}
+
 	return &data, nil
 }
 
@@ -25,7 +25,8 @@ Host: {{host .}}
 Project: [{{.Project.DisplayName}}|{{.UIRoot}}/waterfall/{{.Project.Id}}]
 Commit: [diff|https://github.com/{{.Project.Owner}}/{{.Project.Repo}}/commit/{{.Version.Revision}}]: {{.Version.Message}} | {{.Task.CreateTime | formatAsTimestamp}}
 Evergreen Subscription: {{.SubscriptionID}}; Evergreen Event: {{.EventID}}
-{{range .Tests}}*{{.Name}}* - [Logs|{{.URL}}] | [History|{{.HistoryURL}}]
+{{if .Owners}}Owners: {{join .Owners ", "}}
+{{end}}{{range .Tests}}*{{.Name}}* - [Logs|{{.URL}}] | [History|{{.HistoryURL}}]
 {{end}}
 {{range taskLogURLs . }}[Task Logs ({{.DisplayName}}) | {{.URL}}]
 {{end}}`
@@ -42,6 +43,7 @@ var descriptionTemplate = template.Must(template.New("Desc").Funcs(template.Func
 	"formatAsTimestamp": formatAsTimestamp,
 	"host":              getHostMetadata,
 	"taskLogURLs":       getTaskLogURLs,
+	"join":              strings.Join,
 }).Parse(descriptionTemplateString))
 
 func formatAsTimestamp(t time.Time) string {
@@ -160,6 +162,8 @@ type jiraTemplateData struct {
 	Tests              []jiraTestFailure
 	SpecificTaskStatus string
 	TaskDisplayName    string
+	// Owners are the CODEOWNERS owners of the failing tests.
+	Owners []string
 }
 
 func makeSummaryPrefix(t *task.Task, failed int) string {
@@ -192,6 +196,7 @@ func (j *jiraBuilder) build() (*message.JiraIssue, error) {
 	}
 
 	j.data.SpecificTaskStatus = j.data.Task.GetDisplayStatus()
+	j.data.Owners = failureOwners(j.data.Project, j.data.Task)
 	description, err := j.getDescription()
 	if err != nil {
 		return nil, errors.Wrap(err, "creating description")
@@ -221,6 +226,9 @@ func (j *jiraBuilder) build() (*message.JiraIssue, error) {
 		Components:  components,
 		Labels:      labels,
 	}
+	if len(j.data.Owners) > 0 {
+		issue.Assignee = ownerAssignee(j.data.Owners)
+	}
 
 	grip.Info(message.Fields{
 		"message":      "creating jira ticket for failure",
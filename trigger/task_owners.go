@@ -0,0 +1,71 @@
+package trigger
+
+import (
+	"context"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+// failureOwners returns the CODEOWNERS owners of the task's failing tests.
+// Ownership only adds to a notification, so errors are logged rather than
+// preventing the notification from being sent.
+func failureOwners(pRef *model.ProjectRef, t *task.Task) []string {
+	if pRef == nil || !pRef.IsCodeOwnersRoutingEnabled() {
+		return nil
+	}
+	owners, err := model.FailureOwners(context.Background(), pRef, t)
+	grip.Warning(message.WrapError(err, message.Fields{
+		"message": "could not determine owners of task failure",
+		"task_id": t.Id,
+		"project": pRef.Id,
+	}))
+	return owners
+}
+
+// ownerAssignee returns the Evergreen user that the first owner who is a
+// known GitHub user maps to. Teams and email addresses cannot be assigned.
+func ownerAssignee(owners []string) string {
+	for _, owner := range owners {
+		if userID := ownerUser(owner); userID != "" {
+			return userID
+		}
+	}
+	return ""
+}
+
+// ownerUsers returns the Evergreen users that the owners who are known GitHub
+// users map to, so that failures can be routed to their subscriptions.
+func ownerUsers(owners []string) []string {
+	var userIDs []string
+	for _, owner := range owners {
+		if userID := ownerUser(owner); userID != "" {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
+
+// ownerUser returns the Evergreen user that the owner maps to, if the owner
+// is a known GitHub user.
+func ownerUser(owner string) string {
+	if !strings.HasPrefix(owner, "@") || strings.Contains(owner, "/") {
+		return ""
+	}
+	u, err := user.FindByGithubName(strings.TrimPrefix(owner, "@"))
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "could not find user for owner",
+			"owner":   owner,
+		}))
+		return ""
+	}
+	if u == nil {
+		return ""
+	}
+	return u.Id
+}
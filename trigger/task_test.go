@@ -411,6 +411,12 @@ func (s *taskSuite) TestAbortedTaskDoesNotNotify() {
 	s.Empty(n)
 }
 
+func (s *taskSuite) TestFailureOwnersAreTaskOwners() {
+	s.t.owner = "author"
+	s.t.failureOwnerUsers = []string{"alice", "author"}
+	s.Equal([]string{"author", "alice"}, s.t.Attributes().Owner)
+}
+
 func (s *taskSuite) TestExecutionTask() {
 	t := task.Task{
 		Id:             "dt",
@@ -3,6 +3,7 @@ package trigger
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -21,6 +22,8 @@ import (
 func init() {
 	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionStateChange, makeVersionTriggers)
 	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionGithubCheckFinished, makeVersionTriggers)
+	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionWarningBudgetExceeded, makeVersionTriggers)
+	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionTasksBlocked, makeVersionTriggers)
 }
 
 type versionTriggers struct {
@@ -42,6 +45,8 @@ func makeVersionTriggers() eventHandler {
 		event.TriggerRegression:             t.versionRegression,
 		event.TriggerExceedsDuration:        t.versionExceedsDuration,
 		event.TriggerRuntimeChangeByPercent: t.versionRuntimeChange,
+		event.TriggerWarningBudgetExceeded:  t.versionWarningBudgetExceeded,
+		event.TriggerTasksBlocked:           t.versionTasksBlocked,
 	}
 	return t
 }
@@ -184,6 +189,88 @@ func (t *versionTriggers) versionGithubCheckOutcome(sub *event.Subscription) (*n
 	return t.generate(sub, "")
 }
 
+func (t *versionTriggers) versionWarningBudgetExceeded(sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.VersionWarningBudgetExceeded {
+		return nil, nil
+	}
+	budget := t.version.WarningBudget
+	if budget == nil || !budget.Exceeded() {
+		return nil, nil
+	}
+
+	data, err := t.makeData(sub, "exceeded its warning budget")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to collect version data")
+	}
+	data.githubState = message.GithubStateFailure
+	data.githubContext = "evergreen/config-warnings"
+	data.githubDescription = fmt.Sprintf("%d config warnings exceed the budget of %d (%+d since previous version)", budget.NumWarnings, budget.MaxWarnings, budget.Delta())
+	for i := range data.slack {
+		data.slack[i].Color = evergreenFailColor
+		data.slack[i].Text = data.githubDescription
+	}
+	payload, err := makeCommonPayload(sub, t.Attributes(), data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build notification")
+	}
+
+	return notification.New(t.event.ID, sub.Trigger, &sub.Subscriber, payload)
+}
+
+// versionTasksBlocked notifies about all the version's tasks that were just
+// blocked by a task they depend on, with the chain of dependencies from the
+// task that blocked them to each blocked task.
+func (t *versionTriggers) versionTasksBlocked(sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.VersionTasksBlocked || len(t.data.BlockedTasks) == 0 {
+		return nil, nil
+	}
+
+	taskIDs := []string{}
+	for _, blocked := range t.data.BlockedTasks {
+		taskIDs = append(taskIDs, blocked.Chain...)
+	}
+	tasks, err := task.FindAll(db.Query(task.ByIds(taskIDs)).WithFields(task.DisplayNameKey, task.BuildVariantKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "getting tasks in blocked dependency chains")
+	}
+	taskNames := map[string]string{}
+	for _, tsk := range tasks {
+		taskNames[tsk.Id] = fmt.Sprintf("%s (%s)", tsk.DisplayName, tsk.BuildVariant)
+	}
+	chains := make([]string, 0, len(t.data.BlockedTasks))
+	for _, blocked := range t.data.BlockedTasks {
+		names := make([]string, 0, len(blocked.Chain))
+		for _, id := range blocked.Chain {
+			name, ok := taskNames[id]
+			if !ok {
+				name = id
+			}
+			names = append(names, name)
+		}
+		chains = append(chains, strings.Join(names, " -> "))
+	}
+
+	pastTense := fmt.Sprintf("had %d tasks blocked by failed dependencies", len(t.data.BlockedTasks))
+	if len(t.data.BlockedTasks) == 1 {
+		pastTense = "had a task blocked by a failed dependency"
+	}
+	data, err := t.makeData(sub, pastTense)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to collect version data")
+	}
+	data.Description = fmt.Sprintf("Blocked tasks: %s", strings.Join(chains, "; "))
+	for i := range data.slack {
+		data.slack[i].Color = evergreenFailColor
+		data.slack[i].Text = strings.Join(chains, "\n")
+	}
+	payload, err := makeCommonPayload(sub, t.Attributes(), data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build notification")
+	}
+
+	return notification.New(t.event.ID, sub.Trigger, &sub.Subscriber, payload)
+}
+
 func (t *versionTriggers) versionFailure(sub *event.Subscription) (*notification.Notification, error) {
 	if t.data.Status != evergreen.VersionFailed {
 		return nil, nil
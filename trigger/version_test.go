@@ -10,6 +10,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/alertrecord"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson"
@@ -325,3 +326,37 @@ func (s *VersionSuite) TestVersionRuntimeChange() {
 	s.NoError(err)
 	s.NotNil(n)
 }
+
+func (s *VersionSuite) TestVersionTasksBlocked() {
+	n, err := s.t.versionTasksBlocked(&s.subs[0])
+	s.NoError(err)
+	s.Nil(n, "should not notify for other events")
+
+	for _, tsk := range []task.Task{
+		{Id: "compile", DisplayName: "compile", BuildVariant: "ubuntu", Version: s.version.Id},
+		{Id: "test", DisplayName: "test", BuildVariant: "ubuntu", Version: s.version.Id},
+	} {
+		s.NoError(tsk.Insert())
+	}
+	s.t.event = &event.EventLogEntry{
+		ID:           "event",
+		ResourceType: event.ResourceTypeVersion,
+		EventType:    event.VersionTasksBlocked,
+		ResourceId:   s.version.Id,
+	}
+	s.t.data = &event.VersionEventData{
+		BlockedTasks: []event.BlockedTask{{TaskID: "test", Chain: []string{"compile", "test"}}},
+	}
+	sub := event.NewTasksBlockedSubscriptionByOwner("me", event.Subscriber{
+		Type:   event.SlackSubscriberType,
+		Target: "@me",
+	})
+	n, err = s.t.versionTasksBlocked(&sub)
+	s.NoError(err)
+	s.Require().NotNil(n)
+	payload, ok := n.Payload.(*notification.SlackPayload)
+	s.Require().True(ok)
+	s.Contains(payload.Body, "had a task blocked by a failed dependency")
+	s.Require().NotEmpty(payload.Attachments)
+	s.Equal("compile (ubuntu) -> test (ubuntu)", payload.Attachments[0].Text)
+}
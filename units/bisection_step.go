@@ -0,0 +1,119 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/repotracker"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const bisectionStepJobName = "bisection-step"
+
+func init() {
+	registry.AddJobType(bisectionStepJobName, func() amboy.Job { return makeBisectionStepJob() })
+}
+
+type bisectionStepJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeBisectionStepJob() *bisectionStepJob {
+	j := &bisectionStepJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    bisectionStepJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewBisectionStepJob abandons the steps of in-progress bisections whose
+// tasks were deactivated, are blocked, or have run for too long, and moves
+// those bisections on to their next step. It also looks up the commits that
+// never got a version for bisections that are about to search them, and
+// creates versions in those commits as the bisections need them.
+func NewBisectionStepJob(id string) amboy.Job {
+	j := makeBisectionStepJob()
+	j.SetID(fmt.Sprintf("%s.%s", bisectionStepJobName, id))
+	return j
+}
+
+func (j *bisectionStepJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	j.AddError(model.AbandonStalledBisectionSteps(time.Now()))
+
+	bisections, err := model.FindBisectionsAwaitingVersions()
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	settings := evergreen.GetEnvironment().Settings()
+	for i := range bisections {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+		b := &bisections[i]
+		pRef, err := model.FindMergedProjectRef(b.ProjectId, "", true)
+		if err != nil {
+			j.AddError(errors.Wrapf(err, "finding project '%s' for bisection '%s'", b.ProjectId, b.Id))
+			continue
+		}
+		if pRef == nil {
+			j.AddError(errors.Errorf("project '%s' for bisection '%s' not found", b.ProjectId, b.Id))
+			continue
+		}
+		if b.AwaitingCommits {
+			j.AddError(errors.Wrapf(j.searchUnversionedRevisions(ctx, settings, pRef, b), "searching unversioned commits for bisection '%s'", b.Id))
+			continue
+		}
+		j.AddError(errors.Wrapf(j.createPendingVersion(ctx, settings, pRef, b), "creating version for bisection '%s'", b.Id))
+	}
+}
+
+func (j *bisectionStepJob) searchUnversionedRevisions(ctx context.Context, settings *evergreen.Settings, pRef *model.ProjectRef, b *model.Bisection) error {
+	good, bad, err := b.RevisionRange()
+	if err != nil {
+		return err
+	}
+	revisions, err := repotracker.GetUnversionedRevisions(ctx, settings, *pRef, good, bad)
+	if err != nil {
+		// Don't keep the bisection waiting on commits that can't be looked
+		// up, since that also disables stepback for the task.
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message":   "could not look up unversioned commits, finishing bisection without them",
+			"bisection": b.Id,
+			"project":   b.ProjectId,
+			"job":       j.ID(),
+		}))
+		revisions = nil
+	}
+	return b.SearchUnversionedRevisions(revisions)
+}
+
+func (j *bisectionStepJob) createPendingVersion(ctx context.Context, settings *evergreen.Settings, pRef *model.ProjectRef, b *model.Bisection) error {
+	v, err := repotracker.CreateBisectionVersion(ctx, settings, *pRef, b.PendingRevision)
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message":   "could not create version for bisection, skipping commit",
+			"bisection": b.Id,
+			"project":   b.ProjectId,
+			"revision":  b.PendingRevision,
+			"job":       j.ID(),
+		}))
+		return b.SkipPendingRevision()
+	}
+	return b.ResumeInVersion(v.Id)
+}
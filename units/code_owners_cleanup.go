@@ -0,0 +1,48 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+)
+
+const codeOwnersCleanupJobName = "code-owners-cleanup"
+
+func init() {
+	registry.AddJobType(codeOwnersCleanupJobName, func() amboy.Job { return makeCodeOwnersCleanupJob() })
+}
+
+type codeOwnersCleanupJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeCodeOwnersCleanupJob() *codeOwnersCleanupJob {
+	j := &codeOwnersCleanupJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    codeOwnersCleanupJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewCodeOwnersCleanupJob removes the cached CODEOWNERS files that are older
+// than the retention period.
+func NewCodeOwnersCleanupJob(id string) amboy.Job {
+	j := makeCodeOwnersCleanupJob()
+	j.SetID(fmt.Sprintf("%s.%s", codeOwnersCleanupJobName, id))
+	return j
+}
+
+func (j *codeOwnersCleanupJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	j.AddError(model.RemoveExpiredCodeOwners(time.Now().Add(-model.CodeOwnersTTL)))
+}
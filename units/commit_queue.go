@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -67,6 +68,9 @@ func NewCommitQueueJob(env evergreen.Environment, queueID string, id string) amb
 
 func (j *commitQueueJob) Run(ctx context.Context) {
 	defer j.MarkComplete()
+	// The commit queue's GitHub calls decide whether PRs merge, so they take
+	// priority over other calls when the rate limit is low.
+	ctx = thirdparty.WithGithubCallPriority(ctx, thirdparty.GithubPriorityCritical)
 
 	// reconstitute the environment because it's not stored in the database
 	if j.env == nil {
@@ -342,11 +346,19 @@ func (j *commitQueueJob) processGitHubPRItem(ctx context.Context, cq *commitqueu
 		j.dequeue(cq, nextItem)
 		return
 	}
-	projectConfig, _, err := model.GetPatchedProject(ctx, patchDoc, githubToken)
+	projectConfig, patchConfig, err := model.GetPatchedProject(ctx, patchDoc, githubToken)
 	if err != nil {
 		j.logError(err, "problem getting patched project", nextItem)
 		j.dequeue(cq, nextItem)
 		j.AddError(sendCommitQueueGithubStatus(j.env, pr, message.GithubStateFailure, "can't get project config", ""))
+		return
+	}
+	if err = preflightCommitQueueConfig(ctx, projectConfig, projectRef, patchConfig.PatchedProjectConfig); err != nil {
+		j.logError(err, "PR's changes make the project config invalid", nextItem)
+		event.LogCommitQueueEnqueueFailed(patchDoc.Id.Hex(), err)
+		j.dequeue(cq, nextItem)
+		j.AddError(sendCommitQueueGithubStatus(j.env, pr, message.GithubStateFailure, "PR's changes make the project config invalid", ""))
+		return
 	}
 
 	v, err := model.FinalizePatch(ctx, patchDoc, evergreen.MergeTestRequester, githubToken)
@@ -402,7 +414,7 @@ func (j *commitQueueJob) processCLIPatchItem(ctx context.Context, cq *commitqueu
 		return
 	}
 
-	if err = AddMergeTaskAndVariant(patchDoc, project, projectRef, commitqueue.SourceDiff); err != nil {
+	if err = AddMergeTaskAndVariant(ctx, patchDoc, project, projectRef, commitqueue.SourceDiff); err != nil {
 		j.logError(err, "can't set patch project config", nextItem)
 		event.LogCommitQueueEnqueueFailed(nextItem.Issue, err)
 		j.dequeue(cq, nextItem)
@@ -501,6 +513,9 @@ func sendCommitQueueGithubStatus(env evergreen.Environment, pr *github.PullReque
 		URL:         url,
 	}
 
+	if err = thirdparty.ReserveGithubStatus(thirdparty.GithubPriorityCritical); err != nil {
+		return errors.Wrap(err, "reserving GitHub budget for commit queue status")
+	}
 	c := message.NewGithubStatusMessageWithRepo(level.Notice, msg)
 	sender.Send(c)
 
@@ -520,7 +535,7 @@ func validateBranch(branch *github.Branch) error {
 	return nil
 }
 
-func AddMergeTaskAndVariant(patchDoc *patch.Patch, project *model.Project, projectRef *model.ProjectRef, source string) error {
+func AddMergeTaskAndVariant(ctx context.Context, patchDoc *patch.Patch, project *model.Project, projectRef *model.ProjectRef, source string) error {
 	settings, err := evergreen.GetConfig()
 	if err != nil {
 		return errors.Wrap(err, "error retrieving Evergreen config")
@@ -607,15 +622,8 @@ func AddMergeTaskAndVariant(patchDoc *patch.Patch, project *model.Project, proje
 	project.Tasks = append(project.Tasks, mergeTask)
 	project.TaskGroups = append(project.TaskGroups, mergeTaskGroup)
 
-	validationErrors := validator.CheckProjectErrors(project, true)
-	validationErrors = append(validationErrors, validator.CheckProjectSettings(project, projectRef, false)...)
-	validationErrors = append(validationErrors, validator.CheckPatchedProjectConfigErrors(patchDoc.PatchedProjectConfig)...)
-	catcher := grip.NewBasicCatcher()
-	for _, validationErr := range validationErrors.AtLevel(validator.Error) {
-		catcher.Add(validationErr)
-	}
-	if catcher.HasErrors() {
-		return errors.Errorf("project validation failed: %s", catcher.Resolve())
+	if err := preflightCommitQueueConfig(ctx, project, projectRef, patchDoc.PatchedProjectConfig); err != nil {
+		return err
 	}
 	yamlBytes, err := yaml.Marshal(project)
 	if err != nil {
@@ -633,6 +641,30 @@ func AddMergeTaskAndVariant(patchDoc *patch.Patch, project *model.Project, proje
 	return nil
 }
 
+// CommitQueueConfigError is returned when a commit queue item's changes would
+// make the project config invalid.
+type CommitQueueConfigError struct {
+	Errors validator.ValidationErrors
+}
+
+func (e CommitQueueConfigError) Error() string {
+	return fmt.Sprintf("project validation failed: %s", strings.TrimSpace(validator.ValidationErrorsToString(e.Errors)))
+}
+
+// preflightCommitQueueConfig runs the full validator suite against the
+// project config with the commit queue item's changes applied, so that items
+// that would break the config for everyone are rejected before their merge
+// test version is created.
+func preflightCommitQueueConfig(ctx context.Context, project *model.Project, projectRef *model.ProjectRef, patchedProjectConfig string) error {
+	validationErrors := validator.CheckProjectErrors(ctx, project, projectRef, true)
+	validationErrors = append(validationErrors, validator.CheckProjectSettings(ctx, project, projectRef, false)...)
+	validationErrors = append(validationErrors, validator.CheckPatchedProjectConfigErrors(ctx, patchedProjectConfig)...)
+	if errs := validationErrors.AtLevel(validator.Error); len(errs) > 0 {
+		return CommitQueueConfigError{Errors: errs}
+	}
+	return nil
+}
+
 func setDefaultNotification(username string) error {
 	u, err := user.FindOneById(username)
 	if err != nil {
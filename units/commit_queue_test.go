@@ -21,6 +21,7 @@ import (
 	"github.com/evergreen-ci/utility"
 	"github.com/google/go-github/v34/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -158,7 +159,7 @@ func (s *commitQueueSuite) TestAddMergeTaskAndVariant() {
 	patchDoc := &patch.Patch{}
 	ref := &model.ProjectRef{}
 
-	s.NoError(AddMergeTaskAndVariant(patchDoc, project, ref, commitqueue.SourceDiff))
+	s.NoError(AddMergeTaskAndVariant(context.Background(), patchDoc, project, ref, commitqueue.SourceDiff))
 
 	s.Require().Len(patchDoc.BuildVariants, 1)
 	s.Equal(evergreen.MergeTaskVariant, patchDoc.BuildVariants[0])
@@ -303,3 +304,29 @@ func TestAddMergeTaskDependencies(t *testing.T) {
 	assert.Len(t, dbTask3.DependsOn, 1)
 	assert.Equal(t, dbTask2.Id, dbTask3.DependsOn[0].TaskId)
 }
+
+func TestPreflightCommitQueueConfig(t *testing.T) {
+	require.NoError(t, db.ClearCollections(distro.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(distro.Collection))
+	}()
+	require.NoError(t, (&distro.Distro{Id: "d"}).Insert())
+	pRef := &model.ProjectRef{Id: "p"}
+
+	project := &model.Project{
+		Identifier: "p",
+		Tasks:      []model.ProjectTask{{Name: "t1"}},
+		BuildVariants: []model.BuildVariant{
+			{Name: "bv", RunOn: []string{"d"}, Tasks: []model.BuildVariantTaskUnit{{Name: "t1"}}},
+		},
+	}
+	assert.NoError(t, preflightCommitQueueConfig(context.Background(), project, pRef, ""))
+
+	project.BuildVariants[0].Tasks = append(project.BuildVariants[0].Tasks, model.BuildVariantTaskUnit{Name: "nonexistent"})
+	err := preflightCommitQueueConfig(context.Background(), project, pRef, "")
+	require.Error(t, err)
+	configErr, ok := err.(CommitQueueConfigError)
+	require.True(t, ok)
+	assert.NotEmpty(t, configErr.Errors)
+	assert.Contains(t, err.Error(), "nonexistent")
+}
@@ -256,6 +256,25 @@ func PopulateIdleHostJobs(env evergreen.Environment) amboy.QueueOperation {
 	}
 }
 
+// PopulateDistroDrainJobs enqueues jobs to move tasks off of distros that are
+// still draining.
+func PopulateDistroDrainJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		drains, err := model.FindUnfinishedDistroDrains()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		catcher := grip.NewBasicCatcher()
+		ts := utility.RoundPartOfMinute(0).Format(TSFormat)
+		for _, drain := range drains {
+			catcher.Wrapf(amboy.EnqueueUniqueJob(ctx, queue, NewDistroDrainJob(drain.DistroID, ts)), "enqueueing drain job for distro '%s'", drain.DistroID)
+		}
+
+		return catcher.Resolve()
+	}
+}
+
 func PopulateLastContainerFinishTimeJobs() amboy.QueueOperation {
 	return func(ctx context.Context, queue amboy.Queue) error {
 		catcher := grip.NewBasicCatcher()
@@ -560,6 +579,139 @@ func PopulateDuplicateTaskCheckJobs() amboy.QueueOperation {
 	}
 }
 
+func PopulateStatusRollupReconciliationJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		if deferUnderLoad(evergreen.GetEnvironment(), "status rollup reconciliation") {
+			return nil
+		}
+
+		ts := utility.RoundPartOfHour(15).Format(TSFormat)
+		return queue.Put(ctx, NewStatusRollupReconciliationJob(ts))
+	}
+}
+
+// deferUnderLoad returns whether non-critical background work should be
+// skipped for now because the app server is shedding load.
+func deferUnderLoad(env evergreen.Environment, operation string) bool {
+	status := model.GetLoadShedder().Status(env)
+	if !status.Shedding() {
+		return false
+	}
+	grip.Info(message.Fields{
+		"message":       "deferring background work because the app server is shedding load",
+		"operation":     operation,
+		"db_latency_ms": status.DBLatency.Milliseconds(),
+		"queue_depth":   status.QueueDepth,
+		"pressure":      status.Pressure,
+		"mode":          "degraded",
+	})
+	return true
+}
+
+// PopulateStalePatchCleanupJobs adds a job to warn the authors of stale
+// patches and clean up the patches once they've been warned.
+func PopulateStalePatchCleanupJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		ts := utility.RoundPartOfHour(0).Format(TSFormat)
+		return amboy.EnqueueUniqueJob(ctx, queue, NewStalePatchCleanupJob(ts))
+	}
+}
+
+// PopulateTaskQuarantineExpiryJobs adds a job to warn about task quarantines
+// that will soon expire.
+func PopulateTaskQuarantineExpiryJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		ts := utility.RoundPartOfHour(0).Format(TSFormat)
+		return amboy.EnqueueUniqueJob(ctx, queue, NewTaskQuarantineExpiryJob(ts))
+	}
+}
+
+// PopulateValidationResultsCleanupJobs adds a job to remove the expired
+// results of incremental project config validations.
+func PopulateValidationResultsCleanupJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		ts := utility.RoundPartOfHour(0).Format(TSFormat)
+		return amboy.EnqueueUniqueJob(ctx, queue, NewValidationResultsCleanupJob(ts))
+	}
+}
+
+// PopulateCodeOwnersCleanupJobs adds a job to remove the expired cached
+// CODEOWNERS files.
+func PopulateCodeOwnersCleanupJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		ts := utility.RoundPartOfHour(0).Format(TSFormat)
+		return amboy.EnqueueUniqueJob(ctx, queue, NewCodeOwnersCleanupJob(ts))
+	}
+}
+
+// PopulateTaskFailureLogsCleanupJobs adds a job to remove the expired task
+// failure logs.
+func PopulateTaskFailureLogsCleanupJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		ts := utility.RoundPartOfHour(0).Format(TSFormat)
+		return amboy.EnqueueUniqueJob(ctx, queue, NewTaskFailureLogsCleanupJob(ts))
+	}
+}
+
+// PopulateBisectionStepJobs adds a job to abandon bisection steps whose tasks
+// will not finish and to create the versions that bisections are waiting for.
+func PopulateBisectionStepJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		ts := utility.RoundPartOfHour(15).Format(TSFormat)
+		return amboy.EnqueueUniqueJob(ctx, queue, NewBisectionStepJob(ts))
+	}
+}
+
+// PopulateExternalGateTimeoutJobs adds a job to deactivate the tasks held by
+// external gates that timed out before they were opened.
+func PopulateExternalGateTimeoutJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		ts := utility.RoundPartOfMinute(0).Format(TSFormat)
+		return amboy.EnqueueUniqueJob(ctx, queue, NewExternalGateTimeoutJob(ts))
+	}
+}
+
+// PopulateTasksBlockedNotificationJobs adds a job to notify the owners of the
+// tasks that were blocked by failed dependencies, once per version.
+func PopulateTasksBlockedNotificationJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		ts := utility.RoundPartOfMinute(0).Format(TSFormat)
+		return amboy.EnqueueUniqueJob(ctx, queue, NewTasksBlockedNotificationJob(ts))
+	}
+}
+
+// PopulateGithubVariantChecksJobs adds a job to post the GitHub checks for
+// variants whose status has changed.
+func PopulateGithubVariantChecksJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		ts := utility.RoundPartOfMinute(0).Format(TSFormat)
+		return queue.Put(ctx, NewGithubVariantChecksJob(ts))
+	}
+}
+
+// PopulateStuckTaskWatchdogJobs enqueues a job that handles tasks whose
+// heartbeat has gone stale.
+func PopulateStuckTaskWatchdogJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		flags, err := evergreen.GetServiceFlags()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if flags.MonitorDisabled {
+			grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+				"message": "monitor is disabled",
+				"impact":  "not detecting stuck tasks",
+				"mode":    "degraded",
+			})
+			return nil
+		}
+
+		ts := utility.RoundPartOfMinute(0).Format(TSFormat)
+		return queue.Put(ctx, NewStuckTaskWatchdogJob(ts))
+	}
+}
+
 // PopulateHostStatJobs adds host stats jobs.
 func PopulateHostStatJobs(parts int) amboy.QueueOperation {
 	return func(ctx context.Context, queue amboy.Queue) error {
@@ -1018,6 +1170,9 @@ func PopulateCacheHistoricalTestDataJob(part int) amboy.QueueOperation {
 			})
 			return nil
 		}
+		if deferUnderLoad(evergreen.GetEnvironment(), "cache historical test data") {
+			return nil
+		}
 
 		projects, err := model.FindAllMergedTrackedProjectRefs()
 		if err != nil {
@@ -1268,6 +1423,8 @@ func PopulateDataCleanupJobs(env evergreen.Environment) amboy.QueueOperation {
 		catcher := grip.NewBasicCatcher()
 		catcher.Add(queue.Put(ctx, NewTestResultsCleanupJob(utility.RoundPartOfMinute(2))))
 		catcher.Add(queue.Put(ctx, NewTestLogsCleanupJob(utility.RoundPartOfMinute(2))))
+		catcher.Add(amboy.EnqueueUniqueJob(ctx, queue, NewLogRetentionCleanupJob(utility.RoundPartOfDay(1))))
+		catcher.Add(amboy.EnqueueUniqueJob(ctx, queue, NewVersionArchivalJob(utility.RoundPartOfDay(1))))
 
 		return catcher.Resolve()
 	}
@@ -1283,6 +1440,15 @@ func PopulatePodAllocatorJobs(env evergreen.Environment) amboy.QueueOperation {
 			return errors.WithStack(err)
 		}
 
+		// Container tasks can fall back to hosts even if pod allocation is
+		// disabled, since there is no container capacity for them.
+		if err := task.FallBackContainerTasksToHosts(evergreen.StaleContainerTaskMonitor); err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "could not fall back container tasks to hosts",
+				"context": "pod allocation",
+			}))
+		}
+
 		if flags.PodAllocatorDisabled {
 			grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
 				"message": "pod allocation disabled",
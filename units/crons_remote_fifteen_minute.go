@@ -50,6 +50,8 @@ func (j *cronsRemoteFifteenMinuteJob) Run(ctx context.Context) {
 		PopulatePeriodicBuilds(),
 		PopulateReauthorizeUserJobs(j.env),
 		PopulateCheckUnmarkedBlockedTasks(),
+		PopulateStatusRollupReconciliationJobs(),
+		PopulateBisectionStepJobs(),
 	}
 
 	queue := j.env.RemoteQueue()
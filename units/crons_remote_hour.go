@@ -55,6 +55,11 @@ func (j *cronsRemoteHourJob) Run(ctx context.Context) {
 		PopulateVolumeExpirationJob(),
 		PopulateSSHKeyUpdates(j.env),
 		PopulateDuplicateTaskCheckJobs(),
+		PopulateStalePatchCleanupJobs(),
+		PopulateTaskQuarantineExpiryJobs(),
+		PopulateValidationResultsCleanupJobs(),
+		PopulateCodeOwnersCleanupJobs(),
+		PopulateTaskFailureLogsCleanupJobs(),
 	}
 
 	queue := j.env.RemoteQueue()
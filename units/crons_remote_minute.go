@@ -50,8 +50,12 @@ func (j *cronsRemoteMinuteJob) Run(ctx context.Context) {
 		PopulateBackgroundStatsJobs(j.env, 0),
 		PopulateContainerStateJobs(j.env),
 		PopulateDataCleanupJobs(j.env),
+		PopulateDistroDrainJobs(),
 		PopulateEventSendJobs(j.env),
+		PopulateExternalGateTimeoutJobs(),
+		PopulateTasksBlockedNotificationJobs(),
 		PopulateGenerateTasksJobs(j.env),
+		PopulateGithubVariantChecksJobs(),
 		PopulateHostMonitoring(j.env),
 		PopulateHostTerminationJobs(j.env),
 		PopulateIdleHostJobs(j.env),
@@ -62,6 +66,7 @@ func (j *cronsRemoteMinuteJob) Run(ctx context.Context) {
 		PopulateUserDataDoneJobs(j.env),
 		PopulatePodCreationJobs(j.env),
 		PopulatePodTerminationJobs(j.env),
+		PopulateStuckTaskWatchdogJobs(),
 	}
 
 	catcher := grip.NewBasicCatcher()
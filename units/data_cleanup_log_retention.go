@@ -0,0 +1,114 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	logRetentionCleanupJobName = "data-cleanup-log-retention"
+
+	// logRetentionPageSize is the number of tasks whose logs are removed at
+	// a time.
+	logRetentionPageSize = 1000
+)
+
+func init() {
+	registry.AddJobType(logRetentionCleanupJobName, func() amboy.Job {
+		return makeLogRetentionCleanupJob()
+	})
+}
+
+type dataCleanupLogRetention struct {
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	env evergreen.Environment
+}
+
+func makeLogRetentionCleanupJob() *dataCleanupLogRetention {
+	j := &dataCleanupLogRetention{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    logRetentionCleanupJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewLogRetentionCleanupJob returns a job that removes task and test logs
+// that are older than their project's log retention policy allows. Projects
+// without a retention policy are left to the global test log cleanup.
+func NewLogRetentionCleanupJob(ts time.Time) amboy.Job {
+	j := makeLogRetentionCleanupJob()
+	j.SetID(fmt.Sprintf("%s.%s", logRetentionCleanupJobName, ts.Format(TSFormat)))
+	j.UpdateTimeInfo(amboy.JobTimeInfo{MaxTime: 15 * time.Minute})
+	return j
+}
+
+func (j *dataCleanupLogRetention) Run(ctx context.Context) {
+	defer j.MarkComplete()
+	startAt := time.Now()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if flags.BackgroundCleanupDisabled {
+		return
+	}
+
+	projectRefs, err := model.FindAllMergedProjectRefs()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "finding project refs"))
+		return
+	}
+
+	for _, pRef := range projectRefs {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+		if !pRef.LogRetention.IsSet() {
+			continue
+		}
+
+		res, err := model.ApplyLogRetentionPolicy(ctx, j.env, &pRef, time.Now(), logRetentionPageSize)
+		j.AddError(errors.Wrapf(err, "applying log retention policy for project '%s'", pRef.Id))
+		grip.Info(message.Fields{
+			"job_id":            j.ID(),
+			"job_type":          j.Type().Name,
+			"message":           "applied log retention policy",
+			"project":           pRef.Id,
+			"policy":            pRef.GetLogRetentionPolicy(),
+			"tasks_considered":  res.TasksConsidered,
+			"task_logs":         res.TaskLogs,
+			"test_logs":         res.TestLogs,
+			"system_log_chunks": res.SystemLogChunks,
+			"has_errors":        err != nil,
+		})
+	}
+
+	grip.Info(message.Fields{
+		"job_id":   j.ID(),
+		"job_type": j.Type().Name,
+		"message":  "timing-info",
+		"total":    time.Since(startAt).Seconds(),
+	})
+}
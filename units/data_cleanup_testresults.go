@@ -105,6 +105,9 @@ deleteDocs:
 			}
 		}
 	}
+	// Attachments of test results stored in Cedar are kept as long as
+	// the test results stored in Evergreen.
+	j.AddError(testresult.RemoveAttachmentsCreatedBefore(timestamp))
 
 	grip.Info(message.Fields{
 		"job_id":             j.ID(),
@@ -0,0 +1,134 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	versionArchivalJobName = "data-cleanup-version-archival"
+
+	// versionArchivalLimit is the maximum number of versions archived for a
+	// project in a single run.
+	versionArchivalLimit = 100
+)
+
+func init() {
+	registry.AddJobType(versionArchivalJobName, func() amboy.Job {
+		return makeVersionArchivalJob()
+	})
+}
+
+type dataCleanupVersionArchival struct {
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	env evergreen.Environment
+}
+
+func makeVersionArchivalJob() *dataCleanupVersionArchival {
+	j := &dataCleanupVersionArchival{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    versionArchivalJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewVersionArchivalJob returns a job that moves the builds and tasks of
+// finished versions into cold storage once they are older than their
+// project's archival age. Projects without an archival age are left alone.
+func NewVersionArchivalJob(ts time.Time) amboy.Job {
+	j := makeVersionArchivalJob()
+	j.SetID(fmt.Sprintf("%s.%s", versionArchivalJobName, ts.Format(TSFormat)))
+	j.UpdateTimeInfo(amboy.JobTimeInfo{MaxTime: 30 * time.Minute})
+	return j
+}
+
+func (j *dataCleanupVersionArchival) Run(ctx context.Context) {
+	defer j.MarkComplete()
+	startAt := time.Now()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if flags.BackgroundCleanupDisabled {
+		return
+	}
+
+	projectRefs, err := model.FindAllMergedProjectRefs()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "finding project refs"))
+		return
+	}
+
+	for _, pRef := range projectRefs {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+		archiveAfter := pRef.GetArchiveVersionsAfter()
+		if archiveAfter == 0 {
+			continue
+		}
+
+		versions, err := model.FindVersionsToArchive(pRef.Id, time.Now().Add(-archiveAfter), versionArchivalLimit)
+		if err != nil {
+			j.AddError(errors.Wrapf(err, "finding versions to archive for project '%s'", pRef.Id))
+			continue
+		}
+		res := model.VersionArchiveResult{}
+		archived := 0
+		for i := range versions {
+			v := &versions[i]
+			versionRes, err := model.ArchiveVersion(ctx, j.env, v, time.Now())
+			res.Builds += versionRes.Builds
+			res.Tasks += versionRes.Tasks
+			res.OldTasks += versionRes.OldTasks
+			if err != nil {
+				j.AddError(errors.Wrapf(err, "archiving version '%s'", v.Id))
+				continue
+			}
+			archived++
+		}
+
+		grip.Info(message.Fields{
+			"job_id":                j.ID(),
+			"job_type":              j.Type().Name,
+			"message":               "archived versions",
+			"project":               pRef.Id,
+			"archive_after_days":    pRef.ArchiveVersionsAfterDays,
+			"versions_considered":   len(versions),
+			"versions_archived":     archived,
+			"reached_version_limit": len(versions) == versionArchivalLimit,
+			"builds":                res.Builds,
+			"tasks":                 res.Tasks,
+			"old_tasks":             res.OldTasks,
+		})
+	}
+
+	grip.Info(message.Fields{
+		"job_id":   j.ID(),
+		"job_type": j.Type().Name,
+		"message":  "timing-info",
+		"total":    time.Since(startAt).Seconds(),
+	})
+}
@@ -0,0 +1,81 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const distroDrainJobName = "distro-drain"
+
+func init() {
+	registry.AddJobType(distroDrainJobName, func() amboy.Job {
+		return makeDistroDrainJob()
+	})
+}
+
+type distroDrainJob struct {
+	DistroID string `bson:"distro_id" json:"distro_id" yaml:"distro_id"`
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+func makeDistroDrainJob() *distroDrainJob {
+	j := &distroDrainJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    distroDrainJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewDistroDrainJob returns a job that moves tasks off of a draining distro.
+func NewDistroDrainJob(distroID, ts string) amboy.Job {
+	j := makeDistroDrainJob()
+	j.DistroID = distroID
+	j.SetID(fmt.Sprintf("%s.%s.%s", distroDrainJobName, distroID, ts))
+	j.SetScopes([]string{fmt.Sprintf("%s.%s", distroDrainJobName, distroID)})
+	return j
+}
+
+func (j *distroDrainJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	drain, err := model.FindDistroDrain(j.DistroID)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if drain == nil {
+		j.AddError(errors.Errorf("distro '%s' is not draining", j.DistroID))
+		return
+	}
+
+	progress, err := drain.Process()
+	j.AddError(err)
+	if progress == nil {
+		return
+	}
+
+	grip.Info(message.Fields{
+		"message":          "processed distro drain",
+		"job":              j.ID(),
+		"distro":           j.DistroID,
+		"target_distro":    drain.TargetDistroID,
+		"mode":             drain.Mode,
+		"num_migrated":     drain.NumMigrated,
+		"num_rescheduled":  drain.NumRescheduled,
+		"num_running":      progress.NumRunning,
+		"num_undispatched": progress.NumUndispatched,
+		"finished":         progress.IsFinished(),
+	})
+}
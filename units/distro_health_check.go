@@ -0,0 +1,85 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const distroHealthCheckJobName = "distro-health-check"
+
+func init() {
+	registry.AddJobType(distroHealthCheckJobName, func() amboy.Job { return makeDistroHealthCheckJob() })
+}
+
+type distroHealthCheckJob struct {
+	DistroID string `bson:"distro_id" json:"distro_id" yaml:"distro_id"`
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+func makeDistroHealthCheckJob() *distroHealthCheckJob {
+	j := &distroHealthCheckJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    distroHealthCheckJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewDistroHealthCheckJob checks how many of the tasks that recently finished
+// on the distro failed because of their hosts, and logs an event if the
+// distro is unhealthy. Jobs for the same distro are deduplicated within each
+// minute so that a burst of host failures is checked once.
+func NewDistroHealthCheckJob(distroID string, ts time.Time) amboy.Job {
+	j := makeDistroHealthCheckJob()
+	j.DistroID = distroID
+	j.SetID(fmt.Sprintf("%s.%s.%s", distroHealthCheckJobName, distroID, ts.Truncate(time.Minute).Format(TSFormat)))
+	return j
+}
+
+func (j *distroHealthCheckJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	health, err := model.GetDistroHealth(j.DistroID, model.DefaultDistroHealthWindow)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "getting health of distro '%s'", j.DistroID))
+		return
+	}
+	if health.Healthy {
+		return
+	}
+
+	// Only log one event per window so that a distro that stays unhealthy
+	// doesn't flood its subscribers.
+	alreadyLogged, err := event.HasRecentDistroEvent(j.DistroID, event.EventDistroUnhealthy, health.Since)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if alreadyLogged {
+		return
+	}
+	event.LogDistroUnhealthy(j.DistroID, health)
+	grip.Warning(message.Fields{
+		"message":               "distro is unhealthy",
+		"distro":                j.DistroID,
+		"num_tasks":             health.NumTasks,
+		"num_system_failures":   health.NumSystemFailures,
+		"num_setup_failures":    health.NumSetupFailures,
+		"failure_rate":          health.FailureRate,
+		"num_quarantined_hosts": health.NumQuarantinedHosts,
+		"job":                   j.ID(),
+	})
+}
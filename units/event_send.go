@@ -7,6 +7,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/job"
@@ -102,6 +103,17 @@ func (j *eventSendJob) Run(_ context.Context) {
 		return
 	}
 
+	if priority, ok := githubNotificationPriority(n); ok {
+		if err = thirdparty.ReserveGithubStatus(priority); err != nil {
+			if deferred, ok := thirdparty.IsGithubBudgetDeferred(err); ok {
+				deferGithubJob(j, deferred)
+				return
+			}
+			j.AddError(err)
+			return
+		}
+	}
+
 	err = j.send(n)
 	grip.Error(message.WrapError(err, message.Fields{
 		"job_id":            j.ID(),
@@ -168,6 +180,20 @@ func (j *eventSendJob) checkDegradedMode(n *notification.Notification) error {
 	}
 }
 
+// githubNotificationPriority returns how important posting the notification to
+// GitHub is, if it's posted to GitHub. Build checks are posted for every
+// variant, so they're cosmetic compared to the pull request status.
+func githubNotificationPriority(n *notification.Notification) (thirdparty.GithubCallPriority, bool) {
+	switch n.Subscriber.Type {
+	case event.GithubPullRequestSubscriberType:
+		return thirdparty.GithubPriorityNormal, true
+	case event.GithubCheckSubscriberType:
+		return thirdparty.GithubPriorityCosmetic, true
+	default:
+		return 0, false
+	}
+}
+
 func checkFlag(flag bool) error {
 	if flag {
 		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
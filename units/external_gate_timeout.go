@@ -0,0 +1,60 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+)
+
+const externalGateTimeoutJobName = "external-gate-timeout"
+
+func init() {
+	registry.AddJobType(externalGateTimeoutJobName, func() amboy.Job { return makeExternalGateTimeoutJob() })
+}
+
+type externalGateTimeoutJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeExternalGateTimeoutJob() *externalGateTimeoutJob {
+	j := &externalGateTimeoutJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    externalGateTimeoutJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewExternalGateTimeoutJob deactivates the tasks held by external gates that
+// timed out before they were opened and don't let their tasks run on timeout.
+func NewExternalGateTimeoutJob(id string) amboy.Job {
+	j := makeExternalGateTimeoutJob()
+	j.SetID(fmt.Sprintf("%s.%s", externalGateTimeoutJobName, id))
+	return j
+}
+
+func (j *externalGateTimeoutJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	now := time.Now()
+	versions, err := model.FindVersionsWithTimedOutExternalGates(now)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	for i := range versions {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+		j.AddError(versions[i].DeactivateTimedOutExternalGateTasks(now))
+	}
+}
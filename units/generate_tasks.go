@@ -166,8 +166,16 @@ func (j *generateTasksJob) generate(ctx context.Context, t *task.Task) error {
 	if pref == nil {
 		return j.handleError(pp, v, errors.Errorf("project '%s' not found", t.Project))
 	}
+	logGeneratedOutputSize(t, g, pp)
+	settings, err := evergreen.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "getting admin settings")
+	}
+	if err = model.CheckVersionLimits(pp, pref.Id, settings.GenerateTasksLimits); err != nil {
+		return j.handleError(pp, v, errors.Wrap(err, "generated config exceeds version limits"))
+	}
 	start = time.Now()
-	if err = validator.CheckProjectConfigurationIsValid(p, pref); err != nil {
+	if err = validator.CheckProjectConfigurationIsValid(ctx, p, pref); err != nil {
 		return j.handleError(pp, v, errors.WithStack(err))
 	}
 	grip.Debug(message.Fields{
@@ -180,6 +188,10 @@ func (j *generateTasksJob) generate(ctx context.Context, t *task.Task) error {
 		"version":       t.Version,
 	})
 
+	if err = g.CheckScheduledTaskQuota(p, pref); err != nil {
+		return j.handleError(pp, v, err)
+	}
+
 	start = time.Now()
 	if err := g.CheckForCycles(v, p, pref); err != nil {
 		return errors.Wrap(err, "checking new dependency graph for cycles")
@@ -224,6 +236,27 @@ func (j *generateTasksJob) generate(ctx context.Context, t *task.Task) error {
 	return nil
 }
 
+// logGeneratedOutputSize logs the size of the generator's output along with
+// the totals generated in its version so far.
+func logGeneratedOutputSize(t *task.Task, g *model.GeneratedProject, pp *model.ParserProject) {
+	fields := message.Fields{
+		"message":           "generate.tasks output size",
+		"operation":         "generate.tasks",
+		"project":           t.Project,
+		"task":              t.Id,
+		"version":           t.Version,
+		"config_size_bytes": t.GeneratedJSONSize(),
+		"num_tasks":         len(g.Tasks),
+		"num_variants":      len(g.BuildVariants),
+	}
+	if pp != nil {
+		fields["version_config_size_bytes"] = pp.GeneratedConfigSize
+		fields["version_num_tasks"] = pp.GeneratedTaskCount
+		fields["version_num_variants"] = pp.GeneratedVariantCount
+	}
+	grip.Info(fields)
+}
+
 // handleError return mongo.ErrNoDocuments if another job has raced, the passed in error otherwise.
 func (j *generateTasksJob) handleError(pp *model.ParserProject, v *model.Version, handledError error) error {
 	// Get task again, to exit nil if another generator finished, which caused us to error.
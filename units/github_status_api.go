@@ -9,6 +9,8 @@ import (
 	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
 	"github.com/evergreen-ci/evergreen/model/commitqueue"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/job"
 	"github.com/mongodb/amboy/registry"
@@ -30,6 +32,10 @@ const (
 	githubUpdateTypeProcessingError       = "processing-error"
 
 	evergreenContext = "evergreen"
+
+	// maxGithubDeferrals is the most times that a job is retried because the
+	// GitHub rate limit deferred its call.
+	maxGithubDeferrals = 5
 )
 
 const (
@@ -43,6 +49,7 @@ const (
 	InvalidConfig          = "config file was invalid"
 	EmptyConfig            = "config file was empty"
 	ProjectFailsValidation = "Project fails validation"
+	PatchPolicyViolated    = "patch violates the project's patch policy"
 	OtherErrors            = "Evergreen error"
 )
 
@@ -252,5 +259,42 @@ func (j *githubStatusUpdateJob) Run(_ context.Context) {
 	}
 	j.AddError(c.SetPriority(level.Notice))
 
+	if err = thirdparty.ReserveGithubStatus(j.githubPriority()); err != nil {
+		if deferred, ok := thirdparty.IsGithubBudgetDeferred(err); ok {
+			deferGithubJob(j, deferred)
+			return
+		}
+		j.AddError(err)
+		return
+	}
 	j.sender.Send(c)
 }
+
+// githubPriority returns how important the status update is. Commit queue
+// updates take priority over patch updates.
+func (j *githubStatusUpdateJob) githubPriority() thirdparty.GithubCallPriority {
+	switch j.UpdateType {
+	case githubUpdateTypePushToCommitQueue, githubUpdateTypeDeleteFromCommitQueue:
+		return thirdparty.GithubPriorityCritical
+	default:
+		return thirdparty.GithubPriorityNormal
+	}
+}
+
+// deferGithubJob retries the job once the GitHub rate limit that deferred its
+// call resets.
+func deferGithubJob(j amboy.Job, deferred *thirdparty.GithubBudgetDeferredError) {
+	grip.Info(message.Fields{
+		"message":      "deferring job until GitHub rate limit resets",
+		"job":          j.ID(),
+		"installation": deferred.Installation,
+		"priority":     deferred.Priority.String(),
+		"retry_at":     deferred.RetryAt,
+	})
+	j.UpdateRetryInfo(amboy.JobRetryOptions{
+		Retryable:   utility.TruePtr(),
+		NeedsRetry:  utility.TruePtr(),
+		MaxAttempts: utility.ToIntPtr(maxGithubDeferrals),
+		WaitUntil:   utility.ToTimeDurationPtr(time.Until(deferred.RetryAt)),
+	})
+}
@@ -0,0 +1,155 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	githubVariantChecksJobName = "github-variant-checks"
+
+	// maxGithubVariantChecksPerJob is the most variant checks that one job
+	// posts, so that projects with many variants don't exhaust the GitHub
+	// API rate limit. Any remaining checks are posted by the next job.
+	maxGithubVariantChecksPerJob = 30
+)
+
+func init() {
+	registry.AddJobType(githubVariantChecksJobName, func() amboy.Job { return makeGithubVariantChecksJob() })
+}
+
+type githubVariantChecksJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	env evergreen.Environment
+}
+
+func makeGithubVariantChecksJob() *githubVariantChecksJob {
+	j := &githubVariantChecksJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    githubVariantChecksJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewGithubVariantChecksJob posts the GitHub check for each mainline variant
+// whose status has changed since its check was last posted, for projects that
+// post one check per variant. Status changes are batched between jobs, so a
+// variant's check is posted at most once per job.
+func NewGithubVariantChecksJob(id string) amboy.Job {
+	j := makeGithubVariantChecksJob()
+	j.SetID(fmt.Sprintf("%s.%s", githubVariantChecksJobName, id))
+	return j
+}
+
+func (j *githubVariantChecksJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "getting service flags"))
+		return
+	}
+	if flags.GithubStatusAPIDisabled {
+		return
+	}
+
+	checks, err := model.FindUnpostedGithubVariantChecks(maxGithubVariantChecksPerJob)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "finding unposted variant checks"))
+		return
+	}
+	if len(checks) == 0 {
+		return
+	}
+
+	uiConfig := evergreen.UIConfig{}
+	if err = uiConfig.Get(j.env); err != nil {
+		j.AddError(errors.Wrap(err, "getting UI config"))
+		return
+	}
+	if uiConfig.Url == "" {
+		j.AddError(errors.New("UI URL is empty"))
+		return
+	}
+	token, err := j.env.Settings().GetGithubOauthToken()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "getting GitHub OAuth token"))
+		return
+	}
+
+	numPosted := 0
+	for _, check := range checks {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+		status := message.GithubStatus{
+			Owner:       check.Owner,
+			Repo:        check.Repo,
+			Ref:         check.Build.Revision,
+			Context:     check.Name,
+			State:       check.State,
+			URL:         variantLink(uiConfig, check.Build.Version, check.Build.Id, check.Build.BuildVariant),
+			Description: check.Description,
+		}
+		c := message.NewGithubStatusMessageWithRepo(level.Notice, status)
+		if !c.Loggable() {
+			j.AddError(errors.Errorf("variant check for build '%s' is invalid", check.Build.Id))
+			continue
+		}
+		// Variant checks are cosmetic, so they're the first to wait for the
+		// rate limit to reset. Unposted checks are posted by a later job.
+		if err = thirdparty.ReserveGithubStatus(thirdparty.GithubPriorityCosmetic); err != nil {
+			if _, ok := thirdparty.IsGithubBudgetDeferred(err); !ok {
+				j.AddError(err)
+			}
+			return
+		}
+		// Only record the check as posted once GitHub accepts it, so that
+		// failed checks are retried by a later job.
+		if err = thirdparty.PostGithubStatus(ctx, token, status); err != nil {
+			j.AddError(errors.Wrapf(err, "posting variant check for build '%s'", check.Build.Id))
+			continue
+		}
+		numPosted++
+
+		if err = check.Build.SetGithubCheckPostedStatus(check.Build.GithubCheckStatus); err != nil {
+			j.AddError(errors.Wrapf(err, "recording posted variant check for build '%s'", check.Build.Id))
+		}
+	}
+
+	grip.Info(message.Fields{
+		"message":    "posted GitHub variant checks",
+		"num_checks": numPosted,
+		"job":        j.ID(),
+	})
+}
+
+// variantLink returns the link to the variant's page in the UI, falling back
+// to the build page if there is no new UI.
+func variantLink(uiConfig evergreen.UIConfig, versionID, buildID, variant string) string {
+	if uiConfig.UIv2Url == "" {
+		return fmt.Sprintf("%s/build/%s", uiConfig.Url, url.PathEscape(buildID))
+	}
+	return fmt.Sprintf("%s/version/%s/tasks?variant=%s", uiConfig.UIv2Url, url.PathEscape(versionID), url.QueryEscape(variant))
+}
@@ -250,17 +250,17 @@ func (j *patchIntentProcessor) finishPatch(ctx context.Context, patchDoc *patch.
 		}
 		return errors.Wrap(err, "can't get patched config")
 	}
-	if errs := validator.CheckProjectErrors(project, false); len(errs) != 0 {
+	if errs := validator.CheckProjectErrors(ctx, project, pref, false); len(errs) != 0 {
 		if errs = errs.AtLevel(validator.Error); len(errs) != 0 {
 			validationCatcher.Errorf("invalid patched config syntax: %s", validator.ValidationErrorsToString(errs))
 		}
 	}
-	if errs := validator.CheckProjectSettings(project, pref, false); len(errs) != 0 {
+	if errs := validator.CheckProjectSettings(ctx, project, pref, false); len(errs) != 0 {
 		if errs = errs.AtLevel(validator.Error); len(errs) != 0 {
 			validationCatcher.Errorf("invalid patched config for current project settings: %s", validator.ValidationErrorsToString(errs))
 		}
 	}
-	if errs := validator.CheckPatchedProjectConfigErrors(patchConfig.PatchedProjectConfig); len(errs) != 0 {
+	if errs := validator.CheckPatchedProjectConfigErrors(ctx, patchConfig.PatchedProjectConfig); len(errs) != 0 {
 		if errs = errs.AtLevel(validator.Error); len(errs) != 0 {
 			validationCatcher.Errorf("invalid patched project config syntax: %s", validator.ValidationErrorsToString(errs))
 		}
@@ -280,6 +280,19 @@ func (j *patchIntentProcessor) finishPatch(ctx context.Context, patchDoc *patch.
 		})
 		return nil
 	}
+	// Likewise, don't create patches for github PRs that don't change any
+	// watched paths if the project doesn't create versions for such commits.
+	if patchDoc.IsGithubPRPatch() && len(pref.WatchedPaths) > 0 && pref.GetWatchedPathsPolicy() == model.WatchedPathsPolicyCreate &&
+		len(pref.MatchWatchedPaths(patchDoc.FilesChanged())) == 0 {
+		grip.Debug(message.Fields{
+			"message":       "not creating patch because no files changed are watched",
+			"files_changed": patchDoc.FilesChanged(),
+			"watched_paths": pref.WatchedPaths,
+			"patch_id":      patchDoc.Id,
+			"intent_id":     j.intent.ID(),
+		})
+		return nil
+	}
 
 	patchDoc.PatchedParserProject = patchConfig.PatchedParserProject
 	patchDoc.PatchedProjectConfig = patchConfig.PatchedProjectConfig
@@ -562,18 +575,31 @@ func ProcessTriggerAliases(ctx context.Context, p *patch.Patch, projectRef *mode
 		return nil
 	}
 
-	type aliasGroup struct {
-		project        string
-		status         string
-		parentAsModule string
-	}
-	aliasGroups := make(map[aliasGroup][]patch.PatchTriggerDefinition)
+	definitions := make([]patch.PatchTriggerDefinition, 0, len(aliasNames))
 	for _, aliasName := range aliasNames {
 		alias, found := projectRef.GetPatchTriggerAlias(aliasName)
 		if !found {
 			return errors.Errorf("patch trigger alias '%s' is not defined", aliasName)
 		}
+		definitions = append(definitions, alias)
+	}
+
+	_, err := ProcessTriggerDefinitions(ctx, p, env, definitions)
+	return err
+}
 
+// ProcessTriggerDefinitions creates child patches of the patch for the patch
+// trigger definitions and returns the IDs of the new child patches. Child
+// patches that don't wait on the parent patch's outcome are created
+// immediately; the rest are created in the background.
+func ProcessTriggerDefinitions(ctx context.Context, p *patch.Patch, env evergreen.Environment, definitions []patch.PatchTriggerDefinition) ([]string, error) {
+	type aliasGroup struct {
+		project        string
+		status         string
+		parentAsModule string
+	}
+	aliasGroups := make(map[aliasGroup][]patch.PatchTriggerDefinition)
+	for _, alias := range definitions {
 		// group patches on project, status, parentAsModule
 		group := aliasGroup{
 			project:        alias.ChildProject,
@@ -584,6 +610,7 @@ func ProcessTriggerAliases(ctx context.Context, p *patch.Patch, projectRef *mode
 	}
 
 	triggerIntents := make([]patch.Intent, 0, len(aliasGroups))
+	childPatchIDs := make([]string, 0, len(aliasGroups))
 	for group, definitions := range aliasGroups {
 		triggerIntent := patch.NewTriggerIntent(patch.TriggerIntentOptions{
 			ParentID:       p.Id.Hex(),
@@ -596,20 +623,21 @@ func ProcessTriggerAliases(ctx context.Context, p *patch.Patch, projectRef *mode
 		})
 
 		if err := triggerIntent.Insert(); err != nil {
-			return errors.Wrap(err, "problem inserting trigger intent")
+			return nil, errors.Wrap(err, "problem inserting trigger intent")
 		}
 
 		triggerIntents = append(triggerIntents, triggerIntent)
+		childPatchIDs = append(childPatchIDs, triggerIntent.ID())
 		p.Triggers.ChildPatches = append(p.Triggers.ChildPatches, triggerIntent.ID())
 	}
 	if err := p.SetChildPatches(); err != nil {
-		return errors.Wrap(err, "setting child patch ids")
+		return nil, errors.Wrap(err, "setting child patch ids")
 	}
 
 	for _, intent := range triggerIntents {
 		triggerIntent, ok := intent.(*patch.TriggerIntent)
 		if !ok {
-			return errors.Errorf("intent '%s' didn't not have expected type '%T'", intent.ID(), intent)
+			return nil, errors.Errorf("intent '%s' didn't not have expected type '%T'", intent.ID(), intent)
 		}
 
 		job := NewPatchIntentProcessor(mgobson.ObjectIdHex(intent.ID()), intent)
@@ -618,16 +646,16 @@ func ProcessTriggerAliases(ctx context.Context, p *patch.Patch, projectRef *mode
 			// we need the child patch intents to exist when the parent patch is finalized.
 			job.Run(ctx)
 			if err := job.Error(); err != nil {
-				return errors.Wrap(err, "problem processing child patch")
+				return nil, errors.Wrap(err, "problem processing child patch")
 			}
 		} else {
 			if err := env.RemoteQueue().Put(ctx, job); err != nil {
-				return errors.Wrap(err, "problem enqueueing child patch processing")
+				return nil, errors.Wrap(err, "problem enqueueing child patch processing")
 			}
 		}
 	}
 
-	return nil
+	return childPatchIDs, nil
 }
 
 func (j *patchIntentProcessor) buildCliPatchDoc(ctx context.Context, patchDoc *patch.Patch, githubOauthToken string) error {
@@ -812,6 +840,12 @@ func (j *patchIntentProcessor) buildGithubPatchDoc(ctx context.Context, patchDoc
 	if err != nil {
 		return isMember, err
 	}
+	if err = projectRef.PatchPolicy.Check(projectRef.Id, patchContent, patchDoc.Githash); err != nil {
+		if _, ok := err.(*model.PatchPolicyError); ok {
+			j.gitHubError = PatchPolicyViolated
+		}
+		return isMember, errors.Wrap(err, "checking patch policy")
+	}
 
 	patchFileID := fmt.Sprintf("%s_%s", patchDoc.Id.Hex(), patchDoc.Githash)
 	patchDoc.Patches = append(patchDoc.Patches, patch.ModulePatch{
@@ -84,18 +84,22 @@ func (j *periodicBuildJob) Run(ctx context.Context) {
 		return
 	}
 	defer func() {
-		baseTime := definition.NextRunTime
-		if utility.IsZeroTime(baseTime) {
-			baseTime = time.Now()
+		var nextRunTime time.Time
+		nextRunTime, err = definition.GetNextRunTime(time.Now())
+		if err == nil {
+			err = j.project.UpdateNextPeriodicBuild(definition.ID, nextRunTime)
 		}
-		err = j.project.UpdateNextPeriodicBuild(definition.ID, baseTime.Add(time.Duration(definition.IntervalHours)*time.Hour))
 		grip.Error(message.WrapError(err, message.Fields{
 			"message":    "unable to set next periodic build job time",
 			"project":    j.ProjectID,
 			"definition": j.DefinitionID,
 		}))
 	}()
-	versionID, versionError := j.addVersion(ctx, *definition)
+	var versionID string
+	versionError := model.CheckScheduledTaskQuota(j.project, 0)
+	if versionError == nil {
+		versionID, versionError = j.addVersion(ctx, *definition)
+	}
 
 	if versionError != nil {
 		// if the version fails to be added, create a stub version and
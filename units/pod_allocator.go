@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/pod"
 	"github.com/evergreen-ci/evergreen/model/pod/dispatcher"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -175,7 +176,7 @@ func (j *podAllocatorJob) getIntentPodOptions(containerOpts task.ContainerOption
 			return nil, errors.Wrap(err, "importing Windows version")
 		}
 	}
-	return &pod.TaskIntentPodOptions{
+	opts := &pod.TaskIntentPodOptions{
 		CPU:            containerOpts.CPU,
 		MemoryMB:       containerOpts.MemoryMB,
 		OS:             os,
@@ -183,5 +184,35 @@ func (j *podAllocatorJob) getIntentPodOptions(containerOpts task.ContainerOption
 		WindowsVersion: winVer,
 		Image:          containerOpts.Image,
 		WorkingDir:     containerOpts.WorkingDir,
-	}, nil
+	}
+	if containerOpts.RepoCredsName != "" {
+		creds, err := j.getRepoCreds(containerOpts.RepoCredsName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting repository credentials '%s'", containerOpts.RepoCredsName)
+		}
+		if creds.ExternalSecret != "" {
+			opts.RepoCredsExternalSecret = creds.ExternalSecret
+		} else {
+			opts.RepoUsername = creds.Username
+			opts.RepoPassword = creds.Password
+		}
+	}
+	return opts, nil
+}
+
+// getRepoCreds returns the task's project's container credential with the
+// given name.
+func (j *podAllocatorJob) getRepoCreds(name string) (*model.ContainerCredential, error) {
+	pRef, err := model.FindMergedProjectRef(j.task.Project, j.task.Version, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project ref '%s'", j.task.Project)
+	}
+	if pRef == nil {
+		return nil, errors.Errorf("project ref '%s' not found", j.task.Project)
+	}
+	creds, ok := pRef.ContainerCredentials[name]
+	if !ok {
+		return nil, errors.New("container credential not found in project")
+	}
+	return &creds, nil
 }
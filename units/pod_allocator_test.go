@@ -25,7 +25,7 @@ func TestPodAllocatorJob(t *testing.T) {
 	defer cancel()
 
 	defer func() {
-		assert.NoError(t, db.ClearCollections(task.Collection, pod.Collection, dispatcher.Collection, event.AllLogCollection))
+		assert.NoError(t, db.ClearCollections(task.Collection, pod.Collection, dispatcher.Collection, event.AllLogCollection, model.ProjectRefCollection))
 	}()
 
 	var originalPodInit evergreen.PodInitConfig
@@ -155,6 +155,29 @@ func TestPodAllocatorJob(t *testing.T) {
 			require.NotZero(t, dbTask)
 			assert.False(t, dbTask.ContainerAllocated)
 		},
+		"RunUsesExternalSecretForRepoCreds": func(ctx context.Context, t *testing.T, j *podAllocatorJob, tsk task.Task) {
+			pRef := model.ProjectRef{
+				Id: "project",
+				ContainerCredentials: map[string]model.ContainerCredential{
+					"creds": {ExternalSecret: "external-secret"},
+				},
+			}
+			require.NoError(t, pRef.Insert())
+			tsk.Project = pRef.Id
+			tsk.ContainerOpts.RepoCredsName = "creds"
+			j.task = &tsk
+			require.NoError(t, tsk.Insert())
+
+			j.Run(ctx)
+			require.NoError(t, j.Error())
+
+			dbPod, err := pod.FindOne(db.Query(bson.M{}))
+			require.NoError(t, err)
+			require.NotZero(t, dbPod)
+			assert.Equal(t, "external-secret", dbPod.TaskContainerCreationOpts.RepoCredsExternalSecret)
+			assert.Zero(t, dbPod.TaskContainerCreationOpts.RepoUsername)
+			assert.Zero(t, dbPod.TaskContainerCreationOpts.RepoPassword)
+		},
 		"RunNoopsWhenMaxParallelPodRequestLimitIsReached": func(ctx context.Context, t *testing.T, j *podAllocatorJob, tsk task.Task) {
 			originalPodInit := env.EvergreenSettings.PodInit
 			defer func() {
@@ -182,7 +205,7 @@ func TestPodAllocatorJob(t *testing.T) {
 			tctx, tcancel := context.WithTimeout(ctx, 10*time.Second)
 			defer tcancel()
 
-			require.NoError(t, db.ClearCollections(task.Collection, pod.Collection, dispatcher.Collection, event.AllLogCollection))
+			require.NoError(t, db.ClearCollections(task.Collection, pod.Collection, dispatcher.Collection, event.AllLogCollection, model.ProjectRefCollection))
 
 			tsk := getTaskThatNeedsContainerAllocation()
 			j := NewPodAllocatorJob(tsk.Id, utility.RoundPartOfMinute(0).Format(TSFormat))
@@ -103,7 +103,20 @@ func (j *podCreationJob) Run(ctx context.Context) {
 
 	switch j.pod.Status {
 	case pod.StatusInitializing:
-		opts, err := cloud.ExportECSPodCreationOptions(&settings, j.pod)
+		// The pod keeps the original reference to the external secret rather
+		// than the ARN that it resolves to, in case the secret is replaced.
+		toCreate := *j.pod
+		externalSecretRef := j.pod.TaskContainerCreationOpts.RepoCredsExternalSecret
+		if externalSecretRef != "" {
+			arn, err := cloud.ResolveExternalSecret(ctx, j.smClient, externalSecretRef)
+			if err != nil {
+				j.AddRetryableError(errors.Wrap(err, "resolving external secret for repository credentials"))
+				return
+			}
+			toCreate.TaskContainerCreationOpts.RepoCredsExternalSecret = arn
+		}
+
+		opts, err := cloud.ExportECSPodCreationOptions(&settings, &toCreate)
 		if err != nil {
 			j.AddError(errors.Wrap(err, "exporting pod creation options"))
 			return
@@ -111,6 +124,9 @@ func (j *podCreationJob) Run(ctx context.Context) {
 
 		p, err := j.ecsPodCreator.CreatePod(ctx, *opts)
 		if err != nil {
+			if externalSecretRef != "" {
+				cloud.InvalidateExternalSecret(externalSecretRef)
+			}
 			j.AddRetryableError(errors.Wrap(err, "starting pod"))
 			return
 		}
@@ -156,14 +172,14 @@ func (j *podCreationJob) populateIfUnset(ctx context.Context) error {
 
 	settings := j.env.Settings()
 
-	if j.vault == nil {
-		if j.smClient == nil {
-			client, err := cloud.MakeSecretsManagerClient(settings)
-			if err != nil {
-				return errors.Wrap(err, "initializing Secrets Manager client")
-			}
-			j.smClient = client
+	if j.smClient == nil {
+		client, err := cloud.MakeSecretsManagerClient(settings)
+		if err != nil {
+			return errors.Wrap(err, "initializing Secrets Manager client")
 		}
+		j.smClient = client
+	}
+	if j.vault == nil {
 		j.vault = cloud.MakeSecretsManagerVault(j.smClient)
 	}
 
@@ -74,6 +74,35 @@ func TestPodCreationJob(t *testing.T) {
 			require.NotZero(t, dbPod)
 			assert.Equal(t, pod.StatusStarting, dbPod.Status)
 		},
+		"SucceedsWithExternalRepositoryCredentials": func(ctx context.Context, t *testing.T, j *podCreationJob) {
+			const ref = "team/registry-creds"
+			cocoaMock.GlobalSecretCache[ref] = cocoaMock.StoredSecret{
+				Value: `{"username":"user","password":"pass"}`,
+			}
+			j.pod.TaskContainerCreationOpts.RepoCredsExternalSecret = ref
+			require.NoError(t, j.pod.Insert())
+
+			j.Run(ctx)
+			require.NoError(t, j.Error())
+
+			assert.Equal(t, pod.StatusStarting, j.pod.Status)
+			assert.Equal(t, ref, j.pod.TaskContainerCreationOpts.RepoCredsExternalSecret)
+			assert.Len(t, cocoaMock.GlobalSecretCache, 3, "external secret should not be copied")
+		},
+		"FailsWithNonexistentExternalRepositoryCredentials": func(ctx context.Context, t *testing.T, j *podCreationJob) {
+			j.pod.TaskContainerCreationOpts.RepoCredsExternalSecret = "team/nonexistent"
+			require.NoError(t, j.pod.Insert())
+
+			j.Run(ctx)
+			require.Error(t, j.Error())
+			assert.Zero(t, j.ecsPod)
+			assert.Len(t, cocoaMock.GlobalECSService.Clusters[clusterName], 0)
+
+			dbPod, err := pod.FindOneByID(j.PodID)
+			require.NoError(t, err)
+			require.NotZero(t, dbPod)
+			assert.Equal(t, pod.StatusInitializing, dbPod.Status)
+		},
 		"FailsWithStartingStatus": func(ctx context.Context, t *testing.T, j *podCreationJob) {
 			require.NoError(t, j.pod.Insert())
 			require.NoError(t, j.pod.UpdateStatus(pod.StatusStarting))
@@ -24,6 +24,9 @@ func SchedulePatch(ctx context.Context, patchId string, version *model.Version,
 	if p.IsCommitQueuePatch() {
 		return http.StatusBadRequest, errors.New("can't schedule commit queue patch")
 	}
+	if p.IsArchived() {
+		return http.StatusBadRequest, errors.New("can't schedule patch that was archived as stale")
+	}
 	projectRef, err := model.FindMergedProjectRef(p.Project, p.Version, true)
 	if err != nil {
 		return http.StatusInternalServerError, errors.Wrap(err, "unable to find project ref")
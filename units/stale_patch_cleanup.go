@@ -0,0 +1,176 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const stalePatchCleanupJobName = "stale-patch-cleanup"
+
+func init() {
+	registry.AddJobType(stalePatchCleanupJobName, func() amboy.Job { return makeStalePatchCleanupJob() })
+}
+
+type stalePatchCleanupJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	env evergreen.Environment
+}
+
+func makeStalePatchCleanupJob() *stalePatchCleanupJob {
+	j := &stalePatchCleanupJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    stalePatchCleanupJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewStalePatchCleanupJob warns the authors of patches that will soon be
+// cleaned up by their project's stale patch policy and aborts and archives
+// the patches once the warning time has passed.
+func NewStalePatchCleanupJob(id string) amboy.Job {
+	j := makeStalePatchCleanupJob()
+	j.SetID(fmt.Sprintf("%s.%s", stalePatchCleanupJobName, id))
+	return j
+}
+
+func (j *stalePatchCleanupJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	pRefs, err := model.FindProjectRefsWithStalePatchPolicy()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "finding projects with stale patch policies"))
+		return
+	}
+	if len(pRefs) == 0 {
+		return
+	}
+
+	uiConfig := evergreen.UIConfig{}
+	if err = uiConfig.Get(j.env); err != nil {
+		j.AddError(errors.Wrap(err, "getting UI config"))
+		return
+	}
+
+	for i := range pRefs {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+		j.cleanUpProject(&pRefs[i], uiConfig.Url)
+	}
+}
+
+func (j *stalePatchCleanupJob) cleanUpProject(pRef *model.ProjectRef, uiURL string) {
+	now := time.Now()
+	candidates, err := model.FindStalePatchCandidates(pRef, now)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	policy := pRef.StalePatchPolicy
+	numWarned := 0
+	numArchived := 0
+	numTasksReclaimed := 0
+	notifications := []notification.Notification{}
+	for i := range candidates {
+		p := &candidates[i]
+		switch {
+		case policy.ShouldWarn(p, now):
+			cleanupTime := policy.CleanupTime(p)
+			if earliest := now.Add(policy.GetWarning()); cleanupTime.Before(earliest) {
+				cleanupTime = earliest
+			}
+			n, err := makeStalePatchWarning(p, pRef, cleanupTime, uiURL)
+			if err != nil {
+				j.AddError(err)
+				continue
+			}
+			if err = p.SetStaleWarningSent(now); err != nil {
+				j.AddError(errors.Wrapf(err, "recording stale patch warning for patch '%s'", p.Id.Hex()))
+				continue
+			}
+			if n != nil {
+				notifications = append(notifications, *n)
+			}
+			numWarned++
+		case policy.ShouldCleanUp(p, now):
+			affected, err := model.CleanUpStalePatch(p, now)
+			if err != nil {
+				j.AddError(err)
+				continue
+			}
+			numArchived++
+			numTasksReclaimed += len(affected.TaskIDs)
+		}
+	}
+	if len(notifications) > 0 {
+		j.AddError(errors.Wrap(notification.InsertMany(notifications...), "inserting stale patch warnings"))
+	}
+
+	if numWarned == 0 && numArchived == 0 {
+		return
+	}
+	grip.Info(message.Fields{
+		"message":             "cleaned up stale patches",
+		"project":             pRef.Id,
+		"project_identifier":  pRef.Identifier,
+		"num_warned":          numWarned,
+		"num_archived":        numArchived,
+		"num_tasks_reclaimed": numTasksReclaimed,
+		"job":                 j.ID(),
+	})
+}
+
+// makeStalePatchWarning returns the email that warns the patch's author that
+// the patch will be cleaned up, or nil if the author has no email address.
+func makeStalePatchWarning(p *patch.Patch, pRef *model.ProjectRef, cleanupTime time.Time, uiURL string) (*notification.Notification, error) {
+	author, err := user.FindOneById(p.Author)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding author '%s' of patch '%s'", p.Author, p.Id.Hex())
+	}
+	if author == nil || author.Email() == "" {
+		return nil, nil
+	}
+
+	action := "archived"
+	if p.Version != "" {
+		action = "aborted and archived"
+	}
+	payload := &message.Email{
+		Subject: fmt.Sprintf("Evergreen patch '%s' will be %s as stale", p.Description, action),
+		Body: fmt.Sprintf("Your patch '%s' in project '%s' has been inactive for too long and will be %s at %s (%s). "+
+			"To keep the patch, use the keep-alive endpoint: POST /rest/v2/patches/%s/keep_alive",
+			p.Description, pRef.Identifier, action, cleanupTime.UTC().Format(time.RFC1123), p.GetURL(uiURL), p.Id.Hex()),
+		PlainTextContents: true,
+	}
+	sub := event.Subscriber{
+		Type:   event.EmailSubscriberType,
+		Target: utility.ToStringPtr(author.Email()),
+	}
+	n, err := notification.New("", utility.RandomString(), &sub, payload)
+	return n, errors.Wrapf(err, "creating stale patch warning for patch '%s'", p.Id.Hex())
+}
@@ -0,0 +1,109 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	statusRollupReconciliationJobName = "status-rollup-reconciliation"
+
+	// statusRollupReconciliationWindow is how long after a build finishes
+	// that its status rollup is still reconciled.
+	statusRollupReconciliationWindow = time.Hour
+)
+
+func init() {
+	registry.AddJobType(statusRollupReconciliationJobName, func() amboy.Job { return makeStatusRollupReconciliationJob() })
+}
+
+type statusRollupReconciliationJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeStatusRollupReconciliationJob() *statusRollupReconciliationJob {
+	j := &statusRollupReconciliationJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    statusRollupReconciliationJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewStatusRollupReconciliationJob compares the event-sourced status rollups
+// of recently active builds against a scan of their tasks, reports how
+// correct and fast the rollups are, and recounts any rollups that drifted.
+func NewStatusRollupReconciliationJob(id string) amboy.Job {
+	j := makeStatusRollupReconciliationJob()
+	j.SetID(fmt.Sprintf("%s.%s", statusRollupReconciliationJobName, id))
+	return j
+}
+
+func (j *statusRollupReconciliationJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	builds, err := build.Find(build.ByRollupActiveSince(time.Now().Add(-statusRollupReconciliationWindow)).WithFields(build.IdKey))
+	if err != nil {
+		j.AddError(errors.Wrap(err, "finding builds with status rollups"))
+		return
+	}
+
+	var numMismatchedCounts, numMismatchedStatuses int
+	var rollupLatency, scanLatency time.Duration
+	for _, b := range builds {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+		rec, err := model.ReconcileBuildRollup(b.Id)
+		if err != nil {
+			j.AddError(errors.Wrapf(err, "reconciling status rollup for build '%s'", b.Id))
+			continue
+		}
+		rollupLatency += rec.RollupLatency
+		scanLatency += rec.ScanLatency
+		if rec.CountsMatched && rec.RollupStatus == rec.ScanStatus {
+			continue
+		}
+		if !rec.CountsMatched {
+			numMismatchedCounts++
+		}
+		if rec.RollupStatus != rec.ScanStatus {
+			numMismatchedStatuses++
+		}
+		grip.Warning(message.Fields{
+			"message":        "event-sourced status rollup disagreed with task scan",
+			"build_id":       rec.BuildID,
+			"rollup_status":  rec.RollupStatus,
+			"scan_status":    rec.ScanStatus,
+			"counts_matched": rec.CountsMatched,
+			"job":            j.ID(),
+		})
+	}
+
+	if len(builds) == 0 {
+		return
+	}
+	grip.Info(message.Fields{
+		"message":                 "reconciled event-sourced status rollups",
+		"num_builds":              len(builds),
+		"num_mismatched_counts":   numMismatchedCounts,
+		"num_mismatched_statuses": numMismatchedStatuses,
+		"avg_rollup_latency_ms":   (rollupLatency / time.Duration(len(builds))).Milliseconds(),
+		"avg_scan_latency_ms":     (scanLatency / time.Duration(len(builds))).Milliseconds(),
+		"job":                     j.ID(),
+	})
+}
@@ -0,0 +1,109 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const stuckTaskWatchdogJobName = "stuck-task-watchdog"
+
+func init() {
+	registry.AddJobType(stuckTaskWatchdogJobName, func() amboy.Job { return makeStuckTaskWatchdogJob() })
+}
+
+type stuckTaskWatchdogJob struct {
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	env evergreen.Environment
+}
+
+func makeStuckTaskWatchdogJob() *stuckTaskWatchdogJob {
+	j := &stuckTaskWatchdogJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    stuckTaskWatchdogJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewStuckTaskWatchdogJob finds started tasks whose heartbeat has been stale
+// for longer than their distro's stuck task threshold and applies their
+// project's stuck task policy. Stuck tasks in projects that reset them are
+// system-failed and reset by the task execution timeout job. Stuck tasks in
+// projects that only alert on them are flagged and logged once per execution.
+func NewStuckTaskWatchdogJob(ts string) amboy.Job {
+	j := makeStuckTaskWatchdogJob()
+	j.SetID(fmt.Sprintf("%s.%s", stuckTaskWatchdogJobName, ts))
+	return j
+}
+
+func (j *stuckTaskWatchdogJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	now := time.Now()
+	stuckTasks, err := model.FindStuckTasks("", now)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "finding stuck tasks"))
+		return
+	}
+
+	var numReset, numAlerted int
+	for _, st := range stuckTasks {
+		t := st.Task
+		switch st.Policy {
+		case model.StuckTaskPolicyAlert:
+			flagged, err := t.MarkStuck(now)
+			if err != nil {
+				j.AddError(errors.Wrapf(err, "flagging task '%s' as stuck", t.Id))
+				continue
+			}
+			if !flagged {
+				continue
+			}
+			numAlerted++
+			event.LogTaskStuck(t.Id, t.Execution, t.HostId, t.LastHeartbeat)
+			grip.Alert(message.Fields{
+				"message":        "task is stuck",
+				"task_id":        t.Id,
+				"execution":      t.Execution,
+				"project":        t.Project,
+				"distro":         t.DistroId,
+				"host_id":        t.HostId,
+				"last_heartbeat": t.LastHeartbeat,
+				"stale_secs":     st.StaleFor.Seconds(),
+				"threshold_secs": st.Threshold.Seconds(),
+				"job":            j.ID(),
+			})
+		default:
+			numReset++
+			ts := utility.RoundPartOfHour(15).Format(TSFormat)
+			j.AddError(amboy.EnqueueUniqueJob(ctx, j.env.RemoteQueue(), NewTaskExecutionMonitorJob(t.Id, t.Execution, 1, ts)))
+		}
+	}
+
+	grip.InfoWhen(len(stuckTasks) > 0, message.Fields{
+		"message":     "handled stuck tasks",
+		"num_stuck":   len(stuckTasks),
+		"num_reset":   numReset,
+		"num_alerted": numAlerted,
+		"job":         j.ID(),
+	})
+}
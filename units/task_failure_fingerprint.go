@@ -0,0 +1,78 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+const (
+	taskFailureFingerprintJobName = "task-failure-fingerprint"
+
+	// taskFailureFingerprintTimeout bounds fetching a task's failing tests
+	// from Cedar.
+	taskFailureFingerprintTimeout = 30 * time.Second
+)
+
+func init() {
+	registry.AddJobType(taskFailureFingerprintJobName,
+		func() amboy.Job { return makeTaskFailureFingerprintJob() })
+}
+
+type taskFailureFingerprintJob struct {
+	TaskID    string `bson:"task_id" json:"task_id" yaml:"task_id"`
+	Execution int    `bson:"execution" json:"execution" yaml:"execution"`
+	job.Base  `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+func makeTaskFailureFingerprintJob() *taskFailureFingerprintJob {
+	j := &taskFailureFingerprintJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    taskFailureFingerprintJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewTaskFailureFingerprintJob computes the failure fingerprint of a failed
+// task whose failing tests are stored in Cedar, so that ending the task
+// doesn't wait on Cedar.
+func NewTaskFailureFingerprintJob(taskID string, execution int) amboy.Job {
+	j := makeTaskFailureFingerprintJob()
+	j.TaskID = taskID
+	j.Execution = execution
+	j.SetID(fmt.Sprintf("%s.%s.%d", taskFailureFingerprintJobName, taskID, execution))
+	j.SetPriority(-2)
+	return j
+}
+
+func (j *taskFailureFingerprintJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	t, err := task.FindOneIdAndExecution(j.TaskID, j.Execution)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	// The task was restarted before the job ran.
+	if t == nil {
+		return
+	}
+	if !model.NeedsCedarFailureFingerprint(t) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, taskFailureFingerprintTimeout)
+	defer cancel()
+	j.AddError(errors.Wrapf(model.UpdateCedarFailureFingerprint(ctx, t), "computing failure fingerprint for task '%s'", t.Id))
+}
@@ -0,0 +1,143 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const indexTaskFailureLogJobName = "index-task-failure-log"
+
+func init() {
+	registry.AddJobType(indexTaskFailureLogJobName,
+		func() amboy.Job { return makeIndexTaskFailureLogJob() })
+}
+
+type indexTaskFailureLogJob struct {
+	TaskID    string `bson:"task_id" json:"task_id" yaml:"task_id"`
+	Execution int    `bson:"execution" json:"execution" yaml:"execution"`
+	job.Base  `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	env evergreen.Environment
+}
+
+func makeIndexTaskFailureLogJob() *indexTaskFailureLogJob {
+	j := &indexTaskFailureLogJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    indexTaskFailureLogJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewIndexTaskFailureLogJob saves the failure output from the end of a failed
+// task's log so that it can be searched.
+func NewIndexTaskFailureLogJob(taskID string, execution int) amboy.Job {
+	j := makeIndexTaskFailureLogJob()
+	j.TaskID = taskID
+	j.Execution = execution
+	j.SetID(fmt.Sprintf("%s.%s.%d", indexTaskFailureLogJobName, taskID, execution))
+	j.SetPriority(-2)
+	return j
+}
+
+func (j *indexTaskFailureLogJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	t, err := task.FindOneIdAndExecution(j.TaskID, j.Execution)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	// The task was restarted before the job ran.
+	if t == nil {
+		t, err = task.FindOneOldByIdAndExecution(j.TaskID, j.Execution)
+		if err != nil {
+			j.AddError(err)
+			return
+		}
+	}
+	if t == nil {
+		j.AddError(errors.Errorf("task '%s' execution %d not found", j.TaskID, j.Execution))
+		return
+	}
+	if t.Status != evergreen.TaskFailed {
+		return
+	}
+
+	pRef, err := model.FindMergedProjectRef(t.Project, t.Version, false)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "finding project ref for task '%s'", t.Id))
+		return
+	}
+	if pRef == nil || !pRef.IsFailureLogIndexingEnabled() {
+		return
+	}
+
+	tail, err := j.getLogTail(ctx, t, pRef.DefaultLogger)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "getting log for task '%s'", t.Id))
+		return
+	}
+	j.AddError(model.NewTaskFailureLog(t, tail).Upsert())
+}
+
+// getLogTail returns the last lines of the task's log in chronological order.
+func (j *indexTaskFailureLogJob) getLogTail(ctx context.Context, t *task.Task, logger string) ([]apimodels.LogMessage, error) {
+	if logger == model.BuildloggerLogSender {
+		cedar := j.env.Settings().Cedar
+		userOpts, err := gimlet.NewBasicUserOptions(cedar.User)
+		if err != nil {
+			return nil, errors.Wrap(err, "making Cedar user")
+		}
+		ctx = gimlet.AttachUser(ctx, gimlet.NewBasicUser(userOpts.Key(cedar.APIKey)))
+		r, err := apimodels.GetBuildloggerLogs(ctx, apimodels.GetBuildloggerLogsOptions{
+			BaseURL:       cedar.BaseURL,
+			TaskID:        t.Id,
+			Execution:     utility.ToIntPtr(t.Execution),
+			PrintPriority: true,
+			Tail:          model.TaskFailureLogTailSize,
+			LogType:       apimodels.AllTaskLevelLogs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			grip.Warning(message.WrapError(r.Close(), message.Fields{
+				"message": "could not close buildlogger log reader",
+				"task_id": t.Id,
+				"job":     j.ID(),
+			}))
+		}()
+		return apimodels.ReadBuildloggerToSlice(ctx, t.Id, r), nil
+	}
+
+	msgs, err := model.FindMostRecentLogMessages(t.Id, t.Execution, model.TaskFailureLogTailSize, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The most recent messages are returned newest first.
+	for i, k := 0, len(msgs)-1; i < k; i, k = i+1, k-1 {
+		msgs[i], msgs[k] = msgs[k], msgs[i]
+	}
+	return msgs, nil
+}
@@ -0,0 +1,48 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+)
+
+const taskFailureLogsCleanupJobName = "task-failure-logs-cleanup"
+
+func init() {
+	registry.AddJobType(taskFailureLogsCleanupJobName, func() amboy.Job { return makeTaskFailureLogsCleanupJob() })
+}
+
+type taskFailureLogsCleanupJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeTaskFailureLogsCleanupJob() *taskFailureLogsCleanupJob {
+	j := &taskFailureLogsCleanupJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    taskFailureLogsCleanupJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewTaskFailureLogsCleanupJob removes the failure logs of tasks that finished
+// longer ago than the retention period.
+func NewTaskFailureLogsCleanupJob(id string) amboy.Job {
+	j := makeTaskFailureLogsCleanupJob()
+	j.SetID(fmt.Sprintf("%s.%s", taskFailureLogsCleanupJobName, id))
+	return j
+}
+
+func (j *taskFailureLogsCleanupJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	j.AddError(model.RemoveExpiredTaskFailureLogs(time.Now().Add(-model.TaskFailureLogTTL)))
+}
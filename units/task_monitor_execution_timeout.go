@@ -97,8 +97,39 @@ func (j *taskExecutionTimeoutJob) Run(ctx context.Context) {
 		return
 	}
 
+	threshold := heartbeatTimeoutThreshold
+	if t.Status == evergreen.TaskStarted {
+		var policy string
+		threshold, policy, err = model.GetStuckTaskSettings(t)
+		if err != nil {
+			j.AddError(errors.Wrap(err, "getting stuck task settings"))
+			return
+		}
+		// The stuck task watchdog alerts on tasks in projects that don't want
+		// their stuck tasks reset.
+		if policy == model.StuckTaskPolicyAlert {
+			j.successful = true
+			return
+		}
+	}
+
 	// if the task has heartbeat since this job was queued, let it run
-	if t.LastHeartbeat.Add(heartbeatTimeoutThreshold).After(time.Now()) {
+	if t.LastHeartbeat.Add(threshold).After(time.Now()) {
+		j.successful = true
+		return
+	}
+
+	// The agent can stop heartbeating while it's ending the task, and can
+	// resume ending it if the agent restarts, so let it finish.
+	if t.IsFinalizing() {
+		grip.Info(message.Fields{
+			"message":          "not cleaning up task that the agent is still ending",
+			"operation":        j.Type().Name,
+			"id":               j.ID(),
+			"task":             t.Id,
+			"execution":        t.Execution,
+			"finalizing_until": t.FinalizingUntil,
+		})
 		j.successful = true
 		return
 	}
@@ -0,0 +1,142 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const taskQuarantineExpiryJobName = "task-quarantine-expiry"
+
+func init() {
+	registry.AddJobType(taskQuarantineExpiryJobName, func() amboy.Job { return makeTaskQuarantineExpiryJob() })
+}
+
+type taskQuarantineExpiryJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	env evergreen.Environment
+}
+
+func makeTaskQuarantineExpiryJob() *taskQuarantineExpiryJob {
+	j := &taskQuarantineExpiryJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    taskQuarantineExpiryJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewTaskQuarantineExpiryJob warns the creators of task quarantines and the
+// admins of their projects that the quarantines will soon expire, after which
+// the quarantined tasks will be scheduled again.
+func NewTaskQuarantineExpiryJob(id string) amboy.Job {
+	j := makeTaskQuarantineExpiryJob()
+	j.SetID(fmt.Sprintf("%s.%s", taskQuarantineExpiryJobName, id))
+	return j
+}
+
+func (j *taskQuarantineExpiryJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	quarantines, err := model.FindTaskQuarantinesToWarn(time.Now())
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	numWarned := 0
+	notifications := []notification.Notification{}
+	for i := range quarantines {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			break
+		}
+		q := &quarantines[i]
+		pRef, err := model.FindMergedProjectRef(q.ProjectId, "", false)
+		if err != nil {
+			j.AddError(errors.Wrapf(err, "finding project '%s' for task quarantine '%s'", q.ProjectId, q.Id))
+			continue
+		}
+		if pRef == nil {
+			continue
+		}
+		warnings, err := makeTaskQuarantineExpiryWarnings(q, pRef)
+		if err != nil {
+			j.AddError(err)
+			continue
+		}
+		if err = q.SetExpiryWarningSent(); err != nil {
+			j.AddError(err)
+			continue
+		}
+		notifications = append(notifications, warnings...)
+		numWarned++
+	}
+	if len(notifications) > 0 {
+		j.AddError(errors.Wrap(notification.InsertMany(notifications...), "inserting task quarantine expiry warnings"))
+	}
+
+	if numWarned == 0 {
+		return
+	}
+	grip.Info(message.Fields{
+		"message":    "warned about expiring task quarantines",
+		"num_warned": numWarned,
+		"job":        j.ID(),
+	})
+}
+
+// makeTaskQuarantineExpiryWarnings returns the emails that warn the creator of
+// the quarantine and the project admins that the quarantine will soon expire.
+func makeTaskQuarantineExpiryWarnings(q *model.TaskQuarantine, pRef *model.ProjectRef) ([]notification.Notification, error) {
+	recipients := utility.UniqueStrings(append([]string{q.CreatedBy}, pRef.Admins...))
+	users, err := user.Find(user.ByIds(recipients...))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding users to warn about task quarantine '%s'", q.Id)
+	}
+
+	payload := &message.Email{
+		Subject: fmt.Sprintf("Evergreen quarantine of task '%s' in project '%s' will expire soon", q.TaskName, pRef.Identifier),
+		Body: fmt.Sprintf("The quarantine of task '%s' in build variant '%s' of project '%s' will expire at %s, after which the task will be scheduled again. "+
+			"The task was quarantined by '%s' because: %s. "+
+			"To keep the task quarantined, renew the quarantine: PUT /rest/v2/projects/%s/quarantined_tasks",
+			q.TaskName, q.BuildVariant, pRef.Identifier, q.Expires.UTC().Format(time.RFC1123), q.CreatedBy, q.Reason, pRef.Identifier),
+		PlainTextContents: true,
+	}
+	notifications := []notification.Notification{}
+	for _, u := range users {
+		if u.Email() == "" {
+			continue
+		}
+		sub := event.Subscriber{
+			Type:   event.EmailSubscriberType,
+			Target: utility.ToStringPtr(u.Email()),
+		}
+		n, err := notification.New("", utility.RandomString(), &sub, payload)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating expiry warning for task quarantine '%s'", q.Id)
+		}
+		notifications = append(notifications, *n)
+	}
+	return notifications, nil
+}
@@ -0,0 +1,60 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+)
+
+const tasksBlockedNotificationJobName = "tasks-blocked-notification"
+
+func init() {
+	registry.AddJobType(tasksBlockedNotificationJobName, func() amboy.Job { return makeTasksBlockedNotificationJob() })
+}
+
+type tasksBlockedNotificationJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeTasksBlockedNotificationJob() *tasksBlockedNotificationJob {
+	j := &tasksBlockedNotificationJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    tasksBlockedNotificationJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewTasksBlockedNotificationJob logs one event for each version whose tasks
+// have been blocked by failed dependencies for longer than the notification
+// delay, so the tasks' owners are notified once per version.
+func NewTasksBlockedNotificationJob(id string) amboy.Job {
+	j := makeTasksBlockedNotificationJob()
+	j.SetID(fmt.Sprintf("%s.%s", tasksBlockedNotificationJobName, id))
+	return j
+}
+
+func (j *tasksBlockedNotificationJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	versions, err := model.FindVersionsWithPendingBlockedTasks(time.Now().Add(-model.TasksBlockedNotificationDelay))
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	for _, v := range versions {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+		j.AddError(model.LogPendingBlockedTasks(v.Id))
+	}
+}
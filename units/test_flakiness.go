@@ -0,0 +1,70 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+const testFlakinessJobName = "test-flakiness"
+
+func init() {
+	registry.AddJobType(testFlakinessJobName, func() amboy.Job { return makeTestFlakinessJob() })
+}
+
+type testFlakinessJob struct {
+	TaskID    string `bson:"task_id" json:"task_id" yaml:"task_id"`
+	Execution int    `bson:"execution" json:"execution" yaml:"execution"`
+	job.Base  `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+func makeTestFlakinessJob() *testFlakinessJob {
+	j := &testFlakinessJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    testFlakinessJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewTestFlakinessJob adds the test results of a mainline task execution to
+// the flakiness rollups of its tests. It should be queued whenever results
+// are attached to the task, whether they are stored in Evergreen or Cedar.
+func NewTestFlakinessJob(taskID string, execution int) amboy.Job {
+	j := makeTestFlakinessJob()
+	j.TaskID = taskID
+	j.Execution = execution
+	j.SetID(fmt.Sprintf("%s.%s.%d.%d", testFlakinessJobName, taskID, execution, job.GetNumber()))
+	j.SetPriority(-2)
+	return j
+}
+
+func (j *testFlakinessJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	t, err := task.FindOneIdOldOrNew(j.TaskID, j.Execution)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "finding task '%s' execution %d", j.TaskID, j.Execution))
+		return
+	}
+	if t == nil {
+		j.AddError(errors.Errorf("task '%s' execution %d not found", j.TaskID, j.Execution))
+		return
+	}
+	// The results may be stored in Evergreen or in Cedar.
+	results, err := t.GetNewStyleTestResults()
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "finding test results for task '%s' execution %d", j.TaskID, j.Execution))
+		return
+	}
+	j.AddError(errors.Wrapf(testresult.UpdateFlakiness(results), "updating test flakiness for task '%s' execution %d", j.TaskID, j.Execution))
+}
@@ -0,0 +1,48 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/validator"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+)
+
+const validationResultsCleanupJobName = "validation-results-cleanup"
+
+func init() {
+	registry.AddJobType(validationResultsCleanupJobName, func() amboy.Job { return makeValidationResultsCleanupJob() })
+}
+
+type validationResultsCleanupJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeValidationResultsCleanupJob() *validationResultsCleanupJob {
+	j := &validationResultsCleanupJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    validationResultsCleanupJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewValidationResultsCleanupJob removes the stored results of incremental
+// project config validations that are older than the retention period.
+func NewValidationResultsCleanupJob(id string) amboy.Job {
+	j := makeValidationResultsCleanupJob()
+	j.SetID(fmt.Sprintf("%s.%s", validationResultsCleanupJobName, id))
+	return j
+}
+
+func (j *validationResultsCleanupJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	j.AddError(validator.RemoveExpiredValidationResults(time.Now().Add(-validator.ValidationResultsTTL)))
+}
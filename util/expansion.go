@@ -1,6 +1,7 @@
 package util
 
 import (
+	"fmt"
 	"io/ioutil"
 	"regexp"
 	"strings"
@@ -77,6 +78,7 @@ func (self *Expansions) Remove(expansion string) {
 func (self *Expansions) ExpandString(toExpand string) (string, error) {
 	// replace all expandable parts of the string
 	malformedFound := false
+	var transformErr error
 	expanded := string(expansionRegex.ReplaceAllFunc([]byte(toExpand),
 		func(matchByte []byte) []byte {
 
@@ -90,21 +92,34 @@ func (self *Expansions) ExpandString(toExpand string) (string, error) {
 				malformedFound = true
 			}
 
-			// parse into the name and default value
-			var defaultVal string
-			if idx := strings.Index(match, "|"); idx != -1 {
-				defaultVal = match[idx+1:]
-				match = match[0:idx]
+			// parse into the name and either a default value or the
+			// transforms to apply
+			name, defaultVal, transforms, err := parseExpansion(match)
+			if err != nil {
+				if transformErr == nil {
+					transformErr = err
+				}
+				return matchByte
 			}
 
 			// return the specified expansion, if it is present.
-			if self.Exists(match) {
-				return []byte(self.Get(match))
+			if len(transforms) == 0 {
+				if self.Exists(name) {
+					return []byte(self.Get(name))
+				}
+				return []byte(defaultVal)
 			}
 
-			return []byte(defaultVal)
+			val := self.Get(name)
+			for _, t := range transforms {
+				val = t.transform.apply(val, t.arg)
+			}
+			return []byte(val)
 		}))
 
+	if transformErr != nil {
+		return expanded, errors.Wrapf(transformErr, "expanding '%s'", toExpand)
+	}
 	if malformedFound || strings.Contains(expanded, "${") {
 		return expanded, errors.Errorf("'%s' contains an unclosed expansion", expanded)
 	}
@@ -112,6 +127,112 @@ func (self *Expansions) ExpandString(toExpand string) (string, error) {
 	return expanded, nil
 }
 
+// ValidateExpansionTransforms checks that every expansion in the string that
+// uses transforms only references known transforms with valid arguments.
+func ValidateExpansionTransforms(s string) error {
+	var errs []string
+	for _, match := range expansionRegex.FindAllString(s, -1) {
+		if _, _, _, err := parseExpansion(match[2 : len(match)-1]); err != nil {
+			errs = append(errs, fmt.Sprintf("'%s': %s", match, err.Error()))
+		}
+	}
+	if len(errs) != 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ExpansionNames returns the names of the expansions that the string
+// references.
+func ExpansionNames(s string) []string {
+	var names []string
+	for _, match := range expansionRegex.FindAllString(s, -1) {
+		name, _, _, err := parseExpansion(match[2 : len(match)-1])
+		if err != nil || name == "" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+type expansionTransform struct {
+	hasArg bool
+	apply  func(val, arg string) string
+}
+
+// expansionTransformSeparator separates an expansion's name from the
+// transforms to apply to its value, e.g. ${var::lower} or
+// ${var::trim_prefix:foo|upper}.
+const expansionTransformSeparator = "::"
+
+// expansionTransforms are the built-in functions that can be applied to an
+// expansion's value.
+var expansionTransforms = map[string]expansionTransform{
+	"lower": {apply: func(val, _ string) string { return strings.ToLower(val) }},
+	"upper": {apply: func(val, _ string) string { return strings.ToUpper(val) }},
+	"trim":  {apply: func(val, _ string) string { return strings.TrimSpace(val) }},
+	"trim_prefix": {hasArg: true, apply: func(val, arg string) string {
+		return strings.TrimPrefix(val, arg)
+	}},
+	"trim_suffix": {hasArg: true, apply: func(val, arg string) string {
+		return strings.TrimSuffix(val, arg)
+	}},
+	// default replaces the value if it is unset or empty.
+	"default": {hasArg: true, apply: func(val, arg string) string {
+		if val == "" {
+			return arg
+		}
+		return val
+	}},
+}
+
+type appliedExpansionTransform struct {
+	transform expansionTransform
+	arg       string
+}
+
+// parseExpansion parses the contents of an expansion (without the enclosing
+// ${ and }). Anything after the first | is a default value, unless the name is
+// followed by ::, in which case the rest is parsed as a |-separated chain of
+// transforms.
+func parseExpansion(expansion string) (name string, defaultVal string, transforms []appliedExpansionTransform, err error) {
+	defaultIdx := strings.Index(expansion, "|")
+	transformIdx := strings.Index(expansion, expansionTransformSeparator)
+	if transformIdx == -1 || (defaultIdx != -1 && defaultIdx < transformIdx) {
+		if defaultIdx == -1 {
+			return expansion, "", nil, nil
+		}
+		return expansion[:defaultIdx], expansion[defaultIdx+1:], nil, nil
+	}
+	name = expansion[:transformIdx]
+
+	for _, segment := range strings.Split(expansion[transformIdx+len(expansionTransformSeparator):], "|") {
+		parts := splitExpansionTransform(segment)
+		t, ok := expansionTransforms[parts[0]]
+		if !ok {
+			return "", "", nil, errors.Errorf("unknown expansion transform '%s'", parts[0])
+		}
+		if t.hasArg && len(parts) != 2 {
+			return "", "", nil, errors.Errorf("expansion transform '%s' requires an argument", parts[0])
+		}
+		if !t.hasArg && len(parts) != 1 {
+			return "", "", nil, errors.Errorf("expansion transform '%s' does not take an argument", parts[0])
+		}
+		applied := appliedExpansionTransform{transform: t}
+		if t.hasArg {
+			applied.arg = parts[1]
+		}
+		transforms = append(transforms, applied)
+	}
+
+	return name, "", transforms, nil
+}
+
+func splitExpansionTransform(segment string) []string {
+	return strings.SplitN(segment, ":", 2)
+}
+
 func (self *Expansions) Map() map[string]string {
 	return *self
 }
@@ -162,6 +162,42 @@ func TestExpandString(t *testing.T) {
 
 		})
 
+		Convey("transforms should be applied to the expansion's value", func() {
+			expansions := NewExpansions(map[string]string{
+				"key1": "  Val1  ",
+				"key2": "refs/heads/main",
+				"key3": "",
+			})
+
+			toExpand := "${key1::trim|lower} ${key2::trim_prefix:refs/heads/|upper} " +
+				"${key3::default:blah} ${key4::default:blech} ${key2::trim_suffix:/main}"
+			expanded := "val1 MAIN blah blech refs/heads"
+
+			exp, err := expansions.ExpandString(toExpand)
+			So(err, ShouldBeNil)
+			So(exp, ShouldEqual, expanded)
+
+			Convey("but defaults should be unchanged", func() {
+				exp, err := expansions.ExpandString("${key4|http://localhost|8080} ${key4|lower} ${key4|default:x::upper}")
+				So(err, ShouldBeNil)
+				So(exp, ShouldEqual, "http://localhost|8080 lower default:x::upper")
+			})
+
+			Convey("and invalid transforms should cause an error", func() {
+				for _, bad := range []string{"${key1::lower|uper}", "${key1::trim_prefix}", "${key1::upper:x}", "${key1::}"} {
+					_, err := expansions.ExpandString(bad)
+					So(err, ShouldNotBeNil)
+					So(ValidateExpansionTransforms(bad), ShouldNotBeNil)
+				}
+				So(ValidateExpansionTransforms("${key1::lower} ${key2|blah}"), ShouldBeNil)
+			})
+
+			Convey("and the names of referenced expansions should be found", func() {
+				So(ExpansionNames("${key1::lower} ${key2|blah} ${key1} no expansion"), ShouldResemble, []string{"key1", "key2", "key1"})
+				So(ExpansionNames("${key1::lower|uper}"), ShouldBeEmpty)
+			})
+		})
+
 		Convey("badly formed command strings should cause an error", func() {
 
 			badStr1 := "hello ${key1|blah}${key3}hello${ ${key4} " +
@@ -0,0 +1,317 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The operations that can appear in a JSON Patch document.
+const (
+	JSONPatchOpAdd     = "add"
+	JSONPatchOpRemove  = "remove"
+	JSONPatchOpReplace = "replace"
+	JSONPatchOpMove    = "move"
+	JSONPatchOpCopy    = "copy"
+	JSONPatchOpTest    = "test"
+)
+
+// JSONPatchOperation is one operation in an RFC 6902 JSON Patch document.
+type JSONPatchOperation struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	From string `json:"from,omitempty"`
+	// Value is nil if the operation does not have a value, which is distinct
+	// from a JSON null value.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies the RFC 6902 JSON Patch document to the JSON
+// document and returns the patched document. The patch is applied
+// atomically: if any operation fails, including a failed test operation, an
+// error is returned and none of the operations are applied.
+func ApplyJSONPatch(doc, patch []byte) ([]byte, error) {
+	var ops []JSONPatchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling JSON patch")
+	}
+	root, err := decodeJSONValue(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshalling document")
+	}
+
+	for i, op := range ops {
+		root, err = applyJSONPatchOperation(root, op)
+		if err != nil {
+			return nil, errors.Wrapf(err, "applying operation %d ('%s' at path '%s')", i, op.Op, op.Path)
+		}
+	}
+
+	patched, err := json.Marshal(root)
+	return patched, errors.Wrap(err, "marshalling patched document")
+}
+
+func applyJSONPatchOperation(root interface{}, op JSONPatchOperation) (interface{}, error) {
+	path, err := parseJSONPointer(op.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid path")
+	}
+
+	switch op.Op {
+	case JSONPatchOpAdd, JSONPatchOpReplace, JSONPatchOpTest:
+		if op.Value == nil {
+			return nil, errors.New("operation requires a value")
+		}
+		value, err := decodeJSONValue(op.Value)
+		if err != nil {
+			return nil, errors.Wrap(err, "unmarshalling value")
+		}
+		switch op.Op {
+		case JSONPatchOpAdd:
+			return jsonPointerAdd(root, path, value)
+		case JSONPatchOpReplace:
+			return jsonPointerReplace(root, path, value)
+		default:
+			current, err := jsonPointerGet(root, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, errors.New("test failed: value does not match")
+			}
+			return root, nil
+		}
+	case JSONPatchOpRemove:
+		root, _, err = jsonPointerRemove(root, path)
+		return root, err
+	case JSONPatchOpMove, JSONPatchOpCopy:
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid from path")
+		}
+		var value interface{}
+		if op.Op == JSONPatchOpMove {
+			if len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+				return nil, errors.New("cannot move a value into one of its children")
+			}
+			root, value, err = jsonPointerRemove(root, from)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			value, err = jsonPointerGet(root, from)
+			if err != nil {
+				return nil, err
+			}
+			if value, err = copyJSONValue(value); err != nil {
+				return nil, err
+			}
+		}
+		return jsonPointerAdd(root, path, value)
+	default:
+		return nil, errors.Errorf("unrecognized operation '%s'", op.Op)
+	}
+}
+
+// parseJSONPointer splits an RFC 6901 JSON Pointer into its unescaped
+// reference tokens. The empty pointer refers to the whole document.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Errorf("JSON pointer '%s' must start with '/'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func jsonPointerGet(root interface{}, path []string) (interface{}, error) {
+	node := root
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, errors.Errorf("member '%s' does not exist", token)
+			}
+			node = child
+		case []interface{}:
+			i, err := jsonArrayIndex(token, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, errors.Errorf("cannot get '%s' from a scalar value", token)
+		}
+	}
+	return node, nil
+}
+
+func jsonPointerAdd(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return modifyJSONParent(root, path, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[token] = value
+			return p, nil
+		case []interface{}:
+			i, err := jsonArrayIndex(token, len(p), true)
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, nil)
+			copy(p[i+1:], p[i:])
+			p[i] = value
+			return p, nil
+		default:
+			return nil, errors.Errorf("cannot add '%s' to a scalar value", token)
+		}
+	})
+}
+
+func jsonPointerReplace(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return modifyJSONParent(root, path, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := p[token]; !ok {
+				return nil, errors.Errorf("member '%s' does not exist", token)
+			}
+			p[token] = value
+			return p, nil
+		case []interface{}:
+			i, err := jsonArrayIndex(token, len(p), false)
+			if err != nil {
+				return nil, err
+			}
+			p[i] = value
+			return p, nil
+		default:
+			return nil, errors.Errorf("cannot replace '%s' in a scalar value", token)
+		}
+	})
+}
+
+// jsonPointerRemove removes the value at the path and returns the modified
+// document along with the removed value.
+func jsonPointerRemove(root interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("cannot remove the whole document")
+	}
+	var removed interface{}
+	root, err := modifyJSONParent(root, path, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			child, ok := p[token]
+			if !ok {
+				return nil, errors.Errorf("member '%s' does not exist", token)
+			}
+			removed = child
+			delete(p, token)
+			return p, nil
+		case []interface{}:
+			i, err := jsonArrayIndex(token, len(p), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = p[i]
+			return append(p[:i], p[i+1:]...), nil
+		default:
+			return nil, errors.Errorf("cannot remove '%s' from a scalar value", token)
+		}
+	})
+	return root, removed, err
+}
+
+// modifyJSONParent walks to the parent of the value at the path and replaces
+// the parent with the result of modify. Parents have to be replaced rather
+// than modified in place because modifying an array can reallocate it.
+func modifyJSONParent(node interface{}, path []string, modify func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return modify(node, path[0])
+	}
+
+	token := path[0]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[token]
+		if !ok {
+			return nil, errors.Errorf("member '%s' does not exist", token)
+		}
+		newChild, err := modifyJSONParent(child, path[1:], modify)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = newChild
+		return n, nil
+	case []interface{}:
+		i, err := jsonArrayIndex(token, len(n), false)
+		if err != nil {
+			return nil, err
+		}
+		newChild, err := modifyJSONParent(n[i], path[1:], modify)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = newChild
+		return n, nil
+	default:
+		return nil, errors.Errorf("cannot get '%s' from a scalar value", token)
+	}
+}
+
+// jsonArrayIndex parses the array index token. If allowEnd is set, the index
+// can refer to the position just past the end of the array, including by
+// using '-'.
+func jsonArrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" {
+		if !allowEnd {
+			return 0, errors.New("index '-' refers to a nonexistent element")
+		}
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.ContainsAny(token, "+-") {
+		return 0, errors.Errorf("invalid array index '%s'", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, errors.Errorf("invalid array index '%s'", token)
+	}
+	if i > length || (i == length && !allowEnd) {
+		return 0, errors.Errorf("array index %d is out of bounds", i)
+	}
+	return i, nil
+}
+
+// decodeJSONValue unmarshals the JSON while keeping numbers exactly as they
+// were written.
+func decodeJSONValue(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func copyJSONValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling value to copy")
+	}
+	return decodeJSONValue(data)
+}
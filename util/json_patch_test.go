@@ -0,0 +1,55 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	const doc = `{"a": {"b": [1, 2, 3]}, "c": "d", "e~f": {"g/h": true}}`
+	for name, tCase := range map[string]struct {
+		patch    string
+		expected string
+	}{
+		"AddMember":            {patch: `[{"op": "add", "path": "/x", "value": {"y": null}}]`, expected: `{"a": {"b": [1, 2, 3]}, "c": "d", "e~f": {"g/h": true}, "x": {"y": null}}`},
+		"AddArrayElement":      {patch: `[{"op": "add", "path": "/a/b/1", "value": 5}]`, expected: `{"a": {"b": [1, 5, 2, 3]}, "c": "d", "e~f": {"g/h": true}}`},
+		"AppendArrayElement":   {patch: `[{"op": "add", "path": "/a/b/-", "value": 4}]`, expected: `{"a": {"b": [1, 2, 3, 4]}, "c": "d", "e~f": {"g/h": true}}`},
+		"RemoveMember":         {patch: `[{"op": "remove", "path": "/c"}]`, expected: `{"a": {"b": [1, 2, 3]}, "e~f": {"g/h": true}}`},
+		"RemoveArrayElement":   {patch: `[{"op": "remove", "path": "/a/b/0"}]`, expected: `{"a": {"b": [2, 3]}, "c": "d", "e~f": {"g/h": true}}`},
+		"ReplaceEscapedMember": {patch: `[{"op": "replace", "path": "/e~0f/g~1h", "value": false}]`, expected: `{"a": {"b": [1, 2, 3]}, "c": "d", "e~f": {"g/h": false}}`},
+		"Move":                 {patch: `[{"op": "move", "from": "/c", "path": "/a/c"}]`, expected: `{"a": {"b": [1, 2, 3], "c": "d"}, "e~f": {"g/h": true}}`},
+		"Copy":                 {patch: `[{"op": "copy", "from": "/a/b", "path": "/b"}, {"op": "remove", "path": "/b/0"}]`, expected: `{"a": {"b": [1, 2, 3]}, "b": [2, 3], "c": "d", "e~f": {"g/h": true}}`},
+		"TestThenReplace":      {patch: `[{"op": "test", "path": "/a/b/2", "value": 3}, {"op": "replace", "path": "/a/b/2", "value": 30}]`, expected: `{"a": {"b": [1, 2, 30]}, "c": "d", "e~f": {"g/h": true}}`},
+		"ReplaceDocument":      {patch: `[{"op": "replace", "path": "", "value": []}]`, expected: `[]`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			patched, err := ApplyJSONPatch([]byte(doc), []byte(tCase.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tCase.expected, string(patched))
+		})
+	}
+
+	for name, patch := range map[string]string{
+		"InvalidPatch":         `{"op": "add"}`,
+		"UnrecognizedOp":       `[{"op": "merge", "path": "/c", "value": 1}]`,
+		"MissingValue":         `[{"op": "add", "path": "/c"}]`,
+		"InvalidPointer":       `[{"op": "add", "path": "c", "value": 1}]`,
+		"AddToMissingParent":   `[{"op": "add", "path": "/x/y", "value": 1}]`,
+		"AddOutOfBounds":       `[{"op": "add", "path": "/a/b/4", "value": 1}]`,
+		"AddLeadingZeroIndex":  `[{"op": "add", "path": "/a/b/01", "value": 1}]`,
+		"RemoveMissingMember":  `[{"op": "remove", "path": "/x"}]`,
+		"RemoveDocument":       `[{"op": "remove", "path": ""}]`,
+		"ReplaceMissingMember": `[{"op": "replace", "path": "/x", "value": 1}]`,
+		"ReplaceEndOfArray":    `[{"op": "replace", "path": "/a/b/-", "value": 1}]`,
+		"MoveIntoChild":        `[{"op": "move", "from": "/a", "path": "/a/b/0"}]`,
+		"FailedTest":           `[{"op": "test", "path": "/c", "value": "e"}]`,
+		"LaterOperationFails":  `[{"op": "remove", "path": "/c"}, {"op": "remove", "path": "/c"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ApplyJSONPatch([]byte(doc), []byte(patch))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"context"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/model"
+)
+
+// RuleFailure is a validation rule that a project config fails, along with
+// the errors and warnings that the rule reported.
+type RuleFailure struct {
+	Rule   string           `json:"rule"`
+	Errors ValidationErrors `json:"errors"`
+}
+
+// ReplayProjectValidation runs the current validation rules against a
+// project, which may be from a version created before the rules existed, and
+// returns the rules that the project fails sorted by rule name. The rules that
+// check the project against its settings only run if the project ref is set.
+func ReplayProjectValidation(ctx context.Context, projectInfo model.ProjectInfo, includeLong bool) []RuleFailure {
+	project := projectInfo.Project
+	ctx, span := startValidationSpan(ctx, "ReplayProjectValidation", project)
+	defer span.End()
+
+	errsByRule := map[string]ValidationErrors{}
+	replay := func(rule interface{}, validate func() ValidationErrors) {
+		if errs := traceValidator(ctx, rule, validate); len(errs) > 0 {
+			name := validatorName(rule)
+			errsByRule[name] = append(errsByRule[name], errs...)
+		}
+	}
+	for _, projectErrorValidator := range projectErrorValidators {
+		replay(projectErrorValidator, func() ValidationErrors { return projectErrorValidator(project) })
+	}
+	for _, longSyntaxValidator := range longErrorValidators {
+		replay(longSyntaxValidator, func() ValidationErrors { return longSyntaxValidator(project, includeLong) })
+	}
+	replay(ensureReferentialIntegrity, func() ValidationErrors { return validateReferentialIntegrity(project) })
+	for _, projectWarningValidator := range projectWarningValidators {
+		replay(projectWarningValidator, func() ValidationErrors { return projectWarningValidator(project) })
+	}
+	if projectInfo.Ref != nil {
+		isConfigDefined := projectInfo.Config != nil
+		for _, validateSettings := range projectSettingsValidators {
+			replay(validateSettings, func() ValidationErrors { return validateSettings(project, projectInfo.Ref, isConfigDefined) })
+		}
+	}
+	if projectInfo.Config != nil {
+		for _, projectConfigErrorValidator := range projectConfigErrorValidators {
+			replay(projectConfigErrorValidator, func() ValidationErrors { return projectConfigErrorValidator(projectInfo.Config) })
+		}
+	}
+
+	failures := make([]RuleFailure, 0, len(errsByRule))
+	allErrs := ValidationErrors{}
+	for rule, errs := range errsByRule {
+		failures = append(failures, RuleFailure{Rule: rule, Errors: errs})
+		allErrs = append(allErrs, errs...)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Rule < failures[j].Rule
+	})
+	setValidationAttributes(span, allErrs)
+	return failures
+}
@@ -0,0 +1,64 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayProjectValidation(t *testing.T) {
+	require.NoError(t, db.ClearCollections(distro.Collection))
+
+	project := &model.Project{
+		Identifier: "proj",
+		Tasks: []model.ProjectTask{
+			{Name: "bad|name"},
+			{Name: "all"},
+		},
+	}
+
+	t.Run("ReportsFailingRulesByName", func(t *testing.T) {
+		failures := ReplayProjectValidation(context.Background(), model.ProjectInfo{Project: project}, false)
+		require.NotEmpty(t, failures)
+
+		byRule := map[string]ValidationErrors{}
+		for i, failure := range failures {
+			assert.NotEmpty(t, failure.Errors)
+			if i > 0 {
+				assert.Less(t, failures[i-1].Rule, failure.Rule, "rules should be sorted")
+			}
+			byRule[failure.Rule] = failure.Errors
+		}
+
+		require.Contains(t, byRule, "validateTaskNames")
+		require.Len(t, byRule["validateTaskNames"], 1)
+		assert.Equal(t, Error, byRule["validateTaskNames"][0].Level)
+		assert.Contains(t, byRule["validateTaskNames"][0].Message, "bad|name")
+
+		require.Contains(t, byRule, "checkTasks")
+		for _, validationErr := range byRule["checkTasks"] {
+			assert.Equal(t, Warning, validationErr.Level)
+		}
+	})
+	t.Run("RunsSettingsRulesOnlyWithProjectRef", func(t *testing.T) {
+		ref := &model.ProjectRef{Id: "proj", Identifier: "proj", VersionControlEnabled: utility.TruePtr()}
+
+		for _, failure := range ReplayProjectValidation(context.Background(), model.ProjectInfo{Project: project}, false) {
+			assert.NotEqual(t, "validateVersionControl", failure.Rule)
+		}
+
+		var found bool
+		for _, failure := range ReplayProjectValidation(context.Background(), model.ProjectInfo{Project: project, Ref: ref}, false) {
+			if failure.Rule == "validateVersionControl" {
+				found = true
+			}
+		}
+		assert.True(t, found, "version control is enabled without a project config")
+	})
+}
@@ -40,6 +40,7 @@ var distroSyntaxValidators = []distroValidator{
 	ensureHasValidFinderSettings,
 	ensureHasValidDispatcherSettings,
 	ensureHasValidVirtualWorkstationSettings,
+	ensureValidStuckTaskThreshold,
 }
 
 // CheckDistro checks if the distro configuration syntax is valid. Returns
@@ -205,7 +206,7 @@ func validateSingleProviderSettings(d *distro.Distro) error {
 // ensureUniqueId checks that the distro's id does not collide with an existing id.
 func ensureUniqueId(d *distro.Distro, distroIds []string) ValidationErrors {
 	if utility.StringSliceContains(distroIds, d.Id) {
-		return ValidationErrors{{Error, fmt.Sprintf("distro '%v' uses an existing identifier", d.Id)}}
+		return ValidationErrors{{Level: Error, Message: fmt.Sprintf("distro '%v' uses an existing identifier", d.Id)}}
 	}
 	return nil
 }
@@ -244,7 +245,7 @@ func ensureNoAliases(d *distro.Distro, distroAliases []string) ValidationErrors
 func ensureValidExpansions(ctx context.Context, d *distro.Distro, s *evergreen.Settings) ValidationErrors {
 	for _, e := range d.Expansions {
 		if e.Key == "" {
-			return ValidationErrors{{Error, "distro cannot be blank expansion key"}}
+			return ValidationErrors{{Level: Error, Message: "distro cannot be blank expansion key"}}
 		}
 	}
 	return nil
@@ -254,7 +255,7 @@ func ensureValidExpansions(ctx context.Context, d *distro.Distro, s *evergreen.S
 func ensureValidSSHOptions(ctx context.Context, d *distro.Distro, s *evergreen.Settings) ValidationErrors {
 	for _, o := range d.SSHOptions {
 		if o == "" {
-			return ValidationErrors{{Error, "distro cannot be blank SSH option"}}
+			return ValidationErrors{{Level: Error, Message: "distro cannot be blank SSH option"}}
 		}
 	}
 	return nil
@@ -337,11 +338,11 @@ func ensureValidCloneMethod(ctx context.Context, d *distro.Distro, s *evergreen.
 
 func ensureHasNonZeroID(ctx context.Context, d *distro.Distro, s *evergreen.Settings) ValidationErrors {
 	if d == nil {
-		return ValidationErrors{{Error, "distro cannot be nil"}}
+		return ValidationErrors{{Level: Error, Message: "distro cannot be nil"}}
 	}
 
 	if d.Id == "" {
-		return ValidationErrors{{Error, "distro must specify id"}}
+		return ValidationErrors{{Level: Error, Message: "distro must specify id"}}
 	}
 
 	return nil
@@ -351,7 +352,7 @@ func ensureHasNonZeroID(ctx context.Context, d *distro.Distro, s *evergreen.Sett
 func ensureHasNoUnauthorizedCharacters(ctx context.Context, d *distro.Distro, s *evergreen.Settings) ValidationErrors {
 	if strings.ContainsAny(d.Id, unauthorizedDistroCharacters) {
 		message := fmt.Sprintf("distro '%v' contains unauthorized characters (%v)", d.Id, unauthorizedDistroCharacters)
-		return ValidationErrors{{Error, message}}
+		return ValidationErrors{{Level: Error, Message: message}}
 	}
 	return nil
 }
@@ -363,12 +364,12 @@ func ensureValidContainerPool(ctx context.Context, d *distro.Distro, s *evergree
 		// check if container pool exists
 		pool := s.ContainerPools.GetContainerPool(d.ContainerPool)
 		if pool == nil {
-			return ValidationErrors{{Error, "distro container pool does not exist"}}
+			return ValidationErrors{{Level: Error, Message: "distro container pool does not exist"}}
 		}
 		// warn if container pool exists without valid distro
 		err := distro.ValidateContainerPoolDistros(s)
 		if err != nil {
-			return ValidationErrors{{Error, "error in container pool settings: " + err.Error()}}
+			return ValidationErrors{{Level: Error, Message: "error in container pool settings: " + err.Error()}}
 		}
 	}
 	return nil
@@ -549,3 +550,15 @@ func ensureHasValidVirtualWorkstationSettings(ctx context.Context, d *distro.Dis
 	}
 	return errs
 }
+
+// ensureValidStuckTaskThreshold checks that the distro's stuck task threshold
+// is long enough that tasks with a healthy agent aren't considered stuck.
+func ensureValidStuckTaskThreshold(ctx context.Context, d *distro.Distro, s *evergreen.Settings) ValidationErrors {
+	if d.StuckTaskThreshold == 0 || d.StuckTaskThreshold >= distro.MinStuckTaskThreshold {
+		return nil
+	}
+	return ValidationErrors{{
+		Message: fmt.Sprintf("stuck task threshold %s for distro '%s' must be at least %s", d.StuckTaskThreshold, d.Id, distro.MinStuckTaskThreshold),
+		Level:   Error,
+	}}
+}
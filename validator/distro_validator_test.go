@@ -3,6 +3,7 @@ package validator
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/birch"
 	"github.com/evergreen-ci/evergreen"
@@ -139,6 +140,7 @@ func TestCheckDistro(t *testing.T) {
 
 		Reset(func() {
 			So(db.Clear(distro.Collection), ShouldBeNil)
+			distro.InvalidateCache()
 		})
 	})
 }
@@ -486,14 +488,14 @@ func TestEnsureValidContainerPool(t *testing.T) {
 	assert.NoError(d4.Insert())
 
 	err := ensureValidContainerPool(ctx, d1, conf)
-	assert.Equal(err, ValidationErrors{{Error,
-		"error in container pool settings: container pool 'test-pool-invalid' has invalid distro 'd1'"}})
+	assert.Equal(err, ValidationErrors{{Level: Error,
+		Message: "error in container pool settings: container pool 'test-pool-invalid' has invalid distro 'd1'"}})
 	err = ensureValidContainerPool(ctx, d2, conf)
-	assert.Equal(err, ValidationErrors{{Error,
-		"error in container pool settings: container pool 'test-pool-invalid' has invalid distro 'd1'"}})
+	assert.Equal(err, ValidationErrors{{Level: Error,
+		Message: "error in container pool settings: container pool 'test-pool-invalid' has invalid distro 'd1'"}})
 	err = ensureValidContainerPool(ctx, d3, conf)
-	assert.Equal(err, ValidationErrors{{Error,
-		"distro container pool does not exist"}})
+	assert.Equal(err, ValidationErrors{{Level: Error,
+		Message: "distro container pool does not exist"}})
 	err = ensureValidContainerPool(ctx, d4, conf)
 	assert.Nil(err)
 }
@@ -727,3 +729,13 @@ func TestEnsureHasValidVirtualWorkstationSettings(t *testing.T) {
 		IsVirtualWorkstation: true,
 	}, settings))
 }
+
+func TestEnsureValidStuckTaskThreshold(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.Nil(t, ensureValidStuckTaskThreshold(ctx, &distro.Distro{Id: "d"}, nil))
+	assert.Nil(t, ensureValidStuckTaskThreshold(ctx, &distro.Distro{Id: "d", StuckTaskThreshold: 15 * time.Minute}, nil))
+	assert.NotNil(t, ensureValidStuckTaskThreshold(ctx, &distro.Distro{Id: "d", StuckTaskThreshold: time.Minute}, nil))
+	assert.NotNil(t, ensureValidStuckTaskThreshold(ctx, &distro.Distro{Id: "d", StuckTaskThreshold: -time.Minute}, nil))
+}
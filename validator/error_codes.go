@@ -0,0 +1,179 @@
+package validator
+
+// Codes identify the kind of problem that a validation error reports. Unlike
+// messages, they don't change when the wording of an error does, so callers
+// should match on codes rather than messages. Codes are part of the API, so an
+// existing code must never be renamed or reused for a different problem.
+const (
+	// Project codes.
+	CodeDistroLookupFailed        = "DISTRO_LOOKUP_FAILED"
+	CodePatchedConfigInvalid      = "PATCHED_CONFIG_INVALID"
+	CodeProjectBatchTimeTooLarge  = "PROJECT_BATCHTIME_TOO_LARGE"
+	CodeProjectConfigInvalid      = "PROJECT_CONFIG_INVALID"
+	CodeProjectConfigNotStrict    = "PROJECT_CONFIG_NOT_STRICT"
+	CodeProjectInvalidCommandType = "PROJECT_INVALID_COMMAND_TYPE"
+	CodeProjectNegativeBatchTime  = "PROJECT_NEGATIVE_BATCHTIME"
+	CodeProjectSettingsUnchecked  = "PROJECT_SETTINGS_UNCHECKED"
+
+	// Build variant codes.
+	CodeActivationIgnored              = "ACTIVATION_IGNORED"
+	CodeArtifactDestinationConflict    = "ARTIFACT_DESTINATION_CONFLICT"
+	CodeBVCanaryHoursWithCron          = "BV_CANARY_HOURS_WITH_CRON"
+	CodeBVCanaryIgnored                = "BV_CANARY_IGNORED"
+	CodeBVCanaryInvalid                = "BV_CANARY_INVALID"
+	CodeBVCanaryRarelyActivates        = "BV_CANARY_RARELY_ACTIVATES"
+	CodeBVContainerCPUExceedsPool      = "BV_CONTAINER_CPU_EXCEEDS_POOL"
+	CodeBVContainerMemoryExceedsPool   = "BV_CONTAINER_MEMORY_EXCEEDS_POOL"
+	CodeBVContainerOverridesDistro     = "BV_CONTAINER_OVERRIDES_DISTRO"
+	CodeBVDuplicateDisplayName         = "BV_DUP_DISPLAY_NAME"
+	CodeBVDuplicateName                = "BV_DUP_NAME"
+	CodeBVDuplicateTask                = "BV_DUP_TASK"
+	CodeBVInvalidArtifactNamespace     = "BV_INVALID_ARTIFACT_NAMESPACE"
+	CodeBVInvalidName                  = "BV_INVALID_NAME"
+	CodeBVInvalidRunOnStrategy         = "BV_INVALID_RUN_ON_STRATEGY"
+	CodeBVMissingDisplayName           = "BV_MISSING_DISPLAY_NAME"
+	CodeBVMissingName                  = "BV_MISSING_NAME"
+	CodeBVMissingRunOn                 = "BV_MISSING_RUN_ON"
+	CodeBVNameAmbiguous                = "BV_NAME_AMBIGUOUS"
+	CodeBVNameHasComma                 = "BV_NAME_HAS_COMMA"
+	CodeBVNoTasks                      = "BV_NO_TASKS"
+	CodeBVReservedName                 = "BV_RESERVED_NAME"
+	CodeBVTaskInTaskGroup              = "BV_TASK_IN_TASK_GROUP"
+	CodeBVTaskMissingName              = "BV_TASK_MISSING_NAME"
+	CodeBVUndefinedRunOn               = "BV_UNDEFINED_RUN_ON"
+	CodeBVUndefinedTask                = "BV_UNDEFINED_TASK"
+	CodeCronAndBatchTime               = "CRON_AND_BATCHTIME"
+	CodeCronInvalid                    = "CRON_INVALID"
+	CodeCronTimezoneConflict           = "CRON_TIMEZONE_CONFLICT"
+	CodeRunOnMixesContainersAndDistros = "RUN_ON_MIXES_CONTAINERS_AND_DISTROS"
+	CodeRunOnMultipleContainers        = "RUN_ON_MULTIPLE_CONTAINERS"
+	CodeTimezoneWithoutCron            = "TIMEZONE_WITHOUT_CRON"
+
+	// Task codes.
+	CodeDisplayTaskInvalidExecTaskName = "DISPLAY_TASK_INVALID_EXEC_TASK_NAME"
+	CodeExecTimeoutTooLong             = "EXEC_TIMEOUT_TOO_LONG"
+	CodeExecTimeoutTooShort            = "EXEC_TIMEOUT_TOO_SHORT"
+	CodeExecTimeoutUndefined           = "EXEC_TIMEOUT_UNDEFINED"
+	CodeInvalidAllowedRequester        = "INVALID_ALLOWED_REQUESTER"
+	CodeRestrictedVarReferenced        = "RESTRICTED_VAR_REFERENCED"
+	CodeTaskBatchTimeOnlyInCanary      = "TASK_BATCHTIME_ONLY_IN_CANARY"
+	CodeTaskContainerOverridesDistro   = "TASK_CONTAINER_OVERRIDES_DISTRO"
+	CodeTaskDuplicateName              = "TASK_DUP_NAME"
+	CodeTaskInvalidCompliance          = "TASK_INVALID_COMPLIANCE"
+	CodeTaskInvalidExpectedArtifacts   = "TASK_INVALID_EXPECTED_ARTIFACTS"
+	CodeTaskInvalidName                = "TASK_INVALID_NAME"
+	CodeTaskInvalidOutputs             = "TASK_INVALID_OUTPUTS"
+	CodeTaskInvalidRunOnStrategy       = "TASK_INVALID_RUN_ON_STRATEGY"
+	CodeTaskInvalidTag                 = "TASK_INVALID_TAG"
+	CodeTaskNameAmbiguous              = "TASK_NAME_AMBIGUOUS"
+	CodeTaskNameHasComma               = "TASK_NAME_HAS_COMMA"
+	CodeTaskNeverRuns                  = "TASK_NEVER_RUNS"
+	CodeTaskNoCommands                 = "TASK_NO_COMMANDS"
+	CodeTaskPatchableGitTagOnly        = "TASK_PATCHABLE_GIT_TAG_ONLY"
+	CodeTaskRequesterSettingsIgnored   = "TASK_REQUESTER_SETTINGS_IGNORED"
+	CodeTaskUndefinedRunOn             = "TASK_UNDEFINED_RUN_ON"
+
+	// Dependency codes.
+	CodeCrossVersionDependencyNotSpecific   = "CROSS_VERSION_DEP_NOT_SPECIFIC"
+	CodeCrossVersionDependencyPatchOnly     = "CROSS_VERSION_DEP_PATCH_ONLY"
+	CodeCrossVersionDependencyPatchOptional = "CROSS_VERSION_DEP_PATCH_OPTIONAL"
+	CodeDependencyOmitsNoGeneratedTasks     = "DEP_OMITS_NO_GENERATED_TASKS"
+	CodeDependencyQuarantined               = "DEP_QUARANTINED"
+	CodeTaskAllDependenciesWithOthers       = "TASK_ALL_DEPS_WITH_OTHERS"
+	CodeTaskDependencyCycle                 = "TASK_DEP_CYCLE"
+	CodeTaskDependencyRunsInFewerBuilds     = "TASK_DEP_RUNS_IN_FEWER_BUILDS"
+	CodeTaskDuplicateDependency             = "TASK_DUP_DEP"
+	CodeTaskInvalidDependencyStatus         = "TASK_INVALID_DEP_STATUS"
+	CodeTaskUndefinedDependency             = "TASK_UNDEFINED_DEP"
+	CodeTaskUndefinedDependencyVariant      = "TASK_UNDEFINED_DEP_VARIANT"
+
+	// Task group codes.
+	CodeTaskGroupDuplicateName          = "TASK_GROUP_DUP_NAME"
+	CodeTaskGroupDuplicateTask          = "TASK_GROUP_DUP_TASK"
+	CodeTaskGroupInvalidMaxHosts        = "TASK_GROUP_INVALID_MAX_HOSTS"
+	CodeTaskGroupInvalidTeardownCommand = "TASK_GROUP_INVALID_TEARDOWN_COMMAND"
+	CodeTaskGroupNameConflict           = "TASK_GROUP_NAME_CONFLICT"
+	CodeTaskGroupNoTasks                = "TASK_GROUP_NO_TASKS"
+
+	// Command and function codes.
+	CodeCommandAndFunction         = "COMMAND_AND_FUNCTION"
+	CodeCommandCalledTooManyTimes  = "COMMAND_CALLED_TOO_MANY_TIMES"
+	CodeCommandInvalid             = "COMMAND_INVALID"
+	CodeCommandInvalidExpansion    = "COMMAND_INVALID_EXPANSION"
+	CodeCommandInvalidType         = "COMMAND_INVALID_TYPE"
+	CodeCommandMissingScript       = "COMMAND_MISSING_SCRIPT"
+	CodeDuplicatedCommandBlock     = "DUPLICATED_COMMAND_BLOCK"
+	CodeFunctionDuplicateName      = "FUNCTION_DUP_NAME"
+	CodeFunctionNoCommands         = "FUNCTION_NO_COMMANDS"
+	CodeFunctionReferencesFunction = "FUNCTION_REFERENCES_FUNCTION"
+	CodeHostCreateLimitExceeded    = "HOST_CREATE_LIMIT_EXCEEDED"
+	CodeLoggerConfigInvalid        = "LOGGER_CONFIG_INVALID"
+
+	// Container codes.
+	CodeContainerCPUExceedsPod            = "CONTAINER_CPU_EXCEEDS_POD"
+	CodeContainerDuplicateName            = "CONTAINER_DUP_NAME"
+	CodeContainerFallbackMissingDistro    = "CONTAINER_FALLBACK_MISSING_DISTRO"
+	CodeContainerFallbackNegativeWait     = "CONTAINER_FALLBACK_NEGATIVE_WAIT"
+	CodeContainerFallbackToContainer      = "CONTAINER_FALLBACK_TO_CONTAINER"
+	CodeContainerFallbackUndefinedDistro  = "CONTAINER_FALLBACK_UNDEFINED_DISTRO"
+	CodeContainerFallbackWithoutContainer = "CONTAINER_FALLBACK_WITHOUT_CONTAINER"
+	CodeContainerInvalid                  = "CONTAINER_INVALID"
+	CodeContainerMemoryExceedsPod         = "CONTAINER_MEMORY_EXCEEDS_POD"
+	CodeContainerSizeInvalid              = "CONTAINER_SIZE_INVALID"
+	CodeContainerSizeMissingName          = "CONTAINER_SIZE_MISSING_NAME"
+
+	// Module codes.
+	CodeModuleDuplicateName = "MODULE_DUP_NAME"
+	CodeModuleInvalidRepo   = "MODULE_INVALID_REPO"
+	CodeModuleMissingBranch = "MODULE_MISSING_BRANCH"
+	CodeModuleMissingName   = "MODULE_MISSING_NAME"
+
+	// Alias codes.
+	CodeAliasInvalid             = "ALIAS_INVALID"
+	CodeAliasUndefinedTaskTag    = "ALIAS_UNDEFINED_TASK_TAG"
+	CodeAliasUndefinedVariantTag = "ALIAS_UNDEFINED_VARIANT_TAG"
+
+	// Selector codes.
+	CodeSelectorUndefinedTaskTag    = "SELECTOR_UNDEFINED_TASK_TAG"
+	CodeSelectorUndefinedVariantTag = "SELECTOR_UNDEFINED_VARIANT_TAG"
+
+	// Parameter codes.
+	CodeParameterDuplicateName = "PARAMETER_DUP_NAME"
+	CodeParameterInvalidName   = "PARAMETER_INVALID_NAME"
+	CodeParameterMissingName   = "PARAMETER_MISSING_NAME"
+
+	// Stage and external gate codes.
+	CodeExternalGateDuplicateName    = "EXTERNAL_GATE_DUP_NAME"
+	CodeExternalGateInvalidOnTimeout = "EXTERNAL_GATE_INVALID_ON_TIMEOUT"
+	CodeExternalGateMissingName      = "EXTERNAL_GATE_MISSING_NAME"
+	CodeExternalGateNegativeTimeout  = "EXTERNAL_GATE_NEGATIVE_TIMEOUT"
+	CodeExternalGateUndefined        = "EXTERNAL_GATE_UNDEFINED"
+	CodeStageCycle                   = "STAGE_CYCLE"
+	CodeStageDuplicateName           = "STAGE_DUP_NAME"
+	CodeStageMissingName             = "STAGE_MISSING_NAME"
+	CodeStageNoTasks                 = "STAGE_NO_TASKS"
+	CodeStageOverlap                 = "STAGE_OVERLAP"
+	CodeStageRunsAfterItself         = "STAGE_RUNS_AFTER_ITSELF"
+	CodeStageUndefined               = "STAGE_UNDEFINED"
+
+	// Project settings codes.
+	CodeActivationHookInvalidURL        = "ACTIVATION_HOOK_INVALID_URL"
+	CodeActivationHookNegativeTimeout   = "ACTIVATION_HOOK_NEGATIVE_TIMEOUT"
+	CodeActivationHookNoVariants        = "ACTIVATION_HOOK_NO_VARIANTS"
+	CodeActivationHookUndefinedVariant  = "ACTIVATION_HOOK_UNDEFINED_VARIANT"
+	CodeBuildBaronInvalid               = "BUILD_BARON_INVALID"
+	CodePeriodicBuildInvalid            = "PERIODIC_BUILD_INVALID"
+	CodeSeverityOverrideInvalidSeverity = "SEVERITY_OVERRIDE_INVALID_SEVERITY"
+	CodeSeverityOverrideUnknownRule     = "SEVERITY_OVERRIDE_UNKNOWN_RULE"
+	CodeTaskSyncDisabled                = "TASK_SYNC_DISABLED"
+	CodeTaskSyncInvalid                 = "TASK_SYNC_INVALID"
+	CodeTaskSyncMissingDependency       = "TASK_SYNC_MISSING_DEP"
+	CodeTaskSyncMissingPush             = "TASK_SYNC_MISSING_PUSH"
+	CodeTaskSyncTooManyCommands         = "TASK_SYNC_TOO_MANY_COMMANDS"
+	CodeVersionControlDisabled          = "VERSION_CONTROL_DISABLED"
+	CodeVersionControlUnused            = "VERSION_CONTROL_UNUSED"
+
+	// Validation suppression codes.
+	CodeValidationSuppressionInvalidSelector = "VALIDATION_SUPPRESSION_INVALID_SELECTOR"
+	CodeValidationSuppressionNoCodes         = "VALIDATION_SUPPRESSION_NO_CODES"
+)
@@ -0,0 +1,72 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationErrorCodes(t *testing.T) {
+	codes := func(errs ValidationErrors) []string {
+		found := []string{}
+		for _, err := range errs {
+			found = append(found, err.Code)
+		}
+		return found
+	}
+
+	t.Run("EveryResultHasACode", func(t *testing.T) {
+		project := &model.Project{
+			Tasks: []model.ProjectTask{
+				{Name: "compile", DependsOn: []model.TaskUnitDependency{{Name: "test"}}},
+				{Name: "test", DependsOn: []model.TaskUnitDependency{{Name: "compile"}, {Name: "nonexistent"}}},
+				{Name: "all"},
+			},
+			BuildVariants: []model.BuildVariant{
+				{Name: "bv", Tasks: []model.BuildVariantTaskUnit{{Name: "compile", Variant: "bv"}, {Name: "test", Variant: "bv"}}},
+				{Name: "bv", Tasks: []model.BuildVariantTaskUnit{{Name: "missing", Variant: "bv"}}},
+				{Name: "a,b"},
+			},
+		}
+		errs := append(CheckProjectErrors(context.Background(), project, nil, false), CheckProjectWarnings(context.Background(), project, nil)...)
+		require.NotEmpty(t, errs)
+		for _, err := range errs {
+			assert.NotEmpty(t, err.Code, "result '%s' should have a code", err.Message)
+		}
+	})
+	t.Run("IdentifiesDependencyCycles", func(t *testing.T) {
+		project := &model.Project{
+			Tasks: []model.ProjectTask{{Name: "compile"}, {Name: "test"}},
+			BuildVariants: []model.BuildVariant{
+				{
+					Name: "bv",
+					Tasks: []model.BuildVariantTaskUnit{
+						{Name: "compile", Variant: "bv", DependsOn: []model.TaskUnitDependency{{Name: "test"}}},
+						{Name: "test", Variant: "bv", DependsOn: []model.TaskUnitDependency{{Name: "compile"}}},
+					},
+				},
+			},
+		}
+		assert.Equal(t, []string{CodeTaskDependencyCycle}, codes(validateDependencyGraph(project)))
+	})
+	t.Run("IdentifiesDuplicateBuildVariants", func(t *testing.T) {
+		project := &model.Project{
+			BuildVariants: []model.BuildVariant{
+				{Name: "linux", DisplayName: "Linux"},
+				{Name: "linux", DisplayName: "Linux 2"},
+			},
+		}
+		assert.Equal(t, []string{CodeBVDuplicateName}, codes(validateBVNames(project)))
+	})
+	t.Run("KeepsCodesOfFunctionDefinitionErrors", func(t *testing.T) {
+		project := &model.Project{
+			Functions: map[string]*model.YAMLCommandSet{
+				"fn": {SingleCommand: &model.PluginCommandConf{Command: "shell.exec", Type: "bogus", Params: map[string]interface{}{"script": "echo"}}},
+			},
+		}
+		assert.Contains(t, codes(validatePluginCommands(project)), CodeCommandInvalidType)
+	})
+}
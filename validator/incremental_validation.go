@@ -0,0 +1,170 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// ValidationResultsCollection stores the results of incremental
+	// validations by config hash so that later validations can return only
+	// what changed.
+	ValidationResultsCollection = "validation_results"
+
+	// ValidationResultsTTL is how long the results of an incremental
+	// validation are kept. An incremental validation against results older
+	// than this returns every result as new.
+	ValidationResultsTTL = 7 * 24 * time.Hour
+)
+
+// ID returns an identifier for the validation error that's stable across
+// validations, so that the same problem in two versions of a config has the
+// same ID.
+func (e ValidationError) ID() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s", e.Level, e.Message)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Sorted returns the validation errors in a deterministic order: errors
+// before warnings, then by message.
+func (v ValidationErrors) Sorted() ValidationErrors {
+	sorted := make(ValidationErrors, len(v))
+	copy(sorted, v)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Level != sorted[j].Level {
+			return sorted[i].Level < sorted[j].Level
+		}
+		return sorted[i].Message < sorted[j].Message
+	})
+	return sorted
+}
+
+// IdentifiedValidationError is a validation error with its stable ID.
+type IdentifiedValidationError struct {
+	ID string `json:"id"`
+	ValidationError
+}
+
+func identifyValidationErrors(errs ValidationErrors) []IdentifiedValidationError {
+	identified := make([]IdentifiedValidationError, 0, len(errs))
+	for _, err := range errs.Sorted() {
+		identified = append(identified, IdentifiedValidationError{ID: err.ID(), ValidationError: err})
+	}
+	return identified
+}
+
+// IncrementalValidationResult is the result of an incremental validation.
+type IncrementalValidationResult struct {
+	// ConfigHash identifies the validated config. It should be passed as the
+	// previous config hash of the next incremental validation.
+	ConfigHash string `json:"config_hash"`
+	// Incremental is whether the results are relative to the previous
+	// config. If the previous config's results aren't available, every
+	// result is new.
+	Incremental bool                        `json:"incremental"`
+	New         []IdentifiedValidationError `json:"new"`
+	Resolved    []IdentifiedValidationError `json:"resolved"`
+	// Full is every result of the validation. It's only set if requested.
+	Full []IdentifiedValidationError `json:"full,omitempty"`
+}
+
+type validationResults struct {
+	ConfigHash string           `bson:"_id"`
+	Errors     ValidationErrors `bson:"errors"`
+	CreatedAt  time.Time        `bson:"created_at"`
+}
+
+var (
+	validationResultsErrorsKey    = bsonutil.MustHaveTag(validationResults{}, "Errors")
+	validationResultsCreatedAtKey = bsonutil.MustHaveTag(validationResults{}, "CreatedAt")
+)
+
+// ConfigHash returns a hash identifying the validation input. Inputs with the
+// same hash have the same validation results.
+func ConfigHash(input ValidationInput) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\x00%t\x00%t\x00", input.ProjectID, input.Quiet, input.IncludeLong)
+	_, _ = h.Write(input.ProjectYaml)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// GetIncrementalValidationResult stores the validation results of the input
+// and compares them against the results of the input's previous config.
+func GetIncrementalValidationResult(input ValidationInput, errs ValidationErrors) (*IncrementalValidationResult, error) {
+	result := &IncrementalValidationResult{ConfigHash: ConfigHash(input)}
+	if input.IncludeFull {
+		result.Full = identifyValidationErrors(errs)
+	}
+
+	_, err := db.Upsert(ValidationResultsCollection, bson.M{"_id": result.ConfigHash}, bson.M{
+		"$set": bson.M{
+			validationResultsErrorsKey:    errs,
+			validationResultsCreatedAtKey: time.Now(),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "storing validation results")
+	}
+
+	var previous *validationResults
+	if input.PreviousConfigHash != "" {
+		previous = &validationResults{}
+		err = db.FindOneQ(ValidationResultsCollection, db.Query(bson.M{"_id": input.PreviousConfigHash}), previous)
+		if adb.ResultsNotFound(err) {
+			previous = nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "finding previous validation results")
+		}
+	}
+	if previous == nil {
+		result.New = identifyValidationErrors(errs)
+		result.Resolved = []IdentifiedValidationError{}
+		return result, nil
+	}
+
+	result.Incremental = true
+	added, resolved := DiffValidationErrors(previous.Errors, errs)
+	result.New = identifyValidationErrors(added)
+	result.Resolved = identifyValidationErrors(resolved)
+	return result, nil
+}
+
+// RemoveExpiredValidationResults deletes the incremental validation results
+// that were stored before the given time.
+func RemoveExpiredValidationResults(before time.Time) error {
+	err := db.RemoveAll(ValidationResultsCollection, bson.M{
+		validationResultsCreatedAtKey: bson.M{"$lt": before},
+	})
+	return errors.Wrap(err, "removing expired validation results")
+}
+
+// DiffValidationErrors returns the validation errors in current that aren't
+// in previous and the ones in previous that aren't in current.
+func DiffValidationErrors(previous, current ValidationErrors) (added ValidationErrors, resolved ValidationErrors) {
+	previousIDs := map[string]bool{}
+	for _, err := range previous {
+		previousIDs[err.ID()] = true
+	}
+	currentIDs := map[string]bool{}
+	for _, err := range current {
+		currentIDs[err.ID()] = true
+		if !previousIDs[err.ID()] {
+			added = append(added, err)
+		}
+	}
+	for _, err := range previous {
+		if !currentIDs[err.ID()] {
+			resolved = append(resolved, err)
+		}
+	}
+	return added, resolved
+}
@@ -0,0 +1,70 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationErrorsSorted(t *testing.T) {
+	errs := ValidationErrors{
+		{Level: Warning, Message: "b"},
+		{Level: Error, Message: "z"},
+		{Level: Warning, Message: "a"},
+		{Level: Error, Message: "c"},
+	}
+	assert.Equal(t, ValidationErrors{
+		{Level: Error, Message: "c"},
+		{Level: Error, Message: "z"},
+		{Level: Warning, Message: "a"},
+		{Level: Warning, Message: "b"},
+	}, errs.Sorted())
+	assert.Equal(t, "b", errs[0].Message, "original errors should not be modified")
+
+	assert.Equal(t, ValidationError{Level: Error, Message: "c"}.ID(), ValidationError{Level: Error, Message: "c"}.ID())
+	assert.NotEqual(t, ValidationError{Level: Error, Message: "c"}.ID(), ValidationError{Level: Warning, Message: "c"}.ID())
+}
+
+func TestGetIncrementalValidationResult(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(ValidationResultsCollection))
+	}()
+	require.NoError(t, db.ClearCollections(ValidationResultsCollection))
+
+	unchanged := ValidationError{Level: Warning, Message: "unchanged"}
+	fixed := ValidationError{Level: Error, Message: "fixed"}
+	introduced := ValidationError{Level: Error, Message: "introduced"}
+
+	first := ValidationInput{ProjectYaml: []byte("first"), Incremental: true}
+	result, err := GetIncrementalValidationResult(first, ValidationErrors{unchanged, fixed})
+	require.NoError(t, err)
+	assert.False(t, result.Incremental)
+	assert.Len(t, result.New, 2)
+	assert.Empty(t, result.Resolved)
+	assert.Empty(t, result.Full)
+
+	second := ValidationInput{ProjectYaml: []byte("second"), Incremental: true, PreviousConfigHash: result.ConfigHash, IncludeFull: true}
+	result, err = GetIncrementalValidationResult(second, ValidationErrors{unchanged, introduced})
+	require.NoError(t, err)
+	assert.True(t, result.Incremental)
+	require.Len(t, result.New, 1)
+	assert.Equal(t, introduced.ID(), result.New[0].ID)
+	assert.Equal(t, introduced, result.New[0].ValidationError)
+	require.Len(t, result.Resolved, 1)
+	assert.Equal(t, fixed.ID(), result.Resolved[0].ID)
+	assert.Len(t, result.Full, 2)
+
+	unknown := ValidationInput{ProjectYaml: []byte("third"), Incremental: true, PreviousConfigHash: "not_a_hash"}
+	result, err = GetIncrementalValidationResult(unknown, ValidationErrors{unchanged})
+	require.NoError(t, err)
+	assert.False(t, result.Incremental)
+	assert.Len(t, result.New, 1)
+
+	require.NoError(t, RemoveExpiredValidationResults(time.Now().Add(time.Minute)))
+	result, err = GetIncrementalValidationResult(second, ValidationErrors{unchanged})
+	require.NoError(t, err)
+	assert.False(t, result.Incremental, "previous results should have been removed")
+}
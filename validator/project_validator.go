@@ -2,11 +2,15 @@ package validator
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/agent"
@@ -17,9 +21,11 @@ import (
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/utility"
+	"github.com/mitchellh/mapstructure"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/level"
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
 )
 
 type projectValidator func(*model.Project) ValidationErrors
@@ -43,6 +49,22 @@ const (
 	maxTaskSyncCommandsForDependenciesCheck = 300 // this should take about one second
 )
 
+const (
+	// execTimeoutRuntimeWindow is how far back to look at task runtimes when
+	// checking exec timeouts against them.
+	execTimeoutRuntimeWindow = 14 * 24 * time.Hour
+	// execTimeoutMinRuntimeSamples is the fewest successful runs of a task
+	// needed to compare its exec timeout to its runtime.
+	execTimeoutMinRuntimeSamples = 10
+	// execTimeoutMaxRuntimeFactor is how many times longer than the task's
+	// P95 runtime the exec timeout can be before hung tasks take
+	// needlessly long to be caught.
+	execTimeoutMaxRuntimeFactor = 10
+	// execTimeoutMinRuntimeFactor is how many times longer than the task's
+	// P95 runtime the exec timeout must be to avoid spurious timeouts.
+	execTimeoutMinRuntimeFactor = 1.2
+)
+
 func (vel ValidationErrorLevel) String() string {
 	switch vel {
 	case Error:
@@ -54,8 +76,17 @@ func (vel ValidationErrorLevel) String() string {
 }
 
 type ValidationError struct {
-	Level   ValidationErrorLevel `json:"level"`
-	Message string               `json:"message"`
+	Level   ValidationErrorLevel `json:"level" bson:"level"`
+	Message string               `json:"message" bson:"message"`
+	// Rule is the name of the validation rule that produced the result.
+	Rule string `json:"rule,omitempty" bson:"rule,omitempty"`
+	// Code identifies the kind of problem independently of the message's
+	// wording. It's one of the Code* constants.
+	Code string `json:"code,omitempty" bson:"code,omitempty"`
+	// Task and BuildVariant are the task and build variant that the result
+	// is about, if it's about a single one.
+	Task         string `json:"task,omitempty" bson:"task,omitempty"`
+	BuildVariant string `json:"build_variant,omitempty" bson:"build_variant,omitempty"`
 }
 
 type ValidationErrors []ValidationError
@@ -113,11 +144,21 @@ type ValidationInput struct {
 	Quiet       bool   `json:"quiet" yaml:"quiet"`
 	IncludeLong bool   `json:"include_long" yaml:"include_long"`
 	ProjectID   string `json:"project_id" yaml:"project_id"`
+
+	// Incremental returns only the results that changed since the config
+	// identified by PreviousConfigHash was validated.
+	Incremental        bool   `json:"incremental" yaml:"incremental"`
+	PreviousConfigHash string `json:"previous_config_hash" yaml:"previous_config_hash"`
+	// IncludeFull also returns the full results of an incremental
+	// validation.
+	IncludeFull bool `json:"include_full" yaml:"include_full"`
 }
 
 // Functions used to validate the syntax of a project configuration file.
 var projectErrorValidators = []projectValidator{
 	validateBVFields,
+	validateRunOnStrategies,
+	validateArtifactNamespaces,
 	validateDependencyGraph,
 	validatePluginCommands,
 	validateProjectFields,
@@ -125,6 +166,7 @@ var projectErrorValidators = []projectValidator{
 	validateTaskNames,
 	validateBVNames,
 	validateBVBatchTimes,
+	validateBVCanaries,
 	validateDisplayTaskNames,
 	validateBVTaskNames,
 	validateAllDependenciesSpec,
@@ -136,6 +178,12 @@ var projectErrorValidators = []projectValidator{
 	validateDuplicateBVTasks,
 	validateGenerateTasks,
 	validateAliases,
+	validateAllowedRequesters,
+	validateTaskOutputs,
+	validateTaskCompliance,
+	validateExpectedArtifacts,
+	validateExternalGates,
+	validateStages,
 }
 
 // Functions used to validate the syntax of project configs representing properties found on the project page.
@@ -154,12 +202,27 @@ var projectWarningValidators = []projectValidator{
 	checkModules,
 	checkTasks,
 	checkBuildVariants,
+	checkDuplicatedCommandBlocks,
+	checkAliasTags,
+	checkArtifactDestinations,
+	checkValidationSuppressions,
 }
 
 var projectSettingsValidators = []projectSettingsValidator{
 	validateTaskSyncSettings,
 	validateVersionControl,
 	validateContainers,
+	checkContainerCapacity,
+	validateVariantActivationHooks,
+	validateRestrictedVars,
+	checkQuarantinedDependencies,
+}
+
+// These validators compare the project against the history of its tasks, which
+// is too expensive to do every time a version is created, so they only run
+// when a project is explicitly validated.
+var projectHistoryValidators = []projectSettingsValidator{
+	checkExecTimeoutsAgainstRuntimes,
 }
 
 // These validators have the potential to be very long, and may not be fully run unless specified.
@@ -192,8 +255,9 @@ func getDistros() (ids []string, aliases []string, err error) {
 // all valid aliases for a project. If projectID is empty, it returns all distro
 // IDs and all aliases.
 func getDistrosForProject(projectID string) (ids []string, aliases []string, err error) {
-	// create a slice of all known distros
-	distros, err := distro.Find(distro.All)
+	// create a slice of all known distros, which are cached since they're
+	// looked up by every validation request but rarely change
+	distros, err := distro.FindAllCached()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -219,45 +283,73 @@ func getDistrosForProject(projectID string) (ids []string, aliases []string, err
 	return ids, aliases, nil
 }
 
-// verify that the project configuration semantics is valid
-func CheckProjectWarnings(project *model.Project) ValidationErrors {
+// verify that the project configuration semantics is valid. The project ref's
+// severity overrides, if it's given, and the project's validation
+// suppressions are applied to the results.
+func CheckProjectWarnings(ctx context.Context, project *model.Project, ref *model.ProjectRef) ValidationErrors {
+	ctx, span := startValidationSpan(ctx, "CheckProjectWarnings", project)
+	defer span.End()
+
 	validationErrs := ValidationErrors{}
 	for _, projectWarningValidator := range projectWarningValidators {
-		validationErrs = append(validationErrs,
-			projectWarningValidator(project)...)
+		validationErrs = append(validationErrs, traceValidator(ctx, projectWarningValidator, func() ValidationErrors {
+			return projectWarningValidator(project)
+		})...)
 	}
+	validationErrs = applySeverityOverrides(validationErrs, ref)
+	validationErrs = applyValidationSuppressions(validationErrs, project)
+	setValidationAttributes(span, validationErrs)
 	return validationErrs
 }
 
-// verify that the project configuration syntax is valid
-func CheckProjectErrors(project *model.Project, includeLong bool) ValidationErrors {
+// verify that the project configuration syntax is valid. The project ref's
+// severity overrides, if it's given, and the project's validation
+// suppressions are applied to the results.
+func CheckProjectErrors(ctx context.Context, project *model.Project, ref *model.ProjectRef, includeLong bool) ValidationErrors {
+	ctx, span := startValidationSpan(ctx, "CheckProjectErrors", project)
+	defer span.End()
+
 	validationErrs := ValidationErrors{}
 	for _, projectErrorValidator := range projectErrorValidators {
-		validationErrs = append(validationErrs,
-			projectErrorValidator(project)...)
+		validationErrs = append(validationErrs, traceValidator(ctx, projectErrorValidator, func() ValidationErrors {
+			return projectErrorValidator(project)
+		})...)
 	}
 	for _, longSyntaxValidator := range longErrorValidators {
-		validationErrs = append(validationErrs,
-			longSyntaxValidator(project, includeLong)...)
+		validationErrs = append(validationErrs, traceValidator(ctx, longSyntaxValidator, func() ValidationErrors {
+			return longSyntaxValidator(project, includeLong)
+		})...)
 	}
 
+	validationErrs = append(validationErrs, traceValidator(ctx, ensureReferentialIntegrity, func() ValidationErrors {
+		return validateReferentialIntegrity(project)
+	})...)
+	validationErrs = applySeverityOverrides(validationErrs, ref)
+	validationErrs = applyValidationSuppressions(validationErrs, project)
+	setValidationAttributes(span, validationErrs)
+	return validationErrs
+}
+
+// validateReferentialIntegrity checks that the project's containers are
+// unique and that everything the project refers to exists.
+func validateReferentialIntegrity(project *model.Project) ValidationErrors {
+	validationErrs := ValidationErrors{}
 	// get distro IDs and aliases for ensureReferentialIntegrity validation
 	distroIDs, distroAliases, err := getDistrosForProject(project.Identifier)
 	if err != nil {
-		validationErrs = append(validationErrs, ValidationError{Message: "can't get distros from database"})
+		validationErrs = append(validationErrs, ValidationError{Code: CodeDistroLookupFailed, Message: "can't get distros from database"})
 	}
 	containerNameMap := map[string]bool{}
 	for _, container := range project.Containers {
 		if containerNameMap[container.Name] {
-			validationErrs = append(validationErrs, ValidationError{Message: fmt.Sprintf("container '%s' is defined multiple times", container.Name)})
+			validationErrs = append(validationErrs, ValidationError{Code: CodeContainerDuplicateName, Message: fmt.Sprintf("container '%s' is defined multiple times", container.Name)})
 		}
 		containerNameMap[container.Name] = true
 	}
-	validationErrs = append(validationErrs, ensureReferentialIntegrity(project, containerNameMap, distroIDs, distroAliases)...)
-	return validationErrs
+	return append(validationErrs, ensureReferentialIntegrity(project, containerNameMap, distroIDs, distroAliases)...)
 }
 
-func CheckPatchedProjectConfigErrors(patchedProjectConfig string) ValidationErrors {
+func CheckPatchedProjectConfigErrors(ctx context.Context, patchedProjectConfig string) ValidationErrors {
 	validationErrs := ValidationErrors{}
 	if len(patchedProjectConfig) <= 0 {
 		return validationErrs
@@ -265,47 +357,85 @@ func CheckPatchedProjectConfigErrors(patchedProjectConfig string) ValidationErro
 	projectConfig, err := model.CreateProjectConfig([]byte(patchedProjectConfig), "")
 	if err != nil {
 		validationErrs = append(validationErrs, ValidationError{
+			Code:    CodePatchedConfigInvalid,
 			Message: fmt.Sprintf("Error unmarshalling patched project config: %s", err.Error()),
 		})
 		return validationErrs
 	}
-	return CheckProjectConfigErrors(projectConfig)
+	return CheckProjectConfigErrors(ctx, projectConfig)
 }
 
 // verify that the project configuration syntax is valid
-func CheckProjectConfigErrors(projectConfig *model.ProjectConfig) ValidationErrors {
+func CheckProjectConfigErrors(ctx context.Context, projectConfig *model.ProjectConfig) ValidationErrors {
 	validationErrs := ValidationErrors{}
 	if projectConfig == nil {
 		return validationErrs
 	}
+	ctx, span := startValidationSpan(ctx, "CheckProjectConfigErrors", nil)
+	defer span.End()
+
 	for _, projectConfigErrorValidator := range projectConfigErrorValidators {
-		validationErrs = append(validationErrs,
-			projectConfigErrorValidator(projectConfig)...)
+		validationErrs = append(validationErrs, traceValidator(ctx, projectConfigErrorValidator, func() ValidationErrors {
+			return projectConfigErrorValidator(projectConfig)
+		})...)
 	}
+	setValidationAttributes(span, validationErrs)
 	return validationErrs
 }
 
 // CheckProjectSettings checks the project configuration against the project
 // settings.
-func CheckProjectSettings(p *model.Project, ref *model.ProjectRef, isConfigDefined bool) ValidationErrors {
+func CheckProjectSettings(ctx context.Context, p *model.Project, ref *model.ProjectRef, isConfigDefined bool) ValidationErrors {
+	ctx, span := startValidationSpan(ctx, "CheckProjectSettings", p)
+	defer span.End()
+
 	var errs ValidationErrors
 	for _, validateSettings := range projectSettingsValidators {
-		errs = append(errs, validateSettings(p, ref, isConfigDefined)...)
-	}
+		errs = append(errs, traceValidator(ctx, validateSettings, func() ValidationErrors {
+			return validateSettings(p, ref, isConfigDefined)
+		})...)
+	}
+	// This isn't one of the settings validators because it checks the
+	// overrides against them.
+	errs = append(errs, traceValidator(ctx, checkValidationSeverityOverrides, func() ValidationErrors {
+		return checkValidationSeverityOverrides(p, ref, isConfigDefined)
+	})...)
+	errs = applySeverityOverrides(errs, ref)
+	errs = applyValidationSuppressions(errs, p)
+	setValidationAttributes(span, errs)
+	return errs
+}
+
+// CheckProjectHistory checks the project configuration against the recent
+// history of the project's tasks. It's only meant for explicit validation
+// requests, since it's too expensive to run whenever a version is created.
+func CheckProjectHistory(ctx context.Context, p *model.Project, ref *model.ProjectRef) ValidationErrors {
+	ctx, span := startValidationSpan(ctx, "CheckProjectHistory", p)
+	defer span.End()
+
+	var errs ValidationErrors
+	for _, validateHistory := range projectHistoryValidators {
+		errs = append(errs, traceValidator(ctx, validateHistory, func() ValidationErrors {
+			return validateHistory(p, ref, false)
+		})...)
+	}
+	errs = applySeverityOverrides(errs, ref)
+	errs = applyValidationSuppressions(errs, p)
+	setValidationAttributes(span, errs)
 	return errs
 }
 
 // checks if the project configuration has errors
-func CheckProjectConfigurationIsValid(project *model.Project, pref *model.ProjectRef) error {
+func CheckProjectConfigurationIsValid(ctx context.Context, project *model.Project, pref *model.ProjectRef) error {
 	catcher := grip.NewBasicCatcher()
-	projectErrors := CheckProjectErrors(project, false)
+	projectErrors := CheckProjectErrors(ctx, project, pref, false)
 	if len(projectErrors) != 0 {
 		if errs := projectErrors.AtLevel(Error); len(errs) != 0 {
 			catcher.Errorf("project contains errors: %s", ValidationErrorsToString(errs))
 		}
 	}
 
-	if settingsErrs := CheckProjectSettings(project, pref, false); len(settingsErrs) != 0 {
+	if settingsErrs := CheckProjectSettings(ctx, project, pref, false); len(settingsErrs) != 0 {
 		if errs := settingsErrs.AtLevel(Error); len(errs) != 0 {
 			catcher.Errorf("project contains errors related to project settings: %s", ValidationErrorsToString(errs))
 		}
@@ -326,6 +456,7 @@ func validateAllDependenciesSpec(project *model.Project) ValidationErrors {
 					if dependency.Variant == "" || coveredVariants[dependency.Variant] {
 						errs = append(errs,
 							ValidationError{
+								Code: CodeTaskAllDependenciesWithOthers,
 								Message: fmt.Sprintf("task '%s' contains the all dependencies (%s)' "+
 									"specification and other explicit dependencies or duplicate variants",
 									task.Name, model.AllDependencies),
@@ -346,6 +477,7 @@ func validateContainers(project *model.Project, ref *model.ProjectRef, _ bool) V
 	if err != nil {
 		errs = append(errs,
 			ValidationError{
+				Code:    CodeContainerInvalid,
 				Message: errors.Wrap(err, "error validating containers").Error(),
 				Level:   Error,
 			},
@@ -364,6 +496,7 @@ func validateDependencyGraph(project *model.Project) ValidationErrors {
 			nodeStrings = append(nodeStrings, task.String())
 		}
 		errs = append(errs, ValidationError{
+			Code:    CodeTaskDependencyCycle,
 			Level:   Error,
 			Message: fmt.Sprintf("tasks [%s] form a dependency cycle", strings.Join(nodeStrings, ", ")),
 		})
@@ -411,9 +544,15 @@ func validateProjectConfigPeriodicBuilds(pc *model.ProjectConfig) ValidationErro
 	for _, periodicBuild := range pc.PeriodicBuilds {
 		if err := periodicBuild.Validate(); err != nil {
 			validationErrs = append(validationErrs, ValidationError{
+				Code:    CodePeriodicBuildInvalid,
 				Message: errors.Wrap(err, "error validating periodic builds").Error(),
 				Level:   Error,
 			})
+			continue
+		}
+		if periodicBuild.Cron != "" {
+			validationErrs = append(validationErrs, validateCronTimezone(periodicBuild.Cron, periodicBuild.Timezone,
+				fmt.Sprintf("periodic build '%s'", periodicBuild.ID))...)
 		}
 	}
 	return validationErrs
@@ -430,6 +569,7 @@ func validateProjectConfigAliases(pc *model.ProjectConfig) ValidationErrors {
 	validationErrs := ValidationErrors{}
 	for _, errorMsg := range errs {
 		validationErrs = append(validationErrs, ValidationError{
+			Code:    CodeAliasInvalid,
 			Message: fmt.Sprintf("error validating aliases: %s", errorMsg),
 			Level:   Error,
 		})
@@ -442,6 +582,7 @@ func validateProjectConfigContainers(pc *model.ProjectConfig) ValidationErrors {
 	for name, containerResource := range pc.ContainerSizes {
 		if name == "" {
 			errs = append(errs, ValidationError{
+				Code:    CodeContainerSizeMissingName,
 				Message: "container size name cannot be empty",
 				Level:   Error,
 			})
@@ -450,10 +591,126 @@ func validateProjectConfigContainers(pc *model.ProjectConfig) ValidationErrors {
 		if err := containerResource.Validate(); err != nil {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeContainerSizeInvalid,
 					Message: errors.Wrap(err, "error validating container resources").Error(),
 					Level:   Error,
 				},
 			)
+			continue
+		}
+		errs = append(errs, checkContainerResourcesFitPod(fmt.Sprintf("container size '%s'", name), containerResource, getECSConfig())...)
+	}
+	return errs
+}
+
+// getECSConfig returns the admin settings for the container infrastructure,
+// or nil if they're not available.
+func getECSConfig() *evergreen.ECSConfig {
+	env := evergreen.GetEnvironment()
+	if env == nil || env.Settings() == nil {
+		return nil
+	}
+	return &env.Settings().Providers.AWS.Pod.ECS
+}
+
+// checkContainerResourcesFitPod warns if the container resources are more
+// than a single pod can use, since the container could never be scheduled.
+func checkContainerResourcesFitPod(name string, resources model.ContainerResources, ecsConf *evergreen.ECSConfig) ValidationErrors {
+	if ecsConf == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if ecsConf.MaxCPU > 0 && resources.CPU > ecsConf.MaxCPU {
+		errs = append(errs, ValidationError{
+			Code:    CodeContainerCPUExceedsPod,
+			Level:   Warning,
+			Message: fmt.Sprintf("%s requests %d CPU units, but pods can use at most %d", name, resources.CPU, ecsConf.MaxCPU),
+		})
+	}
+	if ecsConf.MaxMemoryMB > 0 && resources.MemoryMB > ecsConf.MaxMemoryMB {
+		errs = append(errs, ValidationError{
+			Code:    CodeContainerMemoryExceedsPod,
+			Level:   Warning,
+			Message: fmt.Sprintf("%s requests %d MB of memory, but pods can use at most %d MB", name, resources.MemoryMB, ecsConf.MaxMemoryMB),
+		})
+	}
+	return errs
+}
+
+// checkContainerCapacity checks the resources that the project's containers
+// request against the capacity of the container infrastructure. It warns if a
+// container defines resources that are more than a single pod can use, or if
+// a build variant's container tasks would need more resources to all run at
+// once than the whole pool has. Container sizes are checked along with the
+// rest of the project config.
+func checkContainerCapacity(p *model.Project, ref *model.ProjectRef, _ bool) ValidationErrors {
+	ecsConf := getECSConfig()
+	if ecsConf == nil || len(p.Containers) == 0 {
+		return nil
+	}
+
+	var errs ValidationErrors
+	containerResources := map[string]model.ContainerResources{}
+	for _, c := range p.Containers {
+		if c.Resources != nil {
+			containerResources[c.Name] = *c.Resources
+			errs = append(errs, checkContainerResourcesFitPod(fmt.Sprintf("container '%s'", c.Name), *c.Resources, ecsConf)...)
+			continue
+		}
+		if ref == nil {
+			continue
+		}
+		if size, ok := ref.ContainerSizes[c.Size]; ok {
+			containerResources[c.Name] = size
+		}
+	}
+
+	if ecsConf.PoolCPU <= 0 && ecsConf.PoolMemoryMB <= 0 {
+		return errs
+	}
+	for _, bv := range p.BuildVariants {
+		var numTasks int
+		var demand model.ContainerResources
+		for _, bvtu := range bv.Tasks {
+			taskUnits := []model.BuildVariantTaskUnit{bvtu}
+			if bvtu.IsGroup {
+				taskUnits = model.CreateTasksFromGroup(bvtu, p, "")
+			}
+			for _, tu := range taskUnits {
+				if tu.IsDisabled() {
+					continue
+				}
+				runOn := tu.RunOn
+				if len(runOn) == 0 {
+					runOn = bv.RunOn
+				}
+				if len(runOn) == 0 {
+					continue
+				}
+				resources, ok := containerResources[runOn[0]]
+				if !ok {
+					continue
+				}
+				numTasks++
+				demand.CPU += resources.CPU
+				demand.MemoryMB += resources.MemoryMB
+			}
+		}
+		if ecsConf.PoolCPU > 0 && demand.CPU > ecsConf.PoolCPU {
+			errs = append(errs, ValidationError{
+				Code:         CodeBVContainerCPUExceedsPool,
+				Level:        Warning,
+				BuildVariant: bv.Name,
+				Message:      fmt.Sprintf("build variant '%s' has %d container tasks that together request %d CPU units, but the container pool only has %d, so they cannot all run at once", bv.Name, numTasks, demand.CPU, ecsConf.PoolCPU),
+			})
+		}
+		if ecsConf.PoolMemoryMB > 0 && demand.MemoryMB > ecsConf.PoolMemoryMB {
+			errs = append(errs, ValidationError{
+				Code:         CodeBVContainerMemoryExceedsPool,
+				Level:        Warning,
+				BuildVariant: bv.Name,
+				Message:      fmt.Sprintf("build variant '%s' has %d container tasks that together request %d MB of memory, but the container pool only has %d MB, so they cannot all run at once", bv.Name, numTasks, demand.MemoryMB, ecsConf.PoolMemoryMB),
+			})
 		}
 	}
 	return errs
@@ -470,6 +727,7 @@ func validateProjectConfigPlugins(pc *model.ProjectConfig) ValidationErrors {
 	if err != nil {
 		errs = append(errs,
 			ValidationError{
+				Code:    CodeBuildBaronInvalid,
 				Message: errors.Wrap(err, "error validating build baron config").Error(),
 				Level:   Error,
 			},
@@ -489,6 +747,7 @@ func validateAliases(p *model.Project) ValidationErrors {
 	validationErrs := ValidationErrors{}
 	for _, errorMsg := range errs {
 		validationErrs = append(validationErrs, ValidationError{
+			Code:    CodeAliasInvalid,
 			Message: errorMsg,
 			Level:   Error,
 		})
@@ -512,6 +771,7 @@ func validateBVFields(project *model.Project) ValidationErrors {
 		if buildVariant.Name == "" {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeBVMissingName,
 					Message: "all buildvariants must have a name",
 				},
 			)
@@ -519,6 +779,7 @@ func validateBVFields(project *model.Project) ValidationErrors {
 		if len(buildVariant.Tasks) == 0 {
 			errs = append(errs,
 				ValidationError{
+					Code: CodeBVNoTasks,
 					Message: fmt.Sprintf("buildvariant '%s' must have at least one task",
 						buildVariant.Name),
 				},
@@ -565,6 +826,7 @@ func validateBVFields(project *model.Project) ValidationErrors {
 		if hasTaskWithoutDistro {
 			errs = append(errs,
 				ValidationError{
+					Code: CodeBVMissingRunOn,
 					Message: fmt.Sprintf("buildvariant '%s' "+
 						"must either specify run_on field or have every task specify run_on",
 						buildVariant.Name),
@@ -575,6 +837,54 @@ func validateBVFields(project *model.Project) ValidationErrors {
 	return errs
 }
 
+// validateRunOnStrategies checks that the strategies for selecting a distro
+// from run_on are valid.
+func validateRunOnStrategies(project *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	for _, bv := range project.BuildVariants {
+		if bv.RunOnStrategy != "" && !utility.StringSliceContains(model.ValidRunOnStrategies, bv.RunOnStrategy) {
+			errs = append(errs, ValidationError{
+				Code: CodeBVInvalidRunOnStrategy,
+				Message: fmt.Sprintf("buildvariant '%s' has invalid run_on strategy '%s', must be one of: %s",
+					bv.Name, bv.RunOnStrategy, strings.Join(model.ValidRunOnStrategies, ", ")),
+				Level: Error,
+			})
+		}
+		for _, bvt := range bv.Tasks {
+			if bvt.RunOnStrategy != "" && !utility.StringSliceContains(model.ValidRunOnStrategies, bvt.RunOnStrategy) {
+				errs = append(errs, ValidationError{
+					Code: CodeTaskInvalidRunOnStrategy,
+					Message: fmt.Sprintf("task '%s' in buildvariant '%s' has invalid run_on strategy '%s', must be one of: %s",
+						bvt.Name, bv.Name, bvt.RunOnStrategy, strings.Join(model.ValidRunOnStrategies, ", ")),
+					Level: Error,
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// validateArtifactNamespaces checks that build variants' artifact namespaces
+// can be used as a prefix for artifact upload destinations.
+func validateArtifactNamespaces(project *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	for _, bv := range project.BuildVariants {
+		ns := bv.ArtifactNamespace
+		if ns == "" {
+			continue
+		}
+		if strings.IndexFunc(ns, unicode.IsSpace) >= 0 || strings.HasPrefix(ns, "/") || strings.HasSuffix(ns, "/") {
+			errs = append(errs, ValidationError{
+				Code: CodeBVInvalidArtifactNamespace,
+				Message: fmt.Sprintf("buildvariant '%s' has invalid artifact namespace '%s', must not contain whitespace or begin or end with '/'",
+					bv.Name, ns),
+				Level: Error,
+			})
+		}
+	}
+	return errs
+}
+
 // Checks that the basic fields that are required by any project are present and
 // valid.
 func validateProjectFields(project *model.Project) ValidationErrors {
@@ -583,6 +893,7 @@ func validateProjectFields(project *model.Project) ValidationErrors {
 	if project.BatchTime < 0 {
 		errs = append(errs,
 			ValidationError{
+				Code:    CodeProjectNegativeBatchTime,
 				Message: "'batchtime' must be non-negative",
 			},
 		)
@@ -592,6 +903,7 @@ func validateProjectFields(project *model.Project) ValidationErrors {
 		if !utility.StringSliceContains(evergreen.ValidCommandTypes, project.CommandType) {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeProjectInvalidCommandType,
 					Message: fmt.Sprintf("invalid command type: %s", project.CommandType),
 				},
 			)
@@ -609,6 +921,7 @@ func checkProjectFields(project *model.Project) ValidationErrors {
 		// in ProjectRef.getBatchTime()
 		errs = append(errs,
 			ValidationError{
+				Code:    CodeProjectBatchTimeTooLarge,
 				Message: fmt.Sprintf("'batchtime' should not exceed %d", math.MaxInt32),
 				Level:   Warning,
 			},
@@ -641,6 +954,7 @@ func ensureReferentialIntegrity(project *model.Project, containerNameMap map[str
 				if task.Name == "" {
 					errs = append(errs,
 						ValidationError{
+							Code: CodeBVTaskMissingName,
 							Message: fmt.Sprintf("tasks for buildvariant '%s' must each have a name field",
 								buildVariant.Name),
 							Level: Error,
@@ -649,6 +963,7 @@ func ensureReferentialIntegrity(project *model.Project, containerNameMap map[str
 				} else {
 					errs = append(errs,
 						ValidationError{
+							Code: CodeBVUndefinedTask,
 							Message: fmt.Sprintf("buildvariant '%s' references a non-existent task '%s'",
 								buildVariant.Name, task.Name),
 							Level: Error,
@@ -661,9 +976,12 @@ func ensureReferentialIntegrity(project *model.Project, containerNameMap map[str
 			if _, ok := taskGroupTaskSet[task.Name]; ok {
 				errs = append(errs,
 					ValidationError{
+						Code: CodeBVTaskInTaskGroup,
 						Message: fmt.Sprintf("task '%s' in build variant '%s' is already referenced in task group '%s'",
 							task.Name, buildVariant.Name, taskGroupTaskSet[task.Name]),
-						Level: Warning,
+						Level:        Warning,
+						Task:         task.Name,
+						BuildVariant: buildVariant.Name,
 					})
 			}
 			runOnHasDistro := false
@@ -672,19 +990,25 @@ func ensureReferentialIntegrity(project *model.Project, containerNameMap map[str
 				if !utility.StringSliceContains(distroIDs, name) && !utility.StringSliceContains(distroAliases, name) && !containerNameMap[name] {
 					errs = append(errs,
 						ValidationError{
+							Code: CodeTaskUndefinedRunOn,
 							Message: fmt.Sprintf("task '%s' in buildvariant '%s' references a nonexistent distro or container named '%s'",
 								task.Name, buildVariant.Name, name),
-							Level: Warning,
+							Level:        Warning,
+							Task:         task.Name,
+							BuildVariant: buildVariant.Name,
 						},
 					)
 				} else if utility.StringSliceContains(distroIDs, name) && containerNameMap[name] {
 					errs = append(errs,
 						ValidationError{
+							Code: CodeTaskContainerOverridesDistro,
 							Message: fmt.Sprintf("task '%s' in buildvariant '%s' "+
 								"references a container name overlapping with an existing distro '%s', the container "+
 								"configuration will override the distro",
 								task.Name, buildVariant.Name, name),
-							Level: Warning,
+							Level:        Warning,
+							Task:         task.Name,
+							BuildVariant: buildVariant.Name,
 						},
 					)
 				}
@@ -695,7 +1019,8 @@ func ensureReferentialIntegrity(project *model.Project, containerNameMap map[str
 					runOnHasContainer = true
 				}
 			}
-			errs = append(errs, checkRunOn(runOnHasDistro, runOnHasContainer, task.RunOn)...)
+			errs = append(errs, aboutVariant(buildVariant.Name, aboutTask(task.Name, checkRunOn(runOnHasDistro, runOnHasContainer, task.RunOn)))...)
+			errs = append(errs, aboutVariant(buildVariant.Name, aboutTask(task.Name, checkContainerFallback(task, buildVariant, containerNameMap, distroIDs, distroAliases)))...)
 		}
 		runOnHasDistro := false
 		runOnHasContainer := false
@@ -703,19 +1028,23 @@ func ensureReferentialIntegrity(project *model.Project, containerNameMap map[str
 			if !utility.StringSliceContains(distroIDs, name) && !utility.StringSliceContains(distroAliases, name) && !containerNameMap[name] {
 				errs = append(errs,
 					ValidationError{
+						Code: CodeBVUndefinedRunOn,
 						Message: fmt.Sprintf("buildvariant '%s' references a nonexistent distro or container named '%s'",
 							buildVariant.Name, name),
-						Level: Warning,
+						Level:        Warning,
+						BuildVariant: buildVariant.Name,
 					},
 				)
 			} else if utility.StringSliceContains(distroIDs, name) && containerNameMap[name] {
 				errs = append(errs,
 					ValidationError{
+						Code: CodeBVContainerOverridesDistro,
 						Message: fmt.Sprintf("buildvariant '%s' "+
 							"references a container name overlapping with an existing distro '%s', the container "+
 							"configuration will override the distro",
 							buildVariant.Name, name),
-						Level: Warning,
+						Level:        Warning,
+						BuildVariant: buildVariant.Name,
 					},
 				)
 			}
@@ -726,7 +1055,7 @@ func ensureReferentialIntegrity(project *model.Project, containerNameMap map[str
 				runOnHasContainer = true
 			}
 		}
-		errs = append(errs, checkRunOn(runOnHasDistro, runOnHasContainer, buildVariant.RunOn)...)
+		errs = append(errs, aboutVariant(buildVariant.Name, checkRunOn(runOnHasDistro, runOnHasContainer, buildVariant.RunOn))...)
 	}
 	return errs
 }
@@ -734,12 +1063,14 @@ func ensureReferentialIntegrity(project *model.Project, containerNameMap map[str
 func checkRunOn(runOnHasDistro, runOnHasContainer bool, runOn []string) []ValidationError {
 	if runOnHasContainer && runOnHasDistro {
 		return []ValidationError{{
-			Message: "run_on cannot contain a mixture of containers and distros",
+			Code:    CodeRunOnMixesContainersAndDistros,
+			Message: "run_on cannot contain a mixture of containers and distros; to run on a distro when no container is available, use container_fallback instead",
 			Level:   Error,
 		}}
 
 	} else if runOnHasContainer && len(runOn) > 1 {
 		return []ValidationError{{
+			Code:    CodeRunOnMultipleContainers,
 			Message: "only one container can be used from run_on; the first container in the list will be used",
 			Level:   Warning,
 		}}
@@ -747,6 +1078,60 @@ func checkRunOn(runOnHasDistro, runOnHasContainer bool, runOn []string) []Valida
 	return nil
 }
 
+// checkContainerFallback checks that a task that falls back from a container
+// to a distro runs in an existing container and falls back to an existing
+// distro.
+func checkContainerFallback(bvt model.BuildVariantTaskUnit, bv model.BuildVariant, containerNameMap map[string]bool, distroIDs []string, distroAliases []string) ValidationErrors {
+	fallback := bvt.ContainerFallback
+	if fallback == nil {
+		return nil
+	}
+	errs := ValidationErrors{}
+	runOn := bvt.RunOn
+	if len(runOn) == 0 {
+		runOn = bv.RunOn
+	}
+	if len(runOn) == 0 || !containerNameMap[runOn[0]] {
+		errs = append(errs, ValidationError{
+			Code: CodeContainerFallbackWithoutContainer,
+			Message: fmt.Sprintf("task '%s' in buildvariant '%s' has a container fallback but does not run in a container",
+				bvt.Name, bv.Name),
+			Level: Error,
+		})
+	}
+	if fallback.Distro == "" {
+		errs = append(errs, ValidationError{
+			Code: CodeContainerFallbackMissingDistro,
+			Message: fmt.Sprintf("task '%s' in buildvariant '%s' must specify a distro to fall back to",
+				bvt.Name, bv.Name),
+			Level: Error,
+		})
+	} else if containerNameMap[fallback.Distro] {
+		errs = append(errs, ValidationError{
+			Code: CodeContainerFallbackToContainer,
+			Message: fmt.Sprintf("task '%s' in buildvariant '%s' cannot fall back to container '%s'; the fallback must be a distro",
+				bvt.Name, bv.Name, fallback.Distro),
+			Level: Error,
+		})
+	} else if !utility.StringSliceContains(distroIDs, fallback.Distro) && !utility.StringSliceContains(distroAliases, fallback.Distro) {
+		errs = append(errs, ValidationError{
+			Code: CodeContainerFallbackUndefinedDistro,
+			Message: fmt.Sprintf("task '%s' in buildvariant '%s' falls back to nonexistent distro '%s'",
+				bvt.Name, bv.Name, fallback.Distro),
+			Level: Error,
+		})
+	}
+	if fallback.AfterMins < 0 {
+		errs = append(errs, ValidationError{
+			Code: CodeContainerFallbackNegativeWait,
+			Message: fmt.Sprintf("task '%s' in buildvariant '%s' cannot wait a negative number of minutes before falling back",
+				bvt.Name, bv.Name),
+			Level: Error,
+		})
+	}
+	return errs
+}
+
 // validateTaskNames ensures the task names do not contain unauthorized characters.
 func validateTaskNames(project *model.Project) ValidationErrors {
 	unauthorizedTaskCharacters := unauthorizedCharacters + " "
@@ -755,6 +1140,7 @@ func validateTaskNames(project *model.Project) ValidationErrors {
 		if strings.ContainsAny(strings.TrimSpace(task.Name), unauthorizedTaskCharacters) {
 			errs = append(errs,
 				ValidationError{
+					Code: CodeTaskInvalidName,
 					Message: fmt.Sprintf("task name '%s' contains unauthorized characters ('%s')",
 						task.Name, unauthorizedTaskCharacters),
 				})
@@ -769,6 +1155,7 @@ func checkTaskNames(project *model.Project, task *model.ProjectTask) ValidationE
 	// tasks separated by commas in their patches.
 	if strings.Contains(task.Name, ",") {
 		errs = append(errs, ValidationError{
+			Code:    CodeTaskNameHasComma,
 			Level:   Warning,
 			Message: fmt.Sprintf("task name '%s' should not contain commas", task.Name),
 		})
@@ -777,6 +1164,7 @@ func checkTaskNames(project *model.Project, task *model.ProjectTask) ValidationE
 	// all-dependencies specification (also "*").
 	if task.Name == model.AllDependencies {
 		errs = append(errs, ValidationError{
+			Code:    CodeTaskNameAmbiguous,
 			Level:   Warning,
 			Message: "task should not be named '*' because it is ambiguous with the all-dependencies '*' specification",
 		})
@@ -785,6 +1173,7 @@ func checkTaskNames(project *model.Project, task *model.ProjectTask) ValidationE
 	// task specifier when creating patches.
 	if task.Name == "all" {
 		errs = append(errs, ValidationError{
+			Code:    CodeTaskNameAmbiguous,
 			Level:   Warning,
 			Message: "task should not be named 'all' because it is ambiguous in task specifications for patches",
 		})
@@ -801,11 +1190,13 @@ func checkModules(project *model.Project) ValidationErrors {
 		// Warn if name is a duplicate or empty
 		if module.Name == "" {
 			errs = append(errs, ValidationError{
+				Code:    CodeModuleMissingName,
 				Level:   Warning,
 				Message: "module cannot have an empty name",
 			})
 		} else if moduleNames[module.Name] {
 			errs = append(errs, ValidationError{
+				Code:    CodeModuleDuplicateName,
 				Level:   Warning,
 				Message: fmt.Sprintf("module '%s' already exists; the first module name defined will be used", module.Name),
 			})
@@ -816,6 +1207,7 @@ func checkModules(project *model.Project) ValidationErrors {
 		// Warn if branch is empty
 		if module.Branch == "" {
 			errs = append(errs, ValidationError{
+				Code:    CodeModuleMissingBranch,
 				Level:   Warning,
 				Message: fmt.Sprintf("module '%s' should have a set branch", module.Name),
 			})
@@ -825,11 +1217,13 @@ func checkModules(project *model.Project) ValidationErrors {
 		owner, repo, err := thirdparty.ParseGitUrl(module.Repo)
 		if err != nil {
 			errs = append(errs, ValidationError{
+				Code:    CodeModuleInvalidRepo,
 				Level:   Warning,
 				Message: errors.Wrapf(err, "module '%s' does not have a valid repo URL format", module.Name).Error(),
 			})
 		} else if owner == "" || repo == "" {
 			errs = append(errs, ValidationError{
+				Code:    CodeModuleInvalidRepo,
 				Level:   Warning,
 				Message: fmt.Sprintf("module '%s' repo '%s' is missing an owner or repo name", module.Name, module.Repo),
 			})
@@ -849,6 +1243,7 @@ func validateBVNames(project *model.Project) ValidationErrors {
 		if _, ok := buildVariantNames[buildVariant.Name]; ok {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeBVDuplicateName,
 					Message: fmt.Sprintf("buildvariant '%s' already exists", buildVariant.Name),
 				},
 			)
@@ -858,11 +1253,13 @@ func validateBVNames(project *model.Project) ValidationErrors {
 		if dispName == "" {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeBVMissingDisplayName,
 					Message: fmt.Sprintf("buildvariant '%s' does not have a display name", buildVariant.Name),
 				},
 			)
 		} else if dispName == evergreen.MergeTaskVariant {
 			errs = append(errs, ValidationError{
+				Code:    CodeBVReservedName,
 				Message: fmt.Sprintf("the variant name '%s' is reserved for the commit queue", evergreen.MergeTaskVariant),
 			})
 		}
@@ -870,6 +1267,7 @@ func validateBVNames(project *model.Project) ValidationErrors {
 		if strings.ContainsAny(buildVariant.Name, unauthorizedCharacters) {
 			errs = append(errs,
 				ValidationError{
+					Code: CodeBVInvalidName,
 					Message: fmt.Sprintf("buildvariant name '%s' contains unauthorized characters (%s)",
 						buildVariant.Name, unauthorizedCharacters),
 				})
@@ -887,6 +1285,7 @@ func checkBVNames(buildVariant *model.BuildVariant) ValidationErrors {
 	// variants separated by commas in their patches.
 	if strings.Contains(buildVariant.Name, ",") {
 		errs = append(errs, ValidationError{
+			Code:    CodeBVNameHasComma,
 			Level:   Warning,
 			Message: fmt.Sprintf("buildvariant name '%s' should not contains commas", buildVariant.Name),
 		})
@@ -895,6 +1294,7 @@ func checkBVNames(buildVariant *model.BuildVariant) ValidationErrors {
 	// all-dependencies specification (also "*").
 	if buildVariant.Name == model.AllVariants {
 		errs = append(errs, ValidationError{
+			Code:    CodeBVNameAmbiguous,
 			Level:   Warning,
 			Message: "buildvariant should not be named '*' because it is ambiguous with the all-variants '*' specification",
 		})
@@ -903,6 +1303,7 @@ func checkBVNames(buildVariant *model.BuildVariant) ValidationErrors {
 	// task specifier when creating patches.
 	if buildVariant.Name == "all" {
 		errs = append(errs, ValidationError{
+			Code:    CodeBVNameAmbiguous,
 			Level:   Warning,
 			Message: "buildvariant should not be named 'all' because it is ambiguous in buildvariant specifications for patches",
 		})
@@ -917,6 +1318,7 @@ func checkLoggerConfig(task *model.ProjectTask) ValidationErrors {
 	for _, command := range task.Commands {
 		if err := command.Loggers.IsValid(); err != nil {
 			errs = append(errs, ValidationError{
+				Code:    CodeLoggerConfigInvalid,
 				Message: errors.Wrapf(err, "error in logger config for command %s in task %s", command.DisplayName, task.Name).Error(),
 				Level:   Warning,
 			})
@@ -936,6 +1338,7 @@ func validateBVTaskNames(project *model.Project) ValidationErrors {
 			if _, ok := buildVariantTasks[task.Name]; ok {
 				errs = append(errs,
 					ValidationError{
+						Code: CodeBVDuplicateTask,
 						Message: fmt.Sprintf("task '%s' in buildvariant '%s' already exists",
 							task.Name, buildVariant.Name),
 					},
@@ -954,12 +1357,23 @@ func validateBVBatchTimes(project *model.Project) ValidationErrors {
 		for _, t := range buildVariant.Tasks {
 
 			if t.CronBatchTime == "" {
+				if t.CronTimezone != "" {
+					errs = append(errs,
+						ValidationError{
+							Code:         CodeTimezoneWithoutCron,
+							Message:      fmt.Sprintf("task '%s' for variant '%s' time zone ignored since no cron is specified", t.Name, buildVariant.Name),
+							Level:        Warning,
+							Task:         t.Name,
+							BuildVariant: buildVariant.Name,
+						})
+				}
 				continue
 			}
 			// otherwise, cron batchtime is set
 			if t.BatchTime != nil {
 				errs = append(errs,
 					ValidationError{
+						Code:    CodeCronAndBatchTime,
 						Message: fmt.Sprintf("task '%s' cannot specify cron and batchtime for variant '%s'", t.Name, buildVariant.Name),
 						Level:   Error,
 					})
@@ -967,20 +1381,34 @@ func validateBVBatchTimes(project *model.Project) ValidationErrors {
 			if _, err := model.GetActivationTimeWithCron(time.Now(), t.CronBatchTime); err != nil {
 				errs = append(errs,
 					ValidationError{
+						Code: CodeCronInvalid,
 						Message: errors.Wrapf(err, "task cron batchtime '%s' has invalid syntax for task '%s' for build variant '%s'",
 							t.CronBatchTime, t.Name, buildVariant.Name).Error(),
 						Level: Error,
 					},
 				)
+				continue
 			}
+			errs = append(errs, aboutVariant(buildVariant.Name, aboutTask(t.Name, validateCronTimezone(t.CronBatchTime, t.CronTimezone,
+				fmt.Sprintf("task '%s' for build variant '%s'", t.Name, buildVariant.Name))))...)
 		}
 
 		if buildVariant.CronBatchTime == "" {
+			if buildVariant.CronTimezone != "" {
+				errs = append(errs,
+					ValidationError{
+						Code:         CodeTimezoneWithoutCron,
+						Message:      fmt.Sprintf("variant '%s' time zone ignored since no cron is specified", buildVariant.Name),
+						Level:        Warning,
+						BuildVariant: buildVariant.Name,
+					})
+			}
 			continue
 		}
 		if buildVariant.BatchTime != nil {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeCronAndBatchTime,
 					Message: fmt.Sprintf("variant '%s' cannot specify cron and batchtime", buildVariant.Name),
 					Level:   Error,
 				})
@@ -988,15 +1416,104 @@ func validateBVBatchTimes(project *model.Project) ValidationErrors {
 		if _, err := model.GetActivationTimeWithCron(time.Now(), buildVariant.CronBatchTime); err != nil {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeCronInvalid,
 					Message: errors.Wrapf(err, "cron batchtime '%s' has invalid syntax", buildVariant.CronBatchTime).Error(),
 					Level:   Error,
 				},
 			)
+			continue
+		}
+		errs = append(errs, aboutVariant(buildVariant.Name, validateCronTimezone(buildVariant.CronBatchTime, buildVariant.CronTimezone,
+			fmt.Sprintf("build variant '%s'", buildVariant.Name)))...)
+	}
+	return errs
+}
+
+// validateBVCanaries checks that each variant's canary is well-formed and
+// warns if the canary is combined with other activation settings in ways that
+// make it hard to predict which versions the variant runs on.
+func validateBVCanaries(project *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	for _, buildVariant := range project.BuildVariants {
+		canary := buildVariant.Canary
+		if canary == nil {
+			continue
+		}
+		if err := canary.Validate(); err != nil {
+			errs = append(errs, ValidationError{
+				Code:    CodeBVCanaryInvalid,
+				Message: errors.Wrapf(err, "invalid canary for variant '%s'", buildVariant.Name).Error(),
+				Level:   Error,
+			})
+			continue
+		}
+		if !utility.FromBoolTPtr(buildVariant.Activate) {
+			errs = append(errs, ValidationError{
+				Code:         CodeBVCanaryIgnored,
+				Message:      fmt.Sprintf("variant '%s' canary ignored since the variant is never activated automatically", buildVariant.Name),
+				Level:        Warning,
+				BuildVariant: buildVariant.Name,
+			})
+			continue
+		}
+		if buildVariant.BatchTime != nil && canary.Every > 0 {
+			errs = append(errs, ValidationError{
+				Code: CodeBVCanaryRarelyActivates,
+				Message: fmt.Sprintf("variant '%s' only activates on one in every %d versions that are also at least %d minutes after the last activation, "+
+					"so it may run much less often than either the canary or batchtime suggests", buildVariant.Name, canary.Every, *buildVariant.BatchTime),
+				Level:        Warning,
+				BuildVariant: buildVariant.Name,
+			})
+		}
+		if buildVariant.CronBatchTime != "" && canary.HasHours() {
+			errs = append(errs, ValidationError{
+				Code: CodeBVCanaryHoursWithCron,
+				Message: fmt.Sprintf("variant '%s' canary hours are checked when each version is created but the cron decides when it activates, "+
+					"so sampled versions may activate outside of the canary hours", buildVariant.Name),
+				Level:        Warning,
+				BuildVariant: buildVariant.Name,
+			})
+		}
+		for _, t := range buildVariant.Tasks {
+			if t.BatchTime != nil || t.CronBatchTime != "" {
+				errs = append(errs, ValidationError{
+					Code:         CodeTaskBatchTimeOnlyInCanary,
+					Message:      fmt.Sprintf("task '%s' for variant '%s' batchtime only applies to versions in the variant's canary sample", t.Name, buildVariant.Name),
+					Level:        Warning,
+					Task:         t.Name,
+					BuildVariant: buildVariant.Name,
+				})
+			}
 		}
 	}
 	return errs
 }
 
+// validateCronTimezone checks that a valid cron's time zone exists and warns
+// if the cron fires at a local time that's skipped or repeated when daylight
+// saving time begins or ends in that time zone.
+func validateCronTimezone(cronBatchTime, timezone, owner string) ValidationErrors {
+	if timezone == "" {
+		return nil
+	}
+	conflicts, err := model.GetCronDSTConflicts(time.Now(), cronBatchTime, timezone)
+	if err != nil {
+		return ValidationErrors{{
+			Message: errors.Wrapf(err, "invalid cron time zone for %s", owner).Error(),
+			Level:   Error,
+		}}
+	}
+	errs := ValidationErrors{}
+	for _, conflict := range conflicts {
+		errs = append(errs, ValidationError{
+			Code:    CodeCronTimezoneConflict,
+			Message: fmt.Sprintf("%s: %s", owner, conflict),
+			Level:   Warning,
+		})
+	}
+	return errs
+}
+
 func checkBVBatchTimes(buildVariant *model.BuildVariant) ValidationErrors {
 	errs := ValidationErrors{}
 	// check task batchtimes first
@@ -1005,6 +1522,7 @@ func checkBVBatchTimes(buildVariant *model.BuildVariant) ValidationErrors {
 		if utility.FromBoolPtr(t.Activate) && (t.CronBatchTime != "" || t.BatchTime != nil) {
 			errs = append(errs,
 				ValidationError{
+					Code: CodeActivationIgnored,
 					Message: fmt.Sprintf("task '%s' for variant '%s' activation ignored since batchtime specified",
 						t.Name, buildVariant.Name),
 					Level: Warning,
@@ -1016,6 +1534,7 @@ func checkBVBatchTimes(buildVariant *model.BuildVariant) ValidationErrors {
 	if utility.FromBoolPtr(buildVariant.Activate) && (buildVariant.CronBatchTime != "" || buildVariant.BatchTime != nil) {
 		errs = append(errs,
 			ValidationError{
+				Code:    CodeActivationIgnored,
 				Message: fmt.Sprintf("variant '%s' activation ignored since batchtime specified", buildVariant.Name),
 				Level:   Warning,
 			})
@@ -1040,6 +1559,7 @@ func validateDisplayTaskNames(project *model.Project) ValidationErrors {
 				if strings.HasPrefix(etn, "display_") {
 					errs = append(errs,
 						ValidationError{
+							Code:    CodeDisplayTaskInvalidExecTaskName,
 							Level:   Error,
 							Message: fmt.Sprintf("execution task '%s' has prefix 'display_' which is invalid", etn),
 						})
@@ -1062,22 +1582,40 @@ func validateCommands(section string, project *model.Project,
 			if cmd.Function != "" {
 				commandName = fmt.Sprintf("'%s' function", cmd.Function)
 			}
-			errs = append(errs, ValidationError{Message: fmt.Sprintf("%s section in %s: %s", section, commandName, err)})
+			errs = append(errs, ValidationError{Code: CodeCommandInvalid, Message: fmt.Sprintf("%s section in %s: %s", section, commandName, err)})
 		}
 		if cmd.Type != "" {
 			if !utility.StringSliceContains(evergreen.ValidCommandTypes, cmd.Type) {
 				msg := fmt.Sprintf("%s section in '%s': invalid command type: '%s'", section, commandName, cmd.Type)
-				errs = append(errs, ValidationError{Message: msg})
+				errs = append(errs, ValidationError{Code: CodeCommandInvalidType, Message: msg})
 			}
 		}
 		if cmd.Function != "" && cmd.Command != "" {
 			errs = append(errs, ValidationError{
+				Code:    CodeCommandAndFunction,
 				Level:   Error,
 				Message: fmt.Sprintf("cannot specify both command '%s' and function '%s'", cmd.Command, cmd.Function),
 			})
 		}
+		for _, err := range checkExpansionTransforms(cmd.Params) {
+			errs = append(errs, ValidationError{
+				Code:    CodeCommandInvalidExpansion,
+				Level:   Error,
+				Message: fmt.Sprintf("%s section in %s: invalid expansion: %s", section, commandName, err),
+			})
+		}
+		for varName, val := range cmd.Vars {
+			if err := util.ValidateExpansionTransforms(val); err != nil {
+				errs = append(errs, ValidationError{
+					Code:    CodeCommandInvalidExpansion,
+					Level:   Error,
+					Message: fmt.Sprintf("%s section in %s: invalid expansion in var '%s': %s", section, commandName, varName, err),
+				})
+			}
+		}
 		if cmd.Command == evergreen.ShellExecCommandName && cmd.Params["script"] == nil {
 			errs = append(errs, ValidationError{
+				Code:    CodeCommandMissingScript,
 				Level:   Warning,
 				Message: fmt.Sprintf("%s section: command '%s' specified without a script.", section, cmd.Command),
 			})
@@ -1086,6 +1624,35 @@ func validateCommands(section string, project *model.Project,
 	return errs
 }
 
+// checkExpansionTransforms returns an error for each string within the
+// command parameters that uses expansion transforms incorrectly.
+func checkExpansionTransforms(params interface{}) []error {
+	var errs []error
+	switch v := params.(type) {
+	case string:
+		if err := util.ValidateExpansionTransforms(v); err != nil {
+			errs = append(errs, err)
+		}
+	case map[string]interface{}:
+		for _, val := range v {
+			errs = append(errs, checkExpansionTransforms(val)...)
+		}
+	case map[interface{}]interface{}:
+		for _, val := range v {
+			errs = append(errs, checkExpansionTransforms(val)...)
+		}
+	case []interface{}:
+		for _, val := range v {
+			errs = append(errs, checkExpansionTransforms(val)...)
+		}
+	case []string:
+		for _, val := range v {
+			errs = append(errs, checkExpansionTransforms(val)...)
+		}
+	}
+	return errs
+}
+
 // Ensures there any plugin commands referenced in a project's configuration
 // are specified in a valid format
 func validatePluginCommands(project *model.Project) ValidationErrors {
@@ -1097,6 +1664,7 @@ func validatePluginCommands(project *model.Project) ValidationErrors {
 		if commands == nil || len(commands.List()) == 0 {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeFunctionNoCommands,
 					Message: fmt.Sprintf("'%s' function contains no commands", funcName),
 					Level:   Error,
 				},
@@ -1107,6 +1675,7 @@ func validatePluginCommands(project *model.Project) ValidationErrors {
 		for _, err := range valErrs {
 			errs = append(errs,
 				ValidationError{
+					Code:    err.Code,
 					Message: fmt.Sprintf("'%s' definition error: %s", funcName, err.Message),
 					Level:   err.Level,
 				},
@@ -1117,6 +1686,7 @@ func validatePluginCommands(project *model.Project) ValidationErrors {
 			if c.Function != "" {
 				errs = append(errs,
 					ValidationError{
+						Code: CodeFunctionReferencesFunction,
 						Message: fmt.Sprintf("can not reference a function within a "+
 							"function: '%s' referenced within '%s'", c.Function, funcName),
 					},
@@ -1129,6 +1699,7 @@ func validatePluginCommands(project *model.Project) ValidationErrors {
 		if seen[funcName] {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeFunctionDuplicateName,
 					Message: fmt.Sprintf(`duplicate definition of "%s"`, funcName),
 				},
 			)
@@ -1168,6 +1739,7 @@ func validateProjectTaskNames(project *model.Project) ValidationErrors {
 		if _, ok := taskNames[task.Name]; ok {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeTaskDuplicateName,
 					Message: fmt.Sprintf("task '%s' already exists", task.Name),
 				},
 			)
@@ -1185,6 +1757,7 @@ func validateProjectTaskIdsAndTags(project *model.Project) ValidationErrors {
 		// check task name
 		if i := strings.IndexAny(task.Name, model.InvalidCriterionRunes); i == 0 {
 			errs = append(errs, ValidationError{
+				Code: CodeTaskInvalidName,
 				Message: fmt.Sprintf("task '%s' has invalid name: starts with invalid character %s",
 					task.Name, strconv.QuoteRune(rune(task.Name[0])))})
 		}
@@ -1192,11 +1765,13 @@ func validateProjectTaskIdsAndTags(project *model.Project) ValidationErrors {
 		for _, tag := range task.Tags {
 			if i := strings.IndexAny(tag, model.InvalidCriterionRunes); i == 0 {
 				errs = append(errs, ValidationError{
+					Code: CodeTaskInvalidTag,
 					Message: fmt.Sprintf("task '%s' has invalid tag '%s': starts with invalid character %s",
 						task.Name, tag, strconv.QuoteRune(rune(tag[0])))})
 			}
 			if i := util.IndexWhiteSpace(tag); i != -1 {
 				errs = append(errs, ValidationError{
+					Code: CodeTaskInvalidTag,
 					Message: fmt.Sprintf("task '%s' has invalid tag '%s': tag contains white space",
 						task.Name, tag)})
 			}
@@ -1205,44 +1780,311 @@ func validateProjectTaskIdsAndTags(project *model.Project) ValidationErrors {
 	return errs
 }
 
-func checkTaskRuns(project *model.Project) ValidationErrors {
-	var errs ValidationErrors
-	for _, bvtu := range project.FindAllBuildVariantTasks() {
-		if bvtu.SkipOnPatchBuild() && bvtu.SkipOnNonPatchBuild() {
-			errs = append(errs, ValidationError{
-				Level: Warning,
-				Message: fmt.Sprintf("task '%s' will never run because it skips both patch builds and non-patch builds",
-					bvtu.Name),
-			})
+// validateAllowedRequesters ensures that tasks only allow requesters that
+// exist.
+func validateAllowedRequesters(project *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	validRequesters := make([]string, 0, len(evergreen.AllUserRequesterTypes))
+	for _, requester := range evergreen.AllUserRequesterTypes {
+		validRequesters = append(validRequesters, string(requester))
+	}
+	checkRequesters := func(location string, requesters []evergreen.UserRequester) {
+		for _, requester := range requesters {
+			if err := requester.Validate(); err != nil {
+				errs = append(errs, ValidationError{
+					Code:  CodeInvalidAllowedRequester,
+					Level: Error,
+					Message: fmt.Sprintf("%s has invalid allowed requester '%s': must be one of %s",
+						location, requester, strings.Join(validRequesters, ", ")),
+				})
+			}
 		}
-		if bvtu.SkipOnGitTagBuild() && bvtu.SkipOnNonGitTagBuild() {
+	}
+	for _, t := range project.Tasks {
+		checkRequesters(fmt.Sprintf("task '%s'", t.Name), t.AllowedRequesters)
+	}
+	for _, bv := range project.BuildVariants {
+		for _, bvtu := range bv.Tasks {
+			checkRequesters(fmt.Sprintf("task '%s' in build variant '%s'", bvtu.Name, bv.Name), bvtu.AllowedRequesters)
+		}
+	}
+	return errs
+}
+
+// validateTaskOutputs checks that the outputs that tasks declare are
+// well-formed.
+func validateTaskOutputs(project *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	for _, t := range project.Tasks {
+		if err := model.ValidateTaskOutputDefinitions(t.Outputs); err != nil {
 			errs = append(errs, ValidationError{
-				Level: Warning,
-				Message: fmt.Sprintf("task '%s' will never run because it skips both git tag builds and non git tag builds",
-					bvtu.Name),
+				Code:    CodeTaskInvalidOutputs,
+				Level:   Error,
+				Message: fmt.Sprintf("task '%s' has invalid outputs: %s", t.Name, err.Error()),
 			})
 		}
-		// Git-tag-only builds cannot run in patches.
-		if bvtu.SkipOnNonGitTagBuild() && bvtu.SkipOnNonPatchBuild() {
+	}
+	return errs
+}
+
+// validateTaskCompliance checks that the compliance metadata that tasks
+// declare is well-formed.
+func validateTaskCompliance(project *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	for _, t := range project.Tasks {
+		if err := model.ValidateTaskCompliance(t.Compliance); err != nil {
 			errs = append(errs, ValidationError{
-				Level: Warning,
-				Message: fmt.Sprintf("task '%s' will never run because it only runs for git tag builds but also is patch-only",
-					bvtu.Name),
+				Code:    CodeTaskInvalidCompliance,
+				Level:   Error,
+				Message: fmt.Sprintf("task '%s' has invalid compliance metadata: %s", t.Name, err.Error()),
 			})
 		}
-		if bvtu.SkipOnNonGitTagBuild() && utility.FromBoolPtr(bvtu.Patchable) {
+	}
+	return errs
+}
+
+// validateExpectedArtifacts checks that the artifacts that tasks expect to
+// attach are valid glob patterns.
+func validateExpectedArtifacts(project *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	for _, t := range project.Tasks {
+		if err := model.ValidateExpectedArtifacts(t.ExpectedArtifacts); err != nil {
 			errs = append(errs, ValidationError{
-				Level: Warning,
-				Message: fmt.Sprintf("task '%s' cannot be patchable if it only runs for git tag builds",
-					bvtu.Name),
+				Code:    CodeTaskInvalidExpectedArtifacts,
+				Level:   Error,
+				Message: fmt.Sprintf("task '%s' has invalid expected artifacts: %s", t.Name, err.Error()),
 			})
 		}
 	}
 	return errs
 }
 
-// validateTaskDependencies ensures that the dependencies for the tasks have the
-// correct fields, and that the fields have valid values
+// validateExternalGates checks that the external gates are well-formed and
+// that the version and build variants only use gates that are defined.
+func validateExternalGates(project *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	defined := map[string]bool{}
+	for _, gate := range project.ExternalGates {
+		if gate.Name == "" {
+			errs = append(errs, ValidationError{
+				Code:    CodeExternalGateMissingName,
+				Level:   Error,
+				Message: "external gate must have a name",
+			})
+			continue
+		}
+		if defined[gate.Name] {
+			errs = append(errs, ValidationError{
+				Code:    CodeExternalGateDuplicateName,
+				Level:   Error,
+				Message: fmt.Sprintf("external gate '%s' is defined more than once", gate.Name),
+			})
+		}
+		defined[gate.Name] = true
+		if gate.TimeoutSecs < 0 {
+			errs = append(errs, ValidationError{
+				Code:    CodeExternalGateNegativeTimeout,
+				Level:   Error,
+				Message: fmt.Sprintf("external gate '%s' cannot have a negative timeout", gate.Name),
+			})
+		}
+		if gate.OnTimeout != "" && !utility.StringSliceContains(model.ExternalGateOnTimeoutValues, gate.OnTimeout) {
+			errs = append(errs, ValidationError{
+				Code:  CodeExternalGateInvalidOnTimeout,
+				Level: Error,
+				Message: fmt.Sprintf("external gate '%s' has invalid on_timeout '%s', must be one of: %s",
+					gate.Name, gate.OnTimeout, strings.Join(model.ExternalGateOnTimeoutValues, ", ")),
+			})
+		}
+	}
+
+	for _, name := range project.VersionGates {
+		if !defined[name] {
+			errs = append(errs, ValidationError{
+				Code:    CodeExternalGateUndefined,
+				Level:   Error,
+				Message: fmt.Sprintf("version gate '%s' is not a defined external gate", name),
+			})
+		}
+	}
+	for _, bv := range project.BuildVariants {
+		for _, name := range bv.ExternalGates {
+			if !defined[name] {
+				errs = append(errs, ValidationError{
+					Code:    CodeExternalGateUndefined,
+					Level:   Error,
+					Message: fmt.Sprintf("build variant '%s' uses external gate '%s', which is not defined", bv.Name, name),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// validateStages checks that the pipeline stages are uniquely named, select at
+// least one task, don't share tasks, and run after stages that exist without
+// forming a cycle.
+func validateStages(project *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	stagesByName := map[string]model.PipelineStage{}
+	for _, stage := range project.Stages {
+		if stage.Name == "" {
+			errs = append(errs, ValidationError{
+				Code:    CodeStageMissingName,
+				Level:   Error,
+				Message: "stage must have a name",
+			})
+			continue
+		}
+		if _, ok := stagesByName[stage.Name]; ok {
+			errs = append(errs, ValidationError{
+				Code:    CodeStageDuplicateName,
+				Level:   Error,
+				Message: fmt.Sprintf("stage '%s' is defined more than once", stage.Name),
+			})
+			continue
+		}
+		stagesByName[stage.Name] = stage
+	}
+
+	stageByTask := map[model.TVPair]string{}
+	for _, stage := range project.Stages {
+		if len(stage.Tasks) == 0 {
+			errs = append(errs, ValidationError{
+				Code:    CodeStageNoTasks,
+				Level:   Error,
+				Message: fmt.Sprintf("stage '%s' does not select any tasks in any build variant", stage.Name),
+			})
+		}
+		for _, t := range stage.Tasks {
+			if other, ok := stageByTask[t]; ok && other != stage.Name {
+				errs = append(errs, ValidationError{
+					Code:    CodeStageOverlap,
+					Level:   Error,
+					Message: fmt.Sprintf("task '%s' in build variant '%s' is in both stage '%s' and stage '%s'", t.TaskName, t.Variant, other, stage.Name),
+				})
+				continue
+			}
+			stageByTask[t] = stage.Name
+		}
+		for _, after := range stage.After {
+			if after == stage.Name {
+				errs = append(errs, ValidationError{
+					Code:    CodeStageRunsAfterItself,
+					Level:   Error,
+					Message: fmt.Sprintf("stage '%s' cannot run after itself", stage.Name),
+				})
+				continue
+			}
+			if _, ok := stagesByName[after]; !ok {
+				errs = append(errs, ValidationError{
+					Code:    CodeStageUndefined,
+					Level:   Error,
+					Message: fmt.Sprintf("stage '%s' runs after stage '%s', which is not defined", stage.Name, after),
+				})
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	path := []string{}
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		path = append(path, name)
+		for _, after := range stagesByName[name].After {
+			if after == name {
+				continue
+			}
+			if _, ok := stagesByName[after]; !ok {
+				continue
+			}
+			switch state[after] {
+			case visiting:
+				cycle := []string{}
+				for i := len(path) - 1; i >= 0; i-- {
+					cycle = append([]string{path[i]}, cycle...)
+					if path[i] == after {
+						break
+					}
+				}
+				errs = append(errs, ValidationError{
+					Code:    CodeStageCycle,
+					Level:   Error,
+					Message: fmt.Sprintf("stages [%s] form a cycle", strings.Join(cycle, ", ")),
+				})
+			case unvisited:
+				visit(after)
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+	}
+	for _, stage := range project.Stages {
+		if _, ok := stagesByName[stage.Name]; ok && state[stage.Name] == unvisited {
+			visit(stage.Name)
+		}
+	}
+	return errs
+}
+
+func checkTaskRuns(project *model.Project) ValidationErrors {
+	var errs ValidationErrors
+	for _, bvtu := range project.FindAllBuildVariantTasks() {
+		var taskErrs ValidationErrors
+		if len(bvtu.AllowedRequesters) != 0 && (bvtu.Patchable != nil || bvtu.PatchOnly != nil || bvtu.AllowForGitTag != nil || bvtu.GitTagOnly != nil) {
+			taskErrs = append(taskErrs, ValidationError{
+				Code:  CodeTaskRequesterSettingsIgnored,
+				Level: Warning,
+				Message: fmt.Sprintf("task '%s' in build variant '%s' specifies allowed requesters, so its patchable, patch_only, allow_for_git_tag and git_tag_only settings are ignored",
+					bvtu.Name, bvtu.Variant),
+			})
+		}
+		if bvtu.SkipOnPatchBuild() && bvtu.SkipOnNonPatchBuild() {
+			taskErrs = append(taskErrs, ValidationError{
+				Code:  CodeTaskNeverRuns,
+				Level: Warning,
+				Message: fmt.Sprintf("task '%s' will never run because it skips both patch builds and non-patch builds",
+					bvtu.Name),
+			})
+		}
+		if bvtu.SkipOnGitTagBuild() && bvtu.SkipOnNonGitTagBuild() {
+			taskErrs = append(taskErrs, ValidationError{
+				Code:  CodeTaskNeverRuns,
+				Level: Warning,
+				Message: fmt.Sprintf("task '%s' will never run because it skips both git tag builds and non git tag builds",
+					bvtu.Name),
+			})
+		}
+		// Git-tag-only builds cannot run in patches.
+		if bvtu.SkipOnNonGitTagBuild() && bvtu.SkipOnNonPatchBuild() {
+			taskErrs = append(taskErrs, ValidationError{
+				Code:  CodeTaskNeverRuns,
+				Level: Warning,
+				Message: fmt.Sprintf("task '%s' will never run because it only runs for git tag builds but also is patch-only",
+					bvtu.Name),
+			})
+		}
+		if bvtu.SkipOnNonGitTagBuild() && utility.FromBoolPtr(bvtu.Patchable) {
+			taskErrs = append(taskErrs, ValidationError{
+				Code:  CodeTaskPatchableGitTagOnly,
+				Level: Warning,
+				Message: fmt.Sprintf("task '%s' cannot be patchable if it only runs for git tag builds",
+					bvtu.Name),
+			})
+		}
+		errs = append(errs, aboutVariant(bvtu.Variant, aboutTask(bvtu.Name, taskErrs))...)
+	}
+	return errs
+}
+
+// validateTaskDependencies ensures that the dependencies for the tasks have the
+// correct fields, and that the fields have valid values
 func validateTaskDependencies(project *model.Project) ValidationErrors {
 	errs := ValidationErrors{}
 	for _, task := range project.Tasks {
@@ -1255,6 +2097,7 @@ func validateTaskDependencies(project *model.Project) ValidationErrors {
 			if depNames[pair] {
 				errs = append(errs,
 					ValidationError{
+						Code: CodeTaskDuplicateDependency,
 						Message: fmt.Sprintf("duplicate dependency '%s' specified for task '%s'",
 							dep.Name, task.Name),
 					},
@@ -1269,33 +2112,92 @@ func validateTaskDependencies(project *model.Project) ValidationErrors {
 			default:
 				errs = append(errs,
 					ValidationError{
+						Code: CodeTaskInvalidDependencyStatus,
 						Message: fmt.Sprintf("invalid dependency status for task '%s': %s",
 							task.Name, dep.Status)})
 			}
 
-			// check that name of the dependency task is valid
-			if dep.Name != model.AllDependencies && project.FindProjectTask(dep.Name) == nil {
+			// check that name of the dependency task or task group is valid
+			if dep.Name != model.AllDependencies && project.FindProjectTask(dep.Name) == nil && project.FindTaskGroup(dep.Name) == nil {
 				errs = append(errs,
 					ValidationError{
+						Code:  CodeTaskUndefinedDependency,
 						Level: Error,
-						Message: fmt.Sprintf("non-existent task name '%s' in dependencies for task '%s'",
+						Message: fmt.Sprintf("non-existent task or task group name '%s' in dependencies for task '%s'",
 							dep.Name, task.Name),
 					},
 				)
 			}
 			if dep.Variant != "" && dep.Variant != model.AllVariants && project.FindBuildVariant(dep.Variant) == nil {
 				errs = append(errs, ValidationError{
+					Code:  CodeTaskUndefinedDependencyVariant,
 					Level: Error,
 					Message: fmt.Sprintf("non-existent variant name '%s' in dependencies for task '%s'",
 						dep.Variant, task.Name),
 				})
 			}
+			errs = append(errs, checkDependencyEdgeOptions(project, task.Name, dep)...)
 
 		}
 	}
 	return errs
 }
 
+// checkDependencyEdgeOptions checks that a dependency's cross-version and
+// generated task options can be satisfied.
+func checkDependencyEdgeOptions(project *model.Project, taskName string, dep model.TaskUnitDependency) ValidationErrors {
+	errs := ValidationErrors{}
+	if dep.LatestMainline {
+		if dep.Name == model.AllDependencies || dep.Variant == model.AllVariants {
+			errs = append(errs, ValidationError{
+				Code:  CodeCrossVersionDependencyNotSpecific,
+				Level: Error,
+				Message: fmt.Sprintf("cross-version dependency for task '%s' must name a single task and variant",
+					taskName),
+			})
+		}
+		if dep.PatchOptional {
+			errs = append(errs, ValidationError{
+				Code:  CodeCrossVersionDependencyPatchOptional,
+				Level: Warning,
+				Message: fmt.Sprintf("cross-version dependency '%s' for task '%s' does not need to be patch optional because it never runs in patches",
+					dep.Name, taskName),
+			})
+		}
+		if dependedOn := project.FindProjectTask(dep.Name); dependedOn != nil && utility.FromBoolPtr(dependedOn.PatchOnly) {
+			errs = append(errs, ValidationError{
+				Code:  CodeCrossVersionDependencyPatchOnly,
+				Level: Error,
+				Message: fmt.Sprintf("task '%s' has a cross-version dependency on patch-only task '%s', which never runs in the mainline",
+					taskName, dep.Name),
+			})
+		}
+	}
+	if dep.OmitGeneratedTasks && dep.Name != model.AllDependencies {
+		dependedOn := project.FindProjectTask(dep.Name)
+		if dependedOn != nil && !projectTaskGeneratesTasks(dependedOn) {
+			errs = append(errs, ValidationError{
+				Code:  CodeDependencyOmitsNoGeneratedTasks,
+				Level: Warning,
+				Message: fmt.Sprintf("task '%s' omits the generated tasks of dependency '%s', which does not generate tasks",
+					taskName, dep.Name),
+			})
+		}
+	}
+	return errs
+}
+
+// projectTaskGeneratesTasks returns whether the task may run generate.tasks.
+// Functions are not expanded, so a task that calls any function may.
+func projectTaskGeneratesTasks(t *model.ProjectTask) bool {
+	for _, cmd := range t.Commands {
+		if cmd.Command == evergreen.GenerateTasksCommandName || cmd.Function != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func checkTaskDependencies(task *model.ProjectTask, allTasks map[string]model.ProjectTask) ValidationErrors {
 	errs := ValidationErrors{}
 
@@ -1306,18 +2208,21 @@ func checkTaskDependencies(task *model.ProjectTask, allTasks map[string]model.Pr
 		}
 		if utility.FromBoolPtr(dependent.PatchOnly) && !utility.FromBoolPtr(task.PatchOnly) {
 			errs = append(errs, ValidationError{
+				Code:    CodeTaskDependencyRunsInFewerBuilds,
 				Level:   Warning,
 				Message: fmt.Sprintf("Task '%s' depends on patch-only task '%s'. Both will only run in patches", task.Name, dep.Name),
 			})
 		}
 		if !utility.FromBoolTPtr(dependent.Patchable) && utility.FromBoolTPtr(task.Patchable) {
 			errs = append(errs, ValidationError{
+				Code:    CodeTaskDependencyRunsInFewerBuilds,
 				Level:   Warning,
 				Message: fmt.Sprintf("Task '%s' depends on non-patchable task '%s'. Neither will run in patches", task.Name, dep.Name),
 			})
 		}
 		if utility.FromBoolPtr(dependent.GitTagOnly) && !utility.FromBoolPtr(task.GitTagOnly) {
 			errs = append(errs, ValidationError{
+				Code:    CodeTaskDependencyRunsInFewerBuilds,
 				Level:   Warning,
 				Message: fmt.Sprintf("Task '%s' depends on git-tag-only task '%s'. Both will only run when pushing git tags", task.Name, dep.Name),
 			})
@@ -1334,6 +2239,7 @@ func validateParameters(p *model.Project) ValidationErrors {
 	for _, param := range p.Parameters {
 		if _, ok := names[param.Parameter.Key]; ok {
 			errs = append(errs, ValidationError{
+				Code:    CodeParameterDuplicateName,
 				Level:   Error,
 				Message: fmt.Sprintf("parameter '%s' is defined multiple times", param.Parameter.Key),
 			})
@@ -1341,12 +2247,14 @@ func validateParameters(p *model.Project) ValidationErrors {
 		}
 		if strings.Contains(param.Parameter.Key, "=") {
 			errs = append(errs, ValidationError{
+				Code:    CodeParameterInvalidName,
 				Level:   Error,
 				Message: fmt.Sprintf("parameter name '%s' cannot contain `=`", param.Parameter.Key),
 			})
 		}
 		if param.Parameter.Key == "" {
 			errs = append(errs, ValidationError{
+				Code:    CodeParameterMissingName,
 				Level:   Error,
 				Message: "parameter name is missing",
 			})
@@ -1361,6 +2269,7 @@ func validateTaskGroups(p *model.Project) ValidationErrors {
 		// validate that there is at least 1 task
 		if len(tg.Tasks) < 1 {
 			errs = append(errs, ValidationError{
+				Code:    CodeTaskGroupNoTasks,
 				Message: fmt.Sprintf("task group %s must have at least 1 task", tg.Name),
 				Level:   Error,
 			})
@@ -1369,6 +2278,7 @@ func validateTaskGroups(p *model.Project) ValidationErrors {
 		for _, t := range p.Tasks {
 			if t.Name == tg.Name {
 				errs = append(errs, ValidationError{
+					Code:    CodeTaskGroupNameConflict,
 					Message: fmt.Sprintf("%s is used as a name for both a task and task group", t.Name),
 					Level:   Error,
 				})
@@ -1382,6 +2292,7 @@ func validateTaskGroups(p *model.Project) ValidationErrors {
 		for name, count := range counts {
 			if count > 1 {
 				errs = append(errs, ValidationError{
+					Code:    CodeTaskGroupDuplicateTask,
 					Message: fmt.Sprintf("%s is listed in task group %s %d times", name, tg.Name, count),
 					Level:   Error,
 				})
@@ -1392,6 +2303,7 @@ func validateTaskGroups(p *model.Project) ValidationErrors {
 			for _, cmd := range tg.TeardownGroup.List() {
 				if utility.StringSliceContains(evergreen.AttachCommands, cmd.Command) {
 					errs = append(errs, ValidationError{
+						Code:    CodeTaskGroupInvalidTeardownCommand,
 						Message: fmt.Sprintf("%s cannot be used in the group teardown stage", cmd.Command),
 						Level:   Error,
 					})
@@ -1410,15 +2322,19 @@ func checkTaskGroups(p *model.Project) ValidationErrors {
 	for _, tg := range p.TaskGroups {
 		if _, ok := names[tg.Name]; ok {
 			errs = append(errs, ValidationError{
+				Code:    CodeTaskGroupDuplicateName,
 				Level:   Warning,
+				Task:    tg.Name,
 				Message: fmt.Sprintf("task group '%s' is defined multiple times; only the first will be used", tg.Name),
 			})
 		}
 		names[tg.Name] = true
 		if tg.MaxHosts < 1 {
 			errs = append(errs, ValidationError{
+				Code:    CodeTaskGroupInvalidMaxHosts,
 				Message: fmt.Sprintf("task group %s has number of hosts %d less than 1", tg.Name, tg.MaxHosts),
 				Level:   Warning,
+				Task:    tg.Name,
 			})
 		}
 		if len(tg.Tasks) == 1 {
@@ -1426,8 +2342,10 @@ func checkTaskGroups(p *model.Project) ValidationErrors {
 		}
 		if tg.MaxHosts > len(tg.Tasks) {
 			errs = append(errs, ValidationError{
+				Code:    CodeTaskGroupInvalidMaxHosts,
 				Message: fmt.Sprintf("task group %s has max number of hosts %d greater than the number of tasks %d", tg.Name, tg.MaxHosts, len(tg.Tasks)),
 				Level:   Warning,
+				Task:    tg.Name,
 			})
 		}
 		for _, t := range tg.Tasks {
@@ -1438,6 +2356,166 @@ func checkTaskGroups(p *model.Project) ValidationErrors {
 	return errs
 }
 
+const (
+	// minDuplicatedBlockCommands is the fewest commands in a block that is
+	// worth extracting into a function.
+	minDuplicatedBlockCommands = 3
+	// maxDuplicatedBlockCommands is the most commands in a block that is
+	// checked for duplicates, which bounds the number of blocks that are
+	// tracked for each task.
+	maxDuplicatedBlockCommands = 20
+	// minDuplicatedBlockTasks is the fewest tasks that must share a block
+	// before suggesting that it be extracted into a function.
+	minDuplicatedBlockTasks = 3
+)
+
+// commandBlockKey identifies a block of commands by the fingerprints of the
+// commands in it, in order.
+type commandBlockKey [sha256.Size]byte
+
+// extendCommandBlockKey returns the key of the block formed by appending the
+// command with the given fingerprint to the block with the given key, so that
+// the keys of a block's prefixes can be computed incrementally.
+func extendCommandBlockKey(key commandBlockKey, fingerprint string) commandBlockKey {
+	h := sha256.New()
+	_, _ = h.Write(key[:])
+	_, _ = h.Write([]byte(fingerprint))
+	var extended commandBlockKey
+	copy(extended[:], h.Sum(nil))
+	return extended
+}
+
+type duplicatedCommandBlock struct {
+	key          commandBlockKey
+	commands     []model.PluginCommandConf
+	fingerprints []string
+	tasks        []string
+	// windows are the keys of every block of commands within this block.
+	windows map[commandBlockKey]bool
+}
+
+// checkDuplicatedCommandBlocks warns about identical blocks of commands that
+// are repeated across many tasks and suggests a function to replace each of
+// them. Only the longest shared block is reported, rather than every block
+// within it. Blocks are at most maxDuplicatedBlockCommands long, so a longer
+// shared block is reported in pieces.
+func checkDuplicatedCommandBlocks(project *model.Project) ValidationErrors {
+	blocks := map[commandBlockKey]*duplicatedCommandBlock{}
+	var order []commandBlockKey
+	for _, t := range project.Tasks {
+		cmds := make([]model.PluginCommandConf, 0, len(t.Commands))
+		for _, cmd := range t.Commands {
+			cmds = append(cmds, normalizeCommandParams(cmd))
+		}
+		fingerprints := commandFingerprints(cmds)
+		seen := map[commandBlockKey]bool{}
+		// Functions cannot call other functions, so blocks cannot include
+		// function calls.
+		for start := range cmds {
+			var key commandBlockKey
+			for end := start + 1; end <= len(cmds) && end-start <= maxDuplicatedBlockCommands && cmds[end-1].Function == ""; end++ {
+				key = extendCommandBlockKey(key, fingerprints[end-1])
+				if end-start < minDuplicatedBlockCommands || seen[key] {
+					continue
+				}
+				seen[key] = true
+				block, ok := blocks[key]
+				if !ok {
+					block = &duplicatedCommandBlock{
+						key:          key,
+						commands:     cmds[start:end],
+						fingerprints: fingerprints[start:end],
+					}
+					blocks[key] = block
+					order = append(order, key)
+				}
+				block.tasks = append(block.tasks, t.Name)
+			}
+		}
+	}
+
+	var candidates []*duplicatedCommandBlock
+	for _, key := range order {
+		if block := blocks[key]; len(block.tasks) >= minDuplicatedBlockTasks {
+			candidates = append(candidates, block)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].commands) > len(candidates[j].commands)
+	})
+
+	errs := ValidationErrors{}
+	var reported []*duplicatedCommandBlock
+	for _, block := range candidates {
+		if isWithinReportedBlock(block, reported) {
+			continue
+		}
+		block.windows = map[commandBlockKey]bool{}
+		for start := range block.fingerprints {
+			var key commandBlockKey
+			for end := start + 1; end <= len(block.fingerprints); end++ {
+				key = extendCommandBlockKey(key, block.fingerprints[end-1])
+				block.windows[key] = true
+			}
+		}
+		reported = append(reported, block)
+
+		functionName := fmt.Sprintf("shared-commands-%d", len(reported))
+		suggestion, err := yaml.Marshal(map[string]map[string][]model.PluginCommandConf{
+			"functions": {functionName: block.commands},
+		})
+		if err != nil {
+			continue
+		}
+		errs = append(errs, ValidationError{
+			Code:  CodeDuplicatedCommandBlock,
+			Level: Warning,
+			Message: fmt.Sprintf("%d tasks (%s) share an identical block of %d commands; consider replacing it with a call to a function such as:\n%s",
+				len(block.tasks), strings.Join(block.tasks, ", "), len(block.commands), string(suggestion)),
+		})
+	}
+	return errs
+}
+
+// isWithinReportedBlock returns whether the block is part of a longer block
+// that has already been reported for the same tasks.
+func isWithinReportedBlock(block *duplicatedCommandBlock, reported []*duplicatedCommandBlock) bool {
+	for _, r := range reported {
+		if r.windows[block.key] && len(r.tasks) == len(block.tasks) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandFingerprints returns a hash of each command's definition.
+func commandFingerprints(cmds []model.PluginCommandConf) []string {
+	fingerprints := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		out, err := yaml.Marshal(cmd)
+		if err != nil {
+			// A command that cannot be marshalled is never a duplicate.
+			out = []byte(utility.RandomString())
+		}
+		fingerprints = append(fingerprints, fmt.Sprintf("%x", sha256.Sum256(out)))
+	}
+	return fingerprints
+}
+
+// normalizeCommandParams returns the command with its params stored only in
+// the params field, so that commands are compared the same way whether or not
+// they were loaded from the database.
+func normalizeCommandParams(cmd model.PluginCommandConf) model.PluginCommandConf {
+	if cmd.Params == nil && cmd.ParamsYAML != "" {
+		params := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(cmd.ParamsYAML), &params); err == nil {
+			cmd.Params = params
+		}
+	}
+	cmd.ParamsYAML = ""
+	return cmd
+}
+
 // validateDuplicateBVTasks ensures that no task is used multiple times
 // in any given build variant.
 func validateDuplicateBVTasks(p *model.Project) ValidationErrors {
@@ -1474,6 +2552,7 @@ func validateDuplicateBVTasks(p *model.Project) ValidationErrors {
 func checkOrAddTask(task, variant string, tasksFound map[string]interface{}) *ValidationError {
 	if _, found := tasksFound[task]; found {
 		return &ValidationError{
+			Code:    CodeBVDuplicateTask,
 			Message: fmt.Sprintf("task '%s' in '%s' is listed more than once, likely through a task group", task, variant),
 			Level:   Error,
 		}
@@ -1558,6 +2637,7 @@ func validateTimesCalledPerTask(p *model.Project, ts map[string]int, commandName
 			if count, ok := ts[t.Name]; ok {
 				if count > times {
 					errs = append(errs, ValidationError{
+						Code:    CodeCommandCalledTooManyTimes,
 						Message: fmt.Sprintf("build variant '%s' with task '%s' may only call %s %d time(s) but calls it %d time(s)", bv.Name, t.Name, commandName, times, count),
 						Level:   level,
 					})
@@ -1581,12 +2661,14 @@ func validateHostCreateTotals(p *model.Project, counts hostCreateCounts) Validat
 	}
 	if ec2Total > EC2HostCreateTotalLimit {
 		errs = append(errs, ValidationError{
+			Code:    CodeHostCreateLimitExceeded,
 			Message: fmt.Sprintf(errorFmt, "ec2", evergreen.HostCreateCommandName, EC2HostCreateTotalLimit, ec2Total),
 			Level:   Error,
 		})
 	}
 	if dockerTotal > DockerHostCreateTotalLimit {
 		errs = append(errs, ValidationError{
+			Code:    CodeHostCreateLimitExceeded,
 			Message: fmt.Sprintf(errorFmt, "docker", evergreen.HostCreateCommandName, DockerHostCreateTotalLimit, dockerTotal),
 			Level:   Error,
 		})
@@ -1610,6 +2692,7 @@ func validateTaskSyncSettings(p *model.Project, ref *model.ProjectRef, _ bool) V
 	var errs ValidationErrors
 	if s3PushCalls := p.TasksThatCallCommand(evergreen.S3PushCommandName); len(s3PushCalls) != 0 {
 		errs = append(errs, ValidationError{
+			Code:  CodeTaskSyncDisabled,
 			Level: Error,
 			Message: fmt.Sprintf("cannot use %s command in project config when it is disabled by project '%s' settings",
 				ref.Identifier, evergreen.S3PushCommandName),
@@ -1617,6 +2700,7 @@ func validateTaskSyncSettings(p *model.Project, ref *model.ProjectRef, _ bool) V
 	}
 	if s3PullCalls := p.TasksThatCallCommand(evergreen.S3PullCommandName); len(s3PullCalls) != 0 {
 		errs = append(errs, ValidationError{
+			Code:  CodeTaskSyncDisabled,
 			Level: Error,
 			Message: fmt.Sprintf("cannot use %s command in project config when it is disabled by project '%s' settings",
 				ref.Identifier, evergreen.S3PullCommandName),
@@ -1630,12 +2714,14 @@ func validateVersionControl(_ *model.Project, ref *model.ProjectRef, isConfigDef
 	var errs ValidationErrors
 	if ref.IsVersionControlEnabled() && !isConfigDefined {
 		errs = append(errs, ValidationError{
+			Code:  CodeVersionControlUnused,
 			Level: Warning,
 			Message: fmt.Sprintf("version control is enabled for project '%s' but no project config fields have been set.",
 				ref.Identifier),
 		})
 	} else if !ref.IsVersionControlEnabled() && isConfigDefined {
 		errs = append(errs, ValidationError{
+			Code:  CodeVersionControlDisabled,
 			Level: Warning,
 			Message: fmt.Sprintf("version control is disabled for project '%s'; the currently defined project config fields will not be picked up",
 				ref.Identifier),
@@ -1644,6 +2730,280 @@ func validateVersionControl(_ *model.Project, ref *model.ProjectRef, isConfigDef
 	return errs
 }
 
+// validateVariantActivationHooks checks that the project's activation hooks
+// have valid URLs and only refer to variants that exist.
+func validateVariantActivationHooks(p *model.Project, ref *model.ProjectRef, _ bool) ValidationErrors {
+	var errs ValidationErrors
+	for i, hook := range ref.VariantActivationHooks {
+		if err := model.ValidateVariantActivationHookURL(hook.URL); err != nil {
+			errs = append(errs, ValidationError{
+				Code:    CodeActivationHookInvalidURL,
+				Level:   Error,
+				Message: fmt.Sprintf("activation hook %d has invalid URL '%s'", i, hook.URL),
+			})
+		}
+		if hook.TimeoutSecs < 0 {
+			errs = append(errs, ValidationError{
+				Code:    CodeActivationHookNegativeTimeout,
+				Level:   Error,
+				Message: fmt.Sprintf("activation hook %d cannot have a negative timeout", i),
+			})
+		}
+		if len(hook.BuildVariants) == 0 {
+			errs = append(errs, ValidationError{
+				Code:    CodeActivationHookNoVariants,
+				Level:   Error,
+				Message: fmt.Sprintf("activation hook %d must apply to at least one build variant", i),
+			})
+		}
+		for _, variant := range hook.BuildVariants {
+			if p.FindBuildVariant(variant) == nil {
+				errs = append(errs, ValidationError{
+					Code:    CodeActivationHookUndefinedVariant,
+					Level:   Error,
+					Message: fmt.Sprintf("activation hook %d refers to build variant '%s', which does not exist", i, variant),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// validateRestrictedVars warns about tasks that reference project variables
+// whose restrictions keep the variables from being given to those tasks.
+func validateRestrictedVars(p *model.Project, ref *model.ProjectRef, _ bool) ValidationErrors {
+	if ref == nil || ref.Id == "" {
+		return nil
+	}
+	vars, err := model.FindMergedProjectVars(ref.Id)
+	if err != nil {
+		return ValidationErrors{{
+			Level:   Warning,
+			Message: fmt.Sprintf("could not check restricted project variables: %s", err.Error()),
+		}}
+	}
+	if vars == nil || len(vars.RestrictedVars) == 0 {
+		return nil
+	}
+
+	var errs ValidationErrors
+	for _, bv := range p.BuildVariants {
+		for _, bvtu := range bv.Tasks {
+			taskUnits := []model.BuildVariantTaskUnit{bvtu}
+			if bvtu.IsGroup {
+				taskUnits = model.CreateTasksFromGroup(bvtu, p, "")
+			}
+			for _, tu := range taskUnits {
+				for _, name := range taskExpansionNames(p, tu.Name, bv.Name) {
+					restriction, ok := vars.RestrictedVars[name]
+					if !ok || restriction.Matches(tu.Name, bv.Name) {
+						continue
+					}
+					errs = append(errs, ValidationError{
+						Code:         CodeRestrictedVarReferenced,
+						Level:        Warning,
+						Task:         tu.Name,
+						BuildVariant: bv.Name,
+						Message: fmt.Sprintf("task '%s' in build variant '%s' references project variable '%s', which is restricted to other tasks",
+							tu.Name, bv.Name, name),
+					})
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// checkExecTimeoutsAgainstRuntimes warns about tasks whose exec timeouts are
+// far from how long the tasks recently took to run, either so long that hung
+// tasks take needlessly long to time out or so short that tasks risk timing
+// out spuriously.
+func checkExecTimeoutsAgainstRuntimes(p *model.Project, ref *model.ProjectRef, _ bool) ValidationErrors {
+	if ref == nil || ref.Id == "" {
+		return nil
+	}
+	stats, err := model.GetRecentTaskRuntimeStats(ref.Id, execTimeoutRuntimeWindow)
+	if err != nil {
+		return ValidationErrors{{
+			Level:   Warning,
+			Message: fmt.Sprintf("could not check exec timeouts against recent task runtimes: %s", err.Error()),
+		}}
+	}
+	if len(stats) == 0 {
+		return nil
+	}
+
+	var errs ValidationErrors
+	for _, bv := range p.BuildVariants {
+		for _, bvtu := range bv.Tasks {
+			taskUnits := []model.BuildVariantTaskUnit{bvtu}
+			if bvtu.IsGroup {
+				taskUnits = model.CreateTasksFromGroup(bvtu, p, "")
+			}
+			for _, tu := range taskUnits {
+				timeout := time.Duration(getExecTimeoutSecs(p, tu)) * time.Second
+				if timeout <= 0 {
+					continue
+				}
+				s, ok := stats[model.TVPair{Variant: bv.Name, TaskName: tu.Name}]
+				if !ok || s.NumTasks < execTimeoutMinRuntimeSamples || s.P95 <= 0 {
+					continue
+				}
+				ratio := float64(timeout) / float64(s.P95)
+				switch {
+				case ratio > execTimeoutMaxRuntimeFactor:
+					errs = append(errs, ValidationError{
+						Code:         CodeExecTimeoutTooLong,
+						Level:        Warning,
+						Task:         tu.Name,
+						BuildVariant: bv.Name,
+						Message: fmt.Sprintf("task '%s' in build variant '%s' has an exec timeout of %s, which is %.1fx its P95 runtime of %s over its last %d successful runs; a shorter timeout would catch hung tasks sooner",
+							tu.Name, bv.Name, timeout, ratio, s.P95.Round(time.Second), s.NumTasks),
+					})
+				case ratio < execTimeoutMinRuntimeFactor:
+					errs = append(errs, ValidationError{
+						Code:         CodeExecTimeoutTooShort,
+						Level:        Warning,
+						Task:         tu.Name,
+						BuildVariant: bv.Name,
+						Message: fmt.Sprintf("task '%s' in build variant '%s' has an exec timeout of %s, which is only %.1fx its P95 runtime of %s over its last %d successful runs; the task risks timing out spuriously",
+							tu.Name, bv.Name, timeout, ratio, s.P95.Round(time.Second), s.NumTasks),
+					})
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// checkQuarantinedDependencies warns about tasks that depend on a quarantined
+// task with a status that the quarantined task can't satisfy. Quarantined
+// tasks are skipped, so those tasks will be blocked until the quarantine
+// expires.
+func checkQuarantinedDependencies(p *model.Project, ref *model.ProjectRef, _ bool) ValidationErrors {
+	if ref == nil || ref.Id == "" {
+		return nil
+	}
+	quarantines, err := model.FindActiveTaskQuarantines(ref.Id, time.Now())
+	if err != nil {
+		return ValidationErrors{{
+			Level:   Warning,
+			Message: fmt.Sprintf("could not check dependencies on quarantined tasks: %s", err.Error()),
+		}}
+	}
+	if len(quarantines) == 0 {
+		return nil
+	}
+
+	var errs ValidationErrors
+	for _, bv := range p.BuildVariants {
+		for _, bvtu := range bv.Tasks {
+			taskUnits := []model.BuildVariantTaskUnit{bvtu}
+			if bvtu.IsGroup {
+				taskUnits = model.CreateTasksFromGroup(bvtu, p, "")
+			}
+			for _, tu := range taskUnits {
+				for _, dep := range tu.DependsOn {
+					// Only a dependency on any status is satisfied by a
+					// skipped task.
+					if dep.Status == model.AllStatuses {
+						continue
+					}
+					depVariant := dep.Variant
+					if depVariant == "" {
+						depVariant = bv.Name
+					}
+					for _, q := range quarantines {
+						if (depVariant != model.AllVariants && depVariant != q.BuildVariant) ||
+							(dep.Name != model.AllDependencies && dep.Name != q.TaskName) ||
+							(q.BuildVariant == bv.Name && q.TaskName == tu.Name) {
+							continue
+						}
+						errs = append(errs, ValidationError{
+							Code:         CodeDependencyQuarantined,
+							Level:        Warning,
+							Task:         tu.Name,
+							BuildVariant: bv.Name,
+							Message: fmt.Sprintf("task '%s' in build variant '%s' depends on task '%s' in build variant '%s', which is quarantined until %s, so it will be blocked unless it depends on any status of the quarantined task",
+								tu.Name, bv.Name, q.TaskName, q.BuildVariant, q.Expires.UTC().Format(time.RFC3339)),
+						})
+					}
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// getExecTimeoutSecs returns the exec timeout configured for the task on the
+// build variant, falling back to the task's and then the project's timeout.
+func getExecTimeoutSecs(p *model.Project, tu model.BuildVariantTaskUnit) int {
+	if tu.ExecTimeoutSecs > 0 {
+		return tu.ExecTimeoutSecs
+	}
+	if pt := p.FindProjectTask(tu.Name); pt != nil && pt.ExecTimeoutSecs > 0 {
+		return pt.ExecTimeoutSecs
+	}
+	return p.ExecTimeoutSecs
+}
+
+// taskExpansionNames returns the names of the expansions referenced by the
+// task's commands that run on the build variant, including the commands in
+// the functions it calls.
+func taskExpansionNames(p *model.Project, taskName, bv string) []string {
+	projTask := p.FindProjectTask(taskName)
+	if projTask == nil {
+		return nil
+	}
+	var names []string
+	for _, cmd := range projTask.Commands {
+		if cmd.Function == "" {
+			if cmd.RunOnVariant(bv) {
+				names = append(names, expansionNamesInParams(cmd.Params)...)
+			}
+			continue
+		}
+		for _, val := range cmd.Vars {
+			names = append(names, util.ExpansionNames(val)...)
+		}
+		f, ok := p.Functions[cmd.Function]
+		if !ok || f == nil {
+			continue
+		}
+		for _, funcCmd := range f.List() {
+			if funcCmd.RunOnVariant(bv) {
+				names = append(names, expansionNamesInParams(funcCmd.Params)...)
+			}
+		}
+	}
+	return utility.UniqueStrings(names)
+}
+
+func expansionNamesInParams(params interface{}) []string {
+	var names []string
+	switch v := params.(type) {
+	case string:
+		names = append(names, util.ExpansionNames(v)...)
+	case map[string]interface{}:
+		for _, val := range v {
+			names = append(names, expansionNamesInParams(val)...)
+		}
+	case map[interface{}]interface{}:
+		for _, val := range v {
+			names = append(names, expansionNamesInParams(val)...)
+		}
+	case []interface{}:
+		for _, val := range v {
+			names = append(names, expansionNamesInParams(val)...)
+		}
+	case []string:
+		for _, val := range v {
+			names = append(names, expansionNamesInParams(val)...)
+		}
+	}
+	return names
+}
+
 // bvsWithTasksThatCallCommand creates a mapping from build variants to tasks
 // that run the given command cmd, including the list of matching commands for
 // each task. Returns the total number of commands in the map.
@@ -1724,6 +3084,171 @@ func bvsWithTasksThatCallCommand(p *model.Project, cmd string) (map[string]map[s
 	return bvToTasksWithCmds, numCmds, catcher.Resolve()
 }
 
+const (
+	s3PutCommandName  = "s3.put"
+	s3CopyCommandName = "s3Copy.copy"
+)
+
+// versionWideExpansions are the expansions that have the same value for every
+// task in a version, regardless of its build variant.
+var versionWideExpansions = []string{
+	"author",
+	"branch_name",
+	"created_at",
+	"execution",
+	"github_commit",
+	"is_patch",
+	"project",
+	"project_id",
+	"project_identifier",
+	"revision",
+	"revision_order_id",
+	"version_id",
+}
+
+// checkArtifactDestinations warns about s3.put and s3Copy.copy commands that
+// upload to the same destination from tasks in different build variants, since
+// the variants' artifacts would overwrite each other. Destinations that depend
+// on expansions whose value isn't known until the task runs are ignored.
+func checkArtifactDestinations(project *model.Project) ValidationErrors {
+	// destination -> build variants that upload to it
+	bvsByDestination := map[string][]string{}
+	for _, cmdName := range []string{s3PutCommandName, s3CopyCommandName} {
+		// Errors finding tasks are reported by other validators.
+		bvToTaskCmds, _, _ := bvsWithTasksThatCallCommand(project, cmdName)
+		for bvName, taskCmds := range bvToTaskCmds {
+			bv := project.FindBuildVariant(bvName)
+			if bv == nil {
+				continue
+			}
+			for taskName, cmds := range taskCmds {
+				expansions := artifactDestinationExpansions(bv, taskName)
+				for _, cmd := range cmds {
+					for _, dest := range artifactDestinations(cmd, bvName) {
+						expanded, ok := expandArtifactDestination(expansions, dest)
+						if !ok {
+							continue
+						}
+						if !utility.StringSliceContains(bvsByDestination[expanded], bvName) {
+							bvsByDestination[expanded] = append(bvsByDestination[expanded], bvName)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	destinations := make([]string, 0, len(bvsByDestination))
+	for dest, bvs := range bvsByDestination {
+		if len(bvs) > 1 {
+			destinations = append(destinations, dest)
+		}
+	}
+	sort.Strings(destinations)
+
+	errs := ValidationErrors{}
+	for _, dest := range destinations {
+		bvs := bvsByDestination[dest]
+		sort.Strings(bvs)
+		errs = append(errs, ValidationError{
+			Code:  CodeArtifactDestinationConflict,
+			Level: Warning,
+			Message: fmt.Sprintf("build variants '%s' upload artifacts to the same destination '%s' and may overwrite each other's artifacts; "+
+				"consider prefixing the destination with '${%s}'",
+				strings.Join(bvs, "', '"), dest, model.ArtifactNamespaceExpansion),
+		})
+	}
+	return errs
+}
+
+// artifactDestinationExpansions returns the expansions that are known before
+// the task runs and can differ between build variants.
+func artifactDestinationExpansions(bv *model.BuildVariant, taskName string) util.Expansions {
+	expansions := bv.GetExpansions()
+	expansions.Put("build_variant", bv.Name)
+	expansions.Put("task_name", taskName)
+	// The actual IDs aren't known yet, but they're always unique to the build
+	// variant.
+	expansions.Put("build_id", bv.Name)
+	expansions.Put("task_id", fmt.Sprintf("%s_%s", bv.Name, taskName))
+	for _, name := range versionWideExpansions {
+		if !expansions.Exists(name) {
+			expansions.Put(name, versionWideExpansionPlaceholder(name))
+		}
+	}
+	return expansions
+}
+
+// versionWideExpansionPlaceholder stands in for the value of an expansion
+// that's the same for every build variant. It can't be written as the
+// expansion itself, since expanding a string must not leave any expansions.
+func versionWideExpansionPlaceholder(name string) string {
+	return fmt.Sprintf("\x00%s\x00", name)
+}
+
+// expandArtifactDestination expands the destination. It returns false if the
+// destination refers to expansions whose value can't be determined.
+func expandArtifactDestination(expansions util.Expansions, dest string) (string, bool) {
+	for _, name := range util.ExpansionNames(dest) {
+		if !expansions.Exists(name) {
+			return "", false
+		}
+	}
+	expanded, err := expansions.ExpandString(dest)
+	if err != nil {
+		return "", false
+	}
+	for _, name := range versionWideExpansions {
+		expanded = strings.ReplaceAll(expanded, versionWideExpansionPlaceholder(name), fmt.Sprintf("${%s}", name))
+	}
+	return expanded, true
+}
+
+// artifactDestinations returns the unexpanded destinations that the upload
+// command writes to when it runs on the build variant.
+func artifactDestinations(cmd model.PluginCommandConf, bv string) []string {
+	var dests []string
+	switch cmd.Command {
+	case s3PutCommandName:
+		params := struct {
+			Bucket        string   `mapstructure:"bucket"`
+			RemoteFile    string   `mapstructure:"remote_file"`
+			BuildVariants []string `mapstructure:"build_variants"`
+		}{}
+		if err := mapstructure.Decode(cmd.Params, &params); err != nil {
+			return nil
+		}
+		if len(params.BuildVariants) != 0 && !utility.StringSliceContains(params.BuildVariants, bv) {
+			return nil
+		}
+		if params.Bucket != "" && params.RemoteFile != "" {
+			dests = append(dests, fmt.Sprintf("%s/%s", params.Bucket, params.RemoteFile))
+		}
+	case s3CopyCommandName:
+		params := struct {
+			S3CopyFiles []struct {
+				Destination struct {
+					Bucket string `mapstructure:"bucket"`
+					Path   string `mapstructure:"path"`
+				} `mapstructure:"destination"`
+				BuildVariants []string `mapstructure:"build_variants"`
+			} `mapstructure:"s3_copy_files"`
+		}{}
+		if err := mapstructure.Decode(cmd.Params, &params); err != nil {
+			return nil
+		}
+		for _, file := range params.S3CopyFiles {
+			if len(file.BuildVariants) != 0 && !utility.StringSliceContains(file.BuildVariants, bv) {
+				continue
+			}
+			if file.Destination.Bucket != "" && file.Destination.Path != "" {
+				dests = append(dests, fmt.Sprintf("%s/%s", file.Destination.Bucket, file.Destination.Path))
+			}
+		}
+	}
+	return dests
+}
+
 // validateTaskSyncCommands validates project's task sync commands.  In
 // particular, s3.push should be called at most once per task and s3.pull should
 // refer to a valid task running s3.push.  It does not check that the project
@@ -1739,6 +3264,7 @@ func validateTaskSyncCommands(p *model.Project, runLong bool) ValidationErrors {
 	bvToTaskCmds, numCmds, err := bvsWithTasksThatCallCommand(p, evergreen.S3PullCommandName)
 	if err != nil {
 		errs = append(errs, ValidationError{
+			Code:    CodeTaskSyncInvalid,
 			Level:   Error,
 			Message: fmt.Sprintf("could not generate map of build variants with tasks that call command '%s': %s", evergreen.S3PullCommandName, err.Error()),
 		})
@@ -1747,6 +3273,7 @@ func validateTaskSyncCommands(p *model.Project, runLong bool) ValidationErrors {
 	checkDependencies := numCmds <= maxTaskSyncCommandsForDependenciesCheck || runLong
 	if !checkDependencies {
 		errs = append(errs, ValidationError{
+			Code:    CodeTaskSyncTooManyCommands,
 			Level:   Warning,
 			Message: fmt.Sprintf("too many commands using '%s' to check dependencies by default", evergreen.S3PullCommandName),
 		})
@@ -1760,6 +3287,7 @@ func validateTaskSyncCommands(p *model.Project, runLong bool) ValidationErrors {
 				s3PushTaskName, s3PushBVName, parseErr := parseS3PullParameters(cmd)
 				if parseErr != nil {
 					errs = append(errs, ValidationError{
+						Code:    CodeTaskSyncInvalid,
 						Level:   Error,
 						Message: fmt.Sprintf("could not parse parameters for command '%s': %s", cmd.Command, parseErr.Error()),
 					})
@@ -1780,6 +3308,7 @@ func validateTaskSyncCommands(p *model.Project, runLong bool) ValidationErrors {
 					s3PullTaskNode := model.TVPair{TaskName: task, Variant: bv}
 					if err := validateTVDependsOnTV(s3PullTaskNode, s3PushTaskNode, []string{"", evergreen.TaskSucceeded}, p); err != nil {
 						errs = append(errs, ValidationError{
+							Code:  CodeTaskSyncMissingDependency,
 							Level: Error,
 							Message: fmt.Sprintf("problem validating that task running command '%s' depends on task running command '%s': %s",
 								evergreen.S3PullCommandName, evergreen.S3PushCommandName, err.Error()),
@@ -1792,12 +3321,14 @@ func validateTaskSyncCommands(p *model.Project, runLong bool) ValidationErrors {
 				cmds, err := p.CommandsRunOnTV(s3PushTaskNode, evergreen.S3PushCommandName)
 				if err != nil {
 					errs = append(errs, ValidationError{
+						Code:  CodeTaskSyncInvalid,
 						Level: Error,
 						Message: fmt.Sprintf("problem validating that task '%s' runs command '%s': %s",
 							s3PushTaskName, evergreen.S3PushCommandName, err.Error()),
 					})
 				} else if len(cmds) == 0 {
 					errs = append(errs, ValidationError{
+						Code:  CodeTaskSyncMissingPush,
 						Level: Error,
 						Message: fmt.Sprintf("task '%s' in build variant '%s' does not run command '%s'",
 							s3PushTaskName, s3PushBVName, evergreen.S3PushCommandName),
@@ -1926,15 +3457,18 @@ func checkTasks(project *model.Project) ValidationErrors {
 		if len(task.Commands) == 0 {
 			errs = append(errs,
 				ValidationError{
+					Code: CodeTaskNoCommands,
 					Message: fmt.Sprintf("task '%s' does not contain any commands",
 						task.Name),
 					Level: Warning,
+					Task:  task.Name,
 				},
 			)
 		}
 		if project.ExecTimeoutSecs == 0 && task.ExecTimeoutSecs == 0 && !execTimeoutWarningAdded {
 			errs = append(errs,
 				ValidationError{
+					Code: CodeExecTimeoutUndefined,
 					Message: fmt.Sprintf("no exec_timeout_secs defined at the top-level or on one or more tasks; "+
 						"these tasks will default to a timeout of %d hours",
 						int(agent.DefaultExecTimeout.Hours())),
@@ -1943,13 +3477,14 @@ func checkTasks(project *model.Project) ValidationErrors {
 			)
 			execTimeoutWarningAdded = true
 		}
-		errs = append(errs, checkLoggerConfig(&task)...)
-		errs = append(errs, checkTaskDependencies(&task, allTasks)...)
-		errs = append(errs, checkTaskNames(project, &task)...)
+		errs = append(errs, aboutTask(task.Name, checkLoggerConfig(&task))...)
+		errs = append(errs, aboutTask(task.Name, checkTaskDependencies(&task, allTasks))...)
+		errs = append(errs, aboutTask(task.Name, checkTaskNames(project, &task))...)
 	}
 	if project.Loggers != nil {
 		if err := project.Loggers.IsValid(); err != nil {
 			errs = append(errs, ValidationError{
+				Code:    CodeLoggerConfigInvalid,
 				Message: errors.Wrap(err, "error in project-level logger config").Error(),
 				Level:   Warning,
 			})
@@ -1958,6 +3493,129 @@ func checkTasks(project *model.Project) ValidationErrors {
 	return errs
 }
 
+// checkAliasTags checks that the tags that the project's aliases select tasks
+// and variants by are applied to at least one task or variant, since an alias
+// that selects by an unused tag silently selects nothing.
+func checkAliasTags(project *model.Project) ValidationErrors {
+	taskTags := map[string]bool{}
+	for _, t := range project.Tasks {
+		for _, tag := range t.Tags {
+			taskTags[tag] = true
+		}
+	}
+	for _, tg := range project.TaskGroups {
+		for _, tag := range tg.Tags {
+			taskTags[tag] = true
+		}
+	}
+	variantTags := map[string]bool{}
+	for _, bv := range project.BuildVariants {
+		for _, tag := range bv.Tags {
+			variantTags[tag] = true
+		}
+	}
+
+	errs := ValidationErrors{}
+	for _, aliasesOfType := range []struct {
+		aliasType string
+		aliases   []model.ProjectAlias
+	}{
+		{aliasType: "GitHub PR Aliases", aliases: project.GitHubPRAliases},
+		{aliasType: "Github Checks Aliases", aliases: project.GitHubChecksAliases},
+		{aliasType: "Commit Queue Aliases", aliases: project.CommitQueueAliases},
+		{aliasType: "Patch Aliases", aliases: project.PatchAliases},
+		{aliasType: "Git Tag Aliases", aliases: project.GitTagAliases},
+	} {
+		for _, alias := range aliasesOfType.aliases {
+			for _, tag := range alias.TaskTags {
+				if !taskTags[strings.TrimPrefix(tag, "!")] {
+					errs = append(errs, ValidationError{
+						Code:    CodeAliasUndefinedTaskTag,
+						Level:   Warning,
+						Message: fmt.Sprintf("%s: alias '%s' selects tasks by tag '%s', but no tasks or task groups have that tag", aliasesOfType.aliasType, alias.Alias, tag),
+					})
+				}
+			}
+			for _, tag := range alias.VariantTags {
+				if !variantTags[strings.TrimPrefix(tag, "!")] {
+					errs = append(errs, ValidationError{
+						Code:    CodeAliasUndefinedVariantTag,
+						Level:   Warning,
+						Message: fmt.Sprintf("%s: alias '%s' selects variants by tag '%s', but no build variants have that tag", aliasesOfType.aliasType, alias.Alias, tag),
+					})
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// CheckSelectorTags checks that the tags that the task and variant selectors
+// in the project's YAML select by are applied to at least one task, task group
+// or build variant. It checks the intermediate project because translating the
+// project resolves its selectors, so it can explain why a project that selects
+// by an unused tag fails to translate.
+func CheckSelectorTags(pp *model.ParserProject) ValidationErrors {
+	if pp == nil {
+		return nil
+	}
+
+	taskTags := map[string]bool{}
+	for _, t := range pp.Tasks {
+		for _, tag := range t.Tags {
+			taskTags[tag] = true
+		}
+	}
+	for _, tg := range pp.TaskGroups {
+		for _, tag := range tg.Tags {
+			taskTags[tag] = true
+		}
+	}
+	variantTags := map[string]bool{}
+	for _, bv := range pp.BuildVariants {
+		for _, tag := range bv.Tags {
+			variantTags[tag] = true
+		}
+		if bv.Matrix != nil {
+			for _, tag := range bv.Matrix.Tags {
+				variantTags[tag] = true
+			}
+		}
+	}
+	for _, axis := range pp.Axes {
+		for _, value := range axis.Values {
+			for _, tag := range value.Tags {
+				variantTags[tag] = true
+			}
+		}
+	}
+
+	errs := ValidationErrors{}
+	for _, selector := range pp.TaskSelectors() {
+		for _, tag := range model.ParseSelector(selector.Selector).Tags() {
+			if !taskTags[tag] {
+				errs = append(errs, ValidationError{
+					Code:    CodeSelectorUndefinedTaskTag,
+					Level:   Warning,
+					Message: fmt.Sprintf("%s selects tasks by tag '%s', but no tasks or task groups have that tag", selector.Location, tag),
+				})
+			}
+		}
+	}
+	for _, selector := range pp.VariantSelectors() {
+		for _, tag := range model.ParseSelector(selector.Selector).Tags() {
+			if !variantTags[tag] {
+				errs = append(errs, ValidationError{
+					Code:    CodeSelectorUndefinedVariantTag,
+					Level:   Warning,
+					Message: fmt.Sprintf("%s selects build variants by tag '%s', but no build variants have that tag", selector.Location, tag),
+				})
+			}
+		}
+	}
+	return errs
+}
+
 // checkBuildVariants checks whether project build variants contain warnings by checking if each variant
 // has tasks, valid and non-duplicate names, and appropriate batch time settings.
 func checkBuildVariants(project *model.Project) ValidationErrors {
@@ -1970,19 +3628,22 @@ func checkBuildVariants(project *model.Project) ValidationErrors {
 		if len(buildVariant.Tasks) == 0 {
 			errs = append(errs,
 				ValidationError{
-					Message: fmt.Sprintf("buildvariant '%s' contains no tasks", buildVariant.Name),
-					Level:   Warning,
+					Code:         CodeBVNoTasks,
+					Message:      fmt.Sprintf("buildvariant '%s' contains no tasks", buildVariant.Name),
+					Level:        Warning,
+					BuildVariant: buildVariant.Name,
 				},
 			)
 		}
-		errs = append(errs, checkBVNames(&buildVariant)...)
-		errs = append(errs, checkBVBatchTimes(&buildVariant)...)
+		errs = append(errs, aboutVariant(buildVariant.Name, labelRule(checkBVNames, checkBVNames(&buildVariant)))...)
+		errs = append(errs, aboutVariant(buildVariant.Name, checkBVBatchTimes(&buildVariant))...)
 	}
 
 	for k, v := range displayNames {
 		if v > 1 {
 			errs = append(errs,
 				ValidationError{
+					Code:    CodeBVDuplicateDisplayName,
 					Level:   Warning,
 					Message: fmt.Sprintf("%d build variants share the same display name: '%s'", v, k),
 				},
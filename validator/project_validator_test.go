@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
@@ -12,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
 	_ "github.com/evergreen-ci/evergreen/plugin"
 	"github.com/evergreen-ci/utility"
 	. "github.com/smartystreets/goconvey/convey"
@@ -159,7 +161,20 @@ func TestValidateTaskDependencies(t *testing.T) {
 					{Name: "v1", Tasks: []model.BuildVariantTaskUnit{{Name: "1"}, {Name: "2"}, {Name: "tg", IsGroup: true}}},
 				},
 			}
-			So(validateTaskDependencies(p)[0].Message, ShouldResemble, "non-existent task name 'nonexistent' in dependencies for task '3'")
+			So(validateTaskDependencies(p)[0].Message, ShouldResemble, "non-existent task or task group name 'nonexistent' in dependencies for task '3'")
+		})
+		Convey("depending on a task group is valid", func() {
+			p := &model.Project{
+				Tasks: []model.ProjectTask{
+					{Name: "1"},
+					{Name: "2"},
+					{Name: "3", DependsOn: []model.TaskUnitDependency{{Name: "tg"}}},
+				},
+				TaskGroups: []model.TaskGroup{
+					{Name: "tg", Tasks: []string{"1", "2"}},
+				},
+			}
+			So(validateTaskDependencies(p), ShouldResemble, ValidationErrors{})
 		})
 		Convey("depending on a non-patchable task should generate a warning", func() {
 			p := model.Project{
@@ -180,6 +195,102 @@ func TestValidateTaskDependencies(t *testing.T) {
 	})
 }
 
+func TestCheckDependencyEdgeOptions(t *testing.T) {
+	project := &model.Project{
+		Tasks: []model.ProjectTask{
+			{Name: "generator", Commands: []model.PluginCommandConf{{Command: evergreen.GenerateTasksCommandName}}},
+			{Name: "compile", Commands: []model.PluginCommandConf{{Command: "shell.exec"}}},
+			{Name: "patch_only", PatchOnly: utility.TruePtr()},
+		},
+	}
+
+	t.Run("OmitGeneratedTasksOnGenerator", func(t *testing.T) {
+		errs := checkDependencyEdgeOptions(project, "test", model.TaskUnitDependency{Name: "generator", OmitGeneratedTasks: true})
+		assert.Empty(t, errs)
+	})
+	t.Run("OmitGeneratedTasksOnNonGenerator", func(t *testing.T) {
+		errs := checkDependencyEdgeOptions(project, "test", model.TaskUnitDependency{Name: "compile", OmitGeneratedTasks: true})
+		require.Len(t, errs, 1)
+		assert.Equal(t, Warning, errs[0].Level)
+	})
+	t.Run("LatestMainlineOnSpecificTask", func(t *testing.T) {
+		errs := checkDependencyEdgeOptions(project, "test", model.TaskUnitDependency{Name: "compile", Variant: "bv", LatestMainline: true})
+		assert.Empty(t, errs)
+	})
+	t.Run("LatestMainlineOnAllDependencies", func(t *testing.T) {
+		errs := checkDependencyEdgeOptions(project, "test", model.TaskUnitDependency{Name: model.AllDependencies, LatestMainline: true})
+		require.Len(t, errs, 1)
+		assert.Equal(t, Error, errs[0].Level)
+	})
+	t.Run("LatestMainlineOnPatchOnlyTask", func(t *testing.T) {
+		errs := checkDependencyEdgeOptions(project, "test", model.TaskUnitDependency{Name: "patch_only", LatestMainline: true})
+		require.Len(t, errs, 1)
+		assert.Equal(t, Error, errs[0].Level)
+	})
+}
+
+func TestCheckDuplicatedCommandBlocks(t *testing.T) {
+	shell := func(script string) model.PluginCommandConf {
+		return model.PluginCommandConf{Command: "shell.exec", Params: map[string]interface{}{"script": script}}
+	}
+	shared := []model.PluginCommandConf{shell("setup"), shell("compile"), shell("test"), shell("upload")}
+
+	t.Run("SuggestsFunctionForSharedBlock", func(t *testing.T) {
+		project := &model.Project{
+			Tasks: []model.ProjectTask{
+				{Name: "t1", Commands: append([]model.PluginCommandConf{shell("one")}, shared...)},
+				{Name: "t2", Commands: append(append([]model.PluginCommandConf{}, shared...), shell("two"))},
+				{Name: "t3", Commands: shared},
+				{Name: "t4", Commands: shared[1:]},
+			},
+		}
+		errs := checkDuplicatedCommandBlocks(project)
+		require.Len(t, errs, 2)
+		assert.Equal(t, Warning, errs[0].Level)
+		assert.Contains(t, errs[0].Message, "3 tasks (t1, t2, t3) share an identical block of 4 commands")
+		assert.Contains(t, errs[0].Message, "functions:")
+		assert.Contains(t, errs[0].Message, "script: setup")
+		assert.Contains(t, errs[1].Message, "4 tasks (t1, t2, t3, t4) share an identical block of 3 commands")
+		assert.NotContains(t, errs[1].Message, "script: setup")
+	})
+	t.Run("IgnoresBlocksSharedByFewTasks", func(t *testing.T) {
+		project := &model.Project{
+			Tasks: []model.ProjectTask{
+				{Name: "t1", Commands: shared},
+				{Name: "t2", Commands: shared},
+			},
+		}
+		assert.Empty(t, checkDuplicatedCommandBlocks(project))
+	})
+	t.Run("DoesNotIncludeFunctionCalls", func(t *testing.T) {
+		withFunc := []model.PluginCommandConf{shell("setup"), {Function: "fetch"}, shell("compile"), shell("test")}
+		project := &model.Project{
+			Tasks: []model.ProjectTask{
+				{Name: "t1", Commands: withFunc},
+				{Name: "t2", Commands: withFunc},
+				{Name: "t3", Commands: withFunc},
+			},
+		}
+		assert.Empty(t, checkDuplicatedCommandBlocks(project))
+	})
+	t.Run("LimitsBlockLength", func(t *testing.T) {
+		var long []model.PluginCommandConf
+		for i := 0; i < maxDuplicatedBlockCommands+1; i++ {
+			long = append(long, shell(fmt.Sprintf("step %d", i)))
+		}
+		project := &model.Project{
+			Tasks: []model.ProjectTask{
+				{Name: "t1", Commands: long},
+				{Name: "t2", Commands: long},
+				{Name: "t3", Commands: long},
+			},
+		}
+		errs := checkDuplicatedCommandBlocks(project)
+		require.NotEmpty(t, errs)
+		assert.Contains(t, errs[0].Message, fmt.Sprintf("share an identical block of %d commands", maxDuplicatedBlockCommands))
+	})
+}
+
 func TestValidateDependencyGraph(t *testing.T) {
 	Convey("When checking a project's dependency graph", t, func() {
 		Convey("cycles in the dependency graph should cause error to be returned", func() {
@@ -478,6 +589,30 @@ func TestValidateDependencyGraph(t *testing.T) {
 	})
 }
 
+func TestValidateAllowedRequesters(t *testing.T) {
+	project := &model.Project{
+		Tasks: []model.ProjectTask{
+			{Name: "t1", AllowedRequesters: []evergreen.UserRequester{evergreen.RepotrackerVersionUserRequester, evergreen.GithubMergeQueueUserRequester}},
+		},
+		BuildVariants: []model.BuildVariant{
+			{
+				Name: "bv",
+				Tasks: []model.BuildVariantTaskUnit{
+					{Name: "t1", AllowedRequesters: []evergreen.UserRequester{evergreen.PatchVersionUserRequester}},
+				},
+			},
+		},
+	}
+	assert.Empty(t, validateAllowedRequesters(project))
+
+	project.Tasks[0].AllowedRequesters = []evergreen.UserRequester{evergreen.RepotrackerVersionRequester}
+	project.BuildVariants[0].Tasks[0].AllowedRequesters = []evergreen.UserRequester{"nonexistent"}
+	errs := validateAllowedRequesters(project)
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Message, "task 't1' has invalid allowed requester 'gitter_request'")
+	assert.Contains(t, errs[1].Message, "task 't1' in build variant 'bv' has invalid allowed requester 'nonexistent'")
+}
+
 func TestCheckTaskRuns(t *testing.T) {
 	makeProject := func() *model.Project {
 		return &model.Project{
@@ -872,6 +1007,31 @@ func TestValidateBVBatchTimes(t *testing.T) {
 	p.BuildVariants[0].Tasks[0].BatchTime = nil
 	assert.Len(t, validateBVBatchTimes(p), 0)
 
+	// can't use a time zone that doesn't exist
+	p.BuildVariants[0].CronTimezone = "America/Nowhere"
+	errs := validateBVBatchTimes(p)
+	require.Len(t, errs, 1)
+	assert.Equal(t, Error, errs[0].Level)
+	p.BuildVariants[0].CronTimezone = "America/New_York"
+	assert.Len(t, validateBVBatchTimes(p), 0)
+
+	// warning if the cron fires during a daylight saving time transition
+	p.BuildVariants[0].Tasks[0].CronBatchTime = "30 2 * * *"
+	p.BuildVariants[0].Tasks[0].CronTimezone = "America/New_York"
+	errs = validateBVBatchTimes(p)
+	require.NotEmpty(t, errs)
+	for _, err := range errs {
+		assert.Equal(t, Warning, err.Level)
+		assert.Contains(t, err.Message, "task 't1'")
+	}
+
+	// warning if a time zone is set without a cron
+	p.BuildVariants[0].Tasks[0].CronBatchTime = ""
+	errs = validateBVBatchTimes(p)
+	require.Len(t, errs, 1)
+	assert.Equal(t, Warning, errs[0].Level)
+	p.BuildVariants[0].Tasks[0].CronTimezone = ""
+
 	// warning if activated to true with batchtime
 	p.BuildVariants[0].Activate = utility.TruePtr()
 	bv := p.BuildVariants[0]
@@ -1064,6 +1224,38 @@ func TestValidatePeriodicBuilds(t *testing.T) {
 	assert.Len(t, validationErrs, 2)
 	assert.Contains(t, validationErrs[0].Message, "interval must be a positive integer")
 	assert.Contains(t, validationErrs[1].Message, "a config file must be specified")
+
+	projectConfig.PeriodicBuilds = []model.PeriodicBuildDefinition{
+		{
+			ID:         "nightly",
+			ConfigFile: "build.yml",
+			Cron:       "0 3 * * *",
+			Timezone:   "Europe/Berlin",
+		},
+		{
+			ID:            "both",
+			ConfigFile:    "build.yml",
+			IntervalHours: 1,
+			Cron:          "0 3 * * *",
+		},
+		{
+			ID:         "bad_zone",
+			ConfigFile: "build.yml",
+			Cron:       "0 3 * * *",
+			Timezone:   "Mars/Olympus_Mons",
+		},
+		{
+			ID:            "zone_without_cron",
+			ConfigFile:    "build.yml",
+			IntervalHours: 1,
+			Timezone:      "UTC",
+		},
+	}
+	validationErrs = validateProjectConfigPeriodicBuilds(projectConfig)
+	require.Len(t, validationErrs, 3)
+	assert.Contains(t, validationErrs[0].Message, "cannot specify both an interval and a cron")
+	assert.Contains(t, validationErrs[1].Message, "invalid time zone 'Mars/Olympus_Mons'")
+	assert.Contains(t, validationErrs[2].Message, "a time zone can only be specified with a cron")
 }
 
 func TestValidatePlugins(t *testing.T) {
@@ -1420,6 +1612,58 @@ func TestEnsureReferentialIntegrity(t *testing.T) {
 			So(errs[0].Message, ShouldContainSubstring, "run_on cannot contain a mixture of containers and distros")
 		})
 
+		Convey("no error should be thrown if a container task falls back to an existing distro", func() {
+			project := &model.Project{
+				Tasks: []model.ProjectTask{{Name: "compile"}},
+				BuildVariants: []model.BuildVariant{
+					{
+						Name:  "enterprise",
+						RunOn: []string{"c1"},
+						Tasks: []model.BuildVariantTaskUnit{
+							{
+								Name:              "compile",
+								ContainerFallback: &model.ContainerFallback{Distro: "rhel55-alias", AfterMins: 10},
+							},
+						},
+					},
+				},
+			}
+			containerNameMap := map[string]bool{
+				"c1": true,
+			}
+			So(ensureReferentialIntegrity(project, containerNameMap, distroIds, distroAliases), ShouldResemble, ValidationErrors{})
+		})
+
+		Convey("an error should be thrown if a container fallback is invalid", func() {
+			project := &model.Project{
+				Tasks: []model.ProjectTask{{Name: "compile"}, {Name: "test"}},
+				BuildVariants: []model.BuildVariant{
+					{
+						Name:  "enterprise",
+						RunOn: []string{"rhel55"},
+						Tasks: []model.BuildVariantTaskUnit{
+							{
+								Name:              "compile",
+								ContainerFallback: &model.ContainerFallback{Distro: "rhel55"},
+							},
+							{
+								Name:              "test",
+								RunOn:             []string{"c1"},
+								ContainerFallback: &model.ContainerFallback{Distro: "nonexistent"},
+							},
+						},
+					},
+				},
+			}
+			containerNameMap := map[string]bool{
+				"c1": true,
+			}
+			errs := ensureReferentialIntegrity(project, containerNameMap, distroIds, distroAliases)
+			So(len(errs), ShouldEqual, 2)
+			So(errs[0].Message, ShouldContainSubstring, "has a container fallback but does not run in a container")
+			So(errs[1].Message, ShouldContainSubstring, "falls back to nonexistent distro 'nonexistent'")
+		})
+
 		Convey("no error should be thrown if a referenced distro ID for a "+
 			"buildvariant does exist", func() {
 			project := &model.Project{
@@ -1495,6 +1739,95 @@ func TestValidateProjectConfigContainers(t *testing.T) {
 		errs := validateProjectConfigContainers(&pc)
 		assert.NotEmpty(t, errs)
 	})
+	t.Run("WarnsWithSizeLargerThanPod", func(t *testing.T) {
+		ecsConf := &evergreen.GetEnvironment().Settings().Providers.AWS.Pod.ECS
+		ecsConf.MaxCPU = 1024
+		ecsConf.MaxMemoryMB = 4096
+		defer func() {
+			ecsConf.MaxCPU = 0
+			ecsConf.MaxMemoryMB = 0
+		}()
+		pc := model.ProjectConfig{
+			ProjectConfigFields: model.ProjectConfigFields{
+				ContainerSizes: map[string]model.ContainerResources{
+					"small": {
+						CPU:      128,
+						MemoryMB: 128,
+					},
+					"large": {
+						CPU:      2048,
+						MemoryMB: 2048,
+					},
+				},
+			},
+		}
+		errs := validateProjectConfigContainers(&pc)
+		require.Len(t, errs, 1)
+		assert.Equal(t, Warning, errs[0].Level)
+		assert.Contains(t, errs[0].Message, "container size 'large'")
+	})
+}
+
+func TestCheckContainerCapacity(t *testing.T) {
+	ecsConf := &evergreen.GetEnvironment().Settings().Providers.AWS.Pod.ECS
+	ecsConf.MaxCPU = 1024
+	ecsConf.MaxMemoryMB = 4096
+	ecsConf.PoolCPU = 2048
+	ecsConf.PoolMemoryMB = 8192
+	defer func() {
+		ecsConf.MaxCPU = 0
+		ecsConf.MaxMemoryMB = 0
+		ecsConf.PoolCPU = 0
+		ecsConf.PoolMemoryMB = 0
+	}()
+
+	ref := &model.ProjectRef{
+		ContainerSizes: map[string]model.ContainerResources{
+			"medium": {CPU: 1024, MemoryMB: 1024},
+		},
+	}
+	t.Run("SucceedsWithinCapacity", func(t *testing.T) {
+		p := &model.Project{
+			Containers: []model.Container{{Name: "c1", Size: "medium"}},
+			BuildVariants: model.BuildVariants{
+				{Name: "bv", RunOn: []string{"c1"}, Tasks: []model.BuildVariantTaskUnit{{Name: "t1"}, {Name: "t2"}}},
+				{Name: "hosts", RunOn: []string{"distro"}, Tasks: []model.BuildVariantTaskUnit{{Name: "t1"}, {Name: "t2"}, {Name: "t3"}}},
+			},
+		}
+		assert.Empty(t, checkContainerCapacity(p, ref, false))
+	})
+	t.Run("WarnsWithResourcesLargerThanPod", func(t *testing.T) {
+		p := &model.Project{
+			Containers: []model.Container{{Name: "c1", Resources: &model.ContainerResources{CPU: 512, MemoryMB: 8192}}},
+		}
+		errs := checkContainerCapacity(p, ref, false)
+		require.Len(t, errs, 1)
+		assert.Equal(t, Warning, errs[0].Level)
+		assert.Contains(t, errs[0].Message, "memory")
+	})
+	t.Run("WarnsWithVariantLargerThanPool", func(t *testing.T) {
+		p := &model.Project{
+			Containers: []model.Container{{Name: "c1", Size: "medium"}},
+			BuildVariants: model.BuildVariants{
+				{
+					Name:  "bv",
+					RunOn: []string{"c1"},
+					Tasks: []model.BuildVariantTaskUnit{
+						{Name: "t1"},
+						{Name: "t2"},
+						{Name: "t3"},
+						{Name: "t4", RunOn: []string{"distro"}},
+						{Name: "t5", Disable: utility.TruePtr()},
+					},
+				},
+			},
+		}
+		errs := checkContainerCapacity(p, ref, false)
+		require.Len(t, errs, 1)
+		assert.Equal(t, Warning, errs[0].Level)
+		assert.Contains(t, errs[0].Message, "3 container tasks")
+		assert.Contains(t, errs[0].Message, "3072 CPU units")
+	})
 }
 
 func TestValidatePluginCommands(t *testing.T) {
@@ -1533,6 +1866,29 @@ func TestValidatePluginCommands(t *testing.T) {
 			So(validatePluginCommands(project), ShouldNotResemble, ValidationErrors{})
 			So(len(validatePluginCommands(project)), ShouldEqual, 1)
 		})
+		Convey("an error should be thrown if a command uses an unknown expansion transform", func() {
+			project := &model.Project{
+				Tasks: []model.ProjectTask{
+					{
+						Name: "compile",
+						Commands: []model.PluginCommandConf{
+							{
+								Command: "shell.exec",
+								Params: map[string]interface{}{
+									"script": "echo ${branch_name::trim_prefix:release-|lowr}",
+									"env": map[string]interface{}{
+										"BRANCH": "${branch_name::lower}",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			errs := validatePluginCommands(project)
+			So(len(errs), ShouldEqual, 1)
+			So(errs[0].Message, ShouldContainSubstring, "unknown expansion transform 'lowr'")
+		})
 		Convey("an error should be thrown if a shell.exec command has misspelled params", func() {
 			exampleYml := `
 tasks:
@@ -1974,11 +2330,12 @@ func TestCheckProjectWarnings(t *testing.T) {
 
 			_, project, err := model.FindLatestVersionWithValidProject(projectRef.Id)
 			So(err, ShouldBeNil)
-			So(CheckProjectWarnings(project), ShouldResemble, ValidationErrors{})
+			So(CheckProjectWarnings(context.Background(), project, nil), ShouldResemble, ValidationErrors{})
 		})
 
 		Reset(func() {
 			So(db.Clear(distro.Collection), ShouldBeNil)
+			distro.InvalidateCache()
 		})
 	})
 }
@@ -2049,6 +2406,60 @@ func (s *validateProjectFieldsuite) TestWarnOnLargeBatchTimeValue() {
 		"Large batch time validation error should be a warning")
 }
 
+func TestValidateBVCanaries(t *testing.T) {
+	batchtime := 60
+	p := &model.Project{
+		BuildVariants: []model.BuildVariant{
+			{
+				Name:   "expensive",
+				Canary: &model.CanaryActivation{Every: 10},
+				Tasks:  []model.BuildVariantTaskUnit{{Name: "t1"}},
+			},
+		},
+	}
+	assert.Empty(t, validateBVCanaries(p))
+
+	// can't have a canary that doesn't sample anything
+	p.BuildVariants[0].Canary.Every = 0
+	errs := validateBVCanaries(p)
+	require.Len(t, errs, 1)
+	assert.Equal(t, Error, errs[0].Level)
+	p.BuildVariants[0].Canary.Every = 10
+
+	// warning if the variant is never activated automatically
+	p.BuildVariants[0].Activate = utility.FalsePtr()
+	errs = validateBVCanaries(p)
+	require.Len(t, errs, 1)
+	assert.Equal(t, Warning, errs[0].Level)
+	assert.Contains(t, errs[0].Message, "never activated")
+	p.BuildVariants[0].Activate = nil
+
+	// warning if sampling is combined with a batchtime
+	p.BuildVariants[0].BatchTime = &batchtime
+	errs = validateBVCanaries(p)
+	require.Len(t, errs, 1)
+	assert.Equal(t, Warning, errs[0].Level)
+	assert.Contains(t, errs[0].Message, "60 minutes")
+	p.BuildVariants[0].BatchTime = nil
+
+	// warning if canary hours are combined with a cron
+	p.BuildVariants[0].Canary.StartHour = utility.ToIntPtr(22)
+	p.BuildVariants[0].Canary.EndHour = utility.ToIntPtr(6)
+	p.BuildVariants[0].CronBatchTime = "@daily"
+	errs = validateBVCanaries(p)
+	require.Len(t, errs, 1)
+	assert.Equal(t, Warning, errs[0].Level)
+	assert.Contains(t, errs[0].Message, "canary hours")
+	p.BuildVariants[0].CronBatchTime = ""
+
+	// warning if a task batchtime only applies to sampled versions
+	p.BuildVariants[0].Tasks[0].BatchTime = &batchtime
+	errs = validateBVCanaries(p)
+	require.Len(t, errs, 1)
+	assert.Equal(t, Warning, errs[0].Level)
+	assert.Contains(t, errs[0].Message, "task 't1'")
+}
+
 func TestValidateBVFields(t *testing.T) {
 	Convey("When ensuring necessary buildvariant fields are set, ensure that", t, func() {
 		Convey("an error is thrown if no build variants exist", func() {
@@ -2197,7 +2608,7 @@ func TestValidateBVFields(t *testing.T) {
 			}
 			So(validateBVFields(project),
 				ShouldResemble, ValidationErrors{
-					{Level: Error, Message: "buildvariant 'bv1' must either specify run_on field or have every task specify run_on"},
+					{Level: Error, Code: CodeBVMissingRunOn, Message: "buildvariant 'bv1' must either specify run_on field or have every task specify run_on"},
 				})
 		})
 	})
@@ -2417,9 +2828,9 @@ buildvariants:
 	assert.Len(tg.Tasks, 2)
 	assert.Equal("not_in_a_task_group", proj.Tasks[0].Name)
 	assert.Equal("task_in_a_task_group_1", proj.Tasks[0].DependsOn[0].Name)
-	errors := CheckProjectErrors(&proj, false)
+	errors := CheckProjectErrors(context.Background(), &proj, nil, false)
 	assert.Len(errors, 0)
-	warnings := CheckProjectWarnings(&proj)
+	warnings := CheckProjectWarnings(context.Background(), &proj, nil)
 	assert.Len(warnings, 0)
 }
 
@@ -2473,12 +2884,12 @@ buildvariants:
 	proj.BuildVariants[0].DisplayTasks[0].ExecTasks = append(proj.BuildVariants[0].DisplayTasks[0].ExecTasks,
 		"display_three")
 
-	errors := CheckProjectErrors(&proj, false)
+	errors := CheckProjectErrors(context.Background(), &proj, nil, false)
 	assert.Len(errors, 1)
 	assert.Equal(errors[0].Level, Error)
 	assert.Equal("execution task 'display_three' has prefix 'display_' which is invalid",
 		errors[0].Message)
-	warnings := CheckProjectWarnings(&proj)
+	warnings := CheckProjectWarnings(context.Background(), &proj, nil)
 	assert.Len(warnings, 0)
 }
 
@@ -2711,11 +3122,11 @@ buildvariants:
 	require.NoError(err)
 	assert.NotEmpty(proj)
 	assert.NotNil(pp)
-	errs := CheckProjectErrors(&proj, false)
+	errs := CheckProjectErrors(context.Background(), &proj, nil, false)
 	assert.Len(errs, 0, "no errors were found")
-	errs = CheckProjectWarnings(&proj)
+	errs = CheckProjectWarnings(context.Background(), &proj, nil)
 	assert.Len(errs, 2, "two warnings were found")
-	assert.NoError(CheckProjectConfigurationIsValid(&proj, &model.ProjectRef{}), "no errors are reported because they are warnings")
+	assert.NoError(CheckProjectConfigurationIsValid(context.Background(), &proj, &model.ProjectRef{}), "no errors are reported because they are warnings")
 
 	exampleYml = `
 tasks:
@@ -2734,7 +3145,7 @@ buildvariants:
 	require.NoError(err)
 	assert.NotNil(pp)
 	assert.NotEmpty(proj)
-	assert.Error(CheckProjectConfigurationIsValid(&proj, &model.ProjectRef{}))
+	assert.Error(CheckProjectConfigurationIsValid(context.Background(), &proj, &model.ProjectRef{}))
 }
 
 func TestGetDistrosForProject(t *testing.T) {
@@ -2845,6 +3256,138 @@ func TestValidateVersionControl(t *testing.T) {
 
 }
 
+func TestValidateVariantActivationHooks(t *testing.T) {
+	project := &model.Project{
+		BuildVariants: []model.BuildVariant{{Name: "deploy"}},
+	}
+	ref := &model.ProjectRef{
+		Identifier: "proj",
+		VariantActivationHooks: []model.VariantActivationHook{
+			{BuildVariants: []string{"deploy"}, URL: "https://change-management.example.com/approve"},
+		},
+	}
+	assert.Empty(t, validateVariantActivationHooks(project, ref, false))
+
+	ref.VariantActivationHooks = []model.VariantActivationHook{
+		{BuildVariants: []string{"deploy", "nonexistent"}, URL: "not a url"},
+		{URL: "ftp://example.com", TimeoutSecs: -1},
+		{BuildVariants: []string{"deploy"}, URL: "http://169.254.169.254/latest/meta-data"},
+	}
+	verrs := validateVariantActivationHooks(project, ref, false)
+	require.Len(t, verrs, 6)
+	assert.Equal(t, "activation hook 0 has invalid URL 'not a url'", verrs[0].Message)
+	assert.Equal(t, "activation hook 0 refers to build variant 'nonexistent', which does not exist", verrs[1].Message)
+	assert.Equal(t, "activation hook 1 has invalid URL 'ftp://example.com'", verrs[2].Message)
+	assert.Equal(t, "activation hook 1 cannot have a negative timeout", verrs[3].Message)
+	assert.Equal(t, "activation hook 1 must apply to at least one build variant", verrs[4].Message)
+	assert.Equal(t, "activation hook 2 has invalid URL 'http://169.254.169.254/latest/meta-data'", verrs[5].Message)
+}
+
+func TestValidateRestrictedVars(t *testing.T) {
+	require.NoError(t, db.ClearCollections(model.ProjectRefCollection, model.ProjectVarsCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(model.ProjectRefCollection, model.ProjectVarsCollection))
+	}()
+	ref := &model.ProjectRef{Id: "proj", Identifier: "proj"}
+	require.NoError(t, ref.Insert())
+	vars := &model.ProjectVars{
+		Id:   ref.Id,
+		Vars: map[string]string{"deploy_key": "secret", "normal": "value"},
+		RestrictedVars: map[string]model.ProjectVarRestriction{
+			"deploy_key": {TaskRegex: "^deploy$"},
+		},
+	}
+	require.NoError(t, vars.Insert())
+
+	project := &model.Project{
+		Functions: map[string]*model.YAMLCommandSet{
+			"upload": {SingleCommand: &model.PluginCommandConf{
+				Command: "shell.exec",
+				Params:  map[string]interface{}{"script": "upload --key ${deploy_key} --region ${region|us-east-1}"},
+			}},
+		},
+		Tasks: []model.ProjectTask{
+			{Name: "deploy", Commands: []model.PluginCommandConf{{Function: "upload"}}},
+			{Name: "test", Commands: []model.PluginCommandConf{
+				{Command: "shell.exec", Params: map[string]interface{}{"script": "echo ${normal}"}},
+			}},
+			{Name: "leaky", Commands: []model.PluginCommandConf{
+				{Command: "shell.exec", Params: map[string]interface{}{"script": "echo ${deploy_key}"}},
+			}},
+		},
+		BuildVariants: []model.BuildVariant{
+			{Name: "bv", Tasks: []model.BuildVariantTaskUnit{{Name: "deploy"}, {Name: "test"}, {Name: "leaky"}}},
+		},
+	}
+	verrs := validateRestrictedVars(project, ref, false)
+	require.Len(t, verrs, 1)
+	assert.Equal(t, Warning, verrs[0].Level)
+	assert.Equal(t, "task 'leaky' in build variant 'bv' references project variable 'deploy_key', which is restricted to other tasks", verrs[0].Message)
+
+	assert.Empty(t, validateRestrictedVars(project, &model.ProjectRef{}, false))
+}
+
+func TestCheckExecTimeoutsAgainstRuntimes(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection))
+	}()
+	ref := &model.ProjectRef{Id: "proj", Identifier: "proj"}
+	for _, name := range []string{"wasteful", "risky", "fine", "sparse"} {
+		numTasks := execTimeoutMinRuntimeSamples
+		if name == "sparse" {
+			numTasks = execTimeoutMinRuntimeSamples - 1
+		}
+		for i := 0; i < numTasks; i++ {
+			tsk := task.Task{
+				Id:           fmt.Sprintf("%s_%d", name, i),
+				Project:      ref.Id,
+				BuildVariant: "bv",
+				DisplayName:  name,
+				Status:       evergreen.TaskSucceeded,
+				FinishTime:   time.Now().Add(-time.Hour),
+				TimeTaken:    10 * time.Minute,
+			}
+			require.NoError(t, tsk.Insert())
+		}
+	}
+
+	project := &model.Project{
+		ExecTimeoutSecs: 3600,
+		Tasks: []model.ProjectTask{
+			{Name: "wasteful", ExecTimeoutSecs: 7200},
+			{Name: "risky"},
+			{Name: "fine"},
+			{Name: "sparse", ExecTimeoutSecs: 7200},
+		},
+		BuildVariants: []model.BuildVariant{
+			{Name: "bv", Tasks: []model.BuildVariantTaskUnit{
+				{Name: "wasteful"},
+				{Name: "risky", ExecTimeoutSecs: 660},
+				{Name: "fine"},
+				{Name: "sparse"},
+			}},
+		},
+	}
+	verrs := checkExecTimeoutsAgainstRuntimes(project, ref, false)
+	require.Len(t, verrs, 2)
+	assert.Equal(t, Warning, verrs[0].Level)
+	assert.Equal(t, "task 'wasteful' in build variant 'bv' has an exec timeout of 2h0m0s, which is 12.0x its P95 runtime of 10m0s over its last 10 successful runs; a shorter timeout would catch hung tasks sooner", verrs[0].Message)
+	assert.Equal(t, Warning, verrs[1].Level)
+	assert.Equal(t, "task 'risky' in build variant 'bv' has an exec timeout of 11m0s, which is only 1.1x its P95 runtime of 10m0s over its last 10 successful runs; the task risks timing out spuriously", verrs[1].Message)
+
+	assert.Empty(t, checkExecTimeoutsAgainstRuntimes(project, &model.ProjectRef{}, false))
+	assert.Empty(t, checkExecTimeoutsAgainstRuntimes(project, &model.ProjectRef{Id: "other"}, false))
+
+	t.Run("OnlyRunsOnExplicitValidation", func(t *testing.T) {
+		for _, verr := range CheckProjectSettings(context.Background(), project, ref, false) {
+			assert.NotEqual(t, CodeExecTimeoutTooLong, verr.Code)
+			assert.NotEqual(t, CodeExecTimeoutTooShort, verr.Code)
+		}
+		assert.Len(t, CheckProjectHistory(context.Background(), project, ref), 2)
+	})
+}
+
 func TestValidateContainers(t *testing.T) {
 	require.NoError(t, db.Clear(model.ProjectRefCollection))
 	ref := &model.ProjectRef{
@@ -2860,6 +3403,12 @@ func TestValidateContainers(t *testing.T) {
 				Username: "foo",
 				Password: "bar",
 			},
+			"external": model.ContainerCredential{
+				ExternalSecret: "arn:aws:secretsmanager:us-east-1:123456789012:secret:team/registry-creds-AbCdEf",
+			},
+			"malformed": model.ContainerCredential{
+				ExternalSecret: "team//registry creds",
+			},
 		},
 	}
 	require.NoError(t, ref.Insert())
@@ -2907,6 +3456,14 @@ func TestValidateContainers(t *testing.T) {
 	verrs = validateContainers(p, ref, false)
 	require.Len(t, verrs, 1)
 	assert.Contains(t, verrs[0].Message, "credential 'c2' is not defined anywhere")
+	p.Containers[0].Size = "s1"
+	p.Containers[0].Credential = "external"
+	verrs = validateContainers(p, ref, false)
+	assert.Len(t, verrs, 0)
+	p.Containers[0].Credential = "malformed"
+	verrs = validateContainers(p, ref, false)
+	require.Len(t, verrs, 1)
+	assert.Contains(t, verrs[0].Message, "invalid credential 'malformed'")
 	p.Containers[0].System = model.ContainerSystem{
 		OperatingSystem: "oops",
 		CPUArchitecture: "oops",
@@ -4808,3 +5365,300 @@ func TestValidationErrorsAtLevel(t *testing.T) {
 		assert.Empty(t, errs.AtLevel(Error))
 	})
 }
+
+func TestCheckAliasTags(t *testing.T) {
+	p := &model.Project{
+		Tasks: []model.ProjectTask{
+			{Name: "compile", Tags: []string{"primary"}},
+		},
+		TaskGroups: []model.TaskGroup{
+			{Name: "integration", Tags: []string{"grouped"}},
+		},
+		BuildVariants: []model.BuildVariant{
+			{Name: "ubuntu", Tags: []string{"linux"}},
+		},
+		PatchAliases: []model.ProjectAlias{
+			{Alias: "used", TaskTags: []string{"primary", "!grouped"}, VariantTags: []string{"linux"}},
+			{Alias: "unused", TaskTags: []string{"experimental"}, VariantTags: []string{"!windows"}},
+		},
+	}
+
+	errs := checkAliasTags(p)
+	require.Len(t, errs, 2)
+	for _, err := range errs {
+		assert.Equal(t, Warning, err.Level)
+		assert.Contains(t, err.Message, "alias 'unused'")
+	}
+	assert.Contains(t, errs[0].Message, "tag 'experimental'")
+	assert.Contains(t, errs[1].Message, "tag '!windows'")
+}
+
+func TestValidateExternalGates(t *testing.T) {
+	t.Run("ValidGates", func(t *testing.T) {
+		project := &model.Project{
+			ExternalGates: []model.ExternalGate{
+				{Name: "artifact-repo-ready", TimeoutSecs: 3600, OnTimeout: model.ExternalGateOnTimeoutOpen},
+				{Name: "release-approved"},
+			},
+			VersionGates: []string{"release-approved"},
+			BuildVariants: []model.BuildVariant{
+				{Name: "bv", ExternalGates: []string{"artifact-repo-ready"}},
+			},
+		}
+		assert.Empty(t, validateExternalGates(project))
+	})
+	t.Run("UnknownGateNames", func(t *testing.T) {
+		project := &model.Project{
+			ExternalGates: []model.ExternalGate{{Name: "artifact-repo-ready"}},
+			VersionGates:  []string{"release-approved"},
+			BuildVariants: []model.BuildVariant{
+				{Name: "bv", ExternalGates: []string{"artifact-repo-ready", "artifact-repo-redy"}},
+			},
+		}
+		verrs := validateExternalGates(project)
+		require.Len(t, verrs, 2)
+		assert.Equal(t, Error, verrs[0].Level)
+		assert.Equal(t, "version gate 'release-approved' is not a defined external gate", verrs[0].Message)
+		assert.Equal(t, "build variant 'bv' uses external gate 'artifact-repo-redy', which is not defined", verrs[1].Message)
+	})
+	t.Run("InvalidDefinitions", func(t *testing.T) {
+		project := &model.Project{
+			ExternalGates: []model.ExternalGate{
+				{Name: ""},
+				{Name: "gate", TimeoutSecs: -1},
+				{Name: "gate", OnTimeout: "retry"},
+			},
+		}
+		verrs := validateExternalGates(project)
+		require.Len(t, verrs, 4)
+		for _, verr := range verrs {
+			assert.Equal(t, Error, verr.Level)
+		}
+	})
+}
+
+func TestValidateStages(t *testing.T) {
+	compile := model.TVPair{Variant: "linux", TaskName: "compile"}
+	unit := model.TVPair{Variant: "linux", TaskName: "unit"}
+	deploy := model.TVPair{Variant: "linux", TaskName: "deploy"}
+	makeProject := func() *model.Project {
+		return &model.Project{
+			Stages: []model.PipelineStage{
+				{Name: "build", Tasks: []model.TVPair{compile}},
+				{Name: "test", After: []string{"build"}, Tasks: []model.TVPair{unit}},
+				{Name: "deploy", After: []string{"test"}, Tasks: []model.TVPair{deploy}},
+			},
+		}
+	}
+	assert.Empty(t, validateStages(makeProject()))
+	assert.Empty(t, validateStages(&model.Project{}))
+
+	t.Run("Cycle", func(t *testing.T) {
+		p := makeProject()
+		p.Stages[0].After = []string{"deploy"}
+		errs := validateStages(p)
+		require.Len(t, errs, 1)
+		assert.Equal(t, Error, errs[0].Level)
+		assert.Contains(t, errs[0].Message, "stages [build, deploy, test] form a cycle")
+	})
+	t.Run("AfterItself", func(t *testing.T) {
+		p := makeProject()
+		p.Stages[1].After = []string{"test"}
+		errs := validateStages(p)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Message, "cannot run after itself")
+	})
+	t.Run("AfterUndefinedStage", func(t *testing.T) {
+		p := makeProject()
+		p.Stages[2].After = []string{"package"}
+		errs := validateStages(p)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Message, "'package', which is not defined")
+	})
+	t.Run("DuplicateName", func(t *testing.T) {
+		p := makeProject()
+		p.Stages = append(p.Stages, model.PipelineStage{Name: "build", Tasks: []model.TVPair{{Variant: "windows", TaskName: "compile"}}})
+		errs := validateStages(p)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Message, "defined more than once")
+	})
+	t.Run("NoTasks", func(t *testing.T) {
+		p := makeProject()
+		p.Stages[1].Tasks = nil
+		errs := validateStages(p)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Message, "does not select any tasks")
+	})
+	t.Run("TaskInMultipleStages", func(t *testing.T) {
+		p := makeProject()
+		p.Stages[2].Tasks = append(p.Stages[2].Tasks, unit)
+		errs := validateStages(p)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Message, "in both stage 'test' and stage 'deploy'")
+	})
+}
+
+func TestValidateRunOnStrategies(t *testing.T) {
+	project := &model.Project{
+		BuildVariants: []model.BuildVariant{
+			{
+				Name:          "bv",
+				RunOn:         []string{"d0", "d1"},
+				RunOnStrategy: model.RunOnStrategyRoundRobin,
+				Tasks: []model.BuildVariantTaskUnit{
+					{Name: "compile", RunOnStrategy: model.RunOnStrategyWeighted},
+					{Name: "test"},
+				},
+			},
+		},
+	}
+	assert.Empty(t, validateRunOnStrategies(project))
+
+	project.BuildVariants[0].RunOnStrategy = "random"
+	project.BuildVariants[0].Tasks[1].RunOnStrategy = "fastest"
+	errs := validateRunOnStrategies(project)
+	require.Len(t, errs, 2)
+	assert.Equal(t, Error, errs[0].Level)
+	assert.Contains(t, errs[0].Message, "random")
+	assert.Contains(t, errs[1].Message, "fastest")
+}
+
+func TestValidateArtifactNamespaces(t *testing.T) {
+	project := &model.Project{
+		BuildVariants: []model.BuildVariant{
+			{Name: "bv0"},
+			{Name: "bv1", ArtifactNamespace: "linux/x86_64"},
+		},
+	}
+	assert.Empty(t, validateArtifactNamespaces(project))
+
+	project.BuildVariants[0].ArtifactNamespace = "/linux"
+	project.BuildVariants[1].ArtifactNamespace = "linux x86"
+	errs := validateArtifactNamespaces(project)
+	require.Len(t, errs, 2)
+	assert.Equal(t, Error, errs[0].Level)
+	assert.Contains(t, errs[0].Message, "bv0")
+	assert.Contains(t, errs[1].Message, "bv1")
+}
+
+func TestCheckArtifactDestinations(t *testing.T) {
+	s3Put := func(remoteFile string) model.PluginCommandConf {
+		return model.PluginCommandConf{
+			Command: s3PutCommandName,
+			Params: map[string]interface{}{
+				"bucket":      "artifacts",
+				"remote_file": remoteFile,
+			},
+		}
+	}
+	makeProject := func(cmds ...model.PluginCommandConf) *model.Project {
+		return &model.Project{
+			Tasks: []model.ProjectTask{{Name: "compile", Commands: cmds}},
+			BuildVariants: []model.BuildVariant{
+				{Name: "bv0", Tasks: []model.BuildVariantTaskUnit{{Name: "compile"}}},
+				{Name: "bv1", Tasks: []model.BuildVariantTaskUnit{{Name: "compile"}}},
+			},
+		}
+	}
+
+	t.Run("WarnsForSharedDestination", func(t *testing.T) {
+		errs := checkArtifactDestinations(makeProject(s3Put("${revision}/${task_name}.tgz")))
+		require.Len(t, errs, 1)
+		assert.Equal(t, Warning, errs[0].Level)
+		assert.Contains(t, errs[0].Message, "'bv0', 'bv1'")
+		assert.Contains(t, errs[0].Message, "artifacts/${revision}/compile.tgz")
+		assert.Contains(t, errs[0].Message, "${artifact_namespace}")
+	})
+	t.Run("IgnoresPerVariantDestinations", func(t *testing.T) {
+		assert.Empty(t, checkArtifactDestinations(makeProject(s3Put("${build_variant}/compile.tgz"))))
+		assert.Empty(t, checkArtifactDestinations(makeProject(s3Put("${artifact_namespace}/compile.tgz"))))
+		assert.Empty(t, checkArtifactDestinations(makeProject(s3Put("${task_id}.tgz"))))
+	})
+	t.Run("WarnsForSharedArtifactNamespace", func(t *testing.T) {
+		project := makeProject(s3Put("${artifact_namespace}/compile.tgz"))
+		project.BuildVariants[0].ArtifactNamespace = "linux"
+		project.BuildVariants[1].ArtifactNamespace = "linux"
+		errs := checkArtifactDestinations(project)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Message, "artifacts/linux/compile.tgz")
+	})
+	t.Run("UsesVariantExpansions", func(t *testing.T) {
+		project := makeProject(s3Put("${platform}/compile.tgz"))
+		project.BuildVariants[0].Expansions = map[string]string{"platform": "linux"}
+		project.BuildVariants[1].Expansions = map[string]string{"platform": "windows"}
+		assert.Empty(t, checkArtifactDestinations(project))
+
+		project.BuildVariants[1].Expansions["platform"] = "linux"
+		assert.Len(t, checkArtifactDestinations(project), 1)
+	})
+	t.Run("IgnoresUnknownExpansions", func(t *testing.T) {
+		assert.Empty(t, checkArtifactDestinations(makeProject(s3Put("${distro_id}/compile.tgz"))))
+	})
+	t.Run("RespectsBuildVariantFilter", func(t *testing.T) {
+		cmd := s3Put("compile.tgz")
+		cmd.Params["build_variants"] = []interface{}{"bv0"}
+		assert.Empty(t, checkArtifactDestinations(makeProject(cmd)))
+	})
+	t.Run("ChecksS3CopyDestinations", func(t *testing.T) {
+		cmd := model.PluginCommandConf{
+			Command: s3CopyCommandName,
+			Params: map[string]interface{}{
+				"s3_copy_files": []interface{}{
+					map[string]interface{}{
+						"source":      map[string]interface{}{"bucket": "staging", "path": "${build_variant}/compile.tgz"},
+						"destination": map[string]interface{}{"bucket": "release", "path": "compile.tgz"},
+					},
+				},
+			},
+		}
+		errs := checkArtifactDestinations(makeProject(cmd))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Message, "release/compile.tgz")
+	})
+}
+
+func TestCheckSelectorTags(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	projYml := `
+tasks:
+- name: compile
+  tags: ["primary"]
+- name: test
+  depends_on:
+  - name: ".missing_dep"
+    variant: ".missing_variant"
+task_groups:
+- name: tg
+  tags: ["grouped"]
+  tasks: [".primary"]
+buildvariants:
+- name: ubuntu
+  tags: ["linux"]
+  tasks:
+  - name: ".grouped"
+  - name: ".primary !.typo"
+  depends_on:
+  - name: compile
+    variant: ".linux"
+`
+	proj := model.Project{}
+	pp, err := model.LoadProjectInto(ctx, []byte(projYml), nil, "", &proj)
+	require.Error(t, err, "selecting by unused tags should fail to translate")
+	require.NotNil(t, pp)
+
+	errs := CheckSelectorTags(pp)
+	require.Len(t, errs, 3)
+	for _, err := range errs {
+		assert.Equal(t, Warning, err.Level)
+	}
+	assert.Equal(t, CodeSelectorUndefinedTaskTag, errs[0].Code)
+	assert.Contains(t, errs[0].Message, "a dependency of task 'test'")
+	assert.Contains(t, errs[0].Message, "tag 'missing_dep'")
+	assert.Equal(t, CodeSelectorUndefinedTaskTag, errs[1].Code)
+	assert.Contains(t, errs[1].Message, "build variant 'ubuntu'")
+	assert.Contains(t, errs[1].Message, "tag 'typo'")
+	assert.Equal(t, CodeSelectorUndefinedVariantTag, errs[2].Code)
+	assert.Contains(t, errs[2].Message, "tag 'missing_variant'")
+}
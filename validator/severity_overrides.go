@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/model"
+)
+
+// labelRule records the validation rule that produced each result that
+// doesn't already name one. Rules that report results from helpers that can
+// be overridden separately, such as checkBVNames, label them first.
+func labelRule(rule interface{}, errs ValidationErrors) ValidationErrors {
+	name := validatorName(rule)
+	for i := range errs {
+		if errs[i].Rule == "" {
+			errs[i].Rule = name
+		}
+	}
+	return errs
+}
+
+// overridableRules returns the names of the validation rules whose severity a
+// project can override.
+func overridableRules() []string {
+	var rules []interface{}
+	for _, rule := range projectErrorValidators {
+		rules = append(rules, rule)
+	}
+	for _, rule := range longErrorValidators {
+		rules = append(rules, rule)
+	}
+	for _, rule := range projectWarningValidators {
+		rules = append(rules, rule)
+	}
+	for _, rule := range projectSettingsValidators {
+		rules = append(rules, rule)
+	}
+	for _, rule := range projectHistoryValidators {
+		rules = append(rules, rule)
+	}
+	rules = append(rules, ensureReferentialIntegrity, checkBVNames)
+
+	names := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		names = append(names, validatorName(rule))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applySeverityOverrides changes the level of the results of each rule that
+// the project overrides the severity of.
+func applySeverityOverrides(errs ValidationErrors, ref *model.ProjectRef) ValidationErrors {
+	if ref == nil || len(ref.ValidationSeverityOverrides) == 0 {
+		return errs
+	}
+	for i := range errs {
+		switch ref.ValidationSeverityOverrides[errs[i].Rule] {
+		case model.ValidationSeverityError:
+			errs[i].Level = Error
+		case model.ValidationSeverityWarning:
+			errs[i].Level = Warning
+		}
+	}
+	return errs
+}
+
+// checkValidationSeverityOverrides warns about severity overrides that don't
+// name a known rule or severity, since they have no effect.
+func checkValidationSeverityOverrides(_ *model.Project, ref *model.ProjectRef, _ bool) ValidationErrors {
+	if ref == nil || len(ref.ValidationSeverityOverrides) == 0 {
+		return nil
+	}
+	known := map[string]bool{}
+	for _, rule := range overridableRules() {
+		known[rule] = true
+	}
+
+	rules := make([]string, 0, len(ref.ValidationSeverityOverrides))
+	for rule := range ref.ValidationSeverityOverrides {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+
+	errs := ValidationErrors{}
+	for _, rule := range rules {
+		if !known[rule] {
+			errs = append(errs, ValidationError{
+				Level:   Warning,
+				Code:    CodeSeverityOverrideUnknownRule,
+				Message: fmt.Sprintf("validation severity override names unknown rule '%s'", rule),
+			})
+		}
+		switch severity := ref.ValidationSeverityOverrides[rule]; severity {
+		case model.ValidationSeverityError, model.ValidationSeverityWarning:
+		default:
+			errs = append(errs, ValidationError{
+				Level: Warning,
+				Code:  CodeSeverityOverrideInvalidSeverity,
+				Message: fmt.Sprintf("validation severity override for rule '%s' has invalid severity '%s', must be '%s' or '%s'",
+					rule, severity, model.ValidationSeverityError, model.ValidationSeverityWarning),
+			})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeverityOverrides(t *testing.T) {
+	project := &model.Project{
+		Tasks: []model.ProjectTask{{Name: "t1"}, {Name: "t2"}},
+		TaskGroups: []model.TaskGroup{
+			{Name: "tg", MaxHosts: 3, Tasks: []string{"t1", "t2"}},
+		},
+		BuildVariants: []model.BuildVariant{
+			{
+				Name:        "a,b",
+				DisplayName: "a,b",
+				Tasks:       []model.BuildVariantTaskUnit{{Name: "tg", Variant: "a,b", IsGroup: true}},
+			},
+		},
+	}
+	find := func(errs ValidationErrors, rule string) ValidationErrors {
+		found := ValidationErrors{}
+		for _, err := range errs {
+			if err.Rule == rule {
+				found = append(found, err)
+			}
+		}
+		return found
+	}
+
+	t.Run("LabelsRules", func(t *testing.T) {
+		errs := CheckProjectWarnings(context.Background(), project, nil)
+		taskGroupErrs := find(errs, "checkTaskGroups")
+		require.Len(t, taskGroupErrs, 1)
+		assert.Equal(t, Warning, taskGroupErrs[0].Level)
+		bvNameErrs := find(errs, "checkBVNames")
+		require.Len(t, bvNameErrs, 1)
+		assert.Equal(t, Warning, bvNameErrs[0].Level)
+	})
+	t.Run("PromotesWarnings", func(t *testing.T) {
+		ref := &model.ProjectRef{ValidationSeverityOverrides: map[string]string{
+			"checkTaskGroups": model.ValidationSeverityError,
+		}}
+		errs := CheckProjectWarnings(context.Background(), project, ref)
+		taskGroupErrs := find(errs, "checkTaskGroups")
+		require.Len(t, taskGroupErrs, 1)
+		assert.Equal(t, Error, taskGroupErrs[0].Level)
+		bvNameErrs := find(errs, "checkBVNames")
+		require.Len(t, bvNameErrs, 1)
+		assert.Equal(t, Warning, bvNameErrs[0].Level, "results of other rules should not change")
+	})
+	t.Run("DemotesErrors", func(t *testing.T) {
+		errs := applySeverityOverrides(ValidationErrors{
+			{Level: Error, Rule: "validateBVNames", Message: "buildvariant 'bv' does not have a display name"},
+		}, &model.ProjectRef{ValidationSeverityOverrides: map[string]string{
+			"validateBVNames": model.ValidationSeverityWarning,
+		}})
+		require.Len(t, errs, 1)
+		assert.Equal(t, Warning, errs[0].Level)
+		assert.False(t, errs.HasError())
+	})
+}
+
+func TestCheckValidationSeverityOverrides(t *testing.T) {
+	assert.Empty(t, checkValidationSeverityOverrides(&model.Project{}, nil, false))
+
+	ref := &model.ProjectRef{ValidationSeverityOverrides: map[string]string{
+		"checkTaskGroups":    model.ValidationSeverityError,
+		"checkBVNames":       model.ValidationSeverityError,
+		"validateBVNames":    model.ValidationSeverityWarning,
+		"checkNonexistent":   model.ValidationSeverityWarning,
+		"checkBuildVariants": "fatal",
+	}}
+	errs := checkValidationSeverityOverrides(&model.Project{}, ref, false)
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Message, "checkBuildVariants")
+	assert.Contains(t, errs[0].Message, "fatal")
+	assert.Contains(t, errs[1].Message, "checkNonexistent")
+	for _, err := range errs {
+		assert.Equal(t, Warning, err.Level)
+	}
+}
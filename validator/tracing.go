@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/evergreen-ci/evergreen/validator")
+
+// startValidationSpan starts the span for a set of checks on the project as a
+// child of the span in the context, such as the one for creating a version.
+// It's the parent of the spans for each validation rule.
+func startValidationSpan(ctx context.Context, name string, project *model.Project) (context.Context, trace.Span) {
+	var attrs []attribute.KeyValue
+	if project != nil {
+		attrs = append(attrs,
+			attribute.String(evergreen.ProjectIdentifierOtelAttribute, project.Identifier),
+			attribute.Int(evergreen.ProjectNumTasksOtelAttribute, len(project.Tasks)),
+			attribute.Int(evergreen.ProjectNumBuildVariantsOtelAttribute, len(project.BuildVariants)),
+		)
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// traceValidator runs the validation rule in its own span, recording how many
+// errors and warnings it found. The results are labeled with the rule.
+func traceValidator(ctx context.Context, rule interface{}, validate func() ValidationErrors) ValidationErrors {
+	_, span := tracer.Start(ctx, validatorName(rule))
+	defer span.End()
+
+	errs := labelRule(rule, validate())
+	setValidationAttributes(span, errs)
+	return errs
+}
+
+func setValidationAttributes(span trace.Span, errs ValidationErrors) {
+	span.SetAttributes(
+		attribute.Int(evergreen.ValidationNumErrorsOtelAttribute, len(errs.AtLevel(Error))),
+		attribute.Int(evergreen.ValidationNumWarningsOtelAttribute, len(errs.AtLevel(Warning))),
+	)
+}
+
+// validatorName returns the name of the validation rule's function.
+func validatorName(rule interface{}) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(rule).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	return name[strings.LastIndex(name, ".")+1:]
+}
@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestValidatorName(t *testing.T) {
+	assert.Equal(t, "checkTaskGroups", validatorName(checkTaskGroups))
+	assert.Equal(t, "validateContainers", validatorName(projectSettingsValidator(validateContainers)))
+}
+
+func TestCheckProjectWarningsTracesRules(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	project := &model.Project{
+		Identifier: "proj",
+		Tasks:      []model.ProjectTask{{Name: "task"}},
+	}
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "CreateVersion")
+	CheckProjectWarnings(ctx, project, nil)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, len(projectWarningValidators)+2)
+	check := spans[len(spans)-2]
+	assert.Equal(t, "CheckProjectWarnings", check.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), check.Parent().SpanID())
+	assert.Contains(t, check.Attributes(), attribute.String(evergreen.ProjectIdentifierOtelAttribute, "proj"))
+	assert.Contains(t, check.Attributes(), attribute.Int(evergreen.ProjectNumTasksOtelAttribute, 1))
+
+	for i, rule := range projectWarningValidators {
+		assert.Equal(t, validatorName(rule), spans[i].Name())
+		assert.Equal(t, check.SpanContext().SpanID(), spans[i].Parent().SpanID())
+	}
+}
@@ -0,0 +1,144 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model"
+)
+
+// aboutTask records the task that each result that doesn't already name one
+// is about, so that it can be suppressed for that task.
+func aboutTask(taskName string, errs ValidationErrors) ValidationErrors {
+	for i := range errs {
+		if errs[i].Task == "" {
+			errs[i].Task = taskName
+		}
+	}
+	return errs
+}
+
+// aboutVariant records the build variant that each result that doesn't
+// already name one is about, so that it can be suppressed for that variant.
+func aboutVariant(variant string, errs ValidationErrors) ValidationErrors {
+	for i := range errs {
+		if errs[i].BuildVariant == "" {
+			errs[i].BuildVariant = variant
+		}
+	}
+	return errs
+}
+
+// resolvedSuppression is a validation suppression with its selectors resolved
+// to the names of the tasks and build variants that they select. A nil set of
+// tasks or variants matches results about any task or variant.
+type resolvedSuppression struct {
+	codes    map[string]bool
+	tasks    map[string]bool
+	variants map[string]bool
+}
+
+func (s resolvedSuppression) matches(err ValidationError) bool {
+	if !s.codes[err.Code] {
+		return false
+	}
+	if s.tasks != nil && !s.tasks[err.Task] {
+		return false
+	}
+	if s.variants != nil && !s.variants[err.BuildVariant] {
+		return false
+	}
+	return true
+}
+
+// resolveValidationSuppressions resolves the project's validation
+// suppressions. It also returns warnings for the suppressions that can't
+// suppress anything as written.
+func resolveValidationSuppressions(project *model.Project) ([]resolvedSuppression, ValidationErrors) {
+	var suppressions []resolvedSuppression
+	errs := ValidationErrors{}
+	for i, suppression := range project.ValidationSuppressions {
+		if len(suppression.Codes) == 0 {
+			errs = append(errs, ValidationError{
+				Level:   Warning,
+				Code:    CodeValidationSuppressionNoCodes,
+				Message: fmt.Sprintf("validation suppression at index %d does not list any codes, so it has no effect", i),
+			})
+			continue
+		}
+
+		resolved := resolvedSuppression{codes: map[string]bool{}}
+		for _, code := range suppression.Codes {
+			resolved.codes[code] = true
+		}
+		if len(suppression.Tasks) > 0 {
+			resolved.tasks = map[string]bool{}
+		}
+		for _, selector := range suppression.Tasks {
+			tasks, taskGroups, err := project.EvaluateTaskSelector(selector)
+			if err != nil {
+				errs = append(errs, ValidationError{
+					Level:   Warning,
+					Code:    CodeValidationSuppressionInvalidSelector,
+					Message: fmt.Sprintf("validation suppression at index %d has an invalid task selector: %s", i, err.Error()),
+				})
+				continue
+			}
+			for _, name := range append(tasks, taskGroups...) {
+				resolved.tasks[name] = true
+			}
+		}
+		if len(suppression.Variants) > 0 {
+			resolved.variants = map[string]bool{}
+		}
+		for _, selector := range suppression.Variants {
+			variants, err := project.EvaluateVariantSelector(selector)
+			if err != nil {
+				errs = append(errs, ValidationError{
+					Level:   Warning,
+					Code:    CodeValidationSuppressionInvalidSelector,
+					Message: fmt.Sprintf("validation suppression at index %d has an invalid variant selector: %s", i, err.Error()),
+				})
+				continue
+			}
+			for _, name := range variants {
+				resolved.variants[name] = true
+			}
+		}
+		suppressions = append(suppressions, resolved)
+	}
+	return suppressions, errs
+}
+
+// applyValidationSuppressions removes the warnings that the project
+// suppresses. Errors can't be suppressed, including warnings whose severity
+// the project overrides to be an error.
+func applyValidationSuppressions(errs ValidationErrors, project *model.Project) ValidationErrors {
+	if project == nil || len(project.ValidationSuppressions) == 0 {
+		return errs
+	}
+	suppressions, _ := resolveValidationSuppressions(project)
+	remaining := ValidationErrors{}
+	for _, err := range errs {
+		if err.Level != Error && isSuppressed(err, suppressions) {
+			continue
+		}
+		remaining = append(remaining, err)
+	}
+	return remaining
+}
+
+func isSuppressed(err ValidationError, suppressions []resolvedSuppression) bool {
+	for _, suppression := range suppressions {
+		if suppression.matches(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkValidationSuppressions warns about validation suppressions that have
+// no effect.
+func checkValidationSuppressions(project *model.Project) ValidationErrors {
+	_, errs := resolveValidationSuppressions(project)
+	return errs
+}
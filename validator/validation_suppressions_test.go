@@ -0,0 +1,134 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationSuppressions(t *testing.T) {
+	yml := `
+tasks:
+- name: legacy_compile
+  tags: ["legacy"]
+- name: legacy_test
+  tags: ["legacy"]
+- name: compile
+buildvariants:
+- name: old_linux
+  display_name: Old Linux
+- name: linux
+  display_name: Linux
+validation_suppressions:
+- codes: ["TASK_NO_COMMANDS"]
+  tasks: [".legacy"]
+  reason: these tasks are generated
+- codes: ["BV_NO_TASKS"]
+  variants: ["old_linux"]
+`
+	project := &model.Project{}
+	_, err := model.LoadProjectInto(context.Background(), []byte(yml), nil, "", project)
+	require.NoError(t, err)
+	require.Len(t, project.ValidationSuppressions, 2)
+
+	find := func(errs ValidationErrors, code string) ValidationErrors {
+		found := ValidationErrors{}
+		for _, err := range errs {
+			if err.Code == code {
+				found = append(found, err)
+			}
+		}
+		return found
+	}
+
+	t.Run("LabelsTasksAndVariants", func(t *testing.T) {
+		errs := checkTasks(project)
+		noCommands := find(errs, CodeTaskNoCommands)
+		require.Len(t, noCommands, 3)
+		assert.Equal(t, "legacy_compile", noCommands[0].Task)
+
+		errs = checkBuildVariants(project)
+		noTasks := find(errs, CodeBVNoTasks)
+		require.Len(t, noTasks, 2)
+		assert.Equal(t, "old_linux", noTasks[0].BuildVariant)
+	})
+	t.Run("FiltersMatchingWarnings", func(t *testing.T) {
+		errs := CheckProjectWarnings(context.Background(), project, nil)
+		noCommands := find(errs, CodeTaskNoCommands)
+		require.Len(t, noCommands, 1)
+		assert.Equal(t, "compile", noCommands[0].Task)
+		noTasks := find(errs, CodeBVNoTasks)
+		require.Len(t, noTasks, 1)
+		assert.Equal(t, "linux", noTasks[0].BuildVariant)
+		assert.Empty(t, find(errs, CodeValidationSuppressionInvalidSelector))
+	})
+	t.Run("DoesNotFilterErrors", func(t *testing.T) {
+		ref := &model.ProjectRef{ValidationSeverityOverrides: map[string]string{
+			"checkTasks": model.ValidationSeverityError,
+		}}
+		errs := CheckProjectWarnings(context.Background(), project, ref)
+		assert.Len(t, find(errs, CodeTaskNoCommands), 3)
+	})
+	t.Run("ParsesStrictlyAndIntoProjectConfig", func(t *testing.T) {
+		strictProject := &model.Project{}
+		_, err := model.LoadProjectInto(context.Background(), []byte(yml), &model.GetProjectOpts{UnmarshalStrict: true}, "", strictProject)
+		require.NoError(t, err)
+		assert.Equal(t, project.ValidationSuppressions, strictProject.ValidationSuppressions)
+
+		pc, err := model.CreateProjectConfig([]byte(yml), "")
+		require.NoError(t, err)
+		assert.Nil(t, pc, "suppressions alone should not define a project config")
+
+		pc, err = model.CreateProjectConfig([]byte(yml+"\ngithub_trigger_aliases: [\"alias\"]\n"), "")
+		require.NoError(t, err)
+		require.NotNil(t, pc)
+		assert.Equal(t, project.ValidationSuppressions, pc.ValidationSuppressions)
+	})
+	t.Run("FiltersSettingsWarnings", func(t *testing.T) {
+		ref := &model.ProjectRef{Identifier: "project", VersionControlEnabled: utility.TruePtr()}
+		errs := CheckProjectSettings(context.Background(), project, ref, false)
+		require.Len(t, find(errs, CodeVersionControlUnused), 1)
+
+		p := *project
+		p.ValidationSuppressions = append([]model.ValidationSuppression{{Codes: []string{CodeVersionControlUnused}}}, project.ValidationSuppressions...)
+		errs = CheckProjectSettings(context.Background(), &p, ref, false)
+		assert.Empty(t, find(errs, CodeVersionControlUnused))
+	})
+	t.Run("LabelsReferentialIntegrityWarnings", func(t *testing.T) {
+		p := *project
+		p.BuildVariants = []model.BuildVariant{{
+			Name:  "linux",
+			RunOn: []string{"nonexistent"},
+			Tasks: []model.BuildVariantTaskUnit{{Name: "compile", Variant: "linux", RunOn: []string{"nonexistent"}}},
+		}}
+		errs := ensureReferentialIntegrity(&p, map[string]bool{}, nil, nil)
+		taskRunOn := find(errs, CodeTaskUndefinedRunOn)
+		require.Len(t, taskRunOn, 1)
+		assert.Equal(t, "compile", taskRunOn[0].Task)
+		assert.Equal(t, "linux", taskRunOn[0].BuildVariant)
+		bvRunOn := find(errs, CodeBVUndefinedRunOn)
+		require.Len(t, bvRunOn, 1)
+		assert.Equal(t, "linux", bvRunOn[0].BuildVariant)
+	})
+	t.Run("WarnsAboutIneffectiveSuppressions", func(t *testing.T) {
+		p := &model.Project{
+			Tasks:         project.Tasks,
+			BuildVariants: project.BuildVariants,
+			ValidationSuppressions: []model.ValidationSuppression{
+				{Tasks: []string{"compile"}},
+				{Codes: []string{CodeTaskNoCommands}, Tasks: []string{"nonexistent"}},
+				{Codes: []string{CodeBVNoTasks}, Variants: []string{".nonexistent"}},
+			},
+		}
+		errs := checkValidationSuppressions(p)
+		assert.Len(t, find(errs, CodeValidationSuppressionNoCodes), 1)
+		assert.Len(t, find(errs, CodeValidationSuppressionInvalidSelector), 2)
+
+		errs = CheckProjectWarnings(context.Background(), p, nil)
+		assert.Len(t, find(errs, CodeTaskNoCommands), 3, "a suppression with an invalid selector should not suppress anything")
+	})
+}